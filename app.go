@@ -2,10 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/adk/capability"
 	"github.com/run-bigpig/jcp/internal/adk/mcp"
 	"github.com/run-bigpig/jcp/internal/adk/tools"
 	"github.com/run-bigpig/jcp/internal/agent"
@@ -14,6 +20,7 @@ import (
 	"github.com/run-bigpig/jcp/internal/memory"
 	"github.com/run-bigpig/jcp/internal/models"
 	"github.com/run-bigpig/jcp/internal/openclaw"
+	"github.com/run-bigpig/jcp/internal/pkg/indicators"
 	"github.com/run-bigpig/jcp/internal/pkg/paths"
 	"github.com/run-bigpig/jcp/internal/pkg/proxy"
 	"github.com/run-bigpig/jcp/internal/services"
@@ -33,6 +40,7 @@ type App struct {
 	hotTrendService   *hottrend.HotTrendService
 	longHuBangService *services.LongHuBangService
 	marketPusher      *services.MarketDataPusher
+	meetingScheduler  *services.MeetingScheduler
 	meetingService    *meeting.Service
 	sessionService    *services.SessionService
 	strategyService   *services.StrategyService
@@ -42,12 +50,19 @@ type App struct {
 	memoryManager     *memory.Manager
 	updateService     *services.UpdateService
 	openClawServer    *openclaw.Server
+	researchQueue     *meeting.ResearchQueue
+	cacheEpochs       *services.CacheEpochs
 
 	// 会议取消管理
-	meetingCancels   map[string]context.CancelFunc
+	meetingCancels   map[string]*meetingCancel
 	meetingCancelsMu sync.RWMutex
 }
 
+// meetingCancel 进行中会议的取消句柄，用指针区分同一股票先后开始的会议
+type meetingCancel struct {
+	cancel context.CancelFunc
+}
+
 // NewApp creates a new App application struct
 func NewApp() *App {
 	dataDir := paths.GetDataDir()
@@ -75,12 +90,30 @@ func NewApp() *App {
 
 	marketService := services.NewMarketService()
 	newsService := services.NewNewsService()
+	sentimentCfg := configService.GetConfig().NewsSentiment
+	newsService.SetSentimentTerms(sentimentCfg.PositiveTerms, sentimentCfg.NegativeTerms)
 
 	// 初始化龙虎榜服务
 	longHuBangService := services.NewLongHuBangService()
 
+	// 初始化财报日历服务
+	earningsService := services.NewEarningsService()
+
+	// 初始化新股日历服务
+	ipoService := services.NewIPOService()
+
+	// 初始化网页搜索服务（web_search 工具）
+	webSearchService := services.NewWebSearchService(configService)
+
+	// 按交易日历失效的缓存：零点、开盘、收盘、盘后数据发布时自动清空
+	cacheEpochs := services.NewCacheEpochs(marketService)
+	marketService.RegisterCaches(cacheEpochs)
+	longHuBangService.RegisterCaches(cacheEpochs)
+	earningsService.RegisterCaches(cacheEpochs)
+	ipoService.RegisterCaches(cacheEpochs)
+
 	// 初始化工具注册中心
-	toolRegistry := tools.NewRegistry(marketService, newsService, configService, researchReportService, hotTrendSvc, longHuBangService)
+	toolRegistry := tools.NewRegistry(marketService, newsService, configService, researchReportService, hotTrendSvc, longHuBangService, earningsService, ipoService, webSearchService)
 
 	// 初始化 MCP 管理器
 	mcpManager := mcp.NewManager()
@@ -126,11 +159,37 @@ func NewApp() *App {
 		}
 	}
 
+	// 设置思考内容持久化策略
+	meetingService.SetThinkingPersist(configService.GetConfig().ThinkingPersist)
+	meetingService.SetMeetingVisibility(configService.GetConfig().MeetingVisibility)
+	meetingService.SetModelWarmup(configService.GetConfig().ModelWarmup)
+	meetingService.SetFactCheck(!configService.GetConfig().DisableFactCheck)
+	meetingService.SetMeetingBudget(configService.GetConfig().MeetingBudget)
+	meetingService.SetReproducibility(configService.GetConfig().Reproducibility)
+	meetingService.SetAutoContinue(configService.GetConfig().AutoContinueTruncated)
+	meetingService.SetToolArgGuard(configService.GetConfig().ToolArgGuard)
+	meetingService.SetSystemPromptAffixes(configService.GetConfig().GlobalSystemPromptPrefix, configService.GetConfig().GlobalSystemPromptSuffix)
+	meetingService.SetIndexOverviewProvider(marketService.GetIndexOverview)
+	meetingService.SetDataFreshnessProvider(marketService.GetDataFreshness)
+
 	// 初始化Session服务
 	sessionService := services.NewSessionService(dataDir)
+	// 专家 token 用量按会话累计
+	meetingService.SetUsageRecorder(func(stockCode string, usage models.TokenUsage) {
+		if err := sessionService.AddTokenUsage(stockCode, usage); err != nil {
+			log.Warn("record token usage error: %v", err)
+		}
+	})
+	// 会议运行记录（各发言者实际生效的模型参数）保存到会话
+	meetingService.SetMeetingRunRecorder(func(stockCode string, run models.MeetingRun) {
+		if err := sessionService.AddMeetingRun(stockCode, run); err != nil {
+			log.Warn("record meeting run error: %v", err)
+		}
+	})
 
 	// 初始化策略服务
 	strategyService := services.NewStrategyService(dataDir)
+	strategyService.SetKnownTools(toolRegistry.GetAllToolNames())
 
 	// 初始化Agent容器（直接从StrategyService获取数据）
 	agentContainer := agent.NewContainer()
@@ -152,7 +211,7 @@ func NewApp() *App {
 		}
 		return nil
 	}, func(code string) (*models.Stock, error) {
-		stocks, err := marketService.GetStockRealTimeData(code)
+		stocks, err := marketService.GetStockRealTimeDataCached(context.Background(), code)
 		if err != nil {
 			return nil, err
 		}
@@ -161,6 +220,11 @@ func NewApp() *App {
 		}
 		return &stocks[0], nil
 	})
+	openClawServer.SetCacheStatsProvider(func() any {
+		return map[string]any{"realtime": marketService.CacheStats()}
+	})
+	openClawServer.SetSessionResolver(sessionService.GetSession)
+	openClawServer.SetTokenStore(configService)
 
 	log.Info("所有服务初始化完成")
 
@@ -170,6 +234,7 @@ func NewApp() *App {
 		newsService:       newsService,
 		hotTrendService:   hotTrendSvc,
 		longHuBangService: longHuBangService,
+		cacheEpochs:       cacheEpochs,
 		meetingService:    meetingService,
 		sessionService:    sessionService,
 		strategyService:   strategyService,
@@ -179,7 +244,7 @@ func NewApp() *App {
 		memoryManager:     memoryManager,
 		updateService:     updateService,
 		openClawServer:    openClawServer,
-		meetingCancels:    make(map[string]context.CancelFunc),
+		meetingCancels:    make(map[string]*meetingCancel),
 	}
 }
 
@@ -201,18 +266,69 @@ func (a *App) startup(ctx context.Context) {
 	// 设置 Meeting 服务的 AI 配置解析器
 	if a.meetingService != nil {
 		a.meetingService.SetAIConfigResolver(a.getAIConfigByID)
+		// 每位专家发言后推送单行摘要，供小屏/读屏使用
+		a.meetingService.SetDigestListener(func(digest meeting.MeetingDigest) {
+			runtime.EventsEmit(a.ctx, "meeting:digest:"+digest.StockCode, digest)
+		})
 	}
 
+	// 新闻情绪工具使用默认模型对快讯分类
+	a.toolRegistry.SetNewsClassifier(a.classifyNews)
+
+	// 请求被接口以 400 拒绝的参数记录到对应 AI 配置，后续请求不再发送
+	capability.SetLearnedListener(func(configID string, c capability.Capability, supported bool) {
+		if err := a.configService.SetAICapability(configID, string(c), supported); err != nil {
+			log.Warn("保存能力检测结果失败: %v", err)
+		}
+	})
+
+	// 删除 AI 配置后自动清理的失效引用推送给前端
+	a.configService.SetReferencesFixedListener(func(fixes []services.AIConfigRefFix) {
+		runtime.EventsEmit(a.ctx, services.EventConfigReferencesFixed, fixes)
+	})
+
 	// 初始化更新服务
 	if a.updateService != nil {
 		a.updateService.Startup(ctx)
 	}
 
+	// 专家配置变更时通知前端（新增/删除/修改的专家 ID）
+	a.agentContainer.SetChangeListener(func(diff agent.AgentDiff) {
+		runtime.EventsEmit(a.ctx, "agents:changed", diff)
+	})
+
 	// 初始化并启动市场数据推送服务（需要 context）
 	a.marketPusher = services.NewMarketDataPusher(a.marketService, a.configService, a.newsService)
+	alertService := services.NewAlertService(a.configService)
+	// 开启 AI 跟进的提醒触发后自动开一场会，结论保存到该股票会话
+	alertService.SetTriggeredListener(func(alert models.PriceAlert) {
+		if alert.AIFollowUp {
+			go a.runAlertFollowUp(alert)
+		}
+	})
+	a.marketPusher.SetAlertService(alertService)
+	a.marketPusher.RegisterCaches(a.cacheEpochs)
 	a.marketPusher.Start(ctx)
 	log.Info("市场数据推送服务已启动")
 
+	// 深度研究任务队列：后台串行执行，进度推送 research:progress
+	a.researchQueue = meeting.NewResearchQueue(ctx, a.runResearchTask, func(p meeting.ResearchProgress) {
+		runtime.EventsEmit(a.ctx, meeting.EventResearchProgress, p)
+	})
+
+	// 舆情热点：软截止后完成的平台推送 hottrend:update
+	if a.hotTrendService != nil {
+		a.hotTrendService.SetUpdateCallback(func(result hottrend.HotTrendResult) {
+			runtime.EventsEmit(a.ctx, hottrend.EventUpdate, result)
+		})
+	}
+
+	// 启动交易日定时会议（盘前/盘后简报）
+	a.meetingScheduler = services.NewMeetingScheduler(a.marketService, a.configService, a.runScheduledMeeting)
+	a.meetingScheduler.Start(ctx)
+
+	a.cacheEpochs.Start()
+
 	// 启动 OpenClaw 服务（如果已启用）
 	cfg := a.configService.GetConfig()
 	if cfg.OpenClaw.Enabled && cfg.OpenClaw.Port > 0 {
@@ -231,9 +347,23 @@ func (a *App) shutdown(ctx context.Context) {
 	if a.marketPusher != nil {
 		a.marketPusher.Stop()
 	}
+	if a.meetingScheduler != nil {
+		a.meetingScheduler.Stop()
+	}
+	if a.cacheEpochs != nil {
+		a.cacheEpochs.Stop()
+	}
+	a.flushSessions()
 	logger.Close()
 }
 
+// flushSessions 将延迟写盘的会话立即保存（会议结束、应用退出时调用）
+func (a *App) flushSessions() {
+	if err := a.sessionService.Flush(); err != nil {
+		log.Error("保存会话失败: %v", err)
+	}
+}
+
 // Greet returns a greeting for the given name
 func (a *App) Greet(name string) string {
 	return "Hello " + name + ", It's show time!"
@@ -246,6 +376,9 @@ func (a *App) GetConfig() *models.AppConfig {
 
 // UpdateConfig 更新配置
 func (a *App) UpdateConfig(config *models.AppConfig) string {
+	if err := services.NormalizeMeetingSchedules(config.MeetingSchedules); err != nil {
+		return err.Error()
+	}
 	if err := a.configService.UpdateConfig(config); err != nil {
 		return err.Error()
 	}
@@ -257,23 +390,26 @@ func (a *App) UpdateConfig(config *models.AppConfig) string {
 	}
 	// 更新代理配置
 	proxy.GetManager().SetConfig(&config.Proxy)
-	// 更新记忆管理器的 LLM 配置
-	if a.meetingService != nil && config.Memory.AIConfigID != "" {
-		for i := range config.AIConfigs {
-			if config.AIConfigs[i].ID == config.Memory.AIConfigID {
-				a.meetingService.SetMemoryAIConfig(&config.AIConfigs[i])
-				break
-			}
-		}
+	// 更新记忆管理器与 Moderator 的 LLM 配置，引用已清空时传 nil 以回退默认配置
+	if a.meetingService != nil {
+		a.meetingService.SetMemoryAIConfig(a.getAIConfigByID(config.Memory.AIConfigID))
+		a.meetingService.SetModeratorAIConfig(a.getAIConfigByID(config.ModeratorAIID))
 	}
-	// 更新 Moderator AI 配置
-	if a.meetingService != nil && config.ModeratorAIID != "" {
-		for i := range config.AIConfigs {
-			if config.AIConfigs[i].ID == config.ModeratorAIID {
-				a.meetingService.SetModeratorAIConfig(&config.AIConfigs[i])
-				break
-			}
-		}
+	// 更新思考内容持久化策略
+	if a.meetingService != nil {
+		a.meetingService.SetThinkingPersist(config.ThinkingPersist)
+		a.meetingService.SetMeetingVisibility(config.MeetingVisibility)
+		a.meetingService.SetModelWarmup(config.ModelWarmup)
+		a.meetingService.SetFactCheck(!config.DisableFactCheck)
+		a.meetingService.SetMeetingBudget(config.MeetingBudget)
+		a.meetingService.SetReproducibility(config.Reproducibility)
+		a.meetingService.SetAutoContinue(config.AutoContinueTruncated)
+		a.meetingService.SetToolArgGuard(config.ToolArgGuard)
+		a.meetingService.SetSystemPromptAffixes(config.GlobalSystemPromptPrefix, config.GlobalSystemPromptSuffix)
+	}
+	// 更新快讯情绪自定义词条
+	if a.newsService != nil {
+		a.newsService.SetSentimentTerms(config.NewsSentiment.PositiveTerms, config.NewsSentiment.NegativeTerms)
 	}
 	// 更新 OpenClaw 服务配置（热更新）
 	a.applyOpenClawConfig(&config.OpenClaw)
@@ -313,6 +449,36 @@ func (a *App) GetOpenClawStatus() map[string]any {
 	}
 }
 
+// GetAPITokens 获取本地 HTTP API 令牌列表
+func (a *App) GetAPITokens() []models.APIToken {
+	return a.configService.GetAPITokens()
+}
+
+// APITokenCreateResult 创建令牌结果，Plaintext 只在此时返回一次
+type APITokenCreateResult struct {
+	Token     models.APIToken `json:"token"`
+	Plaintext string          `json:"plaintext"`
+	Error     string          `json:"error"`
+}
+
+// CreateAPIToken 创建按权限范围授权的 API 令牌
+func (a *App) CreateAPIToken(name string, scopes []models.APIScope) APITokenCreateResult {
+	token, plaintext, err := a.configService.CreateAPIToken(name, scopes)
+	if err != nil {
+		return APITokenCreateResult{Error: err.Error()}
+	}
+	log.Info("创建 API 令牌: %s %v", token.Name, token.Scopes)
+	return APITokenCreateResult{Token: token, Plaintext: plaintext}
+}
+
+// RevokeAPIToken 吊销 API 令牌，立即生效
+func (a *App) RevokeAPIToken(id string) string {
+	if err := a.configService.RevokeAPIToken(id); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
 // mergeRealtimeStock 合并实时行情字段，保留本地静态字段
 func (a *App) mergeRealtimeStock(base models.Stock, rt models.Stock) models.Stock {
 	merged := base
@@ -343,7 +509,7 @@ func (a *App) GetWatchlist() []models.Stock {
 	for i, s := range list {
 		codes[i] = s.Symbol
 	}
-	realtime, err := a.marketService.GetStockRealTimeData(codes...)
+	realtime, err := a.marketService.GetStockRealTimeData(a.ctx, codes...)
 	if err != nil || len(realtime) == 0 {
 		return list
 	}
@@ -371,9 +537,25 @@ func (a *App) AddToWatchlist(stock models.Stock) string {
 	}
 	// 同步添加到推送订阅
 	a.marketPusher.AddSubscription(stock.Symbol)
+	// 新加的自选股大概率马上打开会议室，提前预热模型连接
+	a.WarmUpAgents(stock.Symbol)
 	return "success"
 }
 
+// WarmUpAgents 异步预热该股票会议将用到的模型（主持人及各启用专家），首条会议消息无需等待建连
+// 每个模型发送一次 1 token 的请求，完成客户端初始化、建连与鉴权；同一配置只请求一次
+func (a *App) WarmUpAgents(stockCode string) {
+	aiConfig := a.getDefaultAIConfig(a.configService.GetConfig())
+	if aiConfig == nil || a.meetingService == nil {
+		return
+	}
+	agents := a.agentContainer.Snapshot().Enabled()
+	go func() {
+		n := a.meetingService.PrimeAgentModels(context.Background(), aiConfig, agents)
+		log.Debug("primed %d meeting models for %s", n, stockCode)
+	}()
+}
+
 // RemoveFromWatchlist 移除自选股
 func (a *App) RemoveFromWatchlist(symbol string) string {
 	if err := a.configService.RemoveFromWatchlist(symbol); err != nil {
@@ -392,29 +574,161 @@ func (a *App) RemoveFromWatchlist(symbol string) string {
 	return "success"
 }
 
+// GetWatchlistGroups 获取自选股分组
+func (a *App) GetWatchlistGroups() []models.WatchlistGroup {
+	return a.configService.GetWatchlistGroups()
+}
+
+// WatchlistGroupResult 新建自选股分组结果
+type WatchlistGroupResult struct {
+	Group models.WatchlistGroup `json:"group"`
+	Error string                `json:"error"`
+}
+
+// CreateWatchlistGroup 新建自选股分组
+func (a *App) CreateWatchlistGroup(name string) WatchlistGroupResult {
+	group, err := a.configService.CreateWatchlistGroup(name)
+	if err != nil {
+		return WatchlistGroupResult{Error: err.Error()}
+	}
+	return WatchlistGroupResult{Group: group}
+}
+
+// RenameWatchlistGroup 重命名自选股分组
+func (a *App) RenameWatchlistGroup(id, name string) string {
+	if err := a.configService.RenameWatchlistGroup(id, name); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// DeleteWatchlistGroup 删除自选股分组，其中的自选股保留
+func (a *App) DeleteWatchlistGroup(id string) string {
+	if err := a.configService.DeleteWatchlistGroup(id); err != nil {
+		return err.Error()
+	}
+	a.marketPusher.SyncSubscriptions()
+	return "success"
+}
+
+// AddStockToGroup 将自选股同时加入指定分组
+func (a *App) AddStockToGroup(symbol, groupID string) string {
+	if err := a.configService.AddStockToGroup(symbol, groupID); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// RemoveStockFromGroup 将自选股移出指定分组，自选股本身保留
+func (a *App) RemoveStockFromGroup(symbol, groupID string) string {
+	if err := a.configService.RemoveStockFromGroup(symbol, groupID); err != nil {
+		return err.Error()
+	}
+	a.marketPusher.SyncSubscriptions()
+	return "success"
+}
+
+// MoveStockToGroup 将自选股移动到指定分组末尾
+func (a *App) MoveStockToGroup(symbol, groupID string) string {
+	if err := a.configService.MoveStockToGroup(symbol, groupID); err != nil {
+		return err.Error()
+	}
+	a.marketPusher.SyncSubscriptions()
+	return "success"
+}
+
+// ReorderWatchlist 按传入的代码顺序调整自选股在各自分组内的顺序
+func (a *App) ReorderWatchlist(symbols []string) string {
+	if err := a.configService.ReorderWatchlist(symbols); err != nil {
+		return err.Error()
+	}
+	a.marketPusher.SyncSubscriptions()
+	return "success"
+}
+
+// GetAlerts 获取价格提醒列表
+func (a *App) GetAlerts() []models.PriceAlert {
+	return a.configService.GetAlerts()
+}
+
+// AddAlert 添加价格提醒（触发后通过 alert:triggered 事件推送，且不再重复触发）
+func (a *App) AddAlert(alert models.PriceAlert) string {
+	if _, err := a.configService.AddAlert(alert); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// AddPriceAlert 按条件添加提醒
+// condition 支持 price_above、price_below、change_percent_above（阈值为涨跌幅%）、volume_spike（阈值为量比倍数）
+func (a *App) AddPriceAlert(symbol, condition string, threshold float64) string {
+	alert := models.PriceAlert{
+		Symbol:    symbol,
+		Condition: models.AlertCondition(condition),
+		Threshold: threshold,
+	}
+	normalized := models.NormalizeSymbol(symbol)
+	for _, stock := range a.configService.GetWatchlist() {
+		if models.NormalizeSymbol(stock.Symbol) == normalized {
+			alert.Name = stock.Name
+			break
+		}
+	}
+	return a.AddAlert(alert)
+}
+
+// RemoveAlert 删除价格提醒
+func (a *App) RemoveAlert(id string) string {
+	if err := a.configService.RemoveAlert(id); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
 // GetStockRealTimeData 获取股票实时数据
 func (a *App) GetStockRealTimeData(codes []string) []models.Stock {
-	stocks, _ := a.marketService.GetStockRealTimeData(codes...)
+	stocks, _ := a.marketService.GetStockRealTimeData(a.ctx, codes...)
 	return stocks
 }
 
 // GetKLineData 获取K线数据
 func (a *App) GetKLineData(code string, period string, days int) []models.KLineData {
-	data, _ := a.marketService.GetKLineData(code, period, days)
+	data, _ := a.marketService.GetKLineData(a.ctx, code, period, days)
 	return data
 }
 
 // GetOrderBook 获取盘口数据（真实五档）
 func (a *App) GetOrderBook(code string) models.OrderBook {
-	orderBook, _ := a.marketService.GetRealOrderBook(code)
+	orderBook, _ := a.marketService.GetRealOrderBook(a.ctx, code)
 	return orderBook
 }
 
+// FocusStock 聚焦股票，后端以 orderbook:<code> 事件快速推送盘口与行情
+func (a *App) FocusStock(code string) {
+	if a.marketPusher != nil {
+		a.marketPusher.FocusStock(code)
+	}
+	// 用户关注某只股票时大概率会发起讨论，提前预热模型连接
+	a.warmupMeetingModels(a.getDefaultAIConfig(a.configService.GetConfig()))
+}
+
+// UnfocusStock 取消聚焦股票
+func (a *App) UnfocusStock() {
+	if a.marketPusher != nil {
+		a.marketPusher.UnfocusStock()
+	}
+}
+
 // SearchStocks 搜索股票
 func (a *App) SearchStocks(keyword string) []services.StockSearchResult {
 	return a.configService.SearchStocks(keyword, 20)
 }
 
+// ResolveStockReference 解析粘贴的行情网址、代码或名称，返回候选股票及是否可直接打开
+func (a *App) ResolveStockReference(input string) services.StockReferenceResult {
+	return a.configService.ResolveStockReference(input)
+}
+
 // getDefaultAIConfig 获取默认AI配置
 func (a *App) getDefaultAIConfig(config *models.AppConfig) *models.AIConfig {
 	for i := range config.AIConfigs {
@@ -431,19 +745,32 @@ func (a *App) getDefaultAIConfig(config *models.AppConfig) *models.AIConfig {
 	return nil
 }
 
-// getAIConfigByID 根据ID获取AI配置，找不到则返回默认配置
+// classifyNews 使用默认 AI 配置对快讯做情绪分类，供 get_news_sentiment 工具调用
+func (a *App) classifyNews(ctx context.Context, headlines []string) ([]string, error) {
+	aiConfig := a.getDefaultAIConfig(a.configService.GetConfig())
+	if aiConfig == nil {
+		return nil, errors.New("未配置 AI 模型")
+	}
+	llm, err := adk.NewModelFactory().CreateModel(ctx, aiConfig)
+	if err != nil {
+		return nil, err
+	}
+	return services.NewLLMNewsClassifier(llm)(ctx, headlines)
+}
+
+// getAIConfigByID 根据ID获取AI配置，找不到返回 nil
+// 专家未指定或指定的配置已删除时，会议服务降级使用会议的 AI 配置
 func (a *App) getAIConfigByID(aiConfigID string) *models.AIConfig {
+	if aiConfigID == "" {
+		return nil
+	}
 	config := a.configService.GetConfig()
-	// 如果指定了ID，尝试查找
-	if aiConfigID != "" {
-		for i := range config.AIConfigs {
-			if config.AIConfigs[i].ID == aiConfigID {
-				return &config.AIConfigs[i]
-			}
+	for i := range config.AIConfigs {
+		if config.AIConfigs[i].ID == aiConfigID {
+			return &config.AIConfigs[i]
 		}
 	}
-	// 找不到则返回默认配置
-	return a.getDefaultAIConfig(config)
+	return nil
 }
 
 // ========== Session API ==========
@@ -458,11 +785,39 @@ func (a *App) GetOrCreateSession(stockCode, stockName string) *models.StockSessi
 }
 
 // GetSessionMessages 获取Session消息
-func (a *App) GetSessionMessages(stockCode string) []models.ChatMessage {
+// includeReasoning 为 false 时不返回模型思考内容
+func (a *App) GetSessionMessages(stockCode string, includeReasoning bool) []models.ChatMessage {
 	if a.sessionService == nil {
 		return nil
 	}
-	return a.sessionService.GetMessages(stockCode)
+	messages := a.sessionService.GetMessages(stockCode)
+	if includeReasoning {
+		return messages
+	}
+	result := make([]models.ChatMessage, len(messages))
+	for i, msg := range messages {
+		msg.Reasoning = ""
+		result[i] = msg
+	}
+	return result
+}
+
+// SearchSessions 跨股票检索历史讨论消息，结果同时通过 session:search:results 事件推送
+func (a *App) SearchSessions(query string, limit int) []services.SessionSearchResult {
+	if a.sessionService == nil {
+		return []services.SessionSearchResult{}
+	}
+	results := a.sessionService.SearchMessages(query, limit)
+	runtime.EventsEmit(a.ctx, "session:search:results", results)
+	return results
+}
+
+// GetSessionStats 获取会话统计：消息数、各专家 token 与参与率、平均每轮回复数
+func (a *App) GetSessionStats(stockCode string) services.SessionStats {
+	if a.sessionService == nil {
+		return services.SessionStats{StockCode: stockCode, ByAgent: map[string]services.AgentStats{}}
+	}
+	return a.sessionService.ComputeSessionStats(stockCode)
 }
 
 // ClearSessionMessages 清空Session消息
@@ -482,6 +837,94 @@ func (a *App) ClearSessionMessages(stockCode string) string {
 	return "success"
 }
 
+// SnapshotMemory 为股票记忆创建快照
+func (a *App) SnapshotMemory(stockCode string) string {
+	if a.memoryManager == nil {
+		return "memory not enabled"
+	}
+	if err := a.memoryManager.SnapshotMemory(stockCode); err != nil {
+		if os.IsNotExist(err) {
+			return "该股票暂无记忆"
+		}
+		return err.Error()
+	}
+	return "success"
+}
+
+// ListMemorySnapshots 列出股票的记忆快照（新的在前）
+func (a *App) ListMemorySnapshots(stockCode string) []memory.SnapshotInfo {
+	if a.memoryManager == nil {
+		return []memory.SnapshotInfo{}
+	}
+	snapshots, err := a.memoryManager.ListSnapshots(stockCode)
+	if err != nil {
+		log.Error("list memory snapshots error: %v", err)
+		return []memory.SnapshotInfo{}
+	}
+	return snapshots
+}
+
+// RestoreMemorySnapshot 将股票记忆回滚到指定快照
+func (a *App) RestoreMemorySnapshot(stockCode, snapshotID string) string {
+	if a.memoryManager == nil {
+		return "memory not enabled"
+	}
+	if err := a.memoryManager.RestoreSnapshot(stockCode, snapshotID); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// GetRelatedMemories 获取与该股票互相提及的其他股票记忆片段（来自跨股票记忆图），limit 不大于 0 时默认 5 条
+func (a *App) GetRelatedMemories(stockCode string, limit int) []memory.RelatedMemoryItem {
+	if a.memoryManager == nil {
+		return []memory.RelatedMemoryItem{}
+	}
+	if limit <= 0 {
+		limit = 5
+	}
+	items := a.memoryManager.GetRelatedMemories(stockCode, limit)
+	if items == nil {
+		return []memory.RelatedMemoryItem{}
+	}
+	return items
+}
+
+// GetActionJournal 获取股票的操作日志（用户在对话中陈述的实际买卖决策，按时间顺序）
+func (a *App) GetActionJournal(stockCode string) []memory.ActionEntry {
+	if a.memoryManager == nil {
+		return []memory.ActionEntry{}
+	}
+	entries, err := a.memoryManager.ActionJournal(stockCode)
+	if err != nil {
+		log.Error("get action journal error: %v", err)
+		return []memory.ActionEntry{}
+	}
+	return entries
+}
+
+// UpdateActionJournalEntry 修改操作日志条目（按 ID 匹配）
+func (a *App) UpdateActionJournalEntry(stockCode string, entry memory.ActionEntry) string {
+	if a.memoryManager == nil {
+		return "memory not enabled"
+	}
+	if err := a.memoryManager.UpdateAction(stockCode, entry); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// DeleteActionJournalEntry 删除操作日志条目
+func (a *App) DeleteActionJournalEntry(stockCode, id string) string {
+	if a.memoryManager == nil {
+		return "memory not enabled"
+	}
+	if err := a.memoryManager.DeleteAction(stockCode, id); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
 // UpdateStockPosition 更新股票持仓信息
 func (a *App) UpdateStockPosition(stockCode string, shares int64, costPrice float64) string {
 	if a.sessionService == nil {
@@ -512,16 +955,19 @@ func (a *App) AddAgentConfig(config models.AgentConfig) string {
 		Tools:       config.Tools,
 		MCPServers:  config.MCPServers,
 		Enabled:     config.Enabled,
+
+		OutputLanguage: config.OutputLanguage,
+		Tone:           config.Tone,
 	}
 	if err := a.strategyService.AddAgentToActiveStrategy(agent); err != nil {
 		return err.Error()
 	}
-	a.agentContainer.LoadAgents(a.strategyService.GetAllAgents())
+	a.reloadAgents()
 	return "success"
 }
 
-// UpdateAgentConfig 更新当前策略中的Agent配置
-func (a *App) UpdateAgentConfig(config models.AgentConfig) string {
+// UpdateAgentConfig 更新当前策略中的Agent配置，revision 为读取策略时的版本
+func (a *App) UpdateAgentConfig(config models.AgentConfig, revision int64) string {
 	agent := models.StrategyAgent{
 		ID:          config.ID,
 		Name:        config.Name,
@@ -532,20 +978,23 @@ func (a *App) UpdateAgentConfig(config models.AgentConfig) string {
 		Tools:       config.Tools,
 		MCPServers:  config.MCPServers,
 		Enabled:     config.Enabled,
+
+		OutputLanguage: config.OutputLanguage,
+		Tone:           config.Tone,
 	}
-	if err := a.strategyService.UpdateAgentInActiveStrategy(agent); err != nil {
-		return err.Error()
+	if err := a.strategyService.UpdateAgentInActiveStrategy(agent, revision); err != nil {
+		return strategyErrorResult(err)
 	}
-	a.agentContainer.LoadAgents(a.strategyService.GetAllAgents())
+	a.reloadAgents()
 	return "success"
 }
 
-// DeleteAgentConfig 从当前策略删除Agent配置
-func (a *App) DeleteAgentConfig(id string) string {
-	if err := a.strategyService.DeleteAgentFromActiveStrategy(id); err != nil {
-		return err.Error()
+// DeleteAgentConfig 从当前策略删除Agent配置，revision 为读取策略时的版本
+func (a *App) DeleteAgentConfig(id string, revision int64) string {
+	if err := a.strategyService.DeleteAgentFromActiveStrategy(id, revision); err != nil {
+		return strategyErrorResult(err)
 	}
-	a.agentContainer.LoadAgents(a.strategyService.GetAllAgents())
+	a.reloadAgents()
 	return "success"
 }
 
@@ -556,6 +1005,11 @@ func (a *App) GetStrategies() []models.Strategy {
 	return a.strategyService.GetAllStrategies()
 }
 
+// GetStrategyRevision 获取策略存储版本，更新/删除时回传以检测并发修改
+func (a *App) GetStrategyRevision() int64 {
+	return a.strategyService.GetRevision()
+}
+
 // GetActiveStrategyID 获取当前激活策略ID
 func (a *App) GetActiveStrategyID() string {
 	return a.strategyService.GetActiveID()
@@ -567,7 +1021,7 @@ func (a *App) SetActiveStrategy(id string) string {
 		return err.Error()
 	}
 	// 重新加载Agent容器
-	a.agentContainer.LoadAgents(a.strategyService.GetAllAgents())
+	a.reloadAgents()
 	// 通知前端策略已切换
 	runtime.EventsEmit(a.ctx, "strategy:changed", id)
 	return "success"
@@ -581,17 +1035,89 @@ func (a *App) AddStrategy(strategy models.Strategy) string {
 	return "success"
 }
 
-// UpdateStrategy 更新策略
-func (a *App) UpdateStrategy(strategy models.Strategy) string {
-	if err := a.strategyService.UpdateStrategy(strategy); err != nil {
+// UpdateStrategy 更新策略，revision 为读取策略时的版本
+func (a *App) UpdateStrategy(strategy models.Strategy, revision int64) string {
+	if err := a.strategyService.UpdateStrategy(strategy, revision); err != nil {
+		return strategyErrorResult(err)
+	}
+	// 修改或删除的可能是当前策略
+	a.reloadAgents()
+	return "success"
+}
+
+// LintStrategy 检查策略专家的输出语言、语气与指令是否冲突，编辑时实时提示
+func (a *App) LintStrategy(strategy models.Strategy) []services.AgentLintIssue {
+	return services.LintStrategyAgents(strategy)
+}
+
+// DeleteStrategy 删除策略，revision 为读取策略时的版本
+func (a *App) DeleteStrategy(id string, revision int64) string {
+	if err := a.strategyService.DeleteStrategy(id, revision); err != nil {
+		return strategyErrorResult(err)
+	}
+	// 修改或删除的可能是当前策略
+	a.reloadAgents()
+	return "success"
+}
+
+// GetStrategyHistory 获取策略的历史版本（最新的在前）
+func (a *App) GetStrategyHistory(id string) []services.StrategyVersion {
+	versions, err := a.strategyService.GetStrategyHistory(id)
+	if err != nil {
+		log.Error("获取策略历史版本失败 [%s]: %v", id, err)
+		return []services.StrategyVersion{}
+	}
+	return versions
+}
+
+// RestoreStrategyVersion 将策略恢复为指定历史版本
+func (a *App) RestoreStrategyVersion(id, versionID string) string {
+	if err := a.strategyService.RestoreStrategyVersion(id, versionID); err != nil {
+		return err.Error()
+	}
+	a.reloadAgents()
+	return "success"
+}
+
+// reloadAgents 按当前策略刷新专家容器，进行中的会议继续使用开始时的快照
+func (a *App) reloadAgents() {
+	a.agentContainer.LoadAgents(a.strategyService.GetAllAgents())
+}
+
+// strategyConflict 策略版本冲突时返回给前端的结果，前端应重新加载后重试
+const strategyConflict = "conflict"
+
+// strategyErrorResult 将策略写入错误转为前端结果，版本冲突返回 strategyConflict
+func strategyErrorResult(err error) string {
+	var conflict *services.StrategyConflictError
+	if errors.As(err, &conflict) {
+		log.Warn("策略写入冲突: %v", err)
+		return strategyConflict
+	}
+	return err.Error()
+}
+
+// ExportStrategy 导出策略为可分享的 JSON，失败返回空
+func (a *App) ExportStrategy(id string) []byte {
+	data, err := a.strategyService.ExportStrategy(id)
+	if err != nil {
+		log.Warn("导出策略失败: %v", err)
+		return nil
+	}
+	return data
+}
+
+// ImportStrategy 从 JSON 导入策略
+func (a *App) ImportStrategy(data []byte) string {
+	if _, err := a.strategyService.ImportStrategy(data); err != nil {
 		return err.Error()
 	}
 	return "success"
 }
 
-// DeleteStrategy 删除策略
-func (a *App) DeleteStrategy(id string) string {
-	if err := a.strategyService.DeleteStrategy(id); err != nil {
+// DuplicateStrategy 复制策略
+func (a *App) DuplicateStrategy(id string) string {
+	if _, err := a.strategyService.DuplicateStrategy(id); err != nil {
 		return err.Error()
 	}
 	return "success"
@@ -653,27 +1179,29 @@ func (a *App) GenerateStrategy(req GenerateStrategyRequest) GenerateStrategyResp
 		})
 	}
 
-	// 获取已启用的MCP服务器列表
+	// 获取已启用的MCP服务器列表及工具（读取工具目录缓存）
+	var mcpIDs []string
+	for _, m := range config.MCPServers {
+		if m.Enabled {
+			mcpIDs = append(mcpIDs, m.ID)
+		}
+	}
+	toolNames := make(map[string][]string, len(mcpIDs))
+	for _, t := range a.mcpManager.GetToolInfosByServerIDs(mcpIDs) {
+		toolNames[t.ServerID] = append(toolNames[t.ServerID], t.Name)
+	}
 	for _, m := range config.MCPServers {
 		if m.Enabled {
-			// 获取该服务器的工具列表
-			var toolNames []string
-			if tools, err := a.mcpManager.GetServerTools(m.ID); err == nil {
-				for _, t := range tools {
-					toolNames = append(toolNames, t.Name)
-				}
-			}
 			input.MCPServers = append(input.MCPServers, services.MCPInfoForGen{
 				ID:    m.ID,
 				Name:  m.Name,
-				Tools: toolNames,
+				Tools: toolNames[m.ID],
 			})
 		}
 	}
 
-	// 设置LLM并生成策略
-	a.strategyService.SetLLM(llm)
-	result, err := a.strategyService.Generate(ctx, input)
+	// 生成策略
+	result, err := a.strategyService.Generate(ctx, llm, input)
 	if err != nil {
 		return GenerateStrategyResponse{Success: false, Error: err.Error()}
 	}
@@ -734,14 +1262,13 @@ func (a *App) EnhancePrompt(req EnhancePromptRequest) EnhancePromptResponse {
 		return EnhancePromptResponse{Success: false, Error: err.Error()}
 	}
 
-	// 设置LLM并增强提示词
-	a.strategyService.SetLLM(llm)
+	// 增强提示词
 	input := services.EnhancePromptInput{
 		OriginalPrompt: req.OriginalPrompt,
 		AgentRole:      req.AgentRole,
 		AgentName:      req.AgentName,
 	}
-	result, err := a.strategyService.EnhancePrompt(ctx, input)
+	result, err := a.strategyService.EnhancePrompt(ctx, llm, input)
 	if err != nil {
 		return EnhancePromptResponse{Success: false, Error: err.Error()}
 	}
@@ -758,34 +1285,79 @@ func (a *App) EnhancePrompt(req EnhancePromptRequest) EnhancePromptResponse {
 type MeetingMessageRequest struct {
 	StockCode    string   `json:"stockCode"`
 	Content      string   `json:"content"`
+	Images       []string `json:"images,omitempty"` // 附带图片：data URL、base64 或本地文件路径
 	MentionIds   []string `json:"mentionIds"`
 	ReplyToId    string   `json:"replyToId"`
 	ReplyContent string   `json:"replyContent"`
+	Visibility   string   `json:"visibility"` // 智能会议专家间发言可见性，空则使用全局配置
+
+	ForceSuspectData bool   `json:"forceSuspectData,omitempty"` // 行情数据异常时仍然开会（用户确认）
+	Mode             string `json:"mode,omitempty"`             // 智能会议模式：空为默认，debate 为辩论模式（专家互相反驳后裁决）
+	Reproducible     bool   `json:"reproducible,omitempty"`     // 复现模式：统一低温度、固定随机种子与当前时间
+	Seed             int32  `json:"seed,omitempty"`             // 复现模式的随机种子，0 使用配置值
+	ConcurrentMode   bool   `json:"concurrentMode,omitempty"`   // 专家任务相互独立时并发发言（按 CPU 核数限流）
 }
 
 // cancelMeetingInternal 内部取消会议方法
 func (a *App) cancelMeetingInternal(stockCode string) {
 	a.meetingCancelsMu.Lock()
-	if cancel, ok := a.meetingCancels[stockCode]; ok {
-		cancel()
+	if mc, ok := a.meetingCancels[stockCode]; ok {
+		mc.cancel()
 		delete(a.meetingCancels, stockCode)
 	}
 	a.meetingCancelsMu.Unlock()
 }
 
-// CancelMeeting 取消指定股票的会议（前端调用）
-func (a *App) CancelMeeting(stockCode string) bool {
-	a.cancelMeetingInternal(stockCode)
-	log.Info("会议已取消: %s", stockCode)
-	return true
+// registerMeetingCancel 登记会议的取消函数，返回会议结束时的清理函数
+// 清理时只删除自己登记的条目，避免旧会议退出时误删新会议的句柄
+func (a *App) registerMeetingCancel(stockCode string, cancel context.CancelFunc) func() {
+	a.meetingCancelsMu.Lock()
+	defer a.meetingCancelsMu.Unlock()
+	return a.addMeetingCancelLocked(stockCode, cancel)
 }
 
-// SendMeetingMessage 发送会议室消息（@指定成员回复）
-func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage {
-	// 获取Session
-	session := a.sessionService.GetSession(req.StockCode)
-	if session == nil {
-		log.Warn("session not found: %s", req.StockCode)
+// tryRegisterMeetingCancel 没有进行中的会议时才登记，检查与登记在同一把锁内完成，避免并发发起的后台会议重复开会
+func (a *App) tryRegisterMeetingCancel(stockCode string, cancel context.CancelFunc) (func(), bool) {
+	a.meetingCancelsMu.Lock()
+	defer a.meetingCancelsMu.Unlock()
+	if _, busy := a.meetingCancels[stockCode]; busy {
+		return nil, false
+	}
+	return a.addMeetingCancelLocked(stockCode, cancel), true
+}
+
+// addMeetingCancelLocked 登记取消函数并返回清理函数，调用方需持有 meetingCancelsMu
+func (a *App) addMeetingCancelLocked(stockCode string, cancel context.CancelFunc) func() {
+	mc := &meetingCancel{cancel: cancel}
+	a.meetingCancels[stockCode] = mc
+	return func() {
+		a.meetingCancelsMu.Lock()
+		if a.meetingCancels[stockCode] == mc {
+			delete(a.meetingCancels, stockCode)
+		}
+		a.meetingCancelsMu.Unlock()
+		cancel()
+	}
+}
+
+// CancelMeeting 取消指定股票的会议（前端调用）
+func (a *App) CancelMeeting(stockCode string) bool {
+	a.cancelMeetingInternal(stockCode)
+	log.Info("会议已取消: %s", stockCode)
+	return true
+}
+
+// CancelToolCall 取消专家正在执行的单个 MCP 工具调用（requestID 来自 tool_progress 事件），专家收到"用户取消"后继续发言
+func (a *App) CancelToolCall(requestID string) bool {
+	return a.mcpManager.CancelToolCall(requestID)
+}
+
+// SendMeetingMessage 发送会议室消息（@指定成员回复）
+func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage {
+	// 获取Session
+	session := a.sessionService.GetSession(req.StockCode)
+	if session == nil {
+		log.Warn("session not found: %s", req.StockCode)
 		return []models.ChatMessage{}
 	}
 
@@ -794,16 +1366,9 @@ func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage
 
 	// 创建可取消的 context
 	meetingCtx, cancel := context.WithCancel(a.ctx)
-	a.meetingCancelsMu.Lock()
-	a.meetingCancels[req.StockCode] = cancel
-	a.meetingCancelsMu.Unlock()
-
-	// 会议结束后清理
-	defer func() {
-		a.meetingCancelsMu.Lock()
-		delete(a.meetingCancels, req.StockCode)
-		a.meetingCancelsMu.Unlock()
-	}()
+	// 会议结束后清理，并将本次讨论写盘
+	defer a.flushSessions()
+	defer a.registerMeetingCancel(req.StockCode, cancel)()
 
 	// 先保存用户消息
 	userMsg := models.ChatMessage{
@@ -815,12 +1380,7 @@ func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage
 	}
 	a.sessionService.AddMessage(req.StockCode, userMsg)
 
-	// 获取股票数据
-	stocks, _ := a.marketService.GetStockRealTimeData(req.StockCode)
-	var stock models.Stock
-	if len(stocks) > 0 {
-		stock = stocks[0]
-	}
+	stock := a.meetingStock(req.StockCode, session.StockName)
 
 	// 获取默认AI配置
 	config := a.configService.GetConfig()
@@ -830,41 +1390,185 @@ func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage
 		return []models.ChatMessage{}
 	}
 
+	// 主持人分析意图期间并行预热专家连接
+	a.warmupMeetingModels(aiConfig)
+
 	// 获取持仓信息
 	position := a.sessionService.GetPosition(req.StockCode)
 
 	// 判断是否为智能模式（无 @ 任何人）
 	if len(req.MentionIds) == 0 {
-		return a.runSmartMeeting(meetingCtx, req.StockCode, stock, req.Content, aiConfig, position)
+		return a.runSmartMeeting(meetingCtx, req, stock, aiConfig, position)
 	}
 
 	// 原有逻辑：@ 指定专家
 	return a.runDirectMeeting(meetingCtx, req, stock, aiConfig, position)
 }
 
+// meetingStock 获取会议使用的股票数据，获取失败按行情异常处理，避免专家拿到空行情分析
+func (a *App) meetingStock(stockCode, stockName string) models.Stock {
+	stocks, _ := a.marketService.GetStockRealTimeDataCached(a.ctx, stockCode)
+	if len(stocks) > 0 {
+		return stocks[0]
+	}
+	return models.Stock{
+		Symbol:        stockCode,
+		Name:          stockName,
+		Type:          models.ClassifySymbol(stockCode),
+		DataSuspect:   true,
+		SuspectReason: "行情获取失败",
+	}
+}
+
+// DryRunResult 会议预演结果
+type DryRunResult struct {
+	Plans []meeting.DryRunPlan `json:"plans"`
+	Error string               `json:"error,omitempty"`
+}
+
+// DryRunMeeting 会议预演（前端调用）：各专家只描述计划调用的工具与理由，不实际调用工具，便于开会前预估消耗
+// @ 了专家时只预演这些专家，否则预演全部启用的专家；预演结果不写入会话
+func (a *App) DryRunMeeting(req MeetingMessageRequest) DryRunResult {
+	session := a.sessionService.GetSession(req.StockCode)
+	if session == nil {
+		return DryRunResult{Error: "会话不存在: " + req.StockCode}
+	}
+	aiConfig := a.getDefaultAIConfig(a.configService.GetConfig())
+	if aiConfig == nil {
+		return DryRunResult{Error: "未配置 AI 服务"}
+	}
+
+	snapshot := a.agentContainer.Snapshot()
+	chatReq := meeting.ChatRequest{
+		StockCode:    req.StockCode,
+		Stock:        a.meetingStock(req.StockCode, session.StockName),
+		Query:        req.Content,
+		ReplyContent: req.ReplyContent,
+		AllAgents:    snapshot.Enabled(),
+		Position:     a.sessionService.GetPosition(req.StockCode),
+	}
+	if len(req.MentionIds) > 0 {
+		chatReq.Agents = snapshot.ByIDs(req.MentionIds)
+		if len(chatReq.Agents) == 0 {
+			return DryRunResult{Error: "专家不存在"}
+		}
+	}
+	if strategy := a.strategyService.GetActiveStrategy(); strategy != nil {
+		chatReq.AgentTimeout = time.Duration(strategy.AgentTimeoutSeconds) * time.Second
+	}
+
+	plans, err := a.meetingService.DryRunMeeting(a.ctx, aiConfig, chatReq)
+	if err != nil {
+		log.Warn("会议预演失败: %v", err)
+		return DryRunResult{Error: err.Error()}
+	}
+	return DryRunResult{Plans: plans}
+}
+
+// MeetingCostResult 会前消耗估算结果
+type MeetingCostResult struct {
+	Estimate *meeting.MeetingCostEstimate `json:"estimate,omitempty"`
+	Error    string                       `json:"error,omitempty"`
+}
+
+// EstimateMeetingCost 会前估算本次提问的 token 用量与费用（前端调用），与正式会议使用相同的专家、策略与可见性设置
+// 只按提示词拼装规则计算，不调用模型；@ 了专家时按直接提问估算
+func (a *App) EstimateMeetingCost(req MeetingMessageRequest) MeetingCostResult {
+	session := a.sessionService.GetSession(req.StockCode)
+	if session == nil {
+		return MeetingCostResult{Error: "会话不存在: " + req.StockCode}
+	}
+	aiConfig := a.getDefaultAIConfig(a.configService.GetConfig())
+	if aiConfig == nil {
+		return MeetingCostResult{Error: "未配置 AI 服务"}
+	}
+
+	snapshot := a.agentContainer.Snapshot()
+	chatReq := meeting.ChatRequest{
+		StockCode: req.StockCode,
+		Stock:     a.meetingStock(req.StockCode, session.StockName),
+		Query:     req.Content,
+		Images:    req.Images,
+		Position:  a.sessionService.GetPosition(req.StockCode),
+	}
+	if len(req.MentionIds) > 0 {
+		chatReq.Agents = snapshot.ByIDs(req.MentionIds)
+		if len(chatReq.Agents) == 0 {
+			return MeetingCostResult{Error: "专家不存在"}
+		}
+		chatReq.ReplyContent = req.ReplyContent
+	} else {
+		chatReq.AllAgents = snapshot.Enabled()
+		chatReq.Visibility = models.MeetingVisibility(req.Visibility)
+		chatReq.Mode = req.Mode
+		chatReq.ConcurrentMode = req.ConcurrentMode
+		if strategy := a.strategyService.GetActiveStrategy(); strategy != nil {
+			chatReq.ParallelRounds = strategy.ParallelRounds
+			chatReq.MaxRounds = strategy.MaxRounds
+		}
+	}
+
+	estimate, err := a.meetingService.EstimateMeetingCost(aiConfig, chatReq)
+	if err != nil {
+		return MeetingCostResult{Error: err.Error()}
+	}
+	return MeetingCostResult{Estimate: estimate}
+}
+
+// warmupMeetingModels 异步预热会议将用到的模型连接（未开启预热时为空操作）
+func (a *App) warmupMeetingModels(aiConfig *models.AIConfig) {
+	if aiConfig == nil || a.meetingService == nil || a.strategyService == nil {
+		return
+	}
+	agents := a.agentContainer.Snapshot().Enabled()
+	go a.meetingService.WarmupConnections(context.Background(), aiConfig, agents)
+}
+
 // runSmartMeeting 智能会议模式
-func (a *App) runSmartMeeting(ctx context.Context, stockCode string, stock models.Stock, query string, aiConfig *models.AIConfig, position *models.StockPosition) []models.ChatMessage {
-	allAgents := a.strategyService.GetEnabledAgents()
+func (a *App) runSmartMeeting(ctx context.Context, req MeetingMessageRequest, stock models.Stock, aiConfig *models.AIConfig, position *models.StockPosition) []models.ChatMessage {
+	stockCode := req.StockCode
+	// 会议全程使用开始时的专家快照，期间编辑专家不影响本场会议
+	allAgents := a.agentContainer.Snapshot().Enabled()
 	chatReq := meeting.ChatRequest{
-		StockCode: stockCode,
-		Stock:     stock,
-		Query:     query,
-		AllAgents: allAgents,
-		Position:  position,
+		StockCode:        stockCode,
+		Stock:            stock,
+		Query:            req.Content,
+		Images:           req.Images,
+		AllAgents:        allAgents,
+		Position:         position,
+		Visibility:       models.MeetingVisibility(req.Visibility),
+		AllowSuspectData: req.ForceSuspectData,
+		Mode:             req.Mode,
+		Reproducible:     req.Reproducible,
+		Seed:             req.Seed,
+		ConcurrentMode:   req.ConcurrentMode,
+	}
+	if strategy := a.strategyService.GetActiveStrategy(); strategy != nil {
+		chatReq.ParallelRounds = strategy.ParallelRounds
+		chatReq.MaxRounds = strategy.MaxRounds
+		chatReq.AgentTimeout = time.Duration(strategy.AgentTimeoutSeconds) * time.Second
 	}
 
 	// 响应回调：每次发言完成后推送
 	respCallback := func(resp meeting.ChatResponse) {
 		msg := models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
+			AgentID:      resp.AgentID,
+			AgentName:    resp.AgentName,
+			Role:         resp.Role,
+			Content:      resp.Content,
+			Round:        resp.Round,
+			MsgType:      resp.MsgType,
+			Error:        resp.Error,
+			MeetingMode:  resp.MeetingMode,
+			Reasoning:    resp.Reasoning,
+			ToolCalls:    resp.ToolCalls,
+			ToolResults:  resp.ToolResults,
+			FinishReason: resp.FinishReason,
+			RoundLabel:   resp.RoundLabel,
+			Rebuts:       resp.Rebuts,
+			AsOf:         resp.AsOf,
 		}
+		a.autoTranslate(stockCode, &msg)
 		a.sessionService.AddMessage(stockCode, msg)
 		runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
 	}
@@ -884,14 +1588,21 @@ func (a *App) runSmartMeeting(ctx context.Context, stockCode string, stock model
 	var messages []models.ChatMessage
 	for _, resp := range responses {
 		messages = append(messages, models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
+			AgentID:      resp.AgentID,
+			AgentName:    resp.AgentName,
+			Role:         resp.Role,
+			Content:      resp.Content,
+			Round:        resp.Round,
+			MsgType:      resp.MsgType,
+			Error:        resp.Error,
+			MeetingMode:  resp.MeetingMode,
+			Reasoning:    resp.Reasoning,
+			ToolCalls:    resp.ToolCalls,
+			ToolResults:  resp.ToolResults,
+			FinishReason: resp.FinishReason,
+			RoundLabel:   resp.RoundLabel,
+			Rebuts:       resp.Rebuts,
+			AsOf:         resp.AsOf,
 		})
 	}
 	return messages
@@ -899,20 +1610,32 @@ func (a *App) runSmartMeeting(ctx context.Context, stockCode string, stock model
 
 // runDirectMeeting 直接 @ 指定专家模式（带事件推送）
 func (a *App) runDirectMeeting(ctx context.Context, req MeetingMessageRequest, stock models.Stock, aiConfig *models.AIConfig, position *models.StockPosition) []models.ChatMessage {
-	agentConfigs := a.strategyService.GetAgentsByIDs(req.MentionIds)
+	agentConfigs := a.agentContainer.Snapshot().ByIDs(req.MentionIds)
 	if len(agentConfigs) == 0 {
 		return []models.ChatMessage{}
 	}
 
 	chatReq := meeting.ChatRequest{
-		Stock:        stock,
-		Agents:       agentConfigs,
-		Query:        req.Content,
-		ReplyContent: req.ReplyContent,
-		Position:     position,
+		Stock:            stock,
+		Agents:           agentConfigs,
+		Query:            req.Content,
+		Images:           req.Images,
+		ReplyContent:     req.ReplyContent,
+		Position:         position,
+		AllowSuspectData: req.ForceSuspectData,
+		Reproducible:     req.Reproducible,
+		Seed:             req.Seed,
+	}
+	if strategy := a.strategyService.GetActiveStrategy(); strategy != nil {
+		chatReq.AgentTimeout = time.Duration(strategy.AgentTimeoutSeconds) * time.Second
+	}
+
+	// 进度回调：与智能模式一致推送专家开始/结束与工具调用事件
+	progressCallback := func(event meeting.ProgressEvent) {
+		runtime.EventsEmit(a.ctx, "meeting:progress:"+req.StockCode, event)
 	}
 
-	responses, err := a.meetingService.SendMessage(ctx, aiConfig, chatReq)
+	responses, err := a.meetingService.SendMessageWithCallback(ctx, aiConfig, chatReq, progressCallback)
 	if err != nil {
 		log.Error("runDirectMeeting error: %v", err)
 		return []models.ChatMessage{}
@@ -922,34 +1645,416 @@ func (a *App) runDirectMeeting(ctx context.Context, req MeetingMessageRequest, s
 	return a.convertSaveAndEmitResponses(req.StockCode, responses, req.ReplyToId)
 }
 
+// SetAutoTranslate 设置该股票会话的专家回复自动翻译，target 为 zh/en，空字符串关闭
+// 开启后与目标语言不符的专家回复会先经记忆管理 LLM 翻译，原文保存在消息的 original 字段
+func (a *App) SetAutoTranslate(stockCode string, target string) string {
+	if target != "" && !meeting.ValidTranslateTarget(target) {
+		return fmt.Sprintf("不支持的翻译语言: %s", target)
+	}
+	if err := a.sessionService.SetAutoTranslate(stockCode, target); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// autoTranslate 会话开启自动翻译时翻译专家回复，失败时静默保留原文
+func (a *App) autoTranslate(stockCode string, msg *models.ChatMessage) {
+	if msg.AgentID == "user" || msg.Error != "" || msg.Content == "" {
+		return
+	}
+	target := a.sessionService.GetAutoTranslate(stockCode)
+	if target == "" {
+		return
+	}
+	fallback := a.getDefaultAIConfig(a.configService.GetConfig())
+	if translated, ok := a.meetingService.TranslateReply(a.ctx, fallback, stockCode, msg.Content, target); ok {
+		msg.Original = msg.Content
+		msg.Content = translated
+		msg.TranslatedTo = target
+	}
+}
+
 // convertSaveAndEmitResponses 转换响应、保存并推送事件（统一体验）
 func (a *App) convertSaveAndEmitResponses(stockCode string, responses []meeting.ChatResponse, replyTo string) []models.ChatMessage {
 	var messages []models.ChatMessage
 	for _, resp := range responses {
+		msg := models.ChatMessage{
+			AgentID:      resp.AgentID,
+			AgentName:    resp.AgentName,
+			Role:         resp.Role,
+			Content:      resp.Content,
+			ReplyTo:      replyTo,
+			Round:        resp.Round,
+			MsgType:      resp.MsgType,
+			Error:        resp.Error,
+			MeetingMode:  resp.MeetingMode,
+			Reasoning:    resp.Reasoning,
+			ToolCalls:    resp.ToolCalls,
+			ToolResults:  resp.ToolResults,
+			FinishReason: resp.FinishReason,
+			RoundLabel:   resp.RoundLabel,
+			Rebuts:       resp.Rebuts,
+			AsOf:         resp.AsOf,
+		}
+		// 保存单条消息
+		a.autoTranslate(stockCode, &msg)
+		a.sessionService.AddMessage(stockCode, msg)
+		// 推送事件（与智能模式一致）
+		runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// runScheduledMeeting 执行一场定时会议：与手动发起的智能会议流程一致，发言保存到会话并推送
+func (a *App) runScheduledMeeting(ctx context.Context, stock models.Stock, query string) (int, error) {
+	return a.runBackgroundMeeting(ctx, stock, query, "定时任务")
+}
+
+// runAlertFollowUp 提醒触发后以提醒内容为问题开一场会
+func (a *App) runAlertFollowUp(alert models.PriceAlert) {
+	name := alert.Name
+	if name == "" {
+		name = alert.Symbol
+	}
+	query := fmt.Sprintf("%s刚刚触发了%s的提醒，怎么看？", name, alert.Describe())
+	stock := models.Stock{Symbol: alert.Symbol, Name: name}
+	if _, err := a.runBackgroundMeeting(a.ctx, stock, query, "价格提醒"); err != nil {
+		log.Warn("提醒 %s 的 AI 跟进失败: %v", alert.ID, err)
+	}
+}
+
+// runBackgroundMeeting 在后台发起一场智能会议，sender 为会话中提问者的显示名
+// 该股票已有会议进行中时跳过，避免打断用户
+func (a *App) runBackgroundMeeting(ctx context.Context, stock models.Stock, query, sender string) (int, error) {
+	meetingCtx, cancel := context.WithCancel(ctx)
+	unregister, ok := a.tryRegisterMeetingCancel(stock.Symbol, cancel)
+	if !ok {
+		cancel()
+		return 0, errors.New("该股票正在开会，跳过本次会议")
+	}
+	defer unregister()
+
+	if _, err := a.sessionService.GetOrCreateSession(stock.Symbol, stock.Name); err != nil {
+		return 0, err
+	}
+	aiConfig := a.getDefaultAIConfig(a.configService.GetConfig())
+	if aiConfig == nil {
+		return 0, errors.New("未配置 AI 模型")
+	}
+	defer a.flushSessions()
+
+	userMsg := models.ChatMessage{
+		AgentID:   "user",
+		AgentName: sender,
+		Content:   query,
+	}
+	a.sessionService.AddMessage(stock.Symbol, userMsg)
+	runtime.EventsEmit(a.ctx, "meeting:message:"+stock.Symbol, userMsg)
+
+	// 获取失败按行情异常处理，与手动会议一致
+	realtime := models.Stock{
+		Symbol:        stock.Symbol,
+		Name:          stock.Name,
+		Type:          models.ClassifySymbol(stock.Symbol),
+		DataSuspect:   true,
+		SuspectReason: "行情获取失败",
+	}
+	if stocks, _ := a.marketService.GetStockRealTimeDataCached(ctx, stock.Symbol); len(stocks) > 0 {
+		realtime = stocks[0]
+	}
+
+	a.warmupMeetingModels(aiConfig)
+	req := MeetingMessageRequest{StockCode: stock.Symbol, Content: query}
+	messages := a.runSmartMeeting(meetingCtx, req, realtime, aiConfig, a.sessionService.GetPosition(stock.Symbol))
+	if len(messages) == 0 {
+		if err := meetingCtx.Err(); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("会议未产生发言")
+	}
+	return len(messages), nil
+}
+
+// RunWatchlistScan 自选股晨间扫描（前端调用）：小组专家基于批量汇总的行情、技术指标与快讯给出今日关注排名
+// 后台执行，过程消息写入专用扫描会话，完成后推送 scan:complete 事件；可通过 CancelMeeting("watchlist_scan") 取消
+func (a *App) RunWatchlistScan() string {
+	ctx, cancel := context.WithCancel(a.ctx)
+	unregister, ok := a.tryRegisterMeetingCancel(meeting.ScanSessionCode, cancel)
+	if !ok {
+		cancel()
+		return "晨间扫描正在进行中"
+	}
+	watchlist := a.configService.GetWatchlist()
+	if len(watchlist) == 0 {
+		unregister()
+		return "自选股为空"
+	}
+	aiConfig := a.getDefaultAIConfig(a.configService.GetConfig())
+	if aiConfig == nil {
+		unregister()
+		return "未配置 AI 模型"
+	}
+	if _, err := a.sessionService.GetOrCreateSession(meeting.ScanSessionCode, meeting.ScanSessionName); err != nil {
+		unregister()
+		return err.Error()
+	}
+
+	go func() {
+		defer a.flushSessions()
+		defer unregister()
+		result, err := a.runWatchlistScan(ctx, watchlist, aiConfig)
+		if err != nil {
+			log.Warn("晨间扫描失败: %v", err)
+			result = &meeting.ScanResult{Error: err.Error(), FinishedAt: time.Now().UnixMilli()}
+		}
+		runtime.EventsEmit(a.ctx, meeting.EventScanComplete, result)
+	}()
+	return "success"
+}
+
+// runWatchlistScan 汇总自选股数据并执行扫描会议
+func (a *App) runWatchlistScan(ctx context.Context, watchlist []models.Stock, aiConfig *models.AIConfig) (*meeting.ScanResult, error) {
+	code := meeting.ScanSessionCode
+	userMsg := models.ChatMessage{
+		AgentID:   "user",
+		AgentName: "老韭菜",
+		Content:   fmt.Sprintf("晨间扫描：从 %d 只自选股中找出今天最值得关注的标的", len(watchlist)),
+	}
+	a.sessionService.AddMessage(code, userMsg)
+	runtime.EventsEmit(a.ctx, "meeting:message:"+code, userMsg)
+
+	req := meeting.ScanRequest{
+		Items:  a.collectScanItems(ctx, watchlist),
+		Agents: a.agentContainer.Snapshot().Enabled(),
+	}
+	if strategy := a.strategyService.GetActiveStrategy(); strategy != nil {
+		req.AgentTimeout = time.Duration(strategy.AgentTimeoutSeconds) * time.Second
+	}
+
+	a.warmupMeetingModels(aiConfig)
+	return a.meetingService.RunWatchlistScan(ctx, aiConfig, req, func(resp meeting.ChatResponse) {
 		msg := models.ChatMessage{
 			AgentID:     resp.AgentID,
 			AgentName:   resp.AgentName,
 			Role:        resp.Role,
 			Content:     resp.Content,
-			ReplyTo:     replyTo,
 			Round:       resp.Round,
 			MsgType:     resp.MsgType,
 			Error:       resp.Error,
 			MeetingMode: resp.MeetingMode,
 		}
-		// 保存单条消息
-		a.sessionService.AddMessage(stockCode, msg)
-		// 推送事件（与智能模式一致）
-		runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
-		messages = append(messages, msg)
+		a.sessionService.AddMessage(code, msg)
+		runtime.EventsEmit(a.ctx, "meeting:message:"+code, msg)
+	}, func(event meeting.ProgressEvent) {
+		runtime.EventsEmit(a.ctx, "meeting:progress:"+code, event)
+	})
+}
+
+// StartResearchTask 提交深度研究任务（前端调用）：研究员多轮调用工具收集资料并撰写带引用的长篇报告
+// 任务排队后台执行（同时最多一个），进度推送 research:progress；完成后报告保存为会话附件，并在会话中追加摘要消息
+func (a *App) StartResearchTask(stockCode, focus string) string {
+	if models.IsIndexSymbol(stockCode) {
+		return "指数不支持深度研究"
 	}
-	return messages
+	session := a.sessionService.GetSession(stockCode)
+	if session == nil {
+		return "会话不存在: " + stockCode
+	}
+	if a.researchAIConfig() == nil {
+		return "未配置 AI 模型"
+	}
+	if _, err := a.researchQueue.Enqueue(stockCode, session.StockName, strings.TrimSpace(focus)); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// CancelResearchTask 取消排队或进行中的深度研究任务，已生成的部分报告仍会保存
+func (a *App) CancelResearchTask(taskID string) bool {
+	return a.researchQueue.Cancel(taskID)
+}
+
+// GetResearchTasks 获取深度研究任务列表，最近提交的在前
+func (a *App) GetResearchTasks() []meeting.ResearchTask {
+	return a.researchQueue.List()
+}
+
+// GetSessionAttachment 获取会话附件（如深度研究报告全文），不存在时返回 nil
+func (a *App) GetSessionAttachment(stockCode, attachmentID string) *models.SessionAttachment {
+	return a.sessionService.GetAttachment(stockCode, attachmentID)
+}
+
+// researchAIConfig 深度研究使用的 AI 配置，未指定时使用默认配置
+func (a *App) researchAIConfig() *models.AIConfig {
+	config := a.configService.GetConfig()
+	if aiConfig := a.getAIConfigByID(config.ResearchAIID); aiConfig != nil {
+		return aiConfig
+	}
+	return a.getDefaultAIConfig(config)
+}
+
+// runResearchTask 执行一个深度研究任务，报告保存为会话附件并追加摘要消息
+// 任务被取消时若已生成部分内容，同样保存并标注未完成
+func (a *App) runResearchTask(ctx context.Context, task meeting.ResearchTask, progress meeting.ProgressCallback) (meeting.ResearchOutcome, error) {
+	defer a.flushSessions()
+	aiConfig := a.researchAIConfig()
+	if aiConfig == nil {
+		return meeting.ResearchOutcome{}, errors.New("未配置 AI 模型")
+	}
+
+	// 获取失败按行情异常处理，与手动会议一致
+	stock := models.Stock{
+		Symbol:        task.StockCode,
+		Name:          task.StockName,
+		Type:          models.ClassifySymbol(task.StockCode),
+		DataSuspect:   true,
+		SuspectReason: "行情获取失败",
+	}
+	if stocks, _ := a.marketService.GetStockRealTimeDataCached(ctx, task.StockCode); len(stocks) > 0 {
+		stock = stocks[0]
+	}
+	budget := a.configService.GetConfig().MeetingBudget
+	result, err := a.meetingService.RunResearch(ctx, aiConfig, meeting.ResearchRequest{
+		Stock:        stock,
+		Focus:        task.Focus,
+		Position:     a.sessionService.GetPosition(task.StockCode),
+		MaxToolCalls: budget.ResearchMaxToolCalls,
+		MaxCost:      budget.ResearchMaxCost,
+	}, progress)
+	if err != nil {
+		return meeting.ResearchOutcome{}, err
+	}
+	outcome := meeting.ResearchOutcome{ToolCalls: result.ToolCalls, Cost: result.Cost}
+
+	title := task.StockName + " 深度研究报告"
+	if task.Focus != "" {
+		title += "：" + task.Focus
+	}
+	if ctx.Err() != nil {
+		title += "（未完成）"
+	}
+	attachmentID, err := a.sessionService.AddAttachment(task.StockCode, models.SessionAttachment{
+		Kind: "research", Title: title, Content: result.Report,
+	})
+	if err != nil {
+		return outcome, fmt.Errorf("保存研究报告失败: %w", err)
+	}
+	outcome.AttachmentID = attachmentID
+
+	note := fmt.Sprintf("完整报告已保存为附件「%s」，共调用工具 %d 次", title, result.ToolCalls)
+	if result.BudgetExhausted {
+		note += "，已达研究预算上限"
+	}
+	msg := models.ChatMessage{
+		AgentID:      meeting.ResearchAgentID,
+		AgentName:    meeting.ResearchAgentName,
+		Role:         "深度研究",
+		Content:      result.Summary + "\n\n" + note,
+		MsgType:      "research",
+		AttachmentID: attachmentID,
+	}
+	a.sessionService.AddMessage(task.StockCode, msg)
+	runtime.EventsEmit(a.ctx, "meeting:message:"+task.StockCode, msg)
+	return outcome, nil
+}
+
+// scanKLineConcurrency 晨间扫描并发拉取日K的上限
+const scanKLineConcurrency = 4
+
+// collectScanItems 批量获取自选股实时行情、日线指标与匹配的快讯
+// 行情缺失按异常处理，由扫描会议排除并注明
+func (a *App) collectScanItems(ctx context.Context, watchlist []models.Stock) []meeting.ScanItem {
+	codes := make([]string, len(watchlist))
+	for i, s := range watchlist {
+		codes[i] = s.Symbol
+	}
+	quotes := make(map[string]models.Stock, len(codes))
+	if stocks, err := a.marketService.GetStockRealTimeDataCached(ctx, codes...); err == nil {
+		for _, s := range stocks {
+			quotes[models.NormalizeSymbol(s.Symbol)] = s
+		}
+	} else {
+		log.Warn("晨间扫描获取行情失败: %v", err)
+	}
+	telegraphs, err := a.newsService.GetTelegraphList(ctx)
+	if err != nil {
+		log.Warn("晨间扫描获取快讯失败: %v", err)
+	}
+
+	items := make([]meeting.ScanItem, len(watchlist))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, scanKLineConcurrency)
+	for i, w := range watchlist {
+		stock, ok := quotes[models.NormalizeSymbol(w.Symbol)]
+		if !ok {
+			stock = models.Stock{
+				Symbol:        w.Symbol,
+				Name:          w.Name,
+				Type:          models.ClassifySymbol(w.Symbol),
+				DataSuspect:   true,
+				SuspectReason: "行情获取失败",
+			}
+		}
+		if stock.Name == "" {
+			stock.Name = w.Name
+		}
+		items[i].Stock = stock
+		for _, tg := range services.MatchTelegraphs(telegraphs, stock, 3) {
+			items[i].News = append(items[i].News, tg.Content)
+		}
+		if stock.DataSuspect {
+			continue
+		}
+		wg.Add(1)
+		go func(item *meeting.ScanItem) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			klines, err := a.marketService.GetKLineData(ctx, item.Stock.Symbol, "1d", 120)
+			if err != nil {
+				log.Warn("晨间扫描获取 %s 日K失败: %v", item.Stock.Symbol, err)
+				return
+			}
+			item.Indicators = indicators.Compute(klines)
+		}(&items[i])
+	}
+	wg.Wait()
+	return items
+}
+
+// GetSuggestedQueries 根据当前策略专家、持仓、市场状态与异动生成输入框下方的推荐问题
+// 纯模板拼装，不调用模型
+func (a *App) GetSuggestedQueries(stockCode string) []services.SuggestedQuery {
+	stock := models.Stock{Symbol: stockCode}
+	if stocks, err := a.marketService.GetStockRealTimeDataCached(a.ctx, stockCode); err == nil && len(stocks) > 0 {
+		stock = stocks[0]
+	} else if session := a.sessionService.GetSession(stockCode); session != nil {
+		stock.Name = session.StockName
+	}
+
+	symbol := models.NormalizeSymbol(stockCode)
+	var alerts []models.PriceAlert
+	for _, alert := range a.configService.GetAlerts() {
+		if alert.Symbol == symbol {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return services.BuildSuggestedQueries(services.SuggestionInput{
+		Stock:        stock,
+		Agents:       a.agentContainer.Snapshot().Enabled(),
+		Position:     a.sessionService.GetPosition(stockCode),
+		MarketStatus: a.marketService.GetMarketStatus().Status,
+		Alerts:       alerts,
+		Now:          time.Now(),
+	})
 }
 
 // RetryAgent 重试单个失败的专家（前端手动触发）
 func (a *App) RetryAgent(stockCode string, agentId string, query string) models.ChatMessage {
 	// 获取股票数据
-	stocks, _ := a.marketService.GetStockRealTimeData(stockCode)
+	stocks, _ := a.marketService.GetStockRealTimeDataCached(a.ctx, stockCode)
 	var stock models.Stock
 	if len(stocks) > 0 {
 		stock = stocks[0]
@@ -964,7 +2069,7 @@ func (a *App) RetryAgent(stockCode string, agentId string, query string) models.
 	}
 
 	// 获取专家配置
-	agents := a.strategyService.GetAgentsByIDs([]string{agentId})
+	agents := a.agentContainer.Snapshot().ByIDs([]string{agentId})
 	if len(agents) == 0 {
 		log.Warn("RetryAgent: agent not found: %s", agentId)
 		return models.ChatMessage{AgentID: agentId, Error: "专家不存在"}
@@ -978,17 +2083,28 @@ func (a *App) RetryAgent(stockCode string, agentId string, query string) models.
 		runtime.EventsEmit(a.ctx, "meeting:progress:"+stockCode, event)
 	}
 
-	resp, err := a.meetingService.RetrySingleAgent(a.ctx, aiConfig, &agentCfg, &stock, query, progressCallback, position)
+	var timeout time.Duration
+	if strategy := a.strategyService.GetActiveStrategy(); strategy != nil {
+		timeout = time.Duration(strategy.AgentTimeoutSeconds) * time.Second
+	}
+	resp, err := a.meetingService.RetrySingleAgent(a.ctx, aiConfig, &agentCfg, &stock, query, progressCallback, position, timeout)
 
 	msg := models.ChatMessage{
-		AgentID:     resp.AgentID,
-		AgentName:   resp.AgentName,
-		Role:        resp.Role,
-		Content:     resp.Content,
-		Round:       resp.Round,
-		MsgType:     resp.MsgType,
-		Error:       resp.Error,
-		MeetingMode: resp.MeetingMode,
+		AgentID:      resp.AgentID,
+		AgentName:    resp.AgentName,
+		Role:         resp.Role,
+		Content:      resp.Content,
+		Round:        resp.Round,
+		MsgType:      resp.MsgType,
+		Error:        resp.Error,
+		MeetingMode:  resp.MeetingMode,
+		Reasoning:    resp.Reasoning,
+		ToolCalls:    resp.ToolCalls,
+		ToolResults:  resp.ToolResults,
+		FinishReason: resp.FinishReason,
+		RoundLabel:   resp.RoundLabel,
+		Rebuts:       resp.Rebuts,
+		AsOf:         resp.AsOf,
 	}
 
 	if err != nil {
@@ -998,7 +2114,9 @@ func (a *App) RetryAgent(stockCode string, agentId string, query string) models.
 	}
 
 	// 成功：保存并推送
+	a.autoTranslate(stockCode, &msg)
 	a.sessionService.AddMessage(stockCode, msg)
+	a.flushSessions()
 	runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
 	return msg
 }
@@ -1009,31 +2127,42 @@ func (a *App) RetryAgentAndContinue(stockCode string) []models.ChatMessage {
 		log.Warn("RetryAgentAndContinue: no interrupted meeting for %s", stockCode)
 		return []models.ChatMessage{}
 	}
+	return a.continueMeeting(stockCode, a.meetingService.ContinueMeeting)
+}
+
+// ResumeMeeting 继续被取消的会议：已发言的专家不再重复发言，轮次与原会议一致
+func (a *App) ResumeMeeting(stockCode string) []models.ChatMessage {
+	// 取消后会议可能尚在退出，进度由 meetingService.ResumeMeeting 等待写入后再判断
+	return a.continueMeeting(stockCode, a.meetingService.ResumeMeeting)
+}
 
+// continueMeeting 以可取消的方式继续中断的会议，发言实时保存并推送
+func (a *App) continueMeeting(stockCode string, run func(context.Context, string, meeting.ResponseCallback, meeting.ProgressCallback) ([]meeting.ChatResponse, error)) []models.ChatMessage {
 	// 创建可取消的 context
 	meetingCtx, cancel := context.WithCancel(a.ctx)
-	a.meetingCancelsMu.Lock()
-	a.meetingCancels[stockCode] = cancel
-	a.meetingCancelsMu.Unlock()
-
-	defer func() {
-		a.meetingCancelsMu.Lock()
-		delete(a.meetingCancels, stockCode)
-		a.meetingCancelsMu.Unlock()
-	}()
+	defer a.flushSessions()
+	defer a.registerMeetingCancel(stockCode, cancel)()
 
 	// 响应回调
 	respCallback := func(resp meeting.ChatResponse) {
 		msg := models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
+			AgentID:      resp.AgentID,
+			AgentName:    resp.AgentName,
+			Role:         resp.Role,
+			Content:      resp.Content,
+			Round:        resp.Round,
+			MsgType:      resp.MsgType,
+			Error:        resp.Error,
+			MeetingMode:  resp.MeetingMode,
+			Reasoning:    resp.Reasoning,
+			ToolCalls:    resp.ToolCalls,
+			ToolResults:  resp.ToolResults,
+			FinishReason: resp.FinishReason,
+			RoundLabel:   resp.RoundLabel,
+			Rebuts:       resp.Rebuts,
+			AsOf:         resp.AsOf,
 		}
+		a.autoTranslate(stockCode, &msg)
 		a.sessionService.AddMessage(stockCode, msg)
 		runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
 	}
@@ -1043,23 +2172,30 @@ func (a *App) RetryAgentAndContinue(stockCode string) []models.ChatMessage {
 		runtime.EventsEmit(a.ctx, "meeting:progress:"+stockCode, event)
 	}
 
-	responses, err := a.meetingService.ContinueMeeting(meetingCtx, stockCode, respCallback, progressCallback)
+	responses, err := run(meetingCtx, stockCode, respCallback, progressCallback)
 	if err != nil {
-		log.Error("RetryAgentAndContinue error: %v", err)
+		log.Error("continue meeting error: %v", err)
 		return []models.ChatMessage{}
 	}
 
 	var messages []models.ChatMessage
 	for _, resp := range responses {
 		messages = append(messages, models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
+			AgentID:      resp.AgentID,
+			AgentName:    resp.AgentName,
+			Role:         resp.Role,
+			Content:      resp.Content,
+			Round:        resp.Round,
+			MsgType:      resp.MsgType,
+			Error:        resp.Error,
+			MeetingMode:  resp.MeetingMode,
+			Reasoning:    resp.Reasoning,
+			ToolCalls:    resp.ToolCalls,
+			ToolResults:  resp.ToolResults,
+			FinishReason: resp.FinishReason,
+			RoundLabel:   resp.RoundLabel,
+			Rebuts:       resp.Rebuts,
+			AsOf:         resp.AsOf,
 		})
 	}
 	return messages
@@ -1071,11 +2207,26 @@ func (a *App) CancelInterruptedMeeting(stockCode string) bool {
 	return true
 }
 
+// GetMeetingUsage 获取该股票最近一场会议的 token 用量与估算费用
+func (a *App) GetMeetingUsage(stockCode string) meeting.MeetingUsage {
+	return a.meetingService.GetMeetingUsage(stockCode)
+}
+
+// GetMeetingDigest 获取会议的纯文本摘要（一行一位专家），runID 为空时返回最近一场
+func (a *App) GetMeetingDigest(stockCode string, runID string) string {
+	return a.meetingService.GetMeetingDigest(stockCode, runID)
+}
+
+// GetMeetingRuns 获取该股票最近几场会议的运行记录（各发言者实际生效的模型、温度、种子），最近的在前
+func (a *App) GetMeetingRuns(stockCode string) []models.MeetingRun {
+	return a.sessionService.GetMeetingRuns(stockCode)
+}
+
 // ========== News API ==========
 
 // GetTelegraphList 获取快讯列表
 func (a *App) GetTelegraphList() []services.Telegraph {
-	telegraphs, err := a.newsService.GetTelegraphList()
+	telegraphs, err := a.newsService.GetTelegraphList(a.ctx)
 	if err != nil {
 		return []services.Telegraph{}
 	}
@@ -1166,8 +2317,35 @@ func (a *App) TestMCPConnection(serverID string) *mcp.ServerStatus {
 	return a.mcpManager.TestConnection(serverID)
 }
 
+// ProviderModelsResult 模型列表结果
+type ProviderModelsResult struct {
+	Models []adk.ModelInfo `json:"models"`
+	Error  string          `json:"error"`
+}
+
+// ListProviderModels 拉取 AI 配置对应服务商的可用模型列表（供设置页下拉选择）
+func (a *App) ListProviderModels(configID string) ProviderModelsResult {
+	var aiConfig *models.AIConfig
+	config := a.configService.GetConfig()
+	for i := range config.AIConfigs {
+		if config.AIConfigs[i].ID == configID {
+			aiConfig = &config.AIConfigs[i]
+			break
+		}
+	}
+	if aiConfig == nil {
+		return ProviderModelsResult{Models: []adk.ModelInfo{}, Error: "AI 配置不存在"}
+	}
+	list, err := adk.NewModelFactory().ListModels(context.Background(), aiConfig)
+	if err != nil {
+		log.Warn("获取模型列表失败 [%s]: %v", aiConfig.Name, err)
+		return ProviderModelsResult{Models: []adk.ModelInfo{}, Error: err.Error()}
+	}
+	return ProviderModelsResult{Models: list}
+}
+
 // TestAIConnection 测试 AI 配置连通性
-// 连接成功后自动检测是否支持 system role，并持久化结果
+// 连接成功后自动检测是否支持 system role 及可选参数能力，并持久化结果
 func (a *App) TestAIConnection(config models.AIConfig) string {
 	factory := adk.NewModelFactory()
 	ctx := context.Background()
@@ -1196,10 +2374,30 @@ func (a *App) TestAIConnection(config models.AIConfig) string {
 		}
 	}
 
+	// 探测可选参数能力，结果覆盖 Provider 默认值
+	for c, supported := range factory.ProbeCapabilities(ctx, &config) {
+		if err := a.configService.SetAICapability(config.ID, string(c), supported); err != nil {
+			log.Warn("保存能力探测结果失败: %v", err)
+			break
+		}
+	}
+
 	return "success"
 }
 
-// GetMCPServerTools 获取指定 MCP 服务器的工具列表
+// GetAICapabilities 获取 AI 配置生效的能力矩阵（Provider 默认值叠加已学习的结果）
+// 已保存的同 ID 配置以保存的学习结果为准，编辑中未保存的配置按传入值计算
+func (a *App) GetAICapabilities(config models.AIConfig) []capability.Info {
+	for _, saved := range a.configService.GetConfig().AIConfigs {
+		if saved.ID == config.ID && saved.Provider == config.Provider {
+			config.Capabilities = saved.Capabilities
+			break
+		}
+	}
+	return capability.Describe(&config)
+}
+
+// GetMCPServerTools 获取指定 MCP 服务器的工具列表（来自工具目录缓存，过期项 stale=true）
 func (a *App) GetMCPServerTools(serverID string) []mcp.ToolInfo {
 	tools, err := a.mcpManager.GetServerTools(serverID)
 	if err != nil {
@@ -1208,6 +2406,14 @@ func (a *App) GetMCPServerTools(serverID string) []mcp.ToolInfo {
 	return tools
 }
 
+// RefreshMCPToolCatalog 立即刷新 MCP 工具目录缓存，serverID 为空时刷新全部
+func (a *App) RefreshMCPToolCatalog(serverID string) string {
+	if err := a.mcpManager.RefreshToolCatalog(serverID); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
 // ========== Window Control API ==========
 
 // WindowMinimize 最小化窗口
@@ -1309,12 +2515,30 @@ func (a *App) GetTradingSchedule() *services.TradingSchedule {
 	return &schedule
 }
 
+// FlushCaches 手动清空全部行情、龙虎榜、财务摘要缓存（排查数据不刷新问题时使用），返回清空的缓存名
+func (a *App) FlushCaches() []string {
+	if a.cacheEpochs == nil {
+		return []string{}
+	}
+	return a.cacheEpochs.FlushAll()
+}
+
+// GetLimitBoardStats 获取涨跌停板统计（涨停/跌停列表、连板高度、炸板率），date 为空取最近交易日
+func (a *App) GetLimitBoardStats(date string) *models.LimitBoardStats {
+	stats, err := a.marketService.GetLimitBoardStats(a.ctx, date)
+	if err != nil {
+		log.Error("获取涨跌停统计失败: %v", err)
+		return nil
+	}
+	return stats
+}
+
 // GetLongHuBangList 获取龙虎榜列表
 func (a *App) GetLongHuBangList(pageSize, pageNumber int, tradeDate string) *services.LongHuBangListResult {
 	if a.longHuBangService == nil {
 		return nil
 	}
-	result, err := a.longHuBangService.GetLongHuBangList(pageSize, pageNumber, tradeDate)
+	result, err := a.longHuBangService.GetLongHuBangList(a.ctx, pageSize, pageNumber, tradeDate)
 	if err != nil {
 		log.Error("获取龙虎榜失败: %v", err)
 		return nil
@@ -1327,7 +2551,7 @@ func (a *App) GetLongHuBangDetail(code, tradeDate string) []models.LongHuBangDet
 	if a.longHuBangService == nil {
 		return nil
 	}
-	details, err := a.longHuBangService.GetStockDetail(code, tradeDate)
+	details, err := a.longHuBangService.GetStockDetail(a.ctx, code, tradeDate)
 	if err != nil {
 		log.Error("获取龙虎榜明细失败: %v", err)
 		return nil
@@ -1341,3 +2565,289 @@ func (a *App) NotifyFrontendReady() {
 		a.marketPusher.SetReady()
 	}
 }
+
+// ========== Export API ==========
+
+// exportLongHuBangPageSize 龙虎榜导出分页大小
+const exportLongHuBangPageSize = 50
+
+// ExportProgressEvent 导出进度事件（通过 export:progress 推送）
+type ExportProgressEvent struct {
+	Kind  string `json:"kind"`  // kline / positions / longhubang
+	Stage string `json:"stage"` // fetch / write / done
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+}
+
+// emitExportProgress 推送导出进度
+func (a *App) emitExportProgress(kind, stage string, done, total int) {
+	runtime.EventsEmit(a.ctx, "export:progress", ExportProgressEvent{Kind: kind, Stage: stage, Done: done, Total: total})
+}
+
+// resolveExportPath 确定导出路径，path 为空时弹出保存对话框
+// 返回空路径表示用户取消
+func (a *App) resolveExportPath(path, defaultName string) (string, error) {
+	if path = strings.TrimSpace(path); path != "" {
+		return path, nil
+	}
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:                "导出 CSV",
+		DefaultFilename:      defaultName,
+		CanCreateDirectories: true,
+		Filters:              []runtime.FileFilter{{DisplayName: "CSV 文件 (*.csv)", Pattern: "*.csv"}},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if !strings.HasSuffix(strings.ToLower(path), ".csv") {
+		path += ".csv"
+	}
+	return path, nil
+}
+
+// writeExport 写出 CSV 并推送进度
+func (a *App) writeExport(kind, path string, header []string, rows [][]string) string {
+	err := services.WriteCSVFile(path, header, rows, func(done, total int) {
+		a.emitExportProgress(kind, "write", done, total)
+	})
+	if err != nil {
+		log.Error("导出%s失败: %v", kind, err)
+		return err.Error()
+	}
+	a.emitExportProgress(kind, "done", len(rows), len(rows))
+	return "success"
+}
+
+// ExportKLineCSV 导出K线数据，path 为空时弹出保存对话框
+// 返回 success / cancelled / 错误信息
+func (a *App) ExportKLineCSV(code, period string, days int, path string) string {
+	path, err := a.resolveExportPath(path, fmt.Sprintf("%s_%s_%s.csv", code, period, time.Now().Format("20060102")))
+	if err != nil {
+		return err.Error()
+	}
+	if path == "" {
+		return "cancelled"
+	}
+	a.emitExportProgress("kline", "fetch", 0, 0)
+	klines, err := a.marketService.GetKLineData(a.ctx, code, period, days)
+	if err != nil {
+		return fmt.Sprintf("获取K线数据失败: %v", err)
+	}
+	return a.writeExport("kline", path, services.KLineCSVHeader, services.KLineCSVRows(klines))
+}
+
+// ExportPositionsCSV 导出全部持仓（附带实时价格与盈亏），path 为空时弹出保存对话框
+func (a *App) ExportPositionsCSV(path string) string {
+	path, err := a.resolveExportPath(path, fmt.Sprintf("positions_%s.csv", time.Now().Format("20060102")))
+	if err != nil {
+		return err.Error()
+	}
+	if path == "" {
+		return "cancelled"
+	}
+
+	records := a.sessionService.ListPositions()
+	codes := make([]string, 0, len(records))
+	for _, r := range records {
+		codes = append(codes, r.Code)
+	}
+
+	// 实时价格获取失败不影响导出，市值与盈亏列留空
+	a.emitExportProgress("positions", "fetch", 0, len(records))
+	if quotes, err := a.marketService.GetStockRealTimeData(a.ctx, codes...); err == nil {
+		prices := make(map[string]float64, len(quotes))
+		for _, q := range quotes {
+			prices[q.Symbol] = q.Price
+		}
+		for i := range records {
+			records[i].Price = prices[records[i].Code]
+		}
+	} else if len(codes) > 0 {
+		log.Warn("导出持仓获取实时价格失败: %v", err)
+	}
+	return a.writeExport("positions", path, services.PositionCSVHeader, services.PositionCSVRows(records))
+}
+
+// ExportLongHuBangCSV 导出指定交易日的完整龙虎榜（自动翻页），path 为空时弹出保存对话框
+func (a *App) ExportLongHuBangCSV(tradeDate, path string) string {
+	if a.longHuBangService == nil {
+		return "龙虎榜服务未初始化"
+	}
+	name := tradeDate
+	if name == "" {
+		name = time.Now().Format("20060102")
+	}
+	path, err := a.resolveExportPath(path, fmt.Sprintf("longhubang_%s.csv", strings.ReplaceAll(name, "-", "")))
+	if err != nil {
+		return err.Error()
+	}
+	if path == "" {
+		return "cancelled"
+	}
+
+	var items []models.LongHuBangItem
+	for page := 1; ; page++ {
+		result, err := a.longHuBangService.GetLongHuBangList(a.ctx, exportLongHuBangPageSize, page, tradeDate)
+		if err != nil {
+			return fmt.Sprintf("获取龙虎榜失败: %v", err)
+		}
+		items = append(items, result.Items...)
+		a.emitExportProgress("longhubang", "fetch", len(items), result.Total)
+		if len(result.Items) < exportLongHuBangPageSize || len(items) >= result.Total {
+			break
+		}
+	}
+	return a.writeExport("longhubang", path, services.LongHuBangCSVHeader, services.LongHuBangCSVRows(items))
+}
+
+// TranscriptExportResult 会议记录导出结果
+type TranscriptExportResult struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// TranscriptExportedEvent 会议记录导出完成事件（通过 export:transcript 推送）
+type TranscriptExportedEvent struct {
+	StockCode string `json:"stockCode"`
+	Format    string `json:"format"`
+	Path      string `json:"path"`
+}
+
+// transcriptMeta 会议记录页眉信息，会话不存在或没有消息时返回 ErrEmptyTranscript
+func (a *App) transcriptMeta(stockCode string) (services.TranscriptMeta, []models.ChatMessage, error) {
+	session := a.sessionService.GetSession(stockCode)
+	messages := a.sessionService.GetMessages(stockCode)
+	if session == nil || len(messages) == 0 {
+		return services.TranscriptMeta{}, nil, services.ErrEmptyTranscript
+	}
+	meta := services.TranscriptMeta{
+		StockCode:  stockCode,
+		StockName:  session.StockName,
+		ExportedAt: time.Now(),
+	}
+	if strategy := a.strategyService.GetActiveStrategy(); strategy != nil {
+		meta.StrategyName = strategy.Name
+	}
+	return meta, messages, nil
+}
+
+// GetMeetingTranscript 渲染股票会话的讨论记录，format 为 markdown、html 或 pdf（可打印的 HTML）
+// 失败时返回 nil
+func (a *App) GetMeetingTranscript(stockCode, format string) []byte {
+	meta, messages, err := a.transcriptMeta(stockCode)
+	if err != nil {
+		return nil
+	}
+	content, _, err := services.NewTranscriptExporter(meta, messages).Export(format)
+	if err != nil {
+		log.Warn("渲染会议记录失败: %v", err)
+		return nil
+	}
+	return content
+}
+
+// ExportMeetingTranscript 导出股票会话的讨论记录到数据目录 exports 下
+// format 为 markdown 或 html（可在浏览器中打印为 PDF）
+func (a *App) ExportMeetingTranscript(stockCode, format string) TranscriptExportResult {
+	format = services.NormalizeTranscriptFormat(format)
+	if format == "" {
+		return TranscriptExportResult{Error: "不支持的导出格式"}
+	}
+	meta, messages, err := a.transcriptMeta(stockCode)
+	if err != nil {
+		return TranscriptExportResult{Error: err.Error()}
+	}
+
+	path, err := services.WriteTranscriptFile(paths.GetExportDir(), format, meta, messages)
+	if err != nil {
+		log.Error("导出会议记录失败: %v", err)
+		return TranscriptExportResult{Error: err.Error()}
+	}
+	log.Info("会议记录已导出: %s", path)
+	runtime.EventsEmit(a.ctx, "export:transcript", TranscriptExportedEvent{StockCode: stockCode, Format: format, Path: path})
+	return TranscriptExportResult{Path: path}
+}
+
+// WatchlistImportResult 自选股导入结果
+type WatchlistImportResult struct {
+	Imported  int    `json:"imported"`
+	Skipped   int    `json:"skipped"` // 已在自选股中的代码
+	Invalid   int    `json:"invalid"` // 不在股票索引中的代码
+	Cancelled bool   `json:"cancelled"`
+	Error     string `json:"error"`
+}
+
+// watchlistFileFilters 自选股导入导出的文件类型
+var watchlistFileFilters = []runtime.FileFilter{
+	{DisplayName: "自选股文件 (*.csv;*.json)", Pattern: "*.csv;*.json"},
+}
+
+// watchlistImportFilters 自选股导入支持的文件类型，含同花顺、通达信导出文件
+var watchlistImportFilters = []runtime.FileFilter{
+	{DisplayName: "自选股文件 (*.csv;*.json;*.txt;*.xls;*.ebk)", Pattern: "*.csv;*.json;*.txt;*.xls;*.ebk"},
+}
+
+// ImportWatchlist 批量导入自选股，path 为空时弹出打开对话框
+// format 支持 csv（symbol,name）、json、ths（同花顺导出）、tdx（通达信 EBK），为空时按扩展名判断
+func (a *App) ImportWatchlist(path, format string) WatchlistImportResult {
+	if path = strings.TrimSpace(path); path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:   "导入自选股",
+			Filters: watchlistImportFilters,
+		})
+		if err != nil {
+			return WatchlistImportResult{Error: err.Error()}
+		}
+		if path == "" {
+			return WatchlistImportResult{Cancelled: true}
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return WatchlistImportResult{Error: fmt.Sprintf("读取文件失败: %v", err)}
+	}
+	if format = strings.TrimSpace(format); format == "" {
+		format = services.DetectWatchlistFormat(path)
+	}
+	report, err := a.configService.ImportWatchlist(data, format)
+	result := WatchlistImportResult{Imported: report.Imported, Skipped: report.Skipped, Invalid: report.Invalid}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	// 同步推送订阅（已订阅的代码会被忽略）
+	for _, s := range a.configService.GetWatchlist() {
+		a.marketPusher.AddSubscription(s.Symbol)
+	}
+	log.Info("导入自选股: 新增 %d, 已存在 %d, 无效 %d", report.Imported, report.Skipped, report.Invalid)
+	return result
+}
+
+// ExportWatchlist 导出自选股为 csv 或 json，path 为空时弹出保存对话框
+// 返回 success / cancelled / 错误信息
+func (a *App) ExportWatchlist(format, path string) string {
+	format = strings.ToLower(format)
+	data, err := a.configService.ExportWatchlist(format)
+	if err != nil {
+		return err.Error()
+	}
+	if path = strings.TrimSpace(path); path == "" {
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:                "导出自选股",
+			DefaultFilename:      fmt.Sprintf("watchlist_%s.%s", time.Now().Format("20060102"), format),
+			CanCreateDirectories: true,
+			Filters:              watchlistFileFilters,
+		})
+		if err != nil {
+			return err.Error()
+		}
+		if path == "" {
+			return "cancelled"
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Sprintf("写入文件失败: %v", err)
+	}
+	return "success"
+}
@@ -7,19 +7,21 @@ import { SettingsDialog } from './components/SettingsDialog';
 import { PositionDialog } from './components/PositionDialog';
 import { HotTrendDialog } from './components/HotTrendDialog';
 import { LongHuBangDialog } from './components/LongHuBangDialog';
+import { LimitBoardDialog } from './components/LimitBoardDialog';
 import { WelcomePage } from './components/WelcomePage';
 import { ThemeSwitcher } from './components/ThemeSwitcher';
 import { useTheme } from './contexts/ThemeContext';
 import { useCandleColor } from './contexts/CandleColorContext';
 import { ResizeHandle } from './components/ResizeHandle';
-import { getWatchlist, addToWatchlist, removeFromWatchlist } from './services/watchlistService';
+import { getWatchlist, addToWatchlist, removeFromWatchlist, getWatchlistGroups, addStockToGroup, removeStockFromGroup, deleteWatchlistGroup, DEFAULT_WATCHLIST_GROUP_ID } from './services/watchlistService';
 import { getKLineData, getOrderBook } from './services/stockService';
 import { getOrCreateSession, StockSession, updateStockPosition } from './services/sessionService';
-import { getConfig, updateConfig } from './services/configService';
-import { useMarketEvents } from './hooks/useMarketEvents';
+import { getConfig, updateConfig, onAlertTriggered, describeAlert } from './services/configService';
+import { useMarketEvents, type FocusStockUpdate } from './hooks/useMarketEvents';
 import { useMarketStatus } from './hooks/useMarketStatus';
-import { Stock, KLineData, OrderBook, TimePeriod, Telegraph, MarketIndex } from './types';
-import { Radio, Settings, List, Minus, Square, X, Copy, Briefcase, TrendingUp, BarChart3 } from 'lucide-react';
+import { isIndexStock } from './utils/symbol';
+import { Stock, KLineData, OrderBook, TimePeriod, Telegraph, MarketIndex, WatchlistGroup } from './types';
+import { Radio, Settings, List, Minus, Square, X, Copy, Briefcase, TrendingUp, BarChart3, Flame } from 'lucide-react';
 import logo from './assets/images/logo.png';
 import { GetTelegraphList, OpenURL, WindowMinimize, WindowMaximize, WindowClose } from '../wailsjs/go/main/App';
 import { WindowIsMaximised, WindowSetSize, WindowGetSize } from '../wailsjs/runtime/runtime';
@@ -47,6 +49,7 @@ const App: React.FC = () => {
   const { colors } = useTheme();
   const cc = useCandleColor();
   const [watchlist, setWatchlist] = useState<Stock[]>([]);
+  const [watchlistGroups, setWatchlistGroups] = useState<WatchlistGroup[]>([]);
   const [selectedSymbol, setSelectedSymbol] = useState<string>('');
   const [currentSession, setCurrentSession] = useState<StockSession | null>(null);
   const [timePeriod, setTimePeriod] = useState<TimePeriod>('1m');
@@ -62,6 +65,7 @@ const App: React.FC = () => {
   const [showPosition, setShowPosition] = useState(false);
   const [showHotTrend, setShowHotTrend] = useState(false);
   const [showLongHuBang, setShowLongHuBang] = useState(false);
+  const [showLimitBoard, setShowLimitBoard] = useState(false);
   const [marketIndices, setMarketIndices] = useState<MarketIndex[]>([]);
   const [isMaximized, setIsMaximized] = useState(false);
   const klineRequestIdRef = useRef(0);
@@ -96,6 +100,13 @@ const App: React.FC = () => {
     setOrderBook(data);
   }, []);
 
+  // 处理聚焦股票的快速推送（行情 + 盘口）
+  const handleFocusUpdate = useCallback((data: FocusStockUpdate) => {
+    const { orderBook: focusOrderBook, ...stock } = data;
+    setOrderBook(focusOrderBook);
+    setWatchlist(prev => prev.map(s => (s.symbol === stock.symbol ? { ...s, ...stock } : s)));
+  }, []);
+
   // 处理快讯数据更新（来自后端推送）
   const handleTelegraphUpdate = useCallback((data: Telegraph) => {
     if (data && data.content) {
@@ -257,24 +268,34 @@ const App: React.FC = () => {
   };
 
   // 使用市场事件 Hook
-  const { subscribeOrderBook, subscribeKLine } = useMarketEvents({
+  const { focusStock, subscribeKLine } = useMarketEvents({
     onStockUpdate: handleStockUpdate,
     onOrderBookUpdate: handleOrderBookUpdate,
     onTelegraphUpdate: handleTelegraphUpdate,
     onMarketIndicesUpdate: handleMarketIndicesUpdate,
     onKLineUpdate: handleKLineUpdate,
+    onFocusUpdate: handleFocusUpdate,
   });
 
   // Handle Adding Stock
   const handleAddStock = async (newStock: Stock) => {
     if (!watchlist.find(s => s.symbol === newStock.symbol)) {
       await addToWatchlist(newStock);
-      setWatchlist(prev => [...prev, newStock]);
+      // 新股票进入默认分组末尾，与后端排列保持一致
+      setWatchlist(prev => {
+        const stock = { ...newStock, groupId: DEFAULT_WATCHLIST_GROUP_ID };
+        let insertAt = 0;
+        prev.forEach((s, i) => { if (s.groupId === DEFAULT_WATCHLIST_GROUP_ID) insertAt = i + 1; });
+        return [...prev.slice(0, insertAt), stock, ...prev.slice(insertAt)];
+      });
+      setWatchlistGroups(prev => prev.map(g =>
+        g.id === DEFAULT_WATCHLIST_GROUP_ID ? { ...g, symbols: [...(g.symbols || []), newStock.symbol] } : g
+      ));
       // 添加后自动选中新股票并加载数据
       setSelectedSymbol(newStock.symbol);
       // 先清空 session，避免显示旧股票的消息
       setCurrentSession(null);
-      subscribeOrderBook(newStock.symbol);
+      focusStock(newStock.symbol);
       // 加载 Session 和盘口数据
       const [session, orderBookData] = await Promise.all([
         getOrCreateSession(newStock.symbol, newStock.name),
@@ -289,6 +310,7 @@ const App: React.FC = () => {
   const handleRemoveStock = async (symbol: string) => {
     await removeFromWatchlist(symbol);
     setWatchlist(prev => prev.filter(s => s.symbol !== symbol));
+    setWatchlistGroups(prev => prev.map(g => ({ ...g, symbols: (g.symbols || []).filter(s => s !== symbol) })));
     // 如果删除的是当前选中的股票，切换到第一个
     if (symbol === selectedSymbol) {
       const remaining = watchlist.filter(s => s.symbol !== symbol);
@@ -298,11 +320,38 @@ const App: React.FC = () => {
     }
   };
 
+  // 分组变更后重新加载分组与自选股（主分组与排列可能随之变化）
+  const reloadWatchlistGroups = async () => {
+    const [list, groups] = await Promise.all([getWatchlist(), getWatchlistGroups()]);
+    setWatchlist(list);
+    setWatchlistGroups(groups);
+  };
+
+  // 加入或移出分组，一只股票可同时属于多个分组
+  const handleToggleGroup = async (symbol: string, groupId: string, member: boolean) => {
+    const result = member ? await addStockToGroup(symbol, groupId) : await removeStockFromGroup(symbol, groupId);
+    if (result !== 'success') {
+      console.error('Failed to update watchlist group:', result);
+      return;
+    }
+    await reloadWatchlistGroups();
+  };
+
+  // 删除分组，其中的自选股保留
+  const handleDeleteGroup = async (groupId: string) => {
+    const result = await deleteWatchlistGroup(groupId);
+    if (result !== 'success') {
+      console.error('Failed to delete watchlist group:', result);
+      return;
+    }
+    await reloadWatchlistGroups();
+  };
+
   // Handle Stock Selection - Load Session and sync data
   const handleSelectStock = async (symbol: string) => {
     setSelectedSymbol(symbol);
-    // 订阅该股票的盘口推送
-    subscribeOrderBook(symbol);
+    // 聚焦该股票，由后端快速推送盘口
+    focusStock(symbol);
     const stock = watchlist.find(s => s.symbol === symbol);
     if (stock) {
       // 并行加载 Session 和盘口数据
@@ -315,6 +364,23 @@ const App: React.FC = () => {
     }
   };
 
+  // 价格提醒触发时发送桌面通知（首次触发时申请通知权限）
+  useEffect(() => {
+    return onAlertTriggered(alert => {
+      if (typeof Notification === 'undefined') return;
+      const notify = () => {
+        const body = `${alert.name || alert.symbol} ${describeAlert(alert)}，现价 ${alert.triggerPrice.toFixed(2)}`
+          + (alert.aiFollowUp ? '，AI 正在分析' : '');
+        new Notification('价格提醒', { body });
+      };
+      if (Notification.permission === 'granted') {
+        notify();
+      } else if (Notification.permission !== 'denied') {
+        Notification.requestPermission().then(p => { if (p === 'granted') notify(); });
+      }
+    });
+  }, []);
+
   // Load watchlist on mount
   useEffect(() => {
     const loadWatchlist = async () => {
@@ -331,12 +397,13 @@ const App: React.FC = () => {
           }
         }
 
-        const list = await getWatchlist();
+        const [list, groups] = await Promise.all([getWatchlist(), getWatchlistGroups()]);
         setWatchlist(list);
+        setWatchlistGroups(groups);
         if (list.length > 0) {
           setSelectedSymbol(list[0].symbol);
-          // 订阅第一个股票的盘口推送
-          subscribeOrderBook(list[0].symbol);
+          // 聚焦第一个股票的盘口推送
+          focusStock(list[0].symbol);
           // 加载第一个股票的Session
           const session = await getOrCreateSession(list[0].symbol, list[0].name);
           setCurrentSession(session);
@@ -354,7 +421,7 @@ const App: React.FC = () => {
       }
     };
     loadWatchlist();
-  }, [subscribeOrderBook]);
+  }, [focusStock]);
 
   // Load K-line data when symbol or period changes
   useEffect(() => {
@@ -462,6 +529,14 @@ const App: React.FC = () => {
                   >
                     <div className="flex items-start gap-2">
                       <span className="text-xs text-accent-2 font-mono shrink-0">{tg.time}</span>
+                      {tg.sentiment && tg.sentiment !== 'neutral' && (
+                        <span
+                          className={`text-[10px] px-1 rounded shrink-0 border border-current ${tg.sentiment === 'positive' ? cc.upClass : cc.downClass}`}
+                          title={`词典情绪得分 ${tg.sentimentScore ?? 0}`}
+                        >
+                          {tg.sentiment === 'positive' ? '利好' : '利空'}
+                        </span>
+                      )}
                       <span className={`text-xs line-clamp-2 ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}>{tg.content}</span>
                     </div>
                   </div>
@@ -479,6 +554,13 @@ const App: React.FC = () => {
           >
             <BarChart3 className="h-4 w-4" />
           </button>
+          <button
+            onClick={() => setShowLimitBoard(true)}
+            className={`p-2 rounded-lg fin-panel border fin-divider transition-colors ${colors.isDark ? 'text-slate-300 hover:text-white' : 'text-slate-600 hover:text-slate-900'} hover:border-red-400/40`}
+            title="涨跌停板"
+          >
+            <Flame className="h-4 w-4" />
+          </button>
           <button
             onClick={() => setShowHotTrend(true)}
             className={`p-2 rounded-lg fin-panel border fin-divider transition-colors ${colors.isDark ? 'text-slate-300 hover:text-white' : 'text-slate-600 hover:text-slate-900'} hover:border-orange-400/40`}
@@ -542,6 +624,9 @@ const App: React.FC = () => {
             onAddStock={handleAddStock}
             onRemoveStock={handleRemoveStock}
             marketIndices={marketIndices}
+            groups={watchlistGroups}
+            onToggleGroup={handleToggleGroup}
+            onDeleteGroup={handleDeleteGroup}
           />
         </div>
 
@@ -556,30 +641,32 @@ const App: React.FC = () => {
               <div className="flex items-center gap-3">
                 <span className={`text-lg font-bold ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>{selectedStock.name}</span>
                 <span className={`text-sm font-mono ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>{selectedStock.symbol}</span>
-                <button
-                  onClick={() => setShowPosition(true)}
-                  className={`flex items-center gap-1 px-2 py-1 rounded text-xs transition-colors ${colors.isDark ? 'text-slate-400 hover:bg-slate-700/50' : 'text-slate-500 hover:bg-slate-200/50'} hover:text-accent-2`}
-                  title="持仓设置"
-                >
-                  <Briefcase className="h-3.5 w-3.5" />
-                  {currentSession?.position && currentSession.position.shares > 0 ? (
-                    (() => {
-                      const pos = currentSession.position;
-                      const marketValue = pos.shares * selectedStock.price;
-                      const costAmount = pos.shares * pos.costPrice;
-                      const profitLoss = marketValue - costAmount;
-                      const profitPercent = costAmount > 0 ? (profitLoss / costAmount) * 100 : 0;
-                      const isProfit = profitLoss >= 0;
-                      return (
-                        <span className={isProfit ? cc.upClass : cc.downClass}>
-                          {pos.shares}股 {isProfit ? '+' : ''}{profitLoss.toFixed(0)} ({isProfit ? '+' : ''}{profitPercent.toFixed(2)}%)
-                        </span>
-                      );
-                    })()
-                  ) : (
-                    <span>设置持仓</span>
-                  )}
-                </button>
+                {!isIndexStock(selectedStock) && (
+                  <button
+                    onClick={() => setShowPosition(true)}
+                    className={`flex items-center gap-1 px-2 py-1 rounded text-xs transition-colors ${colors.isDark ? 'text-slate-400 hover:bg-slate-700/50' : 'text-slate-500 hover:bg-slate-200/50'} hover:text-accent-2`}
+                    title="持仓设置"
+                  >
+                    <Briefcase className="h-3.5 w-3.5" />
+                    {currentSession?.position && currentSession.position.shares > 0 ? (
+                      (() => {
+                        const pos = currentSession.position;
+                        const marketValue = pos.shares * selectedStock.price;
+                        const costAmount = pos.shares * pos.costPrice;
+                        const profitLoss = marketValue - costAmount;
+                        const profitPercent = costAmount > 0 ? (profitLoss / costAmount) * 100 : 0;
+                        const isProfit = profitLoss >= 0;
+                        return (
+                          <span className={isProfit ? cc.upClass : cc.downClass}>
+                            {pos.shares}股 {isProfit ? '+' : ''}{profitLoss.toFixed(0)} ({isProfit ? '+' : ''}{profitPercent.toFixed(2)}%)
+                          </span>
+                        );
+                      })()
+                    ) : (
+                      <span>设置持仓</span>
+                    )}
+                  </button>
+                )}
               </div>
               <div className={`text-3xl font-mono font-bold ${cc.getColorClass(selectedStock.change >= 0)}`}>
                 {selectedStock.price.toFixed(2)}
@@ -651,7 +738,7 @@ const App: React.FC = () => {
 
       <SettingsDialog isOpen={showSettings} onClose={() => setShowSettings(false)} />
       <PositionDialog
-        isOpen={showPosition}
+        isOpen={showPosition && !isIndexStock(selectedStock)}
         onClose={() => setShowPosition(false)}
         stockCode={selectedStock.symbol}
         stockName={selectedStock.name}
@@ -665,6 +752,7 @@ const App: React.FC = () => {
       />
       <HotTrendDialog isOpen={showHotTrend} onClose={() => setShowHotTrend(false)} />
       <LongHuBangDialog isOpen={showLongHuBang} onClose={() => setShowLongHuBang(false)} />
+      <LimitBoardDialog isOpen={showLimitBoard} onClose={() => setShowLimitBoard(false)} />
     </div>
   );
 };
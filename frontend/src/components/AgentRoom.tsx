@@ -1,30 +1,55 @@
 import React, { useState, useEffect, useRef } from 'react';
 import { Stock, KLineData } from '../types';
 import { getAgentConfigs, AgentConfig } from '../services/strategyService';
-import { StockSession, ChatMessage, sendMeetingMessage, MeetingMessageRequest, getSessionMessages, retryAgent, retryAgentAndContinue, cancelInterruptedMeeting } from '../services/sessionService';
-import { MessageSquare, Loader2, Send, User, Users, X, Reply, Trash2, Wrench, CheckCircle2, AlertCircle, Copy, Check, RotateCcw, Pencil, Square } from 'lucide-react';
+import { StockSession, ChatMessage, sendMeetingMessage, MeetingMessageRequest, getSessionMessages, retryAgent, retryAgentAndContinue, resumeMeeting, cancelInterruptedMeeting, getOrCreateSession, MeetingUsage, getSuggestedQueries, SuggestedQuery, setAutoTranslate, dryRunMeeting, DryRunResult, estimateMeetingCost, MeetingCostEstimate } from '../services/sessionService';
+import { MessageSquare, Loader2, Send, User, Users, X, Reply, Trash2, Wrench, CheckCircle2, AlertCircle, Copy, Check, RotateCcw, Pencil, Square, FileDown, FolderOpen, Play, Swords, Languages, Dices, ListChecks, Zap } from 'lucide-react';
 import { clearSessionMessages } from '../services/sessionService';
 import { NodeRenderer } from 'markstream-react';
 import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 import { useMentionPicker } from '../hooks/useMentionPicker';
 import { useTheme } from '../contexts/ThemeContext';
-import { CancelMeeting } from '../../wailsjs/go/main/App';
+import { CancelMeeting, CancelToolCall } from '../../wailsjs/go/main/App';
+import { exportMeetingTranscript, getMeetingTranscript, onTranscriptExported, openExportedFile, TranscriptFormat } from '../services/exportService';
 import 'markstream-react/index.css';
 
+// 单条消息最多附带的图片数与单张大小上限（与后端一致）
+const MAX_IMAGES = 4;
+const MAX_IMAGE_BYTES = 5 * 1024 * 1024;
+
+// 会前估算的 token 数，千以上显示为 k
+const formatTokenCount = (n: number): string => {
+  if (n < 1000) return String(n);
+  return `${(n / 1000).toFixed(n < 10000 ? 1 : 0)}k`;
+};
+
+// 会前估算的费用，不足 0.1 元时保留一位有效数字
+const formatCost = (cost: number): string => (cost >= 0.1 ? cost.toFixed(1) : cost.toPrecision(1));
+
 // 进度事件类型
 interface ProgressEvent {
-  type: 'agent_start' | 'agent_done' | 'tool_call' | 'tool_result' | 'streaming' | 'agent_error' | 'meeting_interrupted';
+  type: 'agent_start' | 'agent_done' | 'tool_call' | 'tool_result' | 'tool_call_started' | 'tool_call_finished' | 'tool_progress' | 'streaming' | 'thinking' | 'agent_error' | 'meeting_interrupted' | 'usage' | 'data_suspect';
   agentId: string;
   agentName: string;
   detail?: string;
   content?: string;
+  partial?: boolean; // LLM 流式增量片段
+  usage?: MeetingUsage; // 会议累计用量（usage 事件）
+  args?: string; // 工具调用参数（tool_call_started）
+  durationMs?: number; // 工具执行耗时（tool_call_finished）
+  model?: string; // 专家使用的模型（agent_start）
+  percent?: number; // 长耗时工具进度百分比（tool_progress），0 表示未知
+  requestId?: string; // 工具调用 ID，用于取消该调用（tool_progress）
 }
 
 // 进度状态
 interface ProgressState {
   currentAgent: string | null;
   currentAgentName: string | null;
-  steps: { type: string; detail: string; done: boolean }[];
+  currentModel?: string; // 当前专家使用的模型
+  steps: {
+    type: string; detail: string; done: boolean; args?: string; preview?: string; durationMs?: number;
+    percent?: number; progressText?: string; requestId?: string; // 长耗时工具进度
+  }[];
   streamingText: string;
 }
 
@@ -41,6 +66,8 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
   const [messages, setMessages] = useState<ChatMessage[]>([]);
   const [simulatingMap, setSimulatingMap] = useState<Record<string, boolean>>({});
   const [userQuery, setUserQuery] = useState('');
+  const [pastedImages, setPastedImages] = useState<string[]>([]); // 粘贴的图片（data URL）
+  const [meetingUsage, setMeetingUsage] = useState<MeetingUsage | null>(null);
   const scrollRef = useRef<HTMLDivElement>(null);
   const inputRef = useRef<HTMLInputElement>(null);
 
@@ -55,6 +82,7 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
 
   // 会议取消标识
   const meetingCancelledRef = useRef<Record<string, boolean>>({});
+  const smartMeetingRef = useRef<Record<string, boolean>>({}); // 进行中的会议是否为智能模式（可恢复）
 
   // 使用自定义 Hooks
   const {
@@ -77,7 +105,20 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
   const [showClearConfirm, setShowClearConfirm] = useState(false);
   const [copiedId, setCopiedId] = useState<string | null>(null);
   const [failedUserMsgId, setFailedUserMsgId] = useState<string | null>(null);
+  const [suspectUserMsgId, setSuspectUserMsgId] = useState<string | null>(null); // 因行情异常未开会的消息，可强制开会
+  // 各股票最近一次会议因行情异常被拒绝的原因（data_suspect 事件写入）
+  const dataSuspectRef = useRef<Record<string, string>>({});
   const [retryingAgentId, setRetryingAgentId] = useState<string | null>(null);
+  const [suggestions, setSuggestions] = useState<SuggestedQuery[]>([]);
+  const [showExportMenu, setShowExportMenu] = useState(false);
+  const [exportedPath, setExportedPath] = useState<string | null>(null); // 当前股票最近一次导出的讨论记录
+  const [resumableMap, setResumableMap] = useState<Record<string, boolean>>({}); // 被取消、可继续的智能会议
+  const [debateMode, setDebateMode] = useState(false); // 辩论模式：首轮后分歧最大的两位专家互相反驳
+  const [reproducible, setReproducible] = useState(false); // 复现模式：统一低温度与固定随机种子，便于对比两次运行
+  const [concurrentMode, setConcurrentMode] = useState(false); // 并发模式：专家互不参考，按 CPU 核数同时发言
+  const [dryRunResult, setDryRunResult] = useState<DryRunResult | null>(null); // 会议预演结果（各专家计划调用的工具）
+  const [dryRunning, setDryRunning] = useState(false);
+  const [costEstimate, setCostEstimate] = useState<MeetingCostEstimate | null>(null); // 会前消耗估算，随输入与模式更新
 
   // 进度状态
   const [progress, setProgress] = useState<ProgressState>({
@@ -99,6 +140,15 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
     }]);
   };
 
+  // 取消单个长耗时工具调用，专家收到"用户取消"后继续发言
+  const handleCancelToolCall = (requestId: string) => {
+    CancelToolCall(requestId).then(ok => {
+      if (!ok) console.warn('[AgentRoom] 工具调用已结束:', requestId);
+    }).catch(err => {
+      console.error('[AgentRoom] 取消工具调用失败:', err);
+    });
+  };
+
   // 取消指定股票的会议
   const cancelMeeting = (stockCode: string) => {
     // 调用后端取消 API
@@ -114,9 +164,33 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
       steps: [],
       streamingText: '',
     });
+    if (smartMeetingRef.current[stockCode]) {
+      setResumableMap(prev => ({ ...prev, [stockCode]: true }));
+    }
     addSystemMessage('讨论已停止');
   };
 
+  // 继续被取消的智能会议：已发言的专家不重复发言
+  const handleResumeMeeting = async () => {
+    if (!session || isSimulating) return;
+    const stockCode = session.stockCode;
+
+    setResumableMap(prev => ({ ...prev, [stockCode]: false }));
+    meetingCancelledRef.current[stockCode] = false;
+    setSimulatingMap(prev => ({ ...prev, [stockCode]: true }));
+    try {
+      const msgs = await resumeMeeting(stockCode);
+      if (!msgs || msgs.length === 0) {
+        addSystemMessage('没有可继续的讨论，请重新提问');
+      }
+    } catch (e) {
+      console.error('[AgentRoom] resumeMeeting error:', e);
+      addSystemMessage('继续讨论失败，请重新提问');
+    } finally {
+      setSimulatingMap(prev => ({ ...prev, [stockCode]: false }));
+    }
+  };
+
   // 加载Agent配置
   const loadAgents = () => {
     getAgentConfigs()
@@ -144,6 +218,67 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
     };
   }, []);
 
+  // 监听专家配置变更（新增/删除/修改），进行中的会议仍使用开始时的快照，下一场会议生效
+  useEffect(() => {
+    const cleanup = EventsOn('agents:changed', (diff: { added?: string[]; removed?: string[]; changed?: string[] }) => {
+      console.log('[AgentRoom] 专家配置已更新', diff);
+      loadAgents();
+    });
+    return () => {
+      EventsOff('agents:changed');
+      if (cleanup) cleanup();
+    };
+  }, []);
+
+  // 切换股票、专家变更或会议结束后刷新推荐问题
+  useEffect(() => {
+    const stockCode = session?.stockCode;
+    if (!stockCode || isSimulating) {
+      setSuggestions([]);
+      return;
+    }
+    getSuggestedQueries(stockCode)
+      .then(items => {
+        if (currentStockCodeRef.current === stockCode) setSuggestions(items);
+      })
+      .catch(err => console.error('[AgentRoom] getSuggestedQueries error:', err));
+  }, [session?.stockCode, isSimulating, allAgents, session?.position?.shares, session?.position?.costPrice]);
+
+  // 输入、@ 的专家或会议模式变化后估算本次提问的消耗（不调用模型），停顿片刻再请求避免逐字刷新
+  useEffect(() => {
+    const stockCode = session?.stockCode;
+    if (!stockCode || isSimulating || !userQuery.trim()) {
+      setCostEstimate(null);
+      return;
+    }
+    const timer = setTimeout(() => {
+      estimateMeetingCost({
+        stockCode,
+        content: userQuery,
+        images: pastedImages,
+        mentionIds: [...mentionedAgents],
+        replyToId: replyToMessage?.id || '',
+        replyContent: replyToMessage?.content || '',
+        mode: debateMode && mentionedAgents.length === 0 ? 'debate' : undefined,
+        concurrentMode: concurrentMode || undefined,
+      })
+        .then(result => {
+          if (currentStockCodeRef.current === stockCode) setCostEstimate(result.estimate || null);
+        })
+        .catch(err => console.error('[AgentRoom] estimateMeetingCost error:', err));
+    }, 500);
+    return () => clearTimeout(timer);
+  }, [session?.stockCode, isSimulating, userQuery, mentionedAgents, replyToMessage, debateMode, concurrentMode, pastedImages, allAgents]);
+
+  // 监听讨论记录导出完成，提供打开文件入口
+  useEffect(() => {
+    return onTranscriptExported(event => {
+      if (event.stockCode === currentStockCodeRef.current) {
+        setExportedPath(event.path);
+      }
+    });
+  }, []);
+
   // 当Session变化时，从后端加载最新消息
   useEffect(() => {
     // 使用 prevStockCodeRef 获取真正的上一次 stockCode
@@ -165,6 +300,8 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
       setMessages([]);
     }
     setUserQuery('');
+    setExportedPath(null);
+    setShowExportMenu(false);
 
     // 更新 refs（在 effect 结束时更新，确保下次能正确检测切换）
     prevStockCodeRef.current = newStockCode;
@@ -208,21 +345,43 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
             return {
               currentAgent: event.agentId,
               currentAgentName: event.agentName,
+              currentModel: event.model,
               steps: [],
               streamingText: '',
             };
           case 'agent_done':
-            return { ...prev, currentAgent: null, currentAgentName: null, steps: [], streamingText: '' };
-          case 'tool_call':
+            return { ...prev, currentAgent: null, currentAgentName: null, currentModel: undefined, steps: [], streamingText: '' };
+          case 'tool_call_started':
             return {
               ...prev,
-              steps: [...prev.steps, { type: 'tool_call', detail: event.detail || '', done: false }],
+              steps: [...prev.steps, { type: 'tool_call', detail: event.detail || '', done: false, args: event.args }],
+            };
+          case 'tool_call_finished': {
+            // 同名工具可能并行调用多次，标记第一个未完成的
+            const idx = prev.steps.findIndex(s => s.type === 'tool_call' && !s.done && s.detail === event.detail);
+            if (idx < 0) return prev;
+            const updatedSteps = [...prev.steps];
+            updatedSteps[idx] = { ...updatedSteps[idx], done: true, preview: event.content, durationMs: event.durationMs };
+            return { ...prev, steps: updatedSteps };
+          }
+          case 'tool_progress': {
+            // 长耗时工具进度，更新最近一个未完成的同名调用
+            let idx = -1;
+            prev.steps.forEach((s, i) => {
+              if (s.type === 'tool_call' && !s.done && s.detail === event.detail) idx = i;
+            });
+            if (idx < 0) return prev;
+            const updatedSteps = [...prev.steps];
+            updatedSteps[idx] = {
+              ...updatedSteps[idx],
+              percent: event.percent || updatedSteps[idx].percent,
+              progressText: event.content || updatedSteps[idx].progressText,
+              requestId: event.requestId,
             };
-          case 'tool_result':
-            const updatedSteps = prev.steps.map(s =>
-              s.type === 'tool_call' && s.detail === event.detail ? { ...s, done: true } : s
-            );
             return { ...prev, steps: updatedSteps };
+          }
+          case 'truncated':
+            return { ...prev, steps: [...prev.steps, { type: 'truncated', detail: event.detail || '', done: true }] };
           case 'streaming':
             return { ...prev, streamingText: prev.streamingText + (event.content || '') };
           case 'meeting_interrupted':
@@ -232,6 +391,16 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
         }
       });
 
+      // usage 事件：更新会议累计用量
+      if (event.type === 'usage' && event.usage) {
+        setMeetingUsage(event.usage);
+      }
+
+      // data_suspect 事件：行情异常，会议未开始，发送结束后提示用户
+      if (event.type === 'data_suspect') {
+        dataSuspectRef.current[stockCode] = event.detail || '';
+      }
+
       // meeting_interrupted 事件：停止会议进行状态（失败消息卡片内联按钮处理重试/放弃）
       if (event.type === 'meeting_interrupted') {
         setSimulatingMap(prev => ({ ...prev, [stockCode]: false }));
@@ -253,15 +422,21 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
   const handleSendMessage = async (
     query: string,
     mentions: string[],
-    replyTo: ChatMessage | null
+    replyTo: ChatMessage | null,
+    images: string[] = [],
+    forceSuspectData = false
   ) => {
     if (!session || !query.trim()) return;
 
     const stockCode = session.stockCode;
+    delete dataSuspectRef.current[stockCode];
 
-    // 重置取消标识
+    // 重置取消标识，新问题开始后上一场会议不再可继续
     meetingCancelledRef.current[stockCode] = false;
+    smartMeetingRef.current[stockCode] = mentions.length === 0;
+    setResumableMap(prev => ({ ...prev, [stockCode]: false }));
     setSimulatingMap(prev => ({ ...prev, [stockCode]: true }));
+    setMeetingUsage(null);
 
     // 添加用户消息用于即时显示
     const userMsg: ChatMessage = {
@@ -282,16 +457,30 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
       const req: MeetingMessageRequest = {
         stockCode: session.stockCode,
         content: query,
+        images,
         mentionIds: mentions,
         replyToId: replyTo?.id || '',
-        replyContent: replyTo?.content || ''
+        replyContent: replyTo?.content || '',
+        forceSuspectData,
+        // 辩论模式仅对小韭菜主持的智能会议生效
+        mode: debateMode && mentions.length === 0 ? 'debate' : undefined,
+        reproducible: reproducible || undefined,
+        concurrentMode: concurrentMode || undefined
       };
 
       // 统一模式：无论智能模式还是直接@模式，消息都通过事件实时推送
       await sendMeetingMessage(req);
-      // 消息已通过事件实时添加，更新session
+      if (stockCode in dataSuspectRef.current) {
+        addSystemMessage(`行情数据异常（${dataSuspectRef.current[stockCode]}），请稍后重试`);
+        setFailedUserMsgId(userMsg.id);
+        setSuspectUserMsgId(userMsg.id);
+        return;
+      }
+      // 消息已通过事件实时添加，更新session（同步最新的 token 用量）
+      const latest = await getOrCreateSession(session.stockCode, session.stockName);
       onSessionUpdate({
         ...session,
+        tokenUsage: latest.tokenUsage,
         messages: [] // 会在事件中更新
       });
     } catch (e) {
@@ -324,16 +513,62 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
     const queryToSend = userQuery;
     const mentionsToSend = [...mentionedAgents];
     const replyToSend = replyToMessage;
+    const imagesToSend = pastedImages;
 
     // 立即清空输入和@状态
     setUserQuery('');
+    setPastedImages([]);
     clearMentions();
     setReplyToMessage(null);
     closePicker();
 
-    handleSendMessage(queryToSend, mentionsToSend, replyToSend);
+    handleSendMessage(queryToSend, mentionsToSend, replyToSend, imagesToSend);
   }
 
+  // 会议预演：用当前输入与 @ 的专家预览各专家计划调用的工具，不清空输入，便于确认后再正式发送
+  const handleDryRun = async () => {
+    if (!session || !userQuery.trim() || dryRunning) return;
+    setDryRunning(true);
+    try {
+      const result = await dryRunMeeting({
+        stockCode: session.stockCode,
+        content: userQuery,
+        mentionIds: [...mentionedAgents],
+        replyToId: replyToMessage?.id || '',
+        replyContent: replyToMessage?.content || '',
+      });
+      if (result.error) {
+        addSystemMessage(`预演失败：${result.error}`);
+      } else {
+        setDryRunResult(result);
+      }
+    } catch (e) {
+      console.error('[AgentRoom] dryRunMeeting error:', e);
+      addSystemMessage('预演失败，请稍后重试');
+    } finally {
+      setDryRunning(false);
+    }
+  };
+
+  // 粘贴截图：读取剪贴板中的图片为 data URL，随下一条消息发送
+  const handlePaste = (e: React.ClipboardEvent<HTMLInputElement>) => {
+    const files = Array.from(e.clipboardData.files).filter(f => f.type.startsWith('image/'));
+    if (files.length === 0) return;
+    e.preventDefault();
+    for (const file of files) {
+      if (file.size > MAX_IMAGE_BYTES) {
+        addSystemMessage(`图片 ${file.name || ''} 超过 5MB，已忽略`);
+        continue;
+      }
+      const reader = new FileReader();
+      reader.onload = () => {
+        const url = reader.result as string;
+        setPastedImages(prev => (prev.length >= MAX_IMAGES ? prev : [...prev, url]));
+      };
+      reader.readAsDataURL(file);
+    }
+  };
+
   // 处理输入变化，检测@符号
   const handleInputChange = (e: React.ChangeEvent<HTMLInputElement>) => {
     const value = e.target.value;
@@ -387,13 +622,22 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
   // 重试发送消息
   const handleRetry = (msg: ChatMessage) => {
     setFailedUserMsgId(null);
+    setSuspectUserMsgId(null);
     handleSendMessage(msg.content, msg.mentions || [], null);
   };
 
+  // 行情异常时仍然开会（专家会收到数据质量警告）
+  const handleForceSuspect = (msg: ChatMessage) => {
+    setFailedUserMsgId(null);
+    setSuspectUserMsgId(null);
+    handleSendMessage(msg.content, msg.mentions || [], null, [], true);
+  };
+
   // 编辑消息
   const handleEdit = (msg: ChatMessage) => {
     setUserQuery(msg.content);
     setFailedUserMsgId(null);
+    setSuspectUserMsgId(null);
     inputRef.current?.focus();
   };
 
@@ -459,6 +703,48 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
     }
   };
 
+  // 导出讨论记录
+  // 切换专家回复自动翻译为中文
+  const handleToggleTranslate = async () => {
+    if (!session) return;
+    const target = session.autoTranslate ? '' : 'zh';
+    if (await setAutoTranslate(session.stockCode, target) === 'success') {
+      onSessionUpdate({ ...session, autoTranslate: target || undefined });
+    }
+  };
+
+  const handleExportTranscript = async (format: TranscriptFormat) => {
+    setShowExportMenu(false);
+    if (!session) return;
+    try {
+      const result = await exportMeetingTranscript(session.stockCode, format);
+      if (result.error) {
+        addSystemMessage(`导出失败：${result.error}`);
+      }
+    } catch (e) {
+      console.error('[AgentRoom] exportMeetingTranscript error:', e);
+      addSystemMessage('导出失败');
+    }
+  };
+
+  // 复制 Markdown 格式的讨论记录，便于粘贴到研究笔记
+  const handleCopyTranscript = async () => {
+    setShowExportMenu(false);
+    if (!session) return;
+    try {
+      const markdown = await getMeetingTranscript(session.stockCode, 'markdown');
+      if (!markdown) {
+        addSystemMessage('复制失败：暂无讨论记录');
+        return;
+      }
+      await navigator.clipboard.writeText(markdown);
+      addSystemMessage('讨论记录已复制为 Markdown');
+    } catch (e) {
+      console.error('[AgentRoom] copy transcript error:', e);
+      addSystemMessage('复制失败');
+    }
+  };
+
   return (
     <div className="relative flex flex-col h-full">
       {/* Header */}
@@ -468,16 +754,75 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
             <Users style={{ color: 'var(--accent)' }} />
             韭菜讨论中心
           </h2>
-          <button
-            onClick={handleClearMessages}
-            disabled={isSimulating || messages.length === 0}
-            className={`p-1.5 rounded transition-colors disabled:opacity-30 disabled:cursor-not-allowed ${colors.isDark ? 'text-slate-400 hover:text-red-400 hover:bg-slate-800' : 'text-slate-500 hover:text-red-500 hover:bg-slate-200'}`}
-            title="清空聊天记录"
-          >
-            <Trash2 size={16} />
-          </button>
+          <div className="flex items-center gap-1">
+            <button
+              onClick={handleToggleTranslate}
+              disabled={!session}
+              className={`p-1.5 rounded transition-colors disabled:opacity-30 ${session?.autoTranslate ? 'text-accent-2' : (colors.isDark ? 'text-slate-400 hover:text-white hover:bg-slate-800' : 'text-slate-500 hover:text-slate-800 hover:bg-slate-200')}`}
+              title={session?.autoTranslate ? '自动翻译已开启：非中文回复将翻译为中文' : '开启自动翻译（非中文回复翻译为中文）'}
+            >
+              <Languages size={16} />
+            </button>
+            <div className="relative">
+              <button
+                onClick={() => setShowExportMenu(v => !v)}
+                disabled={isSimulating || messages.length === 0}
+                className={`p-1.5 rounded transition-colors disabled:opacity-30 disabled:cursor-not-allowed ${colors.isDark ? 'text-slate-400 hover:text-white hover:bg-slate-800' : 'text-slate-500 hover:text-slate-800 hover:bg-slate-200'}`}
+                title="导出讨论记录"
+              >
+                <FileDown size={16} />
+              </button>
+              {showExportMenu && (
+                <div className={`absolute right-0 mt-1 w-36 rounded-lg border shadow-lg z-20 py-1 text-sm ${colors.isDark ? 'bg-slate-800 border-slate-700 text-slate-200' : 'bg-white border-slate-200 text-slate-700'}`}>
+                  <button
+                    onClick={() => handleExportTranscript('markdown')}
+                    className={`w-full text-left px-3 py-1.5 ${colors.isDark ? 'hover:bg-slate-700' : 'hover:bg-slate-100'}`}
+                  >
+                    Markdown
+                  </button>
+                  <button
+                    onClick={() => handleExportTranscript('html')}
+                    className={`w-full text-left px-3 py-1.5 ${colors.isDark ? 'hover:bg-slate-700' : 'hover:bg-slate-100'}`}
+                    title="可在浏览器中打印为 PDF"
+                  >
+                    HTML（可打印 PDF）
+                  </button>
+                  <button
+                    onClick={handleCopyTranscript}
+                    className={`w-full text-left px-3 py-1.5 ${colors.isDark ? 'hover:bg-slate-700' : 'hover:bg-slate-100'}`}
+                  >
+                    复制 Markdown
+                  </button>
+                </div>
+              )}
+            </div>
+            <button
+              onClick={handleClearMessages}
+              disabled={isSimulating || messages.length === 0}
+              className={`p-1.5 rounded transition-colors disabled:opacity-30 disabled:cursor-not-allowed ${colors.isDark ? 'text-slate-400 hover:text-red-400 hover:bg-slate-800' : 'text-slate-500 hover:text-red-500 hover:bg-slate-200'}`}
+              title="清空聊天记录"
+            >
+              <Trash2 size={16} />
+            </button>
+          </div>
         </div>
         <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>@韭菜提问，引用观点深入讨论</p>
+        {exportedPath && (
+          <div className={`mt-2 flex items-center gap-2 text-xs rounded px-2 py-1 ${colors.isDark ? 'bg-slate-800 text-slate-300' : 'bg-slate-100 text-slate-600'}`}>
+            <span className="truncate flex-1" title={exportedPath}>已导出：{exportedPath}</span>
+            <button
+              onClick={() => openExportedFile(exportedPath)}
+              className="flex items-center gap-1 shrink-0 hover:underline"
+              style={{ color: 'var(--accent)' }}
+            >
+              <FolderOpen size={12} />
+              打开文件
+            </button>
+            <button onClick={() => setExportedPath(null)} className="shrink-0 opacity-60 hover:opacity-100" title="关闭">
+              <X size={12} />
+            </button>
+          </div>
+        )}
       </div>
 
       {/* Chat Area */}
@@ -552,6 +897,15 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                           <Pencil size={12} />
                           编辑
                         </button>
+                        {suspectUserMsgId === msg.id && (
+                          <button
+                            onClick={() => handleForceSuspect(msg)}
+                            className={`flex items-center gap-1 text-xs px-2 py-1 rounded transition-colors ${colors.isDark ? 'text-red-400 hover:text-red-300 bg-red-500/10 hover:bg-red-500/20' : 'text-red-600 hover:text-red-500 bg-red-500/10 hover:bg-red-500/20'}`}
+                          >
+                            <AlertCircle size={12} />
+                            仍要讨论
+                          </button>
+                        )}
                       </div>
                     )}
                  </div>
@@ -576,7 +930,7 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                   <div className="flex items-baseline gap-2 mb-1">
                     <span className="text-xs font-bold text-amber-400">{msg.agentName}</span>
                     <span className={`text-[9px] border border-amber-500/30 px-1 rounded ${colors.isDark ? 'text-amber-500/70' : 'text-amber-600/70'}`}>
-                      {isOpening ? '开场' : isSummary ? '总结' : msg.role}
+                      {msg.roundLabel || (isOpening ? '开场' : isSummary ? '总结' : msg.role)}
                     </span>
                   </div>
                   <div className="relative">
@@ -613,9 +967,42 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                 <div className="flex items-baseline gap-2 mb-1">
                   <span className={`text-xs font-bold ${msg.error ? 'text-red-400' : (colors.isDark ? 'text-slate-300' : 'text-slate-600')}`}>{msg.agentName || agent?.name}</span>
                   <span className={`text-[9px] uppercase border fin-divider px-1 rounded fin-chip ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>{msg.role || agent?.role}</span>
+                  {msg.roundLabel && (
+                    <span className={`text-[9px] border border-amber-500/30 px-1 rounded ${colors.isDark ? 'text-amber-500/70' : 'text-amber-600/70'}`}>{msg.roundLabel}</span>
+                  )}
+                  {msg.msgType === 'rebuttal' && msg.rebuts && (
+                    <span className="flex items-center gap-0.5 text-[9px] px-1 rounded bg-orange-500/15 text-orange-400 border border-orange-500/30">
+                      <Swords size={9} />
+                      反驳 → {allAgents.find(a => a.id === msg.rebuts)?.name || msg.rebuts}
+                    </span>
+                  )}
+                  {!msg.error && session?.tokenUsage?.[msg.agentId] && (
+                    <span
+                      className={`text-[9px] font-mono ${colors.isDark ? 'text-slate-600' : 'text-slate-400'}`}
+                      title={`${msg.agentName} 本会话已用 ${session.tokenUsage[msg.agentId].totalTokens} tokens（${session.tokenUsage[msg.agentId].calls} 次发言）`}
+                    >
+                      {session.tokenUsage[msg.agentId].totalTokens} tokens
+                    </span>
+                  )}
                   {msg.error && (
                     <span className="text-[9px] px-1 rounded bg-red-500/20 text-red-400 border border-red-500/30">失败</span>
                   )}
+                  {msg.finishReason === 'MAX_TOKENS' && (
+                    <span
+                      className="text-[9px] px-1 rounded bg-amber-500/20 text-amber-400 border border-amber-500/30"
+                      title="回复达到输出长度上限，可在 AI 配置中调大最大 Token 数"
+                    >
+                      已截断
+                    </span>
+                  )}
+                  {msg.asOf && (
+                    <span
+                      className={`text-[9px] font-mono ${colors.isDark ? 'text-slate-600' : 'text-slate-400'}`}
+                      title="本条发言所依据的行情数据截至时间"
+                    >
+                      截至 {msg.asOf}
+                    </span>
+                  )}
                 </div>
                 <div className="relative">
                   {msg.error ? (
@@ -653,6 +1040,12 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                     <>
                       <div className={`text-sm p-3 rounded-2xl rounded-tl-none leading-relaxed shadow-sm agent-message-content ${colors.isDark ? 'text-slate-200 bg-slate-800/70 border border-slate-700/40' : 'text-slate-700 bg-white border border-slate-200'}`}>
                         <NodeRenderer content={msg.content} />
+                        {msg.original && (
+                          <details className={`mt-2 pt-2 border-t text-xs ${colors.isDark ? 'border-slate-700 text-slate-400' : 'border-slate-200 text-slate-500'}`}>
+                            <summary className="cursor-pointer select-none">已自动翻译，查看原文</summary>
+                            <div className="mt-1 whitespace-pre-wrap">{msg.original}</div>
+                          </details>
+                        )}
                       </div>
                       {/* 操作按钮组 */}
                       <div className="absolute -right-2 top-1 flex flex-col gap-1 opacity-0 group-hover:opacity-100 transition-opacity">
@@ -688,19 +1081,58 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                   <Loader2 className="animate-spin h-4 w-4 text-accent-2" />
                   <span className="text-sm text-accent-2 font-medium">{progress.currentAgentName}</span>
                   <span className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>正在分析...</span>
+                  {progress.currentModel && (
+                    <span className={`text-[10px] px-1.5 py-0.5 fin-chip rounded ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`} title="本次发言使用的模型">
+                      {progress.currentModel}
+                    </span>
+                  )}
+                  {meetingUsage && (
+                    <span className={`ml-auto text-[10px] font-mono ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`} title="本场会议累计用量">
+                      {meetingUsage.totalTokens} tokens{meetingUsage.cost > 0 && ` · ≈${meetingUsage.cost.toFixed(4)}`}
+                    </span>
+                  )}
                 </div>
                 {progress.steps.length > 0 && (
                   <div className="pl-6 space-y-1">
                     {progress.steps.map((step, i) => (
                       <div key={i} className="flex items-center gap-2 text-xs">
-                        {step.done ? (
+                        {step.type === 'truncated' ? (
+                          <AlertCircle className="h-3 w-3 text-amber-400" />
+                        ) : step.done ? (
                           <CheckCircle2 className="h-3 w-3 text-green-400" />
                         ) : (
                           <Wrench className="h-3 w-3 text-amber-400 animate-pulse" />
                         )}
-                        <span className={step.done ? (colors.isDark ? 'text-slate-400' : 'text-slate-500') : 'text-amber-400'}>
-                          {step.detail}
+                        <span
+                          className={step.done ? (colors.isDark ? 'text-slate-400' : 'text-slate-500') : 'text-amber-400'}
+                          title={[step.args, step.preview].filter(Boolean).join('\n\n') || undefined}
+                        >
+                          {step.done ? step.detail : `正在调用 ${step.detail}`}
                         </span>
+                        {step.args && !step.done && (
+                          <span className={`truncate min-w-0 font-mono text-[10px] ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+                            {step.args.replace(/\s+/g, ' ')}
+                          </span>
+                        )}
+                        {!step.done && step.requestId && (
+                          <>
+                            <span className={`truncate min-w-0 text-[10px] ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
+                              {step.percent ? `${Math.round(step.percent)}% ` : ''}{step.progressText}
+                            </span>
+                            <button
+                              onClick={() => handleCancelToolCall(step.requestId!)}
+                              className="shrink-0 px-1.5 py-0.5 rounded text-[10px] text-red-400 hover:bg-red-500/10 transition-colors"
+                              title="仅取消该工具调用，专家会继续发言"
+                            >
+                              取消
+                            </button>
+                          </>
+                        )}
+                        {step.done && step.durationMs !== undefined && (
+                          <span className={`font-mono text-[10px] ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+                            {(step.durationMs / 1000).toFixed(1)}s
+                          </span>
+                        )}
                       </div>
                     ))}
                   </div>
@@ -730,6 +1162,25 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
           </div>
         )}
 
+        {/* 已粘贴图片 */}
+        {pastedImages.length > 0 && (
+          <div className="flex items-center gap-2 mb-2 flex-wrap">
+            {pastedImages.map((img, i) => (
+              <div key={i} className="relative">
+                <img src={img} alt={`图片${i + 1}`} className="h-12 w-12 object-cover rounded border fin-divider" />
+                <button
+                  type="button"
+                  onClick={() => setPastedImages(prev => prev.filter((_, j) => j !== i))}
+                  className="absolute -top-1 -right-1 bg-slate-700 text-white rounded-full p-0.5"
+                  title="移除图片"
+                >
+                  <X size={10} />
+                </button>
+              </div>
+            ))}
+          </div>
+        )}
+
         {/* 已@韭菜标签 */}
         {mentionedAgents.length > 0 && (
           <div className="flex items-center gap-1 mb-2 flex-wrap">
@@ -795,14 +1246,63 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
 
           {/* 输入框 */}
           <form onSubmit={handleSubmit} className="flex gap-2">
+            <button
+              type="button"
+              onClick={() => setDebateMode(v => !v)}
+              disabled={isSimulating}
+              className={`p-2 rounded-lg border transition-colors flex items-center justify-center w-10 h-10 disabled:opacity-50 ${
+                debateMode
+                  ? 'bg-orange-500/20 border-orange-500/50 text-orange-400'
+                  : (colors.isDark ? 'fin-divider text-slate-500 hover:text-slate-300' : 'fin-divider text-slate-400 hover:text-slate-600')
+              }`}
+              title={debateMode ? '辩论模式已开启：首轮后分歧最大的两位专家互相反驳，小韭菜裁决' : '开启辩论模式'}
+            >
+              <Swords size={16} />
+            </button>
+            <button
+              type="button"
+              onClick={() => setReproducible(v => !v)}
+              disabled={isSimulating}
+              className={`p-2 rounded-lg border transition-colors flex items-center justify-center w-10 h-10 disabled:opacity-50 ${
+                reproducible
+                  ? 'bg-sky-500/20 border-sky-500/50 text-sky-400'
+                  : (colors.isDark ? 'fin-divider text-slate-500 hover:text-slate-300' : 'fin-divider text-slate-400 hover:text-slate-600')
+              }`}
+              title={reproducible ? '复现模式已开启：统一低温度、固定随机种子与当前时间，参数记录在会议运行记录中' : '开启复现模式'}
+            >
+              <Dices size={16} />
+            </button>
+            <button
+              type="button"
+              onClick={() => setConcurrentMode(v => !v)}
+              disabled={isSimulating}
+              className={`p-2 rounded-lg border transition-colors flex items-center justify-center w-10 h-10 disabled:opacity-50 ${
+                concurrentMode
+                  ? 'bg-emerald-500/20 border-emerald-500/50 text-emerald-400'
+                  : (colors.isDark ? 'fin-divider text-slate-500 hover:text-slate-300' : 'fin-divider text-slate-400 hover:text-slate-600')
+              }`}
+              title={concurrentMode ? '并发模式已开启：专家互不参考，按 CPU 核数同时发言，适合任务相互独立的提问' : '开启并发模式'}
+            >
+              <Zap size={16} />
+            </button>
+            <button
+              type="button"
+              onClick={handleDryRun}
+              disabled={isSimulating || dryRunning || !userQuery.trim()}
+              className={`p-2 rounded-lg border transition-colors flex items-center justify-center w-10 h-10 disabled:opacity-50 ${colors.isDark ? 'fin-divider text-slate-500 hover:text-slate-300' : 'fin-divider text-slate-400 hover:text-slate-600'}`}
+              title="预演：查看各专家计划调用哪些工具（不实际调用工具）"
+            >
+              {dryRunning ? <Loader2 size={16} className="animate-spin" /> : <ListChecks size={16} />}
+            </button>
             <input
                ref={inputRef}
                type="text"
                value={userQuery}
                onChange={handleInputChange}
                onKeyDown={handleKeyDown}
+               onPaste={handlePaste}
                disabled={isSimulating}
-               placeholder="直接提问或输入 @ 选择韭菜专家..."
+               placeholder="直接提问或输入 @ 选择韭菜专家，可粘贴K线截图..."
                className="flex-1 fin-input rounded-lg px-4 py-2 text-sm placeholder-slate-500 border fin-divider"
             />
             {isSimulating ? (
@@ -826,11 +1326,91 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
             )}
           </form>
         </div>
+        {/* 推荐问题 */}
+        {!isSimulating && (suggestions.length > 0 || (session && resumableMap[session.stockCode])) && (
+          <div className="flex items-center gap-1.5 mt-2 flex-wrap">
+            {session && resumableMap[session.stockCode] && (
+              <button
+                onClick={handleResumeMeeting}
+                className="flex items-center gap-1 px-2 py-0.5 rounded-full border text-[11px] transition-colors hover:opacity-80"
+                style={{ color: 'var(--accent)', borderColor: 'var(--accent)' }}
+                title="从停止处继续，已发言的专家不会重复发言"
+              >
+                <Play size={10} />
+                继续讨论
+              </button>
+            )}
+            {suggestions.map(item => (
+              <button
+                key={item.label}
+                onClick={() => handleSendMessage(item.content, item.mentionIds, null)}
+                className={`px-2 py-0.5 rounded-full border text-[11px] transition-colors ${colors.isDark ? 'border-slate-700 text-slate-400 hover:text-white hover:border-slate-500' : 'border-slate-300 text-slate-500 hover:text-slate-800 hover:border-slate-400'}`}
+                title={item.content}
+              >
+                {item.label}
+              </button>
+            ))}
+          </div>
+        )}
         <div className="mt-1 text-center">
-          <span className={`text-[10px] ${colors.isDark ? 'text-slate-600' : 'text-slate-400'}`}>直接提问由小韭菜安排韭菜专家，@ 可指定韭菜专家</span>
+          <span className={`text-[10px] ${colors.isDark ? 'text-slate-600' : 'text-slate-400'}`}>直接提问由小韭菜安排韭菜专家，@ 可指定韭菜专家{debateMode && '，辩论模式已开启'}</span>
+          {costEstimate && (
+            <span
+              className={`ml-2 text-[10px] font-mono cursor-help ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}
+              title={[
+                `区间 ${formatTokenCount(costEstimate.minTokens)}~${formatTokenCount(costEstimate.maxTokens)} tokens` +
+                  (costEstimate.maxCost > 0 ? `，¥${formatCost(costEstimate.minCost)}~¥${formatCost(costEstimate.maxCost)}` : ''),
+                ...costEstimate.agents.map(a => `${a.agentName}：${formatTokenCount(a.promptTokens + a.completionTokens)} tokens，${a.requests} 次请求`),
+                ...costEstimate.assumptions.map(item => `· ${item}`),
+              ].join('\n')}
+            >
+              预计消耗 ~{formatTokenCount(costEstimate.totalTokens)} tokens{costEstimate.cost > 0 && ` (~¥${formatCost(costEstimate.cost)})`}
+            </span>
+          )}
         </div>
       </div>
 
+      {/* 会议预演结果 */}
+      {dryRunResult && (
+        <div className="absolute inset-0 bg-black/50 flex items-center justify-center z-50 backdrop-blur-sm rounded-lg" onClick={() => setDryRunResult(null)}>
+          <div className="fin-panel border fin-divider rounded-xl p-5 w-[28rem] max-h-[80%] overflow-y-auto shadow-2xl animate-in fade-in zoom-in-95 duration-200" onClick={e => e.stopPropagation()}>
+            <div className="flex items-center justify-between mb-3">
+              <h3 className={`font-medium flex items-center gap-2 ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>
+                <ListChecks size={16} /> 会议预演
+              </h3>
+              <button onClick={() => setDryRunResult(null)} className={colors.isDark ? 'text-slate-500 hover:text-slate-300' : 'text-slate-400 hover:text-slate-600'}>
+                <X size={16} />
+              </button>
+            </div>
+            <div className="space-y-3">
+              {dryRunResult.plans.map(plan => (
+                <div key={plan.agentId} className="border fin-divider rounded-lg p-3">
+                  <div className={`text-xs font-bold mb-1 ${plan.error ? 'text-red-400' : (colors.isDark ? 'text-slate-300' : 'text-slate-600')}`}>{plan.agentName}</div>
+                  {plan.error ? (
+                    <div className="text-xs text-red-400">预演失败：{plan.error}</div>
+                  ) : (
+                    <>
+                      {plan.plannedToolCalls.length > 0 ? (
+                        <ol className="list-decimal list-inside text-xs font-mono space-y-0.5 mb-1">
+                          {plan.plannedToolCalls.map((call, i) => (
+                            <li key={i} className={colors.isDark ? 'text-amber-400/80' : 'text-amber-600'}>{call}</li>
+                          ))}
+                        </ol>
+                      ) : (
+                        <div className={`text-xs mb-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>不调用工具</div>
+                      )}
+                      {plan.reasoning && (
+                        <div className={`text-xs whitespace-pre-wrap ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>{plan.reasoning}</div>
+                      )}
+                    </>
+                  )}
+                </div>
+              ))}
+            </div>
+          </div>
+        </div>
+      )}
+
       {/* 清空确认弹窗 */}
       {showClearConfirm && (
         <div className="absolute inset-0 bg-black/50 flex items-center justify-center z-50 backdrop-blur-sm rounded-lg">
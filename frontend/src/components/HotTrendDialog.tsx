@@ -1,6 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import { X, TrendingUp, RefreshCw, ExternalLink } from 'lucide-react';
 import { GetAllHotTrends, OpenURL } from '../../wailsjs/go/main/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 import { hottrend } from '../../wailsjs/go/models';
 import { useTheme } from '../contexts/ThemeContext';
 
@@ -35,6 +36,15 @@ export const HotTrendDialog: React.FC<HotTrendDialogProps> = ({ isOpen, onClose
     }
   }, [isOpen]);
 
+  // 首次返回时仍在加载的平台，完成后单独推送
+  useEffect(() => {
+    if (!isOpen) return;
+    const cleanup = EventsOn('hottrend:update', (update: hottrend.HotTrendResult) => {
+      setResults(prev => prev.map(r => (r.platform === update.platform ? update : r)));
+    });
+    return () => cleanup();
+  }, [isOpen]);
+
   if (!isOpen) return null;
 
   const currentResult = results.find(r => r.platform === selectedPlatform);
@@ -127,6 +137,8 @@ const PlatformList: React.FC<{
           <div className="text-sm font-medium truncate">{result.platform_cn}</div>
           {result.error ? (
             <div className="text-xs text-red-400">加载失败</div>
+          ) : result.loading && !result.items?.length ? (
+            <div className={`text-xs ${isDark ? 'text-slate-500' : 'text-slate-400'}`}>加载中</div>
           ) : (
             <div className={`text-xs ${isDark ? 'text-slate-500' : 'text-slate-400'}`}>{result.items?.length || 0} 条</div>
           )}
@@ -168,8 +180,22 @@ const HotItemList: React.FC<{
 
   const items = result.items || [];
 
+  if (result.loading && items.length === 0) {
+    return (
+      <div className={`flex-1 flex items-center justify-center gap-2 ${isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+        <RefreshCw className="h-4 w-4 animate-spin" />
+        加载中
+      </div>
+    );
+  }
+
   return (
     <div className="flex-1 overflow-y-auto fin-scrollbar p-3 text-left">
+      {result.stale && (
+        <div className="mb-2 px-3 py-1.5 rounded-lg text-xs text-amber-500 bg-amber-500/10">
+          {result.loading ? '正在刷新，' : '实时获取失败，'}当前为 {new Date(result.updated_at).toLocaleString()} 的缓存数据
+        </div>
+      )}
       {items.map((item, idx) => (
         <HotItemRow key={item.id || idx} item={item} isDark={isDark} />
       ))}
@@ -0,0 +1,157 @@
+import React, { useState, useEffect } from 'react';
+import { X, RefreshCw, Flame } from 'lucide-react';
+import { GetLimitBoardStats } from '../../wailsjs/go/main/App';
+import { models } from '../../wailsjs/go/models';
+import { useCandleColor } from '../contexts/CandleColorContext';
+
+interface LimitBoardDialogProps {
+  isOpen: boolean;
+  onClose: () => void;
+}
+
+// 金额格式化为亿
+const toYi = (v: number) => (v / 1e8).toFixed(2);
+
+export const LimitBoardDialog: React.FC<LimitBoardDialogProps> = ({ isOpen, onClose }) => {
+  const cc = useCandleColor();
+  const [stats, setStats] = useState<models.LimitBoardStats | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [date, setDate] = useState(''); // 空为最近交易日
+  const [tab, setTab] = useState<'up' | 'down'>('up');
+  const [error, setError] = useState('');
+
+  const load = async (d: string) => {
+    setLoading(true);
+    setError('');
+    try {
+      const result = await GetLimitBoardStats(d);
+      setStats(result);
+      if (!result) setError('获取涨跌停数据失败，请检查日期是否为交易日');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    if (isOpen) load(date);
+  }, [isOpen]);
+
+  if (!isOpen) return null;
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center">
+      <div className="absolute inset-0 bg-black/60 backdrop-blur-sm" onClick={onClose} />
+      <div className="relative w-[820px] h-[640px] fin-panel border fin-divider rounded-xl shadow-2xl flex flex-col overflow-hidden">
+        <div className="flex items-center justify-between px-5 py-4 border-b fin-divider">
+          <div className="flex items-center gap-3">
+            <Flame className="w-5 h-5 text-red-500" />
+            <h2 className="text-lg font-semibold fin-text-primary">涨跌停板</h2>
+            {stats && (
+              <span className="text-xs fin-text-tertiary">
+                {stats.date} · {stats.final ? '已收盘' : '盘中（60秒刷新）'}
+              </span>
+            )}
+          </div>
+          <div className="flex items-center gap-3">
+            <input
+              type="date"
+              value={date}
+              onChange={e => { setDate(e.target.value); load(e.target.value); }}
+              className="fin-input border fin-divider rounded-lg px-2 py-1 text-sm"
+              title="不选为最近交易日"
+            />
+            <button onClick={() => load(date)} className="p-2 rounded-lg hover:bg-slate-500/10 fin-text-secondary" title="刷新">
+              <RefreshCw className={`w-4 h-4 ${loading ? 'animate-spin' : ''}`} />
+            </button>
+            <button onClick={onClose} className="p-2 rounded-lg hover:bg-slate-500/10 fin-text-secondary">
+              <X className="w-4 h-4" />
+            </button>
+          </div>
+        </div>
+
+        {error && <div className="px-5 py-3 text-sm text-red-400">{error}</div>}
+
+        {stats && (
+          <>
+            <div className="grid grid-cols-4 gap-3 px-5 py-4">
+              {[
+                { label: '涨停', value: `${stats.limitUpCount}`, className: cc.upClass },
+                { label: '跌停', value: `${stats.limitDownCount}`, className: cc.downClass },
+                { label: '炸板率', value: `${stats.brokenRatio.toFixed(1)}%`, hint: `炸板 ${stats.brokenCount} 家` },
+                { label: '连板高度', value: `${stats.maxBoards} 板` },
+              ].map(card => (
+                <div key={card.label} className="rounded-lg border fin-divider p-3">
+                  <div className="text-xs fin-text-tertiary">{card.label}</div>
+                  <div className={`text-xl font-bold font-mono ${card.className || 'fin-text-primary'}`}>{card.value}</div>
+                  {card.hint && <div className="text-[10px] fin-text-tertiary">{card.hint}</div>}
+                </div>
+              ))}
+            </div>
+
+            {stats.ladder.length > 0 && (
+              <div className="flex flex-wrap gap-2 px-5 pb-3">
+                {stats.ladder.map(t => (
+                  <span key={t.boards} className="text-xs px-2 py-0.5 rounded bg-red-500/10 text-red-400 border border-red-500/30">
+                    {t.boards}板 × {t.count}
+                  </span>
+                ))}
+              </div>
+            )}
+
+            <div className="flex gap-2 px-5 border-b fin-divider">
+              {(['up', 'down'] as const).map(k => (
+                <button
+                  key={k}
+                  onClick={() => setTab(k)}
+                  className={`px-3 py-2 text-sm border-b-2 transition-colors ${tab === k ? 'border-accent text-accent' : 'border-transparent fin-text-secondary'}`}
+                >
+                  {k === 'up' ? `涨停 ${stats.limitUpCount}` : `跌停 ${stats.limitDownCount}`}
+                </button>
+              ))}
+            </div>
+
+            <div className="flex-1 overflow-y-auto fin-scrollbar px-5 py-2">
+              <table className="w-full text-sm">
+                <thead className="text-xs fin-text-tertiary">
+                  {tab === 'up' ? (
+                    <tr className="text-left">
+                      <th className="py-1">名称</th><th>连板</th><th>封单(亿)</th><th>成交(亿)</th><th>首封</th><th>炸板</th><th>行业</th>
+                    </tr>
+                  ) : (
+                    <tr className="text-left">
+                      <th className="py-1">名称</th><th>连续跌停</th><th>封单(亿)</th><th>成交(亿)</th><th>最后封板</th><th>行业</th>
+                    </tr>
+                  )}
+                </thead>
+                <tbody className="fin-text-primary">
+                  {tab === 'up'
+                    ? stats.limitUp.map(s => (
+                      <tr key={s.code} className="border-t fin-divider">
+                        <td className="py-1.5">{s.name} <span className="text-xs fin-text-tertiary font-mono">{s.code}</span></td>
+                        <td className={`font-mono ${s.boards > 1 ? cc.upClass : ''}`}>{s.boards}</td>
+                        <td className="font-mono">{toYi(s.sealAmount)}</td>
+                        <td className="font-mono">{toYi(s.amount)}</td>
+                        <td className="font-mono">{s.firstSealTime}</td>
+                        <td className="font-mono">{s.breakCount || ''}</td>
+                        <td className="text-xs fin-text-secondary">{s.industry}</td>
+                      </tr>
+                    ))
+                    : stats.limitDown.map(s => (
+                      <tr key={s.code} className="border-t fin-divider">
+                        <td className="py-1.5">{s.name} <span className="text-xs fin-text-tertiary font-mono">{s.code}</span></td>
+                        <td className={`font-mono ${s.days > 1 ? cc.downClass : ''}`}>{s.days}</td>
+                        <td className="font-mono">{toYi(s.sealAmount)}</td>
+                        <td className="font-mono">{toYi(s.amount)}</td>
+                        <td className="font-mono">{s.lastSealTime}</td>
+                        <td className="text-xs fin-text-secondary">{s.industry}</td>
+                      </tr>
+                    ))}
+                </tbody>
+              </table>
+            </div>
+          </>
+        )}
+      </div>
+    </div>
+  );
+};
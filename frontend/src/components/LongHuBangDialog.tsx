@@ -1,8 +1,9 @@
 import React, { useState, useEffect } from 'react';
-import { X, TrendingUp, RefreshCw, Calendar, ChevronDown } from 'lucide-react';
+import { X, TrendingUp, RefreshCw, Calendar, ChevronDown, Download } from 'lucide-react';
 import { GetLongHuBangList, GetLongHuBangDetail, GetTradeDates } from '../../wailsjs/go/main/App';
 import { models } from '../../wailsjs/go/models';
 import { useCandleColor } from '../contexts/CandleColorContext';
+import { exportLongHuBangCSV, onExportProgress } from '../services/exportService';
 
 interface LongHuBangDialogProps {
   isOpen: boolean;
@@ -20,6 +21,7 @@ export const LongHuBangDialog: React.FC<LongHuBangDialogProps> = ({ isOpen, onCl
   const [tradeDates, setTradeDates] = useState<string[]>([]);
   const [pageNumber, setPageNumber] = useState(1);
   const [hasMore, setHasMore] = useState(true);
+  const [exporting, setExporting] = useState('');
   const pageSize = 30;
 
   const loadList = async (page: number, date: string, append = false) => {
@@ -80,6 +82,25 @@ export const LongHuBangDialog: React.FC<LongHuBangDialogProps> = ({ isOpen, onCl
     loadList(1, date, false);
   };
 
+  const handleExport = async () => {
+    if (exporting) return;
+    setExporting('导出中...');
+    const off = onExportProgress((p) => {
+      if (p.kind === 'longhubang' && p.total > 0) {
+        setExporting(`导出中 ${p.done}/${p.total}`);
+      }
+    });
+    try {
+      const result = await exportLongHuBangCSV(tradeDate);
+      if (result !== 'success' && result !== 'cancelled') {
+        console.error('[LongHuBang] 导出失败:', result);
+      }
+    } finally {
+      off();
+      setExporting('');
+    }
+  };
+
   const handleLoadMore = () => {
     if (!loadingMore && hasMore) {
       const nextPage = pageNumber + 1;
@@ -101,6 +122,8 @@ export const LongHuBangDialog: React.FC<LongHuBangDialogProps> = ({ isOpen, onCl
           tradeDate={tradeDate}
           tradeDates={tradeDates}
           onDateChange={handleDateChange}
+          onExport={handleExport}
+          exporting={exporting}
         />
         <div className="flex-1 flex overflow-hidden">
           <ItemList
@@ -219,7 +242,9 @@ const DialogHeader: React.FC<{
   tradeDate: string;
   tradeDates: string[];
   onDateChange: (date: string) => void;
-}> = ({ onClose, onRefresh, loading, tradeDate, tradeDates, onDateChange }) => (
+  onExport: () => void;
+  exporting: string;
+}> = ({ onClose, onRefresh, loading, tradeDate, tradeDates, onDateChange, onExport, exporting }) => (
   <div className="flex items-center justify-between px-5 py-4 border-b fin-divider">
     <div className="flex items-center gap-3">
       <TrendingUp className="w-5 h-5 text-red-500" />
@@ -238,6 +263,15 @@ const DialogHeader: React.FC<{
       >
         <RefreshCw className={`w-4 h-4 fin-text-secondary ${loading ? 'animate-spin' : ''}`} />
       </button>
+      <button
+        onClick={onExport}
+        disabled={!!exporting || !tradeDate}
+        className="flex items-center gap-1 p-2 rounded-lg fin-hover transition-colors text-xs fin-text-secondary"
+        title="导出 CSV"
+      >
+        <Download className="w-4 h-4" />
+        {exporting && <span>{exporting}</span>}
+      </button>
       <button onClick={onClose} className="p-2 rounded-lg fin-hover transition-colors">
         <X className="w-4 h-4 fin-text-secondary" />
       </button>
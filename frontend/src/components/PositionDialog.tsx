@@ -1,9 +1,16 @@
 import React, { useState, useEffect } from 'react';
-import { X, Briefcase } from 'lucide-react';
+import { X, Briefcase, Pencil, Trash2, Check } from 'lucide-react';
 import type { StockPosition } from '../types';
+import { getActionJournal, updateActionJournalEntry, deleteActionJournalEntry, type ActionEntry } from '../services/sessionService';
 import { useTheme } from '../contexts/ThemeContext';
 import { useCandleColor } from '../contexts/CandleColorContext';
 
+const ACTION_LABELS: Record<ActionEntry['action'], string> = {
+  buy: '买入',
+  sell: '卖出',
+  hold: '持有',
+};
+
 interface PositionDialogProps {
   isOpen: boolean;
   onClose: () => void;
@@ -27,6 +34,15 @@ export const PositionDialog: React.FC<PositionDialogProps> = ({
   const cc = useCandleColor();
   const [shares, setShares] = useState<string>('');
   const [costPrice, setCostPrice] = useState<string>('');
+  const [journal, setJournal] = useState<ActionEntry[]>([]);
+  const [editing, setEditing] = useState<ActionEntry | null>(null);
+
+  // 加载对话中记录的实际操作（新的在前）
+  useEffect(() => {
+    if (!isOpen) return;
+    setEditing(null);
+    getActionJournal(stockCode).then(entries => setJournal([...entries].reverse()));
+  }, [isOpen, stockCode]);
 
   useEffect(() => {
     if (isOpen && position) {
@@ -57,6 +73,20 @@ export const PositionDialog: React.FC<PositionDialogProps> = ({
     onClose();
   };
 
+  const handleSaveEntry = async () => {
+    if (!editing) return;
+    if (await updateActionJournalEntry(stockCode, editing) === 'success') {
+      setJournal(prev => prev.map(e => e.id === editing.id ? { ...editing, edited: true } : e));
+    }
+    setEditing(null);
+  };
+
+  const handleDeleteEntry = async (id: string) => {
+    if (await deleteActionJournalEntry(stockCode, id) === 'success') {
+      setJournal(prev => prev.filter(e => e.id !== id));
+    }
+  };
+
   return (
     <div className="fixed inset-0 z-50 flex items-center justify-center">
       <div className="absolute inset-0 bg-black/60" onClick={onClose} />
@@ -134,6 +164,81 @@ export const PositionDialog: React.FC<PositionDialogProps> = ({
           )}
         </div>
 
+        {/* 操作日志：对话中陈述的实际买卖决策 */}
+        {journal.length > 0 && (
+          <div className="px-4 pb-4 text-left">
+            <div className={`text-sm mb-2 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>操作日志</div>
+            <div className="max-h-48 overflow-y-auto space-y-1.5">
+              {journal.map(entry => editing?.id === entry.id ? (
+                <div key={entry.id} className={`p-2 rounded-lg space-y-1.5 text-xs ${colors.isDark ? 'bg-slate-800/50' : 'bg-slate-100'}`}>
+                  <div className="flex gap-1.5">
+                    <select
+                      value={editing.action}
+                      onChange={e => setEditing({ ...editing, action: e.target.value as ActionEntry['action'] })}
+                      className="fin-input rounded px-1.5 py-1"
+                    >
+                      {Object.entries(ACTION_LABELS).map(([value, label]) => <option key={value} value={value}>{label}</option>)}
+                    </select>
+                    <input
+                      type="number"
+                      value={editing.price || ''}
+                      onChange={e => setEditing({ ...editing, price: parseFloat(e.target.value) || 0 })}
+                      placeholder="价格"
+                      className="w-20 fin-input rounded px-1.5 py-1"
+                      min="0"
+                      step="0.01"
+                    />
+                    <input
+                      value={editing.quantity || ''}
+                      onChange={e => setEditing({ ...editing, quantity: e.target.value })}
+                      placeholder="数量"
+                      className="w-20 fin-input rounded px-1.5 py-1"
+                    />
+                  </div>
+                  <div className="flex gap-1.5">
+                    <input
+                      value={editing.note}
+                      onChange={e => setEditing({ ...editing, note: e.target.value })}
+                      placeholder="说明"
+                      className="flex-1 fin-input rounded px-1.5 py-1"
+                    />
+                    <button onClick={handleSaveEntry} className="p-1 rounded text-accent-2 hover:bg-accent/10" title="保存">
+                      <Check className="h-3.5 w-3.5" />
+                    </button>
+                    <button onClick={() => setEditing(null)} className={`p-1 rounded ${colors.isDark ? 'text-slate-400 hover:bg-slate-700' : 'text-slate-500 hover:bg-slate-200'}`} title="取消">
+                      <X className="h-3.5 w-3.5" />
+                    </button>
+                  </div>
+                </div>
+              ) : (
+                <div key={entry.id} className={`group flex items-start gap-2 p-2 rounded-lg text-xs ${colors.isDark ? 'bg-slate-800/50' : 'bg-slate-100'}`} title={entry.source}>
+                  <span className={`shrink-0 font-medium ${entry.action === 'hold' ? (colors.isDark ? 'text-slate-300' : 'text-slate-600') : cc.getColorClass(entry.action === 'buy')}`}>
+                    {ACTION_LABELS[entry.action] || entry.action}
+                  </span>
+                  <div className="flex-1 min-w-0">
+                    <div className={colors.isDark ? 'text-slate-200' : 'text-slate-700'}>
+                      {entry.price ? <span className="font-mono mr-1.5">{entry.price.toFixed(2)}</span> : null}
+                      {entry.quantity && <span className="mr-1.5">{entry.quantity}</span>}
+                      {entry.note}
+                    </div>
+                    <div className={colors.isDark ? 'text-slate-500' : 'text-slate-400'}>
+                      {new Date(entry.timestamp).toLocaleString()}{entry.edited ? ' · 已修改' : ''}
+                    </div>
+                  </div>
+                  <div className="flex gap-0.5 opacity-0 group-hover:opacity-100 transition-opacity">
+                    <button onClick={() => setEditing({ ...entry })} className={`p-1 rounded ${colors.isDark ? 'text-slate-400 hover:bg-slate-700' : 'text-slate-500 hover:bg-slate-200'}`} title="编辑">
+                      <Pencil className="h-3.5 w-3.5" />
+                    </button>
+                    <button onClick={() => handleDeleteEntry(entry.id)} className="p-1 rounded text-red-400 hover:bg-red-500/10" title="删除">
+                      <Trash2 className="h-3.5 w-3.5" />
+                    </button>
+                  </div>
+                </div>
+              ))}
+            </div>
+          </div>
+        )}
+
         {/* Footer */}
         <div className="flex gap-2 p-4 border-t fin-divider">
           {position && position.shares > 0 && (
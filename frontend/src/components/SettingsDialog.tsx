@@ -1,10 +1,10 @@
 import React, { useState, useEffect, useCallback, useRef } from 'react';
-import { X, Cpu, ChevronLeft, Plug, Plus, Trash2, Wrench, Check, Loader2, Brain, RefreshCw, Download, RotateCcw, Globe, Layers, Sliders, Star, MessageSquare, Copy, Sparkles } from 'lucide-react';
-import { getConfig, updateConfig, getAvailableTools, ToolInfo, testAIConnection } from '../services/configService';
+import { X, Cpu, ChevronLeft, Plug, Plus, Trash2, Wrench, Check, Loader2, Brain, RefreshCw, Download, RotateCcw, Globe, Layers, Sliders, Star, MessageSquare, Copy, Sparkles, Search } from 'lucide-react';
+import { getConfig, updateConfig, getAvailableTools, ToolInfo, testAIConnection, onConfigReferencesFixed, getAICapabilities, getAPITokens, createAPIToken, revokeAPIToken, APIToken, APIScope, API_SCOPE_LABELS } from '../services/configService';
 import { getAgentConfigs } from '../services/strategyService';
-import { getMCPServers, MCPServerConfig, MCPServerStatus, testMCPConnection, getMCPServerTools, MCPToolInfo } from '../services/mcpService';
+import { getMCPServers, MCPServerConfig, MCPServerStatus, testMCPConnection, getMCPServerTools, refreshMCPToolCatalog, MCPToolInfo } from '../services/mcpService';
 import { checkForUpdate, doUpdate, restartApp, getCurrentVersion, onUpdateProgress, UpdateInfo, UpdateProgress } from '../services/updateService';
-import { getStrategies, getActiveStrategyID, setActiveStrategy, deleteStrategy, generateStrategy, updateStrategy, enhancePrompt, Strategy, StrategyAgent } from '../services/strategyService';
+import { getStrategies, getActiveStrategyID, setActiveStrategy, deleteStrategy, generateStrategy, updateStrategy, enhancePrompt, getStrategyHistory, restoreStrategyVersion, lintStrategy, Strategy, StrategyAgent, StrategyVersion, AgentLintIssue, OUTPUT_LANGUAGES, STRATEGY_CONFLICT } from '../services/strategyService';
 import { useTheme } from '../contexts/ThemeContext';
 import { useCandleColor, CandleColorMode } from '../contexts/CandleColorContext';
 import { useIndicator, IndicatorConfig, IndicatorType, DEFAULT_INDICATORS } from '../contexts/IndicatorContext';
@@ -18,14 +18,34 @@ interface AIConfig {
   modelName: string;
   maxTokens: number;
   temperature: number;
-  timeout: number;
+  timeout: number; // 单次请求超时秒数（0 使用默认 120s）
+  // 临时错误重试次数（0 默认 3 次，-1 关闭）与退避基础延迟毫秒（0 默认 1000）
+  retryMaxAttempts?: number;
+  retryBaseDelayMs?: number;
   isDefault: boolean;
   // OpenAI Responses API 开关
   useResponses: boolean;
+  // 每轮回答前强制先调用的工具（仅 Responses API）
+  forcedToolName?: string;
+  // Azure OpenAI 专用字段
+  isAzure?: boolean;
+  apiVersion?: string;
+  deploymentName?: string;
+  // Anthropic 扩展思考预算（0 关闭）
+  thinkingBudget?: number;
+  // Anthropic 提示缓存
+  enablePromptCache?: boolean;
+  // 实测的能力支持情况，由后端探测与 400 错误学习
+  capabilities?: Record<string, boolean>;
+  // 千 token 单价（会议费用估算）
+  inputPricePer1K?: number;
+  outputPricePer1K?: number;
   // Vertex AI 专用字段
   project: string;
   location: string;
   credentialsJson: string;
+  // Vertex AI Model Garden 模型发布方（如 meta、mistralai）
+  publisher?: string;
 }
 
 interface MemoryConfig {
@@ -44,6 +64,19 @@ type ProxyMode = 'none' | 'system' | 'custom';
 interface ProxyConfig {
   mode: ProxyMode;
   customUrl: string;
+  proxyUsername: string;
+  proxyPassword: string;
+}
+
+// 网页搜索服务类型
+type SearchProvider = '' | 'tavily' | 'serper' | 'bing';
+
+// 网页搜索配置接口（内置 web_search 工具）
+interface SearchConfig {
+  provider: SearchProvider;
+  apiKey: string;
+  maxResults: number;
+  maxCallsPerMeeting: number;
 }
 
 // OpenClaw 配置接口
@@ -53,7 +86,7 @@ interface OpenClawConfig {
   apiKey: string;
 }
 
-type TabType = 'provider' | 'intent' | 'strategy' | 'mcp' | 'memory' | 'chart' | 'proxy' | 'openclaw' | 'update';
+type TabType = 'provider' | 'intent' | 'strategy' | 'mcp' | 'memory' | 'chart' | 'proxy' | 'search' | 'openclaw' | 'update';
 
 interface SettingsDialogProps {
   isOpen: boolean;
@@ -103,6 +136,14 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
   const [proxyConfig, setProxyConfig] = useState<ProxyConfig>({
     mode: 'none',
     customUrl: '',
+    proxyUsername: '',
+    proxyPassword: '',
+  });
+  const [searchConfig, setSearchConfig] = useState<SearchConfig>({
+    provider: '',
+    apiKey: '',
+    maxResults: 0,
+    maxCallsPerMeeting: 0,
   });
   const [openClawConfig, setOpenClawConfig] = useState<OpenClawConfig>({
     enabled: false,
@@ -123,6 +164,25 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
     }
   }, [isOpen]);
 
+  // 删除被引用的 AI 配置后，后端会清理失效引用，这里同步本地状态避免再次保存旧 ID
+  useEffect(() => {
+    if (!isOpen) return;
+    return onConfigReferencesFixed(fixes => {
+      for (const fix of fixes) {
+        if (fix.field === 'moderatorAiId') setModeratorAiId(fix.newId);
+        if (fix.field === 'strategyAiId') setStrategyAiId(fix.newId);
+        if (fix.field === 'memory.aiConfigId') setMemoryConfig(prev => ({ ...prev, aiConfigId: fix.newId }));
+      }
+      const notice = `${fixes.map(f => f.label).join('、')}引用的模型已删除，已改用默认模型`;
+      // 保存过程中收到时留给 doSave 展示，避免被“已保存”覆盖
+      if (savingRef.current) {
+        refsNoticeRef.current = notice;
+      } else {
+        showToast('success', notice);
+      }
+    });
+  }, [isOpen, showToast]);
+
   const loadAllConfigs = async () => {
     const config = await getConfig();
     setAiConfigs(config.aiConfigs || []);
@@ -133,6 +193,16 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
       setProxyConfig({
         mode: config.proxy.mode as ProxyMode,
         customUrl: config.proxy.customUrl || '',
+        proxyUsername: config.proxy.proxyUsername || '',
+        proxyPassword: config.proxy.proxyPassword || '',
+      });
+    }
+    if (config.search) {
+      setSearchConfig({
+        provider: (config.search.provider || '') as SearchProvider,
+        apiKey: config.search.apiKey || '',
+        maxResults: config.search.maxResults || 0,
+        maxCallsPerMeeting: config.search.maxCallsPerMeeting || 0,
       });
     }
     if (config.openClaw) {
@@ -142,8 +212,8 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
         apiKey: config.openClaw.apiKey || '',
       });
     }
-    if (config.moderatorAiId) setModeratorAiId(config.moderatorAiId);
-    if (config.strategyAiId) setStrategyAiId(config.strategyAiId);
+    setModeratorAiId(config.moderatorAiId || '');
+    setStrategyAiId(config.strategyAiId || '');
 
     // 加载策略配置
     const loadedStrategies = await getStrategies();
@@ -166,12 +236,15 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
   };
 
   // 防抖保存的 ref
+  const savingRef = useRef(false);
+  const refsNoticeRef = useRef('');
   const saveTimerRef = useRef<ReturnType<typeof setTimeout> | null>(null);
   const pendingUpdatesRef = useRef<Partial<{
     aiConfigs: AIConfig[];
     mcpServers: MCPServerConfig[];
     memory: MemoryConfig;
     proxy: ProxyConfig;
+    search: SearchConfig;
     moderatorAiId: string;
     strategyAiId: string;
     indicators: any;
@@ -183,6 +256,7 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
     if (Object.keys(updates).length === 0) return;
 
     showToast('loading', '保存中...');
+    savingRef.current = true;
     try {
       const currentConfig = await getConfig();
       await updateConfig({
@@ -192,10 +266,13 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
       } as any);
       pendingUpdatesRef.current = {};
       hideToast();
-      showToast('success', '已保存');
+      showToast('success', refsNoticeRef.current || '已保存');
     } catch (e) {
       hideToast();
       showToast('error', '保存失败');
+    } finally {
+      savingRef.current = false;
+      refsNoticeRef.current = '';
     }
   }, [showToast, hideToast]);
 
@@ -205,6 +282,7 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
     mcpServers: MCPServerConfig[];
     memory: MemoryConfig;
     proxy: ProxyConfig;
+    search: SearchConfig;
     openClaw: OpenClawConfig;
     moderatorAiId: string;
     strategyAiId: string;
@@ -246,6 +324,7 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
     { id: 'memory', label: '记忆管理', icon: <Brain className="h-4 w-4" /> },
     { id: 'chart', label: '图表设置', icon: <Sliders className="h-4 w-4" /> },
     { id: 'proxy', label: '网络代理', icon: <Globe className="h-4 w-4" /> },
+    { id: 'search', label: '网页搜索', icon: <Search className="h-4 w-4" /> },
     { id: 'openclaw', label: 'OpenClaw', icon: <Plug className="h-4 w-4" /> },
     { id: 'update', label: '软件更新', icon: <RefreshCw className="h-4 w-4" /> },
   ];
@@ -331,6 +410,7 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
                   const status = await testMCPConnection(id);
                   setMcpStatus(prev => ({ ...prev, [id]: status }));
                   if (status.connected) {
+                    await refreshMCPToolCatalog(id);
                     const tools = await getMCPServerTools(id);
                     setMcpTools(prev => ({ ...prev, [id]: tools || [] }));
                   }
@@ -360,6 +440,15 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
                 }}
               />
             )}
+            {activeTab === 'search' && (
+              <SearchSettings
+                config={searchConfig}
+                onChange={(config) => {
+                  setSearchConfig(config);
+                  saveConfig({ search: config });
+                }}
+              />
+            )}
             {activeTab === 'openclaw' && (
               <OpenClawSettings
                 config={openClawConfig}
@@ -408,14 +497,20 @@ const Header: React.FC<{ onClose: () => void }> = ({ onClose }) => {
 };
 
 // ========== Provider 设置选项卡 ==========
-const PROVIDERS = ['openai', 'gemini', 'vertexai', 'anthropic'] as const;
+const PROVIDERS = ['openai', 'gemini', 'vertexai', 'vertexai_garden', 'anthropic', 'bedrock', 'ollama', 'deepseek', 'qwen', 'mistral'] as const;
 type ProviderType = typeof PROVIDERS[number];
 
 const PROVIDER_LABELS: Record<ProviderType, string> = {
   openai: 'OpenAI',
   gemini: 'Gemini',
   vertexai: 'Vertex AI',
+  vertexai_garden: 'Vertex AI Model Garden',
   anthropic: 'Anthropic',
+  bedrock: 'AWS Bedrock',
+  ollama: 'Ollama',
+  deepseek: 'DeepSeek',
+  qwen: '通义千问',
+  mistral: 'Mistral',
 };
 
 interface ProviderSettingsProps {
@@ -447,7 +542,7 @@ const ProviderSettings: React.FC<ProviderSettingsProps> = ({ configs, onChange,
       modelName: getDefaultModel(newProviderType),
       maxTokens: 2048,
       temperature: 0.7,
-      timeout: 60,
+      timeout: 0,
       isDefault: configs.length === 0,
       useResponses: false,
       project: '',
@@ -765,9 +860,20 @@ const ProviderEditView: React.FC<ProviderEditViewProps> = ({
   config, onBack, onChange, onDelete
 }) => {
   const { colors } = useTheme();
-  const isVertexAI = config.provider === 'vertexai';
+  const isVertexAI = config.provider === 'vertexai' || config.provider === 'vertexai_garden';
+  const isBedrock = config.provider === 'bedrock';
   const [testing, setTesting] = useState(false);
   const [testResult, setTestResult] = useState<{ success: boolean; error?: string } | null>(null);
+  const [capabilities, setCapabilities] = useState<{ capability: string; label: string; supported: boolean; learned: boolean }[]>([]);
+
+  const loadCapabilities = useCallback(() => {
+    getAICapabilities(config as any).then(setCapabilities).catch(() => setCapabilities([]));
+    // 能力只取决于 Provider 与已保存的学习结果
+  }, [config.id, config.provider]);
+
+  useEffect(() => {
+    loadCapabilities();
+  }, [loadCapabilities]);
 
   const handleTestConnection = async () => {
     setTesting(true);
@@ -779,6 +885,8 @@ const ProviderEditView: React.FC<ProviderEditViewProps> = ({
       setTestResult({ success: false, error: e.message || '未知错误' });
     } finally {
       setTesting(false);
+      // 测试连接会探测能力，刷新展示
+      loadCapabilities();
     }
   };
 
@@ -847,10 +955,12 @@ const ProviderEditView: React.FC<ProviderEditViewProps> = ({
       <div className="space-y-4">
         <FormField label="配置名称" value={config.name} onChange={v => onChange({ ...config, name: v })} />
 
-        {!isVertexAI && (
+        {!isVertexAI && !isBedrock && (
           <>
             <FormField label="Base URL" value={config.baseUrl} onChange={v => onChange({ ...config, baseUrl: v })} />
-            <FormField label="API Key" value={config.apiKey} onChange={v => onChange({ ...config, apiKey: v })} type="password" />
+            {config.provider !== 'ollama' && (
+              <FormField label="API Key" value={config.apiKey} onChange={v => onChange({ ...config, apiKey: v })} type="password" />
+            )}
           </>
         )}
 
@@ -861,10 +971,64 @@ const ProviderEditView: React.FC<ProviderEditViewProps> = ({
           </div>
         )}
 
+        {config.provider === 'openai' && config.useResponses && (
+          <FormField label="强制先调用的工具（可选，如 get_research_report）" value={config.forcedToolName || ''} onChange={v => onChange({ ...config, forcedToolName: v })} />
+        )}
+
+        {config.provider === 'openai' && (
+          <div className="flex items-center justify-between">
+            <label className={`text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>Azure OpenAI</label>
+            <ToggleSwitch checked={!!config.isAzure} onChange={v => onChange({ ...config, isAzure: v })} />
+          </div>
+        )}
+
+        {config.provider === 'openai' && config.isAzure && (
+          <>
+            <FormField label="部署名称（为空则使用模型名称）" value={config.deploymentName || ''} onChange={v => onChange({ ...config, deploymentName: v })} />
+            <FormField label="API Version（为空则使用 2024-10-21）" value={config.apiVersion || ''} onChange={v => onChange({ ...config, apiVersion: v })} />
+          </>
+        )}
+
+        {isBedrock && (
+          <>
+            <FormField label="AWS 区域" value={config.awsRegion || ''} onChange={v => onChange({ ...config, awsRegion: v })} />
+            <FormField label="Access Key ID" value={config.awsAccessKeyId || ''} onChange={v => onChange({ ...config, awsAccessKeyId: v })} />
+            <FormField label="Secret Access Key" value={config.awsSecretAccessKey || ''} onChange={v => onChange({ ...config, awsSecretAccessKey: v })} type="password" />
+            <FormField label="Session Token（临时凭证时填写）" value={config.awsSessionToken || ''} onChange={v => onChange({ ...config, awsSessionToken: v })} type="password" />
+            <FormField label="自定义端点（可选，如 VPC 终端节点）" value={config.baseUrl} onChange={v => onChange({ ...config, baseUrl: v })} />
+          </>
+        )}
+
+        {(config.provider === 'anthropic' || isBedrock) && (
+          <div>
+            <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>扩展思考预算 Token</label>
+            <input
+              type="number"
+              min="0"
+              step="1024"
+              value={config.thinkingBudget || ''}
+              onChange={e => onChange({ ...config, thinkingBudget: Math.max(0, parseInt(e.target.value) || 0) })}
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+              placeholder="0"
+            />
+            <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>留空或 0 表示关闭，最小 1024；仅 Claude 3.7 Sonnet 及 Claude 4 系列生效</p>
+          </div>
+        )}
+
+        {(config.provider === 'anthropic' || isBedrock) && (
+          <div className="flex items-center justify-between">
+            <label className={`text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>提示缓存（缓存专家指令与工具定义）</label>
+            <ToggleSwitch checked={!!config.enablePromptCache} onChange={v => onChange({ ...config, enablePromptCache: v })} />
+          </div>
+        )}
+
         {isVertexAI && (
           <>
             <FormField label="GCP 项目 ID" value={config.project || ''} onChange={v => onChange({ ...config, project: v })} />
             <FormField label="区域" value={config.location || ''} onChange={v => onChange({ ...config, location: v })} />
+            {config.provider === 'vertexai_garden' && (
+              <FormField label="模型发布方（如 meta、mistralai）" value={config.publisher || ''} onChange={v => onChange({ ...config, publisher: v })} />
+            )}
             <div>
               <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>服务账号证书 (JSON)</label>
               <textarea
@@ -919,6 +1083,94 @@ const ProviderEditView: React.FC<ProviderEditViewProps> = ({
           <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>建议值：2048-8192，最大取决于模型,设置为0时表示不传递这个参数</p>
         </div>
 
+        {/* 请求超时配置 */}
+        <div>
+          <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>请求超时（秒）</label>
+          <input
+            type="number"
+            min="0"
+            step="10"
+            value={config.timeout || ''}
+            onChange={e => onChange({ ...config, timeout: Math.max(0, parseInt(e.target.value) || 0) })}
+            className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+            placeholder="120"
+          />
+          <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>留空使用默认 120 秒；流式输出按两次数据之间的间隔计时，本地慢模型可适当调大</p>
+        </div>
+
+        {/* 临时错误重试配置 */}
+        <div>
+          <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>限流/服务错误重试（次数 / 基础延迟毫秒）</label>
+          <div className="flex gap-2">
+            <input
+              type="number"
+              min="-1"
+              max="10"
+              value={config.retryMaxAttempts || ''}
+              onChange={e => onChange({ ...config, retryMaxAttempts: Math.max(-1, parseInt(e.target.value) || 0) })}
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+              placeholder="3"
+            />
+            <input
+              type="number"
+              min="0"
+              step="100"
+              value={config.retryBaseDelayMs || ''}
+              onChange={e => onChange({ ...config, retryBaseDelayMs: Math.max(0, parseInt(e.target.value) || 0) })}
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+              placeholder="1000"
+            />
+          </div>
+          <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>遇到 429/500/502/503 时按指数退避加随机抖动重试（最长间隔 16 秒）；次数填 -1 关闭重试</p>
+        </div>
+
+        {/* 单价配置（费用估算） */}
+        <div>
+          <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>千 Token 单价（输入 / 输出）</label>
+          <div className="flex gap-2">
+            <input
+              type="number"
+              min="0"
+              step="0.001"
+              value={config.inputPricePer1K || ''}
+              onChange={e => onChange({ ...config, inputPricePer1K: Math.max(0, parseFloat(e.target.value) || 0) })}
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+              placeholder="输入单价"
+            />
+            <input
+              type="number"
+              min="0"
+              step="0.001"
+              value={config.outputPricePer1K || ''}
+              onChange={e => onChange({ ...config, outputPricePer1K: Math.max(0, parseFloat(e.target.value) || 0) })}
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+              placeholder="输出单价"
+            />
+          </div>
+          <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>用于估算会议费用，留空则只统计 Token</p>
+        </div>
+
+        {/* 能力矩阵（只读） */}
+        {capabilities.length > 0 && (
+          <div>
+            <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>接口能力</label>
+            <div className="flex flex-wrap gap-1.5">
+              {capabilities.map(c => (
+                <span
+                  key={c.capability}
+                  title={c.learned ? '由测试连接或请求错误实测得出' : '服务商默认值'}
+                  className={`text-xs px-2 py-0.5 rounded ${c.supported
+                    ? 'bg-accent/10 text-accent-2'
+                    : (colors.isDark ? 'bg-slate-700/60 text-slate-500 line-through' : 'bg-slate-200 text-slate-400 line-through')}`}
+                >
+                  {c.label}{c.learned && ' · 实测'}
+                </span>
+              ))}
+            </div>
+            <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>不支持的参数在请求时自动忽略；接口拒绝某参数后会自动标记为不支持</p>
+          </div>
+        )}
+
       </div>
     </div>
   );
@@ -1043,6 +1295,10 @@ const getDefaultBaseUrl = (provider: string): string => {
     case 'openai': return 'https://api.openai.com/v1';
     case 'gemini': return 'https://generativelanguage.googleapis.com';
     case 'anthropic': return 'https://api.anthropic.com';
+    case 'ollama': return 'http://localhost:11434';
+    case 'deepseek': return 'https://api.deepseek.com/v1';
+    case 'qwen': return 'https://dashscope.aliyuncs.com/compatible-mode/v1';
+    case 'mistral': return 'https://api.mistral.ai/v1';
     default: return '';
   }
 };
@@ -1052,7 +1308,13 @@ const getDefaultModel = (provider: string): string => {
     case 'openai': return 'gpt-5.2';
     case 'gemini': return 'gemini-2.5-flash';
     case 'vertexai': return 'gemini-2.5-flash';
+    case 'vertexai_garden': return 'llama-3.3-70b-instruct-maas';
     case 'anthropic': return 'claude-sonnet-4-20250514';
+    case 'bedrock': return 'anthropic.claude-sonnet-4-20250514-v1:0';
+    case 'ollama': return 'qwen2.5:7b';
+    case 'deepseek': return 'deepseek-chat';
+    case 'qwen': return 'qwen-plus';
+    case 'mistral': return 'mistral-large-latest';
     default: return '';
   }
 };
@@ -1395,6 +1657,19 @@ const MCPEditForm: React.FC<MCPEditFormProps> = ({ server, status, tools, onBack
         <FormField label="端点 URL" value={edited.endpoint} onChange={v => handleChange('endpoint', v)} />
       )}
 
+      {/* 长耗时工具：调用超时延长，执行进度显示在会议中并可单独取消 */}
+      <FormField
+        label="长耗时工具 (逗号分隔)"
+        value={(edited.longRunningTools || []).join(', ')}
+        onChange={v => handleChange('longRunningTools', v.split(',').map(s => s.trim()).filter(Boolean))}
+      />
+      <FormField
+        label="长耗时工具超时 (秒，默认 600)"
+        type="number"
+        value={edited.longRunningTimeoutSeconds ? String(edited.longRunningTimeoutSeconds) : ''}
+        onChange={v => handleChange('longRunningTimeoutSeconds', Math.max(0, parseInt(v, 10) || 0))}
+      />
+
       {/* 启用状态 */}
       <div className="flex items-center justify-between pt-2">
         <span className={`text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>启用此服务</span>
@@ -1458,6 +1733,9 @@ const MCPEditForm: React.FC<MCPEditFormProps> = ({ server, status, tools, onBack
             <Wrench className={`h-4 w-4 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`} />
             <span className={`text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>可用工具</span>
             <span className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>({tools.length})</span>
+            {tools.some(t => t.stale) && (
+              <span className="text-xs px-2 py-0.5 rounded bg-yellow-500/20 text-yellow-400">缓存数据，刷新中</span>
+            )}
           </div>
           <div className="space-y-2 max-h-40 overflow-y-auto fin-scrollbar">
             {tools.map(tool => (
@@ -1820,6 +2098,131 @@ const ProxySettings: React.FC<ProxySettingsProps> = ({ config, onChange }) => {
           </p>
         </div>
       )}
+
+      {/* 代理认证：自定义与系统代理均可使用 */}
+      {config.mode !== 'none' && (
+        <div className={`pt-4 border-t ${colors.isDark ? 'border-slate-700' : 'border-slate-300'}`}>
+          <label className={`block text-sm mb-2 ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}>
+            代理认证（可选）
+          </label>
+          <div className="grid grid-cols-2 gap-2">
+            <input
+              type="text"
+              value={config.proxyUsername}
+              onChange={(e) => onChange({ ...config, proxyUsername: e.target.value })}
+              placeholder="用户名"
+              autoComplete="off"
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+            />
+            <input
+              type="password"
+              value={config.proxyPassword}
+              onChange={(e) => onChange({ ...config, proxyPassword: e.target.value })}
+              placeholder="密码"
+              autoComplete="new-password"
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+            />
+          </div>
+          <p className={`text-xs mt-2 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+            企业代理需要 Basic 认证时填写；代理地址中已包含 user:pass 时以地址为准
+          </p>
+        </div>
+      )}
+    </div>
+  );
+};
+
+// ========== 网页搜索设置选项卡 ==========
+interface SearchSettingsProps {
+  config: SearchConfig;
+  onChange: (config: SearchConfig) => void;
+}
+
+const SearchSettings: React.FC<SearchSettingsProps> = ({ config, onChange }) => {
+  const { colors } = useTheme();
+  const providers: { value: SearchProvider; label: string; desc: string }[] = [
+    { value: '', label: '不启用', desc: '专家调用 web_search 时提示未配置' },
+    { value: 'tavily', label: 'Tavily', desc: '面向 AI 的搜索 API，摘要质量较好' },
+    { value: 'serper', label: 'Serper', desc: 'Google 搜索结果' },
+    { value: 'bing', label: 'Bing', desc: 'Bing Web Search API' },
+  ];
+  const labelClass = `block text-sm mb-2 ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`;
+  const inputClass = `w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`;
+
+  return (
+    <div className="space-y-6">
+      <div>
+        <h3 className={`font-medium ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>网页搜索</h3>
+        <p className={`text-sm mt-1 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
+          内置 web_search 工具使用的搜索服务，无需配置 MCP；请求经网络代理发出
+        </p>
+      </div>
+
+      <div className="grid grid-cols-2 gap-3">
+        {providers.map(p => (
+          <label
+            key={p.value || 'none'}
+            className={`flex items-start gap-3 p-3 rounded-lg border cursor-pointer transition-all ${
+              config.provider === p.value
+                ? 'border-[var(--accent)] bg-[var(--accent)]/10'
+                : (colors.isDark ? 'border-slate-700 hover:border-slate-600' : 'border-slate-300 hover:border-slate-400')
+            }`}
+          >
+            <input
+              type="radio"
+              name="searchProvider"
+              checked={config.provider === p.value}
+              onChange={() => onChange({ ...config, provider: p.value })}
+              className="mt-1 accent-[var(--accent)]"
+            />
+            <div>
+              <div className={`text-sm font-medium ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>{p.label}</div>
+              <div className={`text-xs mt-0.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>{p.desc}</div>
+            </div>
+          </label>
+        ))}
+      </div>
+
+      {config.provider && (
+        <div className={`pt-4 border-t space-y-4 ${colors.isDark ? 'border-slate-700' : 'border-slate-300'}`}>
+          <div>
+            <label className={labelClass}>API Key</label>
+            <input
+              type="password"
+              value={config.apiKey}
+              onChange={(e) => onChange({ ...config, apiKey: e.target.value })}
+              autoComplete="new-password"
+              className={inputClass}
+            />
+          </div>
+          <div className="grid grid-cols-2 gap-3">
+            <div>
+              <label className={labelClass}>默认返回条数</label>
+              <input
+                type="number"
+                min={0}
+                max={10}
+                value={config.maxResults}
+                onChange={(e) => onChange({ ...config, maxResults: Math.max(0, parseInt(e.target.value) || 0) })}
+                className={inputClass}
+              />
+            </div>
+            <div>
+              <label className={labelClass}>每场会议调用上限</label>
+              <input
+                type="number"
+                min={0}
+                value={config.maxCallsPerMeeting}
+                onChange={(e) => onChange({ ...config, maxCallsPerMeeting: Math.max(0, parseInt(e.target.value) || 0) })}
+                className={inputClass}
+              />
+            </div>
+          </div>
+          <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+            填 0 使用默认值：返回 5 条，每场会议最多调用 5 次（所有专家共用），防止模型循环搜索
+          </p>
+        </div>
+      )}
     </div>
   );
 };
@@ -1924,12 +2327,122 @@ const OpenClawSettings: React.FC<OpenClawSettingsProps> = ({ config, onChange })
               className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
             />
           </div>
+          <APITokenManager />
         </div>
       )}
     </div>
   );
 };
 
+// ========== API 令牌管理 ==========
+const API_SCOPES = Object.keys(API_SCOPE_LABELS) as APIScope[];
+
+const APITokenManager: React.FC = () => {
+  const { colors } = useTheme();
+  const [tokens, setTokens] = useState<APIToken[]>([]);
+  const [name, setName] = useState('');
+  const [scopes, setScopes] = useState<APIScope[]>(['read_market']);
+  const [created, setCreated] = useState<{ name: string; plaintext: string } | null>(null);
+  const [error, setError] = useState('');
+
+  const refresh = useCallback(() => {
+    getAPITokens().then(setTokens);
+  }, []);
+  useEffect(refresh, [refresh]);
+
+  const toggleScope = (scope: APIScope) => {
+    setScopes(prev => prev.includes(scope) ? prev.filter(s => s !== scope) : [...prev, scope]);
+  };
+
+  const handleCreate = async () => {
+    const result = await createAPIToken(name, scopes);
+    if (result.error) {
+      setError(result.error);
+      return;
+    }
+    setError('');
+    setName('');
+    setCreated({ name: result.token.name, plaintext: result.plaintext });
+    refresh();
+  };
+
+  const handleRevoke = async (id: string) => {
+    const result = await revokeAPIToken(id);
+    if (result !== 'success') {
+      setError(result);
+    }
+    refresh();
+  };
+
+  const textColor = colors.isDark ? 'text-white' : 'text-slate-800';
+  const mutedColor = colors.isDark ? 'text-slate-400' : 'text-slate-500';
+
+  return (
+    <div className={`space-y-3 pt-4 border-t ${colors.isDark ? 'border-slate-700' : 'border-slate-300'}`}>
+      <div>
+        <div className={`text-sm font-medium ${textColor}`}>访问令牌</div>
+        <p className={`text-xs mt-1 ${mutedColor}`}>按权限范围授权，请求头携带 Authorization: Bearer &lt;令牌&gt;；吊销后立即失效</p>
+      </div>
+
+      {tokens.map(token => (
+        <div key={token.id} className={`flex items-center justify-between p-2 rounded-lg border ${colors.isDark ? 'border-slate-700' : 'border-slate-300'}`}>
+          <div className="min-w-0">
+            <div className={`text-sm ${textColor}`}>{token.name}</div>
+            <div className={`text-xs ${mutedColor}`}>
+              {(token.scopes || []).map(s => API_SCOPE_LABELS[s as APIScope] ?? s).join('、')}
+              {' · '}
+              {token.lastUsedAt ? `最近使用 ${new Date(token.lastUsedAt).toLocaleString('zh-CN', { hour12: false })}` : '从未使用'}
+            </div>
+          </div>
+          <button onClick={() => handleRevoke(token.id)} className="p-1.5 rounded text-red-400 hover:bg-red-500/10" title="吊销">
+            <Trash2 className="h-4 w-4" />
+          </button>
+        </div>
+      ))}
+
+      {created && (
+        <div className="p-2 rounded-lg border border-yellow-500/50 bg-yellow-500/10 space-y-1">
+          <div className="text-xs text-yellow-400">令牌「{created.name}」已创建，明文只显示这一次，请立即复制保存</div>
+          <div className="flex items-center gap-2">
+            <code className={`flex-1 text-xs break-all ${textColor}`}>{created.plaintext}</code>
+            <button onClick={() => navigator.clipboard.writeText(created.plaintext)} className={`p-1.5 rounded ${mutedColor}`} title="复制">
+              <Copy className="h-4 w-4" />
+            </button>
+            <button onClick={() => setCreated(null)} className={`p-1.5 rounded ${mutedColor}`} title="关闭">
+              <X className="h-4 w-4" />
+            </button>
+          </div>
+        </div>
+      )}
+
+      <div className="space-y-2">
+        <input
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+          placeholder="令牌用途，如：看板脚本"
+          className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${textColor}`}
+        />
+        <div className="flex flex-wrap gap-3">
+          {API_SCOPES.map(scope => (
+            <label key={scope} className={`flex items-center gap-1.5 text-xs ${textColor}`}>
+              <input type="checkbox" checked={scopes.includes(scope)} onChange={() => toggleScope(scope)} />
+              {API_SCOPE_LABELS[scope]}
+            </label>
+          ))}
+        </div>
+        {error && <div className="text-xs text-red-400">{error}</div>}
+        <button
+          onClick={handleCreate}
+          disabled={!name.trim() || scopes.length === 0}
+          className="flex items-center gap-1 px-3 py-1.5 rounded-lg text-sm bg-[var(--accent)] text-white disabled:opacity-50"
+        >
+          <Plus className="h-4 w-4" />创建令牌
+        </button>
+      </div>
+    </div>
+  );
+};
+
 // ========== 更新设置选项卡 ==========
 const UpdateSettings: React.FC = () => {
   const { colors } = useTheme();
@@ -2084,6 +2597,17 @@ const StrategySettings: React.FC<StrategySettingsProps> = ({
     getAvailableTools().then(setAvailableTools);
   }, []);
 
+  // 策略已被其他操作修改：重新加载列表与当前选中项，提示用户重试
+  const reloadAfterConflict = async () => {
+    const latest = (await getStrategies()) || [];
+    onStrategiesChange(latest);
+    const strategy = selectedStrategy ? latest.find(s => s.id === selectedStrategy.id) || null : null;
+    setSelectedStrategy(strategy);
+    setSelectedAgent(prev => (prev && strategy?.agents.find(a => a.id === prev.id)) || null);
+    if (!strategy) setView('list');
+    showToast('error', '策略已被其他操作修改，已重新加载，请重试');
+  };
+
   // 进入策略的专家列表
   const handleSelectStrategy = (strategy: Strategy) => {
     setSelectedStrategy(strategy);
@@ -2115,9 +2639,26 @@ const StrategySettings: React.FC<StrategySettingsProps> = ({
     const updatedAgents = selectedStrategy.agents.map(a =>
       a.id === updatedAgent.id ? updatedAgent : a
     );
-    const updatedStrategy = { ...selectedStrategy, agents: updatedAgents };
-    setSelectedStrategy(updatedStrategy);
     setSelectedAgent(updatedAgent);
+    await saveSelectedStrategy({ ...selectedStrategy, agents: updatedAgents });
+  };
+
+  // 切换首轮并行发言
+  const handleParallelToggle = async (parallelRounds: boolean) => {
+    if (!selectedStrategy) return;
+    await saveSelectedStrategy({ ...selectedStrategy, parallelRounds });
+  };
+
+  // 修改会议轮数上限与专家超时
+  const handleMeetingLimitsChange = async (limits: Pick<Strategy, 'maxRounds' | 'agentTimeoutSeconds'>) => {
+    if (!selectedStrategy) return;
+    if (limits.maxRounds === (selectedStrategy.maxRounds || 0) && limits.agentTimeoutSeconds === (selectedStrategy.agentTimeoutSeconds || 0)) return;
+    await saveSelectedStrategy({ ...selectedStrategy, ...limits });
+  };
+
+  // 保存当前查看的策略
+  const saveSelectedStrategy = async (updatedStrategy: Strategy) => {
+    setSelectedStrategy(updatedStrategy);
 
     // 更新策略列表
     const newStrategies = strategies.map(s =>
@@ -2127,10 +2668,18 @@ const StrategySettings: React.FC<StrategySettingsProps> = ({
 
     // 保存到后端
     try {
-      await updateStrategy(updatedStrategy);
+      const result = await updateStrategy(updatedStrategy);
+      if (result === STRATEGY_CONFLICT) {
+        await reloadAfterConflict();
+        return;
+      }
+      if (result !== 'success') {
+        showToast('error', result);
+        return;
+      }
       showToast('success', '已保存');
       // 如果是当前激活策略，重新加载 agents
-      if (selectedStrategy.id === activeStrategyId) {
+      if (updatedStrategy.id === activeStrategyId) {
         onAgentsReload();
       }
     } catch (e) {
@@ -2138,6 +2687,24 @@ const StrategySettings: React.FC<StrategySettingsProps> = ({
     }
   };
 
+  // 恢复当前查看策略的历史版本
+  const handleRestoreVersion = async (versionId: string): Promise<boolean> => {
+    if (!selectedStrategy) return false;
+    const result = await restoreStrategyVersion(selectedStrategy.id, versionId);
+    if (result !== 'success') {
+      showToast('error', result);
+      return false;
+    }
+    const latest = (await getStrategies()) || [];
+    onStrategiesChange(latest);
+    setSelectedStrategy(latest.find(s => s.id === selectedStrategy.id) || null);
+    if (selectedStrategy.id === activeStrategyId) {
+      onAgentsReload();
+    }
+    showToast('success', '已恢复到历史版本');
+    return true;
+  };
+
   const handleGenerate = async () => {
     if (!prompt.trim()) return;
     setGenerating(true);
@@ -2161,6 +2728,8 @@ const StrategySettings: React.FC<StrategySettingsProps> = ({
     const result = await deleteStrategy(id);
     if (result === 'success') {
       onStrategiesChange(strategies.filter(s => s.id !== id));
+    } else if (result === STRATEGY_CONFLICT) {
+      await reloadAfterConflict();
     }
   };
 
@@ -2196,6 +2765,9 @@ const StrategySettings: React.FC<StrategySettingsProps> = ({
         onBack={handleBackToList}
         onSelectAgent={handleSelectAgent}
         onAgentToggle={handleUpdateAgent}
+        onParallelToggle={handleParallelToggle}
+        onMeetingLimitsChange={handleMeetingLimitsChange}
+        onRestoreVersion={handleRestoreVersion}
       />
     );
   }
@@ -2381,13 +2953,24 @@ interface StrategyAgentListProps {
   onBack: () => void;
   onSelectAgent: (agent: StrategyAgent) => void;
   onAgentToggle: (agent: StrategyAgent) => void;
+  onParallelToggle: (parallel: boolean) => void;
+  onMeetingLimitsChange: (limits: Pick<Strategy, 'maxRounds' | 'agentTimeoutSeconds'>) => void;
+  onRestoreVersion: (versionId: string) => Promise<boolean>;
 }
 
 const StrategyAgentList: React.FC<StrategyAgentListProps> = ({
-  strategy, isActive, onBack, onSelectAgent, onAgentToggle
+  strategy, isActive, onBack, onSelectAgent, onAgentToggle, onParallelToggle, onMeetingLimitsChange, onRestoreVersion
 }) => {
   const { colors } = useTheme();
   const enabledCount = strategy.agents?.filter(a => a.enabled).length || 0;
+  // 输入时只更新本地值，失焦后保存，避免每次按键产生历史版本
+  const [maxRounds, setMaxRounds] = useState(strategy.maxRounds || 0);
+  const [agentTimeoutSeconds, setAgentTimeoutSeconds] = useState(strategy.agentTimeoutSeconds || 0);
+  useEffect(() => {
+    setMaxRounds(strategy.maxRounds || 0);
+    setAgentTimeoutSeconds(strategy.agentTimeoutSeconds || 0);
+  }, [strategy.id, strategy.maxRounds, strategy.agentTimeoutSeconds]);
+  const saveLimits = () => onMeetingLimitsChange({ maxRounds, agentTimeoutSeconds });
 
   return (
     <div className="space-y-4">
@@ -2421,6 +3004,45 @@ const StrategyAgentList: React.FC<StrategyAgentListProps> = ({
         共 {strategy.agents?.length || 0} 位专家，{enabledCount} 位已启用
       </div>
 
+      {/* 首轮并行发言 */}
+      <div className="flex items-center justify-between">
+        <div>
+          <label className={`text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>首轮并行发言</label>
+          <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>智能会议中专家同时分析、互不参考，速度更快</p>
+        </div>
+        <ToggleSwitch checked={!!strategy.parallelRounds} onChange={onParallelToggle} />
+      </div>
+
+      {/* 会议轮数上限与专家超时 */}
+      <div className="grid grid-cols-2 gap-3">
+        <div>
+          <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>发言轮数上限</label>
+          <input
+            type="number"
+            min={0}
+            value={maxRounds || ''}
+            onChange={e => setMaxRounds(Math.max(0, parseInt(e.target.value) || 0))}
+            onBlur={saveLimits}
+            placeholder="默认 3 轮"
+            className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+          />
+          <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>含首轮分析与辩论反驳轮</p>
+        </div>
+        <div>
+          <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>专家超时（秒）</label>
+          <input
+            type="number"
+            min={0}
+            value={agentTimeoutSeconds || ''}
+            onChange={e => setAgentTimeoutSeconds(Math.max(0, parseInt(e.target.value) || 0))}
+            onBlur={saveLimits}
+            placeholder="默认 180 秒"
+            className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+          />
+          <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>超时的专家显示为发言失败；默认 180 秒，为工具调用留出时间，模型限流重试的等待不计入超时</p>
+        </div>
+      </div>
+
       {/* 专家列表 */}
       <div className="space-y-2">
         {strategy.agents?.map(agent => (
@@ -2432,6 +3054,90 @@ const StrategyAgentList: React.FC<StrategyAgentListProps> = ({
           />
         ))}
       </div>
+
+      {/* 历史版本 */}
+      <StrategyHistory strategy={strategy} onRestore={onRestoreVersion} />
+    </div>
+  );
+};
+
+// 策略历史版本：展开时加载，恢复后刷新
+interface StrategyHistoryProps {
+  strategy: Strategy;
+  onRestore: (versionId: string) => Promise<boolean>;
+}
+
+const StrategyHistory: React.FC<StrategyHistoryProps> = ({ strategy, onRestore }) => {
+  const { colors } = useTheme();
+  const [expanded, setExpanded] = useState(false);
+  const [versions, setVersions] = useState<StrategyVersion[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [restoringId, setRestoringId] = useState('');
+
+  const loadVersions = useCallback(async () => {
+    setLoading(true);
+    try {
+      setVersions((await getStrategyHistory(strategy.id)) || []);
+    } finally {
+      setLoading(false);
+    }
+  }, [strategy.id]);
+
+  // 策略保存后历史随之变化，展开状态下重新加载
+  useEffect(() => {
+    if (expanded) loadVersions();
+  }, [expanded, strategy, loadVersions]);
+
+  const handleRestore = async (versionId: string) => {
+    setRestoringId(versionId);
+    try {
+      if (await onRestore(versionId)) await loadVersions();
+    } finally {
+      setRestoringId('');
+    }
+  };
+
+  return (
+    <div className={`pt-3 border-t ${colors.isDark ? 'border-slate-700' : 'border-slate-200'}`}>
+      <button
+        onClick={() => setExpanded(!expanded)}
+        className={`flex items-center gap-1.5 text-sm ${colors.isDark ? 'text-slate-400 hover:text-white' : 'text-slate-500 hover:text-slate-800'}`}
+      >
+        <RotateCcw className="h-3.5 w-3.5" />
+        历史版本
+      </button>
+      {expanded && (
+        <div className="mt-2 space-y-1.5">
+          {loading && versions.length === 0 && (
+            <div className={`flex items-center gap-2 text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+              <Loader2 className="h-3.5 w-3.5 animate-spin" /> 加载中...
+            </div>
+          )}
+          {!loading && versions.length === 0 && (
+            <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>暂无历史版本，每次修改策略前会自动保存（最多 10 个）</p>
+          )}
+          {versions.map(v => (
+            <div
+              key={v.versionId}
+              className={`flex items-center justify-between px-3 py-2 rounded-lg fin-chip text-xs ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}
+            >
+              <div className="min-w-0">
+                <div>{new Date(v.createdAt).toLocaleString('zh-CN', { hour12: false })}</div>
+                <div className={`truncate ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+                  {v.strategy.name} · {v.strategy.agents?.filter(a => a.enabled).length || 0}/{v.strategy.agents?.length || 0} 位专家启用
+                </div>
+              </div>
+              <button
+                onClick={() => handleRestore(v.versionId)}
+                disabled={!!restoringId}
+                className="shrink-0 px-2 py-1 text-accent-2 hover:bg-accent/20 rounded disabled:opacity-50"
+              >
+                {restoringId === v.versionId ? <Loader2 className="h-3.5 w-3.5 animate-spin" /> : '恢复'}
+              </button>
+            </div>
+          ))}
+        </div>
+      )}
     </div>
   );
 };
@@ -2671,6 +3377,17 @@ interface AgentBasicConfigProps {
 const AgentBasicConfig: React.FC<AgentBasicConfigProps> = ({ agent, aiConfigs, onChange }) => {
   const { colors } = useTheme();
   const [enhancing, setEnhancing] = useState(false);
+  const [lintIssues, setLintIssues] = useState<AgentLintIssue[]>([]);
+
+  // 编辑指令、输出语言或语气后检查冲突（防抖）
+  useEffect(() => {
+    const timer = setTimeout(() => {
+      lintStrategy({ agents: [agent] } as Strategy)
+        .then(setLintIssues)
+        .catch(() => setLintIssues([]));
+    }, 500);
+    return () => clearTimeout(timer);
+  }, [agent.instruction, agent.outputLanguage, agent.tone]);
 
   const handleEnhance = async () => {
     if (!agent.instruction?.trim()) return;
@@ -2714,6 +3431,55 @@ const AgentBasicConfig: React.FC<AgentBasicConfigProps> = ({ agent, aiConfigs, o
         <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>为该专家指定专用的 AI 模型，留空则使用系统默认配置</p>
       </div>
 
+      {/* 单专家 token 预算 */}
+      <div>
+        <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>单次发言 Token 上限</label>
+        <input
+          type="number"
+          min={0}
+          value={agent.maxTokensPerAgent || ''}
+          onChange={e => onChange('maxTokensPerAgent', Math.max(0, parseInt(e.target.value) || 0))}
+          placeholder="沿用 AI 配置"
+          className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+        />
+        <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>限制该专家的输出长度，只会收紧 AI 配置中的最大 Token 数</p>
+      </div>
+
+      {/* 输出语言与语气 */}
+      <div className="grid grid-cols-2 gap-3">
+        <div>
+          <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>回复语言</label>
+          <select
+            value={agent.outputLanguage || ''}
+            onChange={e => onChange('outputLanguage', e.target.value)}
+            className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+          >
+            <option value="">不限定</option>
+            {OUTPUT_LANGUAGES.map(l => (
+              <option key={l.value} value={l.value}>{l.label}</option>
+            ))}
+          </select>
+        </div>
+        <div>
+          <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>语气风格</label>
+          <input
+            type="text"
+            value={agent.tone || ''}
+            onChange={e => onChange('tone', e.target.value)}
+            placeholder="如：沉稳专业，先结论后论据"
+            className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+          />
+        </div>
+      </div>
+      <p className={`text-xs -mt-2 ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>作为最终输出要求注入，优先于系统指令与内置提示的语言和风格</p>
+      {lintIssues.length > 0 && (
+        <div className="space-y-1">
+          {lintIssues.map((issue, i) => (
+            <p key={i} className={`text-xs ${issue.level === 'error' ? 'text-red-500' : 'text-amber-500'}`}>{issue.message}</p>
+          ))}
+        </div>
+      )}
+
       {/* 系统指令 */}
       <div>
         <div className="flex items-center justify-between mb-1.5">
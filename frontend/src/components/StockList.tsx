@@ -1,7 +1,8 @@
 import React, { useState, useEffect, useRef } from 'react';
-import { Stock, MarketIndex } from '../types';
-import { searchStocks, StockSearchResult } from '../services/stockService';
-import { TrendingUp, TrendingDown, Search, X } from 'lucide-react';
+import { Stock, MarketIndex, WatchlistGroup } from '../types';
+import { DEFAULT_WATCHLIST_GROUP_ID } from '../services/watchlistService';
+import { searchStocks, resolveStockReference, StockSearchResult } from '../services/stockService';
+import { TrendingUp, TrendingDown, Search, X, FolderPlus, Check, Trash2 } from 'lucide-react';
 import { MarketIndices } from './MarketIndices';
 import { useTheme } from '../contexts/ThemeContext';
 import { useCandleColor } from '../contexts/CandleColorContext';
@@ -13,6 +14,9 @@ interface StockListProps {
   onAddStock: (stock: Stock) => void;
   onRemoveStock?: (symbol: string) => void;
   marketIndices?: MarketIndex[];
+  groups?: WatchlistGroup[]; // 自选股分组，多于一个时按分组展示，属于多个分组的股票在各分组下都会出现
+  onToggleGroup?: (symbol: string, groupId: string, member: boolean) => void; // 加入（member 为 true）或移出分组
+  onDeleteGroup?: (groupId: string) => void;
 }
 
 export const StockList: React.FC<StockListProps> = ({
@@ -21,7 +25,10 @@ export const StockList: React.FC<StockListProps> = ({
   onSelect,
   onAddStock,
   onRemoveStock,
-  marketIndices
+  marketIndices,
+  groups = [],
+  onToggleGroup,
+  onDeleteGroup
 }) => {
   const { colors } = useTheme();
   const cc = useCandleColor();
@@ -29,6 +36,8 @@ export const StockList: React.FC<StockListProps> = ({
   const [searchResults, setSearchResults] = useState<StockSearchResult[]>([]);
   const [showDropdown, setShowDropdown] = useState(false);
   const [isSearching, setIsSearching] = useState(false);
+  const [resolveMessage, setResolveMessage] = useState('');
+  const [groupMenuKey, setGroupMenuKey] = useState<string | null>(null); // 打开分组菜单的行（分组 ID + 代码）
   const searchRef = useRef<HTMLDivElement>(null);
   const debounceRef = useRef<ReturnType<typeof setTimeout>>();
 
@@ -73,11 +82,18 @@ export const StockList: React.FC<StockListProps> = ({
     };
   }, [searchTerm]);
 
-  // 选择搜索结果添加股票
+  // 选择搜索结果：已在自选中则直接选中，否则添加
   const handleSelectResult = (result: StockSearchResult) => {
+    setResolveMessage('');
+    if (stocks.some(s => s.symbol === result.symbol)) {
+      onSelect(result.symbol);
+      setSearchTerm('');
+      setShowDropdown(false);
+      return;
+    }
     const newStock: Stock = {
       symbol: result.symbol,
-      name: result.name,
+      name: result.name || result.symbol,
       price: 0,
       change: 0,
       changePercent: 0,
@@ -95,6 +111,117 @@ export const StockList: React.FC<StockListProps> = ({
     setShowDropdown(false);
   };
 
+  // 解析网址、代码或名称：足够确定时直接打开，否则列出候选
+  const resolveAndOpen = async (text: string) => {
+    const res = await resolveStockReference(text);
+    if (res.autoOpen && res.candidates.length > 0) {
+      handleSelectResult(res.candidates[0]);
+      return;
+    }
+    if (debounceRef.current) clearTimeout(debounceRef.current);
+    setIsSearching(false);
+    setSearchResults(res.candidates);
+    setShowDropdown(res.candidates.length > 0);
+    setResolveMessage(res.message || '');
+  };
+
+  // 粘贴网址或含代码的文本时直接解析，普通名称仍走输入搜索
+  const handlePaste = (e: React.ClipboardEvent<HTMLInputElement>) => {
+    const text = e.clipboardData.getData('text').trim();
+    if (!/https?:\/\/|\.(com|cn)\/|\d{6}/i.test(text)) return;
+    e.preventDefault();
+    resolveAndOpen(text);
+  };
+
+  // 多个分组时按分组展示，一只股票属于几个分组就出现几次；否则平铺
+  const sections: { group?: WatchlistGroup; stocks: Stock[] }[] = groups.length > 1
+    ? groups.map(group => ({ group, stocks: stocks.filter(s => group.symbols?.includes(s.symbol)) }))
+    : [{ stocks }];
+
+  const renderStock = (stock: Stock, rowKey: string) => {
+    const isSelected = stock.symbol === selectedSymbol;
+    const isPositive = stock.change >= 0;
+    return (
+      <div
+        key={rowKey}
+        onClick={() => onSelect(stock.symbol)}
+        className={`group relative p-4 border-b fin-divider-soft cursor-pointer transition-colors ${colors.isDark ? 'hover:bg-slate-800/40' : 'hover:bg-slate-100/60'} ${isSelected ? (colors.isDark ? 'bg-slate-800/40' : 'bg-slate-100/60') + ' border-l-4 border-l-accent' : 'border-l-4 border-l-transparent'}`}
+      >
+        <div className="flex justify-between items-start mb-1">
+          <div className="flex-1 min-w-0">
+            <div className="flex items-center gap-2">
+              <span className={`font-bold ${colors.isDark ? 'text-slate-100' : 'text-slate-800'}`}>{stock.name}</span>
+              {onToggleGroup && groups.length > 1 && (
+                <button
+                  onClick={(e) => {
+                    e.stopPropagation();
+                    setGroupMenuKey(groupMenuKey === rowKey ? null : rowKey);
+                  }}
+                  className={`opacity-0 group-hover:opacity-100 p-0.5 rounded hover:bg-accent/20 transition-all ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}
+                  title="所属分组"
+                >
+                  <FolderPlus size={14} />
+                </button>
+              )}
+              {onRemoveStock && (
+                <button
+                  onClick={(e) => {
+                    e.stopPropagation();
+                    onRemoveStock(stock.symbol);
+                  }}
+                  className={`opacity-0 group-hover:opacity-100 p-0.5 rounded hover:bg-red-500/20 hover:text-red-400 transition-all ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}
+                >
+                  <X size={14} />
+                </button>
+              )}
+            </div>
+            <div className={`text-xs font-mono truncate text-left ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>{stock.symbol}</div>
+          </div>
+          <div className="text-right">
+            <div className={`font-mono ${cc.getColorClass(isPositive)}`}>
+              {stock.price.toFixed(2)}
+            </div>
+            <div className={`text-xs font-mono flex items-center justify-end ${cc.getColorClass(isPositive)}`}>
+              {isPositive ? <TrendingUp size={12} className="mr-1"/> : <TrendingDown size={12} className="mr-1"/>}
+              {isPositive ? '+' : ''}{stock.changePercent.toFixed(2)}%
+            </div>
+          </div>
+        </div>
+        <div className={`flex justify-between items-center text-xs mt-2 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+          <span>量: {formatVolume(stock.volume)}</span>
+          {stock.sector && (
+            <span className={`fin-chip px-1.5 py-0.5 rounded ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}>{stock.sector}</span>
+          )}
+        </div>
+
+        {/* 分组菜单：勾选即加入，取消勾选即移出 */}
+        {groupMenuKey === rowKey && onToggleGroup && (
+          <div
+            onClick={(e) => e.stopPropagation()}
+            className={`absolute left-4 top-10 z-40 min-w-[8rem] rounded-lg shadow-xl py-1 text-xs text-left ${colors.isDark ? 'bg-slate-800 border border-slate-600' : 'bg-white border border-slate-300'}`}
+          >
+            {groups.map(g => {
+              const member = !!g.symbols?.includes(stock.symbol);
+              return (
+                <div
+                  key={g.id}
+                  onClick={() => {
+                    onToggleGroup(stock.symbol, g.id, !member);
+                    setGroupMenuKey(null);
+                  }}
+                  className={`flex items-center gap-2 px-3 py-1.5 cursor-pointer ${colors.isDark ? 'text-slate-200 hover:bg-slate-700' : 'text-slate-700 hover:bg-slate-100'}`}
+                >
+                  <span className="w-3">{member && <Check size={12} />}</span>
+                  <span>{g.name}</span>
+                </div>
+              );
+            })}
+          </div>
+        )}
+      </div>
+    );
+  };
+
   return (
     <div className="flex flex-col h-full relative">
       <div className="p-4 border-b fin-divider-soft">
@@ -108,9 +235,11 @@ export const StockList: React.FC<StockListProps> = ({
             <input
               type="text"
               value={searchTerm}
-              onChange={(e) => setSearchTerm(e.target.value)}
+              onChange={(e) => { setSearchTerm(e.target.value); setResolveMessage(''); }}
+              onPaste={handlePaste}
+              onKeyDown={(e) => e.key === 'Enter' && searchTerm.trim() && resolveAndOpen(searchTerm.trim())}
               onFocus={() => searchResults.length > 0 && setShowDropdown(true)}
-              placeholder="搜索股票代码或名称..."
+              placeholder="搜索代码、名称或粘贴行情网址..."
               className={`w-full fin-input rounded-lg pl-9 pr-4 py-2 text-sm ${colors.isDark ? 'placeholder-slate-500' : 'placeholder-slate-400'}`}
             />
             {isSearching && (
@@ -118,9 +247,16 @@ export const StockList: React.FC<StockListProps> = ({
             )}
           </div>
 
+          {resolveMessage && !showDropdown && (
+            <div className={`mt-1 text-xs text-left ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>{resolveMessage}</div>
+          )}
+
           {/* 搜索下拉结果 */}
           {showDropdown && (
             <div className={`absolute top-full left-0 right-0 mt-1 max-h-64 overflow-y-auto rounded-lg shadow-xl text-left ${colors.isDark ? 'bg-slate-800 border border-slate-600' : 'bg-white border border-slate-300'}`}>
+              {resolveMessage && (
+                <div className={`px-3 py-1.5 text-xs border-b ${colors.isDark ? 'text-slate-400 border-slate-700' : 'text-slate-500 border-slate-200'}`}>{resolveMessage}</div>
+              )}
               {searchResults.map((result) => (
                 <div
                   key={result.symbol}
@@ -129,7 +265,7 @@ export const StockList: React.FC<StockListProps> = ({
                 >
                   <div className="flex justify-between items-center">
                     <div>
-                      <span className={colors.isDark ? 'text-slate-200' : 'text-slate-700'}>{result.name}</span>
+                      <span className={colors.isDark ? 'text-slate-200' : 'text-slate-700'}>{result.name || result.symbol}</span>
                       <span className="ml-2 font-mono text-accent-2 text-sm">{result.symbol}</span>
                     </div>
                     <span className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>{result.market}</span>
@@ -145,53 +281,25 @@ export const StockList: React.FC<StockListProps> = ({
       </div>
 
       <div className="flex-1 overflow-y-auto fin-scrollbar">
-        {stocks.map((stock) => {
-          const isSelected = stock.symbol === selectedSymbol;
-          const isPositive = stock.change >= 0;
-
-          return (
-            <div
-              key={stock.symbol}
-              onClick={() => onSelect(stock.symbol)}
-              className={`group p-4 border-b fin-divider-soft cursor-pointer transition-colors ${colors.isDark ? 'hover:bg-slate-800/40' : 'hover:bg-slate-100/60'} ${isSelected ? (colors.isDark ? 'bg-slate-800/40' : 'bg-slate-100/60') + ' border-l-4 border-l-accent' : 'border-l-4 border-l-transparent'}`}
-            >
-              <div className="flex justify-between items-start mb-1">
-                <div className="flex-1 min-w-0">
-                  <div className="flex items-center gap-2">
-                    <span className={`font-bold ${colors.isDark ? 'text-slate-100' : 'text-slate-800'}`}>{stock.name}</span>
-                    {onRemoveStock && (
-                      <button
-                        onClick={(e) => {
-                          e.stopPropagation();
-                          onRemoveStock(stock.symbol);
-                        }}
-                        className={`opacity-0 group-hover:opacity-100 p-0.5 rounded hover:bg-red-500/20 hover:text-red-400 transition-all ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}
-                      >
-                        <X size={14} />
-                      </button>
-                    )}
-                  </div>
-                  <div className={`text-xs font-mono truncate text-left ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>{stock.symbol}</div>
-                </div>
-                <div className="text-right">
-                  <div className={`font-mono ${cc.getColorClass(isPositive)}`}>
-                    {stock.price.toFixed(2)}
-                  </div>
-                  <div className={`text-xs font-mono flex items-center justify-end ${cc.getColorClass(isPositive)}`}>
-                    {isPositive ? <TrendingUp size={12} className="mr-1"/> : <TrendingDown size={12} className="mr-1"/>}
-                    {isPositive ? '+' : ''}{stock.changePercent.toFixed(2)}%
-                  </div>
-                </div>
-              </div>
-              <div className={`flex justify-between items-center text-xs mt-2 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
-                <span>量: {formatVolume(stock.volume)}</span>
-                {stock.sector && (
-                  <span className={`fin-chip px-1.5 py-0.5 rounded ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}>{stock.sector}</span>
+        {sections.map(section => (
+          <React.Fragment key={section.group?.id ?? 'all'}>
+            {section.group && (
+              <div className={`group/header flex items-center justify-between px-4 py-1.5 text-xs font-medium border-b fin-divider-soft ${colors.isDark ? 'text-slate-400 bg-slate-800/30' : 'text-slate-500 bg-slate-100/50'}`}>
+                <span>{section.group.name}</span>
+                {onDeleteGroup && section.group.id !== DEFAULT_WATCHLIST_GROUP_ID && (
+                  <button
+                    onClick={() => onDeleteGroup(section.group!.id)}
+                    className="opacity-0 group-hover/header:opacity-100 p-0.5 rounded hover:bg-red-500/20 hover:text-red-400 transition-all"
+                    title="删除分组（其中的自选股保留）"
+                  >
+                    <Trash2 size={12} />
+                  </button>
                 )}
               </div>
-            </div>
-          );
-        })}
+            )}
+            {section.stocks.map(stock => renderStock(stock, section.group ? `${section.group.id}:${stock.symbol}` : stock.symbol))}
+          </React.Fragment>
+        ))}
       </div>
     </div>
   );
@@ -1,6 +1,6 @@
 import { useEffect, useCallback, useRef } from 'react';
 import { EventsOn, EventsOff, EventsEmit } from '@wailsjs/runtime/runtime';
-import { NotifyFrontendReady } from '../../wailsjs/go/main/App';
+import { NotifyFrontendReady, FocusStock, UnfocusStock } from '../../wailsjs/go/main/App';
 import { Stock, OrderBook, Telegraph, MarketIndex, KLineData } from '../types';
 
 // K线推送数据结构
@@ -20,6 +20,12 @@ const EVENT_MARKET_SUBSCRIBE = 'market:subscribe';
 const EVENT_ORDERBOOK_SUBSCRIBE = 'market:orderbook:subscribe';
 const EVENT_KLINE_UPDATE = 'market:kline:update';
 const EVENT_KLINE_SUBSCRIBE = 'market:kline:subscribe';
+const EVENT_FOCUS_ORDERBOOK_PREFIX = 'orderbook:';
+
+// 聚焦股票推送数据（行情 + 盘口）
+export interface FocusStockUpdate extends Stock {
+  orderBook: OrderBook;
+}
 
 interface UseMarketEventsOptions {
   onStockUpdate?: (stocks: Stock[]) => void;
@@ -27,6 +33,7 @@ interface UseMarketEventsOptions {
   onTelegraphUpdate?: (telegraph: Telegraph) => void;
   onMarketIndicesUpdate?: (indices: MarketIndex[]) => void;
   onKLineUpdate?: (data: KLineUpdateData) => void;
+  onFocusUpdate?: (data: FocusStockUpdate) => void;
 }
 
 /**
@@ -34,7 +41,7 @@ interface UseMarketEventsOptions {
  * 监听后端推送的实时市场数据
  */
 export function useMarketEvents(options: UseMarketEventsOptions) {
-  const { onStockUpdate, onOrderBookUpdate, onTelegraphUpdate, onMarketIndicesUpdate, onKLineUpdate } = options;
+  const { onStockUpdate, onOrderBookUpdate, onTelegraphUpdate, onMarketIndicesUpdate, onKLineUpdate, onFocusUpdate } = options;
 
   // 使用 ref 保存回调，避免重复注册
   const stockCallbackRef = useRef(onStockUpdate);
@@ -42,6 +49,8 @@ export function useMarketEvents(options: UseMarketEventsOptions) {
   const telegraphCallbackRef = useRef(onTelegraphUpdate);
   const marketIndicesCallbackRef = useRef(onMarketIndicesUpdate);
   const klineCallbackRef = useRef(onKLineUpdate);
+  const focusCallbackRef = useRef(onFocusUpdate);
+  const focusEventRef = useRef<string | null>(null);
 
   // 更新 ref
   useEffect(() => {
@@ -50,7 +59,8 @@ export function useMarketEvents(options: UseMarketEventsOptions) {
     telegraphCallbackRef.current = onTelegraphUpdate;
     marketIndicesCallbackRef.current = onMarketIndicesUpdate;
     klineCallbackRef.current = onKLineUpdate;
-  }, [onStockUpdate, onOrderBookUpdate, onTelegraphUpdate, onMarketIndicesUpdate, onKLineUpdate]);
+    focusCallbackRef.current = onFocusUpdate;
+  }, [onStockUpdate, onOrderBookUpdate, onTelegraphUpdate, onMarketIndicesUpdate, onKLineUpdate, onFocusUpdate]);
 
   // 注册事件监听
   useEffect(() => {
@@ -100,6 +110,11 @@ export function useMarketEvents(options: UseMarketEventsOptions) {
       EventsOff(EVENT_TELEGRAPH_UPDATE);
       EventsOff(EVENT_MARKET_INDICES_UPDATE);
       EventsOff(EVENT_KLINE_UPDATE);
+      if (focusEventRef.current) {
+        EventsOff(focusEventRef.current);
+        focusEventRef.current = null;
+        UnfocusStock();
+      }
     };
   }, []);
 
@@ -118,5 +133,28 @@ export function useMarketEvents(options: UseMarketEventsOptions) {
     EventsEmit(EVENT_KLINE_SUBSCRIBE, code, period);
   }, []);
 
-  return { subscribe, subscribeOrderBook, subscribeKLine };
+  // 聚焦股票（切换时取消上一只的快速推送）
+  const focusStock = useCallback((code: string) => {
+    const eventName = EVENT_FOCUS_ORDERBOOK_PREFIX + code;
+    if (focusEventRef.current === eventName) return;
+    if (focusEventRef.current) {
+      EventsOff(focusEventRef.current);
+    }
+    focusEventRef.current = eventName;
+    EventsOn(eventName, (data: FocusStockUpdate) => {
+      focusCallbackRef.current?.(data);
+    });
+    FocusStock(code);
+  }, []);
+
+  // 取消聚焦
+  const unfocusStock = useCallback(() => {
+    if (focusEventRef.current) {
+      EventsOff(focusEventRef.current);
+      focusEventRef.current = null;
+    }
+    UnfocusStock();
+  }, []);
+
+  return { subscribe, subscribeOrderBook, subscribeKLine, focusStock, unfocusStock };
 }
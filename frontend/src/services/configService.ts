@@ -1,6 +1,7 @@
 // 配置服务 - 调用后端API
-import { GetConfig, UpdateConfig, GetAvailableTools, TestAIConnection } from '@wailsjs/go/main/App';
-import type { models } from '@wailsjs/go/models';
+import { GetConfig, UpdateConfig, GetAvailableTools, TestAIConnection, AddPriceAlert, GetAICapabilities, GetAPITokens, CreateAPIToken, RevokeAPIToken } from '@wailsjs/go/main/App';
+import type { models, capability, main } from '@wailsjs/go/models';
+import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 
 export type AppConfig = models.AppConfig;
 
@@ -24,6 +25,98 @@ export const getAvailableTools = async (): Promise<ToolInfo[]> => {
 };
 
 // 测试 AI 配置连通性
+// 获取 AI 配置生效的能力矩阵（不支持的参数请求时会被自动丢弃）
+export const getAICapabilities = async (config: models.AIConfig): Promise<capability.Info[]> => {
+  return (await GetAICapabilities(config)) || [];
+};
+
 export const testAIConnection = async (config: models.AIConfig): Promise<string> => {
   return await TestAIConnection(config);
 };
+
+// 删除 AI 配置后被自动清理的引用，newId 为空表示改用默认配置
+export interface AIConfigRefFix {
+  field: 'defaultAiId' | 'moderatorAiId' | 'strategyAiId' | 'memory.aiConfigId';
+  label: string;
+  oldId: string;
+  newId: string;
+}
+
+export function onConfigReferencesFixed(callback: (fixes: AIConfigRefFix[]) => void): () => void {
+  EventsOn('config:references_fixed', callback);
+  return () => EventsOff('config:references_fixed');
+}
+
+// 定时会议单只股票完成结果
+export interface ScheduledMeetingResult {
+  scheduleId: string;
+  scheduleName: string;
+  stockCode: string;
+  stockName: string;
+  messageCount: number;
+  error?: string;
+  finishedAt: number;
+}
+
+export function onScheduledMeetingDone(callback: (result: ScheduledMeetingResult) => void): () => void {
+  EventsOn('meeting:scheduled:done', callback);
+  return () => EventsOff('meeting:scheduled:done');
+}
+
+// 价格提醒条件：change_percent_above 阈值为涨跌幅(%)，volume_spike 阈值为量比倍数
+export type AlertCondition = 'price_above' | 'price_below' | 'change_percent_above' | 'volume_spike';
+
+export const addPriceAlert = async (symbol: string, condition: AlertCondition, threshold: number): Promise<string> => {
+  return await AddPriceAlert(symbol, condition, threshold);
+};
+
+// 提醒触发后推送，同一提醒只推送一次
+export function onAlertTriggered(callback: (alert: models.PriceAlert) => void): () => void {
+  EventsOn('alert:triggered', callback);
+  return () => EventsOff('alert:triggered');
+}
+
+const ALERT_LABELS: Record<string, string> = {
+  above: '涨破',
+  below: '跌破',
+  change_percent_above: '涨幅达到',
+  volume_spike: '放量至',
+};
+
+// describeAlert 提醒条件的中文描述，与后端 PriceAlert.Describe 保持一致
+export function describeAlert(alert: models.PriceAlert): string {
+  const label = ALERT_LABELS[alert.condition] ?? alert.condition;
+  switch (alert.condition) {
+    case 'change_percent_above':
+      return `${label} ${alert.threshold.toFixed(2)}%`;
+    case 'volume_spike':
+      return `${label}近期平均的 ${alert.threshold.toFixed(1)} 倍`;
+    default:
+      return `${label} ${alert.threshold.toFixed(2)}`;
+  }
+}
+
+// 本地 HTTP API 令牌权限范围，admin 包含全部权限
+export type APIScope = 'read_market' | 'read_sessions' | 'run_meetings' | 'admin';
+
+export const API_SCOPE_LABELS: Record<APIScope, string> = {
+  read_market: '查询行情',
+  read_sessions: '读取会话',
+  run_meetings: '发起会议',
+  admin: '管理（全部权限）',
+};
+
+export type APIToken = models.APIToken;
+
+export const getAPITokens = async (): Promise<APIToken[]> => {
+  return (await GetAPITokens()) || [];
+};
+
+// 创建令牌，明文只在返回结果中出现一次
+export const createAPIToken = async (name: string, scopes: APIScope[]): Promise<main.APITokenCreateResult> => {
+  return await CreateAPIToken(name, scopes);
+};
+
+export const revokeAPIToken = async (id: string): Promise<string> => {
+  return await RevokeAPIToken(id);
+};
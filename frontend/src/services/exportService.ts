@@ -0,0 +1,72 @@
+import { ExportKLineCSV, ExportPositionsCSV, ExportLongHuBangCSV, ExportMeetingTranscript, GetMeetingTranscript, OpenURL } from '../../wailsjs/go/main/App';
+import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
+
+// 导出进度事件
+export interface ExportProgress {
+  kind: 'kline' | 'positions' | 'longhubang';
+  stage: 'fetch' | 'write' | 'done';
+  done: number;
+  total: number;
+}
+
+// 导出结果：success 成功，cancelled 用户取消，其余为错误信息
+export type ExportResult = 'success' | 'cancelled' | string;
+
+// 导出K线（path 为空时弹出保存对话框）
+export async function exportKLineCSV(code: string, period: string, days: number, path = ''): Promise<ExportResult> {
+  return await ExportKLineCSV(code, period, days, path);
+}
+
+// 导出全部持仓
+export async function exportPositionsCSV(path = ''): Promise<ExportResult> {
+  return await ExportPositionsCSV(path);
+}
+
+// 导出指定交易日龙虎榜（tradeDate 为空时取最新交易日）
+export async function exportLongHuBangCSV(tradeDate: string, path = ''): Promise<ExportResult> {
+  return await ExportLongHuBangCSV(tradeDate, path);
+}
+
+export function onExportProgress(callback: (progress: ExportProgress) => void): () => void {
+  EventsOn('export:progress', callback);
+  return () => EventsOff('export:progress');
+}
+
+// 会议记录导出格式：html 可在浏览器中打印为 PDF
+export type TranscriptFormat = 'markdown' | 'html';
+
+// 会议记录导出结果：path 为写入的文件路径，失败时 error 非空
+export interface TranscriptExportResult {
+  path: string;
+  error: string;
+}
+
+// 导出股票会话的讨论记录到数据目录 exports 下
+export async function exportMeetingTranscript(stockCode: string, format: TranscriptFormat): Promise<TranscriptExportResult> {
+  return await ExportMeetingTranscript(stockCode, format);
+}
+
+// 渲染讨论记录文本（Wails 将 []byte 编码为 base64 字符串），无记录时返回空串
+export async function getMeetingTranscript(stockCode: string, format: TranscriptFormat): Promise<string> {
+  const data = (await GetMeetingTranscript(stockCode, format)) as unknown as string | null;
+  if (!data) return '';
+  return new TextDecoder().decode(Uint8Array.from(atob(data), c => c.charCodeAt(0)));
+}
+
+// 用系统默认程序打开导出的文件
+export function openExportedFile(path: string): void {
+  const normalized = path.replace(/\\/g, '/');
+  OpenURL('file://' + (normalized.startsWith('/') ? '' : '/') + encodeURI(normalized));
+}
+
+// 会议记录导出完成事件
+export interface TranscriptExportedEvent {
+  stockCode: string;
+  format: TranscriptFormat;
+  path: string;
+}
+
+export function onTranscriptExported(callback: (event: TranscriptExportedEvent) => void): () => void {
+  EventsOn('export:transcript', callback);
+  return () => EventsOff('export:transcript');
+}
@@ -1,5 +1,5 @@
 import { models } from '../../wailsjs/go/models';
-import { GetMCPServers, AddMCPServer, UpdateMCPServer, DeleteMCPServer, GetMCPStatus, TestMCPConnection, GetMCPServerTools } from '../../wailsjs/go/main/App';
+import { GetMCPServers, AddMCPServer, UpdateMCPServer, DeleteMCPServer, GetMCPStatus, TestMCPConnection, GetMCPServerTools, RefreshMCPToolCatalog } from '../../wailsjs/go/main/App';
 
 export type MCPServerConfig = models.MCPServerConfig;
 
@@ -8,6 +8,8 @@ export interface MCPServerStatus {
   id: string;
   connected: boolean;
   error: string;
+  lastSuccess?: number; // 最近一次连接成功时间（毫秒），来自后台健康检查
+  failures?: number;    // 连续失败次数
 }
 
 // MCP 工具信息
@@ -16,6 +18,7 @@ export interface MCPToolInfo {
   description: string;
   serverId: string;
   serverName: string;
+  stale: boolean; // 来自过期缓存，后台正在刷新
 }
 
 export async function getMCPServers(): Promise<MCPServerConfig[]> {
@@ -48,3 +51,8 @@ export async function testMCPConnection(serverID: string): Promise<MCPServerStat
 export async function getMCPServerTools(serverID: string): Promise<MCPToolInfo[]> {
   return await GetMCPServerTools(serverID);
 }
+
+// 立即刷新 MCP 工具目录缓存（serverID 为空时刷新全部）
+export async function refreshMCPToolCatalog(serverID: string): Promise<string> {
+  return await RefreshMCPToolCatalog(serverID);
+}
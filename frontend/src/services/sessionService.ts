@@ -1,4 +1,5 @@
-import { GetOrCreateSession, GetSessionMessages, ClearSessionMessages, SendMeetingMessage, UpdateStockPosition, RetryAgent, RetryAgentAndContinue, CancelInterruptedMeeting } from '../../wailsjs/go/main/App';
+import { GetOrCreateSession, GetSessionMessages, ClearSessionMessages, SendMeetingMessage, DryRunMeeting, EstimateMeetingCost, UpdateStockPosition, RetryAgent, RetryAgentAndContinue, ResumeMeeting, CancelInterruptedMeeting, GetMeetingUsage, GetSuggestedQueries, GetActionJournal, UpdateActionJournalEntry, DeleteActionJournalEntry, RunWatchlistScan, SetAutoTranslate, GetMeetingRuns, GetSessionStats, StartResearchTask, CancelResearchTask, GetResearchTasks, GetSessionAttachment } from '../../wailsjs/go/main/App';
+import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 import type { StockPosition } from '../types';
 
 export interface StockSession {
@@ -9,6 +10,86 @@ export interface StockSession {
   position?: StockPosition; // 持仓信息
   createdAt: number;
   updatedAt: number;
+  tokenUsage?: Record<string, TokenUsage>; // 各专家 token 累计用量，key: agentId
+  autoTranslate?: AutoTranslateTarget; // 专家回复自动翻译的目标语言，未设置为关闭
+  meetingRuns?: MeetingRun[]; // 最近几场会议实际生效的模型参数
+  attachments?: SessionAttachment[]; // 深度研究报告等长文附件
+}
+
+// 会话附件，正文不进入讨论上下文，由消息的 attachmentId 引用
+export interface SessionAttachment {
+  id: string;
+  kind: string; // research=深度研究报告
+  title: string;
+  content: string; // Markdown 正文
+  createdAt: number;
+}
+
+// 自动翻译目标语言
+export type AutoTranslateTarget = 'zh' | 'en';
+
+// 专家在会话内的 token 累计用量
+export interface TokenUsage {
+  agentId: string;
+  agentName: string;
+  inputTokens: number;
+  outputTokens: number;
+  totalTokens: number;
+  calls: number;
+}
+
+// 一场会议的运行记录，用于对比两次运行的参数差异
+export interface MeetingRun {
+  id: string; // 与会议摘要的 runId 一致
+  mode: string; // smart/direct/debate
+  query: string;
+  startedAt: number;
+  finishedAt: number;
+  reproducible: boolean; // 是否开启复现模式
+  seed?: number; // 复现模式使用的随机种子，传回 MeetingMessageRequest.seed 即可按同一种子重跑
+  agents: MeetingRunAgent[];
+}
+
+// 单个发言者实际使用的模型参数
+export interface MeetingRunAgent {
+  agentId: string;
+  agentName: string;
+  aiConfigId: string;
+  provider: string;
+  modelName: string;
+  temperature: number;
+  maxTokens?: number;
+  seedSupported: boolean; // 种子是否实际发送给模型
+}
+
+// 会话统计
+export interface SessionStats {
+  stockCode: string;
+  totalMessages: number; // 全部消息数（含用户提问）
+  byAgent: Record<string, AgentStats>; // 各发言者统计（不含用户），key: agentId
+  firstMessageAt: number;
+  lastMessageAt: number;
+  rounds: number; // 讨论轮数，每次提问开启一轮
+  averageRoundLength: number; // 平均每轮的回复条数
+}
+
+// 单个发言者在会话内的统计
+export interface AgentStats {
+  agentName: string;
+  messageCount: number;
+  totalTokens: number;
+  tokensEstimated: boolean; // 无实际用量记录，按内容长度估算
+  participationRate: number; // 参与发言的轮数占比（0~1）
+}
+
+// 单场会议的 token 用量与估算费用
+export interface MeetingUsage {
+  stockCode: string;
+  promptTokens: number;
+  completionTokens: number;
+  totalTokens: number;
+  cost: number;
+  turns: number;
 }
 
 export interface ChatMessage {
@@ -23,16 +104,31 @@ export interface ChatMessage {
   round?: number;
   msgType?: string;
   error?: string;  // 失败时的错误信息
-  meetingMode?: string; // smart=串行, direct=独立
+  meetingMode?: string; // smart=串行, direct=独立, debate=辩论轮
+  roundLabel?: string; // 轮次标签（辩论模式）
+  rebuts?: string; // 反驳的专家 ID
+  reasoning?: string; // 模型思考内容
+  toolCalls?: string[]; // 本次发言调用的工具
+  toolResults?: string[]; // 与 toolCalls 对应的工具结果 JSON（截断）
+  finishReason?: string; // 结束原因，MAX_TOKENS 表示回复被截断
+  original?: string; // 自动翻译前的原文，content 为译文
+  translatedTo?: string; // 译文语言
+  attachmentId?: string; // 关联的会话附件（如深度研究报告）
 }
 
 // 会议室消息请求
 export interface MeetingMessageRequest {
   stockCode: string;
   content: string;
+  images?: string[]; // 附带图片（data URL）
   mentionIds: string[];
   replyToId: string;
   replyContent: string;
+  forceSuspectData?: boolean; // 行情数据异常时仍然开会
+  mode?: string; // 智能会议模式：debate 为辩论模式
+  reproducible?: boolean; // 复现模式：统一低温度、固定随机种子与当前时间
+  seed?: number; // 复现模式的随机种子，不传使用配置值
+  concurrentMode?: boolean; // 并发模式：专家任务相互独立时按 CPU 核数同时发言
 }
 
 // 获取或创建Session
@@ -40,9 +136,9 @@ export const getOrCreateSession = async (stockCode: string, stockName: string):
   return await GetOrCreateSession(stockCode, stockName);
 };
 
-// 获取Session消息
-export const getSessionMessages = async (stockCode: string): Promise<ChatMessage[]> => {
-  return await GetSessionMessages(stockCode);
+// 获取Session消息（includeReasoning 为 true 时附带模型思考内容）
+export const getSessionMessages = async (stockCode: string, includeReasoning = false): Promise<ChatMessage[]> => {
+  return await GetSessionMessages(stockCode, includeReasoning);
 };
 
 // 清空Session消息
@@ -55,6 +151,62 @@ export const sendMeetingMessage = async (req: MeetingMessageRequest): Promise<Ch
   return await SendMeetingMessage(req);
 };
 
+// 会议预演中单个专家计划调用的工具
+export interface DryRunPlan {
+  agentId: string;
+  agentName: string;
+  plannedToolCalls: string[]; // 按调用顺序，含关键参数
+  reasoning: string;
+  error?: string;
+}
+
+// 会议预演结果
+export interface DryRunResult {
+  plans: DryRunPlan[];
+  error?: string;
+}
+
+// 会议预演：各专家只描述计划调用的工具与理由，不实际调用工具，结果不写入会话
+export const dryRunMeeting = async (req: MeetingMessageRequest): Promise<DryRunResult> => {
+  return await DryRunMeeting(req);
+};
+
+// 会前消耗估算中单个发言者的预计用量，小韭菜合并为一项
+export interface AgentCostEstimate {
+  agentId: string;
+  agentName: string;
+  model: string;
+  requests: number; // 预计 LLM 请求次数，含工具调用后的再次请求
+  promptTokens: number;
+  completionTokens: number;
+  cost: number;
+}
+
+// 会前消耗估算
+export interface MeetingCostEstimate {
+  mode: string; // smart/debate/direct
+  agents: AgentCostEstimate[];
+  promptTokens: number;
+  completionTokens: number;
+  totalTokens: number;
+  cost: number;
+  minTokens: number;
+  maxTokens: number;
+  minCost: number;
+  maxCost: number;
+  assumptions: string[]; // 估算使用的假设
+}
+
+export interface MeetingCostResult {
+  estimate?: MeetingCostEstimate;
+  error?: string;
+}
+
+// 会前估算本次提问的 token 用量与费用，不调用模型
+export const estimateMeetingCost = async (req: MeetingMessageRequest): Promise<MeetingCostResult> => {
+  return await EstimateMeetingCost(req);
+};
+
 // 更新股票持仓信息
 export const updateStockPosition = async (stockCode: string, shares: number, costPrice: number): Promise<string> => {
   return await UpdateStockPosition(stockCode, shares, costPrice);
@@ -70,7 +222,144 @@ export const retryAgentAndContinue = async (stockCode: string): Promise<ChatMess
   return await RetryAgentAndContinue(stockCode);
 };
 
+// 继续被取消的智能会议（已发言的专家不重复发言）
+export const resumeMeeting = async (stockCode: string): Promise<ChatMessage[]> => {
+  return await ResumeMeeting(stockCode);
+};
+
 // 取消中断的会议（用户放弃重试）
 export const cancelInterruptedMeeting = async (stockCode: string): Promise<boolean> => {
   return await CancelInterruptedMeeting(stockCode);
 };
+
+// 设置会话自动翻译，target 为空字符串时关闭
+export const setAutoTranslate = async (stockCode: string, target: AutoTranslateTarget | ''): Promise<string> => {
+  return await SetAutoTranslate(stockCode, target);
+};
+
+// 获取最近一场会议的用量
+export const getMeetingUsage = async (stockCode: string): Promise<MeetingUsage> => {
+  return await GetMeetingUsage(stockCode);
+};
+
+// 获取会话统计
+export const getSessionStats = async (stockCode: string): Promise<SessionStats> => {
+  return await GetSessionStats(stockCode);
+};
+
+// 获取最近几场会议的运行记录，最近的在前
+export const getMeetingRuns = async (stockCode: string): Promise<MeetingRun[]> => {
+  return await GetMeetingRuns(stockCode);
+};
+
+// 输入框下方的推荐问题，content/mentionIds 可直接作为会议消息发送
+export interface SuggestedQuery {
+  label: string;
+  content: string;
+  mentionIds: string[];
+}
+
+// 获取推荐问题（按当前策略专家、持仓、市场状态与异动模板生成）
+export const getSuggestedQueries = async (stockCode: string): Promise<SuggestedQuery[]> => {
+  return (await GetSuggestedQueries(stockCode)) || [];
+};
+
+// 操作日志条目：用户在对话中陈述的实际买卖决策
+export interface ActionEntry {
+  id: string;
+  stockCode: string;
+  action: 'buy' | 'sell' | 'hold';
+  price?: number;
+  quantity?: string;
+  note: string;
+  source: string; // 用户原话
+  timestamp: number;
+  edited?: boolean;
+}
+
+export const getActionJournal = async (stockCode: string): Promise<ActionEntry[]> => {
+  return ((await GetActionJournal(stockCode)) || []) as ActionEntry[];
+};
+
+export const updateActionJournalEntry = async (stockCode: string, entry: ActionEntry): Promise<string> => {
+  return await UpdateActionJournalEntry(stockCode, entry as any);
+};
+
+export const deleteActionJournalEntry = async (stockCode: string, id: string): Promise<string> => {
+  return await DeleteActionJournalEntry(stockCode, id);
+};
+
+// 晨间扫描专用会话代码，过程消息与普通会议一样按该代码推送，可用 CancelMeeting 取消
+export const SCAN_SESSION_CODE = 'watchlist_scan';
+
+export interface ScanPick {
+  rank: number;
+  code: string;
+  name: string;
+  reason: string;
+}
+
+// 晨间扫描结果，error 非空表示扫描失败或排名未能解析
+export interface ScanResult {
+  picks: ScanPick[] | null;
+  excluded?: { code: string; name: string; reason: string }[]; // 行情异常未参与扫描的股票
+  summary: string;
+  error?: string;
+  finishedAt: number;
+}
+
+// 发起自选股晨间扫描，后台执行，返回 "success" 或错误信息
+export const runWatchlistScan = async (): Promise<string> => {
+  return await RunWatchlistScan();
+};
+
+export function onScanComplete(callback: (result: ScanResult) => void): () => void {
+  EventsOn('scan:complete', callback);
+  return () => EventsOff('scan:complete');
+}
+
+export type ResearchStatus = 'queued' | 'running' | 'done' | 'failed' | 'cancelled';
+
+// 深度研究任务，后台排队执行（同时最多一个），切换页面不影响
+export interface ResearchTask {
+  id: string;
+  stockCode: string;
+  stockName: string;
+  focus: string;
+  status: ResearchStatus;
+  createdAt: number;
+  startedAt?: number;
+  finishedAt?: number;
+  error?: string;
+  toolCalls: number;
+  cost: number;
+  attachmentId?: string; // 报告保存后的会话附件 ID
+}
+
+// research:progress 事件载荷，event 为空表示仅任务状态变化；event 与会议进度事件格式相同
+export interface ResearchProgress {
+  task: ResearchTask;
+  event?: { type: string; agentName: string; detail: string; content: string; partial?: boolean };
+}
+
+// 提交深度研究任务，focus 为研究重点（可为空），返回 "success" 或错误信息
+export const startResearchTask = async (stockCode: string, focus: string): Promise<string> => {
+  return await StartResearchTask(stockCode, focus);
+};
+
+export const cancelResearchTask = async (taskId: string): Promise<boolean> => {
+  return await CancelResearchTask(taskId);
+};
+
+export const getResearchTasks = async (): Promise<ResearchTask[]> => {
+  return ((await GetResearchTasks()) || []) as ResearchTask[];
+};
+
+export const getSessionAttachment = async (stockCode: string, attachmentId: string): Promise<SessionAttachment | null> => {
+  return ((await GetSessionAttachment(stockCode, attachmentId)) || null) as SessionAttachment | null;
+};
+
+export function onResearchProgress(callback: (progress: ResearchProgress) => void): () => void {
+  EventsOn('research:progress', callback);
+  return () => EventsOff('research:progress');
+}
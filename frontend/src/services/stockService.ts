@@ -1,5 +1,5 @@
 // 市场数据服务 - 调用后端API
-import { GetStockRealTimeData, GetKLineData, GetOrderBook, SearchStocks } from '@wailsjs/go/main/App';
+import { GetStockRealTimeData, GetKLineData, GetOrderBook, SearchStocks, ResolveStockReference, FocusStock, UnfocusStock } from '@wailsjs/go/main/App';
 import type { Stock, KLineData, OrderBook } from '../types';
 
 // 股票搜索结果类型
@@ -23,8 +23,37 @@ export const getOrderBook = async (code: string): Promise<OrderBook> => {
   return await GetOrderBook(code);
 };
 
+// 聚焦股票：后端通过 orderbook:<code> 事件快速推送盘口与行情
+export const focusStock = async (code: string): Promise<void> => {
+  return await FocusStock(code);
+};
+
+// 取消聚焦
+export const unfocusStock = async (): Promise<void> => {
+  return await UnfocusStock();
+};
+
 // 搜索股票
 export const searchStocks = async (keyword: string): Promise<StockSearchResult[]> => {
   if (!keyword.trim()) return [];
   return await SearchStocks(keyword) as StockSearchResult[];
 };
+
+// 股票引用解析候选
+export interface StockCandidate extends StockSearchResult {
+  confidence: number;
+  source: 'url' | 'code' | 'name';
+}
+
+// 股票引用解析结果，autoOpen 为 true 时首个候选可直接打开
+export interface StockReferenceResult {
+  input: string;
+  candidates: StockCandidate[];
+  autoOpen: boolean;
+  message?: string;
+}
+
+// 解析粘贴的行情网址、代码或名称
+export const resolveStockReference = async (input: string): Promise<StockReferenceResult> => {
+  return await ResolveStockReference(input) as StockReferenceResult;
+};
@@ -1,4 +1,4 @@
-import { GetStrategies, GetActiveStrategyID, SetActiveStrategy, AddStrategy, UpdateStrategy, DeleteStrategy, GenerateStrategy, EnhancePrompt, GetAgentConfigs, AddAgentConfig, UpdateAgentConfig, DeleteAgentConfig } from '../../wailsjs/go/main/App';
+import { GetStrategies, GetStrategyRevision, GetActiveStrategyID, SetActiveStrategy, AddStrategy, UpdateStrategy, DeleteStrategy, DuplicateStrategy, ExportStrategy, ImportStrategy, GenerateStrategy, GetStrategyHistory, RestoreStrategyVersion, EnhancePrompt, LintStrategy, GetAgentConfigs, AddAgentConfig, UpdateAgentConfig, DeleteAgentConfig } from '../../wailsjs/go/main/App';
 
 // 策略专属专家配置
 export interface StrategyAgent {
@@ -12,6 +12,9 @@ export interface StrategyAgent {
   mcpServers: string[];
   enabled: boolean;
   aiConfigId: string;
+  maxTokensPerAgent?: number; // 单次发言输出 token 上限，0/空表示沿用 AI 配置
+  outputLanguage?: string; // 回复语言代码，优先于指令与系统提示的语言，空表示不限定
+  tone?: string; // 回复语气风格，空表示不限定
 }
 
 export interface Strategy {
@@ -20,6 +23,9 @@ export interface Strategy {
   description: string;
   color: string;
   agents: StrategyAgent[];
+  parallelRounds?: boolean; // 智能会议首轮专家并行发言
+  maxRounds?: number; // 专家发言的最大轮数，0/空为默认 3 轮
+  agentTimeoutSeconds?: number; // 单个专家发言超时（秒），0/空为默认
   isBuiltin: boolean;
   source: string;
   sourceMeta: string;
@@ -37,8 +43,21 @@ export interface GenerateStrategyResponse {
   reasoning?: string;
 }
 
-// 获取所有策略
+// 策略已被其他操作修改时后端返回的结果，需重新加载后重试
+export const STRATEGY_CONFLICT = 'conflict';
+
+// 最近一次加载策略时的存储版本，更新/删除时回传给后端检测并发修改
+let strategyRevision = 0;
+
+// 本端写入成功后存储版本加一（后端每次保存递增一次）
+const trackWrite = (result: string): string => {
+  if (result === 'success') strategyRevision++;
+  return result;
+};
+
+// 获取所有策略（先取版本再取列表，期间若有写入只会多报冲突而不会漏报）
 export const getStrategies = async (): Promise<Strategy[]> => {
+  strategyRevision = await GetStrategyRevision();
   return await GetStrategies();
 };
 
@@ -49,27 +68,85 @@ export const getActiveStrategyID = async (): Promise<string> => {
 
 // 设置当前激活策略
 export const setActiveStrategy = async (id: string): Promise<string> => {
-  return await SetActiveStrategy(id);
+  return trackWrite(await SetActiveStrategy(id));
 };
 
 // 添加策略
 export const addStrategy = async (strategy: Strategy): Promise<string> => {
-  return await AddStrategy(strategy as any);
+  return trackWrite(await AddStrategy(strategy as any));
 };
 
 // 更新策略
 export const updateStrategy = async (strategy: Strategy): Promise<string> => {
-  return await UpdateStrategy(strategy as any);
+  return trackWrite(await UpdateStrategy(strategy as any, strategyRevision));
 };
 
 // 删除策略
 export const deleteStrategy = async (id: string): Promise<string> => {
-  return await DeleteStrategy(id);
+  return trackWrite(await DeleteStrategy(id, strategyRevision));
+};
+
+// 复制策略
+export const duplicateStrategy = async (id: string): Promise<string> => {
+  return trackWrite(await DuplicateStrategy(id));
+};
+
+// 导出策略为 JSON 文本（Wails 将 []byte 编码为 base64 字符串）
+export const exportStrategy = async (id: string): Promise<string> => {
+  const data = (await ExportStrategy(id)) as unknown as string | null;
+  if (!data) return '';
+  return new TextDecoder().decode(Uint8Array.from(atob(data), c => c.charCodeAt(0)));
+};
+
+// 从 JSON 文本导入策略（[]byte 参数同样以 base64 字符串传给后端）
+export const importStrategy = async (json: string): Promise<string> => {
+  const binary = Array.from(new TextEncoder().encode(json), b => String.fromCharCode(b)).join('');
+  return trackWrite(await ImportStrategy(btoa(binary) as unknown as number[]));
+};
+
+// 专家回复语言选项（与后端支持的语言代码一致）
+export const OUTPUT_LANGUAGES: { value: string; label: string }[] = [
+  { value: 'zh', label: '简体中文' },
+  { value: 'zh-TW', label: '繁體中文' },
+  { value: 'en', label: 'English' },
+  { value: 'ja', label: '日本語' },
+];
+
+// 专家配置检查问题
+export interface AgentLintIssue {
+  agentId: string;
+  agentName: string;
+  level: 'error' | 'warning';
+  message: string;
+}
+
+// 检查策略专家的输出语言、语气与指令是否冲突
+export const lintStrategy = async (strategy: Strategy): Promise<AgentLintIssue[]> => {
+  return ((await LintStrategy(strategy as any)) || []) as AgentLintIssue[];
+};
+
+// 策略历史版本（每次更新前保存的完整快照）
+export interface StrategyVersion {
+  versionId: string;
+  createdAt: number;
+  strategy: Strategy;
+}
+
+// 获取策略历史版本（最新的在前）
+export const getStrategyHistory = async (id: string): Promise<StrategyVersion[]> => {
+  return (await GetStrategyHistory(id)) as unknown as StrategyVersion[];
+};
+
+// 恢复策略到指定历史版本
+export const restoreStrategyVersion = async (id: string, versionId: string): Promise<string> => {
+  return trackWrite(await RestoreStrategyVersion(id, versionId));
 };
 
 // AI生成策略
 export const generateStrategy = async (prompt: string): Promise<GenerateStrategyResponse> => {
-  return await GenerateStrategy({ prompt });
+  const result = await GenerateStrategy({ prompt });
+  if (result.success) strategyRevision++;
+  return result;
 };
 
 // 提示词增强请求
@@ -104,6 +181,9 @@ export interface AgentConfig {
   mcpServers: string[];
   enabled: boolean;
   aiConfigId: string;
+  maxTokensPerAgent?: number;
+  outputLanguage?: string;
+  tone?: string;
 }
 
 // 获取所有已启用的Agent配置
@@ -113,15 +193,15 @@ export const getAgentConfigs = async (): Promise<AgentConfig[]> => {
 
 // 添加Agent配置
 export const addAgentConfig = async (config: AgentConfig): Promise<string> => {
-  return await AddAgentConfig(config);
+  return trackWrite(await AddAgentConfig(config));
 };
 
 // 更新Agent配置
 export const updateAgentConfig = async (config: AgentConfig): Promise<string> => {
-  return await UpdateAgentConfig(config);
+  return trackWrite(await UpdateAgentConfig(config, strategyRevision));
 };
 
 // 删除Agent配置
 export const deleteAgentConfig = async (id: string): Promise<string> => {
-  return await DeleteAgentConfig(id);
+  return trackWrite(await DeleteAgentConfig(id, strategyRevision));
 };
@@ -1,6 +1,10 @@
 // 自选股服务 - 调用后端API
-import { GetWatchlist, AddToWatchlist, RemoveFromWatchlist } from '@wailsjs/go/main/App';
-import type { Stock } from '../types';
+import { GetWatchlist, AddToWatchlist, RemoveFromWatchlist, GetWatchlistGroups, CreateWatchlistGroup, RenameWatchlistGroup, DeleteWatchlistGroup, AddStockToGroup, RemoveStockFromGroup, MoveStockToGroup, ReorderWatchlist } from '@wailsjs/go/main/App';
+import type { main } from '@wailsjs/go/models';
+import type { Stock, WatchlistGroup } from '../types';
+
+// 默认分组 ID，与后端 models.DefaultWatchlistGroupID 一致
+export const DEFAULT_WATCHLIST_GROUP_ID = 'default';
 
 export const getWatchlist = async (): Promise<Stock[]> => {
   return await GetWatchlist() as Stock[];
@@ -13,3 +17,40 @@ export const addToWatchlist = async (stock: Stock): Promise<string> => {
 export const removeFromWatchlist = async (symbol: string): Promise<string> => {
   return await RemoveFromWatchlist(symbol);
 };
+
+export const getWatchlistGroups = async (): Promise<WatchlistGroup[]> => {
+  return (await GetWatchlistGroups()) || [];
+};
+
+export const createWatchlistGroup = async (name: string): Promise<main.WatchlistGroupResult> => {
+  return await CreateWatchlistGroup(name);
+};
+
+export const renameWatchlistGroup = async (id: string, name: string): Promise<string> => {
+  return await RenameWatchlistGroup(id, name);
+};
+
+// 删除分组，默认分组不可删除；其中的自选股改到所属的其他分组或默认分组
+export const deleteWatchlistGroup = async (id: string): Promise<string> => {
+  return await DeleteWatchlistGroup(id);
+};
+
+// 同时加入分组，不改变展示位置
+export const addStockToGroup = async (symbol: string, groupId: string): Promise<string> => {
+  return await AddStockToGroup(symbol, groupId);
+};
+
+// 移出分组，自选股本身保留
+export const removeStockFromGroup = async (symbol: string, groupId: string): Promise<string> => {
+  return await RemoveStockFromGroup(symbol, groupId);
+};
+
+// 移动到目标分组末尾
+export const moveStockToGroup = async (symbol: string, groupId: string): Promise<string> => {
+  return await MoveStockToGroup(symbol, groupId);
+};
+
+// 按传入顺序调整各分组内的排列，不跨分组移动
+export const reorderWatchlist = async (symbols: string[]): Promise<string> => {
+  return await ReorderWatchlist(symbols);
+};
@@ -12,6 +12,21 @@ export interface Stock {
   high: number;
   low: number;
   preClose: number;
+  quoteTime?: string; // 行情时间
+  type?: 'stock' | 'index'; // 标的类型，指数不支持持仓
+  dataSuspect?: boolean; // 行情未通过合理性校验
+  suspectReason?: string;
+  quoteState?: 'pre_open' | 'halted'; // 盘前未开盘或停牌，现价为昨收
+  groupId?: string; // 自选股分组
+  sortOrder?: number; // 分组内排序
+}
+
+// 自选股分组
+export interface WatchlistGroup {
+  id: string;
+  name: string;
+  sortOrder: number;
+  symbols: string[]; // 分组内的自选股，一只股票可属于多个分组，groupId 为其展示所在分组
 }
 
 // 股票持仓信息
@@ -73,10 +88,13 @@ export interface ChatMessage {
   mentions?: string[];
   round?: number;        // 讨论轮次
   msgType?: MsgType;     // 消息类型
+  roundLabel?: string;   // 轮次标签（辩论模式）
+  rebuts?: string;       // 反驳的专家 ID
+  asOf?: string;         // 发言所依据的行情数据截至时间
 }
 
 // 消息类型
-export type MsgType = 'opening' | 'opinion' | 'summary';
+export type MsgType = 'opening' | 'opinion' | 'rebuttal' | 'summary';
 
 export type TimePeriod = '1m' | '1d' | '1w' | '1mo';
 
@@ -85,6 +103,8 @@ export interface Telegraph {
   time: string;
   content: string;
   url: string;
+  sentiment?: 'positive' | 'negative' | 'neutral'; // 词典情绪标签
+  sentimentScore?: number;
 }
 
 // MCP 传输类型
@@ -100,6 +120,8 @@ export interface MCPServerConfig {
   args: string[];
   toolFilter: string[];
   enabled: boolean;
+  longRunningTools?: string[];          // 长耗时工具：延长超时并显示进度，可单独取消
+  longRunningTimeoutSeconds?: number;   // 长耗时工具单次调用超时（秒），0 为默认 600
 }
 
 // 大盘指数数据
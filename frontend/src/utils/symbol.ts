@@ -0,0 +1,13 @@
+// 证券代码工具（与后端 models.ClassifySymbol 规则保持一致）
+
+import { Stock } from '../types';
+
+/** 上证 000xxx、深证 399xxx、北证 899xxx 为指数 */
+export function isIndexSymbol(symbol: string): boolean {
+  return /^(sh000|sz399|bj899)\d{3}$/i.test(symbol.trim());
+}
+
+/** 判断标的是否为指数：优先使用后端返回的类型 */
+export function isIndexStock(stock: Pick<Stock, 'symbol' | 'type'>): boolean {
+  return stock.type === 'index' || isIndexSymbol(stock.symbol);
+}
@@ -6,11 +6,21 @@ import {main} from '../models';
 import {hottrend} from '../models';
 import {tools} from '../models';
 import {mcp} from '../models';
+import {adk} from '../models';
+import {capability} from '../models';
+import {meeting} from '../models';
+import {memory} from '../models';
 
 export function AddAgentConfig(arg1:models.AgentConfig):Promise<string>;
 
+export function AddAlert(arg1:models.PriceAlert):Promise<string>;
+
 export function AddMCPServer(arg1:models.MCPServerConfig):Promise<string>;
 
+export function AddPriceAlert(arg1:string,arg2:string,arg3:number):Promise<string>;
+
+export function AddStockToGroup(arg1:string,arg2:string):Promise<string>;
+
 export function AddStrategy(arg1:models.Strategy):Promise<string>;
 
 export function AddToWatchlist(arg1:models.Stock):Promise<string>;
@@ -19,26 +29,68 @@ export function CancelInterruptedMeeting(arg1:string):Promise<boolean>;
 
 export function CancelMeeting(arg1:string):Promise<boolean>;
 
+export function CancelResearchTask(arg1:string):Promise<boolean>;
+
+export function CancelToolCall(arg1:string):Promise<boolean>;
+
 export function CheckForUpdate():Promise<services.UpdateInfo>;
 
 export function ClearSessionMessages(arg1:string):Promise<string>;
 
-export function DeleteAgentConfig(arg1:string):Promise<string>;
+export function CreateAPIToken(arg1:string,arg2:Array<string>):Promise<main.APITokenCreateResult>;
+
+export function CreateWatchlistGroup(arg1:string):Promise<main.WatchlistGroupResult>;
+
+export function DeleteActionJournalEntry(arg1:string,arg2:string):Promise<string>;
+
+export function DeleteAgentConfig(arg1:string,arg2:number):Promise<string>;
 
 export function DeleteMCPServer(arg1:string):Promise<string>;
 
-export function DeleteStrategy(arg1:string):Promise<string>;
+export function DeleteStrategy(arg1:string,arg2:number):Promise<string>;
+
+export function DeleteWatchlistGroup(arg1:string):Promise<string>;
 
 export function DoUpdate():Promise<string>;
 
+export function DryRunMeeting(arg1:main.MeetingMessageRequest):Promise<main.DryRunResult>;
+
+export function DuplicateStrategy(arg1:string):Promise<string>;
+
 export function EnhancePrompt(arg1:main.EnhancePromptRequest):Promise<main.EnhancePromptResponse>;
 
+export function EstimateMeetingCost(arg1:main.MeetingMessageRequest):Promise<main.MeetingCostResult>;
+
+export function ExportKLineCSV(arg1:string,arg2:string,arg3:number,arg4:string):Promise<string>;
+
+export function ExportLongHuBangCSV(arg1:string,arg2:string):Promise<string>;
+
+export function ExportMeetingTranscript(arg1:string,arg2:string):Promise<main.TranscriptExportResult>;
+
+export function ExportPositionsCSV(arg1:string):Promise<string>;
+
+export function ExportStrategy(arg1:string):Promise<Array<number>>;
+
+export function ExportWatchlist(arg1:string,arg2:string):Promise<string>;
+
+export function FlushCaches():Promise<Array<string>>;
+
+export function FocusStock(arg1:string):Promise<void>;
+
 export function GenerateStrategy(arg1:main.GenerateStrategyRequest):Promise<main.GenerateStrategyResponse>;
 
+export function GetAICapabilities(arg1:models.AIConfig):Promise<Array<capability.Info>>;
+
+export function GetAPITokens():Promise<Array<models.APIToken>>;
+
+export function GetActionJournal(arg1:string):Promise<Array<memory.ActionEntry>>;
+
 export function GetActiveStrategyID():Promise<string>;
 
 export function GetAgentConfigs():Promise<Array<models.AgentConfig>>;
 
+export function GetAlerts():Promise<Array<models.PriceAlert>>;
+
 export function GetAllHotTrends():Promise<Array<hottrend.HotTrendResult>>;
 
 export function GetAvailableTools():Promise<Array<tools.ToolInfo>>;
@@ -53,6 +105,8 @@ export function GetHotTrendPlatforms():Promise<Array<hottrend.PlatformInfo>>;
 
 export function GetKLineData(arg1:string,arg2:string,arg3:number):Promise<Array<models.KLineData>>;
 
+export function GetLimitBoardStats(arg1:string):Promise<models.LimitBoardStats>;
+
 export function GetLongHuBangDetail(arg1:string,arg2:string):Promise<Array<models.LongHuBangDetail>>;
 
 export function GetLongHuBangList(arg1:number,arg2:number,arg3:string):Promise<services.LongHuBangListResult>;
@@ -63,18 +117,40 @@ export function GetMCPServers():Promise<Array<models.MCPServerConfig>>;
 
 export function GetMCPStatus():Promise<Array<mcp.ServerStatus>>;
 
+export function GetMeetingDigest(arg1:string,arg2:string):Promise<string>;
+
+export function GetMeetingRuns(arg1:string):Promise<Array<models.MeetingRun>>;
+
+export function GetMeetingTranscript(arg1:string,arg2:string):Promise<Array<number>>;
+
+export function GetMeetingUsage(arg1:string):Promise<meeting.MeetingUsage>;
+
 export function GetOpenClawStatus():Promise<Record<string, any>>;
 
 export function GetOrCreateSession(arg1:string,arg2:string):Promise<models.StockSession>;
 
 export function GetOrderBook(arg1:string):Promise<models.OrderBook>;
 
-export function GetSessionMessages(arg1:string):Promise<Array<models.ChatMessage>>;
+export function GetRelatedMemories(arg1:string,arg2:number):Promise<Array<memory.RelatedMemoryItem>>;
+
+export function GetResearchTasks():Promise<Array<meeting.ResearchTask>>;
+
+export function GetSessionAttachment(arg1:string,arg2:string):Promise<models.SessionAttachment>;
+
+export function GetSessionMessages(arg1:string,arg2:boolean):Promise<Array<models.ChatMessage>>;
+
+export function GetSessionStats(arg1:string):Promise<services.SessionStats>;
 
 export function GetStockRealTimeData(arg1:Array<string>):Promise<Array<models.Stock>>;
 
 export function GetStrategies():Promise<Array<models.Strategy>>;
 
+export function GetStrategyHistory(arg1:string):Promise<Array<services.StrategyVersion>>;
+
+export function GetStrategyRevision():Promise<number>;
+
+export function GetSuggestedQueries(arg1:string):Promise<Array<services.SuggestedQuery>>;
+
 export function GetTelegraphList():Promise<Array<services.Telegraph>>;
 
 export function GetTradeDates(arg1:number):Promise<Array<string>>;
@@ -83,31 +159,79 @@ export function GetTradingSchedule():Promise<services.TradingSchedule>;
 
 export function GetWatchlist():Promise<Array<models.Stock>>;
 
+export function GetWatchlistGroups():Promise<Array<models.WatchlistGroup>>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportStrategy(arg1:Array<number>):Promise<string>;
+
+export function ImportWatchlist(arg1:string,arg2:string):Promise<main.WatchlistImportResult>;
+
+export function LintStrategy(arg1:models.Strategy):Promise<Array<services.AgentLintIssue>>;
+
+export function ListMemorySnapshots(arg1:string):Promise<Array<memory.SnapshotInfo>>;
+
+export function ListProviderModels(arg1:string):Promise<main.ProviderModelsResult>;
+
+export function MoveStockToGroup(arg1:string,arg2:string):Promise<string>;
+
 export function NotifyFrontendReady():Promise<void>;
 
 export function OpenURL(arg1:string):Promise<void>;
 
+export function RefreshMCPToolCatalog(arg1:string):Promise<string>;
+
+export function RemoveAlert(arg1:string):Promise<string>;
+
 export function RemoveFromWatchlist(arg1:string):Promise<string>;
 
+export function RemoveStockFromGroup(arg1:string,arg2:string):Promise<string>;
+
+export function RenameWatchlistGroup(arg1:string,arg2:string):Promise<string>;
+
+export function ReorderWatchlist(arg1:Array<string>):Promise<string>;
+
+export function ResolveStockReference(arg1:string):Promise<services.StockReferenceResult>;
+
 export function RestartApp():Promise<string>;
 
+export function RestoreMemorySnapshot(arg1:string,arg2:string):Promise<string>;
+
+export function RestoreStrategyVersion(arg1:string,arg2:string):Promise<string>;
+
+export function ResumeMeeting(arg1:string):Promise<Array<models.ChatMessage>>;
+
 export function RetryAgent(arg1:string,arg2:string,arg3:string):Promise<models.ChatMessage>;
 
 export function RetryAgentAndContinue(arg1:string):Promise<Array<models.ChatMessage>>;
 
+export function RevokeAPIToken(arg1:string):Promise<string>;
+
+export function RunWatchlistScan():Promise<string>;
+
+export function SearchSessions(arg1:string,arg2:number):Promise<Array<services.SessionSearchResult>>;
+
 export function SearchStocks(arg1:string):Promise<Array<services.StockSearchResult>>;
 
 export function SendMeetingMessage(arg1:main.MeetingMessageRequest):Promise<Array<models.ChatMessage>>;
 
 export function SetActiveStrategy(arg1:string):Promise<string>;
 
+export function SetAutoTranslate(arg1:string,arg2:string):Promise<string>;
+
+export function SnapshotMemory(arg1:string):Promise<string>;
+
+export function StartResearchTask(arg1:string,arg2:string):Promise<string>;
+
 export function TestAIConnection(arg1:models.AIConfig):Promise<string>;
 
 export function TestMCPConnection(arg1:string):Promise<mcp.ServerStatus>;
 
-export function UpdateAgentConfig(arg1:models.AgentConfig):Promise<string>;
+export function UnfocusStock():Promise<void>;
+
+export function UpdateActionJournalEntry(arg1:string,arg2:memory.ActionEntry):Promise<string>;
+
+export function UpdateAgentConfig(arg1:models.AgentConfig,arg2:number):Promise<string>;
 
 export function UpdateConfig(arg1:models.AppConfig):Promise<string>;
 
@@ -115,7 +239,9 @@ export function UpdateMCPServer(arg1:models.MCPServerConfig):Promise<string>;
 
 export function UpdateStockPosition(arg1:string,arg2:number,arg3:number):Promise<string>;
 
-export function UpdateStrategy(arg1:models.Strategy):Promise<string>;
+export function UpdateStrategy(arg1:models.Strategy,arg2:number):Promise<string>;
+
+export function WarmUpAgents(arg1:string):Promise<void>;
 
 export function WindowClose():Promise<void>;
 
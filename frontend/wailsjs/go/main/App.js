@@ -6,10 +6,22 @@ export function AddAgentConfig(arg1) {
   return window['go']['main']['App']['AddAgentConfig'](arg1);
 }
 
+export function AddAlert(arg1) {
+  return window['go']['main']['App']['AddAlert'](arg1);
+}
+
 export function AddMCPServer(arg1) {
   return window['go']['main']['App']['AddMCPServer'](arg1);
 }
 
+export function AddPriceAlert(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddPriceAlert'](arg1, arg2, arg3);
+}
+
+export function AddStockToGroup(arg1, arg2) {
+  return window['go']['main']['App']['AddStockToGroup'](arg1, arg2);
+}
+
 export function AddStrategy(arg1) {
   return window['go']['main']['App']['AddStrategy'](arg1);
 }
@@ -26,6 +38,14 @@ export function CancelMeeting(arg1) {
   return window['go']['main']['App']['CancelMeeting'](arg1);
 }
 
+export function CancelResearchTask(arg1) {
+  return window['go']['main']['App']['CancelResearchTask'](arg1);
+}
+
+export function CancelToolCall(arg1) {
+  return window['go']['main']['App']['CancelToolCall'](arg1);
+}
+
 export function CheckForUpdate() {
   return window['go']['main']['App']['CheckForUpdate']();
 }
@@ -34,30 +54,102 @@ export function ClearSessionMessages(arg1) {
   return window['go']['main']['App']['ClearSessionMessages'](arg1);
 }
 
-export function DeleteAgentConfig(arg1) {
-  return window['go']['main']['App']['DeleteAgentConfig'](arg1);
+export function CreateAPIToken(arg1, arg2) {
+  return window['go']['main']['App']['CreateAPIToken'](arg1, arg2);
+}
+
+export function CreateWatchlistGroup(arg1) {
+  return window['go']['main']['App']['CreateWatchlistGroup'](arg1);
+}
+
+export function DeleteActionJournalEntry(arg1, arg2) {
+  return window['go']['main']['App']['DeleteActionJournalEntry'](arg1, arg2);
+}
+
+export function DeleteAgentConfig(arg1, arg2) {
+  return window['go']['main']['App']['DeleteAgentConfig'](arg1, arg2);
 }
 
 export function DeleteMCPServer(arg1) {
   return window['go']['main']['App']['DeleteMCPServer'](arg1);
 }
 
-export function DeleteStrategy(arg1) {
-  return window['go']['main']['App']['DeleteStrategy'](arg1);
+export function DeleteStrategy(arg1, arg2) {
+  return window['go']['main']['App']['DeleteStrategy'](arg1, arg2);
+}
+
+export function DeleteWatchlistGroup(arg1) {
+  return window['go']['main']['App']['DeleteWatchlistGroup'](arg1);
 }
 
 export function DoUpdate() {
   return window['go']['main']['App']['DoUpdate']();
 }
 
+export function DryRunMeeting(arg1) {
+  return window['go']['main']['App']['DryRunMeeting'](arg1);
+}
+
+export function DuplicateStrategy(arg1) {
+  return window['go']['main']['App']['DuplicateStrategy'](arg1);
+}
+
 export function EnhancePrompt(arg1) {
   return window['go']['main']['App']['EnhancePrompt'](arg1);
 }
 
+export function EstimateMeetingCost(arg1) {
+  return window['go']['main']['App']['EstimateMeetingCost'](arg1);
+}
+
+export function ExportKLineCSV(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportKLineCSV'](arg1, arg2, arg3, arg4);
+}
+
+export function ExportLongHuBangCSV(arg1, arg2) {
+  return window['go']['main']['App']['ExportLongHuBangCSV'](arg1, arg2);
+}
+
+export function ExportMeetingTranscript(arg1, arg2) {
+  return window['go']['main']['App']['ExportMeetingTranscript'](arg1, arg2);
+}
+
+export function ExportPositionsCSV(arg1) {
+  return window['go']['main']['App']['ExportPositionsCSV'](arg1);
+}
+
+export function ExportStrategy(arg1) {
+  return window['go']['main']['App']['ExportStrategy'](arg1);
+}
+
+export function ExportWatchlist(arg1, arg2) {
+  return window['go']['main']['App']['ExportWatchlist'](arg1, arg2);
+}
+
+export function FlushCaches() {
+  return window['go']['main']['App']['FlushCaches']();
+}
+
+export function FocusStock(arg1) {
+  return window['go']['main']['App']['FocusStock'](arg1);
+}
+
 export function GenerateStrategy(arg1) {
   return window['go']['main']['App']['GenerateStrategy'](arg1);
 }
 
+export function GetAICapabilities(arg1) {
+  return window['go']['main']['App']['GetAICapabilities'](arg1);
+}
+
+export function GetAPITokens() {
+  return window['go']['main']['App']['GetAPITokens']();
+}
+
+export function GetActionJournal(arg1) {
+  return window['go']['main']['App']['GetActionJournal'](arg1);
+}
+
 export function GetActiveStrategyID() {
   return window['go']['main']['App']['GetActiveStrategyID']();
 }
@@ -66,6 +158,10 @@ export function GetAgentConfigs() {
   return window['go']['main']['App']['GetAgentConfigs']();
 }
 
+export function GetAlerts() {
+  return window['go']['main']['App']['GetAlerts']();
+}
+
 export function GetAllHotTrends() {
   return window['go']['main']['App']['GetAllHotTrends']();
 }
@@ -94,6 +190,10 @@ export function GetKLineData(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetKLineData'](arg1, arg2, arg3);
 }
 
+export function GetLimitBoardStats(arg1) {
+  return window['go']['main']['App']['GetLimitBoardStats'](arg1);
+}
+
 export function GetLongHuBangDetail(arg1, arg2) {
   return window['go']['main']['App']['GetLongHuBangDetail'](arg1, arg2);
 }
@@ -114,6 +214,22 @@ export function GetMCPStatus() {
   return window['go']['main']['App']['GetMCPStatus']();
 }
 
+export function GetMeetingDigest(arg1, arg2) {
+  return window['go']['main']['App']['GetMeetingDigest'](arg1, arg2);
+}
+
+export function GetMeetingRuns(arg1) {
+  return window['go']['main']['App']['GetMeetingRuns'](arg1);
+}
+
+export function GetMeetingTranscript(arg1, arg2) {
+  return window['go']['main']['App']['GetMeetingTranscript'](arg1, arg2);
+}
+
+export function GetMeetingUsage(arg1) {
+  return window['go']['main']['App']['GetMeetingUsage'](arg1);
+}
+
 export function GetOpenClawStatus() {
   return window['go']['main']['App']['GetOpenClawStatus']();
 }
@@ -126,8 +242,24 @@ export function GetOrderBook(arg1) {
   return window['go']['main']['App']['GetOrderBook'](arg1);
 }
 
-export function GetSessionMessages(arg1) {
-  return window['go']['main']['App']['GetSessionMessages'](arg1);
+export function GetRelatedMemories(arg1, arg2) {
+  return window['go']['main']['App']['GetRelatedMemories'](arg1, arg2);
+}
+
+export function GetResearchTasks() {
+  return window['go']['main']['App']['GetResearchTasks']();
+}
+
+export function GetSessionAttachment(arg1, arg2) {
+  return window['go']['main']['App']['GetSessionAttachment'](arg1, arg2);
+}
+
+export function GetSessionMessages(arg1, arg2) {
+  return window['go']['main']['App']['GetSessionMessages'](arg1, arg2);
+}
+
+export function GetSessionStats(arg1) {
+  return window['go']['main']['App']['GetSessionStats'](arg1);
 }
 
 export function GetStockRealTimeData(arg1) {
@@ -138,6 +270,18 @@ export function GetStrategies() {
   return window['go']['main']['App']['GetStrategies']();
 }
 
+export function GetStrategyHistory(arg1) {
+  return window['go']['main']['App']['GetStrategyHistory'](arg1);
+}
+
+export function GetStrategyRevision() {
+  return window['go']['main']['App']['GetStrategyRevision']();
+}
+
+export function GetSuggestedQueries(arg1) {
+  return window['go']['main']['App']['GetSuggestedQueries'](arg1);
+}
+
 export function GetTelegraphList() {
   return window['go']['main']['App']['GetTelegraphList']();
 }
@@ -154,10 +298,38 @@ export function GetWatchlist() {
   return window['go']['main']['App']['GetWatchlist']();
 }
 
+export function GetWatchlistGroups() {
+  return window['go']['main']['App']['GetWatchlistGroups']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportStrategy(arg1) {
+  return window['go']['main']['App']['ImportStrategy'](arg1);
+}
+
+export function ImportWatchlist(arg1, arg2) {
+  return window['go']['main']['App']['ImportWatchlist'](arg1, arg2);
+}
+
+export function LintStrategy(arg1) {
+  return window['go']['main']['App']['LintStrategy'](arg1);
+}
+
+export function ListMemorySnapshots(arg1) {
+  return window['go']['main']['App']['ListMemorySnapshots'](arg1);
+}
+
+export function ListProviderModels(arg1) {
+  return window['go']['main']['App']['ListProviderModels'](arg1);
+}
+
+export function MoveStockToGroup(arg1, arg2) {
+  return window['go']['main']['App']['MoveStockToGroup'](arg1, arg2);
+}
+
 export function NotifyFrontendReady() {
   return window['go']['main']['App']['NotifyFrontendReady']();
 }
@@ -166,14 +338,50 @@ export function OpenURL(arg1) {
   return window['go']['main']['App']['OpenURL'](arg1);
 }
 
+export function RefreshMCPToolCatalog(arg1) {
+  return window['go']['main']['App']['RefreshMCPToolCatalog'](arg1);
+}
+
+export function RemoveAlert(arg1) {
+  return window['go']['main']['App']['RemoveAlert'](arg1);
+}
+
 export function RemoveFromWatchlist(arg1) {
   return window['go']['main']['App']['RemoveFromWatchlist'](arg1);
 }
 
+export function RemoveStockFromGroup(arg1, arg2) {
+  return window['go']['main']['App']['RemoveStockFromGroup'](arg1, arg2);
+}
+
+export function RenameWatchlistGroup(arg1, arg2) {
+  return window['go']['main']['App']['RenameWatchlistGroup'](arg1, arg2);
+}
+
+export function ReorderWatchlist(arg1) {
+  return window['go']['main']['App']['ReorderWatchlist'](arg1);
+}
+
+export function ResolveStockReference(arg1) {
+  return window['go']['main']['App']['ResolveStockReference'](arg1);
+}
+
 export function RestartApp() {
   return window['go']['main']['App']['RestartApp']();
 }
 
+export function RestoreMemorySnapshot(arg1, arg2) {
+  return window['go']['main']['App']['RestoreMemorySnapshot'](arg1, arg2);
+}
+
+export function RestoreStrategyVersion(arg1, arg2) {
+  return window['go']['main']['App']['RestoreStrategyVersion'](arg1, arg2);
+}
+
+export function ResumeMeeting(arg1) {
+  return window['go']['main']['App']['ResumeMeeting'](arg1);
+}
+
 export function RetryAgent(arg1, arg2, arg3) {
   return window['go']['main']['App']['RetryAgent'](arg1, arg2, arg3);
 }
@@ -182,6 +390,18 @@ export function RetryAgentAndContinue(arg1) {
   return window['go']['main']['App']['RetryAgentAndContinue'](arg1);
 }
 
+export function RevokeAPIToken(arg1) {
+  return window['go']['main']['App']['RevokeAPIToken'](arg1);
+}
+
+export function RunWatchlistScan() {
+  return window['go']['main']['App']['RunWatchlistScan']();
+}
+
+export function SearchSessions(arg1, arg2) {
+  return window['go']['main']['App']['SearchSessions'](arg1, arg2);
+}
+
 export function SearchStocks(arg1) {
   return window['go']['main']['App']['SearchStocks'](arg1);
 }
@@ -194,6 +414,18 @@ export function SetActiveStrategy(arg1) {
   return window['go']['main']['App']['SetActiveStrategy'](arg1);
 }
 
+export function SetAutoTranslate(arg1, arg2) {
+  return window['go']['main']['App']['SetAutoTranslate'](arg1, arg2);
+}
+
+export function SnapshotMemory(arg1) {
+  return window['go']['main']['App']['SnapshotMemory'](arg1);
+}
+
+export function StartResearchTask(arg1, arg2) {
+  return window['go']['main']['App']['StartResearchTask'](arg1, arg2);
+}
+
 export function TestAIConnection(arg1) {
   return window['go']['main']['App']['TestAIConnection'](arg1);
 }
@@ -202,8 +434,16 @@ export function TestMCPConnection(arg1) {
   return window['go']['main']['App']['TestMCPConnection'](arg1);
 }
 
-export function UpdateAgentConfig(arg1) {
-  return window['go']['main']['App']['UpdateAgentConfig'](arg1);
+export function UnfocusStock() {
+  return window['go']['main']['App']['UnfocusStock']();
+}
+
+export function UpdateActionJournalEntry(arg1, arg2) {
+  return window['go']['main']['App']['UpdateActionJournalEntry'](arg1, arg2);
+}
+
+export function UpdateAgentConfig(arg1, arg2) {
+  return window['go']['main']['App']['UpdateAgentConfig'](arg1, arg2);
 }
 
 export function UpdateConfig(arg1) {
@@ -218,8 +458,12 @@ export function UpdateStockPosition(arg1, arg2, arg3) {
   return window['go']['main']['App']['UpdateStockPosition'](arg1, arg2, arg3);
 }
 
-export function UpdateStrategy(arg1) {
-  return window['go']['main']['App']['UpdateStrategy'](arg1);
+export function UpdateStrategy(arg1, arg2) {
+  return window['go']['main']['App']['UpdateStrategy'](arg1, arg2);
+}
+
+export function WarmUpAgents(arg1) {
+  return window['go']['main']['App']['WarmUpAgents'](arg1);
 }
 
 export function WindowClose() {
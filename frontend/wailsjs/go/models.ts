@@ -1,3 +1,47 @@
+export namespace adk {
+	
+	export class ModelInfo {
+	    id: string;
+	    displayName: string;
+	    contextWindow: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.displayName = source["displayName"];
+	        this.contextWindow = source["contextWindow"];
+	    }
+	}
+
+}
+
+export namespace capability {
+	
+	export class Info {
+	    capability: string;
+	    label: string;
+	    supported: boolean;
+	    learned: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.capability = source["capability"];
+	        this.label = source["label"];
+	        this.supported = source["supported"];
+	        this.learned = source["learned"];
+	    }
+	}
+
+}
+
 export namespace hottrend {
 	
 	export class HotItem {
@@ -31,6 +75,8 @@ export namespace hottrend {
 	    // Go type: time
 	    updated_at: any;
 	    from_cache: boolean;
+	    stale: boolean;
+	    loading: boolean;
 	    error: string;
 	
 	    static createFrom(source: any = {}) {
@@ -44,6 +90,8 @@ export namespace hottrend {
 	        this.items = this.convertValues(source["items"], HotItem);
 	        this.updated_at = this.convertValues(source["updated_at"], null);
 	        this.from_cache = source["from_cache"];
+	        this.stale = source["stale"];
+	        this.loading = source["loading"];
 	        this.error = source["error"];
 	    }
 	
@@ -86,6 +134,72 @@ export namespace hottrend {
 
 export namespace main {
 	
+	export class APITokenCreateResult {
+	    token: models.APIToken;
+	    plaintext: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new APITokenCreateResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.token = this.convertValues(source["token"], models.APIToken);
+	        this.plaintext = source["plaintext"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DryRunResult {
+	    plans: meeting.DryRunPlan[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DryRunResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.plans = this.convertValues(source["plans"], meeting.DryRunPlan);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class EnhancePromptRequest {
 	    originalPrompt: string;
 	    agentRole: string;
@@ -166,12 +280,51 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class MeetingCostResult {
+	    estimate?: meeting.MeetingCostEstimate;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingCostResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.estimate = this.convertValues(source["estimate"], meeting.MeetingCostEstimate);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class MeetingMessageRequest {
 	    stockCode: string;
 	    content: string;
+	    images?: string[];
 	    mentionIds: string[];
 	    replyToId: string;
 	    replyContent: string;
+	    visibility: string;
+	    forceSuspectData?: boolean;
+	    mode?: string;
+	    reproducible?: boolean;
+	    seed?: number;
+	    concurrentMode?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new MeetingMessageRequest(source);
@@ -181,9 +334,114 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.stockCode = source["stockCode"];
 	        this.content = source["content"];
+	        this.images = source["images"];
 	        this.mentionIds = source["mentionIds"];
 	        this.replyToId = source["replyToId"];
 	        this.replyContent = source["replyContent"];
+	        this.visibility = source["visibility"];
+	        this.forceSuspectData = source["forceSuspectData"];
+	        this.mode = source["mode"];
+	        this.reproducible = source["reproducible"];
+	        this.seed = source["seed"];
+	        this.concurrentMode = source["concurrentMode"];
+	    }
+	}
+	export class ProviderModelsResult {
+	    models: adk.ModelInfo[];
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProviderModelsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.models = this.convertValues(source["models"], adk.ModelInfo);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TranscriptExportResult {
+	    path: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscriptExportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.error = source["error"];
+	    }
+	}
+	export class WatchlistGroupResult {
+	    group: models.WatchlistGroup;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchlistGroupResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.group = this.convertValues(source["group"], models.WatchlistGroup);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WatchlistImportResult {
+	    imported: number;
+	    skipped: number;
+	    invalid: number;
+	    cancelled: boolean;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchlistImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.imported = source["imported"];
+	        this.skipped = source["skipped"];
+	        this.invalid = source["invalid"];
+	        this.cancelled = source["cancelled"];
+	        this.error = source["error"];
 	    }
 	}
 
@@ -195,6 +453,8 @@ export namespace mcp {
 	    id: string;
 	    connected: boolean;
 	    error: string;
+	    lastSuccess: number;
+	    failures: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new ServerStatus(source);
@@ -205,6 +465,8 @@ export namespace mcp {
 	        this.id = source["id"];
 	        this.connected = source["connected"];
 	        this.error = source["error"];
+	        this.lastSuccess = source["lastSuccess"];
+	        this.failures = source["failures"];
 	    }
 	}
 	export class ToolInfo {
@@ -212,6 +474,7 @@ export namespace mcp {
 	    description: string;
 	    serverId: string;
 	    serverName: string;
+	    stale: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ToolInfo(source);
@@ -223,86 +486,431 @@ export namespace mcp {
 	        this.description = source["description"];
 	        this.serverId = source["serverId"];
 	        this.serverName = source["serverName"];
+	        this.stale = source["stale"];
 	    }
 	}
 
 }
 
-export namespace models {
+export namespace meeting {
 	
-	export class AIConfig {
-	    id: string;
-	    name: string;
-	    provider: string;
-	    baseUrl: string;
-	    apiKey: string;
-	    modelName: string;
-	    maxTokens: number;
-	    temperature: number;
-	    timeout: number;
-	    isDefault: boolean;
-	    useResponses: boolean;
-	    noSystemRole: boolean;
-	    project: string;
-	    location: string;
-	    credentialsJson: string;
+	export class AgentCostEstimate {
+	    agentId: string;
+	    agentName: string;
+	    model: string;
+	    requests: number;
+	    promptTokens: number;
+	    completionTokens: number;
+	    cost: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new AIConfig(source);
+	        return new AgentCostEstimate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.provider = source["provider"];
-	        this.baseUrl = source["baseUrl"];
-	        this.apiKey = source["apiKey"];
-	        this.modelName = source["modelName"];
-	        this.maxTokens = source["maxTokens"];
-	        this.temperature = source["temperature"];
-	        this.timeout = source["timeout"];
-	        this.isDefault = source["isDefault"];
-	        this.useResponses = source["useResponses"];
-	        this.noSystemRole = source["noSystemRole"];
-	        this.project = source["project"];
-	        this.location = source["location"];
-	        this.credentialsJson = source["credentialsJson"];
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.model = source["model"];
+	        this.requests = source["requests"];
+	        this.promptTokens = source["promptTokens"];
+	        this.completionTokens = source["completionTokens"];
+	        this.cost = source["cost"];
 	    }
 	}
-	export class AgentConfig {
-	    id: string;
-	    name: string;
-	    role: string;
-	    avatar: string;
-	    color: string;
-	    instruction: string;
-	    tools: string[];
-	    mcpServers: string[];
-	    enabled: boolean;
-	    aiConfigId: string;
+	export class DryRunPlan {
+	    agentId: string;
+	    agentName: string;
+	    plannedToolCalls: string[];
+	    reasoning: string;
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new AgentConfig(source);
+	        return new DryRunPlan(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.role = source["role"];
-	        this.avatar = source["avatar"];
-	        this.color = source["color"];
-	        this.instruction = source["instruction"];
-	        this.tools = source["tools"];
-	        this.mcpServers = source["mcpServers"];
-	        this.enabled = source["enabled"];
-	        this.aiConfigId = source["aiConfigId"];
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.plannedToolCalls = source["plannedToolCalls"];
+	        this.reasoning = source["reasoning"];
+	        this.error = source["error"];
 	    }
 	}
-	export class KDJConfig {
-	    enabled: boolean;
-	    period: number;
+	export class MeetingCostEstimate {
+	    mode: string;
+	    agents: AgentCostEstimate[];
+	    promptTokens: number;
+	    completionTokens: number;
+	    totalTokens: number;
+	    cost: number;
+	    minTokens: number;
+	    maxTokens: number;
+	    minCost: number;
+	    maxCost: number;
+	    assumptions: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingCostEstimate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.agents = this.convertValues(source["agents"], AgentCostEstimate);
+	        this.promptTokens = source["promptTokens"];
+	        this.completionTokens = source["completionTokens"];
+	        this.totalTokens = source["totalTokens"];
+	        this.cost = source["cost"];
+	        this.minTokens = source["minTokens"];
+	        this.maxTokens = source["maxTokens"];
+	        this.minCost = source["minCost"];
+	        this.maxCost = source["maxCost"];
+	        this.assumptions = source["assumptions"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MeetingUsage {
+	    stockCode: string;
+	    promptTokens: number;
+	    completionTokens: number;
+	    totalTokens: number;
+	    cost: number;
+	    turns: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.promptTokens = source["promptTokens"];
+	        this.completionTokens = source["completionTokens"];
+	        this.totalTokens = source["totalTokens"];
+	        this.cost = source["cost"];
+	        this.turns = source["turns"];
+	    }
+	}
+	export class ResearchTask {
+	    id: string;
+	    stockCode: string;
+	    stockName: string;
+	    focus: string;
+	    status: string;
+	    createdAt: number;
+	    startedAt?: number;
+	    finishedAt?: number;
+	    error?: string;
+	    toolCalls: number;
+	    cost: number;
+	    attachmentId?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResearchTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.focus = source["focus"];
+	        this.status = source["status"];
+	        this.createdAt = source["createdAt"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	        this.error = source["error"];
+	        this.toolCalls = source["toolCalls"];
+	        this.cost = source["cost"];
+	        this.attachmentId = source["attachmentId"];
+	    }
+	}
+
+}
+
+export namespace memory {
+	
+	export class ActionEntry {
+	    id: string;
+	    stockCode: string;
+	    action: string;
+	    price?: number;
+	    quantity?: string;
+	    note: string;
+	    source: string;
+	    timestamp: number;
+	    edited?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActionEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.stockCode = source["stockCode"];
+	        this.action = source["action"];
+	        this.price = source["price"];
+	        this.quantity = source["quantity"];
+	        this.note = source["note"];
+	        this.source = source["source"];
+	        this.timestamp = source["timestamp"];
+	        this.edited = source["edited"];
+	    }
+	}
+	export class RelatedMemoryItem {
+	    stockCode: string;
+	    stockName: string;
+	    snippet: string;
+	    mentions: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RelatedMemoryItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.snippet = source["snippet"];
+	        this.mentions = source["mentions"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class SnapshotInfo {
+	    id: string;
+	    stockCode: string;
+	    createdAt: number;
+	    summary: string;
+	    totalRounds: number;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SnapshotInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.stockCode = source["stockCode"];
+	        this.createdAt = source["createdAt"];
+	        this.summary = source["summary"];
+	        this.totalRounds = source["totalRounds"];
+	        this.size = source["size"];
+	    }
+	}
+
+}
+
+export namespace models {
+	
+	export class AIConfig {
+	    id: string;
+	    name: string;
+	    provider: string;
+	    baseUrl: string;
+	    apiKey: string;
+	    modelName: string;
+	    maxTokens: number;
+	    temperature: number;
+	    timeout: number;
+	    isDefault: boolean;
+	    retryMaxAttempts?: number;
+	    retryBaseDelayMs?: number;
+	    useResponses: boolean;
+	    noSystemRole: boolean;
+	    forcedToolName?: string;
+	    isAzure: boolean;
+	    apiVersion: string;
+	    deploymentName: string;
+	    thinkingBudget?: number;
+	    enablePromptCache?: boolean;
+	    capabilities?: Record<string, boolean>;
+	    inputPricePer1K?: number;
+	    outputPricePer1K?: number;
+	    project: string;
+	    location: string;
+	    credentialsJson: string;
+	    publisher?: string;
+	    awsRegion?: string;
+	    awsAccessKeyId?: string;
+	    awsSecretAccessKey?: string;
+	    awsSessionToken?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AIConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.provider = source["provider"];
+	        this.baseUrl = source["baseUrl"];
+	        this.apiKey = source["apiKey"];
+	        this.modelName = source["modelName"];
+	        this.maxTokens = source["maxTokens"];
+	        this.temperature = source["temperature"];
+	        this.timeout = source["timeout"];
+	        this.isDefault = source["isDefault"];
+	        this.retryMaxAttempts = source["retryMaxAttempts"];
+	        this.retryBaseDelayMs = source["retryBaseDelayMs"];
+	        this.useResponses = source["useResponses"];
+	        this.noSystemRole = source["noSystemRole"];
+	        this.forcedToolName = source["forcedToolName"];
+	        this.isAzure = source["isAzure"];
+	        this.apiVersion = source["apiVersion"];
+	        this.deploymentName = source["deploymentName"];
+	        this.thinkingBudget = source["thinkingBudget"];
+	        this.enablePromptCache = source["enablePromptCache"];
+	        this.capabilities = source["capabilities"];
+	        this.inputPricePer1K = source["inputPricePer1K"];
+	        this.outputPricePer1K = source["outputPricePer1K"];
+	        this.project = source["project"];
+	        this.location = source["location"];
+	        this.credentialsJson = source["credentialsJson"];
+	        this.publisher = source["publisher"];
+	        this.awsRegion = source["awsRegion"];
+	        this.awsAccessKeyId = source["awsAccessKeyId"];
+	        this.awsSecretAccessKey = source["awsSecretAccessKey"];
+	        this.awsSessionToken = source["awsSessionToken"];
+	    }
+	}
+	export class APIToken {
+	    id: string;
+	    name: string;
+	    tokenHash: string;
+	    scopes: string[];
+	    createdAt: number;
+	    lastUsedAt?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new APIToken(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.tokenHash = source["tokenHash"];
+	        this.scopes = source["scopes"];
+	        this.createdAt = source["createdAt"];
+	        this.lastUsedAt = source["lastUsedAt"];
+	    }
+	}
+	export class AgentConfig {
+	    id: string;
+	    name: string;
+	    role: string;
+	    avatar: string;
+	    color: string;
+	    instruction: string;
+	    tools: string[];
+	    mcpServers: string[];
+	    enabled: boolean;
+	    aiConfigId: string;
+	    maxTokensPerAgent?: number;
+	    outputLanguage?: string;
+	    tone?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.role = source["role"];
+	        this.avatar = source["avatar"];
+	        this.color = source["color"];
+	        this.instruction = source["instruction"];
+	        this.tools = source["tools"];
+	        this.mcpServers = source["mcpServers"];
+	        this.enabled = source["enabled"];
+	        this.aiConfigId = source["aiConfigId"];
+	        this.maxTokensPerAgent = source["maxTokensPerAgent"];
+	        this.outputLanguage = source["outputLanguage"];
+	        this.tone = source["tone"];
+	    }
+	}
+	export class MeetingBudget {
+	    maxAgentTurns: number;
+	    maxRounds: number;
+	    researchMaxToolCalls: number;
+	    researchMaxCost: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingBudget(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxAgentTurns = source["maxAgentTurns"];
+	        this.maxRounds = source["maxRounds"];
+	        this.researchMaxToolCalls = source["researchMaxToolCalls"];
+	        this.researchMaxCost = source["researchMaxCost"];
+	    }
+	}
+	export class MeetingSchedule {
+	    id: string;
+	    name: string;
+	    enabled: boolean;
+	    time: string;
+	    stockCodes: string[];
+	    prompt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingSchedule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.enabled = source["enabled"];
+	        this.time = source["time"];
+	        this.stockCodes = source["stockCodes"];
+	        this.prompt = source["prompt"];
+	    }
+	}
+	export class NewsSentiment {
+	    positiveTerms: string[];
+	    negativeTerms: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NewsSentiment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.positiveTerms = source["positiveTerms"];
+	        this.negativeTerms = source["negativeTerms"];
+	    }
+	}
+	export class KDJConfig {
+	    enabled: boolean;
+	    period: number;
 	    k: number;
 	    d: number;
 	
@@ -473,6 +1081,8 @@ export namespace models {
 	export class ProxyConfig {
 	    mode: string;
 	    customUrl: string;
+	    proxyUsername?: string;
+	    proxyPassword?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ProxyConfig(source);
@@ -482,6 +1092,8 @@ export namespace models {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.mode = source["mode"];
 	        this.customUrl = source["customUrl"];
+	        this.proxyUsername = source["proxyUsername"];
+	        this.proxyPassword = source["proxyPassword"];
 	    }
 	}
 	export class MemoryConfig {
@@ -515,6 +1127,9 @@ export namespace models {
 	    args: string[];
 	    toolFilter: string[];
 	    enabled: boolean;
+	    staleToleranceSeconds?: number;
+	    longRunningTools?: string[];
+	    longRunningTimeoutSeconds?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new MCPServerConfig(source);
@@ -530,40 +1145,121 @@ export namespace models {
 	        this.args = source["args"];
 	        this.toolFilter = source["toolFilter"];
 	        this.enabled = source["enabled"];
+	        this.staleToleranceSeconds = source["staleToleranceSeconds"];
+	        this.longRunningTools = source["longRunningTools"];
+	        this.longRunningTimeoutSeconds = source["longRunningTimeoutSeconds"];
 	    }
 	}
-	export class AppConfig {
-	    theme: string;
-	    candleColorMode: string;
-	    aiConfigs: AIConfig[];
-	    defaultAiId: string;
-	    strategyAiId: string;
-	    moderatorAiId: string;
-	    mcpServers: MCPServerConfig[];
-	    memory: MemoryConfig;
-	    proxy: ProxyConfig;
-	    layout: LayoutConfig;
-	    openClaw: OpenClawConfig;
-	    indicators: IndicatorConfig;
+	export class Reproducibility {
+	    temperature: number;
+	    seed: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new AppConfig(source);
+	        return new Reproducibility(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.theme = source["theme"];
-	        this.candleColorMode = source["candleColorMode"];
-	        this.aiConfigs = this.convertValues(source["aiConfigs"], AIConfig);
+	        this.temperature = source["temperature"];
+	        this.seed = source["seed"];
+	    }
+	}
+	export class SearchConfig {
+	    provider: string;
+	    apiKey: string;
+	    maxResults: number;
+	    maxCallsPerMeeting: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.apiKey = source["apiKey"];
+	        this.maxResults = source["maxResults"];
+	        this.maxCallsPerMeeting = source["maxCallsPerMeeting"];
+	    }
+	}
+	export class ToolArgGuard {
+	    policy: string;
+	    patterns: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolArgGuard(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.policy = source["policy"];
+	        this.patterns = source["patterns"];
+	    }
+	}
+	export class AppConfig {
+	    theme: string;
+	    candleColorMode: string;
+	    aiConfigs: AIConfig[];
+	    defaultAiId: string;
+	    strategyAiId: string;
+	    moderatorAiId: string;
+	    researchAiId: string;
+	    mcpServers: MCPServerConfig[];
+	    memory: MemoryConfig;
+	    proxy: ProxyConfig;
+	    layout: LayoutConfig;
+	    openClaw: OpenClawConfig;
+	    apiTokens: APIToken[];
+	    indicators: IndicatorConfig;
+	    thinkingPersist: string;
+	    meetingVisibility: string;
+	    modelWarmup: string;
+	    disableFactCheck: boolean;
+	    newsSentiment: NewsSentiment;
+	    meetingBudget: MeetingBudget;
+	    reproducibility: Reproducibility;
+	    autoContinueTruncated: boolean;
+	    meetingSchedules: MeetingSchedule[];
+	    globalSystemPromptPrefix: string;
+	    globalSystemPromptSuffix: string;
+	    announcementMaxChars: number;
+	    toolArgGuard: ToolArgGuard;
+	    search: SearchConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.theme = source["theme"];
+	        this.candleColorMode = source["candleColorMode"];
+	        this.aiConfigs = this.convertValues(source["aiConfigs"], AIConfig);
 	        this.defaultAiId = source["defaultAiId"];
 	        this.strategyAiId = source["strategyAiId"];
 	        this.moderatorAiId = source["moderatorAiId"];
+	        this.researchAiId = source["researchAiId"];
 	        this.mcpServers = this.convertValues(source["mcpServers"], MCPServerConfig);
 	        this.memory = this.convertValues(source["memory"], MemoryConfig);
 	        this.proxy = this.convertValues(source["proxy"], ProxyConfig);
 	        this.layout = this.convertValues(source["layout"], LayoutConfig);
 	        this.openClaw = this.convertValues(source["openClaw"], OpenClawConfig);
+	        this.apiTokens = this.convertValues(source["apiTokens"], APIToken);
 	        this.indicators = this.convertValues(source["indicators"], IndicatorConfig);
+	        this.thinkingPersist = source["thinkingPersist"];
+	        this.meetingVisibility = source["meetingVisibility"];
+	        this.modelWarmup = source["modelWarmup"];
+	        this.disableFactCheck = source["disableFactCheck"];
+	        this.newsSentiment = this.convertValues(source["newsSentiment"], NewsSentiment);
+	        this.meetingBudget = this.convertValues(source["meetingBudget"], MeetingBudget);
+	        this.reproducibility = this.convertValues(source["reproducibility"], Reproducibility);
+	        this.autoContinueTruncated = source["autoContinueTruncated"];
+	        this.meetingSchedules = this.convertValues(source["meetingSchedules"], MeetingSchedule);
+	        this.globalSystemPromptPrefix = source["globalSystemPromptPrefix"];
+	        this.globalSystemPromptSuffix = source["globalSystemPromptSuffix"];
+	        this.announcementMaxChars = source["announcementMaxChars"];
+	        this.toolArgGuard = this.convertValues(source["toolArgGuard"], ToolArgGuard);
+	        this.search = this.convertValues(source["search"], SearchConfig);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -598,6 +1294,16 @@ export namespace models {
 	    msgType?: string;
 	    error?: string;
 	    meetingMode?: string;
+	    roundLabel?: string;
+	    rebuts?: string;
+	    reasoning?: string;
+	    toolCalls?: string[];
+	    toolResults?: string[];
+	    finishReason?: string;
+	    original?: string;
+	    translatedTo?: string;
+	    attachmentId?: string;
+	    asOf?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ChatMessage(source);
@@ -617,6 +1323,16 @@ export namespace models {
 	        this.msgType = source["msgType"];
 	        this.error = source["error"];
 	        this.meetingMode = source["meetingMode"];
+	        this.roundLabel = source["roundLabel"];
+	        this.rebuts = source["rebuts"];
+	        this.reasoning = source["reasoning"];
+	        this.toolCalls = source["toolCalls"];
+	        this.toolResults = source["toolResults"];
+	        this.finishReason = source["finishReason"];
+	        this.original = source["original"];
+	        this.translatedTo = source["translatedTo"];
+	        this.attachmentId = source["attachmentId"];
+	        this.asOf = source["asOf"];
 	    }
 	}
 	
@@ -655,6 +1371,130 @@ export namespace models {
 	    }
 	}
 	
+	export class LimitBoardStats {
+	    date: string;
+	    limitUpCount: number;
+	    limitDownCount: number;
+	    brokenCount: number;
+	    brokenRatio: number;
+	    maxBoards: number;
+	    ladder: LimitBoardTier[];
+	    limitUp: LimitUpStock[];
+	    limitDown: LimitDownStock[];
+	    final: boolean;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LimitBoardStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.limitUpCount = source["limitUpCount"];
+	        this.limitDownCount = source["limitDownCount"];
+	        this.brokenCount = source["brokenCount"];
+	        this.brokenRatio = source["brokenRatio"];
+	        this.maxBoards = source["maxBoards"];
+	        this.ladder = this.convertValues(source["ladder"], LimitBoardTier);
+	        this.limitUp = this.convertValues(source["limitUp"], LimitUpStock);
+	        this.limitDown = this.convertValues(source["limitDown"], LimitDownStock);
+	        this.final = source["final"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LimitBoardTier {
+	    boards: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LimitBoardTier(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.boards = source["boards"];
+	        this.count = source["count"];
+	    }
+	}
+	export class LimitDownStock {
+	    code: string;
+	    name: string;
+	    price: number;
+	    changePercent: number;
+	    amount: number;
+	    sealAmount: number;
+	    days: number;
+	    lastSealTime: string;
+	    industry: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LimitDownStock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.price = source["price"];
+	        this.changePercent = source["changePercent"];
+	        this.amount = source["amount"];
+	        this.sealAmount = source["sealAmount"];
+	        this.days = source["days"];
+	        this.lastSealTime = source["lastSealTime"];
+	        this.industry = source["industry"];
+	    }
+	}
+	export class LimitUpStock {
+	    code: string;
+	    name: string;
+	    price: number;
+	    changePercent: number;
+	    amount: number;
+	    sealAmount: number;
+	    boards: number;
+	    firstSealTime: string;
+	    lastSealTime: string;
+	    breakCount: number;
+	    industry: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LimitUpStock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.price = source["price"];
+	        this.changePercent = source["changePercent"];
+	        this.amount = source["amount"];
+	        this.sealAmount = source["sealAmount"];
+	        this.boards = source["boards"];
+	        this.firstSealTime = source["firstSealTime"];
+	        this.lastSealTime = source["lastSealTime"];
+	        this.breakCount = source["breakCount"];
+	        this.industry = source["industry"];
+	    }
+	}
 	export class LongHuBangDetail {
 	    rank: number;
 	    operName: string;
@@ -793,6 +1633,58 @@ export namespace models {
 	
 	
 	
+	export class PriceAlert {
+	    id: string;
+	    symbol: string;
+	    name: string;
+	    condition: string;
+	    threshold: number;
+	    aiFollowUp: boolean;
+	    triggered: boolean;
+	    triggerPrice: number;
+	    triggerValue?: number;
+	    createdAt: number;
+	    triggeredAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PriceAlert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.symbol = source["symbol"];
+	        this.name = source["name"];
+	        this.condition = source["condition"];
+	        this.threshold = source["threshold"];
+	        this.aiFollowUp = source["aiFollowUp"];
+	        this.triggered = source["triggered"];
+	        this.triggerPrice = source["triggerPrice"];
+	        this.triggerValue = source["triggerValue"];
+	        this.createdAt = source["createdAt"];
+	        this.triggeredAt = source["triggeredAt"];
+	    }
+	}
+	export class SessionAttachment {
+	    id: string;
+	    kind: string;
+	    title: string;
+	    content: string;
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionAttachment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.title = source["title"];
+	        this.content = source["content"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
 	export class Stock {
 	    symbol: string;
 	    name: string;
@@ -807,6 +1699,13 @@ export namespace models {
 	    high: number;
 	    low: number;
 	    preClose: number;
+	    quoteTime?: string;
+	    type?: string;
+	    dataSuspect?: boolean;
+	    suspectReason?: string;
+	    quoteState?: string;
+	    groupId?: string;
+	    sortOrder?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new Stock(source);
@@ -827,6 +1726,13 @@ export namespace models {
 	        this.high = source["high"];
 	        this.low = source["low"];
 	        this.preClose = source["preClose"];
+	        this.quoteTime = source["quoteTime"];
+	        this.type = source["type"];
+	        this.dataSuspect = source["dataSuspect"];
+	        this.suspectReason = source["suspectReason"];
+	        this.quoteState = source["quoteState"];
+	        this.groupId = source["groupId"];
+	        this.sortOrder = source["sortOrder"];
 	    }
 	}
 	export class StockPosition {
@@ -843,6 +1749,98 @@ export namespace models {
 	        this.costPrice = source["costPrice"];
 	    }
 	}
+	export class MeetingRunAgent {
+	    agentId: string;
+	    agentName: string;
+	    aiConfigId: string;
+	    provider: string;
+	    modelName: string;
+	    temperature: number;
+	    maxTokens?: number;
+	    seedSupported: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingRunAgent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.aiConfigId = source["aiConfigId"];
+	        this.provider = source["provider"];
+	        this.modelName = source["modelName"];
+	        this.temperature = source["temperature"];
+	        this.maxTokens = source["maxTokens"];
+	        this.seedSupported = source["seedSupported"];
+	    }
+	}
+	export class MeetingRun {
+	    id: string;
+	    mode: string;
+	    query: string;
+	    startedAt: number;
+	    finishedAt: number;
+	    reproducible: boolean;
+	    seed?: number;
+	    agents: MeetingRunAgent[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.mode = source["mode"];
+	        this.query = source["query"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	        this.reproducible = source["reproducible"];
+	        this.seed = source["seed"];
+	        this.agents = this.convertValues(source["agents"], MeetingRunAgent);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TokenUsage {
+	    agentId: string;
+	    agentName: string;
+	    inputTokens: number;
+	    outputTokens: number;
+	    totalTokens: number;
+	    calls: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TokenUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.inputTokens = source["inputTokens"];
+	        this.outputTokens = source["outputTokens"];
+	        this.totalTokens = source["totalTokens"];
+	        this.calls = source["calls"];
+	    }
+	}
 	export class StockSession {
 	    id: string;
 	    stockCode: string;
@@ -851,6 +1849,10 @@ export namespace models {
 	    position?: StockPosition;
 	    createdAt: number;
 	    updatedAt: number;
+	    tokenUsage?: Record<string, TokenUsage>;
+	    autoTranslate?: string;
+	    meetingRuns?: MeetingRun[];
+	    attachments?: SessionAttachment[];
 	
 	    static createFrom(source: any = {}) {
 	        return new StockSession(source);
@@ -865,6 +1867,10 @@ export namespace models {
 	        this.position = this.convertValues(source["position"], StockPosition);
 	        this.createdAt = source["createdAt"];
 	        this.updatedAt = source["updatedAt"];
+	        this.tokenUsage = this.convertValues(source["tokenUsage"], TokenUsage, true);
+	        this.autoTranslate = source["autoTranslate"];
+	        this.meetingRuns = this.convertValues(source["meetingRuns"], MeetingRun);
+	        this.attachments = this.convertValues(source["attachments"], SessionAttachment);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -896,6 +1902,9 @@ export namespace models {
 	    mcpServers: string[];
 	    enabled: boolean;
 	    aiConfigId: string;
+	    maxTokensPerAgent?: number;
+	    outputLanguage?: string;
+	    tone?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new StrategyAgent(source);
@@ -913,6 +1922,9 @@ export namespace models {
 	        this.mcpServers = source["mcpServers"];
 	        this.enabled = source["enabled"];
 	        this.aiConfigId = source["aiConfigId"];
+	        this.maxTokensPerAgent = source["maxTokensPerAgent"];
+	        this.outputLanguage = source["outputLanguage"];
+	        this.tone = source["tone"];
 	    }
 	}
 	export class Strategy {
@@ -921,6 +1933,9 @@ export namespace models {
 	    description: string;
 	    color: string;
 	    agents: StrategyAgent[];
+	    parallelRounds: boolean;
+	    maxRounds: number;
+	    agentTimeoutSeconds: number;
 	    isBuiltin: boolean;
 	    source: string;
 	    sourceMeta: string;
@@ -937,6 +1952,9 @@ export namespace models {
 	        this.description = source["description"];
 	        this.color = source["color"];
 	        this.agents = this.convertValues(source["agents"], StrategyAgent);
+	        this.parallelRounds = source["parallelRounds"];
+	        this.maxRounds = source["maxRounds"];
+	        this.agentTimeoutSeconds = source["agentTimeoutSeconds"];
 	        this.isBuiltin = source["isBuiltin"];
 	        this.source = source["source"];
 	        this.sourceMeta = source["sourceMeta"];
@@ -962,10 +1980,46 @@ export namespace models {
 		}
 	}
 
+	export class WatchlistGroup {
+	    id: string;
+	    name: string;
+	    sortOrder: number;
+	    symbols: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchlistGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.sortOrder = source["sortOrder"];
+	        this.symbols = source["symbols"];
+	    }
+	}
 }
 
 export namespace services {
 	
+	export class AgentLintIssue {
+	    agentId: string;
+	    agentName: string;
+	    level: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentLintIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.level = source["level"];
+	        this.message = source["message"];
+	    }
+	}
 	export class LongHuBangListResult {
 	    items: models.LongHuBangItem[];
 	    total: number;
@@ -998,6 +2052,148 @@ export namespace services {
 		    return a;
 		}
 	}
+	export class AgentStats {
+	    agentName: string;
+	    messageCount: number;
+	    totalTokens: number;
+	    tokensEstimated: boolean;
+	    participationRate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentName = source["agentName"];
+	        this.messageCount = source["messageCount"];
+	        this.totalTokens = source["totalTokens"];
+	        this.tokensEstimated = source["tokensEstimated"];
+	        this.participationRate = source["participationRate"];
+	    }
+	}
+	export class SessionStats {
+	    stockCode: string;
+	    totalMessages: number;
+	    byAgent: Record<string, AgentStats>;
+	    firstMessageAt: number;
+	    lastMessageAt: number;
+	    rounds: number;
+	    averageRoundLength: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.totalMessages = source["totalMessages"];
+	        this.byAgent = this.convertValues(source["byAgent"], AgentStats, true);
+	        this.firstMessageAt = source["firstMessageAt"];
+	        this.lastMessageAt = source["lastMessageAt"];
+	        this.rounds = source["rounds"];
+	        this.averageRoundLength = source["averageRoundLength"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionSearchResult {
+	    stockCode: string;
+	    stockName: string;
+	    messageId: string;
+	    agentName: string;
+	    messageSnippet: string;
+	    timestamp: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionSearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.messageId = source["messageId"];
+	        this.agentName = source["agentName"];
+	        this.messageSnippet = source["messageSnippet"];
+	        this.timestamp = source["timestamp"];
+	    }
+	}
+	export class StockCandidate {
+	    symbol: string;
+	    name: string;
+	    industry: string;
+	    market: string;
+	    confidence: number;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockCandidate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.symbol = source["symbol"];
+	        this.name = source["name"];
+	        this.industry = source["industry"];
+	        this.market = source["market"];
+	        this.confidence = source["confidence"];
+	        this.source = source["source"];
+	    }
+	}
+	export class StockReferenceResult {
+	    input: string;
+	    candidates: StockCandidate[];
+	    autoOpen: boolean;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockReferenceResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input = source["input"];
+	        this.candidates = this.convertValues(source["candidates"], StockCandidate);
+	        this.autoOpen = source["autoOpen"];
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class StockSearchResult {
 	    symbol: string;
 	    name: string;
@@ -1016,10 +2212,62 @@ export namespace services {
 	        this.market = source["market"];
 	    }
 	}
+	export class StrategyVersion {
+	    versionId: string;
+	    createdAt: number;
+	    strategy: models.Strategy;
+	
+	    static createFrom(source: any = {}) {
+	        return new StrategyVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.versionId = source["versionId"];
+	        this.createdAt = source["createdAt"];
+	        this.strategy = this.convertValues(source["strategy"], models.Strategy);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SuggestedQuery {
+	    label: string;
+	    content: string;
+	    mentionIds: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SuggestedQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.content = source["content"];
+	        this.mentionIds = source["mentionIds"];
+	    }
+	}
 	export class Telegraph {
 	    time: string;
 	    content: string;
 	    url: string;
+	    sentiment: string;
+	    sentimentScore: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new Telegraph(source);
@@ -1030,6 +2278,8 @@ export namespace services {
 	        this.time = source["time"];
 	        this.content = source["content"];
 	        this.url = source["url"];
+	        this.sentiment = source["sentiment"];
+	        this.sentimentScore = source["sentimentScore"];
 	    }
 	}
 	export class TradingPeriod {
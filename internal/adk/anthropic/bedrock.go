@@ -0,0 +1,197 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// bedrockAnthropicVersion Bedrock 上 Claude 模型要求的 anthropic_version
+const bedrockAnthropicVersion = "bedrock-2023-05-31"
+
+// BedrockConfig AWS Bedrock 访问配置
+type BedrockConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string // 可选，覆盖默认的 https://bedrock-runtime.<region>.amazonaws.com（如 VPC 终端节点）
+}
+
+// BedrockEndpoint 返回 Bedrock Runtime 端点
+func BedrockEndpoint(cfg BedrockConfig) string {
+	if cfg.Endpoint != "" {
+		return strings.TrimRight(cfg.Endpoint, "/")
+	}
+	return "https://bedrock-runtime." + cfg.Region + ".amazonaws.com"
+}
+
+// NewBedrockModel 创建经 AWS Bedrock 调用的 Claude 模型，modelName 为 Bedrock 模型 ID 或推理配置文件 ID
+// 消息与工具转换、SSE 事件处理与直连 Anthropic 完全一致
+func NewBedrockModel(modelName string, cfg BedrockConfig, httpClient *http.Client, noSystemRole bool) *AnthropicModel {
+	m := NewAnthropicModel(modelName, "", "", httpClient, noSystemRole)
+	m.bedrock = &cfg
+	return m
+}
+
+// bedrockBody 将 Messages 请求转为 Bedrock InvokeModel 请求体
+// 模型在 URL 中指定，流式由端点决定，请求体不带 model/stream，改带 anthropic_version
+func bedrockBody(ar *MessagesRequest) ([]byte, error) {
+	data, err := json.Marshal(ar)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "model")
+	delete(fields, "stream")
+	fields["anthropic_version"] = json.RawMessage(`"` + bedrockAnthropicVersion + `"`)
+	return json.Marshal(fields)
+}
+
+// doBedrockRequest 发送 SigV4 签名的 InvokeModel 请求
+// 流式请求的响应体为 event-stream 二进制帧，转换为 SSE 文本后返回，上层按直连 API 处理
+func (m *AnthropicModel) doBedrockRequest(ctx context.Context, ar *MessagesRequest) (*http.Response, error) {
+	body, err := bedrockBody(ar)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	action := "invoke"
+	if ar.Stream {
+		action = "invoke-with-response-stream"
+	}
+	// 模型 ID 中的 ":" 等字符按 AWS 规则转义，签名时再编码一次
+	modelPath := "/model/" + awsURIEncode(m.modelName) + "/" + action
+	endpoint, err := url.Parse(BedrockEndpoint(*m.bedrock) + modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("build endpoint: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	if ar.Stream {
+		httpReq.Header.Set("Accept", "application/vnd.amazon.eventstream")
+	}
+	signV4(httpReq, body, awsCredentials{
+		AccessKeyID:     m.bedrock.AccessKeyID,
+		SecretAccessKey: m.bedrock.SecretAccessKey,
+		SessionToken:    m.bedrock.SessionToken,
+	}, m.bedrock.Region, "bedrock", time.Now())
+
+	resp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		resp.Body.Close()
+		modelLog.Error("Bedrock 响应异常: status=%d, body=%s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if ar.Stream {
+		resp.Body = eventStreamToSSE(resp.Body)
+	}
+	return resp, nil
+}
+
+// eventStreamToSSE 将 Bedrock event-stream 转为 Anthropic SSE 文本流
+// chunk 帧的 payload 为 {"bytes":"<base64 的 Anthropic 事件 JSON>"}；exception 帧转为读错误
+// 返回的 ReadCloser 关闭时同时关闭原始响应体
+func eventStreamToSSE(body io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer body.Close()
+		for {
+			msg, err := readEventStreamMessage(body)
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				pw.CloseWithError(err)
+				return
+			}
+			line, err := bedrockEventToSSE(msg)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if line == "" {
+				continue
+			}
+			if _, err := io.WriteString(pw, line); err != nil {
+				return // 读取端已关闭
+			}
+		}
+	}()
+	return &sseReadCloser{PipeReader: pr, body: body}
+}
+
+// bedrockEventToSSE 将一帧转换为 "event: ...\ndata: ...\n\n"，非数据帧返回空串
+func bedrockEventToSSE(msg *eventStreamMessage) (string, error) {
+	if msg.Headers[":message-type"] == "exception" || msg.Headers[":message-type"] == "error" {
+		kind := msg.Headers[":exception-type"]
+		if kind == "" {
+			kind = msg.Headers[":error-code"]
+		}
+		var detail struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(msg.Payload, &detail)
+		if detail.Message == "" {
+			detail.Message = string(msg.Payload)
+		}
+		return "", fmt.Errorf("Bedrock %s: %s", kind, detail.Message)
+	}
+	if msg.Headers[":event-type"] != "chunk" {
+		return "", nil
+	}
+
+	var chunk struct {
+		Bytes string `json:"bytes"`
+	}
+	if err := json.Unmarshal(msg.Payload, &chunk); err != nil {
+		return "", fmt.Errorf("解析 Bedrock chunk 失败: %w", err)
+	}
+	event, err := base64.StdEncoding.DecodeString(chunk.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("解码 Bedrock chunk 失败: %w", err)
+	}
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(event, &head); err != nil {
+		return "", fmt.Errorf("解析 Bedrock 事件失败: %w", err)
+	}
+	// SSE 的 data 必须单行
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, event); err != nil {
+		return "", fmt.Errorf("解析 Bedrock 事件失败: %w", err)
+	}
+	return "event: " + head.Type + "\ndata: " + compact.String() + "\n\n", nil
+}
+
+// sseReadCloser 转换后的 SSE 流，关闭时中断转换协程并关闭原始响应体
+type sseReadCloser struct {
+	*io.PipeReader
+	body io.Closer
+}
+
+func (r *sseReadCloser) Close() error {
+	r.PipeReader.Close()
+	return r.body.Close()
+}
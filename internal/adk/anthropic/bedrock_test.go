@@ -0,0 +1,197 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// AWS SigV4 测试套件 get-vanilla 用例
+func TestSignV4_Vanilla(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	now, _ := time.Parse("20060102T150405Z", "20150830T123600Z")
+	signV4(req, nil, awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "service", now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("Authorization = %q\nwant %q", got, want)
+	}
+}
+
+func TestCanonicalURI_DoubleEncodesModelID(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://bedrock-runtime.us-east-1.amazonaws.com/model/"+
+		awsURIEncode("anthropic.claude-sonnet-4-20250514-v1:0")+"/invoke", nil)
+	want := "/model/anthropic.claude-sonnet-4-20250514-v1%253A0/invoke"
+	if got := canonicalURI(req.URL); got != want {
+		t.Fatalf("canonicalURI = %q, want %q", got, want)
+	}
+}
+
+// encodeEventStream 按 AWS event-stream 格式编码一帧（仅字符串头）
+func encodeEventStream(headers map[string]string, payload []byte) []byte {
+	var hb bytes.Buffer
+	for name, value := range headers {
+		hb.WriteByte(byte(len(name)))
+		hb.WriteString(name)
+		hb.WriteByte(7)
+		binary.Write(&hb, binary.BigEndian, uint16(len(value)))
+		hb.WriteString(value)
+	}
+	total := eventStreamPreludeLen + hb.Len() + len(payload) + 4
+	var msg bytes.Buffer
+	binary.Write(&msg, binary.BigEndian, uint32(total))
+	binary.Write(&msg, binary.BigEndian, uint32(hb.Len()))
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(hb.Bytes())
+	msg.Write(payload)
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	return msg.Bytes()
+}
+
+// chunkFrame 将 Anthropic 事件 JSON 包装为 Bedrock chunk 帧
+func chunkFrame(event string) []byte {
+	payload, _ := json.Marshal(map[string]string{"bytes": base64.StdEncoding.EncodeToString([]byte(event))})
+	return encodeEventStream(map[string]string{
+		":message-type": "event",
+		":event-type":   "chunk",
+		":content-type": "application/json",
+	}, payload)
+}
+
+func TestReadEventStreamMessage(t *testing.T) {
+	frame := chunkFrame(`{"type":"ping"}`)
+	msg, err := readEventStreamMessage(bytes.NewReader(frame))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Headers[":event-type"] != "chunk" || !strings.Contains(string(msg.Payload), "bytes") {
+		t.Fatalf("msg = %+v", msg)
+	}
+
+	// 篡改 payload 后应校验失败
+	frame[len(frame)-6] ^= 0xff
+	if _, err := readEventStreamMessage(bytes.NewReader(frame)); err == nil {
+		t.Fatal("CRC 不匹配应返回错误")
+	}
+	if _, err := readEventStreamMessage(bytes.NewReader(nil)); err != io.EOF {
+		t.Fatalf("空流应返回 io.EOF, got %v", err)
+	}
+}
+
+func TestBedrock_Streaming(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"model":"claude","usage":{"input_tokens":5,"output_tokens":0}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"你好"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"，世界"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":4}}`,
+		`{"type":"message_stop"}`,
+	}
+
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		for _, ev := range events {
+			w.Write(chunkFrame(ev))
+		}
+	}))
+	defer srv.Close()
+
+	m := NewBedrockModel("anthropic.claude-sonnet-4-20250514-v1:0", BedrockConfig{
+		Region:          "us-east-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		Endpoint:        srv.URL,
+	}, srv.Client(), false)
+
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}},
+		Config:   &genai.GenerateContentConfig{MaxOutputTokens: 64},
+	}
+	var partial strings.Builder
+	var final *model.LLMResponse
+	for resp, err := range m.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Partial {
+			for _, p := range resp.Content.Parts {
+				partial.WriteString(p.Text)
+			}
+			continue
+		}
+		final = resp
+	}
+
+	if partial.String() != "你好，世界" {
+		t.Fatalf("partial text = %q", partial.String())
+	}
+	if final == nil || len(final.Content.Parts) == 0 || final.Content.Parts[0].Text != "你好，世界" {
+		t.Fatalf("final = %+v", final)
+	}
+	if gotPath != "/model/anthropic.claude-sonnet-4-20250514-v1%3A0/invoke-with-response-stream" {
+		t.Fatalf("path = %q", gotPath)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(gotAuth, "/us-east-1/bedrock/aws4_request") {
+		t.Fatalf("Authorization = %q", gotAuth)
+	}
+	if gotBody["anthropic_version"] != bedrockAnthropicVersion {
+		t.Fatalf("anthropic_version = %v", gotBody["anthropic_version"])
+	}
+	if _, ok := gotBody["model"]; ok {
+		t.Fatal("请求体不应包含 model")
+	}
+	if _, ok := gotBody["stream"]; ok {
+		t.Fatal("请求体不应包含 stream")
+	}
+}
+
+func TestBedrock_StreamException(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(chunkFrame(`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"model":"claude","usage":{"input_tokens":5,"output_tokens":0}}}`))
+		w.Write(encodeEventStream(map[string]string{
+			":message-type":   "exception",
+			":exception-type": "throttlingException",
+		}, []byte(`{"message":"Too many requests"}`)))
+	}))
+	defer srv.Close()
+
+	m := NewBedrockModel("anthropic.claude-3-haiku-20240307-v1:0", BedrockConfig{
+		Region: "us-west-2", AccessKeyID: "AKID", SecretAccessKey: "secret", Endpoint: srv.URL,
+	}, srv.Client(), false)
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}},
+	}
+	var gotErr error
+	for _, err := range m.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+	}
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "throttlingException") {
+		t.Fatalf("err = %v, want throttlingException", gotErr)
+	}
+}
@@ -1,8 +1,10 @@
 package anthropic
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/run-bigpig/jcp/internal/logger"
@@ -40,8 +42,39 @@ func extractTextFromContent(content *genai.Content) string {
 	return strings.Join(texts, "\n")
 }
 
+// MinThinkingBudget Anthropic 要求的最小思考预算
+const MinThinkingBudget = 1024
+
+// redactedThinkingPrefix redacted_thinking 块在 genai.Part.ThoughtSignature 中的前缀
+// 加密内容与签名共用该字段回传，用前缀区分两种块
+const redactedThinkingPrefix = "redacted_thinking:"
+
+// thinkingModelRe 支持扩展思考的模型：Claude 3.7 Sonnet 及 Claude 4 之后的 Opus/Sonnet/Haiku
+var thinkingModelRe = regexp.MustCompile(`claude-(?:3-7-sonnet|(?:opus|sonnet|haiku)-[4-9])`)
+
+// SupportsExtendedThinking 模型是否支持扩展思考
+func SupportsExtendedThinking(modelName string) bool {
+	return thinkingModelRe.MatchString(strings.ToLower(modelName))
+}
+
+// applyThinking 启用扩展思考
+// 约束：budget_tokens >= 1024 且小于 max_tokens；开启后 temperature/top_p 不可自定义
+func applyThinking(ar *MessagesRequest, budget int) {
+	if budget < MinThinkingBudget {
+		budget = MinThinkingBudget
+	}
+	if ar.MaxTokens <= budget {
+		// 预算不挤占正文输出
+		ar.MaxTokens += budget
+	}
+	ar.Thinking = &ThinkingConfig{Type: "enabled", BudgetTokens: budget}
+	ar.Temperature = nil
+	ar.TopP = nil
+}
+
 // toAnthropicRequest 将 ADK LLMRequest 转换为 Anthropic Messages 请求
-func toAnthropicRequest(req *model.LLMRequest, modelName string, noSystemRole bool) (*MessagesRequest, error) {
+// thinkingBudget > 0 且模型支持时开启扩展思考
+func toAnthropicRequest(req *model.LLMRequest, modelName string, noSystemRole bool, thinkingBudget int, promptCache bool) (*MessagesRequest, error) {
 	ar := &MessagesRequest{
 		Model:     modelName,
 		MaxTokens: 4096, // Anthropic 要求必须设置
@@ -106,9 +139,33 @@ func toAnthropicRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 		}
 	}
 
+	if promptCache {
+		applyPromptCache(ar)
+	}
+
+	if thinkingBudget > 0 {
+		if SupportsExtendedThinking(modelName) {
+			applyThinking(ar, thinkingBudget)
+		} else {
+			convertLog.Debug("模型 %s 不支持扩展思考，忽略 thinking budget", modelName)
+		}
+	}
+
 	return ar, nil
 }
 
+// applyPromptCache 在 system 与最后一个工具定义上打缓存断点
+// 缓存前缀按 tools → system 顺序计算，两处断点可让每轮发言复用专家指令与工具定义
+func applyPromptCache(ar *MessagesRequest) {
+	ephemeral := &CacheControl{Type: "ephemeral"}
+	if ar.System != "" {
+		ar.SystemCache = ephemeral
+	}
+	if n := len(ar.Tools); n > 0 {
+		ar.Tools[n-1].CacheControl = ephemeral
+	}
+}
+
 // toAnthropicMessages 将 genai.Content 列表转换为 Anthropic messages
 func toAnthropicMessages(contents []*genai.Content) ([]Message, error) {
 	var msgs []Message
@@ -126,8 +183,13 @@ func toAnthropicMessages(contents []*genai.Content) ([]Message, error) {
 		var blocks []ContentBlock
 
 		for _, part := range content.Parts {
-			// 跳过 thought parts（不回传给 API）
+			// thought parts：带签名的原样回传（工具调用多轮时 API 要求），无签名的丢弃
 			if part.Thought {
+				if role == "assistant" {
+					if block, ok := thinkingBlockFromPart(part); ok {
+						blocks = append(blocks, block)
+					}
+				}
 				continue
 			}
 
@@ -139,6 +201,15 @@ func toAnthropicMessages(contents []*genai.Content) ([]Message, error) {
 				})
 			}
 
+			// 内联图片 → image
+			if part.InlineData != nil {
+				block, err := imageBlockFromPart(part)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, block)
+			}
+
 			// 函数调用 → tool_use
 			if part.FunctionCall != nil {
 				inputJSON, err := json.Marshal(part.FunctionCall.Args)
@@ -182,6 +253,50 @@ func toAnthropicMessages(contents []*genai.Content) ([]Message, error) {
 	return msgs, nil
 }
 
+// imageBlockFromPart 内联数据转为 base64 图片块，仅支持 Anthropic 接受的图片格式
+func imageBlockFromPart(part *genai.Part) (ContentBlock, error) {
+	mime := part.InlineData.MIMEType
+	switch mime {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return ContentBlock{}, fmt.Errorf("不支持的图片格式: %s", mime)
+	}
+	return ContentBlock{
+		Type: "image",
+		Source: &ImageSource{
+			Type:      "base64",
+			MediaType: mime,
+			Data:      base64.StdEncoding.EncodeToString(part.InlineData.Data),
+		},
+	}, nil
+}
+
+// thinkingBlockFromPart 由带签名的 thought part 还原 thinking / redacted_thinking 块
+func thinkingBlockFromPart(part *genai.Part) (ContentBlock, bool) {
+	sig := string(part.ThoughtSignature)
+	if sig == "" {
+		return ContentBlock{}, false
+	}
+	if data, ok := strings.CutPrefix(sig, redactedThinkingPrefix); ok {
+		return ContentBlock{Type: "redacted_thinking", Data: data}, true
+	}
+	return ContentBlock{Type: "thinking", Thinking: part.Text, Signature: sig}, true
+}
+
+// thinkingPart 构造 thought part，签名写入 ThoughtSignature 以便回传
+func thinkingPart(thinking, signature string) *genai.Part {
+	part := &genai.Part{Text: thinking, Thought: true}
+	if signature != "" {
+		part.ThoughtSignature = []byte(signature)
+	}
+	return part
+}
+
+// redactedThinkingPart 构造 redacted_thinking 对应的 thought part（无可见文本）
+func redactedThinkingPart(data string) *genai.Part {
+	return &genai.Part{Thought: true, ThoughtSignature: []byte(redactedThinkingPrefix + data)}
+}
+
 // convertTools 将 genai.Tool 转换为 Anthropic Tool
 func convertTools(genaiTools []*genai.Tool) ([]Tool, error) {
 	var tools []Tool
@@ -292,8 +407,12 @@ func convertAnthropicResponse(resp *MessagesResponse) (*model.LLMResponse, error
 				content.Parts = append(content.Parts, &genai.Part{Text: block.Text})
 			}
 		case "thinking":
-			if block.Thinking != "" {
-				content.Parts = append(content.Parts, &genai.Part{Text: block.Thinking, Thought: true})
+			if block.Thinking != "" || block.Signature != "" {
+				content.Parts = append(content.Parts, thinkingPart(block.Thinking, block.Signature))
+			}
+		case "redacted_thinking":
+			if block.Data != "" {
+				content.Parts = append(content.Parts, redactedThinkingPart(block.Data))
 			}
 		case "tool_use":
 			args := make(map[string]any)
@@ -325,10 +444,13 @@ func convertUsage(u *Usage) *genai.GenerateContentResponseUsageMetadata {
 	if u == nil {
 		return nil
 	}
+	// Anthropic 的 input_tokens 不含缓存部分，合并后与其他 Provider 口径一致
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
 	return &genai.GenerateContentResponseUsageMetadata{
-		PromptTokenCount:     int32(u.InputTokens),
-		CandidatesTokenCount: int32(u.OutputTokens),
-		TotalTokenCount:      int32(u.InputTokens + u.OutputTokens),
+		PromptTokenCount:        int32(prompt),
+		CachedContentTokenCount: int32(u.CacheReadInputTokens),
+		CandidatesTokenCount:    int32(u.OutputTokens),
+		TotalTokenCount:         int32(prompt + u.OutputTokens),
 	}
 }
 
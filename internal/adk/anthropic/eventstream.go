@@ -0,0 +1,106 @@
+package anthropic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// AWS event-stream 二进制帧（Bedrock 流式响应使用）：
+// total_len(4) | headers_len(4) | prelude_crc(4) | headers | payload | message_crc(4)
+const (
+	eventStreamPreludeLen = 12
+	eventStreamMaxMessage = 16 * 1024 * 1024
+)
+
+// eventStreamMessage 解码后的一帧
+type eventStreamMessage struct {
+	Headers map[string]string // 仅保留字符串类型的头（:event-type、:message-type 等）
+	Payload []byte
+}
+
+// readEventStreamMessage 读取一帧，流正常结束时返回 io.EOF
+func readEventStreamMessage(r io.Reader) (*eventStreamMessage, error) {
+	prelude := make([]byte, eventStreamPreludeLen)
+	if _, err := io.ReadFull(r, prelude); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("event-stream 帧头不完整: %w", err)
+		}
+		return nil, err
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, errors.New("event-stream 帧头校验失败")
+	}
+	if totalLen > eventStreamMaxMessage || totalLen < eventStreamPreludeLen+4+headersLen {
+		return nil, fmt.Errorf("event-stream 帧长度异常: total=%d headers=%d", totalLen, headersLen)
+	}
+
+	rest := make([]byte, totalLen-eventStreamPreludeLen)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("event-stream 帧不完整: %w", err)
+	}
+	body, msgCRC := rest[:len(rest)-4], binary.BigEndian.Uint32(rest[len(rest)-4:])
+	crc := crc32.Update(crc32.ChecksumIEEE(prelude), crc32.IEEETable, body)
+	if crc != msgCRC {
+		return nil, errors.New("event-stream 帧校验失败")
+	}
+
+	headers, err := parseEventStreamHeaders(body[:headersLen])
+	if err != nil {
+		return nil, err
+	}
+	return &eventStreamMessage{Headers: headers, Payload: body[headersLen:]}, nil
+}
+
+// parseEventStreamHeaders 解析帧头：name_len(1) | name | type(1) | value
+func parseEventStreamHeaders(b []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(b) > 0 {
+		nameLen := int(b[0])
+		if len(b) < 1+nameLen+1 {
+			return nil, errors.New("event-stream 头部截断")
+		}
+		name := string(b[1 : 1+nameLen])
+		typ := b[1+nameLen]
+		b = b[2+nameLen:]
+
+		var size int
+		switch typ {
+		case 0, 1: // bool true/false，无值
+		case 2: // byte
+			size = 1
+		case 3: // int16
+			size = 2
+		case 4: // int32
+			size = 4
+		case 5, 8: // int64、timestamp
+			size = 8
+		case 9: // uuid
+			size = 16
+		case 6, 7: // bytes、string：2 字节长度前缀
+			if len(b) < 2 {
+				return nil, errors.New("event-stream 头部截断")
+			}
+			n := int(binary.BigEndian.Uint16(b))
+			if len(b) < 2+n {
+				return nil, errors.New("event-stream 头部截断")
+			}
+			if typ == 7 {
+				headers[name] = string(b[2 : 2+n])
+			}
+			b = b[2+n:]
+			continue
+		default:
+			return nil, fmt.Errorf("event-stream 未知头部类型: %d", typ)
+		}
+		if len(b) < size {
+			return nil, errors.New("event-stream 头部截断")
+		}
+		b = b[size:]
+	}
+	return headers, nil
+}
@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/pkg/httpbody"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 )
@@ -31,6 +32,12 @@ type AnthropicModel struct {
 	apiKey       string
 	modelName    string
 	noSystemRole bool
+	// thinkingBudget 扩展思考预算（0 表示关闭）
+	thinkingBudget int
+	// promptCache 是否为 system 与工具定义开启提示缓存
+	promptCache bool
+	// bedrock 非 nil 时经 AWS Bedrock 调用（SigV4 签名）
+	bedrock *BedrockConfig
 }
 
 func normalizeBaseURL(baseURL string) string {
@@ -50,6 +57,16 @@ func NewAnthropicModel(modelName, apiKey, baseURL string, httpClient *http.Clien
 	}
 }
 
+// SetThinkingBudget 设置扩展思考预算，仅对支持的模型生效
+func (m *AnthropicModel) SetThinkingBudget(budget int) {
+	m.thinkingBudget = budget
+}
+
+// SetPromptCache 设置是否开启提示缓存
+func (m *AnthropicModel) SetPromptCache(enabled bool) {
+	m.promptCache = enabled
+}
+
 // Name 返回模型名称
 func (m *AnthropicModel) Name() string {
 	return m.modelName
@@ -65,6 +82,10 @@ func (m *AnthropicModel) GenerateContent(ctx context.Context, req *model.LLMRequ
 
 // doRequest 发送 HTTP 请求到 Anthropic API
 func (m *AnthropicModel) doRequest(ctx context.Context, ar *MessagesRequest) (*http.Response, error) {
+	if m.bedrock != nil {
+		return m.doBedrockRequest(ctx, ar)
+	}
+
 	jsonBody, err := json.Marshal(ar)
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
@@ -103,7 +124,7 @@ func (m *AnthropicModel) doRequest(ctx context.Context, ar *MessagesRequest) (*h
 // generate 非流式生成
 func (m *AnthropicModel) generate(ctx context.Context, req *model.LLMRequest) iter.Seq2[*model.LLMResponse, error] {
 	return func(yield func(*model.LLMResponse, error) bool) {
-		ar, err := toAnthropicRequest(req, m.modelName, m.noSystemRole)
+		ar, err := toAnthropicRequest(req, m.modelName, m.noSystemRole, m.thinkingBudget, m.promptCache)
 		if err != nil {
 			yield(nil, err)
 			return
@@ -142,7 +163,7 @@ func (m *AnthropicModel) generate(ctx context.Context, req *model.LLMRequest) it
 // generateStream 流式生成
 func (m *AnthropicModel) generateStream(ctx context.Context, req *model.LLMRequest) iter.Seq2[*model.LLMResponse, error] {
 	return func(yield func(*model.LLMResponse, error) bool) {
-		ar, err := toAnthropicRequest(req, m.modelName, m.noSystemRole)
+		ar, err := toAnthropicRequest(req, m.modelName, m.noSystemRole, m.thinkingBudget, m.promptCache)
 		if err != nil {
 			yield(nil, err)
 			return
@@ -156,17 +177,23 @@ func (m *AnthropicModel) generateStream(ctx context.Context, req *model.LLMReque
 		}
 		defer resp.Body.Close()
 
-		m.processStream(resp.Body, yield)
+		// 取消时立即断开连接，避免服务端继续生成并计费
+		body, stop := httpbody.CloseOnCancel(ctx, resp.Body)
+		defer stop()
+
+		m.processStream(body, yield)
 	}
 }
 
 // blockState 跟踪流式内容块状态
 type blockState struct {
-	blockType string // text / tool_use / thinking
+	blockType string // text / tool_use / thinking / redacted_thinking
 	toolID    string
 	toolName  string
 	text      string
 	thinking  string
+	signature string
+	data      string // redacted_thinking 加密内容
 	toolArgs  string
 }
 
@@ -243,9 +270,12 @@ func (m *AnthropicModel) handleSSEEvent(
 			return nil
 		}
 		bs := &blockState{blockType: ev.ContentBlock.Type}
-		if ev.ContentBlock.Type == "tool_use" {
+		switch ev.ContentBlock.Type {
+		case "tool_use":
 			bs.toolID = ev.ContentBlock.ID
 			bs.toolName = ev.ContentBlock.Name
+		case "redacted_thinking":
+			bs.data = ev.ContentBlock.Data
 		}
 		blocks[ev.Index] = bs
 
@@ -262,7 +292,7 @@ func (m *AnthropicModel) handleSSEEvent(
 		}
 		*stopReason = ev.Delta.StopReason
 		if ev.Usage != nil {
-			*usage = ev.Usage
+			*usage = mergeUsage(*usage, ev.Usage)
 		}
 
 	case "message_stop":
@@ -322,6 +352,9 @@ func (m *AnthropicModel) handleDelta(
 			return errStopIteration
 		}
 
+	case "signature_delta":
+		bs.signature += ev.Delta.Signature
+
 	case "input_json_delta":
 		bs.toolArgs += ev.Delta.PartialJSON
 	}
@@ -347,10 +380,12 @@ func (m *AnthropicModel) emitFinalResponse(
 
 		switch bs.blockType {
 		case "thinking":
-			if bs.thinking != "" {
-				aggregated.Parts = append(aggregated.Parts, &genai.Part{
-					Text: bs.thinking, Thought: true,
-				})
+			if bs.thinking != "" || bs.signature != "" {
+				aggregated.Parts = append(aggregated.Parts, thinkingPart(bs.thinking, bs.signature))
+			}
+		case "redacted_thinking":
+			if bs.data != "" {
+				aggregated.Parts = append(aggregated.Parts, redactedThinkingPart(bs.data))
 			}
 		case "text":
 			if bs.text != "" {
@@ -384,3 +419,23 @@ func (m *AnthropicModel) emitFinalResponse(
 	}
 	yield(finalResp, nil)
 }
+
+// mergeUsage 合并 message_delta 的用量
+// message_delta 通常只带累计 output_tokens，输入与缓存 token 以 message_start 为准
+func mergeUsage(start, delta *Usage) *Usage {
+	if start == nil {
+		return delta
+	}
+	merged := *start
+	merged.OutputTokens = delta.OutputTokens
+	if delta.InputTokens > 0 {
+		merged.InputTokens = delta.InputTokens
+	}
+	if delta.CacheCreationInputTokens > 0 {
+		merged.CacheCreationInputTokens = delta.CacheCreationInputTokens
+	}
+	if delta.CacheReadInputTokens > 0 {
+		merged.CacheReadInputTokens = delta.CacheReadInputTokens
+	}
+	return &merged
+}
@@ -3,10 +3,13 @@ package anthropic
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
@@ -27,7 +30,7 @@ func TestToAnthropicRequest_Basic(t *testing.T) {
 		},
 	}
 
-	ar, err := toAnthropicRequest(req, "claude-opus-4-6", false)
+	ar, err := toAnthropicRequest(req, "claude-opus-4-6", false, 0, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -313,3 +316,273 @@ func TestIntegration_Streaming(t *testing.T) {
 		t.Error("never received TurnComplete response")
 	}
 }
+
+func TestToAnthropicRequest_Thinking(t *testing.T) {
+	temp := float32(0.7)
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}},
+		Config:   &genai.GenerateContentConfig{MaxOutputTokens: 2048, Temperature: &temp},
+	}
+
+	ar, err := toAnthropicRequest(req, "claude-sonnet-4-5", false, 4000, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ar.Thinking == nil || ar.Thinking.Type != "enabled" || ar.Thinking.BudgetTokens != 4000 {
+		t.Fatalf("thinking = %+v", ar.Thinking)
+	}
+	// max_tokens 必须大于 budget，temperature 不可自定义
+	if ar.MaxTokens != 6048 || ar.Temperature != nil {
+		t.Errorf("max_tokens = %d, temperature = %v", ar.MaxTokens, ar.Temperature)
+	}
+	body, _ := json.Marshal(ar)
+	if !strings.Contains(string(body), `"thinking":{"type":"enabled","budget_tokens":4000}`) {
+		t.Errorf("request body = %s", body)
+	}
+
+	// 预算下限
+	ar, _ = toAnthropicRequest(req, "claude-3-7-sonnet-20250219", false, 100, false)
+	if ar.Thinking == nil || ar.Thinking.BudgetTokens != MinThinkingBudget {
+		t.Errorf("thinking = %+v, want budget %d", ar.Thinking, MinThinkingBudget)
+	}
+
+	// 不支持的模型忽略预算
+	ar, _ = toAnthropicRequest(req, "claude-3-5-haiku-latest", false, 4000, false)
+	if ar.Thinking != nil || ar.Temperature == nil {
+		t.Errorf("unsupported model should not enable thinking: %+v", ar.Thinking)
+	}
+}
+
+func TestThinkingBlock_RoundTrip(t *testing.T) {
+	resp := &MessagesResponse{
+		StopReason: "tool_use",
+		Content: []ContentBlock{
+			{Type: "thinking", Thinking: "需要先查行情", Signature: "sig-abc"},
+			{Type: "redacted_thinking", Data: "enc-xyz"},
+			{Type: "tool_use", ID: "tu_1", Name: "get_quote", Input: json.RawMessage(`{"code":"sh600519"}`)},
+		},
+	}
+	llmResp, err := convertAnthropicResponse(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parts := llmResp.Content.Parts
+	if len(parts) != 3 || !parts[0].Thought || parts[0].Text != "需要先查行情" || string(parts[0].ThoughtSignature) != "sig-abc" {
+		t.Fatalf("parts = %+v", parts)
+	}
+
+	// 将模型回复与工具结果回传，thinking 块需携带原签名
+	contents := []*genai.Content{
+		{Role: "user", Parts: []*genai.Part{{Text: "茅台怎么样"}}},
+		llmResp.Content,
+		{Role: "user", Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{ID: "tu_1", Response: map[string]any{"price": 1500}}}}},
+	}
+	msgs, err := toAnthropicMessages(contents)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	blocks := msgs[1].Content
+	if len(blocks) != 3 {
+		t.Fatalf("assistant blocks = %+v", blocks)
+	}
+	if blocks[0].Type != "thinking" || blocks[0].Thinking != "需要先查行情" || blocks[0].Signature != "sig-abc" {
+		t.Errorf("thinking block = %+v", blocks[0])
+	}
+	if blocks[1].Type != "redacted_thinking" || blocks[1].Data != "enc-xyz" {
+		t.Errorf("redacted block = %+v", blocks[1])
+	}
+	body, _ := json.Marshal(blocks[0])
+	if string(body) != `{"type":"thinking","thinking":"需要先查行情","signature":"sig-abc"}` {
+		t.Errorf("thinking json = %s", body)
+	}
+
+	// 无签名的思考内容（如其他来源）不回传
+	msgs, _ = toAnthropicMessages([]*genai.Content{{Role: "model", Parts: []*genai.Part{{Text: "想法", Thought: true}, {Text: "结论"}}}})
+	if len(msgs[0].Content) != 1 || msgs[0].Content[0].Type != "text" {
+		t.Errorf("unsigned thought should be dropped: %+v", msgs[0].Content)
+	}
+}
+
+func TestProcessStream_ThinkingSignature(t *testing.T) {
+	stream := strings.Join([]string{
+		"event: content_block_start",
+		`data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`,
+		"event: content_block_delta",
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"分析"}}`,
+		"event: content_block_delta",
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig-1"}}`,
+		"event: content_block_start",
+		`data: {"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+		"event: content_block_delta",
+		`data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"结论"}}`,
+		"event: message_stop",
+		`data: {"type":"message_stop"}`,
+	}, "\n")
+
+	var final *model.LLMResponse
+	m := NewAnthropicModel("claude-sonnet-4-5", "", "", http.DefaultClient, false)
+	m.processStream(strings.NewReader(stream), func(r *model.LLMResponse, err error) bool {
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		if r.TurnComplete {
+			final = r
+		}
+		return true
+	})
+	if final == nil || len(final.Content.Parts) != 2 {
+		t.Fatalf("final = %+v", final)
+	}
+	if p := final.Content.Parts[0]; !p.Thought || p.Text != "分析" || string(p.ThoughtSignature) != "sig-1" {
+		t.Errorf("thinking part = %+v", p)
+	}
+}
+
+func TestToAnthropicRequest_PromptCache(t *testing.T) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "你是一位宏观分析师"}}},
+			Tools: []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{
+				{Name: "get_news", ParametersJsonSchema: map[string]any{"type": "object"}},
+				{Name: "get_kline_data", ParametersJsonSchema: map[string]any{"type": "object"}},
+			}}},
+		},
+	}
+
+	ar, _ := toAnthropicRequest(req, "claude-sonnet-4-5", false, 0, false)
+	data, _ := json.Marshal(ar)
+	if strings.Contains(string(data), "cache_control") || !strings.Contains(string(data), `"system":"你是一位宏观分析师"`) {
+		t.Errorf("cache disabled request = %s", data)
+	}
+
+	ar, _ = toAnthropicRequest(req, "claude-sonnet-4-5", false, 0, true)
+	data, _ = json.Marshal(ar)
+	var body struct {
+		System []SystemBlock `json:"system"`
+		Tools  []Tool        `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+	if len(body.System) != 1 || body.System[0].Text != "你是一位宏观分析师" || body.System[0].CacheControl == nil || body.System[0].CacheControl.Type != "ephemeral" {
+		t.Errorf("system = %+v", body.System)
+	}
+	if len(body.Tools) != 2 || body.Tools[0].CacheControl != nil || body.Tools[1].CacheControl == nil {
+		t.Errorf("tools = %+v", body.Tools)
+	}
+}
+
+func TestProcessStream_CacheUsage(t *testing.T) {
+	stream := strings.Join([]string{
+		"event: message_start",
+		`data: {"type":"message_start","message":{"usage":{"input_tokens":20,"output_tokens":1,"cache_creation_input_tokens":0,"cache_read_input_tokens":1800}}}`,
+		"event: content_block_start",
+		`data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		"event: content_block_delta",
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"结论"}}`,
+		"event: message_delta",
+		`data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":50}}`,
+		"event: message_stop",
+		`data: {"type":"message_stop"}`,
+	}, "\n")
+
+	var final *model.LLMResponse
+	m := NewAnthropicModel("claude-sonnet-4-5", "", "", http.DefaultClient, false)
+	m.processStream(strings.NewReader(stream), func(r *model.LLMResponse, err error) bool {
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		if r.TurnComplete {
+			final = r
+		}
+		return true
+	})
+	if final == nil || final.UsageMetadata == nil {
+		t.Fatalf("final = %+v", final)
+	}
+	u := final.UsageMetadata
+	if u.PromptTokenCount != 1820 || u.CachedContentTokenCount != 1800 || u.CandidatesTokenCount != 50 || u.TotalTokenCount != 1870 {
+		t.Errorf("usage = %+v", u)
+	}
+}
+
+// detachedTransport 丢弃请求 ctx，模拟不遵守取消的 Transport
+type detachedTransport struct{}
+
+func (detachedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return http.DefaultTransport.RoundTrip(req.WithContext(context.Background()))
+}
+
+func TestGenerateStream_CancelClosesConnection(t *testing.T) {
+	closed := make(chan time.Time, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: content_block_start\n")
+		fmt.Fprint(w, `data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`+"\n\n")
+		fmt.Fprint(w, "event: content_block_delta\n")
+		fmt.Fprint(w, `data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"先看"}}`+"\n\n")
+		w.(http.Flusher).Flush()
+		// 模拟仍在生成的慢速模型，直到客户端断开
+		select {
+		case <-r.Context().Done():
+			closed <- time.Now()
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	m := NewAnthropicModel("claude-sonnet-4-5", "key", srv.URL, &http.Client{Transport: detachedTransport{}}, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := &model.LLMRequest{Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "茅台怎么看"}}}}}
+	var cancelledAt time.Time
+	for resp, err := range m.GenerateContent(ctx, req, true) {
+		if err != nil {
+			t.Fatalf("取消不应返回错误: %v", err)
+		}
+		if resp.Partial && cancelledAt.IsZero() {
+			cancelledAt = time.Now()
+			cancel()
+		}
+	}
+	if cancelledAt.IsZero() {
+		t.Fatal("未收到流式分片")
+	}
+
+	select {
+	case at := <-closed:
+		if d := at.Sub(cancelledAt); d > time.Second {
+			t.Errorf("连接在取消 %v 后才关闭", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("取消 1 秒后连接仍未关闭")
+	}
+}
+
+func TestToAnthropicMessages_Image(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	msgs, err := toAnthropicMessages([]*genai.Content{{Role: "user", Parts: []*genai.Part{
+		{Text: "看看这张K线图"},
+		genai.NewPartFromBytes(png, "image/png"),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || len(msgs[0].Content) != 2 {
+		t.Fatalf("msgs = %+v", msgs)
+	}
+	data, _ := json.Marshal(msgs[0].Content[1])
+	want := `{"type":"image","source":{"type":"base64","media_type":"image/png","data":"iVBORw0KGgo="}}`
+	if string(data) != want {
+		t.Fatalf("image block = %s, want %s", data, want)
+	}
+
+	_, err = toAnthropicMessages([]*genai.Content{{Role: "user", Parts: []*genai.Part{
+		genai.NewPartFromBytes([]byte("%PDF"), "application/pdf"),
+	}}})
+	if err == nil {
+		t.Fatal("非图片附件应返回错误")
+	}
+}
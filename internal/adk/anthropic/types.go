@@ -13,6 +13,40 @@ type MessagesRequest struct {
 	Stream      bool      `json:"stream,omitempty"`
 	Tools       []Tool    `json:"tools,omitempty"`
 	StopSequences []string `json:"stop_sequences,omitempty"`
+	Thinking    *ThinkingConfig `json:"thinking,omitempty"`
+
+	// SystemCache 非空时 system 以文本块数组输出并附带缓存标记
+	SystemCache *CacheControl `json:"-"`
+}
+
+// CacheControl 提示缓存标记
+type CacheControl struct {
+	Type string `json:"type"` // ephemeral
+}
+
+// SystemBlock system 文本块（需要附带 cache_control 时使用）
+type SystemBlock struct {
+	Type         string        `json:"type"` // text
+	Text         string        `json:"text"`
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// MarshalJSON 开启缓存时 system 只能用文本块数组表达，否则保持字符串形式
+func (r MessagesRequest) MarshalJSON() ([]byte, error) {
+	type Alias MessagesRequest
+	if r.SystemCache == nil || r.System == "" {
+		return json.Marshal(Alias(r))
+	}
+	return json.Marshal(struct {
+		Alias
+		System []SystemBlock `json:"system"`
+	}{Alias(r), []SystemBlock{{Type: "text", Text: r.System, CacheControl: r.SystemCache}}})
+}
+
+// ThinkingConfig 扩展思考配置
+type ThinkingConfig struct {
+	Type         string `json:"type"` // enabled
+	BudgetTokens int    `json:"budget_tokens"`
 }
 
 // Message 消息
@@ -24,13 +58,20 @@ type Message struct {
 // ContentBlock 内容块（多态）
 // 使用自定义 MarshalJSON 按 Type 输出不同字段，避免序列化冲突
 type ContentBlock struct {
-	Type string `json:"type"` // text / image / tool_use / tool_result / thinking
+	Type string `json:"type"` // text / image / tool_use / tool_result / thinking / redacted_thinking
 
 	// text
 	Text string `json:"text,omitempty"`
 
-	// thinking
-	Thinking string `json:"thinking,omitempty"`
+	// image
+	Source *ImageSource `json:"source,omitempty"`
+
+	// thinking（回传时必须带原签名，否则多轮工具调用会被拒绝）
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+
+	// redacted_thinking
+	Data string `json:"data,omitempty"`
 
 	// tool_use
 	ID    string          `json:"id,omitempty"`
@@ -51,11 +92,22 @@ func (b ContentBlock) MarshalJSON() ([]byte, error) {
 			Type string `json:"type"`
 			Text string `json:"text"`
 		}{b.Type, b.Text})
+	case "image":
+		return json.Marshal(struct {
+			Type   string       `json:"type"`
+			Source *ImageSource `json:"source"`
+		}{b.Type, b.Source})
 	case "thinking":
 		return json.Marshal(struct {
-			Type     string `json:"type"`
-			Thinking string `json:"thinking"`
-		}{b.Type, b.Thinking})
+			Type      string `json:"type"`
+			Thinking  string `json:"thinking"`
+			Signature string `json:"signature,omitempty"`
+		}{b.Type, b.Thinking, b.Signature})
+	case "redacted_thinking":
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			Data string `json:"data"`
+		}{b.Type, b.Data})
 	case "tool_use":
 		return json.Marshal(struct {
 			Type  string          `json:"type"`
@@ -77,11 +129,19 @@ func (b ContentBlock) MarshalJSON() ([]byte, error) {
 	}
 }
 
+// ImageSource 图片内容来源（base64 内联）
+type ImageSource struct {
+	Type      string `json:"type"`       // base64
+	MediaType string `json:"media_type"` // image/jpeg、image/png、image/gif、image/webp
+	Data      string `json:"data"`
+}
+
 // Tool 工具定义
 type Tool struct {
-	Name        string          `json:"name"`
-	Description string          `json:"description,omitempty"`
-	InputSchema json.RawMessage `json:"input_schema"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description,omitempty"`
+	InputSchema  json.RawMessage `json:"input_schema"`
+	CacheControl *CacheControl   `json:"cache_control,omitempty"`
 }
 
 // ---- 响应类型 ----
@@ -102,6 +162,9 @@ type MessagesResponse struct {
 type Usage struct {
 	InputTokens  int `json:"input_tokens"`
 	OutputTokens int `json:"output_tokens"`
+	// 提示缓存：写入缓存与命中缓存的 token，不计入 InputTokens
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
 }
 
 // ---- SSE 事件类型 ----
@@ -128,9 +191,10 @@ type SSEContentBlockDelta struct {
 
 // Delta 增量内容
 type Delta struct {
-	Type     string          `json:"type"` // text_delta / input_json_delta / thinking_delta
+	Type     string          `json:"type"` // text_delta / input_json_delta / thinking_delta / signature_delta
 	Text     string          `json:"text,omitempty"`
 	Thinking string          `json:"thinking,omitempty"`
+	Signature string         `json:"signature,omitempty"`
 	PartialJSON string       `json:"partial_json,omitempty"`
 }
 
@@ -0,0 +1,173 @@
+package adk
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/tool"
+)
+
+// redactedValue 字符串中敏感数字脱敏后的占位符
+// 数值类型的参数不能替换为字符串（会违反工具的参数 schema），脱敏时直接去掉该字段
+const redactedValue = "[REDACTED]"
+
+var (
+	// sensitiveKeyPattern 内置敏感字段名：成本价、持仓数量、账户
+	// position/holding 只匹配表示持仓数量的组合，避免误伤分页位置、持有人等普通参数
+	sensitiveKeyPattern = regexp.MustCompile(`(?i)cost_?price|avg_?cost|^cost$|shares|^holdings?$|(position|holding)_?(qty|quantity|size|shares|amount|cost)|account|成本|持仓|持股|股数|仓位|账户|账号`)
+	// accountLikePattern 类似账号的数字：银行卡号、沪市 A 股账户、深市账户
+	accountLikePattern = regexp.MustCompile(`\b(?:\d{16,19}|[A-Z]\d{9}|0\d{9})\b`)
+	// numberPattern 字符串中的数字
+	numberPattern = regexp.MustCompile(`\d+(?:\.\d+)?`)
+)
+
+// argGuard 外部工具调用参数过滤：找出与持仓相关的数字，按策略脱敏或拒绝调用
+type argGuard struct {
+	policy models.ToolArgPolicy
+	keys   []*regexp.Regexp
+	values []float64 // 当前持仓的成本价、持仓数量、持仓成本
+}
+
+// newArgGuard 根据配置与当前持仓创建参数过滤器，策略为 off 时返回 nil
+func newArgGuard(cfg models.ToolArgGuard, position *models.StockPosition) *argGuard {
+	if cfg.Policy == models.ToolArgPolicyOff {
+		return nil
+	}
+	g := &argGuard{policy: cfg.Policy, keys: []*regexp.Regexp{sensitiveKeyPattern}}
+	if g.policy != models.ToolArgPolicyBlock {
+		g.policy = models.ToolArgPolicyRedact
+	}
+	for _, p := range cfg.Patterns {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			log.Warn("忽略无效的敏感字段规则 %q: %v", p, err)
+			continue
+		}
+		g.keys = append(g.keys, re)
+	}
+	if position != nil && position.Shares > 0 {
+		// 个位数太常见（如 limit、页码），不作为持仓数字匹配
+		for _, v := range []float64{position.CostPrice, float64(position.Shares), position.CostPrice * float64(position.Shares)} {
+			if v >= 10 {
+				g.values = append(g.values, v)
+			}
+		}
+	}
+	return g
+}
+
+// beforeToolCall 返回只检查外部工具的回调，local 为内置工具名
+// redact 策略原地替换参数（字符串脱敏、数值字段去掉）后继续执行；block 策略返回错误结果跳过执行
+func (g *argGuard) beforeToolCall(local map[string]bool) llmagent.BeforeToolCallback {
+	return func(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+		if local[t.Name()] {
+			return nil, nil
+		}
+		redacted, findings := g.scan(args)
+		if len(findings) == 0 {
+			return nil, nil
+		}
+		// 只记录字段路径，不记录敏感值本身
+		log.Warn("[ToolArgGuard] agent=%s tool=%s policy=%s fields=%v", ctx.AgentName(), t.Name(), g.policy, findings)
+		if g.policy == models.ToolArgPolicyBlock {
+			return map[string]any{"error": fmt.Sprintf(
+				"调用外部工具 %s 的参数包含持仓敏感信息（%s），已拒绝调用。请去掉成本价、持仓数量、账号等个人信息后再调用",
+				t.Name(), strings.Join(findings, ", "))}, nil
+		}
+		for k := range args {
+			if v, ok := redacted[k]; ok {
+				args[k] = v
+			} else {
+				delete(args, k)
+			}
+		}
+		return nil, nil
+	}
+}
+
+// scan 检查参数，返回脱敏后的参数与命中的字段路径，不修改传入的参数
+func (g *argGuard) scan(args map[string]any) (map[string]any, []string) {
+	var findings []string
+	out, _ := g.walk(args, "", false, &findings)
+	m, _ := out.(map[string]any)
+	return m, findings
+}
+
+// walk 递归处理参数，sensitive 表示位于敏感字段之下，其中的数字一律脱敏
+// 命中的数值返回 keep=false，由上层从 map 或数组中去掉
+func (g *argGuard) walk(v any, path string, sensitive bool, findings *[]string) (out any, keep bool) {
+	switch val := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, item := range val {
+			if r, ok := g.walk(item, joinArgPath(path, k), sensitive || g.sensitiveKey(k), findings); ok {
+				m[k] = r
+			}
+		}
+		return m, true
+	case []any:
+		list := make([]any, 0, len(val))
+		for i, item := range val {
+			if r, ok := g.walk(item, fmt.Sprintf("%s[%d]", path, i), sensitive, findings); ok {
+				list = append(list, r)
+			}
+		}
+		return list, true
+	case string:
+		s := accountLikePattern.ReplaceAllString(val, redactedValue)
+		s = numberPattern.ReplaceAllStringFunc(s, func(n string) string {
+			f, err := strconv.ParseFloat(n, 64)
+			if err == nil && (sensitive || g.positionValue(f)) {
+				return redactedValue
+			}
+			return n
+		})
+		if s != val {
+			*findings = append(*findings, path)
+		}
+		return s, true
+	case float64, float32, int, int32, int64, uint, uint32, uint64:
+		f, _ := strconv.ParseFloat(fmt.Sprint(val), 64)
+		if sensitive || g.positionValue(f) {
+			*findings = append(*findings, path)
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// sensitiveKey 字段名是否命中敏感规则
+func (g *argGuard) sensitiveKey(key string) bool {
+	for _, re := range g.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// positionValue 数字是否与当前持仓数字相同（精确到分）
+func (g *argGuard) positionValue(f float64) bool {
+	for _, v := range g.values {
+		if math.Abs(f-v) < 0.005 {
+			return true
+		}
+	}
+	return false
+}
+
+func joinArgPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
@@ -0,0 +1,184 @@
+package adk
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+var testPosition = &models.StockPosition{Shares: 1200, CostPrice: 1650.32}
+
+func TestArgGuardScan(t *testing.T) {
+	g := newArgGuard(models.ToolArgGuard{}, testPosition)
+	args := map[string]any{
+		"query": "茅台 成本1650.32 持有1200股怎么办",
+		"filters": map[string]any{
+			"costPrice": 1650.32,
+			"market":    "sh",
+			"limit":     float64(10),
+			"total":     1650.32 * 1200,
+		},
+		"items":   []any{map[string]any{"holding": "约1200股"}, "600519", float64(5), []any{"2025-10-16", float64(1200)}},
+		"account": "A123456789",
+		"note":    "卡号 6222021234567890123",
+	}
+
+	got, findings := g.scan(args)
+	// 数值字段不替换为字符串，直接去掉
+	want := map[string]any{
+		"query": "茅台 成本[REDACTED] 持有[REDACTED]股怎么办",
+		"filters": map[string]any{
+			"market": "sh",
+			"limit":  float64(10),
+		},
+		"items":   []any{map[string]any{"holding": "约[REDACTED]股"}, "600519", float64(5), []any{"2025-10-16"}},
+		"account": "[REDACTED]",
+		"note":    "卡号 [REDACTED]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("redacted = %#v", got)
+	}
+	sort.Strings(findings)
+	wantFindings := []string{"account", "filters.costPrice", "filters.total", "items[0].holding", "items[3][1]", "note", "query"}
+	if !reflect.DeepEqual(findings, wantFindings) {
+		t.Errorf("findings = %v", findings)
+	}
+	if args["query"] != "茅台 成本1650.32 持有1200股怎么办" {
+		t.Error("scan should not modify the original args")
+	}
+
+	// 无持仓时只按字段名与账号规则检查
+	if _, findings := newArgGuard(models.ToolArgGuard{}, nil).scan(map[string]any{"q": "1650.32", "n": float64(1200)}); len(findings) != 0 {
+		t.Errorf("no position findings = %v", findings)
+	}
+	// 分页位置、持有人等普通字段不算持仓
+	plain := map[string]any{"position": float64(20), "holder": "社保基金", "cost_center": "a1", "share_type": "A"}
+	if _, findings := newArgGuard(models.ToolArgGuard{}, nil).scan(plain); len(findings) != 0 {
+		t.Errorf("plain findings = %v", findings)
+	}
+	for _, key := range []string{"costPrice", "avg_cost", "holdings", "position_size", "holdingQty", "shares", "account_no", "持仓数量"} {
+		if !g.sensitiveKey(key) {
+			t.Errorf("%s should be sensitive", key)
+		}
+	}
+}
+
+func TestArgGuardConfig(t *testing.T) {
+	if newArgGuard(models.ToolArgGuard{Policy: models.ToolArgPolicyOff}, testPosition) != nil {
+		t.Error("off policy should disable the guard")
+	}
+	g := newArgGuard(models.ToolArgGuard{Policy: "unknown", Patterns: []string{"broker_?id", "(", " "}}, nil)
+	if g.policy != models.ToolArgPolicyRedact || len(g.keys) != 2 {
+		t.Fatalf("guard = %+v", g)
+	}
+	got, _ := g.scan(map[string]any{"BrokerID": float64(77), "page": float64(2)})
+	if _, ok := got["BrokerID"]; ok || got["page"] != float64(2) {
+		t.Errorf("custom pattern = %v", got)
+	}
+}
+
+// argsLLM 第一轮以 args 调用 ext 工具，记录工具返回给模型的结果
+type argsLLM struct {
+	args     map[string]any
+	response *map[string]any
+}
+
+func (argsLLM) Name() string { return "args" }
+
+func (m argsLLM) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		last := req.Contents[len(req.Contents)-1]
+		if fr := last.Parts[0].FunctionResponse; fr != nil {
+			*m.response = fr.Response
+			yield(&model.LLMResponse{Content: genai.NewContentFromText("done", genai.RoleModel)}, nil)
+			return
+		}
+		yield(&model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{
+			FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "ext", Args: m.args},
+		}}}}, nil)
+	}
+}
+
+// runGuardedTool 以 policy 运行一次 ext 工具调用，返回工具收到的参数与模型收到的结果
+func runGuardedTool(t *testing.T, policy models.ToolArgPolicy, local bool, args map[string]any) (received map[string]any, response map[string]any) {
+	t.Helper()
+	type extArgs struct {
+		Query     string  `json:"query"`
+		CostPrice float64 `json:"costPrice,omitempty"` // 数值字段，参数 schema 不接受字符串
+	}
+	ext, err := functiontool.New(functiontool.Config{Name: "ext", Description: "external"},
+		func(_ tool.Context, in extArgs) (map[string]any, error) {
+			received = map[string]any{"query": in.Query, "costPrice": in.CostPrice}
+			return map[string]any{"ok": true}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := newArgGuard(models.ToolArgGuard{Policy: policy}, testPosition)
+	a, err := llmagent.New(llmagent.Config{
+		Name:                "expert",
+		Model:               argsLLM{args: args, response: &response},
+		Tools:               []tool.Tool{ext},
+		BeforeToolCallbacks: []llmagent.BeforeToolCallback{beforeToolCall, guard.beforeToolCall(map[string]bool{"ext": local})},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "test", Agent: a, SessionService: sessions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessions.Create(context.Background(), &session.CreateRequest{AppName: "test", UserID: "u", SessionID: "s"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.Run(context.Background(), "u", "s", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return received, response
+}
+
+func TestArgGuardBeforeToolCall(t *testing.T) {
+	args := func() map[string]any { return map[string]any{"query": "成本1650.32还能拿吗"} }
+
+	received, _ := runGuardedTool(t, models.ToolArgPolicyRedact, false, args())
+	if received["query"] != "成本[REDACTED]还能拿吗" {
+		t.Errorf("redact: tool received %v", received)
+	}
+
+	received, response := runGuardedTool(t, models.ToolArgPolicyBlock, false, args())
+	if received != nil {
+		t.Errorf("block: tool should not run, received %v", received)
+	}
+	if msg, _ := response["error"].(string); !strings.Contains(msg, "已拒绝调用") || !strings.Contains(msg, "query") || strings.Contains(msg, "1650") {
+		t.Errorf("block: response = %v", response)
+	}
+
+	// 内置工具不过滤
+	received, _ = runGuardedTool(t, models.ToolArgPolicyBlock, true, args())
+	if received["query"] != "成本1650.32还能拿吗" {
+		t.Errorf("local tool: received %v", received)
+	}
+
+	// 数值字段去掉后仍满足工具参数 schema，工具正常执行
+	received, response = runGuardedTool(t, models.ToolArgPolicyRedact, false, map[string]any{"query": "茅台", "costPrice": 1650.32})
+	if received == nil || received["query"] != "茅台" || received["costPrice"] != float64(0) {
+		t.Errorf("numeric field: received %v, response %v", received, response)
+	}
+}
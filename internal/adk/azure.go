@@ -0,0 +1,74 @@
+package adk
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// DefaultAzureAPIVersion Azure OpenAI 默认 api-version（GA 版本）
+const DefaultAzureAPIVersion = "2024-10-21"
+
+// normalizeAzureBaseURL 规范化 Azure 资源地址为 https://{resource}.openai.azure.com
+// 兼容用户直接粘贴带 /openai 或完整部署路径的地址
+func normalizeAzureBaseURL(baseURL string) string {
+	baseURL = strings.TrimSpace(strings.TrimRight(baseURL, "/"))
+	if i := strings.Index(baseURL, "/openai"); i >= 0 {
+		baseURL = baseURL[:i]
+	}
+	return baseURL
+}
+
+// azureAPIVersion 返回配置的 api-version，为空时使用默认值
+func azureAPIVersion(config *models.AIConfig) string {
+	if v := strings.TrimSpace(config.APIVersion); v != "" {
+		return v
+	}
+	return DefaultAzureAPIVersion
+}
+
+// azureDeployment 返回部署名，未填写时使用模型名
+func azureDeployment(config *models.AIConfig) string {
+	if d := strings.TrimSpace(config.DeploymentName); d != "" {
+		return d
+	}
+	return config.ModelName
+}
+
+// azureEndpoint 构建 Azure OpenAI 端点
+// chat/completions 走部署路径：{base}/openai/deployments/{deployment}/chat/completions?api-version=...
+// responses 不区分部署，由请求体 model 字段指定部署名：{base}/openai/responses?api-version=...
+func azureEndpoint(config *models.AIConfig, api string) string {
+	path := "/openai/" + api
+	if api == "chat/completions" {
+		path = "/openai/deployments/" + url.PathEscape(azureDeployment(config)) + "/" + api
+	}
+	return normalizeAzureBaseURL(config.BaseURL) + path + "?api-version=" + url.QueryEscape(azureAPIVersion(config))
+}
+
+// openAIEndpoint 构建 OpenAI 兼容接口端点（api 如 chat/completions、responses）
+func openAIEndpoint(config *models.AIConfig, api string) string {
+	if config.IsAzure {
+		return azureEndpoint(config, api)
+	}
+	return normalizeOpenAIBaseURL(config.BaseURL) + "/" + api
+}
+
+// openAIRequestModel 请求体中的 model 字段，Azure 使用部署名
+func openAIRequestModel(config *models.AIConfig) string {
+	if config.IsAzure {
+		return azureDeployment(config)
+	}
+	return config.ModelName
+}
+
+// setOpenAIAuth 设置鉴权头：Azure 使用 api-key，其余使用 Bearer Token
+func setOpenAIAuth(req *http.Request, config *models.AIConfig) {
+	if config.IsAzure {
+		req.Header.Set("api-key", config.APIKey)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+config.APIKey)
+}
@@ -0,0 +1,155 @@
+package adk
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk/capability"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// capabilityLLM 按能力矩阵丢弃不支持参数的模型包装
+// 请求返回 400 且指向某项参数时，标记该能力不支持并通知持久化，后续请求不再发送
+type capabilityLLM struct {
+	model.LLM
+	configID string
+
+	mu   sync.RWMutex
+	caps capability.Set
+}
+
+// withCapabilities 包装模型，请求前按配置的生效能力过滤参数
+func withCapabilities(llm model.LLM, config *models.AIConfig) model.LLM {
+	if llm == nil || config == nil {
+		return llm
+	}
+	return &capabilityLLM{LLM: llm, configID: config.ID, caps: capability.Effective(config)}
+}
+
+// GenerateContent 过滤请求参数后调用底层模型，并从错误中学习不支持的能力
+func (m *capabilityLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.mu.RLock()
+		caps := m.caps
+		m.mu.RUnlock()
+
+		for resp, err := range m.LLM.GenerateContent(ctx, stripUnsupported(req, caps, m.Name()), stream) {
+			if err != nil {
+				m.learn(err)
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// learn 记录 400 错误指向的能力为不支持
+func (m *capabilityLLM) learn(err error) {
+	for _, c := range capability.FromError(err) {
+		m.mu.Lock()
+		supported := m.caps.Supports(c)
+		if supported {
+			caps := maps.Clone(m.caps)
+			caps[c] = false
+			m.caps = caps
+		}
+		m.mu.Unlock()
+		if supported {
+			log.Warn("模型 %s 拒绝了%s参数，后续请求将不再发送", m.Name(), c.Label())
+			capability.Learn(m.configID, c, false)
+		}
+	}
+}
+
+// stripUnsupported 返回去掉不支持参数的请求副本，无需修改时返回原请求
+// 请求配置可能被多位专家共用，不能原地修改
+func stripUnsupported(req *model.LLMRequest, caps capability.Set, modelName string) *model.LLMRequest {
+	if req == nil || req.Config == nil {
+		return req
+	}
+	cfg := *req.Config
+	changed := false
+	if cfg.ThinkingConfig != nil && capability.Drop(caps, capability.Thinking, modelName) {
+		cfg.ThinkingConfig = nil
+		changed = true
+	}
+	if (cfg.ResponseMIMEType == "application/json" || cfg.ResponseSchema != nil || cfg.ResponseJsonSchema != nil) &&
+		capability.Drop(caps, capability.JSONMode, modelName) {
+		cfg.ResponseMIMEType = ""
+		cfg.ResponseSchema = nil
+		cfg.ResponseJsonSchema = nil
+		changed = true
+	}
+	if cfg.ToolConfig != nil && capability.Drop(caps, capability.ToolChoice, modelName) {
+		cfg.ToolConfig = nil
+		changed = true
+	}
+	if cfg.Seed != nil && capability.Drop(caps, capability.Seed, modelName) {
+		cfg.Seed = nil
+		changed = true
+	}
+	if !changed {
+		return req
+	}
+	stripped := *req
+	stripped.Config = &cfg
+	return &stripped
+}
+
+// ProbeCapabilities 对 OpenAI 兼容接口逐项探测默认支持的能力
+// 请求成功记为支持，400 且指向该参数记为不支持，其他错误（网络、鉴权等）不下结论
+func (f *ModelFactory) ProbeCapabilities(ctx context.Context, config *models.AIConfig) map[capability.Capability]bool {
+	switch config.Provider {
+	case models.AIProviderOpenAI, models.AIProviderDeepSeek, models.AIProviderQwen, models.AIProviderMistral:
+	default:
+		return nil
+	}
+	llm, err := f.createModel(ctx, config)
+	if err != nil {
+		return nil
+	}
+
+	probes := map[capability.Capability]*genai.GenerateContentConfig{
+		capability.Thinking: {MaxOutputTokens: 1, ThinkingConfig: &genai.ThinkingConfig{ThinkingLevel: genai.ThinkingLevelLow}},
+		capability.JSONMode: {MaxOutputTokens: 1, ResponseMIMEType: "application/json"},
+	}
+	defaults := capability.Defaults(config.Provider)
+	results := make(map[capability.Capability]bool)
+	for c, cfg := range probes {
+		if !defaults.Supports(c) {
+			continue
+		}
+		req := &model.LLMRequest{
+			Contents: []*genai.Content{
+				{Role: "user", Parts: []*genai.Part{{Text: "reply with json: {}"}}},
+			},
+			Config: cfg,
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		err := probeGenerate(probeCtx, llm, req)
+		cancel()
+		switch {
+		case err == nil:
+			results[c] = true
+		case slices.Contains(capability.FromError(err), c):
+			results[c] = false
+		}
+	}
+	return results
+}
+
+// probeGenerate 发送探测请求，只关心是否报错
+func probeGenerate(ctx context.Context, llm model.LLM, req *model.LLMRequest) error {
+	for _, err := range llm.GenerateContent(ctx, req, false) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Package capability 维护各 Provider 支持的可选请求参数（能力矩阵）
+// 默认值按 Provider 给出，连通性探测与请求返回的 400 错误会按配置学习覆盖，
+// 请求构造时据此丢弃不支持的参数，避免整次请求被拒绝
+package capability
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var log = logger.New("capability")
+
+// Capability 可选请求参数
+type Capability string
+
+const (
+	Thinking    Capability = "thinking"     // 思考/推理参数（reasoning_effort、thinking budget）
+	JSONMode    Capability = "json_mode"    // JSON 输出模式（response_format）
+	PromptCache Capability = "prompt_cache" // 提示缓存断点（cache_control）
+	ToolChoice  Capability = "tool_choice"  // 指定工具调用方式（tool_choice / tool_config）
+	Seed        Capability = "seed"         // 随机种子（复现模式）
+)
+
+// All 全部能力，按展示顺序排列
+var All = []Capability{Thinking, JSONMode, PromptCache, ToolChoice, Seed}
+
+// Label 能力的中文名称
+func (c Capability) Label() string {
+	switch c {
+	case Thinking:
+		return "思考预算"
+	case JSONMode:
+		return "JSON 模式"
+	case PromptCache:
+		return "提示缓存"
+	case ToolChoice:
+		return "工具调用方式"
+	case Seed:
+		return "随机种子"
+	}
+	return string(c)
+}
+
+// Set 能力集合，nil 表示不做限制
+type Set map[Capability]bool
+
+// Supports 是否支持该能力
+func (s Set) Supports(c Capability) bool {
+	if s == nil {
+		return true
+	}
+	return s[c]
+}
+
+// defaults 各 Provider 的默认能力，未列出的能力视为不支持
+var defaults = map[models.AIProvider]Set{
+	models.AIProviderOpenAI:         {Thinking: true, JSONMode: true, ToolChoice: true, Seed: true},
+	models.AIProviderGemini:         {Thinking: true, JSONMode: true, ToolChoice: true, Seed: true},
+	models.AIProviderVertexAI:       {Thinking: true, JSONMode: true, ToolChoice: true, Seed: true},
+	models.AIProviderVertexAIGarden: {JSONMode: true},
+	models.AIProviderAnthropic:      {Thinking: true, PromptCache: true, ToolChoice: true},
+	models.AIProviderBedrock:        {Thinking: true, PromptCache: true, ToolChoice: true},
+	models.AIProviderOllama:         {},
+	models.AIProviderDeepSeek:       {JSONMode: true, ToolChoice: true},
+	models.AIProviderQwen:           {JSONMode: true, ToolChoice: true},
+	models.AIProviderMistral:        {JSONMode: true, ToolChoice: true},
+}
+
+// Defaults 返回 Provider 的默认能力（副本）
+func Defaults(provider models.AIProvider) Set {
+	set := make(Set, len(All))
+	for c, ok := range defaults[provider] {
+		set[c] = ok
+	}
+	return set
+}
+
+// Effective 返回配置实际生效的能力：Provider 默认值叠加配置上学习到的覆盖
+func Effective(config *models.AIConfig) Set {
+	if config == nil {
+		return nil
+	}
+	set := Defaults(config.Provider)
+	for name, ok := range config.Capabilities {
+		set[Capability(name)] = ok
+	}
+	return set
+}
+
+// SupportsSeed 配置是否会把随机种子发送给模型
+// OpenAI Responses API 没有 seed 参数，即使能力矩阵支持也视为不支持
+func SupportsSeed(config *models.AIConfig) bool {
+	if config == nil {
+		return false
+	}
+	if config.Provider == models.AIProviderOpenAI && config.UseResponses {
+		return false
+	}
+	return Effective(config).Supports(Seed)
+}
+
+// Info 单项能力的生效状态，供配置编辑器展示
+type Info struct {
+	Capability Capability `json:"capability"`
+	Label      string     `json:"label"`
+	Supported  bool       `json:"supported"`
+	Learned    bool       `json:"learned"` // 来自探测或 400 错误，而非 Provider 默认值
+}
+
+// Describe 返回配置全部能力的生效状态
+func Describe(config *models.AIConfig) []Info {
+	set := Effective(config)
+	infos := make([]Info, 0, len(All))
+	for _, c := range All {
+		_, learned := config.Capabilities[string(c)]
+		infos = append(infos, Info{Capability: c, Label: c.Label(), Supported: set.Supports(c), Learned: learned})
+	}
+	return infos
+}
+
+// Drop 能力不支持时记录调试日志并返回 true，调用方据此丢弃对应参数
+func Drop(set Set, c Capability, modelName string) bool {
+	if set.Supports(c) {
+		return false
+	}
+	log.Debug("模型 %s 不支持%s，已忽略该参数", modelName, c.Label())
+	return true
+}
+
+// errorKeywords 400 错误信息中指向各能力参数的关键词（小写匹配）
+var errorKeywords = map[Capability][]string{
+	Thinking:    {"reasoning_effort", "thinking", "budget_tokens"},
+	JSONMode:    {"response_format", "response_mime_type", "responsemimetype", "json_object"},
+	PromptCache: {"cache_control"},
+	ToolChoice:  {"tool_choice", "tool_config", "toolconfig", "function_calling_config"},
+	Seed:        {"seed"},
+}
+
+// FromError 从请求错误中识别被接口拒绝的能力参数，仅处理 400 错误
+func FromError(err error) []Capability {
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	if !strings.Contains(msg, "400") {
+		return nil
+	}
+	var found []Capability
+	for _, c := range All {
+		for _, kw := range errorKeywords[c] {
+			if strings.Contains(msg, kw) {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// LearnedListener 学习到能力支持情况后的回调，用于持久化到对应 AI 配置
+type LearnedListener func(configID string, c Capability, supported bool)
+
+var (
+	listenerMu sync.RWMutex
+	listener   LearnedListener
+)
+
+// SetLearnedListener 设置能力学习回调（全局，所有模型工厂共用）
+func SetLearnedListener(fn LearnedListener) {
+	listenerMu.Lock()
+	defer listenerMu.Unlock()
+	listener = fn
+}
+
+// Learn 通知学习结果
+func Learn(configID string, c Capability, supported bool) {
+	listenerMu.RLock()
+	fn := listener
+	listenerMu.RUnlock()
+	if fn != nil && configID != "" {
+		fn(configID, c, supported)
+	}
+}
@@ -0,0 +1,78 @@
+package capability
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestEffective(t *testing.T) {
+	cfg := &models.AIConfig{
+		Provider:     models.AIProviderAnthropic,
+		Capabilities: map[string]bool{"prompt_cache": false, "json_mode": true},
+	}
+	set := Effective(cfg)
+	if !set.Supports(Thinking) || set.Supports(PromptCache) || !set.Supports(JSONMode) {
+		t.Errorf("effective = %v", set)
+	}
+	// 覆盖不影响 Provider 默认值
+	if !Defaults(models.AIProviderAnthropic).Supports(PromptCache) {
+		t.Error("defaults mutated")
+	}
+
+	infos := Describe(cfg)
+	if len(infos) != len(All) {
+		t.Fatalf("describe = %+v", infos)
+	}
+	for _, info := range infos {
+		wantLearned := info.Capability == PromptCache || info.Capability == JSONMode
+		if info.Learned != wantLearned || info.Supported != set.Supports(info.Capability) {
+			t.Errorf("info = %+v", info)
+		}
+	}
+}
+
+func TestSupportsSeed(t *testing.T) {
+	tests := []struct {
+		cfg  *models.AIConfig
+		want bool
+	}{
+		{&models.AIConfig{Provider: models.AIProviderOpenAI}, true},
+		{&models.AIConfig{Provider: models.AIProviderOpenAI, UseResponses: true}, false},
+		{&models.AIConfig{Provider: models.AIProviderOpenAI, Capabilities: map[string]bool{"seed": false}}, false},
+		{&models.AIConfig{Provider: models.AIProviderGemini}, true},
+		{&models.AIConfig{Provider: models.AIProviderAnthropic}, false},
+		{nil, false},
+	}
+	for i, tt := range tests {
+		if got := SupportsSeed(tt.cfg); got != tt.want {
+			t.Errorf("case %d: SupportsSeed = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		err  string
+		want []Capability
+	}{
+		{`error, status code: 400, message: Unrecognized request argument supplied: reasoning_effort`, []Capability{Thinking}},
+		{`HTTP 400: {"error":{"message":"tools.0.cache_control: Extra inputs are not permitted"}}`, []Capability{PromptCache}},
+		{`Error 400, Message: tool_config is not supported, Status: INVALID_ARGUMENT`, []Capability{ToolChoice}},
+		{`Responses API 错误 (HTTP 400): response_format json_object not supported`, []Capability{JSONMode}},
+		{`error, status code: 400, message: Unrecognized request argument supplied: seed`, []Capability{Seed}},
+		// 非 400 错误不学习
+		{`HTTP 500: response_format internal error`, nil},
+		{`HTTP 400: invalid api key`, nil},
+	}
+	for _, tt := range tests {
+		if got := FromError(errors.New(tt.err)); !slices.Equal(got, tt.want) {
+			t.Errorf("FromError(%q) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+	if FromError(nil) != nil {
+		t.Error("nil error")
+	}
+}
@@ -0,0 +1,195 @@
+package adk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/adk/capability"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// captureServer 记录每次请求体，按 respond 返回响应
+func captureServer(t *testing.T, respond func(call int, w http.ResponseWriter)) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		call := len(bodies)
+		mu.Unlock()
+		respond(call, w)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), bodies...)
+	}
+}
+
+func okChatCompletion(_ int, w http.ResponseWriter) {
+	w.Write([]byte(`{"choices":[{"message":{"content":"{}"},"finish_reason":"stop"}]}`))
+}
+
+// optionalParamsRequest 带齐全部可选参数的请求
+func optionalParamsRequest() *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}},
+		Config: &genai.GenerateContentConfig{
+			ThinkingConfig:   &genai.ThinkingConfig{ThinkingLevel: genai.ThinkingLevelHigh},
+			ResponseMIMEType: "application/json",
+			ToolConfig: &genai.ToolConfig{
+				FunctionCallingConfig: &genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeAny},
+			},
+			Tools: []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{
+				Name:        "get_quote",
+				Description: "查询行情",
+			}}}},
+		},
+	}
+}
+
+func generateOnce(t *testing.T, llm model.LLM, req *model.LLMRequest) error {
+	t.Helper()
+	for _, err := range llm.GenerateContent(context.Background(), req, false) {
+		return err
+	}
+	return nil
+}
+
+func TestCapabilities_DropUnsupportedParams(t *testing.T) {
+	srv, bodies := captureServer(t, okChatCompletion)
+	f := NewModelFactory()
+
+	// 默认能力：三项参数都会发送
+	cfg := &models.AIConfig{ID: "a", Provider: models.AIProviderOpenAI, BaseURL: srv.URL, ModelName: "gpt-test"}
+	llm, err := f.CreateModel(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generateOnce(t, llm, optionalParamsRequest()); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"reasoning_effort"`, `"response_format"`, `"tool_choice"`} {
+		if !strings.Contains(bodies()[0], key) {
+			t.Errorf("默认能力下请求缺少 %s: %s", key, bodies()[0])
+		}
+	}
+
+	// 标记为不支持后，参数不出现在请求 JSON 中
+	cfg.Capabilities = map[string]bool{"thinking": false, "json_mode": false, "tool_choice": false}
+	llm, err = f.CreateModel(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := optionalParamsRequest()
+	if err := generateOnce(t, llm, req); err != nil {
+		t.Fatal(err)
+	}
+	body := bodies()[1]
+	for _, key := range []string{`"reasoning_effort"`, `"response_format"`, `"tool_choice"`} {
+		if strings.Contains(body, key) {
+			t.Errorf("不支持的参数 %s 出现在请求中: %s", key, body)
+		}
+	}
+	if !strings.Contains(body, `"get_quote"`) {
+		t.Errorf("工具定义不应被丢弃: %s", body)
+	}
+	// 原请求配置不被修改（可能被多位专家共用）
+	if req.Config.ThinkingConfig == nil || req.Config.ResponseMIMEType == "" || req.Config.ToolConfig == nil {
+		t.Errorf("原请求被修改: %+v", req.Config)
+	}
+}
+
+func TestCapabilities_AnthropicPromptCacheAndThinking(t *testing.T) {
+	srv, bodies := captureServer(t, func(_ int, w http.ResponseWriter) {
+		w.Write([]byte(`{"content":[{"type":"text","text":"hi"}],"stop_reason":"end_turn"}`))
+	})
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "你是分析师"}}},
+			MaxOutputTokens:   4096,
+		},
+	}
+	f := NewModelFactory()
+	for i, caps := range []map[string]bool{nil, {"prompt_cache": false, "thinking": false}} {
+		cfg := &models.AIConfig{
+			Provider: models.AIProviderAnthropic, BaseURL: srv.URL, ModelName: "claude-sonnet-4-5",
+			ThinkingBudget: 2048, EnablePromptCache: true, Capabilities: caps,
+		}
+		llm, err := f.CreateModel(context.Background(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		generateOnce(t, llm, req)
+		if len(bodies()) != i+1 {
+			t.Fatalf("requests = %d", len(bodies()))
+		}
+		body := bodies()[i]
+		sent := strings.Contains(body, "cache_control") || strings.Contains(body, `"thinking"`)
+		bothSent := strings.Contains(body, "cache_control") && strings.Contains(body, `"thinking"`)
+		if caps == nil && !bothSent {
+			t.Errorf("默认能力下应发送 thinking 与 cache_control: %s", body)
+		}
+		if caps != nil && sent {
+			t.Errorf("不支持的参数出现在请求中: %s", body)
+		}
+	}
+}
+
+func TestCapabilities_LearnFrom400(t *testing.T) {
+	srv, bodies := captureServer(t, func(call int, w http.ResponseWriter) {
+		if call == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"Unrecognized request argument supplied: response_format","type":"invalid_request_error"}}`))
+			return
+		}
+		okChatCompletion(call, w)
+	})
+
+	type learned struct {
+		id        string
+		c         capability.Capability
+		supported bool
+	}
+	var got []learned
+	capability.SetLearnedListener(func(id string, c capability.Capability, supported bool) {
+		got = append(got, learned{id, c, supported})
+	})
+	t.Cleanup(func() { capability.SetLearnedListener(nil) })
+
+	cfg := &models.AIConfig{ID: "cfg-1", Provider: models.AIProviderDeepSeek, BaseURL: srv.URL}
+	llm, err := NewModelFactory().CreateModel(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}},
+		Config:   &genai.GenerateContentConfig{ResponseMIMEType: "application/json"},
+	}
+	if err := generateOnce(t, llm, req); err == nil {
+		t.Fatal("expected 400 error")
+	}
+	if len(got) != 1 || got[0] != (learned{"cfg-1", capability.JSONMode, false}) {
+		t.Fatalf("learned = %+v", got)
+	}
+
+	// 同一模型实例的后续请求立即生效
+	if err := generateOnce(t, llm, req); err != nil {
+		t.Fatal(err)
+	}
+	if body := bodies()[1]; strings.Contains(body, "response_format") {
+		t.Errorf("学习后仍发送 response_format: %s", body)
+	}
+}
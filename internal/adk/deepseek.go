@@ -0,0 +1,38 @@
+package adk
+
+import (
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// DeepSeek 默认配置
+const (
+	DefaultDeepSeekBaseURL = "https://api.deepseek.com/v1"
+	DeepSeekChatModel      = "deepseek-chat"
+	DeepSeekReasonerModel  = "deepseek-reasoner"
+)
+
+// isDeepSeekReasoner 是否为 DeepSeek 推理模型
+func isDeepSeekReasoner(modelName string) bool {
+	return strings.EqualFold(strings.TrimSpace(modelName), DeepSeekReasonerModel)
+}
+
+// deepSeekConfig 补全 DeepSeek 默认值，返回可交给 OpenAI 兼容实现的配置副本
+// DeepSeek 仅提供 chat/completions，不支持 Responses API / Azure
+func deepSeekConfig(config *models.AIConfig) *models.AIConfig {
+	c := *config
+	if strings.TrimSpace(c.BaseURL) == "" {
+		c.BaseURL = DefaultDeepSeekBaseURL
+	}
+	if strings.TrimSpace(c.ModelName) == "" {
+		c.ModelName = DeepSeekChatModel
+	}
+	c.UseResponses = false
+	c.IsAzure = false
+	// deepseek-reasoner 支持 system prompt，推理内容以 <think> 块输出，由 thinkTagStreamParser 处理
+	if isDeepSeekReasoner(c.ModelName) {
+		c.NoSystemRole = false
+	}
+	return &c
+}
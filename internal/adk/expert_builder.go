@@ -16,14 +16,36 @@ import (
 	"google.golang.org/genai"
 )
 
+// IndexOverviewProvider 指数概览数据提供者
+type IndexOverviewProvider func(code string) (*models.IndexOverview, error)
+
+// ActionJournalProvider 用户实际操作记录提供者，返回可直接拼入提示词的文本
+type ActionJournalProvider func(code string) string
+
 // ExpertAgentBuilder 专家 Agent 构建器
 type ExpertAgentBuilder struct {
-	llm          model.LLM
-	aiConfig     *models.AIConfig // AI 配置（包含 temperature、maxTokens）
-	toolRegistry *tools.Registry
-	mcpManager   *mcp.Manager
+	llm           model.LLM
+	aiConfig      *models.AIConfig // AI 配置（包含 temperature、maxTokens）
+	toolRegistry  *tools.Registry
+	mcpManager    *mcp.Manager
+	indexOverview IndexOverviewProvider // 标的为指数时用于构建指数上下文
+	actionJournal ActionJournalProvider // 风控专家的用户操作记录
+	promptPrefix  string                // 全局系统指令前缀（如合规声明）
+	promptSuffix  string                // 全局系统指令后缀
+	fixedNow      time.Time             // 非零时替代系统指令中的当前时间（复现模式固定为会议开始时间）
+	freshness     *models.DataFreshness // 行情数据时效，非实时数据时要求回复首行声明截至时间
+	dryRun        bool                  // 预演模式：不挂载工具，只让专家描述计划调用的工具
+	argGuard      models.ToolArgGuard   // 外部工具调用参数的持仓敏感信息过滤
 }
 
+// DryRunSuffix 预演模式追加在系统指令末尾的要求，专家只输出计划调用的工具与理由
+const DryRunSuffix = `## 预演模式（最高优先级）
+Describe the tools you would call and why, but do not actually call them.
+本次只做计划预演：按调用顺序列出你回答该问题时会调用的工具（含关键参数），说明理由，不要给出分析结论。
+只输出 JSON，不要输出其他内容：
+{"toolCalls":["get_kline_data(code=sh600519, period=1d)"],"reasoning":"为什么需要这些工具、为什么按这个顺序"}
+不需要调用工具时 toolCalls 为空数组。`
+
 // NewExpertAgentBuilder 创建专家 Agent 构建器
 func NewExpertAgentBuilder(llm model.LLM, aiConfig *models.AIConfig) *ExpertAgentBuilder {
 	return &ExpertAgentBuilder{llm: llm, aiConfig: aiConfig}
@@ -39,19 +61,65 @@ func NewExpertAgentBuilderFull(llm model.LLM, aiConfig *models.AIConfig, registr
 	return &ExpertAgentBuilder{llm: llm, aiConfig: aiConfig, toolRegistry: registry, mcpManager: mcpMgr}
 }
 
+// AIConfig 返回构建器使用的 AI 配置
+func (b *ExpertAgentBuilder) AIConfig() *models.AIConfig {
+	return b.aiConfig
+}
+
+// SetIndexOverviewProvider 设置指数概览数据提供者
+func (b *ExpertAgentBuilder) SetIndexOverviewProvider(p IndexOverviewProvider) {
+	b.indexOverview = p
+}
+
+// SetActionJournalProvider 设置用户操作记录提供者
+func (b *ExpertAgentBuilder) SetActionJournalProvider(p ActionJournalProvider) {
+	b.actionJournal = p
+}
+
+// SetSystemPromptAffixes 设置注入每位专家系统指令首尾的全局前缀与后缀，空字符串表示不注入
+func (b *ExpertAgentBuilder) SetSystemPromptAffixes(prefix, suffix string) {
+	b.promptPrefix = strings.TrimSpace(prefix)
+	b.promptSuffix = strings.TrimSpace(suffix)
+}
+
+// SetFixedTime 固定系统指令中的当前时间与盘中状态，零值表示使用实时时间
+func (b *ExpertAgentBuilder) SetFixedTime(t time.Time) {
+	b.fixedNow = t
+}
+
+// SetDataFreshness 设置本场会议行情数据的时效，nil 表示不注入
+func (b *ExpertAgentBuilder) SetDataFreshness(f *models.DataFreshness) {
+	b.freshness = f
+}
+
+// SetDryRun 设置预演模式：构建的专家不挂载任何工具，系统指令末尾追加 DryRunSuffix
+func (b *ExpertAgentBuilder) SetDryRun(dryRun bool) {
+	b.dryRun = dryRun
+}
+
+// SetToolArgGuard 设置外部工具（MCP）调用参数的敏感信息过滤配置，零值按 redact 策略过滤
+func (b *ExpertAgentBuilder) SetToolArgGuard(cfg models.ToolArgGuard) {
+	b.argGuard = cfg
+}
+
+// IsRiskAgent 是否为风控类专家，风控建议需结合用户已做的操作
+func IsRiskAgent(config *models.AgentConfig) bool {
+	return config.ID == "risk" || strings.Contains(config.Role, "风控") || strings.Contains(config.Role, "风险")
+}
+
 // BuildAgentWithContext 根据配置构建 LLM Agent（支持引用上下文）
 func (b *ExpertAgentBuilder) BuildAgentWithContext(config *models.AgentConfig, stock *models.Stock, query string, replyContent string, position *models.StockPosition) (agent.Agent, error) {
 	instruction := b.buildInstructionWithContext(config, stock, query, replyContent, position)
 
-	// 获取 Agent 配置的工具
+	// 获取 Agent 配置的工具（预演模式只在指令中列出工具，不提供调用能力）
 	var agentTools []tool.Tool
-	if b.toolRegistry != nil && len(config.Tools) > 0 {
+	if !b.dryRun && b.toolRegistry != nil && len(config.Tools) > 0 {
 		agentTools = b.toolRegistry.GetTools(config.Tools)
 	}
 
 	// 获取 MCP toolsets
 	var toolsets []tool.Toolset
-	if b.mcpManager != nil && len(config.MCPServers) > 0 {
+	if !b.dryRun && b.mcpManager != nil && len(config.MCPServers) > 0 {
 		log.Info("Agent %s 请求 MCP servers: %v", config.ID, config.MCPServers)
 		toolsets = b.mcpManager.GetToolsetsByIDs(config.MCPServers)
 		log.Info("Agent %s 获取到 %d 个 toolsets", config.ID, len(toolsets))
@@ -72,30 +140,92 @@ func (b *ExpertAgentBuilder) BuildAgentWithContext(config *models.AgentConfig, s
 			generateConfig.MaxOutputTokens = int32(b.aiConfig.MaxTokens)
 		}
 	}
+	// 专家单独的 token 预算：只收紧不放宽 AI 配置的上限
+	if limit := int32(config.MaxTokensPerAgent); limit > 0 {
+		if generateConfig == nil {
+			generateConfig = &genai.GenerateContentConfig{}
+		}
+		if generateConfig.MaxOutputTokens == 0 || limit < generateConfig.MaxOutputTokens {
+			generateConfig.MaxOutputTokens = limit
+		}
+	}
+
+	// Gemini 以 VALIDATED 模式调用工具，按声明的 schema 约束参数
+	if len(agentTools) > 0 && b.aiConfig != nil &&
+		(b.aiConfig.Provider == models.AIProviderGemini || b.aiConfig.Provider == models.AIProviderVertexAI) {
+		if generateConfig == nil {
+			generateConfig = &genai.GenerateContentConfig{}
+		}
+		generateConfig.ToolConfig = &genai.ToolConfig{
+			FunctionCallingConfig: &genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeValidated},
+		}
+	}
+
+	// 外部工具调用前过滤参数中的持仓信息，放在预算检查之后，被拒绝的调用同样计入预算
+	beforeCallbacks := []llmagent.BeforeToolCallback{beforeToolCall}
+	if len(toolsets) > 0 {
+		if guard := newArgGuard(b.argGuard, position); guard != nil {
+			local := make(map[string]bool, len(agentTools))
+			for _, t := range agentTools {
+				local[t.Name()] = true
+			}
+			beforeCallbacks = append(beforeCallbacks, guard.beforeToolCall(local))
+		}
+	}
 
 	return llmagent.New(llmagent.Config{
 		Name:                  config.ID,
-		Model:                 b.llm,
+		Model:                 NewRetryingLLM(b.llm, b.aiConfig), // 限流与服务端临时错误自动重试
 		Description:           config.Role,
 		Instruction:           instruction,
 		Tools:                 agentTools,
 		Toolsets:              toolsets,
 		GenerateContentConfig: generateConfig,
+		BeforeToolCallbacks:   beforeCallbacks,
+		AfterToolCallbacks:    []llmagent.AfterToolCallback{afterToolCall},
 	})
 }
 
+// PromptPreview 专家单次请求随附的系统指令与工具描述，用于会前估算消耗
+type PromptPreview struct {
+	Instruction     string // 系统指令
+	ToolSchemaBytes int    // 内置工具声明的字节数
+	MCPToolCount    int    // MCP 工具数量，声明需连接服务器才能取得，由调用方按经验值估算
+}
+
+// PreviewPrompt 按 BuildAgentWithContext 相同的规则构建系统指令并统计工具描述大小，不创建 Agent、不调用模型
+func (b *ExpertAgentBuilder) PreviewPrompt(config *models.AgentConfig, stock *models.Stock, query string, replyContent string, position *models.StockPosition) PromptPreview {
+	preview := PromptPreview{Instruction: b.buildInstructionWithContext(config, stock, query, replyContent, position)}
+	if b.dryRun {
+		return preview
+	}
+	if b.toolRegistry != nil && len(config.Tools) > 0 {
+		preview.ToolSchemaBytes = b.toolRegistry.DeclarationSize(config.Tools)
+	}
+	if b.mcpManager != nil && len(config.MCPServers) > 0 {
+		preview.MCPToolCount = len(b.mcpManager.GetToolInfosByServerIDs(config.MCPServers))
+	}
+	return preview
+}
+
 // buildInstructionWithContext 构建 Agent 指令（支持引用上下文）
 func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentConfig, stock *models.Stock, query string, replyContent string, position *models.StockPosition) string {
 	baseInstruction := config.Instruction
 	if baseInstruction == "" {
 		baseInstruction = fmt.Sprintf("你是一位%s，名字是%s。", config.Role, config.Name)
 	}
+	if b.promptPrefix != "" {
+		baseInstruction = b.promptPrefix + "\n\n" + baseInstruction
+	}
 
 	// 构建可用工具说明
 	toolsDescription := b.buildToolsDescription(config)
 
 	// 获取当前时间和盘中状态
-	now := time.Now()
+	now := b.fixedNow
+	if now.IsZero() {
+		now = time.Now()
+	}
 	timeStr := now.Format("2006-01-02 15:04:05")
 	weekday := now.Weekday()
 	hour, minute := now.Hour(), now.Minute()
@@ -132,14 +262,32 @@ func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentCon
 - <tool>、</tool>
 - 任何类似 <xxx:tool_call> 格式的标签
 直接使用 API 提供的 tool_calls 功能，不要在文本中模拟工具调用。
+`, baseInstruction, toolsDescription, timeStr, marketStatus)
+	if block := buildFreshnessContext(b.freshness); block != "" {
+		prompt += block
+	}
 
+	isIndex := models.IsIndexSymbol(stock.Symbol)
+	if isIndex {
+		prompt += b.buildIndexContext(stock)
+	} else {
+		prompt += fmt.Sprintf(`
 股票: %s (%s)
 当前价格: %.2f
 涨跌幅: %.2f%%
-`, baseInstruction, toolsDescription, timeStr, marketStatus, stock.Symbol, stock.Name, stock.Price, stock.ChangePercent)
+`, stock.Symbol, stock.Name, stock.Price, stock.ChangePercent)
+	}
+	if stock.DataSuspect {
+		prompt += fmt.Sprintf(`
+**【数据质量警告】上方行情疑似异常（%s），价格与涨跌幅可能不准确。**
+请勿据此得出暴涨、暴跌等结论；如需引用行情，先调用工具获取最新数据核实，无法核实时明确说明数据不可靠。
+`, stock.SuspectReason)
+	} else if label := stock.QuoteState.Label(); label != "" {
+		prompt += fmt.Sprintf("（今日%s，当前价格为昨收价，尚无成交）\n", label)
+	}
 
-	// 如果有持仓信息，加入上下文
-	if position != nil && position.Shares > 0 {
+	// 如果有持仓信息，加入上下文（指数无持仓概念）
+	if !isIndex && position != nil && position.Shares > 0 {
 		marketValue := float64(position.Shares) * stock.Price
 		costAmount := float64(position.Shares) * position.CostPrice
 		profitLoss := marketValue - costAmount
@@ -152,6 +300,11 @@ func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentCon
 持仓市值: %.2f，盈亏: %.2f (%.2f%%)
 `, position.Shares, position.CostPrice, marketValue, profitLoss, profitPercent)
 	}
+	if !isIndex && b.actionJournal != nil && IsRiskAgent(config) {
+		if journal := b.actionJournal(stock.Symbol); journal != "" {
+			prompt += "\n" + journal
+		}
+	}
 
 	// 如果有引用内容，加入上下文
 	if replyContent != "" {
@@ -167,10 +320,127 @@ func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentCon
 
 请用简洁专业的语言回答，控制在150字以内。`, query)
 	}
+	if b.promptSuffix != "" {
+		prompt += "\n\n" + b.promptSuffix
+	}
+	// 输出要求放在最后，优先于上文所有指令的语言与风格
+	if block := buildOutputRequirements(config, b.freshness); block != "" {
+		prompt += "\n\n" + block
+	}
+	if b.dryRun {
+		prompt += "\n\n" + DryRunSuffix
+	}
 
 	return prompt
 }
 
+// buildFreshnessContext 构建数据时效说明：行情时间、日K最新日期与是否实时
+func buildFreshnessContext(f *models.DataFreshness) string {
+	if f == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n## 数据时效\n")
+	fmt.Fprintf(&sb, "数据截至: %s（%s）\n", f.AsOf, f.MarketStatus)
+	if f.QuoteTime != "" {
+		fmt.Fprintf(&sb, "行情时间: %s\n", f.QuoteTime)
+	}
+	if f.KLineDate != "" {
+		fmt.Fprintf(&sb, "日K最新: %s\n", f.KLineDate)
+	}
+	if f.Live {
+		sb.WriteString("当前为盘中实时数据。\n")
+	} else {
+		sb.WriteString("**当前不是盘中实时数据**，价格与涨跌幅是最近一次收盘（或休市前）的数据，不要称之为\"今天\"的实时行情，也不要暗示市场正在交易。\n")
+	}
+	return sb.String()
+}
+
+// buildOutputRequirements 构建专家的回复语言、语气与数据时效声明要求，均无需要求时返回空
+// 非中文回复时使用英文书写，避免中文系统提示把回复带回中文
+func buildOutputRequirements(config *models.AgentConfig, freshness *models.DataFreshness) string {
+	lang, _ := models.NormalizeOutputLanguage(config.OutputLanguage)
+	tone := strings.TrimSpace(config.Tone)
+	disclaimer := ""
+	if freshness != nil && !freshness.Live {
+		disclaimer = freshness.Disclaimer
+	}
+	if lang == "" && tone == "" && disclaimer == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	if lang == "" || lang == "zh" {
+		sb.WriteString("## 输出要求（最高优先级）\n")
+		sb.WriteString("以下要求优先于上文所有指令与提示中隐含的语言和风格：\n")
+		if lang == "zh" {
+			sb.WriteString("- 只使用简体中文回复，即使指令、工具结果或其他专家的发言使用其他语言。\n")
+		}
+		if tone != "" {
+			fmt.Fprintf(&sb, "- 语气风格：%s\n", tone)
+		}
+		if disclaimer != "" {
+			fmt.Fprintf(&sb, "- 回复第一行必须单独写「%s」，再另起一行开始分析。\n", disclaimer)
+		}
+		return strings.TrimRight(sb.String(), "\n")
+	}
+
+	name := models.OutputLanguageName(lang)
+	sb.WriteString("## Output requirements (highest priority)\n")
+	sb.WriteString("These requirements override the language and style implied by everything above, including the Chinese system prompts:\n")
+	fmt.Fprintf(&sb, "- Respond only in %s. Do not switch languages, even when instructions, tool results, quoted views or other experts' messages are in Chinese. Stock names and codes may be kept as-is.\n", name)
+	if tone != "" {
+		fmt.Fprintf(&sb, "- Tone: %s\n", tone)
+	}
+	if disclaimer != "" {
+		fmt.Fprintf(&sb, "- The market data is not live. Open your reply with a single line stating the data cut-off (%s, i.e. \"%s\") in %s, then start the analysis on a new line.\n", freshness.AsOf, disclaimer, name)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// buildIndexContext 构建指数上下文：点位、成分涨跌家数与板块表现
+func (b *ExpertAgentBuilder) buildIndexContext(stock *models.Stock) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `
+指数: %s (%s)
+当前点位: %.2f
+涨跌幅: %.2f%%
+**注意：本次讨论的标的是指数而非个股**，请从大盘趋势、市场情绪、板块轮动等角度分析，不要讨论个股基本面、盘口挂单或持仓操作；盘口、龙虎榜等个股工具对指数不适用。
+`, stock.Symbol, stock.Name, stock.Price, stock.ChangePercent)
+
+	if b.indexOverview == nil {
+		return sb.String()
+	}
+	overview, err := b.indexOverview(stock.Symbol)
+	if err != nil || overview == nil {
+		log.Warn("获取指数概览失败: %s, %v", stock.Symbol, err)
+		return sb.String()
+	}
+
+	total := overview.Up + overview.Down + overview.Flat
+	if total > 0 {
+		fmt.Fprintf(&sb, "成分涨跌: 上涨 %d 家，下跌 %d 家，平盘 %d 家（上涨占比 %.1f%%）\n",
+			overview.Up, overview.Down, overview.Flat, float64(overview.Up)/float64(total)*100)
+	}
+	writeSectors := func(title string, sectors []models.SectorPerformance) {
+		if len(sectors) == 0 {
+			return
+		}
+		parts := make([]string, 0, len(sectors))
+		for _, s := range sectors {
+			item := fmt.Sprintf("%s %+.2f%%", s.Name, s.ChangePercent)
+			if s.LeaderName != "" {
+				item += fmt.Sprintf("（%s %+.2f%%）", s.LeaderName, s.LeaderChange)
+			}
+			parts = append(parts, item)
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", title, strings.Join(parts, "、"))
+	}
+	writeSectors("领涨板块", overview.Leading)
+	writeSectors("领跌板块", overview.Lagging)
+	return sb.String()
+}
+
 // buildToolsDescription 构建可用工具说明
 func (b *ExpertAgentBuilder) buildToolsDescription(config *models.AgentConfig) string {
 	var searchTools []string // 搜索类工具
@@ -0,0 +1,122 @@
+package adk
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestBuildInstruction_SystemPromptAffixes(t *testing.T) {
+	agent := &models.AgentConfig{ID: "tech", Name: "老张", Role: "技术分析师", Instruction: "你是技术分析专家。"}
+	stock := &models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500}
+
+	b := NewExpertAgentBuilder(nil, nil)
+	plain := b.buildInstructionWithContext(agent, stock, "怎么看", "", nil)
+	if !strings.HasPrefix(plain, agent.Instruction) {
+		t.Fatalf("默认不应注入前缀: %q", plain[:40])
+	}
+
+	b.SetSystemPromptAffixes("  回复仅供学习参考，不构成投资建议。\n", "请在回复末尾提示风险。")
+	got := b.buildInstructionWithContext(agent, stock, "怎么看", "", nil)
+	if !strings.HasPrefix(got, "回复仅供学习参考，不构成投资建议。\n\n"+agent.Instruction) {
+		t.Errorf("前缀应位于专家指令之前: %q", got[:80])
+	}
+	if !strings.HasSuffix(got, "\n\n请在回复末尾提示风险。") {
+		t.Errorf("后缀应位于指令末尾: %q", got[len(got)-60:])
+	}
+}
+
+func TestBuildInstruction_FixedTime(t *testing.T) {
+	agent := &models.AgentConfig{ID: "tech", Name: "老张", Role: "技术分析师"}
+	stock := &models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500}
+
+	b := NewExpertAgentBuilder(nil, nil)
+	b.SetFixedTime(time.Date(2025, 3, 8, 10, 15, 0, 0, time.Local))
+	got := b.buildInstructionWithContext(agent, stock, "怎么看", "", nil)
+	if !strings.Contains(got, "当前时间: 2025-03-08 10:15:00\n市场状态: 休市（周末）") {
+		t.Errorf("应使用固定时间: %q", got)
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "更新 testdata 中的指令快照")
+
+func TestBuildInstruction_OutputLanguageSnapshot(t *testing.T) {
+	agent := &models.AgentConfig{
+		ID: "macro", Name: "Alex", Role: "宏观分析师",
+		Instruction:    "你是宏观分析师，关注利率与汇率。请用中文回答。",
+		OutputLanguage: "en",
+		Tone:           "concise, data-driven",
+	}
+	stock := &models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500, ChangePercent: 1.25}
+
+	b := NewExpertAgentBuilder(nil, nil)
+	b.SetFixedTime(time.Date(2025, 3, 10, 10, 15, 0, 0, time.Local))
+	b.SetSystemPromptAffixes("", "请在回复末尾提示风险。")
+	got := b.buildInstructionWithContext(agent, stock, "怎么看", "", nil)
+
+	golden := filepath.Join("testdata", "instruction_zh_en.golden")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("读取快照失败（可用 -update 生成）: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("指令与快照不一致:\n%s", got)
+	}
+	// 输出要求位于全局后缀之后，是指令的最后一段
+	if !strings.HasSuffix(got, "- Tone: concise, data-driven") {
+		t.Errorf("输出要求应位于指令末尾")
+	}
+}
+
+func TestBuildOutputRequirements(t *testing.T) {
+	if got := buildOutputRequirements(&models.AgentConfig{}, nil); got != "" {
+		t.Errorf("未设置时不注入: %q", got)
+	}
+	got := buildOutputRequirements(&models.AgentConfig{OutputLanguage: "中文", Tone: "犀利"}, nil)
+	if !strings.Contains(got, "只使用简体中文回复") || !strings.HasSuffix(got, "- 语气风格：犀利") {
+		t.Errorf("中文输出要求: %q", got)
+	}
+	if got := buildOutputRequirements(&models.AgentConfig{Tone: "犀利"}, nil); strings.Contains(got, "简体中文") {
+		t.Errorf("只设置语气时不限定语言: %q", got)
+	}
+
+	// 非实时数据要求首行声明截至时间，实时数据不要求
+	closed := &models.DataFreshness{AsOf: "2026-03-06 15:00", MarketStatus: "已收盘", Disclaimer: "数据截至 3月6日收盘"}
+	if got := buildOutputRequirements(&models.AgentConfig{}, closed); !strings.Contains(got, "回复第一行必须单独写「数据截至 3月6日收盘」") {
+		t.Errorf("非实时数据声明: %q", got)
+	}
+	if got := buildOutputRequirements(&models.AgentConfig{OutputLanguage: "en"}, closed); !strings.Contains(got, "Open your reply with a single line stating the data cut-off (2026-03-06 15:00") {
+		t.Errorf("英文非实时数据声明: %q", got)
+	}
+	live := &models.DataFreshness{AsOf: "2026-03-06 10:00", MarketStatus: "交易中", Live: true}
+	if got := buildOutputRequirements(&models.AgentConfig{}, live); got != "" {
+		t.Errorf("实时数据不要求声明: %q", got)
+	}
+}
+
+func TestBuildFreshnessContext(t *testing.T) {
+	if got := buildFreshnessContext(nil); got != "" {
+		t.Errorf("未设置时不注入: %q", got)
+	}
+	got := buildFreshnessContext(&models.DataFreshness{
+		AsOf: "2026-03-06 15:00", QuoteTime: "2026-03-06 15:00:03", KLineDate: "2026-03-06", MarketStatus: "休市",
+	})
+	for _, want := range []string{"数据截至: 2026-03-06 15:00（休市）", "行情时间: 2026-03-06 15:00:03", "日K最新: 2026-03-06", "当前不是盘中实时数据"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("缺少 %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolCatalogTTL 工具目录缓存有效期，过期后先返回旧数据（stale）并在后台刷新
+const ToolCatalogTTL = 10 * time.Minute
+
+// toolListTimeout 单个服务器 ListTools 的超时
+const toolListTimeout = 10 * time.Second
+
+// catalogRetryInterval 拉取失败后，读取缓存触发重试的最小间隔
+const catalogRetryInterval = 30 * time.Second
+
+// catalogEntry 单个服务器的工具目录缓存
+type catalogEntry struct {
+	tools       []ToolInfo
+	fetchedAt   time.Time     // 最近一次成功拉取时间，零值表示从未成功
+	attemptedAt time.Time     // 最近一次拉取完成时间
+	err         error         // 最近一次拉取的错误
+	loading     chan struct{} // 非 nil 表示正在拉取，完成后关闭
+}
+
+// toolCatalog 按服务器缓存 ListTools 结果
+type toolCatalog struct {
+	mu         sync.Mutex
+	entries    map[string]*catalogEntry
+	generation int // LoadConfigs 时递增，丢弃旧配置的拉取结果
+}
+
+func newToolCatalog() *toolCatalog {
+	return &toolCatalog{entries: make(map[string]*catalogEntry)}
+}
+
+// reset 清空缓存（配置变更后调用）
+func (c *toolCatalog) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*catalogEntry)
+	c.generation++
+}
+
+// listServerTools 连接服务器并拉取工具列表
+func listServerTools(ctx context.Context, cfg *models.MCPServerConfig) ([]ToolInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, toolListTimeout)
+	defer cancel()
+
+	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
+	client := mcp.NewClient(impl, nil)
+	session, err := client.Connect(ctx, createTransport(cfg), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	toolsResp, err := session.ListTools(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make([]ToolInfo, 0, len(toolsResp.Tools))
+	for _, t := range toolsResp.Tools {
+		tools = append(tools, ToolInfo{
+			Name:        t.Name,
+			Description: t.Description,
+			ServerID:    cfg.ID,
+			ServerName:  cfg.Name,
+		})
+	}
+	return tools, nil
+}
+
+// startRefresh 为服务器发起一次后台拉取并返回完成信号
+// 已有拉取进行中时复用，joined 为 true
+func (m *Manager) startRefresh(serverID string) (done <-chan struct{}, joined bool) {
+	m.mu.RLock()
+	cfg, ok := m.configs[serverID]
+	ctx := m.ctx
+	m.mu.RUnlock()
+	if !ok {
+		closed := make(chan struct{})
+		close(closed)
+		return closed, false
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c := m.catalog
+	c.mu.Lock()
+	entry := c.entries[serverID]
+	if entry == nil {
+		entry = &catalogEntry{}
+		c.entries[serverID] = entry
+	}
+	if entry.loading != nil {
+		loading := entry.loading
+		c.mu.Unlock()
+		return loading, true
+	}
+	loading := make(chan struct{})
+	entry.loading = loading
+	gen := c.generation
+	c.mu.Unlock()
+
+	go func() {
+		tools, err := m.listTools(ctx, cfg)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		defer close(loading)
+		entry.loading = nil
+		if gen != c.generation {
+			return
+		}
+		entry.attemptedAt = time.Now()
+		entry.err = err
+		if err != nil {
+			log.Warn("刷新工具目录失败 [%s]: %v", cfg.Name, err)
+			return
+		}
+		entry.tools = tools
+		entry.fetchedAt = time.Now()
+		log.Debug("工具目录已刷新 [%s]: %d 个工具", cfg.Name, len(tools))
+	}()
+	return loading, false
+}
+
+// refreshAll 后台刷新所有已配置服务器的工具目录
+func (m *Manager) refreshAll() {
+	m.mu.RLock()
+	ids := make([]string, 0, len(m.configs))
+	for id := range m.configs {
+		ids = append(ids, id)
+	}
+	m.mu.RUnlock()
+	for _, id := range ids {
+		m.startRefresh(id)
+	}
+}
+
+// catalogRefreshLoop 定时刷新工具目录，生命周期跟随主 context
+func (m *Manager) catalogRefreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(ToolCatalogTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refreshAll()
+		}
+	}
+}
+
+// cachedTools 读取缓存的工具目录，返回副本、是否过期和最近一次错误
+// 过期（或最近一次刷新失败）时顺带发起后台刷新；从未成功拉取时 ok 为 false
+func (m *Manager) cachedTools(serverID string) (tools []ToolInfo, stale bool, ok bool, err error) {
+	c := m.catalog
+	c.mu.Lock()
+	entry := c.entries[serverID]
+	if entry == nil || entry.fetchedAt.IsZero() {
+		if entry != nil {
+			err = entry.err
+		}
+		c.mu.Unlock()
+		return nil, false, false, err
+	}
+	stale = entry.err != nil || time.Since(entry.fetchedAt) > ToolCatalogTTL
+	retry := stale && time.Since(entry.attemptedAt) > catalogRetryInterval
+	tools = make([]ToolInfo, len(entry.tools))
+	copy(tools, entry.tools)
+	c.mu.Unlock()
+
+	if retry {
+		m.startRefresh(serverID)
+	}
+	if stale {
+		for i := range tools {
+			tools[i].Stale = true
+		}
+	}
+	return tools, stale, true, nil
+}
+
+// GetServerTools 获取指定 MCP 服务器的工具列表（优先使用缓存）
+// 缓存过期时返回旧数据并标记 Stale，同时后台刷新；从未拉取过时等待首次拉取完成
+func (m *Manager) GetServerTools(serverID string) ([]ToolInfo, error) {
+	m.mu.RLock()
+	_, configured := m.configs[serverID]
+	m.mu.RUnlock()
+	if !configured {
+		return nil, nil
+	}
+
+	if tools, _, ok, _ := m.cachedTools(serverID); ok {
+		return tools, nil
+	}
+	done, _ := m.startRefresh(serverID)
+	<-done
+	tools, _, ok, err := m.cachedTools(serverID)
+	if !ok {
+		if err == nil {
+			err = fmt.Errorf("工具目录不可用: %s", serverID)
+		}
+		return nil, err
+	}
+	return tools, nil
+}
+
+// RefreshToolCatalog 立即重新拉取工具目录并等待完成，serverID 为空时刷新全部
+func (m *Manager) RefreshToolCatalog(serverID string) error {
+	m.mu.RLock()
+	var ids []string
+	if serverID == "" {
+		for id := range m.configs {
+			ids = append(ids, id)
+		}
+	} else if _, ok := m.configs[serverID]; ok {
+		ids = []string{serverID}
+	}
+	m.mu.RUnlock()
+	if serverID != "" && len(ids) == 0 {
+		return fmt.Errorf("服务器未配置: %s", serverID)
+	}
+
+	var firstErr error
+	for _, id := range ids {
+		done, joined := m.startRefresh(id)
+		<-done
+		if joined {
+			// 进行中的拉取早于本次请求发起，结束后再拉一次
+			done, _ = m.startRefresh(id)
+			<-done
+		}
+		m.catalog.mu.Lock()
+		if entry := m.catalog.entries[id]; entry != nil && entry.err != nil && firstErr == nil {
+			firstErr = entry.err
+		}
+		m.catalog.mu.Unlock()
+	}
+	return firstErr
+}
+
+// GetToolInfosByServerIDs 根据服务器 ID 列表获取工具信息（并发读取缓存，结果按传入顺序）
+func (m *Manager) GetToolInfosByServerIDs(serverIDs []string) []ToolInfo {
+	results := make([][]ToolInfo, len(serverIDs))
+	var wg sync.WaitGroup
+	for i, id := range serverIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tools, err := m.GetServerTools(id)
+			if err != nil {
+				log.Error("获取服务器工具失败 [%s]: %v", id, err)
+				return
+			}
+			results[i] = tools
+		}()
+	}
+	wg.Wait()
+
+	var allTools []ToolInfo
+	for _, tools := range results {
+		allTools = append(allTools, tools...)
+	}
+	log.Info("共获取 %d 个工具, 服务器IDs: %v", len(allTools), serverIDs)
+	return allTools
+}
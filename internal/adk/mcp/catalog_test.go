@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// newCatalogManager 创建使用假 ListTools 的管理器（未 Initialize，不创建 toolset）
+func newCatalogManager(t *testing.T, list func(ctx context.Context, cfg *models.MCPServerConfig) ([]ToolInfo, error)) *Manager {
+	t.Helper()
+	m := NewManager()
+	m.listTools = list
+	if err := m.LoadConfigs([]models.MCPServerConfig{
+		{ID: "a", Name: "A", Enabled: true},
+		{ID: "b", Name: "B", Enabled: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestToolCatalog_CachesAndServesStale(t *testing.T) {
+	var calls atomic.Int32
+	m := newCatalogManager(t, func(_ context.Context, cfg *models.MCPServerConfig) ([]ToolInfo, error) {
+		calls.Add(1)
+		return []ToolInfo{{Name: "search", ServerID: cfg.ID, ServerName: cfg.Name}}, nil
+	})
+
+	for range 3 {
+		tools, err := m.GetServerTools("a")
+		if err != nil || len(tools) != 1 || tools[0].Stale {
+			t.Fatalf("tools = %+v, err = %v", tools, err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("ListTools 调用 %d 次，应只拉取一次", n)
+	}
+
+	// 过期后立即返回旧数据并后台刷新
+	m.catalog.mu.Lock()
+	m.catalog.entries["a"].fetchedAt = time.Now().Add(-2 * ToolCatalogTTL)
+	m.catalog.entries["a"].attemptedAt = m.catalog.entries["a"].fetchedAt
+	m.catalog.mu.Unlock()
+	tools, _ := m.GetServerTools("a")
+	if len(tools) != 1 || !tools[0].Stale {
+		t.Fatalf("过期缓存应标记 stale: %+v", tools)
+	}
+	if err := m.RefreshToolCatalog("a"); err != nil {
+		t.Fatal(err)
+	}
+	if tools, _ := m.GetServerTools("a"); len(tools) != 1 || tools[0].Stale {
+		t.Fatalf("刷新后应为新数据: %+v", tools)
+	}
+}
+
+func TestToolCatalog_KeepsOldToolsOnRefreshError(t *testing.T) {
+	var fail atomic.Bool
+	m := newCatalogManager(t, func(_ context.Context, cfg *models.MCPServerConfig) ([]ToolInfo, error) {
+		if fail.Load() {
+			return nil, errors.New("connection refused")
+		}
+		return []ToolInfo{{Name: "quote", ServerID: cfg.ID}}, nil
+	})
+	if _, err := m.GetServerTools("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	fail.Store(true)
+	if err := m.RefreshToolCatalog("a"); err == nil {
+		t.Fatal("刷新失败应返回错误")
+	}
+	tools, err := m.GetServerTools("a")
+	if err != nil || len(tools) != 1 || !tools[0].Stale {
+		t.Fatalf("刷新失败应保留旧数据并标记 stale: %+v, %v", tools, err)
+	}
+
+	// 从未成功拉取的服务器返回错误
+	if _, err := m.GetServerTools("b"); err == nil {
+		t.Fatal("首次拉取失败应返回错误")
+	}
+	if tools, err := m.GetServerTools("missing"); tools != nil || err != nil {
+		t.Fatalf("未配置的服务器: %v, %v", tools, err)
+	}
+}
+
+func TestToolCatalog_ConcurrentServersAndReload(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	started := map[string]int{}
+	m := newCatalogManager(t, func(_ context.Context, cfg *models.MCPServerConfig) ([]ToolInfo, error) {
+		mu.Lock()
+		started[cfg.ID]++
+		mu.Unlock()
+		<-release
+		return []ToolInfo{{Name: "t-" + cfg.ID, ServerID: cfg.ID}}, nil
+	})
+
+	done := make(chan []ToolInfo)
+	go func() { done <- m.GetToolInfosByServerIDs([]string{"b", "a"}) }()
+	// 两个服务器并发拉取，互不等待
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(started)
+		mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("服务器未并发拉取")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	tools := <-done
+	if len(tools) != 2 || tools[0].ServerID != "b" || tools[1].ServerID != "a" {
+		t.Fatalf("tools = %+v", tools)
+	}
+
+	// 重新加载配置后缓存失效
+	if err := m.LoadConfigs([]models.MCPServerConfig{{ID: "a", Name: "A2", Enabled: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if tools, err := m.GetServerTools("a"); err != nil || len(tools) != 1 {
+		t.Fatalf("tools = %+v, err = %v", tools, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if started["a"] != 2 {
+		t.Fatalf("配置变更后应重新拉取, started = %v", started)
+	}
+	if tools, _ := m.GetServerTools("b"); tools != nil {
+		t.Fatalf("已移除的服务器不应有缓存: %+v", tools)
+	}
+}
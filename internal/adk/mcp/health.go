@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// HealthCheckInterval 后台健康检查间隔
+const HealthCheckInterval = 30 * time.Second
+
+// 重连退避参数：连续失败后检查间隔从 HealthCheckInterval 起翻倍，直到上限
+const (
+	reconnectMaxDelay     = 5 * time.Minute
+	defaultStaleTolerance = 5 * time.Minute // 未配置 StaleToleranceSeconds 时的默认值
+	healthPingTimeout     = 5 * time.Second
+)
+
+// serverHealth 单个服务器的连接健康状态
+type serverHealth struct {
+	lastSuccess time.Time // 最近一次连接成功时间
+	lastError   error     // 最近一次失败的错误，成功后清空
+	failures    int       // 连续失败次数
+	nextAttempt time.Time // 退避期结束时间，之前不再尝试重连
+}
+
+// connected 最近一次检查是否成功
+func (h *serverHealth) connected() bool {
+	return h != nil && !h.lastSuccess.IsZero() && h.lastError == nil
+}
+
+// reconnectBackoff 第 n 次连续失败后的等待时间
+func reconnectBackoff(failures int) time.Duration {
+	d := HealthCheckInterval
+	for i := 1; i < failures && d < reconnectMaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, reconnectMaxDelay)
+}
+
+// staleTolerance 连接失败后仍可使用旧 toolset 的时长
+func staleTolerance(cfg *models.MCPServerConfig) time.Duration {
+	if cfg.StaleToleranceSeconds > 0 {
+		return time.Duration(cfg.StaleToleranceSeconds) * time.Second
+	}
+	return defaultStaleTolerance
+}
+
+// pingServer 建立一次会话并发送 ping
+func pingServer(ctx context.Context, cfg *models.MCPServerConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, healthPingTimeout)
+	defer cancel()
+
+	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
+	client := mcp.NewClient(impl, nil)
+	session, err := client.Connect(ctx, createTransport(cfg), nil)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	return session.Ping(ctx, nil)
+}
+
+// healthLocked 返回服务器的健康记录，不存在时创建（调用方需持有锁）
+func (m *Manager) healthLocked(id string) *serverHealth {
+	h := m.health[id]
+	if h == nil {
+		h = &serverHealth{}
+		m.health[id] = h
+	}
+	return h
+}
+
+// recordSuccessLocked 记录连接成功并清除退避（调用方需持有锁）
+func (m *Manager) recordSuccessLocked(id string) {
+	h := m.healthLocked(id)
+	if h.failures > 0 {
+		log.Info("MCP 服务器已恢复 [%s]，此前连续失败 %d 次", id, h.failures)
+	}
+	h.lastSuccess = time.Now()
+	h.lastError = nil
+	h.failures = 0
+	h.nextAttempt = time.Time{}
+}
+
+// recordFailureLocked 记录连接失败并按指数退避推迟下次重连（调用方需持有锁）
+func (m *Manager) recordFailureLocked(id string, err error) {
+	h := m.healthLocked(id)
+	h.lastError = err
+	h.failures++
+	delay := reconnectBackoff(h.failures)
+	h.nextAttempt = time.Now().Add(delay)
+	log.Warn("MCP 服务器连接失败 [%s]（连续 %d 次），%s 后重试: %v", id, h.failures, delay, err)
+}
+
+// canAttemptLocked 是否已过退避期（调用方需持有锁）
+func (m *Manager) canAttemptLocked(id string) bool {
+	h := m.health[id]
+	return h == nil || !time.Now().Before(h.nextAttempt)
+}
+
+// startHealthLoopLocked 重启后台健康检查，生命周期跟随主 context（调用方需持有锁）
+func (m *Manager) startHealthLoopLocked() {
+	if m.ctx == nil {
+		return
+	}
+	if m.stopHealth != nil {
+		m.stopHealth()
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.stopHealth = cancel
+	go m.healthLoop(ctx)
+}
+
+// healthLoop 立即检查一次，之后每 HealthCheckInterval 检查一次
+func (m *Manager) healthLoop(ctx context.Context) {
+	ticker := time.NewTicker(HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		m.checkHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkHealth ping 所有已过退避期的服务器；失败的服务器恢复后重建 toolset
+func (m *Manager) checkHealth(ctx context.Context) {
+	m.mu.RLock()
+	var due []*models.MCPServerConfig
+	for id, cfg := range m.configs {
+		if m.canAttemptLocked(id) {
+			due = append(due, cfg)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, cfg := range due {
+		err := m.ping(ctx, cfg)
+		if ctx.Err() != nil {
+			return
+		}
+
+		m.mu.Lock()
+		if m.configs[cfg.ID] != cfg {
+			// 检查期间配置已变更
+			m.mu.Unlock()
+			continue
+		}
+		if err != nil {
+			m.recordFailureLocked(cfg.ID, err)
+			m.mu.Unlock()
+			continue
+		}
+		recovered := m.health[cfg.ID] != nil && m.health[cfg.ID].failures > 0
+		m.recordSuccessLocked(cfg.ID)
+		if _, cached := m.toolsets[cfg.ID]; recovered || !cached {
+			if ts, err := m.createToolsetLocked(cfg); err == nil {
+				m.toolsets[cfg.ID] = ts
+			}
+		}
+		m.mu.Unlock()
+	}
+}
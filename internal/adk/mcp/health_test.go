@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestReconnectBackoff(t *testing.T) {
+	want := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		4:  4 * time.Minute,
+		5:  5 * time.Minute,
+		20: 5 * time.Minute,
+	}
+	for n, d := range want {
+		if got := reconnectBackoff(n); got != d {
+			t.Errorf("reconnectBackoff(%d) = %s, want %s", n, got, d)
+		}
+	}
+}
+
+func TestHealthCheck_StaleToolsetAndRecovery(t *testing.T) {
+	var down atomic.Bool
+	var pings atomic.Int32
+	m := NewManager()
+	m.ping = func(_ context.Context, _ *models.MCPServerConfig) error {
+		pings.Add(1)
+		if down.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	if err := m.LoadConfigs([]models.MCPServerConfig{
+		{ID: "a", Name: "A", Enabled: true, StaleToleranceSeconds: 60},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	m.checkHealth(ctx)
+	if st := m.GetAllStatus(); len(st) != 1 || !st[0].Connected || st[0].LastSuccess == 0 {
+		t.Fatalf("status = %+v", st)
+	}
+	first := m.GetToolsetsByIDs([]string{"a"})
+	if len(first) != 1 {
+		t.Fatalf("toolsets = %d", len(first))
+	}
+
+	// 断开后进入退避期，容忍期内继续返回旧 toolset
+	down.Store(true)
+	m.checkHealth(ctx)
+	st := m.GetAllStatus()[0]
+	if st.Connected || st.Failures != 1 || st.Error == "" {
+		t.Fatalf("status = %+v", st)
+	}
+	if got := m.GetToolsetsByIDs([]string{"a"}); len(got) != 1 || got[0] != first[0] {
+		t.Fatal("容忍期内应返回旧 toolset")
+	}
+
+	// 退避期内不再 ping
+	before := pings.Load()
+	m.checkHealth(ctx)
+	if pings.Load() != before {
+		t.Fatal("退避期内不应重试")
+	}
+
+	// 超过容忍期后不再返回
+	m.mu.Lock()
+	m.health["a"].lastSuccess = time.Now().Add(-2 * time.Minute)
+	m.mu.Unlock()
+	if got := m.GetToolsetsByIDs([]string{"a"}); len(got) != 0 {
+		t.Fatal("超过容忍期不应返回旧 toolset")
+	}
+
+	// 退避期结束后恢复，重建 toolset 并清除失败计数
+	down.Store(false)
+	m.mu.Lock()
+	m.health["a"].nextAttempt = time.Time{}
+	m.mu.Unlock()
+	m.checkHealth(ctx)
+	st = m.GetAllStatus()[0]
+	if !st.Connected || st.Failures != 0 || st.Error != "" {
+		t.Fatalf("status = %+v", st)
+	}
+	if got := m.GetToolsetsByIDs([]string{"a"}); len(got) != 1 || got[0] == first[0] {
+		t.Fatal("恢复后应使用重建的 toolset")
+	}
+}
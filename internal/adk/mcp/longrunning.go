@@ -0,0 +1,294 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// DefaultLongRunningTimeout 长耗时工具未配置 LongRunningTimeoutSeconds 时的单次调用超时
+const DefaultLongRunningTimeout = 10 * time.Minute
+
+// ToolCancelledOutput 用户取消工具调用后返回给模型的结果
+const ToolCancelledOutput = "用户取消"
+
+// errToolCallCancelled 用户主动取消工具调用
+var errToolCallCancelled = errors.New("tool call cancelled by user")
+
+// ToolProgress MCP 工具调用进度（来自服务器的 progress 通知）
+type ToolProgress struct {
+	RequestID string  `json:"requestId"` // 工具调用 ID，可用于 CancelToolCall
+	Server    string  `json:"server"`
+	Tool      string  `json:"tool"`
+	Message   string  `json:"message"`
+	Progress  float64 `json:"progress"`
+	Total     float64 `json:"total"` // 0 表示服务器未给出总量
+}
+
+// Percent 进度百分比，总量未知时返回 0
+func (p ToolProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(p.Progress/p.Total*100, 100)
+}
+
+// CallHooks 工具调用回调，通过 context 传入（同一 context 下的所有 MCP 工具调用共用）
+type CallHooks struct {
+	// OnLongRunning 长耗时工具开始调用，timeout 为该调用的超时
+	OnLongRunning func(requestID, toolName string, timeout time.Duration)
+	// OnProgress 收到服务器的进度通知
+	OnProgress func(ToolProgress)
+}
+
+type callHooksKey struct{}
+
+// WithCallHooks 为 context 下的 MCP 工具调用设置回调
+func WithCallHooks(ctx context.Context, hooks *CallHooks) context.Context {
+	return context.WithValue(ctx, callHooksKey{}, hooks)
+}
+
+func callHooksFrom(ctx context.Context) *CallHooks {
+	hooks, _ := ctx.Value(callHooksKey{}).(*CallHooks)
+	return hooks
+}
+
+// progressTokenKey 工具调用 context 中的进度 token，由发送中间件写入 tools/call 请求
+type progressTokenKey struct{}
+
+// activeCall 进行中的工具调用
+type activeCall struct {
+	server string
+	tool   string
+	hooks  *CallHooks
+	cancel context.CancelCauseFunc
+}
+
+// newClient 创建 MCP 客户端：转发进度通知，并为工具调用附加进度 token
+func (m *Manager) newClient(cfg *models.MCPServerConfig) *mcp.Client {
+	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
+	client := mcp.NewClient(impl, &mcp.ClientOptions{ProgressNotificationHandler: m.handleProgress})
+	client.AddSendingMiddleware(progressTokenMiddleware)
+	return client
+}
+
+// progressTokenMiddleware 为 tools/call 请求附加 context 中的进度 token
+func progressTokenMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if token, ok := ctx.Value(progressTokenKey{}).(string); ok && method == "tools/call" {
+			if params, ok := req.GetParams().(*mcp.CallToolParams); ok {
+				// SetProgressToken 在 Meta 为 nil 时不会写回，需先初始化
+				if params.Meta == nil {
+					params.Meta = mcp.Meta{}
+				}
+				params.SetProgressToken(token)
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// handleProgress 将进度通知转发给对应调用的回调
+func (m *Manager) handleProgress(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+	token, ok := req.Params.ProgressToken.(string)
+	if !ok {
+		return
+	}
+	m.callsMu.Lock()
+	call := m.calls[token]
+	m.callsMu.Unlock()
+	if call == nil || call.hooks == nil || call.hooks.OnProgress == nil {
+		return
+	}
+	call.hooks.OnProgress(ToolProgress{
+		RequestID: token,
+		Server:    call.server,
+		Tool:      call.tool,
+		Message:   req.Params.Message,
+		Progress:  req.Params.Progress,
+		Total:     req.Params.Total,
+	})
+}
+
+// CancelToolCall 取消进行中的工具调用，向服务器发送取消通知，模型收到 ToolCancelledOutput
+// 调用不存在或已结束时返回 false
+func (m *Manager) CancelToolCall(requestID string) bool {
+	m.callsMu.Lock()
+	call := m.calls[requestID]
+	m.callsMu.Unlock()
+	if call == nil {
+		return false
+	}
+	log.Info("用户取消工具调用 [%s] %s: %s", call.server, call.tool, requestID)
+	call.cancel(errToolCallCancelled)
+	return true
+}
+
+func (m *Manager) startCall(requestID string, call *activeCall) {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
+	m.calls[requestID] = call
+}
+
+func (m *Manager) endCall(requestID string) {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
+	delete(m.calls, requestID)
+}
+
+// longRunningTimeout 长耗时工具的单次调用超时
+func longRunningTimeout(cfg *models.MCPServerConfig) time.Duration {
+	if cfg.LongRunningTimeoutSeconds > 0 {
+		return time.Duration(cfg.LongRunningTimeoutSeconds) * time.Second
+	}
+	return DefaultLongRunningTimeout
+}
+
+// callToolset 包装 mcptoolset，使工具调用可跟踪进度和单独取消
+type callToolset struct {
+	tool.Toolset
+	m   *Manager
+	cfg *models.MCPServerConfig
+}
+
+// runnableTool mcptoolset 返回的工具实现的接口
+type runnableTool interface {
+	tool.Tool
+	Declaration() *genai.FunctionDeclaration
+	Run(ctx tool.Context, args any) (map[string]any, error)
+}
+
+func (ts *callToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := ts.Toolset.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]tool.Tool, 0, len(tools))
+	for _, t := range tools {
+		rt, ok := t.(runnableTool)
+		if !ok {
+			wrapped = append(wrapped, t)
+			continue
+		}
+		wrapped = append(wrapped, &callTool{
+			runnableTool: rt,
+			m:            ts.m,
+			server:       ts.cfg.Name,
+			longRunning:  rt.IsLongRunning() || slices.Contains(ts.cfg.LongRunningTools, rt.Name()),
+			timeout:      longRunningTimeout(ts.cfg),
+		})
+	}
+	return wrapped, nil
+}
+
+// callTool 单个 MCP 工具：登记进行中的调用，长耗时工具使用独立超时
+type callTool struct {
+	runnableTool
+	m           *Manager
+	server      string
+	longRunning bool
+	timeout     time.Duration
+}
+
+// IsLongRunning 始终返回 false：ADK 会把长耗时工具当作异步调用并立即结束本轮，这里同步等待结果
+func (t *callTool) IsLongRunning() bool {
+	return false
+}
+
+func (t *callTool) ProcessRequest(_ tool.Context, req *model.LLMRequest) error {
+	return packTool(req, t)
+}
+
+func (t *callTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	requestID := ctx.FunctionCallID()
+	if requestID == "" {
+		requestID = fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+	}
+	hooks := callHooksFrom(ctx)
+
+	// 先通知调用方延长外层超时，再创建本次调用的超时
+	if t.longRunning && hooks != nil && hooks.OnLongRunning != nil {
+		hooks.OnLongRunning(requestID, t.Name(), t.timeout)
+	}
+	callCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if t.longRunning {
+		var stop context.CancelFunc
+		callCtx, stop = context.WithTimeout(callCtx, t.timeout)
+		defer stop()
+	}
+	callCtx = context.WithValue(callCtx, progressTokenKey{}, requestID)
+
+	t.m.startCall(requestID, &activeCall{server: t.server, tool: t.Name(), hooks: hooks, cancel: cancel})
+	defer t.m.endCall(requestID)
+
+	// 与内置工具一致：会议取消或调用超时时立即返回，不等待不响应取消的 MCP 服务
+	type runResult struct {
+		out map[string]any
+		err error
+	}
+	done := make(chan runResult, 1)
+	go func() {
+		out, err := t.runnableTool.Run(toolCallContext{Context: ctx, ctx: callCtx}, args)
+		done <- runResult{out, err}
+	}()
+	var res runResult
+	select {
+	case res = <-done:
+	case <-callCtx.Done():
+		res.err = callCtx.Err()
+	}
+	if errors.Is(context.Cause(callCtx), errToolCallCancelled) {
+		return map[string]any{"output": ToolCancelledOutput}, nil
+	}
+	return res.out, res.err
+}
+
+// toolCallContext 使用独立 context（取消、超时、进度 token）的 tool.Context
+type toolCallContext struct {
+	tool.Context
+	ctx context.Context
+}
+
+func (c toolCallContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c toolCallContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c toolCallContext) Err() error                  { return c.ctx.Err() }
+func (c toolCallContext) Value(key any) any           { return c.ctx.Value(key) }
+
+// packTool 将工具声明加入请求（同 ADK 内部的 toolutils.PackTool）
+func packTool(req *model.LLMRequest, t runnableTool) error {
+	if req.Tools == nil {
+		req.Tools = make(map[string]any)
+	}
+	name := t.Name()
+	if _, ok := req.Tools[name]; ok {
+		return fmt.Errorf("duplicate tool: %q", name)
+	}
+	req.Tools[name] = t
+
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	decl := t.Declaration()
+	if decl == nil {
+		return nil
+	}
+	for _, gt := range req.Config.Tools {
+		if gt != nil && gt.FunctionDeclarations != nil {
+			gt.FunctionDeclarations = append(gt.FunctionDeclarations, decl)
+			return nil
+		}
+	}
+	req.Config.Tools = append(req.Config.Tools, &genai.Tool{FunctionDeclarations: []*genai.FunctionDeclaration{decl}})
+	return nil
+}
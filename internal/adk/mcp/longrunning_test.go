@@ -0,0 +1,217 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+// fakeReadonlyContext 仅提供 context 能力的 agent.ReadonlyContext
+type fakeReadonlyContext struct {
+	agent.ReadonlyContext
+	ctx context.Context
+}
+
+func (c fakeReadonlyContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c fakeReadonlyContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c fakeReadonlyContext) Err() error                  { return c.ctx.Err() }
+func (c fakeReadonlyContext) Value(key any) any           { return c.ctx.Value(key) }
+
+// fakeToolContext 仅提供 context 能力和调用 ID 的 tool.Context
+type fakeToolContext struct {
+	tool.Context
+	ctx context.Context
+	id  string
+}
+
+func (c fakeToolContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c fakeToolContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c fakeToolContext) Err() error                  { return c.ctx.Err() }
+func (c fakeToolContext) Value(key any) any           { return c.ctx.Value(key) }
+func (c fakeToolContext) FunctionCallID() string      { return c.id }
+
+// fakeBacktestServer 内存 MCP 服务器：backtest 工具发送 3 次进度通知，之后等待 release 或被取消
+func fakeBacktestServer(t *testing.T, release <-chan struct{}, cancelled chan<- struct{}) mcp.Transport {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "backtest", InputSchema: &jsonschema.Schema{Type: "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			token := req.Params.GetProgressToken()
+			for i := 1; i <= 3; i++ {
+				req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+					ProgressToken: token,
+					Progress:      float64(i),
+					Total:         4,
+					Message:       fmt.Sprintf("回测第 %d 年", i),
+				})
+			}
+			select {
+			case <-release:
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "年化收益 12%"}}}, nil
+			case <-ctx.Done():
+				close(cancelled)
+				return nil, ctx.Err()
+			}
+		})
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ss.Close() })
+	return clientTransport
+}
+
+// backtestTool 通过 Manager 创建 toolset 并取出 backtest 工具
+func backtestTool(t *testing.T, m *Manager, transport mcp.Transport) tool.Tool {
+	t.Helper()
+	m.transport = func(*models.MCPServerConfig) mcp.Transport { return transport }
+	cfg := &models.MCPServerConfig{ID: "bt", Name: "回测", LongRunningTools: []string{"backtest"}, LongRunningTimeoutSeconds: 60}
+	ts, err := m.CreateToolset(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools, err := ts.Tools(fakeReadonlyContext{ctx: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 1 {
+		t.Fatalf("tools = %d, want 1", len(tools))
+	}
+	if tools[0].IsLongRunning() {
+		t.Fatal("包装后的工具不应向 ADK 报告 IsLongRunning")
+	}
+	return tools[0]
+}
+
+type runResult struct {
+	out map[string]any
+	err error
+}
+
+func runTool(ctx context.Context, tl tool.Tool, id string) <-chan runResult {
+	ch := make(chan runResult, 1)
+	go func() {
+		out, err := tl.(runnableTool).Run(fakeToolContext{ctx: ctx, id: id}, map[string]any{})
+		ch <- runResult{out, err}
+	}()
+	return ch
+}
+
+func TestLongRunningTool_ForwardsProgress(t *testing.T) {
+	release := make(chan struct{})
+	m := NewManager()
+	tl := backtestTool(t, m, fakeBacktestServer(t, release, make(chan struct{})))
+
+	progress := make(chan ToolProgress, 10)
+	var timeout time.Duration
+	ctx := WithCallHooks(context.Background(), &CallHooks{
+		OnLongRunning: func(_, _ string, d time.Duration) { timeout = d },
+		OnProgress:    func(p ToolProgress) { progress <- p },
+	})
+	done := runTool(ctx, tl, "call-1")
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case p := <-progress:
+			if p.RequestID != "call-1" || p.Tool != "backtest" || p.Server != "回测" {
+				t.Fatalf("progress = %+v", p)
+			}
+			if p.Percent() != float64(i)*25 || p.Message != fmt.Sprintf("回测第 %d 年", i) {
+				t.Fatalf("progress %d = %+v (%.0f%%)", i, p, p.Percent())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("未收到第 %d 次进度", i)
+		}
+	}
+	if timeout != time.Minute {
+		t.Fatalf("long-running timeout = %s, want 1m", timeout)
+	}
+	close(release)
+
+	res := <-done
+	if res.err != nil || res.out["output"] != "年化收益 12%" {
+		t.Fatalf("Run = %v, %v", res.out, res.err)
+	}
+	if m.CancelToolCall("call-1") {
+		t.Fatal("已结束的调用不应可取消")
+	}
+}
+
+func TestLongRunningTool_CancelToolCall(t *testing.T) {
+	cancelled := make(chan struct{})
+	m := NewManager()
+	tl := backtestTool(t, m, fakeBacktestServer(t, make(chan struct{}), cancelled))
+
+	progress := make(chan ToolProgress, 10)
+	ctx := WithCallHooks(context.Background(), &CallHooks{OnProgress: func(p ToolProgress) { progress <- p }})
+	done := runTool(ctx, tl, "call-2")
+
+	select {
+	case <-progress:
+	case <-time.After(5 * time.Second):
+		t.Fatal("未收到进度")
+	}
+	if !m.CancelToolCall("call-2") {
+		t.Fatal("CancelToolCall 应找到进行中的调用")
+	}
+
+	res := <-done
+	if res.err != nil || res.out["output"] != ToolCancelledOutput {
+		t.Fatalf("Run = %v, %v, want %q", res.out, res.err, ToolCancelledOutput)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("服务器未收到取消通知")
+	}
+}
+
+func TestCallTool_ReturnsOnMeetingCancel(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake", Version: "1.0.0"}, nil)
+	release := make(chan struct{})
+	server.AddTool(&mcp.Tool{Name: "slow", InputSchema: &jsonschema.Schema{Type: "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			<-release // 模拟不响应取消的 MCP 服务
+			return &mcp.CallToolResult{}, nil
+		})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	defer close(release) // 先放行处理函数，ss.Close 会等待其返回
+
+	m := NewManager()
+	m.transport = func(*models.MCPServerConfig) mcp.Transport { return clientTransport }
+	ts, err := m.CreateToolset(&models.MCPServerConfig{ID: "slow", Name: "慢服务"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools, err := ts.Tools(fakeReadonlyContext{ctx: context.Background()})
+	if err != nil || len(tools) != 1 {
+		t.Fatalf("tools = %v, %v", tools, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	res := <-runTool(ctx, tools[0], "call-3")
+	if !errors.Is(res.err, context.Canceled) {
+		t.Fatalf("Run = %v, %v, want context.Canceled", res.out, res.err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("取消后 %v 才返回", d)
+	}
+}
@@ -4,6 +4,7 @@ package mcp
 
 import (
 	"context"
+	"fmt"
 	"os/exec"
 	"sync"
 	"time"
@@ -18,11 +19,13 @@ import (
 
 var log = logger.New("mcp")
 
-// ServerStatus MCP 服务器状态
+// ServerStatus MCP 服务器状态（来自后台健康检查）
 type ServerStatus struct {
-	ID        string `json:"id"`
-	Connected bool   `json:"connected"`
-	Error     string `json:"error"`
+	ID          string `json:"id"`
+	Connected   bool   `json:"connected"`
+	Error       string `json:"error"`
+	LastSuccess int64  `json:"lastSuccess"` // 最近一次连接成功时间（毫秒），0 表示从未成功
+	Failures    int    `json:"failures"`    // 连续失败次数
 }
 
 // ToolInfo MCP 工具信息
@@ -31,6 +34,7 @@ type ToolInfo struct {
 	Description string `json:"description"`
 	ServerID    string `json:"serverId"`
 	ServerName  string `json:"serverName"`
+	Stale       bool   `json:"stale"` // 来自已过期的缓存，后台正在刷新
 }
 
 // Manager MCP 服务管理器
@@ -40,13 +44,31 @@ type Manager struct {
 	mu       sync.RWMutex
 	configs  map[string]*models.MCPServerConfig
 	toolsets map[string]tool.Toolset // 缓存已创建的 toolset
+
+	catalog   *toolCatalog // 工具目录缓存
+	listTools func(ctx context.Context, cfg *models.MCPServerConfig) ([]ToolInfo, error)
+
+	health     map[string]*serverHealth // 连接健康状态
+	ping       func(ctx context.Context, cfg *models.MCPServerConfig) error
+	stopHealth context.CancelFunc // 停止当前的后台健康检查
+
+	transport func(cfg *models.MCPServerConfig) mcp.Transport // 创建 toolset 使用的传输层
+
+	callsMu sync.Mutex
+	calls   map[string]*activeCall // 进行中的工具调用，按调用 ID 索引
 }
 
 // NewManager 创建 MCP 管理器（需要调用 Initialize 绑定 context）
 func NewManager() *Manager {
 	return &Manager{
-		configs:  make(map[string]*models.MCPServerConfig),
-		toolsets: make(map[string]tool.Toolset),
+		configs:   make(map[string]*models.MCPServerConfig),
+		toolsets:  make(map[string]tool.Toolset),
+		catalog:   newToolCatalog(),
+		listTools: listServerTools,
+		health:    make(map[string]*serverHealth),
+		ping:      pingServer,
+		transport: createTransport,
+		calls:     make(map[string]*activeCall),
 	}
 }
 
@@ -70,6 +92,11 @@ func (m *Manager) Initialize(ctx context.Context) error {
 		m.toolsets[id] = ts
 		log.Info("预初始化 toolset 成功: %s", cfg.Name)
 	}
+
+	// 预热工具目录并定时刷新（需在释放锁后执行）
+	go m.refreshAll()
+	go m.catalogRefreshLoop(ctx)
+	m.startHealthLoopLocked()
 	return nil
 }
 
@@ -81,6 +108,8 @@ func (m *Manager) LoadConfigs(configs []models.MCPServerConfig) error {
 	// 清空旧配置和缓存
 	m.configs = make(map[string]*models.MCPServerConfig)
 	m.toolsets = make(map[string]tool.Toolset)
+	m.health = make(map[string]*serverHealth)
+	m.catalog.reset()
 
 	for i := range configs {
 		cfg := &configs[i]
@@ -102,6 +131,8 @@ func (m *Manager) LoadConfigs(configs []models.MCPServerConfig) error {
 			m.toolsets[id] = ts
 			log.Info("初始化 toolset 成功: %s", cfg.Name)
 		}
+		go m.refreshAll()
+		m.startHealthLoopLocked()
 	}
 	return nil
 }
@@ -132,14 +163,15 @@ func (m *Manager) CreateToolset(cfg *models.MCPServerConfig) (tool.Toolset, erro
 // createToolsetLocked 内部方法，创建 toolset（调用方需持有锁）
 func (m *Manager) createToolsetLocked(cfg *models.MCPServerConfig) (tool.Toolset, error) {
 	ts, err := mcptoolset.New(mcptoolset.Config{
-		Transport: createTransport(cfg),
+		Client:    m.newClient(cfg),
+		Transport: m.transport(cfg),
 	})
 	if err != nil {
 		log.Error("创建 mcptoolset 失败 [%s]: %v", cfg.Name, err)
 		return nil, err
 	}
 	log.Debug("mcptoolset 已创建: %s", cfg.Name)
-	return ts, nil
+	return &callToolset{Toolset: ts, m: m, cfg: cfg}, nil
 }
 
 // GetToolsetsByIDs 根据 ID 列表获取 toolsets（使用缓存）
@@ -150,26 +182,16 @@ func (m *Manager) GetToolsetsByIDs(ids []string) []tool.Toolset {
 	log.Info("请求获取 toolsets, IDs: %v", ids)
 	var result []tool.Toolset
 	for _, id := range ids {
-		// 先检查缓存
-		if ts, ok := m.toolsets[id]; ok {
-			log.Debug("使用缓存的 toolset: %s", id)
-			result = append(result, ts)
-			continue
-		}
-
-		// 缓存未命中，创建新的
 		cfg, ok := m.configs[id]
 		if !ok {
 			log.Warn("MCP 配置不存在: %s", id)
 			continue
 		}
-		ts, err := m.createToolsetLocked(cfg)
+		ts, err := m.toolsetLocked(cfg)
 		if err != nil {
-			log.Error("创建 toolset 失败 [%s]: %v", id, err)
+			log.Error("获取 toolset 失败 [%s]: %v", id, err)
 			continue
 		}
-		// 存入缓存
-		m.toolsets[id] = ts
 		result = append(result, ts)
 	}
 	log.Info("返回 toolsets 数量: %d", len(result))
@@ -182,23 +204,49 @@ func (m *Manager) GetAllToolsets() []tool.Toolset {
 	defer m.mu.Unlock()
 
 	var result []tool.Toolset
-	for id, cfg := range m.configs {
-		// 先检查缓存
-		if ts, ok := m.toolsets[id]; ok {
-			result = append(result, ts)
-			continue
-		}
-		// 创建并缓存
-		ts, err := m.createToolsetLocked(cfg)
+	for _, cfg := range m.configs {
+		ts, err := m.toolsetLocked(cfg)
 		if err != nil {
+			log.Error("获取 toolset 失败 [%s]: %v", cfg.ID, err)
 			continue
 		}
-		m.toolsets[id] = ts
 		result = append(result, ts)
 	}
 	return result
 }
 
+// toolsetLocked 获取服务器的 toolset（调用方需持有锁）
+// 连接正常时使用缓存；连接失败时在退避期外重建，重建失败或仍在退避期内时，
+// 最近一次成功在 StaleToleranceSeconds 内则继续返回旧 toolset
+func (m *Manager) toolsetLocked(cfg *models.MCPServerConfig) (tool.Toolset, error) {
+	id := cfg.ID
+	cached, ok := m.toolsets[id]
+	h := m.health[id]
+	if ok && (h == nil || h.lastError == nil) {
+		log.Debug("使用缓存的 toolset: %s", id)
+		return cached, nil
+	}
+
+	var err error
+	if m.canAttemptLocked(id) {
+		var ts tool.Toolset
+		if ts, err = m.createToolsetLocked(cfg); err == nil {
+			m.toolsets[id] = ts
+			return ts, nil
+		}
+		m.recordFailureLocked(id, err)
+		h = m.health[id]
+	} else {
+		err = fmt.Errorf("连接失败，%s 后重试: %w", time.Until(h.nextAttempt).Round(time.Second), h.lastError)
+	}
+
+	if ok && !h.lastSuccess.IsZero() && time.Since(h.lastSuccess) < staleTolerance(cfg) {
+		log.Warn("MCP 服务器不可用 [%s]，继续使用 %s 前可用的 toolset: %v", cfg.Name, time.Since(h.lastSuccess).Round(time.Second), err)
+		return cached, nil
+	}
+	return nil, err
+}
+
 // GetAllStatus 获取所有服务器状态
 func (m *Manager) GetAllStatus() []ServerStatus {
 	m.mu.RLock()
@@ -206,7 +254,18 @@ func (m *Manager) GetAllStatus() []ServerStatus {
 
 	result := make([]ServerStatus, 0, len(m.configs))
 	for id := range m.configs {
-		result = append(result, ServerStatus{ID: id})
+		status := ServerStatus{ID: id}
+		if h := m.health[id]; h != nil {
+			status.Connected = h.connected()
+			status.Failures = h.failures
+			if h.lastError != nil {
+				status.Error = h.lastError.Error()
+			}
+			if !h.lastSuccess.IsZero() {
+				status.LastSuccess = h.lastSuccess.UnixMilli()
+			}
+		}
+		result = append(result, status)
 	}
 	return result
 }
@@ -227,68 +286,22 @@ func (m *Manager) TestConnection(serverID string) *ServerStatus {
 
 	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
 	client := mcp.NewClient(impl, nil)
-	_, err := client.Connect(ctx, createTransport(cfg), nil)
+	session, err := client.Connect(ctx, createTransport(cfg), nil)
 
+	// 手动测试结果同步到健康状态
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if err != nil {
 		log.Error("测试连接失败 [%s]: %v", cfg.Name, err)
+		if m.configs[serverID] == cfg {
+			m.recordFailureLocked(serverID, err)
+		}
 		return &ServerStatus{ID: serverID, Connected: false, Error: err.Error()}
 	}
+	session.Close()
 	log.Info("测试连接成功: %s", cfg.Name)
-	return &ServerStatus{ID: serverID, Connected: true}
-}
-
-// GetServerTools 获取指定 MCP 服务器的工具列表
-func (m *Manager) GetServerTools(serverID string) ([]ToolInfo, error) {
-	m.mu.RLock()
-	cfg, ok := m.configs[serverID]
-	m.mu.RUnlock()
-
-	if !ok {
-		return nil, nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
-	client := mcp.NewClient(impl, nil)
-	session, err := client.Connect(ctx, createTransport(cfg), nil)
-	if err != nil {
-		return nil, err
-	}
-	defer session.Close()
-
-	toolsResp, err := session.ListTools(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var tools []ToolInfo
-	for _, t := range toolsResp.Tools {
-		tools = append(tools, ToolInfo{
-			Name:        t.Name,
-			Description: t.Description,
-			ServerID:    serverID,
-			ServerName:  cfg.Name,
-		})
-	}
-	return tools, nil
-}
-
-// GetToolInfosByServerIDs 根据服务器 ID 列表获取工具信息
-func (m *Manager) GetToolInfosByServerIDs(serverIDs []string) []ToolInfo {
-	log.Info("获取工具信息, 服务器IDs: %v", serverIDs)
-	var allTools []ToolInfo
-	for _, id := range serverIDs {
-		tools, err := m.GetServerTools(id)
-		if err != nil {
-			log.Error("获取服务器工具失败 [%s]: %v", id, err)
-			continue
-		}
-		if tools != nil {
-			allTools = append(allTools, tools...)
-		}
+	if m.configs[serverID] == cfg {
+		m.recordSuccessLocked(serverID)
 	}
-	log.Info("共获取 %d 个工具", len(allTools))
-	return allTools
+	return &ServerStatus{ID: serverID, Connected: true, LastSuccess: m.health[serverID].lastSuccess.UnixMilli()}
 }
@@ -0,0 +1,39 @@
+package adk
+
+import (
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// Mistral AI 默认配置
+const (
+	DefaultMistralBaseURL = "https://api.mistral.ai/v1"
+	MistralDefaultModel   = "mistral-large-latest"
+)
+
+// normalizeMistralBaseURL 规范化 Mistral 接口地址
+// 官方地址已含 /v1，去掉用户误填的接口后缀，缺少版本号时由 normalizeOpenAIBaseURL 补齐
+func normalizeMistralBaseURL(baseURL string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return DefaultMistralBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/chat/completions")
+	return normalizeOpenAIBaseURL(baseURL)
+}
+
+// mistralConfig 补全 Mistral 默认值，返回可交给 OpenAI 兼容实现的配置副本
+// 仅使用 chat/completions（支持 function calling），不支持 Responses API / Azure
+func mistralConfig(config *models.AIConfig) *models.AIConfig {
+	c := *config
+	c.BaseURL = normalizeMistralBaseURL(c.BaseURL)
+	if strings.TrimSpace(c.ModelName) == "" {
+		c.ModelName = MistralDefaultModel
+	}
+	c.UseResponses = false
+	c.IsAzure = false
+	// 原生支持 system role
+	c.NoSystemRole = false
+	return &c
+}
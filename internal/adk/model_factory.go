@@ -15,6 +15,8 @@ import (
 	"cloud.google.com/go/auth/credentials"
 	"cloud.google.com/go/auth/httptransport"
 	"github.com/run-bigpig/jcp/internal/adk/anthropic"
+	"github.com/run-bigpig/jcp/internal/adk/capability"
+	"github.com/run-bigpig/jcp/internal/adk/ollama"
 	"github.com/run-bigpig/jcp/internal/adk/openai"
 	"github.com/run-bigpig/jcp/internal/models"
 	"github.com/run-bigpig/jcp/internal/pkg/proxy"
@@ -35,13 +37,17 @@ type uaTransport struct {
 	base http.RoundTripper
 }
 
+// RoundTrip 不修改调用方的请求，克隆时保留请求 ctx 以便取消能传递到连接
 func (t *uaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
 	req.Header.Set("User-Agent", cherryStudioUA)
 	return t.base.RoundTrip(req)
 }
 
 // ModelFactory 模型工厂，根据配置创建对应的 adk model
-type ModelFactory struct{}
+type ModelFactory struct {
+	warm warmupState // 连接预热状态
+}
 
 // NewModelFactory 创建模型工厂
 func NewModelFactory() *ModelFactory {
@@ -49,12 +55,24 @@ func NewModelFactory() *ModelFactory {
 }
 
 // CreateModel 根据 AI 配置创建对应的模型
+// 返回的模型每次请求都带 RequestTimeout 超时，并按能力矩阵丢弃不支持的参数
 func (f *ModelFactory) CreateModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
+	llm, err := f.createModel(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return withRequestTimeout(withCapabilities(llm, config), RequestTimeout(config)), nil
+}
+
+// createModel 按 Provider 创建模型
+func (f *ModelFactory) createModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
 	switch config.Provider {
 	case models.AIProviderGemini:
 		return f.createGeminiModel(ctx, config)
 	case models.AIProviderVertexAI:
 		return f.createVertexAIModel(ctx, config)
+	case models.AIProviderVertexAIGarden:
+		return f.createVertexAIGardenModel(ctx, config)
 	case models.AIProviderOpenAI:
 		if config.UseResponses {
 			return f.createOpenAIResponsesModel(config)
@@ -62,6 +80,16 @@ func (f *ModelFactory) CreateModel(ctx context.Context, config *models.AIConfig)
 		return f.createOpenAIModel(config)
 	case models.AIProviderAnthropic:
 		return f.createAnthropicModel(config)
+	case models.AIProviderBedrock:
+		return f.createBedrockModel(config)
+	case models.AIProviderOllama:
+		return f.createOllamaModel(config)
+	case models.AIProviderDeepSeek:
+		return f.createDeepSeekModel(config)
+	case models.AIProviderQwen:
+		return f.createQwenModel(config)
+	case models.AIProviderMistral:
+		return f.createMistralModel(config)
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", config.Provider)
 	}
@@ -74,7 +102,7 @@ func (f *ModelFactory) createGeminiModel(ctx context.Context, config *models.AIC
 		Backend: genai.BackendGeminiAPI,
 		// 注入代理 Transport
 		HTTPClient: &http.Client{
-			Transport: &uaTransport{base: proxy.GetManager().GetTransport()},
+			Transport: &uaTransport{base: proxy.GetManager().SharedTransport()},
 		},
 	}
 
@@ -83,8 +111,49 @@ func (f *ModelFactory) createGeminiModel(ctx context.Context, config *models.AIC
 
 // createVertexAIModel 创建 Vertex AI 模型
 func (f *ModelFactory) createVertexAIModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
+	clientConfig, err := vertexAIClientConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return gemini.NewModel(ctx, config.ModelName, clientConfig)
+}
+
+// createVertexAIGardenModel 创建 Vertex AI Model Garden 上的第三方模型
+// 与 Gemini 同样走 generateContent，只是模型需使用带发布方的完整资源名
+func (f *ModelFactory) createVertexAIGardenModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
+	name, err := vertexAIGardenModelName(config)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig, err := vertexAIClientConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return gemini.NewModel(ctx, name, clientConfig)
+}
+
+// vertexAIGardenModelName 构建 Model Garden 模型的完整资源名
+// 格式：projects/{project}/locations/{location}/publishers/{publisher}/models/{model}
+func vertexAIGardenModelName(config *models.AIConfig) (string, error) {
+	if config.Project == "" || config.Location == "" {
+		return "", fmt.Errorf("Vertex AI Model Garden 需要配置项目 ID 和区域")
+	}
+	publisher := strings.Trim(config.Publisher, "/ ")
+	if publisher == "" {
+		return "", fmt.Errorf("Vertex AI Model Garden 需要配置模型发布方（如 meta、mistralai）")
+	}
+	// 兼容填写了 publishers/{publisher}/models/ 前缀的模型名
+	modelName := strings.TrimPrefix(strings.Trim(config.ModelName, "/ "), "publishers/"+publisher+"/models/")
+	if modelName == "" {
+		return "", fmt.Errorf("Vertex AI Model Garden 需要配置模型名称")
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/publishers/%s/models/%s", config.Project, config.Location, publisher, modelName), nil
+}
+
+// vertexAIClientConfig 创建 Vertex AI 客户端配置（服务账号或默认凭证鉴权）
+func vertexAIClientConfig(config *models.AIConfig) (*genai.ClientConfig, error) {
 	// 获取代理 Transport
-	uaRT := &uaTransport{base: proxy.GetManager().GetTransport()}
+	uaRT := &uaTransport{base: proxy.GetManager().SharedTransport()}
 
 	// 获取凭证
 	var creds *auth.Credentials
@@ -110,15 +179,13 @@ func (f *ModelFactory) createVertexAIModel(ctx context.Context, config *models.A
 		return nil, fmt.Errorf("failed to create authenticated HTTP client: %w", err)
 	}
 
-	clientConfig := &genai.ClientConfig{
+	return &genai.ClientConfig{
 		Backend:     genai.BackendVertexAI,
 		Project:     config.Project,
 		Location:    config.Location,
 		Credentials: creds,
 		HTTPClient:  httpClient,
-	}
-
-	return gemini.NewModel(ctx, config.ModelName, clientConfig)
+	}, nil
 }
 
 // normalizeOpenAIBaseURL 规范化 OpenAI BaseURL
@@ -138,12 +205,40 @@ func normalizeOpenAIBaseURL(baseURL string) string {
 func (f *ModelFactory) createOpenAIModel(config *models.AIConfig) (model.LLM, error) {
 	openaiCfg := go_openai.DefaultConfig(config.APIKey)
 	openaiCfg.BaseURL = normalizeOpenAIBaseURL(config.BaseURL)
+	if config.IsAzure {
+		// Azure：api-key 鉴权，部署名进入 URL 路径并追加 api-version
+		openaiCfg = go_openai.DefaultAzureConfig(config.APIKey, normalizeAzureBaseURL(config.BaseURL))
+		openaiCfg.APIVersion = azureAPIVersion(config)
+		deployment := azureDeployment(config)
+		openaiCfg.AzureModelMapperFunc = func(string) string { return deployment }
+	}
 	// 注入代理 Transport
 	openaiCfg.HTTPClient = &http.Client{
-		Transport: &uaTransport{base: proxy.GetManager().GetTransport()},
+		Transport: &uaTransport{base: proxy.GetManager().SharedTransport()},
 	}
 
-	return openai.NewOpenAIModel(config.ModelName, openaiCfg, config.NoSystemRole), nil
+	m := openai.NewOpenAIModel(config.ModelName, openaiCfg, config.NoSystemRole)
+	m.StrictTools = supportsStrictTools(config)
+	return m, nil
+}
+
+// supportsStrictTools 是否开启 strict function calling
+// 仅官方 OpenAI 与 Azure 支持，第三方兼容接口可能拒绝未知的 strict 字段
+func supportsStrictTools(config *models.AIConfig) bool {
+	if config.Provider != models.AIProviderOpenAI {
+		return false
+	}
+	if config.IsAzure {
+		return true
+	}
+	u, err := url.Parse(normalizeOpenAIBaseURL(config.BaseURL))
+	return err == nil && u.Host == "api.openai.com"
+}
+
+// SupportsImageInput 模型是否可接收图片输入
+// DeepSeek 官方接口仅支持文本；其余提供商取决于具体模型，不支持时由接口返回错误
+func SupportsImageInput(config *models.AIConfig) bool {
+	return config == nil || config.Provider != models.AIProviderDeepSeek
 }
 
 // normalizeAnthropicBaseURL 规范化 Anthropic BaseURL
@@ -160,9 +255,71 @@ func normalizeAnthropicBaseURL(baseURL string) string {
 func (f *ModelFactory) createAnthropicModel(config *models.AIConfig) (model.LLM, error) {
 	baseURL := normalizeAnthropicBaseURL(config.BaseURL)
 	httpClient := &http.Client{
-		Transport: &uaTransport{base: proxy.GetManager().GetTransport()},
+		Transport: &uaTransport{base: proxy.GetManager().SharedTransport()},
+	}
+	m := anthropic.NewAnthropicModel(config.ModelName, config.APIKey, baseURL, httpClient, config.NoSystemRole)
+	applyAnthropicOptions(m, config)
+	return m, nil
+}
+
+// applyAnthropicOptions 按能力矩阵应用扩展思考与提示缓存设置
+func applyAnthropicOptions(m *anthropic.AnthropicModel, config *models.AIConfig) {
+	caps := capability.Effective(config)
+	if config.ThinkingBudget > 0 && !capability.Drop(caps, capability.Thinking, config.ModelName) {
+		m.SetThinkingBudget(config.ThinkingBudget)
+	}
+	if config.EnablePromptCache && !capability.Drop(caps, capability.PromptCache, config.ModelName) {
+		m.SetPromptCache(true)
 	}
-	return anthropic.NewAnthropicModel(config.ModelName, config.APIKey, baseURL, httpClient, config.NoSystemRole), nil
+}
+
+// bedrockConfig 从 AI 配置提取 Bedrock 访问配置
+func bedrockConfig(config *models.AIConfig) anthropic.BedrockConfig {
+	return anthropic.BedrockConfig{
+		Region:          strings.TrimSpace(config.AWSRegion),
+		AccessKeyID:     strings.TrimSpace(config.AWSAccessKeyID),
+		SecretAccessKey: strings.TrimSpace(config.AWSSecretAccessKey),
+		SessionToken:    strings.TrimSpace(config.AWSSessionToken),
+		Endpoint:        strings.TrimSpace(config.BaseURL),
+	}
+}
+
+// createBedrockModel 创建经 AWS Bedrock 调用的 Claude 模型（复用 Anthropic 转换与流处理）
+func (f *ModelFactory) createBedrockModel(config *models.AIConfig) (model.LLM, error) {
+	cfg := bedrockConfig(config)
+	if cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("Bedrock 需要填写 Region、Access Key 与 Secret Key")
+	}
+	httpClient := &http.Client{
+		Transport: &uaTransport{base: proxy.GetManager().SharedTransport()},
+	}
+	m := anthropic.NewBedrockModel(config.ModelName, cfg, httpClient, config.NoSystemRole)
+	applyAnthropicOptions(m, config)
+	return m, nil
+}
+
+// createOllamaModel 创建 Ollama 模型（原生 /api/chat，无需 API Key）
+// 不走 normalizeOpenAIBaseURL：Ollama 原生接口位于 /api 下，追加 /v1 会导致 404
+func (f *ModelFactory) createOllamaModel(config *models.AIConfig) (model.LLM, error) {
+	httpClient := &http.Client{
+		Transport: &uaTransport{base: proxy.GetManager().SharedTransport()},
+	}
+	return ollama.NewOllamaModel(config.ModelName, config.BaseURL, httpClient, config.NoSystemRole), nil
+}
+
+// createDeepSeekModel 创建 DeepSeek 模型（OpenAI 兼容接口）
+func (f *ModelFactory) createDeepSeekModel(config *models.AIConfig) (model.LLM, error) {
+	return f.createOpenAIModel(deepSeekConfig(config))
+}
+
+// createQwenModel 创建通义千问模型（DashScope OpenAI 兼容接口）
+func (f *ModelFactory) createQwenModel(config *models.AIConfig) (model.LLM, error) {
+	return f.createOpenAIModel(qwenConfig(config))
+}
+
+// createMistralModel 创建 Mistral 模型（OpenAI 兼容接口）
+func (f *ModelFactory) createMistralModel(config *models.AIConfig) (model.LLM, error) {
+	return f.createOpenAIModel(mistralConfig(config))
 }
 
 // createOpenAIResponsesModel 创建使用 Responses API 的 OpenAI 模型
@@ -171,9 +328,15 @@ func (f *ModelFactory) createOpenAIResponsesModel(config *models.AIConfig) (mode
 
 	// 使用代理管理器的 HTTP Client
 	httpClient := &http.Client{
-		Transport: &uaTransport{base: proxy.GetManager().GetTransport()},
+		Transport: &uaTransport{base: proxy.GetManager().SharedTransport()},
+	}
+	m := openai.NewResponsesModel(openAIRequestModel(config), config.APIKey, baseURL, httpClient, config.NoSystemRole)
+	m.StrictTools = supportsStrictTools(config)
+	m.ForcedToolName = strings.TrimSpace(config.ForcedToolName)
+	if config.IsAzure {
+		m.SetAzureEndpoint(azureEndpoint(config, "responses"))
 	}
-	return openai.NewResponsesModel(config.ModelName, config.APIKey, baseURL, httpClient, config.NoSystemRole), nil
+	return m, nil
 }
 
 // TestConnection 测试 AI 配置的连通性
@@ -187,10 +350,20 @@ func (f *ModelFactory) TestConnection(ctx context.Context, config *models.AIConf
 		return f.testOpenAIConnection(ctx, config)
 	case models.AIProviderGemini:
 		return f.testGeminiConnection(ctx, config)
-	case models.AIProviderVertexAI:
+	case models.AIProviderVertexAI, models.AIProviderVertexAIGarden:
 		return f.testVertexAIConnection(ctx, config)
 	case models.AIProviderAnthropic:
 		return f.testAnthropicConnection(ctx, config)
+	case models.AIProviderBedrock:
+		return f.testBedrockConnection(ctx, config)
+	case models.AIProviderOllama:
+		return f.testOllamaConnection(ctx, config)
+	case models.AIProviderDeepSeek:
+		return f.testOpenAIConnection(ctx, deepSeekConfig(config))
+	case models.AIProviderQwen:
+		return f.testOpenAIConnection(ctx, qwenConfig(config))
+	case models.AIProviderMistral:
+		return f.testOpenAIConnection(ctx, mistralConfig(config))
 	default:
 		return fmt.Errorf("不支持的 provider: %s", config.Provider)
 	}
@@ -208,6 +381,18 @@ func (f *ModelFactory) DetectSystemRoleSupport(ctx context.Context, config *mode
 		return f.detectOpenAISystemRole(ctx, config)
 	case models.AIProviderAnthropic:
 		return f.detectAnthropicSystemRole(ctx, config)
+	case models.AIProviderDeepSeek:
+		// 推理模型输出先走思考过程，30 token 的探测必然拿不到暗号，且其本身支持 system prompt
+		if isDeepSeekReasoner(config.ModelName) {
+			return false
+		}
+		return f.detectOpenAISystemRole(ctx, deepSeekConfig(config))
+	case models.AIProviderQwen:
+		// Qwen3 先输出思考过程，短探测拿不到暗号；DashScope 本身支持 system role
+		return false
+	case models.AIProviderMistral:
+		// Mistral 原生支持 system role，无需探测
+		return false
 	default:
 		return false // Gemini/VertexAI 原生支持
 	}
@@ -218,8 +403,7 @@ func (f *ModelFactory) detectOpenAISystemRole(ctx context.Context, config *model
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	baseURL := normalizeOpenAIBaseURL(config.BaseURL)
-	transport := proxy.GetManager().GetTransport()
+	transport := proxy.GetManager().SharedTransport()
 
 	systemPrompt := fmt.Sprintf(
 		"You must reply with exactly: %s. Do not add anything else.",
@@ -230,17 +414,17 @@ func (f *ModelFactory) detectOpenAISystemRole(ctx context.Context, config *model
 	var endpoint string
 
 	if config.UseResponses {
-		endpoint = strings.TrimSuffix(baseURL, "/") + "/responses"
+		endpoint = openAIEndpoint(config, "responses")
 		body = map[string]any{
-			"model":             config.ModelName,
+			"model":             openAIRequestModel(config),
 			"max_output_tokens": 30,
 			"input":             "Please follow the system instruction.",
 			"instructions":      systemPrompt,
 		}
 	} else {
-		endpoint = strings.TrimSuffix(baseURL, "/") + "/chat/completions"
+		endpoint = openAIEndpoint(config, "chat/completions")
 		body = map[string]any{
-			"model":      config.ModelName,
+			"model":      openAIRequestModel(config),
 			"max_tokens": 30,
 			"messages": []map[string]string{
 				{"role": "system", "content": systemPrompt},
@@ -249,7 +433,7 @@ func (f *ModelFactory) detectOpenAISystemRole(ctx context.Context, config *model
 		}
 	}
 
-	respBody, statusCode, err := f.doProbeRequest(ctx, endpoint, config.APIKey, transport, body)
+	respBody, statusCode, err := f.doProbeRequest(ctx, endpoint, config, transport, body)
 	if err != nil {
 		log.Warn("模型 [%s] system role 探测请求失败: %v", config.ModelName, err)
 		return false
@@ -278,7 +462,7 @@ func (f *ModelFactory) detectAnthropicSystemRole(ctx context.Context, config *mo
 	defer cancel()
 
 	baseURL := normalizeAnthropicBaseURL(config.BaseURL)
-	transport := proxy.GetManager().GetTransport()
+	transport := proxy.GetManager().SharedTransport()
 
 	body := map[string]any{
 		"model":      config.ModelName,
@@ -349,28 +533,31 @@ func (f *ModelFactory) extractAnthropicReplyText(respBody []byte) string {
 // testOpenAIConnection 测试 OpenAI 兼容接口连通性
 // 根据 UseResponses 配置决定使用 Responses API 或 Chat Completions API
 func (f *ModelFactory) testOpenAIConnection(ctx context.Context, config *models.AIConfig) error {
-	baseURL := normalizeOpenAIBaseURL(config.BaseURL)
-	transport := proxy.GetManager().GetTransport()
+	transport := proxy.GetManager().SharedTransport()
 
 	var body map[string]interface{}
 	var endpoint string
 
 	if config.UseResponses {
 		// 使用 Responses API 端点测试
-		endpoint = strings.TrimSuffix(baseURL, "/") + "/responses"
+		endpoint = openAIEndpoint(config, "responses")
 		body = map[string]interface{}{
-			"model":             config.ModelName,
+			"model":             openAIRequestModel(config),
 			"max_output_tokens": 1,
 			"input":             "hi",
 		}
 	} else {
 		// 使用 Chat Completions API 端点测试
-		endpoint = strings.TrimSuffix(baseURL, "/") + "/chat/completions"
+		endpoint = openAIEndpoint(config, "chat/completions")
 		body = map[string]interface{}{
-			"model":      config.ModelName,
+			"model":      openAIRequestModel(config),
 			"max_tokens": 1,
 			"messages":   []map[string]string{{"role": "user", "content": "hi"}},
 		}
+		// Qwen3 非流式调用须关闭思考，否则 DashScope 直接报错
+		if config.Provider == models.AIProviderQwen {
+			body["enable_thinking"] = false
+		}
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -383,7 +570,7 @@ func (f *ModelFactory) testOpenAIConnection(ctx context.Context, config *models.
 		return fmt.Errorf("请求创建失败: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+config.APIKey)
+	setOpenAIAuth(req, config)
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) CherryStudio/1.2.4 Chrome/126.0.6478.234 Electron/31.7.6 Safari/537.36")
 
 	client := &http.Client{Transport: transport}
@@ -411,9 +598,9 @@ func (f *ModelFactory) testGeminiConnection(ctx context.Context, config *models.
 	return f.testViaGenerate(ctx, llm)
 }
 
-// testVertexAIConnection 测试 Vertex AI 连通性
+// testVertexAIConnection 测试 Vertex AI（含 Model Garden）连通性
 func (f *ModelFactory) testVertexAIConnection(ctx context.Context, config *models.AIConfig) error {
-	llm, err := f.createVertexAIModel(ctx, config)
+	llm, err := f.createModel(ctx, config)
 	if err != nil {
 		return fmt.Errorf("客户端创建失败: %w", err)
 	}
@@ -424,7 +611,7 @@ func (f *ModelFactory) testVertexAIConnection(ctx context.Context, config *model
 // testAnthropicConnection 测试 Anthropic 连通性
 func (f *ModelFactory) testAnthropicConnection(ctx context.Context, config *models.AIConfig) error {
 	baseURL := normalizeAnthropicBaseURL(config.BaseURL)
-	transport := proxy.GetManager().GetTransport()
+	transport := proxy.GetManager().SharedTransport()
 
 	body := map[string]any{
 		"model":      config.ModelName,
@@ -465,6 +652,72 @@ func (f *ModelFactory) testAnthropicConnection(ctx context.Context, config *mode
 	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
 }
 
+// testBedrockConnection 测试 Bedrock 连通性
+// 发送 max_tokens=1 的 InvokeModel 请求，同时验证签名凭证、Region 与模型访问权限
+func (f *ModelFactory) testBedrockConnection(ctx context.Context, config *models.AIConfig) error {
+	llm, err := f.createBedrockModel(config)
+	if err != nil {
+		return err
+	}
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)},
+		Config:   &genai.GenerateContentConfig{MaxOutputTokens: 1},
+	}
+	for _, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return fmt.Errorf("连接失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// testOllamaConnection 测试 Ollama 连通性
+// 通过 /api/tags 验证服务可达且模型已拉取到本地
+func (f *ModelFactory) testOllamaConnection(ctx context.Context, config *models.AIConfig) error {
+	endpoint, err := url.JoinPath(ollama.NormalizeBaseURL(config.BaseURL), "api", "tags")
+	if err != nil {
+		return fmt.Errorf("无效 BaseURL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("请求创建失败: %w", err)
+	}
+
+	client := &http.Client{Transport: proxy.GetManager().SharedTransport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("连接失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var tags ollama.TagsResponse
+	if err := json.Unmarshal(respBody, &tags); err != nil {
+		return fmt.Errorf("解析模型列表失败: %w", err)
+	}
+	for _, m := range tags.Models {
+		if ollamaModelMatches(m.Name, config.ModelName) || ollamaModelMatches(m.Model, config.ModelName) {
+			return nil
+		}
+	}
+	return fmt.Errorf("模型 %s 未在 Ollama 中找到，请先执行 ollama pull %s", config.ModelName, config.ModelName)
+}
+
+// ollamaModelMatches 比较模型名，未写 tag 时按 latest 处理
+func ollamaModelMatches(name, want string) bool {
+	if name == want {
+		return true
+	}
+	if !strings.Contains(want, ":") {
+		return name == want+":latest"
+	}
+	return false
+}
+
 // testViaGenerate 通过 GenerateContent 发送最小请求测试连通性
 func (f *ModelFactory) testViaGenerate(ctx context.Context, llm model.LLM) error {
 	req := &model.LLMRequest{
@@ -486,7 +739,7 @@ func (f *ModelFactory) testViaGenerate(ctx context.Context, llm model.LLM) error
 }
 
 // doProbeRequest 发送探测请求，返回响应体、状态码
-func (f *ModelFactory) doProbeRequest(ctx context.Context, endpoint, apiKey string, transport http.RoundTripper, body map[string]any) ([]byte, int, error) {
+func (f *ModelFactory) doProbeRequest(ctx context.Context, endpoint string, config *models.AIConfig, transport http.RoundTripper, body map[string]any) ([]byte, int, error) {
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return nil, 0, fmt.Errorf("请求构造失败: %w", err)
@@ -497,7 +750,7 @@ func (f *ModelFactory) doProbeRequest(ctx context.Context, endpoint, apiKey stri
 		return nil, 0, fmt.Errorf("请求创建失败: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	setOpenAIAuth(req, config)
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) CherryStudio/1.2.4 Chrome/126.0.6478.234 Electron/31.7.6 Safari/537.36")
 
 	client := &http.Client{Transport: transport}
@@ -1,6 +1,18 @@
 package adk
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
 
 func TestNormalizeAnthropicBaseURL(t *testing.T) {
 	tests := []struct {
@@ -23,3 +35,281 @@ func TestNormalizeAnthropicBaseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureEndpoint(t *testing.T) {
+	cfg := &models.AIConfig{
+		BaseURL:        "https://corp.openai.azure.com/openai/",
+		ModelName:      "gpt-4o",
+		DeploymentName: "gpt4o-prod",
+		IsAzure:        true,
+	}
+	want := "https://corp.openai.azure.com/openai/deployments/gpt4o-prod/chat/completions?api-version=" + DefaultAzureAPIVersion
+	if got := openAIEndpoint(cfg, "chat/completions"); got != want {
+		t.Fatalf("chat endpoint = %q, want %q", got, want)
+	}
+
+	cfg.APIVersion = "2025-03-01-preview"
+	want = "https://corp.openai.azure.com/openai/responses?api-version=2025-03-01-preview"
+	if got := openAIEndpoint(cfg, "responses"); got != want {
+		t.Fatalf("responses endpoint = %q, want %q", got, want)
+	}
+
+	// 未填写部署名时回退为模型名
+	cfg.DeploymentName = ""
+	if got := openAIRequestModel(cfg); got != "gpt-4o" {
+		t.Fatalf("request model = %q, want gpt-4o", got)
+	}
+}
+
+func TestTestConnection_Azure(t *testing.T) {
+	var gotPath, gotVersion, gotKey, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotVersion = r.URL.Query().Get("api-version")
+		gotKey = r.Header.Get("api-key")
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"choices":[{"message":{"content":"hi"}}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := &models.AIConfig{
+		Provider:       models.AIProviderOpenAI,
+		BaseURL:        srv.URL,
+		APIKey:         "azure-key",
+		ModelName:      "gpt-4o",
+		DeploymentName: "gpt4o-prod",
+		APIVersion:     "2024-06-01",
+		IsAzure:        true,
+	}
+	if err := NewModelFactory().TestConnection(context.Background(), cfg); err != nil {
+		t.Fatalf("TestConnection error: %v", err)
+	}
+	if gotPath != "/openai/deployments/gpt4o-prod/chat/completions" || gotVersion != "2024-06-01" {
+		t.Errorf("request = %s?api-version=%s", gotPath, gotVersion)
+	}
+	if gotKey != "azure-key" || gotAuth != "" {
+		t.Errorf("headers api-key=%q authorization=%q", gotKey, gotAuth)
+	}
+}
+
+func TestTestConnection_Ollama(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected Authorization header")
+		}
+		w.Write([]byte(`{"models":[{"name":"llama3:latest","model":"llama3:latest"},{"name":"qwen2.5:7b","model":"qwen2.5:7b"}]}`))
+	}))
+	defer srv.Close()
+
+	f := NewModelFactory()
+	tests := []struct {
+		name    string
+		baseURL string
+		model   string
+		wantErr bool
+	}{
+		{name: "exact tag", baseURL: srv.URL, model: "qwen2.5:7b"},
+		{name: "implicit latest", baseURL: srv.URL, model: "llama3"},
+		{name: "openai style v1 url", baseURL: srv.URL + "/v1", model: "llama3"},
+		{name: "model not pulled", baseURL: srv.URL, model: "mistral", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &models.AIConfig{Provider: models.AIProviderOllama, BaseURL: tc.baseURL, ModelName: tc.model}
+			err := f.TestConnection(context.Background(), cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("TestConnection(%s) err = %v, wantErr %v", tc.model, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeepSeekProvider(t *testing.T) {
+	var gotPath, gotModel, gotAuth string
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		var body struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotModel = body.Model
+		w.Write([]byte(`{"choices":[{"message":{"content":"hi"}}]}`))
+	}))
+	defer srv.Close()
+
+	f := NewModelFactory()
+	cfg := &models.AIConfig{Provider: models.AIProviderDeepSeek, BaseURL: srv.URL, APIKey: "ds-key", UseResponses: true}
+	if err := f.TestConnection(context.Background(), cfg); err != nil {
+		t.Fatalf("TestConnection error: %v", err)
+	}
+	// 忽略 Responses 开关，模型名默认 deepseek-chat
+	if gotPath != "/v1/chat/completions" || gotModel != DeepSeekChatModel || gotAuth != "Bearer ds-key" {
+		t.Errorf("request path=%s model=%s auth=%s", gotPath, gotModel, gotAuth)
+	}
+
+	// deepseek-reasoner 支持 system role，不发探测请求
+	calls = 0
+	reasoner := &models.AIConfig{Provider: models.AIProviderDeepSeek, BaseURL: srv.URL, ModelName: DeepSeekReasonerModel}
+	if f.DetectSystemRoleSupport(context.Background(), reasoner) || calls != 0 {
+		t.Errorf("reasoner should keep system role without probing (calls=%d)", calls)
+	}
+	if c := deepSeekConfig(&models.AIConfig{ModelName: DeepSeekReasonerModel, NoSystemRole: true}); c.NoSystemRole || c.BaseURL != DefaultDeepSeekBaseURL {
+		t.Errorf("deepSeekConfig = %+v", c)
+	}
+	if _, err := f.CreateModel(context.Background(), reasoner); err != nil {
+		t.Errorf("CreateModel error: %v", err)
+	}
+}
+
+func TestQwenProvider(t *testing.T) {
+	var gotPath string
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"choices":[{"message":{"content":"hi"}}]}`))
+	}))
+	defer srv.Close()
+
+	f := NewModelFactory()
+	cfg := &models.AIConfig{Provider: models.AIProviderQwen, BaseURL: srv.URL + "/compatible-mode/v1/", APIKey: "sk-qwen", ModelName: "qwen3-235b-a22b"}
+	if err := f.TestConnection(context.Background(), cfg); err != nil {
+		t.Fatalf("TestConnection error: %v", err)
+	}
+	if gotPath != "/compatible-mode/v1/chat/completions" || body["model"] != "qwen3-235b-a22b" || body["enable_thinking"] != false {
+		t.Errorf("request path=%s body=%v", gotPath, body)
+	}
+
+	for in, want := range map[string]string{
+		"": DefaultQwenBaseURL,
+		"https://dashscope.aliyuncs.com/compatible-mode":                     DefaultQwenBaseURL,
+		"https://dashscope.aliyuncs.com/compatible-mode/v1/chat/completions": DefaultQwenBaseURL,
+		"https://dashscope-intl.aliyuncs.com/compatible-mode/v1/":            "https://dashscope-intl.aliyuncs.com/compatible-mode/v1",
+	} {
+		if got := normalizeQwenBaseURL(in); got != want {
+			t.Errorf("normalizeQwenBaseURL(%q) = %s, want %s", in, got, want)
+		}
+	}
+	if got := openAIEndpoint(qwenConfig(&models.AIConfig{}), "chat/completions"); got != DefaultQwenBaseURL+"/chat/completions" {
+		t.Errorf("endpoint = %s", got)
+	}
+	if _, err := f.CreateModel(context.Background(), cfg); err != nil {
+		t.Errorf("CreateModel error: %v", err)
+	}
+}
+
+func TestMistralProvider(t *testing.T) {
+	var gotPath, gotAuth string
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"choices":[{"message":{"content":"hi"}}]}`))
+	}))
+	defer srv.Close()
+
+	f := NewModelFactory()
+	cfg := &models.AIConfig{Provider: models.AIProviderMistral, BaseURL: srv.URL + "/v1/chat/completions", APIKey: "mistral-key", ModelName: "mistral-small-latest"}
+	if err := f.TestConnection(context.Background(), cfg); err != nil {
+		t.Fatalf("TestConnection error: %v", err)
+	}
+	if gotPath != "/v1/chat/completions" || gotAuth != "Bearer mistral-key" || body["model"] != "mistral-small-latest" {
+		t.Errorf("request path=%s auth=%s body=%v", gotPath, gotAuth, body)
+	}
+
+	for in, want := range map[string]string{
+		"":                           DefaultMistralBaseURL,
+		"https://api.mistral.ai":     DefaultMistralBaseURL,
+		"https://api.mistral.ai/v1/": DefaultMistralBaseURL,
+	} {
+		if got := normalizeMistralBaseURL(in); got != want {
+			t.Errorf("normalizeMistralBaseURL(%q) = %s, want %s", in, got, want)
+		}
+	}
+	if c := mistralConfig(&models.AIConfig{UseResponses: true, NoSystemRole: true}); c.ModelName != MistralDefaultModel || c.UseResponses || c.NoSystemRole {
+		t.Errorf("mistralConfig = %+v", c)
+	}
+	if f.DetectSystemRoleSupport(context.Background(), cfg) {
+		t.Error("Mistral 支持 system role，不应降级")
+	}
+	if _, err := f.CreateModel(context.Background(), cfg); err != nil {
+		t.Errorf("CreateModel error: %v", err)
+	}
+}
+
+func TestVertexAIGardenModelName(t *testing.T) {
+	const want = "projects/p1/locations/us-central1/publishers/meta/models/llama-3.3-70b-instruct-maas"
+	for _, modelName := range []string{"llama-3.3-70b-instruct-maas", "publishers/meta/models/llama-3.3-70b-instruct-maas"} {
+		cfg := &models.AIConfig{Provider: models.AIProviderVertexAIGarden, Project: "p1", Location: "us-central1", Publisher: "meta", ModelName: modelName}
+		got, err := vertexAIGardenModelName(cfg)
+		if err != nil || got != want {
+			t.Errorf("vertexAIGardenModelName(%q) = %q, %v, want %q", modelName, got, err, want)
+		}
+	}
+	for _, cfg := range []*models.AIConfig{
+		{Project: "p1", Location: "us-central1", ModelName: "mistral-large"},
+		{Location: "us-central1", Publisher: "mistralai", ModelName: "mistral-large"},
+		{Project: "p1", Location: "us-central1", Publisher: "mistralai"},
+	} {
+		if _, err := vertexAIGardenModelName(cfg); err == nil {
+			t.Errorf("vertexAIGardenModelName(%+v) 应返回错误", cfg)
+		}
+	}
+}
+
+// 集成测试：需要设置环境变量 MISTRAL_TEST_KEY
+func TestIntegration_MistralStreaming(t *testing.T) {
+	apiKey := os.Getenv("MISTRAL_TEST_KEY")
+	if apiKey == "" {
+		t.Skip("跳过集成测试：未设置 MISTRAL_TEST_KEY")
+	}
+
+	llm, err := NewModelFactory().CreateModel(context.Background(), &models.AIConfig{
+		Provider:  models.AIProviderMistral,
+		APIKey:    apiKey,
+		ModelName: "mistral-small-latest",
+	})
+	if err != nil {
+		t.Fatalf("CreateModel error: %v", err)
+	}
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: "user", Parts: []*genai.Part{{Text: "Reply with exactly: PONG"}}},
+		},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "You are a terse assistant."}}},
+			MaxOutputTokens:   32,
+		},
+	}
+
+	var text strings.Builder
+	var gotFinal bool
+	for resp, err := range llm.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			t.Fatalf("streaming error: %v", err)
+		}
+		if resp == nil || resp.Content == nil {
+			continue
+		}
+		if resp.Partial {
+			for _, p := range resp.Content.Parts {
+				text.WriteString(p.Text)
+			}
+		}
+		if resp.TurnComplete {
+			gotFinal = true
+		}
+	}
+	if !gotFinal {
+		t.Error("never received TurnComplete response")
+	}
+	t.Logf("streaming response: %s", text.String())
+}
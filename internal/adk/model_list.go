@@ -0,0 +1,238 @@
+package adk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk/ollama"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// ModelInfo Provider 可用模型
+type ModelInfo struct {
+	ID            string `json:"id"`
+	DisplayName   string `json:"displayName"`
+	ContextWindow int    `json:"contextWindow"` // 上下文长度（token），接口未提供时为 0
+}
+
+// ErrModelListUnsupported 该 Provider 没有模型列表接口
+var ErrModelListUnsupported = errors.New("该服务商不提供模型列表接口，请手动填写模型名称")
+
+// geminiModelsEndpoint Gemini 模型列表接口（测试中可替换）
+var geminiModelsEndpoint = "https://generativelanguage.googleapis.com/v1beta/models"
+
+// modelListMaxPages 分页拉取的最大页数，防止异常接口无限翻页
+const modelListMaxPages = 20
+
+// ListModels 拉取 Provider 的可用模型列表（按 ID 排序）
+// OpenAI 兼容接口走 GET /v1/models，Gemini 与 Anthropic 走各自的 models 接口
+func (f *ModelFactory) ListModels(ctx context.Context, config *models.AIConfig) ([]ModelInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	var (
+		list []ModelInfo
+		err  error
+	)
+	switch config.Provider {
+	case models.AIProviderOpenAI:
+		if config.IsAzure {
+			// Azure 部署列表属于管理面接口，需要 ARM 凭据
+			return nil, ErrModelListUnsupported
+		}
+		list, err = f.listOpenAIModels(ctx, config)
+	case models.AIProviderDeepSeek:
+		list, err = f.listOpenAIModels(ctx, deepSeekConfig(config))
+	case models.AIProviderQwen:
+		list, err = f.listOpenAIModels(ctx, qwenConfig(config))
+	case models.AIProviderMistral:
+		list, err = f.listOpenAIModels(ctx, mistralConfig(config))
+	case models.AIProviderGemini:
+		list, err = f.listGeminiModels(ctx, config)
+	case models.AIProviderAnthropic:
+		list, err = f.listAnthropicModels(ctx, config)
+	case models.AIProviderOllama:
+		list, err = f.listOllamaModels(ctx, config)
+	default:
+		return nil, ErrModelListUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// getModelList 发送 GET 请求并解析 JSON，404/405 视为不提供列表接口
+func getModelList(ctx context.Context, endpoint string, header http.Header, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("请求创建失败: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	client := &http.Client{Transport: &uaTransport{base: proxy.GetManager().SharedTransport()}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("连接失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 8*1024*1024))
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed:
+		return ErrModelListUnsupported
+	case resp.StatusCode != http.StatusOK:
+		if len(respBody) > 512 {
+			respBody = respBody[:512]
+		}
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("解析模型列表失败: %w", err)
+	}
+	return nil
+}
+
+// listOpenAIModels OpenAI 兼容接口 GET /v1/models
+// 部分聚合服务（如 OpenRouter）会附带 context_length
+func (f *ModelFactory) listOpenAIModels(ctx context.Context, config *models.AIConfig) ([]ModelInfo, error) {
+	var resp struct {
+		Data []struct {
+			ID            string `json:"id"`
+			Name          string `json:"name"`
+			ContextLength int    `json:"context_length"`
+			ContextWindow int    `json:"context_window"`
+		} `json:"data"`
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+config.APIKey)
+	if err := getModelList(ctx, openAIEndpoint(config, "models"), header, &resp); err != nil {
+		return nil, err
+	}
+
+	list := make([]ModelInfo, 0, len(resp.Data))
+	for _, m := range resp.Data {
+		if m.ID == "" {
+			continue
+		}
+		info := ModelInfo{ID: m.ID, DisplayName: m.Name, ContextWindow: m.ContextLength}
+		if info.DisplayName == "" {
+			info.DisplayName = m.ID
+		}
+		if info.ContextWindow == 0 {
+			info.ContextWindow = m.ContextWindow
+		}
+		list = append(list, info)
+	}
+	return list, nil
+}
+
+// listGeminiModels Gemini GET /v1beta/models，只保留支持 generateContent 的模型
+func (f *ModelFactory) listGeminiModels(ctx context.Context, config *models.AIConfig) ([]ModelInfo, error) {
+	var list []ModelInfo
+	pageToken := ""
+	for page := 0; page < modelListMaxPages; page++ {
+		q := url.Values{"pageSize": {"1000"}}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		var resp struct {
+			Models []struct {
+				Name                       string   `json:"name"`
+				DisplayName                string   `json:"displayName"`
+				InputTokenLimit            int      `json:"inputTokenLimit"`
+				SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+			} `json:"models"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		header := http.Header{}
+		header.Set("x-goog-api-key", config.APIKey)
+		if err := getModelList(ctx, geminiModelsEndpoint+"?"+q.Encode(), header, &resp); err != nil {
+			return nil, err
+		}
+		for _, m := range resp.Models {
+			if !slices.Contains(m.SupportedGenerationMethods, "generateContent") {
+				continue
+			}
+			list = append(list, ModelInfo{
+				ID:            strings.TrimPrefix(m.Name, "models/"),
+				DisplayName:   m.DisplayName,
+				ContextWindow: m.InputTokenLimit,
+			})
+		}
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+	return list, nil
+}
+
+// listAnthropicModels Anthropic GET /v1/models（按 after_id 翻页）
+// 接口不返回上下文长度，ContextWindow 为 0
+func (f *ModelFactory) listAnthropicModels(ctx context.Context, config *models.AIConfig) ([]ModelInfo, error) {
+	endpoint, err := url.JoinPath(normalizeAnthropicBaseURL(config.BaseURL), "v1", "models")
+	if err != nil {
+		return nil, fmt.Errorf("无效 BaseURL: %w", err)
+	}
+
+	var list []ModelInfo
+	afterID := ""
+	for page := 0; page < modelListMaxPages; page++ {
+		q := url.Values{"limit": {"1000"}}
+		if afterID != "" {
+			q.Set("after_id", afterID)
+		}
+		var resp struct {
+			Data []struct {
+				ID          string `json:"id"`
+				DisplayName string `json:"display_name"`
+			} `json:"data"`
+			HasMore bool   `json:"has_more"`
+			LastID  string `json:"last_id"`
+		}
+		header := http.Header{}
+		header.Set("x-api-key", config.APIKey)
+		header.Set("anthropic-version", "2023-06-01")
+		if err := getModelList(ctx, endpoint+"?"+q.Encode(), header, &resp); err != nil {
+			return nil, err
+		}
+		for _, m := range resp.Data {
+			list = append(list, ModelInfo{ID: m.ID, DisplayName: m.DisplayName})
+		}
+		if !resp.HasMore || resp.LastID == "" {
+			break
+		}
+		afterID = resp.LastID
+	}
+	return list, nil
+}
+
+// listOllamaModels Ollama GET /api/tags（本地已拉取的模型）
+func (f *ModelFactory) listOllamaModels(ctx context.Context, config *models.AIConfig) ([]ModelInfo, error) {
+	endpoint, err := url.JoinPath(ollama.NormalizeBaseURL(config.BaseURL), "api", "tags")
+	if err != nil {
+		return nil, fmt.Errorf("无效 BaseURL: %w", err)
+	}
+	var tags ollama.TagsResponse
+	if err := getModelList(ctx, endpoint, nil, &tags); err != nil {
+		return nil, err
+	}
+	list := make([]ModelInfo, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		list = append(list, ModelInfo{ID: m.Name, DisplayName: m.Name})
+	}
+	return list, nil
+}
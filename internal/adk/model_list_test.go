@@ -0,0 +1,96 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestListModels_OpenAI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("request = %s %s auth=%q", r.Method, r.URL.Path, r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"data":[{"id":"gpt-4o"},{"id":"deepseek/deepseek-chat","name":"DeepSeek V3","context_length":65536}]}`))
+	}))
+	defer srv.Close()
+
+	list, err := NewModelFactory().ListModels(context.Background(), &models.AIConfig{Provider: models.AIProviderOpenAI, BaseURL: srv.URL, APIKey: "sk-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ModelInfo{
+		{ID: "deepseek/deepseek-chat", DisplayName: "DeepSeek V3", ContextWindow: 65536},
+		{ID: "gpt-4o", DisplayName: "gpt-4o"},
+	}
+	if len(list) != len(want) || list[0] != want[0] || list[1] != want[1] {
+		t.Errorf("list = %+v", list)
+	}
+}
+
+func TestListModels_Gemini(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-goog-api-key") != "key" {
+			t.Errorf("missing api key header")
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"models":[{"name":"models/gemini-2.5-flash","displayName":"Gemini 2.5 Flash","inputTokenLimit":1048576,"supportedGenerationMethods":["generateContent","countTokens"]},{"name":"models/text-embedding-004","supportedGenerationMethods":["embedContent"]}],"nextPageToken":"p2"}`))
+			return
+		}
+		w.Write([]byte(`{"models":[{"name":"models/gemini-2.5-pro","displayName":"Gemini 2.5 Pro","inputTokenLimit":1048576,"supportedGenerationMethods":["generateContent"]}]}`))
+	}))
+	defer srv.Close()
+	old := geminiModelsEndpoint
+	geminiModelsEndpoint = srv.URL + "/v1beta/models"
+	defer func() { geminiModelsEndpoint = old }()
+
+	list, err := NewModelFactory().ListModels(context.Background(), &models.AIConfig{Provider: models.AIProviderGemini, APIKey: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "gemini-2.5-flash" || list[0].ContextWindow != 1048576 || list[1].ID != "gemini-2.5-pro" {
+		t.Errorf("list = %+v", list)
+	}
+}
+
+func TestListModels_Anthropic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" || r.Header.Get("x-api-key") != "ak" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("request = %s headers=%v", r.URL.Path, r.Header)
+		}
+		if r.URL.Query().Get("after_id") == "" {
+			w.Write([]byte(`{"data":[{"id":"claude-sonnet-4-5","display_name":"Claude Sonnet 4.5"}],"has_more":true,"last_id":"claude-sonnet-4-5"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"claude-haiku-4-5","display_name":"Claude Haiku 4.5"}],"has_more":false}`))
+	}))
+	defer srv.Close()
+
+	list, err := NewModelFactory().ListModels(context.Background(), &models.AIConfig{Provider: models.AIProviderAnthropic, BaseURL: srv.URL + "/v1", APIKey: "ak"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "claude-haiku-4-5" || list[1].DisplayName != "Claude Sonnet 4.5" {
+		t.Errorf("list = %+v", list)
+	}
+}
+
+func TestListModels_Unsupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	f := NewModelFactory()
+	for _, cfg := range []*models.AIConfig{
+		{Provider: models.AIProviderOpenAI, BaseURL: srv.URL},
+		{Provider: models.AIProviderOpenAI, IsAzure: true, BaseURL: srv.URL},
+		{Provider: models.AIProviderVertexAI},
+	} {
+		if _, err := f.ListModels(context.Background(), cfg); !errors.Is(err, ErrModelListUnsupported) {
+			t.Errorf("%+v: err = %v, want ErrModelListUnsupported", cfg, err)
+		}
+	}
+}
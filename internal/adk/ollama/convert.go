@@ -0,0 +1,289 @@
+package ollama
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var convertLog = logger.New("ollama:convert")
+
+// callSeq 工具调用序号，用于生成进程内唯一的调用 ID
+var callSeq atomic.Int64
+
+// extractTextFromContent 提取 genai.Content 中的纯文本
+func extractTextFromContent(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// toChatRequest 将 ADK LLMRequest 转换为 Ollama /api/chat 请求
+func toChatRequest(req *model.LLMRequest, modelName string, noSystemRole bool) (*ChatRequest, error) {
+	cr := &ChatRequest{Model: modelName}
+
+	msgs, err := toMessages(req.Contents)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		systemText := extractTextFromContent(req.Config.SystemInstruction)
+		if systemText != "" {
+			if !noSystemRole {
+				msgs = append([]Message{{Role: "system", Content: systemText}}, msgs...)
+			} else if len(msgs) > 0 && msgs[0].Role == "user" {
+				// 不支持 system role：注入到第一条 user 消息前面
+				msgs[0].Content = systemText + "\n\n" + msgs[0].Content
+			} else {
+				msgs = append([]Message{{Role: "user", Content: systemText}}, msgs...)
+			}
+		}
+	}
+	cr.Messages = msgs
+
+	if req.Config != nil && len(req.Config.Tools) > 0 {
+		tools, err := convertTools(req.Config.Tools)
+		if err != nil {
+			return nil, err
+		}
+		cr.Tools = tools
+	}
+
+	if req.Config != nil {
+		opts := &Options{}
+		if req.Config.Temperature != nil {
+			t := float64(*req.Config.Temperature)
+			opts.Temperature = &t
+		}
+		if req.Config.TopP != nil {
+			p := float64(*req.Config.TopP)
+			opts.TopP = &p
+		}
+		if req.Config.MaxOutputTokens > 0 {
+			opts.NumPredict = int(req.Config.MaxOutputTokens)
+		}
+		if len(req.Config.StopSequences) > 0 {
+			opts.Stop = req.Config.StopSequences
+		}
+		if opts.Temperature != nil || opts.TopP != nil || opts.NumPredict > 0 || len(opts.Stop) > 0 {
+			cr.Options = opts
+		}
+	}
+
+	return cr, nil
+}
+
+// toMessages 将 genai.Content 列表转换为 Ollama messages
+// 函数响应拆分为独立的 tool 消息，Ollama 通过 tool_name 关联调用
+func toMessages(contents []*genai.Content) ([]Message, error) {
+	var msgs []Message
+
+	for _, content := range contents {
+		if content == nil {
+			continue
+		}
+
+		role := "user"
+		if content.Role == "model" {
+			role = "assistant"
+		}
+
+		msg := Message{Role: role}
+		var texts []string
+
+		for _, part := range content.Parts {
+			// 跳过 thought parts（不回传给 API）
+			if part.Thought {
+				continue
+			}
+
+			if part.Text != "" {
+				texts = append(texts, part.Text)
+			}
+
+			if part.InlineData != nil {
+				if !strings.HasPrefix(part.InlineData.MIMEType, "image/") {
+					return nil, fmt.Errorf("不支持的附件类型: %s", part.InlineData.MIMEType)
+				}
+				msg.Images = append(msg.Images, base64.StdEncoding.EncodeToString(part.InlineData.Data))
+			}
+
+			if part.FunctionCall != nil {
+				args := part.FunctionCall.Args
+				if args == nil {
+					args = map[string]any{}
+				}
+				msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+					Function: ToolCallFunction{Name: part.FunctionCall.Name, Arguments: args},
+				})
+			}
+
+			if part.FunctionResponse != nil {
+				respJSON, err := json.Marshal(part.FunctionResponse.Response)
+				if err != nil {
+					return nil, fmt.Errorf("marshal function response: %w", err)
+				}
+				msgs = append(msgs, Message{
+					Role:     "tool",
+					Content:  string(respJSON),
+					ToolName: part.FunctionResponse.Name,
+				})
+			}
+		}
+
+		msg.Content = strings.Join(texts, "\n")
+		if msg.Content == "" && len(msg.ToolCalls) == 0 && len(msg.Images) == 0 {
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// convertTools 将 genai.Tool 转换为 Ollama Tool
+func convertTools(genaiTools []*genai.Tool) ([]Tool, error) {
+	var tools []Tool
+	for _, gt := range genaiTools {
+		if gt == nil {
+			continue
+		}
+		for _, fd := range gt.FunctionDeclarations {
+			var schema any = fd.ParametersJsonSchema
+			if fd.ParametersJsonSchema == nil {
+				if fd.Parameters == nil {
+					return nil, fmt.Errorf("parameters is nil for tool %s", fd.Name)
+				}
+				schema = fd.Parameters
+			}
+			schemaJSON, err := json.Marshal(schema)
+			if err != nil {
+				return nil, fmt.Errorf("marshal tool schema: %w", err)
+			}
+			if normalized, err := lowercaseSchemaTypes(schemaJSON); err == nil {
+				schemaJSON = normalized
+			} else {
+				convertLog.Warn("规范化 tool schema 失败 (%s): %v", fd.Name, err)
+			}
+			tools = append(tools, Tool{
+				Type: "function",
+				Function: ToolFunction{
+					Name:        fd.Name,
+					Description: fd.Description,
+					Parameters:  schemaJSON,
+				},
+			})
+		}
+	}
+	return tools, nil
+}
+
+// lowercaseSchemaTypes 将 genai.Schema 的大写类型（OBJECT/STRING）转为 JSON Schema 小写形式
+func lowercaseSchemaTypes(raw json.RawMessage) (json.RawMessage, error) {
+	var schema any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return raw, err
+	}
+	lowercaseSchemaNode(schema)
+	return json.Marshal(schema)
+}
+
+// lowercaseSchemaNode 递归处理 schema 节点
+func lowercaseSchemaNode(node any) {
+	switch n := node.(type) {
+	case map[string]any:
+		for k, v := range n {
+			if k == "type" {
+				if s, ok := v.(string); ok {
+					n[k] = strings.ToLower(s)
+					continue
+				}
+			}
+			lowercaseSchemaNode(v)
+		}
+	case []any:
+		for _, v := range n {
+			lowercaseSchemaNode(v)
+		}
+	}
+}
+
+// toFunctionCalls 将 Ollama 工具调用转换为 genai FunctionCall
+// Ollama 不返回调用 ID，按序号生成以便与函数响应对应
+func toFunctionCalls(calls []ToolCall) []*genai.Part {
+	parts := make([]*genai.Part, 0, len(calls))
+	for _, tc := range calls {
+		args := tc.Function.Arguments
+		if args == nil {
+			args = map[string]any{}
+		}
+		parts = append(parts, &genai.Part{
+			FunctionCall: &genai.FunctionCall{
+				ID:   fmt.Sprintf("ollama_call_%d", callSeq.Add(1)),
+				Name: tc.Function.Name,
+				Args: args,
+			},
+		})
+	}
+	return parts
+}
+
+// convertChatResponse 将 Ollama 响应转换为 ADK LLMResponse
+func convertChatResponse(resp *ChatResponse) *model.LLMResponse {
+	content := &genai.Content{
+		Role:  genai.RoleModel,
+		Parts: []*genai.Part{},
+	}
+	if resp.Message.Thinking != "" {
+		content.Parts = append(content.Parts, &genai.Part{Text: resp.Message.Thinking, Thought: true})
+	}
+	if resp.Message.Content != "" {
+		content.Parts = append(content.Parts, &genai.Part{Text: resp.Message.Content})
+	}
+	content.Parts = append(content.Parts, toFunctionCalls(resp.Message.ToolCalls)...)
+
+	return &model.LLMResponse{
+		Content:       content,
+		UsageMetadata: convertUsage(resp),
+		FinishReason:  convertDoneReason(resp.DoneReason),
+		TurnComplete:  true,
+	}
+}
+
+// convertUsage 转换 token 用量
+func convertUsage(resp *ChatResponse) *genai.GenerateContentResponseUsageMetadata {
+	if resp == nil || resp.PromptEvalCount+resp.EvalCount == 0 {
+		return nil
+	}
+	return &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     int32(resp.PromptEvalCount),
+		CandidatesTokenCount: int32(resp.EvalCount),
+		TotalTokenCount:      int32(resp.PromptEvalCount + resp.EvalCount),
+	}
+}
+
+// convertDoneReason 转换结束原因
+func convertDoneReason(reason string) genai.FinishReason {
+	switch reason {
+	case "stop", "":
+		return genai.FinishReasonStop
+	case "length":
+		return genai.FinishReasonMaxTokens
+	default:
+		return genai.FinishReasonUnspecified
+	}
+}
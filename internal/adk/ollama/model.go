@@ -0,0 +1,237 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/pkg/httpbody"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var modelLog = logger.New("ollama:model")
+
+// DefaultBaseURL Ollama 本地默认地址
+const DefaultBaseURL = "http://localhost:11434"
+
+// 确保实现 model.LLM 接口
+var _ model.LLM = &OllamaModel{}
+
+// OllamaModel Ollama 原生 /api/chat 模型
+type OllamaModel struct {
+	httpClient   *http.Client
+	baseURL      string
+	modelName    string
+	noSystemRole bool
+}
+
+// NormalizeBaseURL 规范化 BaseURL，为空时使用本地默认地址
+// 兼容用户填写 OpenAI 兼容地址（/v1）或完整的 /api 路径
+func NormalizeBaseURL(baseURL string) string {
+	baseURL = strings.TrimSpace(strings.TrimRight(baseURL, "/"))
+	if baseURL == "" {
+		return DefaultBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/v1")
+	baseURL = strings.TrimSuffix(baseURL, "/api")
+	return baseURL
+}
+
+// NewOllamaModel 创建 Ollama 模型
+func NewOllamaModel(modelName, baseURL string, httpClient *http.Client, noSystemRole bool) *OllamaModel {
+	return &OllamaModel{
+		httpClient:   httpClient,
+		baseURL:      NormalizeBaseURL(baseURL),
+		modelName:    modelName,
+		noSystemRole: noSystemRole,
+	}
+}
+
+// Name 返回模型名称
+func (m *OllamaModel) Name() string {
+	return m.modelName
+}
+
+// GenerateContent 实现 model.LLM 接口
+func (m *OllamaModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	if stream {
+		return m.generateStream(ctx, req)
+	}
+	return m.generate(ctx, req)
+}
+
+// doRequest 发送 HTTP 请求到 Ollama /api/chat
+func (m *OllamaModel) doRequest(ctx context.Context, cr *ChatRequest) (*http.Response, error) {
+	jsonBody, err := json.Marshal(cr)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	endpoint, err := url.JoinPath(m.baseURL, "api", "chat")
+	if err != nil {
+		return nil, fmt.Errorf("build endpoint: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		resp.Body.Close()
+		modelLog.Error("API 响应异常: status=%d, body=%s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp, nil
+}
+
+// generate 非流式生成
+func (m *OllamaModel) generate(ctx context.Context, req *model.LLMRequest) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		cr, err := toChatRequest(req, m.modelName, m.noSystemRole)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		cr.Stream = false
+
+		resp, err := m.doRequest(ctx, cr)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+		if err != nil {
+			yield(nil, fmt.Errorf("read response: %w", err))
+			return
+		}
+
+		var chatResp ChatResponse
+		if err := json.Unmarshal(body, &chatResp); err != nil {
+			yield(nil, fmt.Errorf("unmarshal response: %w", err))
+			return
+		}
+		if chatResp.Error != "" {
+			yield(nil, fmt.Errorf("Ollama API error: %s", chatResp.Error))
+			return
+		}
+
+		yield(convertChatResponse(&chatResp), nil)
+	}
+}
+
+// generateStream 流式生成（NDJSON，每行一个分片，done=true 为最后一行）
+func (m *OllamaModel) generateStream(ctx context.Context, req *model.LLMRequest) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		cr, err := toChatRequest(req, m.modelName, m.noSystemRole)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		cr.Stream = true
+
+		resp, err := m.doRequest(ctx, cr)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		// 取消时立即断开连接，避免服务端继续生成并计费
+		body, stop := httpbody.CloseOnCancel(ctx, resp.Body)
+		defer stop()
+
+		m.processStream(body, yield)
+	}
+}
+
+// processStream 处理 NDJSON 流
+func (m *OllamaModel) processStream(body io.Reader, yield func(*model.LLMResponse, error) bool) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024) // 1MB buffer
+
+	var text, thinking strings.Builder
+	var toolCalls []ToolCall
+	var last ChatResponse
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			modelLog.Warn("解析流式分片失败: %v", err)
+			continue
+		}
+		if chunk.Error != "" {
+			yield(nil, fmt.Errorf("Ollama API error: %s", chunk.Error))
+			return
+		}
+
+		if chunk.Message.Thinking != "" {
+			thinking.WriteString(chunk.Message.Thinking)
+			if !yield(partialResponse(&genai.Part{Text: chunk.Message.Thinking, Thought: true}), nil) {
+				return
+			}
+		}
+		if chunk.Message.Content != "" {
+			text.WriteString(chunk.Message.Content)
+			if !yield(partialResponse(&genai.Part{Text: chunk.Message.Content}), nil) {
+				return
+			}
+		}
+		toolCalls = append(toolCalls, chunk.Message.ToolCalls...)
+
+		if chunk.Done {
+			last = chunk
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if !errors.Is(err, context.Canceled) {
+			yield(nil, fmt.Errorf("流读取错误: %w", err))
+		}
+		return
+	}
+
+	// 发送最终聚合响应
+	last.Message = Message{
+		Role:      "assistant",
+		Content:   text.String(),
+		Thinking:  thinking.String(),
+		ToolCalls: toolCalls,
+	}
+	yield(convertChatResponse(&last), nil)
+}
+
+// partialResponse 构造流式增量响应
+func partialResponse(part *genai.Part) *model.LLMResponse {
+	return &model.LLMResponse{
+		Content:      &genai.Content{Role: "model", Parts: []*genai.Part{part}},
+		Partial:      true,
+		TurnComplete: false,
+	}
+}
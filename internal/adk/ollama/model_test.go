@@ -0,0 +1,190 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "empty uses localhost", in: "", want: DefaultBaseURL},
+		{name: "trim trailing slash", in: "http://gpu-box:11434/", want: "http://gpu-box:11434"},
+		{name: "strip openai v1", in: "http://localhost:11434/v1", want: "http://localhost:11434"},
+		{name: "strip api", in: "http://localhost:11434/api/", want: "http://localhost:11434"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizeBaseURL(tc.in); got != tc.want {
+				t.Fatalf("NormalizeBaseURL(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToChatRequest_ToolRoundTrip(t *testing.T) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: "user", Parts: []*genai.Part{{Text: "茅台现价"}}},
+			{Role: "model", Parts: []*genai.Part{
+				{Text: "思考中", Thought: true},
+				{FunctionCall: &genai.FunctionCall{ID: "ollama_call_1", Name: "get_stock_realtime", Args: map[string]any{"code": "sh600519"}}},
+			}},
+			{Role: "user", Parts: []*genai.Part{
+				{FunctionResponse: &genai.FunctionResponse{ID: "ollama_call_1", Name: "get_stock_realtime", Response: map[string]any{"price": 1500.5}}},
+			}},
+		},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "你是分析师"}}},
+			MaxOutputTokens:   256,
+			Tools: []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{
+				Name:        "get_stock_realtime",
+				Description: "实时行情",
+				Parameters: &genai.Schema{
+					Type:       genai.TypeObject,
+					Properties: map[string]*genai.Schema{"code": {Type: genai.TypeString}},
+				},
+			}}}},
+		},
+	}
+
+	cr, err := toChatRequest(req, "qwen2.5:7b", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	roles := make([]string, len(cr.Messages))
+	for i, m := range cr.Messages {
+		roles[i] = m.Role
+	}
+	if got := strings.Join(roles, ","); got != "system,user,assistant,tool" {
+		t.Fatalf("roles = %s", got)
+	}
+	assistant := cr.Messages[2]
+	if assistant.Content != "" || len(assistant.ToolCalls) != 1 || assistant.ToolCalls[0].Function.Arguments["code"] != "sh600519" {
+		t.Errorf("assistant message unexpected: %+v", assistant)
+	}
+	tool := cr.Messages[3]
+	if tool.ToolName != "get_stock_realtime" || !strings.Contains(tool.Content, "1500.5") {
+		t.Errorf("tool message unexpected: %+v", tool)
+	}
+	if cr.Options == nil || cr.Options.NumPredict != 256 {
+		t.Errorf("options = %+v, want num_predict 256", cr.Options)
+	}
+	if len(cr.Tools) != 1 || !strings.Contains(string(cr.Tools[0].Function.Parameters), `"type":"object"`) {
+		t.Errorf("tool schema not normalized: %s", cr.Tools[0].Function.Parameters)
+	}
+}
+
+func TestGenerateContent_Stream(t *testing.T) {
+	var gotReq ChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected Authorization header")
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		chunks := []string{
+			`{"message":{"role":"assistant","content":"","thinking":"先查行情"},"done":false}`,
+			`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_stock_realtime","arguments":{"code":"sh600519"}}}]},"done":false}`,
+			`{"message":{"role":"assistant","content":"茅台"},"done":false}`,
+			`{"message":{"role":"assistant","content":"上涨"},"done":true,"done_reason":"stop","prompt_eval_count":12,"eval_count":8}`,
+		}
+		for _, c := range chunks {
+			w.Write([]byte(c + "\n"))
+		}
+	}))
+	defer srv.Close()
+
+	m := NewOllamaModel("qwen2.5:7b", srv.URL, srv.Client(), false)
+	req := &model.LLMRequest{Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}}}
+
+	var partials []string
+	var final *model.LLMResponse
+	for resp, err := range m.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		if resp.Partial {
+			partials = append(partials, resp.Content.Parts[0].Text)
+			continue
+		}
+		final = resp
+	}
+
+	if !gotReq.Stream || gotReq.Model != "qwen2.5:7b" {
+		t.Errorf("request = %+v", gotReq)
+	}
+	if strings.Join(partials, "|") != "先查行情|茅台|上涨" {
+		t.Errorf("partials = %v", partials)
+	}
+	if final == nil || !final.TurnComplete {
+		t.Fatal("missing final response")
+	}
+	var text string
+	var call *genai.FunctionCall
+	for _, p := range final.Content.Parts {
+		if p.FunctionCall != nil {
+			call = p.FunctionCall
+		} else if !p.Thought {
+			text += p.Text
+		}
+	}
+	if text != "茅台上涨" {
+		t.Errorf("final text = %q", text)
+	}
+	if call == nil || call.Name != "get_stock_realtime" || call.ID == "" || call.Args["code"] != "sh600519" {
+		t.Errorf("function call = %+v", call)
+	}
+	if final.UsageMetadata == nil || final.UsageMetadata.TotalTokenCount != 20 {
+		t.Errorf("usage = %+v", final.UsageMetadata)
+	}
+}
+
+func TestGenerateContent_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model 'nope' not found"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	m := NewOllamaModel("nope", srv.URL, srv.Client(), false)
+	req := &model.LLMRequest{Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}}}
+	for _, err := range m.GenerateContent(context.Background(), req, false) {
+		if err == nil || !strings.Contains(err.Error(), "404") {
+			t.Fatalf("err = %v, want HTTP 404", err)
+		}
+	}
+}
+
+func TestToMessages_Images(t *testing.T) {
+	msgs, err := toMessages([]*genai.Content{{Role: "user", Parts: []*genai.Part{
+		genai.NewPartFromBytes([]byte("GIF89a"), "image/gif"),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || len(msgs[0].Images) != 1 || msgs[0].Images[0] != "R0lGODlh" {
+		t.Fatalf("仅含图片的消息应保留: %+v", msgs)
+	}
+
+	_, err = toMessages([]*genai.Content{{Role: "user", Parts: []*genai.Part{
+		genai.NewPartFromBytes([]byte("%PDF"), "application/pdf"),
+	}}})
+	if err == nil {
+		t.Fatal("非图片附件应返回错误")
+	}
+}
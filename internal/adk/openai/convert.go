@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -205,6 +206,15 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 			return openai.ChatCompletionRequest{}, err
 		}
 		openaiReq.Tools = tools
+		// 指定工具调用方式：ANY 要求必须调用工具，NONE 禁止调用
+		if fc := req.Config.ToolConfig; fc != nil && fc.FunctionCallingConfig != nil {
+			switch fc.FunctionCallingConfig.Mode {
+			case genai.FunctionCallingConfigModeAny:
+				openaiReq.ToolChoice = "required"
+			case genai.FunctionCallingConfigModeNone:
+				openaiReq.ToolChoice = "none"
+			}
+		}
 	}
 
 	// 应用配置
@@ -221,6 +231,10 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 		if len(req.Config.StopSequences) > 0 {
 			openaiReq.Stop = req.Config.StopSequences
 		}
+		if req.Config.Seed != nil {
+			seed := int(*req.Config.Seed)
+			openaiReq.Seed = &seed
+		}
 
 		// 处理系统指令
 		if req.Config.SystemInstruction != nil {
@@ -230,7 +244,14 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 				injected := false
 				for i, msg := range openaiMessages {
 					if msg.Role == openai.ChatMessageRoleUser {
-						openaiMessages[i].Content = systemText + "\n\n" + msg.Content
+						if len(msg.MultiContent) > 0 {
+							openaiMessages[i].MultiContent = append([]openai.ChatMessagePart{{
+								Type: openai.ChatMessagePartTypeText,
+								Text: systemText,
+							}}, msg.MultiContent...)
+						} else {
+							openaiMessages[i].Content = systemText + "\n\n" + msg.Content
+						}
 						injected = true
 						break
 					}
@@ -299,6 +320,7 @@ func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatComplet
 	// 收集各类内容
 	var textContent string
 	var reasoningContent string
+	var images []openai.ChatMessagePart
 	var toolCalls []openai.ToolCall
 
 	for _, part := range parts {
@@ -313,6 +335,18 @@ func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatComplet
 			textContent += part.Text
 		}
 
+		// 处理内联图片
+		if part.InlineData != nil {
+			url, err := imageDataURL(part.InlineData)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, openai.ChatMessagePart{
+				Type:     openai.ChatMessagePartTypeImageURL,
+				ImageURL: &openai.ChatMessageImageURL{URL: url},
+			})
+		}
+
 		// 处理函数调用
 		if part.FunctionCall != nil {
 			argsJSON, err := json.Marshal(part.FunctionCall.Args)
@@ -331,8 +365,16 @@ func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatComplet
 		}
 	}
 
-	// 设置消息内容
-	if textContent != "" {
+	// 设置消息内容，含图片时使用 MultiContent（与 Content 互斥）
+	if len(images) > 0 {
+		if textContent != "" {
+			openaiMsg.MultiContent = append(openaiMsg.MultiContent, openai.ChatMessagePart{
+				Type: openai.ChatMessagePartTypeText,
+				Text: textContent,
+			})
+		}
+		openaiMsg.MultiContent = append(openaiMsg.MultiContent, images...)
+	} else if textContent != "" {
 		openaiMsg.Content = textContent
 	}
 
@@ -348,6 +390,14 @@ func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatComplet
 	return append(toolRespMessages, openaiMsg), nil
 }
 
+// imageDataURL 将内联图片转为 data URL，非图片数据返回错误
+func imageDataURL(blob *genai.Blob) (string, error) {
+	if !strings.HasPrefix(blob.MIMEType, "image/") {
+		return "", fmt.Errorf("不支持的附件类型: %s", blob.MIMEType)
+	}
+	return "data:" + blob.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(blob.Data), nil
+}
+
 // convertRoleToOpenAI 转换角色
 func convertRoleToOpenAI(role string) string {
 	switch role {
@@ -28,6 +28,7 @@ type OpenAIModel struct {
 	Client       *openai.Client
 	ModelName    string
 	NoSystemRole bool // 不支持 system role 时需要降级处理
+	StrictTools  bool // 为满足约束的工具开启 strict function calling
 }
 
 // NewOpenAIModel 创建 OpenAI 模型
@@ -61,6 +62,9 @@ func (o *OpenAIModel) generate(ctx context.Context, req *model.LLMRequest) iter.
 			yield(nil, err)
 			return
 		}
+		if o.StrictTools {
+			applyStrictTools(openaiReq.Tools)
+		}
 
 		resp, err := o.Client.CreateChatCompletion(ctx, openaiReq)
 		if err != nil {
@@ -86,7 +90,12 @@ func (o *OpenAIModel) generateStream(ctx context.Context, req *model.LLMRequest)
 			yield(nil, err)
 			return
 		}
+		if o.StrictTools {
+			applyStrictTools(openaiReq.Tools)
+		}
 		openaiReq.Stream = true
+		// 流式模式默认不返回用量，需显式开启（用量在最后一个无 choices 的 chunk 中）
+		openaiReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
 
 		stream, err := o.Client.CreateChatCompletionStream(ctx, openaiReq)
 		if err != nil {
@@ -94,13 +103,16 @@ func (o *OpenAIModel) generateStream(ctx context.Context, req *model.LLMRequest)
 			return
 		}
 		defer stream.Close()
+		// 取消时立即断开连接，避免服务端继续生成并计费
+		stop := context.AfterFunc(ctx, func() { stream.Close() })
+		defer stop()
 
-		o.processStream(stream, yield)
+		o.processStream(ctx, stream, yield)
 	}
 }
 
 // processStream 处理流式响应
-func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield func(*model.LLMResponse, error) bool) {
+func (o *OpenAIModel) processStream(ctx context.Context, stream *openai.ChatCompletionStream, yield func(*model.LLMResponse, error) bool) {
 	aggregatedContent := &genai.Content{
 		Role:  "model",
 		Parts: []*genai.Part{},
@@ -134,6 +146,10 @@ func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield f
 	var streamErr error
 	for {
 		chunk, err := stream.Recv()
+		// 取消后连接已被关闭，读错误以 ctx 为准
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
 		if errors.Is(err, context.Canceled) {
 			return
 		}
@@ -145,6 +161,14 @@ func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield f
 			break
 		}
 
+		if chunk.Usage != nil {
+			usageMetadata = &genai.GenerateContentResponseUsageMetadata{
+				PromptTokenCount:     int32(chunk.Usage.PromptTokens),
+				CandidatesTokenCount: int32(chunk.Usage.CompletionTokens),
+				TotalTokenCount:      int32(chunk.Usage.TotalTokens),
+			}
+		}
+
 		if len(chunk.Choices) == 0 {
 			continue
 		}
@@ -192,14 +216,6 @@ func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield f
 		if choice.FinishReason != "" {
 			finishReason = convertFinishReason(string(choice.FinishReason))
 		}
-
-		if chunk.Usage != nil {
-			usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-				PromptTokenCount:     int32(chunk.Usage.PromptTokens),
-				CandidatesTokenCount: int32(chunk.Usage.CompletionTokens),
-				TotalTokenCount:      int32(chunk.Usage.TotalTokens),
-			}
-		}
 	}
 
 	// 刷新流式标签解析器（处理标签跨 chunk 场景）
@@ -0,0 +1,285 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// newTestModel 创建指向 httptest 服务的模型
+func newTestModel(t *testing.T, handler http.HandlerFunc) *OpenAIModel {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	cfg := openai.DefaultConfig("test-key")
+	cfg.BaseURL = srv.URL + "/v1"
+	return NewOpenAIModel("deepseek-reasoner", cfg, false)
+}
+
+func userRequest() *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "茅台怎么看"}}}},
+	}
+}
+
+func TestGenerateStream_ReasoningContent(t *testing.T) {
+	deltas := []string{
+		`{"role":"assistant","reasoning_content":"先看"}`,
+		`{"reasoning_content":"估值"}`,
+		`{"content":"估值"}`,
+		`{"reasoning_content":"，再看资金"}`,
+		`{"content":"偏高"}`,
+	}
+	m := newTestModel(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, d := range deltas {
+			fmt.Fprintf(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":%s}]}\n\n", d)
+		}
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[],\"usage\":{\"prompt_tokens\":12,\"completion_tokens\":8,\"total_tokens\":20}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	var partials []*genai.Part
+	var final *model.LLMResponse
+	for resp, err := range m.GenerateContent(context.Background(), userRequest(), true) {
+		if err != nil {
+			t.Fatalf("stream error: %v", err)
+		}
+		if resp.Partial {
+			partials = append(partials, resp.Content.Parts...)
+		} else {
+			final = resp
+		}
+	}
+
+	var thought, text strings.Builder
+	for _, p := range partials {
+		if p.Thought {
+			thought.WriteString(p.Text)
+		} else {
+			text.WriteString(p.Text)
+		}
+	}
+	if len(partials) != 5 || thought.String() != "先看估值，再看资金" || text.String() != "估值偏高" {
+		t.Errorf("partials thought=%q text=%q (%d parts)", thought.String(), text.String(), len(partials))
+	}
+
+	if final == nil || len(final.Content.Parts) != 2 {
+		t.Fatalf("final = %+v", final)
+	}
+	if p := final.Content.Parts[0]; !p.Thought || p.Text != "先看估值，再看资金" {
+		t.Errorf("final thought part = %+v", p)
+	}
+	if p := final.Content.Parts[1]; p.Thought || p.Text != "估值偏高" {
+		t.Errorf("final text part = %+v", p)
+	}
+	if final.FinishReason != genai.FinishReasonStop || final.UsageMetadata == nil || final.UsageMetadata.TotalTokenCount != 20 {
+		t.Errorf("finish=%v usage=%+v", final.FinishReason, final.UsageMetadata)
+	}
+}
+
+func TestGenerate_ReasoningContent(t *testing.T) {
+	m := newTestModel(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","reasoning_content":"先看估值","content":"估值偏高"}}]}`)
+	})
+
+	var final *model.LLMResponse
+	for resp, err := range m.GenerateContent(context.Background(), userRequest(), false) {
+		if err != nil {
+			t.Fatalf("generate error: %v", err)
+		}
+		final = resp
+	}
+	if final == nil || len(final.Content.Parts) != 2 {
+		t.Fatalf("final = %+v", final)
+	}
+	if p := final.Content.Parts[0]; !p.Thought || p.Text != "先看估值" {
+		t.Errorf("thought part = %+v", p)
+	}
+	if p := final.Content.Parts[1]; p.Thought || p.Text != "估值偏高" {
+		t.Errorf("text part = %+v", p)
+	}
+}
+
+// detachedTransport 丢弃请求 ctx，模拟不遵守取消的 Transport
+type detachedTransport struct{}
+
+func (detachedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return http.DefaultTransport.RoundTrip(req.WithContext(context.Background()))
+}
+
+func TestGenerateStream_CancelClosesConnection(t *testing.T) {
+	detached := &http.Client{Transport: detachedTransport{}}
+	tests := []struct {
+		name  string
+		first string // 第一个分片，之后服务端挂起直到连接断开
+		build func(url string) model.LLM
+	}{
+		{
+			name:  "chat",
+			first: "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"先看\"}}]}\n\n",
+			build: func(url string) model.LLM {
+				cfg := openai.DefaultConfig("test-key")
+				cfg.BaseURL = url + "/v1"
+				cfg.HTTPClient = detached
+				return NewOpenAIModel("gpt-4o", cfg, false)
+			},
+		},
+		{
+			name:  "responses",
+			first: "event: response.output_text.delta\ndata: {\"delta\":\"先看\"}\n\n",
+			build: func(url string) model.LLM {
+				return NewResponsesModel("gpt-4o", "test-key", url+"/v1", detached, false)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			closed := make(chan time.Time, 1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprint(w, tt.first)
+				w.(http.Flusher).Flush()
+				select {
+				case <-r.Context().Done():
+					closed <- time.Now()
+				case <-time.After(10 * time.Second):
+				}
+			}))
+			defer srv.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var cancelledAt time.Time
+			for resp, err := range tt.build(srv.URL).GenerateContent(ctx, userRequest(), true) {
+				if err != nil {
+					t.Fatalf("取消不应返回错误: %v", err)
+				}
+				if resp.Partial && cancelledAt.IsZero() {
+					cancelledAt = time.Now()
+					cancel()
+				}
+			}
+			if cancelledAt.IsZero() {
+				t.Fatal("未收到流式分片")
+			}
+
+			select {
+			case at := <-closed:
+				if d := at.Sub(cancelledAt); d > time.Second {
+					t.Errorf("连接在取消 %v 后才关闭", d)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("取消 1 秒后连接仍未关闭")
+			}
+		})
+	}
+}
+
+func TestConvert_ImageParts(t *testing.T) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{
+			{Text: "看图"},
+			genai.NewPartFromBytes([]byte{0xff, 0xd8, 0xff}, "image/jpeg"),
+		}}},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "你是K线王"}}},
+		},
+	}
+	const dataURL = "data:image/jpeg;base64,/9j/"
+
+	// chat/completions：含图片时使用 MultiContent，不支持 system role 时系统指令作为首个文本块
+	chatReq, err := toOpenAIChatCompletionRequest(req, "gpt-4o", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := chatReq.Messages[0]
+	if msg.Content != "" || len(msg.MultiContent) != 3 {
+		t.Fatalf("msg = %+v", msg)
+	}
+	if msg.MultiContent[0].Text != "你是K线王" || msg.MultiContent[1].Text != "看图" ||
+		msg.MultiContent[2].Type != openai.ChatMessagePartTypeImageURL || msg.MultiContent[2].ImageURL.URL != dataURL {
+		t.Fatalf("multi content = %+v", msg.MultiContent)
+	}
+
+	// Responses API：input_text + input_image
+	respReq, err := toResponsesRequest(req, "gpt-4o", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, ok := respReq.Input.([]ResponsesInputItem)
+	if !ok || len(items) != 1 {
+		t.Fatalf("input = %#v", respReq.Input)
+	}
+	parts, ok := items[0].Content.([]ResponsesInputContentPart)
+	if !ok || len(parts) != 2 || parts[0].Type != "input_text" || parts[1].Type != "input_image" || parts[1].ImageURL != dataURL {
+		t.Fatalf("content = %#v", items[0].Content)
+	}
+}
+
+func TestResponses_ForcedToolChoice(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		http.Error(w, "stub", http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+	m := NewResponsesModel("gpt-4o", "test-key", srv.URL, nil, false)
+	m.ForcedToolName = "get_research_report"
+
+	tools := []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{
+		{Name: "get_research_report"}, {Name: "get_news"},
+	}}}
+	req := userRequest()
+	req.Config = &genai.GenerateContentConfig{Tools: tools}
+	for range m.GenerateContent(context.Background(), req, false) {
+	}
+
+	// 已调用过该工具后不再强制
+	called := userRequest()
+	called.Config = &genai.GenerateContentConfig{Tools: tools}
+	called.Contents = append(called.Contents,
+		&genai.Content{Role: "model", Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "c1", Name: "get_research_report"}}}},
+		&genai.Content{Role: "user", Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{ID: "c1", Name: "get_research_report", Response: map[string]any{"data": "..."}}}}},
+	)
+	for range m.GenerateContent(context.Background(), called, false) {
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("requests = %d", len(bodies))
+	}
+	if !strings.Contains(bodies[0], `"tool_choice":{"type":"function","name":"get_research_report"}`) {
+		t.Errorf("forced request = %s", bodies[0])
+	}
+	if strings.Contains(bodies[1], `"tool_choice"`) {
+		t.Errorf("tool_choice should be cleared after the call: %s", bodies[1])
+	}
+
+	// 专家未配置该工具时不强制；ToolConfig 指定单个函数时映射为命名 tool_choice
+	other := userRequest()
+	other.Config = &genai.GenerateContentConfig{
+		Tools: []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{Name: "get_news"}}}},
+		ToolConfig: &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+			Mode: genai.FunctionCallingConfigModeAny, AllowedFunctionNames: []string{"get_news"},
+		}},
+	}
+	apiReq, err := toResponsesRequest(other, "gpt-4o", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyForcedToolChoice(&apiReq, m.ForcedToolName, other.Contents)
+	if choice, ok := apiReq.ToolChoice.(ResponsesToolChoice); !ok || choice.Name != "get_news" {
+		t.Errorf("tool_choice = %#v", apiReq.ToolChoice)
+	}
+}
@@ -33,9 +33,12 @@ func toResponsesRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 			injected := false
 			for i, item := range inputItems {
 				if item.Role == "user" {
-					if s, ok := item.Content.(string); ok {
-						inputItems[i].Content = systemText + "\n\n" + s
-					} else {
+					switch c := item.Content.(type) {
+					case string:
+						inputItems[i].Content = systemText + "\n\n" + c
+					case []ResponsesInputContentPart:
+						inputItems[i].Content = append([]ResponsesInputContentPart{{Type: "input_text", Text: systemText}}, c...)
+					default:
 						inputItems[i].Content = systemText
 					}
 					injected = true
@@ -71,6 +74,19 @@ func toResponsesRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 	// 转换工具定义
 	if len(req.Config.Tools) > 0 {
 		apiReq.Tools = convertResponsesTools(req.Config.Tools)
+		// 指定工具调用方式：ANY 要求必须调用工具（只允许一个函数时指定该函数），NONE 禁止调用
+		if fc := req.Config.ToolConfig; fc != nil && fc.FunctionCallingConfig != nil {
+			switch fc.FunctionCallingConfig.Mode {
+			case genai.FunctionCallingConfigModeAny:
+				if names := fc.FunctionCallingConfig.AllowedFunctionNames; len(names) == 1 {
+					apiReq.ToolChoice = ResponsesToolChoice{Type: "function", Name: names[0]}
+				} else {
+					apiReq.ToolChoice = "required"
+				}
+			case genai.FunctionCallingConfigModeNone:
+				apiReq.ToolChoice = "none"
+			}
+		}
 	}
 
 	// 应用生成参数
@@ -92,6 +108,44 @@ func toResponsesRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 	return apiReq, nil
 }
 
+// applyForcedToolChoice 本轮尚未调用过 name 工具时强制调用它，调用过后恢复由模型自行决定
+// 请求未携带该工具（专家未配置）时不生效
+func applyForcedToolChoice(apiReq *CreateResponseRequest, name string, contents []*genai.Content) {
+	if name == "" {
+		return
+	}
+	available := false
+	for _, t := range apiReq.Tools {
+		if t.Name == name {
+			available = true
+			break
+		}
+	}
+	if !available {
+		return
+	}
+	// 从最近一条用户提问往后查找是否已调用过该工具
+	for i := len(contents) - 1; i >= 0; i-- {
+		c := contents[i]
+		if c == nil {
+			continue
+		}
+		userText := false
+		for _, part := range c.Parts {
+			if part.FunctionCall != nil && part.FunctionCall.Name == name {
+				return
+			}
+			if c.Role == "user" && part.Text != "" {
+				userText = true
+			}
+		}
+		if userText {
+			break
+		}
+	}
+	apiReq.ToolChoice = ResponsesToolChoice{Type: "function", Name: name}
+}
+
 // toResponsesInputItems 将 genai.Content 列表转换为 Responses API input
 func toResponsesInputItems(contents []*genai.Content) ([]ResponsesInputItem, error) {
 	var items []ResponsesInputItem
@@ -126,8 +180,9 @@ func toResponsesInputItem(content *genai.Content) ([]ResponsesInputItem, error)
 		}
 	}
 
-	// 收集文本、reasoning、函数调用
+	// 收集文本、图片、函数调用
 	var textContent string
+	var images []ResponsesInputContentPart
 	var toolCallItems []ResponsesInputItem
 
 	for _, part := range content.Parts {
@@ -137,6 +192,13 @@ func toResponsesInputItem(content *genai.Content) ([]ResponsesInputItem, error)
 		if part.Text != "" && !part.Thought {
 			textContent += part.Text
 		}
+		if part.InlineData != nil {
+			url, err := imageDataURL(part.InlineData)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, ResponsesInputContentPart{Type: "input_image", ImageURL: url})
+		}
 		if part.FunctionCall != nil {
 			argsJSON, err := json.Marshal(part.FunctionCall.Args)
 			if err != nil {
@@ -154,7 +216,17 @@ func toResponsesInputItem(content *genai.Content) ([]ResponsesInputItem, error)
 
 	// 构建普通消息
 	role := convertRoleForResponses(content.Role)
-	if textContent != "" {
+	if len(images) > 0 {
+		// 含图片时 content 使用数组形式，文本在前
+		var parts []ResponsesInputContentPart
+		if textContent != "" {
+			parts = append(parts, ResponsesInputContentPart{Type: "input_text", Text: textContent})
+		}
+		items = append(items, ResponsesInputItem{
+			Role:    role,
+			Content: append(parts, images...),
+		})
+	} else if textContent != "" {
 		items = append(items, ResponsesInputItem{
 			Role:    role,
 			Content: textContent,
@@ -269,7 +341,22 @@ func convertResponsesResponse(resp *CreateResponseResponse) (*model.LLMResponse,
 	return &model.LLMResponse{
 		Content:       content,
 		UsageMetadata: usageMetadata,
-		FinishReason:  genai.FinishReasonStop,
+		FinishReason:  responsesFinishReason(resp),
 		TurnComplete:  true,
 	}, nil
 }
+
+// responsesFinishReason 根据响应状态得出结束原因，未完成时区分长度截断与内容过滤
+func responsesFinishReason(resp *CreateResponseResponse) genai.FinishReason {
+	if resp.Status != "incomplete" || resp.IncompleteDetails == nil {
+		return genai.FinishReasonStop
+	}
+	switch resp.IncompleteDetails.Reason {
+	case "max_output_tokens":
+		return genai.FinishReasonMaxTokens
+	case "content_filter":
+		return genai.FinishReasonSafety
+	default:
+		return genai.FinishReasonOther
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
@@ -15,6 +16,7 @@ import (
 	"google.golang.org/genai"
 
 	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/pkg/httpbody"
 )
 
 var respLog = logger.New("openai:responses")
@@ -36,6 +38,11 @@ type ResponsesModel struct {
 	apiKey       string
 	modelName    string
 	NoSystemRole bool // 不支持 system role 时需要降级处理
+	StrictTools  bool // 为满足约束的工具开启 strict function calling
+	// 每轮回答前强制先调用的工具名，空表示不强制
+	ForcedToolName string
+
+	azureEndpoint string // Azure OpenAI 完整端点（含 api-version），非空时使用 api-key 鉴权
 }
 
 // NewResponsesModel 创建 Responses API 模型
@@ -65,8 +72,16 @@ func (r *ResponsesModel) GenerateContent(ctx context.Context, req *model.LLMRequ
 	return r.generate(ctx, req)
 }
 
+// SetAzureEndpoint 切换为 Azure OpenAI 端点
+func (r *ResponsesModel) SetAzureEndpoint(endpoint string) {
+	r.azureEndpoint = endpoint
+}
+
 // responsesEndpoint 返回 Responses API 端点 URL
 func (r *ResponsesModel) responsesEndpoint() string {
+	if r.azureEndpoint != "" {
+		return r.azureEndpoint
+	}
 	return r.baseURL + "/responses"
 }
 
@@ -77,7 +92,11 @@ func (r *ResponsesModel) doRequest(ctx context.Context, body []byte, stream bool
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	if r.azureEndpoint != "" {
+		req.Header.Set("api-key", r.apiKey)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) CherryStudio/1.2.4 Chrome/126.0.6478.234 Electron/31.7.6 Safari/537.36")
 	if stream {
 		req.Header.Set("Accept", "text/event-stream")
@@ -95,6 +114,10 @@ func (r *ResponsesModel) generate(ctx context.Context, req *model.LLMRequest) it
 			yield(nil, err)
 			return
 		}
+		if r.StrictTools {
+			applyStrictResponsesTools(apiReq.Tools)
+		}
+		applyForcedToolChoice(&apiReq, r.ForcedToolName, req.Contents)
 		apiReq.Stream = false
 
 		body, err := json.Marshal(apiReq)
@@ -139,6 +162,10 @@ func (r *ResponsesModel) generateStream(ctx context.Context, req *model.LLMReque
 			yield(nil, err)
 			return
 		}
+		if r.StrictTools {
+			applyStrictResponsesTools(apiReq.Tools)
+		}
+		applyForcedToolChoice(&apiReq, r.ForcedToolName, req.Contents)
 		apiReq.Stream = true
 
 		body, err := json.Marshal(apiReq)
@@ -160,7 +187,11 @@ func (r *ResponsesModel) generateStream(ctx context.Context, req *model.LLMReque
 			return
 		}
 
-		r.processResponsesStream(resp.Body, yield)
+		// 取消时立即断开连接，避免服务端继续生成并计费
+		sse, stop := httpbody.CloseOnCancel(ctx, resp.Body)
+		defer stop()
+
+		r.processResponsesStream(sse, yield)
 	}
 }
 
@@ -175,6 +206,7 @@ func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*mode
 	toolCallsMap := make(map[string]*responsesToolCallBuilder)
 	var toolCallOrder []string
 	var usageMetadata *genai.GenerateContentResponseUsageMetadata
+	finishReason := genai.FinishReasonStop
 	var currentEventType string
 	thinkParser := newThinkTagStreamParser()
 
@@ -201,16 +233,18 @@ func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*mode
 			r.handleOutputItemAdded(data, toolCallsMap, &toolCallOrder)
 		case "response.output_item.done":
 			r.handleOutputItemDone(data, toolCallsMap, &toolCallOrder)
-		case "response.completed":
-			r.handleCompleted(data, &usageMetadata)
+		case "response.completed", "response.incomplete":
+			r.handleCompleted(data, &usageMetadata, &finishReason)
 		}
 
 		currentEventType = ""
 	}
 
 	if err := scanner.Err(); err != nil {
-		respLog.Warn("SSE 流读取错误: %v", err)
-		yield(nil, fmt.Errorf("SSE 流读取错误: %w", err))
+		if !errors.Is(err, context.Canceled) {
+			respLog.Warn("SSE 流读取错误: %v", err)
+			yield(nil, fmt.Errorf("SSE 流读取错误: %w", err))
+		}
 		return
 	}
 
@@ -258,7 +292,7 @@ func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*mode
 	finalResp := &model.LLMResponse{
 		Content:       aggregatedContent,
 		UsageMetadata: usageMetadata,
-		FinishReason:  genai.FinishReasonStop,
+		FinishReason:  finishReason,
 		Partial:       false,
 		TurnComplete:  true,
 	}
@@ -372,13 +406,14 @@ func (r *ResponsesModel) handleOutputItemDone(data string, toolCallsMap map[stri
 	}
 }
 
-// handleCompleted 处理 response.completed 事件
-func (r *ResponsesModel) handleCompleted(data string, usageMetadata **genai.GenerateContentResponseUsageMetadata) {
+// handleCompleted 处理 response.completed / response.incomplete 事件
+func (r *ResponsesModel) handleCompleted(data string, usageMetadata **genai.GenerateContentResponseUsageMetadata, finishReason *genai.FinishReason) {
 	var completed ResponsesCompleted
 	if err := json.Unmarshal([]byte(data), &completed); err != nil {
 		respLog.Warn("解析完成事件失败: %v", err)
 		return
 	}
+	*finishReason = responsesFinishReason(&completed.Response)
 	if completed.Response.Usage != nil {
 		*usageMetadata = &genai.GenerateContentResponseUsageMetadata{
 			PromptTokenCount:     int32(completed.Response.Usage.InputTokens),
@@ -8,6 +8,7 @@ type CreateResponseRequest struct {
 	Input              any                 `json:"input"`                         // string 或 []ResponsesInputItem
 	Instructions       string              `json:"instructions,omitempty"`
 	Tools              []ResponsesTool     `json:"tools,omitempty"`
+	ToolChoice         any                 `json:"tool_choice,omitempty"` // "auto"/"required"/"none" 或 ResponsesToolChoice
 	Stream             bool                `json:"stream,omitempty"`
 	MaxOutputTokens    int                 `json:"max_output_tokens,omitempty"`
 	Temperature        *float32            `json:"temperature,omitempty"`
@@ -31,6 +32,13 @@ type ResponsesInputItem struct {
 	Arguments string `json:"arguments,omitempty"`
 }
 
+// ResponsesInputContentPart 输入消息 content 数组中的一项（含图片时使用）
+type ResponsesInputContentPart struct {
+	Type     string `json:"type"`                // "input_text", "input_image"
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"` // 图片 URL 或 data URL
+}
+
 // ResponsesTool Responses API 工具定义（扁平化，name 在顶层）
 type ResponsesTool struct {
 	Type        string `json:"type"`                  // "function"
@@ -40,6 +48,12 @@ type ResponsesTool struct {
 	Strict      bool   `json:"strict,omitempty"`
 }
 
+// ResponsesToolChoice 指定必须调用的函数
+type ResponsesToolChoice struct {
+	Type string `json:"type"` // "function"
+	Name string `json:"name"`
+}
+
 // ResponsesReasoning 推理/思考配置
 type ResponsesReasoning struct {
 	Effort string `json:"effort,omitempty"` // "low", "medium", "high"
@@ -58,6 +72,13 @@ type CreateResponseResponse struct {
 	Output     []ResponsesOutputItem `json:"output"`
 	OutputText string                `json:"output_text"`
 	Usage      *ResponsesUsage       `json:"usage,omitempty"`
+
+	IncompleteDetails *ResponsesIncompleteDetails `json:"incomplete_details,omitempty"` // status 为 incomplete 时的原因
+}
+
+// ResponsesIncompleteDetails 响应未完成的原因
+type ResponsesIncompleteDetails struct {
+	Reason string `json:"reason"` // "max_output_tokens", "content_filter"
 }
 
 // ResponsesOutputItem output 数组中的一项
@@ -121,6 +142,7 @@ type ResponsesOutputItemDone struct {
 }
 
 // ResponsesCompleted 响应完成事件 (response.completed)
+// 同样用于 response.incomplete 事件
 type ResponsesCompleted struct {
 	Type     string                 `json:"type"`
 	Response CreateResponseResponse `json:"response"`
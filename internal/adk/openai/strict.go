@@ -0,0 +1,68 @@
+package openai
+
+import (
+	"encoding/json"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// strictCompatible 判断参数 schema 是否满足 strict function calling 的约束：
+// 每个对象都禁止额外字段，且全部属性列入 required
+func strictCompatible(params any) bool {
+	if params == nil {
+		return false
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return false
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return false
+	}
+	return strictNode(schema)
+}
+
+func strictNode(node map[string]any) bool {
+	if props, ok := node["properties"].(map[string]any); ok {
+		if ap, ok := node["additionalProperties"].(bool); !ok || ap {
+			return false
+		}
+		required := map[string]bool{}
+		if list, ok := node["required"].([]any); ok {
+			for _, r := range list {
+				if name, ok := r.(string); ok {
+					required[name] = true
+				}
+			}
+		}
+		for name, p := range props {
+			child, ok := p.(map[string]any)
+			if !required[name] || !ok || !strictNode(child) {
+				return false
+			}
+		}
+	}
+	if items, ok := node["items"].(map[string]any); ok {
+		return strictNode(items)
+	}
+	return true
+}
+
+// applyStrictTools 为满足约束的工具开启 strict 模式
+func applyStrictTools(tools []openai.Tool) {
+	for i := range tools {
+		if f := tools[i].Function; f != nil && strictCompatible(f.Parameters) {
+			f.Strict = true
+		}
+	}
+}
+
+// applyStrictResponsesTools 为满足约束的 Responses 工具开启 strict 模式
+func applyStrictResponsesTools(tools []ResponsesTool) {
+	for i := range tools {
+		if strictCompatible(tools[i].Parameters) {
+			tools[i].Strict = true
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package adk
+
+import (
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 通义千问（阿里云百炼 DashScope）默认配置
+const (
+	DefaultQwenBaseURL = "https://dashscope.aliyuncs.com/compatible-mode/v1"
+	QwenDefaultModel   = "qwen-plus"
+)
+
+// normalizeQwenBaseURL 规范化 DashScope 兼容模式地址
+// 官方路径 /compatible-mode/v1 已含版本号，去掉用户误填的接口后缀，避免重复追加 /v1
+func normalizeQwenBaseURL(baseURL string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return DefaultQwenBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/chat/completions")
+	if strings.HasSuffix(baseURL, "/compatible-mode") {
+		baseURL += "/v1"
+	}
+	return baseURL
+}
+
+// qwenConfig 补全通义千问默认值，返回可交给 OpenAI 兼容实现的配置副本
+// 兼容模式仅提供 chat/completions，不支持 Responses API / Azure
+func qwenConfig(config *models.AIConfig) *models.AIConfig {
+	c := *config
+	c.BaseURL = normalizeQwenBaseURL(c.BaseURL)
+	if strings.TrimSpace(c.ModelName) == "" {
+		c.ModelName = QwenDefaultModel
+	}
+	c.UseResponses = false
+	c.IsAzure = false
+	// Qwen3 系列推理内容以 <think> 块输出，由 thinkTagStreamParser 处理；原生支持 system role
+	c.NoSystemRole = false
+	return &c
+}
@@ -0,0 +1,172 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	go_openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 模型请求重试默认参数（AIConfig.RetryMaxAttempts / RetryBaseDelayMs 为 0 时使用）
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = time.Second
+	RetryMaxDelay           = 16 * time.Second
+)
+
+// httpStatusPattern 从各 Provider 的错误信息中提取 HTTP 状态码，如 "HTTP 429: ..."、"status code: 503"
+var httpStatusPattern = regexp.MustCompile(`(?i)(?:HTTP|status code:?)\s*(\d{3})\b`)
+
+// RetryingLLM 对临时性 HTTP 错误（429、500、502、503）自动重试的模型包装
+// 只在尚未向调用方输出任何数据时重试；流式输出中途出错时原样返回错误，避免重复内容
+type RetryingLLM struct {
+	model.LLM
+	MaxRetries int           // 最大重试次数（不含首次请求）
+	BaseDelay  time.Duration // 指数退避基础延迟，第 n 次重试等待 BaseDelay*2^(n-1)，上限 RetryMaxDelay
+
+	sleep func(ctx context.Context, d time.Duration) error // 测试替换
+}
+
+// NewRetryingLLM 按 AI 配置包装重试，RetryMaxAttempts 为负数时原样返回
+func NewRetryingLLM(llm model.LLM, config *models.AIConfig) model.LLM {
+	if llm == nil {
+		return nil
+	}
+	if _, ok := llm.(*RetryingLLM); ok {
+		return llm
+	}
+	retries, base := DefaultRetryMaxAttempts, DefaultRetryBaseDelay
+	if config != nil {
+		if config.RetryMaxAttempts < 0 {
+			return llm
+		}
+		if config.RetryMaxAttempts > 0 {
+			retries = config.RetryMaxAttempts
+		}
+		if config.RetryBaseDelayMs > 0 {
+			base = time.Duration(config.RetryBaseDelayMs) * time.Millisecond
+		}
+	}
+	return &RetryingLLM{LLM: llm, MaxRetries: retries, BaseDelay: base}
+}
+
+// GenerateContent 转发请求，首个数据块之前遇到临时错误时退避后重试
+func (m *RetryingLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for attempt := 0; ; attempt++ {
+			yielded := false
+			var retryErr error
+			for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+				if err != nil && !yielded && attempt < m.MaxRetries && ctx.Err() == nil && IsTransientLLMError(err) {
+					retryErr = err
+					break
+				}
+				yielded = true
+				if !yield(resp, err) {
+					return
+				}
+			}
+			if retryErr == nil {
+				return
+			}
+
+			delay := m.backoff(attempt + 1)
+			log.Warn("模型请求临时错误，%v 后第 %d/%d 次重试: %v", delay, attempt+1, m.MaxRetries, retryErr)
+			if onWait := retryWaitFrom(ctx); onWait != nil {
+				onWait(delay)
+			}
+			if err := m.wait(ctx, delay); err != nil {
+				yield(nil, retryErr)
+				return
+			}
+		}
+	}
+}
+
+type retryWaitKey struct{}
+
+// WithRetryWait 返回携带重试等待通知的 context，每次退避前以等待时长调用 onWait
+// 调用方可据此延长自身超时，避免退避等待占满超时导致重试中途被取消
+func WithRetryWait(ctx context.Context, onWait func(delay time.Duration)) context.Context {
+	return context.WithValue(ctx, retryWaitKey{}, onWait)
+}
+
+func retryWaitFrom(ctx context.Context) func(time.Duration) {
+	onWait, _ := ctx.Value(retryWaitKey{}).(func(time.Duration))
+	return onWait
+}
+
+// backoff 第 n 次重试的等待时间：指数退避取上限后，在 [d/2, d) 内随机抖动，避免多位专家同时重试
+func (m *RetryingLLM) backoff(n int) time.Duration {
+	base := m.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	d := RetryMaxDelay
+	if n <= 5 {
+		d = min(base<<(n-1), RetryMaxDelay)
+	}
+	half := d / 2
+	return half + rand.N(d-half)
+}
+
+func (m *RetryingLLM) wait(ctx context.Context, d time.Duration) error {
+	if m.sleep != nil {
+		return m.sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// IsTransientLLMError 是否为可重试的临时错误（HTTP 429、500、502、503）
+func IsTransientLLMError(err error) bool {
+	switch llmErrorStatus(err) {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// llmErrorStatus 提取错误对应的 HTTP 状态码，无法识别时返回 0
+func llmErrorStatus(err error) int {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0
+	}
+	var apiErr *go_openai.APIError
+	if errors.As(err, &apiErr) && apiErr.HTTPStatusCode > 0 {
+		return apiErr.HTTPStatusCode
+	}
+	var reqErr *go_openai.RequestError
+	if errors.As(err, &reqErr) && reqErr.HTTPStatusCode > 0 {
+		return reqErr.HTTPStatusCode
+	}
+	var gErr genai.APIError
+	if errors.As(err, &gErr) {
+		return gErr.Code
+	}
+	var gErrPtr *genai.APIError
+	if errors.As(err, &gErrPtr) {
+		return gErrPtr.Code
+	}
+	if m := httpStatusPattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	go_openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// flakyLLM 前 failures 次调用返回 err，之后返回一段文本；partial 为 true 时先输出一块再报错
+type flakyLLM struct {
+	err      error
+	failures int
+	partial  bool
+	calls    int
+}
+
+func (f *flakyLLM) Name() string { return "flaky" }
+
+func (f *flakyLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		f.calls++
+		if f.calls <= f.failures {
+			if f.partial && !yield(&model.LLMResponse{Content: genai.NewContentFromText("部分", genai.RoleModel), Partial: true}, nil) {
+				return
+			}
+			yield(nil, f.err)
+			return
+		}
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func collect(llm model.LLM) (texts []string, errs []error) {
+	for resp, err := range llm.GenerateContent(context.Background(), &model.LLMRequest{}, true) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		texts = append(texts, resp.Content.Parts[0].Text)
+	}
+	return texts, errs
+}
+
+func newTestRetrying(inner model.LLM, config *models.AIConfig) (*RetryingLLM, *[]time.Duration) {
+	r := NewRetryingLLM(inner, config).(*RetryingLLM)
+	var delays []time.Duration
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return r, &delays
+}
+
+func TestRetryingLLM_RetriesTransientErrors(t *testing.T) {
+	inner := &flakyLLM{err: errors.New("HTTP 429: rate limited"), failures: 2}
+	r, delays := newTestRetrying(inner, nil)
+
+	texts, errs := collect(r)
+	if len(errs) != 0 || len(texts) != 1 || texts[0] != "ok" {
+		t.Fatalf("texts=%v errs=%v", texts, errs)
+	}
+	if inner.calls != 3 || len(*delays) != 2 {
+		t.Errorf("calls=%d delays=%v", inner.calls, *delays)
+	}
+}
+
+func TestRetryingLLM_GivesUpAfterMaxAttempts(t *testing.T) {
+	inner := &flakyLLM{err: errors.New("status code: 503"), failures: 10}
+	r, delays := newTestRetrying(inner, &models.AIConfig{RetryMaxAttempts: 2, RetryBaseDelayMs: 100})
+
+	_, errs := collect(r)
+	if len(errs) != 1 || inner.calls != 3 {
+		t.Fatalf("calls=%d errs=%v", inner.calls, errs)
+	}
+	for i, d := range *delays {
+		max := 100 * time.Millisecond << i
+		if d < max/2 || d >= max {
+			t.Errorf("delay[%d] = %v, want [%v, %v)", i, d, max/2, max)
+		}
+	}
+}
+
+func TestRetryingLLM_NoRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		inner  *flakyLLM
+		config *models.AIConfig
+	}{
+		{"非临时错误", &flakyLLM{err: errors.New("HTTP 400: bad request"), failures: 1}, nil},
+		{"已输出部分内容", &flakyLLM{err: errors.New("HTTP 502"), failures: 1, partial: true}, nil},
+		{"配置关闭重试", &flakyLLM{err: errors.New("HTTP 429"), failures: 1}, &models.AIConfig{RetryMaxAttempts: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := NewRetryingLLM(tt.inner, tt.config)
+			if r, ok := llm.(*RetryingLLM); ok {
+				r.sleep = func(context.Context, time.Duration) error { return nil }
+			}
+			if _, errs := collect(llm); len(errs) != 1 || tt.inner.calls != 1 {
+				t.Errorf("calls=%d errs=%v", tt.inner.calls, errs)
+			}
+		})
+	}
+}
+
+func TestRetryingLLM_CancelledWait(t *testing.T) {
+	inner := &flakyLLM{err: errors.New("HTTP 429"), failures: 10}
+	r := NewRetryingLLM(inner, nil).(*RetryingLLM)
+	r.sleep = func(context.Context, time.Duration) error { return context.Canceled }
+
+	if _, errs := collect(r); len(errs) != 1 || inner.calls != 1 {
+		t.Errorf("calls=%d errs=%v", inner.calls, errs)
+	}
+}
+
+func TestRetryingLLM_RetryWait(t *testing.T) {
+	inner := &flakyLLM{err: errors.New("HTTP 503"), failures: 2}
+	r, delays := newTestRetrying(inner, nil)
+	var notified []time.Duration
+	ctx := WithRetryWait(context.Background(), func(d time.Duration) { notified = append(notified, d) })
+
+	for _, err := range r.GenerateContent(ctx, &model.LLMRequest{}, false) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !slices.Equal(notified, *delays) || len(notified) != 2 {
+		t.Errorf("notified = %v, delays = %v", notified, *delays)
+	}
+}
+
+func TestNewRetryingLLM(t *testing.T) {
+	if NewRetryingLLM(nil, nil) != nil {
+		t.Error("nil 模型应原样返回")
+	}
+	r := NewRetryingLLM(&flakyLLM{}, nil).(*RetryingLLM)
+	if r.MaxRetries != DefaultRetryMaxAttempts || r.BaseDelay != DefaultRetryBaseDelay {
+		t.Errorf("defaults = %d/%v", r.MaxRetries, r.BaseDelay)
+	}
+	if NewRetryingLLM(r, &models.AIConfig{RetryMaxAttempts: 5}) != model.LLM(r) {
+		t.Error("不应重复包装")
+	}
+}
+
+func TestRetryingLLM_BackoffCap(t *testing.T) {
+	r := &RetryingLLM{BaseDelay: time.Second}
+	for n := 1; n <= 40; n++ {
+		if d := r.backoff(n); d <= 0 || d >= RetryMaxDelay {
+			t.Fatalf("backoff(%d) = %v", n, d)
+		}
+	}
+	if d := r.backoff(10); d < RetryMaxDelay/2 {
+		t.Errorf("backoff(10) = %v, want >= %v", d, RetryMaxDelay/2)
+	}
+}
+
+func TestIsTransientLLMError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&go_openai.APIError{HTTPStatusCode: 429}, true},
+		{fmt.Errorf("wrap: %w", &go_openai.RequestError{HTTPStatusCode: 502}), true},
+		{genai.APIError{Code: 503}, true},
+		{&genai.APIError{Code: 500}, true},
+		{errors.New("Anthropic API 错误 (HTTP 529): overloaded"), false},
+		{errors.New("HTTP 401: invalid key"), false},
+		{fmt.Errorf("timeout: %w", context.DeadlineExceeded), false},
+		{errors.New("connection reset"), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransientLLMError(tt.err); got != tt.want {
+			t.Errorf("IsTransientLLMError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
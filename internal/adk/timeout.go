@@ -0,0 +1,87 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"google.golang.org/adk/model"
+)
+
+// DefaultRequestTimeout 单次模型请求默认超时（AIConfig.Timeout 为 0 时使用）
+// 非流式为整次请求的截止时间，流式为两个数据块之间的最长空闲时间
+const DefaultRequestTimeout = 120 * time.Second
+
+// ErrRequestTimeout 模型请求超时
+var ErrRequestTimeout = errors.New("模型请求超时")
+
+// RequestTimeout 返回配置的单次请求超时
+func RequestTimeout(config *models.AIConfig) time.Duration {
+	if config != nil && config.Timeout > 0 {
+		return time.Duration(config.Timeout) * time.Second
+	}
+	return DefaultRequestTimeout
+}
+
+// timeoutLLM 为每次 GenerateContent 加超时的模型包装
+// 上游 ctx 的取消（如 CancelMeeting）照常传递
+type timeoutLLM struct {
+	model.LLM
+	timeout time.Duration
+}
+
+// withRequestTimeout 包装模型，timeout <= 0 时原样返回
+func withRequestTimeout(llm model.LLM, timeout time.Duration) model.LLM {
+	if llm == nil || timeout <= 0 {
+		return llm
+	}
+	return &timeoutLLM{LLM: llm, timeout: timeout}
+}
+
+// GenerateContent 非流式使用总截止时间；流式使用空闲超时，每收到一个数据块重新计时
+func (m *timeoutLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		timeoutErr := fmt.Errorf("%w（%v 内无响应）", ErrRequestTimeout, m.timeout)
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		var timer *time.Timer
+		if stream {
+			timer = time.AfterFunc(m.timeout, func() { cancel(timeoutErr) })
+		} else {
+			var cancelTimeout context.CancelFunc
+			ctx, cancelTimeout = context.WithTimeoutCause(ctx, m.timeout, timeoutErr)
+			defer cancelTimeout()
+		}
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		finished := false
+		for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+			if err != nil && errors.Is(context.Cause(ctx), ErrRequestTimeout) {
+				err = context.Cause(ctx)
+			}
+			finished = err != nil || (resp != nil && !resp.Partial)
+			// 下游处理数据块的耗时不计入空闲时间
+			if timer != nil {
+				timer.Stop()
+			}
+			if !yield(resp, err) {
+				return
+			}
+			if timer != nil {
+				timer.Reset(m.timeout)
+			}
+		}
+		// Provider 把取消视为正常结束、不返回错误，超时导致的中断需要在这里补报
+		if cause := context.Cause(ctx); !finished && errors.Is(cause, ErrRequestTimeout) {
+			yield(nil, cause)
+		}
+	}
+}
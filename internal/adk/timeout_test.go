@@ -0,0 +1,106 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+// slowLLM 按 delays 依次间隔输出数据块，等待期间响应 ctx 取消
+type slowLLM struct {
+	delays []time.Duration
+}
+
+func (m *slowLLM) Name() string { return "slow" }
+
+func (m *slowLLM) GenerateContent(ctx context.Context, _ *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for _, d := range m.delays {
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			}
+			if !yield(&model.LLMResponse{Partial: true}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// drain 读取全部数据块，返回块数与第一个错误
+func drain(llm model.LLM, ctx context.Context, stream bool) (int, error) {
+	n := 0
+	for _, err := range llm.GenerateContent(ctx, &model.LLMRequest{}, stream) {
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func TestRequestTimeout_StreamIdle(t *testing.T) {
+	// 总时长超过超时，但每个间隔都在空闲超时内
+	llm := withRequestTimeout(&slowLLM{delays: []time.Duration{30 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond}}, 80*time.Millisecond)
+	if n, err := drain(llm, context.Background(), true); err != nil || n != 3 {
+		t.Fatalf("stream: n = %d, err = %v", n, err)
+	}
+
+	// 中途卡住
+	llm = withRequestTimeout(&slowLLM{delays: []time.Duration{10 * time.Millisecond, time.Second}}, 50*time.Millisecond)
+	if n, err := drain(llm, context.Background(), true); !errors.Is(err, ErrRequestTimeout) || n != 1 {
+		t.Fatalf("stalled stream: n = %d, err = %v", n, err)
+	}
+}
+
+func TestRequestTimeout_NonStreamDeadline(t *testing.T) {
+	llm := withRequestTimeout(&slowLLM{delays: []time.Duration{30 * time.Millisecond, 30 * time.Millisecond}}, 40*time.Millisecond)
+	if _, err := drain(llm, context.Background(), false); !errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("err = %v, want ErrRequestTimeout", err)
+	}
+}
+
+func TestRequestTimeout_ParentCancel(t *testing.T) {
+	llm := withRequestTimeout(&slowLLM{delays: []time.Duration{time.Second}}, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err := drain(llm, ctx, true)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+// quietLLM 输出一个分片后挂起，取消时像真实 Provider 一样静默结束、不返回错误
+type quietLLM struct{}
+
+func (quietLLM) Name() string { return "quiet" }
+
+func (quietLLM) GenerateContent(ctx context.Context, _ *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if !yield(&model.LLMResponse{Partial: true}, nil) {
+			return
+		}
+		<-ctx.Done()
+	}
+}
+
+func TestRequestTimeout_QuietProviderStall(t *testing.T) {
+	llm := withRequestTimeout(quietLLM{}, 30*time.Millisecond)
+	if n, err := drain(llm, context.Background(), true); !errors.Is(err, ErrRequestTimeout) || n != 1 {
+		t.Fatalf("n = %d, err = %v, want ErrRequestTimeout", n, err)
+	}
+
+	// 上游取消仍然静默结束
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	llm = withRequestTimeout(quietLLM{}, time.Minute)
+	if n, err := drain(llm, ctx, true); err != nil || n != 1 {
+		t.Fatalf("parent cancel: n = %d, err = %v", n, err)
+	}
+}
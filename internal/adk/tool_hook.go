@@ -0,0 +1,87 @@
+package adk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/adk/tool"
+)
+
+// ToolCallHook 专家工具调用的开始/结束通知
+// 通过 WithToolCallHook 挂到传给 runner.Run 的 context 上，内置工具与 MCP 工具均会触发
+// MaxCalls 大于 0 时限制工具调用次数，超出上限或调用 Exhaust 后不再执行工具，直接回复模型基于已有信息作答
+type ToolCallHook struct {
+	OnStart  func(name string, args map[string]any)
+	OnFinish func(name string, result map[string]any, err error, elapsed time.Duration)
+	MaxCalls int
+
+	starts    sync.Map // FunctionCallID -> time.Time，同一轮可能并行调用多个工具
+	calls     atomic.Int32
+	exhausted atomic.Bool
+}
+
+// Calls 已发起的工具调用次数（含因预算被拒绝的调用）
+func (h *ToolCallHook) Calls() int {
+	return int(h.calls.Load())
+}
+
+// Exhaust 停止后续工具调用（如费用超出上限），已在执行的工具不受影响
+func (h *ToolCallHook) Exhaust() {
+	h.exhausted.Store(true)
+}
+
+// denied 本次调用是否超出工具预算，超出时返回回复给模型的提示
+func (h *ToolCallHook) denied() map[string]any {
+	n := int(h.calls.Add(1))
+	switch {
+	case h.exhausted.Load():
+		return map[string]any{"error": "工具调用预算已用完，请基于已获取的信息直接作答"}
+	case h.MaxCalls > 0 && n > h.MaxCalls:
+		return map[string]any{"error": fmt.Sprintf("工具调用次数已达上限 %d 次，请基于已获取的信息直接作答", h.MaxCalls)}
+	}
+	return nil
+}
+
+type toolCallHookKey struct{}
+
+// WithToolCallHook 返回携带工具调用通知的 context
+func WithToolCallHook(ctx context.Context, hook *ToolCallHook) context.Context {
+	return context.WithValue(ctx, toolCallHookKey{}, hook)
+}
+
+func toolCallHookFrom(ctx context.Context) *ToolCallHook {
+	hook, _ := ctx.Value(toolCallHookKey{}).(*ToolCallHook)
+	return hook
+}
+
+// beforeToolCall 工具执行前回调，超出工具预算时返回非 nil 结果跳过工具执行，否则只做通知
+func beforeToolCall(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	hook := toolCallHookFrom(ctx)
+	if hook == nil {
+		return nil, nil
+	}
+	hook.starts.Store(ctx.FunctionCallID(), time.Now())
+	if hook.OnStart != nil {
+		hook.OnStart(t.Name(), args)
+	}
+	return hook.denied(), nil
+}
+
+// afterToolCall 工具执行后回调（成功或失败均触发），只做通知，不改变结果
+func afterToolCall(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+	hook := toolCallHookFrom(ctx)
+	if hook == nil {
+		return nil, nil
+	}
+	var elapsed time.Duration
+	if start, ok := hook.starts.LoadAndDelete(ctx.FunctionCallID()); ok {
+		elapsed = time.Since(start.(time.Time))
+	}
+	if hook.OnFinish != nil {
+		hook.OnFinish(t.Name(), result, err, elapsed)
+	}
+	return nil, nil
+}
@@ -0,0 +1,157 @@
+package adk
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// toolCallingLLM 第一轮请求调用 echo 工具，拿到工具结果后回复文本
+type toolCallingLLM struct{}
+
+func (toolCallingLLM) Name() string { return "tool-calling" }
+
+func (toolCallingLLM) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		last := req.Contents[len(req.Contents)-1]
+		if last.Parts[0].FunctionResponse != nil {
+			yield(&model.LLMResponse{Content: genai.NewContentFromText("done", genai.RoleModel)}, nil)
+			return
+		}
+		yield(&model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{
+			FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "echo", Args: map[string]any{"text": "hi"}},
+		}}}}, nil)
+	}
+}
+
+// newEchoRunner 创建挂载 echo 工具与工具回调的 runner，executed 统计工具实际执行次数
+func newEchoRunner(t *testing.T, executed *atomic.Int32) (*runner.Runner, session.Service) {
+	t.Helper()
+	type echoArgs struct {
+		Text string `json:"text"`
+	}
+	echo, err := functiontool.New(functiontool.Config{Name: "echo", Description: "echo"},
+		func(_ tool.Context, in echoArgs) (map[string]any, error) {
+			executed.Add(1)
+			time.Sleep(5 * time.Millisecond)
+			return map[string]any{"text": in.Text}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                "expert",
+		Model:               toolCallingLLM{},
+		Tools:               []tool.Tool{echo},
+		BeforeToolCallbacks: []llmagent.BeforeToolCallback{beforeToolCall},
+		AfterToolCallbacks:  []llmagent.AfterToolCallback{afterToolCall},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "test", Agent: a, SessionService: sessions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, sessions
+}
+
+func TestToolCallHook(t *testing.T) {
+	var executed atomic.Int32
+	r, sessions := newEchoRunner(t, &executed)
+	if _, err := sessions.Create(context.Background(), &session.CreateRequest{AppName: "test", UserID: "u", SessionID: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var started, finished []string
+	var gotArgs, gotResult map[string]any
+	var elapsed time.Duration
+	hook := &ToolCallHook{
+		OnStart: func(name string, args map[string]any) {
+			mu.Lock()
+			defer mu.Unlock()
+			started, gotArgs = append(started, name), args
+		},
+		OnFinish: func(name string, result map[string]any, err error, d time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			finished, gotResult, elapsed = append(finished, name), result, d
+		},
+	}
+
+	ctx := WithToolCallHook(context.Background(), hook)
+	for _, err := range r.Run(ctx, "u", "s", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(started) != 1 || started[0] != "echo" || gotArgs["text"] != "hi" {
+		t.Errorf("started = %v, args = %v", started, gotArgs)
+	}
+	if len(finished) != 1 || gotResult["text"] != "hi" || elapsed < 5*time.Millisecond {
+		t.Errorf("finished = %v, result = %v, elapsed = %v", finished, gotResult, elapsed)
+	}
+
+	// 未挂 hook 时回调为空操作
+	if _, err := sessions.Create(context.Background(), &session.CreateRequest{AppName: "test", UserID: "u", SessionID: "s2"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.Run(context.Background(), "u", "s2", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(started) != 1 {
+		t.Errorf("hook should not fire without context value, started = %v", started)
+	}
+}
+
+func TestToolCallHook_Budget(t *testing.T) {
+	var executed atomic.Int32
+	r, sessions := newEchoRunner(t, &executed)
+	if _, err := sessions.Create(context.Background(), &session.CreateRequest{AppName: "test", UserID: "u", SessionID: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotResult map[string]any
+	hook := &ToolCallHook{
+		MaxCalls: 5,
+		OnFinish: func(_ string, result map[string]any, _ error, _ time.Duration) { gotResult = result },
+	}
+	hook.Exhaust()
+	ctx := WithToolCallHook(context.Background(), hook)
+	for _, err := range r.Run(ctx, "u", "s", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 预算用完后工具不执行，模型收到提示
+	if executed.Load() != 0 || hook.Calls() != 1 {
+		t.Errorf("executed = %d, calls = %d", executed.Load(), hook.Calls())
+	}
+	if msg, _ := gotResult["error"].(string); !strings.Contains(msg, "预算已用完") {
+		t.Errorf("result = %v", gotResult)
+	}
+
+	over := &ToolCallHook{MaxCalls: 1}
+	if over.denied() != nil || over.denied() == nil {
+		t.Error("second call should exceed MaxCalls = 1")
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// 公告工具描述
+const (
+	announcementsDescription       = "获取个股最近的交易所公告列表（标题、类型、日期、链接），用于排查解禁、定增、减持、业绩预告等风险事件"
+	announcementContentDescription = "下载公告并提取正文纯文本（支持 PDF 与网页），需要先通过 get_announcements 获取公告链接；正文过长时截断"
+)
+
+// GetAnnouncementsInput 公告列表输入参数
+type GetAnnouncementsInput struct {
+	Code  string `json:"code" jsonschema:"股票代码，如 sh600519 或 600519"`
+	Limit int    `json:"limit,omitzero" jsonschema:"返回条数，默认10条，最多50条"`
+}
+
+// GetAnnouncementsOutput 公告列表输出
+type GetAnnouncementsOutput struct {
+	Announcements []services.Announcement `json:"announcements" jsonschema:"公告列表，按日期倒序"`
+}
+
+// createAnnouncementsTool 创建公告列表工具
+func (r *Registry) createAnnouncementsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetAnnouncementsInput) (GetAnnouncementsOutput, error) {
+		fmt.Printf("[Tool:get_announcements] 调用开始, code=%s, limit=%d\n", input.Code, input.Limit)
+
+		list, err := r.newsService.GetAnnouncements(ctx, input.Code, input.Limit)
+		if err != nil {
+			fmt.Printf("[Tool:get_announcements] 错误: %v\n", err)
+			return GetAnnouncementsOutput{}, err
+		}
+
+		fmt.Printf("[Tool:get_announcements] 调用完成, 返回%d条公告\n", len(list))
+		return GetAnnouncementsOutput{Announcements: list}, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_announcements",
+		Description: announcementsDescription,
+	}, handler)
+}
+
+// GetAnnouncementContentInput 公告正文输入参数
+type GetAnnouncementContentInput struct {
+	URL      string `json:"url" jsonschema:"公告链接，来自 get_announcements 返回的 url"`
+	MaxChars int    `json:"max_chars,omitzero" jsonschema:"返回正文的最大字数，默认及上限由设置决定"`
+}
+
+// createAnnouncementContentTool 创建公告正文工具
+func (r *Registry) createAnnouncementContentTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetAnnouncementContentInput) (services.AnnouncementContent, error) {
+		fmt.Printf("[Tool:get_announcement_content] 调用开始, url=%s\n", input.URL)
+
+		// 设置中的字数上限同时作为默认值，模型只能在其内调小
+		limit := services.AnnouncementDefaultMaxChars
+		if cfg := r.configService.GetConfig(); cfg != nil && cfg.AnnouncementMaxChars > 0 {
+			limit = cfg.AnnouncementMaxChars
+		}
+		maxChars := limit
+		if input.MaxChars > 0 {
+			maxChars = min(input.MaxChars, limit)
+		}
+
+		result, err := r.newsService.GetAnnouncementContent(ctx, input.URL, maxChars)
+		if err != nil {
+			fmt.Printf("[Tool:get_announcement_content] 错误: %v\n", err)
+			return services.AnnouncementContent{}, err
+		}
+
+		fmt.Printf("[Tool:get_announcement_content] 调用完成, source=%s, 字数=%d/%d\n", result.Source, len([]rune(result.Text)), result.TotalChars)
+		return *result, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_announcement_content",
+		Description: announcementContentDescription,
+	}, handler)
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetEarningsCalendarInput 财报日历查询输入参数
+type GetEarningsCalendarInput struct {
+	Codes     []string `json:"codes,omitzero" jsonschema:"股票代码列表，如 [\"sh600519\", \"000001\"]，为空则查询全市场"`
+	DaysAhead int      `json:"daysAhead,omitzero" jsonschema:"查询未来多少天内的披露安排，默认30，最大90"`
+}
+
+// EarningsEvent 财报披露事件
+type EarningsEvent struct {
+	Symbol   string  `json:"symbol" jsonschema:"股票代码"`
+	Name     string  `json:"name" jsonschema:"股票名称"`
+	Date     string  `json:"date" jsonschema:"预约披露日期，格式YYYY-MM-DD"`
+	Report   string  `json:"report" jsonschema:"报告类型，如2025年三季报"`
+	Estimate float64 `json:"estimate" jsonschema:"业绩预告归母净利润中值（亿元），无预告为0"`
+	Previous float64 `json:"previous" jsonschema:"上年同期归母净利润（亿元），无预告为0"`
+}
+
+// GetEarningsCalendarOutput 财报日历查询输出
+type GetEarningsCalendarOutput struct {
+	Events  []EarningsEvent `json:"events" jsonschema:"按披露日期升序排列的财报事件"`
+	Message string          `json:"message,omitzero" jsonschema:"补充说明"`
+}
+
+// createEarningsCalendarTool 创建财报日历工具
+func (r *Registry) createEarningsCalendarTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetEarningsCalendarInput) (GetEarningsCalendarOutput, error) {
+		fmt.Printf("[Tool:get_earnings_calendar] 调用开始, codes=%v, daysAhead=%d\n", input.Codes, input.DaysAhead)
+
+		// 指数没有财报，过滤后若只剩指数则直接返回
+		codes := make([]string, 0, len(input.Codes))
+		for _, code := range input.Codes {
+			if !models.IsIndexSymbol(code) {
+				codes = append(codes, code)
+			}
+		}
+		if len(input.Codes) > 0 && len(codes) == 0 {
+			return GetEarningsCalendarOutput{Events: []EarningsEvent{}, Message: indexNotApplicable}, nil
+		}
+
+		items, err := r.earningsService.GetEarningsCalendar(ctx, codes, input.DaysAhead)
+		if err != nil {
+			fmt.Printf("[Tool:get_earnings_calendar] 错误: %v\n", err)
+			return GetEarningsCalendarOutput{}, err
+		}
+
+		events := make([]EarningsEvent, 0, len(items))
+		for _, item := range items {
+			events = append(events, EarningsEvent{
+				Symbol:   item.Code,
+				Name:     item.Name,
+				Date:     item.Date,
+				Report:   item.ReportType,
+				Estimate: item.ForecastProfit / 1e8,
+				Previous: item.PrevProfit / 1e8,
+			})
+		}
+		fmt.Printf("[Tool:get_earnings_calendar] 调用完成, 返回%d条事件\n", len(events))
+
+		output := GetEarningsCalendarOutput{Events: events}
+		if len(events) == 0 {
+			output.Message = "查询范围内暂无待披露的财报"
+		}
+		return output, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_earnings_calendar",
+		Description: earningsCalendarDescription,
+	}, handler)
+}
+
+// earningsCalendarDescription 财报日历工具描述，金额单位为亿元
+const earningsCalendarDescription = "获取A股财报预约披露日历，包括披露日期、报告类型，以及业绩预告的归母净利润与上年同期值"
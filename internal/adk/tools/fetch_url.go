@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// fetchURLDescription 网页读取工具描述
+const fetchURLDescription = "打开网页链接并提取正文，返回标题、发布时间（可识别时）和去除导航、广告后的正文（最多约6000字）；用于阅读快讯、搜索结果中给出的链接，仅支持公网 http/https 网址"
+
+// FetchURLInput 网页读取输入参数
+type FetchURLInput struct {
+	URL string `json:"url" jsonschema:"网页链接，需以 http:// 或 https:// 开头"`
+}
+
+// createFetchURLTool 创建网页读取工具
+func (r *Registry) createFetchURLTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input FetchURLInput) (services.WebPage, error) {
+		fmt.Printf("[Tool:fetch_url] 调用开始, url=%s\n", input.URL)
+
+		page, err := r.urlReader.Read(ctx, input.URL, services.DefaultURLReaderChars)
+		if err != nil {
+			fmt.Printf("[Tool:fetch_url] 错误: %v\n", err)
+			return services.WebPage{}, err
+		}
+
+		fmt.Printf("[Tool:fetch_url] 调用完成, title=%s, 字数=%d/%d\n", page.Title, len([]rune(page.Text)), page.TotalChars)
+		return *page, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "fetch_url",
+		Description: fetchURLDescription,
+	}, handler)
+}
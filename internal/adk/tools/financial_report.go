@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetFinancialReportInput 财务报表查询输入参数
+type GetFinancialReportInput struct {
+	Code       string              `json:"code" jsonschema:"股票代码，如 sh600519 或 600519"`
+	ReportType FinancialReportType `json:"reportType,omitzero" jsonschema:"报表类型: income(利润表), balance(资产负债表), cashflow(现金流量表), summary(三大报表要点及毛利率、ROE等衍生比率)，默认income"`
+	Quarters   int                 `json:"quarters,omitzero" jsonschema:"最近多少个报告期，默认4（summary默认8），最大12"`
+}
+
+// GetFinancialReportOutput 财务报表查询输出
+type GetFinancialReportOutput struct {
+	Periods []string             `json:"periods" jsonschema:"报告期，倒序排列，如 2025Q3"`
+	Metrics map[string][]float64 `json:"metrics" jsonschema:"指标名 -> 各报告期数值，与 periods 对应；金额单位亿元，带(%)的为百分比，缺失为0"`
+	Table   string               `json:"table" jsonschema:"便于阅读的表格，缺失值显示为 -"`
+}
+
+// createFinancialReportTool 创建财务报表工具
+func (r *Registry) createFinancialReportTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetFinancialReportInput) (GetFinancialReportOutput, error) {
+		return r.financialReport(ctx, "get_stock_financial_report", input)
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_stock_financial_report",
+		Description: financialReportDescription,
+	}, handler)
+}
+
+// GetFinancialSummaryInput 财务摘要查询输入参数
+type GetFinancialSummaryInput struct {
+	Code     string `json:"code" jsonschema:"股票代码，如 sh600519 或 600519"`
+	Quarters int    `json:"quarters,omitzero" jsonschema:"最近多少个报告期，默认8，最大12"`
+}
+
+// createFinancialSummaryTool 创建三大报表摘要工具，等同 get_stock_financial_report 的 reportType=summary
+func (r *Registry) createFinancialSummaryTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetFinancialSummaryInput) (GetFinancialReportOutput, error) {
+		return r.financialReport(ctx, "get_financial_report", GetFinancialReportInput{
+			Code: input.Code, ReportType: services.FinancialSummary, Quarters: input.Quarters,
+		})
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_financial_report",
+		Description: financialSummaryDescription,
+	}, handler)
+}
+
+// financialReport 查询财务报表或三大报表摘要，name 为日志中的工具名
+func (r *Registry) financialReport(ctx tool.Context, name string, input GetFinancialReportInput) (GetFinancialReportOutput, error) {
+	fmt.Printf("[Tool:%s] 调用开始, code=%s, reportType=%s, quarters=%d\n",
+		name, input.Code, input.ReportType, input.Quarters)
+
+	if input.Code == "" {
+		return GetFinancialReportOutput{Table: "请提供股票代码"}, nil
+	}
+	if models.IsIndexSymbol(input.Code) {
+		return GetFinancialReportOutput{Table: indexNotApplicable}, nil
+	}
+
+	var report *services.FinancialReport
+	var err error
+	if input.ReportType == services.FinancialSummary {
+		report, err = r.earningsService.GetFinancialSummary(ctx, input.Code, input.Quarters)
+	} else {
+		report, err = r.earningsService.GetFinancialReport(ctx, input.Code, string(input.ReportType), input.Quarters)
+	}
+	if err != nil {
+		fmt.Printf("[Tool:%s] 错误: %v\n", name, err)
+		return GetFinancialReportOutput{}, err
+	}
+	fmt.Printf("[Tool:%s] 调用完成, 返回%d期报表\n", name, len(report.Periods))
+
+	output := GetFinancialReportOutput{Periods: report.Periods, Metrics: financialMetrics(report)}
+	if len(report.Periods) == 0 {
+		output.Table = "暂无财务报表数据"
+		return output, nil
+	}
+	reportType := input.ReportType
+	if reportType == "" {
+		reportType = services.FinancialIncome
+	}
+	output.Table = formatFinancialTable(report, financialReportNames[reportType])
+	return output, nil
+}
+
+// financialReportDescription 财务报表工具描述
+const financialReportDescription = "获取个股最近几期财务报表（利润表、资产负债表、现金流量表）的核心科目，金额单位亿元，季报为年初至报告期末累计值；reportType=summary 返回最近8个季度三大报表要点及毛利率、ROE、资产负债率、经营现金流/净利润等衍生比率，适合分析盈利质量与成长性"
+
+// financialSummaryDescription 财务摘要工具描述
+const financialSummaryDescription = "获取个股最近8个季度的财务摘要：利润表、资产负债表、现金流量表要点及毛利率、ROE、资产负债率、经营现金流/净利润等衍生比率，适合分析盈利质量与成长性"
+
+// financialReportNames 报表类型的中文名称
+var financialReportNames = map[FinancialReportType]string{
+	services.FinancialIncome:   "利润表",
+	services.FinancialBalance:  "资产负债表",
+	services.FinancialCashflow: "现金流量表",
+	services.FinancialSummary:  "财务摘要",
+}
+
+// financialLabel 指标名，百分比指标带 (%) 后缀
+func financialLabel(row services.FinancialRow) string {
+	if row.Ratio {
+		return row.Label + "(%)"
+	}
+	return row.Label
+}
+
+// financialValue 金额换算为亿元，百分比保持不变，保留两位小数
+func financialValue(v float64, ratio bool) float64 {
+	if !ratio {
+		v /= 1e8
+	}
+	return math.Round(v*100) / 100
+}
+
+// financialMetrics 指标名 -> 各报告期数值（亿元/百分比），缺失为 0
+func financialMetrics(report *services.FinancialReport) map[string][]float64 {
+	metrics := make(map[string][]float64, len(report.Rows))
+	for _, row := range report.Rows {
+		values := make([]float64, len(row.Values))
+		for i, v := range row.Values {
+			if v != nil {
+				values[i] = financialValue(*v, row.Ratio)
+			}
+		}
+		metrics[financialLabel(row)] = values
+	}
+	return metrics
+}
+
+// formatFinancialTable 以报告期为列、指标为行输出紧凑表格，title 为报表名称
+func formatFinancialTable(report *services.FinancialReport, title string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s(%s) %s（金额单位：亿元，季报为年初累计值）\n", report.Name, report.Code, title)
+	sb.WriteString("指标 | " + strings.Join(report.Periods, " | ") + "\n")
+	for _, row := range report.Rows {
+		cells := make([]string, len(row.Values))
+		for i, v := range row.Values {
+			cells[i] = "-"
+			if v != nil {
+				cells[i] = fmt.Sprintf("%.2f", financialValue(*v, row.Ratio))
+			}
+		}
+		sb.WriteString(financialLabel(row) + " | " + strings.Join(cells, " | ") + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -12,8 +12,8 @@ import (
 
 // GetHotTrendInput 舆情热点输入参数
 type GetHotTrendInput struct {
-	Platform string `json:"platform,omitzero" jsonschema:"平台名称，可选值：weibo/zhihu/bilibili/baidu/douyin/toutiao，不填则获取所有平台"`
-	Limit    int    `json:"limit,omitzero" jsonschema:"每个平台返回的热点条数，默认10条"`
+	Platform HotTrendPlatform `json:"platform,omitzero" jsonschema:"平台名称，可选值：weibo/zhihu/bilibili/baidu/douyin/toutiao，不填则获取所有平台"`
+	Limit    int              `json:"limit,omitzero" jsonschema:"每个平台返回的热点条数，默认10条"`
 }
 
 // GetHotTrendOutput 舆情热点输出
@@ -42,11 +42,11 @@ func (r *Registry) createHotTrendTool() (tool.Tool, error) {
 
 		if input.Platform != "" {
 			// 获取单个平台
-			trendResult := r.hotTrendService.GetHotTrend(input.Platform)
+			trendResult := r.hotTrendService.GetHotTrend(string(input.Platform))
 			formatTrendResult(&result, trendResult, limit)
 		} else {
-			// 获取所有平台
-			results := r.hotTrendService.GetAllHotTrends()
+			// 获取所有平台（等待各平台完成或超时，失败的平台回退到过期缓存）
+			results := r.hotTrendService.GetHotTrends(nil)
 			for _, trendResult := range results {
 				formatTrendResult(&result, trendResult, limit)
 				result.WriteString("\n")
@@ -57,7 +57,7 @@ func (r *Registry) createHotTrendTool() (tool.Tool, error) {
 		return GetHotTrendOutput{Data: result.String()}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_hottrend",
 		Description: "获取全网舆情热点，支持微博、知乎、B站、百度、抖音、头条等平台的实时热搜榜单",
 	}, handler)
@@ -70,7 +70,11 @@ func formatTrendResult(sb *strings.Builder, tr hottrend.HotTrendResult, limit in
 		return
 	}
 
-	sb.WriteString(fmt.Sprintf("【%s】热搜榜:\n", tr.PlatformCN))
+	if tr.Stale {
+		sb.WriteString(fmt.Sprintf("【%s】热搜榜（实时获取失败，以下为 %s 的缓存数据）:\n", tr.PlatformCN, tr.UpdatedAt.Format("01-02 15:04")))
+	} else {
+		sb.WriteString(fmt.Sprintf("【%s】热搜榜:\n", tr.PlatformCN))
+	}
 	count := 0
 	for _, item := range tr.Items {
 		if count >= limit {
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/pkg/indicators"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// defaultIndicatorBars 技术指标默认取数根数，保证 MA60、MACD 有足够的预热数据
+const defaultIndicatorBars = 120
+
+// GetTechnicalIndicatorsInput 技术指标输入参数
+type GetTechnicalIndicatorsInput struct {
+	Code   string      `json:"code" jsonschema:"股票代码，如 sh600519"`
+	Period KLinePeriod `json:"period,omitempty" jsonschema:"K线周期: 1m(5分钟), 1d(日线), 1w(周线), 1mo(月线)，默认1d"`
+	Days   int         `json:"days,omitzero" jsonschema:"参与计算的K线根数，默认120，过少时长周期指标会省略"`
+
+	Indicators []IndicatorName `json:"indicators,omitzero" jsonschema:"需要的指标: ma(MA5/10/20/60), macd, kdj, rsi, boll，为空则全部返回"`
+}
+
+// GetTechnicalIndicatorsOutput 技术指标输出
+type GetTechnicalIndicatorsOutput struct {
+	Message    string                 `json:"message,omitempty" jsonschema:"无法计算时的说明"`
+	Indicators *indicators.Indicators `json:"indicators,omitempty" jsonschema:"最新一根K线上的技术指标"`
+	Signals    []string               `json:"signals,omitempty" jsonschema:"信号摘要，如均线金叉、MACD零轴上方死叉、RSI超买、跌破布林下轨"`
+	Notes      []string               `json:"notes,omitempty" jsonschema:"K线不足被省略的指标等说明"`
+}
+
+// createTechnicalIndicatorsTool 创建技术指标工具
+func (r *Registry) createTechnicalIndicatorsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetTechnicalIndicatorsInput) (GetTechnicalIndicatorsOutput, error) {
+		fmt.Printf("[Tool:get_technical_indicators] 调用开始, code=%s, period=%s, days=%d\n", input.Code, input.Period, input.Days)
+
+		if input.Code == "" {
+			fmt.Println("[Tool:get_technical_indicators] 错误: 未提供股票代码")
+			return GetTechnicalIndicatorsOutput{Message: "请提供股票代码"}, nil
+		}
+
+		period := string(input.Period)
+		if period == "" {
+			period = "1d"
+		}
+		days := input.Days
+		if days <= 0 {
+			days = defaultIndicatorBars
+		}
+
+		klines, err := r.marketService.GetKLineData(ctx, input.Code, period, days)
+		if err != nil {
+			fmt.Printf("[Tool:get_technical_indicators] 错误: %v\n", err)
+			return GetTechnicalIndicatorsOutput{}, err
+		}
+		if len(klines) == 0 {
+			return GetTechnicalIndicatorsOutput{Message: "暂无K线数据"}, nil
+		}
+		names := make([]string, len(input.Indicators))
+		for i, name := range input.Indicators {
+			names[i] = string(name)
+		}
+		// K线不足时返回能算出的部分指标，省略项写入 notes
+		a := indicators.Analyze(klines, names)
+
+		fmt.Printf("[Tool:get_technical_indicators] 调用完成, 基于%d条K线, 信号%d条\n", len(klines), len(a.Signals))
+		return GetTechnicalIndicatorsOutput{Indicators: a.Indicators, Signals: a.Signals, Notes: a.Notes}, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_technical_indicators",
+		Description: "计算股票技术指标（MA5/10/20/60、EMA12/26、MACD(12,26,9)、RSI(14)、KDJ(9,3,3)、布林带(20,2)），返回最新一根K线上的指标值与金叉、死叉、超买、超卖等信号摘要；可通过 indicators 只取部分指标",
+	}, handler)
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"fmt"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetIPOCalendarInput 新股日历查询输入参数
+type GetIPOCalendarInput struct {
+	DaysAhead int `json:"daysAhead,omitzero" jsonschema:"查询未来多少天内申购或上市的新股，默认14，最大60"`
+}
+
+// IPOItem 新股信息
+type IPOItem struct {
+	Symbol      string  `json:"symbol" jsonschema:"股票代码"`
+	Name        string  `json:"name" jsonschema:"股票名称"`
+	ApplyDate   string  `json:"applyDate" jsonschema:"申购日期，格式YYYY-MM-DD"`
+	ListingDate string  `json:"listingDate" jsonschema:"上市日期，格式YYYY-MM-DD，未确定为空"`
+	IssuePrice  float64 `json:"issuePrice" jsonschema:"发行价（元），未定价为0"`
+	MarketCap   float64 `json:"marketCap" jsonschema:"按发行价计算的发行市值（亿元）"`
+	Industry    string  `json:"industry" jsonschema:"所属行业或主营业务"`
+}
+
+// GetIPOCalendarOutput 新股日历查询输出
+type GetIPOCalendarOutput struct {
+	IPOs    []IPOItem `json:"ipos" jsonschema:"按上市日期（未定时为申购日期）升序排列的新股"`
+	Message string    `json:"message,omitzero" jsonschema:"补充说明"`
+}
+
+// createIPOCalendarTool 创建新股日历工具
+func (r *Registry) createIPOCalendarTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetIPOCalendarInput) (GetIPOCalendarOutput, error) {
+		fmt.Printf("[Tool:get_ipo_calendar] 调用开始, daysAhead=%d\n", input.DaysAhead)
+
+		items, err := r.ipoService.GetIPOCalendar(ctx, input.DaysAhead)
+		if err != nil {
+			fmt.Printf("[Tool:get_ipo_calendar] 错误: %v\n", err)
+			return GetIPOCalendarOutput{}, err
+		}
+
+		ipos := make([]IPOItem, 0, len(items))
+		for _, item := range items {
+			ipos = append(ipos, IPOItem{
+				Symbol:      item.Code,
+				Name:        item.Name,
+				ApplyDate:   item.ApplyDate,
+				ListingDate: item.ListingDate,
+				IssuePrice:  item.IssuePrice,
+				MarketCap:   item.MarketCap / 1e8,
+				Industry:    item.Industry,
+			})
+		}
+		fmt.Printf("[Tool:get_ipo_calendar] 调用完成, 返回%d只新股\n", len(ipos))
+
+		output := GetIPOCalendarOutput{IPOs: ipos}
+		if len(ipos) == 0 {
+			output.Message = "查询范围内暂无新股申购或上市"
+		}
+		return output, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_ipo_calendar",
+		Description: ipoCalendarDescription,
+	}, handler)
+}
+
+// ipoCalendarDescription 新股日历工具描述，金额单位为亿元
+const ipoCalendarDescription = "获取A股新股日历，包括近期申购和即将上市的新股、申购与上市日期、发行价、发行市值和所属行业"
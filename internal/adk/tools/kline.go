@@ -2,6 +2,10 @@ package tools
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/indicators"
+	"github.com/run-bigpig/jcp/internal/services"
 
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
@@ -9,36 +13,44 @@ import (
 
 // GetKLineInput K线数据输入参数
 type GetKLineInput struct {
-	Code   string `json:"code" jsonschema:"股票代码，如 sh600519"`
-	Period string `json:"period,omitempty" jsonschema:"K线周期: 1m(5分钟), 1d(日线), 1w(周线), 1mo(月线)，默认1d"`
-	Days   int    `json:"days,omitzero" jsonschema:"获取天数，默认30"`
+	Code         string      `json:"code" jsonschema:"股票代码，如 sh600519"`
+	Period       KLinePeriod `json:"period,omitempty" jsonschema:"K线周期: 1m(5分钟), 1d(日线), 1w(周线), 1mo(月线)，默认1d"`
+	Days         int         `json:"days,omitzero" jsonschema:"K线根数，日线即交易日数（不含周末节假日），周线/月线为周数/月数，默认30"`
+	CalendarDays int         `json:"calendarDays,omitzero" jsonschema:"按自然日取数的窗口天数，如最近一个月填30；填写后忽略days，一般无需使用"`
 }
 
 // GetKLineOutput K线数据输出
 type GetKLineOutput struct {
-	Data string `json:"data" jsonschema:"K线数据"`
+	Data       string                 `json:"data" jsonschema:"K线数据"`
+	Indicators *indicators.Indicators `json:"indicators,omitempty" jsonschema:"基于本次K线计算的最新技术指标，数据不足的指标省略"`
 }
 
 // createKLineTool 创建K线数据工具
 func (r *Registry) createKLineTool() (tool.Tool, error) {
 	handler := func(ctx tool.Context, input GetKLineInput) (GetKLineOutput, error) {
-		fmt.Printf("[Tool:get_kline_data] 调用开始, code=%s, period=%s, days=%d\n", input.Code, input.Period, input.Days)
+		fmt.Printf("[Tool:get_kline_data] 调用开始, code=%s, period=%s, days=%d, calendarDays=%d\n", input.Code, input.Period, input.Days, input.CalendarDays)
 
 		if input.Code == "" {
 			fmt.Println("[Tool:get_kline_data] 错误: 未提供股票代码")
 			return GetKLineOutput{Data: "请提供股票代码"}, nil
 		}
 
-		period := input.Period
+		period := string(input.Period)
 		if period == "" {
 			period = "1d"
 		}
 		days := input.Days
+		if input.CalendarDays > 0 {
+			days = calendarWindowBars(r.marketService, period, input.CalendarDays)
+			if days == 0 {
+				return GetKLineOutput{Data: fmt.Sprintf("最近%d个自然日内没有交易日", input.CalendarDays)}, nil
+			}
+		}
 		if days == 0 {
 			days = 30
 		}
 
-		klines, err := r.marketService.GetKLineData(input.Code, period, days)
+		klines, err := r.marketService.GetKLineData(ctx, input.Code, period, days)
 		if err != nil {
 			fmt.Printf("[Tool:get_kline_data] 错误: %v\n", err)
 			return GetKLineOutput{}, err
@@ -56,11 +68,26 @@ func (r *Registry) createKLineTool() (tool.Tool, error) {
 		}
 
 		fmt.Printf("[Tool:get_kline_data] 调用完成, 返回%d条数据\n", len(klines))
-		return GetKLineOutput{Data: result}, nil
+		return GetKLineOutput{Data: result, Indicators: indicators.Compute(klines)}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_kline_data",
-		Description: "获取股票K线数据，支持5分钟线、日线、周线、月线",
+		Description: klineToolDescription,
 	}, handler)
 }
+
+// klineToolDescription K线工具描述，说明 days 按交易日计
+const klineToolDescription = "获取股票K线数据，支持5分钟线、日线、周线、月线；days 为K线根数，日线按交易日计（最近20天即20根日K），需要自然日窗口时使用 calendarDays"
+
+// calendarWindowBars 自然日窗口换算为K线根数：日线按交易日历统计，周线/月线向上取整
+func calendarWindowBars(ms *services.MarketService, period string, calendarDays int) int {
+	switch period {
+	case "1w":
+		return (calendarDays + 6) / 7
+	case "1mo":
+		return (calendarDays + 29) / 30
+	default:
+		return ms.CalendarDaysToTradingDays(time.Now(), calendarDays)
+	}
+}
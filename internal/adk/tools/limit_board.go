@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// limitBoardDescription 涨跌停板工具描述
+const limitBoardDescription = "获取A股涨停板/跌停板统计：涨停家数、跌停家数、连板高度与连板梯队、炸板率，以及涨停股的连板数、封单额、首次封板时间和跌停股列表，用于判断短线情绪与赚钱效应"
+
+// GetLimitBoardInput 涨跌停板输入参数
+type GetLimitBoardInput struct {
+	Date  string `json:"date,omitzero" jsonschema:"交易日期，格式YYYY-MM-DD，不填为最近一个交易日"`
+	Limit int    `json:"limit,omitzero" jsonschema:"涨停/跌停股各返回的条数，默认10条，最多50条（连板股优先）"`
+}
+
+// GetLimitBoardOutput 涨跌停板输出
+type GetLimitBoardOutput struct {
+	Data string `json:"data" jsonschema:"涨跌停板统计"`
+}
+
+// createLimitBoardTool 创建涨跌停板工具
+func (r *Registry) createLimitBoardTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetLimitBoardInput) (GetLimitBoardOutput, error) {
+		fmt.Printf("[Tool:get_limit_board] 调用开始, date=%s, limit=%d\n", input.Date, input.Limit)
+
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 10
+		}
+		if limit > 50 {
+			limit = 50
+		}
+
+		stats, err := r.marketService.GetLimitBoardStats(ctx, input.Date)
+		if err != nil {
+			fmt.Printf("[Tool:get_limit_board] 错误: %v\n", err)
+			return GetLimitBoardOutput{}, err
+		}
+
+		fmt.Printf("[Tool:get_limit_board] 调用完成, 涨停%d 跌停%d\n", stats.LimitUpCount, stats.LimitDownCount)
+		return GetLimitBoardOutput{Data: formatLimitBoardStats(stats, limit)}, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_limit_board",
+		Description: limitBoardDescription,
+	}, handler)
+}
+
+// formatLimitBoardStats 格式化涨跌停统计，涨停/跌停列表各取前 limit 条
+func formatLimitBoardStats(s *models.LimitBoardStats, limit int) string {
+	var sb strings.Builder
+	status := "盘中数据，仍会变化"
+	if s.Final {
+		status = "已收盘"
+	}
+	fmt.Fprintf(&sb, "【%s 涨跌停统计】（%s）\n", s.Date, status)
+	fmt.Fprintf(&sb, "涨停 %d 家，跌停 %d 家，炸板 %d 家，炸板率 %.1f%%，连板高度 %d 板\n",
+		s.LimitUpCount, s.LimitDownCount, s.BrokenCount, s.BrokenRatio, s.MaxBoards)
+
+	if len(s.Ladder) > 0 {
+		tiers := make([]string, len(s.Ladder))
+		for i, t := range s.Ladder {
+			tiers[i] = fmt.Sprintf("%d板×%d", t.Boards, t.Count)
+		}
+		fmt.Fprintf(&sb, "连板梯队: %s\n", strings.Join(tiers, "、"))
+	}
+
+	if len(s.LimitUp) > 0 {
+		sb.WriteString("\n【涨停股】\n")
+		for _, st := range s.LimitUp[:min(limit, len(s.LimitUp))] {
+			fmt.Fprintf(&sb, "  %s(%s) %d板 封单%.2f亿 成交%.2f亿 首封%s", st.Name, st.Code, st.Boards, st.SealAmount/1e8, st.Amount/1e8, st.FirstSealTime)
+			if st.BreakCount > 0 {
+				fmt.Fprintf(&sb, " 炸板%d次", st.BreakCount)
+			}
+			if st.Industry != "" {
+				fmt.Fprintf(&sb, " [%s]", st.Industry)
+			}
+			sb.WriteString("\n")
+		}
+		if n := len(s.LimitUp) - limit; n > 0 {
+			fmt.Fprintf(&sb, "  ……其余 %d 家略\n", n)
+		}
+	}
+
+	if len(s.LimitDown) > 0 {
+		sb.WriteString("\n【跌停股】\n")
+		for _, st := range s.LimitDown[:min(limit, len(s.LimitDown))] {
+			fmt.Fprintf(&sb, "  %s(%s) 连续跌停%d天 封单%.2f亿 成交%.2f亿", st.Name, st.Code, st.Days, st.SealAmount/1e8, st.Amount/1e8)
+			if st.Industry != "" {
+				fmt.Fprintf(&sb, " [%s]", st.Industry)
+			}
+			sb.WriteString("\n")
+		}
+		if n := len(s.LimitDown) - limit; n > 0 {
+			fmt.Fprintf(&sb, "  ……其余 %d 家略\n", n)
+		}
+	}
+	return sb.String()
+}
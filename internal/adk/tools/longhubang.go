@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
 
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
@@ -40,7 +41,7 @@ func (r *Registry) createLongHuBangTool() (tool.Tool, error) {
 			pageNumber = 1
 		}
 
-		listResult, err := r.longHuBangService.GetLongHuBangList(pageSize, pageNumber, input.TradeDate)
+		listResult, err := r.longHuBangService.GetLongHuBangList(ctx, pageSize, pageNumber, input.TradeDate)
 		if err != nil {
 			lhbLog.Error("获取龙虎榜失败: %v", err)
 			return GetLongHuBangOutput{}, err
@@ -69,7 +70,7 @@ func (r *Registry) createLongHuBangTool() (tool.Tool, error) {
 		return GetLongHuBangOutput{Data: result}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_longhubang",
 		Description: "获取A股龙虎榜数据，包括上榜股票、净买入金额、买卖金额、上榜原因等信息，数据来源于东方财富",
 	}, handler)
@@ -94,8 +95,11 @@ func (r *Registry) createLongHuBangDetailTool() (tool.Tool, error) {
 		if input.Code == "" || input.TradeDate == "" {
 			return GetLongHuBangDetailOutput{}, fmt.Errorf("股票代码和交易日期不能为空")
 		}
+		if models.IsIndexSymbol(input.Code) {
+			return GetLongHuBangDetailOutput{Data: indexNotApplicable}, nil
+		}
 
-		details, err := r.longHuBangService.GetStockDetail(input.Code, input.TradeDate)
+		details, err := r.longHuBangService.GetStockDetail(ctx, input.Code, input.TradeDate)
 		if err != nil {
 			lhbLog.Error("获取营业部明细失败: %v", err)
 			return GetLongHuBangDetailOutput{}, err
@@ -133,7 +137,7 @@ func (r *Registry) createLongHuBangDetailTool() (tool.Tool, error) {
 		return GetLongHuBangDetailOutput{Data: result}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_longhubang_detail",
 		Description: "获取个股龙虎榜营业部买卖明细，需要提供股票代码和交易日期",
 	}, handler)
@@ -3,6 +3,9 @@ package tools
 import (
 	"fmt"
 
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
@@ -22,7 +25,7 @@ func (r *Registry) createNewsTool() (tool.Tool, error) {
 	handler := func(ctx tool.Context, input GetNewsInput) (GetNewsOutput, error) {
 		fmt.Printf("[Tool:get_news] 调用开始, limit=%d\n", input.Limit)
 
-		news, err := r.newsService.GetTelegraphList()
+		news, err := r.newsService.GetTelegraphList(ctx)
 		if err != nil {
 			fmt.Printf("[Tool:get_news] 错误: %v\n", err)
 			return GetNewsOutput{}, err
@@ -46,8 +49,46 @@ func (r *Registry) createNewsTool() (tool.Tool, error) {
 		return GetNewsOutput{Data: result}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_news",
 		Description: "获取最新财经快讯，来源于财联社",
 	}, handler)
 }
+
+// newsSentimentDescription 新闻情绪工具描述
+const newsSentimentDescription = "对最近一批财经快讯（约20条）做情绪评分，返回利好/利空/中性条数、[-1,1]综合得分及代表性利好、利空标题；code 为空时评估市场整体情绪"
+
+// GetNewsSentimentInput 新闻情绪输入参数
+type GetNewsSentimentInput struct {
+	Code string `json:"code,omitzero" jsonschema:"股票代码，如 sh600519；为空时评估全部快讯"`
+}
+
+// createNewsSentimentTool 创建新闻情绪工具
+func (r *Registry) createNewsSentimentTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetNewsSentimentInput) (services.SentimentResult, error) {
+		fmt.Printf("[Tool:get_news_sentiment] 调用开始, code=%s\n", input.Code)
+
+		var stock models.Stock
+		if input.Code != "" {
+			stock.Symbol = models.NormalizeSymbol(input.Code)
+			// 名称用于匹配快讯，行情获取失败时只按代码匹配
+			if stocks, err := r.marketService.GetStockRealTimeDataCached(ctx, stock.Symbol); err == nil && len(stocks) > 0 {
+				stock.Name = stocks[0].Name
+			}
+		}
+
+		result, err := r.newsService.GetNewsSentiment(ctx, stock, r.newsClassifier)
+		if err != nil {
+			fmt.Printf("[Tool:get_news_sentiment] 错误: %v\n", err)
+			return services.SentimentResult{}, err
+		}
+
+		fmt.Printf("[Tool:get_news_sentiment] 调用完成, 利好%d 利空%d 中性%d\n", result.BullishCount, result.BearishCount, result.NeutralCount)
+		return *result, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_news_sentiment",
+		Description: newsSentimentDescription,
+	}, handler)
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"fmt"
 
+	"github.com/run-bigpig/jcp/internal/models"
+
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
@@ -26,8 +28,11 @@ func (r *Registry) createOrderBookTool() (tool.Tool, error) {
 			fmt.Println("[Tool:get_orderbook] 错误: 未提供股票代码")
 			return GetOrderBookOutput{Data: "请提供股票代码"}, nil
 		}
+		if models.IsIndexSymbol(input.Code) {
+			return GetOrderBookOutput{Data: indexNotApplicable}, nil
+		}
 
-		ob, err := r.marketService.GetRealOrderBook(input.Code)
+		ob, err := r.marketService.GetRealOrderBook(ctx, input.Code)
 		if err != nil {
 			fmt.Printf("[Tool:get_orderbook] 错误: %v\n", err)
 			return GetOrderBookOutput{}, err
@@ -48,7 +53,7 @@ func (r *Registry) createOrderBookTool() (tool.Tool, error) {
 		return GetOrderBookOutput{Data: result}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_orderbook",
 		Description: "获取股票五档盘口数据，显示买卖五档的价格和挂单量",
 	}, handler)
@@ -1,6 +1,9 @@
 package tools
 
 import (
+	"encoding/json"
+	"sync"
+
 	"github.com/run-bigpig/jcp/internal/services"
 	"github.com/run-bigpig/jcp/internal/services/hottrend"
 
@@ -13,6 +16,9 @@ type ToolInfo struct {
 	Description string `json:"description"`
 }
 
+// indexNotApplicable 个股专用工具被用于指数时的返回内容
+const indexNotApplicable = "指数不适用"
+
 // Registry 工具注册中心
 type Registry struct {
 	marketService         *services.MarketService
@@ -21,8 +27,16 @@ type Registry struct {
 	researchReportService *services.ResearchReportService
 	hotTrendService       *hottrend.HotTrendService
 	longHuBangService     *services.LongHuBangService
+	earningsService       *services.EarningsService
+	ipoService            *services.IPOService
+	webSearchService      *services.WebSearchService
+	urlReader             *services.URLReader
+	newsClassifier        services.NewsClassifier // 快讯情绪分类模型，未设置时使用词典打分
 	tools                 map[string]tool.Tool
 	toolInfos             map[string]ToolInfo // 工具信息映射
+
+	webSearchMu    sync.Mutex
+	webSearchCalls map[string]int // 未挂载会议计数时按 invocation 统计的 web_search 调用次数
 }
 
 // NewRegistry 创建工具注册中心
@@ -33,6 +47,9 @@ func NewRegistry(
 	researchReportService *services.ResearchReportService,
 	hotTrendService *hottrend.HotTrendService,
 	longHuBangService *services.LongHuBangService,
+	earningsService *services.EarningsService,
+	ipoService *services.IPOService,
+	webSearchService *services.WebSearchService,
 ) *Registry {
 	r := &Registry{
 		marketService:         marketService,
@@ -41,6 +58,10 @@ func NewRegistry(
 		researchReportService: researchReportService,
 		hotTrendService:       hotTrendService,
 		longHuBangService:     longHuBangService,
+		earningsService:       earningsService,
+		ipoService:            ipoService,
+		webSearchService:      webSearchService,
+		urlReader:             services.NewURLReader(),
 		tools:                 make(map[string]tool.Tool),
 		toolInfos:             make(map[string]ToolInfo),
 	}
@@ -54,14 +75,32 @@ func (r *Registry) registerAllTools() {
 	r.registerTool("get_stock_realtime", "获取股票实时行情数据，包括当前价格、涨跌幅、开盘价、最高价、最低价、成交量等", r.createStockRealtimeTool)
 
 	// 注册K线数据工具
-	r.registerTool("get_kline_data", "获取股票K线数据，支持5分钟线、日线、周线、月线", r.createKLineTool)
+	r.registerTool("get_kline_data", klineToolDescription, r.createKLineTool)
+
+	// 注册技术指标工具
+	r.registerTool("get_technical_indicators", "计算股票技术指标（MA、MACD、KDJ、RSI、布林带），返回最新指标值与金叉、超买等信号摘要", r.createTechnicalIndicatorsTool)
 
 	// 注册盘口数据工具
 	r.registerTool("get_orderbook", "获取股票五档盘口数据，包括买卖五档价格和数量", r.createOrderBookTool)
 
+	// 注册板块数据工具
+	r.registerTool("get_sector_data", sectorDataDescription, r.createSectorDataTool)
+
+	// 注册涨跌停板工具
+	r.registerTool("get_limit_board", limitBoardDescription, r.createLimitBoardTool)
+
 	// 注册快讯工具
 	r.registerTool("get_news", "获取最新财经快讯，来源于财联社", r.createNewsTool)
 
+	// 注册新闻情绪工具
+	r.registerTool("get_news_sentiment", newsSentimentDescription, r.createNewsSentimentTool)
+
+	// 注册公告工具
+	r.registerTool("get_announcements", announcementsDescription, r.createAnnouncementsTool)
+
+	// 注册公告正文工具
+	r.registerTool("get_announcement_content", announcementContentDescription, r.createAnnouncementContentTool)
+
 	// 注册股票搜索工具
 	r.registerTool("search_stocks", "搜索股票，根据关键词搜索股票代码和名称", r.createSearchStocksTool)
 
@@ -79,6 +118,29 @@ func (r *Registry) registerAllTools() {
 
 	// 注册龙虎榜营业部明细工具
 	r.registerTool("get_longhubang_detail", "获取个股龙虎榜营业部买卖明细，需要提供股票代码和交易日期", r.createLongHuBangDetailTool)
+
+	// 注册财报日历工具
+	r.registerTool("get_earnings_calendar", earningsCalendarDescription, r.createEarningsCalendarTool)
+
+	// 注册新股日历工具
+	r.registerTool("get_ipo_calendar", ipoCalendarDescription, r.createIPOCalendarTool)
+
+	// 注册网页搜索工具
+	r.registerTool("web_search", webSearchDescription, r.createWebSearchTool)
+
+	// 注册网页读取工具
+	r.registerTool("fetch_url", fetchURLDescription, r.createFetchURLTool)
+
+	// 注册财务报表工具
+	r.registerTool("get_stock_financial_report", financialReportDescription, r.createFinancialReportTool)
+
+	// 注册三大报表摘要工具
+	r.registerTool("get_financial_report", financialSummaryDescription, r.createFinancialSummaryTool)
+}
+
+// SetNewsClassifier 设置 get_news_sentiment 使用的快讯分类模型，应在会议开始前调用
+func (r *Registry) SetNewsClassifier(classify services.NewsClassifier) {
+	r.newsClassifier = classify
 }
 
 // registerTool 注册单个工具并保存信息
@@ -106,6 +168,28 @@ func (r *Registry) GetTools(names []string) []tool.Tool {
 	return result
 }
 
+// DeclarationSize 返回指定工具声明序列化后的字节数，即每次请求随附的工具描述大小
+// 只计入发送给模型的名称、描述与参数 schema，返回值 schema 不随请求发送
+func (r *Registry) DeclarationSize(names []string) int {
+	size := 0
+	for _, t := range r.GetTools(names) {
+		ft, ok := t.(functionTool)
+		if !ok {
+			continue
+		}
+		decl := ft.Declaration()
+		var params any = decl.ParametersJsonSchema
+		if decl.ParametersJsonSchema == nil {
+			params = decl.Parameters
+		}
+		data, err := json.Marshal(map[string]any{"name": decl.Name, "description": decl.Description, "parameters": params})
+		if err == nil {
+			size += len(data)
+		}
+	}
+	return size
+}
+
 // GetAllTools 获取所有工具
 func (r *Registry) GetAllTools() []tool.Tool {
 	var result []tool.Tool
@@ -3,6 +3,8 @@ package tools
 import (
 	"fmt"
 
+	"github.com/run-bigpig/jcp/internal/models"
+
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
@@ -30,6 +32,9 @@ func (r *Registry) createResearchReportTool() (tool.Tool, error) {
 			fmt.Println("[Tool:get_research_report] 错误: 未提供股票代码")
 			return GetResearchReportOutput{Data: "请提供股票代码"}, nil
 		}
+		if models.IsIndexSymbol(input.Code) {
+			return GetResearchReportOutput{Data: indexNotApplicable}, nil
+		}
 
 		pageSize := input.PageSize
 		if pageSize == 0 {
@@ -40,7 +45,7 @@ func (r *Registry) createResearchReportTool() (tool.Tool, error) {
 			pageNo = 1
 		}
 
-		result, err := r.researchReportService.GetResearchReports(input.Code, pageSize, pageNo)
+		result, err := r.researchReportService.GetResearchReports(ctx, input.Code, pageSize, pageNo)
 		if err != nil {
 			fmt.Printf("[Tool:get_research_report] 错误: %v\n", err)
 			return GetResearchReportOutput{}, err
@@ -55,7 +60,7 @@ func (r *Registry) createResearchReportTool() (tool.Tool, error) {
 		}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_research_report",
 		Description: "获取个股研报列表，包括券商评级、研究员、预测EPS/PE等信息",
 	}, handler)
@@ -82,7 +87,7 @@ func (r *Registry) createReportContentTool() (tool.Tool, error) {
 			return GetReportContentOutput{Content: "请提供研报的 infoCode"}, nil
 		}
 
-		result, err := r.researchReportService.GetReportContent(input.InfoCode)
+		result, err := r.researchReportService.GetReportContent(ctx, input.InfoCode)
 		if err != nil {
 			fmt.Printf("[Tool:get_report_content] 错误: %v\n", err)
 			return GetReportContentOutput{}, err
@@ -96,7 +101,7 @@ func (r *Registry) createReportContentTool() (tool.Tool, error) {
 		}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_report_content",
 		Description: "获取研报正文内容，需要先通过 get_research_report 获取研报列表中的 infoCode",
 	}, handler)
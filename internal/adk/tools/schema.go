@@ -0,0 +1,292 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/indicators"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// enumValuer 取值有限的输入类型，生成 schema 时写入 enum 约束
+type enumValuer interface {
+	EnumValues() []string
+}
+
+// KLinePeriod K线周期
+type KLinePeriod string
+
+// EnumValues 实现 enumValuer
+func (KLinePeriod) EnumValues() []string { return []string{"1m", "1d", "1w", "1mo"} }
+
+// HotTrendPlatform 舆情平台
+type HotTrendPlatform string
+
+// EnumValues 实现 enumValuer
+func (HotTrendPlatform) EnumValues() []string {
+	return []string{"weibo", "zhihu", "bilibili", "baidu", "douyin", "toutiao"}
+}
+
+// FinancialReportType 财务报表类型
+type FinancialReportType string
+
+// EnumValues 实现 enumValuer
+func (FinancialReportType) EnumValues() []string {
+	return []string{"income", "balance", "cashflow", "summary"}
+}
+
+// IndicatorName 技术指标名称
+type IndicatorName string
+
+// EnumValues 实现 enumValuer
+func (IndicatorName) EnumValues() []string { return indicators.Names }
+
+var enumValuerType = reflect.TypeFor[enumValuer]()
+
+// strictInputSchema 由输入结构体推导严格 schema
+// 所有字段列入 required，可选字段改为可空类型，数组显式声明元素类型，命名枚举类型写入 enum，
+// 满足 OpenAI strict function calling 的约束
+func strictInputSchema[T any]() (*jsonschema.Schema, error) {
+	s, err := jsonschema.For[T](nil)
+	if err != nil {
+		return nil, err
+	}
+	tightenSchema(s, reflect.TypeFor[T]())
+	return s, nil
+}
+
+// tightenSchema 按 Go 类型递归收紧 schema
+func tightenSchema(s *jsonschema.Schema, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	stripNull(s)
+
+	if t.Implements(enumValuerType) {
+		for _, v := range reflect.Zero(t).Interface().(enumValuer).EnumValues() {
+			s.Enum = append(s.Enum, v)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if s.Items == nil {
+			s.Items = &jsonschema.Schema{}
+		}
+		tightenSchema(s.Items, t.Elem())
+	case reflect.Struct:
+		required := make(map[string]bool, len(s.Required))
+		for _, name := range s.Required {
+			required[name] = true
+		}
+		s.Required = nil
+		for _, f := range reflect.VisibleFields(t) {
+			name := jsonFieldName(f)
+			prop, ok := s.Properties[name]
+			if f.Anonymous || !ok {
+				continue
+			}
+			tightenSchema(prop, f.Type)
+			if !required[name] {
+				makeNullable(prop)
+			}
+			s.Required = append(s.Required, name)
+		}
+		s.AdditionalProperties = &jsonschema.Schema{Not: &jsonschema.Schema{}}
+	}
+}
+
+// jsonFieldName 字段的 JSON 名称
+func jsonFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// stripNull 去掉推导时为切片、指针附加的 null，只保留实际类型
+func stripNull(s *jsonschema.Schema) {
+	if len(s.Types) == 0 {
+		return
+	}
+	var types []string
+	for _, t := range s.Types {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 1 {
+		s.Type, s.Types = types[0], nil
+	} else {
+		s.Types = types
+	}
+}
+
+// makeNullable 可选字段以可空类型表达（strict 模式要求字段全部 required）
+func makeNullable(s *jsonschema.Schema) {
+	if s.Type != "" {
+		s.Types = []string{s.Type, "null"}
+		s.Type = ""
+	}
+	if len(s.Enum) > 0 {
+		s.Enum = append(s.Enum, nil)
+	}
+}
+
+// listSeparator 模型把数组写成字符串时常用的分隔符
+var listSeparator = regexp.MustCompile(`[,，、;；\s]+`)
+
+// coerceArgs 宽松处理模型传入的参数，使其满足 schema
+// null 视为未传；数组字段收到 "a,b" 时拆分；整数字段收到数字字符串时转换
+func coerceArgs(toolName string, s *jsonschema.Schema, args map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for name, v := range args {
+		if v == nil {
+			continue
+		}
+		prop := s.Properties[name]
+		if prop == nil {
+			out[name] = v
+			continue
+		}
+		switch schemaType(prop) {
+		case "array":
+			if str, ok := v.(string); ok {
+				var items []any
+				for _, item := range listSeparator.Split(strings.TrimSpace(str), -1) {
+					if item != "" {
+						items = append(items, item)
+					}
+				}
+				fmt.Printf("[Tool:%s] 警告: 参数 %s 应为数组，已将字符串 %q 拆分为 %d 项\n", toolName, name, str, len(items))
+				v = items
+			}
+		case "integer":
+			if str, ok := v.(string); ok {
+				if n, err := strconv.Atoi(strings.TrimSpace(str)); err == nil {
+					fmt.Printf("[Tool:%s] 警告: 参数 %s 应为整数，已将字符串 %q 转换\n", toolName, name, str)
+					v = n
+				}
+			}
+		}
+		out[name] = v
+	}
+	return out
+}
+
+// schemaType 返回 schema 的非 null 类型
+func schemaType(s *jsonschema.Schema) string {
+	if s.Type != "" {
+		return s.Type
+	}
+	for _, t := range s.Types {
+		if t != "null" {
+			return t
+		}
+	}
+	return ""
+}
+
+// functionTool ADK 函数工具的运行时接口
+type functionTool interface {
+	tool.Tool
+	Declaration() *genai.FunctionDeclaration
+	Run(ctx tool.Context, args any) (map[string]any, error)
+}
+
+// strictTool 对外声明严格 schema，执行前宽松纠正参数后交给原工具
+type strictTool struct {
+	functionTool
+	schema *jsonschema.Schema
+}
+
+// newStrictTool 创建使用严格 schema 的函数工具
+// 原工具保留推导出的宽松 schema 做校验，纠正后的参数可以通过
+func newStrictTool[TArgs, TResults any](cfg functiontool.Config, handler functiontool.Func[TArgs, TResults]) (tool.Tool, error) {
+	schema, err := strictInputSchema[TArgs]()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build strict schema: %w", err)
+	}
+	inner, err := functiontool.New(cfg, handler)
+	if err != nil {
+		return nil, err
+	}
+	ft, ok := inner.(functionTool)
+	if !ok {
+		return nil, fmt.Errorf("unexpected tool type %T", inner)
+	}
+	return &strictTool{functionTool: ft, schema: schema}, nil
+}
+
+// Declaration 使用严格 schema 替换推导的参数 schema
+func (t *strictTool) Declaration() *genai.FunctionDeclaration {
+	decl := t.functionTool.Declaration()
+	decl.ParametersJsonSchema = t.schema
+	return decl
+}
+
+// Run 纠正参数后执行
+// 会议取消时立即返回 ctx 错误，不等待仍在进行的行情接口请求（由服务自身的超时兜底结束）
+func (t *strictTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	if m, ok := args.(map[string]any); ok {
+		args = coerceArgs(t.Name(), t.schema, m)
+	}
+	if ctx == nil {
+		return t.functionTool.Run(ctx, args)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		out map[string]any
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		out, err := t.functionTool.Run(ctx, args)
+		done <- result{out, err}
+	}()
+	select {
+	case r := <-done:
+		return r.out, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ProcessRequest 将工具声明写入请求
+// 不能委托给原工具：它会把自身注册到 req.Tools，导致执行时绕过参数纠正
+func (t *strictTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if req.Tools == nil {
+		req.Tools = make(map[string]any)
+	}
+	name := t.Name()
+	if _, ok := req.Tools[name]; ok {
+		return fmt.Errorf("duplicate tool: %q", name)
+	}
+	req.Tools[name] = t
+
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	for _, gt := range req.Config.Tools {
+		if gt != nil && gt.FunctionDeclarations != nil {
+			gt.FunctionDeclarations = append(gt.FunctionDeclarations, t.Declaration())
+			return nil
+		}
+	}
+	req.Config.Tools = append(req.Config.Tools, &genai.Tool{
+		FunctionDeclarations: []*genai.FunctionDeclaration{t.Declaration()},
+	})
+	return nil
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestStrictInputSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (any, error)
+		want string
+	}{
+		{
+			name: "stock_realtime",
+			fn:   func() (any, error) { return strictInputSchema[GetStockRealtimeInput]() },
+			want: `{"type":"object","required":["codes"],"properties":{"codes":{"type":"array","description":"股票代码列表，如 sh600519, sz000001","items":{"type":"string"}}},"additionalProperties":false}`,
+		},
+		{
+			name: "kline",
+			fn:   func() (any, error) { return strictInputSchema[GetKLineInput]() },
+			want: `{"type":"object","required":["code","period","days","calendarDays"],"properties":{"calendarDays":{"type":["integer","null"],"description":"按自然日取数的窗口天数，如最近一个月填30；填写后忽略days，一般无需使用"},"code":{"type":"string","description":"股票代码，如 sh600519"},"days":{"type":["integer","null"],"description":"K线根数，日线即交易日数（不含周末节假日），周线/月线为周数/月数，默认30"},"period":{"type":["string","null"],"description":"K线周期: 1m(5分钟), 1d(日线), 1w(周线), 1mo(月线)，默认1d","enum":["1m","1d","1w","1mo",null]}},"additionalProperties":false}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.fn()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, want any
+			data, _ := json.Marshal(s)
+			json.Unmarshal(data, &got)
+			json.Unmarshal([]byte(tt.want), &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("schema =\n%s\nwant\n%s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrictToolCoercesArgs(t *testing.T) {
+	var got GetKLineInput
+	var codes []string
+	klineTool, err := newStrictTool(functiontool.Config{Name: "kline"}, func(ctx tool.Context, input GetKLineInput) (GetKLineOutput, error) {
+		got = input
+		return GetKLineOutput{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	realtimeTool, err := newStrictTool(functiontool.Config{Name: "realtime"}, func(ctx tool.Context, input GetStockRealtimeInput) (GetStockRealtimeOutput, error) {
+		codes = input.Codes
+		return GetStockRealtimeOutput{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 数组写成逗号/顿号分隔的字符串
+	if _, err := realtimeTool.(functionTool).Run(nil, map[string]any{"codes": "sh600519, sz000001、sh000001"}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if want := []string{"sh600519", "sz000001", "sh000001"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("codes = %v, want %v", codes, want)
+	}
+
+	// strict 模式下可选字段传 null，整数写成字符串
+	if _, err := klineTool.(functionTool).Run(nil, map[string]any{"code": "sh600519", "period": nil, "days": "60"}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got.Code != "sh600519" || got.Period != "" || got.Days != 60 {
+		t.Errorf("input = %+v", got)
+	}
+}
+
+// cancelToolContext 只提供 context.Context 行为的 tool.Context
+type cancelToolContext struct {
+	tool.Context
+	ctx context.Context
+}
+
+func (c cancelToolContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c cancelToolContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c cancelToolContext) Err() error                  { return c.ctx.Err() }
+func (c cancelToolContext) Value(key any) any           { return c.ctx.Value(key) }
+
+func TestStrictToolRunAbortsOnCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slowTool, err := newStrictTool(functiontool.Config{Name: "slow"}, func(ctx tool.Context, input GetNewsInput) (GetNewsOutput, error) {
+		<-release // 模拟不响应取消的行情接口请求
+		return GetNewsOutput{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = slowTool.(functionTool).Run(cancelToolContext{ctx: ctx}, map[string]any{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("取消后 %v 才返回", d)
+	}
+}
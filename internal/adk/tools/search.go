@@ -49,7 +49,7 @@ func (r *Registry) createSearchStocksTool() (tool.Tool, error) {
 		return SearchStocksOutput{Data: result}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "search_stocks",
 		Description: "搜索股票，支持按代码或名称搜索",
 	}, handler)
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// sectorDataDescription 板块工具描述
+const sectorDataDescription = "获取行业/概念板块表现：当日领涨领跌板块的涨跌幅、领涨股、主力净流入和5日相对强弱；传入股票代码时额外返回该股所属的行业与概念及其当日排名，用于回答“属于什么板块、板块强不强”"
+
+// GetSectorDataInput 板块数据输入参数
+type GetSectorDataInput struct {
+	Code  string `json:"code,omitzero" jsonschema:"股票代码，如 sh600519；不填或为指数时只返回板块排行"`
+	Limit int    `json:"limit,omitzero" jsonschema:"领涨/领跌板块各返回的条数，默认5条，最多20条"`
+}
+
+// GetSectorDataOutput 板块数据输出
+type GetSectorDataOutput struct {
+	Data string `json:"data" jsonschema:"板块表现数据"`
+}
+
+// createSectorDataTool 创建板块数据工具
+func (r *Registry) createSectorDataTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetSectorDataInput) (GetSectorDataOutput, error) {
+		fmt.Printf("[Tool:get_sector_data] 调用开始, code=%s, limit=%d\n", input.Code, input.Limit)
+
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 5
+		}
+		if limit > 20 {
+			limit = 20
+		}
+
+		var sb strings.Builder
+		if input.Code != "" && !models.IsIndexSymbol(input.Code) {
+			sectors, err := r.marketService.GetStockSectors(ctx, input.Code)
+			if err != nil {
+				fmt.Printf("[Tool:get_sector_data] 获取所属板块失败: %v\n", err)
+				fmt.Fprintf(&sb, "所属板块获取失败: %v\n\n", err)
+			} else {
+				formatStockSectors(&sb, sectors)
+			}
+		}
+
+		overview, err := r.marketService.GetSectorPerformance(ctx, limit)
+		if err != nil {
+			fmt.Printf("[Tool:get_sector_data] 错误: %v\n", err)
+			if sb.Len() == 0 {
+				return GetSectorDataOutput{}, err
+			}
+			fmt.Fprintf(&sb, "板块排行获取失败: %v\n", err)
+			return GetSectorDataOutput{Data: sb.String()}, nil
+		}
+		formatSectorOverview(&sb, overview)
+
+		fmt.Printf("[Tool:get_sector_data] 调用完成\n")
+		return GetSectorDataOutput{Data: sb.String()}, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "get_sector_data",
+		Description: sectorDataDescription,
+	}, handler)
+}
+
+// formatStockSectors 格式化个股所属板块
+func formatStockSectors(sb *strings.Builder, s *models.StockSectors) {
+	fmt.Fprintf(sb, "【%s 所属板块】\n", s.Code)
+	if len(s.Industries) == 0 && len(s.Concepts) == 0 {
+		sb.WriteString("  未找到所属行业/概念板块\n")
+	}
+	for _, sector := range s.Industries {
+		sb.WriteString("  行业 ")
+		writeSectorLine(sb, sector, s.IndustryCount, s.Benchmark != "")
+	}
+	for _, sector := range s.Concepts {
+		sb.WriteString("  概念 ")
+		writeSectorLine(sb, sector, s.ConceptCount, s.Benchmark != "")
+	}
+	writeBenchmark(sb, s.Benchmark, s.BenchmarkChange5D)
+	sb.WriteString("\n")
+}
+
+// formatSectorOverview 格式化板块涨跌排行
+func formatSectorOverview(sb *strings.Builder, o *models.SectorOverview) {
+	hasRS := o.Benchmark != ""
+	writeList := func(title string, sectors []models.SectorPerformance, total int) {
+		if len(sectors) == 0 {
+			return
+		}
+		fmt.Fprintf(sb, "【%s】\n", title)
+		for _, sector := range sectors {
+			sb.WriteString("  ")
+			writeSectorLine(sb, sector, total, hasRS)
+		}
+	}
+	writeList("领涨行业", o.TopIndustries, o.IndustryCount)
+	writeList("领跌行业", o.BottomIndustries, o.IndustryCount)
+	writeList("领涨概念", o.TopConcepts, o.ConceptCount)
+	writeList("领跌概念", o.BottomConcepts, o.ConceptCount)
+	writeBenchmark(sb, o.Benchmark, o.BenchmarkChange5D)
+}
+
+// writeSectorLine 输出单个板块：排名、涨跌幅、5日表现、主力净流入与领涨股
+func writeSectorLine(sb *strings.Builder, s models.SectorPerformance, total int, hasRS bool) {
+	fmt.Fprintf(sb, "%s %+.2f%% 排名 %d/%d", s.Name, s.ChangePercent, s.Rank, total)
+	if hasRS {
+		fmt.Fprintf(sb, " 5日 %+.2f%%(相对强弱 %+.2f)", s.Change5D, s.RelStrength5D)
+	} else {
+		fmt.Fprintf(sb, " 5日 %+.2f%%", s.Change5D)
+	}
+	fmt.Fprintf(sb, " 主力净流入 %.2f亿", s.NetInflow/1e8)
+	if s.LeaderName != "" {
+		fmt.Fprintf(sb, " 领涨 %s %+.2f%%", s.LeaderName, s.LeaderChange)
+	}
+	sb.WriteString("\n")
+}
+
+// writeBenchmark 输出相对强弱的计算口径
+func writeBenchmark(sb *strings.Builder, name string, change5D float64) {
+	if name == "" {
+		return
+	}
+	fmt.Fprintf(sb, "（相对强弱 = 板块5日涨跌幅 - %s 5日涨跌幅 %+.2f%%，为正表示近5日强于大盘）\n", name, change5D)
+}
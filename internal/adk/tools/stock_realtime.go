@@ -28,7 +28,7 @@ func (r *Registry) createStockRealtimeTool() (tool.Tool, error) {
 			return GetStockRealtimeOutput{Data: "请提供股票代码"}, nil
 		}
 
-		stocks, err := r.marketService.GetStockRealTimeData(input.Codes...)
+		stocks, err := r.marketService.GetStockRealTimeDataCached(ctx, input.Codes...)
 		if err != nil {
 			fmt.Printf("[Tool:get_stock_realtime] 错误: %v\n", err)
 			return GetStockRealtimeOutput{}, err
@@ -43,7 +43,7 @@ func (r *Registry) createStockRealtimeTool() (tool.Tool, error) {
 
 		// 获取大盘指数数据
 		var marketIndexResult string
-		indices, err := r.marketService.GetMarketIndices()
+		indices, err := r.marketService.GetMarketIndices(ctx)
 		if err != nil {
 			fmt.Printf("[Tool:get_stock_realtime] 获取大盘指数失败: %v\n", err)
 		} else {
@@ -57,7 +57,7 @@ func (r *Registry) createStockRealtimeTool() (tool.Tool, error) {
 		return GetStockRealtimeOutput{Data: result, MarketIndex: marketIndexResult}, nil
 	}
 
-	return functiontool.New(functiontool.Config{
+	return newStrictTool(functiontool.Config{
 		Name:        "get_stock_realtime",
 		Description: "获取股票实时行情数据，包括当前价格、涨跌幅、开盘价、最高价、最低价、成交量等，以及大盘指数数据",
 	}, handler)
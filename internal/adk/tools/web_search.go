@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// webSearchDescription 网页搜索工具描述
+const webSearchDescription = "搜索互联网网页，返回标题、摘要和链接，可选抓取第一条结果的正文；用于查询行情数据工具覆盖不到的最新事件、政策原文和公司动态。每场会议调用次数有限，请合并关键词"
+
+// firstPageMaxChars 抓取第一条结果正文的字数上限
+const firstPageMaxChars = 3000
+
+// WebSearchInput 网页搜索输入参数
+type WebSearchInput struct {
+	Query      string `json:"query" jsonschema:"搜索关键词"`
+	Limit      int    `json:"limit,omitzero" jsonschema:"返回条数，默认5条，最多10条"`
+	FetchFirst bool   `json:"fetchFirst,omitzero" jsonschema:"是否抓取第一条结果的网页正文（最多3000字）"`
+}
+
+// WebSearchItem 网页搜索结果
+type WebSearchItem struct {
+	Title   string `json:"title" jsonschema:"标题"`
+	Snippet string `json:"snippet" jsonschema:"摘要"`
+	URL     string `json:"url" jsonschema:"链接"`
+}
+
+// WebSearchOutput 网页搜索输出
+type WebSearchOutput struct {
+	Results   []WebSearchItem `json:"results" jsonschema:"搜索结果"`
+	FirstPage string          `json:"firstPage,omitzero" jsonschema:"第一条结果的网页正文"`
+	Message   string          `json:"message,omitzero" jsonschema:"补充说明"`
+}
+
+// webSearchBudget 一场会议内 web_search 的调用计数
+type webSearchBudget struct {
+	calls atomic.Int32
+}
+
+type webSearchBudgetKey struct{}
+
+// WithWebSearchBudget 返回携带新的 web_search 调用计数的 context，每场会议开始时调用一次
+// 同一场会议的所有专家共用该计数，上限取搜索配置的每场会议调用次数
+func WithWebSearchBudget(ctx context.Context) context.Context {
+	return context.WithValue(ctx, webSearchBudgetKey{}, &webSearchBudget{})
+}
+
+// maxInvocationBudgets 按 invocation 计数的最多条目，超出时清空重新计数
+const maxInvocationBudgets = 256
+
+// takeWebSearchCall 占用一次调用额度，超出上限时返回 false
+// 未挂载会议计数时按 invocation 计数（如单独重试的专家），避免模型循环调用
+func (r *Registry) takeWebSearchCall(ctx tool.Context, max int) bool {
+	if budget, ok := ctx.Value(webSearchBudgetKey{}).(*webSearchBudget); ok {
+		return int(budget.calls.Add(1)) <= max
+	}
+	r.webSearchMu.Lock()
+	defer r.webSearchMu.Unlock()
+	if r.webSearchCalls == nil || len(r.webSearchCalls) >= maxInvocationBudgets {
+		r.webSearchCalls = make(map[string]int)
+	}
+	r.webSearchCalls[ctx.InvocationID()]++
+	return r.webSearchCalls[ctx.InvocationID()] <= max
+}
+
+// createWebSearchTool 创建网页搜索工具
+func (r *Registry) createWebSearchTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input WebSearchInput) (WebSearchOutput, error) {
+		fmt.Printf("[Tool:web_search] 调用开始, query=%s, limit=%d, fetchFirst=%v\n", input.Query, input.Limit, input.FetchFirst)
+
+		if input.Query == "" {
+			return WebSearchOutput{Results: []WebSearchItem{}, Message: "请提供搜索关键词"}, nil
+		}
+		max := r.webSearchService.Config().MaxCallsPerMeeting
+		if max <= 0 {
+			max = services.DefaultWebSearchCalls
+		}
+		if !r.takeWebSearchCall(ctx, max) {
+			fmt.Printf("[Tool:web_search] 已达本场会议调用上限 %d 次\n", max)
+			return WebSearchOutput{Results: []WebSearchItem{}, Message: fmt.Sprintf("本场会议网页搜索已达上限 %d 次，请基于已获取的信息作答", max)}, nil
+		}
+
+		results, err := r.webSearchService.Search(ctx, input.Query, input.Limit)
+		if errors.Is(err, services.ErrWebSearchNotConfigured) {
+			return WebSearchOutput{Results: []WebSearchItem{}, Message: err.Error()}, nil
+		}
+		if err != nil {
+			fmt.Printf("[Tool:web_search] 错误: %v\n", err)
+			return WebSearchOutput{}, err
+		}
+
+		output := WebSearchOutput{Results: make([]WebSearchItem, 0, len(results))}
+		for _, res := range results {
+			output.Results = append(output.Results, WebSearchItem{Title: res.Title, Snippet: res.Snippet, URL: res.URL})
+		}
+		if len(results) == 0 {
+			output.Message = "未找到相关网页"
+		} else if input.FetchFirst {
+			// 与 fetch_url 共用网页读取器，同样校验协议、内网地址与跳转；正文抓取失败不影响搜索结果
+			if page, err := r.urlReader.Read(ctx, results[0].URL, firstPageMaxChars); err != nil {
+				output.Message = fmt.Sprintf("第一条结果正文获取失败: %v", err)
+			} else {
+				output.FirstPage = page.Text
+			}
+		}
+		fmt.Printf("[Tool:web_search] 调用完成, 返回%d条结果\n", len(output.Results))
+		return output, nil
+	}
+
+	return newStrictTool(functiontool.Config{
+		Name:        "web_search",
+		Description: webSearchDescription,
+	}, handler)
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+// invocationToolContext 带固定 invocation ID 的 tool.Context
+type invocationToolContext struct {
+	cancelToolContext
+	id string
+}
+
+func (c invocationToolContext) InvocationID() string { return c.id }
+
+func TestTakeWebSearchCall(t *testing.T) {
+	r := NewRegistry(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// 同一场会议的多位专家共用额度
+	meeting := WithWebSearchBudget(context.Background())
+	for i := 1; i <= 6; i++ {
+		ctx := invocationToolContext{cancelToolContext{ctx: meeting}, string(rune('a' + i))}
+		if got, want := r.takeWebSearchCall(ctx, 5), i <= 5; got != want {
+			t.Errorf("call %d = %v, want %v", i, got, want)
+		}
+	}
+	// 新会议重新计数
+	next := invocationToolContext{cancelToolContext{ctx: WithWebSearchBudget(context.Background())}, "a"}
+	if !r.takeWebSearchCall(next, 5) {
+		t.Error("new meeting should have a fresh budget")
+	}
+
+	// 未挂载会议计数时按 invocation 计数
+	single := invocationToolContext{cancelToolContext{ctx: context.Background()}, "retry"}
+	for i := 1; i <= 3; i++ {
+		if got, want := r.takeWebSearchCall(single, 2), i <= 2; got != want {
+			t.Errorf("invocation call %d = %v, want %v", i, got, want)
+		}
+	}
+	if !r.takeWebSearchCall(invocationToolContext{cancelToolContext{ctx: context.Background()}, "other"}, 2) {
+		t.Error("other invocation should have its own budget")
+	}
+}
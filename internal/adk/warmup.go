@@ -0,0 +1,181 @@
+package adk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk/anthropic"
+	"github.com/run-bigpig/jcp/internal/adk/ollama"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+const (
+	// MaxConcurrentWarmups 同时预热的 Provider 数上限
+	MaxConcurrentWarmups = 3
+	// warmupTimeout 单个 Provider 预热超时
+	warmupTimeout = 5 * time.Second
+	// warmupTTL 预热有效期，需短于共享 Transport 的 IdleConnTimeout(90s)
+	warmupTTL = 60 * time.Second
+)
+
+// warmupState 预热状态，key 为 Provider 源站（scheme://host）
+type warmupState struct {
+	mu       sync.Mutex
+	warmedAt map[string]time.Time
+	inflight map[string]bool
+}
+
+// warmupOrigin 解析配置实际请求的源站
+// 所有模型客户端共用代理管理器的 SharedTransport，预热建立的连接会被同源请求复用
+func warmupOrigin(config *models.AIConfig) string {
+	var endpoint string
+	switch config.Provider {
+	case models.AIProviderOpenAI:
+		endpoint = openAIEndpoint(config, "chat/completions")
+	case models.AIProviderDeepSeek:
+		endpoint = openAIEndpoint(deepSeekConfig(config), "chat/completions")
+	case models.AIProviderQwen:
+		endpoint = openAIEndpoint(qwenConfig(config), "chat/completions")
+	case models.AIProviderMistral:
+		endpoint = openAIEndpoint(mistralConfig(config), "chat/completions")
+	case models.AIProviderAnthropic:
+		endpoint = normalizeAnthropicBaseURL(config.BaseURL)
+	case models.AIProviderBedrock:
+		endpoint = anthropic.BedrockEndpoint(bedrockConfig(config))
+	case models.AIProviderOllama:
+		endpoint = ollama.NormalizeBaseURL(config.BaseURL)
+	case models.AIProviderGemini:
+		endpoint = "https://generativelanguage.googleapis.com"
+	case models.AIProviderVertexAI, models.AIProviderVertexAIGarden:
+		if config.Location == "" || config.Location == "global" {
+			endpoint = "https://aiplatform.googleapis.com"
+		} else {
+			endpoint = "https://" + config.Location + "-aiplatform.googleapis.com"
+		}
+	default:
+		return ""
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// IsWarm 该配置的源站是否在有效期内预热过
+func (f *ModelFactory) IsWarm(config *models.AIConfig) bool {
+	if config == nil {
+		return false
+	}
+	origin := warmupOrigin(config)
+	f.warm.mu.Lock()
+	defer f.warm.mu.Unlock()
+	t, ok := f.warm.warmedAt[origin]
+	return ok && time.Since(t) < warmupTTL
+}
+
+// claimWarmup 标记源站开始预热，已预热或正在预热时返回 false
+func (f *ModelFactory) claimWarmup(origin string) bool {
+	f.warm.mu.Lock()
+	defer f.warm.mu.Unlock()
+	if f.warm.warmedAt == nil {
+		f.warm.warmedAt = make(map[string]time.Time)
+		f.warm.inflight = make(map[string]bool)
+	}
+	if t, ok := f.warm.warmedAt[origin]; ok && time.Since(t) < warmupTTL {
+		return false
+	}
+	if f.warm.inflight[origin] {
+		return false
+	}
+	f.warm.inflight[origin] = true
+	return true
+}
+
+// finishWarmup 结束预热，成功时记录时间
+func (f *ModelFactory) finishWarmup(origin string, ok bool) {
+	f.warm.mu.Lock()
+	defer f.warm.mu.Unlock()
+	delete(f.warm.inflight, origin)
+	if ok {
+		f.warm.warmedAt[origin] = time.Now()
+	}
+}
+
+// Warmup 预建立到各 Provider 的连接，降低首个专家发言的首 token 延迟
+// 按源站去重，最多 MaxConcurrentWarmups 个并发；返回本次实际预热成功的源站数
+func (f *ModelFactory) Warmup(ctx context.Context, configs []*models.AIConfig, mode models.ModelWarmup) int {
+	if mode != models.ModelWarmupConnect && mode != models.ModelWarmupPing {
+		return 0
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		warmed int
+		sem    = make(chan struct{}, MaxConcurrentWarmups)
+	)
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		origin := warmupOrigin(cfg)
+		if origin == "" || !f.claimWarmup(origin) {
+			continue
+		}
+		wg.Add(1)
+		go func(cfg *models.AIConfig, origin string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				f.finishWarmup(origin, false)
+				return
+			}
+
+			start := time.Now()
+			err := f.warmupOne(ctx, cfg, origin, mode)
+			f.finishWarmup(origin, err == nil)
+			if err != nil {
+				log.Warn("预热 %s 失败(%s): %v", origin, mode, err)
+				return
+			}
+			log.Info("预热 %s 完成(%s)，耗时 %v", origin, mode, time.Since(start))
+			mu.Lock()
+			warmed++
+			mu.Unlock()
+		}(cfg, origin)
+	}
+	wg.Wait()
+	return warmed
+}
+
+// warmupOne 预热单个源站
+// connect：HEAD 源站，只完成 DNS/TCP/TLS 握手，不消耗 token
+// ping：复用连接测试发送 1 token 请求，连同鉴权链路一起预热
+func (f *ModelFactory) warmupOne(ctx context.Context, config *models.AIConfig, origin string, mode models.ModelWarmup) error {
+	ctx, cancel := context.WithTimeout(ctx, warmupTimeout)
+	defer cancel()
+
+	if mode == models.ModelWarmupPing {
+		return f.TestConnection(ctx, config)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, origin, nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Transport: &uaTransport{base: proxy.GetManager().SharedTransport()}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	// 任意状态码都说明连接已建立；关闭 Body 让连接回到空闲池
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,116 @@
+package adk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestWarmup_DedupAndConcurrencyLimit(t *testing.T) {
+	var (
+		heads          int32
+		inflight, peak int32
+		peakMu         sync.Mutex
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		atomic.AddInt32(&heads, 1)
+		n := atomic.AddInt32(&inflight, 1)
+		peakMu.Lock()
+		if n > peak {
+			peak = n
+		}
+		peakMu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&inflight, -1)
+	})
+
+	var configs []*models.AIConfig
+	for i := 0; i < MaxConcurrentWarmups+2; i++ {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+		configs = append(configs, &models.AIConfig{Provider: models.AIProviderOpenAI, BaseURL: srv.URL})
+	}
+	// 同源配置只预热一次
+	configs = append(configs, &models.AIConfig{Provider: models.AIProviderOpenAI, BaseURL: configs[0].BaseURL + "/v1", ModelName: "other"})
+
+	f := NewModelFactory()
+	if n := f.Warmup(context.Background(), configs, models.ModelWarmupOff); n != 0 {
+		t.Fatalf("off mode warmed %d", n)
+	}
+	if n := f.Warmup(context.Background(), configs, models.ModelWarmupConnect); n != MaxConcurrentWarmups+2 {
+		t.Fatalf("warmed = %d, want %d", n, MaxConcurrentWarmups+2)
+	}
+	if heads != int32(MaxConcurrentWarmups+2) {
+		t.Errorf("HEAD requests = %d, want %d", heads, MaxConcurrentWarmups+2)
+	}
+	if peak > MaxConcurrentWarmups {
+		t.Errorf("peak concurrency = %d, want <= %d", peak, MaxConcurrentWarmups)
+	}
+	if !f.IsWarm(configs[len(configs)-1]) {
+		t.Error("config sharing the origin should be warm")
+	}
+
+	// 有效期内不重复预热
+	if n := f.Warmup(context.Background(), configs, models.ModelWarmupConnect); n != 0 || heads != int32(MaxConcurrentWarmups+2) {
+		t.Errorf("second warmup = %d, heads = %d", n, heads)
+	}
+}
+
+func TestWarmup_ConnectionReusedByModel(t *testing.T) {
+	var newConns int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		json.NewEncoder(w).Encode(map[string]any{
+			"message": map[string]string{"role": "assistant", "content": "ok"},
+			"done":    true,
+		})
+	}))
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	f := NewModelFactory()
+	config := &models.AIConfig{ID: "warm", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	if n := f.Warmup(context.Background(), []*models.AIConfig{config}, models.ModelWarmupConnect); n != 1 {
+		t.Fatalf("warmed = %d", n)
+	}
+	if n := atomic.LoadInt32(&newConns); n != 1 {
+		t.Fatalf("warmup opened %d connections", n)
+	}
+
+	llm, err := f.CreateModel(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)}}
+	for _, err := range llm.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 模型请求复用预热建立的连接，不再新建
+	if n := atomic.LoadInt32(&newConns); n != 1 {
+		t.Errorf("connections = %d after model request, want 1", n)
+	}
+}
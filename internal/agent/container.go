@@ -1,63 +1,188 @@
 package agent
 
 import (
+	"reflect"
+	"slices"
 	"sync"
 
+	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/models"
 )
 
+var log = logger.New("AgentContainer")
+
+// Snapshot 某一时刻的专家配置快照，创建后只读
+// 会议开始时获取快照并全程使用，期间编辑专家不会影响进行中的会议
+type Snapshot struct {
+	Version int64
+	order   []string
+	configs map[string]models.AgentConfig
+}
+
+// newSnapshot 深拷贝配置创建快照，保留传入顺序，重复 ID 以后者为准
+func newSnapshot(version int64, configs []models.AgentConfig) *Snapshot {
+	s := &Snapshot{Version: version, configs: make(map[string]models.AgentConfig, len(configs))}
+	for _, cfg := range configs {
+		if _, ok := s.configs[cfg.ID]; !ok {
+			s.order = append(s.order, cfg.ID)
+		}
+		s.configs[cfg.ID] = cloneConfig(cfg)
+	}
+	return s
+}
+
+// cloneConfig 深拷贝，避免与调用方共享 Tools/MCPServers 底层数组
+func cloneConfig(cfg models.AgentConfig) models.AgentConfig {
+	cfg.Tools = slices.Clone(cfg.Tools)
+	cfg.MCPServers = slices.Clone(cfg.MCPServers)
+	return cfg
+}
+
+// Get 获取指定专家配置的副本
+func (s *Snapshot) Get(id string) (models.AgentConfig, bool) {
+	cfg, ok := s.configs[id]
+	if !ok {
+		return models.AgentConfig{}, false
+	}
+	return cloneConfig(cfg), true
+}
+
+// All 按加载顺序返回全部专家配置的副本
+func (s *Snapshot) All() []models.AgentConfig {
+	result := make([]models.AgentConfig, 0, len(s.order))
+	for _, id := range s.order {
+		result = append(result, cloneConfig(s.configs[id]))
+	}
+	return result
+}
+
+// Enabled 按加载顺序返回已启用的专家配置副本
+func (s *Snapshot) Enabled() []models.AgentConfig {
+	var result []models.AgentConfig
+	for _, id := range s.order {
+		if cfg := s.configs[id]; cfg.Enabled {
+			result = append(result, cloneConfig(cfg))
+		}
+	}
+	return result
+}
+
+// ByIDs 按 ids 顺序返回存在的专家配置副本
+func (s *Snapshot) ByIDs(ids []string) []models.AgentConfig {
+	var result []models.AgentConfig
+	for _, id := range ids {
+		if cfg, ok := s.configs[id]; ok {
+			result = append(result, cloneConfig(cfg))
+		}
+	}
+	return result
+}
+
+// AgentDiff 两次加载之间的专家变更（ID 列表）
+type AgentDiff struct {
+	Version int64    `json:"version"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// Empty 是否没有任何变更
+func (d AgentDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// diffSnapshots 计算 prev 到 next 的变更
+func diffSnapshots(prev, next *Snapshot) AgentDiff {
+	diff := AgentDiff{Version: next.Version}
+	for _, id := range next.order {
+		old, ok := prev.configs[id]
+		if !ok {
+			diff.Added = append(diff.Added, id)
+		} else if !reflect.DeepEqual(old, next.configs[id]) {
+			diff.Changed = append(diff.Changed, id)
+		}
+	}
+	for _, id := range prev.order {
+		if _, ok := next.configs[id]; !ok {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+	return diff
+}
+
 // Container 专家容器
+// 持有当前专家配置快照，LoadAgents 整体替换快照而不修改旧快照
 type Container struct {
-	agents map[string]*ExpertAgent
-	mu     sync.RWMutex
+	snapshot *Snapshot
+	onChange func(AgentDiff)
+	mu       sync.RWMutex
 }
 
 // NewContainer 创建专家容器
 func NewContainer() *Container {
 	return &Container{
-		agents: make(map[string]*ExpertAgent),
+		snapshot: newSnapshot(0, nil),
 	}
 }
 
-// LoadAgents 加载Agent配置到容器
-func (c *Container) LoadAgents(configs []models.AgentConfig) {
+// SetChangeListener 设置专家变更回调（LoadAgents 产生变更时调用）
+func (c *Container) SetChangeListener(fn func(AgentDiff)) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.onChange = fn
+}
 
-	for i := range configs {
-		c.agents[configs[i].ID] = NewExpertAgent(&configs[i])
+// LoadAgents 以 configs 替换全部专家配置，返回相对上一快照的变更
+// 无变更时不生成新快照，也不触发回调
+func (c *Container) LoadAgents(configs []models.AgentConfig) AgentDiff {
+	c.mu.Lock()
+	next := newSnapshot(c.snapshot.Version+1, configs)
+	diff := diffSnapshots(c.snapshot, next)
+	if diff.Empty() {
+		c.mu.Unlock()
+		return AgentDiff{Version: c.snapshot.Version}
+	}
+	c.snapshot = next
+	onChange := c.onChange
+	c.mu.Unlock()
+
+	log.Info("专家配置已更新 v%d: 新增 %v, 删除 %v, 修改 %v", diff.Version, diff.Added, diff.Removed, diff.Changed)
+	if onChange != nil {
+		onChange(diff)
 	}
+	return diff
 }
 
-// GetAgent 获取指定Agent
-func (c *Container) GetAgent(id string) *ExpertAgent {
+// Snapshot 获取当前专家配置快照
+func (c *Container) Snapshot() *Snapshot {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.agents[id]
+	return c.snapshot
+}
+
+// GetAgent 获取指定Agent
+func (c *Container) GetAgent(id string) *ExpertAgent {
+	cfg, ok := c.Snapshot().Get(id)
+	if !ok {
+		return nil
+	}
+	return NewExpertAgent(&cfg)
 }
 
 // GetAgentsByIDs 根据ID列表获取Agent
 func (c *Container) GetAgentsByIDs(ids []string) []*ExpertAgent {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	var result []*ExpertAgent
-	for _, id := range ids {
-		if agent, ok := c.agents[id]; ok {
-			result = append(result, agent)
-		}
-	}
-	return result
+	return toExpertAgents(c.Snapshot().ByIDs(ids))
 }
 
 // GetAllAgents 获取所有Agent
 func (c *Container) GetAllAgents() []*ExpertAgent {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	return toExpertAgents(c.Snapshot().All())
+}
 
-	result := make([]*ExpertAgent, 0, len(c.agents))
-	for _, agent := range c.agents {
-		result = append(result, agent)
+func toExpertAgents(configs []models.AgentConfig) []*ExpertAgent {
+	result := make([]*ExpertAgent, 0, len(configs))
+	for i := range configs {
+		result = append(result, NewExpertAgent(&configs[i]))
 	}
 	return result
 }
@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestContainerLoadAgentsDiff(t *testing.T) {
+	c := NewContainer()
+	var events []AgentDiff
+	c.SetChangeListener(func(d AgentDiff) { events = append(events, d) })
+
+	configs := []models.AgentConfig{
+		{ID: "a", Name: "A", Enabled: true, Tools: []string{"get_news"}},
+		{ID: "b", Name: "B", Enabled: false},
+	}
+	diff := c.LoadAgents(configs)
+	if diff.Version != 1 || !slices.Equal(diff.Added, []string{"a", "b"}) || len(events) != 1 {
+		t.Fatalf("diff = %+v, events = %d", diff, len(events))
+	}
+	snap := c.Snapshot()
+
+	// 调用方修改传入切片不影响快照
+	configs[0].Tools[0] = "get_kline_data"
+	if cfg, _ := snap.Get("a"); cfg.Tools[0] != "get_news" {
+		t.Fatalf("快照应深拷贝 Tools: %v", cfg.Tools)
+	}
+
+	// 相同配置不产生新版本
+	if diff := c.LoadAgents(snap.All()); !diff.Empty() || diff.Version != 1 || len(events) != 1 {
+		t.Fatalf("无变更 diff = %+v, events = %d", diff, len(events))
+	}
+
+	diff = c.LoadAgents([]models.AgentConfig{
+		{ID: "a", Name: "A2", Enabled: true, Tools: []string{"get_news"}},
+		{ID: "c", Name: "C", Enabled: true},
+	})
+	if diff.Version != 2 || !slices.Equal(diff.Added, []string{"c"}) ||
+		!slices.Equal(diff.Removed, []string{"b"}) || !slices.Equal(diff.Changed, []string{"a"}) {
+		t.Fatalf("diff = %+v", diff)
+	}
+
+	// 旧快照保持不变，新快照反映变更
+	if cfg, _ := snap.Get("a"); cfg.Name != "A" || len(snap.All()) != 2 {
+		t.Errorf("旧快照被修改: %+v", snap.All())
+	}
+	if got := c.Snapshot().Enabled(); len(got) != 2 || got[0].Name != "A2" || got[1].ID != "c" {
+		t.Errorf("Enabled = %+v", got)
+	}
+	if got := c.GetAgentsByIDs([]string{"c", "b", "a"}); len(got) != 2 || got[0].GetID() != "c" || got[1].GetName() != "A2" {
+		t.Errorf("GetAgentsByIDs = %+v", got)
+	}
+	if c.GetAgent("b") != nil {
+		t.Error("已删除的专家不应返回")
+	}
+}
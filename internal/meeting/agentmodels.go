@@ -0,0 +1,71 @@
+package meeting
+
+import (
+	"context"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+)
+
+// agentModels 单次会议内按 AI 配置 ID 缓存模型，使用同一配置的专家共用一个实例
+type agentModels struct {
+	s          *Service
+	defaultCfg *models.AIConfig // 会议配置，专家未指定或指定的配置不可用时使用
+
+	mu   sync.Mutex
+	llms map[string]model.LLM
+}
+
+// newAgentModels 创建会议级模型缓存，defaultLLM 为已创建的会议模型（可为 nil）
+func (s *Service) newAgentModels(defaultCfg *models.AIConfig, defaultLLM model.LLM) *agentModels {
+	am := &agentModels{s: s, defaultCfg: defaultCfg, llms: make(map[string]model.LLM)}
+	if defaultCfg != nil && defaultLLM != nil {
+		am.llms[defaultCfg.ID] = defaultLLM
+	}
+	return am
+}
+
+// forAgent 返回专家使用的模型及其配置
+// 专家的 AIConfigID 为空或无法解析时使用会议配置；专属模型创建失败同样降级为会议配置
+// 会议中调用时登记到运行记录，复现模式下返回强制温度与种子的模型
+func (am *agentModels) forAgent(ctx context.Context, agentCfg *models.AgentConfig) (model.LLM, *models.AIConfig, error) {
+	cfg := am.s.resolveAgentAIConfig(agentCfg, am.defaultCfg)
+	llm, err := am.get(ctx, cfg)
+	if err != nil && cfg != am.defaultCfg {
+		log.Warn("agent %s create model %s error, using meeting config: %v", agentCfg.ID, cfg.ModelName, err)
+		cfg = am.defaultCfg
+		llm, err = am.get(ctx, cfg)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return meetingRunFrom(ctx).bind(agentCfg, cfg, llm), cfg, nil
+}
+
+// get 按配置 ID 取缓存的模型，没有时创建
+func (am *agentModels) get(ctx context.Context, cfg *models.AIConfig) (model.LLM, error) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if llm, ok := am.llms[cfg.ID]; ok {
+		return llm, nil
+	}
+	llm, err := am.s.modelFactory.CreateModel(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	am.llms[cfg.ID] = llm
+	return llm, nil
+}
+
+// modelLabel 进度事件中展示的模型名称：配置名（模型名）
+func modelLabel(cfg *models.AIConfig) string {
+	if cfg == nil {
+		return ""
+	}
+	if cfg.Name == "" || cfg.Name == cfg.ModelName {
+		return cfg.ModelName
+	}
+	return cfg.Name + "（" + cfg.ModelName + "）"
+}
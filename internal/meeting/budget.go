@@ -0,0 +1,61 @@
+package meeting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// ProgressTypeBudgetExhausted 发言预算耗尽，跳过剩余专家直接进入总结
+const ProgressTypeBudgetExhausted = "budget_exhausted"
+
+// SetMeetingBudget 设置智能会议的专家发言次数与轮数上限
+func (s *Service) SetMeetingBudget(budget models.MeetingBudget) {
+	s.budget = budget
+}
+
+// applyTurnBudget 按发言次数上限截取本轮专家（按主持人选择顺序保留），超出部分通过进度事件告知
+// 会议开始时统一截取，预算耗尽后照常由主持人总结，而不是在专家轮中途停止
+func (s *Service) applyTurnBudget(agents []models.AgentConfig, progressCallback ProgressCallback) []models.AgentConfig {
+	limit := s.budget.MaxAgentTurns
+	if limit <= 0 || len(agents) <= limit {
+		return agents
+	}
+
+	skipped := make([]string, 0, len(agents)-limit)
+	for _, cfg := range agents[limit:] {
+		skipped = append(skipped, cfg.Name)
+	}
+	log.Info("meeting budget exhausted, skip agents: %v", skipped)
+	emitProgress(progressCallback, ProgressEvent{
+		Type: ProgressTypeBudgetExhausted, AgentID: "moderator", AgentName: "小韭菜",
+		Detail: fmt.Sprintf("已达本场发言上限 %d 次，跳过：%s", limit, strings.Join(skipped, "、")),
+	})
+	return agents[:limit]
+}
+
+// roundLimit 本场专家发言轮数上限：策略配置优先，未配置时使用全局会议预算，均为 0 时由 allowRound 取 DefaultMaxRounds
+func (s *Service) roundLimit(strategyRounds int) int {
+	if strategyRounds > 0 {
+		return strategyRounds
+	}
+	return s.budget.MaxRounds
+}
+
+// allowRound 判断是否还能进行第 round 轮专家发言，maxRounds 为 0 时使用 DefaultMaxRounds
+// 超出上限时通过进度事件告知，由调用方直接进入总结
+func allowRound(maxRounds, round int, progressCallback ProgressCallback) bool {
+	if maxRounds <= 0 {
+		maxRounds = DefaultMaxRounds
+	}
+	if round <= maxRounds {
+		return true
+	}
+	log.Info("meeting round limit %d reached, skip round %d", maxRounds, round)
+	emitProgress(progressCallback, ProgressEvent{
+		Type: ProgressTypeBudgetExhausted, AgentID: "moderator", AgentName: "小韭菜",
+		Detail: fmt.Sprintf("已达本场发言轮数上限 %d 轮，直接总结", maxRounds),
+	})
+	return false
+}
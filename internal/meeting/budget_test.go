@@ -0,0 +1,56 @@
+package meeting
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestRunSmartMeeting_TurnBudget(t *testing.T) {
+	srv, _ := parallelMockServer(t, nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+
+	for _, parallel := range []bool{false, true} {
+		svc := NewServiceFull(nil, nil)
+		svc.SetMeetingBudget(models.MeetingBudget{MaxAgentTurns: 2})
+		req := parallelRequest()
+		req.ParallelRounds = parallel
+
+		var exhausted []ProgressEvent
+		responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, func(e ProgressEvent) {
+			if e.Type == ProgressTypeBudgetExhausted {
+				exhausted = append(exhausted, e)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range responses {
+			if r.Error != "" {
+				t.Fatalf("agent %s failed: %s", r.AgentID, r.Error)
+			}
+			ids = append(ids, r.AgentID+":"+r.MsgType)
+		}
+		// 预算耗尽后跳过剩余专家，仍由主持人总结
+		want := "moderator:opening,a1:opinion,a2:opinion,moderator:summary"
+		if got := strings.Join(ids, ","); got != want {
+			t.Fatalf("parallel=%v responses = %s, want %s", parallel, got, want)
+		}
+		if len(exhausted) != 1 || !strings.Contains(exhausted[0].Detail, "专家a3、专家a4") {
+			t.Fatalf("parallel=%v budget events = %+v", parallel, exhausted)
+		}
+	}
+
+	// 默认不限制
+	svc := NewServiceFull(nil, nil)
+	responses, err := svc.RunSmartMeeting(context.Background(), aiConfig, parallelRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 6 {
+		t.Fatalf("默认应全部发言: %d", len(responses))
+	}
+}
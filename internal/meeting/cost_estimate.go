@@ -0,0 +1,290 @@
+package meeting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 会前估算的经验值，估算结果的 Assumptions 中逐条说明
+const (
+	estimateBytesPerToken    = 4    // 按 UTF-8 字节数估算 token，与会话统计一致（中文约 0.75 token/字）
+	estimateMessageTokens    = 4    // 每条消息的角色与模板开销
+	estimateImageTokens      = 1000 // 每张图片
+	estimateDecisionRunes    = 80   // 小韭菜决策中的意图、议题与开场白
+	estimateTaskRunes        = 40   // 小韭菜为每位专家拆解的任务
+	estimateReplyRunes       = 200  // 专家首轮发言（提示词要求 150 字以内，实际常略超）
+	estimateRebuttalRunes    = 300  // 辩论反驳
+	estimateSummaryRunes     = 300  // 小韭菜总结
+	estimateVerdictRunes     = 400  // 辩论裁决
+	estimatePickRunes        = 40   // 小韭菜挑选辩论双方
+	estimateToolCalls        = 2    // 配置了工具的专家平均调用工具次数
+	estimateMaxToolCalls     = 4    // 费用区间上限按此次数估算
+	estimateToolCallTokens   = 30   // 单次工具调用的参数
+	estimateToolResultTokens = 800  // 单次工具返回结果
+	estimateMCPToolBytes     = 400  // 单个 MCP 工具声明
+	estimateErrorMargin      = 0.3  // 费用区间额外计入的估算误差
+)
+
+// AgentCostEstimate 单个发言者的预计消耗，小韭菜的意图分析、辩论选人与总结合并为一项
+type AgentCostEstimate struct {
+	AgentID          string  `json:"agentId"`
+	AgentName        string  `json:"agentName"`
+	Model            string  `json:"model"`
+	Requests         int     `json:"requests"` // 预计 LLM 请求次数，含工具调用后的再次请求
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// MeetingCostEstimate 会前估算的 token 用量与费用，只按提示词拼装规则计算，不调用模型
+type MeetingCostEstimate struct {
+	Mode             string              `json:"mode"`   // smart/debate/direct
+	Agents           []AgentCostEstimate `json:"agents"` // 按首次发言顺序
+	PromptTokens     int64               `json:"promptTokens"`
+	CompletionTokens int64               `json:"completionTokens"`
+	TotalTokens      int64               `json:"totalTokens"`
+	Cost             float64             `json:"cost"` // 按各模型 AIConfig 中的千 token 单价计算，未配置单价时为 0
+	MinTokens        int64               `json:"minTokens"`
+	MaxTokens        int64               `json:"maxTokens"`
+	MinCost          float64             `json:"minCost"` // 不调用工具并计入误差的下限
+	MaxCost          float64             `json:"maxCost"` // 多次调用工具并计入误差的上限
+	Assumptions      []string            `json:"assumptions"`
+}
+
+// EstimateMeetingCost 估算本次提问的 token 用量与费用，按 req 的选人、可见性、并行与辩论设置模拟发言顺序
+// 指定了专家（req.Agents）时按直接提问估算，否则按智能会议估算并假设小韭菜选中全部候选专家
+func (s *Service) EstimateMeetingCost(aiConfig *models.AIConfig, req ChatRequest) (*MeetingCostEstimate, error) {
+	if aiConfig == nil {
+		return nil, ErrNoAIConfig
+	}
+	if len(req.Agents) == 0 && len(req.AllAgents) == 0 {
+		return nil, ErrNoAgents
+	}
+
+	est := s.estimateMeeting(aiConfig, req, estimateToolCalls)
+	low := s.estimateMeeting(aiConfig, req, 0)
+	high := s.estimateMeeting(aiConfig, req, estimateMaxToolCalls)
+	est.MinTokens = int64(float64(low.TotalTokens) * (1 - estimateErrorMargin))
+	est.MaxTokens = int64(float64(high.TotalTokens) * (1 + estimateErrorMargin))
+	est.MinCost = low.Cost * (1 - estimateErrorMargin)
+	est.MaxCost = high.Cost * (1 + estimateErrorMargin)
+	est.Assumptions = append(est.Assumptions,
+		fmt.Sprintf("配置了工具的专家平均调用 %d 次工具（区间按 0~%d 次），每次结果约 %d token", estimateToolCalls, estimateMaxToolCalls, estimateToolResultTokens),
+		fmt.Sprintf("按 UTF-8 字节数 / %d 估算 token（中文约 0.75 token/字），区间另计 ±%d%% 误差", estimateBytesPerToken, int(estimateErrorMargin*100)),
+		"未计入会后记忆摘要、快讯情绪分类等后台调用",
+	)
+	if est.Cost == 0 {
+		est.Assumptions = append(est.Assumptions, "模型未配置千 token 单价，费用按 0 计")
+	}
+	return est, nil
+}
+
+// estimateMeeting 按 toolCalls 次工具调用估算一场会议
+func (s *Service) estimateMeeting(aiConfig *models.AIConfig, req ChatRequest, toolCalls int) *MeetingCostEstimate {
+	e := &costEstimator{
+		s: s, aiConfig: aiConfig, req: req, toolCalls: toolCalls,
+		est:   &MeetingCostEstimate{Agents: []AgentCostEstimate{}, Assumptions: []string{}},
+		index: make(map[string]int),
+	}
+	if len(req.Agents) > 0 {
+		e.direct()
+	} else {
+		e.smart()
+	}
+	for _, a := range e.est.Agents {
+		e.est.PromptTokens += a.PromptTokens
+		e.est.CompletionTokens += a.CompletionTokens
+		e.est.Cost += a.Cost
+	}
+	e.est.TotalTokens = e.est.PromptTokens + e.est.CompletionTokens
+	return e.est
+}
+
+// costEstimator 按会议流程依次累加各次请求的估算用量
+type costEstimator struct {
+	s         *Service
+	aiConfig  *models.AIConfig
+	req       ChatRequest
+	toolCalls int
+	est       *MeetingCostEstimate
+	index     map[string]int // 发言者 ID -> Agents 下标
+}
+
+// direct 直接提问：被 @ 的专家并行回答原问题，不经小韭菜
+func (e *costEstimator) direct() {
+	e.est.Mode = MeetingModeDirect
+	for i := range e.req.Agents {
+		e.agentTurn(&e.req.Agents[i], e.req.Query, e.req.ReplyContent, len(e.req.Images), estimateReplyRunes)
+	}
+	e.note("直接提问 %d 位专家，各自独立回答，约 %d 字", len(e.req.Agents), estimateReplyRunes)
+}
+
+// smart 智能会议：小韭菜分析 → 专家首轮 → 总结，辩论模式以反驳与裁决代替总结
+func (e *costEstimator) smart() {
+	req := e.req
+	e.est.Mode = MeetingModeSmart
+	moderator := NewModerator(nil)
+	moderator.SetVisibility(e.s.resolveVisibility(req))
+
+	agents := req.AllAgents
+	if limit := e.s.budget.MaxAgentTurns; limit > 0 && len(agents) > limit {
+		agents = agents[:limit]
+		e.note("受发言上限限制，%d 位候选专家中只有前 %d 位发言", len(req.AllAgents), limit)
+	} else {
+		e.note("小韭菜选中全部 %d 位候选专家", len(agents))
+	}
+	e.moderatorTurn(moderator.buildAnalyzePrompt(&req.Stock, imageHint(req.Query, len(req.Images)), req.AllAgents),
+		estimateDecisionRunes+len(agents)*estimateTaskRunes)
+
+	memoryContext := e.memoryContext()
+	if memoryContext != "" {
+		e.note("每位专家注入历史记忆约 %d token", estimateTokens(memoryContext))
+	}
+
+	parallel := req.ParallelRounds || req.ConcurrentMode
+	if parallel {
+		e.note("首轮专家并行发言，互不参考")
+	} else {
+		e.note("首轮专家串行发言：%s", visibilityLabel(moderator.visibility))
+	}
+	e.note("专家任务约 %d 字，首轮发言约 %d 字", estimateTaskRunes, estimateReplyRunes)
+
+	task := placeholderText(estimateTaskRunes)
+	var history []DiscussionEntry
+	for i := range agents {
+		cfg := &agents[i]
+		previousContext := memoryContext
+		if !parallel {
+			previousContext = buildPeerContext(history, moderator.visibility)
+			if memoryContext != "" {
+				previousContext = memoryContext + "\n" + previousContext
+			}
+		}
+		e.agentTurn(cfg, task, previousContext, len(req.Images), estimateReplyRunes)
+		history = append(history, DiscussionEntry{
+			Round: 1, AgentID: cfg.ID, AgentName: cfg.Name, Role: cfg.Role, Content: placeholderText(estimateReplyRunes),
+		})
+	}
+
+	maxRounds := e.s.roundLimit(req.MaxRounds)
+	if maxRounds <= 0 {
+		maxRounds = DefaultMaxRounds
+	}
+	if req.Mode != MeetingModeDebate || len(history) < 2 || RoundRebuttal > maxRounds {
+		e.moderatorTurn(moderator.buildSummarizePrompt(&req.Stock, req.Query, history), estimateSummaryRunes)
+		e.note("小韭菜总结约 %d 字", estimateSummaryRunes)
+		return
+	}
+
+	// 辩论：无法预知分歧，按前两位专家互相反驳估算
+	e.est.Mode = MeetingModeDebate
+	e.moderatorTurn(moderator.buildDebatePickPrompt(&req.Stock, req.Query, history), estimatePickRunes)
+	issue := placeholderText(estimatePickRunes)
+	pair := []DiscussionEntry{history[0], history[1]}
+	var lastRebuttal string
+	for i, self := range pair {
+		opponent := pair[1-i]
+		e.agentTurn(&agents[i], buildRebuttalPrompt(issue, self, opponent, lastRebuttal), memoryContext, 0, estimateRebuttalRunes)
+		lastRebuttal = placeholderText(estimateRebuttalRunes)
+		history = append(history, DiscussionEntry{
+			Round: RoundRebuttal, AgentID: self.AgentID, AgentName: self.AgentName,
+			Role: self.Role, Content: lastRebuttal, Target: opponent.AgentName,
+		})
+	}
+	e.moderatorTurn(moderator.buildVerdictPrompt(&req.Stock, req.Query, issue, history), estimateVerdictRunes)
+	e.note("辩论由前两位专家互相反驳，反驳约 %d 字，裁决约 %d 字", estimateRebuttalRunes, estimateVerdictRunes)
+}
+
+// memoryContext 会议将注入的历史记忆，只读取不保存
+func (e *costEstimator) memoryContext() string {
+	if e.s.memoryManager == nil {
+		return ""
+	}
+	mem, err := e.s.memoryManager.GetOrCreate(e.req.Stock.Symbol, e.req.Stock.Name)
+	if err != nil || mem == nil {
+		return ""
+	}
+	return e.s.memoryManager.BuildContext(mem, e.req.Query)
+}
+
+// agentTurn 估算专家一次发言：系统指令、用户消息与工具声明随每次请求发送，工具结果累加到之后的请求
+func (e *costEstimator) agentTurn(cfg *models.AgentConfig, query, replyContent string, images, replyRunes int) {
+	aiConfig := e.s.resolveAgentAIConfig(cfg, e.aiConfig)
+	builder := e.s.createBuilder(context.Background(), nil, aiConfig)
+	preview := builder.PreviewPrompt(cfg, &e.req.Stock, query, replyContent, e.req.Position)
+
+	toolBytes := preview.ToolSchemaBytes + preview.MCPToolCount*estimateMCPToolBytes
+	base := estimateTokens(preview.Instruction) + estimateTokens(query) + int64(toolBytes/estimateBytesPerToken) +
+		2*estimateMessageTokens + int64(images*estimateImageTokens)
+	calls := 0
+	if toolBytes > 0 {
+		calls = e.toolCalls
+	}
+	perCall := int64(estimateToolCallTokens + estimateToolResultTokens + 2*estimateMessageTokens)
+
+	var prompt int64
+	for i := 0; i <= calls; i++ {
+		prompt += base + int64(i)*perCall
+	}
+	completion := estimateTokens(placeholderText(replyRunes))
+	if limit := replyTokenLimit(cfg, aiConfig); limit > 0 && completion > limit {
+		completion = limit
+	}
+	completion += int64(calls * estimateToolCallTokens)
+	e.add(cfg.ID, cfg.Name, aiConfig, calls+1, prompt, completion)
+}
+
+// moderatorTurn 估算小韭菜的一次单轮 Prompt 调用
+func (e *costEstimator) moderatorTurn(prompt string, replyRunes int) {
+	aiConfig := e.s.moderatorAIConfig
+	if aiConfig == nil {
+		aiConfig = e.aiConfig
+	}
+	e.add("moderator", "小韭菜", aiConfig, 1,
+		estimateTokens(prompt)+estimateMessageTokens, estimateTokens(placeholderText(replyRunes)))
+}
+
+// add 累加到发言者名下
+func (e *costEstimator) add(id, name string, aiConfig *models.AIConfig, requests int, prompt, completion int64) {
+	i, ok := e.index[id]
+	if !ok {
+		i = len(e.est.Agents)
+		e.index[id] = i
+		e.est.Agents = append(e.est.Agents, AgentCostEstimate{AgentID: id, AgentName: name, Model: modelLabel(aiConfig)})
+	}
+	a := &e.est.Agents[i]
+	a.Requests += requests
+	a.PromptTokens += prompt
+	a.CompletionTokens += completion
+	a.Cost += estimateCost(aiConfig, prompt, completion)
+}
+
+// note 记录一条估算假设
+func (e *costEstimator) note(format string, args ...any) {
+	e.est.Assumptions = append(e.est.Assumptions, fmt.Sprintf(format, args...))
+}
+
+// replyTokenLimit 专家单次发言的输出上限，与 BuildAgentWithContext 一致：专家预算只收紧 AI 配置的上限
+func replyTokenLimit(cfg *models.AgentConfig, aiConfig *models.AIConfig) int64 {
+	limit := 0
+	if aiConfig != nil {
+		limit = aiConfig.MaxTokens
+	}
+	if cfg.MaxTokensPerAgent > 0 && (limit == 0 || cfg.MaxTokensPerAgent < limit) {
+		limit = cfg.MaxTokensPerAgent
+	}
+	return int64(limit)
+}
+
+// estimateTokens 按 UTF-8 字节数估算 token 数
+func estimateTokens(text string) int64 {
+	return int64(len(text) / estimateBytesPerToken)
+}
+
+// placeholderText 代替尚未产生的发言，按中文字数占位
+func placeholderText(runes int) string {
+	return strings.Repeat("字", runes)
+}
@@ -0,0 +1,302 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode"
+
+	"github.com/run-bigpig/jcp/internal/adk/mcp"
+	"github.com/run-bigpig/jcp/internal/adk/tools"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// mockTokens 模拟供应商分词，刻意与估算使用的 UTF-8 字节数 / 4 不同：
+// 汉字与全角标点 1 token/字（估算按 0.75），其余按 3 字节 1 token
+func mockTokens(text string) int {
+	var cjk, other int
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef) {
+			cjk++
+		} else {
+			other += len(string(r))
+		}
+	}
+	return cjk + other/3
+}
+
+// mockReply 截取指定字数的模拟发言
+func mockReply(runes int) string {
+	const sentence = "贵州茅台近期放量突破前高，MACD 金叉，短期支撑 1480 元、压力 1560 元，估值处于历史中枢，批价企稳，建议逢回调分批布局并控制仓位。"
+	text := []rune(strings.Repeat(sentence, runes/len([]rune(sentence))+1))
+	return string(text[:runes])
+}
+
+// usageRecorder 记录模拟供应商返回的累计用量
+type usageRecorder struct {
+	mu                 sync.Mutex
+	requests           int
+	toolResults        int // 专家请求中最终带回的工具结果条数
+	prompt, completion int64
+}
+
+func (u *usageRecorder) total() int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.prompt + u.completion
+}
+
+// costMockServer 模拟 Ollama 并按 mockTokens 返回用量：主持人选中全部专家，发言长度接近真实会议
+// 带工具的专家先调用 toolCalls 次 toolName 再发言
+func costMockServer(t *testing.T, agentIDs []string, toolName string, toolCalls int) (*httptest.Server, *usageRecorder) {
+	rec := &usageRecorder{}
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		prompt, called := 0, 0
+		for _, m := range q.Messages {
+			prompt += mockTokens(m.Content) + 4
+			for _, call := range m.ToolCalls {
+				prompt += mockTokens(string(call))
+			}
+			if m.Role == "tool" {
+				called++
+			}
+		}
+		for _, tool := range q.Tools {
+			prompt += mockTokens(string(tool))
+		}
+		body := q.Text()
+
+		var reply string
+		var toolCall []map[string]any
+		switch {
+		case strings.Contains(body, "负责组织专家讨论"):
+			tasks := make(map[string]string, len(agentIDs))
+			for _, id := range agentIDs {
+				tasks[id] = "结合近期量价与估值分析贵州茅台短期走势，给出支撑位、压力位和操作建议"
+			}
+			data, _ := json.Marshal(map[string]any{
+				"intent": "判断后市走势", "selected": agentIDs, "tasks": tasks,
+				"topic": "贵州茅台后市研判", "opening": "各位专家好，今天围绕贵州茅台近期走势和估值展开讨论，请从各自角度给出判断。",
+			})
+			reply = string(data)
+		case strings.Contains(body, "找出观点分歧最大"):
+			reply = `{"pair":["` + agentIDs[0] + `","` + agentIDs[1] + `"],"issue":"估值是否已充分反映业绩放缓"}`
+		case strings.Contains(body, "请作为裁判"):
+			reply = mockReply(380)
+		case strings.Contains(body, "请总结讨论"):
+			reply = mockReply(300)
+		case strings.Contains(body, "## 辩论环节"):
+			reply = mockReply(280)
+		case len(q.Tools) > 0 && called < toolCalls:
+			toolCall = []map[string]any{{"function": map[string]any{
+				"name": toolName, "arguments": map[string]any{"code": "sh600519", "days": 30},
+			}}}
+		default:
+			reply = mockReply(190)
+			rec.mu.Lock()
+			rec.toolResults += called
+			rec.mu.Unlock()
+		}
+
+		completion := mockTokens(reply)
+		if toolCall != nil {
+			data, _ := json.Marshal(toolCall)
+			completion = mockTokens(string(data))
+		}
+		rec.mu.Lock()
+		rec.requests++
+		rec.prompt += int64(prompt)
+		rec.completion += int64(completion)
+		rec.mu.Unlock()
+		return ollamaReply{Content: reply, ToolCalls: toolCall, PromptEval: prompt, Eval: completion}
+	})
+	return srv, rec
+}
+
+func costRequest(toolNames []string) ChatRequest {
+	experts := []struct{ id, name, role, instruction string }{
+		{"tech", "技术派", "技术分析师", "你是一位资深技术分析师，擅长通过K线形态、均线系统、MACD、KDJ 等指标判断趋势与买卖点。分析时先说明趋势方向，再给出关键支撑位与压力位，最后给出操作建议与止损位。"},
+		{"value", "价值派", "基本面分析师", "你是一位专注基本面的价值投资者，重视公司护城河、盈利质量、现金流与估值水平。分析时结合行业景气度与公司财务数据，判断当前价格是否具备安全边际。"},
+		{"risk", "风控官", "风险控制专家", "你是一位风险控制专家，关注仓位管理、回撤控制与黑天鹅事件。请指出当前最主要的风险点，并给出仓位与止损方面的具体建议。"},
+	}
+	var agents []models.AgentConfig
+	for _, e := range experts {
+		agents = append(agents, models.AgentConfig{ID: e.id, Name: e.name, Role: e.role, Instruction: e.instruction, Tools: toolNames, Enabled: true})
+	}
+	return ChatRequest{
+		StockCode: "sh600519",
+		Stock:     models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500, ChangePercent: 1.2},
+		Query:     "茅台最近放量上涨，后市怎么看？现在适合加仓吗？",
+		AllAgents: agents,
+		Position:  &models.StockPosition{Shares: 100, CostPrice: 1420},
+	}
+}
+
+// mockMCPManager 模拟外部 MCP 服务：一个返回约 estimateToolResultTokens 字行情摘要的工具
+func mockMCPManager(t *testing.T) *mcp.Manager {
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "quotes", Version: "1.0.0"}, nil)
+	server.AddTool(&sdkmcp.Tool{
+		Name:        "stock_brief",
+		Description: "获取个股近期行情、资金流向与公告摘要",
+		InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{
+			"code": {Type: "string", Description: "股票代码"},
+			"days": {Type: "integer", Description: "回看天数"},
+		}},
+	}, func(context.Context, *sdkmcp.CallToolRequest) (*sdkmcp.CallToolResult, error) {
+		return &sdkmcp.CallToolResult{Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: mockReply(estimateToolResultTokens)}}}, nil
+	})
+	srv := httptest.NewServer(sdkmcp.NewStreamableHTTPHandler(func(*http.Request) *sdkmcp.Server { return server }, nil))
+	t.Cleanup(func() {
+		srv.CloseClientConnections()
+		srv.Close()
+	})
+
+	m := mcp.NewManager()
+	m.LoadConfigs([]models.MCPServerConfig{{ID: "quotes", Name: "行情", Endpoint: srv.URL, Enabled: true}})
+	if err := m.RefreshToolCatalog("quotes"); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestEstimateMeetingCost_MatchesMockRuns(t *testing.T) {
+	registry := tools.NewRegistry(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	cases := []struct {
+		name  string
+		mcp   bool // 专家配置外部工具，模拟供应商按估算假设的次数调用
+		setup func(*ChatRequest)
+	}{
+		{"sequential", false, func(req *ChatRequest) {}},
+		{"independent", false, func(req *ChatRequest) { req.Visibility = models.MeetingVisibilityIndependent }},
+		{"parallel", false, func(req *ChatRequest) { req.ParallelRounds = true }},
+		{"debate", false, func(req *ChatRequest) {
+			req.Mode = MeetingModeDebate
+			req.Visibility = models.MeetingVisibilityDebate
+		}},
+		{"direct", false, func(req *ChatRequest) {
+			req.Agents = req.AllAgents[:2]
+			req.ReplyContent = mockReply(150)
+		}},
+		{"tool calls", true, func(req *ChatRequest) {}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := costRequest(nil)
+			var mcpMgr *mcp.Manager
+			if tc.mcp {
+				mcpMgr = mockMCPManager(t)
+				for i := range req.AllAgents {
+					req.AllAgents[i].MCPServers = []string{"quotes"}
+				}
+			}
+			tc.setup(&req)
+			ids := make([]string, 0, len(req.AllAgents))
+			for _, a := range req.AllAgents {
+				ids = append(ids, a.ID)
+			}
+			srv, rec := costMockServer(t, ids, "stock_brief", estimateToolCalls)
+			svc := NewServiceFull(registry, mcpMgr)
+			aiConfig := &models.AIConfig{
+				ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock",
+				InputPricePer1K: 0.002, OutputPricePer1K: 0.008,
+			}
+
+			// 与界面展示一致，核对估算的总量与费用
+			est, err := svc.EstimateMeetingCost(aiConfig, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec.requests != 0 {
+				t.Fatalf("estimate sent %d requests", rec.requests)
+			}
+
+			if len(req.Agents) > 0 {
+				_, err = svc.SendMessage(context.Background(), aiConfig, req)
+			} else {
+				_, err = svc.RunSmartMeeting(context.Background(), aiConfig, req)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var estAgents int64
+			for _, a := range est.Agents {
+				if a.AgentID != "moderator" {
+					estAgents += a.PromptTokens + a.CompletionTokens
+				}
+			}
+			usage := svc.GetMeetingUsage(req.Stock.Symbol)
+			checkWithin(t, "total", est.TotalTokens, rec.total())
+			checkWithin(t, "agents", estAgents, usage.TotalTokens)
+
+			cost := (float64(rec.prompt)*aiConfig.InputPricePer1K + float64(rec.completion)*aiConfig.OutputPricePer1K) / 1000
+			t.Logf("cost: estimated %.4f (%.4f~%.4f), actual %.4f", est.Cost, est.MinCost, est.MaxCost, cost)
+			if math.Abs(est.Cost/cost-1) > 0.3 || cost < est.MinCost || cost > est.MaxCost {
+				t.Errorf("cost: estimated %.4f (%.4f~%.4f), actual %.4f", est.Cost, est.MinCost, est.MaxCost, cost)
+			}
+			if actual := rec.total(); actual < est.MinTokens || actual > est.MaxTokens {
+				t.Errorf("tokens %d outside %d~%d", actual, est.MinTokens, est.MaxTokens)
+			}
+			if tc.mcp && rec.toolResults != len(ids)*estimateToolCalls {
+				t.Errorf("tool results = %d, want %d", rec.toolResults, len(ids)*estimateToolCalls)
+			}
+		})
+	}
+}
+
+// checkWithin 估算与实际用量的偏差不超过 30%
+func checkWithin(t *testing.T, label string, estimated, actual int64) {
+	t.Helper()
+	if actual == 0 {
+		t.Fatalf("%s: no actual usage recorded", label)
+	}
+	ratio := float64(estimated) / float64(actual)
+	t.Logf("%s: estimated %d, actual %d (%.2f)", label, estimated, actual, ratio)
+	if math.Abs(ratio-1) > 0.3 {
+		t.Errorf("%s: estimated %d tokens, actual %d (ratio %.2f)", label, estimated, actual, ratio)
+	}
+}
+
+func TestEstimateMeetingCost_Range(t *testing.T) {
+	registry := tools.NewRegistry(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	svc := NewServiceFull(registry, nil)
+	if _, err := svc.EstimateMeetingCost(nil, costRequest(nil)); err != ErrNoAIConfig {
+		t.Errorf("nil config err = %v", err)
+	}
+	aiConfig := &models.AIConfig{ID: "mock", ModelName: "mock", InputPricePer1K: 0.002, OutputPricePer1K: 0.008}
+	if _, err := svc.EstimateMeetingCost(aiConfig, ChatRequest{}); err != ErrNoAgents {
+		t.Errorf("no agents err = %v", err)
+	}
+
+	plain, err := svc.EstimateMeetingCost(aiConfig, costRequest(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	withTools, err := svc.EstimateMeetingCost(aiConfig, costRequest([]string{"get_kline_data", "get_news"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, est := range []*MeetingCostEstimate{plain, withTools} {
+		if !(est.MinCost < est.Cost && est.Cost < est.MaxCost) || !(est.MinTokens < est.TotalTokens && est.TotalTokens < est.MaxTokens) {
+			t.Errorf("range = %d~%d tokens, %.4f~%.4f cost, point %d / %.4f", est.MinTokens, est.MaxTokens, est.MinCost, est.MaxCost, est.TotalTokens, est.Cost)
+		}
+		if len(est.Assumptions) == 0 {
+			t.Error("missing assumptions")
+		}
+	}
+	// 工具描述随每次请求发送，且按平均调用次数多出若干轮请求
+	if withTools.PromptTokens <= plain.PromptTokens || withTools.Agents[1].Requests != 1+estimateToolCalls {
+		t.Errorf("tools: %d vs %d prompt tokens, agent requests %d", withTools.PromptTokens, plain.PromptTokens, withTools.Agents[1].Requests)
+	}
+	if names := []string{plain.Agents[0].AgentID, plain.Agents[1].AgentID, plain.Agents[3].AgentID}; strings.Join(names, ",") != "moderator,tech,risk" {
+		t.Errorf("agents = %v", names)
+	}
+}
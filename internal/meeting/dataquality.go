@@ -0,0 +1,25 @@
+package meeting
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProgressTypeDataSuspect 行情数据异常、会议未开始时推送的事件，Detail 为异常原因
+const ProgressTypeDataSuspect = "data_suspect"
+
+// ErrDataSuspect 标的行情未通过合理性校验，需用户确认后（AllowSuspectData）才开始会议
+var ErrDataSuspect = errors.New("行情数据异常，请稍后重试")
+
+// checkStockData 行情异常且用户未确认时拒绝开始会议
+// 用户确认后照常进行，由专家指令中的数据质量警告提醒专家核实
+func checkStockData(req ChatRequest, progressCallback ProgressCallback) error {
+	if !req.Stock.DataSuspect || req.AllowSuspectData {
+		return nil
+	}
+	log.Warn("stock data suspect for %s: %s", req.Stock.Symbol, req.Stock.SuspectReason)
+	emitProgress(progressCallback, ProgressEvent{
+		Type: ProgressTypeDataSuspect, AgentID: "moderator", AgentName: "小韭菜", Detail: req.Stock.SuspectReason,
+	})
+	return fmt.Errorf("%w: %s", ErrDataSuspect, req.Stock.SuspectReason)
+}
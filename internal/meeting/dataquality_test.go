@@ -0,0 +1,102 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+func TestSendMessage_DataSuspect(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		instructions []string
+	)
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		mu.Lock()
+		instructions = append(instructions, q.First())
+		mu.Unlock()
+		return ollamaReply{Content: "观点"}
+	})
+
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := ChatRequest{
+		Stock:  models.Stock{Symbol: "sh600519", Name: "贵州茅台", DataSuspect: true, SuspectReason: "现价为 0（数据源异常或停牌）"},
+		Query:  "后市怎么看",
+		Agents: []models.AgentConfig{{ID: "tech", Name: "技术分析师", Instruction: "技术指令"}},
+	}
+
+	var events []ProgressEvent
+	_, err := svc.SendMessageWithCallback(context.Background(), aiConfig, req, func(e ProgressEvent) {
+		events = append(events, e)
+	})
+	if !errors.Is(err, ErrDataSuspect) {
+		t.Fatalf("err = %v, want ErrDataSuspect", err)
+	}
+	if len(events) != 1 || events[0].Type != ProgressTypeDataSuspect || events[0].Detail != req.Stock.SuspectReason {
+		t.Fatalf("events = %+v", events)
+	}
+	if len(instructions) != 0 {
+		t.Fatalf("拒绝开会时不应请求模型: %d", len(instructions))
+	}
+	if _, err := svc.RunSmartMeeting(context.Background(), aiConfig, ChatRequest{Stock: req.Stock, AllAgents: req.Agents}); !errors.Is(err, ErrDataSuspect) {
+		t.Fatalf("smart err = %v, want ErrDataSuspect", err)
+	}
+
+	// 用户确认后照常开会，专家指令带数据质量警告
+	req.AllowSuspectData = true
+	responses, err := svc.SendMessage(context.Background(), aiConfig, req)
+	if err != nil || len(responses) != 1 || responses[0].Error != "" {
+		t.Fatalf("responses = %+v, err = %v", responses, err)
+	}
+	if len(instructions) != 1 || !strings.Contains(instructions[0], "数据质量警告") || !strings.Contains(instructions[0], "现价为 0") {
+		t.Fatalf("instructions = %q", instructions)
+	}
+}
+
+func TestRunSmartMeeting_PreOpenQuote(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		instructions []string
+	)
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		switch {
+		case q.Analyze():
+			return ollamaReply{Content: `{"intent":"盘前简报","selected":["tech"],"tasks":{},"topic":"盘前简报","opening":"开始"}`}
+		case q.Summarize():
+			return ollamaReply{Content: "总结"}
+		}
+		mu.Lock()
+		instructions = append(instructions, q.First())
+		mu.Unlock()
+		return ollamaReply{Content: "观点"}
+	})
+
+	// 9:15 定时盘前简报：新浪现价为 0，仅有昨收
+	stock := models.Stock{Symbol: "sh600519", Name: "贵州茅台", PreClose: 1480, Change: -1480, ChangePercent: -100}
+	services.ValidateQuoteAt(&stock, time.Date(2025, 3, 7, 9, 15, 0, 0, time.FixedZone("CST", 8*60*60)))
+
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := ChatRequest{
+		Stock:     stock,
+		Query:     "请做一份盘前简报",
+		AllAgents: []models.AgentConfig{{ID: "tech", Name: "技术分析师", Instruction: "技术指令", Enabled: true}},
+	}
+	responses, err := svc.RunSmartMeeting(context.Background(), aiConfig, req)
+	if err != nil {
+		t.Fatalf("盘前行情不应拒绝开会: %v", err)
+	}
+	if len(responses) == 0 || len(instructions) != 1 {
+		t.Fatalf("responses = %d, instructions = %d", len(responses), len(instructions))
+	}
+	if !strings.Contains(instructions[0], "当前价格: 1480.00") || !strings.Contains(instructions[0], "尚未开盘") || strings.Contains(instructions[0], "数据质量警告") {
+		t.Fatalf("instruction = %q", instructions[0])
+	}
+}
@@ -0,0 +1,94 @@
+package meeting
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// extendableCtx 可延长截止时间的超时 context，行为同 context.WithTimeout
+// 长耗时工具调用时通过 extendDeadline 延长专家及会议的超时
+type extendableCtx struct {
+	context.Context // 父 context，提供 Value
+
+	mu       sync.Mutex
+	deadline time.Time
+	timer    *time.Timer
+	done     chan struct{}
+	err      error
+	stop     func() bool // 停止监听父 context
+}
+
+type extendableCtxKey struct{}
+
+// withExtendableTimeout 同 context.WithTimeout，返回的 context 可通过 extendDeadline 延长
+func withExtendableTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	c := &extendableCtx{Context: parent, deadline: time.Now().Add(d), done: make(chan struct{})}
+	// 持锁注册，避免回调在字段赋值前执行
+	c.mu.Lock()
+	c.timer = time.AfterFunc(d, func() { c.cancel(context.DeadlineExceeded) })
+	c.stop = context.AfterFunc(parent, func() { c.cancel(parent.Err()) })
+	c.mu.Unlock()
+	return c, func() { c.cancel(context.Canceled) }
+}
+
+func (c *extendableCtx) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+	c.timer.Stop()
+	c.stop()
+}
+
+func (c *extendableCtx) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	deadline := c.deadline
+	c.mu.Unlock()
+	if parent, ok := c.Context.Deadline(); ok && parent.Before(deadline) {
+		return parent, true
+	}
+	return deadline, true
+}
+
+func (c *extendableCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *extendableCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *extendableCtx) Value(key any) any {
+	if key == (extendableCtxKey{}) {
+		return c
+	}
+	return c.Context.Value(key)
+}
+
+// extend 将截止时间推迟到至少 d 之后，并同样延长外层的可延长 context
+func (c *extendableCtx) extend(d time.Duration) {
+	c.mu.Lock()
+	if c.err == nil {
+		if deadline := time.Now().Add(d); deadline.After(c.deadline) {
+			c.deadline = deadline
+			c.timer.Reset(d)
+		}
+	}
+	c.mu.Unlock()
+	if parent, ok := c.Context.Value(extendableCtxKey{}).(*extendableCtx); ok {
+		parent.extend(d)
+	}
+}
+
+// extendDeadline 延长 ctx 链上所有可延长超时，使剩余时间至少为 d；没有可延长超时时不做处理
+func extendDeadline(ctx context.Context, d time.Duration) {
+	if c, ok := ctx.Value(extendableCtxKey{}).(*extendableCtx); ok {
+		c.extend(d)
+	}
+}
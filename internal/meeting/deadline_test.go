@@ -0,0 +1,56 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExtendableTimeout(t *testing.T) {
+	meetingCtx, meetingCancel := withExtendableTimeout(context.Background(), 50*time.Millisecond)
+	defer meetingCancel()
+	agentCtx, agentCancel := withExtendableTimeout(meetingCtx, 50*time.Millisecond)
+	defer agentCancel()
+
+	// 长耗时工具开始后延长专家及会议超时
+	extendDeadline(agentCtx, 300*time.Millisecond)
+	select {
+	case <-agentCtx.Done():
+		t.Fatalf("延长后提前超时: %v", agentCtx.Err())
+	case <-time.After(150 * time.Millisecond):
+	}
+	if meetingCtx.Err() != nil {
+		t.Fatalf("会议超时未随专家延长: %v", meetingCtx.Err())
+	}
+
+	select {
+	case <-agentCtx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("延长后的超时未生效")
+	}
+	if !errors.Is(agentCtx.Err(), context.DeadlineExceeded) {
+		t.Fatalf("Err = %v, want DeadlineExceeded", agentCtx.Err())
+	}
+}
+
+func TestExtendableTimeout_ParentCancel(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, stop := withExtendableTimeout(parent, time.Minute)
+	defer stop()
+
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+	cancel()
+	select {
+	case <-child.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("父 context 取消未传递")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Fatalf("Err = %v, want Canceled", ctx.Err())
+	}
+	if d, ok := ctx.Deadline(); !ok || time.Until(d) < 50*time.Second {
+		t.Fatalf("Deadline = %v, %v", d, ok)
+	}
+}
@@ -0,0 +1,317 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// MeetingModeDebate 辩论模式：首轮分析后，小韭菜挑出分歧最大的两位专家互相反驳，最后给出裁决
+// 通过 ChatRequest.Mode 选择，默认仍为智能模式
+const MeetingModeDebate = "debate"
+
+// 辩论模式的轮次（开场 0、首轮分析 1 与智能模式一致）
+const (
+	RoundAnalysis = 1 // 首轮分析
+	RoundRebuttal = 2 // 反驳轮
+	RoundVerdict  = 3 // 裁决
+
+	MsgTypeRebuttal = "rebuttal"
+)
+
+// DebatePick 小韭菜选出的辩论双方
+type DebatePick struct {
+	Pair  []string `json:"pair"`  // 两位专家 ID
+	Issue string   `json:"issue"` // 分歧焦点
+}
+
+// PickDebaters 从首轮发言中选出观点分歧最大的两位专家
+func (m *Moderator) PickDebaters(ctx context.Context, stock *models.Stock, query string, history []DiscussionEntry) (*DebatePick, error) {
+	content, err := m.generate(ctx, m.buildDebatePickPrompt(stock, query, history))
+	if err != nil {
+		return nil, fmt.Errorf("moderator pick debaters error: %w", err)
+	}
+	return parseDebatePick(content, history)
+}
+
+// VerdictStream 根据首轮分析与反驳给出裁决，onDelta 为 nil 时不流式输出
+func (m *Moderator) VerdictStream(ctx context.Context, stock *models.Stock, query, issue string, history []DiscussionEntry, onDelta func(text string)) (string, error) {
+	prompt := m.buildVerdictPrompt(stock, query, issue, history)
+	if onDelta == nil {
+		return m.generate(ctx, prompt)
+	}
+	return m.generateStream(ctx, prompt, onDelta)
+}
+
+// parseDebatePick 解析辩论双方，两位专家必须是首轮发言过的不同专家
+func parseDebatePick(content string, history []DiscussionEntry) (*DebatePick, error) {
+	jsonStr := (&Moderator{}).extractJSON(strings.TrimSpace(content))
+	if jsonStr == "" {
+		return nil, fmt.Errorf("无法从响应中提取 JSON: %s", truncateString(content, 200))
+	}
+	var pick DebatePick
+	if err := json.Unmarshal([]byte(jsonStr), &pick); err != nil {
+		return nil, fmt.Errorf("JSON 解析失败: %w, 原文: %s", err, truncateString(jsonStr, 200))
+	}
+	spoke := func(id string) bool {
+		return slices.ContainsFunc(history, func(e DiscussionEntry) bool { return e.AgentID == id })
+	}
+	if len(pick.Pair) != 2 || pick.Pair[0] == pick.Pair[1] || !spoke(pick.Pair[0]) || !spoke(pick.Pair[1]) {
+		return nil, fmt.Errorf("无效的辩论双方: %v", pick.Pair)
+	}
+	return &pick, nil
+}
+
+// buildDebatePickPrompt 构建选择辩论双方的 Prompt
+func (m *Moderator) buildDebatePickPrompt(stock *models.Stock, query string, history []DiscussionEntry) string {
+	var sb strings.Builder
+	sb.WriteString("你是会议小韭菜。专家们已完成首轮分析，请找出观点分歧最大的两位专家，安排他们互相反驳。\n\n")
+	fmt.Fprintf(&sb, "## 标的：%s (%s)\n\n", stock.Name, stock.Symbol)
+	sb.WriteString("## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	sb.WriteString("## 首轮发言\n")
+	for _, e := range history {
+		fmt.Fprintf(&sb, "【%s（%s，ID: %s）】\n%s\n\n", e.AgentName, e.Role, e.AgentID, e.Content)
+	}
+	sb.WriteString("## 要求\n")
+	sb.WriteString("1. 优先选择结论方向相反（如看多与看空）的两位；都一致时，选择依据或风险判断差异最大的两位\n")
+	sb.WriteString("2. 用一句话概括两人的分歧焦点\n\n")
+	sb.WriteString("## 输出格式（仅输出JSON）\n")
+	sb.WriteString(`{"pair":["id1","id2"],"issue":"分歧焦点"}`)
+	return sb.String()
+}
+
+// buildRebuttalPrompt 构建专家反驳对方的任务，rebuttal 为对方已对自己作出的反驳（可为空）
+func buildRebuttalPrompt(issue string, self, opponent DiscussionEntry, rebuttal string) string {
+	if issue == "" {
+		issue = "整体判断"
+	}
+	var sb strings.Builder
+	sb.WriteString("## 辩论环节：反驳\n")
+	fmt.Fprintf(&sb, "小韭菜认为你与%s（%s）在「%s」上分歧最大，请反驳对方。\n\n", opponent.AgentName, opponent.Role, issue)
+	sb.WriteString("### 你的首轮观点\n")
+	sb.WriteString(self.Content + "\n\n")
+	fmt.Fprintf(&sb, "### %s的首轮观点\n", opponent.AgentName)
+	sb.WriteString(opponent.Content + "\n\n")
+	if rebuttal != "" {
+		fmt.Fprintf(&sb, "### %s对你的反驳\n", opponent.AgentName)
+		sb.WriteString(rebuttal + "\n\n")
+	}
+	sb.WriteString("### 要求\n")
+	sb.WriteString("1. 针对对方的核心论据逐条反驳，指出其数据或逻辑上的漏洞\n")
+	sb.WriteString("2. 可以承认对方说对的部分，但要说明为何不改变你的判断；若被说服，明确修正你的结论\n")
+	sb.WriteString("3. 不要重复首轮内容，控制在 300 字以内\n")
+	return sb.String()
+}
+
+// buildVerdictPrompt 构建裁决 Prompt
+func (m *Moderator) buildVerdictPrompt(stock *models.Stock, query, issue string, history []DiscussionEntry) string {
+	var sb strings.Builder
+	sb.WriteString("你是会议小韭菜，专家们进行了辩论，请作为裁判给老韭菜裁决。\n\n")
+	fmt.Fprintf(&sb, "## 标的：%s (%s)\n\n", stock.Name, stock.Symbol)
+	sb.WriteString("## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	if issue != "" {
+		sb.WriteString("## 争议焦点\n")
+		sb.WriteString(issue + "\n\n")
+	}
+	sb.WriteString("## 第一轮：分析\n")
+	for _, e := range history {
+		if e.Round != RoundRebuttal {
+			fmt.Fprintf(&sb, "【%s（%s）】\n%s\n\n", e.AgentName, e.Role, e.Content)
+		}
+	}
+	sb.WriteString("## 第二轮：反驳\n")
+	for _, e := range history {
+		if e.Round == RoundRebuttal {
+			fmt.Fprintf(&sb, "【%s 反驳 %s】\n%s\n\n", e.AgentName, e.Target, e.Content)
+		}
+	}
+	sb.WriteString("## 输出要求\n")
+	sb.WriteString("1. 裁决：哪一方的论据更有说服力及关键依据（可以判定各有道理，但要说明分界条件）\n")
+	sb.WriteString("2. 双方都未能驳倒的关键风险或机会\n")
+	sb.WriteString("3. 给老韭菜的最终建议\n\n")
+	sb.WriteString("控制在 400 字以内。")
+	return sb.String()
+}
+
+// labelDebateRound 为辩论模式的发言补充轮次标签
+func labelDebateRound(resp ChatResponse) ChatResponse {
+	if resp.RoundLabel != "" {
+		return resp
+	}
+	switch resp.MsgType {
+	case "opening":
+		resp.RoundLabel = "开场"
+	case "opinion":
+		resp.RoundLabel = "第一轮 · 分析"
+	case MsgTypeRebuttal:
+		resp.RoundLabel = "第二轮 · 反驳"
+	case "summary":
+		resp.RoundLabel = "总结"
+		if resp.Round == RoundVerdict {
+			resp.RoundLabel = "裁决"
+		}
+	}
+	return resp
+}
+
+// debateCallback 包装回调，为辩论模式的每条发言补充轮次标签
+func debateCallback(cb ResponseCallback) ResponseCallback {
+	if cb == nil {
+		return nil
+	}
+	return func(resp ChatResponse) {
+		cb(labelDebateRound(resp))
+	}
+}
+
+// runDebate 首轮结束后的辩论轮：选出分歧最大的两位专家互相反驳，再由小韭菜裁决
+// 首轮发言不足两位或已达发言轮数上限时，降级为普通总结
+func (s *Service) runDebate(
+	ctx context.Context,
+	state *MeetingState,
+	agentModels *agentModels,
+	respCallback ResponseCallback,
+	progressCallback ProgressCallback,
+) []ChatResponse {
+	history := state.History
+	responses := make([]ChatResponse, len(state.Responses))
+	for i, resp := range state.Responses {
+		responses[i] = labelDebateRound(resp)
+	}
+	summarize := func() []ChatResponse {
+		out, _ := s.runMeetingSummary(ctx, state, history, responses, respCallback, progressCallback)
+		if n := len(out); n > len(responses) {
+			out[n-1] = labelDebateRound(out[n-1])
+		}
+		return out
+	}
+	if len(history) < 2 {
+		log.Info("debate: only %d opinions, falling back to summary", len(history))
+		return summarize()
+	}
+	if !allowRound(state.MaxRounds, RoundRebuttal, progressCallback) {
+		return summarize()
+	}
+
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "寻找分歧",
+	})
+	pickCtx, pickCancel := context.WithTimeout(ctx, ModeratorTimeout)
+	pick, err := state.Moderator.PickDebaters(pickCtx, &state.Stock, state.Query, history)
+	pickCancel()
+	emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜"})
+	if err != nil {
+		if ctx.Err() != nil {
+			return responses
+		}
+		// 选择失败时让前两位发言的专家辩论
+		log.Warn("debate: pick debaters failed, using first two: %v", err)
+		pick = &DebatePick{Pair: []string{history[0].AgentID, history[1].AgentID}}
+	}
+	log.Info("debate: %v on %q", pick.Pair, pick.Issue)
+
+	first := make(map[string]DiscussionEntry, len(history))
+	for _, e := range history {
+		first[e.AgentID] = e
+	}
+	agents := make(map[string]models.AgentConfig, len(state.SelectedAgents))
+	for _, a := range state.SelectedAgents {
+		agents[a.ID] = a
+	}
+
+	// 双方依次反驳，后发言的一方同时看到对方对自己的反驳
+	var lastRebuttal string
+	for i, id := range pick.Pair {
+		if ctx.Err() != nil {
+			return responses
+		}
+		agentCfg, ok := agents[id]
+		if !ok {
+			continue
+		}
+		self, opponent := first[id], first[pick.Pair[1-i]]
+
+		agentLLM, agentAIConfig, err := agentModels.forAgent(ctx, &agentCfg)
+		if err != nil {
+			log.Error("debate: create agent LLM error: %v", err)
+			continue
+		}
+		builder := s.createBuilder(ctx, agentLLM, agentAIConfig)
+
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: "反驳" + opponent.AgentName,
+			Model: modelLabel(agentAIConfig),
+		})
+		query := buildRebuttalPrompt(pick.Issue, self, opponent, lastRebuttal)
+		out, err := retryRun(ctx, MaxAgentRetries, func() (agentOutput, error) {
+			return runAgentWithTimeout(ctx, agentTimeout(builder.AIConfig(), state.AgentTimeout), func(agentCtx context.Context) (agentOutput, error) {
+				return s.runSingleAgent(agentCtx, builder, &agentCfg, &state.Stock, query, nil, state.MemoryContext, progressCallback, state.Position)
+			})
+		})
+		if err != nil {
+			// 反驳失败不中断会议，裁决基于已有发言
+			emitProgress(progressCallback, ProgressEvent{Type: "agent_error", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: err.Error()})
+			emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name})
+			log.Error("debate: agent %s rebuttal failed: %v", agentCfg.ID, err)
+			lastRebuttal = ""
+			continue
+		}
+		emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name})
+
+		resp := ChatResponse{
+			AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role,
+			Content: out.Content, Round: RoundRebuttal, MsgType: MsgTypeRebuttal, MeetingMode: MeetingModeDebate,
+			Rebuts:           opponent.AgentID,
+			Reasoning:        applyThinkingPersist(s.thinkingPersist, out.Reasoning),
+			ToolCalls:        out.ToolCalls,
+			ToolResults:      out.ToolResults,
+			FinishReason:     out.FinishReason,
+			PromptTokens:     out.InputTokens,
+			CompletionTokens: out.OutputTokens,
+		}
+		resp = labelDebateRound(resp)
+		responses = append(responses, resp)
+		if respCallback != nil {
+			respCallback(resp)
+		}
+		history = append(history, DiscussionEntry{
+			Round: RoundRebuttal, AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+			Role: agentCfg.Role, Content: out.Content, Target: opponent.AgentName,
+		})
+		lastRebuttal = out.Content
+	}
+
+	// 裁决
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "裁决",
+	})
+	verdictCtx, verdictCancel := context.WithTimeout(ctx, ModeratorTimeout)
+	verdict, err := state.Moderator.VerdictStream(verdictCtx, &state.Stock, state.Query, pick.Issue, history, moderatorDeltaFunc(progressCallback))
+	verdictCancel()
+	emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜"})
+	if err != nil {
+		log.Error("debate: verdict error: %v", err)
+		return responses
+	}
+	if verdict == "" {
+		return responses
+	}
+
+	// 裁决作为会议总结保存，恢复、导出与记忆沿用总结的处理
+	verdictResp := labelDebateRound(ChatResponse{
+		AgentID: "moderator", AgentName: "小韭菜", Role: "会议主持",
+		Content: verdict, Round: RoundVerdict, MsgType: "summary", MeetingMode: MeetingModeDebate,
+	})
+	responses = append(responses, verdictResp)
+	if respCallback != nil {
+		respCallback(verdictResp)
+	}
+	s.saveRoundMemory(state, history, verdict)
+	return responses
+}
@@ -0,0 +1,144 @@
+package meeting
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// debateMockServer 模拟 Ollama：a1 看多、a3 看空，小韭菜选出 a1 与 a3 辩论
+func debateMockServer(t *testing.T) *httptest.Server {
+	return ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		text := q.Text()
+		reply := "观点"
+		switch {
+		case strings.Contains(text, "负责组织专家讨论"):
+			reply = `{"intent":"走势","selected":["a1","a2","a3"],"topic":"走势","opening":"开始"}`
+		case strings.Contains(text, "观点分歧最大的两位专家"):
+			reply = `{"pair":["a1","a3"],"issue":"估值是否过高"}`
+		case strings.Contains(text, "请作为裁判"):
+			reply = "裁决"
+		case strings.Contains(text, "辩论环节"):
+			for _, id := range []string{"a1", "a3"} {
+				if strings.Contains(text, "指令-"+id) {
+					reply = "反驳-" + id
+				}
+			}
+		default:
+			for _, id := range []string{"a1", "a2", "a3"} {
+				if strings.Contains(text, "指令-"+id) {
+					reply = "观点-" + id
+				}
+			}
+		}
+		return ollamaReply{Content: reply}
+	})
+}
+
+func TestRunSmartMeeting_Debate(t *testing.T) {
+	srv := debateMockServer(t)
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := parallelRequest()
+	req.ParallelRounds = false
+	req.Mode = MeetingModeDebate
+
+	var labels []string
+	responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, func(resp ChatResponse) {
+		labels = append(labels, resp.AgentID+":"+resp.RoundLabel)
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "moderator:开场,a1:第一轮 · 分析,a2:第一轮 · 分析,a3:第一轮 · 分析,a1:第二轮 · 反驳,a3:第二轮 · 反驳,moderator:裁决"
+	if got := strings.Join(labels, ","); got != want {
+		t.Fatalf("callbacks = %s\nwant %s", got, want)
+	}
+	if len(responses) != 7 {
+		t.Fatalf("responses = %+v", responses)
+	}
+	for i, wantRebuts := range map[int]string{4: "a3", 5: "a1"} {
+		r := responses[i]
+		if r.MsgType != MsgTypeRebuttal || r.Round != RoundRebuttal || r.Rebuts != wantRebuts || r.Content != "反驳-"+r.AgentID {
+			t.Errorf("responses[%d] = %+v", i, r)
+		}
+	}
+	if last := responses[6]; last.MsgType != "summary" || last.Round != RoundVerdict || last.Content != "裁决" {
+		t.Errorf("verdict = %+v", last)
+	}
+}
+
+func TestRunSmartMeeting_DebateRoundLimit(t *testing.T) {
+	srv := debateMockServer(t)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+
+	// 轮数上限来自策略（请求）或全局会议预算
+	for _, viaBudget := range []bool{false, true} {
+		svc := NewServiceFull(nil, nil)
+		req := parallelRequest()
+		req.ParallelRounds = false
+		req.Mode = MeetingModeDebate
+		if viaBudget {
+			svc.SetMeetingBudget(models.MeetingBudget{MaxRounds: 1})
+		} else {
+			req.MaxRounds = 1
+		}
+
+		var limited bool
+		responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, func(ev ProgressEvent) {
+			limited = limited || ev.Type == ProgressTypeBudgetExhausted
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 只允许 1 轮发言时跳过反驳轮，直接总结
+		for _, r := range responses {
+			if r.MsgType == MsgTypeRebuttal {
+				t.Fatalf("budget=%v 超过轮数上限仍进行了反驳: %+v", viaBudget, r)
+			}
+		}
+		if last := responses[len(responses)-1]; last.MsgType != "summary" || last.RoundLabel != "总结" {
+			t.Fatalf("budget=%v last = %+v", viaBudget, last)
+		}
+		if !limited {
+			t.Errorf("budget=%v 应通过进度事件告知已达轮数上限", viaBudget)
+		}
+	}
+}
+
+func TestRoundLimit(t *testing.T) {
+	svc := NewServiceFull(nil, nil)
+	if got := svc.roundLimit(0); got != 0 {
+		t.Errorf("未配置 = %d", got)
+	}
+	svc.SetMeetingBudget(models.MeetingBudget{MaxRounds: 1})
+	if got := svc.roundLimit(0); got != 1 {
+		t.Errorf("会议预算 = %d", got)
+	}
+	if got := svc.roundLimit(2); got != 2 {
+		t.Errorf("策略优先 = %d", got)
+	}
+}
+
+func TestParseDebatePick(t *testing.T) {
+	history := []DiscussionEntry{{AgentID: "a1"}, {AgentID: "a2"}}
+	pick, err := parseDebatePick("```json\n{\"pair\":[\"a2\",\"a1\"],\"issue\":\"估值\"}\n```", history)
+	if err != nil || pick.Pair[0] != "a2" || pick.Issue != "估值" {
+		t.Fatalf("pick = %+v, %v", pick, err)
+	}
+	for _, bad := range []string{
+		`{"pair":["a1","a1"]}`,
+		`{"pair":["a1","a9"]}`,
+		`{"pair":["a1"]}`,
+		`无 JSON`,
+	} {
+		if _, err := parseDebatePick(bad, history); err == nil {
+			t.Errorf("parseDebatePick(%q) 应返回错误", bad)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package meeting
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 会议纯文本摘要（供小屏/读屏场景使用）
+const (
+	DigestLineMaxRunes  = 80 // 单行摘要最大字数（含专家名）
+	maxDigestRunsPerKey = 5  // 每只股票保留的会议摘要场次
+)
+
+// MeetingDigest 一场会议的滚动摘要，每位专家发言完成后追加一行
+type MeetingDigest struct {
+	StockCode string   `json:"stockCode"`
+	RunID     string   `json:"runId"` // 会议场次 ID，智能会议 / 直接提问开始时生成
+	Lines     []string `json:"lines"`
+}
+
+// Text 纯文本形式，一行一位专家
+func (d MeetingDigest) Text() string {
+	return strings.Join(d.Lines, "\n")
+}
+
+// DigestListener 摘要更新回调，每追加一行调用一次
+type DigestListener func(digest MeetingDigest)
+
+var (
+	// digestMarkdownRe 行首的 Markdown 标记（标题、引用、列表）
+	digestMarkdownRe = regexp.MustCompile(`^(?:(?:#+|>|[-*+]\s|\d+[.)]\s|\d+、)\s*)+`)
+	// digestSentenceEndRe 句末标点
+	digestSentenceEndRe = regexp.MustCompile(`[。！？!?；;]`)
+	// digestKeyLevelRe 关键价位表述，如"支撑位 13.8"、"关键位：13.80 元"
+	digestKeyLevelRe = regexp.MustCompile(`(关键位|支撑位?|压力位?|阻力位?|止损位?|目标价)\s*(?:在|为|是)?\s*[:：]?\s*[¥￥]?\s*(\d+(?:\.\d+)?)`)
+)
+
+// digestLine 由专家发言生成单行摘要："钱姐: 主力净流入, 偏多 | 关键位 13.8"
+func digestLine(agentName, content string) string {
+	sentence := firstSentence(content)
+	if sentence == "" {
+		return ""
+	}
+	line := agentName + ": " + sentence
+	if m := digestKeyLevelRe.FindStringSubmatch(content); m != nil && !strings.Contains(sentence, m[2]) {
+		line += " | " + m[1] + " " + m[2]
+	}
+	return truncateRunes(line, DigestLineMaxRunes)
+}
+
+// firstSentence 取正文第一句（跳过空行，去掉 Markdown 标记与加粗）
+func firstSentence(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(digestMarkdownRe.ReplaceAllString(strings.TrimSpace(line), ""))
+		line = strings.NewReplacer("**", "", "__", "", "`", "").Replace(line)
+		if line == "" {
+			continue
+		}
+		if loc := digestSentenceEndRe.FindStringIndex(line); loc != nil {
+			line = line[:loc[0]]
+		}
+		line = strings.TrimRight(strings.TrimSpace(line), "，,：:")
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// truncateRunes 按字数截断，超出时以省略号结尾
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// SetDigestListener 设置会议摘要更新回调
+func (s *Service) SetDigestListener(listener DigestListener) {
+	s.digestListener = listener
+}
+
+// startDigest 新会议开始时为该股票开启新的摘要场次，返回场次 ID
+func (s *Service) startDigest(stockCode string) string {
+	if stockCode == "" {
+		return ""
+	}
+	s.digestMu.Lock()
+	defer s.digestMu.Unlock()
+	return s.startDigestLocked(stockCode).RunID
+}
+
+// startDigestLocked 开启新场次并只保留最近几场，调用方需持有 digestMu
+func (s *Service) startDigestLocked(stockCode string) *MeetingDigest {
+	d := &MeetingDigest{
+		StockCode: stockCode,
+		RunID:     fmt.Sprintf("%s-%d", stockCode, time.Now().UnixNano()),
+		Lines:     []string{},
+	}
+	runs := append(s.digests[stockCode], d)
+	if len(runs) > maxDigestRunsPerKey {
+		runs = runs[len(runs)-maxDigestRunsPerKey:]
+	}
+	s.digests[stockCode] = runs
+	return d
+}
+
+// appendDigest 追加一位专家的摘要行并通知监听方
+// 继续会议与单专家重试追加到最近一场；尚无场次时自动开启
+func (s *Service) appendDigest(stockCode, agentName, content string) {
+	line := digestLine(agentName, content)
+	if stockCode == "" || line == "" {
+		return
+	}
+	s.digestMu.Lock()
+	var d *MeetingDigest
+	if runs := s.digests[stockCode]; len(runs) > 0 {
+		d = runs[len(runs)-1]
+	} else {
+		d = s.startDigestLocked(stockCode)
+	}
+	d.Lines = append(d.Lines, line)
+	snapshot := MeetingDigest{StockCode: d.StockCode, RunID: d.RunID, Lines: append([]string(nil), d.Lines...)}
+	s.digestMu.Unlock()
+
+	if s.digestListener != nil {
+		s.digestListener(snapshot)
+	}
+}
+
+// GetMeetingDigest 获取某场会议的纯文本摘要，runID 为空时返回最近一场
+func (s *Service) GetMeetingDigest(stockCode, runID string) string {
+	s.digestMu.Lock()
+	defer s.digestMu.Unlock()
+	runs := s.digests[stockCode]
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runID == "" || runs[i].RunID == runID {
+			return runs[i].Text()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package meeting
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestDigestLine(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"first sentence with key level", "主力净流入, 偏多。短线关注关键位 13.8 的得失。", "钱姐: 主力净流入, 偏多 | 关键位 13.8"},
+		{"markdown heading skipped", "## **资金面**\n\n- 北向资金连续流出！后市谨慎", "钱姐: 资金面"},
+		{"list item", "\n> 1. 量能不足，观望为主", "钱姐: 量能不足，观望为主"},
+		{"level already in sentence", "支撑位 12.5 有效。", "钱姐: 支撑位 12.5 有效"},
+		{"empty", "  \n  ", ""},
+	}
+	for _, tt := range tests {
+		if got := digestLine("钱姐", tt.content); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	long := digestLine("钱姐", strings.Repeat("长", 200))
+	if utf8.RuneCountInString(long) != DigestLineMaxRunes || !strings.HasSuffix(long, "…") {
+		t.Errorf("long line = %q", long)
+	}
+}
+
+func TestMeetingDigest(t *testing.T) {
+	llm := &mockLLM{}
+	srv := ollamaMockServer(t, llm.reply)
+
+	svc := NewServiceFull(nil, nil)
+	var events []MeetingDigest
+	svc.SetDigestListener(func(d MeetingDigest) { events = append(events, d) })
+
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := ChatRequest{
+		StockCode: "sh600519",
+		Stock:     models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500},
+		Query:     "能买吗",
+		AllAgents: []models.AgentConfig{{ID: "macro", Name: "老陈", Role: "宏观策略", Enabled: true}},
+	}
+	if _, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, nil); err != nil {
+		t.Fatalf("meeting error: %v", err)
+	}
+	if len(events) != 1 || events[0].StockCode != "sh600519" || events[0].RunID == "" {
+		t.Fatalf("digest events = %+v", events)
+	}
+	first := events[0].RunID
+	if got := svc.GetMeetingDigest("sh600519", first); got != "老陈: 上涨家数占优，指数短期偏强" {
+		t.Errorf("digest = %q", got)
+	}
+
+	// 新会议开启新场次，旧场次仍可按 runID 取回
+	if _, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, nil); err != nil {
+		t.Fatalf("meeting error: %v", err)
+	}
+	if len(events) != 2 || events[1].RunID == first || len(events[1].Lines) != 1 {
+		t.Fatalf("second run events = %+v", events)
+	}
+	if svc.GetMeetingDigest("sh600519", first) == "" || svc.GetMeetingDigest("sh600519", "") == "" {
+		t.Error("digest runs should be retrievable")
+	}
+	if got := svc.GetMeetingDigest("sh600519", "unknown"); got != "" {
+		t.Errorf("unknown run digest = %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// DryRunPlan 预演模式下单个专家计划调用的工具与理由
+type DryRunPlan struct {
+	AgentID          string   `json:"agentId"`
+	AgentName        string   `json:"agentName"`
+	PlannedToolCalls []string `json:"plannedToolCalls"` // 按调用顺序，含关键参数
+	Reasoning        string   `json:"reasoning"`
+	Error            string   `json:"error,omitempty"` // 该专家预演失败时的错误信息
+}
+
+// DryRunMeeting 预演会议：各专家使用与正式会议相同的指令，但不挂载工具，只描述计划调用的工具与理由
+// 指定了专家（req.Agents）时只预演这些专家，否则预演全部候选专家（不经小韭菜选人，避免额外消耗）
+// 结果按专家顺序返回，单个专家失败记录在其 Error 中
+func (s *Service) DryRunMeeting(ctx context.Context, aiConfig *models.AIConfig, req ChatRequest) ([]DryRunPlan, error) {
+	if aiConfig == nil {
+		return nil, ErrNoAIConfig
+	}
+	agents := req.Agents
+	if len(agents) == 0 {
+		agents = req.AllAgents
+	}
+	if len(agents) == 0 {
+		return nil, ErrNoAgents
+	}
+
+	dryRunCtx, cancel := withExtendableTimeout(ctx, MeetingTimeout)
+	defer cancel()
+
+	agentModels := s.newAgentModels(aiConfig, nil)
+	plans := make([]DryRunPlan, len(agents))
+	var wg sync.WaitGroup
+	for i := range agents {
+		wg.Add(1)
+		go func(i int, cfg models.AgentConfig) {
+			defer wg.Done()
+			plans[i] = s.dryRunAgent(dryRunCtx, agentModels, &cfg, req)
+		}(i, agents[i])
+	}
+	wg.Wait()
+	return plans, nil
+}
+
+// dryRunAgent 让单个专家输出工具调用计划，不重试
+// 预演不是正式发言，不计入会议用量与摘要，也不做数据核对
+func (s *Service) dryRunAgent(ctx context.Context, agentModels *agentModels, cfg *models.AgentConfig, req ChatRequest) DryRunPlan {
+	plan := DryRunPlan{AgentID: cfg.ID, AgentName: cfg.Name, PlannedToolCalls: []string{}}
+	agentLLM, agentAIConfig, err := agentModels.forAgent(ctx, cfg)
+	if err != nil {
+		plan.Error = fmt.Sprintf("create model error: %v", err)
+		return plan
+	}
+	builder := s.createBuilder(ctx, agentLLM, agentAIConfig)
+	builder.SetDryRun(true)
+
+	out, err := runAgentWithTimeout(ctx, agentTimeout(builder.AIConfig(), req.AgentTimeout), func(agentCtx context.Context) (agentOutput, error) {
+		return s.runAgentSession(agentCtx, builder, cfg, &req.Stock, req.Query, nil, req.ReplyContent, nil, req.Position)
+	})
+	if err != nil {
+		log.Warn("dry run agent %s failed: %v", cfg.ID, err)
+		plan.Error = err.Error()
+		return plan
+	}
+	plan.PlannedToolCalls, plan.Reasoning = parseDryRunPlan(out.Content)
+	return plan
+}
+
+// parseDryRunPlan 解析专家输出的 JSON 计划，无法解析时整段作为理由返回
+func parseDryRunPlan(content string) ([]string, string) {
+	var parsed struct {
+		ToolCalls []string `json:"toolCalls"`
+		Reasoning string   `json:"reasoning"`
+	}
+	jsonStr := (&Moderator{}).extractJSON(content)
+	if jsonStr == "" || json.Unmarshal([]byte(jsonStr), &parsed) != nil {
+		return []string{}, strings.TrimSpace(content)
+	}
+	calls := make([]string, 0, len(parsed.ToolCalls))
+	for _, c := range parsed.ToolCalls {
+		if c = strings.TrimSpace(c); c != "" {
+			calls = append(calls, c)
+		}
+	}
+	return calls, strings.TrimSpace(parsed.Reasoning)
+}
@@ -0,0 +1,76 @@
+package meeting
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestDryRunMeeting(t *testing.T) {
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		system := q.First()
+		if !strings.HasSuffix(system, adk.DryRunSuffix) || len(q.Tools) > 0 {
+			t.Errorf("预演请求应追加预演要求且不带工具: tools=%d\n%s", len(q.Tools), system)
+		}
+
+		reply := "```json\n{\"toolCalls\":[\"get_kline_data(code=sh600519, period=1d)\",\" \",\"get_news()\"],\"reasoning\":\"先看走势再看消息\"}\n```"
+		switch {
+		case strings.Contains(system, "指令-a2"):
+			reply = "我会先看K线"
+		case strings.Contains(system, "指令-a3"):
+			return ollamaReply{Status: http.StatusBadRequest}
+		}
+		return ollamaReply{Content: reply, PromptEval: 100, Eval: 20}
+	})
+
+	svc := NewServiceFull(nil, nil)
+	recorded := 0
+	svc.SetUsageRecorder(func(string, models.TokenUsage) { recorded++ })
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := parallelRequest()
+	req.AllAgents = req.AllAgents[:3]
+
+	plans, err := svc.DryRunMeeting(context.Background(), aiConfig, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans) != 3 || plans[0].AgentID != "a1" || plans[1].AgentID != "a2" || plans[2].AgentID != "a3" {
+		t.Fatalf("plans = %+v", plans)
+	}
+	if got := strings.Join(plans[0].PlannedToolCalls, ";"); got != "get_kline_data(code=sh600519, period=1d);get_news()" || plans[0].Reasoning != "先看走势再看消息" {
+		t.Errorf("plan = %+v", plans[0])
+	}
+	// 非 JSON 输出整段作为理由
+	if len(plans[1].PlannedToolCalls) != 0 || plans[1].Reasoning != "我会先看K线" {
+		t.Errorf("fallback plan = %+v", plans[1])
+	}
+	// 单个专家失败不影响其他专家
+	if plans[2].Error == "" {
+		t.Errorf("failed plan = %+v", plans[2])
+	}
+
+	// 预演不计入会议用量与摘要
+	if usage := svc.GetMeetingUsage(req.Stock.Symbol); usage.TotalTokens != 0 || recorded != 0 {
+		t.Errorf("dry run recorded usage: %+v, recorder calls %d", usage, recorded)
+	}
+	if digest := svc.GetMeetingDigest(req.Stock.Symbol, ""); digest != "" {
+		t.Errorf("dry run wrote digest: %q", digest)
+	}
+
+	// 指定专家时只预演指定的专家
+	req.Agents = req.AllAgents[1:2]
+	if plans, err := svc.DryRunMeeting(context.Background(), aiConfig, req); err != nil || len(plans) != 1 || plans[0].AgentID != "a2" {
+		t.Errorf("mentioned plans = %+v, %v", plans, err)
+	}
+}
+
+func TestDryRunMeeting_NoAgents(t *testing.T) {
+	svc := NewServiceFull(nil, nil)
+	if _, err := svc.DryRunMeeting(context.Background(), &models.AIConfig{}, ChatRequest{}); err != ErrNoAgents {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package meeting
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// ProgressTypeFactCheck 专家发言中的行情数字与会议快照不符时推送的事件
+const ProgressTypeFactCheck = "fact_check"
+
+// 数字核对容差：快照与发言之间存在时间差，只标记明显矛盾
+const (
+	FactCheckPriceTolerance  = 0.02 // 现价相对误差上限
+	FactCheckChangeTolerance = 1.0  // 涨跌幅绝对误差上限（百分点）
+)
+
+var (
+	// factPriceRe 现价表述，要求带 ¥/元 单位，如"现价 12.50 元"、"最新价：¥12.5"
+	factPriceRe = regexp.MustCompile(`(?:现价|当前股价|最新股价|当前价格?|最新价格?)\s*(?:为|是|报|在|约)?\s*[:：]?\s*([¥￥]?)\s*(\d+(?:\.\d+)?)\s*(元?)`)
+	// factChangeRe 涨跌幅表述，要求当前/最新/现前缀，如"当前涨幅 3.2%"
+	factChangeRe = regexp.MustCompile(`(?:当前|最新|现)(涨跌幅|涨幅|跌幅)\s*(?:为|是|达|报|约)?\s*[:：]?\s*([+-]?\d+(?:\.\d+)?)\s*[%％]`)
+	// factSentenceRe 按句切分，只核对提及标的的句子
+	factSentenceRe = regexp.MustCompile(`[。！？!?；;\n]+`)
+)
+
+// factMismatch 一处与快照矛盾的数字
+type factMismatch struct {
+	Kind    string // price / change
+	Claimed float64
+	Actual  float64
+}
+
+// checkFacts 核对发言中关于标的的现价/涨跌幅表述，返回与快照矛盾的项
+// 保守策略：只看同句提及标的名称或代码、且使用现价/当前/最新措辞的数字
+func checkFacts(content string, stock *models.Stock) []factMismatch {
+	if stock == nil || stock.Price <= 0 || models.IsIndexSymbol(stock.Symbol) {
+		return nil
+	}
+	var result []factMismatch
+	for _, sentence := range factSentenceRe.Split(content, -1) {
+		if !mentionsSubject(sentence, stock) {
+			continue
+		}
+		for _, m := range factPriceRe.FindAllStringSubmatch(sentence, -1) {
+			if m[1] == "" && m[3] == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(m[2], 64)
+			if err != nil || v <= 0 {
+				continue
+			}
+			if math.Abs(v-stock.Price)/stock.Price > FactCheckPriceTolerance {
+				result = append(result, factMismatch{Kind: "price", Claimed: v, Actual: stock.Price})
+			}
+		}
+		for _, m := range factChangeRe.FindAllStringSubmatch(sentence, -1) {
+			v, err := strconv.ParseFloat(m[2], 64)
+			if err != nil {
+				continue
+			}
+			// "跌幅 2%" 即 -2%；已写负号的不再取反
+			if m[1] == "跌幅" && v > 0 {
+				v = -v
+			}
+			if math.Abs(v-stock.ChangePercent) > FactCheckChangeTolerance {
+				result = append(result, factMismatch{Kind: "change", Claimed: v, Actual: stock.ChangePercent})
+			}
+		}
+	}
+	return result
+}
+
+// mentionsSubject 句子是否提及标的名称或六位代码
+func mentionsSubject(sentence string, stock *models.Stock) bool {
+	if stock.Name != "" && strings.Contains(sentence, stock.Name) {
+		return true
+	}
+	code := strings.TrimLeft(strings.ToLower(stock.Symbol), "shzbj.")
+	return len(code) == 6 && strings.Contains(sentence, code)
+}
+
+// factCheckFootnote 生成附在发言末尾的更正脚注
+func factCheckFootnote(mismatches []factMismatch) string {
+	parts := make([]string, 0, len(mismatches))
+	for _, m := range mismatches {
+		switch m.Kind {
+		case "price":
+			parts = append(parts, fmt.Sprintf("现价 %s 元与行情快照 %.2f 元不符", formatFloat(m.Claimed), m.Actual))
+		case "change":
+			parts = append(parts, fmt.Sprintf("涨跌幅 %+.2f%% 与行情快照 %+.2f%% 不符", m.Claimed, m.Actual))
+		}
+	}
+	return "\n\n> 数据核对：" + strings.Join(parts, "；") + "，请以实时行情为准。"
+}
+
+// formatFloat 去掉多余的小数零
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// applyFactCheck 核对发言并在矛盾时追加脚注、推送 fact_check 事件
+func (s *Service) applyFactCheck(content string, stock *models.Stock, cfg *models.AgentConfig, progressCallback ProgressCallback) string {
+	if !s.factCheck {
+		return content
+	}
+	mismatches := checkFacts(content, stock)
+	if len(mismatches) == 0 {
+		return content
+	}
+	footnote := factCheckFootnote(mismatches)
+	log.Warn("专家 %s 发言数字与快照不符: %+v", cfg.ID, mismatches)
+	emitProgress(progressCallback, ProgressEvent{
+		Type: ProgressTypeFactCheck, AgentID: cfg.ID, AgentName: cfg.Name,
+		Detail: fmt.Sprintf("%d 处数字与行情快照不符", len(mismatches)), Content: strings.TrimSpace(footnote),
+	})
+	return content + footnote
+}
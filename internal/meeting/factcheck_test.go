@@ -0,0 +1,63 @@
+package meeting
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestCheckFacts(t *testing.T) {
+	stock := &models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500, ChangePercent: -1.2}
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"price mismatch", "贵州茅台现价 1650 元，估值偏高。", []string{"price"}},
+		{"price within tolerance", "贵州茅台最新价：¥1510，走势平稳。", nil},
+		{"code mention", "600519 当前价格为 1200.5 元", []string{"price"}},
+		{"change mismatch", "贵州茅台当前涨幅 3.5%，放量上攻", []string{"change"}},
+		{"drop wording", "贵州茅台最新跌幅 1.5%", nil},
+		{"both", "贵州茅台现价1800元；贵州茅台当前涨跌幅+2%", []string{"price", "change"}},
+		{"other sentence", "贵州茅台走势偏弱。五粮液现价 180 元", nil},
+		{"target price", "贵州茅台目标价 1800 元，支撑位 1400 元", nil},
+		{"no unit", "贵州茅台现价 1800 附近有压力", nil},
+		{"no current wording", "贵州茅台涨幅 5% 以上才算突破", nil},
+	}
+	for _, tt := range tests {
+		var kinds []string
+		for _, m := range checkFacts(tt.content, stock) {
+			kinds = append(kinds, m.Kind)
+		}
+		if strings.Join(kinds, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.name, kinds, tt.want)
+		}
+	}
+
+	index := &models.Stock{Symbol: "sh000001", Name: "上证指数", Price: 3300}
+	if got := checkFacts("上证指数现价 2800 元", index); got != nil {
+		t.Errorf("index should be skipped, got %+v", got)
+	}
+}
+
+func TestApplyFactCheck(t *testing.T) {
+	stock := &models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500, ChangePercent: 0.5}
+	cfg := &models.AgentConfig{ID: "macro", Name: "老陈"}
+	content := "贵州茅台现价 1650 元"
+
+	svc := NewServiceFull(nil, nil)
+	var events []ProgressEvent
+	got := svc.applyFactCheck(content, stock, cfg, func(e ProgressEvent) { events = append(events, e) })
+	if !strings.HasPrefix(got, content) || !strings.Contains(got, "现价 1650 元与行情快照 1500.00 元不符") {
+		t.Errorf("content = %q", got)
+	}
+	if len(events) != 1 || events[0].Type != ProgressTypeFactCheck || events[0].AgentID != "macro" {
+		t.Errorf("events = %+v", events)
+	}
+
+	svc.SetFactCheck(false)
+	if got := svc.applyFactCheck(content, stock, cfg, nil); got != content {
+		t.Errorf("disabled fact check changed content: %q", got)
+	}
+}
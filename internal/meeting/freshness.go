@@ -0,0 +1,71 @@
+package meeting
+
+import (
+	"context"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// DataFreshnessProvider 计算标的行情数据时效的函数类型
+type DataFreshnessProvider func(ctx context.Context, stock models.Stock, now time.Time) *models.DataFreshness
+
+// SetDataFreshnessProvider 设置行情数据时效提供者，未设置时不注入时效说明也不标注截至时间
+func (s *Service) SetDataFreshnessProvider(p DataFreshnessProvider) {
+	s.dataFreshness = p
+}
+
+// dataFreshnessKey 行情数据时效在 context 中的 key
+type dataFreshnessKey struct{}
+
+// withDataFreshness 计算一次本场会议的行情数据时效并放入 context，同一场会议的专家共用
+func (s *Service) withDataFreshness(ctx context.Context, stock models.Stock, now time.Time) context.Context {
+	if s.dataFreshness == nil || stock.Symbol == "" {
+		return ctx
+	}
+	return contextWithDataFreshness(ctx, s.dataFreshness(ctx, stock, now))
+}
+
+// contextWithDataFreshness 把已有的行情数据时效放入 context（恢复会议时沿用中断前的时效）
+func contextWithDataFreshness(ctx context.Context, f *models.DataFreshness) context.Context {
+	if f == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, dataFreshnessKey{}, f)
+}
+
+// dataFreshnessFrom 取 context 中的行情数据时效，没有时返回 nil
+func dataFreshnessFrom(ctx context.Context) *models.DataFreshness {
+	f, _ := ctx.Value(dataFreshnessKey{}).(*models.DataFreshness)
+	return f
+}
+
+// stampAsOf 为发言标注行情数据截至时间，失败的发言不标注
+func stampAsOf(f *models.DataFreshness, resp ChatResponse) ChatResponse {
+	if f != nil && resp.Error == "" {
+		resp.AsOf = f.AsOf
+	}
+	return resp
+}
+
+// asOfCallback 包装回调，推送前为每条发言标注截至时间
+func asOfCallback(ctx context.Context, cb ResponseCallback) ResponseCallback {
+	f := dataFreshnessFrom(ctx)
+	if cb == nil || f == nil {
+		return cb
+	}
+	return func(resp ChatResponse) {
+		cb(stampAsOf(f, resp))
+	}
+}
+
+// stampResponsesAsOf 为返回的发言列表标注截至时间
+func stampResponsesAsOf(ctx context.Context, responses []ChatResponse) {
+	f := dataFreshnessFrom(ctx)
+	if f == nil {
+		return
+	}
+	for i := range responses {
+		responses[i] = stampAsOf(f, responses[i])
+	}
+}
@@ -0,0 +1,88 @@
+package meeting
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestRunSmartMeeting_DataFreshness(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		prompts []string
+	)
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		switch {
+		case q.Analyze():
+			return ollamaReply{Content: `{"intent":"走势","selected":["a1","a2"],"topic":"走势","opening":"开始"}`}
+		case q.Summarize():
+			return ollamaReply{Content: "总结"}
+		}
+		mu.Lock()
+		prompts = append(prompts, q.First())
+		mu.Unlock()
+		return ollamaReply{Content: "观点"}
+	})
+
+	svc := NewServiceFull(nil, nil)
+	calls := 0
+	svc.SetDataFreshnessProvider(func(_ context.Context, stock models.Stock, now time.Time) *models.DataFreshness {
+		calls++
+		return &models.DataFreshness{AsOf: "2026-03-06 15:00", MarketStatus: "周末休市", Disclaimer: "数据截至 3月6日收盘"}
+	})
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := parallelRequest()
+	req.AllAgents = req.AllAgents[:2]
+
+	var pushed []ChatResponse
+	responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, func(resp ChatResponse) {
+		pushed = append(pushed, resp)
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("一场会议只计算一次数据时效, got %d", calls)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expert prompts = %d", len(prompts))
+	}
+	for _, p := range prompts {
+		if !strings.Contains(p, "## 数据时效") || !strings.Contains(p, "回复第一行必须单独写「数据截至 3月6日收盘」") {
+			t.Errorf("专家指令缺少数据时效要求:\n%s", p)
+		}
+	}
+	// 开场、专家发言与总结都标注截至时间，推送与返回一致
+	if len(pushed) != 4 || len(responses) != 4 {
+		t.Fatalf("pushed = %d, responses = %d", len(pushed), len(responses))
+	}
+	for i := range responses {
+		if pushed[i].AsOf != "2026-03-06 15:00" || responses[i].AsOf != "2026-03-06 15:00" {
+			t.Errorf("#%d asOf: pushed %q, returned %q", i, pushed[i].AsOf, responses[i].AsOf)
+		}
+	}
+}
+
+func TestStampAsOf(t *testing.T) {
+	f := &models.DataFreshness{AsOf: "2026-03-06 15:00"}
+	if got := stampAsOf(f, ChatResponse{Content: "观点"}); got.AsOf != f.AsOf {
+		t.Errorf("asOf = %q", got.AsOf)
+	}
+	// 失败的发言没有内容，不标注
+	if got := stampAsOf(f, ChatResponse{Error: "timeout"}); got.AsOf != "" {
+		t.Errorf("failed response stamped: %q", got.AsOf)
+	}
+	// 未设置提供者时不改动发言
+	if cb := asOfCallback(context.Background(), nil); cb != nil {
+		t.Error("nil callback should stay nil")
+	}
+	responses := []ChatResponse{{Content: "观点"}}
+	stampResponsesAsOf(context.Background(), responses)
+	if responses[0].AsOf != "" {
+		t.Errorf("stamped without freshness: %q", responses[0].AsOf)
+	}
+}
@@ -0,0 +1,86 @@
+package meeting
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// 图片输入限制
+const (
+	MaxMeetingImages = 4               // 单条消息最多附带的图片数
+	maxImageBytes    = 5 * 1024 * 1024 // 单张图片大小上限（Anthropic 为 5MB）
+)
+
+// ErrImageInputUnsupported 专家使用的模型不支持图片输入
+var ErrImageInputUnsupported = errors.New("模型不支持图片输入")
+
+// supportedImageTypes 各家视觉模型均支持的图片格式
+var supportedImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// decodeImages 将消息附带的图片解析为内联数据 part
+// 每项可以是 data URL（data:image/png;base64,...）、裸 base64 或本地文件路径，格式按内容识别
+func decodeImages(images []string) ([]*genai.Part, error) {
+	if len(images) > MaxMeetingImages {
+		return nil, fmt.Errorf("单条消息最多附带 %d 张图片", MaxMeetingImages)
+	}
+	parts := make([]*genai.Part, 0, len(images))
+	for i, img := range images {
+		data, err := loadImage(strings.TrimSpace(img))
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 张图片: %w", i+1, err)
+		}
+		if len(data) > maxImageBytes {
+			return nil, fmt.Errorf("第 %d 张图片超过 %dMB", i+1, maxImageBytes>>20)
+		}
+		mime := http.DetectContentType(data)
+		if !supportedImageTypes[mime] {
+			return nil, fmt.Errorf("第 %d 张图片格式不支持: %s", i+1, mime)
+		}
+		parts = append(parts, genai.NewPartFromBytes(data, mime))
+	}
+	return parts, nil
+}
+
+// loadImage 读取单张图片的原始字节
+func loadImage(img string) ([]byte, error) {
+	if img == "" {
+		return nil, errors.New("内容为空")
+	}
+	if rest, ok := strings.CutPrefix(img, "data:"); ok {
+		_, payload, found := strings.Cut(rest, ";base64,")
+		if !found {
+			return nil, errors.New("仅支持 base64 编码的 data URL")
+		}
+		return base64.StdEncoding.DecodeString(payload)
+	}
+	if info, err := os.Stat(img); err == nil && !info.IsDir() {
+		if info.Size() > maxImageBytes {
+			return nil, fmt.Errorf("超过 %dMB", maxImageBytes>>20)
+		}
+		return os.ReadFile(img)
+	}
+	data, err := base64.StdEncoding.DecodeString(img)
+	if err != nil {
+		return nil, errors.New("既不是有效的 base64 数据，也不是存在的文件路径")
+	}
+	return data, nil
+}
+
+// imageHint 附带图片时给主持人的提示（主持人只看文本，据此安排需要看图的专家）
+func imageHint(query string, n int) string {
+	if n == 0 {
+		return query
+	}
+	return fmt.Sprintf("%s\n（用户附带了 %d 张图片，专家发言时可以看到）", query, n)
+}
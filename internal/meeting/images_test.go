@@ -0,0 +1,67 @@
+package meeting
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 最小 PNG 文件头，足以让 http.DetectContentType 识别
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestDecodeImages(t *testing.T) {
+	raw := base64.StdEncoding.EncodeToString(pngHeader)
+	path := filepath.Join(t.TempDir(), "kline.png")
+	if err := os.WriteFile(path, pngHeader, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parts, err := decodeImages([]string{"data:image/png;base64," + raw, raw, path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("len(parts) = %d", len(parts))
+	}
+	for i, p := range parts {
+		if p.InlineData == nil || p.InlineData.MIMEType != "image/png" || string(p.InlineData.Data) != string(pngHeader) {
+			t.Fatalf("parts[%d] = %+v", i, p.InlineData)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		images []string
+		want   string
+	}{
+		{"empty", []string{" "}, "内容为空"},
+		{"invalid", []string{"not-an-image!"}, "既不是有效的 base64"},
+		{"url encoded", []string{"data:image/png,abc"}, "data URL"},
+		{"not image", []string{base64.StdEncoding.EncodeToString([]byte("%PDF-1.4"))}, "格式不支持"},
+		{"too many", []string{raw, raw, raw, raw, raw}, fmt.Sprintf("最多附带 %d 张", MaxMeetingImages)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeImages(tt.images)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want contains %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageInputUnsupportedNotRetryable(t *testing.T) {
+	err := fmt.Errorf("技术分析师 使用的 DeepSeek（deepseek-chat）: %w", ErrImageInputUnsupported)
+	if isRetryableError(err) {
+		t.Fatal("模型不支持图片输入不应重试")
+	}
+	if got := imageHint("分析走势", 0); got != "分析走势" {
+		t.Fatalf("imageHint = %q", got)
+	}
+	if got := imageHint("分析走势", 2); !strings.Contains(got, "2 张图片") {
+		t.Fatalf("imageHint = %q", got)
+	}
+}
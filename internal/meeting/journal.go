@@ -0,0 +1,29 @@
+package meeting
+
+import (
+	"context"
+	"time"
+)
+
+// actionExtractTimeout 从用户消息提取实际操作的超时
+const actionExtractTimeout = 30 * time.Second
+
+// recordUserActions 后台提取用户消息中陈述的实际操作（买入/卖出/持有），记入操作日志
+// 与专家发言并行，不阻塞会议
+func (s *Service) recordUserActions(stockCode, query string) {
+	if s.memoryManager == nil || query == "" {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), actionExtractTimeout)
+		defer cancel()
+		entries, err := s.memoryManager.RecordActions(ctx, stockCode, query)
+		if err != nil {
+			log.Warn("record user actions for %s error: %v", stockCode, err)
+			return
+		}
+		if len(entries) > 0 {
+			log.Info("recorded %d user actions for %s", len(entries), stockCode)
+		}
+	}()
+}
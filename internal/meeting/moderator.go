@@ -15,12 +15,18 @@ import (
 
 // Moderator 小韭菜 Agent
 type Moderator struct {
-	llm model.LLM
+	llm        model.LLM
+	visibility models.MeetingVisibility // 本次会议专家间发言可见性
 }
 
 // NewModerator 创建小韭菜
 func NewModerator(llm model.LLM) *Moderator {
-	return &Moderator{llm: llm}
+	return &Moderator{llm: llm, visibility: models.MeetingVisibilitySequential}
+}
+
+// SetVisibility 设置本次会议的发言可见性（写入规划与总结 Prompt）
+func (m *Moderator) SetVisibility(v models.MeetingVisibility) {
+	m.visibility = normalizeVisibility(v)
 }
 
 // ModeratorDecision 小韭菜决策结果
@@ -39,6 +45,7 @@ type DiscussionEntry struct {
 	AgentName string `json:"agentName"`
 	Role      string `json:"role"`
 	Content   string `json:"content"`
+	Target    string `json:"target,omitempty"` // 反驳对象名称（辩论反驳轮）
 }
 
 // Analyze 分析用户意图并选择专家
@@ -57,13 +64,83 @@ func (m *Moderator) Summarize(ctx context.Context, stock *models.Stock, query st
 	return m.generate(ctx, prompt)
 }
 
-// generate 调用 LLM 生成内容
-func (m *Moderator) generate(ctx context.Context, prompt string) (string, error) {
-	req := &model.LLMRequest{
+// SummarizeStream 流式总结，onDelta 为每个正文增量片段回调
+// onDelta 为 nil 时等同于 Summarize
+func (m *Moderator) SummarizeStream(ctx context.Context, stock *models.Stock, query string, history []DiscussionEntry, onDelta func(text string)) (string, error) {
+	prompt := m.buildSummarizePrompt(stock, query, history)
+	if onDelta == nil {
+		return m.generate(ctx, prompt)
+	}
+	return m.generateStream(ctx, prompt, onDelta)
+}
+
+// moderatorDeltaFunc 将小韭菜的流式片段转换为进度事件
+func moderatorDeltaFunc(cb ProgressCallback) func(string) {
+	if cb == nil {
+		return nil
+	}
+	return func(text string) {
+		cb(newDeltaEvent("moderator", "小韭菜", text, false))
+	}
+}
+
+// newPromptRequest 构造单轮 Prompt 请求
+func newPromptRequest(prompt string) *model.LLMRequest {
+	return &model.LLMRequest{
 		Contents: []*genai.Content{
 			{Role: "user", Parts: []*genai.Part{genai.NewPartFromText(prompt)}},
 		},
 	}
+}
+
+// generateStream 流式调用 LLM，只累积 Partial 片段，最终聚合响应仅在没有增量时兜底
+func (m *Moderator) generateStream(ctx context.Context, prompt string, onDelta func(text string)) (string, error) {
+	text, _, err := streamPrompt(ctx, m.llm, prompt, onDelta)
+	return text, err
+}
+
+// streamPrompt 以单轮 Prompt 流式调用 llm，返回正文与最后一次完整响应的用量
+// 只累积 Partial 片段，最终聚合响应仅在没有增量时兜底
+func streamPrompt(ctx context.Context, llm model.LLM, prompt string, onDelta func(text string)) (string, *genai.GenerateContentResponseUsageMetadata, error) {
+	var partial, final strings.Builder
+	var usage *genai.GenerateContentResponseUsageMetadata
+	for resp, err := range llm.GenerateContent(ctx, newPromptRequest(prompt), true) {
+		if err != nil {
+			return "", nil, err
+		}
+		if resp == nil {
+			continue
+		}
+		if resp.UsageMetadata != nil && !resp.Partial {
+			usage = resp.UsageMetadata
+		}
+		if resp.Content == nil {
+			continue
+		}
+		for _, part := range resp.Content.Parts {
+			if part.Thought || part.Text == "" {
+				continue
+			}
+			if resp.Partial {
+				partial.WriteString(part.Text)
+				if onDelta != nil {
+					onDelta(part.Text)
+				}
+			} else {
+				final.WriteString(part.Text)
+			}
+		}
+	}
+	result := partial.String()
+	if result == "" {
+		result = final.String()
+	}
+	return openai.FilterVendorToolCallMarkers(result), usage, nil
+}
+
+// generate 调用 LLM 生成内容
+func (m *Moderator) generate(ctx context.Context, prompt string) (string, error) {
+	req := newPromptRequest(prompt)
 
 	var result strings.Builder
 	for resp, err := range m.llm.GenerateContent(ctx, req, false) {
@@ -89,14 +166,22 @@ func (m *Moderator) generate(ctx context.Context, prompt string) (string, error)
 func (m *Moderator) buildAnalyzePrompt(stock *models.Stock, query string, agents []models.AgentConfig) string {
 	var sb strings.Builder
 	sb.WriteString("你是「财经会议室」的小韭菜，负责组织专家讨论。\n\n")
-	sb.WriteString("## 当前股票\n")
-	fmt.Fprintf(&sb, "%s (%s)，现价 %.2f，涨跌幅 %.2f%%\n\n", stock.Name, stock.Symbol, stock.Price, stock.ChangePercent)
+	if models.IsIndexSymbol(stock.Symbol) {
+		sb.WriteString("## 当前指数\n")
+		fmt.Fprintf(&sb, "%s (%s)，点位 %.2f，涨跌幅 %.2f%%\n", stock.Name, stock.Symbol, stock.Price, stock.ChangePercent)
+		sb.WriteString("标的为指数而非个股：请围绕大盘趋势、市场情绪、板块轮动拆解任务，不要安排盘口、龙虎榜、持仓相关分析。\n\n")
+	} else {
+		sb.WriteString("## 当前股票\n")
+		fmt.Fprintf(&sb, "%s (%s)，现价 %.2f，涨跌幅 %.2f%%\n\n", stock.Name, stock.Symbol, stock.Price, stock.ChangePercent)
+	}
 	sb.WriteString("## 老韭菜问题\n")
 	sb.WriteString(query + "\n\n")
 	sb.WriteString("## 可邀请的专家\n")
 	for _, a := range agents {
 		fmt.Fprintf(&sb, "- %s（ID: %s）：%s\n", a.Name, a.ID, a.Role)
 	}
+	sb.WriteString("\n## 讨论形式\n")
+	sb.WriteString(visibilityLabel(m.visibility) + "\n")
 	sb.WriteString("\n## 你的任务\n")
 	sb.WriteString("1. 分析老韭菜问题的核心意图\n")
 	sb.WriteString(fmt.Sprintf("2. 除非用户特别约束专家数量,否则选择 1-%d 位最相关的专家\n", len(agents)))
@@ -111,16 +196,29 @@ func (m *Moderator) buildAnalyzePrompt(stock *models.Stock, query string, agents
 func (m *Moderator) buildSummarizePrompt(stock *models.Stock, query string, history []DiscussionEntry) string {
 	var sb strings.Builder
 	sb.WriteString("你是会议小韭菜，请总结讨论并给老韭菜结论。\n\n")
-	fmt.Fprintf(&sb, "## 股票：%s (%s)\n\n", stock.Name, stock.Symbol)
+	subject := "股票"
+	if models.IsIndexSymbol(stock.Symbol) {
+		subject = "指数"
+	}
+	fmt.Fprintf(&sb, "## %s：%s (%s)\n\n", subject, stock.Name, stock.Symbol)
 	sb.WriteString("## 老韭菜问题\n")
 	sb.WriteString(query + "\n\n")
+	sb.WriteString("## 讨论形式\n")
+	sb.WriteString(visibilityLabel(m.visibility) + "\n\n")
 	sb.WriteString("## 讨论记录\n")
 	for _, e := range history {
 		fmt.Fprintf(&sb, "【%s（%s）】\n%s\n\n", e.AgentName, e.Role, e.Content)
 	}
 	sb.WriteString("## 输出要求\n")
 	sb.WriteString("1. 核心结论（直接回答老韭菜）\n")
-	sb.WriteString("2. 各方观点摘要\n")
+	switch m.visibility {
+	case models.MeetingVisibilityIndependent:
+		sb.WriteString("2. 各方观点摘要（专家独立发言，注意指出观点的一致与分歧）\n")
+	case models.MeetingVisibilityDebate:
+		sb.WriteString("2. 各方观点摘要（梳理专家之间的赞同与反驳，说明争议焦点）\n")
+	default:
+		sb.WriteString("2. 各方观点摘要\n")
+	}
 	sb.WriteString("3. 综合建议\n\n")
 	sb.WriteString("控制在 300 字以内。")
 	return sb.String()
@@ -0,0 +1,115 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ollamaRequest 模拟 Ollama 收到的 /api/chat 请求
+type ollamaRequest struct {
+	Model    string `json:"model"`
+	Messages []struct {
+		Role      string            `json:"role"`
+		Content   string            `json:"content"`
+		ToolCalls []json.RawMessage `json:"tool_calls"`
+	} `json:"messages"`
+	Tools   []json.RawMessage `json:"tools"`
+	Options *struct {
+		Temperature *float64 `json:"temperature"`
+		NumPredict  int      `json:"num_predict"`
+	} `json:"options"`
+
+	ctx context.Context
+}
+
+// First 第一条消息，即系统指令
+func (q *ollamaRequest) First() string { return q.Messages[0].Content }
+
+// Last 最后一条消息
+func (q *ollamaRequest) Last() string { return q.Messages[len(q.Messages)-1].Content }
+
+// Text 全部消息拼接后的文本
+func (q *ollamaRequest) Text() string {
+	var all strings.Builder
+	for _, m := range q.Messages {
+		all.WriteString(m.Content)
+	}
+	return all.String()
+}
+
+// Analyze 是否为小韭菜的意图分析请求
+func (q *ollamaRequest) Analyze() bool {
+	return strings.Contains(q.First(), "负责组织专家讨论")
+}
+
+// Summarize 是否为小韭菜的总结请求
+func (q *ollamaRequest) Summarize() bool { return strings.Contains(q.First(), "请总结讨论") }
+
+// ToolNames 请求声明的工具名
+func (q *ollamaRequest) ToolNames() []string {
+	var names []string
+	for _, raw := range q.Tools {
+		var tool struct {
+			Function struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		}
+		json.Unmarshal(raw, &tool)
+		names = append(names, tool.Function.Name)
+	}
+	return names
+}
+
+// Context 请求的 context，客户端取消时结束
+func (q *ollamaRequest) Context() context.Context { return q.ctx }
+
+// ollamaReply 模拟 Ollama 的回复
+type ollamaReply struct {
+	Content    string
+	ToolCalls  []map[string]any
+	DoneReason string
+	PromptEval int  // prompt_eval_count
+	Eval       int  // eval_count
+	Status     int  // 非 0 时返回该状态码的错误
+	Drop       bool // 不写回复，用于请求已取消的场景
+}
+
+// ollamaMockServer 基于 Ollama /api/chat 协议的模拟服务，每个请求交给 handle 生成回复
+// 服务在测试结束时关闭
+func ollamaMockServer(t testing.TB, handle func(q *ollamaRequest) ollamaReply) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := &ollamaRequest{ctx: r.Context()}
+		if err := json.NewDecoder(r.Body).Decode(q); err != nil || len(q.Messages) == 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		reply := handle(q)
+		if reply.Drop {
+			return
+		}
+		if reply.Status != 0 {
+			http.Error(w, "boom", reply.Status)
+			return
+		}
+
+		msg := map[string]any{"role": "assistant", "content": reply.Content}
+		if reply.ToolCalls != nil {
+			msg["tool_calls"] = reply.ToolCalls
+		}
+		chunk := map[string]any{"message": msg, "done": true}
+		if reply.DoneReason != "" {
+			chunk["done_reason"] = reply.DoneReason
+		}
+		if reply.PromptEval != 0 || reply.Eval != 0 {
+			chunk["prompt_eval_count"] = reply.PromptEval
+			chunk["eval_count"] = reply.Eval
+		}
+		json.NewEncoder(w).Encode(chunk)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
@@ -0,0 +1,177 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"runtime"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/genai"
+)
+
+// MaxParallelAgents 并行首轮同时发言的专家上限
+const MaxParallelAgents = 3
+
+// parallelLimit 并行首轮同时发言的专家上限：并发模式为 CPU 核数，否则为 MaxParallelAgents
+func parallelLimit(req ChatRequest) int {
+	if req.ConcurrentMode {
+		return runtime.NumCPU()
+	}
+	return MaxParallelAgents
+}
+
+// runFirstRoundParallel 首轮专家并行发言（互不参考，仅注入记忆上下文）
+// 同时运行的专家不超过 parallelLimit；每位专家完成即回调，返回的响应与历史按专家顺序排列，保证保存的消息顺序确定
+// 单个专家失败只记录错误响应，不中断其他专家
+func (s *Service) runFirstRoundParallel(
+	ctx context.Context,
+	agentModels *agentModels,
+	req ChatRequest,
+	images []*genai.Part,
+	agents []models.AgentConfig,
+	tasks map[string]string,
+	memoryContext string,
+	respCallback ResponseCallback,
+	progressCallback ProgressCallback,
+) ([]ChatResponse, []DiscussionEntry) {
+	type result struct {
+		index int
+		resp  *ChatResponse // nil 表示该专家被跳过（模型创建失败）
+	}
+	limit := parallelLimit(req)
+	sem := make(chan struct{}, limit)
+	results := make(chan result, len(agents))
+
+	log.Debug("running %d agents in parallel, limit %d", len(agents), limit)
+
+	for i := range agents {
+		go func(i int, cfg models.AgentConfig) {
+			var resp *ChatResponse
+			select {
+			case sem <- struct{}{}:
+				resp, _ = s.runOpinionAgent(ctx, agentModels, req, images, &cfg, tasks, memoryContext, progressCallback)
+				<-sem
+			case <-ctx.Done():
+				resp = failedOpinion(&cfg, ctx.Err())
+			}
+			results <- result{index: i, resp: resp}
+		}(i, agents[i])
+	}
+
+	// 按完成顺序回调，前端即时展示先完成的专家
+	slots := make([]*ChatResponse, len(agents))
+	for range agents {
+		r := <-results
+		slots[r.index] = r.resp
+		if r.resp != nil && respCallback != nil && !cancelledResp(ctx, r.resp) {
+			respCallback(*r.resp)
+		}
+	}
+
+	var (
+		responses []ChatResponse
+		history   []DiscussionEntry
+	)
+	for _, resp := range slots {
+		if resp == nil || cancelledResp(ctx, resp) {
+			continue
+		}
+		responses = append(responses, *resp)
+		if resp.Error == "" {
+			// 仅正文进入历史，思考内容不回注总结上下文
+			history = append(history, DiscussionEntry{
+				Round:     1,
+				AgentID:   resp.AgentID,
+				AgentName: resp.AgentName,
+				Role:      resp.Role,
+				Content:   resp.Content,
+			})
+		}
+	}
+	log.Info("parallel round done, %d/%d agents succeeded", len(history), len(agents))
+	return responses, history
+}
+
+// runOpinionAgent 运行首轮单个专家，串行与并行首轮共用
+// peerContext 为注入的记忆与前序发言上下文；模型创建失败时返回 nil
+// 发言失败时返回带 Error 的响应及错误，由调用方决定是否中断会议
+func (s *Service) runOpinionAgent(
+	ctx context.Context,
+	agentModels *agentModels,
+	req ChatRequest,
+	images []*genai.Part,
+	agentCfg *models.AgentConfig,
+	tasks map[string]string,
+	peerContext string,
+	progressCallback ProgressCallback,
+) (*ChatResponse, error) {
+	agentLLM, agentAIConfig, err := agentModels.forAgent(ctx, agentCfg)
+	if err != nil {
+		log.Error("create agent LLM error: %v", err)
+		return nil, err
+	}
+	builder := s.createBuilder(ctx, agentLLM, agentAIConfig)
+
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
+		Model: modelLabel(agentAIConfig),
+	})
+	defer emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name})
+
+	// 获取主持人为该专家分配的专属任务，若无则降级为用户原始问题
+	agentQuery := req.Query
+	if task := tasks[agentCfg.ID]; task != "" {
+		agentQuery = task
+	}
+
+	// 带超时控制 + 指数退避重试
+	out, err := retryRun(ctx, MaxAgentRetries, func() (agentOutput, error) {
+		return runAgentWithTimeout(ctx, agentTimeout(builder.AIConfig(), req.AgentTimeout), func(agentCtx context.Context) (agentOutput, error) {
+			return s.runSingleAgent(agentCtx, builder, agentCfg, &req.Stock, agentQuery, images, peerContext, progressCallback, req.Position)
+		})
+	})
+	if err != nil {
+		// 用户取消不算失败，恢复会议时重新发言
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			emitProgress(progressCallback, ProgressEvent{Type: "agent_error", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: err.Error()})
+		}
+		log.Error("agent %s failed after retries: %v", agentCfg.ID, err)
+		return failedOpinion(agentCfg, err), err
+	}
+
+	log.Debug("agent %s done, content len: %d", agentCfg.ID, len(out.Content))
+	return &ChatResponse{
+		AgentID:          agentCfg.ID,
+		AgentName:        agentCfg.Name,
+		Role:             agentCfg.Role,
+		Content:          out.Content,
+		Round:            1,
+		MsgType:          "opinion",
+		MeetingMode:      MeetingModeSmart,
+		Reasoning:        applyThinkingPersist(s.thinkingPersist, out.Reasoning),
+		ToolCalls:        out.ToolCalls,
+		ToolResults:      out.ToolResults,
+		FinishReason:     out.FinishReason,
+		PromptTokens:     out.InputTokens,
+		CompletionTokens: out.OutputTokens,
+	}, nil
+}
+
+// failedOpinion 首轮发言失败的响应
+func failedOpinion(agentCfg *models.AgentConfig, err error) *ChatResponse {
+	return &ChatResponse{
+		AgentID:     agentCfg.ID,
+		AgentName:   agentCfg.Name,
+		Role:        agentCfg.Role,
+		Round:       1,
+		MsgType:     "opinion",
+		Error:       err.Error(),
+		MeetingMode: MeetingModeSmart,
+	}
+}
+
+// cancelledResp 用户取消导致的失败发言，不作为失败消息展示（恢复会议时重新发言）
+func cancelledResp(ctx context.Context, resp *ChatResponse) bool {
+	return resp.Error != "" && errors.Is(ctx.Err(), context.Canceled)
+}
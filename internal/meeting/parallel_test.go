@@ -0,0 +1,356 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// parallelMockServer 模拟 Ollama：主持人选中全部专家，专家按 delays 中的时长返回
+// block 非空时专家请求一直阻塞到它被关闭或请求取消
+func parallelMockServer(t testing.TB, delays map[string]time.Duration, block chan struct{}) (*httptest.Server, *concurrencyGauge) {
+	gauge := &concurrencyGauge{}
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		switch {
+		case q.Analyze():
+			return ollamaReply{Content: `{"intent":"走势","selected":["a1","a2","a3","a4"],"tasks":{},"topic":"走势","opening":"开始"}`}
+		case q.Summarize():
+			return ollamaReply{Content: "总结"}
+		}
+		gauge.inc()
+		defer gauge.dec()
+		first := q.First()
+		reply := "观点"
+		for id, d := range delays {
+			if strings.Contains(first, "指令-"+id) {
+				reply = "观点-" + id
+				select {
+				case <-time.After(d):
+				case <-q.Context().Done():
+					return ollamaReply{Drop: true}
+				}
+			}
+		}
+		if block != nil {
+			select {
+			case <-block:
+			case <-q.Context().Done():
+				return ollamaReply{Drop: true}
+			}
+		}
+		return ollamaReply{Content: reply}
+	})
+	return srv, gauge
+}
+
+// concurrencyGauge 记录同时进行中的专家请求数及其峰值
+type concurrencyGauge struct {
+	mu        sync.Mutex
+	cur, peak int
+}
+
+func (g *concurrencyGauge) inc() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cur++
+	g.peak = max(g.peak, g.cur)
+}
+
+func (g *concurrencyGauge) dec() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cur--
+}
+
+func parallelRequest() ChatRequest {
+	var agents []models.AgentConfig
+	for _, id := range []string{"a1", "a2", "a3", "a4"} {
+		agents = append(agents, models.AgentConfig{ID: id, Name: "专家" + id, Role: "分析", Instruction: "指令-" + id, Enabled: true})
+	}
+	return ChatRequest{
+		StockCode:      "sh600519",
+		Stock:          models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500},
+		Query:          "后市怎么看",
+		AllAgents:      agents,
+		ParallelRounds: true,
+	}
+}
+
+func TestRunSmartMeeting_ParallelRounds(t *testing.T) {
+	// 先选中的专家更慢，完成顺序与专家顺序相反
+	srv, gauge := parallelMockServer(t, map[string]time.Duration{
+		"a1": 300 * time.Millisecond, "a2": 200 * time.Millisecond, "a3": 100 * time.Millisecond, "a4": 0,
+	}, nil)
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+
+	var callbacks []string
+	responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, parallelRequest(), func(resp ChatResponse) {
+		callbacks = append(callbacks, resp.AgentID)
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 回调按完成顺序（a3、a4 谁先取得并发名额不确定），返回（保存）的响应按专家顺序
+	if got := strings.Join(callbacks, ","); got != "moderator,a4,a3,a2,a1,moderator" && got != "moderator,a3,a4,a2,a1,moderator" {
+		t.Fatalf("callbacks = %s, want completion order", got)
+	}
+	want := "moderator,a1,a2,a3,a4,moderator"
+	var ids []string
+	for _, r := range responses {
+		if r.Error != "" {
+			t.Fatalf("agent %s failed: %s", r.AgentID, r.Error)
+		}
+		ids = append(ids, r.AgentID)
+	}
+	if got := strings.Join(ids, ","); got != want {
+		t.Fatalf("responses = %s, want %s", got, want)
+	}
+	if responses[1].Content != "观点-a1" || responses[len(responses)-1].MsgType != "summary" {
+		t.Fatalf("responses = %+v", responses)
+	}
+	if gauge.peak < 2 || gauge.peak > MaxParallelAgents {
+		t.Fatalf("peak concurrency = %d, want 2..%d", gauge.peak, MaxParallelAgents)
+	}
+}
+
+func TestRunSmartMeeting_ParallelCancel(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	srv, gauge := parallelMockServer(t, nil, block)
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// 等专家开始发言后取消
+		for {
+			gauge.mu.Lock()
+			started := gauge.cur > 0
+			gauge.mu.Unlock()
+			if started {
+				cancel()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	done := make(chan struct{})
+	var err error
+	go func() {
+		_, err = svc.RunSmartMeeting(ctx, aiConfig, parallelRequest())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("取消后会议未及时结束")
+	}
+	if !errors.Is(err, ErrMeetingTimeout) {
+		t.Fatalf("err = %v, want ErrMeetingTimeout", err)
+	}
+}
+
+// 并发模式会议取消后恢复，剩余专家仍按并发模式的上限同时发言
+func TestContinueMeeting_ConcurrentMode(t *testing.T) {
+	delays := map[string]time.Duration{}
+	for _, id := range []string{"a1", "a2", "a3", "a4"} {
+		delays[id] = 100 * time.Millisecond
+	}
+	srv, gauge := parallelMockServer(t, delays, nil)
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := parallelRequest()
+	req.ParallelRounds = false
+	req.ConcurrentMode = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for {
+			gauge.mu.Lock()
+			started := gauge.cur > 0
+			gauge.mu.Unlock()
+			if started {
+				cancel()
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	if _, err := svc.RunSmartMeeting(ctx, aiConfig, req); !errors.Is(err, ErrMeetingTimeout) {
+		t.Fatalf("err = %v, want ErrMeetingTimeout", err)
+	}
+	svc.meetingStatesMu.RLock()
+	state := svc.meetingStates[req.StockCode]
+	svc.meetingStatesMu.RUnlock()
+	if state == nil || !state.Parallel || !state.ConcurrentMode {
+		t.Fatalf("state = %+v, 应记录并发模式", state)
+	}
+
+	// 等取消的请求退出后重新统计峰值
+	for {
+		gauge.mu.Lock()
+		idle := gauge.cur == 0
+		if idle {
+			gauge.peak = 0
+		}
+		gauge.mu.Unlock()
+		if idle {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	responses, err := svc.ContinueMeeting(context.Background(), req.StockCode, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range responses {
+		if r.MsgType == "opinion" {
+			ids = append(ids, r.AgentID)
+		}
+	}
+	if got := strings.Join(ids, ","); got != "a1,a2,a3,a4" || responses[len(responses)-1].MsgType != "summary" {
+		t.Fatalf("opinions = %s, responses = %+v", got, responses)
+	}
+	if want := min(4, runtime.NumCPU()); gauge.peak != want {
+		t.Fatalf("resumed peak concurrency = %d, want %d", gauge.peak, want)
+	}
+}
+
+func TestRunSmartMeeting_AgentTimeout(t *testing.T) {
+	srv, _ := parallelMockServer(t, map[string]time.Duration{"a2": 5 * time.Second}, nil)
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := parallelRequest()
+	req.ParallelRounds = false
+	req.AgentTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("专家超时未生效，耗时 %s", elapsed)
+	}
+
+	// 超时的专家生成失败消息，串行会议在此中断
+	last := responses[len(responses)-1]
+	if last.AgentID != "a2" || !strings.Contains(last.Error, ErrAgentTimeout.Error()) {
+		t.Fatalf("last response = %+v", last)
+	}
+	if !svc.HasInterruptedMeeting(req.StockCode) {
+		t.Error("专家超时后应保留会议进度以便继续")
+	}
+}
+
+func TestRetrySingleAgent_AgentTimeout(t *testing.T) {
+	srv, _ := parallelMockServer(t, map[string]time.Duration{"a2": 5 * time.Second}, nil)
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := parallelRequest()
+
+	start := time.Now()
+	resp, err := svc.RetrySingleAgent(context.Background(), aiConfig, &req.AllAgents[1], &req.Stock, req.Query, nil, nil, 100*time.Millisecond)
+	if !errors.Is(err, ErrAgentTimeout) || resp.Error == "" {
+		t.Fatalf("resp = %+v, err = %v", resp, err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("重试未使用策略超时，耗时 %s", elapsed)
+	}
+}
+
+// 模型退避重试的等待不占用专家超时，超时短于退避时间时重试仍能完成
+func TestRetrySingleAgent_RetryBackoffExtendsTimeout(t *testing.T) {
+	var calls atomic.Int32
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		if calls.Add(1) == 1 {
+			return ollamaReply{Status: http.StatusServiceUnavailable}
+		}
+		return ollamaReply{Content: "重试后的观点"}
+	})
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock", RetryBaseDelayMs: 400}
+	req := parallelRequest()
+
+	resp, err := svc.RetrySingleAgent(context.Background(), aiConfig, &req.AllAgents[0], &req.Stock, req.Query, nil, nil, 100*time.Millisecond)
+	if err != nil || !strings.Contains(resp.Content, "重试后的观点") || calls.Load() != 2 {
+		t.Fatalf("resp = %+v, err = %v, calls = %d", resp, err, calls.Load())
+	}
+}
+
+func TestAgentTimeoutFrom(t *testing.T) {
+	aiConfig := &models.AIConfig{}
+	if got := agentTimeoutFrom(context.Background(), aiConfig); got != agentTimeout(aiConfig, 0) {
+		t.Errorf("default = %s", got)
+	}
+	// MCP 长耗时工具按专家实际超时延长截止时间
+	var got time.Duration
+	runAgentWithTimeout(context.Background(), 42*time.Second, func(ctx context.Context) (agentOutput, error) {
+		got = agentTimeoutFrom(ctx, aiConfig)
+		return agentOutput{}, nil
+	})
+	if got != 42*time.Second {
+		t.Errorf("configured = %s", got)
+	}
+}
+
+func TestParallelLimit(t *testing.T) {
+	req := parallelRequest()
+	if got := parallelLimit(req); got != MaxParallelAgents {
+		t.Errorf("parallel rounds limit = %d, want %d", got, MaxParallelAgents)
+	}
+	req.ConcurrentMode = true
+	if got := parallelLimit(req); got != runtime.NumCPU() {
+		t.Errorf("concurrent mode limit = %d, want %d", got, runtime.NumCPU())
+	}
+}
+
+// BenchmarkRunSmartMeeting_ConcurrentMode 对比串行与并发模式下一场会议的耗时，每位专家模拟 20ms 的模型延迟
+// 并发上限为 CPU 核数，单核机器上两者耗时相同
+func BenchmarkRunSmartMeeting_ConcurrentMode(b *testing.B) {
+	delays := map[string]time.Duration{}
+	for _, id := range []string{"a1", "a2", "a3", "a4"} {
+		delays[id] = 20 * time.Millisecond
+	}
+	srv, _ := parallelMockServer(b, delays, nil)
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+
+	run := func(b *testing.B, concurrent bool) time.Duration {
+		req := parallelRequest()
+		req.ParallelRounds = false
+		req.ConcurrentMode = concurrent
+		start := time.Now()
+		for i := 0; i < b.N; i++ {
+			if _, err := svc.RunSmartMeeting(context.Background(), aiConfig, req); err != nil {
+				b.Fatal(err)
+			}
+		}
+		return time.Since(start)
+	}
+
+	var sequential time.Duration
+	b.Run("sequential", func(b *testing.B) {
+		sequential = run(b, false) / time.Duration(b.N)
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		elapsed := run(b, true) / time.Duration(b.N)
+		if sequential > 0 && elapsed > 0 {
+			b.ReportMetric(float64(sequential)/float64(elapsed), "speedup")
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package meeting
+
+import (
+	"context"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk/capability"
+	"github.com/run-bigpig/jcp/internal/adk/tools"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// MeetingRunRecorder 会议运行记录保存函数类型，每场会议结束时调用
+type MeetingRunRecorder func(stockCode string, run models.MeetingRun)
+
+// SetReproducibility 设置复现模式使用的温度与随机种子
+func (s *Service) SetReproducibility(cfg models.Reproducibility) {
+	s.reproducibility = cfg
+}
+
+// SetMeetingRunRecorder 设置会议运行记录保存函数
+func (s *Service) SetMeetingRunRecorder(recorder MeetingRunRecorder) {
+	s.runRecorder = recorder
+}
+
+// meetingRunKey 会议运行记录在 context 中的 key
+type meetingRunKey struct{}
+
+// moderatorRunAgent 运行记录中小韭菜的身份
+var moderatorRunAgent = &models.AgentConfig{ID: "moderator", Name: "小韭菜"}
+
+// meetingRun 一场会议进行中的运行记录，各发言者创建模型时登记实际生效的参数
+type meetingRun struct {
+	defaultCfg   *models.AIConfig // 会议配置，发言者未指定配置时登记此配置
+	reproducible bool
+	temperature  float32
+	seed         int32
+	startedAt    time.Time
+
+	mu  sync.Mutex
+	run models.MeetingRun
+}
+
+// beginMeetingRun 开始记录一场会议，返回携带运行记录（及行情数据时效）的 context 与结束时调用的 finish
+// 复现模式下种子取请求值，其次取配置值，都为 0 时随机生成并记录，便于按同一种子重跑
+func (s *Service) beginMeetingRun(ctx context.Context, aiConfig *models.AIConfig, runID, mode string, req ChatRequest) (context.Context, func()) {
+	startedAt := time.Now()
+	r := &meetingRun{defaultCfg: aiConfig, reproducible: req.Reproducible, startedAt: startedAt}
+	r.run = models.MeetingRun{
+		ID:           runID,
+		Mode:         mode,
+		Query:        req.Query,
+		StartedAt:    startedAt.UnixMilli(),
+		Reproducible: req.Reproducible,
+		Agents:       []models.MeetingRunAgent{},
+	}
+	if req.Reproducible {
+		seed := req.Seed
+		if seed == 0 {
+			seed = s.reproducibility.Seed
+		}
+		if seed == 0 {
+			seed = rand.Int32N(math.MaxInt32) + 1
+		}
+		r.temperature = float32(s.reproducibility.Temperature)
+		r.seed = seed
+		r.run.Seed = seed
+		log.Info("reproducible meeting %s: temperature %.2f, seed %d", runID, r.temperature, seed)
+	}
+	ctx = s.withDataFreshness(ctx, req.Stock, startedAt)
+	ctx = tools.WithWebSearchBudget(ctx)
+	return context.WithValue(ctx, meetingRunKey{}, r), func() { s.finishMeetingRun(req.Stock.Symbol, r) }
+}
+
+// finishMeetingRun 保存运行记录，没有发言者登记（如会议开始前即失败）时不保存
+func (s *Service) finishMeetingRun(stockCode string, r *meetingRun) {
+	r.mu.Lock()
+	run := r.run
+	run.Agents = append([]models.MeetingRunAgent(nil), r.run.Agents...)
+	r.mu.Unlock()
+	if s.runRecorder == nil || stockCode == "" || len(run.Agents) == 0 {
+		return
+	}
+	run.FinishedAt = time.Now().UnixMilli()
+	s.runRecorder(stockCode, run)
+}
+
+// meetingRunFrom 取 context 中的运行记录，不在会议中时返回 nil
+func meetingRunFrom(ctx context.Context) *meetingRun {
+	r, _ := ctx.Value(meetingRunKey{}).(*meetingRun)
+	return r
+}
+
+// fixedTime 复现模式下专家系统指令使用的当前时间（会议开始时间），否则为零值
+func (r *meetingRun) fixedTime() time.Time {
+	if r == nil || !r.reproducible {
+		return time.Time{}
+	}
+	return r.startedAt
+}
+
+// bind 登记发言者实际生效的参数，复现模式下返回强制温度与种子的模型
+// cfg 为 nil 表示使用会议配置；同一发言者重试或多轮发言时只登记第一次
+func (r *meetingRun) bind(agentCfg *models.AgentConfig, cfg *models.AIConfig, llm model.LLM) model.LLM {
+	if r == nil {
+		return llm
+	}
+	if cfg == nil {
+		cfg = r.defaultCfg
+	}
+	if cfg == nil || llm == nil {
+		return llm
+	}
+	entry := models.MeetingRunAgent{
+		AgentID:     agentCfg.ID,
+		AgentName:   agentCfg.Name,
+		AIConfigID:  cfg.ID,
+		Provider:    cfg.Provider,
+		ModelName:   cfg.ModelName,
+		Temperature: cfg.Temperature,
+		MaxTokens:   cfg.MaxTokens,
+	}
+	if limit := agentCfg.MaxTokensPerAgent; limit > 0 && (entry.MaxTokens == 0 || limit < entry.MaxTokens) {
+		entry.MaxTokens = limit
+	}
+	if r.reproducible {
+		entry.Temperature = float64(r.temperature)
+		entry.SeedSupported = capability.SupportsSeed(cfg)
+	}
+
+	r.mu.Lock()
+	known := false
+	for _, a := range r.run.Agents {
+		if a.AgentID == entry.AgentID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		r.run.Agents = append(r.run.Agents, entry)
+	}
+	r.mu.Unlock()
+
+	if !r.reproducible {
+		return llm
+	}
+	if !known && !entry.SeedSupported {
+		log.Info("agent %s model %s does not support seed, reproducible run uses temperature only", agentCfg.ID, cfg.ModelName)
+	}
+	return &reproducibleLLM{LLM: llm, temperature: r.temperature, seed: r.seed}
+}
+
+// reproducibleLLM 复现模式的模型包装，每次请求强制使用统一的温度与随机种子
+// 不支持种子的模型由能力矩阵在请求前丢弃该参数
+type reproducibleLLM struct {
+	model.LLM
+	temperature float32
+	seed        int32
+}
+
+// GenerateContent 覆盖请求的温度与种子后调用底层模型
+// 请求配置可能被多位专家共用，复制后再修改
+func (m *reproducibleLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	if req != nil {
+		var cfg genai.GenerateContentConfig
+		if req.Config != nil {
+			cfg = *req.Config
+		}
+		temperature, seed := m.temperature, m.seed
+		cfg.Temperature = &temperature
+		cfg.Seed = &seed
+		pinned := *req
+		pinned.Config = &cfg
+		req = &pinned
+	}
+	return m.LLM.GenerateContent(ctx, req, stream)
+}
@@ -0,0 +1,138 @@
+package meeting
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+)
+
+func TestRunSmartMeeting_Reproducible(t *testing.T) {
+	llm := &mockLLM{}
+	var mu sync.Mutex
+	var temperatures []float64
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		mu.Lock()
+		if q.Options != nil && q.Options.Temperature != nil {
+			temperatures = append(temperatures, *q.Options.Temperature)
+		} else {
+			temperatures = append(temperatures, -1)
+		}
+		mu.Unlock()
+		return llm.reply(q)
+	})
+
+	svc := NewServiceFull(nil, nil)
+	svc.SetReproducibility(models.Reproducibility{Temperature: 0.1, Seed: 42})
+	var runs []models.MeetingRun
+	svc.SetMeetingRunRecorder(func(stockCode string, run models.MeetingRun) {
+		if stockCode != "sh600519" {
+			t.Errorf("stockCode = %s", stockCode)
+		}
+		runs = append(runs, run)
+	})
+
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock", Temperature: 0.8, MaxTokens: 2000}
+	req := ChatRequest{
+		StockCode:    "sh600519",
+		Stock:        models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500},
+		Query:        "能买吗",
+		AllAgents:    []models.AgentConfig{{ID: "macro", Name: "老陈", Role: "宏观策略", Enabled: true, MaxTokensPerAgent: 500}},
+		Reproducible: true,
+	}
+	if _, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, nil); err != nil {
+		t.Fatalf("meeting error: %v", err)
+	}
+
+	// 小韭菜与专家的每次请求都使用复现温度
+	if len(temperatures) != 3 {
+		t.Fatalf("requests = %d", len(temperatures))
+	}
+	for _, temp := range temperatures {
+		if temp < 0.099 || temp > 0.101 {
+			t.Errorf("temperatures = %v", temperatures)
+			break
+		}
+	}
+
+	if len(runs) != 1 {
+		t.Fatalf("runs = %d", len(runs))
+	}
+	run := runs[0]
+	if !run.Reproducible || run.Seed != 42 || run.Mode != MeetingModeSmart || run.ID == "" || run.FinishedAt < run.StartedAt {
+		t.Errorf("run = %+v", run)
+	}
+	want := []models.MeetingRunAgent{
+		{AgentID: "moderator", AgentName: "小韭菜", AIConfigID: "mock", Provider: models.AIProviderOllama, ModelName: "mock", Temperature: float64(float32(0.1)), MaxTokens: 2000},
+		{AgentID: "macro", AgentName: "老陈", AIConfigID: "mock", Provider: models.AIProviderOllama, ModelName: "mock", Temperature: float64(float32(0.1)), MaxTokens: 500},
+	}
+	if len(run.Agents) != len(want) || run.Agents[0] != want[0] || run.Agents[1] != want[1] {
+		t.Errorf("agents = %+v", run.Agents)
+	}
+	// 系统指令中的当前时间固定为会议开始时间
+	started := time.UnixMilli(run.StartedAt).Format("2006-01-02 15:04:05")
+	if !strings.Contains(llm.instruction, "当前时间: "+started) {
+		t.Errorf("instruction should use meeting start time %s", started)
+	}
+
+	// 普通会议记录配置中的温度，不带种子
+	req.Reproducible = false
+	if _, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, nil); err != nil {
+		t.Fatalf("meeting error: %v", err)
+	}
+	if len(runs) != 2 || runs[1].Reproducible || runs[1].Seed != 0 || runs[1].Agents[1].Temperature != 0.8 {
+		t.Errorf("plain run = %+v", runs[len(runs)-1])
+	}
+	// 最后一次请求为小韭菜总结，倒数第二次为专家发言
+	if got := temperatures[len(temperatures)-2]; got < 0.799 || got > 0.801 {
+		t.Errorf("plain agent temperature = %v", temperatures)
+	}
+}
+
+// captureLLM 记录最近一次请求的模型
+type captureLLM struct {
+	req *model.LLMRequest
+}
+
+func (m *captureLLM) Name() string { return "capture" }
+
+func (m *captureLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	m.req = req
+	return func(yield func(*model.LLMResponse, error) bool) {}
+}
+
+func TestMeetingRunBind_Seed(t *testing.T) {
+	r := &meetingRun{reproducible: true, temperature: 0.2, seed: 7}
+	agent := &models.AgentConfig{ID: "tech", Name: "老张"}
+
+	inner := &captureLLM{}
+	openai := &models.AIConfig{ID: "gpt", Provider: models.AIProviderOpenAI, ModelName: "gpt-4o", Temperature: 0.9}
+	llm := r.bind(agent, openai, inner)
+	for range llm.GenerateContent(context.Background(), &model.LLMRequest{}, false) {
+	}
+	if cfg := inner.req.Config; cfg == nil || cfg.Seed == nil || *cfg.Seed != 7 || *cfg.Temperature != 0.2 {
+		t.Fatalf("request config = %+v", inner.req.Config)
+	}
+	if !r.run.Agents[0].SeedSupported {
+		t.Errorf("openai should support seed: %+v", r.run.Agents[0])
+	}
+
+	// 不支持种子的 Provider 记录下来而不是报错，重复登记只保留第一次
+	r.bind(&models.AgentConfig{ID: "fund", Name: "钱姐"}, &models.AIConfig{ID: "claude", Provider: models.AIProviderAnthropic}, inner)
+	r.bind(agent, &models.AIConfig{ID: "claude", Provider: models.AIProviderAnthropic}, inner)
+	if len(r.run.Agents) != 2 || r.run.Agents[1].SeedSupported || r.run.Agents[0].AIConfigID != "gpt" {
+		t.Errorf("agents = %+v", r.run.Agents)
+	}
+
+	// 不在会议中时原样返回
+	var none *meetingRun
+	if got := none.bind(agent, openai, inner); got != model.LLM(inner) {
+		t.Error("nil run should return original llm")
+	}
+}
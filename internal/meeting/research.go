@@ -0,0 +1,258 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/adk/openai"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// 深度研究配置
+const (
+	ResearchAgentID   = "researcher"
+	ResearchAgentName = "研究员"
+
+	DefaultResearchToolCalls = 40               // 未配置上限时的工具调用次数
+	researchTimeout          = 20 * time.Minute // 单个研究任务的最长运行时间
+	researchHardCapRatio     = 1.5              // 费用超出上限后停止调用工具，再超出该倍数时直接结束
+	researchSummaryLimit     = 300              // 摘要消息字数上限
+)
+
+// ErrEmptyResearchReport 研究员未产出报告正文
+var ErrEmptyResearchReport = errors.New("研究员未生成报告")
+
+// researchTools 研究员可用的工具：研报、财报、业绩日历、行情与技术面、快讯、舆情、龙虎榜、公告
+var researchTools = []string{
+	"get_stock_realtime",
+	"get_research_report",
+	"get_report_content",
+	"get_financial_report",
+	"get_stock_financial_report",
+	"get_earnings_calendar",
+	"get_kline_data",
+	"get_technical_indicators",
+	"get_sector_data",
+	"get_news",
+	"get_hottrend",
+	"get_longhubang",
+	"get_longhubang_detail",
+	"get_announcements",
+	"get_announcement_content",
+}
+
+// researchInstruction 研究员系统指令，要求多轮收集资料并按固定章节成文、标注引用
+const researchInstruction = `你是一名资深A股研究员，负责就单只股票撰写深度研究报告。
+
+工作方式：
+1. 先列出需要的资料，再多轮调用工具收集：研报列表与正文、三大财务报表、业绩日历、日K与技术指标、所属板块与板块强弱、财经快讯、舆情热点、龙虎榜、公司公告（解禁、定增、业绩预告等）；
+2. 工具调用次数有上限，不要用相同参数重复调用；收到"已达上限"或"预算已用完"的提示后，立即基于已获取的资料成文；
+3. 只使用工具返回的数据，不编造数字；未能获取的信息注明"未获取"。
+
+报告使用 Markdown，按以下章节输出：
+# 股票名称（代码）深度研究报告
+## 摘要（3~5 条核心结论）
+## 一、公司与业务概况
+## 二、财务分析
+## 三、机构观点
+## 四、技术面
+## 五、消息面与市场情绪
+## 六、风险提示
+## 七、结论与跟踪要点
+## 参考资料
+
+正文中的数据与观点用 [1]、[2] 标注出处，"参考资料"按编号列出来源（工具名、券商/媒体、标题或报告期、日期），每条都必须来自本次工具返回的结果。`
+
+// ResearchRequest 深度研究请求
+type ResearchRequest struct {
+	Stock        models.Stock
+	Focus        string // 用户指定的研究重点，可为空
+	Position     *models.StockPosition
+	MaxToolCalls int     // 工具调用上限，0 使用 DefaultResearchToolCalls
+	MaxCost      float64 // 费用上限，0 表示不限
+}
+
+// ResearchResult 深度研究结果
+type ResearchResult struct {
+	Report          string  `json:"report"`  // Markdown 报告全文
+	Summary         string  `json:"summary"` // 报告摘要，用于会话消息
+	ToolCalls       int     `json:"toolCalls"`
+	InputTokens     int64   `json:"inputTokens"`
+	OutputTokens    int64   `json:"outputTokens"`
+	Cost            float64 `json:"cost"`
+	BudgetExhausted bool    `json:"budgetExhausted"` // 是否因工具次数或费用上限提前收尾
+}
+
+// researchAgent 研究员的专家配置
+func researchAgent() models.AgentConfig {
+	return models.AgentConfig{
+		ID:          ResearchAgentID,
+		Name:        ResearchAgentName,
+		Role:        "深度研究",
+		Instruction: researchInstruction,
+		Tools:       researchTools,
+	}
+}
+
+// buildResearchQuery 构建研究任务的用户消息
+func buildResearchQuery(stock models.Stock, focus string) string {
+	query := fmt.Sprintf("请对 %s（%s）做一次深度研究，输出完整的研究报告。", stock.Name, stock.Symbol)
+	if focus = strings.TrimSpace(focus); focus != "" {
+		query += "\n本次研究重点：" + focus
+	}
+	return query
+}
+
+// RunResearch 由研究员多轮调用工具收集资料并撰写深度研究报告
+// 工具次数或费用超出上限时停止收集资料、基于已有信息成文；费用超出上限的 researchHardCapRatio 倍时直接结束并保留已生成的内容
+func (s *Service) RunResearch(ctx context.Context, aiConfig *models.AIConfig, req ResearchRequest, progress ProgressCallback) (*ResearchResult, error) {
+	if aiConfig == nil {
+		return nil, ErrNoAIConfig
+	}
+	ctx, cancel := withExtendableTimeout(ctx, researchTimeout)
+	defer cancel()
+
+	llm, err := s.modelFactory.CreateModel(ctx, aiConfig)
+	if err != nil {
+		return nil, fmt.Errorf("create model error: %w", err)
+	}
+	cfg := researchAgent()
+	query := buildResearchQuery(req.Stock, req.Focus)
+	agentInstance, err := s.createBuilder(ctx, llm, aiConfig).BuildAgentWithContext(&cfg, &req.Stock, query, "", req.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "jcp", Agent: agentInstance, SessionService: sessionService})
+	if err != nil {
+		return nil, err
+	}
+	sessionID := fmt.Sprintf("research-%s-%d", req.Stock.Symbol, time.Now().UnixNano())
+	if _, err = sessionService.Create(ctx, &session.CreateRequest{AppName: "jcp", UserID: "user", SessionID: sessionID}); err != nil {
+		return nil, fmt.Errorf("create session error: %w", err)
+	}
+
+	maxCalls := req.MaxToolCalls
+	if maxCalls <= 0 {
+		maxCalls = DefaultResearchToolCalls
+	}
+	hook := newToolCallHook(cfg.ID, cfg.Name, progress)
+	if hook == nil {
+		hook = &adk.ToolCallHook{}
+	}
+	hook.MaxCalls = maxCalls
+	ctx = adk.WithToolCallHook(ctx, hook)
+
+	runCfg := agent.RunConfig{}
+	if progress != nil {
+		runCfg.StreamingMode = agent.StreamingModeSSE
+	}
+	emitProgress(progress, ProgressEvent{
+		Type: "agent_start", AgentID: cfg.ID, AgentName: cfg.Name, Detail: "开始深度研究", Model: aiConfig.ModelName,
+	})
+
+	result := &ResearchResult{}
+	var sb strings.Builder
+	var finishReason genai.FinishReason
+	costCapped := false
+	for event, err := range r.Run(ctx, "user", sessionID, genai.NewContentFromText(query, genai.RoleUser), runCfg) {
+		if err != nil {
+			// 超时或取消时保留已生成的内容，由调用方决定是否保存
+			if ctx.Err() != nil && sb.Len() > 0 {
+				log.Warn("research %s interrupted, keep partial report: %v", req.Stock.Symbol, err)
+				break
+			}
+			return nil, err
+		}
+		if event == nil {
+			continue
+		}
+		if u := event.LLMResponse.UsageMetadata; u != nil && !event.LLMResponse.Partial {
+			result.InputTokens += int64(u.PromptTokenCount)
+			result.OutputTokens += int64(u.CandidatesTokenCount) + int64(u.ThoughtsTokenCount)
+			result.Cost = estimateCost(aiConfig, result.InputTokens, result.OutputTokens)
+			emitProgress(progress, ProgressEvent{
+				Type: ProgressTypeUsage, AgentID: cfg.ID, AgentName: cfg.Name,
+				Usage: &MeetingUsage{
+					StockCode: req.Stock.Symbol, PromptTokens: result.InputTokens, CompletionTokens: result.OutputTokens,
+					TotalTokens: result.InputTokens + result.OutputTokens, Cost: result.Cost, Turns: 1,
+				},
+			})
+			if req.MaxCost > 0 && result.Cost >= req.MaxCost && !costCapped {
+				costCapped = true
+				hook.Exhaust()
+				log.Info("research %s cost %.4f reached cap %.4f", req.Stock.Symbol, result.Cost, req.MaxCost)
+				emitProgress(progress, ProgressEvent{
+					Type: ProgressTypeBudgetExhausted, AgentID: cfg.ID, AgentName: cfg.Name,
+					Detail: fmt.Sprintf("费用已达上限 %.2f，停止收集资料并开始成文", req.MaxCost),
+				})
+			}
+			if costCapped && result.Cost >= req.MaxCost*researchHardCapRatio {
+				log.Warn("research %s cost %.4f exceeded hard cap, stop", req.Stock.Symbol, result.Cost)
+				break
+			}
+		}
+		if event.LLMResponse.FinishReason != "" && !event.LLMResponse.Partial {
+			finishReason = event.LLMResponse.FinishReason
+		}
+		if event.LLMResponse.Content == nil {
+			continue
+		}
+		for _, part := range event.LLMResponse.Content.Parts {
+			if part.Thought || part.Text == "" {
+				continue
+			}
+			// streaming 模式下只累积 Partial 片段，避免重复
+			if progress == nil {
+				sb.WriteString(part.Text)
+			} else if event.LLMResponse.Partial {
+				sb.WriteString(part.Text)
+				progress(newDeltaEvent(cfg.ID, cfg.Name, part.Text, false))
+			}
+		}
+	}
+
+	result.ToolCalls = hook.Calls()
+	result.BudgetExhausted = costCapped || result.ToolCalls > maxCalls
+	s.recordUsage(&req.Stock, &cfg, result.InputTokens, result.OutputTokens)
+
+	report := strings.TrimSpace(openai.FilterVendorToolCallMarkers(sb.String()))
+	if report == "" {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrEmptyResearchReport
+	}
+	if isTruncated(finishReason) {
+		report = markTruncated(report, &cfg, progress)
+	}
+	result.Report = report
+	result.Summary = researchSummary(report)
+	emitProgress(progress, ProgressEvent{Type: "agent_done", AgentID: cfg.ID, AgentName: cfg.Name})
+	return result, nil
+}
+
+// researchSummary 提取报告"摘要"章节作为会话消息，找不到时截取报告开头
+func researchSummary(report string) string {
+	summary := report
+	if _, after, ok := strings.Cut(report, "## 摘要"); ok {
+		// 跳过标题行剩余部分（如"（3~5 条核心结论）"）
+		if _, body, ok := strings.Cut(after, "\n"); ok {
+			summary = body
+		}
+		if end := strings.Index(summary, "\n## "); end >= 0 {
+			summary = summary[:end]
+		}
+	}
+	return truncateRunes(strings.TrimSpace(summary), researchSummaryLimit)
+}
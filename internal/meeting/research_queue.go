@@ -0,0 +1,225 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventResearchProgress 深度研究任务状态与进度事件，载荷为 ResearchProgress
+const EventResearchProgress = "research:progress"
+
+// 深度研究任务状态
+const (
+	ResearchQueued    = "queued"
+	ResearchRunning   = "running"
+	ResearchDone      = "done"
+	ResearchFailed    = "failed"
+	ResearchCancelled = "cancelled"
+)
+
+// maxResearchTasks 内存中保留的任务数，超出时丢弃最早结束的任务
+const maxResearchTasks = 20
+
+// ResearchTask 深度研究任务
+type ResearchTask struct {
+	ID           string  `json:"id"`
+	StockCode    string  `json:"stockCode"`
+	StockName    string  `json:"stockName"`
+	Focus        string  `json:"focus"`
+	Status       string  `json:"status"`
+	CreatedAt    int64   `json:"createdAt"`
+	StartedAt    int64   `json:"startedAt,omitempty"`
+	FinishedAt   int64   `json:"finishedAt,omitempty"`
+	Error        string  `json:"error,omitempty"`
+	ToolCalls    int     `json:"toolCalls"`
+	Cost         float64 `json:"cost"`
+	AttachmentID string  `json:"attachmentId,omitempty"` // 报告保存后的会话附件 ID
+}
+
+// finished 任务是否已结束
+func (t *ResearchTask) finished() bool {
+	return t.Status == ResearchDone || t.Status == ResearchFailed || t.Status == ResearchCancelled
+}
+
+// ResearchProgress 推送给前端的研究任务事件，Event 为空表示仅任务状态变化
+type ResearchProgress struct {
+	Task  ResearchTask   `json:"task"`
+	Event *ProgressEvent `json:"event,omitempty"`
+}
+
+// ResearchOutcome 单个任务的执行结果，由 ResearchRunFunc 返回
+type ResearchOutcome struct {
+	ToolCalls    int
+	Cost         float64
+	AttachmentID string
+}
+
+// ResearchRunFunc 执行一个研究任务（生成报告并保存），ctx 在任务取消时结束
+type ResearchRunFunc func(ctx context.Context, task ResearchTask, progress ProgressCallback) (ResearchOutcome, error)
+
+// ResearchQueue 深度研究任务队列，按提交顺序逐个执行（同时最多 1 个）
+// 任务在后台运行，与前端页面生命周期无关，状态变化与进度通过 notify 推送
+type ResearchQueue struct {
+	ctx    context.Context
+	run    ResearchRunFunc
+	notify func(ResearchProgress)
+
+	mu      sync.Mutex
+	tasks   []*ResearchTask    // 按提交顺序
+	cancel  context.CancelFunc // 取消运行中的任务
+	working bool               // 是否已有 work 协程在执行
+}
+
+// NewResearchQueue 创建研究任务队列，ctx 结束时运行中的任务随之取消
+func NewResearchQueue(ctx context.Context, run ResearchRunFunc, notify func(ResearchProgress)) *ResearchQueue {
+	return &ResearchQueue{ctx: ctx, run: run, notify: notify}
+}
+
+// Enqueue 提交研究任务，同一股票已有排队或运行中的任务时返回错误
+func (q *ResearchQueue) Enqueue(stockCode, stockName, focus string) (ResearchTask, error) {
+	q.mu.Lock()
+	for _, t := range q.tasks {
+		if t.StockCode == stockCode && !t.finished() {
+			q.mu.Unlock()
+			return ResearchTask{}, errors.New("该股票已有进行中的深度研究任务")
+		}
+	}
+	task := &ResearchTask{
+		ID: uuid.New().String(), StockCode: stockCode, StockName: stockName, Focus: focus,
+		Status: ResearchQueued, CreatedAt: time.Now().UnixMilli(),
+	}
+	q.tasks = append(q.tasks, task)
+	q.trimLocked()
+	snapshot := *task
+	start := !q.working
+	q.working = true
+	q.mu.Unlock()
+
+	q.emit(snapshot, nil)
+	if start {
+		go q.work()
+	}
+	return snapshot, nil
+}
+
+// Cancel 取消排队或运行中的任务，任务不存在或已结束时返回 false
+func (q *ResearchQueue) Cancel(id string) bool {
+	q.mu.Lock()
+	task := q.findLocked(id)
+	if task == nil || task.finished() {
+		q.mu.Unlock()
+		return false
+	}
+	if task.Status == ResearchRunning {
+		// 运行中的任务由 work 在 run 返回后标记为已取消
+		cancel := q.cancel
+		q.mu.Unlock()
+		cancel()
+		return true
+	}
+	task.Status = ResearchCancelled
+	task.FinishedAt = time.Now().UnixMilli()
+	snapshot := *task
+	q.mu.Unlock()
+
+	q.emit(snapshot, nil)
+	return true
+}
+
+// List 返回全部任务快照，最近提交的在前
+func (q *ResearchQueue) List() []ResearchTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	tasks := make([]ResearchTask, 0, len(q.tasks))
+	for i := len(q.tasks) - 1; i >= 0; i-- {
+		tasks = append(tasks, *q.tasks[i])
+	}
+	return tasks
+}
+
+// work 依次执行排队中的任务，队列清空后退出
+func (q *ResearchQueue) work() {
+	for {
+		q.mu.Lock()
+		var task *ResearchTask
+		for _, t := range q.tasks {
+			if t.Status == ResearchQueued {
+				task = t
+				break
+			}
+		}
+		if task == nil {
+			q.working = false
+			q.mu.Unlock()
+			return
+		}
+		ctx, cancel := context.WithCancel(q.ctx)
+		q.cancel = cancel
+		task.Status = ResearchRunning
+		task.StartedAt = time.Now().UnixMilli()
+		snapshot := *task
+		q.mu.Unlock()
+
+		q.emit(snapshot, nil)
+		outcome, err := q.run(ctx, snapshot, func(event ProgressEvent) {
+			q.emit(snapshot, &event)
+		})
+
+		q.mu.Lock()
+		task.ToolCalls, task.Cost, task.AttachmentID = outcome.ToolCalls, outcome.Cost, outcome.AttachmentID
+		switch {
+		case ctx.Err() != nil && q.ctx.Err() == nil:
+			// 用户取消，已生成的部分报告仍由 run 保存
+			task.Status = ResearchCancelled
+		case err != nil:
+			task.Status, task.Error = ResearchFailed, err.Error()
+		default:
+			task.Status = ResearchDone
+		}
+		task.FinishedAt = time.Now().UnixMilli()
+		q.cancel = nil
+		snapshot = *task
+		q.mu.Unlock()
+		cancel()
+
+		q.emit(snapshot, nil)
+	}
+}
+
+// findLocked 按 ID 查找任务，调用方需持有锁
+func (q *ResearchQueue) findLocked(id string) *ResearchTask {
+	for _, t := range q.tasks {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// trimLocked 任务数超出上限时丢弃最早结束的任务，排队与运行中的任务始终保留
+func (q *ResearchQueue) trimLocked() {
+	for len(q.tasks) > maxResearchTasks {
+		idx := -1
+		for i, t := range q.tasks {
+			if t.finished() {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return
+		}
+		q.tasks = append(q.tasks[:idx], q.tasks[idx+1:]...)
+	}
+}
+
+// emit 推送任务状态或进度
+func (q *ResearchQueue) emit(task ResearchTask, event *ProgressEvent) {
+	if q.notify != nil {
+		q.notify(ResearchProgress{Task: task, Event: event})
+	}
+}
@@ -0,0 +1,138 @@
+package meeting
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestRunResearch(t *testing.T) {
+	var prompt string
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		prompt = q.Last()
+		report := "# 贵州茅台（sh600519）深度研究报告\n## 摘要（3~5 条核心结论）\n1. 营收稳健增长[1]\n## 一、公司与业务概况\n白酒龙头\n## 参考资料\n[1] get_stock_financial_report 2025Q3"
+		return ollamaReply{Content: report, DoneReason: "stop", PromptEval: 1000, Eval: 500}
+	})
+
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{
+		ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock",
+		InputPricePer1K: 0.01, OutputPricePer1K: 0.02,
+	}
+	req := ResearchRequest{Stock: models.Stock{Symbol: "sh600519", Name: "贵州茅台"}, Focus: "分红政策"}
+	result, err := svc.RunResearch(context.Background(), aiConfig, req, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(prompt, "本次研究重点：分红政策") {
+		t.Errorf("prompt = %s", prompt)
+	}
+	if !strings.HasPrefix(result.Report, "# 贵州茅台") || result.Summary != "1. 营收稳健增长[1]" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.InputTokens != 1000 || result.OutputTokens != 500 || result.Cost < 0.0199 || result.Cost > 0.0201 {
+		t.Errorf("usage = %+v", result)
+	}
+	if result.BudgetExhausted {
+		t.Error("budget should not be exhausted")
+	}
+}
+
+func TestResearchSummary(t *testing.T) {
+	if got := researchSummary("# 标题\n正文开头"); got != "# 标题\n正文开头" {
+		t.Errorf("no summary section: %q", got)
+	}
+	if got := researchSummary("## 摘要\n- 结论A\n- 结论B\n\n## 一、概况\n..."); got != "- 结论A\n- 结论B" {
+		t.Errorf("summary = %q", got)
+	}
+}
+
+func TestResearchQueue(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+	var events []ResearchProgress
+	q := NewResearchQueue(context.Background(), func(ctx context.Context, task ResearchTask, progress ProgressCallback) (ResearchOutcome, error) {
+		mu.Lock()
+		order = append(order, task.StockCode)
+		mu.Unlock()
+		progress(ProgressEvent{Type: ProgressTypeToolCallStarted, Detail: "get_news"})
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return ResearchOutcome{ToolCalls: 1}, ctx.Err()
+		}
+		return ResearchOutcome{ToolCalls: 3, AttachmentID: "att-" + task.StockCode}, nil
+	}, func(p ResearchProgress) {
+		mu.Lock()
+		events = append(events, p)
+		mu.Unlock()
+	})
+
+	first, err := q.Enqueue("sh600519", "贵州茅台", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Enqueue("sh600519", "贵州茅台", ""); err == nil {
+		t.Error("duplicate stock should be rejected while pending")
+	}
+	second, _ := q.Enqueue("sz000001", "平安银行", "")
+	third, _ := q.Enqueue("sh600000", "浦发银行", "")
+
+	waitStatus := func(id, status string) ResearchTask {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			for _, task := range q.List() {
+				if task.ID == id && task.Status == status {
+					return task
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("task %s did not reach %s: %+v", id, status, q.List())
+		return ResearchTask{}
+	}
+
+	// 同时只运行一个任务，排队中的任务可直接取消
+	waitStatus(first.ID, ResearchRunning)
+	if !q.Cancel(third.ID) {
+		t.Error("cancel queued task failed")
+	}
+	release <- struct{}{}
+	if done := waitStatus(first.ID, ResearchDone); done.AttachmentID != "att-sh600519" || done.ToolCalls != 3 {
+		t.Errorf("done task = %+v", done)
+	}
+
+	// 取消运行中的任务
+	waitStatus(second.ID, ResearchRunning)
+	if !q.Cancel(second.ID) {
+		t.Error("cancel running task failed")
+	}
+	waitStatus(second.ID, ResearchCancelled)
+	if q.Cancel(second.ID) {
+		t.Error("cancel finished task should return false")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(order, ","); got != "sh600519,sz000001" {
+		t.Errorf("run order = %s", got)
+	}
+	var progressEvents int
+	for _, e := range events {
+		if e.Event != nil {
+			progressEvents++
+		}
+	}
+	if progressEvents != 2 {
+		t.Errorf("progress events = %d", progressEvents)
+	}
+	if tasks := q.List(); tasks[0].ID != third.ID || tasks[0].Status != ResearchCancelled {
+		t.Errorf("list = %+v", tasks)
+	}
+}
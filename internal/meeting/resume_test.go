@@ -0,0 +1,144 @@
+package meeting
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// resumeMockServer 模拟 Ollama：专家 a2 的第一次发言一直阻塞，started 在其开始时关闭
+func resumeMockServer(t *testing.T) (*httptest.Server, <-chan struct{}, func() map[string]int) {
+	var (
+		mu      sync.Mutex
+		calls   = make(map[string]int)
+		started = make(chan struct{})
+	)
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		switch {
+		case q.Analyze():
+			return ollamaReply{Content: `{"intent":"走势","selected":["a1","a2","a3","a4"],"tasks":{"a3":"任务-a3"},"topic":"走势","opening":"开始"}`}
+		case q.Summarize():
+			return ollamaReply{Content: "总结"}
+		}
+		reply := "观点"
+		for _, id := range []string{"a1", "a2", "a3", "a4"} {
+			if !strings.Contains(q.First(), "指令-"+id) {
+				continue
+			}
+			mu.Lock()
+			calls[id]++
+			n := calls[id]
+			mu.Unlock()
+			reply = "观点-" + id
+			if id == "a2" && n == 1 {
+				close(started)
+				<-q.Context().Done()
+				return ollamaReply{Drop: true}
+			}
+		}
+		return ollamaReply{Content: reply}
+	})
+	return srv, started, func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		out := make(map[string]int, len(calls))
+		for k, v := range calls {
+			out[k] = v
+		}
+		return out
+	}
+}
+
+func TestResumeMeeting(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		name := "serial"
+		if parallel {
+			name = "parallel"
+		}
+		t.Run(name, func(t *testing.T) {
+			srv, started, calls := resumeMockServer(t)
+			svc := NewServiceFull(nil, nil)
+			aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+			req := parallelRequest()
+			req.ParallelRounds = parallel
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-started
+				cancel()
+			}()
+
+			var first []ChatResponse
+			svc.RunSmartMeetingWithCallback(ctx, aiConfig, req, func(resp ChatResponse) {
+				first = append(first, resp)
+			}, nil)
+			for _, r := range first {
+				if r.Error != "" {
+					t.Fatalf("取消不应产生失败消息: %+v", r)
+				}
+			}
+			if !svc.HasInterruptedMeeting(req.StockCode) {
+				t.Fatal("取消后应保留会议进度")
+			}
+
+			var resumed []string
+			responses, err := svc.ResumeMeeting(context.Background(), req.StockCode, func(resp ChatResponse) {
+				if resp.Error != "" {
+					t.Errorf("resume failed: %+v", resp)
+				}
+				resumed = append(resumed, resp.AgentID+"#"+string(rune('0'+resp.Round)))
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 已发言的专家不重复发言，其余专家恢复后各发言一次，轮次与原会议一致
+			spoke := make(map[string]bool)
+			for _, r := range first {
+				spoke[r.AgentID] = true
+			}
+			if spoke["a2"] || (!parallel && !spoke["a1"]) {
+				t.Fatalf("first = %+v", first)
+			}
+			got := calls()
+			var want []string
+			for _, id := range []string{"a1", "a2", "a3", "a4"} {
+				if spoke[id] && got[id] != 1 {
+					t.Errorf("%s 已发言，恢复后又发言（共 %d 次）", id, got[id])
+				}
+				if !spoke[id] {
+					want = append(want, id+"#1")
+				}
+			}
+			want = append(want, "moderator#2")
+			if parallel && len(resumed) > 0 {
+				// 并行恢复按完成顺序回调，只比较发言集合，总结仍在最后
+				slices.Sort(resumed[:len(resumed)-1])
+			}
+			if strings.Join(resumed, ",") != strings.Join(want, ",") {
+				t.Fatalf("resumed = %v, want %v", resumed, want)
+			}
+			if len(responses) != 6 || responses[0].MsgType != "opening" {
+				t.Errorf("responses = %+v", responses)
+			}
+			if svc.HasInterruptedMeeting(req.StockCode) {
+				t.Error("恢复完成后不应再有中断状态")
+			}
+		})
+	}
+}
+
+func TestResumeMeeting_NothingToResume(t *testing.T) {
+	svc := NewServiceFull(nil, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := svc.ResumeMeeting(ctx, "sh600519", nil, nil); err == nil {
+		t.Fatal("没有中断的会议时应返回错误")
+	}
+}
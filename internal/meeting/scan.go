@@ -0,0 +1,400 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/indicators"
+)
+
+const (
+	ScanSessionCode   = "watchlist_scan" // 晨间扫描专用会话（与股票会话并列，按此代码取消）
+	ScanSessionName   = "自选股晨间扫描"
+	EventScanComplete = "scan:complete" // 扫描完成事件，携带 ScanResult
+
+	maxScanPanel = 3 // 参与扫描的专家上限，控制耗时与费用
+	maxScanPicks = 5 // 排名最多保留的股票数
+
+	maxScanNewsRunes = 80 // 单条快讯注入 Prompt 的字数上限
+)
+
+// ErrNoScanData 自选股行情全部不可用
+var ErrNoScanData = errors.New("自选股行情均不可用，无法扫描")
+
+// ScanItem 单只自选股的扫描数据，由调用方批量汇总（不通过工具逐只查询）
+type ScanItem struct {
+	Stock      models.Stock
+	Indicators *indicators.Indicators // 日线技术指标，获取失败为 nil
+	News       []string               // 隔夜匹配到的快讯
+}
+
+// ScanRequest 晨间扫描请求
+type ScanRequest struct {
+	Items        []ScanItem
+	Agents       []models.AgentConfig // 可用专家，按顺序取前 maxScanPanel 位组成扫描小组
+	AgentTimeout time.Duration        // 单个专家发言超时，0 为默认
+}
+
+// ScanPick 排名中的一只股票
+type ScanPick struct {
+	Rank   int    `json:"rank"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ScanExclusion 因数据异常未参与扫描的股票
+type ScanExclusion struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ScanResult 晨间扫描结果
+type ScanResult struct {
+	Picks      []ScanPick      `json:"picks"`
+	Excluded   []ScanExclusion `json:"excluded,omitempty"`
+	Summary    string          `json:"summary"`
+	Error      string          `json:"error,omitempty"`
+	FinishedAt int64           `json:"finishedAt"`
+}
+
+// splitScanItems 剔除行情异常的股票，返回可扫描的数据与排除说明
+func splitScanItems(items []ScanItem) ([]ScanItem, []ScanExclusion) {
+	var valid []ScanItem
+	var excluded []ScanExclusion
+	for _, item := range items {
+		reason := ""
+		switch {
+		case item.Stock.DataSuspect:
+			reason = item.Stock.SuspectReason
+			if reason == "" {
+				reason = "行情数据异常"
+			}
+		case item.Stock.Price <= 0:
+			reason = "无有效现价"
+		}
+		if reason != "" {
+			excluded = append(excluded, ScanExclusion{Code: item.Stock.Symbol, Name: item.Stock.Name, Reason: reason})
+			continue
+		}
+		valid = append(valid, item)
+	}
+	return valid, excluded
+}
+
+// RunWatchlistScan 自选股晨间扫描：小组专家基于服务端汇总的数据各自挑选关注标的，小韭菜汇总排名
+// 专家不调用工具，直接以 Prompt 分析，行情异常的股票不参与并在结果中注明
+func (s *Service) RunWatchlistScan(ctx context.Context, aiConfig *models.AIConfig, req ScanRequest, respCallback ResponseCallback, progressCallback ProgressCallback) (*ScanResult, error) {
+	if aiConfig == nil {
+		return nil, ErrNoAIConfig
+	}
+	if len(req.Agents) == 0 {
+		return nil, ErrNoAgents
+	}
+	items, excluded := splitScanItems(req.Items)
+	if len(items) == 0 {
+		return nil, ErrNoScanData
+	}
+	defer s.beginRun(ScanSessionCode)()
+	s.resetMeetingUsage(ScanSessionCode)
+
+	meetingCtx, meetingCancel := withExtendableTimeout(ctx, MeetingTimeout)
+	defer meetingCancel()
+
+	modelCtx, modelCancel := context.WithTimeout(meetingCtx, ModelCreationTimeout)
+	llm, err := s.modelFactory.CreateModel(modelCtx, aiConfig)
+	modelCancel()
+	if err != nil {
+		return nil, fmt.Errorf("create model error: %w", err)
+	}
+	moderator := NewModerator(s.moderatorLLM(meetingCtx, llm, aiConfig))
+	agentModels := s.newAgentModels(aiConfig, llm)
+
+	respond := func(resp ChatResponse) {
+		resp.MeetingMode = MeetingModeSmart
+		if respCallback != nil {
+			respCallback(resp)
+		}
+	}
+
+	opening := fmt.Sprintf("开始晨间扫描，共 %d 只自选股参与。", len(items))
+	if note := excludedNote(excluded); note != "" {
+		opening += "\n" + note
+	}
+	respond(ChatResponse{
+		AgentID: "moderator", AgentName: "小韭菜", Role: "会议主持",
+		Content: opening, Round: 0, MsgType: "opening",
+	})
+
+	panel := req.Agents[:min(maxScanPanel, len(req.Agents))]
+	panel = s.applyTurnBudget(panel, progressCallback)
+
+	scanStock := &models.Stock{Symbol: ScanSessionCode, Name: ScanSessionName}
+	data := buildScanData(items)
+	var history []DiscussionEntry
+	for _, agentCfg := range panel {
+		if meetingCtx.Err() != nil {
+			break
+		}
+		agentLLM, agentAIConfig, err := agentModels.forAgent(meetingCtx, &agentCfg)
+		if err != nil {
+			log.Error("scan: create agent LLM error, skip %s: %v", agentCfg.ID, err)
+			continue
+		}
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
+			Model: modelLabel(agentAIConfig),
+		})
+
+		prompt := buildScanAgentPrompt(&agentCfg, data, buildPeerContext(history, models.MeetingVisibilitySequential))
+		out, err := retryRun(meetingCtx, MaxAgentRetries, func() (agentOutput, error) {
+			return runAgentWithTimeout(meetingCtx, agentTimeout(agentAIConfig, req.AgentTimeout), func(agentCtx context.Context) (agentOutput, error) {
+				text, usage, err := streamPrompt(agentCtx, agentLLM, prompt, func(text string) {
+					emitProgress(progressCallback, newDeltaEvent(agentCfg.ID, agentCfg.Name, text, false))
+				})
+				out := agentOutput{Content: text}
+				if usage != nil {
+					out.InputTokens = int64(usage.PromptTokenCount)
+					out.OutputTokens = int64(usage.CandidatesTokenCount) + int64(usage.ThoughtsTokenCount)
+				}
+				return out, err
+			})
+		})
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+		})
+		if err != nil {
+			if meetingCtx.Err() != nil {
+				break
+			}
+			log.Error("scan: agent %s failed: %v", agentCfg.ID, err)
+			respond(ChatResponse{
+				AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role,
+				Round: 1, MsgType: "opinion", Error: err.Error(),
+			})
+			continue
+		}
+
+		s.recordUsage(scanStock, &agentCfg, out.InputTokens, out.OutputTokens)
+		if out.InputTokens+out.OutputTokens > 0 {
+			total := s.addMeetingUsage(ScanSessionCode, agentAIConfig, out.InputTokens, out.OutputTokens)
+			emitProgress(progressCallback, ProgressEvent{
+				Type: ProgressTypeUsage, AgentID: agentCfg.ID, AgentName: agentCfg.Name, Usage: &total,
+			})
+		}
+		respond(ChatResponse{
+			AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role,
+			Content: out.Content, Round: 1, MsgType: "opinion",
+			PromptTokens: out.InputTokens, CompletionTokens: out.OutputTokens,
+		})
+		history = append(history, DiscussionEntry{
+			Round: 1, AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role, Content: out.Content,
+		})
+	}
+
+	if err := meetingCtx.Err(); err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, errors.New("扫描小组专家均未完成分析")
+	}
+
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "汇总关注排名",
+	})
+	summaryCtx, summaryCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
+	content, err := moderator.generate(summaryCtx, buildScanSummaryPrompt(items, history))
+	summaryCancel()
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("扫描汇总失败: %w", err)
+	}
+
+	result := &ScanResult{Excluded: excluded, FinishedAt: time.Now().UnixMilli()}
+	result.Picks, result.Summary, err = parseScanPicks(moderator.extractJSON(content), items)
+	text := formatScanResult(result)
+	if err != nil {
+		log.Warn("scan: parse picks error: %v", err)
+		result.Error = "未能解析关注排名"
+		text = content
+		if note := excludedNote(excluded); note != "" {
+			text += "\n\n" + note
+		}
+	}
+	respond(ChatResponse{
+		AgentID: "moderator", AgentName: "小韭菜", Role: "会议主持",
+		Content: text, Round: 2, MsgType: "summary",
+	})
+	return result, nil
+}
+
+// buildScanData 把自选股数据格式化为紧凑的文本块，所有专家共用
+func buildScanData(items []ScanItem) string {
+	var sb strings.Builder
+	for _, item := range items {
+		st := item.Stock
+		fmt.Fprintf(&sb, "- %s(%s) 现价%.2f 涨跌%+.2f%% 开%.2f 高%.2f 低%.2f 昨收%.2f 成交额%.2f亿\n",
+			st.Name, st.Symbol, st.Price, st.ChangePercent, st.Open, st.High, st.Low, st.PreClose, st.Amount/1e8)
+		if label := st.QuoteState.Label(); label != "" {
+			sb.WriteString("  状态：今日" + label + "，现价为昨收\n")
+		}
+		if tech := formatScanIndicators(item.Indicators); tech != "" {
+			sb.WriteString("  技术：" + tech + "\n")
+		}
+		if len(item.News) > 0 {
+			news := make([]string, len(item.News))
+			for i, n := range item.News {
+				news[i] = truncateRunes(n, maxScanNewsRunes)
+			}
+			sb.WriteString("  快讯：" + strings.Join(news, "；") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// formatScanIndicators 日线指标摘要，数据不足的指标省略
+func formatScanIndicators(ind *indicators.Indicators) string {
+	if ind == nil {
+		return ""
+	}
+	var parts []string
+	for _, key := range []string{"ma5", "ma20", "ma60"} {
+		if v, ok := ind.MA[key]; ok {
+			parts = append(parts, fmt.Sprintf("%s %.2f", strings.ToUpper(key), v))
+		}
+	}
+	if ind.MACD != nil {
+		parts = append(parts, fmt.Sprintf("MACD柱 %.3f", ind.MACD.Hist))
+	}
+	if ind.RSI != nil {
+		parts = append(parts, fmt.Sprintf("RSI14 %.1f", *ind.RSI))
+	}
+	if ind.KDJ != nil {
+		parts = append(parts, fmt.Sprintf("KDJ-J %.1f", ind.KDJ.J))
+	}
+	if ind.Boll != nil {
+		parts = append(parts, fmt.Sprintf("布林 %.2f/%.2f/%.2f", ind.Boll.Upper, ind.Boll.Mid, ind.Boll.Lower))
+	}
+	return strings.Join(parts, " ")
+}
+
+func buildScanAgentPrompt(agent *models.AgentConfig, data, peerContext string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "你是%s，%s。\n", agent.Name, agent.Role)
+	if agent.Instruction != "" {
+		sb.WriteString(agent.Instruction + "\n")
+	}
+	sb.WriteString("\n现在是开盘前的自选股晨间扫描。以下是已汇总好的自选股数据（实时行情、日线技术指标、隔夜快讯），无需再查询：\n")
+	sb.WriteString(data)
+	if peerContext != "" {
+		sb.WriteString("\n" + peerContext + "\n")
+	}
+	sb.WriteString(`
+要求：
+1. 从你的专业角度挑出今天最值得关注的 3~5 只股票，按优先级排序
+2. 每只用一句话说明理由，机会和风险都算值得关注
+3. 不要逐只点评全部股票，总字数不超过300字`)
+	return sb.String()
+}
+
+func buildScanSummaryPrompt(items []ScanItem, history []DiscussionEntry) string {
+	var sb strings.Builder
+	sb.WriteString("你是会议主持人小韭菜。请综合扫描小组的意见，给出今天最值得关注的 3~5 只自选股排名。\n\n候选股票（只能从中选择）：\n")
+	for _, item := range items {
+		fmt.Fprintf(&sb, "- %s %s\n", item.Stock.Symbol, item.Stock.Name)
+	}
+	sb.WriteString("\n专家意见：\n")
+	for _, h := range history {
+		fmt.Fprintf(&sb, "【%s（%s）】%s\n", h.AgentName, h.Role, h.Content)
+	}
+	sb.WriteString(`
+请以JSON格式输出：{"picks":[{"code":"股票代码","reason":"一句话理由，不超过40字"}],"summary":"一句话总体判断"}
+picks 按关注优先级排序。只输出JSON，不要其他内容：`)
+	return sb.String()
+}
+
+// parseScanPicks 解析小韭菜的排名，只保留候选股票（按代码或名称匹配），去重后最多 maxScanPicks 只
+func parseScanPicks(jsonStr string, items []ScanItem) ([]ScanPick, string, error) {
+	var raw struct {
+		Picks []struct {
+			Code   string `json:"code"`
+			Name   string `json:"name"`
+			Reason string `json:"reason"`
+		} `json:"picks"`
+		Summary string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
+		return nil, "", fmt.Errorf("parse scan picks json error: %w", err)
+	}
+
+	picks := make([]ScanPick, 0, maxScanPicks)
+	seen := make(map[string]bool)
+	for _, r := range raw.Picks {
+		code := models.NormalizeSymbol(r.Code)
+		var stock *models.Stock
+		for i := range items {
+			st := &items[i].Stock
+			symbol := models.NormalizeSymbol(st.Symbol)
+			// 模型可能省略市场前缀，只给6位代码
+			if symbol == code || (len(code) == 6 && strings.HasSuffix(symbol, code)) ||
+				(r.Name != "" && st.Name == strings.TrimSpace(r.Name)) {
+				stock = st
+				break
+			}
+		}
+		if stock == nil || seen[stock.Symbol] {
+			continue
+		}
+		seen[stock.Symbol] = true
+		picks = append(picks, ScanPick{
+			Rank: len(picks) + 1, Code: stock.Symbol, Name: stock.Name, Reason: strings.TrimSpace(r.Reason),
+		})
+		if len(picks) == maxScanPicks {
+			break
+		}
+	}
+	if len(picks) == 0 {
+		return nil, "", errors.New("排名中没有有效的候选股票")
+	}
+	return picks, strings.TrimSpace(raw.Summary), nil
+}
+
+// formatScanResult 排名结果的会话消息正文
+func formatScanResult(result *ScanResult) string {
+	var sb strings.Builder
+	sb.WriteString("**今日关注**\n")
+	for _, p := range result.Picks {
+		fmt.Fprintf(&sb, "%d. %s（%s）：%s\n", p.Rank, p.Name, p.Code, p.Reason)
+	}
+	if result.Summary != "" {
+		sb.WriteString("\n" + result.Summary + "\n")
+	}
+	if note := excludedNote(result.Excluded); note != "" {
+		sb.WriteString("\n" + note + "\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// excludedNote 排除说明，无排除时为空
+func excludedNote(excluded []ScanExclusion) string {
+	if len(excluded) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(excluded))
+	for _, e := range excluded {
+		name := e.Name
+		if name == "" {
+			name = e.Code
+		}
+		parts = append(parts, fmt.Sprintf("%s（%s）", name, e.Reason))
+	}
+	return "> 以下股票行情异常，未参与扫描：" + strings.Join(parts, "、")
+}
@@ -0,0 +1,129 @@
+package meeting
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/indicators"
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+func TestRunWatchlistScan(t *testing.T) {
+	var mu sync.Mutex
+	var agentPrompts []string
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		prompt := q.First()
+		reply := "贵州茅台放量突破，平安银行跌破布林下轨"
+		if strings.Contains(prompt, "扫描小组的意见") {
+			// 含不在候选中的代码与重复项，应被过滤
+			reply = "```json\n" + `{"picks":[{"code":"600519","reason":"放量突破前高"},{"code":"sz300750","reason":"不在候选"},` +
+				`{"code":"sz000001","reason":"跌破下轨注意风险"},{"code":"sh600519","reason":"重复"}],"summary":"整体偏谨慎"}` + "\n```"
+		} else {
+			mu.Lock()
+			agentPrompts = append(agentPrompts, prompt)
+			mu.Unlock()
+		}
+		return ollamaReply{Content: reply, DoneReason: "stop", PromptEval: 100, Eval: 20}
+	})
+
+	svc := NewServiceFull(nil, nil)
+	svc.SetMeetingBudget(models.MeetingBudget{MaxAgentTurns: 1})
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	rsi := 72.5
+	req := ScanRequest{
+		Items: []ScanItem{
+			{
+				Stock:      models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1688, ChangePercent: 2.1},
+				Indicators: &indicators.Indicators{MA: map[string]float64{"ma5": 1650}, RSI: &rsi},
+				News:       []string{"贵州茅台发布年报"},
+			},
+			{Stock: models.Stock{Symbol: "sz000001", Name: "平安银行", Price: 10.5, ChangePercent: -1.2}},
+			{Stock: models.Stock{Symbol: "sh600000", Name: "浦发银行", DataSuspect: true, SuspectReason: "行情获取失败"}},
+		},
+		Agents: []models.AgentConfig{
+			{ID: "a1", Name: "技术派", Role: "技术分析"},
+			{ID: "a2", Name: "基本面", Role: "基本面分析"},
+		},
+	}
+
+	var responses []ChatResponse
+	result, err := svc.RunWatchlistScan(context.Background(), aiConfig, req, func(resp ChatResponse) {
+		responses = append(responses, resp)
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, r := range responses {
+		ids = append(ids, r.AgentID+":"+r.MsgType)
+	}
+	// 发言预算对扫描小组同样生效
+	if got := strings.Join(ids, ","); got != "moderator:opening,a1:opinion,moderator:summary" {
+		t.Fatalf("responses = %s", got)
+	}
+	if len(agentPrompts) != 1 {
+		t.Fatalf("agent prompts = %d", len(agentPrompts))
+	}
+	prompt := agentPrompts[0]
+	for _, want := range []string{"贵州茅台(sh600519) 现价1688.00", "MA5 1650.00", "RSI14 72.5", "快讯：贵州茅台发布年报", "平安银行(sz000001)"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("agent prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "浦发银行") {
+		t.Errorf("suspect stock should be excluded from prompt:\n%s", prompt)
+	}
+
+	if len(result.Picks) != 2 || result.Picks[0] != (ScanPick{Rank: 1, Code: "sh600519", Name: "贵州茅台", Reason: "放量突破前高"}) ||
+		result.Picks[1].Code != "sz000001" || result.Picks[1].Rank != 2 {
+		t.Fatalf("picks = %+v", result.Picks)
+	}
+	if result.Summary != "整体偏谨慎" || len(result.Excluded) != 1 || result.Excluded[0].Code != "sh600000" {
+		t.Errorf("result = %+v", result)
+	}
+	summary := responses[len(responses)-1].Content
+	if !strings.Contains(summary, "1. 贵州茅台（sh600519）：放量突破前高") || !strings.Contains(summary, "浦发银行（行情获取失败）") {
+		t.Errorf("summary = %s", summary)
+	}
+	if u := svc.GetMeetingUsage(ScanSessionCode); u.Turns != 1 {
+		t.Errorf("usage = %+v", u)
+	}
+}
+
+func TestRunWatchlistScan_AllSuspect(t *testing.T) {
+	svc := NewServiceFull(nil, nil)
+	req := ScanRequest{
+		Items:  []ScanItem{{Stock: models.Stock{Symbol: "sh600000", Name: "浦发银行", DataSuspect: true}}},
+		Agents: []models.AgentConfig{{ID: "a1"}},
+	}
+	if _, err := svc.RunWatchlistScan(context.Background(), &models.AIConfig{}, req, nil, nil); err != ErrNoScanData {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestSplitScanItems_PreOpen(t *testing.T) {
+	// 9:05 晨间扫描：新浪现价为 0，仅有昨收
+	at := time.Date(2025, 3, 7, 9, 5, 0, 0, time.FixedZone("CST", 8*60*60))
+	var items []ScanItem
+	for _, s := range []models.Stock{
+		{Symbol: "sh600519", Name: "贵州茅台", PreClose: 1480},
+		{Symbol: "sz000001", Name: "平安银行", PreClose: 10.5},
+		{Symbol: "sh600000", Name: "浦发银行"},
+	} {
+		services.ValidateQuoteAt(&s, at)
+		items = append(items, ScanItem{Stock: s})
+	}
+	valid, excluded := splitScanItems(items)
+	if len(valid) != 2 || len(excluded) != 1 || excluded[0].Code != "sh600000" {
+		t.Fatalf("valid = %+v, excluded = %+v", valid, excluded)
+	}
+	data := buildScanData(valid)
+	if !strings.Contains(data, "贵州茅台(sh600519) 现价1480.00 涨跌+0.00%") || !strings.Contains(data, "状态：今日尚未开盘") {
+		t.Errorf("scan data = %s", data)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/run-bigpig/jcp/internal/adk"
@@ -29,11 +30,15 @@ var log = logger.New("Meeting")
 // 超时配置常量
 const (
 	MeetingTimeout       = 10 * time.Minute // 整个会议的最大时长
-	AgentTimeout         = 3 * time.Minute  // 单个专家发言的最大时长
+	AgentTimeout         = 3 * time.Minute  // 单个专家发言的默认最大时长，需容纳多次工具调用，故不采用 30 秒
 	ModeratorTimeout     = 2 * time.Minute  // 小韭菜分析/总结的最大时长
 	ModelCreationTimeout = 15 * time.Second // 模型创建的最大时长
+	ResumeWaitTimeout    = 10 * time.Second // 恢复会议时等待上一场会议退出的最长时间
 )
 
+// DefaultMaxRounds 未配置时智能会议专家发言的最大轮数
+const DefaultMaxRounds = 3
+
 // 重试配置常量
 const (
 	MaxAgentRetries = 2                // 单个专家最大重试次数
@@ -47,10 +52,11 @@ var (
 	ErrModeratorTimeout = errors.New("小韭菜响应超时")
 	ErrNoAIConfig       = errors.New("未配置 AI 服务")
 	ErrNoAgents         = errors.New("没有可用的专家")
+	ErrAgentTimeout     = errors.New("专家发言超时")
 )
 
 // isRetryableError 判断错误是否可重试
-// 超时、主动取消、配置错误不重试；网络错误、API 临时错误可重试
+// 超时、主动取消、配置错误不重试；HTTP 临时错误已在模型层重试，其余网络错误可重试
 func isRetryableError(err error) bool {
 	if err == nil {
 		return false
@@ -58,6 +64,13 @@ func isRetryableError(err error) bool {
 	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 		return false
 	}
+	if errors.Is(err, ErrAgentTimeout) || errors.Is(err, ErrImageInputUnsupported) {
+		return false
+	}
+	// 限流与服务端临时错误已由模型层 adk.RetryingLLM 退避重试，这里不再叠加一层
+	if adk.IsTransientLLMError(err) {
+		return false
+	}
 	msg := err.Error()
 	// 配置类错误不重试
 	if strings.Contains(msg, "config") || strings.Contains(msg, "not found") {
@@ -68,7 +81,8 @@ func isRetryableError(err error) bool {
 
 // retryRun 带指数退避的重试包装
 // 在父 ctx 未取消的前提下，最多重试 maxRetries 次
-func retryRun(ctx context.Context, maxRetries int, fn func() (string, error)) (string, error) {
+func retryRun[T any](ctx context.Context, maxRetries int, fn func() (T, error)) (T, error) {
+	var zero T
 	result, err := fn()
 	if err == nil || !isRetryableError(err) {
 		return result, err
@@ -85,7 +99,7 @@ func retryRun(ctx context.Context, maxRetries int, fn func() (string, error)) (s
 
 		select {
 		case <-ctx.Done():
-			return "", ctx.Err()
+			return zero, ctx.Err()
 		case <-time.After(delay):
 		}
 
@@ -96,30 +110,71 @@ func retryRun(ctx context.Context, maxRetries int, fn func() (string, error)) (s
 		}
 		lastErr = err
 		if !isRetryableError(err) {
-			return "", err
+			return zero, err
 		}
 	}
-	return "", fmt.Errorf("重试 %d 次后仍失败: %w", maxRetries, lastErr)
+	return zero, fmt.Errorf("重试 %d 次后仍失败: %w", maxRetries, lastErr)
 }
 
 // AIConfigResolver AI配置解析器函数类型
-// 根据 AIConfigID 返回对应的 AI 配置，如果 ID 为空或找不到则返回默认配置
+// 根据 AIConfigID 返回对应的 AI 配置，找不到时返回 nil（由调用方降级为会议配置）
 type AIConfigResolver func(aiConfigID string) *models.AIConfig
 
+// UsageRecorder token 用量记录函数类型，每位专家发言完成后调用
+type UsageRecorder func(stockCode string, usage models.TokenUsage)
+
 // MeetingState 中断的会议状态缓存（用于失败后恢复继续执行）
 type MeetingState struct {
 	AIConfig       *models.AIConfig
 	Stock          models.Stock
 	Query          string
+	Images         []*genai.Part // 用户消息附带的图片
 	Position       *models.StockPosition
 	SelectedAgents []models.AgentConfig // 全部选中的专家
+	Tasks          map[string]string    // 小韭菜为各专家分配的任务
+	Parallel       bool                 // 首轮并行发言（恢复时剩余专家同样并行、不注入其他专家发言）
+	ConcurrentMode bool                 // 并发模式，恢复时沿用同一并发上限
 	History        []DiscussionEntry    // 已完成的讨论历史
 	Responses      []ChatResponse       // 已完成的响应
-	FailedIndex    int                  // 失败的专家在 selectedAgents 中的索引
+	FailedIndex    int                  // 失败（或被取消）的专家在 selectedAgents 中的索引
+	Cancelled      bool                 // 由用户取消而中断，而非专家失败
 	MemoryContext  string               // 记忆上下文
 	StockMemory    *memory.StockMemory  // 股票记忆引用
 	Moderator      *Moderator           // 主持人引用（用于最终总结）
+	Mode           string               // 会议模式，debate 时总结前进行辩论
+	MaxRounds      int                  // 专家发言的最大轮数，0 时依次使用会议预算与 DefaultMaxRounds
+	AgentTimeout   time.Duration        // 单个专家发言超时，0 为默认
 	CreatedAt      time.Time            // 创建时间（用于 TTL 清理）
+
+	Freshness *models.DataFreshness // 会议开始时的行情数据时效（恢复时沿用）
+}
+
+// completed 已发言的专家 ID（恢复时跳过）
+func (st *MeetingState) completed() map[string]bool {
+	done := make(map[string]bool, len(st.History))
+	for _, h := range st.History {
+		done[h.AgentID] = true
+	}
+	return done
+}
+
+// agentQuery 返回主持人为专家分配的任务，没有时使用用户原始问题
+func (st *MeetingState) agentQuery(agentID string) string {
+	if task := st.Tasks[agentID]; task != "" {
+		return task
+	}
+	return st.Query
+}
+
+// resumeRequest 恢复并行会议时专家发言所需的请求参数
+func (st *MeetingState) resumeRequest() ChatRequest {
+	return ChatRequest{
+		Stock:          st.Stock,
+		Query:          st.Query,
+		Position:       st.Position,
+		ConcurrentMode: st.ConcurrentMode,
+		AgentTimeout:   st.AgentTimeout,
+	}
 }
 
 // MeetingStateTTL 中断状态缓存过期时间
@@ -134,8 +189,29 @@ type Service struct {
 	memoryAIConfig    *models.AIConfig         // 记忆管理使用的 LLM 配置
 	moderatorAIConfig *models.AIConfig         // 意图分析(小韭菜)使用的 LLM 配置
 	aiConfigResolver  AIConfigResolver         // AI配置解析器
+	thinkingPersist   models.ThinkingPersist   // 思考内容持久化策略
+	visibility        models.MeetingVisibility // 默认的专家间发言可见性
+	indexOverview     IndexOverviewProvider    // 指数概览数据（标的为指数时注入专家上下文）
+	dataFreshness     DataFreshnessProvider    // 行情数据时效（注入专家上下文并标注发言截至时间）
+	usageRecorder     UsageRecorder            // 专家 token 用量记录
+	runRecorder       MeetingRunRecorder       // 会议运行记录保存
+	reproducibility   models.Reproducibility   // 复现模式参数
+	warmupMode        models.ModelWarmup       // 会议前模型连接预热模式
+	factCheck         bool                     // 是否核对专家发言中的现价/涨跌幅
+	autoContinue      bool                     // 回复因长度截断时自动续写一次
+	promptPrefix      string                   // 全局系统指令前缀
+	promptSuffix      string                   // 全局系统指令后缀
+	argGuard          models.ToolArgGuard      // 外部工具调用参数的持仓敏感信息过滤
+	budget            models.MeetingBudget     // 智能会议发言预算
 	meetingStates     map[string]*MeetingState // 中断的会议状态缓存，key: stockCode
 	meetingStatesMu   sync.RWMutex
+	running           map[string]chan struct{} // 执行中的会议，结束时关闭，key: stockCode
+	runningMu         sync.Mutex
+	meetingUsage      map[string]*MeetingUsage // 会议用量统计，key: stockCode
+	usageMu           sync.Mutex
+	digests           map[string][]*MeetingDigest // 会议纯文本摘要（最近几场），key: stockCode
+	digestMu          sync.Mutex
+	digestListener    DigestListener // 会议摘要更新回调
 }
 
 // NewServiceFull 创建完整配置的会议室服务
@@ -144,7 +220,11 @@ func NewServiceFull(registry *tools.Registry, mcpMgr *mcp.Manager) *Service {
 		modelFactory:  adk.NewModelFactory(),
 		toolRegistry:  registry,
 		mcpManager:    mcpMgr,
+		factCheck:     true,
 		meetingStates: make(map[string]*MeetingState),
+		running:       make(map[string]chan struct{}),
+		meetingUsage:  make(map[string]*MeetingUsage),
+		digests:       make(map[string][]*MeetingDigest),
 	}
 }
 
@@ -154,20 +234,190 @@ func (s *Service) SetMemoryManager(memMgr *memory.Manager) {
 }
 
 // SetMemoryAIConfig 设置记忆管理使用的 LLM 配置
+// 传 nil 表示引用的配置已删除或未指定，改用会议默认 LLM
 func (s *Service) SetMemoryAIConfig(aiConfig *models.AIConfig) {
+	if aiConfig == nil && s.memoryAIConfig != nil {
+		log.Warn("memory AI config %s removed, memory will use meeting LLM", s.memoryAIConfig.ID)
+	}
 	s.memoryAIConfig = aiConfig
 }
 
 // SetModeratorAIConfig 设置意图分析(小韭菜)使用的 LLM 配置
+// 传 nil 表示引用的配置已删除或未指定，改用会议默认 LLM
 func (s *Service) SetModeratorAIConfig(aiConfig *models.AIConfig) {
+	if aiConfig == nil && s.moderatorAIConfig != nil {
+		log.Warn("moderator AI config %s removed, moderator will use meeting LLM", s.moderatorAIConfig.ID)
+	}
 	s.moderatorAIConfig = aiConfig
 }
 
+// moderatorLLM 创建意图分析使用的 LLM，独立配置不可用时回退到会议 LLM
+func (s *Service) moderatorLLM(ctx context.Context, fallback model.LLM, fallbackConfig *models.AIConfig) model.LLM {
+	run := meetingRunFrom(ctx)
+	cfg := s.moderatorAIConfig
+	if cfg == nil {
+		return run.bind(moderatorRunAgent, nil, adk.NewRetryingLLM(fallback, fallbackConfig))
+	}
+	llm, err := s.modelFactory.CreateModel(ctx, cfg)
+	if err != nil {
+		log.Error("create moderator LLM (%s/%s) failed, FALLING BACK to meeting LLM: %v", cfg.ID, cfg.ModelName, err)
+		return run.bind(moderatorRunAgent, nil, adk.NewRetryingLLM(fallback, fallbackConfig))
+	}
+	log.Debug("using dedicated moderator LLM: %s", cfg.ModelName)
+	return run.bind(moderatorRunAgent, cfg, adk.NewRetryingLLM(llm, cfg))
+}
+
+// applyMemoryLLM 设置记忆管理器的 LLM，独立配置不可用时回退到会议 LLM
+func (s *Service) applyMemoryLLM(ctx context.Context, fallback model.LLM) {
+	if s.memoryManager == nil {
+		return
+	}
+	cfg := s.memoryAIConfig
+	if cfg == nil {
+		s.memoryManager.SetLLM(fallback)
+		return
+	}
+	llm, err := s.modelFactory.CreateModel(ctx, cfg)
+	if err != nil {
+		log.Error("create memory LLM (%s/%s) failed, FALLING BACK to meeting LLM: %v", cfg.ID, cfg.ModelName, err)
+		s.memoryManager.SetLLM(fallback)
+		return
+	}
+	log.Debug("using dedicated memory LLM: %s", cfg.ModelName)
+	s.memoryManager.SetLLM(llm)
+}
+
 // SetAIConfigResolver 设置 AI 配置解析器
 func (s *Service) SetAIConfigResolver(resolver AIConfigResolver) {
 	s.aiConfigResolver = resolver
 }
 
+// SetThinkingPersist 设置思考内容持久化策略
+func (s *Service) SetThinkingPersist(mode models.ThinkingPersist) {
+	s.thinkingPersist = mode
+}
+
+// SetMeetingVisibility 设置默认的专家间发言可见性（请求未指定时使用）
+func (s *Service) SetMeetingVisibility(v models.MeetingVisibility) {
+	s.visibility = v
+}
+
+// IndexOverviewProvider 指数概览数据提供者，随会议取消而中止请求
+type IndexOverviewProvider func(ctx context.Context, code string) (*models.IndexOverview, error)
+
+// SetIndexOverviewProvider 设置指数概览数据提供者
+func (s *Service) SetIndexOverviewProvider(p IndexOverviewProvider) {
+	s.indexOverview = p
+}
+
+// SetUsageRecorder 设置专家 token 用量记录器
+func (s *Service) SetUsageRecorder(recorder UsageRecorder) {
+	s.usageRecorder = recorder
+}
+
+// SetModelWarmup 设置模型连接预热模式
+func (s *Service) SetModelWarmup(mode models.ModelWarmup) {
+	s.warmupMode = mode
+}
+
+// SetFactCheck 设置是否核对专家发言中的行情数字
+func (s *Service) SetFactCheck(enabled bool) {
+	s.factCheck = enabled
+}
+
+// SetSystemPromptAffixes 设置注入每位专家系统指令首尾的全局前缀与后缀
+func (s *Service) SetSystemPromptAffixes(prefix, suffix string) {
+	s.promptPrefix = prefix
+	s.promptSuffix = suffix
+}
+
+// SetToolArgGuard 设置外部工具调用参数的敏感信息过滤配置
+func (s *Service) SetToolArgGuard(cfg models.ToolArgGuard) {
+	s.argGuard = cfg
+}
+
+// WarmupConnections 预热即将参会的模型连接（主持人 + 各专家解析后的配置）
+// 在会议开始前或聚焦股票时异步调用，未开启预热时直接返回
+func (s *Service) WarmupConnections(ctx context.Context, aiConfig *models.AIConfig, agents []models.AgentConfig) int {
+	if s.warmupMode == "" || s.warmupMode == models.ModelWarmupOff || aiConfig == nil {
+		return 0
+	}
+	configs := []*models.AIConfig{aiConfig, s.moderatorAIConfig}
+	for i := range agents {
+		configs = append(configs, s.resolveAgentAIConfig(&agents[i], aiConfig))
+	}
+	return s.modelFactory.Warmup(ctx, configs, s.warmupMode)
+}
+
+// primeTimeout 单个模型预热请求的超时
+const primeTimeout = 15 * time.Second
+
+// PrimeAgentModels 为会议配置、主持人与各专家解析后的模型各发送一次 1 token 的 GenerateContent
+// 提前完成客户端初始化、建连与鉴权；按配置 ID 去重，返回请求成功的模型数
+func (s *Service) PrimeAgentModels(ctx context.Context, aiConfig *models.AIConfig, agents []models.AgentConfig) int {
+	if aiConfig == nil {
+		return 0
+	}
+	configs := []*models.AIConfig{aiConfig, s.moderatorAIConfig}
+	for i := range agents {
+		configs = append(configs, s.resolveAgentAIConfig(&agents[i], aiConfig))
+	}
+
+	var (
+		wg     sync.WaitGroup
+		primed atomic.Int32
+		seen   = make(map[string]bool)
+		sem    = make(chan struct{}, adk.MaxConcurrentWarmups)
+	)
+	for _, cfg := range configs {
+		if cfg == nil || seen[cfg.ID] {
+			continue
+		}
+		seen[cfg.ID] = true
+		wg.Add(1)
+		go func(cfg *models.AIConfig) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := s.primeModel(ctx, cfg); err != nil {
+				log.Warn("prime model %s failed: %v", cfg.ModelName, err)
+				return
+			}
+			primed.Add(1)
+		}(cfg)
+	}
+	wg.Wait()
+	return int(primed.Load())
+}
+
+// primeModel 创建模型并发送最小请求
+func (s *Service) primeModel(ctx context.Context, cfg *models.AIConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, primeTimeout)
+	defer cancel()
+	llm, err := s.modelFactory.CreateModel(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)},
+		Config:   &genai.GenerateContentConfig{MaxOutputTokens: 1},
+	}
+	for _, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveVisibility 解析本次会议的可见性：请求优先，其次全局配置
+func (s *Service) resolveVisibility(req ChatRequest) models.MeetingVisibility {
+	if req.Visibility != "" {
+		return normalizeVisibility(req.Visibility)
+	}
+	return normalizeVisibility(s.visibility)
+}
+
 // ChatRequest 聊天请求
 type ChatRequest struct {
 	StockCode    string                `json:"stockCode"` // 股票代码（用于状态缓存 key）
@@ -175,9 +425,24 @@ type ChatRequest struct {
 	KLineData    []models.KLineData    `json:"klineData"`
 	Agents       []models.AgentConfig  `json:"agents"`
 	Query        string                `json:"query"`
+	Images       []string              `json:"images,omitempty"` // 附带图片：data URL、base64 或本地文件路径
 	ReplyContent string                `json:"replyContent"`
 	AllAgents    []models.AgentConfig  `json:"allAgents"` // 所有可用专家（智能模式用）
 	Position     *models.StockPosition `json:"position"`  // 用户持仓信息
+
+	Visibility     models.MeetingVisibility `json:"visibility,omitempty"`     // 专家间发言可见性（空则使用全局配置）
+	ParallelRounds bool                     `json:"parallelRounds,omitempty"` // 智能模式首轮专家并行发言
+	ConcurrentMode bool                     `json:"concurrentMode,omitempty"` // 专家任务相互独立时并发发言，同时运行数上限为 CPU 核数
+
+	AllowSuspectData bool `json:"allowSuspectData,omitempty"` // 行情校验异常时仍然开会（用户确认）
+
+	Mode string `json:"mode,omitempty"` // 智能会议模式：空为默认智能模式，debate 为辩论模式
+
+	MaxRounds    int           `json:"maxRounds,omitempty"`    // 专家发言的最大轮数（首轮分析、辩论反驳轮），0 为 DefaultMaxRounds
+	AgentTimeout time.Duration `json:"agentTimeout,omitempty"` // 单个专家发言超时，0 为默认（见 agentTimeout）
+
+	Reproducible bool  `json:"reproducible,omitempty"` // 复现模式：统一低温度、固定随机种子与系统指令中的当前时间
+	Seed         int32 `json:"seed,omitempty"`         // 复现模式的随机种子，0 使用配置值（传入历史运行记录的种子即可重跑）
 }
 
 // 会议模式常量
@@ -188,27 +453,66 @@ const (
 
 // ChatResponse 聊天响应
 type ChatResponse struct {
-	AgentID     string `json:"agentId"`
-	AgentName   string `json:"agentName"`
-	Role        string `json:"role"`
-	Content     string `json:"content"`
-	Round       int    `json:"round"`
-	MsgType     string `json:"msgType"`               // opening/opinion/summary
-	Error       string `json:"error,omitempty"`       // 失败时的错误信息，前端据此显示重试按钮
-	MeetingMode string `json:"meetingMode,omitempty"` // smart=串行, direct=独立
+	AgentID     string   `json:"agentId"`
+	AgentName   string   `json:"agentName"`
+	Role        string   `json:"role"`
+	Content     string   `json:"content"`
+	Round       int      `json:"round"`
+	MsgType     string   `json:"msgType"`               // opening/opinion/summary
+	Error       string   `json:"error,omitempty"`       // 失败时的错误信息，前端据此显示重试按钮
+	MeetingMode string   `json:"meetingMode,omitempty"` // smart=串行, direct=独立, debate=辩论轮
+	RoundLabel  string   `json:"roundLabel,omitempty"`  // 轮次标签（辩论模式），前端据此分组展示
+	Rebuts      string   `json:"rebuts,omitempty"`      // 反驳的专家 ID（rebuttal）
+	Reasoning   string   `json:"reasoning,omitempty"`   // 思考内容（已按持久化策略处理）
+	ToolCalls   []string `json:"toolCalls,omitempty"`   // 本次发言调用的工具（名称与参数摘要）
+	ToolResults []string `json:"toolResults,omitempty"` // 与 ToolCalls 一一对应的工具结果 JSON（截断），无结果为空串
+	// 结束原因（genai.FinishReason），MAX_TOKENS 表示回复因长度限制被截断
+	FinishReason string `json:"finishReason,omitempty"`
+	// 本次发言的 token 用量（仅专家发言）
+	PromptTokens     int64 `json:"promptTokens,omitempty"`
+	CompletionTokens int64 `json:"completionTokens,omitempty"`
+	// 发言所依据行情数据的截至时间，随消息保存以便导出后仍可分辨
+	AsOf string `json:"asOf,omitempty"`
 }
 
 // ResponseCallback 响应回调函数类型
 // 每当有新的发言产生时调用，用于实时推送到前端
 type ResponseCallback func(resp ChatResponse)
 
+// 流式增量事件类型
+const (
+	ProgressTypeTextDelta     = "streaming" // 正文增量
+	ProgressTypeThinkingDelta = "thinking"  // 思考内容增量
+)
+
 // ProgressEvent 进度事件（细粒度实时反馈）
 type ProgressEvent struct {
-	Type      string `json:"type"`      // thinking/tool_call/tool_result/streaming/agent_start/agent_done
-	AgentID   string `json:"agentId"`   // 当前专家 ID
-	AgentName string `json:"agentName"` // 当前专家名称
-	Detail    string `json:"detail"`    // 工具名称或阶段描述
-	Content   string `json:"content"`   // 流式文本片段或工具结果摘要
+	Type      string        `json:"type"`              // thinking/tool_call/tool_result/tool_call_started/tool_call_finished/streaming/agent_start/agent_done
+	AgentID   string        `json:"agentId"`           // 当前专家 ID
+	AgentName string        `json:"agentName"`         // 当前专家名称
+	Detail    string        `json:"detail"`            // 工具名称或阶段描述
+	Model     string        `json:"model,omitempty"`   // 专家使用的模型（仅 agent_start）
+	Content   string        `json:"content"`           // 流式文本片段或工具结果摘要
+	Partial   bool          `json:"partial,omitempty"` // 是否为 LLM 流式增量片段
+	Usage     *MeetingUsage `json:"usage,omitempty"`   // 会议累计用量（仅 usage 事件）
+	// 工具执行事件（tool_call_started/tool_call_finished）
+	Args       string `json:"args,omitempty"`       // 格式化后的调用参数
+	DurationMs int64  `json:"durationMs,omitempty"` // 工具执行耗时
+	// 长耗时工具进度（tool_progress）
+	Percent   float64 `json:"percent,omitempty"`   // 进度百分比，0 表示未知
+	RequestID string  `json:"requestId,omitempty"` // 工具调用 ID，用于取消该调用
+}
+
+// newDeltaEvent 构造一个流式增量事件（每个 Partial LLMResponse 对应一个）
+func newDeltaEvent(agentID, agentName, text string, thought bool) ProgressEvent {
+	eventType := ProgressTypeTextDelta
+	if thought {
+		eventType = ProgressTypeThinkingDelta
+	}
+	return ProgressEvent{
+		Type: eventType, AgentID: agentID, AgentName: agentName,
+		Content: text, Partial: true,
+	}
 }
 
 // ProgressCallback 进度回调函数类型
@@ -223,14 +527,33 @@ func emitProgress(cb ProgressCallback, event ProgressEvent) {
 
 // SendMessage 发送会议消息，生成多专家回复（并行执行）
 func (s *Service) SendMessage(ctx context.Context, aiConfig *models.AIConfig, req ChatRequest) ([]ChatResponse, error) {
+	return s.SendMessageWithCallback(ctx, aiConfig, req, nil)
+}
+
+// SendMessageWithCallback 发送会议消息（并行执行），与智能模式一样推送专家开始/结束、工具调用等进度事件
+func (s *Service) SendMessageWithCallback(ctx context.Context, aiConfig *models.AIConfig, req ChatRequest, progressCallback ProgressCallback) ([]ChatResponse, error) {
+	if err := checkStockData(req, progressCallback); err != nil {
+		return nil, err
+	}
+	images, err := decodeImages(req.Images)
+	if err != nil {
+		return nil, err
+	}
 	llm, err := s.modelFactory.CreateModel(ctx, aiConfig)
 	if err != nil {
 		log.Error("CreateModel error: %v", err)
 		return nil, err
 	}
 	log.Info("model created successfully")
-
-	return s.runAgentsParallel(ctx, llm, aiConfig, req)
+	s.applyMemoryLLM(ctx, llm)
+	s.recordUserActions(req.Stock.Symbol, req.Query)
+
+	s.resetMeetingUsage(req.Stock.Symbol)
+	ctx, finishRun := s.beginMeetingRun(ctx, aiConfig, s.startDigest(req.Stock.Symbol), MeetingModeDirect, req)
+	defer finishRun()
+	responses, err := s.runAgentsParallel(ctx, llm, aiConfig, req, images, progressCallback)
+	stampResponsesAsOf(ctx, responses)
+	return responses, err
 }
 
 // RunSmartMeeting 智能会议模式（小韭菜编排）
@@ -248,9 +571,16 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 	if len(req.AllAgents) == 0 {
 		return "", ErrNoAgents
 	}
+	req.MaxRounds = s.roundLimit(req.MaxRounds)
+	if err := checkStockData(req, nil); err != nil {
+		return "", err
+	}
+	s.resetMeetingUsage(req.Stock.Symbol)
+	ctx, finishRun := s.beginMeetingRun(ctx, aiConfig, s.startDigest(req.Stock.Symbol), MeetingModeSmart, req)
+	defer finishRun()
 
 	// 设置整个会议的超时上下文
-	meetingCtx, meetingCancel := context.WithTimeout(ctx, MeetingTimeout)
+	meetingCtx, meetingCancel := withExtendableTimeout(ctx, MeetingTimeout)
 	defer meetingCancel()
 
 	// 创建模型
@@ -262,31 +592,12 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 	}
 
 	// 创建 Moderator LLM
-	var moderatorLLM model.LLM
-	if s.moderatorAIConfig != nil {
-		moderatorLLM, err = s.modelFactory.CreateModel(meetingCtx, s.moderatorAIConfig)
-		if err != nil {
-			log.Warn("create moderator LLM error, fallback to default: %v", err)
-			moderatorLLM = llm
-		}
-	} else {
-		moderatorLLM = llm
-	}
-	moderator := NewModerator(moderatorLLM)
+	moderator := NewModerator(s.moderatorLLM(meetingCtx, llm, aiConfig))
+	moderator.SetVisibility(s.resolveVisibility(req))
 
 	// 设置记忆 LLM
-	if s.memoryManager != nil {
-		if s.memoryAIConfig != nil {
-			memoryLLM, err := s.modelFactory.CreateModel(meetingCtx, s.memoryAIConfig)
-			if err == nil {
-				s.memoryManager.SetLLM(memoryLLM)
-			} else {
-				s.memoryManager.SetLLM(llm)
-			}
-		} else {
-			s.memoryManager.SetLLM(llm)
-		}
-	}
+	s.applyMemoryLLM(meetingCtx, llm)
+	agentModels := s.newAgentModels(aiConfig, llm)
 
 	// 加载股票记忆
 	var stockMemory *memory.StockMemory
@@ -315,6 +626,10 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 	if len(selectedAgents) == 0 {
 		return "", fmt.Errorf("小韭菜未选中任何有效专家")
 	}
+	selectedAgents = s.applyTurnBudget(selectedAgents, nil)
+	if !allowRound(req.MaxRounds, RoundAnalysis, nil) {
+		return "", nil
+	}
 
 	// 第1轮：专家串行发言，失败时跳过继续
 	var history []DiscussionEntry
@@ -326,15 +641,14 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 
 		log.Debug("[OpenClaw] agent %d/%d: %s starting", i+1, len(selectedAgents), agentCfg.Name)
 
-		agentAIConfig := s.resolveAgentAIConfig(&agentCfg, aiConfig)
-		agentLLM, err := s.modelFactory.CreateModel(meetingCtx, agentAIConfig)
+		agentLLM, agentAIConfig, err := agentModels.forAgent(meetingCtx, &agentCfg)
 		if err != nil {
 			log.Error("[OpenClaw] create agent LLM error, skip %s: %v", agentCfg.ID, err)
 			continue
 		}
-		builder := s.createBuilder(agentLLM, agentAIConfig)
+		builder := s.createBuilder(meetingCtx, agentLLM, agentAIConfig)
 
-		previousContext := s.buildPreviousContext(history)
+		previousContext := buildPeerContext(history, moderator.visibility)
 		if memoryContext != "" {
 			previousContext = memoryContext + "\n" + previousContext
 		}
@@ -346,10 +660,10 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 			}
 		}
 
-		content, err := retryRun(meetingCtx, MaxAgentRetries, func() (string, error) {
-			agentCtx, agentCancel := context.WithTimeout(meetingCtx, AgentTimeout)
-			defer agentCancel()
-			return s.runSingleAgent(agentCtx, builder, &agentCfg, &req.Stock, agentQuery, previousContext, nil, req.Position)
+		out, err := retryRun(meetingCtx, MaxAgentRetries, func() (agentOutput, error) {
+			return runAgentWithTimeout(meetingCtx, agentTimeout(builder.AIConfig(), req.AgentTimeout), func(agentCtx context.Context) (agentOutput, error) {
+				return s.runSingleAgent(agentCtx, builder, &agentCfg, &req.Stock, agentQuery, nil, previousContext, nil, req.Position)
+			})
 		})
 
 		if err != nil {
@@ -359,9 +673,9 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 
 		history = append(history, DiscussionEntry{
 			Round: 1, AgentID: agentCfg.ID, AgentName: agentCfg.Name,
-			Role: agentCfg.Role, Content: content,
+			Role: agentCfg.Role, Content: out.Content,
 		})
-		log.Debug("[OpenClaw] agent %s done, content len: %d", agentCfg.ID, len(content))
+		log.Debug("[OpenClaw] agent %s done, content len: %d", agentCfg.ID, len(out.Content))
 	}
 
 	if len(history) == 0 {
@@ -394,16 +708,39 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 // RunSmartMeetingWithCallback 智能会议模式（带实时回调）
 // respCallback 在每个发言完成后调用
 // progressCallback 在工具调用、流式输出等细粒度事件时调用
-func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *models.AIConfig, req ChatRequest, respCallback ResponseCallback, progressCallback ProgressCallback) ([]ChatResponse, error) {
+func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *models.AIConfig, req ChatRequest, respCallback ResponseCallback, progressCallback ProgressCallback) (out []ChatResponse, err error) {
 	if aiConfig == nil {
 		return nil, ErrNoAIConfig
 	}
 	if len(req.AllAgents) == 0 {
 		return nil, ErrNoAgents
 	}
+	req.MaxRounds = s.roundLimit(req.MaxRounds)
+	if err := checkStockData(req, progressCallback); err != nil {
+		return nil, err
+	}
+	images, err := decodeImages(req.Images)
+	if err != nil {
+		return nil, err
+	}
+	s.resetMeetingUsage(req.Stock.Symbol)
+	mode := MeetingModeSmart
+	if req.Mode == MeetingModeDebate {
+		mode = MeetingModeDebate
+		respCallback = debateCallback(respCallback)
+	}
+	ctx, finishRun := s.beginMeetingRun(ctx, aiConfig, s.startDigest(req.Stock.Symbol), mode, req)
+	defer finishRun()
+	respCallback = asOfCallback(ctx, respCallback)
+	defer func() { stampResponsesAsOf(ctx, out) }()
+	if req.StockCode != "" {
+		// 新问题开始后，上一场会议的中断状态不再可恢复
+		s.dropMeetingState(req.StockCode)
+		defer s.beginRun(req.StockCode)()
+	}
 
 	// 设置整个会议的超时上下文
-	meetingCtx, meetingCancel := context.WithTimeout(ctx, MeetingTimeout)
+	meetingCtx, meetingCancel := withExtendableTimeout(ctx, MeetingTimeout)
 	defer meetingCancel()
 
 	// 创建模型（带超时）
@@ -417,36 +754,13 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 	var responses []ChatResponse
 
 	// 创建 Moderator LLM（优先使用独立配置）
-	var moderatorLLM model.LLM
-	if s.moderatorAIConfig != nil {
-		moderatorLLM, err = s.modelFactory.CreateModel(meetingCtx, s.moderatorAIConfig)
-		if err != nil {
-			log.Warn("create moderator LLM error, fallback to default: %v", err)
-			moderatorLLM = llm
-		} else {
-			log.Debug("using dedicated moderator LLM: %s", s.moderatorAIConfig.ModelName)
-		}
-	} else {
-		moderatorLLM = llm
-	}
-	moderator := NewModerator(moderatorLLM)
+	moderator := NewModerator(s.moderatorLLM(meetingCtx, llm, aiConfig))
+	moderator.SetVisibility(s.resolveVisibility(req))
 
-	// 设置 LLM 到记忆管理器（启用摘要功能）
-	if s.memoryManager != nil {
-		// 优先使用配置的记忆 LLM，否则使用会议 LLM
-		if s.memoryAIConfig != nil {
-			memoryLLM, err := s.modelFactory.CreateModel(meetingCtx, s.memoryAIConfig)
-			if err == nil {
-				s.memoryManager.SetLLM(memoryLLM)
-				log.Debug("using dedicated memory LLM: %s", s.memoryAIConfig.ModelName)
-			} else {
-				log.Warn("create memory LLM error, fallback to meeting LLM: %v", err)
-				s.memoryManager.SetLLM(llm)
-			}
-		} else {
-			s.memoryManager.SetLLM(llm)
-		}
-	}
+	// 设置 LLM 到记忆管理器（启用摘要功能），优先使用配置的记忆 LLM，否则使用会议 LLM
+	s.applyMemoryLLM(meetingCtx, llm)
+	s.recordUserActions(req.Stock.Symbol, req.Query)
+	agentModels := s.newAgentModels(aiConfig, llm)
 
 	// 加载股票记忆（如果启用了记忆管理）
 	var stockMemory *memory.StockMemory
@@ -467,7 +781,7 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 	})
 
 	moderatorCtx, moderatorCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
-	decision, err := moderator.Analyze(moderatorCtx, &req.Stock, req.Query, req.AllAgents)
+	decision, err := moderator.Analyze(moderatorCtx, &req.Stock, imageHint(req.Query, len(images)), req.AllAgents)
 	moderatorCancel()
 
 	if err != nil {
@@ -506,148 +820,121 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 	if len(selectedAgents) == 0 {
 		return responses, nil
 	}
+	selectedAgents = s.applyTurnBudget(selectedAgents, progressCallback)
+	// 专家发言的每一轮都受策略的轮数上限约束，反驳轮在 runDebate 中检查
+	if !allowRound(req.MaxRounds, RoundAnalysis, progressCallback) {
+		return responses, nil
+	}
 
-	// 第1轮：专家串行发言，后一个参考前面的内容
 	var history []DiscussionEntry
 
-	for i, agentCfg := range selectedAgents {
-		// 检查会议是否已超时
-		select {
-		case <-meetingCtx.Done():
-			log.Warn("meeting timeout, got %d responses", len(responses))
-			return responses, ErrMeetingTimeout
-		default:
+	// snapshot 记录当前进度，index 为下一位待发言专家在 selectedAgents 中的下标
+	snapshot := func(index int) *MeetingState {
+		return &MeetingState{
+			AIConfig:       aiConfig,
+			Stock:          req.Stock,
+			Query:          req.Query,
+			Images:         images,
+			Position:       req.Position,
+			SelectedAgents: selectedAgents,
+			Tasks:          decision.Tasks,
+			Parallel:       req.ParallelRounds || req.ConcurrentMode,
+			ConcurrentMode: req.ConcurrentMode,
+			History:        history,
+			Responses:      responses,
+			FailedIndex:    index,
+			MemoryContext:  memoryContext,
+			StockMemory:    stockMemory,
+			Moderator:      moderator,
+			Mode:           req.Mode,
+			MaxRounds:      req.MaxRounds,
+			AgentTimeout:   req.AgentTimeout,
+			Freshness:      dataFreshnessFrom(ctx),
+			CreatedAt:      time.Now(),
 		}
+	}
 
-		log.Debug("agent %d/%d: %s starting", i+1, len(selectedAgents), agentCfg.Name)
-
-		// 获取该专家的 AI 配置
-		agentAIConfig := s.resolveAgentAIConfig(&agentCfg, aiConfig)
-
-		// 为该专家创建 LLM
-		agentLLM, err := s.modelFactory.CreateModel(meetingCtx, agentAIConfig)
-		if err != nil {
-			log.Error("create agent LLM error: %v", err)
-			continue
+	if req.ParallelRounds || req.ConcurrentMode {
+		// 第1轮：专家并行发言，互不参考
+		var first []ChatResponse
+		first, history = s.runFirstRoundParallel(meetingCtx, agentModels, req, images, selectedAgents, decision.Tasks, memoryContext, respCallback, progressCallback)
+		responses = append(responses, first...)
+		if meetingCtx.Err() != nil {
+			s.cacheCancelledMeeting(ctx, req.StockCode, snapshot(0))
+			log.Warn("meeting timeout, got %d responses", len(responses))
+			return responses, ErrMeetingTimeout
 		}
-		builder := s.createBuilder(agentLLM, agentAIConfig)
-
-		// 发送专家开始事件
-		emitProgress(progressCallback, ProgressEvent{
-			Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
-		})
-
-		// 构建前面专家发言的上下文
-		previousContext := s.buildPreviousContext(history)
-		// 合并记忆上下文
-		if memoryContext != "" {
-			previousContext = memoryContext + "\n" + previousContext
+		if len(history) == 0 {
+			log.Warn("all agents failed in parallel round, skipping summary")
+			return responses, nil
 		}
-
-		// 获取主持人为该专家分配的专属任务，若无则降级为用户原始问题
-		agentQuery := req.Query
-		if decision.Tasks != nil {
-			if task, ok := decision.Tasks[agentCfg.ID]; ok && task != "" {
-				agentQuery = task
+	} else {
+		// 第1轮：专家串行发言，后一个参考前面的内容
+		for i, agentCfg := range selectedAgents {
+			// 检查会议是否已超时
+			select {
+			case <-meetingCtx.Done():
+				s.cacheCancelledMeeting(ctx, req.StockCode, snapshot(i))
+				log.Warn("meeting timeout, got %d responses", len(responses))
+				return responses, ErrMeetingTimeout
+			default:
 			}
-		}
 
-		// 运行单个专家（带超时控制 + 指数退避重试）
-		content, err := retryRun(meetingCtx, MaxAgentRetries, func() (string, error) {
-			agentCtx, agentCancel := context.WithTimeout(meetingCtx, AgentTimeout)
-			defer agentCancel()
-			return s.runSingleAgent(agentCtx, builder, &agentCfg, &req.Stock, agentQuery, previousContext, progressCallback, req.Position)
-		})
+			log.Debug("agent %d/%d: %s starting", i+1, len(selectedAgents), agentCfg.Name)
 
-		if err != nil {
-			emitProgress(progressCallback, ProgressEvent{
-				Type: "agent_error", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: err.Error(),
-			})
-			emitProgress(progressCallback, ProgressEvent{
-				Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
-			})
-			log.Error("agent %s failed after retries: %v", agentCfg.ID, err)
+			// 构建前面专家发言的上下文
+			previousContext := buildPeerContext(history, moderator.visibility)
+			// 合并记忆上下文
+			if memoryContext != "" {
+				previousContext = memoryContext + "\n" + previousContext
+			}
 
-			// 将失败的 agent 加入响应，标记错误
-			failedResp := ChatResponse{
-				AgentID:     agentCfg.ID,
-				AgentName:   agentCfg.Name,
-				Role:        agentCfg.Role,
-				Content:     "",
-				Round:       1,
-				MsgType:     "opinion",
-				Error:       err.Error(),
-				MeetingMode: MeetingModeSmart,
+			resp, err := s.runOpinionAgent(meetingCtx, agentModels, req, images, &agentCfg, decision.Tasks, previousContext, progressCallback)
+			if resp == nil {
+				continue
 			}
-			responses = append(responses, failedResp)
+			if err != nil && s.cacheCancelledMeeting(ctx, req.StockCode, snapshot(i)) {
+				// 用户取消：不记录失败消息，恢复时从该专家重新开始
+				break
+			}
+
+			// 添加到响应并立即回调（失败的专家标记错误）
+			responses = append(responses, *resp)
 			if respCallback != nil {
-				respCallback(failedResp)
+				respCallback(*resp)
 			}
 
-			// 缓存中断状态，用于后续恢复继续执行
-			if req.StockCode != "" {
-				s.cacheMeetingState(req.StockCode, &MeetingState{
-					AIConfig:       aiConfig,
-					Stock:          req.Stock,
-					Query:          req.Query,
-					Position:       req.Position,
-					SelectedAgents: selectedAgents,
-					History:        history,
-					Responses:      responses,
-					FailedIndex:    i,
-					MemoryContext:  memoryContext,
-					StockMemory:    stockMemory,
-					Moderator:      moderator,
-					CreatedAt:      time.Now(),
-				})
+			if err != nil {
+				// 缓存中断状态，用于后续恢复继续执行
+				if req.StockCode != "" {
+					s.cacheMeetingState(req.StockCode, snapshot(i))
+
+					// 收集剩余专家 ID
+					remainingIDs := make([]string, 0, len(selectedAgents)-i-1)
+					for _, ra := range selectedAgents[i+1:] {
+						remainingIDs = append(remainingIDs, ra.ID)
+					}
 
-				// 收集剩余专家 ID
-				remainingIDs := make([]string, 0, len(selectedAgents)-i-1)
-				for _, ra := range selectedAgents[i+1:] {
-					remainingIDs = append(remainingIDs, ra.ID)
+					// 发送 meeting_interrupted 事件
+					emitProgress(progressCallback, ProgressEvent{
+						Type: "meeting_interrupted", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+						Detail: err.Error(), Content: strings.Join(remainingIDs, ","),
+					})
 				}
 
-				// 发送 meeting_interrupted 事件
-				emitProgress(progressCallback, ProgressEvent{
-					Type: "meeting_interrupted", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
-					Detail: err.Error(), Content: strings.Join(remainingIDs, ","),
-				})
+				// 中断串行执行，不再继续后续专家
+				break
 			}
 
-			// 中断串行执行，不再继续后续专家
-			break
-		}
-
-		// 发送专家完成事件
-		emitProgress(progressCallback, ProgressEvent{
-			Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
-		})
-
-		// 添加到响应并立即回调
-		resp := ChatResponse{
-			AgentID:     agentCfg.ID,
-			AgentName:   agentCfg.Name,
-			Role:        agentCfg.Role,
-			Content:     content,
-			Round:       1,
-			MsgType:     "opinion",
-			MeetingMode: MeetingModeSmart,
-		}
-		responses = append(responses, resp)
-		if respCallback != nil {
-			respCallback(resp)
+			// 记录到历史（仅正文，思考内容不回注后续专家的上下文）
+			history = append(history, DiscussionEntry{
+				Round:     1,
+				AgentID:   agentCfg.ID,
+				AgentName: agentCfg.Name,
+				Role:      agentCfg.Role,
+				Content:   resp.Content,
+			})
 		}
-
-		// 记录到历史
-		history = append(history, DiscussionEntry{
-			Round:     1,
-			AgentID:   agentCfg.ID,
-			AgentName: agentCfg.Name,
-			Role:      agentCfg.Role,
-			Content:   content,
-		})
-
-		log.Debug("agent %s done, content len: %d", agentCfg.ID, len(content))
 	}
 
 	// 检查是否被中断（有缓存状态说明中断了，跳过总结）
@@ -661,13 +948,23 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 		}
 	}
 
+	// 辩论模式：反驳轮 + 裁决代替总结
+	if req.Mode == MeetingModeDebate {
+		responses = s.runDebate(meetingCtx, snapshot(len(selectedAgents)), agentModels, respCallback, progressCallback)
+		if n := len(responses); n == 0 || responses[n-1].MsgType != "summary" {
+			// 辩论被取消时保留首轮进度，恢复后重新辩论
+			s.cacheCancelledMeeting(ctx, req.StockCode, snapshot(len(selectedAgents)))
+		}
+		return responses, nil
+	}
+
 	// 最终轮：小韭菜总结（带超时）
 	emitProgress(progressCallback, ProgressEvent{
 		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "总结讨论",
 	})
 
 	summaryCtx, summaryCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
-	summary, err := moderator.Summarize(summaryCtx, &req.Stock, req.Query, history)
+	summary, err := moderator.SummarizeStream(summaryCtx, &req.Stock, req.Query, history, moderatorDeltaFunc(progressCallback))
 	summaryCancel()
 
 	emitProgress(progressCallback, ProgressEvent{
@@ -675,6 +972,9 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 	})
 
 	if err != nil {
+		if s.cacheCancelledMeeting(ctx, req.StockCode, snapshot(len(selectedAgents))) {
+			return responses, nil
+		}
 		if errors.Is(err, context.DeadlineExceeded) {
 			log.Warn("summary timeout, returning partial results")
 		} else {
@@ -719,7 +1019,7 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 }
 
 // runAgentsParallel 并行运行多个 Agent（带超时控制）
-func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, defaultAIConfig *models.AIConfig, req ChatRequest) ([]ChatResponse, error) {
+func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, defaultAIConfig *models.AIConfig, req ChatRequest, images []*genai.Part, progressCallback ProgressCallback) ([]ChatResponse, error) {
 	var (
 		wg        sync.WaitGroup
 		mu        sync.Mutex
@@ -727,40 +1027,39 @@ func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, d
 	)
 
 	// 设置整体超时
-	parallelCtx, cancel := context.WithTimeout(ctx, MeetingTimeout)
+	parallelCtx, cancel := withExtendableTimeout(ctx, MeetingTimeout)
 	defer cancel()
 
 	log.Debug("running %d agents in parallel", len(req.Agents))
+	agentModels := s.newAgentModels(defaultAIConfig, defaultLLM)
 
 	for _, agentConfig := range req.Agents {
 		wg.Add(1)
 		go func(cfg models.AgentConfig) {
 			defer wg.Done()
 
-			// 获取该专家的 AI 配置
-			agentAIConfig := s.resolveAgentAIConfig(&cfg, defaultAIConfig)
-
-			// 为该专家创建 LLM
-			var agentLLM model.LLM
-			var err error
-			if agentAIConfig == defaultAIConfig {
-				agentLLM = defaultLLM
-			} else {
-				agentLLM, err = s.modelFactory.CreateModel(parallelCtx, agentAIConfig)
-				if err != nil {
-					log.Error("create agent LLM error: %v", err)
-					return
-				}
+			// 获取该专家的模型（同一配置在会议内复用）
+			agentLLM, agentAIConfig, err := agentModels.forAgent(parallelCtx, &cfg)
+			if err != nil {
+				log.Error("create agent LLM error: %v", err)
+				return
 			}
-			builder := s.createBuilder(agentLLM, agentAIConfig)
+			builder := s.createBuilder(parallelCtx, agentLLM, agentAIConfig)
+
+			emitProgress(progressCallback, ProgressEvent{
+				Type: "agent_start", AgentID: cfg.ID, AgentName: cfg.Name, Detail: cfg.Role,
+				Model: modelLabel(agentAIConfig),
+			})
+			defer emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: cfg.ID, AgentName: cfg.Name})
 
 			// 单个 Agent 带指数退避重试
-			content, err := retryRun(parallelCtx, MaxAgentRetries, func() (string, error) {
-				agentCtx, agentCancel := context.WithTimeout(parallelCtx, AgentTimeout)
-				defer agentCancel()
-				return s.runSingleAgent(agentCtx, builder, &cfg, &req.Stock, req.Query, req.ReplyContent, nil, req.Position)
+			out, err := retryRun(parallelCtx, MaxAgentRetries, func() (agentOutput, error) {
+				return runAgentWithTimeout(parallelCtx, agentTimeout(builder.AIConfig(), req.AgentTimeout), func(agentCtx context.Context) (agentOutput, error) {
+					return s.runSingleAgent(agentCtx, builder, &cfg, &req.Stock, req.Query, images, req.ReplyContent, progressCallback, req.Position)
+				})
 			})
 			if err != nil {
+				emitProgress(progressCallback, ProgressEvent{Type: "agent_error", AgentID: cfg.ID, AgentName: cfg.Name, Detail: err.Error()})
 				log.Error("agent %s failed after retries: %v", cfg.ID, err)
 				mu.Lock()
 				responses = append(responses, ChatResponse{
@@ -777,14 +1076,20 @@ func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, d
 
 			mu.Lock()
 			responses = append(responses, ChatResponse{
-				AgentID:     cfg.ID,
-				AgentName:   cfg.Name,
-				Role:        cfg.Role,
-				Content:     content,
-				MeetingMode: MeetingModeDirect,
+				AgentID:          cfg.ID,
+				AgentName:        cfg.Name,
+				Role:             cfg.Role,
+				Content:          out.Content,
+				MeetingMode:      MeetingModeDirect,
+				Reasoning:        applyThinkingPersist(s.thinkingPersist, out.Reasoning),
+				ToolCalls:        out.ToolCalls,
+				ToolResults:      out.ToolResults,
+				FinishReason:     out.FinishReason,
+				PromptTokens:     out.InputTokens,
+				CompletionTokens: out.OutputTokens,
 			})
 			mu.Unlock()
-			log.Debug("agent %s done, content len: %d", cfg.ID, len(content))
+			log.Debug("agent %s done, content len: %d", cfg.ID, len(out.Content))
 		}(agentConfig)
 	}
 
@@ -793,21 +1098,100 @@ func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, d
 	return responses, nil
 }
 
+// agentTimeout 单个专家发言的最大时长，configured 为策略配置的超时（0 为默认）
+// 默认不短于一次模型请求的超时，避免调大请求超时的慢模型仍被 AgentTimeout 截断
+func agentTimeout(aiConfig *models.AIConfig, configured time.Duration) time.Duration {
+	if configured > 0 {
+		return configured
+	}
+	if t := adk.RequestTimeout(aiConfig); t > AgentTimeout {
+		return t
+	}
+	return AgentTimeout
+}
+
+// runAgentWithTimeout 在单个专家的发言超时内运行 run
+// 专家自身超时（会议未结束）时返回 ErrAgentTimeout，由调用方生成失败消息告知用户
+func runAgentWithTimeout(ctx context.Context, timeout time.Duration, run func(ctx context.Context) (agentOutput, error)) (agentOutput, error) {
+	agentCtx, cancel := withExtendableTimeout(ctx, timeout)
+	defer cancel()
+	out, err := run(context.WithValue(agentCtx, agentTimeoutKey{}, timeout))
+	if err != nil && ctx.Err() == nil && errors.Is(agentCtx.Err(), context.DeadlineExceeded) {
+		return out, fmt.Errorf("%w：超过 %d 秒未完成", ErrAgentTimeout, int(timeout.Seconds()))
+	}
+	return out, err
+}
+
+type agentTimeoutKey struct{}
+
+// agentTimeoutFrom 取 runAgentWithTimeout 在 ctx 上记录的专家超时，未记录时使用默认值
+func agentTimeoutFrom(ctx context.Context, aiConfig *models.AIConfig) time.Duration {
+	if t, ok := ctx.Value(agentTimeoutKey{}).(time.Duration); ok && t > 0 {
+		return t
+	}
+	return agentTimeout(aiConfig, 0)
+}
+
 // runSingleAgent 运行单个 Agent（统一入口）
 // progressCallback 为 nil 时不发送进度事件，也不启用 streaming 模式
+// 思考内容（Thought parts）与正文分开收集，由调用方按持久化策略处理
+// images 随用户消息一起发送，模型不支持图片输入时直接返回 ErrImageInputUnsupported
+// 发言完成后记录用量、核对数据并写入会议摘要
 func (s *Service) runSingleAgent(
 	ctx context.Context,
 	builder *adk.ExpertAgentBuilder,
 	cfg *models.AgentConfig,
 	stock *models.Stock,
 	query string,
+	images []*genai.Part,
+	replyContent string,
+	progressCallback ProgressCallback,
+	position *models.StockPosition,
+) (agentOutput, error) {
+	out, err := s.runAgentSession(ctx, builder, cfg, stock, query, images, replyContent, progressCallback, position)
+	if err != nil {
+		return agentOutput{}, err
+	}
+
+	s.recordUsage(stock, cfg, out.InputTokens, out.OutputTokens)
+	if stock != nil && stock.Symbol != "" && out.InputTokens+out.OutputTokens > 0 {
+		total := s.addMeetingUsage(stock.Symbol, builder.AIConfig(), out.InputTokens, out.OutputTokens)
+		emitProgress(progressCallback, ProgressEvent{
+			Type: ProgressTypeUsage, AgentID: cfg.ID, AgentName: cfg.Name, Usage: &total,
+		})
+	}
+
+	content := s.applyFactCheck(out.Content, stock, cfg, progressCallback)
+	if stock != nil {
+		s.appendDigest(stock.Symbol, cfg.Name, content)
+	}
+	if isTruncated(genai.FinishReason(out.FinishReason)) {
+		content = markTruncated(content, cfg, progressCallback)
+	}
+	out.Content = content
+	return out, nil
+}
+
+// runAgentSession 构建专家并运行一次会话，返回原始发言与用量，不记录用量、不写会议摘要
+// 预演等不计入会议的调用直接使用
+func (s *Service) runAgentSession(
+	ctx context.Context,
+	builder *adk.ExpertAgentBuilder,
+	cfg *models.AgentConfig,
+	stock *models.Stock,
+	query string,
+	images []*genai.Part,
 	replyContent string,
 	progressCallback ProgressCallback,
 	position *models.StockPosition,
-) (string, error) {
+) (agentOutput, error) {
+	if len(images) > 0 && !adk.SupportsImageInput(builder.AIConfig()) {
+		aiConfig := builder.AIConfig()
+		return agentOutput{}, fmt.Errorf("%s 使用的 %s（%s）: %w", cfg.Name, aiConfig.Name, aiConfig.ModelName, ErrImageInputUnsupported)
+	}
 	agentInstance, err := builder.BuildAgentWithContext(cfg, stock, query, replyContent, position)
 	if err != nil {
-		return "", err
+		return agentOutput{}, err
 	}
 
 	sessionService := session.InMemoryService()
@@ -817,7 +1201,7 @@ func (s *Service) runSingleAgent(
 		SessionService: sessionService,
 	})
 	if err != nil {
-		return "", err
+		return agentOutput{}, err
 	}
 
 	sessionID := fmt.Sprintf("session-%s-%d", cfg.ID, time.Now().UnixNano())
@@ -826,62 +1210,148 @@ func (s *Service) runSingleAgent(
 		UserID:    "user",
 		SessionID: sessionID,
 	}); err != nil {
-		return "", fmt.Errorf("create session error: %w", err)
+		return agentOutput{}, fmt.Errorf("create session error: %w", err)
 	}
 
 	userMsg := &genai.Content{
 		Role:  "user",
-		Parts: []*genai.Part{genai.NewPartFromText(query)},
+		Parts: append([]*genai.Part{genai.NewPartFromText(query)}, images...),
 	}
 
-	// 有 progressCallback 时启用 streaming，否则普通模式
+	// 有 progressCallback 时启用 streaming 并推送工具执行事件，否则普通模式
 	runCfg := agent.RunConfig{}
 	if progressCallback != nil {
 		runCfg.StreamingMode = agent.StreamingModeSSE
+		ctx = adk.WithToolCallHook(ctx, newToolCallHook(cfg.ID, cfg.Name, progressCallback))
 	}
-
-	var sb strings.Builder
-	for event, err := range r.Run(ctx, "user", sessionID, userMsg, runCfg) {
-		if err != nil {
-			return "", err
-		}
-		if event == nil || event.LLMResponse.Content == nil {
-			continue
-		}
-		for _, part := range event.LLMResponse.Content.Parts {
-			if part.Thought {
+	timeout := agentTimeoutFrom(ctx, builder.AIConfig())
+	ctx = mcp.WithCallHooks(ctx, newMCPCallHooks(ctx, cfg.ID, cfg.Name, timeout, progressCallback))
+	// 模型退避重试前延长专家超时，退避等待不占用发言时间，避免配置的超时较短时重试被中途取消
+	deadlineCtx := ctx
+	ctx = adk.WithRetryWait(ctx, func(delay time.Duration) { extendDeadline(deadlineCtx, delay+timeout) })
+
+	var thoughtSB strings.Builder
+	var inputTokens, outputTokens int64
+	var toolCalls toolCallRecorder
+	// 首 token 延迟，结合是否预热过记录日志，便于对比预热效果
+	warm := s.modelFactory.IsWarm(builder.AIConfig())
+	start := time.Now()
+	var firstToken time.Duration
+	// runTurn 发送一条用户消息并收集回复正文，返回最后一次模型响应的结束原因
+	runTurn := func(msg *genai.Content) (string, genai.FinishReason, error) {
+		var sb strings.Builder
+		var finishReason genai.FinishReason
+		for event, err := range r.Run(ctx, "user", sessionID, msg, runCfg) {
+			if err != nil {
+				if len(images) > 0 && ctx.Err() == nil {
+					return "", "", fmt.Errorf("%w（本条消息附带图片，请确认模型支持图片输入）", err)
+				}
+				return "", "", err
+			}
+			if event == nil {
 				continue
 			}
-			if part.FunctionCall != nil && progressCallback != nil {
-				progressCallback(ProgressEvent{
-					Type: "tool_call", AgentID: cfg.ID, AgentName: cfg.Name,
-					Detail: part.FunctionCall.Name,
-				})
+			if firstToken == 0 && event.LLMResponse.Content != nil {
+				firstToken = time.Since(start)
+				log.Info("agent %s first token latency: %v (warm=%v)", cfg.ID, firstToken, warm)
 			}
-			if part.FunctionResponse != nil && progressCallback != nil {
-				progressCallback(ProgressEvent{
-					Type: "tool_result", AgentID: cfg.ID, AgentName: cfg.Name,
-					Detail: part.FunctionResponse.Name,
-				})
+			// 用量只在完整响应上统计，工具调用产生的多轮 LLM 请求累加
+			if u := event.LLMResponse.UsageMetadata; u != nil && !event.LLMResponse.Partial {
+				inputTokens += int64(u.PromptTokenCount)
+				outputTokens += int64(u.CandidatesTokenCount) + int64(u.ThoughtsTokenCount)
+			}
+			if event.LLMResponse.FinishReason != "" && !event.LLMResponse.Partial {
+				finishReason = event.LLMResponse.FinishReason
 			}
-			if part.Text != "" {
-				// streaming 模式下只累积 Partial 片段，避免重复
-				if progressCallback != nil {
-					if event.LLMResponse.Partial {
+			if event.LLMResponse.Content == nil {
+				continue
+			}
+			for _, part := range event.LLMResponse.Content.Parts {
+				if part.Thought {
+					// 与正文一致：streaming 模式下只累积 Partial 片段
+					if part.Text != "" && (progressCallback == nil || event.LLMResponse.Partial) {
+						thoughtSB.WriteString(part.Text)
+						if progressCallback != nil {
+							progressCallback(newDeltaEvent(cfg.ID, cfg.Name, part.Text, true))
+						}
+					}
+					continue
+				}
+				if part.FunctionCall != nil {
+					toolCalls.add(part.FunctionCall)
+				}
+				if part.FunctionCall != nil && progressCallback != nil {
+					progressCallback(ProgressEvent{
+						Type: "tool_call", AgentID: cfg.ID, AgentName: cfg.Name,
+						Detail: part.FunctionCall.Name,
+					})
+				}
+				if part.FunctionResponse != nil {
+					toolCalls.addResult(part.FunctionResponse)
+				}
+				if part.FunctionResponse != nil && progressCallback != nil {
+					progressCallback(ProgressEvent{
+						Type: "tool_result", AgentID: cfg.ID, AgentName: cfg.Name,
+						Detail: part.FunctionResponse.Name,
+					})
+				}
+				if part.Text != "" {
+					// streaming 模式下只累积 Partial 片段，避免重复
+					if progressCallback != nil {
+						if event.LLMResponse.Partial {
+							sb.WriteString(part.Text)
+							progressCallback(newDeltaEvent(cfg.ID, cfg.Name, part.Text, false))
+						}
+					} else {
 						sb.WriteString(part.Text)
-						progressCallback(ProgressEvent{
-							Type: "streaming", AgentID: cfg.ID, AgentName: cfg.Name,
-							Content: part.Text,
-						})
 					}
-				} else {
-					sb.WriteString(part.Text)
 				}
 			}
 		}
+		return sb.String(), finishReason, nil
+	}
+
+	text, finishReason, err := runTurn(userMsg)
+	if err != nil {
+		return agentOutput{}, err
+	}
+	// 因长度截断时按配置在同一会话中续写一次，续写的用量计入本次发言
+	if isTruncated(finishReason) && s.autoContinue && ctx.Err() == nil {
+		emitProgress(progressCallback, ProgressEvent{
+			Type: ProgressTypeTruncated, AgentID: cfg.ID, AgentName: cfg.Name, Detail: "回复被截断，自动续写",
+		})
+		cont, contReason, err := runTurn(genai.NewContentFromText(continuePrompt, genai.RoleUser))
+		if err != nil {
+			log.Warn("agent %s continuation failed: %v", cfg.ID, err)
+		} else {
+			text, finishReason = stitchContinuation(text, cont), contReason
+		}
 	}
 
-	return openai.FilterVendorToolCallMarkers(sb.String()), nil
+	return agentOutput{
+		Content:      openai.FilterVendorToolCallMarkers(text),
+		Reasoning:    thoughtSB.String(),
+		ToolCalls:    toolCalls.calls,
+		ToolResults:  toolCalls.toolResults(),
+		FinishReason: string(finishReason),
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+	}, nil
+}
+
+// recordUsage 记录专家本次发言的 token 用量（按股票会话归档）
+func (s *Service) recordUsage(stock *models.Stock, cfg *models.AgentConfig, inputTokens, outputTokens int64) {
+	if s.usageRecorder == nil || stock == nil || stock.Symbol == "" || inputTokens+outputTokens == 0 {
+		return
+	}
+	s.usageRecorder(stock.Symbol, models.TokenUsage{
+		AgentID:      cfg.ID,
+		AgentName:    cfg.Name,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		TotalTokens:  inputTokens + outputTokens,
+		Calls:        1,
+	})
 }
 
 // filterAgentsOrdered 按指定顺序筛选专家（保持小韭菜选择的顺序）
@@ -899,19 +1369,6 @@ func (s *Service) filterAgentsOrdered(all []models.AgentConfig, ids []string) []
 	return result
 }
 
-// buildPreviousContext 构建前面专家发言的上下文
-func (s *Service) buildPreviousContext(history []DiscussionEntry) string {
-	if len(history) == 0 {
-		return ""
-	}
-	var sb strings.Builder
-	sb.WriteString("【前面专家的发言】\n")
-	for _, entry := range history {
-		fmt.Fprintf(&sb, "- %s（%s）：%s\n\n", entry.AgentName, entry.Role, entry.Content)
-	}
-	return sb.String()
-}
-
 // extractKeyPointsFromHistory 从讨论历史中提取关键点
 func (s *Service) extractKeyPointsFromHistory(ctx context.Context, history []DiscussionEntry) []string {
 	// 如果有记忆管理器，使用 LLM 智能提取
@@ -952,22 +1409,39 @@ func (s *Service) resolveAgentAIConfig(agentCfg *models.AgentConfig, defaultConf
 			log.Debug("agent %s using custom AI: %s", agentCfg.ID, resolved.ModelName)
 			return resolved
 		}
+		log.Warn("agent %s AI config %s not found, using meeting config", agentCfg.ID, agentCfg.AIConfigID)
 	}
 	return defaultConfig
 }
 
 // createBuilder 创建 ExpertAgentBuilder
-func (s *Service) createBuilder(llm model.LLM, aiConfig *models.AIConfig) *adk.ExpertAgentBuilder {
-	if s.mcpManager != nil {
-		return adk.NewExpertAgentBuilderFull(llm, aiConfig, s.toolRegistry, s.mcpManager)
+func (s *Service) createBuilder(ctx context.Context, llm model.LLM, aiConfig *models.AIConfig) *adk.ExpertAgentBuilder {
+	var builder *adk.ExpertAgentBuilder
+	switch {
+	case s.mcpManager != nil:
+		builder = adk.NewExpertAgentBuilderFull(llm, aiConfig, s.toolRegistry, s.mcpManager)
+	case s.toolRegistry != nil:
+		builder = adk.NewExpertAgentBuilderWithTools(llm, aiConfig, s.toolRegistry)
+	default:
+		builder = adk.NewExpertAgentBuilder(llm, aiConfig)
 	}
-	if s.toolRegistry != nil {
-		return adk.NewExpertAgentBuilderWithTools(llm, aiConfig, s.toolRegistry)
+	if s.indexOverview != nil {
+		builder.SetIndexOverviewProvider(func(code string) (*models.IndexOverview, error) {
+			return s.indexOverview(ctx, code)
+		})
+	}
+	builder.SetSystemPromptAffixes(s.promptPrefix, s.promptSuffix)
+	builder.SetFixedTime(meetingRunFrom(ctx).fixedTime())
+	builder.SetDataFreshness(dataFreshnessFrom(ctx))
+	builder.SetToolArgGuard(s.argGuard)
+	if s.memoryManager != nil {
+		builder.SetActionJournalProvider(s.memoryManager.BuildJournalContext)
 	}
-	return adk.NewExpertAgentBuilder(llm, aiConfig)
+	return builder
 }
 
 // RetrySingleAgent 重试单个失败的专家（前端手动重试调用）
+// timeout 为策略配置的专家超时，0 为默认
 func (s *Service) RetrySingleAgent(
 	ctx context.Context,
 	aiConfig *models.AIConfig,
@@ -976,25 +1450,26 @@ func (s *Service) RetrySingleAgent(
 	query string,
 	progressCallback ProgressCallback,
 	position *models.StockPosition,
+	timeout time.Duration,
 ) (ChatResponse, error) {
-	// 获取该专家的 AI 配置
-	agentAIConfig := s.resolveAgentAIConfig(agentCfg, aiConfig)
-
-	agentLLM, err := s.modelFactory.CreateModel(ctx, agentAIConfig)
+	ctx = s.withDataFreshness(ctx, *stock, time.Now())
+	// 获取该专家的模型
+	agentLLM, agentAIConfig, err := s.newAgentModels(aiConfig, nil).forAgent(ctx, agentCfg)
 	if err != nil {
 		return ChatResponse{}, fmt.Errorf("create model error: %w", err)
 	}
-	builder := s.createBuilder(agentLLM, agentAIConfig)
+	builder := s.createBuilder(ctx, agentLLM, agentAIConfig)
 
 	emitProgress(progressCallback, ProgressEvent{
 		Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
+		Model: modelLabel(agentAIConfig),
 	})
 
 	// 带指数退避重试
-	content, err := retryRun(ctx, MaxAgentRetries, func() (string, error) {
-		agentCtx, cancel := context.WithTimeout(ctx, AgentTimeout)
-		defer cancel()
-		return s.runSingleAgent(agentCtx, builder, agentCfg, stock, query, "", progressCallback, position)
+	out, err := retryRun(ctx, MaxAgentRetries, func() (agentOutput, error) {
+		return runAgentWithTimeout(ctx, agentTimeout(builder.AIConfig(), timeout), func(agentCtx context.Context) (agentOutput, error) {
+			return s.runSingleAgent(agentCtx, builder, agentCfg, stock, query, nil, "", progressCallback, position)
+		})
 	})
 
 	emitProgress(progressCallback, ProgressEvent{
@@ -1012,15 +1487,21 @@ func (s *Service) RetrySingleAgent(
 		}, err
 	}
 
-	return ChatResponse{
-		AgentID:     agentCfg.ID,
-		AgentName:   agentCfg.Name,
-		Role:        agentCfg.Role,
-		Content:     content,
-		Round:       1,
-		MsgType:     "opinion",
-		MeetingMode: MeetingModeDirect,
-	}, nil
+	return stampAsOf(dataFreshnessFrom(ctx), ChatResponse{
+		AgentID:          agentCfg.ID,
+		AgentName:        agentCfg.Name,
+		Role:             agentCfg.Role,
+		Content:          out.Content,
+		Round:            1,
+		MsgType:          "opinion",
+		MeetingMode:      MeetingModeDirect,
+		Reasoning:        applyThinkingPersist(s.thinkingPersist, out.Reasoning),
+		ToolCalls:        out.ToolCalls,
+		ToolResults:      out.ToolResults,
+		FinishReason:     out.FinishReason,
+		PromptTokens:     out.InputTokens,
+		CompletionTokens: out.OutputTokens,
+	}), nil
 }
 
 // cacheMeetingState 缓存中断的会议状态
@@ -1028,7 +1509,67 @@ func (s *Service) cacheMeetingState(stockCode string, state *MeetingState) {
 	s.meetingStatesMu.Lock()
 	defer s.meetingStatesMu.Unlock()
 	s.meetingStates[stockCode] = state
-	log.Info("cached meeting state for %s, failedIndex=%d", stockCode, state.FailedIndex)
+	log.Info("cached meeting state for %s, failedIndex=%d, cancelled=%v", stockCode, state.FailedIndex, state.Cancelled)
+}
+
+// cacheCancelledMeeting 会议被用户取消（ctx 被 cancel 而非超时）时缓存进度，返回是否已缓存
+func (s *Service) cacheCancelledMeeting(ctx context.Context, stockCode string, state *MeetingState) bool {
+	if stockCode == "" || !errors.Is(ctx.Err(), context.Canceled) {
+		return false
+	}
+	state.Cancelled = true
+	s.cacheMeetingState(stockCode, state)
+	return true
+}
+
+// dropMeetingState 丢弃中断状态
+func (s *Service) dropMeetingState(stockCode string) {
+	s.meetingStatesMu.Lock()
+	defer s.meetingStatesMu.Unlock()
+	delete(s.meetingStates, stockCode)
+}
+
+// beginRun 标记该股票的会议开始执行，返回结束时调用的函数
+func (s *Service) beginRun(stockCode string) func() {
+	done := make(chan struct{})
+	s.runningMu.Lock()
+	s.running[stockCode] = done
+	s.runningMu.Unlock()
+	return func() {
+		s.runningMu.Lock()
+		if s.running[stockCode] == done {
+			delete(s.running, stockCode)
+		}
+		s.runningMu.Unlock()
+		close(done)
+	}
+}
+
+// waitRun 等待该股票正在退出的会议结束（取消后进度在会议退出前才写入）
+func (s *Service) waitRun(ctx context.Context, stockCode string) {
+	s.runningMu.Lock()
+	done := s.running[stockCode]
+	s.runningMu.Unlock()
+	if done == nil {
+		return
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+	case <-time.After(ResumeWaitTimeout):
+		log.Warn("previous meeting for %s still running after %v", stockCode, ResumeWaitTimeout)
+	}
+}
+
+// ResumeMeeting 恢复被取消的会议：等待上一场会议退出后，跳过已发言的专家继续讨论
+func (s *Service) ResumeMeeting(
+	ctx context.Context,
+	stockCode string,
+	respCallback ResponseCallback,
+	progressCallback ProgressCallback,
+) ([]ChatResponse, error) {
+	s.waitRun(ctx, stockCode)
+	return s.ContinueMeeting(ctx, stockCode, respCallback, progressCallback)
 }
 
 // CancelInterruptedMeeting 取消中断的会议（用户放弃重试时调用）
@@ -1060,7 +1601,7 @@ func (s *Service) ContinueMeeting(
 	stockCode string,
 	respCallback ResponseCallback,
 	progressCallback ProgressCallback,
-) ([]ChatResponse, error) {
+) (out []ChatResponse, err error) {
 	// 取出缓存状态
 	s.meetingStatesMu.Lock()
 	state, ok := s.meetingStates[stockCode]
@@ -1073,54 +1614,106 @@ func (s *Service) ContinueMeeting(
 		return nil, fmt.Errorf("没有可恢复的会议状态")
 	}
 
-	log.Info("continuing meeting for %s, failedIndex=%d, total=%d",
-		stockCode, state.FailedIndex, len(state.SelectedAgents))
+	log.Info("continuing meeting for %s, failedIndex=%d, total=%d, cancelled=%v",
+		stockCode, state.FailedIndex, len(state.SelectedAgents), state.Cancelled)
+	if state.Mode == MeetingModeDebate {
+		respCallback = debateCallback(respCallback)
+	}
+	defer s.beginRun(stockCode)()
+	// 沿用中断前的数据时效，恢复后的发言与之前的发言依据同一份行情
+	ctx = contextWithDataFreshness(ctx, state.Freshness)
+	// 续开的会议重新计算网页搜索次数
+	ctx = tools.WithWebSearchBudget(ctx)
+	respCallback = asOfCallback(ctx, respCallback)
+	defer func() { stampResponsesAsOf(ctx, out) }()
 
 	// 设置会议超时
-	meetingCtx, meetingCancel := context.WithTimeout(ctx, MeetingTimeout)
+	meetingCtx, meetingCancel := withExtendableTimeout(ctx, MeetingTimeout)
 	defer meetingCancel()
 
 	responses := state.Responses
 	history := state.History
+	completed := state.completed()
+	agentModels := s.newAgentModels(state.AIConfig, nil)
+
+	// snapshot 记录当前进度，供再次取消或失败后恢复
+	snapshot := func(index int) *MeetingState {
+		next := *state
+		next.History = history
+		next.Responses = responses
+		next.FailedIndex = index
+		next.Cancelled = false
+		next.CreatedAt = time.Now()
+		return &next
+	}
 
-	// 从失败的专家开始，依次执行
+	// 并行会议的剩余专家同样并行发言，沿用原会议的并发上限
 	startIndex := state.FailedIndex
+	if state.Parallel {
+		var remaining []models.AgentConfig
+		for _, cfg := range state.SelectedAgents[startIndex:] {
+			if !completed[cfg.ID] {
+				remaining = append(remaining, cfg)
+			}
+		}
+		first, firstHistory := s.runFirstRoundParallel(meetingCtx, agentModels, state.resumeRequest(), state.Images, remaining, state.Tasks, state.MemoryContext, respCallback, progressCallback)
+		responses = append(responses, first...)
+		history = append(history, firstHistory...)
+		if meetingCtx.Err() != nil {
+			s.cacheCancelledMeeting(ctx, stockCode, snapshot(startIndex))
+			log.Warn("continue meeting timeout, got %d responses", len(responses))
+			return responses, ErrMeetingTimeout
+		}
+		startIndex = len(state.SelectedAgents)
+	}
+
+	// 从失败的专家开始，依次执行（已发言的专家跳过）
 	for i := startIndex; i < len(state.SelectedAgents); i++ {
 		select {
 		case <-meetingCtx.Done():
+			s.cacheCancelledMeeting(ctx, stockCode, snapshot(i))
 			log.Warn("continue meeting timeout, got %d responses", len(responses))
 			return responses, ErrMeetingTimeout
 		default:
 		}
 
 		agentCfg := state.SelectedAgents[i]
+		if completed[agentCfg.ID] {
+			continue
+		}
 		log.Debug("continue: agent %d/%d: %s", i+1, len(state.SelectedAgents), agentCfg.Name)
 
-		// 获取该专家的 AI 配置
-		agentAIConfig := s.resolveAgentAIConfig(&agentCfg, state.AIConfig)
-
-		agentLLM, err := s.modelFactory.CreateModel(meetingCtx, agentAIConfig)
+		// 获取该专家的模型（同一配置在会议内复用）
+		agentLLM, agentAIConfig, err := agentModels.forAgent(meetingCtx, &agentCfg)
 		if err != nil {
 			log.Error("continue: create agent LLM error: %v", err)
 			continue
 		}
-		builder := s.createBuilder(agentLLM, agentAIConfig)
+		builder := s.createBuilder(meetingCtx, agentLLM, agentAIConfig)
 
 		emitProgress(progressCallback, ProgressEvent{
 			Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
+			Model: modelLabel(agentAIConfig),
 		})
 
-		previousContext := s.buildPreviousContext(history)
-		if state.MemoryContext != "" {
-			previousContext = state.MemoryContext + "\n" + previousContext
+		// 并行模式下专家互不参考，只注入记忆上下文
+		previousContext := state.MemoryContext
+		if !state.Parallel {
+			previousContext = buildPeerContext(history, state.Moderator.visibility)
+			if state.MemoryContext != "" {
+				previousContext = state.MemoryContext + "\n" + previousContext
+			}
 		}
 
-		content, err := retryRun(meetingCtx, MaxAgentRetries, func() (string, error) {
-			agentCtx, agentCancel := context.WithTimeout(meetingCtx, AgentTimeout)
-			defer agentCancel()
-			return s.runSingleAgent(agentCtx, builder, &agentCfg, &state.Stock, state.Query, previousContext, progressCallback, state.Position)
+		out, err := retryRun(meetingCtx, MaxAgentRetries, func() (agentOutput, error) {
+			return runAgentWithTimeout(meetingCtx, agentTimeout(builder.AIConfig(), state.AgentTimeout), func(agentCtx context.Context) (agentOutput, error) {
+				return s.runSingleAgent(agentCtx, builder, &agentCfg, &state.Stock, state.agentQuery(agentCfg.ID), state.Images, previousContext, progressCallback, state.Position)
+			})
 		})
 
+		if err != nil && s.cacheCancelledMeeting(ctx, stockCode, snapshot(i)) {
+			break
+		}
 		if err != nil {
 			emitProgress(progressCallback, ProgressEvent{Type: "agent_error", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: err.Error()})
 			emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name})
@@ -1136,20 +1729,7 @@ func (s *Service) ContinueMeeting(
 			}
 
 			// 再次缓存，允许用户继续重试
-			s.cacheMeetingState(stockCode, &MeetingState{
-				AIConfig:       state.AIConfig,
-				Stock:          state.Stock,
-				Query:          state.Query,
-				Position:       state.Position,
-				SelectedAgents: state.SelectedAgents,
-				History:        history,
-				Responses:      responses,
-				FailedIndex:    i,
-				MemoryContext:  state.MemoryContext,
-				StockMemory:    state.StockMemory,
-				Moderator:      state.Moderator,
-				CreatedAt:      time.Now(),
-			})
+			s.cacheMeetingState(stockCode, snapshot(i))
 
 			remainingIDs := make([]string, 0, len(state.SelectedAgents)-i-1)
 			for _, ra := range state.SelectedAgents[i+1:] {
@@ -1166,7 +1746,13 @@ func (s *Service) ContinueMeeting(
 
 		resp := ChatResponse{
 			AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role,
-			Content: content, Round: 1, MsgType: "opinion", MeetingMode: MeetingModeSmart,
+			Content: out.Content, Round: 1, MsgType: "opinion", MeetingMode: MeetingModeSmart,
+			Reasoning:        applyThinkingPersist(s.thinkingPersist, out.Reasoning),
+			ToolCalls:        out.ToolCalls,
+			ToolResults:      out.ToolResults,
+			FinishReason:     out.FinishReason,
+			PromptTokens:     out.InputTokens,
+			CompletionTokens: out.OutputTokens,
 		}
 		responses = append(responses, resp)
 		if respCallback != nil {
@@ -1175,7 +1761,7 @@ func (s *Service) ContinueMeeting(
 
 		history = append(history, DiscussionEntry{
 			Round: 1, AgentID: agentCfg.ID, AgentName: agentCfg.Name,
-			Role: agentCfg.Role, Content: content,
+			Role: agentCfg.Role, Content: out.Content,
 		})
 	}
 
@@ -1187,8 +1773,18 @@ func (s *Service) ContinueMeeting(
 		return responses, nil
 	}
 
-	// 全部完成，执行小韭菜总结
-	return s.runMeetingSummary(meetingCtx, state, history, responses, respCallback, progressCallback)
+	// 全部完成，执行小韭菜总结（辩论模式先进行反驳轮）
+	if state.Mode == MeetingModeDebate {
+		next := snapshot(len(state.SelectedAgents))
+		responses = s.runDebate(meetingCtx, next, agentModels, respCallback, progressCallback)
+	} else {
+		responses, err = s.runMeetingSummary(meetingCtx, state, history, responses, respCallback, progressCallback)
+	}
+	if n := len(responses); n == 0 || responses[n-1].MsgType != "summary" {
+		// 总结被取消时保留进度，恢复后直接总结
+		s.cacheCancelledMeeting(ctx, stockCode, snapshot(len(state.SelectedAgents)))
+	}
+	return responses, err
 }
 
 // runMeetingSummary 执行小韭菜总结（ContinueMeeting 专用）
@@ -1205,7 +1801,7 @@ func (s *Service) runMeetingSummary(
 	})
 
 	summaryCtx, summaryCancel := context.WithTimeout(ctx, ModeratorTimeout)
-	summary, err := state.Moderator.Summarize(summaryCtx, &state.Stock, state.Query, history)
+	summary, err := state.Moderator.SummarizeStream(summaryCtx, &state.Stock, state.Query, history, moderatorDeltaFunc(progressCallback))
 	summaryCancel()
 
 	emitProgress(progressCallback, ProgressEvent{
@@ -1233,16 +1829,20 @@ func (s *Service) runMeetingSummary(
 		}
 	}
 
-	// 异步保存记忆
-	if s.memoryManager != nil && state.StockMemory != nil && summary != "" {
-		go func() {
-			bgCtx := context.Background()
-			keyPoints := s.extractKeyPointsFromHistory(bgCtx, history)
-			if err := s.memoryManager.AddRound(bgCtx, state.StockMemory, state.Query, summary, keyPoints); err != nil {
-				log.Error("save memory error: %v", err)
-			}
-		}()
-	}
-
+	s.saveRoundMemory(state, history, summary)
 	return responses, nil
 }
+
+// saveRoundMemory 异步保存本轮讨论到股票记忆
+func (s *Service) saveRoundMemory(state *MeetingState, history []DiscussionEntry, summary string) {
+	if s.memoryManager == nil || state.StockMemory == nil || summary == "" {
+		return
+	}
+	go func() {
+		bgCtx := context.Background()
+		keyPoints := s.extractKeyPointsFromHistory(bgCtx, history)
+		if err := s.memoryManager.AddRound(bgCtx, state.StockMemory, state.Query, summary, keyPoints); err != nil {
+			log.Error("save memory error: %v", err)
+		}
+	}()
+}
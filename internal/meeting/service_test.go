@@ -0,0 +1,353 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// mockLLM 基于 Ollama /api/chat 协议的模拟 LLM，按 Prompt 内容路由回复
+type mockLLM struct {
+	mu          sync.Mutex
+	analyze     string // 小韭菜意图分析 Prompt
+	summarize   string // 小韭菜总结 Prompt
+	instruction string // 专家系统指令
+	numPredict  int    // 专家请求的输出 token 上限
+}
+
+func (m *mockLLM) reply(q *ollamaRequest) ollamaReply {
+	first := q.First()
+	var reply string
+	m.mu.Lock()
+	switch {
+	case q.Analyze():
+		m.analyze = first
+		reply = `{"intent":"大盘走势","selected":["macro"],"tasks":{"macro":"分析上证指数短期趋势"},"topic":"大盘","opening":"我们来聊聊大盘"}`
+	case q.Summarize():
+		m.summarize = first
+		reply = "结论：指数震荡偏强"
+	default:
+		m.instruction = first
+		if q.Options != nil {
+			m.numPredict = q.Options.NumPredict
+		}
+		reply = "上涨家数占优，指数短期偏强"
+	}
+	m.mu.Unlock()
+	return ollamaReply{Content: reply, DoneReason: "stop", PromptEval: 100, Eval: 20}
+}
+
+func TestRunSmartMeeting_Index(t *testing.T) {
+	llm := &mockLLM{}
+	srv := ollamaMockServer(t, llm.reply)
+
+	svc := NewServiceFull(nil, nil)
+	svc.SetIndexOverviewProvider(func(_ context.Context, code string) (*models.IndexOverview, error) {
+		return &models.IndexOverview{
+			Code: code, Name: "上证指数", Up: 1500, Down: 600, Flat: 100,
+			Leading: []models.SectorPerformance{{Name: "半导体", ChangePercent: 3.21, LeaderName: "中芯国际", LeaderChange: 8.5}},
+			Lagging: []models.SectorPerformance{{Name: "银行", ChangePercent: -1.05}},
+		}, nil
+	})
+
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := ChatRequest{
+		StockCode: "sh000001",
+		Stock:     models.Stock{Symbol: "sh000001", Name: "上证指数", Price: 3300.12, ChangePercent: 0.85},
+		Query:     "大盘后市怎么看",
+		AllAgents: []models.AgentConfig{{ID: "macro", Name: "宏观分析师", Role: "宏观策略", Enabled: true}},
+		// 指数不应使用持仓信息，即使调用方误传
+		Position: &models.StockPosition{Shares: 100, CostPrice: 3000},
+	}
+
+	var events []ProgressEvent
+	responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, func(e ProgressEvent) {
+		events = append(events, e)
+	})
+	if err != nil {
+		t.Fatalf("meeting error: %v", err)
+	}
+
+	var types []string
+	for _, r := range responses {
+		if r.Error != "" {
+			t.Fatalf("agent %s failed: %s", r.AgentID, r.Error)
+		}
+		types = append(types, r.MsgType)
+	}
+	if got := strings.Join(types, ","); got != "opening,opinion,summary" {
+		t.Fatalf("msg types = %s", got)
+	}
+	if responses[1].Content != "上涨家数占优，指数短期偏强" || responses[2].Content != "结论：指数震荡偏强" {
+		t.Errorf("responses = %+v", responses)
+	}
+
+	if !strings.Contains(llm.analyze, "## 当前指数") || !strings.Contains(llm.analyze, "不要安排盘口") {
+		t.Errorf("analyze prompt missing index hint:\n%s", llm.analyze)
+	}
+	if !strings.Contains(llm.summarize, "## 指数：上证指数") {
+		t.Errorf("summarize prompt missing index subject:\n%s", llm.summarize)
+	}
+	for _, want := range []string{
+		"指数: sh000001 (上证指数)",
+		"本次讨论的标的是指数而非个股",
+		"上涨 1500 家，下跌 600 家，平盘 100 家",
+		"领涨板块: 半导体 +3.21%（中芯国际 +8.50%）",
+		"领跌板块: 银行 -1.05%",
+	} {
+		if !strings.Contains(llm.instruction, want) {
+			t.Errorf("instruction missing %q:\n%s", want, llm.instruction)
+		}
+	}
+	if strings.Contains(llm.instruction, "用户持仓") || strings.Contains(llm.instruction, "股票: ") {
+		t.Errorf("instruction should not contain stock/position context:\n%s", llm.instruction)
+	}
+	if len(events) == 0 {
+		t.Error("expected progress events")
+	}
+}
+
+func TestRunSmartMeeting_TokenBudgetAndUsage(t *testing.T) {
+	llm := &mockLLM{}
+	srv := ollamaMockServer(t, llm.reply)
+
+	svc := NewServiceFull(nil, nil)
+	var recorded []models.TokenUsage
+	svc.SetUsageRecorder(func(stockCode string, usage models.TokenUsage) {
+		if stockCode != "sh600519" {
+			t.Errorf("stockCode = %s", stockCode)
+		}
+		recorded = append(recorded, usage)
+	})
+
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock", MaxTokens: 2048}
+	req := ChatRequest{
+		StockCode: "sh600519",
+		Stock:     models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500},
+		Query:     "能买吗",
+		AllAgents: []models.AgentConfig{{ID: "macro", Name: "老陈", Role: "宏观策略", Enabled: true, MaxTokensPerAgent: 300}},
+	}
+	if _, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, nil); err != nil {
+		t.Fatalf("meeting error: %v", err)
+	}
+
+	if llm.numPredict != 300 {
+		t.Errorf("num_predict = %d, want per-agent budget 300", llm.numPredict)
+	}
+	if len(recorded) != 1 {
+		t.Fatalf("recorded %d usages, want 1", len(recorded))
+	}
+	u := recorded[0]
+	if u.AgentID != "macro" || u.AgentName != "老陈" || u.InputTokens != 100 || u.OutputTokens != 20 || u.TotalTokens != 120 || u.Calls != 1 {
+		t.Errorf("usage = %+v", u)
+	}
+}
+
+func TestRunSmartMeeting_MeetingUsage(t *testing.T) {
+	llm := &mockLLM{}
+	srv := ollamaMockServer(t, llm.reply)
+
+	svc := NewServiceFull(nil, nil)
+	aiConfig := &models.AIConfig{
+		ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock",
+		InputPricePer1K: 1, OutputPricePer1K: 2,
+	}
+	req := ChatRequest{
+		StockCode: "sh600519",
+		Stock:     models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500},
+		Query:     "能买吗",
+		AllAgents: []models.AgentConfig{{ID: "macro", Name: "老陈", Role: "宏观策略", Enabled: true}},
+	}
+
+	var usageEvents []ProgressEvent
+	responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, func(e ProgressEvent) {
+		if e.Type == ProgressTypeUsage {
+			usageEvents = append(usageEvents, e)
+		}
+	})
+	if err != nil {
+		t.Fatalf("meeting error: %v", err)
+	}
+	if r := responses[1]; r.PromptTokens != 100 || r.CompletionTokens != 20 {
+		t.Errorf("opinion tokens = %d/%d, want 100/20", r.PromptTokens, r.CompletionTokens)
+	}
+	if len(usageEvents) != 1 || usageEvents[0].AgentID != "macro" || usageEvents[0].Usage == nil || usageEvents[0].Usage.TotalTokens != 120 {
+		t.Fatalf("usage events = %+v", usageEvents)
+	}
+
+	u := svc.GetMeetingUsage("sh600519")
+	if u.PromptTokens != 100 || u.CompletionTokens != 20 || u.Turns != 1 {
+		t.Errorf("meeting usage = %+v", u)
+	}
+	// 100/1000*1 + 20/1000*2
+	if u.Cost < 0.1399 || u.Cost > 0.1401 {
+		t.Errorf("cost = %f, want 0.14", u.Cost)
+	}
+
+	// 新会议重新计数
+	if _, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, nil); err != nil {
+		t.Fatalf("meeting error: %v", err)
+	}
+	if u := svc.GetMeetingUsage("sh600519"); u.Turns != 1 || u.TotalTokens != 120 {
+		t.Errorf("usage after second meeting = %+v", u)
+	}
+}
+
+// 专家指定的 AI 配置优先，未指定、配置已删除或模型创建失败时使用会议配置
+func TestSendMessage_AgentAIConfigOverride(t *testing.T) {
+	newServer := func(calls *[]string, mu *sync.Mutex) *httptest.Server {
+		return ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+			mu.Lock()
+			for _, id := range []string{"chen", "kline", "stale", "broken"} {
+				if strings.Contains(q.First(), "指令-"+id) {
+					*calls = append(*calls, id)
+				}
+			}
+			mu.Unlock()
+			return ollamaReply{Content: "观点"}
+		})
+	}
+	var (
+		mu                        sync.Mutex
+		meetingCalls, customCalls []string
+	)
+	meetingSrv := newServer(&meetingCalls, &mu)
+	customSrv := newServer(&customCalls, &mu)
+
+	custom := &models.AIConfig{ID: "gpt", Name: "便宜模型", Provider: models.AIProviderOllama, BaseURL: customSrv.URL, ModelName: "custom"}
+	broken := &models.AIConfig{ID: "bad", Provider: "unknown", ModelName: "bad"}
+	svc := NewServiceFull(nil, nil)
+	svc.SetAIConfigResolver(func(id string) *models.AIConfig {
+		switch id {
+		case custom.ID:
+			return custom
+		case broken.ID:
+			return broken
+		}
+		return nil
+	})
+
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: meetingSrv.URL, ModelName: "mock"}
+	req := ChatRequest{
+		Stock: models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500},
+		Query: "后市怎么看",
+		Agents: []models.AgentConfig{
+			{ID: "chen", Name: "老陈", Instruction: "指令-chen", AIConfigID: "gpt"},
+			{ID: "kline", Name: "K线王", Instruction: "指令-kline"},
+			{ID: "stale", Name: "已删配置", Instruction: "指令-stale", AIConfigID: "deleted"},
+			{ID: "broken", Name: "坏配置", Instruction: "指令-broken", AIConfigID: "bad"},
+			{ID: "chen2", Name: "老陈2", Instruction: "指令-chen", AIConfigID: "gpt"},
+		},
+	}
+	used := map[string]string{}
+	responses, err := svc.SendMessageWithCallback(context.Background(), aiConfig, req, func(e ProgressEvent) {
+		if e.Type == "agent_start" {
+			mu.Lock()
+			used[e.AgentID] = e.Model
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range responses {
+		if r.Error != "" {
+			t.Fatalf("agent %s failed: %s", r.AgentID, r.Error)
+		}
+	}
+	if strings.Join(customCalls, ",") != "chen,chen" {
+		t.Errorf("custom calls = %v", customCalls)
+	}
+	if len(meetingCalls) != 3 || strings.Contains(strings.Join(meetingCalls, ","), "chen") {
+		t.Errorf("meeting calls = %v", meetingCalls)
+	}
+	want := map[string]string{"chen": "便宜模型（custom）", "chen2": "便宜模型（custom）", "kline": "mock", "stale": "mock", "broken": "mock"}
+	if !reflect.DeepEqual(used, want) {
+		t.Errorf("progress models = %v, want %v", used, want)
+	}
+}
+
+// 同一会议内使用相同配置的专家共用模型实例
+func TestAgentModels_CacheByConfigID(t *testing.T) {
+	custom := &models.AIConfig{ID: "gpt", Provider: models.AIProviderOllama, ModelName: "custom"}
+	svc := NewServiceFull(nil, nil)
+	svc.SetAIConfigResolver(func(id string) *models.AIConfig {
+		if id == custom.ID {
+			return custom
+		}
+		return nil
+	})
+	meetingCfg := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, ModelName: "mock"}
+	am := svc.newAgentModels(meetingCfg, nil)
+
+	ctx := context.Background()
+	a, cfgA, err := am.forAgent(ctx, &models.AgentConfig{ID: "a", AIConfigID: "gpt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _, _ := am.forAgent(ctx, &models.AgentConfig{ID: "b", AIConfigID: "gpt"})
+	c, cfgC, _ := am.forAgent(ctx, &models.AgentConfig{ID: "c"})
+	if cfgA != custom || cfgC != meetingCfg {
+		t.Fatalf("configs = %s, %s", cfgA.ID, cfgC.ID)
+	}
+	if a != b || a == c || len(am.llms) != 2 {
+		t.Errorf("models not cached by config ID: %d instances", len(am.llms))
+	}
+}
+
+// 添加自选股时预热：各模型按配置 ID 去重，各发送一次 1 token 请求
+func TestPrimeAgentModels(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		mu.Lock()
+		calls = append(calls, fmt.Sprintf("%s/%d", q.Model, q.Options.NumPredict))
+		mu.Unlock()
+		return ollamaReply{Content: "o"}
+	})
+
+	custom := &models.AIConfig{ID: "cheap", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "custom"}
+	svc := NewServiceFull(nil, nil)
+	svc.SetAIConfigResolver(func(id string) *models.AIConfig {
+		if id == custom.ID {
+			return custom
+		}
+		return nil
+	})
+	svc.SetModeratorAIConfig(&models.AIConfig{ID: "mod", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "moderator"})
+	meetingCfg := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	agents := []models.AgentConfig{{ID: "a", AIConfigID: "cheap"}, {ID: "b", AIConfigID: "cheap"}, {ID: "c"}}
+
+	if n := svc.PrimeAgentModels(context.Background(), meetingCfg, agents); n != 3 {
+		t.Fatalf("primed = %d, want 3", n)
+	}
+	sort.Strings(calls)
+	if got := strings.Join(calls, ","); got != "custom/1,mock/1,moderator/1" {
+		t.Errorf("calls = %s", got)
+	}
+}
+
+// 限流等 HTTP 临时错误由 adk.RetryingLLM 重试，会议层不再叠加重试
+func TestRetryRun_SkipsTransientLLMErrors(t *testing.T) {
+	calls := 0
+	_, err := retryRun(context.Background(), MaxAgentRetries, func() (agentOutput, error) {
+		calls++
+		return agentOutput{}, errors.New("HTTP 429: Too Many Requests")
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("calls = %d, err = %v", calls, err)
+	}
+	if !isRetryableError(errors.New("connection reset by peer")) {
+		t.Error("network errors should still be retried")
+	}
+}
@@ -0,0 +1,96 @@
+package meeting
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/adk/tools"
+	"github.com/run-bigpig/jcp/internal/agent"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 会议进行中编辑专家，会议应始终使用开始时的快照
+// 除了重新加载容器，还原地修改传给 LoadAgents 的配置（含 Tools 底层数组），
+// 快照若与调用方或容器共享数据，第二位专家会看到新指令或新工具
+func TestRunSmartMeeting_AgentSnapshot(t *testing.T) {
+	container := agent.NewContainer()
+	configs := []models.AgentConfig{
+		{ID: "macro", Name: "宏观分析师", Role: "宏观策略", Instruction: "宏观指令-旧", Enabled: true},
+		{ID: "tech", Name: "技术分析师", Role: "技术分析", Instruction: "技术指令-旧", Tools: []string{"get_stock_realtime"}, Enabled: true},
+	}
+	container.LoadAgents(configs)
+
+	var mu sync.Mutex
+	var instructions []string
+	var techTools []string
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		switch {
+		case q.Analyze():
+			return ollamaReply{Content: `{"intent":"走势","selected":["macro","tech"],"tasks":{},"topic":"走势","opening":"开始"}`}
+		case q.Summarize():
+			return ollamaReply{Content: "总结"}
+		}
+		first := q.First()
+		mu.Lock()
+		instructions = append(instructions, first)
+		if strings.Contains(first, "技术指令") {
+			techTools = append(techTools, q.ToolNames()...)
+		}
+		mu.Unlock()
+		if strings.Contains(first, "宏观指令-旧") {
+			// 第一位专家发言期间编辑专家：原地改写第二位专家的指令与工具，
+			// 再以新配置重新加载容器（改指令并删除第二位专家）
+			configs[1].Instruction = "技术指令-新"
+			configs[1].Tools[0] = "get_kline_data"
+			container.LoadAgents([]models.AgentConfig{
+				{ID: "macro", Name: "宏观分析师", Role: "宏观策略", Instruction: "宏观指令-新", Enabled: true},
+			})
+		}
+		return ollamaReply{Content: "观点"}
+	})
+
+	svc := NewServiceFull(tools.NewRegistry(nil, nil, nil, nil, nil, nil, nil, nil, nil), nil)
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	req := ChatRequest{
+		StockCode: "sh600519",
+		Stock:     models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500},
+		Query:     "后市怎么看",
+		AllAgents: container.Snapshot().Enabled(),
+	}
+
+	responses, err := svc.RunSmartMeeting(context.Background(), aiConfig, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var speakers []string
+	for _, r := range responses {
+		if r.MsgType == "opinion" {
+			if r.Error != "" {
+				t.Fatalf("agent %s failed: %s", r.AgentID, r.Error)
+			}
+			speakers = append(speakers, r.AgentID)
+		}
+	}
+	if strings.Join(speakers, ",") != "macro,tech" {
+		t.Fatalf("speakers = %v, 已删除的专家仍应在本场会议发言", speakers)
+	}
+	if len(instructions) != 2 || !strings.Contains(instructions[1], "技术指令-旧") {
+		t.Fatalf("第二位专家应使用快照中的指令: %v", instructions)
+	}
+	for _, ins := range instructions {
+		if strings.Contains(ins, "宏观指令-新") || strings.Contains(ins, "技术指令-新") {
+			t.Fatalf("会议不应看到开始后的修改:\n%s", ins)
+		}
+	}
+	if strings.Join(techTools, ",") != "get_stock_realtime" {
+		t.Fatalf("第二位专家应使用快照中的工具: %v", techTools)
+	}
+
+	// 下一场会议使用新快照
+	if got := container.Snapshot().Enabled(); len(got) != 1 || got[0].Instruction != "宏观指令-新" {
+		t.Errorf("new snapshot = %+v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package meeting
+
+import (
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// ReasoningSummaryLength 截断保存时思考内容保留的最大字数
+const ReasoningSummaryLength = 300
+
+// agentOutput 单个专家的输出（正文与思考内容分离）
+type agentOutput struct {
+	Content     string
+	Reasoning   string
+	ToolCalls   []string // 调用过的工具，格式为 name(参数摘要)
+	ToolResults []string // 与 ToolCalls 对应的结果 JSON（截断），无任何结果时为 nil
+
+	FinishReason string // 最后一次模型响应的结束原因（genai.FinishReason），MAX_TOKENS 表示被截断
+
+	InputTokens  int64 // 本次发言累计输入 token（含工具调用轮次）
+	OutputTokens int64 // 本次发言累计输出 token（含思考）
+}
+
+// applyThinkingPersist 按持久化策略处理思考内容
+// 未配置时按 discard 处理，与历史行为保持一致
+func applyThinkingPersist(mode models.ThinkingPersist, reasoning string) string {
+	reasoning = strings.TrimSpace(reasoning)
+	if reasoning == "" {
+		return ""
+	}
+	switch mode {
+	case models.ThinkingPersistFull:
+		return reasoning
+	case models.ThinkingPersistSummary:
+		runes := []rune(reasoning)
+		if len(runes) <= ReasoningSummaryLength {
+			return reasoning
+		}
+		return string(runes[:ReasoningSummaryLength]) + "..."
+	default:
+		return ""
+	}
+}
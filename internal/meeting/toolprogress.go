@@ -0,0 +1,173 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/adk/mcp"
+
+	"google.golang.org/genai"
+)
+
+// 工具执行进度事件类型（工具 Run 前后由 Agent 回调触发，区别于模型发起调用的 tool_call/tool_result）
+const (
+	ProgressTypeToolCallStarted  = "tool_call_started"  // Detail 为工具名，Args 为格式化后的参数
+	ProgressTypeToolCallFinished = "tool_call_finished" // Content 为结果预览，DurationMs 为耗时
+	// 长耗时 MCP 工具的进度：Detail 为工具名，Content 为进度说明，Percent 为百分比，RequestID 可用于取消该调用
+	ProgressTypeToolProgress = "tool_progress"
+)
+
+// 工具进度内容长度上限（字数）
+const (
+	toolArgsPreviewLimit   = 500
+	toolResultPreviewLimit = 200
+)
+
+// newToolCallHook 将专家的工具调用转为进度事件，cb 为 nil 时返回 nil
+func newToolCallHook(cfgID, cfgName string, cb ProgressCallback) *adk.ToolCallHook {
+	if cb == nil {
+		return nil
+	}
+	return &adk.ToolCallHook{
+		OnStart: func(name string, args map[string]any) {
+			cb(ProgressEvent{
+				Type: ProgressTypeToolCallStarted, AgentID: cfgID, AgentName: cfgName,
+				Detail: name, Args: truncateRunes(prettyJSON(args, true), toolArgsPreviewLimit),
+			})
+		},
+		OnFinish: func(name string, result map[string]any, err error, elapsed time.Duration) {
+			preview := prettyJSON(result, false)
+			if err != nil {
+				preview = "错误: " + err.Error()
+			}
+			cb(ProgressEvent{
+				Type: ProgressTypeToolCallFinished, AgentID: cfgID, AgentName: cfgName,
+				Detail: name, Content: truncateRunes(preview, toolResultPreviewLimit),
+				DurationMs: elapsed.Milliseconds(),
+			})
+		},
+	}
+}
+
+// newMCPCallHooks 长耗时 MCP 工具调用时延长 ctx 上的专家及会议超时（工具超时 + extra），并将进度转为进度事件
+func newMCPCallHooks(ctx context.Context, cfgID, cfgName string, extra time.Duration, cb ProgressCallback) *mcp.CallHooks {
+	return &mcp.CallHooks{
+		OnLongRunning: func(requestID, toolName string, timeout time.Duration) {
+			extendDeadline(ctx, timeout+extra)
+			emitProgress(cb, ProgressEvent{
+				Type: ProgressTypeToolProgress, AgentID: cfgID, AgentName: cfgName,
+				Detail: toolName, Content: fmt.Sprintf("长耗时工具执行中（最长 %s）", timeout), RequestID: requestID,
+			})
+		},
+		OnProgress: func(p mcp.ToolProgress) {
+			emitProgress(cb, ProgressEvent{
+				Type: ProgressTypeToolProgress, AgentID: cfgID, AgentName: cfgName,
+				Detail: p.Tool, Content: p.Message, Percent: p.Percent(), RequestID: p.RequestID,
+			})
+		},
+	}
+}
+
+// prettyJSON 序列化工具参数或结果，indent 为 true 时缩进输出；空值返回空串
+func prettyJSON(v map[string]any, indent bool) string {
+	if len(v) == 0 {
+		return ""
+	}
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// toolCallArgsLimit 工具调用记录中参数摘要的长度上限（字数）
+const toolCallArgsLimit = 120
+
+// toolCallResultLimit 工具调用记录中结果 JSON 的长度上限（字数），超出截断
+const toolCallResultLimit = 2000
+
+// toolCallRecorder 收集专家本次发言调用过的工具及其结果，随消息保存供导出等场景使用
+// streaming 模式下同一调用可能在多个事件中重复出现，按调用 ID（无 ID 时按摘要）去重
+type toolCallRecorder struct {
+	seen    map[string]int // 去重键 -> calls 下标
+	calls   []string
+	results []string // 与 calls 一一对应，未收到结果时为空串
+}
+
+func (r *toolCallRecorder) add(call *genai.FunctionCall) {
+	summary := formatToolCall(call.Name, call.Args)
+	key := call.ID
+	if key == "" {
+		key = summary
+	}
+	if _, ok := r.seen[key]; ok {
+		return
+	}
+	if r.seen == nil {
+		r.seen = make(map[string]int)
+	}
+	r.seen[key] = len(r.calls)
+	r.calls = append(r.calls, summary)
+	r.results = append(r.results, "")
+}
+
+// addResult 记录工具结果：按调用 ID 对应，无 ID 时对应同名且尚无结果的第一次调用
+func (r *toolCallRecorder) addResult(resp *genai.FunctionResponse) {
+	i, ok := r.seen[resp.ID]
+	if resp.ID == "" || !ok {
+		i = -1
+		for j, call := range r.calls {
+			if r.results[j] == "" && strings.HasPrefix(call, resp.Name+"(") {
+				i = j
+				break
+			}
+		}
+	}
+	if i < 0 || r.results[i] != "" {
+		return
+	}
+	data, err := json.Marshal(resp.Response)
+	if err != nil {
+		return
+	}
+	r.results[i] = truncateRunes(string(data), toolCallResultLimit)
+}
+
+// toolResults 返回与 calls 对应的结果，全部为空时返回 nil
+func (r *toolCallRecorder) toolResults() []string {
+	for _, res := range r.results {
+		if res != "" {
+			return r.results
+		}
+	}
+	return nil
+}
+
+// formatToolCall 格式化为 name(k=v, ...)，参数按键排序
+func formatToolCall(name string, args map[string]any) string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v, err := json.Marshal(args[k])
+		if err != nil {
+			continue
+		}
+		parts = append(parts, k+"="+strings.Trim(string(v), `"`))
+	}
+	return name + "(" + truncateRunes(strings.Join(parts, ", "), toolCallArgsLimit) + ")"
+}
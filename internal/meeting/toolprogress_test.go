@@ -0,0 +1,70 @@
+package meeting
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+func TestNewToolCallHook(t *testing.T) {
+	if newToolCallHook("macro", "宏观分析师", nil) != nil {
+		t.Fatal("无回调时应返回 nil")
+	}
+
+	var events []ProgressEvent
+	hook := newToolCallHook("macro", "宏观分析师", func(e ProgressEvent) { events = append(events, e) })
+	hook.OnStart("get_kline_data", map[string]any{"code": "sh600519", "days": 60})
+	hook.OnFinish("get_kline_data", map[string]any{"data": strings.Repeat("k", 500)}, nil, 1500*time.Millisecond)
+	hook.OnFinish("get_news", nil, errors.New("timeout"), 0)
+
+	if len(events) != 3 {
+		t.Fatalf("events = %+v", events)
+	}
+	start := events[0]
+	if start.Type != ProgressTypeToolCallStarted || start.AgentID != "macro" || start.Detail != "get_kline_data" ||
+		start.Args != "{\n  \"code\": \"sh600519\",\n  \"days\": 60\n}" {
+		t.Errorf("start = %+v", start)
+	}
+	done := events[1]
+	if done.Type != ProgressTypeToolCallFinished || done.DurationMs != 1500 ||
+		len([]rune(done.Content)) != toolResultPreviewLimit || !strings.HasSuffix(done.Content, "…") {
+		t.Errorf("finished = %+v", done)
+	}
+	if events[2].Content != "错误: timeout" {
+		t.Errorf("error preview = %q", events[2].Content)
+	}
+}
+
+func TestToolCallRecorder(t *testing.T) {
+	var r toolCallRecorder
+	r.add(&genai.FunctionCall{ID: "c1", Name: "get_kline_data", Args: map[string]any{"days": 60, "code": "sh600519"}})
+	// streaming 模式下同一调用重复出现
+	r.add(&genai.FunctionCall{ID: "c1", Name: "get_kline_data", Args: map[string]any{"days": 60, "code": "sh600519"}})
+	r.add(&genai.FunctionCall{Name: "get_news"})
+	r.add(&genai.FunctionCall{Name: "get_news"})
+	r.add(&genai.FunctionCall{Name: "search_stocks", Args: map[string]any{"keyword": strings.Repeat("茅", 200)}})
+
+	if len(r.calls) != 3 {
+		t.Fatalf("calls = %v", r.calls)
+	}
+	if r.calls[0] != "get_kline_data(code=sh600519, days=60)" || r.calls[1] != "get_news()" {
+		t.Errorf("calls = %v", r.calls)
+	}
+	if n := len([]rune(r.calls[2])); n != len("search_stocks()")+toolCallArgsLimit {
+		t.Errorf("参数摘要应截断, len = %d", n)
+	}
+
+	if r.toolResults() != nil {
+		t.Errorf("无结果时应为 nil: %v", r.toolResults())
+	}
+	// 有 ID 按 ID 对应，无 ID 按同名且尚无结果的调用对应，重复事件不覆盖
+	r.addResult(&genai.FunctionResponse{Name: "get_news", Response: map[string]any{"items": []string{"a"}}})
+	r.addResult(&genai.FunctionResponse{ID: "c1", Name: "get_kline_data", Response: map[string]any{"count": 60}})
+	r.addResult(&genai.FunctionResponse{ID: "c1", Name: "get_kline_data", Response: map[string]any{"count": 1}})
+	if got := r.toolResults(); len(got) != 3 || got[0] != `{"count":60}` || got[1] != `{"items":["a"]}` || got[2] != "" {
+		t.Errorf("results = %q", got)
+	}
+}
@@ -0,0 +1,108 @@
+package meeting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 自动翻译目标语言
+const (
+	TranslateTargetZH = "zh"
+	TranslateTargetEN = "en"
+)
+
+// 翻译配置
+const (
+	translateAgentID    = "translator"
+	translateAgentName  = "翻译"
+	translateTimeout    = 60 * time.Second
+	minTranslateLetters = 20  // 汉字与拉丁字母总数过少（如仅有代码、数字）时不翻译
+	maxHanRatioForZH    = 0.2 // 汉字占比低于此值视为非中文
+	minHanRatioForEN    = 0.5 // 汉字占比高于此值视为中文
+)
+
+// translateLanguageNames 目标语言在提示词中的名称
+var translateLanguageNames = map[string]string{
+	TranslateTargetZH: "简体中文",
+	TranslateTargetEN: "English",
+}
+
+// ValidTranslateTarget 是否为支持的翻译目标语言
+func ValidTranslateTarget(target string) bool {
+	_, ok := translateLanguageNames[target]
+	return ok
+}
+
+// NeedsTranslation 按文字比例粗判回复语言是否与目标语言不符
+// 只统计汉字与拉丁字母，数字、标点、代码不参与判断
+func NeedsTranslation(text, target string) bool {
+	var han, latin int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if han+latin < minTranslateLetters {
+		return false
+	}
+	ratio := float64(han) / float64(han+latin)
+	switch target {
+	case TranslateTargetZH:
+		return ratio < maxHanRatioForZH
+	case TranslateTargetEN:
+		return ratio > minHanRatioForEN
+	}
+	return false
+}
+
+// buildTranslatePrompt 构建翻译提示词，要求保留 Markdown 结构与证券代码
+func buildTranslatePrompt(text, target string) string {
+	return fmt.Sprintf(`将下面的股票分析内容翻译为%s。
+要求：只输出译文，不要解释；保留 Markdown 格式、数字、证券代码与专有名词缩写；术语使用A股投资者习惯的说法。
+
+%s`, translateLanguageNames[target], text)
+}
+
+// TranslateReply 将专家回复翻译为目标语言，优先使用记忆管理的 LLM，未配置时使用 fallback
+// 无需翻译或翻译失败时返回 ok=false，调用方保留原文；翻译用量计入该股票会话的 token 统计
+func (s *Service) TranslateReply(ctx context.Context, fallback *models.AIConfig, stockCode, text, target string) (string, bool) {
+	if !NeedsTranslation(text, target) {
+		return "", false
+	}
+	cfg := s.memoryAIConfig
+	if cfg == nil {
+		cfg = fallback
+	}
+	if cfg == nil {
+		return "", false
+	}
+	llm, err := s.modelFactory.CreateModel(ctx, cfg)
+	if err != nil {
+		log.Warn("create translate LLM (%s) failed: %v", cfg.ModelName, err)
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, translateTimeout)
+	defer cancel()
+	translated, usage, err := streamPrompt(ctx, llm, buildTranslatePrompt(text, target), nil)
+	if usage != nil {
+		s.recordUsage(&models.Stock{Symbol: stockCode}, &models.AgentConfig{ID: translateAgentID, Name: translateAgentName},
+			int64(usage.PromptTokenCount), int64(usage.CandidatesTokenCount)+int64(usage.ThoughtsTokenCount))
+	}
+	if err != nil {
+		log.Warn("translate reply for %s failed, keep original: %v", stockCode, err)
+		return "", false
+	}
+	if translated = strings.TrimSpace(translated); translated == "" {
+		return "", false
+	}
+	return translated, true
+}
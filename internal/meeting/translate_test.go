@@ -0,0 +1,76 @@
+package meeting
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestNeedsTranslation(t *testing.T) {
+	english := "The stock broke out above its 20-day moving average with strong volume."
+	chinese := "贵州茅台放量突破二十日均线，短期趋势转强，MACD 金叉，建议关注回踩确认。"
+	cases := []struct {
+		text, target string
+		want         bool
+	}{
+		{english, TranslateTargetZH, true},
+		{chinese, TranslateTargetZH, false},
+		{chinese, TranslateTargetEN, true},
+		{english, TranslateTargetEN, false},
+		{"sh600519 1688.00 +2.1%", TranslateTargetZH, false}, // 字母过少
+		{english, "fr", false},
+	}
+	for _, c := range cases {
+		if got := NeedsTranslation(c.text, c.target); got != c.want {
+			t.Errorf("NeedsTranslation(%q, %s) = %v, want %v", c.text, c.target, got, c.want)
+		}
+	}
+}
+
+func TestTranslateReply(t *testing.T) {
+	fail := false
+	var prompts []string
+	srv := ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		if fail {
+			return ollamaReply{Status: http.StatusInternalServerError}
+		}
+		prompts = append(prompts, q.First())
+		return ollamaReply{Content: "放量突破二十日均线。", DoneReason: "stop", PromptEval: 50, Eval: 10}
+	})
+
+	svc := NewServiceFull(nil, nil)
+	var usages []models.TokenUsage
+	svc.SetUsageRecorder(func(stockCode string, usage models.TokenUsage) {
+		if stockCode != "sh600519" {
+			t.Errorf("stockCode = %s", stockCode)
+		}
+		usages = append(usages, usage)
+	})
+	aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: srv.URL, ModelName: "mock"}
+	english := "The stock broke out above its 20-day moving average with strong volume."
+
+	got, ok := svc.TranslateReply(context.Background(), aiConfig, "sh600519", english, TranslateTargetZH)
+	if !ok || got != "放量突破二十日均线。" {
+		t.Fatalf("translated = %q, ok = %v", got, ok)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "简体中文") || !strings.Contains(prompts[0], english) {
+		t.Errorf("prompts = %v", prompts)
+	}
+	if len(usages) != 1 || usages[0].AgentID != translateAgentID || usages[0].TotalTokens != 60 {
+		t.Errorf("usages = %+v", usages)
+	}
+
+	// 已是目标语言时不调用模型
+	if _, ok := svc.TranslateReply(context.Background(), aiConfig, "sh600519", "放量突破二十日均线，短期趋势转强，建议关注回踩确认。", TranslateTargetZH); ok || len(prompts) != 1 {
+		t.Errorf("chinese reply should not be translated, prompts = %d", len(prompts))
+	}
+
+	// 翻译失败时保留原文
+	fail = true
+	if _, ok := svc.TranslateReply(context.Background(), aiConfig, "sh600519", english, TranslateTargetZH); ok {
+		t.Error("expected fallback to original on failure")
+	}
+}
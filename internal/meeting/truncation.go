@@ -0,0 +1,60 @@
+package meeting
+
+import (
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/genai"
+)
+
+// ProgressTypeTruncated 专家回复因输出长度上限被截断；Detail 说明是否正在自动续写
+const ProgressTypeTruncated = "truncated"
+
+// TruncatedMarker 截断的回复末尾追加的提示
+const TruncatedMarker = "[回复因长度限制被截断]"
+
+// continuePrompt 自动续写时发送的用户消息
+const continuePrompt = "继续"
+
+// 续写拼接时检查重叠的范围（字数）：过短的重叠可能是巧合，不去重
+const (
+	minStitchOverlap = 4
+	maxStitchOverlap = 500
+)
+
+// SetAutoContinue 设置专家回复被截断时是否自动续写一次
+func (s *Service) SetAutoContinue(enabled bool) {
+	s.autoContinue = enabled
+}
+
+// isTruncated 结束原因是否为输出长度截断
+func isTruncated(reason genai.FinishReason) bool {
+	return reason == genai.FinishReasonMaxTokens
+}
+
+// stitchContinuation 拼接续写内容：模型常从截断处的半句重新开始，去掉续写开头与原文结尾重复的部分
+func stitchContinuation(text, cont string) string {
+	if text == "" {
+		return cont
+	}
+	head := strings.TrimLeft(cont, " \t\r\n")
+	tail, next := []rune(text), []rune(head)
+	limit := min(len(tail), len(next), maxStitchOverlap)
+	for n := limit; n >= minStitchOverlap; n-- {
+		if string(tail[len(tail)-n:]) == string(next[:n]) {
+			return text + string(next[n:])
+		}
+	}
+	return text + cont
+}
+
+// markTruncated 在截断的回复末尾追加提示，并推送截断事件
+func markTruncated(content string, cfg *models.AgentConfig, progressCallback ProgressCallback) string {
+	log.Warn("agent %s reply truncated by max tokens", cfg.ID)
+	emitProgress(progressCallback, ProgressEvent{
+		Type: ProgressTypeTruncated, AgentID: cfg.ID, AgentName: cfg.Name,
+		Detail: cfg.Name + " 的回复因长度限制被截断",
+	})
+	return strings.TrimRight(content, " \t\r\n") + "\n\n" + TruncatedMarker
+}
@@ -0,0 +1,92 @@
+package meeting
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestStitchContinuation(t *testing.T) {
+	tests := []struct {
+		text, cont, want string
+	}{
+		{"短期市场情绪偏向谨", "市场情绪偏向谨慎，建议观望。", "短期市场情绪偏向谨慎，建议观望。"},
+		{"短期市场情绪偏向谨", "\n慎，建议观望。", "短期市场情绪偏向谨\n慎，建议观望。"},
+		{"支撑位在 12", "12.5 元附近", "支撑位在 1212.5 元附近"}, // 重叠过短视为巧合
+		{"", "全部内容", "全部内容"},
+	}
+	for _, tt := range tests {
+		if got := stitchContinuation(tt.text, tt.cont); got != tt.want {
+			t.Errorf("stitchContinuation(%q, %q) = %q, want %q", tt.text, tt.cont, got, tt.want)
+		}
+	}
+}
+
+// truncationMockServer 模拟 Ollama：专家首次回复因长度截断，收到"继续"后补全
+func truncationMockServer(t *testing.T) *httptest.Server {
+	return ollamaMockServer(t, func(q *ollamaRequest) ollamaReply {
+		reply, reason := "短期市场情绪偏向谨", "length"
+		switch {
+		case q.Analyze():
+			reply, reason = `{"intent":"走势","selected":["a1"],"topic":"走势","opening":"开始"}`, "stop"
+		case q.Summarize():
+			reply, reason = "总结", "stop"
+		case q.Last() == continuePrompt:
+			reply, reason = "市场情绪偏向谨慎，建议观望。", "stop"
+		}
+		return ollamaReply{Content: reply, DoneReason: reason, PromptEval: 10, Eval: 5}
+	})
+}
+
+func TestRunSmartMeeting_Truncated(t *testing.T) {
+	for _, autoContinue := range []bool{false, true} {
+		name := "marker"
+		if autoContinue {
+			name = "auto_continue"
+		}
+		t.Run(name, func(t *testing.T) {
+			svc := NewServiceFull(nil, nil)
+			svc.SetAutoContinue(autoContinue)
+			aiConfig := &models.AIConfig{ID: "mock", Provider: models.AIProviderOllama, BaseURL: truncationMockServer(t).URL, ModelName: "mock"}
+			req := parallelRequest()
+			req.ParallelRounds = false
+
+			var truncated []string
+			responses, err := svc.RunSmartMeetingWithCallback(context.Background(), aiConfig, req, nil, func(ev ProgressEvent) {
+				if ev.Type == ProgressTypeTruncated {
+					truncated = append(truncated, ev.Detail)
+				}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(responses) != 3 {
+				t.Fatalf("responses = %+v", responses)
+			}
+			got := responses[1]
+
+			if !autoContinue {
+				if got.Content != "短期市场情绪偏向谨\n\n"+TruncatedMarker || got.FinishReason != "MAX_TOKENS" {
+					t.Fatalf("response = %+v", got)
+				}
+				if len(truncated) != 1 {
+					t.Fatalf("truncated events = %v", truncated)
+				}
+				return
+			}
+			// 续写去掉重叠部分，用量计入同一条发言
+			if got.Content != "短期市场情绪偏向谨慎，建议观望。" || got.FinishReason != "STOP" {
+				t.Fatalf("response = %+v", got)
+			}
+			if got.PromptTokens != 20 || got.CompletionTokens != 10 {
+				t.Errorf("usage = %d/%d, want 20/10", got.PromptTokens, got.CompletionTokens)
+			}
+			if len(truncated) != 1 || !strings.Contains(truncated[0], "自动续写") {
+				t.Errorf("truncated events = %v", truncated)
+			}
+		})
+	}
+}
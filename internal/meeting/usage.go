@@ -0,0 +1,65 @@
+package meeting
+
+import (
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// ProgressTypeUsage 专家发言结束后推送的用量事件，携带本场会议的累计用量
+const ProgressTypeUsage = "usage"
+
+// MeetingUsage 单场会议的 token 用量与估算费用
+// 智能会议 / 直接提问开始时重置，继续会议与单专家重试在此基础上累加
+type MeetingUsage struct {
+	StockCode        string  `json:"stockCode"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	TotalTokens      int64   `json:"totalTokens"`
+	Cost             float64 `json:"cost"`  // 估算费用，按各模型 AIConfig 中的千 token 单价计算
+	Turns            int     `json:"turns"` // 计入统计的专家发言次数
+}
+
+// estimateCost 按 AIConfig 中的单价估算费用，未配置单价时为 0
+func estimateCost(aiConfig *models.AIConfig, promptTokens, completionTokens int64) float64 {
+	if aiConfig == nil {
+		return 0
+	}
+	return float64(promptTokens)/1000*aiConfig.InputPricePer1K +
+		float64(completionTokens)/1000*aiConfig.OutputPricePer1K
+}
+
+// resetMeetingUsage 新会议开始时清空该股票的用量
+func (s *Service) resetMeetingUsage(stockCode string) {
+	if stockCode == "" {
+		return
+	}
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	delete(s.meetingUsage, stockCode)
+}
+
+// addMeetingUsage 累加一次专家发言的用量，返回累计快照
+func (s *Service) addMeetingUsage(stockCode string, aiConfig *models.AIConfig, promptTokens, completionTokens int64) MeetingUsage {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	u, ok := s.meetingUsage[stockCode]
+	if !ok {
+		u = &MeetingUsage{StockCode: stockCode}
+		s.meetingUsage[stockCode] = u
+	}
+	u.PromptTokens += promptTokens
+	u.CompletionTokens += completionTokens
+	u.TotalTokens += promptTokens + completionTokens
+	u.Cost += estimateCost(aiConfig, promptTokens, completionTokens)
+	u.Turns++
+	return *u
+}
+
+// GetMeetingUsage 获取该股票最近一场会议的用量，无记录时返回零值
+func (s *Service) GetMeetingUsage(stockCode string) MeetingUsage {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	if u, ok := s.meetingUsage[stockCode]; ok {
+		return *u
+	}
+	return MeetingUsage{StockCode: stockCode}
+}
@@ -0,0 +1,91 @@
+package meeting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 前序发言上下文预算（按字数计，约等于 token 上限的保守估计）
+// sequential/debate 模式会把前面专家的发言注入后续专家的上下文，需限制总量
+const (
+	PeerContextBudget   = 2400 // 注入的前序发言总字数上限
+	PeerEntryMinRunes   = 200  // 单条发言截断后最少保留字数
+	peerTruncatedSuffix = "...（已截断）"
+)
+
+// normalizeVisibility 规范化可见性配置，未知值按 sequential 处理（保持历史行为）
+func normalizeVisibility(v models.MeetingVisibility) models.MeetingVisibility {
+	switch v {
+	case models.MeetingVisibilityIndependent, models.MeetingVisibilityDebate:
+		return v
+	default:
+		return models.MeetingVisibilitySequential
+	}
+}
+
+// visibilityLabel 可见性模式的中文说明（用于主持人 Prompt）
+func visibilityLabel(v models.MeetingVisibility) string {
+	switch normalizeVisibility(v) {
+	case models.MeetingVisibilityIndependent:
+		return "独立发言（专家互不可见，各自给出独立观点）"
+	case models.MeetingVisibilityDebate:
+		return "辩论（后发言专家可见前面发言，并需点名表示赞同或反驳）"
+	default:
+		return "顺序发言（后发言专家可参考前面专家的观点）"
+	}
+}
+
+// buildPeerContext 按可见性模式构建前面专家发言的上下文
+// independent 不注入；sequential 注入发言；debate 额外要求点名赞同或反驳
+func buildPeerContext(history []DiscussionEntry, visibility models.MeetingVisibility) string {
+	visibility = normalizeVisibility(visibility)
+	if visibility == models.MeetingVisibilityIndependent || len(history) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("【前面专家的发言】\n")
+	for i, entry := range history {
+		content := truncatePeerEntry(entry.Content, peerEntryBudget(history, i))
+		fmt.Fprintf(&sb, "- %s（%s）：%s\n\n", entry.AgentName, entry.Role, content)
+	}
+
+	if visibility == models.MeetingVisibilityDebate {
+		names := make([]string, 0, len(history))
+		for _, entry := range history {
+			names = append(names, entry.AgentName)
+		}
+		fmt.Fprintf(&sb, "【辩论要求】请逐一点名回应 %s 的观点，明确写出“赞同某某”或“反驳某某”并给出理由，不要简单复述。\n",
+			strings.Join(names, "、"))
+	}
+	return sb.String()
+}
+
+// peerEntryBudget 计算第 i 条发言可用的字数
+// 越靠近当前发言者的内容越重要：最近一条不截断（受总预算约束），较早的平分剩余预算
+func peerEntryBudget(history []DiscussionEntry, i int) int {
+	last := len(history) - 1
+	lastLen := len([]rune(history[last].Content))
+	if lastLen > PeerContextBudget/2 {
+		lastLen = PeerContextBudget / 2
+	}
+	if i == last {
+		return lastLen
+	}
+	share := (PeerContextBudget - lastLen) / last
+	if share < PeerEntryMinRunes {
+		share = PeerEntryMinRunes
+	}
+	return share
+}
+
+// truncatePeerEntry 按字数截断单条发言
+func truncatePeerEntry(content string, limit int) string {
+	runes := []rune(content)
+	if len(runes) <= limit {
+		return content
+	}
+	return string(runes[:limit]) + peerTruncatedSuffix
+}
@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 跨股票记忆参数
+const (
+	relatedSnippetLimit  = 120 // 关联记忆片段字数上限
+	RelatedContextLimit  = 3   // 注入讨论上下文的关联股票数
+	minStockNameRuneSize = 2   // 名称过短（如单字）时不参与匹配，避免误判
+)
+
+// stockCodePattern 匹配 sh600519、600519、600519.SH 等形式的股票代码
+var stockCodePattern = regexp.MustCompile(`(?i)\b(sh|sz|bj)?(\d{6})(?:\.(sh|sz|bj))?\b`)
+
+// CrossRef 两只股票之间的交叉引用
+type CrossRef struct {
+	Count     int   `json:"count"`      // 摘要中互相提及的次数
+	UpdatedAt int64 `json:"updated_at"` // 最近一次提及时间
+}
+
+// RelatedMemoryItem 关联股票的记忆片段
+type RelatedMemoryItem struct {
+	StockCode string `json:"stockCode"`
+	StockName string `json:"stockName"`
+	Snippet   string `json:"snippet"`   // 关联股票摘要中提及本股票的内容，未直接提及时为摘要开头
+	Mentions  int    `json:"mentions"`  // 互相提及次数
+	UpdatedAt int64  `json:"updatedAt"` // 最近一次提及时间
+}
+
+// GraphStore 跨股票记忆图（全局 graph.json），边为双向交叉引用
+type GraphStore struct {
+	path   string
+	mu     sync.Mutex
+	edges  map[string]map[string]*CrossRef // 股票代码 -> 关联股票代码 -> 引用
+	linked map[string]int64                // 股票代码 -> 已计入引用的最新讨论轮次时间
+}
+
+// graphFile graph.json 的内容；早期版本只保存 edges 本身
+type graphFile struct {
+	Edges  map[string]map[string]*CrossRef `json:"edges"`
+	Linked map[string]int64                `json:"linked"`
+}
+
+// NewGraphStore 创建跨股票记忆图存储
+func NewGraphStore(dataDir string) *GraphStore {
+	dir := filepath.Join(dataDir, "memory_graph")
+	os.MkdirAll(dir, 0755)
+	return &GraphStore{path: filepath.Join(dir, "graph.json")}
+}
+
+// loadLocked 首次使用时从文件加载，文件不存在视为空图
+func (g *GraphStore) loadLocked() error {
+	if g.edges != nil {
+		return nil
+	}
+	g.edges = make(map[string]map[string]*CrossRef)
+	g.linked = make(map[string]int64)
+	data, err := os.ReadFile(g.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var file graphFile
+	if err := json.Unmarshal(data, &file); err == nil && file.Edges != nil {
+		g.edges = file.Edges
+		if file.Linked != nil {
+			g.linked = file.Linked
+		}
+		return nil
+	}
+	return json.Unmarshal(data, &g.edges)
+}
+
+// Link 记录 code 与 related 中各股票的双向引用
+// upTo 为本次摘要覆盖的最新讨论轮次时间，不晚于已计入的轮次时跳过，
+// 避免回滚记忆后重新压缩同一批轮次时重复计数
+func (g *GraphStore) Link(code string, related []string, upTo int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.loadLocked(); err != nil {
+		return err
+	}
+	if upTo <= g.linked[code] {
+		return nil
+	}
+	g.linked[code] = upTo
+	now := time.Now().UnixMilli()
+	for _, other := range related {
+		if other == code {
+			continue
+		}
+		g.addLocked(code, other, now)
+		g.addLocked(other, code, now)
+	}
+	data, err := json.MarshalIndent(graphFile{Edges: g.edges, Linked: g.linked}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(g.path, data, 0644)
+}
+
+func (g *GraphStore) addLocked(from, to string, now int64) {
+	refs, ok := g.edges[from]
+	if !ok {
+		refs = make(map[string]*CrossRef)
+		g.edges[from] = refs
+	}
+	ref, ok := refs[to]
+	if !ok {
+		ref = &CrossRef{}
+		refs[to] = ref
+	}
+	ref.Count++
+	ref.UpdatedAt = now
+}
+
+// Related 返回与 code 关联的股票，按提及次数、最近提及时间倒序
+func (g *GraphStore) Related(code string) (map[string]CrossRef, []string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.loadLocked(); err != nil {
+		return nil, nil, err
+	}
+	refs := make(map[string]CrossRef, len(g.edges[code]))
+	codes := make([]string, 0, len(g.edges[code]))
+	for other, ref := range g.edges[code] {
+		refs[other] = *ref
+		codes = append(codes, other)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		a, b := refs[codes[i]], refs[codes[j]]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.UpdatedAt > b.UpdatedAt
+	})
+	return refs, codes, nil
+}
+
+// extractMentionedStocks 从文本中提取提及的股票
+// 带市场前缀的代码直接采用；不带前缀的代码与股票名称只匹配已有记忆的股票（known: 代码 -> 名称），避免把金额等数字误判为代码
+func extractMentionedStocks(text, self string, known map[string]string) []string {
+	seen := map[string]bool{self: true}
+	var found []string
+	add := func(code string) {
+		if !seen[code] {
+			seen[code] = true
+			found = append(found, code)
+		}
+	}
+
+	// 不带前缀的代码 -> 已知的完整代码
+	bare := make(map[string]string, len(known))
+	for code := range known {
+		if len(code) == 8 {
+			bare[code[2:]] = code
+		}
+	}
+	for _, m := range stockCodePattern.FindAllStringSubmatch(text, -1) {
+		market := strings.ToLower(m[1] + m[3])
+		switch {
+		case market == "sh" || market == "sz" || market == "bj":
+			add(market + m[2])
+		case bare[m[2]] != "":
+			add(bare[m[2]])
+		}
+	}
+
+	codes := make([]string, 0, len(known))
+	for code := range known {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if name := known[code]; len([]rune(name)) >= minStockNameRuneSize && strings.Contains(text, name) {
+			add(code)
+		}
+	}
+	return found
+}
+
+// mentionSnippet 从摘要中取出提及目标股票（名称或代码）的句子，找不到时返回摘要开头
+func mentionSnippet(summary, code, name string) string {
+	summary = strings.TrimSpace(summary)
+	terms := []string{code}
+	if len(code) == 8 {
+		terms = append(terms, code[2:])
+	}
+	if len([]rune(name)) >= minStockNameRuneSize {
+		terms = append(terms, name)
+	}
+
+	var hits []string
+	for _, sentence := range splitSentences(summary) {
+		for _, term := range terms {
+			if strings.Contains(strings.ToLower(sentence), strings.ToLower(term)) {
+				hits = append(hits, sentence)
+				break
+			}
+		}
+	}
+	if len(hits) > 0 {
+		return truncateRunes(strings.Join(hits, ""), relatedSnippetLimit)
+	}
+	return truncateRunes(summary, relatedSnippetLimit)
+}
+
+// splitSentences 按中文句号、分号、换行切分句子，保留句末标点
+func splitSentences(text string) []string {
+	var sentences []string
+	var sb strings.Builder
+	for _, r := range text {
+		if r == '\n' {
+			if s := strings.TrimSpace(sb.String()); s != "" {
+				sentences = append(sentences, s)
+			}
+			sb.Reset()
+			continue
+		}
+		sb.WriteRune(r)
+		if r == '。' || r == '；' || r == '！' || r == '？' {
+			sentences = append(sentences, strings.TrimSpace(sb.String()))
+			sb.Reset()
+		}
+	}
+	if s := strings.TrimSpace(sb.String()); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// truncateRunes 按字数截断，超出时以省略号结尾
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubSummarizer 返回固定摘要
+type stubSummarizer struct{ summary string }
+
+func (s stubSummarizer) SummarizeRounds(context.Context, []RoundMemory) (string, error) {
+	return s.summary, nil
+}
+func (stubSummarizer) ExtractFacts(context.Context, string, string) ([]MemoryEntry, error) {
+	return nil, nil
+}
+func (stubSummarizer) ExtractKeyPoints(context.Context, []DiscussionInput) ([]string, error) {
+	return nil, nil
+}
+func (stubSummarizer) ExtractActions(context.Context, string) ([]ActionEntry, error) {
+	return nil, nil
+}
+
+func TestExtractMentionedStocks(t *testing.T) {
+	known := map[string]string{"sh688347": "华虹公司", "sh688981": "中芯国际", "sz000001": "平安银行"}
+	text := "中芯国际扩产利好设备链，华虹公司(688347)同样受益；对比 SZ300750 与 600519.SH。营收增长123456万元。"
+	got := extractMentionedStocks(text, "sh688981", known)
+	// 带前缀代码直接采用，裸代码只匹配已知股票，自身不计入
+	want := []string{"sh688347", "sz300750", "sh600519"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mentions = %v, want %v", got, want)
+	}
+}
+
+func TestMentionSnippet(t *testing.T) {
+	summary := "产能利用率回升。华虹公司同样受益于国产替代；估值偏高。"
+	if got := mentionSnippet(summary, "sh688347", "华虹公司"); got != "华虹公司同样受益于国产替代；" {
+		t.Errorf("snippet = %q", got)
+	}
+	if got := mentionSnippet(summary, "sz000001", "平安银行"); got != summary {
+		t.Errorf("fallback snippet = %q", got)
+	}
+}
+
+func TestGetRelatedMemories(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManagerWithConfig(dir, Config{MaxRecentRounds: 1, MaxKeyFacts: 20, MaxSummaryLength: 300, CompressThreshold: 2})
+	defer m.Close()
+	m.summarizer = stubSummarizer{summary: "晶圆代工景气回升。华虹公司产能利用率同样改善，可对照跟踪。"}
+
+	huahong := NewStockMemory("sh688347", "华虹公司")
+	huahong.Summary = "特色工艺需求稳定。"
+	if err := m.Save(huahong); err != nil {
+		t.Fatal(err)
+	}
+	smic := NewStockMemory("sh688981", "中芯国际")
+	for i := 0; i < 2; i++ {
+		if err := m.AddRound(context.Background(), smic, "怎么看", "景气回升", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Save(smic); err != nil {
+		t.Fatal(err)
+	}
+
+	// 双向引用：华虹的关联记忆来自中芯国际摘要中提及华虹的句子
+	related := m.GetRelatedMemories("sh688347", 5)
+	if len(related) != 1 || related[0].StockCode != "sh688981" || related[0].Snippet != "华虹公司产能利用率同样改善，可对照跟踪。" || related[0].Mentions != 1 {
+		t.Fatalf("related = %+v", related)
+	}
+	if back := m.GetRelatedMemories("sh688981", 5); len(back) != 1 || back[0].Snippet != "特色工艺需求稳定。" {
+		t.Fatalf("reverse related = %+v", back)
+	}
+	if ctx := m.BuildContext(huahong, "后市"); !strings.Contains(ctx, "【关联股票记忆】\n- 中芯国际(sh688981): 华虹公司产能利用率") {
+		t.Errorf("context = %s", ctx)
+	}
+
+	// 图持久化到 graph.json，重新打开后仍可查询
+	reopened := NewGraphStore(dir)
+	if _, codes, err := reopened.Related("sh688981"); err != nil || !reflect.DeepEqual(codes, []string{"sh688347"}) {
+		t.Errorf("reopened = %v, %v", codes, err)
+	}
+}
+
+// 回滚记忆后重新压缩同一批轮次不重复计数，新的轮次照常计入
+func TestGraphLink_CountsNewRoundsOnly(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManagerWithConfig(dir, Config{MaxRecentRounds: 1, MaxKeyFacts: 20, MaxSummaryLength: 300, CompressThreshold: 2})
+	defer m.Close()
+	m.summarizer = stubSummarizer{summary: "华虹公司产能利用率同样改善。"}
+	if err := m.Save(NewStockMemory("sh688347", "华虹公司")); err != nil {
+		t.Fatal(err)
+	}
+
+	smic := NewStockMemory("sh688981", "中芯国际")
+	for i := 0; i < 2; i++ {
+		if err := m.AddRound(context.Background(), smic, "怎么看", "景气回升", nil); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			smic.RecentRounds[0].Timestamp = 1000
+		}
+	}
+	mentions := func() int {
+		refs, _, err := m.graph.Related("sh688347")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return refs["sh688981"].Count
+	}
+	if got := mentions(); got != 1 {
+		t.Fatalf("mentions = %d, want 1", got)
+	}
+
+	// 模拟回滚：压缩过的轮次重新出现并再次压缩
+	smic.RecentRounds = append([]RoundMemory{{Round: 1, Query: "怎么看", Consensus: "景气回升", Timestamp: 1000}}, smic.RecentRounds...)
+	if err := m.compress(context.Background(), smic); err != nil {
+		t.Fatal(err)
+	}
+	if got := mentions(); got != 1 {
+		t.Fatalf("recompressed mentions = %d, want 1", got)
+	}
+
+	// 新一轮被压缩时计入
+	if err := m.AddRound(context.Background(), smic, "还能买吗", "继续跟踪", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := mentions(); got != 2 {
+		t.Fatalf("new round mentions = %d, want 2", got)
+	}
+}
+
+func TestGraphStore_LegacyFile(t *testing.T) {
+	dir := t.TempDir()
+	g := NewGraphStore(dir)
+	legacy := `{"sh688981":{"sh688347":{"count":3,"updated_at":1}},"sh688347":{"sh688981":{"count":3,"updated_at":1}}}`
+	if err := os.WriteFile(g.path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if refs, codes, err := g.Related("sh688981"); err != nil || !reflect.DeepEqual(codes, []string{"sh688347"}) || refs["sh688347"].Count != 3 {
+		t.Fatalf("legacy = %v, %v, %v", refs, codes, err)
+	}
+	if err := g.Link("sh688981", []string{"sh688347"}, 5); err != nil {
+		t.Fatal(err)
+	}
+	if refs, _, _ := NewGraphStore(dir).Related("sh688347"); refs["sh688981"].Count != 4 {
+		t.Errorf("after link = %+v", refs)
+	}
+}
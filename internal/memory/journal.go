@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActionType 用户实际操作类型
+type ActionType string
+
+const (
+	ActionBuy  ActionType = "buy"  // 买入、建仓、加仓、补仓
+	ActionSell ActionType = "sell" // 卖出、减仓、清仓、止损止盈
+	ActionHold ActionType = "hold" // 决定继续持有、不动
+)
+
+// MaxJournalEntries 每只股票保留的操作日志条数，超出时删除最旧的
+const MaxJournalEntries = 200
+
+// JournalContextEntries 注入风控专家上下文的最近操作条数
+const JournalContextEntries = 5
+
+// ErrActionNotFound 操作日志条目不存在
+var ErrActionNotFound = errors.New("操作记录不存在")
+
+// actionKeywords 可能包含操作陈述的关键词，未命中时不调用 LLM
+var actionKeywords = []string{
+	"买", "卖", "加仓", "减仓", "清仓", "建仓", "补仓", "平仓", "止损", "止盈",
+	"割肉", "抛", "出了", "上车", "下车", "持有", "拿着", "不动",
+}
+
+// ActionEntry 操作日志条目：用户在对话中陈述的实际决策，区别于专家分析
+type ActionEntry struct {
+	ID        string     `json:"id"`
+	StockCode string     `json:"stockCode"`
+	Action    ActionType `json:"action"`
+	Price     float64    `json:"price,omitempty"`    // 成交价，未提及为 0
+	Quantity  string     `json:"quantity,omitempty"` // 数量描述，如 1000股、三分之一
+	Note      string     `json:"note"`               // 操作摘要
+	Source    string     `json:"source"`             // 用户原话
+	Timestamp int64      `json:"timestamp"`
+	Edited    bool       `json:"edited,omitempty"` // 用户修改过
+}
+
+// mayContainAction 快速判断内容是否可能包含操作陈述
+func mayContainAction(content string) bool {
+	for _, kw := range actionKeywords {
+		if strings.Contains(content, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// JournalStore 操作日志文件存储（按股票隔离）
+type JournalStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJournalStore 创建操作日志存储
+func NewJournalStore(dataDir string) *JournalStore {
+	dir := filepath.Join(dataDir, "journals")
+	os.MkdirAll(dir, 0755)
+	return &JournalStore{dir: dir}
+}
+
+func (j *JournalStore) getPath(stockCode string) string {
+	return filepath.Join(j.dir, stockCode+".json")
+}
+
+// List 按时间顺序返回股票的操作日志
+func (j *JournalStore) List(stockCode string) ([]ActionEntry, error) {
+	if !validName(stockCode) {
+		return nil, fmt.Errorf("无效的股票代码: %q", stockCode)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.loadLocked(stockCode)
+}
+
+// Add 追加操作日志
+func (j *JournalStore) Add(stockCode string, entries []ActionEntry) error {
+	if !validName(stockCode) {
+		return fmt.Errorf("无效的股票代码: %q", stockCode)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	list, err := j.loadLocked(stockCode)
+	if err != nil {
+		return err
+	}
+	list = append(list, entries...)
+	if len(list) > MaxJournalEntries {
+		list = list[len(list)-MaxJournalEntries:]
+	}
+	return j.saveLocked(stockCode, list)
+}
+
+// Update 修改操作日志条目（按 ID 匹配），并标记为用户修改
+func (j *JournalStore) Update(stockCode string, entry ActionEntry) error {
+	return j.modify(stockCode, entry.ID, func(list []ActionEntry, i int) []ActionEntry {
+		entry.StockCode = stockCode
+		entry.Timestamp = list[i].Timestamp
+		entry.Edited = true
+		list[i] = entry
+		return list
+	})
+}
+
+// Delete 删除操作日志条目
+func (j *JournalStore) Delete(stockCode, id string) error {
+	return j.modify(stockCode, id, func(list []ActionEntry, i int) []ActionEntry {
+		return append(list[:i], list[i+1:]...)
+	})
+}
+
+func (j *JournalStore) modify(stockCode, id string, fn func([]ActionEntry, int) []ActionEntry) error {
+	if !validName(stockCode) {
+		return fmt.Errorf("无效的股票代码: %q", stockCode)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	list, err := j.loadLocked(stockCode)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		if list[i].ID == id {
+			return j.saveLocked(stockCode, fn(list, i))
+		}
+	}
+	return ErrActionNotFound
+}
+
+func (j *JournalStore) loadLocked(stockCode string) ([]ActionEntry, error) {
+	data, err := os.ReadFile(j.getPath(stockCode))
+	if os.IsNotExist(err) {
+		return []ActionEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []ActionEntry
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (j *JournalStore) saveLocked(stockCode string, list []ActionEntry) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.getPath(stockCode), data, 0644)
+}
+
+// buildJournalContext 把最近的操作日志格式化为专家上下文
+func buildJournalContext(entries []ActionEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	if len(entries) > JournalContextEntries {
+		entries = entries[len(entries)-JournalContextEntries:]
+	}
+	var sb strings.Builder
+	sb.WriteString("【用户实际操作记录】（建议需考虑用户已做的操作）\n")
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "- [%s] %s", time.UnixMilli(e.Timestamp).Format("2006-01-02 15:04"), actionLabel(e.Action))
+		if e.Price > 0 {
+			fmt.Fprintf(&sb, " 价格%.2f", e.Price)
+		}
+		if e.Quantity != "" {
+			fmt.Fprintf(&sb, " 数量%s", e.Quantity)
+		}
+		if e.Note != "" {
+			fmt.Fprintf(&sb, "：%s", e.Note)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func actionLabel(action ActionType) string {
+	switch action {
+	case ActionBuy:
+		return "买入"
+	case ActionSell:
+		return "卖出"
+	case ActionHold:
+		return "持有"
+	}
+	return string(action)
+}
+
+// ExtractActions 从用户消息中提取实际操作
+func (s *LLMSummarizer) ExtractActions(ctx context.Context, content string) ([]ActionEntry, error) {
+	result, err := s.generate(ctx, buildActionPrompt(content))
+	if err != nil {
+		return nil, err
+	}
+	return parseActions(result, content)
+}
+
+func buildActionPrompt(content string) string {
+	return fmt.Sprintf(`判断以下用户发言中是否陈述了自己已经执行或已经决定的交易操作（买入/卖出/继续持有）。
+
+用户发言：
+%s
+
+要求：
+1. 只提取用户本人已做或明确决定的操作，提问、假设、计划中的操作和对行情的分析都不算
+2. 加仓、补仓、建仓算 buy；减仓、清仓、止损、止盈算 sell；决定不动、继续拿着算 hold
+3. 提到价格时填 price（数字），提到数量或比例时原样填 quantity（如 1000股、三分之一）
+4. note 用一句话概括操作，不超过30字
+
+请以JSON数组格式输出，每项包含 action、price、quantity、note；没有操作时输出 []。
+只输出JSON数组，不要其他内容：`, content)
+}
+
+func parseActions(jsonStr, source string) ([]ActionEntry, error) {
+	jsonStr = strings.TrimSpace(jsonStr)
+	jsonStr = strings.TrimPrefix(jsonStr, "```json")
+	jsonStr = strings.TrimPrefix(jsonStr, "```")
+	jsonStr = strings.TrimSuffix(jsonStr, "```")
+	jsonStr = strings.TrimSpace(jsonStr)
+
+	var raw []struct {
+		Action   string  `json:"action"`
+		Price    float64 `json:"price"`
+		Quantity string  `json:"quantity"`
+		Note     string  `json:"note"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
+		return nil, fmt.Errorf("parse actions json error: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	entries := make([]ActionEntry, 0, len(raw))
+	for _, r := range raw {
+		action := ActionType(strings.ToLower(strings.TrimSpace(r.Action)))
+		if action != ActionBuy && action != ActionSell && action != ActionHold {
+			continue
+		}
+		entries = append(entries, ActionEntry{
+			ID:        uuid.New().String(),
+			Action:    action,
+			Price:     max(r.Price, 0),
+			Quantity:  strings.TrimSpace(r.Quantity),
+			Note:      strings.TrimSpace(r.Note),
+			Source:    source,
+			Timestamp: now,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJournalStore(t *testing.T) {
+	j := NewJournalStore(t.TempDir())
+
+	if list, err := j.List("sh600519"); err != nil || len(list) != 0 {
+		t.Fatalf("empty journal = %v, %v", list, err)
+	}
+	entries := []ActionEntry{
+		{ID: "a1", StockCode: "sh600519", Action: ActionSell, Price: 14.2, Quantity: "三分之一", Note: "减仓三分之一", Timestamp: 1},
+		{ID: "a2", StockCode: "sh600519", Action: ActionHold, Note: "剩余继续持有", Timestamp: 2},
+	}
+	if err := j.Add("sh600519", entries); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := ActionEntry{ID: "a1", Action: ActionSell, Price: 14.3, Quantity: "三分之一", Note: "减仓"}
+	if err := j.Update("sh600519", edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Delete("sh600519", "a2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Delete("sh600519", "missing"); !errors.Is(err, ErrActionNotFound) {
+		t.Errorf("delete missing: err = %v", err)
+	}
+
+	list, _ := j.List("sh600519")
+	if len(list) != 1 || list[0].Price != 14.3 || !list[0].Edited || list[0].Timestamp != 1 || list[0].StockCode != "sh600519" {
+		t.Fatalf("journal = %+v", list)
+	}
+	if _, err := j.List("../x"); err == nil {
+		t.Error("非法股票代码应返回错误")
+	}
+}
+
+func TestParseActions(t *testing.T) {
+	source := "我在14.2减了三分之一，剩下的先拿着"
+	got, err := parseActions("```json\n"+`[{"action":"sell","price":14.2,"quantity":"三分之一","note":"减仓三分之一"},{"action":"HOLD","note":"剩余继续持有"},{"action":"watch","note":"无效"}]`+"\n```", source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Action != ActionSell || got[0].Price != 14.2 || got[1].Action != ActionHold || got[0].Source != source {
+		t.Fatalf("actions = %+v", got)
+	}
+
+	if !mayContainAction(source) || mayContainAction("这只股票的估值怎么样？") {
+		t.Error("mayContainAction 判断错误")
+	}
+
+	ctx := buildJournalContext(got)
+	if !strings.Contains(ctx, "卖出 价格14.20 数量三分之一：减仓三分之一") || !strings.Contains(ctx, "持有：剩余继续持有") {
+		t.Errorf("journal context = %q", ctx)
+	}
+}
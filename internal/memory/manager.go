@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -16,6 +17,8 @@ type Manager struct {
 	tokenizer  Tokenizer
 	relevance  *Relevance
 	summarizer Summarizer
+	journal    *JournalStore
+	graph      *GraphStore // 跨股票交叉引用
 	dataDir    string
 	saveCh     chan *StockMemory // 异步保存通道
 	closeCh    chan struct{}     // 关闭信号
@@ -29,6 +32,8 @@ func NewManager(dataDir string) *Manager {
 		storage:   NewFileStorage(dataDir),
 		tokenizer: tokenizer,
 		relevance: NewRelevance(tokenizer),
+		journal:   NewJournalStore(dataDir),
+		graph:     NewGraphStore(dataDir),
 		dataDir:   dataDir,
 		saveCh:    make(chan *StockMemory, 100), // 缓冲通道
 		closeCh:   make(chan struct{}),
@@ -130,6 +135,15 @@ func (m *Manager) BuildContext(mem *StockMemory, currentQuery string) string {
 		}
 	}
 
+	// 4. 其他股票讨论中与本股票相关的内容
+	if related := m.GetRelatedMemories(mem.StockCode, RelatedContextLimit); len(related) > 0 {
+		sb.WriteString("【关联股票记忆】\n")
+		for _, item := range related {
+			fmt.Fprintf(&sb, "- %s(%s): %s\n", item.StockName, item.StockCode, item.Snippet)
+		}
+		sb.WriteString("\n")
+	}
+
 	return sb.String()
 }
 
@@ -165,6 +179,11 @@ func (m *Manager) compress(ctx context.Context, mem *StockMemory) error {
 		return nil
 	}
 
+	// 压缩前备份磁盘上的记忆文件，摘要质量不佳时可回滚
+	if err := m.SnapshotMemory(mem.StockCode); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("snapshot memory error: %v\n", err)
+	}
+
 	toCompress := mem.RecentRounds[:len(mem.RecentRounds)-keepCount]
 	toKeep := mem.RecentRounds[len(mem.RecentRounds)-keepCount:]
 
@@ -184,9 +203,69 @@ func (m *Manager) compress(ctx context.Context, mem *StockMemory) error {
 	mem.Summary = m.mergeSummaries(mem.Summary, newSummary)
 	mem.RecentRounds = toKeep
 
+	// 摘要中提及的其他股票写入跨股票记忆图，只计入此前未计入的轮次
+	upTo := toCompress[len(toCompress)-1].Timestamp
+	if err := m.graph.Link(mem.StockCode, extractMentionedStocks(newSummary, mem.StockCode, m.knownStocks()), upTo); err != nil {
+		fmt.Printf("link cross-stock memory error: %v\n", err)
+	}
 	return nil
 }
 
+// knownStocks 已有记忆的股票，代码 -> 名称
+func (m *Manager) knownStocks() map[string]string {
+	codes, err := m.storage.List()
+	if err != nil {
+		return nil
+	}
+	known := make(map[string]string, len(codes))
+	for _, code := range codes {
+		if mem, err := m.storage.Load(code); err == nil {
+			known[code] = mem.StockName
+		}
+	}
+	return known
+}
+
+// GetRelatedMemories 按跨股票记忆图查找与该股票互相提及的股票，返回其记忆片段（最多 limit 条）
+// 关联股票没有记忆（或已删除）时跳过
+func (m *Manager) GetRelatedMemories(stockCode string, limit int) []RelatedMemoryItem {
+	refs, codes, err := m.graph.Related(stockCode)
+	if err != nil {
+		fmt.Printf("load cross-stock memory error: %v\n", err)
+		return nil
+	}
+	var name string
+	if self, err := m.storage.Load(stockCode); err == nil {
+		name = self.StockName
+	}
+
+	items := make([]RelatedMemoryItem, 0, min(limit, len(codes)))
+	for _, code := range codes {
+		if len(items) >= limit {
+			break
+		}
+		other, err := m.storage.Load(code)
+		if err != nil {
+			continue
+		}
+		text := other.Summary
+		if text == "" && len(other.RecentRounds) > 0 {
+			text = other.RecentRounds[len(other.RecentRounds)-1].Consensus
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		items = append(items, RelatedMemoryItem{
+			StockCode: code,
+			StockName: other.StockName,
+			Snippet:   mentionSnippet(text, stockCode, name),
+			Mentions:  refs[code].Count,
+			UpdatedAt: refs[code].UpdatedAt,
+		})
+	}
+	return items
+}
+
 // mergeSummaries 合并摘要
 func (m *Manager) mergeSummaries(old, new string) string {
 	if old == "" {
@@ -247,11 +326,71 @@ func (m *Manager) fallbackExtractKeyPoints(discussions []DiscussionInput) []stri
 	return points
 }
 
-// DeleteMemory 删除指定股票的记忆
+// RecordActions 从用户消息中提取实际操作并记入操作日志，返回新增的条目
+// 不含操作关键词或未设置 LLM 时直接跳过
+func (m *Manager) RecordActions(ctx context.Context, stockCode, content string) ([]ActionEntry, error) {
+	if m.summarizer == nil || !mayContainAction(content) {
+		return nil, nil
+	}
+	entries, err := m.summarizer.ExtractActions(ctx, content)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].StockCode = stockCode
+	}
+	if err := m.journal.Add(stockCode, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ActionJournal 获取股票的操作日志（按时间顺序）
+func (m *Manager) ActionJournal(stockCode string) ([]ActionEntry, error) {
+	return m.journal.List(stockCode)
+}
+
+// UpdateAction 修改操作日志条目
+func (m *Manager) UpdateAction(stockCode string, entry ActionEntry) error {
+	return m.journal.Update(stockCode, entry)
+}
+
+// DeleteAction 删除操作日志条目
+func (m *Manager) DeleteAction(stockCode, id string) error {
+	return m.journal.Delete(stockCode, id)
+}
+
+// BuildJournalContext 构建最近操作记录上下文，没有记录时返回空
+func (m *Manager) BuildJournalContext(stockCode string) string {
+	entries, err := m.journal.List(stockCode)
+	if err != nil {
+		return ""
+	}
+	return buildJournalContext(entries)
+}
+
+// DeleteMemory 删除指定股票的记忆（快照保留，可用于找回）
 func (m *Manager) DeleteMemory(stockCode string) error {
 	return m.storage.Delete(stockCode)
 }
 
+// SnapshotMemory 为股票记忆文件创建带时间戳的快照
+func (m *Manager) SnapshotMemory(stockCode string) error {
+	_, err := m.storage.Snapshot(stockCode)
+	return err
+}
+
+// ListSnapshots 列出股票的记忆快照（新的在前）
+func (m *Manager) ListSnapshots(stockCode string) ([]SnapshotInfo, error) {
+	return m.storage.ListSnapshots(stockCode)
+}
+
+// RestoreSnapshot 将股票记忆回滚到指定快照
+func (m *Manager) RestoreSnapshot(stockCode, snapshotID string) error {
+	_, err := m.storage.RestoreSnapshot(stockCode, snapshotID)
+	return err
+}
+
 // Close 释放资源
 func (m *Manager) Close() {
 	// 关闭异步保存协程
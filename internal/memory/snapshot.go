@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSnapshotsPerStock 每只股票保留的记忆快照数，超出时删除最旧的
+const MaxSnapshotsPerStock = 10
+
+// snapshotSummaryPreview 快照列表中摘要预览的最大字数
+const snapshotSummaryPreview = 80
+
+// SnapshotInfo 记忆快照信息
+type SnapshotInfo struct {
+	ID          string `json:"id"`          // 快照 ID（创建时间的纳秒时间戳）
+	StockCode   string `json:"stockCode"`   // 股票代码
+	CreatedAt   int64  `json:"createdAt"`   // 快照时间（毫秒）
+	Summary     string `json:"summary"`     // 摘要预览
+	TotalRounds int    `json:"totalRounds"` // 快照时的总讨论轮次
+	Size        int64  `json:"size"`        // 文件大小（字节）
+}
+
+// ErrSnapshotNotFound 快照不存在
+var ErrSnapshotNotFound = errors.New("记忆快照不存在")
+
+// snapshotDir 快照目录：memories/snapshots/{stockCode}
+func (s *FileStorage) snapshotDir(stockCode string) string {
+	return filepath.Join(s.dir, "snapshots", stockCode)
+}
+
+// validName 校验股票代码/快照 ID，防止路径穿越
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// Snapshot 把当前记忆文件复制为一个带时间戳的快照
+// 记忆文件不存在时返回 os.ErrNotExist
+func (s *FileStorage) Snapshot(stockCode string) (*SnapshotInfo, error) {
+	if !validName(stockCode) {
+		return nil, fmt.Errorf("无效的股票代码: %q", stockCode)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshotLocked(stockCode)
+}
+
+// snapshotLocked 创建快照，调用方需持有写锁
+func (s *FileStorage) snapshotLocked(stockCode string) (*SnapshotInfo, error) {
+	data, err := os.ReadFile(s.getPath(stockCode))
+	if err != nil {
+		return nil, err
+	}
+	dir := s.snapshotDir(stockCode)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	id := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := os.WriteFile(filepath.Join(dir, id+".json"), data, 0644); err != nil {
+		return nil, err
+	}
+	s.pruneSnapshots(dir)
+
+	info := snapshotInfo(stockCode, id, data)
+	return &info, nil
+}
+
+// pruneSnapshots 只保留最近 MaxSnapshotsPerStock 个快照
+func (s *FileStorage) pruneSnapshots(dir string) {
+	ids := listSnapshotIDs(dir)
+	for len(ids) > MaxSnapshotsPerStock {
+		os.Remove(filepath.Join(dir, ids[len(ids)-1]+".json"))
+		ids = ids[:len(ids)-1]
+	}
+}
+
+// listSnapshotIDs 列出目录下的快照 ID（新的在前）
+func listSnapshotIDs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+		id := strings.TrimSuffix(name, ".json")
+		if _, err := strconv.ParseInt(id, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	// 纳秒时间戳位数相同，按字符串逆序即按时间倒序
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids
+}
+
+// snapshotInfo 从快照内容解析列表信息
+func snapshotInfo(stockCode, id string, data []byte) SnapshotInfo {
+	info := SnapshotInfo{ID: id, StockCode: stockCode, Size: int64(len(data))}
+	if ns, err := strconv.ParseInt(id, 10, 64); err == nil {
+		info.CreatedAt = ns / int64(time.Millisecond)
+	}
+	var mem StockMemory
+	if json.Unmarshal(data, &mem) == nil {
+		info.TotalRounds = mem.TotalRounds
+		runes := []rune(mem.Summary)
+		if len(runes) > snapshotSummaryPreview {
+			info.Summary = string(runes[:snapshotSummaryPreview]) + "..."
+		} else {
+			info.Summary = mem.Summary
+		}
+	}
+	return info
+}
+
+// ListSnapshots 列出股票的记忆快照（新的在前）
+func (s *FileStorage) ListSnapshots(stockCode string) ([]SnapshotInfo, error) {
+	if !validName(stockCode) {
+		return nil, fmt.Errorf("无效的股票代码: %q", stockCode)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dir := s.snapshotDir(stockCode)
+	ids := listSnapshotIDs(dir)
+	result := make([]SnapshotInfo, 0, len(ids))
+	for _, id := range ids {
+		data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+		if err != nil {
+			continue
+		}
+		result = append(result, snapshotInfo(stockCode, id, data))
+	}
+	return result, nil
+}
+
+// RestoreSnapshot 用快照覆盖当前记忆文件并刷新缓存
+// 覆盖前会先为当前记忆创建快照，恢复错了还能再回滚
+func (s *FileStorage) RestoreSnapshot(stockCode, snapshotID string) (*StockMemory, error) {
+	if !validName(stockCode) || !validName(snapshotID) {
+		return nil, ErrSnapshotNotFound
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(s.snapshotDir(stockCode), snapshotID+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrSnapshotNotFound
+		}
+		return nil, err
+	}
+	var mem StockMemory
+	if err := json.Unmarshal(data, &mem); err != nil {
+		return nil, fmt.Errorf("快照已损坏: %w", err)
+	}
+	if mem.StockCode != stockCode {
+		return nil, fmt.Errorf("快照股票代码不匹配: %s", mem.StockCode)
+	}
+	if _, err := s.snapshotLocked(stockCode); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("备份当前记忆失败: %w", err)
+	}
+	if err := os.WriteFile(s.getPath(stockCode), data, 0644); err != nil {
+		return nil, err
+	}
+	s.cache[stockCode] = &mem
+	return &mem, nil
+}
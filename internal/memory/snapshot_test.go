@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestFileStorageSnapshots(t *testing.T) {
+	s := NewFileStorage(t.TempDir())
+
+	if _, err := s.Snapshot("sh600519"); !os.IsNotExist(err) {
+		t.Fatalf("snapshot without memory file: err = %v", err)
+	}
+
+	mem := NewStockMemory("sh600519", "贵州茅台")
+	mem.Summary = "估值合理，等待回调"
+	mem.TotalRounds = 5
+	if err := s.Save(mem); err != nil {
+		t.Fatal(err)
+	}
+	good, err := s.Snapshot("sh600519")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟一次糟糕的压缩
+	mem.Summary = "乱码"
+	mem.TotalRounds = 6
+	if err := s.Save(mem); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.RestoreSnapshot("sh600519", "../sh600519"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("path traversal: err = %v", err)
+	}
+	if _, err := s.RestoreSnapshot("sh600519", good.ID); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := s.Load("sh600519")
+	if err != nil || restored.Summary != "估值合理，等待回调" || restored.TotalRounds != 5 {
+		t.Fatalf("restored = %+v, err = %v", restored, err)
+	}
+
+	// 恢复前的状态也留有快照
+	list, err := s.ListSnapshots("sh600519")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("snapshots = %+v, err = %v", list, err)
+	}
+	if list[0].Summary != "乱码" || list[0].TotalRounds != 6 || list[1].ID != good.ID {
+		t.Errorf("snapshots not newest first: %+v", list)
+	}
+
+	for i := 0; i < MaxSnapshotsPerStock+3; i++ {
+		if _, err := s.Snapshot("sh600519"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if list, _ := s.ListSnapshots("sh600519"); len(list) != MaxSnapshotsPerStock {
+		t.Errorf("kept %d snapshots, want %d", len(list), MaxSnapshotsPerStock)
+	}
+	if codes, _ := s.List(); len(codes) != 1 || codes[0] != "sh600519" {
+		t.Errorf("List() = %v, snapshot dir should be ignored", codes)
+	}
+}
@@ -13,6 +13,9 @@ type Storage interface {
 	Save(mem *StockMemory) error
 	Delete(stockCode string) error
 	List() ([]string, error)
+	Snapshot(stockCode string) (*SnapshotInfo, error)
+	ListSnapshots(stockCode string) ([]SnapshotInfo, error)
+	RestoreSnapshot(stockCode, snapshotID string) (*StockMemory, error)
 }
 
 // FileStorage 文件存储（按股票隔离）
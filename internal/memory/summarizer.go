@@ -17,6 +17,7 @@ type Summarizer interface {
 	SummarizeRounds(ctx context.Context, rounds []RoundMemory) (string, error)
 	ExtractFacts(ctx context.Context, content, agentName string) ([]MemoryEntry, error)
 	ExtractKeyPoints(ctx context.Context, discussions []DiscussionInput) ([]string, error)
+	ExtractActions(ctx context.Context, content string) ([]ActionEntry, error)
 }
 
 // DiscussionInput 讨论输入（用于关键点提取）
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"slices"
+	"strings"
+)
+
 // AgentConfig Agent配置（从策略转换而来）
 type AgentConfig struct {
 	ID          string   `json:"id"`
@@ -12,4 +17,46 @@ type AgentConfig struct {
 	MCPServers  []string `json:"mcpServers"`
 	Enabled     bool     `json:"enabled"`
 	AIConfigID  string   `json:"aiConfigId"` // 可选，空则用默认AI
+
+	MaxTokensPerAgent int    `json:"maxTokensPerAgent,omitempty"` // 单次发言输出 token 上限，0 表示沿用 AI 配置
+	OutputLanguage    string `json:"outputLanguage,omitempty"`    // 回复语言代码（zh/zh-TW/en/ja），优先于指令与系统提示的语言，空则不限定
+	Tone              string `json:"tone,omitempty"`              // 回复语气风格，如「沉稳专业」，空则不限定
+}
+
+// outputLanguage 支持的专家回复语言
+type outputLanguage struct {
+	Code    string
+	Name    string   // 英文名，用于输出要求
+	Aliases []string // 归一化时接受的别名（小写）
+}
+
+var outputLanguages = []outputLanguage{
+	{Code: "zh", Name: "Simplified Chinese (简体中文)", Aliases: []string{"zh-cn", "zh-hans", "chinese", "simplified chinese", "中文", "简体中文", "汉语"}},
+	{Code: "zh-TW", Name: "Traditional Chinese (繁體中文)", Aliases: []string{"zh-tw", "zh-hk", "zh-hant", "traditional chinese", "繁体中文", "繁體中文"}},
+	{Code: "en", Name: "English", Aliases: []string{"en-us", "en-gb", "english", "英文", "英语"}},
+	{Code: "ja", Name: "Japanese (日本語)", Aliases: []string{"ja-jp", "japanese", "日本語", "日语", "日文"}},
+}
+
+// NormalizeOutputLanguage 将语言代码或常见名称归一化为支持的代码，空字符串表示不限定；未知语言返回 false
+func NormalizeOutputLanguage(lang string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(lang))
+	if key == "" {
+		return "", true
+	}
+	for _, l := range outputLanguages {
+		if key == strings.ToLower(l.Code) || slices.Contains(l.Aliases, key) {
+			return l.Code, true
+		}
+	}
+	return "", false
+}
+
+// OutputLanguageName 回复语言的英文名，未知代码原样返回
+func OutputLanguageName(code string) string {
+	for _, l := range outputLanguages {
+		if l.Code == code {
+			return l.Name
+		}
+	}
+	return code
 }
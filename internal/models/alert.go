@@ -0,0 +1,77 @@
+package models
+
+import "fmt"
+
+// AlertCondition 价格提醒触发条件
+type AlertCondition string
+
+const (
+	AlertAbove       AlertCondition = "above"                // 价格涨到阈值及以上
+	AlertBelow       AlertCondition = "below"                // 价格跌到阈值及以下
+	AlertChangeAbove AlertCondition = "change_percent_above" // 涨跌幅达到阈值（%）及以上
+	AlertVolumeSpike AlertCondition = "volume_spike"         // 放量：本轮成交量增量达到近期平均增量的阈值倍数
+)
+
+// ParseAlertCondition 解析提醒条件，兼容 price_above/price_below 写法，无效时返回 false
+func ParseAlertCondition(s string) (AlertCondition, bool) {
+	switch s {
+	case "above", "price_above":
+		return AlertAbove, true
+	case "below", "price_below":
+		return AlertBelow, true
+	case string(AlertChangeAbove):
+		return AlertChangeAbove, true
+	case string(AlertVolumeSpike):
+		return AlertVolumeSpike, true
+	}
+	return "", false
+}
+
+// PriceAlert 价格提醒
+type PriceAlert struct {
+	ID           string         `json:"id"`
+	Symbol       string         `json:"symbol"`
+	Name         string         `json:"name"`
+	Condition    AlertCondition `json:"condition"`
+	Threshold    float64        `json:"threshold"`
+	AIFollowUp   bool           `json:"aiFollowUp"`             // 触发后自动发起一次智能会议
+	Triggered    bool           `json:"triggered"`              // 已触发的提醒不再重复触发
+	TriggerPrice float64        `json:"triggerPrice"`           // 触发时的价格
+	TriggerValue float64        `json:"triggerValue,omitempty"` // 触发时的观测值（价格、涨跌幅或量比）
+	CreatedAt    int64          `json:"createdAt"`
+	TriggeredAt  int64          `json:"triggeredAt"`
+}
+
+// Match 判断行情是否满足触发条件，返回触发判断用的观测值（价格、涨跌幅或量比）
+// 价格为 0 视为无效行情（如停牌）
+func (a PriceAlert) Match(stock Stock, volumeRatio float64) (float64, bool) {
+	if stock.Price <= 0 {
+		return 0, false
+	}
+	switch a.Condition {
+	case AlertAbove:
+		return stock.Price, stock.Price >= a.Threshold
+	case AlertBelow:
+		return stock.Price, stock.Price <= a.Threshold
+	case AlertChangeAbove:
+		return stock.ChangePercent, stock.ChangePercent >= a.Threshold
+	case AlertVolumeSpike:
+		return volumeRatio, volumeRatio >= a.Threshold
+	}
+	return 0, false
+}
+
+// Describe 触发条件的中文描述，如"涨破 12.00"
+func (a PriceAlert) Describe() string {
+	switch a.Condition {
+	case AlertAbove:
+		return fmt.Sprintf("涨破 %.2f", a.Threshold)
+	case AlertBelow:
+		return fmt.Sprintf("跌破 %.2f", a.Threshold)
+	case AlertChangeAbove:
+		return fmt.Sprintf("涨幅达到 %.2f%%", a.Threshold)
+	case AlertVolumeSpike:
+		return fmt.Sprintf("放量至近期平均的 %.1f 倍", a.Threshold)
+	}
+	return string(a.Condition)
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+)
+
+// APIScope 本地 HTTP API 令牌的权限范围
+type APIScope string
+
+const (
+	APIScopeReadMarket   APIScope = "read_market"   // 查询行情
+	APIScopeReadSessions APIScope = "read_sessions" // 读取会话记录
+	APIScopeRunMeetings  APIScope = "run_meetings"  // 发起分析会议
+	APIScopeAdmin        APIScope = "admin"         // 管理令牌，包含全部权限
+)
+
+// APIScopes 全部权限范围，按展示顺序排列
+var APIScopes = []APIScope{APIScopeReadMarket, APIScopeReadSessions, APIScopeRunMeetings, APIScopeAdmin}
+
+// ValidAPIScope 是否为已知的权限范围
+func ValidAPIScope(scope APIScope) bool {
+	return slices.Contains(APIScopes, scope)
+}
+
+// APIToken 本地 HTTP API 令牌记录，只保存令牌的哈希，明文仅在创建时返回一次
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`      // 用途说明，如"看板脚本"
+	TokenHash  string     `json:"tokenHash"` // 令牌 SHA-256（十六进制）
+	Scopes     []APIScope `json:"scopes"`
+	CreatedAt  int64      `json:"createdAt"`
+	LastUsedAt int64      `json:"lastUsedAt,omitempty"` // 最近一次鉴权通过的时间（毫秒），用于审计
+}
+
+// Allows 令牌是否具备 scope 权限，admin 包含全部权限，scope 为空表示无需特定权限
+func (t *APIToken) Allows(scope APIScope) bool {
+	return scope == "" || slices.Contains(t.Scopes, scope) || slices.Contains(t.Scopes, APIScopeAdmin)
+}
+
+// HashAPIToken 计算令牌明文的哈希
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
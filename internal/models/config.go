@@ -4,10 +4,16 @@ package models
 type AIProvider string
 
 const (
-	AIProviderOpenAI    AIProvider = "openai"
-	AIProviderGemini    AIProvider = "gemini"
-	AIProviderVertexAI  AIProvider = "vertexai"
-	AIProviderAnthropic AIProvider = "anthropic"
+	AIProviderOpenAI         AIProvider = "openai"
+	AIProviderGemini         AIProvider = "gemini"
+	AIProviderVertexAI       AIProvider = "vertexai"
+	AIProviderVertexAIGarden AIProvider = "vertexai_garden" // Vertex AI Model Garden 上的第三方模型（Llama、Mistral 等）
+	AIProviderAnthropic      AIProvider = "anthropic"
+	AIProviderOllama         AIProvider = "ollama"
+	AIProviderDeepSeek       AIProvider = "deepseek"
+	AIProviderQwen           AIProvider = "qwen"
+	AIProviderBedrock        AIProvider = "bedrock" // AWS Bedrock 上的 Claude 模型
+	AIProviderMistral        AIProvider = "mistral"
 )
 
 // AIConfig AI服务配置
@@ -20,16 +26,41 @@ type AIConfig struct {
 	ModelName   string     `json:"modelName"`
 	MaxTokens   int        `json:"maxTokens"`
 	Temperature float64    `json:"temperature"`
-	Timeout     int        `json:"timeout"`
+	Timeout     int        `json:"timeout"` // 单次模型请求超时秒数（0 使用默认 120s；流式输出为两个数据块之间的空闲超时）
 	IsDefault   bool       `json:"isDefault"`
+	// 429/5xx 等临时错误的最大重试次数（0 使用默认 3 次，负数关闭重试）与指数退避基础延迟毫秒数（0 使用默认 1000）
+	RetryMaxAttempts int `json:"retryMaxAttempts,omitempty"`
+	RetryBaseDelayMs int `json:"retryBaseDelayMs,omitempty"`
 	// OpenAI Responses API 开关
 	UseResponses bool `json:"useResponses"`
 	// 不支持 system role（自动检测，用户不可见）
 	NoSystemRole bool `json:"noSystemRole"`
-	// Vertex AI 专用字段
+	// 每轮回答前强制先调用的工具名（仅 Responses API 生效），如 get_research_report
+	ForcedToolName string `json:"forcedToolName,omitempty"`
+	// Azure OpenAI 专用字段（Provider 为 openai 时生效）
+	IsAzure        bool   `json:"isAzure"`
+	APIVersion     string `json:"apiVersion"`
+	DeploymentName string `json:"deploymentName"`
+	// Anthropic 扩展思考预算 token（0 为关闭，最小 1024）
+	ThinkingBudget int `json:"thinkingBudget,omitempty"`
+	// Anthropic 提示缓存：为 system 与工具定义打缓存断点
+	EnablePromptCache bool `json:"enablePromptCache,omitempty"`
+	// 实测的能力支持情况（能力名 -> 是否支持），由连通性探测与请求 400 错误学习，覆盖 Provider 默认值
+	Capabilities map[string]bool `json:"capabilities,omitempty"`
+	// 千 token 单价（用于会议费用估算，币种由用户自定）
+	InputPricePer1K  float64 `json:"inputPricePer1K,omitempty"`
+	OutputPricePer1K float64 `json:"outputPricePer1K,omitempty"`
+	// AWS Bedrock 专用字段（Provider 为 bedrock 时生效，BaseURL 非空时作为自定义端点）
+	AWSRegion          string `json:"awsRegion,omitempty"`
+	AWSAccessKeyID     string `json:"awsAccessKeyId,omitempty"`
+	AWSSecretAccessKey string `json:"awsSecretAccessKey,omitempty"`
+	AWSSessionToken    string `json:"awsSessionToken,omitempty"`
+	// Vertex AI 专用字段（CredentialsJSON 仅 Vertex AI 使用，Qwen 等 API Key 鉴权的 Provider 忽略）
 	Project         string `json:"project"`
 	Location        string `json:"location"`
 	CredentialsJSON string `json:"credentialsJson"`
+	// Vertex AI Model Garden 模型发布方（如 meta、mistralai），Provider 为 vertexai_garden 时生效
+	Publisher string `json:"publisher,omitempty"`
 }
 
 // MCPTransportType MCP传输类型
@@ -51,24 +82,128 @@ type MCPServerConfig struct {
 	Args          []string         `json:"args"`          // 命令行参数
 	ToolFilter    []string         `json:"toolFilter"`    // 工具过滤列表（空则全部）
 	Enabled       bool             `json:"enabled"`       // 是否启用
+
+	StaleToleranceSeconds int `json:"staleToleranceSeconds,omitempty"` // 连接失败后继续使用旧 toolset 的秒数（0 为默认 300）
+
+	LongRunningTools          []string `json:"longRunningTools,omitempty"`          // 长耗时工具名称，调用时延长超时并转发进度
+	LongRunningTimeoutSeconds int      `json:"longRunningTimeoutSeconds,omitempty"` // 长耗时工具单次调用超时秒数（0 为默认 600）
 }
 
 // AppConfig 应用配置
 type AppConfig struct {
-	Theme           string            `json:"theme"`           // 主题色: military, ocean, purple, orange, dark
-	CandleColorMode string            `json:"candleColorMode"` // 涨跌颜色模式: red-up(红涨绿跌) / green-up(绿涨红跌)
-	AIConfigs       []AIConfig        `json:"aiConfigs"`
-	DefaultAIID     string            `json:"defaultAiId"`
-	StrategyAIID    string            `json:"strategyAiId"`  // 策略生成用AI
-	ModeratorAIID   string            `json:"moderatorAiId"` // 意图分析(小韭菜)用AI
-	MCPServers      []MCPServerConfig `json:"mcpServers"`    // MCP服务器配置列表
-	Memory          MemoryConfig      `json:"memory"`        // 记忆管理配置
-	Proxy           ProxyConfig       `json:"proxy"`         // 代理配置
-	Layout          LayoutConfig      `json:"layout"`        // 界面布局配置
-	OpenClaw        OpenClawConfig    `json:"openClaw"`      // OpenClaw 服务配置
-	Indicators      IndicatorConfig   `json:"indicators"`    // 技术指标配置
+	Theme             string            `json:"theme"`           // 主题色: military, ocean, purple, orange, dark
+	CandleColorMode   string            `json:"candleColorMode"` // 涨跌颜色模式: red-up(红涨绿跌) / green-up(绿涨红跌)
+	AIConfigs         []AIConfig        `json:"aiConfigs"`
+	DefaultAIID       string            `json:"defaultAiId"`
+	StrategyAIID      string            `json:"strategyAiId"`      // 策略生成用AI
+	ModeratorAIID     string            `json:"moderatorAiId"`     // 意图分析(小韭菜)用AI
+	ResearchAIID      string            `json:"researchAiId"`      // 深度研究员用AI
+	MCPServers        []MCPServerConfig `json:"mcpServers"`        // MCP服务器配置列表
+	Memory            MemoryConfig      `json:"memory"`            // 记忆管理配置
+	Proxy             ProxyConfig       `json:"proxy"`             // 代理配置
+	Layout            LayoutConfig      `json:"layout"`            // 界面布局配置
+	OpenClaw          OpenClawConfig    `json:"openClaw"`          // OpenClaw 服务配置
+	APITokens         []APIToken        `json:"apiTokens"`         // 本地 HTTP API 令牌（按权限范围授权）
+	Indicators        IndicatorConfig   `json:"indicators"`        // 技术指标配置
+	ThinkingPersist   ThinkingPersist   `json:"thinkingPersist"`   // 思考内容持久化策略
+	MeetingVisibility MeetingVisibility `json:"meetingVisibility"` // 智能会议专家间发言可见性
+	ModelWarmup       ModelWarmup       `json:"modelWarmup"`       // 会议前模型连接预热模式
+	DisableFactCheck  bool              `json:"disableFactCheck"`  // 关闭专家发言行情数字核对
+	NewsSentiment     NewsSentiment     `json:"newsSentiment"`     // 快讯词典情绪打分配置
+	MeetingBudget     MeetingBudget     `json:"meetingBudget"`     // 智能会议发言预算
+	Reproducibility   Reproducibility   `json:"reproducibility"`   // 会议复现模式参数
+
+	AutoContinueTruncated    bool              `json:"autoContinueTruncated"`    // 专家回复因长度限制被截断时自动续写一次
+	MeetingSchedules         []MeetingSchedule `json:"meetingSchedules"`         // 交易日定时会议（盘前/盘后简报）
+	GlobalSystemPromptPrefix string            `json:"globalSystemPromptPrefix"` // 注入每位专家系统指令开头的全局前缀（如合规声明），空则不注入
+	GlobalSystemPromptSuffix string            `json:"globalSystemPromptSuffix"` // 追加在每位专家系统指令末尾的全局后缀，空则不追加
+	AnnouncementMaxChars     int               `json:"announcementMaxChars"`     // get_announcement_content 返回正文的字数上限，0 使用默认值
+	ToolArgGuard             ToolArgGuard      `json:"toolArgGuard"`             // 外部工具调用参数的持仓敏感信息过滤
+	Search                   SearchConfig      `json:"search"`                   // 内置 web_search 工具的搜索服务
+}
+
+// SearchConfig 内置 web_search 工具的搜索服务配置
+type SearchConfig struct {
+	Provider           SearchProvider `json:"provider"` // 搜索服务，空表示未启用
+	APIKey             string         `json:"apiKey"`
+	MaxResults         int            `json:"maxResults"`         // 默认返回条数，0 使用默认值
+	MaxCallsPerMeeting int            `json:"maxCallsPerMeeting"` // 每场会议最多调用次数，0 使用默认值
+}
+
+// SearchProvider 网页搜索服务
+type SearchProvider string
+
+const (
+	SearchProviderTavily SearchProvider = "tavily"
+	SearchProviderSerper SearchProvider = "serper" // Google 搜索结果
+	SearchProviderBing   SearchProvider = "bing"   // Bing Web Search API
+)
+
+// ToolArgGuard 外部工具（MCP）调用参数的敏感信息过滤
+// 检查成本价、持仓数量等字段，以及与当前持仓数字相同的数值和类似账号的长数字；内置工具不受影响
+type ToolArgGuard struct {
+	Policy   ToolArgPolicy `json:"policy"`   // 命中时的处理策略，空为 redact
+	Patterns []string      `json:"patterns"` // 追加的敏感字段名正则（不区分大小写），与内置规则同时生效
+}
+
+// ToolArgPolicy 外部工具参数命中敏感信息时的处理策略
+type ToolArgPolicy string
+
+const (
+	ToolArgPolicyRedact ToolArgPolicy = "redact" // 字符串中的敏感数字替换为占位符、敏感数值字段去掉后继续调用
+	ToolArgPolicyBlock  ToolArgPolicy = "block"  // 拒绝调用，并告知模型原因
+	ToolArgPolicyOff    ToolArgPolicy = "off"    // 不检查
+)
+
+// MeetingBudget 智能会议发言预算，另含深度研究任务的工具与费用上限
+// 发言次数与轮数超出上限时跳过剩余专家或轮次，直接由主持人总结
+type MeetingBudget struct {
+	MaxAgentTurns int `json:"maxAgentTurns"` // 每场最多专家发言次数，0 表示不限（由主持人选择决定）
+	MaxRounds     int `json:"maxRounds"`     // 每场专家发言的最大轮数（首轮分析、辩论反驳轮），0 为默认 3 轮；策略配置了轮数时以策略为准
+
+	ResearchMaxToolCalls int     `json:"researchMaxToolCalls"` // 深度研究任务的工具调用上限，0 使用默认值
+	ResearchMaxCost      float64 `json:"researchMaxCost"`      // 深度研究任务的费用上限（按 AI 配置单价估算），0 表示不限
+}
+
+// Reproducibility 会议复现模式参数，会议请求开启复现模式时生效
+type Reproducibility struct {
+	Temperature float64 `json:"temperature"` // 所有发言者统一使用的温度
+	Seed        int32   `json:"seed"`        // 固定随机种子，0 表示每场会议随机生成（记录在会议运行记录中）
+}
+
+// NewsSentiment 快讯词典情绪打分的自定义词条（在内置金融词典基础上追加）
+type NewsSentiment struct {
+	PositiveTerms []string `json:"positiveTerms"` // 利好词
+	NegativeTerms []string `json:"negativeTerms"` // 利空词
 }
 
+// ThinkingPersist 模型思考内容持久化策略
+type ThinkingPersist string
+
+const (
+	ThinkingPersistFull    ThinkingPersist = "full"    // 完整保存
+	ThinkingPersistSummary ThinkingPersist = "summary" // 截断保存
+	ThinkingPersistDiscard ThinkingPersist = "discard" // 不保存
+)
+
+// MeetingVisibility 智能会议中专家之间的发言可见性
+type MeetingVisibility string
+
+const (
+	MeetingVisibilityIndependent MeetingVisibility = "independent" // 互不可见，独立观点避免锚定
+	MeetingVisibilitySequential  MeetingVisibility = "sequential"  // 后发言者可见前面的发言
+	MeetingVisibilityDebate      MeetingVisibility = "debate"      // 可见且需点名赞同或反驳
+)
+
+// ModelWarmup 模型连接预热模式
+type ModelWarmup string
+
+const (
+	ModelWarmupOff     ModelWarmup = "off"     // 不预热
+	ModelWarmupConnect ModelWarmup = "connect" // 仅建立连接（HEAD 请求，不消耗 token）
+	ModelWarmupPing    ModelWarmup = "ping"    // 发送 1 token 请求，连同鉴权一起预热
+)
+
 // ProxyMode 代理模式
 type ProxyMode string
 
@@ -80,8 +215,10 @@ const (
 
 // ProxyConfig 代理配置
 type ProxyConfig struct {
-	Mode      ProxyMode `json:"mode"`
-	CustomURL string    `json:"customUrl"` // 自定义代理地址
+	Mode          ProxyMode `json:"mode"`
+	CustomURL     string    `json:"customUrl"`               // 自定义代理地址
+	ProxyUsername string    `json:"proxyUsername,omitempty"` // 代理 Basic 认证用户名
+	ProxyPassword string    `json:"proxyPassword,omitempty"` // 代理 Basic 认证密码
 }
 
 // MemoryConfig 记忆管理配置
@@ -0,0 +1,11 @@
+package models
+
+// MeetingSchedule 定时会议（如盘前/盘后简报），交易日到点后对选定股票自动开会
+type MeetingSchedule struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Enabled    bool     `json:"enabled"`
+	Time       string   `json:"time"`       // 触发时间 HH:MM（北京时间），如 09:15、15:10
+	StockCodes []string `json:"stockCodes"` // 参会股票代码，为空表示全部自选股
+	Prompt     string   `json:"prompt"`     // 会议问题模板，支持 {{name}}、{{code}}、{{date}} 占位符
+}
@@ -15,20 +15,85 @@ type StockSession struct {
 	Position  *StockPosition `json:"position"`  // 持仓信息
 	CreatedAt int64          `json:"createdAt"`
 	UpdatedAt int64          `json:"updatedAt"`
+
+	TokenUsage map[string]*TokenUsage `json:"tokenUsage,omitempty"` // 各专家 token 累计用量，key: agentID
+
+	AutoTranslate string `json:"autoTranslate,omitempty"` // 专家回复自动翻译的目标语言（zh/en），空为关闭
+
+	MeetingRuns []MeetingRun `json:"meetingRuns,omitempty"` // 最近几场会议实际生效的模型参数
+
+	Attachments []SessionAttachment `json:"attachments,omitempty"` // 深度研究报告等长文附件
+}
+
+// SessionAttachment 会话附件，正文不进入讨论上下文，由消息的 AttachmentID 引用
+type SessionAttachment struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"` // research=深度研究报告
+	Title     string `json:"title"`
+	Content   string `json:"content"` // Markdown 正文
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// MeetingRun 一场会议的运行记录，保存各发言者实际生效的模型参数，便于对比两次运行的差异
+type MeetingRun struct {
+	ID           string            `json:"id"`   // 会议场次 ID，与会议摘要的 runId 一致
+	Mode         string            `json:"mode"` // smart/direct/debate
+	Query        string            `json:"query"`
+	StartedAt    int64             `json:"startedAt"`
+	FinishedAt   int64             `json:"finishedAt"`
+	Reproducible bool              `json:"reproducible"`   // 是否开启复现模式
+	Seed         int32             `json:"seed,omitempty"` // 复现模式使用的随机种子，传回会议请求即可按同一种子重跑
+	Agents       []MeetingRunAgent `json:"agents"`         // 按首次发言顺序排列，含小韭菜
+}
+
+// MeetingRunAgent 单个发言者实际使用的模型参数
+type MeetingRunAgent struct {
+	AgentID       string     `json:"agentId"`
+	AgentName     string     `json:"agentName"`
+	AIConfigID    string     `json:"aiConfigId"`
+	Provider      AIProvider `json:"provider"`
+	ModelName     string     `json:"modelName"`
+	Temperature   float64    `json:"temperature"`
+	MaxTokens     int        `json:"maxTokens,omitempty"` // 生效的输出上限（AI 配置与专家预算取小），0 为不限
+	SeedSupported bool       `json:"seedSupported"`       // 复现模式下种子是否实际发送给模型，不支持的 Provider 记为 false
+}
+
+// TokenUsage 专家在会话内的 token 累计用量
+type TokenUsage struct {
+	AgentID      string `json:"agentId"`
+	AgentName    string `json:"agentName"`
+	InputTokens  int64  `json:"inputTokens"`
+	OutputTokens int64  `json:"outputTokens"`
+	TotalTokens  int64  `json:"totalTokens"`
+	Calls        int    `json:"calls"` // 累计发言次数
 }
 
 // ChatMessage 聊天消息
 type ChatMessage struct {
-	ID        string   `json:"id"`
-	AgentID   string   `json:"agentId"`
-	AgentName string   `json:"agentName"`
-	Role      string   `json:"role"`
-	Content   string   `json:"content"`
-	Timestamp int64    `json:"timestamp"`
-	ReplyTo   string   `json:"replyTo,omitempty"`   // 引用的消息ID
-	Mentions  []string `json:"mentions,omitempty"`  // @的成员ID列表
-	Round     int      `json:"round,omitempty"`     // 讨论轮次
-	MsgType   string   `json:"msgType,omitempty"`   // 消息类型: opening/opinion/summary
+	ID          string   `json:"id"`
+	AgentID     string   `json:"agentId"`
+	AgentName   string   `json:"agentName"`
+	Role        string   `json:"role"`
+	Content     string   `json:"content"`
+	Timestamp   int64    `json:"timestamp"`
+	ReplyTo     string   `json:"replyTo,omitempty"`     // 引用的消息ID
+	Mentions    []string `json:"mentions,omitempty"`    // @的成员ID列表
+	Round       int      `json:"round,omitempty"`       // 讨论轮次
+	MsgType     string   `json:"msgType,omitempty"`     // 消息类型: opening/opinion/rebuttal/summary/research
 	Error       string   `json:"error,omitempty"`       // 失败时的错误信息
-	MeetingMode string   `json:"meetingMode,omitempty"` // smart=串行, direct=独立
+	MeetingMode string   `json:"meetingMode,omitempty"` // smart=串行, direct=独立, debate=辩论轮
+	RoundLabel  string   `json:"roundLabel,omitempty"`  // 轮次标签（辩论模式）
+	Rebuts      string   `json:"rebuts,omitempty"`      // 反驳的专家 ID（rebuttal）
+	Reasoning   string   `json:"reasoning,omitempty"`   // 模型思考内容（按策略保存，不回注上下文）
+	ToolCalls   []string `json:"toolCalls,omitempty"`   // 本次发言调用的工具（名称与参数摘要）
+	ToolResults []string `json:"toolResults,omitempty"` // 与 ToolCalls 一一对应的工具结果 JSON（截断）
+
+	FinishReason string `json:"finishReason,omitempty"` // 结束原因，MAX_TOKENS 表示回复被截断
+
+	Original     string `json:"original,omitempty"`     // 自动翻译前的原文，Content 为译文
+	TranslatedTo string `json:"translatedTo,omitempty"` // 译文语言
+
+	AttachmentID string `json:"attachmentId,omitempty"` // 关联的会话附件（如深度研究报告）
+
+	AsOf string `json:"asOf,omitempty"` // 发言所依据行情数据的截至时间（北京时间）
 }
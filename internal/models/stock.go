@@ -15,6 +15,50 @@ type Stock struct {
 	High          float64 `json:"high"`
 	Low           float64 `json:"low"`
 	PreClose      float64 `json:"preClose"`
+	QuoteTime     string  `json:"quoteTime,omitempty"` // 行情时间，如 2025-03-07 15:00:00
+
+	Type InstrumentType `json:"type,omitempty"` // 标的类型：stock/index
+
+	GroupID   string `json:"groupId,omitempty"`   // 自选股所属分组
+	SortOrder int    `json:"sortOrder,omitempty"` // 分组内排序，从 0 开始
+
+	DataSuspect   bool       `json:"dataSuspect,omitempty"`   // 行情未通过合理性校验（现价为 0、涨跌幅越界等）
+	SuspectReason string     `json:"suspectReason,omitempty"` // 校验不通过的原因
+	QuoteState    QuoteState `json:"quoteState,omitempty"`    // 盘前未开盘或停牌，现价取昨收
+}
+
+// QuoteState 无成交时的行情状态，数据源此时现价为 0，以昨收作为参考价
+type QuoteState string
+
+const (
+	QuoteStatePreOpen QuoteState = "pre_open" // 盘前尚未开盘
+	QuoteStateHalted  QuoteState = "halted"   // 停牌
+)
+
+// Label 状态的中文说明
+func (s QuoteState) Label() string {
+	switch s {
+	case QuoteStatePreOpen:
+		return "尚未开盘"
+	case QuoteStateHalted:
+		return "停牌"
+	}
+	return ""
+}
+
+// 自选股默认分组，旧版无分组的自选股加载时归入此分组
+const (
+	DefaultWatchlistGroupID   = "default"
+	DefaultWatchlistGroupName = "默认分组"
+)
+
+// WatchlistGroup 自选股分组
+// 一只自选股可属于多个分组，Stock.GroupID 为其在列表中展示所在的主分组，始终包含在 Symbols 中
+type WatchlistGroup struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	SortOrder int      `json:"sortOrder"`
+	Symbols   []string `json:"symbols"` // 分组内的自选股代码，旧版分组文件没有该字段，加载时按主分组补齐
 }
 
 // KLineData K线数据
@@ -58,6 +102,119 @@ type MarketIndex struct {
 	Amount        float64 `json:"amount"`        // 成交额(万元)
 }
 
+// DataFreshness 会议所用行情数据的时效，非盘中时专家回复需声明数据截至时间
+type DataFreshness struct {
+	AsOf         string `json:"asOf"`                 // 数据截至时间，如 2025-03-07 15:00
+	QuoteTime    string `json:"quoteTime,omitempty"`  // 行情时间
+	KLineDate    string `json:"klineDate,omitempty"`  // 日K最后一根的日期
+	MarketStatus string `json:"marketStatus"`         // 市场状态，如 交易中、已收盘、周末休市
+	Live         bool   `json:"live"`                 // 是否为盘中实时数据
+	Disclaimer   string `json:"disclaimer,omitempty"` // 非实时数据时回复首行的声明，如 数据截至 3月7日收盘
+}
+
+// IndexOverview 指数概览：成分涨跌家数与行业板块表现
+type IndexOverview struct {
+	Code    string              `json:"code"`    // 指数代码，如 sh000001
+	Name    string              `json:"name"`    // 指数名称
+	Up      int                 `json:"up"`      // 上涨家数
+	Down    int                 `json:"down"`    // 下跌家数
+	Flat    int                 `json:"flat"`    // 平盘家数
+	Leading []SectorPerformance `json:"leading"` // 领涨板块
+	Lagging []SectorPerformance `json:"lagging"` // 领跌板块
+}
+
+// 板块类型
+const (
+	SectorIndustry = "industry" // 行业板块
+	SectorConcept  = "concept"  // 概念板块
+)
+
+// SectorPerformance 板块表现
+type SectorPerformance struct {
+	Code          string  `json:"code,omitempty"`          // 板块代码，如 BK0477
+	Type          string  `json:"type,omitempty"`          // 板块类型：industry/concept
+	Name          string  `json:"name"`                    // 板块名称
+	ChangePercent float64 `json:"changePercent"`           // 涨跌幅(%)
+	Rank          int     `json:"rank,omitempty"`          // 当日涨幅在同类板块中的排名
+	LeaderName    string  `json:"leaderName"`              // 领涨股名称
+	LeaderChange  float64 `json:"leaderChange"`            // 领涨股涨跌幅(%)
+	NetInflow     float64 `json:"netInflow,omitempty"`     // 主力净流入(元)
+	Change5D      float64 `json:"change5d,omitempty"`      // 5日涨跌幅(%)
+	RelStrength5D float64 `json:"relStrength5d,omitempty"` // 5日相对强弱：5日涨跌幅减去基准指数5日涨跌幅(百分点)
+}
+
+// SectorOverview 当日行业与概念板块涨跌排行
+type SectorOverview struct {
+	Benchmark         string              `json:"benchmark,omitempty"` // 相对强弱基准指数，获取失败时为空
+	BenchmarkChange5D float64             `json:"benchmarkChange5d"`   // 基准指数5日涨跌幅(%)
+	IndustryCount     int                 `json:"industryCount"`       // 行业板块总数
+	ConceptCount      int                 `json:"conceptCount"`        // 概念板块总数
+	TopIndustries     []SectorPerformance `json:"topIndustries"`       // 领涨行业
+	BottomIndustries  []SectorPerformance `json:"bottomIndustries"`    // 领跌行业
+	TopConcepts       []SectorPerformance `json:"topConcepts"`         // 领涨概念
+	BottomConcepts    []SectorPerformance `json:"bottomConcepts"`      // 领跌概念
+}
+
+// StockSectors 个股所属的行业与概念板块及其当日表现
+type StockSectors struct {
+	Code              string              `json:"code"`
+	Benchmark         string              `json:"benchmark,omitempty"`
+	BenchmarkChange5D float64             `json:"benchmarkChange5d"`
+	IndustryCount     int                 `json:"industryCount"`
+	ConceptCount      int                 `json:"conceptCount"`
+	Industries        []SectorPerformance `json:"industries"` // 所属行业
+	Concepts          []SectorPerformance `json:"concepts"`   // 所属概念，按当日涨幅排序
+}
+
+// LimitUpStock 涨停股
+type LimitUpStock struct {
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Price         float64 `json:"price"`
+	ChangePercent float64 `json:"changePercent"`
+	Amount        float64 `json:"amount"`        // 成交额(元)
+	SealAmount    float64 `json:"sealAmount"`    // 封单额(元)
+	Boards        int     `json:"boards"`        // 连板数，首板为 1
+	FirstSealTime string  `json:"firstSealTime"` // 首次封板时间 HH:MM:SS
+	LastSealTime  string  `json:"lastSealTime"`  // 最后封板时间
+	BreakCount    int     `json:"breakCount"`    // 炸板次数
+	Industry      string  `json:"industry"`
+}
+
+// LimitDownStock 跌停股
+type LimitDownStock struct {
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Price         float64 `json:"price"`
+	ChangePercent float64 `json:"changePercent"`
+	Amount        float64 `json:"amount"`       // 成交额(元)
+	SealAmount    float64 `json:"sealAmount"`   // 封单额(元)
+	Days          int     `json:"days"`         // 连续跌停天数
+	LastSealTime  string  `json:"lastSealTime"` // 最后封板时间
+	Industry      string  `json:"industry"`
+}
+
+// LimitBoardTier 连板梯队：某一连板高度的家数
+type LimitBoardTier struct {
+	Boards int `json:"boards"`
+	Count  int `json:"count"`
+}
+
+// LimitBoardStats 涨跌停板统计：涨停家数、连板高度、炸板率
+type LimitBoardStats struct {
+	Date           string           `json:"date"` // 交易日 YYYY-MM-DD
+	LimitUpCount   int              `json:"limitUpCount"`
+	LimitDownCount int              `json:"limitDownCount"`
+	BrokenCount    int              `json:"brokenCount"` // 曾涨停但收盘未封住的家数
+	BrokenRatio    float64          `json:"brokenRatio"` // 炸板率(%) = 炸板 / (涨停 + 炸板)
+	MaxBoards      int              `json:"maxBoards"`   // 连板高度
+	Ladder         []LimitBoardTier `json:"ladder"`      // 连板梯队，按高度降序
+	LimitUp        []LimitUpStock   `json:"limitUp"`     // 按连板数降序、首次封板时间升序
+	LimitDown      []LimitDownStock `json:"limitDown"`   // 按封单额降序
+	Final          bool             `json:"final"`       // 该交易日已收盘，数据不再变化
+	UpdatedAt      int64            `json:"updatedAt"`   // 获取时间(毫秒)
+}
+
 // LongHuBangItem 龙虎榜单条数据
 type LongHuBangItem struct {
 	TradeDate     string  `json:"tradeDate"`     // 交易日期
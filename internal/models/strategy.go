@@ -12,6 +12,10 @@ type StrategyAgent struct {
 	MCPServers  []string `json:"mcpServers"`
 	Enabled     bool     `json:"enabled"`
 	AIConfigID  string   `json:"aiConfigId"` // 可选，空则用默认AI
+
+	MaxTokensPerAgent int    `json:"maxTokensPerAgent,omitempty"` // 单次发言输出 token 上限，0 表示沿用 AI 配置
+	OutputLanguage    string `json:"outputLanguage,omitempty"`    // 回复语言代码（zh/zh-TW/en/ja），优先于指令与系统提示的语言，空则不限定
+	Tone              string `json:"tone,omitempty"`              // 回复语气风格，如「沉稳专业」，空则不限定
 }
 
 // Strategy 策略配置
@@ -22,6 +26,10 @@ type Strategy struct {
 	Color       string          `json:"color"`
 	Agents      []StrategyAgent `json:"agents"` // 策略专属的专家配置
 
+	ParallelRounds      bool `json:"parallelRounds"`      // 智能会议首轮专家并行发言（互不参考，速度更快）
+	MaxRounds           int  `json:"maxRounds"`           // 智能会议专家发言的最大轮数（首轮分析、辩论反驳轮），0 为默认 3 轮
+	AgentTimeoutSeconds int  `json:"agentTimeoutSeconds"` // 单个专家发言超时（秒），0 为默认
+
 	IsBuiltin  bool   `json:"isBuiltin"`
 	Source     string `json:"source"`     // builtin/user/ai
 	SourceMeta string `json:"sourceMeta"` // AI生成时的原始prompt
@@ -32,4 +40,5 @@ type Strategy struct {
 type StrategyStore struct {
 	ActiveID   string     `json:"activeId"`
 	Strategies []Strategy `json:"strategies"`
+	Revision   int64      `json:"revision"` // 每次写入递增，用于检测并发修改
 }
@@ -0,0 +1,47 @@
+package models
+
+import "strings"
+
+// InstrumentType 标的类型
+type InstrumentType string
+
+const (
+	InstrumentStock InstrumentType = "stock" // 个股
+	InstrumentIndex InstrumentType = "index" // 指数
+)
+
+// NormalizeSymbol 规范化证券代码为 sh600519 形式
+// 兼容大小写、新浪简化行情前缀 s_ 以及 600519.SH 后缀写法
+func NormalizeSymbol(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.TrimPrefix(code, "s_")
+	if i := strings.LastIndexByte(code, '.'); i > 0 {
+		switch suffix := code[i+1:]; suffix {
+		case "sh", "sz", "bj":
+			code = suffix + code[:i]
+		}
+	}
+	return code
+}
+
+// ClassifySymbol 根据代码识别标的类型
+// 上证 000xxx、深证 399xxx、北证 899xxx 为指数（sz000xxx 为深市个股，需区分交易所）
+func ClassifySymbol(code string) InstrumentType {
+	code = NormalizeSymbol(code)
+	if len(code) != 8 {
+		return InstrumentStock
+	}
+	market, num := code[:2], code[2:]
+	switch {
+	case market == "sh" && strings.HasPrefix(num, "000"),
+		market == "sz" && strings.HasPrefix(num, "399"),
+		market == "bj" && strings.HasPrefix(num, "899"):
+		return InstrumentIndex
+	}
+	return InstrumentStock
+}
+
+// IsIndexSymbol 判断是否为指数代码
+func IsIndexSymbol(code string) bool {
+	return ClassifySymbol(code) == InstrumentIndex
+}
@@ -0,0 +1,24 @@
+package models
+
+import "testing"
+
+func TestClassifySymbol(t *testing.T) {
+	tests := []struct {
+		code string
+		want InstrumentType
+	}{
+		{"sh000001", InstrumentIndex},
+		{"s_sh000001", InstrumentIndex},
+		{"SZ399006", InstrumentIndex},
+		{"000300.SH", InstrumentIndex},
+		{"bj899050", InstrumentIndex},
+		{"sz000001", InstrumentStock}, // 平安银行
+		{"sh600519", InstrumentStock},
+		{"000001", InstrumentStock}, // 无交易所前缀无法区分，按个股处理
+	}
+	for _, tc := range tests {
+		if got := ClassifySymbol(tc.code); got != tc.want {
+			t.Errorf("ClassifySymbol(%q) = %s, want %s", tc.code, got, tc.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package openclaw
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
@@ -76,7 +77,11 @@ func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
 	summary, err := s.meetingService.RunSmartMeetingSync(ctx, aiConfig, chatReq)
 	if err != nil {
 		log.Error("分析失败: %v", err)
-		writeJSON(w, http.StatusInternalServerError, AnalyzeResponse{Error: err.Error()})
+		status := http.StatusInternalServerError
+		if errors.Is(err, meeting.ErrDataSuspect) {
+			status = http.StatusServiceUnavailable // 行情源暂时异常，调用方可稍后重试
+		}
+		writeJSON(w, status, AnalyzeResponse{Error: err.Error()})
 		return
 	}
 
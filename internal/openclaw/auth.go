@@ -0,0 +1,79 @@
+package openclaw
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TokenStore 按权限范围授权的 API 令牌来源
+type TokenStore interface {
+	GetAPITokens() []models.APIToken
+	TouchAPIToken(id string, at time.Time)
+}
+
+// 鉴权失败时响应体中的错误码
+const (
+	errUnauthorized      = "unauthorized"       // 未提供令牌或令牌无效（401）
+	errInsufficientScope = "insufficient_scope" // 令牌缺少该路由所需权限（403）
+)
+
+// AuthError 鉴权失败的响应体
+type AuthError struct {
+	Error         string            `json:"error"`
+	Message       string            `json:"message"`
+	RequiredScope models.APIScope   `json:"requiredScope,omitempty"`
+	TokenScopes   []models.APIScope `json:"tokenScopes,omitempty"`
+}
+
+// SetTokenStore 设置 API 令牌来源（需在 Start 前调用），令牌增删与吊销即时生效
+func (s *Server) SetTokenStore(store TokenStore) {
+	s.tokenStore = store
+}
+
+// withScope 鉴权中间件：校验 Bearer 令牌具备 scope 权限
+// 未配置 API Key 也没有令牌时不鉴权；旧版 API Key 视为拥有全部权限
+func (s *Server) withScope(scope models.APIScope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var tokens []models.APIToken
+		if s.tokenStore != nil {
+			tokens = s.tokenStore.GetAPITokens()
+		}
+		if s.apiKey == "" && len(tokens) == 0 {
+			next(w, r)
+			return
+		}
+
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || bearer == "" {
+			writeJSON(w, http.StatusUnauthorized, AuthError{Error: errUnauthorized, Message: "missing bearer token"})
+			return
+		}
+		if s.apiKey != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(s.apiKey)) == 1 {
+			next(w, r)
+			return
+		}
+
+		hash := models.HashAPIToken(bearer)
+		for i := range tokens {
+			token := &tokens[i]
+			if subtle.ConstantTimeCompare([]byte(hash), []byte(token.TokenHash)) != 1 {
+				continue
+			}
+			if !token.Allows(scope) {
+				writeJSON(w, http.StatusForbidden, AuthError{
+					Error: errInsufficientScope, Message: "token lacks required scope",
+					RequiredScope: scope, TokenScopes: token.Scopes,
+				})
+				return
+			}
+			s.tokenStore.TouchAPIToken(token.ID, time.Now())
+			next(w, r)
+			return
+		}
+		writeJSON(w, http.StatusUnauthorized, AuthError{Error: errUnauthorized, Message: "invalid token"})
+	}
+}
@@ -0,0 +1,143 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/agent"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// memTokenStore 内存令牌来源，明文 -> 权限
+type memTokenStore struct {
+	mu      sync.Mutex
+	tokens  []models.APIToken
+	touched map[string]int
+}
+
+func newMemTokenStore(tokens map[string][]models.APIScope) *memTokenStore {
+	store := &memTokenStore{touched: make(map[string]int)}
+	for plaintext, scopes := range tokens {
+		store.tokens = append(store.tokens, models.APIToken{ID: plaintext, TokenHash: models.HashAPIToken(plaintext), Scopes: scopes})
+	}
+	return store
+}
+
+func (m *memTokenStore) GetAPITokens() []models.APIToken { return m.tokens }
+
+func (m *memTokenStore) TouchAPIToken(id string, _ time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.touched[id]++
+}
+
+func newTestServer(store TokenStore, apiKey string) http.Handler {
+	s := NewServer(nil, agent.NewContainer(), func(string) *models.AIConfig { return nil },
+		func(code string) (*models.Stock, error) { return &models.Stock{Symbol: code, Price: 10}, nil })
+	s.SetSessionResolver(func(code string) *models.StockSession { return &models.StockSession{StockCode: code} })
+	s.SetTokenStore(store)
+	s.apiKey = apiKey
+	return s.newMux()
+}
+
+func doRequest(h http.Handler, path, token string) *httptest.ResponseRecorder {
+	// /analyze 用 GET 请求，通过鉴权后返回 405，不会真正开会
+	req := httptest.NewRequest(http.MethodGet, path+"?code=sh600519", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouteScopes(t *testing.T) {
+	// 每个令牌只有一个权限，明文即权限名
+	tokens := map[string][]models.APIScope{}
+	for _, scope := range models.APIScopes {
+		tokens[string(scope)] = []models.APIScope{scope}
+	}
+	store := newMemTokenStore(tokens)
+	h := newTestServer(store, "")
+
+	for _, rt := range routes {
+		t.Run(rt.path, func(t *testing.T) {
+			if rt.scope == "" {
+				if rec := doRequest(h, rt.path, ""); rec.Code != http.StatusOK {
+					t.Fatalf("public route status = %d", rec.Code)
+				}
+				return
+			}
+			if rec := doRequest(h, rt.path, ""); rec.Code != http.StatusUnauthorized {
+				t.Errorf("no token status = %d", rec.Code)
+			}
+			for plaintext := range tokens {
+				rec := doRequest(h, rt.path, plaintext)
+				allowed := plaintext == string(rt.scope) || plaintext == string(models.APIScopeAdmin)
+				if allowed && (rec.Code == http.StatusUnauthorized || rec.Code == http.StatusForbidden) {
+					t.Errorf("token %s should pass, status = %d", plaintext, rec.Code)
+				}
+				if !allowed {
+					if rec.Code != http.StatusForbidden {
+						t.Errorf("token %s status = %d, want 403", plaintext, rec.Code)
+						continue
+					}
+					var body AuthError
+					if err := json.NewDecoder(rec.Body).Decode(&body); err != nil ||
+						body.Error != errInsufficientScope || body.RequiredScope != rt.scope {
+						t.Errorf("token %s 403 body = %+v, %v", plaintext, body, err)
+					}
+				}
+			}
+		})
+	}
+	if store.touched[string(models.APIScopeReadMarket)] == 0 {
+		t.Error("鉴权通过应记录使用时间")
+	}
+}
+
+func TestAuth_InvalidTokenAndLegacyKey(t *testing.T) {
+	store := newMemTokenStore(map[string][]models.APIScope{"reader": {models.APIScopeReadMarket}})
+	h := newTestServer(store, "legacy-key")
+
+	rec := doRequest(h, "/quote", "wrong")
+	var body AuthError
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusUnauthorized || body.Error != errUnauthorized {
+		t.Errorf("invalid token status = %d, body = %+v", rec.Code, body)
+	}
+	// 旧版 API Key 拥有全部权限
+	if rec := doRequest(h, "/tokens", "legacy-key"); rec.Code != http.StatusOK {
+		t.Errorf("legacy key status = %d", rec.Code)
+	}
+
+	// 未配置密钥与令牌时不鉴权
+	open := newTestServer(newMemTokenStore(nil), "")
+	if rec := doRequest(open, "/quote", ""); rec.Code != http.StatusOK {
+		t.Errorf("open server status = %d", rec.Code)
+	}
+}
+
+func TestHealthOmitsCacheStats(t *testing.T) {
+	s := NewServer(nil, agent.NewContainer(), func(string) *models.AIConfig { return nil }, nil)
+	s.SetCacheStatsProvider(func() any { return map[string]int{"hits": 3} })
+	s.SetTokenStore(newMemTokenStore(map[string][]models.APIScope{"admin": {models.APIScopeAdmin}}))
+	h := s.newMux()
+
+	var health map[string]any
+	json.NewDecoder(doRequest(h, "/health", "").Body).Decode(&health)
+	if len(health) != 1 || health["status"] != "ok" {
+		t.Errorf("health = %v", health)
+	}
+	rec := doRequest(h, "/cache", "admin")
+	var body struct {
+		Cache map[string]int `json:"cache"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || rec.Code != http.StatusOK || body.Cache["hits"] != 3 {
+		t.Errorf("cache status = %d, body = %+v, err = %v", rec.Code, body, err)
+	}
+}
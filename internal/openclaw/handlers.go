@@ -3,27 +3,24 @@ package openclaw
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
-)
 
-// withAuth 鉴权中间件
-func (s *Server) withAuth(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if s.apiKey != "" {
-			auth := r.Header.Get("Authorization")
-			if !strings.HasPrefix(auth, "Bearer ") || strings.TrimPrefix(auth, "Bearer ") != s.apiKey {
-				writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "unauthorized"})
-				return
-			}
-		}
-		next(w, r)
-	}
-}
+	"github.com/run-bigpig/jcp/internal/models"
+)
 
+// handleHealth 存活检查，无需鉴权，只返回状态
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
 }
 
+// handleCache 缓存统计，供管理脚本排查命中率
+func (s *Server) handleCache(w http.ResponseWriter, r *http.Request) {
+	var stats any = map[string]any{}
+	if s.cacheStats != nil {
+		stats = s.cacheStats()
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"cache": stats})
+}
+
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	agents := s.agentContainer.GetAllAgents()
 	writeJSON(w, http.StatusOK, map[string]any{
@@ -33,6 +30,55 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *Server) handleQuote(w http.ResponseWriter, r *http.Request) {
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "code required"})
+		return
+	}
+	stock, err := s.stockResolver(code)
+	if err != nil || stock == nil {
+		writeJSON(w, http.StatusBadGateway, map[string]any{"error": "failed to get stock data"})
+		return
+	}
+	writeJSON(w, http.StatusOK, stock)
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "code required"})
+		return
+	}
+	var session *models.StockSession
+	if s.sessionResolver != nil {
+		session = s.sessionResolver(code)
+	}
+	if session == nil {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "session not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+// handleTokens 列出令牌（不含哈希），供管理脚本审计最近使用时间
+func (s *Server) handleTokens(w http.ResponseWriter, r *http.Request) {
+	type tokenInfo struct {
+		ID         string            `json:"id"`
+		Name       string            `json:"name"`
+		Scopes     []models.APIScope `json:"scopes"`
+		CreatedAt  int64             `json:"createdAt"`
+		LastUsedAt int64             `json:"lastUsedAt,omitempty"`
+	}
+	infos := []tokenInfo{}
+	if s.tokenStore != nil {
+		for _, t := range s.tokenStore.GetAPITokens() {
+			infos = append(infos, tokenInfo{ID: t.ID, Name: t.Name, Scopes: t.Scopes, CreatedAt: t.CreatedAt, LastUsedAt: t.LastUsedAt})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tokens": infos})
+}
+
 func writeJSON(w http.ResponseWriter, code int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
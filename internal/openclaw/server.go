@@ -18,6 +18,12 @@ var log = logger.New("OpenClaw")
 // StockResolver 根据股票代码获取实时数据
 type StockResolver func(code string) (*models.Stock, error)
 
+// SessionResolver 根据股票代码获取会话，不存在时返回 nil
+type SessionResolver func(code string) *models.StockSession
+
+// StatsProvider 提供健康检查附带的运行统计
+type StatsProvider func() any
+
 // Server OpenClaw HTTP 服务
 type Server struct {
 	mu             sync.RWMutex
@@ -28,6 +34,10 @@ type Server struct {
 	agentContainer *agent.Container
 	aiResolver     func(string) *models.AIConfig
 	stockResolver  StockResolver
+	cacheStats     StatsProvider
+
+	sessionResolver SessionResolver
+	tokenStore      TokenStore
 }
 
 // NewServer 创建 OpenClaw 服务
@@ -40,6 +50,47 @@ func NewServer(ms *meeting.Service, ac *agent.Container, resolver func(string) *
 	}
 }
 
+// SetCacheStatsProvider 设置缓存统计来源，由需要管理权限的 /cache 返回（需在 Start 前调用）
+func (s *Server) SetCacheStatsProvider(p StatsProvider) {
+	s.cacheStats = p
+}
+
+// SetSessionResolver 设置会话来源，供 /session 读取会话记录（需在 Start 前调用）
+func (s *Server) SetSessionResolver(r SessionResolver) {
+	s.sessionResolver = r
+}
+
+// route 路由及其所需权限，scope 为空表示无需鉴权
+type route struct {
+	path    string
+	scope   models.APIScope
+	handler func(*Server) http.HandlerFunc
+}
+
+// routes 全部路由，权限在此集中声明
+var routes = []route{
+	{"/health", "", func(s *Server) http.HandlerFunc { return s.handleHealth }},
+	{"/status", "", func(s *Server) http.HandlerFunc { return s.handleStatus }},
+	{"/quote", models.APIScopeReadMarket, func(s *Server) http.HandlerFunc { return s.handleQuote }},
+	{"/session", models.APIScopeReadSessions, func(s *Server) http.HandlerFunc { return s.handleSession }},
+	{"/analyze", models.APIScopeRunMeetings, func(s *Server) http.HandlerFunc { return s.handleAnalyze }},
+	{"/tokens", models.APIScopeAdmin, func(s *Server) http.HandlerFunc { return s.handleTokens }},
+	{"/cache", models.APIScopeAdmin, func(s *Server) http.HandlerFunc { return s.handleCache }},
+}
+
+// newMux 按路由表注册处理器，需要权限的路由经过鉴权中间件
+func (s *Server) newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, rt := range routes {
+		h := rt.handler(s)
+		if rt.scope != "" {
+			h = s.withScope(rt.scope, h)
+		}
+		mux.HandleFunc(rt.path, h)
+	}
+	return mux
+}
+
 // Start 启动服务
 func (s *Server) Start(port int, apiKey string) error {
 	s.mu.Lock()
@@ -55,14 +106,9 @@ func (s *Server) Start(port int, apiKey string) error {
 		return fmt.Errorf("端口 %d 被占用", port)
 	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/health", s.handleHealth)
-	mux.HandleFunc("/status", s.handleStatus)
-	mux.HandleFunc("/analyze", s.withAuth(s.handleAnalyze))
-
 	s.port = port
 	s.apiKey = apiKey
-	s.server = &http.Server{Handler: mux}
+	s.server = &http.Server{Handler: s.newMux()}
 
 	go func() {
 		log.Info("OpenClaw 服务启动于端口 %d", port)
@@ -0,0 +1,29 @@
+package httpbody
+
+import (
+	"context"
+	"io"
+)
+
+// CloseOnCancel ctx 取消时立即关闭响应体，使阻塞中的流式读取马上返回并断开连接
+// 不依赖 Transport 对请求 ctx 的处理（自定义 HTTPDoer、代理拨号器未必遵守）
+// 返回的 Reader 在取消后把读错误统一为 ctx.Err()，调用方可按 context.Canceled 识别主动取消
+// 调用方需 defer stop()
+func CloseOnCancel(ctx context.Context, body io.ReadCloser) (r io.Reader, stop func() bool) {
+	stop = context.AfterFunc(ctx, func() { body.Close() })
+	return &cancelReader{ctx: ctx, r: body}, stop
+}
+
+// cancelReader 取消后返回 ctx.Err() 的 Reader
+type cancelReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *cancelReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err != nil && c.ctx.Err() != nil {
+		return n, c.ctx.Err()
+	}
+	return n, err
+}
@@ -0,0 +1,236 @@
+// Package indicators 基于K线计算常用技术指标
+// 口径与国内行情软件一致：EMA 以首个值为种子，MACD 柱为 2*(DIF-DEA)，KDJ 初值 50
+package indicators
+
+import (
+	"math"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// Indicators 最新一根K线上的指标值，数据不足以计算的指标省略
+type Indicators struct {
+	Time string             `json:"time"`
+	Bars int                `json:"bars"`            // 参与计算的K线根数
+	MA   map[string]float64 `json:"ma,omitempty"`    // ma5/ma10/ma20/ma60
+	EMA  map[string]float64 `json:"ema,omitempty"`   // ema12/ema26
+	MACD *MACD              `json:"macd,omitempty"`  // MACD(12,26,9)
+	RSI  *float64           `json:"rsi14,omitempty"` // RSI(14)
+	KDJ  *KDJ               `json:"kdj,omitempty"`   // KDJ(9,3,3)
+	Boll *Boll              `json:"boll,omitempty"`  // 布林带(20,2)
+}
+
+// MACD 指标值
+type MACD struct {
+	DIF  float64 `json:"dif"`
+	DEA  float64 `json:"dea"`
+	Hist float64 `json:"hist"`
+}
+
+// KDJ 指标值
+type KDJ struct {
+	K float64 `json:"k"`
+	D float64 `json:"d"`
+	J float64 `json:"j"`
+}
+
+// Boll 布林带
+type Boll struct {
+	Upper float64 `json:"upper"`
+	Mid   float64 `json:"mid"`
+	Lower float64 `json:"lower"`
+}
+
+// Compute 计算 klines（按时间升序）最新一根上的指标，空切片返回 nil
+func Compute(klines []models.KLineData) *Indicators {
+	n := len(klines)
+	if n == 0 {
+		return nil
+	}
+	closes := make([]float64, n)
+	highs := make([]float64, n)
+	lows := make([]float64, n)
+	for i, k := range klines {
+		closes[i], highs[i], lows[i] = k.Close, k.High, k.Low
+	}
+
+	ind := &Indicators{Time: klines[n-1].Time, Bars: n}
+	for _, p := range []struct {
+		key    string
+		period int
+	}{{"ma5", 5}, {"ma10", 10}, {"ma20", 20}, {"ma60", 60}} {
+		if v := last(MA(closes, p.period)); !math.IsNaN(v) {
+			if ind.MA == nil {
+				ind.MA = make(map[string]float64)
+			}
+			ind.MA[p.key] = round(v)
+		}
+	}
+	// EMA/MACD 以首值为种子，不足长周期时结果不可靠，不输出
+	if n >= 26 {
+		ind.EMA = map[string]float64{
+			"ema12": round(last(EMA(closes, 12))),
+			"ema26": round(last(EMA(closes, 26))),
+		}
+		dif, dea, hist := MACDSeries(closes, 12, 26, 9)
+		ind.MACD = &MACD{DIF: round(last(dif)), DEA: round(last(dea)), Hist: round(last(hist))}
+	}
+	if v := last(RSI(closes, 14)); !math.IsNaN(v) {
+		v = round(v)
+		ind.RSI = &v
+	}
+	if k, d, j := KDJSeries(highs, lows, closes, 9, 3, 3); !math.IsNaN(last(k)) {
+		ind.KDJ = &KDJ{K: round(last(k)), D: round(last(d)), J: round(last(j))}
+	}
+	if upper, mid, lower := BollSeries(closes, 20, 2); !math.IsNaN(last(mid)) {
+		ind.Boll = &Boll{Upper: round(last(upper)), Mid: round(last(mid)), Lower: round(last(lower))}
+	}
+	return ind
+}
+
+// MA 简单移动平均，前 period-1 个值为 NaN
+func MA(values []float64, period int) []float64 {
+	out := nanSlice(len(values))
+	if period <= 0 {
+		return out
+	}
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// EMA 指数移动平均，alpha = 2/(period+1)，以首个值为种子
+func EMA(values []float64, period int) []float64 {
+	out := make([]float64, len(values))
+	if len(values) == 0 || period <= 0 {
+		return out
+	}
+	alpha := 2 / float64(period+1)
+	out[0] = values[0]
+	for i := 1; i < len(values); i++ {
+		out[i] = alpha*values[i] + (1-alpha)*out[i-1]
+	}
+	return out
+}
+
+// MACDSeries 返回 DIF、DEA 与 MACD 柱（2*(DIF-DEA)）
+func MACDSeries(closes []float64, fast, slow, signal int) (dif, dea, hist []float64) {
+	emaFast, emaSlow := EMA(closes, fast), EMA(closes, slow)
+	dif = make([]float64, len(closes))
+	for i := range closes {
+		dif[i] = emaFast[i] - emaSlow[i]
+	}
+	dea = EMA(dif, signal)
+	hist = make([]float64, len(closes))
+	for i := range closes {
+		hist[i] = 2 * (dif[i] - dea[i])
+	}
+	return dif, dea, hist
+}
+
+// RSI 相对强弱指标（Wilder 平滑），前 period 个值为 NaN
+func RSI(closes []float64, period int) []float64 {
+	out := nanSlice(len(closes))
+	if period <= 0 || len(closes) <= period {
+		return out
+	}
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		gain, loss = accumulate(gain, loss, closes[i]-closes[i-1])
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	out[period] = rsiValue(gain, loss)
+	for i := period + 1; i < len(closes); i++ {
+		g, l := accumulate(0, 0, closes[i]-closes[i-1])
+		gain = (gain*float64(period-1) + g) / float64(period)
+		loss = (loss*float64(period-1) + l) / float64(period)
+		out[i] = rsiValue(gain, loss)
+	}
+	return out
+}
+
+func accumulate(gain, loss, change float64) (float64, float64) {
+	if change > 0 {
+		return gain + change, loss
+	}
+	return gain, loss - change
+}
+
+func rsiValue(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// KDJSeries 随机指标，K、D 以 50 为初值按 1/m 平滑，J = 3K - 2D；前 n-1 个值为 NaN
+func KDJSeries(highs, lows, closes []float64, n, m1, m2 int) (k, d, j []float64) {
+	size := len(closes)
+	k, d, j = nanSlice(size), nanSlice(size), nanSlice(size)
+	if n <= 0 || m1 <= 0 || m2 <= 0 {
+		return k, d, j
+	}
+	prevK, prevD := 50.0, 50.0
+	for i := n - 1; i < size; i++ {
+		hh, ll := highs[i], lows[i]
+		for x := i - n + 1; x < i; x++ {
+			hh = math.Max(hh, highs[x])
+			ll = math.Min(ll, lows[x])
+		}
+		rsv := 50.0
+		if hh > ll {
+			rsv = (closes[i] - ll) / (hh - ll) * 100
+		}
+		prevK = (float64(m1-1)*prevK + rsv) / float64(m1)
+		prevD = (float64(m2-1)*prevD + prevK) / float64(m2)
+		k[i], d[i], j[i] = prevK, prevD, 3*prevK-2*prevD
+	}
+	return k, d, j
+}
+
+// BollSeries 布林带：中轨为 period 日均线，上下轨为中轨 ± width 倍总体标准差
+func BollSeries(closes []float64, period int, width float64) (upper, mid, lower []float64) {
+	mid = MA(closes, period)
+	upper, lower = nanSlice(len(closes)), nanSlice(len(closes))
+	for i := period - 1; i >= 0 && i < len(closes); i++ {
+		var variance float64
+		for _, v := range closes[i-period+1 : i+1] {
+			variance += (v - mid[i]) * (v - mid[i])
+		}
+		sd := math.Sqrt(variance / float64(period))
+		upper[i], lower[i] = mid[i]+width*sd, mid[i]-width*sd
+	}
+	return upper, mid, lower
+}
+
+func nanSlice(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+func last(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	return values[len(values)-1]
+}
+
+// round 保留三位小数，避免输出过长
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,92 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestMAAndEMA(t *testing.T) {
+	ma := MA([]float64{1, 2, 3, 4, 5}, 3)
+	if !math.IsNaN(ma[1]) || ma[2] != 2 || ma[4] != 4 {
+		t.Fatalf("MA = %v", ma)
+	}
+	ema := EMA([]float64{1, 2, 3}, 2)
+	if ema[0] != 1 || !almostEqual(ema[1], 5.0/3) || !almostEqual(ema[2], 23.0/9) {
+		t.Fatalf("EMA = %v", ema)
+	}
+}
+
+func TestRSI(t *testing.T) {
+	rising := []float64{1, 2, 3, 4, 5, 6}
+	if got := last(RSI(rising, 3)); got != 100 {
+		t.Fatalf("单边上涨 RSI = %v, want 100", got)
+	}
+	// 涨跌幅相同、交替出现时首个 RSI 为 50
+	alt := []float64{10, 11, 10, 11, 10}
+	if got := RSI(alt, 4)[4]; !almostEqual(got, 50) {
+		t.Fatalf("RSI = %v, want 50", got)
+	}
+	if got := RSI(rising, 6); !math.IsNaN(last(got)) {
+		t.Fatalf("数据不足时应为 NaN: %v", got)
+	}
+}
+
+func TestKDJAndBoll(t *testing.T) {
+	// 收盘即最高价时 RSV=100，K/D 自 50 向上收敛
+	highs := []float64{10, 11, 12}
+	lows := []float64{9, 10, 11}
+	k, d, j := KDJSeries(highs, lows, highs, 2, 3, 3)
+	if !math.IsNaN(k[0]) || !almostEqual(k[1], 200.0/3) || !almostEqual(d[1], 50+50.0/9) {
+		t.Fatalf("K = %v, D = %v", k, d)
+	}
+	if !almostEqual(j[1], 3*k[1]-2*d[1]) {
+		t.Fatalf("J = %v", j)
+	}
+
+	upper, mid, lower := BollSeries([]float64{5, 5, 5, 5}, 3, 2)
+	if mid[3] != 5 || upper[3] != 5 || lower[3] != 5 {
+		t.Fatalf("常数序列布林带应收敛于均值: %v %v %v", upper, mid, lower)
+	}
+	upper, mid, lower = BollSeries([]float64{1, 3}, 2, 2)
+	if mid[1] != 2 || upper[1] != 4 || lower[1] != 0 {
+		t.Fatalf("Boll = %v %v %v", upper, mid, lower)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	if Compute(nil) != nil {
+		t.Fatal("空K线应返回 nil")
+	}
+
+	short := make([]models.KLineData, 10)
+	for i := range short {
+		c := float64(10 + i)
+		short[i] = models.KLineData{Time: fmt.Sprintf("d%d", i), Open: c, High: c + 1, Low: c - 1, Close: c}
+	}
+	ind := Compute(short)
+	if ind.Time != "d9" || ind.Bars != 10 || ind.MA["ma5"] != 17 || ind.MA["ma10"] != 14.5 {
+		t.Fatalf("ind = %+v", ind)
+	}
+	if _, ok := ind.MA["ma20"]; ok || ind.MACD != nil || ind.RSI != nil || ind.Boll != nil || ind.KDJ == nil {
+		t.Fatalf("数据不足的指标应省略: %+v", ind)
+	}
+
+	flat := make([]models.KLineData, 60)
+	for i := range flat {
+		flat[i] = models.KLineData{High: 10, Low: 10, Close: 10}
+	}
+	ind = Compute(flat)
+	if ind.MA["ma60"] != 10 || ind.MACD == nil || ind.MACD.DIF != 0 || ind.MACD.Hist != 0 {
+		t.Fatalf("ind = %+v", ind)
+	}
+	if ind.RSI == nil || *ind.RSI != 50 || ind.KDJ.K != 50 || ind.Boll.Upper != 10 {
+		t.Fatalf("ind = %+v", ind)
+	}
+}
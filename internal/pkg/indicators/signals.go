@@ -0,0 +1,221 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 指标名称，用于按需选择指标
+const (
+	NameMA   = "ma"
+	NameMACD = "macd"
+	NameKDJ  = "kdj"
+	NameRSI  = "rsi"
+	NameBoll = "boll"
+)
+
+// Names 全部可选指标，按输出顺序排列
+var Names = []string{NameMA, NameMACD, NameKDJ, NameRSI, NameBoll}
+
+// minBars 各指标至少需要的K线根数，与 Compute 的计算口径一致
+var minBars = map[string]int{NameMACD: 26, NameKDJ: 9, NameRSI: 15, NameBoll: 20}
+
+// maPeriods 输出的均线周期
+var maPeriods = []int{5, 10, 20, 60}
+
+// 信号判定阈值
+const (
+	rsiOverbought = 70
+	rsiOversold   = 30
+	kdjOverbought = 80
+	kdjOversold   = 20
+)
+
+// Analysis 按需选择的指标、文字信号与数据不足说明
+type Analysis struct {
+	Indicators *Indicators `json:"indicators,omitempty"`
+	Signals    []string    `json:"signals,omitempty"` // 金叉、死叉、超买、超卖等信号
+	Notes      []string    `json:"notes,omitempty"`   // 数据不足被省略的指标、忽略的指标名
+}
+
+// Analyze 计算 klines（按时间升序）上选中的指标与信号，names 为空表示全部
+// K线不足以计算某项指标时省略该指标并在 Notes 中说明，不视为错误
+func Analyze(klines []models.KLineData, names []string) *Analysis {
+	selected, unknown := normalizeNames(names)
+	a := &Analysis{}
+	for _, name := range unknown {
+		a.Notes = append(a.Notes, fmt.Sprintf("不支持的指标 %s，已忽略（可选: %s）", name, strings.Join(Names, ", ")))
+	}
+	ind := Compute(klines)
+	if ind == nil {
+		a.Notes = append(a.Notes, "暂无K线数据")
+		return a
+	}
+	a.Indicators = pick(ind, selected)
+
+	n := len(klines)
+	closes := make([]float64, n)
+	highs := make([]float64, n)
+	lows := make([]float64, n)
+	for i, k := range klines {
+		closes[i], highs[i], lows[i] = k.Close, k.High, k.Low
+	}
+	for _, name := range selected {
+		if name == NameMA {
+			var missing []string
+			for _, p := range maPeriods {
+				if n < p {
+					missing = append(missing, fmt.Sprintf("MA%d", p))
+				}
+			}
+			if len(missing) > 0 {
+				a.Notes = append(a.Notes, fmt.Sprintf("K线仅 %d 根，%s 已省略", n, strings.Join(missing, "/")))
+			}
+		} else if need := minBars[name]; n < need {
+			a.Notes = append(a.Notes, fmt.Sprintf("K线仅 %d 根，%s 至少需要 %d 根，已省略", n, strings.ToUpper(name), need))
+			continue
+		}
+		a.Signals = append(a.Signals, signals(name, ind, closes, highs, lows)...)
+	}
+	return a
+}
+
+// normalizeNames 统一为小写并去重，返回支持的指标（按 Names 顺序）与不支持的名称
+func normalizeNames(names []string) (selected, unknown []string) {
+	if len(names) == 0 {
+		return Names, nil
+	}
+	want := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "":
+		case name == "bollinger":
+			want[NameBoll] = true
+		case slices.Contains(Names, name):
+			want[name] = true
+		case !slices.Contains(unknown, name):
+			unknown = append(unknown, name)
+		}
+	}
+	for _, name := range Names {
+		if want[name] {
+			selected = append(selected, name)
+		}
+	}
+	return selected, unknown
+}
+
+// pick 只保留选中的指标，EMA 随 MACD 输出
+func pick(ind *Indicators, selected []string) *Indicators {
+	out := &Indicators{Time: ind.Time, Bars: ind.Bars}
+	for _, name := range selected {
+		switch name {
+		case NameMA:
+			out.MA = ind.MA
+		case NameMACD:
+			out.EMA, out.MACD = ind.EMA, ind.MACD
+		case NameKDJ:
+			out.KDJ = ind.KDJ
+		case NameRSI:
+			out.RSI = ind.RSI
+		case NameBoll:
+			out.Boll = ind.Boll
+		}
+	}
+	return out
+}
+
+// signals 单项指标的文字信号
+func signals(name string, ind *Indicators, closes, highs, lows []float64) []string {
+	var out []string
+	switch name {
+	case NameMA:
+		switch cross(MA(closes, 5), MA(closes, 10)) {
+		case 1:
+			out = append(out, "MA5 上穿 MA10，均线金叉")
+		case -1:
+			out = append(out, "MA5 下穿 MA10，均线死叉")
+		}
+		if len(ind.MA) == len(maPeriods) {
+			ma5, ma10, ma20, ma60 := ind.MA["ma5"], ind.MA["ma10"], ind.MA["ma20"], ind.MA["ma60"]
+			switch {
+			case ma5 > ma10 && ma10 > ma20 && ma20 > ma60:
+				out = append(out, "均线多头排列")
+			case ma5 < ma10 && ma10 < ma20 && ma20 < ma60:
+				out = append(out, "均线空头排列")
+			}
+		}
+	case NameMACD:
+		dif, dea, _ := MACDSeries(closes, 12, 26, 9)
+		axis := "零轴下方"
+		if ind.MACD.DIF > 0 {
+			axis = "零轴上方"
+		}
+		switch cross(dif, dea) {
+		case 1:
+			out = append(out, "MACD "+axis+"金叉")
+		case -1:
+			out = append(out, "MACD "+axis+"死叉")
+		default:
+			if ind.MACD.Hist > 0 {
+				out = append(out, "MACD 红柱，DIF 位于"+axis)
+			} else {
+				out = append(out, "MACD 绿柱，DIF 位于"+axis)
+			}
+		}
+	case NameKDJ:
+		k, d, _ := KDJSeries(highs, lows, closes, 9, 3, 3)
+		switch cross(k, d) {
+		case 1:
+			out = append(out, "KDJ 金叉")
+		case -1:
+			out = append(out, "KDJ 死叉")
+		}
+		switch {
+		case ind.KDJ.J > 100 || ind.KDJ.K > kdjOverbought:
+			out = append(out, fmt.Sprintf("KDJ 超买（K=%.1f，J=%.1f）", ind.KDJ.K, ind.KDJ.J))
+		case ind.KDJ.J < 0 || ind.KDJ.K < kdjOversold:
+			out = append(out, fmt.Sprintf("KDJ 超卖（K=%.1f，J=%.1f）", ind.KDJ.K, ind.KDJ.J))
+		}
+	case NameRSI:
+		switch rsi := *ind.RSI; {
+		case rsi >= rsiOverbought:
+			out = append(out, fmt.Sprintf("RSI14=%.1f 超买", rsi))
+		case rsi <= rsiOversold:
+			out = append(out, fmt.Sprintf("RSI14=%.1f 超卖", rsi))
+		}
+	case NameBoll:
+		switch price := closes[len(closes)-1]; {
+		case price > ind.Boll.Upper:
+			out = append(out, "收盘价突破布林上轨")
+		case price < ind.Boll.Lower:
+			out = append(out, "收盘价跌破布林下轨")
+		}
+	}
+	return out
+}
+
+// cross 判断最新两根K线上 fast 与 slow 的交叉：1 为上穿（金叉），-1 为下穿（死叉），0 为无交叉或数据不足
+func cross(fast, slow []float64) int {
+	n := len(fast)
+	if n < 2 || len(slow) != n {
+		return 0
+	}
+	for _, v := range []float64{fast[n-2], fast[n-1], slow[n-2], slow[n-1]} {
+		if math.IsNaN(v) {
+			return 0
+		}
+	}
+	switch {
+	case fast[n-2] <= slow[n-2] && fast[n-1] > slow[n-1]:
+		return 1
+	case fast[n-2] >= slow[n-2] && fast[n-1] < slow[n-1]:
+		return -1
+	}
+	return 0
+}
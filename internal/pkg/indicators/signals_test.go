@@ -0,0 +1,83 @@
+package indicators
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func klinesFromCloses(closes []float64) []models.KLineData {
+	out := make([]models.KLineData, len(closes))
+	for i, c := range closes {
+		out[i] = models.KLineData{Open: c, High: c + 0.5, Low: c - 0.5, Close: c}
+	}
+	return out
+}
+
+func TestCross(t *testing.T) {
+	if got := cross([]float64{1, 3}, []float64{2, 2}); got != 1 {
+		t.Errorf("golden cross = %d", got)
+	}
+	if got := cross([]float64{3, 1}, []float64{2, 2}); got != -1 {
+		t.Errorf("death cross = %d", got)
+	}
+	if got := cross([]float64{3, 4}, []float64{2, 2}); got != 0 {
+		t.Errorf("no cross = %d", got)
+	}
+	if got := cross([]float64{nanSlice(1)[0], 3}, []float64{2, 2}); got != 0 {
+		t.Errorf("NaN cross = %d", got)
+	}
+}
+
+func TestAnalyze_Signals(t *testing.T) {
+	rising := make([]float64, 70)
+	for i := range rising {
+		rising[i] = 10 + float64(i)
+	}
+	a := Analyze(klinesFromCloses(rising), nil)
+	if len(a.Notes) != 0 || a.Indicators.MA["ma60"] == 0 || a.Indicators.MACD == nil || a.Indicators.Boll == nil {
+		t.Fatalf("analysis = %+v", a)
+	}
+	for _, want := range []string{"均线多头排列", "MACD 红柱，DIF 位于零轴上方", "RSI14=100.0 超买"} {
+		if !slices.Contains(a.Signals, want) {
+			t.Errorf("signals %v missing %q", a.Signals, want)
+		}
+	}
+
+	// 阴跌后放量大涨，MA5 上穿 MA10
+	falling := make([]float64, 30)
+	for i := range falling {
+		falling[i] = 60 - float64(i)
+	}
+	falling = append(falling, 80)
+	a = Analyze(klinesFromCloses(falling), []string{"MA"})
+	if !slices.Contains(a.Signals, "MA5 上穿 MA10，均线金叉") {
+		t.Errorf("signals = %v", a.Signals)
+	}
+	if a.Indicators.MACD != nil || a.Indicators.RSI != nil {
+		t.Errorf("unselected indicators should be omitted: %+v", a.Indicators)
+	}
+}
+
+func TestAnalyze_Partial(t *testing.T) {
+	closes := make([]float64, 12)
+	for i := range closes {
+		closes[i] = 10
+	}
+	a := Analyze(klinesFromCloses(closes), []string{"macd", "ma", "xyz", "MA"})
+	if a.Indicators == nil || a.Indicators.MA["ma10"] != 10 || a.Indicators.MACD != nil || a.Indicators.KDJ != nil {
+		t.Fatalf("indicators = %+v", a.Indicators)
+	}
+	notes := strings.Join(a.Notes, "\n")
+	for _, want := range []string{"不支持的指标 xyz", "K线仅 12 根，MA20/MA60 已省略", "MACD 至少需要 26 根"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("notes missing %q:\n%s", want, notes)
+		}
+	}
+
+	if a := Analyze(nil, nil); a.Indicators != nil || len(a.Notes) != 1 {
+		t.Errorf("empty analysis = %+v", a)
+	}
+}
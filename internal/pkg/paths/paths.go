@@ -14,6 +14,11 @@ func GetDataDir() string {
 	return filepath.Join(userConfigDir, "jcp")
 }
 
+// GetExportDir 获取导出文件目录
+func GetExportDir() string {
+	return filepath.Join(GetDataDir(), "exports")
+}
+
 // GetCacheDir 获取缓存目录
 func GetCacheDir() string {
 	return filepath.Join(GetDataDir(), "cache")
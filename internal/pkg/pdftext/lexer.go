@@ -0,0 +1,315 @@
+package pdftext
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// keyword 关键字或内容流操作符（如 Tj、obj、true）
+type keyword string
+
+// maxNesting 数组/字典最大嵌套层数
+const maxNesting = 64
+
+// lexer PDF 语法解析，同时用于对象内容、内容流与 CMap
+type lexer struct {
+	b   []byte
+	pos int
+}
+
+func newLexer(b []byte) *lexer {
+	return &lexer{b: b}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return isSpace(c)
+}
+
+// skipSpace 跳过空白与注释
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.b) {
+		c := l.b[l.pos]
+		if isSpace(c) {
+			l.pos++
+		} else if c == '%' {
+			for l.pos < len(l.b) && l.b[l.pos] != '\n' && l.b[l.pos] != '\r' {
+				l.pos++
+			}
+		} else {
+			return
+		}
+	}
+}
+
+// object 读取下一个对象，输入结束时返回 false
+// 字典为 map[string]any，数组为 []any，字符串为 []byte，数字为 float64
+func (l *lexer) object() (any, bool) {
+	return l.objectDepth(0)
+}
+
+func (l *lexer) objectDepth(depth int) (any, bool) {
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.b) {
+			return nil, false
+		}
+		c := l.b[l.pos]
+		switch {
+		case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+			// 不成对的分隔符，跳过后继续
+			l.pos++
+			continue
+		case (c == '[' || c == '<' && l.pos+1 < len(l.b) && l.b[l.pos+1] == '<') && depth >= maxNesting:
+			// 嵌套过深，跳过开始符，内容按同一层解析
+			l.pos++
+			continue
+		case c == '/':
+			return l.name(), true
+		case c == '(':
+			return l.literalString(), true
+		case c == '<' && l.pos+1 < len(l.b) && l.b[l.pos+1] == '<':
+			l.pos += 2
+			return l.dict(depth), true
+		case c == '<':
+			return l.hexString(), true
+		case c == '[':
+			l.pos++
+			return l.array(depth), true
+		}
+		break
+	}
+
+	tok := l.regular()
+	if n, err := strconv.ParseFloat(tok, 64); err == nil {
+		// "N G R" 为间接引用
+		save := l.pos
+		if gen, ok := l.peekRegular(); ok {
+			if _, err := strconv.Atoi(gen); err == nil {
+				if r, ok := l.peekRegular(); ok && r == "R" {
+					return pdfRef(int(n)), true
+				}
+			}
+		}
+		l.pos = save
+		return n, true
+	}
+	return keyword(tok), true
+}
+
+// regular 读取普通字符组成的记号
+func (l *lexer) regular() string {
+	start := l.pos
+	for l.pos < len(l.b) && !isDelimiter(l.b[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		// 非法字符，前进一个字节避免死循环
+		l.pos++
+	}
+	return string(l.b[start:l.pos])
+}
+
+// peekRegular 读取下一个普通记号，用于引用判断，调用方负责回退
+func (l *lexer) peekRegular() (string, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.b) || isDelimiter(l.b[l.pos]) {
+		return "", false
+	}
+	return l.regular(), true
+}
+
+func (l *lexer) name() pdfName {
+	l.pos++ // '/'
+	start := l.pos
+	for l.pos < len(l.b) && !isDelimiter(l.b[l.pos]) {
+		l.pos++
+	}
+	raw := l.b[start:l.pos]
+	if bytes.IndexByte(raw, '#') < 0 {
+		return pdfName(raw)
+	}
+	// #xx 转义
+	var out []byte
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if v, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				i += 2
+				continue
+			}
+		}
+		out = append(out, raw[i])
+	}
+	return pdfName(out)
+}
+
+func (l *lexer) literalString() []byte {
+	l.pos++ // '('
+	var out []byte
+	depth := 1
+	for l.pos < len(l.b) {
+		c := l.b[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.b) {
+				return out
+			}
+			e := l.b[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				// 续行
+				if l.pos < len(l.b) && l.b[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.b) && l.b[l.pos] >= '0' && l.b[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.b[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *lexer) hexString() []byte {
+	l.pos++ // '<'
+	var out []byte
+	hi, half := byte(0), false
+	for l.pos < len(l.b) {
+		c := l.b[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue
+		}
+		if half {
+			out = append(out, hi<<4|v)
+		} else {
+			hi = v
+		}
+		half = !half
+	}
+	if half {
+		out = append(out, hi<<4)
+	}
+	return out
+}
+
+func (l *lexer) array(depth int) []any {
+	arr := []any{}
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.b) {
+			return arr
+		}
+		if l.b[l.pos] == ']' {
+			l.pos++
+			return arr
+		}
+		v, ok := l.objectDepth(depth + 1)
+		if !ok {
+			return arr
+		}
+		arr = append(arr, v)
+	}
+}
+
+func (l *lexer) dict(depth int) map[string]any {
+	dict := make(map[string]any)
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.b) {
+			return dict
+		}
+		if l.b[l.pos] == '>' {
+			if l.pos+1 < len(l.b) && l.b[l.pos+1] == '>' {
+				l.pos += 2
+			} else {
+				l.pos++
+			}
+			return dict
+		}
+		if l.b[l.pos] != '/' {
+			// 非名称键，跳过该值
+			if _, ok := l.objectDepth(depth + 1); !ok {
+				return dict
+			}
+			continue
+		}
+		key := l.name()
+		v, ok := l.objectDepth(depth + 1)
+		if !ok {
+			return dict
+		}
+		dict[string(key)] = v
+	}
+}
+
+// skipInlineImage 跳过内联图片（BI ... ID 二进制数据 EI），避免把图片数据当作操作符
+func (l *lexer) skipInlineImage() {
+	idx := bytes.Index(l.b[l.pos:], []byte("ID"))
+	if idx < 0 {
+		l.pos = len(l.b)
+		return
+	}
+	l.pos += idx + 2
+	for l.pos < len(l.b) {
+		e := bytes.Index(l.b[l.pos:], []byte("EI"))
+		if e < 0 {
+			l.pos = len(l.b)
+			return
+		}
+		at := l.pos + e
+		l.pos = at + 2
+		if at > 0 && isSpace(l.b[at-1]) && (l.pos >= len(l.b) || isSpace(l.b[l.pos])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,691 @@
+// Package pdftext 从 PDF 中提取纯文本（仅依赖标准库）
+// 面向交易所公告这类文字型 PDF：按页顺序解析内容流中的文本操作符，通过字体的 ToUnicode 映射还原中文
+// 不支持加密文档与扫描件（图片）中的文字
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrEncrypted 加密的 PDF
+var ErrEncrypted = errors.New("加密的 PDF 暂不支持")
+
+// ErrNoText 未提取到文本（扫描件或解析失败）
+var ErrNoText = errors.New("PDF 中未提取到文本")
+
+// maxPageDepth 页面树最大深度，防止循环引用
+const maxPageDepth = 32
+
+// 解析上限，防止畸形或恶意构造的 PDF 耗尽内存或长时间占用 CPU
+const (
+	maxStreamSize  = 16 << 20 // 单个流解压后的大小上限，超出时丢弃该流，防止压缩炸弹
+	maxDecodedSize = 64 << 20 // 整个文档累计解压量上限，同一个流被多页引用时重复计入
+	maxObjects     = 1 << 18  // 对象数上限（含对象流中的对象）
+	maxCMapSets    = 1 << 18  // 单个 ToUnicode 映射累计写入次数上限，bfrange 可声明大量编码
+)
+
+var (
+	objHeaderPattern  = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	rootPattern       = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	whitespacePattern = regexp.MustCompile(`[ \t]+`)
+)
+
+// Extract 提取 PDF 文本，页与页之间空一行
+func Extract(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \r\n\t"), []byte("%PDF")) {
+		return "", errors.New("不是有效的 PDF 文件")
+	}
+	doc := newDocument(data)
+	if doc.encrypted() {
+		return "", ErrEncrypted
+	}
+
+	var pages []string
+	for _, page := range doc.pages() {
+		if text := doc.pageText(page); text != "" {
+			pages = append(pages, text)
+		}
+	}
+	text := strings.TrimSpace(strings.Join(pages, "\n\n"))
+	if text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+// ===== 文档对象 =====
+
+// pdfName 名称对象（不含斜杠）
+type pdfName string
+
+// pdfRef 间接引用（对象号）
+type pdfRef int
+
+// document 已拆分的 PDF 对象
+type document struct {
+	data    []byte
+	objects map[int][]byte // 对象号 -> obj 与 endobj 之间的内容
+	parsed  map[int]any
+	cmaps   map[int]*cmap // ToUnicode 流对象号 -> 映射
+	decoded int           // 已解压的字节数，见 maxDecodedSize
+}
+
+func newDocument(data []byte) *document {
+	d := &document{data: data, objects: make(map[int][]byte), parsed: make(map[int]any), cmaps: make(map[int]*cmap)}
+	d.scanObjects()
+	d.expandObjectStreams()
+	return d
+}
+
+// scanObjects 按 "N G obj ... endobj" 切分对象，不依赖 xref 表（后出现的同号对象为增量更新，覆盖旧值）
+// 对象互不重叠：落在前一个对象（含其流数据）内的 "N G obj" 字样不是对象头
+func (d *document) scanObjects() {
+	matches := objHeaderPattern.FindAllSubmatchIndex(d.data, maxObjects)
+	endobjs := indexAll(d.data, "endobj")
+	streams := indexAll(d.data, "stream")
+	endstreams := indexAll(d.data, "endstream")
+	prevEnd := 0
+	for i, m := range matches {
+		if m[0] < prevEnd {
+			continue
+		}
+		num, err := strconv.Atoi(string(d.data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		nextHeader := len(d.data)
+		if i+1 < len(matches) {
+			nextHeader = matches[i+1][0]
+		}
+		start := m[1]
+		end := objectEnd(start, nextHeader, endobjs, streams, endstreams)
+		if end < 0 {
+			continue
+		}
+		d.objects[num] = d.data[start:end]
+		prevEnd = end
+	}
+}
+
+// objectEnd 返回对象内容结束位置（endobj 前），流数据中可能出现 endobj 字样，需先跳过 endstream
+// 各关键字位置已预先找出，避免对每个对象从头搜索剩余数据；缺少 endobj 的对象截止到下一个对象头
+func objectEnd(start, nextHeader int, endobjs, streams, endstreams []int) int {
+	endobj := nextIndex(endobjs, start)
+	if st := nextIndex(streams, start); st >= 0 && (endobj < 0 || st < endobj) {
+		if es := nextIndex(endstreams, st); es >= 0 {
+			if e := nextIndex(endobjs, es); e >= 0 {
+				return e
+			}
+		}
+	}
+	if endobj < 0 {
+		return -1
+	}
+	return min(endobj, nextHeader)
+}
+
+// indexAll 返回 sep 在 data 中所有出现位置（升序）
+func indexAll(data []byte, sep string) []int {
+	var idx []int
+	for pos := 0; ; {
+		i := bytes.Index(data[pos:], []byte(sep))
+		if i < 0 {
+			return idx
+		}
+		idx = append(idx, pos+i)
+		pos += i + len(sep)
+	}
+}
+
+// nextIndex 返回 idx 中第一个不小于 pos 的位置，没有时返回 -1
+func nextIndex(idx []int, pos int) int {
+	if i := sort.SearchInts(idx, pos); i < len(idx) {
+		return idx[i]
+	}
+	return -1
+}
+
+// expandObjectStreams 展开对象流（PDF 1.5+ 把字典对象压缩存放在 /Type /ObjStm 流中）
+func (d *document) expandObjectStreams() {
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		dict, ok := d.object(num).(map[string]any)
+		if !ok || dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data := d.stream(num)
+		first, _ := d.resolve(dict["First"]).(float64)
+		if data == nil || int(first) <= 0 || int(first) > len(data) {
+			continue
+		}
+		header := strings.Fields(string(data[:int(first)]))
+		for i := 0; i+1 < len(header) && len(d.objects) < maxObjects; i += 2 {
+			objNum, err1 := strconv.Atoi(header[i])
+			offset, err2 := strconv.Atoi(header[i+1])
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			end := len(data)
+			if i+3 < len(header) {
+				next, err := strconv.Atoi(header[i+3])
+				if err != nil {
+					continue
+				}
+				end = int(first) + next
+			}
+			start := int(first) + offset
+			if start < int(first) || start > end || end > len(data) {
+				continue
+			}
+			if _, exists := d.objects[objNum]; !exists {
+				d.objects[objNum] = data[start:end]
+			}
+		}
+	}
+}
+
+// object 解析对象内容（字典、数组或其他值），结果缓存
+func (d *document) object(num int) any {
+	if v, ok := d.parsed[num]; ok {
+		return v
+	}
+	body, ok := d.objects[num]
+	if !ok {
+		return nil
+	}
+	d.parsed[num] = nil // 防止循环引用
+	v, _ := newLexer(body).object()
+	d.parsed[num] = v
+	return v
+}
+
+// resolve 解引用间接对象
+func (d *document) resolve(v any) any {
+	for i := 0; i < 8; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.object(int(ref))
+	}
+	return nil
+}
+
+// stream 返回流对象解码后的数据，不支持的编码返回 nil
+func (d *document) stream(num int) []byte {
+	body, ok := d.objects[num]
+	if !ok {
+		return nil
+	}
+	idx := bytes.Index(body, []byte("stream"))
+	if idx < 0 {
+		return nil
+	}
+	dict, _ := d.object(num).(map[string]any)
+	raw := body[idx+len("stream"):]
+	raw = bytes.TrimPrefix(raw, []byte("\r"))
+	raw = bytes.TrimPrefix(raw, []byte("\n"))
+	if length, ok := d.resolve(dict["Length"]).(float64); ok && int(length) > 0 && int(length) <= len(raw) {
+		raw = raw[:int(length)]
+	} else if end := bytes.LastIndex(raw, []byte("endstream")); end >= 0 {
+		raw = bytes.TrimRight(raw[:end], "\r\n")
+	}
+
+	var filters []pdfName
+	switch f := d.resolve(dict["Filter"]).(type) {
+	case pdfName:
+		filters = []pdfName{f}
+	case []any:
+		for _, item := range f {
+			if name, ok := d.resolve(item).(pdfName); ok {
+				filters = append(filters, name)
+			}
+		}
+	}
+	for _, f := range filters {
+		if f != "FlateDecode" {
+			return nil
+		}
+		limit := min(maxStreamSize, maxDecodedSize-d.decoded)
+		if limit <= 0 {
+			return nil
+		}
+		r, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil
+		}
+		decoded, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+		d.decoded += len(decoded)
+		if len(decoded) > limit {
+			return nil
+		}
+		// 部分生成器的压缩流缺少校验尾，已解出的数据仍可用；其余错误视为流已损坏
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		raw = decoded
+	}
+	return raw
+}
+
+// encrypted 是否为加密文档
+func (d *document) encrypted() bool {
+	return bytes.Contains(d.data, []byte("/Encrypt"))
+}
+
+// page 页面对象及继承的资源
+type page struct {
+	dict      map[string]any
+	resources map[string]any
+}
+
+// pages 按页面树顺序返回全部页面，找不到页面树时按对象号顺序返回 /Type /Page 对象
+func (d *document) pages() []page {
+	var pages []page
+	if m := rootPattern.FindAllSubmatch(d.data, -1); len(m) > 0 {
+		root, _ := strconv.Atoi(string(m[len(m)-1][1]))
+		if catalog, ok := d.object(root).(map[string]any); ok {
+			d.walkPages(catalog["Pages"], nil, 0, map[int]bool{}, &pages)
+		}
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		if dict, ok := d.object(num).(map[string]any); ok && dict["Type"] == pdfName("Page") {
+			res, _ := d.resolve(dict["Resources"]).(map[string]any)
+			pages = append(pages, page{dict: dict, resources: res})
+		}
+	}
+	return pages
+}
+
+func (d *document) walkPages(node any, inherited map[string]any, depth int, seen map[int]bool, pages *[]page) {
+	if ref, ok := node.(pdfRef); ok {
+		if seen[int(ref)] {
+			return
+		}
+		seen[int(ref)] = true
+	}
+	dict, ok := d.resolve(node).(map[string]any)
+	if !ok || depth > maxPageDepth {
+		return
+	}
+	if res, ok := d.resolve(dict["Resources"]).(map[string]any); ok {
+		inherited = res
+	}
+	if dict["Type"] == pdfName("Page") {
+		*pages = append(*pages, page{dict: dict, resources: inherited})
+		return
+	}
+	kids, _ := d.resolve(dict["Kids"]).([]any)
+	for _, kid := range kids {
+		d.walkPages(kid, inherited, depth+1, seen, pages)
+	}
+}
+
+// pageText 提取单页文本
+func (d *document) pageText(p page) string {
+	var content []byte
+	switch c := p.dict["Contents"].(type) {
+	case pdfRef:
+		if arr, ok := d.resolve(c).([]any); ok {
+			content = d.concatStreams(arr)
+		} else {
+			content = d.stream(int(c))
+		}
+	case []any:
+		content = d.concatStreams(c)
+	}
+	if len(content) == 0 {
+		return ""
+	}
+	return d.contentText(content, d.pageFonts(p.resources))
+}
+
+func (d *document) concatStreams(refs []any) []byte {
+	var buf bytes.Buffer
+	for _, item := range refs {
+		if ref, ok := item.(pdfRef); ok {
+			buf.Write(d.stream(int(ref)))
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+// pageFonts 页面字体资源名 -> ToUnicode 映射（字体没有映射时为 nil）
+func (d *document) pageFonts(resources map[string]any) map[string]*cmap {
+	fonts := make(map[string]*cmap)
+	fontDict, _ := d.resolve(resources["Font"]).(map[string]any)
+	for name, ref := range fontDict {
+		font, ok := d.resolve(ref).(map[string]any)
+		if !ok {
+			continue
+		}
+		if tu, ok := font["ToUnicode"].(pdfRef); ok {
+			fonts[name] = d.toUnicode(int(tu))
+		} else {
+			fonts[name] = nil
+		}
+	}
+	return fonts
+}
+
+func (d *document) toUnicode(num int) *cmap {
+	if c, ok := d.cmaps[num]; ok {
+		return c
+	}
+	c := parseCMap(d.stream(num))
+	d.cmaps[num] = c
+	return c
+}
+
+// ===== 内容流 =====
+
+// textWriter 按行累积文本
+type textWriter struct {
+	sb      strings.Builder
+	hasText bool // 当前行是否已有文字
+}
+
+func (w *textWriter) newline() {
+	if w.hasText {
+		w.sb.WriteByte('\n')
+		w.hasText = false
+	}
+}
+
+func (w *textWriter) write(s string) {
+	if s == "" {
+		return
+	}
+	w.sb.WriteString(s)
+	if strings.TrimSpace(s) != "" {
+		w.hasText = true
+	}
+}
+
+// contentText 解析内容流中的文本操作符
+func (d *document) contentText(content []byte, fonts map[string]*cmap) string {
+	var w textWriter
+	var operands []any
+	var font *cmap
+	lastY, hasY := 0.0, false
+	l := newLexer(content)
+	for {
+		v, ok := l.object()
+		if !ok {
+			break
+		}
+		op, isOp := v.(keyword)
+		if !isOp {
+			operands = append(operands, v)
+			continue
+		}
+		switch op {
+		case "Tf":
+			if len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = fonts[string(name)]
+				}
+			}
+		case "Tj":
+			if s, ok := lastString(operands); ok {
+				w.write(font.decode(s))
+			}
+		case "'":
+			w.newline()
+			if s, ok := lastString(operands); ok {
+				w.write(font.decode(s))
+			}
+		case "\"":
+			w.newline()
+			if s, ok := lastString(operands); ok {
+				w.write(font.decode(s))
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				arr, _ := operands[len(operands)-1].([]any)
+				for _, item := range arr {
+					switch x := item.(type) {
+					case []byte:
+						w.write(font.decode(x))
+					case float64:
+						// 较大的负间距通常是词间空格
+						if x < -250 {
+							w.write(" ")
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[1].(float64); ok && ty != 0 {
+					w.newline()
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				if y, ok := operands[5].(float64); ok {
+					if hasY && y != lastY {
+						w.newline()
+					}
+					lastY, hasY = y, true
+				}
+			}
+		case "T*":
+			w.newline()
+		case "BI":
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+	return cleanText(w.sb.String())
+}
+
+func lastString(operands []any) ([]byte, bool) {
+	if len(operands) == 0 {
+		return nil, false
+	}
+	s, ok := operands[len(operands)-1].([]byte)
+	return s, ok
+}
+
+// cleanText 合并多余空白并去掉空行
+func cleanText(text string) string {
+	lines := strings.Split(text, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		line = strings.TrimSpace(whitespacePattern.ReplaceAllString(line, " "))
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// ===== ToUnicode 映射 =====
+
+// cmap 字符编码 -> Unicode 文本
+type cmap struct {
+	codeLen int // 编码字节数，1 或 2
+	m       map[string]string
+	sets    int // 累计写入次数，见 maxCMapSets
+}
+
+// parseCMap 解析 ToUnicode CMap 的 bfchar / bfrange 段
+func parseCMap(data []byte) *cmap {
+	c := &cmap{m: make(map[string]string)}
+	if len(data) == 0 {
+		return c
+	}
+	l := newLexer(data)
+	var operands []any
+	mode := ""
+	for {
+		v, ok := l.object()
+		if !ok {
+			break
+		}
+		op, isOp := v.(keyword)
+		if !isOp {
+			if mode != "" {
+				operands = append(operands, v)
+			}
+			continue
+		}
+		switch op {
+		case "beginbfchar", "beginbfrange", "begincodespacerange":
+			mode, operands = string(op), operands[:0]
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if lo, ok := operands[0].([]byte); ok && c.codeLen == 0 {
+					c.codeLen = len(lo)
+				}
+			}
+			mode = ""
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].([]byte)
+				dst, ok2 := operands[i+1].([]byte)
+				if ok1 && ok2 {
+					c.set(src, utf16Text(dst))
+				}
+			}
+			mode = ""
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].([]byte)
+				hi, ok2 := operands[i+1].([]byte)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 {
+					continue
+				}
+				c.setRange(lo, hi, operands[i+2])
+			}
+			mode = ""
+		}
+	}
+	if c.codeLen == 0 {
+		c.codeLen = 2
+	}
+	return c
+}
+
+func (c *cmap) set(src []byte, dst string) {
+	if c.sets >= maxCMapSets {
+		return
+	}
+	c.sets++
+	c.m[string(src)] = dst
+	if c.codeLen == 0 {
+		c.codeLen = len(src)
+	}
+}
+
+// setRange 连续编码映射到连续字符，或逐个映射到数组中的目标
+func (c *cmap) setRange(lo, hi []byte, dst any) {
+	start, end := bytesToInt(lo), bytesToInt(hi)
+	if end < start || end-start > 0xFFFF {
+		return
+	}
+	for code := start; code <= end && c.sets < maxCMapSets; code++ {
+		src := intToBytes(code, len(lo))
+		switch t := dst.(type) {
+		case []byte:
+			runes := []rune(utf16Text(t))
+			if len(runes) == 0 {
+				continue
+			}
+			runes[len(runes)-1] += rune(code - start)
+			c.set(src, string(runes))
+		case []any:
+			if idx := code - start; idx < len(t) {
+				if b, ok := t[idx].([]byte); ok {
+					c.set(src, utf16Text(b))
+				}
+			}
+		}
+	}
+}
+
+// decode 按映射解码字符串，没有映射时按单字节字符处理
+func (c *cmap) decode(s []byte) string {
+	if c == nil || len(c.m) == 0 {
+		if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+			return utf16Text(s[2:])
+		}
+		var sb strings.Builder
+		for _, b := range s {
+			if b >= 0x20 && b != 0x7F {
+				sb.WriteRune(rune(b))
+			}
+		}
+		return sb.String()
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		n := min(c.codeLen, len(s)-i)
+		if text, ok := c.m[string(s[i:i+n])]; ok {
+			sb.WriteString(text)
+			i += n
+			continue
+		}
+		// 变长编码：尝试单字节
+		if n > 1 {
+			if text, ok := c.m[string(s[i:i+1])]; ok {
+				sb.WriteString(text)
+				i++
+				continue
+			}
+		}
+		i += n
+	}
+	return sb.String()
+}
+
+func utf16Text(b []byte) string {
+	if len(b)%2 == 1 {
+		b = append([]byte{0}, b...)
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+func bytesToInt(b []byte) int {
+	n := 0
+	for _, x := range b {
+		n = n<<8 | int(x)
+	}
+	return n
+}
+
+func intToBytes(n, size int) []byte {
+	b := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		b[i] = byte(n)
+		n >>= 8
+	}
+	return b
+}
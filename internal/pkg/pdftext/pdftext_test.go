@@ -0,0 +1,229 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildPDF 按顺序拼装对象（对象号从 1 开始），stream 非空的对象追加流数据；不写 xref，解析不依赖它
+func buildPDF(objects []string, streams map[int][]byte, trailer string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	for i, obj := range objects {
+		num := i + 1
+		fmt.Fprintf(&buf, "%d 0 obj\n", num)
+		if data, ok := streams[num]; ok {
+			fmt.Fprintf(&buf, "%s\nstream\n", fmt.Sprintf(obj, len(data)))
+			buf.Write(data)
+			buf.WriteString("\nendstream\n")
+		} else {
+			buf.WriteString(obj + "\n")
+		}
+		buf.WriteString("endobj\n")
+	}
+	fmt.Fprintf(&buf, "trailer\n%s\n%%%%EOF\n", trailer)
+	return buf.Bytes()
+}
+
+func deflate(s string) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestExtract(t *testing.T) {
+	content := `BT /F1 12 Tf 1 0 0 1 50 700 Tm <00010002> Tj
+1 0 0 1 50 680 Tm [<0003> -300 <0001>] TJ
+/F2 10 Tf 0 -20 Td (Hello \(PDF\)) Tj ET
+BI /W 1 /H 1 ID ` + "\x00BT\xff" + ` EI
+BT /F2 10 Tf 50 600 Td (end) Tj ET`
+	cmapData := `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar
+<0001> <516C>
+<0002> <544A>
+endbfchar
+1 beginbfrange
+<0003> <0004> <8D22>
+endbfrange
+endcmap`
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 4 0 R /F2 6 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents [5 0 R] >>",
+		"<< /Type /Font /Subtype /Type0 /Encoding /Identity-H /ToUnicode 7 0 R >>",
+		"<< /Length %d /Filter /FlateDecode >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Length %d >>",
+	}, map[int][]byte{5: deflate(content), 7: []byte(cmapData)}, "<< /Root 1 0 R /Size 8 >>")
+
+	text, err := Extract(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 跨字体的编码各自映射；内联图片数据不参与解析
+	want := "公告\n财 公\nHello (PDF)\nend"
+	if text != want {
+		t.Errorf("text = %q, want %q", text, want)
+	}
+}
+
+func TestExtractObjectStream(t *testing.T) {
+	// PDF 1.5 对象流：目录、页面树与页面存放在压缩的 ObjStm 中
+	var header, objs string
+	for i, obj := range []string{
+		"<< /Type /Catalog /Pages 11 0 R >>",
+		"<< /Type /Pages /Kids [12 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 11 0 R /Contents 2 0 R >>",
+	} {
+		header += fmt.Sprintf("%d %d ", 10+i, len(objs))
+		objs += obj + " "
+	}
+	objStm := deflate(header + objs)
+	data := buildPDF([]string{
+		fmt.Sprintf("<< /Type /ObjStm /N 3 /First %d /Length %%d /Filter /FlateDecode >>", len(header)),
+		"<< /Length %d /Filter [/FlateDecode] >>",
+	}, map[int][]byte{1: objStm, 2: deflate("BT 10 10 Td (\\345) Tj T* (line2) ' ET")}, "<< /Root 10 0 R >>")
+
+	text, err := Extract(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "å\nline2" {
+		t.Errorf("text = %q", text)
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	if _, err := Extract([]byte("<html></html>")); err == nil {
+		t.Error("non-pdf should fail")
+	}
+	encrypted := buildPDF([]string{"<< /Type /Catalog >>"}, nil, "<< /Root 1 0 R /Encrypt 2 0 R >>")
+	if _, err := Extract(encrypted); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("encrypted err = %v", err)
+	}
+	// 只有图片的扫描件
+	scanned := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		"<< /Length %d >>",
+	}, map[int][]byte{4: []byte("q 100 0 0 100 0 0 cm /Im1 Do Q")}, "<< /Root 1 0 R >>")
+	if _, err := Extract(scanned); !errors.Is(err, ErrNoText) {
+		t.Errorf("scanned err = %v", err)
+	}
+}
+
+func TestExtractStreamLimits(t *testing.T) {
+	// 解压后超过上限的流整体丢弃；缺少校验尾的流仍可使用
+	bomb := deflate("BT (bomb) Tj ET" + strings.Repeat(" ", maxStreamSize))
+	noTail := deflate("BT (tail) Tj ET")
+	noTail = noTail[:len(noTail)-4]
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents [4 0 R 5 0 R 6 0 R] >>",
+		"<< /Length %d /Filter /FlateDecode >>",
+		"<< /Length %d /Filter /FlateDecode >>",
+		"<< /Length %d /Filter /FlateDecode >>",
+	}, map[int][]byte{4: bomb, 5: noTail, 6: deflate("BT (ok) Tj ET")}, "<< /Root 1 0 R >>")
+
+	text, err := Extract(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "tailok" {
+		t.Errorf("text = %q", text)
+	}
+	if strings.Contains(text, "bomb") {
+		t.Error("oversized stream should be dropped")
+	}
+}
+
+func TestExtractDocumentLimits(t *testing.T) {
+	// 多页重复引用同一个大流：累计解压量超过上限后不再解压
+	big := deflate("BT (p) Tj ET" + strings.Repeat(" ", maxStreamSize-64))
+	objects := []string{"<< /Type /Catalog /Pages 2 0 R >>", ""}
+	var kids []string
+	for i := 0; i < 8; i++ {
+		kids = append(kids, fmt.Sprintf("%d 0 R", len(objects)+1))
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>", 11))
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count 8 >>", strings.Join(kids, " "))
+	objects = append(objects, "<< /Length %d /Filter /FlateDecode >>")
+	text, err := Extract(buildPDF(objects, map[int][]byte{11: big}, "<< /Root 1 0 R >>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(text, "p"); n != maxDecodedSize/maxStreamSize {
+		t.Errorf("pages decoded = %d, want %d", n, maxDecodedSize/maxStreamSize)
+	}
+
+	// ToUnicode 声明大量编码区间时写入次数受限
+	var cmapData strings.Builder
+	cmapData.WriteString("1000 beginbfrange\n")
+	for i := 0; i < 1000; i++ {
+		cmapData.WriteString("<00000000> <0000FFFF> <4E00>\n")
+	}
+	cmapData.WriteString("endbfrange")
+	if c := parseCMap([]byte(cmapData.String())); c.sets != maxCMapSets || len(c.m) > maxCMapSets {
+		t.Errorf("cmap sets = %d, entries = %d", c.sets, len(c.m))
+	}
+}
+
+// 畸形输入不应导致栈溢出或长时间占用 CPU
+func TestExtractMalformed(t *testing.T) {
+	for name, body := range map[string]string{
+		"deep nesting":     strings.Repeat("[<<", 1<<19),
+		"unpaired":         strings.Repeat("]>)}", 1<<19),
+		"headers no end":   strings.Repeat("1 0 obj << /Type /Page ", 1<<16),
+		"streams no end":   strings.Repeat("1 0 obj << >> stream\n", 1<<16) + "endobj",
+		"objstm no offset": "1 0 obj << /Type /ObjStm /First 4 >> stream\n5 x 6 0 << >>\nendstream endobj",
+	} {
+		t.Run(name, func(t *testing.T) {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				Extract([]byte("%PDF-1.7\n" + body))
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("extract did not finish")
+			}
+		})
+	}
+}
+
+func FuzzExtract(f *testing.F) {
+	f.Add(buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		"<< /Length %d /Filter /FlateDecode >>",
+		"<< /Type /Font /Subtype /Type0 /ToUnicode 6 0 R >>",
+		"<< /Length %d >>",
+	}, map[int][]byte{
+		4: deflate("BT /F1 12 Tf 1 0 0 1 50 700 Tm <00010002> Tj [<0003> -300 (x)] TJ T* (y) ' ET"),
+		6: []byte("1 begincodespacerange <0000> <FFFF> endcodespacerange 1 beginbfchar <0001> <516C> endbfchar 1 beginbfrange <0002> <0003> [<544A> <8D22>] endbfrange"),
+	}, "<< /Root 1 0 R >>"))
+	f.Add(buildPDF([]string{
+		"<< /Type /ObjStm /N 1 /First 5 /Length %d >>",
+	}, map[int][]byte{1: []byte("10 0 << /Type /Page /Contents 1 0 R >>")}, "<< /Root 10 0 R >>"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj << /Type /Page /Contents 2 0 R >> endobj 2 0 obj << /Length 9 >> stream\nBT (a) Tj\nendstream endobj"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		text, err := Extract(data)
+		if err == nil && text == "" {
+			t.Error("empty text without error")
+		}
+	})
+}
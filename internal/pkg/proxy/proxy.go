@@ -62,6 +62,14 @@ func (m *Manager) GetTransport() *http.Transport {
 	return m.transport.Clone()
 }
 
+// SharedTransport 获取共享的 Transport，调用方不得修改其字段
+// 同一源站的请求共用连接池：模型客户端与连接预热使用同一实例，预热建立的连接才能被复用
+func (m *Manager) SharedTransport() *http.Transport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.transport
+}
+
 // GetClient 获取配置好代理的 HTTP Client
 func (m *Manager) GetClient() *http.Client {
 	m.mu.RLock()
@@ -79,6 +87,10 @@ func (m *Manager) GetClientWithTimeout(timeout time.Duration) *http.Client {
 
 // rebuildTransport 根据当前配置重建 Transport
 func (m *Manager) rebuildTransport() {
+	// 代理变化后旧连接不再复用，已在使用旧 Transport 的请求不受影响
+	if m.transport != nil {
+		m.transport.CloseIdleConnections()
+	}
 	m.transport = &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   30 * time.Second,
@@ -96,12 +108,12 @@ func (m *Manager) rebuildTransport() {
 		m.transport.Proxy = nil
 
 	case models.ProxyModeSystem:
-		m.transport.Proxy = m.systemProxyFunc
+		m.transport.Proxy = withProxyAuth(m.systemProxyFunc, m.config.ProxyUsername, m.config.ProxyPassword)
 
 	case models.ProxyModeCustom:
 		if m.config.CustomURL != "" {
 			if proxyURL, err := url.Parse(m.config.CustomURL); err == nil {
-				m.transport.Proxy = http.ProxyURL(proxyURL)
+				m.transport.Proxy = withProxyAuth(http.ProxyURL(proxyURL), m.config.ProxyUsername, m.config.ProxyPassword)
 			}
 		}
 	}
@@ -112,6 +124,23 @@ func (m *Manager) rebuildTransport() {
 	}
 }
 
+// withProxyAuth 为代理地址附加 Basic 认证信息，Transport 会据此在 CONNECT 及明文 HTTP 代理请求上
+// 携带 Proxy-Authorization 头；未配置认证或代理地址已自带认证信息时保持原样
+func withProxyAuth(proxy func(*http.Request) (*url.URL, error), username, password string) func(*http.Request) (*url.URL, error) {
+	if username == "" && password == "" {
+		return proxy
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := proxy(req)
+		if proxyURL == nil || err != nil || proxyURL.User != nil {
+			return proxyURL, err
+		}
+		authed := *proxyURL
+		authed.User = url.UserPassword(username, password)
+		return &authed, nil
+	}
+}
+
 // systemProxyFunc 获取系统代理（作为 Transport.Proxy 函数）
 func (m *Manager) systemProxyFunc(req *http.Request) (*url.URL, error) {
 	// 优先使用环境变量
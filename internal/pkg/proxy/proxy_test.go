@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// authProxy 测试用 HTTP 代理：校验 Proxy-Authorization 后转发明文请求或建立 CONNECT 隧道
+type authProxy struct {
+	want string
+	mu   sync.Mutex
+	seen []string // 收到的请求方法
+}
+
+func (p *authProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.seen = append(p.seen, r.Method)
+	p.mu.Unlock()
+	if r.Header.Get("Proxy-Authorization") != p.want {
+		w.Header().Set("Proxy-Authenticate", `Basic realm="test"`)
+		w.WriteHeader(http.StatusProxyAuthRequired)
+		return
+	}
+	if r.Method == http.MethodConnect {
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		go func() {
+			io.Copy(upstream, buf)
+			upstream.Close()
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+		return
+	}
+	resp, err := http.DefaultTransport.RoundTrip(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+func TestProxyBasicAuth(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer target.Close()
+	tlsTarget := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer tlsTarget.Close()
+
+	fixture := &authProxy{want: "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:s3cret"))}
+	proxySrv := httptest.NewServer(fixture)
+	defer proxySrv.Close()
+
+	get := func(cfg *models.ProxyConfig, url string) int {
+		m := &Manager{config: cfg}
+		m.rebuildTransport()
+		transport := m.GetTransport()
+		transport.TLSClientConfig = tlsTarget.Client().Transport.(*http.Transport).TLSClientConfig
+		resp, err := (&http.Client{Transport: transport}).Get(url)
+		if err != nil {
+			// CONNECT 被拒时 Transport 直接返回错误
+			return http.StatusProxyAuthRequired
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	authed := &models.ProxyConfig{Mode: models.ProxyModeCustom, CustomURL: proxySrv.URL, ProxyUsername: "alice", ProxyPassword: "s3cret"}
+	if code := get(authed, target.URL); code != http.StatusOK {
+		t.Errorf("http via authed proxy = %d", code)
+	}
+	if code := get(authed, tlsTarget.URL); code != http.StatusOK {
+		t.Errorf("https (CONNECT) via authed proxy = %d", code)
+	}
+
+	anonymous := &models.ProxyConfig{Mode: models.ProxyModeCustom, CustomURL: proxySrv.URL}
+	if code := get(anonymous, target.URL); code != http.StatusProxyAuthRequired {
+		t.Errorf("http without credentials = %d", code)
+	}
+	if code := get(anonymous, tlsTarget.URL); code != http.StatusProxyAuthRequired {
+		t.Errorf("https without credentials = %d", code)
+	}
+
+	fixture.mu.Lock()
+	defer fixture.mu.Unlock()
+	connects := 0
+	for _, method := range fixture.seen {
+		if method == http.MethodConnect {
+			connects++
+		}
+	}
+	if connects != 2 {
+		t.Errorf("proxy saw %v, want 2 CONNECT requests", fixture.seen)
+	}
+}
+
+func TestWithProxyAuth_KeepsURLCredentials(t *testing.T) {
+	m := &Manager{config: &models.ProxyConfig{
+		Mode:          models.ProxyModeCustom,
+		CustomURL:     "http://bob:pw@127.0.0.1:8080",
+		ProxyUsername: "alice",
+		ProxyPassword: "s3cret",
+	}}
+	m.rebuildTransport()
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	proxyURL, err := m.GetTransport().Proxy(req)
+	if err != nil || proxyURL.User.String() != "bob:pw" {
+		t.Errorf("proxy url = %v, err = %v", proxyURL, err)
+	}
+}
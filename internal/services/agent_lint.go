@@ -0,0 +1,127 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 专家配置检查级别
+const (
+	LintError   = "error"   // 无法保存
+	LintWarning = "warning" // 可以保存，但行为可能与预期不符
+)
+
+// agentToneMaxRunes 语气描述建议长度上限，更长的要求应写入指令
+const agentToneMaxRunes = 60
+
+// AgentLintIssue 专家配置检查问题
+type AgentLintIssue struct {
+	AgentID   string `json:"agentId"`
+	AgentName string `json:"agentName"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+}
+
+// languageDemandPatterns 指令文本中明确要求回复语言的表述，按语言代码归类
+var languageDemandPatterns = []struct {
+	lang     string
+	patterns []*regexp.Regexp
+}{
+	{"zh-TW", []*regexp.Regexp{
+		regexp.MustCompile(`(用|使用|以)繁[体體]中文`),
+		regexp.MustCompile(`(?i)\bin traditional chinese\b`),
+	}},
+	{"zh", []*regexp.Regexp{
+		regexp.MustCompile(`(用|使用|以)(简体)?中文(回答|回复|输出|作答|交流|撰写)`),
+		regexp.MustCompile(`(?i)\b(respond|reply|answer|write|speak|output)\b[^.\n]{0,40}\bin (simplified )?chinese\b`),
+	}},
+	{"en", []*regexp.Regexp{
+		regexp.MustCompile(`(用|使用|以)英[文语](回答|回复|输出|作答|交流|撰写)`),
+		regexp.MustCompile(`(?i)\b(respond|reply|answer|write|speak|output)\b[^.\n]{0,40}\bin english\b`),
+		regexp.MustCompile(`(?i)\benglish only\b`),
+	}},
+	{"ja", []*regexp.Regexp{
+		regexp.MustCompile(`(用|使用|以)日[文语](回答|回复|输出|作答|交流|撰写)`),
+		regexp.MustCompile(`(?i)\b(respond|reply|answer|write|speak|output)\b[^.\n]{0,40}\bin japanese\b`),
+	}},
+}
+
+// detectLanguageDemands 返回文本中明确要求的回复语言
+func detectLanguageDemands(text string) []string {
+	var langs []string
+	for _, d := range languageDemandPatterns {
+		for _, p := range d.patterns {
+			if p.MatchString(text) {
+				langs = append(langs, d.lang)
+				break
+			}
+		}
+	}
+	return langs
+}
+
+// LintStrategyAgents 检查策略专家的输出语言与语气设定，以及与指令文本的冲突
+func LintStrategyAgents(strategy models.Strategy) []AgentLintIssue {
+	var issues []AgentLintIssue
+	for _, a := range strategy.Agents {
+		add := func(level, format string, args ...any) {
+			issues = append(issues, AgentLintIssue{AgentID: a.ID, AgentName: a.Name, Level: level, Message: fmt.Sprintf(format, args...)})
+		}
+
+		lang, ok := models.NormalizeOutputLanguage(a.OutputLanguage)
+		if !ok {
+			add(LintError, "未知的输出语言: %s（支持 zh、zh-TW、en、ja）", a.OutputLanguage)
+			continue
+		}
+
+		for _, demanded := range detectLanguageDemands(a.Instruction) {
+			switch {
+			case lang == "" && demanded != "zh":
+				add(LintWarning, "指令要求使用 %s 回复，但未设置输出语言，会议中可能受中文系统提示影响改用中文；建议将输出语言设为 %s", models.OutputLanguageName(demanded), demanded)
+			case lang != "" && demanded != lang:
+				add(LintWarning, "指令要求使用 %s 回复，与输出语言 %s 冲突，将以输出语言为准", models.OutputLanguageName(demanded), models.OutputLanguageName(lang))
+			}
+		}
+
+		tone := strings.TrimSpace(a.Tone)
+		for _, demanded := range detectLanguageDemands(tone) {
+			if demanded != lang {
+				add(LintWarning, "语气中包含语言要求（%s），请改用输出语言设置", models.OutputLanguageName(demanded))
+			}
+		}
+		if n := len([]rune(tone)); n > agentToneMaxRunes {
+			add(LintWarning, "语气描述过长（%d 字），详细要求建议写入指令", n)
+		}
+	}
+	return issues
+}
+
+// normalizeAgentOutputs 归一化策略中全部专家的输出设定，不修改调用方的专家切片
+func normalizeAgentOutputs(strategy *models.Strategy) error {
+	if strategy.Agents == nil {
+		return nil
+	}
+	agents := make([]models.StrategyAgent, len(strategy.Agents))
+	for i, a := range strategy.Agents {
+		if err := normalizeAgentOutput(&a); err != nil {
+			return err
+		}
+		agents[i] = a
+	}
+	strategy.Agents = agents
+	return nil
+}
+
+// normalizeAgentOutput 归一化输出语言代码并去掉语气首尾空白，未知语言返回错误
+func normalizeAgentOutput(agent *models.StrategyAgent) error {
+	lang, ok := models.NormalizeOutputLanguage(agent.OutputLanguage)
+	if !ok {
+		return fmt.Errorf("专家「%s」未知的输出语言: %s", agent.Name, agent.OutputLanguage)
+	}
+	agent.OutputLanguage = lang
+	agent.Tone = strings.TrimSpace(agent.Tone)
+	return nil
+}
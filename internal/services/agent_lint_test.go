@@ -0,0 +1,87 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestLintStrategyAgents(t *testing.T) {
+	strategy := models.Strategy{Agents: []models.StrategyAgent{
+		{ID: "a", Name: "英文无锁", Instruction: "You are a macro analyst. Always respond in English."},
+		{ID: "b", Name: "冲突", Instruction: "你是技术分析师，请用中文回答。", OutputLanguage: "English"},
+		{ID: "c", Name: "未知语言", OutputLanguage: "klingon"},
+		{ID: "d", Name: "语气", OutputLanguage: "zh", Tone: "用英文回复，" + strings.Repeat("很", agentToneMaxRunes)},
+		{ID: "e", Name: "一致", Instruction: "Reply in English only.", OutputLanguage: "en", Tone: "concise"},
+		{ID: "f", Name: "中文默认", Instruction: "请使用中文回复。"},
+	}}
+
+	got := map[string][]AgentLintIssue{}
+	for _, issue := range LintStrategyAgents(strategy) {
+		got[issue.AgentID] = append(got[issue.AgentID], issue)
+	}
+	if len(got["a"]) != 1 || got["a"][0].Level != LintWarning || !strings.Contains(got["a"][0].Message, "建议将输出语言设为 en") {
+		t.Errorf("a = %+v", got["a"])
+	}
+	if len(got["b"]) != 1 || !strings.Contains(got["b"][0].Message, "将以输出语言为准") {
+		t.Errorf("b = %+v", got["b"])
+	}
+	if len(got["c"]) != 1 || got["c"][0].Level != LintError {
+		t.Errorf("c = %+v", got["c"])
+	}
+	if len(got["d"]) != 2 {
+		t.Errorf("d = %+v", got["d"])
+	}
+	if len(got["e"]) != 0 || len(got["f"]) != 0 {
+		t.Errorf("unexpected issues: e=%+v f=%+v", got["e"], got["f"])
+	}
+}
+
+func TestAddStrategyNormalizesOutputLanguage(t *testing.T) {
+	s := NewStrategyService(t.TempDir())
+	st := models.Strategy{ID: "lang", Name: "语言", Agents: []models.StrategyAgent{
+		{ID: "a", Name: "Alex", OutputLanguage: "English", Tone: "  concise  "},
+	}}
+	if err := s.AddStrategy(st); err != nil {
+		t.Fatal(err)
+	}
+	var saved models.StrategyAgent
+	for _, item := range s.GetAllStrategies() {
+		if item.ID == "lang" {
+			saved = item.Agents[0]
+		}
+	}
+	if saved.OutputLanguage != "en" || saved.Tone != "concise" {
+		t.Errorf("saved = %+v", saved)
+	}
+	// 调用方的切片不被修改
+	if st.Agents[0].OutputLanguage != "English" {
+		t.Errorf("caller strategy mutated: %+v", st.Agents[0])
+	}
+
+	st.ID = "bad"
+	st.Agents = []models.StrategyAgent{{ID: "b", Name: "Bob", OutputLanguage: "klingon"}}
+	if err := s.AddStrategy(st); err == nil || !strings.Contains(err.Error(), "未知的输出语言") {
+		t.Errorf("unknown language err = %v", err)
+	}
+}
+
+func TestParseGenerateResponseOutputLanguage(t *testing.T) {
+	s := NewStrategyService(t.TempDir())
+	resp := `{"strategy":{"name":"出海","agents":[
+		{"name":"Alex","instruction":"You are...","outputLanguage":"English","tone":"formal"},
+		{"name":"老王","outputLanguage":"火星文"}
+	]},"reasoning":"r"}`
+	result, err := s.parseGenerateResponse(resp, "海外视角")
+	if err != nil {
+		t.Fatal(err)
+	}
+	agents := result.Strategy.Agents
+	if agents[0].OutputLanguage != "en" || agents[0].Tone != "formal" || agents[1].OutputLanguage != "" {
+		t.Errorf("agents = %+v", agents)
+	}
+	if !strings.Contains(s.getOutputTemplate(), `"outputLanguage"`) || !strings.Contains(s.getOutputTemplate(), `"tone"`) {
+		t.Error("output template should include outputLanguage and tone")
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// EventAlertTriggered 价格提醒触发事件，载荷为 models.PriceAlert
+const EventAlertTriggered = "alert:triggered"
+
+const (
+	// volumeWindow 计算放量时参考的最近成交量增量个数
+	volumeWindow = 20
+	// volumeMinSamples 样本不足时不判断放量，避免开盘初期误报
+	volumeMinSamples = 5
+)
+
+// AlertTriggeredListener 提醒新触发后的回调
+type AlertTriggeredListener func(alert models.PriceAlert)
+
+// AlertService 价格提醒服务，由 MarketDataPusher 每轮推送时检查
+type AlertService struct {
+	configService *ConfigService
+
+	mu       sync.Mutex
+	volumes  map[string]*volumeTracker // 股票代码 -> 成交量增量记录
+	listener AlertTriggeredListener
+}
+
+// NewAlertService 创建价格提醒服务
+func NewAlertService(configService *ConfigService) *AlertService {
+	return &AlertService{
+		configService: configService,
+		volumes:       make(map[string]*volumeTracker),
+	}
+}
+
+// SetTriggeredListener 设置提醒触发回调（每条新触发的提醒调用一次）
+func (s *AlertService) SetTriggeredListener(listener AlertTriggeredListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listener = listener
+}
+
+// ActiveSymbols 未触发提醒涉及的股票代码（去重）
+func (s *AlertService) ActiveSymbols() []string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, a := range s.configService.GetAlerts() {
+		if a.Triggered || seen[a.Symbol] {
+			continue
+		}
+		seen[a.Symbol] = true
+		codes = append(codes, a.Symbol)
+	}
+	return codes
+}
+
+// Check 用最新行情检查未触发的提醒，返回本轮新触发的提醒（已持久化为 Triggered）
+// 提醒只触发一次，同一行情多次推送不会重复通知
+func (s *AlertService) Check(stocks []models.Stock) ([]models.PriceAlert, error) {
+	quotes := make(map[string]models.Stock, len(stocks))
+	for _, st := range stocks {
+		quotes[models.NormalizeSymbol(st.Symbol)] = st
+	}
+	ratios := s.observeVolumes(quotes)
+
+	hits := make(map[string]alertHit)
+	for _, a := range s.configService.GetAlerts() {
+		if a.Triggered {
+			continue
+		}
+		stock, ok := quotes[a.Symbol]
+		if !ok {
+			continue
+		}
+		if value, ok := a.Match(stock, ratios[a.Symbol]); ok {
+			hits[a.ID] = alertHit{price: stock.Price, value: value}
+		}
+	}
+	if len(hits) == 0 {
+		return nil, nil
+	}
+	triggered, err := s.configService.markAlertsTriggered(hits)
+
+	s.mu.Lock()
+	listener := s.listener
+	s.mu.Unlock()
+	if listener != nil {
+		for _, a := range triggered {
+			listener(a)
+		}
+	}
+	return triggered, err
+}
+
+// observeVolumes 记录本轮成交量并返回各股票的量比（本轮增量 / 近期平均增量）
+func (s *AlertService) observeVolumes(quotes map[string]models.Stock) map[string]float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ratios := make(map[string]float64, len(quotes))
+	for code, st := range quotes {
+		if st.Price <= 0 {
+			continue
+		}
+		tracker := s.volumes[code]
+		if tracker == nil {
+			tracker = &volumeTracker{}
+			s.volumes[code] = tracker
+		}
+		ratios[code] = tracker.observe(st.Volume)
+	}
+	return ratios
+}
+
+// volumeTracker 单只股票的成交量增量记录
+type volumeTracker struct {
+	last   int64
+	deltas []int64
+}
+
+// observe 记录累计成交量，返回本轮增量相对此前平均增量的倍数，样本不足时返回 0
+func (t *volumeTracker) observe(volume int64) float64 {
+	if volume < t.last || t.last == 0 {
+		// 首次观测或累计量回落（新交易日），重新开始统计
+		t.last = volume
+		t.deltas = t.deltas[:0]
+		return 0
+	}
+	delta := volume - t.last
+	t.last = volume
+	if delta == 0 {
+		// 行情未更新（午休、停牌或数据源未刷新）不计入样本
+		return 0
+	}
+
+	var ratio float64
+	if len(t.deltas) >= volumeMinSamples {
+		var sum int64
+		for _, d := range t.deltas {
+			sum += d
+		}
+		if avg := float64(sum) / float64(len(t.deltas)); avg > 0 {
+			ratio = float64(delta) / avg
+		}
+	}
+	t.deltas = append(t.deltas, delta)
+	if len(t.deltas) > volumeWindow {
+		t.deltas = t.deltas[len(t.deltas)-volumeWindow:]
+	}
+	return ratio
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestAlertService(t *testing.T) {
+	dir := t.TempDir()
+	cs, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	below, err := cs.AddAlert(models.PriceAlert{Symbol: "600519.SH", Condition: models.AlertBelow, Threshold: 1800})
+	if err != nil || below.ID == "" || below.Symbol != "sh600519" {
+		t.Fatalf("add alert = %+v, err = %v", below, err)
+	}
+	above, _ := cs.AddAlert(models.PriceAlert{Symbol: "sz000001", Condition: models.AlertAbove, Threshold: 12})
+	if _, err := cs.AddAlert(models.PriceAlert{Symbol: "sz000001", Condition: "cross", Threshold: 12}); err == nil {
+		t.Error("expected invalid condition error")
+	}
+
+	svc := NewAlertService(cs)
+	if got := svc.ActiveSymbols(); len(got) != 2 {
+		t.Errorf("active symbols = %v", got)
+	}
+
+	// 未满足条件、停牌（价格为 0）均不触发
+	if got, _ := svc.Check([]models.Stock{{Symbol: "sh600519", Price: 1850}, {Symbol: "sz000001", Price: 0}}); len(got) != 0 {
+		t.Fatalf("unexpected trigger: %+v", got)
+	}
+
+	got, err := svc.Check([]models.Stock{{Symbol: "sh600519", Price: 1795.5}, {Symbol: "sz000001", Price: 11}})
+	if err != nil || len(got) != 1 || got[0].ID != below.ID || !got[0].Triggered || got[0].TriggerPrice != 1795.5 {
+		t.Fatalf("triggered = %+v, err = %v", got, err)
+	}
+	// 已触发的提醒不再重复触发
+	if got, _ := svc.Check([]models.Stock{{Symbol: "sh600519", Price: 1700}}); len(got) != 0 {
+		t.Errorf("alert fired twice: %+v", got)
+	}
+	if got := svc.ActiveSymbols(); len(got) != 1 || got[0] != "sz000001" {
+		t.Errorf("active symbols after trigger = %v", got)
+	}
+
+	// 触发状态持久化到 alerts.json
+	reloaded, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alerts := reloaded.GetAlerts()
+	if len(alerts) != 2 || !alerts[0].Triggered || alerts[1].Triggered {
+		t.Errorf("reloaded alerts = %+v", alerts)
+	}
+
+	if err := reloaded.RemoveAlert(above.ID); err != nil || len(reloaded.GetAlerts()) != 1 {
+		t.Errorf("remove alert: err = %v, alerts = %+v", err, reloaded.GetAlerts())
+	}
+}
+
+func TestAlertService_ChangeAndVolume(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	change, err := cs.AddAlert(models.PriceAlert{Symbol: "sh600519", Condition: "change_percent_above", Threshold: 5})
+	if err != nil || change.Condition != models.AlertChangeAbove {
+		t.Fatalf("add change alert = %+v, err = %v", change, err)
+	}
+	spike, _ := cs.AddAlert(models.PriceAlert{Symbol: "sz000001", Condition: models.AlertVolumeSpike, Threshold: 3})
+	if a, err := cs.AddAlert(models.PriceAlert{Symbol: "sz000001", Condition: "price_below", Threshold: 10}); err != nil || a.Condition != models.AlertBelow {
+		t.Errorf("price_below alias = %+v, err = %v", a, err)
+	}
+
+	svc := NewAlertService(cs)
+	var notified []string
+	svc.SetTriggeredListener(func(a models.PriceAlert) { notified = append(notified, a.ID) })
+
+	// 每轮成交量增量 100，样本不足或增量平稳时不算放量
+	volume := int64(1000)
+	for i := 0; i < 7; i++ {
+		volume += 100
+		got, _ := svc.Check([]models.Stock{
+			{Symbol: "sh600519", Price: 1800, ChangePercent: 4.9},
+			{Symbol: "sz000001", Price: 11, Volume: volume},
+		})
+		if len(got) != 0 {
+			t.Fatalf("round %d unexpected trigger: %+v", i, got)
+		}
+	}
+
+	volume += 400
+	got, err := svc.Check([]models.Stock{
+		{Symbol: "sh600519", Price: 1890, ChangePercent: 5.2},
+		{Symbol: "sz000001", Price: 11, Volume: volume},
+	})
+	if err != nil || len(got) != 2 {
+		t.Fatalf("triggered = %+v, err = %v", got, err)
+	}
+	for _, a := range got {
+		switch a.ID {
+		case change.ID:
+			if a.TriggerValue != 5.2 || a.TriggerPrice != 1890 {
+				t.Errorf("change alert = %+v", a)
+			}
+		case spike.ID:
+			if a.TriggerValue != 4 {
+				t.Errorf("volume ratio = %v, want 4", a.TriggerValue)
+			}
+		default:
+			t.Errorf("unexpected alert %+v", a)
+		}
+	}
+	if len(notified) != 2 {
+		t.Errorf("listener notified %v", notified)
+	}
+}
+
+func TestVolumeTracker_Reset(t *testing.T) {
+	var tr volumeTracker
+	for _, v := range []int64{100, 200, 300, 400, 500, 600} {
+		tr.observe(v)
+	}
+	if r := tr.observe(1100); r != 5 {
+		t.Fatalf("ratio = %v, want 5", r)
+	}
+	// 累计量回落视为新交易日，重新积累样本
+	if r := tr.observe(50); r != 0 || len(tr.deltas) != 0 {
+		t.Errorf("after reset ratio = %v, deltas = %v", r, tr.deltas)
+	}
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+	"github.com/run-bigpig/jcp/internal/pkg/pdftext"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 东方财富公告接口
+const (
+	announcementListURL    = "https://np-anotice-stock.eastmoney.com/api/security/ann?sr=-1&page_size=%d&page_index=1&ann_type=A&client_source=web&stock_list=%s&f_node=0&s_node=0"
+	announcementContentURL = "https://np-cnotice-stock.eastmoney.com/api/content/ann?art_code=%s&client_source=web&page_index=%d"
+	announcementDetailURL  = "https://data.eastmoney.com/notices/detail/%s/%s.html"
+)
+
+// 公告参数
+const (
+	AnnouncementDefaultMaxChars = 8000 // 公告正文默认截取字数
+
+	announcementDefaultLimit = 10
+	announcementMaxLimit     = 50
+	announcementListTTL      = 10 * time.Minute
+	announcementMaxPages     = 20       // 正文接口分页上限
+	announcementMaxDownload  = 20 << 20 // 附件下载上限 20MB
+	announcementMaxRedirects = 5
+	announcementCacheSubDir  = "announcements"
+	announcementCacheMaxAge  = 30 * 24 * time.Hour // 正文缓存保留时间
+	announcementCacheMaxSize = 200 << 20           // 正文缓存目录上限 200MB，超出时按修改时间从旧到新删除
+)
+
+// announcementHosts 公告链接允许的域名（含子域名）：东方财富、巨潮资讯与沪深北交易所
+var announcementHosts = []string{"eastmoney.com", "cninfo.com.cn", "sse.com.cn", "szse.cn", "bse.cn"}
+
+// errAnnouncementHost 公告链接不在允许的域名内
+var errAnnouncementHost = errors.New("仅支持东方财富、巨潮资讯与交易所的公告链接")
+
+// artCodePattern 从东方财富公告详情链接中提取公告编号
+var artCodePattern = regexp.MustCompile(`(?i)(?:/notices/detail/\w+/|art_code=)(AN\w+)`)
+
+// Announcement 交易所公告
+type Announcement struct {
+	Title string `json:"title"`
+	Type  string `json:"type"` // 公告类型，如 业绩预告、限售股份上市流通
+	Date  string `json:"date"` // 公告日期 YYYY-MM-DD
+	URL   string `json:"url"`
+}
+
+// AnnouncementContent 公告正文
+type AnnouncementContent struct {
+	URL        string `json:"url"`
+	Source     string `json:"source"` // 正文来源：html / pdf
+	Text       string `json:"text"`
+	TotalChars int    `json:"totalChars"` // 截取前的总字数
+	Truncated  bool   `json:"truncated"`
+}
+
+// announcementListCache 公告列表缓存
+type announcementListCache struct {
+	data      []Announcement
+	timestamp time.Time
+}
+
+// announcementFile 公告正文磁盘缓存，保存完整正文，截取在读取时进行
+type announcementFile struct {
+	URL    string `json:"url"`
+	Source string `json:"source"`
+	Text   string `json:"text"`
+}
+
+// GetAnnouncements 获取个股最近 limit 条交易所公告（按公告日期倒序），结果缓存 10 分钟
+func (s *NewsService) GetAnnouncements(ctx context.Context, code string, limit int) ([]Announcement, error) {
+	codes := normalizeEarningsCodes([]string{code})
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("股票代码不能为空")
+	}
+	if limit <= 0 {
+		limit = announcementDefaultLimit
+	}
+	limit = min(limit, announcementMaxLimit)
+
+	key := fmt.Sprintf("%s:%d", codes[0], limit)
+	s.announcementMu.Lock()
+	if c, ok := s.announcementCache[key]; ok && time.Since(c.timestamp) < announcementListTTL {
+		s.announcementMu.Unlock()
+		return c.data, nil
+	}
+	s.announcementMu.Unlock()
+
+	body, err := s.downloadAnnouncement(ctx, fmt.Sprintf(announcementListURL, limit, codes[0]))
+	if err != nil {
+		return nil, err
+	}
+	list, err := parseAnnouncementList(body, codes[0])
+	if err != nil {
+		return nil, err
+	}
+
+	s.announcementMu.Lock()
+	s.announcementCache[key] = &announcementListCache{data: list, timestamp: time.Now()}
+	s.announcementMu.Unlock()
+	return list, nil
+}
+
+// parseAnnouncementList 解析公告列表接口响应
+func parseAnnouncementList(body []byte, code string) ([]Announcement, error) {
+	var resp struct {
+		Data struct {
+			List []struct {
+				ArtCode    string `json:"art_code"`
+				Title      string `json:"title"`
+				NoticeDate string `json:"notice_date"`
+				Columns    []struct {
+					ColumnName string `json:"column_name"`
+				} `json:"columns"`
+			} `json:"list"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析公告列表失败: %w", err)
+	}
+
+	list := make([]Announcement, 0, len(resp.Data.List))
+	for _, item := range resp.Data.List {
+		if item.ArtCode == "" {
+			continue
+		}
+		types := make([]string, 0, len(item.Columns))
+		for _, c := range item.Columns {
+			if c.ColumnName != "" {
+				types = append(types, c.ColumnName)
+			}
+		}
+		list = append(list, Announcement{
+			Title: strings.TrimSpace(item.Title),
+			Type:  strings.Join(types, "、"),
+			Date:  datePart(item.NoticeDate),
+			URL:   fmt.Sprintf(announcementDetailURL, code, item.ArtCode),
+		})
+	}
+	return list, nil
+}
+
+// GetAnnouncementContent 下载公告并提取纯文本，支持东方财富公告详情页、PDF 与普通网页
+// 完整正文按 URL 哈希缓存到磁盘，maxChars <= 0 时使用默认截取字数
+func (s *NewsService) GetAnnouncementContent(ctx context.Context, rawURL string, maxChars int) (*AnnouncementContent, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("无效的公告链接: %s", rawURL)
+	}
+	if err := s.checkAnnouncementURL(u); err != nil {
+		return nil, err
+	}
+	rawURL = u.String()
+	if maxChars <= 0 {
+		maxChars = AnnouncementDefaultMaxChars
+	}
+
+	cachePath := s.announcementCachePath(rawURL)
+	var file announcementFile
+	if data, err := os.ReadFile(cachePath); err == nil && json.Unmarshal(data, &file) == nil && file.Text != "" {
+		return truncateAnnouncement(&file, maxChars), nil
+	}
+
+	if m := artCodePattern.FindStringSubmatch(rawURL); m != nil {
+		file, err = s.fetchEastmoneyNotice(ctx, strings.ToUpper(m[1]))
+	} else {
+		file, err = s.fetchAnnouncementDocument(ctx, rawURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	file.URL = rawURL
+
+	if data, err := json.Marshal(file); err == nil {
+		if err := os.WriteFile(cachePath, data, 0644); err != nil {
+			log.Warn("写入公告缓存失败: %v", err)
+		}
+		pruneAnnouncementCache(filepath.Dir(cachePath), time.Now(), announcementCacheMaxSize)
+	}
+	return truncateAnnouncement(&file, maxChars), nil
+}
+
+// checkAnnouncementURL 公告链接只允许 http(s) 协议与 announcementHosts 中的域名，防止借公告工具访问内网
+func (s *NewsService) checkAnnouncementURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errAnnouncementHost
+	}
+	if s.announcementAnyHost {
+		return nil
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	for _, allowed := range announcementHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return errAnnouncementHost
+}
+
+// newAnnouncementClient 公告下载客户端：跳转同样校验域名，直连时在建立连接前拒绝内网地址
+func (s *NewsService) newAnnouncementClient() *http.Client {
+	client := proxy.GetManager().GetClientWithTimeout(60 * time.Second)
+	(&URLReader{}).guardTransport(client.Transport.(*http.Transport))
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= announcementMaxRedirects {
+			return fmt.Errorf("公告链接跳转次数过多")
+		}
+		return s.checkAnnouncementURL(req.URL)
+	}
+	return client
+}
+
+// pruneAnnouncementCache 删除超过保留时间的正文缓存，目录总大小超过 maxSize 时再从最旧的开始删除
+func pruneAnnouncementCache(dir string, now time.Time, maxSize int64) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cacheFile
+	var total int64
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || info.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if now.Sub(info.ModTime()) > announcementCacheMaxAge {
+			if err := os.Remove(path); err != nil {
+				log.Warn("清理公告缓存失败: %v", err)
+			}
+			continue
+		}
+		files = append(files, cacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= maxSize {
+			break
+		}
+		if err := os.Remove(f.path); err != nil {
+			log.Warn("清理公告缓存失败: %v", err)
+			continue
+		}
+		total -= f.size
+	}
+}
+
+// announcementCachePath 公告正文缓存文件路径（URL 的 SHA-256）
+func (s *NewsService) announcementCachePath(rawURL string) string {
+	dir := s.announcementDir
+	if dir == "" {
+		dir = paths.EnsureCacheDir(announcementCacheSubDir)
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// fetchEastmoneyNotice 通过正文接口获取公告文本，接口无正文时下载 PDF 附件
+func (s *NewsService) fetchEastmoneyNotice(ctx context.Context, artCode string) (announcementFile, error) {
+	var sb strings.Builder
+	attachURL := ""
+	for page := 1; page <= announcementMaxPages; page++ {
+		body, err := s.downloadAnnouncement(ctx, fmt.Sprintf(announcementContentURL, artCode, page))
+		if err != nil {
+			return announcementFile{}, err
+		}
+		var resp struct {
+			Data struct {
+				AttachURL     string `json:"attach_url"`
+				NoticeContent string `json:"notice_content"`
+				PageSize      int    `json:"page_size"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return announcementFile{}, fmt.Errorf("解析公告正文失败: %w", err)
+		}
+		attachURL = resp.Data.AttachURL
+		sb.WriteString(resp.Data.NoticeContent)
+		if page >= resp.Data.PageSize {
+			break
+		}
+	}
+
+	if text := cleanAnnouncementText(sb.String()); text != "" {
+		return announcementFile{Source: "html", Text: text}, nil
+	}
+	if attachURL == "" {
+		return announcementFile{}, fmt.Errorf("公告 %s 没有正文", artCode)
+	}
+	return s.fetchAnnouncementDocument(ctx, attachURL)
+}
+
+// fetchAnnouncementDocument 下载公告文件，按内容识别 PDF 或 HTML 并提取文本
+func (s *NewsService) fetchAnnouncementDocument(ctx context.Context, rawURL string) (announcementFile, error) {
+	body, err := s.downloadAnnouncement(ctx, rawURL)
+	if err != nil {
+		return announcementFile{}, err
+	}
+	if bytes.HasPrefix(bytes.TrimLeft(body, " \r\n\t"), []byte("%PDF")) {
+		text, err := pdftext.Extract(body)
+		if err != nil {
+			return announcementFile{}, fmt.Errorf("解析公告 PDF 失败: %w", err)
+		}
+		return announcementFile{Source: "pdf", Text: cleanAnnouncementText(text)}, nil
+	}
+
+	text, err := htmlToText(body)
+	if err != nil {
+		return announcementFile{}, err
+	}
+	if text == "" {
+		return announcementFile{}, fmt.Errorf("公告页面没有正文")
+	}
+	return announcementFile{Source: "html", Text: text}, nil
+}
+
+// downloadAnnouncement 经代理管理器的客户端下载，限制响应大小
+// 公告附件链接来自接口响应，同样只允许公告域名
+func (s *NewsService) downloadAnnouncement(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	if err := s.checkAnnouncementURL(req.URL); err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://data.eastmoney.com/")
+
+	resp, err := s.downloadClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求公告失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("请求公告失败: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, announcementMaxDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取公告失败: %w", err)
+	}
+	if len(body) > announcementMaxDownload {
+		return nil, fmt.Errorf("公告文件超过 %dMB", announcementMaxDownload>>20)
+	}
+	return body, nil
+}
+
+// htmlToText 提取网页正文文本，块级元素换行
+func htmlToText(body []byte) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("解析公告页面失败: %w", err)
+	}
+	doc.Find("script, style, noscript, head").Remove()
+	doc.Find("br").ReplaceWithHtml("\n")
+	doc.Find("p, div, li, tr, h1, h2, h3, h4, h5, h6").AppendHtml("\n")
+	return cleanAnnouncementText(doc.Text()), nil
+}
+
+// cleanAnnouncementText 去掉不换行空格、行首尾空白与空行
+func cleanAnnouncementText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "\u00a0", " "))
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// truncateAnnouncement 按字符数截取正文
+func truncateAnnouncement(file *announcementFile, maxChars int) *AnnouncementContent {
+	runes := []rune(file.Text)
+	result := &AnnouncementContent{URL: file.URL, Source: file.Source, Text: file.Text, TotalChars: len(runes)}
+	if len(runes) > maxChars {
+		result.Text = string(runes[:maxChars])
+		result.Truncated = true
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseAnnouncementList(t *testing.T) {
+	body := []byte(`{"data":{"list":[
+		{"art_code":"AN202510281234567890","title":" 关于限售股份上市流通的提示性公告 ","notice_date":"2025-10-28 00:00:00",
+		 "columns":[{"column_code":"001","column_name":"限售股份上市流通"},{"column_name":"提示性公告"}]},
+		{"art_code":"","title":"无编号条目"}
+	]},"success":1}`)
+	list, err := parseAnnouncementList(body, "600519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("list = %+v", list)
+	}
+	want := Announcement{
+		Title: "关于限售股份上市流通的提示性公告",
+		Type:  "限售股份上市流通、提示性公告",
+		Date:  "2025-10-28",
+		URL:   "https://data.eastmoney.com/notices/detail/600519/AN202510281234567890.html",
+	}
+	if list[0] != want {
+		t.Errorf("item = %+v", list[0])
+	}
+	if m := artCodePattern.FindStringSubmatch(want.URL); m == nil || m[1] != "AN202510281234567890" {
+		t.Errorf("art code = %v", m)
+	}
+}
+
+// announcementPDF 最小的未压缩 PDF，单字节字体无 ToUnicode
+const announcementPDF = `%PDF-1.4
+1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj
+2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj
+3 0 obj << /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >> endobj
+4 0 obj << /Length 66 >>
+stream
+BT /F1 12 Tf 50 700 Td (Lock-up expiry) Tj 0 -20 Td (notice) Tj ET
+endstream
+endobj
+5 0 obj << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >> endobj
+trailer << /Root 1 0 R >>
+%%EOF`
+
+func TestGetAnnouncementContent(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/a.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte(announcementPDF))
+		case "/a.html":
+			w.Write([]byte(`<html><head><title>t</title><script>var x=1;</script></head>
+<body><h1>业绩预告</h1><p>预计净利润同比增长&nbsp;50%</p><div>第二段<br>第三行</div></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewNewsService()
+	s.announcementDir = t.TempDir()
+	s.announcementAnyHost = true
+	s.downloadClient = srv.Client()
+	ctx := context.Background()
+
+	pdf, err := s.GetAnnouncementContent(ctx, srv.URL+"/a.pdf", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdf.Source != "pdf" || pdf.Text != "Lock-up expiry\nnotice" || pdf.Truncated {
+		t.Errorf("pdf = %+v", pdf)
+	}
+
+	html, err := s.GetAnnouncementContent(ctx, srv.URL+"/a.html", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html.Source != "html" || html.Text != "业绩预告\n预计净利润同比增长 50%\n第二段\n第三行" {
+		t.Errorf("html = %q", html.Text)
+	}
+
+	// 第二次读取磁盘缓存，按字数截取
+	cut, err := s.GetAnnouncementContent(ctx, srv.URL+"/a.html", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits.Load() != 2 || cut.Text != "业绩预告" || !cut.Truncated || cut.TotalChars != len([]rune(html.Text)) {
+		t.Errorf("cached = %+v, hits = %d", cut, hits.Load())
+	}
+	if entries, _ := os.ReadDir(s.announcementDir); len(entries) != 2 {
+		t.Errorf("cache files = %d", len(entries))
+	}
+
+	if _, err := s.GetAnnouncementContent(ctx, srv.URL+"/missing", 0); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("missing err = %v", err)
+	}
+	if _, err := s.GetAnnouncementContent(ctx, "file:///etc/passwd", 0); err == nil {
+		t.Error("non-http url should fail")
+	}
+}
+
+func TestAnnouncementURLAllowlist(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	s := NewNewsService()
+	s.announcementDir = t.TempDir()
+	ctx := context.Background()
+	for _, rawURL := range []string{
+		srv.URL + "/a.pdf",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.8/notice.pdf",
+		"https://eastmoney.com.evil.example/a.pdf",
+		"https://evil-cninfo.com.cn/a.pdf",
+	} {
+		if _, err := s.GetAnnouncementContent(ctx, rawURL, 0); !errors.Is(err, errAnnouncementHost) {
+			t.Errorf("%s: err = %v", rawURL, err)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Errorf("blocked urls requested %d times", hits.Load())
+	}
+
+	for _, rawURL := range []string{
+		"https://data.eastmoney.com/notices/detail/600519/AN202501011234567890.html",
+		"https://pdf.dfcfw.com.eastmoney.com/a.pdf",
+		"http://static.cninfo.com.cn/finalpage/2025-01-01/1222.PDF",
+		"https://www.sse.com.cn/disclosure/a.pdf",
+		"https://disc.szse.cn/download/a.pdf",
+		"https://www.bse.cn/disclosure/a.pdf",
+	} {
+		u, _ := url.Parse(rawURL)
+		if err := s.checkAnnouncementURL(u); err != nil {
+			t.Errorf("%s: err = %v", rawURL, err)
+		}
+	}
+
+	// 跳转目标同样校验域名（首个请求已由 downloadAnnouncement 校验）
+	redirect := httptest.NewServer(http.RedirectHandler("http://127.0.0.1:1/admin", http.StatusFound))
+	defer redirect.Close()
+	client := s.newAnnouncementClient()
+	client.Transport = redirect.Client().Transport
+	if _, err := client.Get(redirect.URL); !errors.Is(err, errAnnouncementHost) {
+		t.Errorf("redirect err = %v", err)
+	}
+}
+
+func TestPruneAnnouncementCache(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	write := func(name string, size int, age time.Duration) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, now.Add(-age), now.Add(-age))
+	}
+	write("expired.json", 10, announcementCacheMaxAge+time.Hour)
+	write("oldest.json", 500, 3*time.Hour)
+	write("older.json", 500, 2*time.Hour)
+	write("newest.json", 10, time.Hour)
+	write("other.txt", 10, announcementCacheMaxAge+time.Hour)
+
+	pruneAnnouncementCache(dir, now, 1000)
+	var names []string
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if got := strings.Join(names, ","); got != "newest.json,older.json,other.txt" {
+		t.Errorf("remaining = %s", got)
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// apiTokenPrefix 令牌明文前缀，便于在脚本与日志中识别
+const apiTokenPrefix = "jcp_"
+
+// apiTokenTouchInterval 最近使用时间的落盘间隔，避免每个请求都写配置文件
+const apiTokenTouchInterval = time.Minute
+
+// ErrAPITokenNotFound 令牌不存在或已吊销
+var ErrAPITokenNotFound = errors.New("令牌不存在或已吊销")
+
+// GetAPITokens 获取 API 令牌列表（副本）
+func (cs *ConfigService) GetAPITokens() []models.APIToken {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	tokens := make([]models.APIToken, len(cs.config.APITokens))
+	copy(tokens, cs.config.APITokens)
+	return tokens
+}
+
+// CreateAPIToken 创建 API 令牌，返回记录与明文；明文不落盘，只在此时返回一次
+func (cs *ConfigService) CreateAPIToken(name string, scopes []models.APIScope) (models.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.APIToken{}, "", fmt.Errorf("令牌名称不能为空")
+	}
+	var granted []models.APIScope
+	for _, scope := range scopes {
+		if !models.ValidAPIScope(scope) {
+			return models.APIToken{}, "", fmt.Errorf("无效的权限范围: %s", scope)
+		}
+		if !slices.Contains(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	if len(granted) == 0 {
+		return models.APIToken{}, "", fmt.Errorf("至少需要一个权限范围")
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return models.APIToken{}, "", err
+	}
+	plaintext := apiTokenPrefix + hex.EncodeToString(secret)
+	token := models.APIToken{
+		ID:        uuid.New().String()[:8],
+		Name:      name,
+		TokenHash: models.HashAPIToken(plaintext),
+		Scopes:    granted,
+		CreatedAt: time.Now().UnixMilli(),
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	// 换新切片，避免与持有旧配置的读取方并发读写
+	cs.config.APITokens = append(slices.Clip(cs.config.APITokens), token)
+	return token, plaintext, cs.saveConfigLocked()
+}
+
+// RevokeAPIToken 吊销 API 令牌，立即生效
+func (cs *ConfigService) RevokeAPIToken(id string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	i := slices.IndexFunc(cs.config.APITokens, func(t models.APIToken) bool { return t.ID == id })
+	if i < 0 {
+		return ErrAPITokenNotFound
+	}
+	cs.config.APITokens = slices.Delete(slices.Clone(cs.config.APITokens), i, i+1)
+	return cs.saveConfigLocked()
+}
+
+// TouchAPIToken 记录令牌最近使用时间，距上次记录不足 apiTokenTouchInterval 时不落盘
+func (cs *ConfigService) TouchAPIToken(id string, at time.Time) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	i := slices.IndexFunc(cs.config.APITokens, func(t models.APIToken) bool { return t.ID == id })
+	if i < 0 || at.UnixMilli()-cs.config.APITokens[i].LastUsedAt < apiTokenTouchInterval.Milliseconds() {
+		return
+	}
+	tokens := slices.Clone(cs.config.APITokens)
+	tokens[i].LastUsedAt = at.UnixMilli()
+	cs.config.APITokens = tokens
+	if err := cs.saveConfigLocked(); err != nil {
+		log.Warn("保存令牌使用时间失败: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestAPITokens(t *testing.T) {
+	dir := t.TempDir()
+	cs, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := cs.CreateAPIToken("看板", nil); err == nil {
+		t.Error("没有权限范围应报错")
+	}
+	if _, _, err := cs.CreateAPIToken("看板", []models.APIScope{"write_all"}); err == nil {
+		t.Error("未知权限范围应报错")
+	}
+
+	token, plaintext, err := cs.CreateAPIToken(" 看板 ", []models.APIScope{models.APIScopeReadMarket, models.APIScopeReadMarket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(plaintext, apiTokenPrefix) || token.TokenHash != models.HashAPIToken(plaintext) {
+		t.Fatalf("token = %+v, plaintext = %s", token, plaintext)
+	}
+	if token.Name != "看板" || len(token.Scopes) != 1 {
+		t.Errorf("token = %+v", token)
+	}
+
+	// 前端保存的旧配置不会覆盖令牌
+	stale := *cs.GetConfig()
+	stale.APITokens = nil
+	if err := cs.UpdateConfig(&stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// 使用时间按间隔落盘
+	now := time.Now()
+	cs.TouchAPIToken(token.ID, now)
+	cs.TouchAPIToken(token.ID, now.Add(time.Second))
+	reloaded, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := reloaded.GetAPITokens()
+	if len(tokens) != 1 || tokens[0].LastUsedAt != now.UnixMilli() {
+		t.Fatalf("reloaded tokens = %+v", tokens)
+	}
+	if strings.Contains(tokens[0].TokenHash, plaintext) {
+		t.Error("明文不应落盘")
+	}
+
+	if err := cs.RevokeAPIToken(token.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.RevokeAPIToken(token.ID); err != ErrAPITokenNotFound {
+		t.Errorf("重复吊销 err = %v", err)
+	}
+	if len(cs.GetAPITokens()) != 0 {
+		t.Error("吊销后仍存在")
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheGranularity 缓存失效粒度
+type CacheGranularity string
+
+const (
+	// CacheIntraday 盘中数据：本地零点、交易日开盘与收盘时失效
+	CacheIntraday CacheGranularity = "intraday"
+	// CacheDaily 按自然日缓存：本地零点失效
+	CacheDaily CacheGranularity = "daily"
+	// CacheTradeDate 按交易日发布的盘后数据（如龙虎榜）：交易日数据发布后失效
+	CacheTradeDate CacheGranularity = "trade_date"
+)
+
+const (
+	cacheEpochTick        = 30 * time.Second
+	marketOpenMinutes     = 9*60 + 30
+	marketCloseMinutes    = 15 * 60
+	tradeDataReadyMinutes = 18 * 60 // 龙虎榜等盘后数据约 18:00 发布完毕
+)
+
+// cacheEpochLoc 纪元按北京时间划分
+var cacheEpochLoc = time.FixedZone("CST", 8*60*60)
+
+// epochCache 已注册的缓存
+type epochCache struct {
+	granularity CacheGranularity
+	flush       func()
+	epoch       string // 上次检查时所处的纪元，空表示尚未检查
+}
+
+// CacheEpochs 缓存纪元服务：按交易日历划分纪元，跨越边界时清空已注册的缓存
+// 避免应用隔夜运行时，按"天"缓存的数据因 TTL 从进程内写入时间起算而一直返回前一天的结果
+type CacheEpochs struct {
+	isTradeDate func(time.Time) bool
+	now         func() time.Time
+
+	mu     sync.Mutex
+	caches map[string]*epochCache
+
+	cancel   context.CancelFunc
+	loopDone chan struct{} // 检查循环退出信号，Start 后才有值
+}
+
+// NewCacheEpochs 创建缓存纪元服务，交易日判定使用市场服务的节假日数据
+func NewCacheEpochs(ms *MarketService) *CacheEpochs {
+	return newCacheEpochs(ms.isTradeDate, time.Now)
+}
+
+func newCacheEpochs(isTradeDate func(time.Time) bool, now func() time.Time) *CacheEpochs {
+	return &CacheEpochs{isTradeDate: isTradeDate, now: now, caches: make(map[string]*epochCache)}
+}
+
+// Register 注册缓存，跨越 granularity 对应的边界时调用 flush 清空；同名重复注册会覆盖
+// 注册时不计算纪元（交易日判定可能需要联网加载节假日），由首次检查记录
+func (e *CacheEpochs) Register(name string, granularity CacheGranularity, flush func()) {
+	e.mu.Lock()
+	e.caches[name] = &epochCache{granularity: granularity, flush: flush}
+	e.mu.Unlock()
+}
+
+// Start 启动纪元检查
+func (e *CacheEpochs) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.loopDone = make(chan struct{})
+	go e.loop(ctx)
+}
+
+// Stop 停止纪元检查
+func (e *CacheEpochs) Stop() {
+	if e.cancel == nil {
+		return
+	}
+	e.cancel()
+	<-e.loopDone
+}
+
+func (e *CacheEpochs) loop(ctx context.Context) {
+	defer close(e.loopDone)
+	ticker := time.NewTicker(cacheEpochTick)
+	defer ticker.Stop()
+	safeCall(func() { e.check() })
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			safeCall(func() { e.check() })
+		}
+	}
+}
+
+// check 清空纪元已变化的缓存，返回清空的缓存名
+func (e *CacheEpochs) check() []string {
+	now := e.now()
+	epochs := make(map[CacheGranularity]string)
+	var names []string
+	var flushes []func()
+
+	e.mu.Lock()
+	for name, c := range e.caches {
+		epoch, ok := epochs[c.granularity]
+		if !ok {
+			epoch = cacheEpochKey(c.granularity, now, e.isTradeDate)
+			epochs[c.granularity] = epoch
+		}
+		if epoch == c.epoch {
+			continue
+		}
+		first := c.epoch == ""
+		c.epoch = epoch
+		if first {
+			continue
+		}
+		names = append(names, name)
+		flushes = append(flushes, c.flush)
+	}
+	e.mu.Unlock()
+
+	// 在锁外清空，各缓存使用自己的锁
+	for _, flush := range flushes {
+		flush()
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		log.Info("缓存纪元切换，已清空: %s", strings.Join(names, ", "))
+	}
+	return names
+}
+
+// FlushAll 立即清空全部已注册的缓存（排查数据问题时手动使用），返回清空的缓存名
+func (e *CacheEpochs) FlushAll() []string {
+	e.mu.Lock()
+	names := make([]string, 0, len(e.caches))
+	flushes := make([]func(), 0, len(e.caches))
+	for name, c := range e.caches {
+		names = append(names, name)
+		flushes = append(flushes, c.flush)
+	}
+	e.mu.Unlock()
+
+	for _, flush := range flushes {
+		flush()
+	}
+	sort.Strings(names)
+	log.Info("手动清空缓存: %s", strings.Join(names, ", "))
+	return names
+}
+
+// cacheEpochKey 返回 t 所处的纪元，纪元相同的时刻共享同一份缓存
+func cacheEpochKey(granularity CacheGranularity, t time.Time, isTradeDate func(time.Time) bool) string {
+	t = t.In(cacheEpochLoc)
+	date := t.Format("2006-01-02")
+	minutes := t.Hour()*60 + t.Minute()
+	switch granularity {
+	case CacheIntraday:
+		if !isTradeDate(t) {
+			return date
+		}
+		switch {
+		case minutes < marketOpenMinutes:
+			return date + "/pre"
+		case minutes < marketCloseMinutes:
+			return date + "/open"
+		default:
+			return date + "/closed"
+		}
+	case CacheTradeDate:
+		// 最近一个盘后数据已发布的交易日
+		day := t
+		if minutes < tradeDataReadyMinutes {
+			day = day.AddDate(0, 0, -1)
+		}
+		for i := 0; i < maxCalendarScan && !isTradeDate(day); i++ {
+			day = day.AddDate(0, 0, -1)
+		}
+		return day.Format("2006-01-02")
+	default:
+		return date
+	}
+}
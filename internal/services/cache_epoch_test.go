@@ -0,0 +1,106 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testTradeDate 周末与 2026 年国庆（10-01~10-08）休市
+func testTradeDate(t time.Time) bool {
+	t = t.In(cacheEpochLoc)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	date := t.Format("2006-01-02")
+	return date < "2026-10-01" || date > "2026-10-08"
+}
+
+func cst(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, cacheEpochLoc)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCacheEpochKey(t *testing.T) {
+	cases := []struct {
+		granularity CacheGranularity
+		at          string
+		want        string
+	}{
+		{CacheDaily, "2026-10-16 23:59", "2026-10-16"},
+		{CacheIntraday, "2026-10-16 09:29", "2026-10-16/pre"},
+		{CacheIntraday, "2026-10-16 09:30", "2026-10-16/open"},
+		{CacheIntraday, "2026-10-16 15:00", "2026-10-16/closed"},
+		{CacheIntraday, "2026-10-17 10:00", "2026-10-17"},
+		{CacheTradeDate, "2026-10-16 17:59", "2026-10-15"},
+		{CacheTradeDate, "2026-10-16 18:00", "2026-10-16"},
+		{CacheTradeDate, "2026-10-18 20:00", "2026-10-16"},
+		// 长假后首个交易日盘后数据发布前，仍是节前最后一个交易日
+		{CacheTradeDate, "2026-10-09 08:00", "2026-09-30"},
+	}
+	for _, c := range cases {
+		if got := cacheEpochKey(c.granularity, cst(c.at), testTradeDate); got != c.want {
+			t.Errorf("%s @ %s = %s, want %s", c.granularity, c.at, got, c.want)
+		}
+	}
+}
+
+func TestCacheEpochsRollover(t *testing.T) {
+	now := cst("2026-10-15 20:00")
+	e := newCacheEpochs(testTradeDate, func() time.Time { return now })
+	flushed := map[string]int{}
+	for name, g := range map[string]CacheGranularity{"kline": CacheIntraday, "summary": CacheDaily, "longhubang": CacheTradeDate} {
+		e.Register(name, g, func() { flushed[name]++ })
+	}
+
+	// 首次检查只记录纪元
+	if names := e.check(); len(names) != 0 {
+		t.Fatalf("first check flushed %v", names)
+	}
+
+	steps := []struct {
+		at   string
+		want []string
+	}{
+		{"2026-10-15 23:59", nil},
+		{"2026-10-16 00:00", []string{"kline", "summary"}},
+		{"2026-10-16 09:29", nil},
+		{"2026-10-16 09:30", []string{"kline"}},
+		{"2026-10-16 14:59", nil},
+		{"2026-10-16 15:00", []string{"kline"}},
+		{"2026-10-16 17:59", nil},
+		{"2026-10-16 18:00", []string{"longhubang"}},
+		{"2026-10-17 00:00", []string{"kline", "summary"}},
+		{"2026-10-17 18:00", nil}, // 周六没有新的盘后数据
+	}
+	for _, step := range steps {
+		now = cst(step.at)
+		if got := e.check(); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s flushed %v, want %v", step.at, got, step.want)
+		}
+	}
+	if want := map[string]int{"kline": 4, "summary": 2, "longhubang": 1}; !reflect.DeepEqual(flushed, want) {
+		t.Errorf("flush counts = %v", flushed)
+	}
+
+	if names := e.FlushAll(); !reflect.DeepEqual(names, []string{"kline", "longhubang", "summary"}) {
+		t.Errorf("FlushAll = %v", names)
+	}
+	if flushed["longhubang"] != 2 {
+		t.Errorf("longhubang flushed %d times", flushed["longhubang"])
+	}
+}
+
+func TestLongHuBangRegisterCaches(t *testing.T) {
+	s := NewLongHuBangService()
+	s.cache = &lhbCache{key: "50_1_", timestamp: time.Now()}
+	e := newCacheEpochs(testTradeDate, time.Now)
+	s.RegisterCaches(e)
+	e.FlushAll()
+	if s.cache != nil {
+		t.Error("longhubang cache should be cleared")
+	}
+}
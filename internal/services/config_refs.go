@@ -0,0 +1,80 @@
+package services
+
+import (
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// EventConfigReferencesFixed 删除 AI 配置后自动清理失效引用的事件，载荷为 []AIConfigRefFix
+const EventConfigReferencesFixed = "config:references_fixed"
+
+// AIConfigRefFix 一处被清理的 AI 配置引用
+type AIConfigRefFix struct {
+	Field string `json:"field"` // 配置字段，如 moderatorAiId、memory.aiConfigId
+	Label string `json:"label"` // 字段中文名
+	OldID string `json:"oldId"` // 已不存在的配置 ID
+	NewID string `json:"newId"` // 修正后的 ID，空表示改用默认配置
+}
+
+// ReferencesFixedListener 失效引用被清理后的回调
+type ReferencesFixedListener func(fixes []AIConfigRefFix)
+
+// SetReferencesFixedListener 设置失效引用清理回调（UpdateConfig 时触发）
+func (cs *ConfigService) SetReferencesFixedListener(listener ReferencesFixedListener) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.refsFixedListener = listener
+}
+
+// FixAIConfigReferences 清理指向已删除 AI 配置的引用
+// 默认配置重新指向标记为默认（或第一个）的配置；其余引用清空，运行时即回退到默认配置
+func FixAIConfigReferences(cfg *models.AppConfig) []AIConfigRefFix {
+	exists := make(map[string]bool, len(cfg.AIConfigs))
+	for _, c := range cfg.AIConfigs {
+		exists[c.ID] = true
+	}
+
+	var fixes []AIConfigRefFix
+	if cfg.DefaultAIID != "" && !exists[cfg.DefaultAIID] {
+		fallback := ""
+		for _, c := range cfg.AIConfigs {
+			if c.IsDefault {
+				fallback = c.ID
+				break
+			}
+		}
+		if fallback == "" && len(cfg.AIConfigs) > 0 {
+			fallback = cfg.AIConfigs[0].ID
+		}
+		fixes = append(fixes, AIConfigRefFix{Field: "defaultAiId", Label: "默认模型", OldID: cfg.DefaultAIID, NewID: fallback})
+		cfg.DefaultAIID = fallback
+	}
+
+	refs := []struct {
+		field, label string
+		id           *string
+	}{
+		{"moderatorAiId", "意图分析(小韭菜)模型", &cfg.ModeratorAIID},
+		{"strategyAiId", "策略生成模型", &cfg.StrategyAIID},
+		{"researchAiId", "深度研究模型", &cfg.ResearchAIID},
+		{"memory.aiConfigId", "记忆管理模型", &cfg.Memory.AIConfigID},
+	}
+	for _, ref := range refs {
+		if *ref.id == "" || exists[*ref.id] {
+			continue
+		}
+		fixes = append(fixes, AIConfigRefFix{Field: ref.field, Label: ref.label, OldID: *ref.id})
+		*ref.id = ""
+	}
+	return fixes
+}
+
+// logReferenceFixes 记录被清理的引用
+func logReferenceFixes(fixes []AIConfigRefFix) {
+	for _, f := range fixes {
+		if f.NewID == "" {
+			log.Warn("AI 配置 %s 已不存在，%s 改用默认配置", f.OldID, f.Label)
+		} else {
+			log.Warn("AI 配置 %s 已不存在，%s 改为 %s", f.OldID, f.Label, f.NewID)
+		}
+	}
+}
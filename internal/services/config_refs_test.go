@@ -0,0 +1,118 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestFixAIConfigReferences(t *testing.T) {
+	base := func() *models.AppConfig {
+		return &models.AppConfig{
+			AIConfigs: []models.AIConfig{
+				{ID: "a"},
+				{ID: "b", IsDefault: true},
+			},
+			DefaultAIID:   "b",
+			ModeratorAIID: "a",
+			StrategyAIID:  "a",
+			Memory:        models.MemoryConfig{AIConfigID: "a"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(cfg *models.AppConfig)
+		check  func(cfg *models.AppConfig) bool
+		fields []string
+	}{
+		{"无失效引用", func(cfg *models.AppConfig) {}, func(cfg *models.AppConfig) bool {
+			return cfg.ModeratorAIID == "a" && cfg.Memory.AIConfigID == "a"
+		}, nil},
+		{"删除意图分析模型", func(cfg *models.AppConfig) { cfg.ModeratorAIID = "x" }, func(cfg *models.AppConfig) bool {
+			return cfg.ModeratorAIID == "" && cfg.StrategyAIID == "a"
+		}, []string{"moderatorAiId"}},
+		{"删除记忆模型", func(cfg *models.AppConfig) { cfg.Memory.AIConfigID = "x" }, func(cfg *models.AppConfig) bool {
+			return cfg.Memory.AIConfigID == "" && cfg.ModeratorAIID == "a"
+		}, []string{"memory.aiConfigId"}},
+		{"删除策略模型", func(cfg *models.AppConfig) { cfg.StrategyAIID = "x" }, func(cfg *models.AppConfig) bool {
+			return cfg.StrategyAIID == ""
+		}, []string{"strategyAiId"}},
+		{"默认模型改指向 IsDefault", func(cfg *models.AppConfig) { cfg.DefaultAIID = "x" }, func(cfg *models.AppConfig) bool {
+			return cfg.DefaultAIID == "b"
+		}, []string{"defaultAiId"}},
+		{"无 IsDefault 时取第一个", func(cfg *models.AppConfig) {
+			cfg.DefaultAIID = "x"
+			cfg.AIConfigs[1].IsDefault = false
+		}, func(cfg *models.AppConfig) bool {
+			return cfg.DefaultAIID == "a"
+		}, []string{"defaultAiId"}},
+		{"被引用的配置被删除", func(cfg *models.AppConfig) { cfg.AIConfigs = cfg.AIConfigs[1:] }, func(cfg *models.AppConfig) bool {
+			return cfg.DefaultAIID == "b" && cfg.ModeratorAIID == "" && cfg.StrategyAIID == "" && cfg.Memory.AIConfigID == ""
+		}, []string{"moderatorAiId", "strategyAiId", "memory.aiConfigId"}},
+		{"全部配置被删除", func(cfg *models.AppConfig) { cfg.AIConfigs = nil }, func(cfg *models.AppConfig) bool {
+			return cfg.DefaultAIID == "" && cfg.ModeratorAIID == "" && cfg.Memory.AIConfigID == ""
+		}, []string{"defaultAiId", "moderatorAiId", "strategyAiId", "memory.aiConfigId"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			tt.mutate(cfg)
+			fixes := FixAIConfigReferences(cfg)
+			if len(fixes) != len(tt.fields) {
+				t.Fatalf("fixes = %+v, want %v", fixes, tt.fields)
+			}
+			for i, f := range fixes {
+				if f.Field != tt.fields[i] {
+					t.Errorf("fixes[%d] = %s, want %s", i, f.Field, tt.fields[i])
+				}
+			}
+			if !tt.check(cfg) {
+				t.Errorf("cfg = %+v", cfg)
+			}
+			if again := FixAIConfigReferences(cfg); len(again) != 0 {
+				t.Errorf("重复清理应无变化: %+v", again)
+			}
+		})
+	}
+}
+
+func TestUpdateConfigNotifiesReferenceFixes(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []AIConfigRefFix
+	cs.SetReferencesFixedListener(func(fixes []AIConfigRefFix) { got = fixes })
+
+	cfg := *cs.GetConfig()
+	cfg.AIConfigs = []models.AIConfig{{ID: "a", IsDefault: true}}
+	cfg.DefaultAIID = "a"
+	cfg.ModeratorAIID = "a"
+	if err := cs.UpdateConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Fatalf("无失效引用时不应通知: %+v", got)
+	}
+
+	next := cfg
+	next.AIConfigs = []models.AIConfig{{ID: "b", IsDefault: true}}
+	if err := cs.UpdateConfig(&next); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].NewID != "b" || got[1].Field != "moderatorAiId" {
+		t.Fatalf("fixes = %+v", got)
+	}
+
+	// 重新加载后引用保持清理后的值
+	reloaded, err := NewConfigService(filepath.Dir(cs.configPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := reloaded.GetConfig(); c.DefaultAIID != "b" || c.ModeratorAIID != "" {
+		t.Fatalf("reloaded = %+v", c)
+	}
+}
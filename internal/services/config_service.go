@@ -2,11 +2,16 @@ package services
 
 import (
 	"encoding/json"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/run-bigpig/jcp/internal/embed"
 	"github.com/run-bigpig/jcp/internal/models"
 )
@@ -15,9 +20,15 @@ import (
 type ConfigService struct {
 	configPath    string
 	watchlistPath string
+	groupsPath    string
+	alertsPath    string
 	config        *models.AppConfig
 	watchlist     []models.Stock
+	groups        []models.WatchlistGroup
+	alerts        []models.PriceAlert
 	mu            sync.RWMutex
+
+	refsFixedListener ReferencesFixedListener
 }
 
 // NewConfigService 创建配置服务
@@ -29,6 +40,8 @@ func NewConfigService(dataDir string) (*ConfigService, error) {
 	cs := &ConfigService{
 		configPath:    filepath.Join(dataDir, "config.json"),
 		watchlistPath: filepath.Join(dataDir, "watchlist.json"),
+		groupsPath:    filepath.Join(dataDir, "watchlist_groups.json"),
+		alertsPath:    filepath.Join(dataDir, "alerts.json"),
 	}
 
 	if err := cs.loadConfig(); err != nil {
@@ -37,6 +50,9 @@ func NewConfigService(dataDir string) (*ConfigService, error) {
 	if err := cs.loadWatchlist(); err != nil {
 		return nil, err
 	}
+	if err := cs.loadAlerts(); err != nil {
+		return nil, err
+	}
 
 	return cs, nil
 }
@@ -142,7 +158,21 @@ func (cs *ConfigService) loadConfig() error {
 	if ind.KDJ.D == 0 {
 		ind.KDJ.D = d.KDJ.D
 	}
+	if config.ThinkingPersist == "" {
+		config.ThinkingPersist = models.ThinkingPersistDiscard
+	}
+	if config.MeetingVisibility == "" {
+		config.MeetingVisibility = models.MeetingVisibilitySequential
+	}
+	if config.ModelWarmup == "" {
+		config.ModelWarmup = models.ModelWarmupOff
+	}
 	cs.config = &config
+	// 手动编辑配置文件删除 AI 配置后，启动时同样清理失效引用
+	if fixes := FixAIConfigReferences(cs.config); len(fixes) > 0 {
+		logReferenceFixes(fixes)
+		return cs.saveConfigLocked()
+	}
 	return nil
 }
 
@@ -168,6 +198,9 @@ func (cs *ConfigService) defaultConfig() *models.AppConfig {
 			RSI:  models.RSIConfig{Enabled: false, Period: 14},
 			KDJ:  models.KDJConfig{Enabled: false, Period: 9, K: 3, D: 3},
 		},
+		ThinkingPersist:   models.ThinkingPersistDiscard,
+		MeetingVisibility: models.MeetingVisibilitySequential,
+		ModelWarmup:       models.ModelWarmupOff,
 	}
 }
 
@@ -188,11 +221,48 @@ func (cs *ConfigService) GetConfig() *models.AppConfig {
 }
 
 // UpdateConfig 更新配置
+// 引用已删除 AI 配置的字段会被清理，并通过 ReferencesFixedListener 通知
 func (cs *ConfigService) UpdateConfig(config *models.AppConfig) error {
 	cs.mu.Lock()
-	defer cs.mu.Unlock()
+	fixes := FixAIConfigReferences(config)
+	// API 令牌只通过专用接口增删，前端保存的配置可能是旧副本
+	config.APITokens = cs.config.APITokens
 	cs.config = config
-	return cs.saveConfigLocked()
+	err := cs.saveConfigLocked()
+	listener := cs.refsFixedListener
+	cs.mu.Unlock()
+
+	if len(fixes) > 0 {
+		logReferenceFixes(fixes)
+		if listener != nil {
+			listener(fixes)
+		}
+	}
+	return err
+}
+
+// SetAICapability 记录 AI 配置实测的能力支持情况并保存
+func (cs *ConfigService) SetAICapability(aiID, capability string, supported bool) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	for i := range cs.config.AIConfigs {
+		c := &cs.config.AIConfigs[i]
+		if c.ID != aiID {
+			continue
+		}
+		if v, ok := c.Capabilities[capability]; ok && v == supported {
+			return nil
+		}
+		// 换新 map，避免与持有旧配置的读取方并发读写
+		caps := maps.Clone(c.Capabilities)
+		if caps == nil {
+			caps = make(map[string]bool)
+		}
+		caps[capability] = supported
+		c.Capabilities = caps
+		return cs.saveConfigLocked()
+	}
+	return fmt.Errorf("AI 配置不存在: %s", aiID)
 }
 
 // loadWatchlist 加载自选股列表
@@ -204,6 +274,7 @@ func (cs *ConfigService) loadWatchlist() error {
 	if os.IsNotExist(err) {
 		// 文件不存在时，初始化为空列表
 		cs.watchlist = []models.Stock{}
+		cs.normalizeWatchlistLocked()
 		return cs.saveWatchlistLocked()
 	}
 	if err != nil {
@@ -214,8 +285,20 @@ func (cs *ConfigService) loadWatchlist() error {
 	if err := json.Unmarshal(data, &watchlist); err != nil {
 		return err
 	}
-
 	cs.watchlist = watchlist
+
+	// 旧版没有分组文件，自选股全部归入默认分组
+	groups, err := os.ReadFile(cs.groupsPath)
+	if err == nil {
+		if err := json.Unmarshal(groups, &cs.groups); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if cs.normalizeWatchlistLocked() {
+		return cs.saveWatchlistLocked()
+	}
 	return nil
 }
 
@@ -225,17 +308,24 @@ func (cs *ConfigService) saveWatchlistLocked() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(cs.watchlistPath, data, 0644)
+	if err := os.WriteFile(cs.watchlistPath, data, 0644); err != nil {
+		return err
+	}
+	groups, err := json.MarshalIndent(cs.groups, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cs.groupsPath, groups, 0644)
 }
 
-// GetWatchlist 获取自选股列表
+// GetWatchlist 获取自选股列表，按分组顺序及分组内顺序排列
 func (cs *ConfigService) GetWatchlist() []models.Stock {
 	cs.mu.RLock()
 	defer cs.mu.RUnlock()
-	return cs.watchlist
+	return slices.Clone(cs.watchlist)
 }
 
-// AddToWatchlist 添加自选股
+// AddToWatchlist 添加自选股，未指定分组时加入默认分组末尾
 func (cs *ConfigService) AddToWatchlist(stock models.Stock) error {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
@@ -245,7 +335,12 @@ func (cs *ConfigService) AddToWatchlist(stock models.Stock) error {
 			return nil
 		}
 	}
+	if stock.GroupID == "" {
+		stock.GroupID = models.DefaultWatchlistGroupID
+	}
+	stock.SortOrder = len(cs.watchlist)
 	cs.watchlist = append(cs.watchlist, stock)
+	cs.normalizeWatchlistLocked()
 	return cs.saveWatchlistLocked()
 }
 
@@ -257,12 +352,124 @@ func (cs *ConfigService) RemoveFromWatchlist(symbol string) error {
 	for i, s := range cs.watchlist {
 		if s.Symbol == symbol {
 			cs.watchlist = append(cs.watchlist[:i], cs.watchlist[i+1:]...)
+			cs.normalizeWatchlistLocked()
 			return cs.saveWatchlistLocked()
 		}
 	}
 	return nil
 }
 
+// loadAlerts 加载价格提醒
+func (cs *ConfigService) loadAlerts() error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	data, err := os.ReadFile(cs.alertsPath)
+	if os.IsNotExist(err) {
+		cs.alerts = []models.PriceAlert{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var alerts []models.PriceAlert
+	if err := json.Unmarshal(data, &alerts); err != nil {
+		return err
+	}
+	cs.alerts = alerts
+	return nil
+}
+
+// saveAlertsLocked 保存价格提醒(需要已持有锁)
+func (cs *ConfigService) saveAlertsLocked() error {
+	data, err := json.MarshalIndent(cs.alerts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cs.alertsPath, data, 0644)
+}
+
+// GetAlerts 获取价格提醒列表（副本）
+func (cs *ConfigService) GetAlerts() []models.PriceAlert {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return append([]models.PriceAlert{}, cs.alerts...)
+}
+
+// AddAlert 添加价格提醒，返回补全 ID 与创建时间后的提醒
+func (cs *ConfigService) AddAlert(alert models.PriceAlert) (models.PriceAlert, error) {
+	alert.Symbol = models.NormalizeSymbol(alert.Symbol)
+	if alert.Symbol == "" {
+		return alert, fmt.Errorf("股票代码不能为空")
+	}
+	condition, ok := models.ParseAlertCondition(string(alert.Condition))
+	if !ok {
+		return alert, fmt.Errorf("无效的提醒条件: %s", alert.Condition)
+	}
+	alert.Condition = condition
+	if alert.Threshold <= 0 {
+		return alert, fmt.Errorf("提醒阈值必须大于 0")
+	}
+	alert.ID = uuid.New().String()[:8]
+	alert.Triggered = false
+	alert.TriggerPrice = 0
+	alert.TriggerValue = 0
+	alert.TriggeredAt = 0
+	alert.CreatedAt = time.Now().UnixMilli()
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.alerts = append(cs.alerts, alert)
+	return alert, cs.saveAlertsLocked()
+}
+
+// RemoveAlert 删除价格提醒
+func (cs *ConfigService) RemoveAlert(id string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for i, a := range cs.alerts {
+		if a.ID == id {
+			cs.alerts = append(cs.alerts[:i], cs.alerts[i+1:]...)
+			return cs.saveAlertsLocked()
+		}
+	}
+	return nil
+}
+
+// alertHit 提醒触发时的行情
+type alertHit struct {
+	price float64 // 现价
+	value float64 // 触发判断用的观测值（价格、涨跌幅或量比）
+}
+
+// markAlertsTriggered 批量标记提醒已触发，返回实际新触发的提醒
+// hits 为 alertID -> 触发时行情，已触发或已删除的提醒会被忽略
+func (cs *ConfigService) markAlertsTriggered(hits map[string]alertHit) ([]models.PriceAlert, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	var triggered []models.PriceAlert
+	for i := range cs.alerts {
+		a := &cs.alerts[i]
+		hit, ok := hits[a.ID]
+		if !ok || a.Triggered {
+			continue
+		}
+		a.Triggered = true
+		a.TriggerPrice = hit.price
+		a.TriggerValue = hit.value
+		a.TriggeredAt = now
+		triggered = append(triggered, *a)
+	}
+	if len(triggered) == 0 {
+		return nil, nil
+	}
+	return triggered, cs.saveAlertsLocked()
+}
+
 // stockBasicData stock_basic.json 的数据结构
 type stockBasicData struct {
 	Data struct {
@@ -339,6 +546,9 @@ func (cs *ConfigService) SearchStocks(keyword string, limit int) []StockSearchRe
 				} else if strings.HasSuffix(tsCode, ".SZ") {
 					market = "深圳"
 					fullSymbol = "sz" + symbol
+				} else if strings.HasSuffix(tsCode, ".BJ") {
+					market = "北京"
+					fullSymbol = "bj" + symbol
 				}
 			}
 			if fullSymbol == "" {
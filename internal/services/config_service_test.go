@@ -0,0 +1,30 @@
+package services
+
+import "testing"
+
+func TestConfigService_SystemPromptAffixesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cs, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg := cs.GetConfig(); cfg.GlobalSystemPromptPrefix != "" || cfg.GlobalSystemPromptSuffix != "" {
+		t.Fatalf("默认应为空: %q %q", cfg.GlobalSystemPromptPrefix, cfg.GlobalSystemPromptSuffix)
+	}
+
+	cfg := *cs.GetConfig()
+	cfg.GlobalSystemPromptPrefix = "回复仅供学习参考。"
+	cfg.GlobalSystemPromptSuffix = "投资有风险。"
+	if err := cs.UpdateConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reloaded.GetConfig()
+	if got.GlobalSystemPromptPrefix != cfg.GlobalSystemPromptPrefix || got.GlobalSystemPromptSuffix != cfg.GlobalSystemPromptSuffix {
+		t.Errorf("reloaded = %q %q", got.GlobalSystemPromptPrefix, got.GlobalSystemPromptSuffix)
+	}
+}
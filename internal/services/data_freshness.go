@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// GetDataFreshness 计算标的行情数据的时效：行情时间、日K最后一根日期与交易日历中的市场状态
+// 非盘中时 Disclaimer 给出"数据截至 X月X日收盘"，供专家回复首行声明
+func (ms *MarketService) GetDataFreshness(ctx context.Context, stock models.Stock, now time.Time) *models.DataFreshness {
+	now = now.In(cacheEpochLoc)
+	status := ms.marketStatusAt(now)
+	f := &models.DataFreshness{
+		QuoteTime:    stock.QuoteTime,
+		MarketStatus: status.StatusText,
+		Live:         status.Status == "trading",
+	}
+	if klines, err := ms.GetKLineData(ctx, stock.Symbol, "1d", 5); err != nil {
+		log.Warn("获取日K用于数据时效失败: %s, %v", stock.Symbol, err)
+	} else if len(klines) > 0 {
+		f.KLineDate = datePart(klines[len(klines)-1].Time)
+	}
+	quoteTime, hasQuote := parseQuoteTime(stock.QuoteTime)
+
+	if f.Live {
+		f.AsOf = now.Format("2006-01-02 15:04")
+		if hasQuote {
+			f.AsOf = quoteTime.Format("2006-01-02 15:04")
+		}
+		return f
+	}
+	if status.Status == "lunch_break" {
+		f.AsOf = now.Format("2006-01-02") + " 11:30"
+		f.Disclaimer = fmt.Sprintf("数据截至 %d月%d日 11:30（午间休市）", now.Month(), now.Day())
+		return f
+	}
+
+	// 最近一次收盘：交易日收盘后为当天，否则为上一个交易日；停牌股以行情日期为准
+	closeDate := now
+	if !status.IsTradeDay || status.Status != "closed" {
+		closeDate = ms.previousTradeDate(now)
+	}
+	if hasQuote && quoteTime.Format("2006-01-02") < closeDate.Format("2006-01-02") {
+		closeDate = quoteTime
+	}
+	f.AsOf = closeDate.Format("2006-01-02") + " 15:00"
+	f.Disclaimer = fmt.Sprintf("数据截至 %d月%d日收盘", closeDate.Month(), closeDate.Day())
+	return f
+}
+
+// previousTradeDate now 之前（不含当天）最近的交易日
+func (ms *MarketService) previousTradeDate(now time.Time) time.Time {
+	for i := 1; i < maxCalendarScan; i++ {
+		if date := now.AddDate(0, 0, -i); ms.isTradeDate(date) {
+			return date
+		}
+	}
+	return now.AddDate(0, 0, -1)
+}
+
+// parseQuoteTime 解析新浪行情时间 "2025-03-07 15:00:00"，只有日期时按当天零点
+func parseQuoteTime(s string) (time.Time, bool) {
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", s, cacheEpochLoc); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", datePart(s), cacheEpochLoc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
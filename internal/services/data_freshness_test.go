@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestGetDataFreshness(t *testing.T) {
+	withHolidays(t, 2025, map[string]bool{
+		"2025-10-01": true, "2025-10-02": true, "2025-10-03": true, "2025-10-06": true, "2025-10-07": true, "2025-10-08": true,
+	})
+	// 日K获取失败只影响 KLineDate
+	ms := &MarketService{client: &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 10, day, hour, minute, 0, 0, cacheEpochLoc)
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		quoteTime  string
+		live       bool
+		asOf       string
+		disclaimer string
+	}{
+		{"盘中取行情时间", at(9, 10, 0), "2025-10-09 09:59:58", true, "2025-10-09 09:59", ""},
+		{"午间休市", at(9, 12, 0), "2025-10-09 11:30:00", false, "2025-10-09 11:30", "数据截至 10月9日 11:30（午间休市）"},
+		{"收盘后为当天", at(9, 16, 0), "2025-10-09 15:00:00", false, "2025-10-09 15:00", "数据截至 10月9日收盘"},
+		{"盘前为上一交易日（跨长假）", at(9, 8, 0), "2025-09-30 15:00:00", false, "2025-09-30 15:00", "数据截至 9月30日收盘"},
+		{"节假日", at(6, 10, 0), "", false, "2025-09-30 15:00", "数据截至 9月30日收盘"},
+		{"停牌股以行情日期为准", at(10, 16, 0), "2025-09-26 15:00:00", false, "2025-09-26 15:00", "数据截至 9月26日收盘"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := ms.GetDataFreshness(context.Background(), models.Stock{Symbol: "sh600519", QuoteTime: tt.quoteTime}, tt.now)
+			if f.Live != tt.live || f.AsOf != tt.asOf || f.Disclaimer != tt.disclaimer {
+				t.Errorf("got %+v", f)
+			}
+			if f.KLineDate != "" {
+				t.Errorf("kline date should be empty when fetch fails: %q", f.KLineDate)
+			}
+		})
+	}
+}
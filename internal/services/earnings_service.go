@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 东方财富数据中心API
+const (
+	eastmoneyDatacenterAPI = "https://datacenter-web.eastmoney.com/api/data/v1/get"
+	// 定期报告预约披露时间
+	earningsAppointReport = "RPT_PUBLIC_BS_APPOIN"
+	// 业绩预告
+	earningsForecastReport = "RPT_PUBLIC_OP_NEWPREDICT"
+	// 数据中心查询结果为空时的返回码
+	datacenterEmptyCode = 9201
+)
+
+// 财报日历查询参数
+const (
+	defaultEarningsDays = 30
+	maxEarningsDays     = 90
+	maxEarningsEvents   = 50 // 未指定股票时全市场最多返回的事件数
+)
+
+// EarningsCalendarItem 财报披露日历条目
+type EarningsCalendarItem struct {
+	Code           string  `json:"code"`           // 股票代码（不带市场前缀）
+	Name           string  `json:"name"`           // 股票名称
+	Date           string  `json:"date"`           // 预约披露日期 YYYY-MM-DD
+	ReportType     string  `json:"reportType"`     // 报告类型，如 "2025年三季报"
+	ForecastProfit float64 `json:"forecastProfit"` // 业绩预告归母净利润中值（元），无预告为0
+	PrevProfit     float64 `json:"prevProfit"`     // 上年同期归母净利润（元），无预告为0
+	reportDate     string  // 报告期，用于匹配业绩预告
+}
+
+// EarningsService 财报日历服务
+type EarningsService struct {
+	client *http.Client
+
+	summaryMu    sync.Mutex
+	summaryCache map[string]*financialSummaryCache // key: 代码:期数
+}
+
+// NewEarningsService 创建财报日历服务
+func NewEarningsService() *EarningsService {
+	return &EarningsService{
+		client:       proxy.GetManager().GetClientWithTimeout(15 * time.Second),
+		summaryCache: make(map[string]*financialSummaryCache),
+	}
+}
+
+// GetEarningsCalendar 获取未来 daysAhead 天内的财报披露日历
+// codes 为空时返回全市场最近的披露安排
+func (s *EarningsService) GetEarningsCalendar(ctx context.Context, codes []string, daysAhead int) ([]EarningsCalendarItem, error) {
+	if daysAhead <= 0 {
+		daysAhead = defaultEarningsDays
+	}
+	daysAhead = min(daysAhead, maxEarningsDays)
+
+	now := time.Now()
+	from := now.Format("2006-01-02")
+	to := now.AddDate(0, 0, daysAhead).Format("2006-01-02")
+
+	codes = normalizeEarningsCodes(codes)
+	var filter string
+	pageSize := maxEarningsEvents
+	if len(codes) > 0 {
+		// 个股按报告期倒序取最近几期，改期后的日期在本地重新判断
+		filter = fmt.Sprintf("(SECURITY_CODE in (%s))", quoteCodes(codes))
+		pageSize = len(codes) * 4
+	} else {
+		filter = fmt.Sprintf("(FIRST_APPOINT_DATE>='%s')(FIRST_APPOINT_DATE<='%s')", from, to)
+	}
+
+	body, err := s.queryDatacenter(ctx, earningsAppointReport, filter, "REPORT_DATE", pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items, err := parseEarningsAppointments(body, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) > maxEarningsEvents {
+		items = items[:maxEarningsEvents]
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	// 业绩预告仅作补充，失败不影响日历本身
+	eventCodes := make([]string, 0, len(items))
+	for _, item := range items {
+		eventCodes = append(eventCodes, item.Code)
+	}
+	forecastFilter := fmt.Sprintf("(SECURITY_CODE in (%s))", quoteCodes(eventCodes))
+	if body, err := s.queryDatacenter(ctx, earningsForecastReport, forecastFilter, "NOTICE_DATE", len(eventCodes)*4); err != nil {
+		log.Warn("获取业绩预告失败: %v", err)
+	} else if err := applyEarningsForecasts(items, body); err != nil {
+		log.Warn("解析业绩预告失败: %v", err)
+	}
+	return items, nil
+}
+
+// queryDatacenter 请求东方财富数据中心报表，按 sortColumn 倒序
+func (s *EarningsService) queryDatacenter(ctx context.Context, reportName, filter, sortColumn string, pageSize int) ([]byte, error) {
+	return queryEastmoneyDatacenter(ctx, s.client, eastmoneyDatacenterAPI, reportName, filter, sortColumn, pageSize)
+}
+
+// queryEastmoneyDatacenter 请求 apiURL 上的数据中心报表，apiURL 便于测试时替换
+func queryEastmoneyDatacenter(ctx context.Context, client *http.Client, apiURL, reportName, filter, sortColumn string, pageSize int) ([]byte, error) {
+	params := url.Values{}
+	params.Set("reportName", reportName)
+	params.Set("columns", "ALL")
+	params.Set("filter", filter)
+	params.Set("sortColumns", sortColumn)
+	params.Set("sortTypes", "-1")
+	params.Set("pageSize", fmt.Sprintf("%d", pageSize))
+	params.Set("pageNumber", "1")
+	params.Set("source", "WEB")
+	params.Set("client", "WEB")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://data.eastmoney.com/")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	return body, nil
+}
+
+// earningsAppointResponse 预约披露API响应结构
+type earningsAppointResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Result  *struct {
+		Data []struct {
+			SecurityCode     string `json:"SECURITY_CODE"`
+			SecurityNameAbbr string `json:"SECURITY_NAME_ABBR"`
+			ReportDate       string `json:"REPORT_DATE"`
+			ReportTypeName   string `json:"REPORT_TYPE_NAME"`
+			FirstAppointDate string `json:"FIRST_APPOINT_DATE"`
+			FirstChangeDate  string `json:"FIRST_CHANGE_DATE"`
+			SecondChangeDate string `json:"SECOND_CHANGE_DATE"`
+			ThirdChangeDate  string `json:"THIRD_CHANGE_DATE"`
+			ActualPublish    string `json:"ACTUAL_PUBLISH_DATE"`
+		} `json:"data"`
+	} `json:"result"`
+}
+
+// parseEarningsAppointments 解析预约披露数据，只保留 [from, to] 内尚未披露的事件，按日期升序
+func parseEarningsAppointments(body []byte, from, to string) ([]EarningsCalendarItem, error) {
+	var resp earningsAppointResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析预约披露数据失败: %w", err)
+	}
+	items := []EarningsCalendarItem{}
+	if resp.Result == nil {
+		// 查询结果为空时接口返回 code=9201 且 result 为 null
+		if resp.Code == datacenterEmptyCode {
+			return items, nil
+		}
+		return nil, fmt.Errorf("获取预约披露数据失败: %s", resp.Message)
+	}
+
+	for _, d := range resp.Result.Data {
+		if datePart(d.ActualPublish) != "" {
+			continue
+		}
+		// 以最近一次变更后的日期为准
+		date := datePart(d.FirstAppointDate)
+		for _, changed := range []string{d.FirstChangeDate, d.SecondChangeDate, d.ThirdChangeDate} {
+			if c := datePart(changed); c != "" {
+				date = c
+			}
+		}
+		if date == "" || date < from || date > to {
+			continue
+		}
+		items = append(items, EarningsCalendarItem{
+			Code:       d.SecurityCode,
+			Name:       d.SecurityNameAbbr,
+			Date:       date,
+			ReportType: d.ReportTypeName,
+			reportDate: datePart(d.ReportDate),
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Date < items[j].Date })
+	return items, nil
+}
+
+// earningsForecastResponse 业绩预告API响应结构
+type earningsForecastResponse struct {
+	Result *struct {
+		Data []struct {
+			SecurityCode      string   `json:"SECURITY_CODE"`
+			ReportDate        string   `json:"REPORT_DATE"`
+			PredictFinance    string   `json:"PREDICT_FINANCE"`
+			PredictAmtLower   *float64 `json:"PREDICT_AMT_LOWER"`
+			PredictAmtUpper   *float64 `json:"PREDICT_AMT_UPPER"`
+			PreyearSamePeriod *float64 `json:"PREYEAR_SAME_PERIOD"`
+		} `json:"data"`
+	} `json:"result"`
+}
+
+// applyEarningsForecasts 按股票代码与报告期填入业绩预告的归母净利润
+func applyEarningsForecasts(items []EarningsCalendarItem, body []byte) error {
+	var resp earningsForecastResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+	if resp.Result == nil {
+		return nil
+	}
+
+	type forecast struct{ estimate, previous float64 }
+	forecasts := make(map[string]forecast)
+	for _, d := range resp.Result.Data {
+		// 只取归母净利润，跳过扣非、营收等口径
+		if !strings.Contains(d.PredictFinance, "归属于") || strings.Contains(d.PredictFinance, "扣除") {
+			continue
+		}
+		key := d.SecurityCode + "|" + datePart(d.ReportDate)
+		if _, ok := forecasts[key]; ok {
+			continue // 按公告日期倒序，保留最新一次预告
+		}
+		var f forecast
+		switch {
+		case d.PredictAmtLower != nil && d.PredictAmtUpper != nil:
+			f.estimate = (*d.PredictAmtLower + *d.PredictAmtUpper) / 2
+		case d.PredictAmtLower != nil:
+			f.estimate = *d.PredictAmtLower
+		case d.PredictAmtUpper != nil:
+			f.estimate = *d.PredictAmtUpper
+		}
+		if d.PreyearSamePeriod != nil {
+			f.previous = *d.PreyearSamePeriod
+		}
+		forecasts[key] = f
+	}
+
+	for i := range items {
+		if f, ok := forecasts[items[i].Code+"|"+items[i].reportDate]; ok {
+			items[i].ForecastProfit = f.estimate
+			items[i].PrevProfit = f.previous
+		}
+	}
+	return nil
+}
+
+// normalizeEarningsCodes 去除市场前缀与重复代码
+func normalizeEarningsCodes(codes []string) []string {
+	seen := make(map[string]bool, len(codes))
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.ToLower(strings.TrimSpace(code))
+		for _, prefix := range []string{"sh", "sz", "bj"} {
+			code = strings.TrimPrefix(code, prefix)
+		}
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		result = append(result, code)
+	}
+	return result
+}
+
+// quoteCodes 将代码列表拼接为数据中心 in 过滤条件
+func quoteCodes(codes []string) string {
+	quoted := make([]string, len(codes))
+	for i, code := range codes {
+		quoted[i] = `"` + code + `"`
+	}
+	return strings.Join(quoted, ",")
+}
+
+// datePart 截取 "2025-10-28 00:00:00" 中的日期部分
+func datePart(s string) string {
+	if len(s) > 10 {
+		return s[:10]
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEarningsAppointments(t *testing.T) {
+	body := []byte(`{"success":true,"message":"ok","code":0,"result":{"data":[
+		{"SECURITY_CODE":"600519","SECURITY_NAME_ABBR":"贵州茅台","REPORT_DATE":"2025-09-30 00:00:00","REPORT_TYPE_NAME":"2025年三季报","FIRST_APPOINT_DATE":"2025-10-25 00:00:00","FIRST_CHANGE_DATE":"2025-10-29 00:00:00","SECOND_CHANGE_DATE":null,"THIRD_CHANGE_DATE":null,"ACTUAL_PUBLISH_DATE":null},
+		{"SECURITY_CODE":"000001","SECURITY_NAME_ABBR":"平安银行","REPORT_DATE":"2025-09-30 00:00:00","REPORT_TYPE_NAME":"2025年三季报","FIRST_APPOINT_DATE":"2025-10-20 00:00:00","FIRST_CHANGE_DATE":null,"SECOND_CHANGE_DATE":null,"THIRD_CHANGE_DATE":null,"ACTUAL_PUBLISH_DATE":null},
+		{"SECURITY_CODE":"000002","SECURITY_NAME_ABBR":"万科A","REPORT_DATE":"2025-09-30 00:00:00","REPORT_TYPE_NAME":"2025年三季报","FIRST_APPOINT_DATE":"2025-10-21 00:00:00","ACTUAL_PUBLISH_DATE":"2025-10-21 00:00:00"},
+		{"SECURITY_CODE":"600000","SECURITY_NAME_ABBR":"浦发银行","REPORT_DATE":"2025-06-30 00:00:00","REPORT_TYPE_NAME":"2025年中报","FIRST_APPOINT_DATE":"2025-08-30 00:00:00"}
+	]}}`)
+
+	items, err := parseEarningsAppointments(body, "2025-10-16", "2025-11-15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 已披露与窗口外的事件被过滤，改期后以最新日期为准并按日期升序
+	if len(items) != 2 || items[0].Code != "000001" || items[1].Date != "2025-10-29" {
+		t.Fatalf("items = %+v", items)
+	}
+
+	forecast := []byte(`{"result":{"data":[
+		{"SECURITY_CODE":"600519","REPORT_DATE":"2025-09-30 00:00:00","PREDICT_FINANCE":"扣除非经常性损益后的净利润","PREDICT_AMT_LOWER":1,"PREDICT_AMT_UPPER":1,"PREYEAR_SAME_PERIOD":1},
+		{"SECURITY_CODE":"600519","REPORT_DATE":"2025-09-30 00:00:00","PREDICT_FINANCE":"归属于上市公司股东的净利润","PREDICT_AMT_LOWER":6000000000,"PREDICT_AMT_UPPER":7000000000,"PREYEAR_SAME_PERIOD":6080000000},
+		{"SECURITY_CODE":"600519","REPORT_DATE":"2025-09-30 00:00:00","PREDICT_FINANCE":"归属于上市公司股东的净利润","PREDICT_AMT_LOWER":1,"PREDICT_AMT_UPPER":1,"PREYEAR_SAME_PERIOD":1},
+		{"SECURITY_CODE":"000001","REPORT_DATE":"2025-06-30 00:00:00","PREDICT_FINANCE":"归属于上市公司股东的净利润","PREDICT_AMT_LOWER":5,"PREDICT_AMT_UPPER":null,"PREYEAR_SAME_PERIOD":4}
+	]}}`)
+	if err := applyEarningsForecasts(items, forecast); err != nil {
+		t.Fatal(err)
+	}
+	if items[1].ForecastProfit != 6.5e9 || items[1].PrevProfit != 6.08e9 {
+		t.Fatalf("应取最新的归母净利润预告: %+v", items[1])
+	}
+	if items[0].ForecastProfit != 0 {
+		t.Fatalf("报告期不同不应匹配: %+v", items[0])
+	}
+
+	empty, err := parseEarningsAppointments([]byte(`{"success":false,"message":"返回数据为空","code":9201,"result":null}`), "2025-10-16", "2025-11-15")
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("空结果 = %+v, %v", empty, err)
+	}
+	if _, err := parseEarningsAppointments([]byte(`{"success":false,"message":"参数错误","code":9501,"result":null}`), "2025-10-16", "2025-11-15"); err == nil {
+		t.Fatal("接口错误应返回 error")
+	}
+}
+
+func TestNormalizeEarningsCodes(t *testing.T) {
+	got := normalizeEarningsCodes([]string{"sh600519", " SZ000001 ", "600519", "", "bj430047"})
+	want := []string{"600519", "000001", "430047"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// utf8BOM 写在 CSV 开头，Excel 据此按 UTF-8 识别中文
+const utf8BOM = "\ufeff"
+
+// exportProgressStep 每写入多少行回调一次进度
+const exportProgressStep = 500
+
+// ExportProgress 导出进度回调（done/total 为行数，total 未知时为 0）
+type ExportProgress func(done, total int)
+
+// KLineCSVHeader K线导出列顺序
+// 时间,开盘,最高,最低,收盘,成交量,成交额,MA5,MA10,MA20
+var KLineCSVHeader = []string{"时间", "开盘", "最高", "最低", "收盘", "成交量", "成交额", "MA5", "MA10", "MA20"}
+
+// PositionCSVHeader 持仓导出列顺序
+// 股票代码,股票名称,持仓数量,成本价,现价,持仓成本,持仓市值,盈亏,盈亏比例(%)
+var PositionCSVHeader = []string{"股票代码", "股票名称", "持仓数量", "成本价", "现价", "持仓成本", "持仓市值", "盈亏", "盈亏比例(%)"}
+
+// LongHuBangCSVHeader 龙虎榜导出列顺序（金额单位：元）
+// 交易日期,股票代码,股票名称,收盘价,涨跌幅(%),换手率(%),净买额,买入额,卖出额,成交额,流通市值,成交占比(%),净买占比(%),上榜原因,原因详情
+var LongHuBangCSVHeader = []string{"交易日期", "股票代码", "股票名称", "收盘价", "涨跌幅(%)", "换手率(%)", "净买额", "买入额", "卖出额", "成交额", "流通市值", "成交占比(%)", "净买占比(%)", "上榜原因", "原因详情"}
+
+// PositionRecord 持仓导出记录
+type PositionRecord struct {
+	Code      string
+	Name      string
+	Shares    int64
+	CostPrice float64
+	Price     float64 // 现价，未获取到时为 0
+}
+
+// WriteCSV 写入带 BOM 的 CSV，字段中的逗号、引号、换行由 encoding/csv 转义
+func WriteCSV(w io.Writer, header []string, rows [][]string, progress ExportProgress) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true // Excel 默认换行
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+		if progress != nil && (i+1)%exportProgressStep == 0 {
+			progress(i+1, len(rows))
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	if progress != nil {
+		progress(len(rows), len(rows))
+	}
+	return nil
+}
+
+// WriteCSVFile 写入 CSV 文件
+func WriteCSVFile(path string, header []string, rows [][]string, progress ExportProgress) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	bw := bufio.NewWriter(f)
+	if err := WriteCSV(bw, header, rows, progress); err != nil {
+		f.Close()
+		return fmt.Errorf("写入 CSV 失败: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("写入 CSV 失败: %w", err)
+	}
+	return f.Close()
+}
+
+// KLineCSVRows 转换 K 线数据为 CSV 行
+func KLineCSVRows(klines []models.KLineData) [][]string {
+	rows := make([][]string, 0, len(klines))
+	for _, k := range klines {
+		rows = append(rows, []string{
+			k.Time,
+			formatFloat(k.Open), formatFloat(k.High), formatFloat(k.Low), formatFloat(k.Close),
+			strconv.FormatInt(k.Volume, 10), formatFloat(k.Amount),
+			formatOptional(k.MA5), formatOptional(k.MA10), formatOptional(k.MA20),
+		})
+	}
+	return rows
+}
+
+// PositionCSVRows 转换持仓记录为 CSV 行
+func PositionCSVRows(records []PositionRecord) [][]string {
+	rows := make([][]string, 0, len(records))
+	for _, r := range records {
+		cost := float64(r.Shares) * r.CostPrice
+		row := []string{r.Code, r.Name, strconv.FormatInt(r.Shares, 10), formatFloat(r.CostPrice)}
+		if r.Price > 0 {
+			value := float64(r.Shares) * r.Price
+			profitPercent := ""
+			if cost > 0 {
+				profitPercent = formatFloat((value - cost) / cost * 100)
+			}
+			row = append(row, formatFloat(r.Price), formatFloat(cost), formatFloat(value), formatFloat(value-cost), profitPercent)
+		} else {
+			// 未获取到现价时，市值与盈亏留空
+			row = append(row, "", formatFloat(cost), "", "", "")
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// LongHuBangCSVRows 转换龙虎榜数据为 CSV 行
+func LongHuBangCSVRows(items []models.LongHuBangItem) [][]string {
+	rows := make([][]string, 0, len(items))
+	for _, it := range items {
+		rows = append(rows, []string{
+			it.TradeDate, it.Code, it.Name,
+			formatFloat(it.ClosePrice), formatFloat(it.ChangePercent), formatFloat(it.TurnoverRate),
+			formatFloat(it.NetBuyAmt), formatFloat(it.BuyAmt), formatFloat(it.SellAmt), formatFloat(it.TotalAmt),
+			formatFloat(it.FreeCap), formatFloat(it.DealRatio), formatFloat(it.NetRatio),
+			it.Reason, it.ReasonDetail,
+		})
+	}
+	return rows
+}
+
+// formatFloat 保留必要精度，避免科学计数法
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatOptional 0 视为无数据（如均线在数据不足时为空）
+func formatOptional(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return formatFloat(v)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestWriteCSV_BOMAndEscaping(t *testing.T) {
+	items := []models.LongHuBangItem{{
+		TradeDate: "2024-01-05", Code: "600519", Name: "贵州茅台",
+		ClosePrice: 1688.5, NetBuyAmt: -12345678.9,
+		Reason:       "日涨幅偏离值达7%的证券",
+		ReasonDetail: `机构专用,"深股通"席位`,
+	}}
+
+	var buf bytes.Buffer
+	var lastDone, lastTotal int
+	if err := WriteCSV(&buf, LongHuBangCSVHeader, LongHuBangCSVRows(items), func(done, total int) {
+		lastDone, lastTotal = done, total
+	}); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	if lastDone != 1 || lastTotal != 1 {
+		t.Errorf("progress = %d/%d, want 1/1", lastDone, lastTotal)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, utf8BOM) {
+		t.Fatal("missing UTF-8 BOM")
+	}
+	if !strings.Contains(out, `"机构专用,""深股通""席位"`) {
+		t.Errorf("field with comma/quote not escaped:\n%s", out)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(out, utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("rows = %d, want 2", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(LongHuBangCSVHeader, ",") {
+		t.Errorf("header = %v", records[0])
+	}
+	row := records[1]
+	if len(row) != len(LongHuBangCSVHeader) {
+		t.Fatalf("columns = %d, want %d", len(row), len(LongHuBangCSVHeader))
+	}
+	if row[1] != "600519" || row[3] != "1688.5" || row[6] != "-12345678.9" || row[14] != `机构专用,"深股通"席位` {
+		t.Errorf("row = %v", row)
+	}
+}
+
+func TestKLineAndPositionRows(t *testing.T) {
+	klines := KLineCSVRows([]models.KLineData{{Time: "2024-01-05", Open: 10, High: 11, Low: 9.5, Close: 10.8, Volume: 123456, Amount: 1.5e8, MA5: 10.2}})
+	want := []string{"2024-01-05", "10", "11", "9.5", "10.8", "123456", "150000000", "10.2", "", ""}
+	if strings.Join(klines[0], "|") != strings.Join(want, "|") {
+		t.Errorf("kline row = %v, want %v", klines[0], want)
+	}
+
+	rows := PositionCSVRows([]PositionRecord{
+		{Code: "sh600519", Name: "贵州茅台", Shares: 100, CostPrice: 1500, Price: 1650},
+		{Code: "sz000001", Name: "平安银行", Shares: 200, CostPrice: 10},
+	})
+	if got := strings.Join(rows[0], "|"); got != "sh600519|贵州茅台|100|1500|1650|150000|165000|15000|10" {
+		t.Errorf("position row = %s", got)
+	}
+	// 无现价时市值与盈亏留空
+	if got := strings.Join(rows[1], "|"); got != "sz000001|平安银行|200|10||2000|||" {
+		t.Errorf("position row without price = %s", got)
+	}
+}
+
+func TestWriteCSVFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	if err := WriteCSVFile(path, KLineCSVHeader, nil, nil); err != nil {
+		t.Fatalf("WriteCSVFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := utf8BOM + strings.Join(KLineCSVHeader, ",") + "\r\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 财务报表类型
+const (
+	FinancialIncome   = "income"   // 利润表
+	FinancialBalance  = "balance"  // 资产负债表
+	FinancialCashflow = "cashflow" // 现金流量表
+	FinancialSummary  = "summary"  // 三大报表要点及衍生比率，见 GetFinancialSummary
+)
+
+// 财务报表查询参数
+const (
+	defaultFinancialQuarters = 4
+	maxFinancialQuarters     = 12
+)
+
+// financialMetric 报表字段，Ratio 为百分比，其余为金额（元）
+type financialMetric struct {
+	Field string
+	Label string
+	Ratio bool
+}
+
+// financialReports 各报表对应的数据中心报表名与展示字段
+var financialReports = map[string]struct {
+	report  string
+	metrics []financialMetric
+}{
+	FinancialIncome: {"RPT_DMSK_FN_INCOME", []financialMetric{
+		{Field: "TOTAL_OPERATE_INCOME", Label: "营业总收入"},
+		{Field: "OPERATE_INCOME", Label: "营业收入"},
+		{Field: "TOI_RATIO", Label: "营收同比", Ratio: true},
+		{Field: "OPERATE_COST", Label: "营业成本"},
+		{Field: "SALE_EXPENSE", Label: "销售费用"},
+		{Field: "MANAGE_EXPENSE", Label: "管理费用"},
+		{Field: "FINANCE_EXPENSE", Label: "财务费用"},
+		{Field: "OPERATE_PROFIT", Label: "营业利润"},
+		{Field: "TOTAL_PROFIT", Label: "利润总额"},
+		{Field: "PARENT_NETPROFIT", Label: "归母净利润"},
+		{Field: "PARENT_NETPROFIT_RATIO", Label: "归母净利同比", Ratio: true},
+		{Field: "DEDUCT_PARENT_NETPROFIT", Label: "扣非净利润"},
+	}},
+	FinancialBalance: {"RPT_DMSK_FN_BALANCE", []financialMetric{
+		{Field: "TOTAL_ASSETS", Label: "总资产"},
+		{Field: "TOTAL_ASSETS_RATIO", Label: "总资产同比", Ratio: true},
+		{Field: "MONETARYFUNDS", Label: "货币资金"},
+		{Field: "ACCOUNTS_RECE", Label: "应收账款"},
+		{Field: "INVENTORY", Label: "存货"},
+		{Field: "TOTAL_LIABILITIES", Label: "总负债"},
+		{Field: "ACCOUNTS_PAYABLE", Label: "应付账款"},
+		{Field: "ADVANCE_RECEIVABLES", Label: "预收账款"},
+		{Field: "DEBT_ASSET_RATIO", Label: "资产负债率", Ratio: true},
+		{Field: "TOTAL_EQUITY", Label: "股东权益"},
+	}},
+	FinancialCashflow: {"RPT_DMSK_FN_CASHFLOW", []financialMetric{
+		{Field: "NETCASH_OPERATE", Label: "经营现金流净额"},
+		{Field: "NETCASH_OPERATE_RATIO", Label: "经营现金流同比", Ratio: true},
+		{Field: "SALES_SERVICES", Label: "销售收现"},
+		{Field: "NETCASH_INVEST", Label: "投资现金流净额"},
+		{Field: "NETCASH_FINANCE", Label: "筹资现金流净额"},
+		{Field: "CCE_ADD", Label: "现金净增加额"},
+	}},
+}
+
+// FinancialRow 报表中的一项指标，Values 与 FinancialReport.Periods 一一对应，缺失为 nil
+type FinancialRow struct {
+	Label  string
+	Ratio  bool // 百分比指标，否则为金额（元）
+	Values []*float64
+}
+
+// FinancialReport 单只股票最近若干期的财务报表，报告期倒序
+// 季报数据为年初至报告期末的累计值
+type FinancialReport struct {
+	Code    string
+	Name    string
+	Periods []string // 报告期，如 2025Q3
+	Rows    []FinancialRow
+}
+
+// GetFinancialReport 获取个股最近 quarters 期的财务报表，reportType 为 income/balance/cashflow
+func (s *EarningsService) GetFinancialReport(ctx context.Context, code, reportType string, quarters int) (*FinancialReport, error) {
+	if reportType == "" {
+		reportType = FinancialIncome
+	}
+	spec, ok := financialReports[reportType]
+	if !ok {
+		return nil, fmt.Errorf("不支持的报表类型: %s", reportType)
+	}
+	codes := normalizeEarningsCodes([]string{code})
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("股票代码不能为空")
+	}
+	if quarters <= 0 {
+		quarters = defaultFinancialQuarters
+	}
+	quarters = min(quarters, maxFinancialQuarters)
+
+	filter := fmt.Sprintf(`(SECURITY_CODE="%s")`, codes[0])
+	body, err := s.queryDatacenter(ctx, spec.report, filter, "REPORT_DATE", quarters)
+	if err != nil {
+		return nil, err
+	}
+	report, err := parseFinancialReport(body, spec.metrics)
+	if err != nil {
+		return nil, err
+	}
+	report.Code = codes[0]
+	return report, nil
+}
+
+// financialReportResponse 财务报表API响应结构，字段随报表不同按名称读取
+type financialReportResponse struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Result  *struct {
+		Data []map[string]any `json:"data"`
+	} `json:"result"`
+}
+
+// parseFinancialReport 按字段列表解析报表数据，保持接口返回的报告期倒序
+func parseFinancialReport(body []byte, metrics []financialMetric) (*FinancialReport, error) {
+	var resp financialReportResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析财务报表失败: %w", err)
+	}
+	report := &FinancialReport{Periods: []string{}}
+	if resp.Result == nil {
+		if resp.Code == datacenterEmptyCode {
+			return report, nil
+		}
+		return nil, fmt.Errorf("获取财务报表失败: %s", resp.Message)
+	}
+
+	for _, m := range metrics {
+		report.Rows = append(report.Rows, FinancialRow{Label: m.Label, Ratio: m.Ratio})
+	}
+	for _, d := range resp.Result.Data {
+		date, _ := d["REPORT_DATE"].(string)
+		report.Periods = append(report.Periods, quarterLabel(datePart(date)))
+		if name, ok := d["SECURITY_NAME_ABBR"].(string); ok && report.Name == "" {
+			report.Name = name
+		}
+		for i, m := range metrics {
+			var value *float64
+			if v, ok := d[m.Field].(float64); ok {
+				value = &v
+			}
+			report.Rows[i].Values = append(report.Rows[i].Values, value)
+		}
+	}
+	return report, nil
+}
+
+// quarterLabel 将报告期 2025-09-30 转为 2025Q3，无法识别时原样返回
+func quarterLabel(date string) string {
+	year, monthDay, ok := strings.Cut(date, "-")
+	if !ok {
+		return date
+	}
+	switch monthDay {
+	case "03-31":
+		return year + "Q1"
+	case "06-30":
+		return year + "Q2"
+	case "09-30":
+		return year + "Q3"
+	case "12-31":
+		return year + "Q4"
+	}
+	return date
+}
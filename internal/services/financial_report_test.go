@@ -0,0 +1,46 @@
+package services
+
+import "testing"
+
+func TestParseFinancialReport(t *testing.T) {
+	body := []byte(`{"success":true,"message":"ok","code":0,"result":{"data":[
+		{"SECURITY_CODE":"600519","SECURITY_NAME_ABBR":"贵州茅台","REPORT_DATE":"2025-09-30 00:00:00","TOTAL_OPERATE_INCOME":130903889634.88,"TOI_RATIO":6.32,"PARENT_NETPROFIT":64626746712.64},
+		{"SECURITY_CODE":"600519","SECURITY_NAME_ABBR":"贵州茅台","REPORT_DATE":"2025-06-30 00:00:00","TOTAL_OPERATE_INCOME":91094322180.94,"TOI_RATIO":null,"PARENT_NETPROFIT":45402667411.89}
+	]}}`)
+	metrics := []financialMetric{
+		{Field: "TOTAL_OPERATE_INCOME", Label: "营业总收入"},
+		{Field: "TOI_RATIO", Label: "营收同比", Ratio: true},
+		{Field: "PARENT_NETPROFIT", Label: "归母净利润"},
+	}
+
+	report, err := parseFinancialReport(body, metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Name != "贵州茅台" || len(report.Periods) != 2 || report.Periods[0] != "2025Q3" || report.Periods[1] != "2025Q2" {
+		t.Fatalf("report = %+v", report)
+	}
+	if len(report.Rows) != 3 || !report.Rows[1].Ratio || *report.Rows[1].Values[0] != 6.32 {
+		t.Fatalf("rows = %+v", report.Rows)
+	}
+	// 接口返回 null 的字段为缺失
+	if report.Rows[1].Values[1] != nil {
+		t.Errorf("null 应解析为缺失: %v", *report.Rows[1].Values[1])
+	}
+
+	empty, err := parseFinancialReport([]byte(`{"success":false,"message":"返回数据为空","code":9201,"result":null}`), metrics)
+	if err != nil || len(empty.Periods) != 0 {
+		t.Fatalf("空结果 = %+v, %v", empty, err)
+	}
+	if _, err := parseFinancialReport([]byte(`{"success":false,"message":"参数错误","code":9501,"result":null}`), metrics); err == nil {
+		t.Fatal("接口错误应返回 error")
+	}
+}
+
+func TestQuarterLabel(t *testing.T) {
+	for in, want := range map[string]string{"2025-03-31": "2025Q1", "2024-12-31": "2024Q4", "2025-05-01": "2025-05-01", "": ""} {
+		if got := quarterLabel(in); got != want {
+			t.Errorf("quarterLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 财务摘要查询参数
+const (
+	defaultSummaryQuarters = 8
+	financialSummaryTTL    = 24 * time.Hour // 财报盘中不会变化，按天缓存
+	financialSummaryMax    = 64             // 缓存的股票/期数组合上限，超出时淘汰最早的
+)
+
+// financialHighlights 三大报表纳入摘要的科目（按 FinancialRow.Label 选取）
+var financialHighlights = []struct {
+	reportType string
+	labels     []string
+}{
+	{FinancialIncome, []string{"营业总收入", "营业收入", "营收同比", "归母净利润", "归母净利同比", "扣非净利润"}},
+	{FinancialBalance, []string{"总资产", "货币资金", "总负债", "股东权益"}},
+	{FinancialCashflow, []string{"经营现金流净额", "投资现金流净额", "筹资现金流净额"}},
+}
+
+// 衍生比率名称，均为百分比
+const (
+	ratioGrossMargin = "毛利率"
+	ratioROE         = "ROE(年初累计)"
+	ratioDebt        = "资产负债率"
+	ratioOCFProfit   = "经营现金流/归母净利"
+)
+
+// financialSummaryCache 财务摘要缓存
+type financialSummaryCache struct {
+	data      *FinancialReport
+	timestamp time.Time
+}
+
+// RegisterCaches 将财务摘要缓存注册到缓存纪元服务，零点清空
+func (s *EarningsService) RegisterCaches(e *CacheEpochs) {
+	e.Register("financial_summary", CacheDaily, func() {
+		s.summaryMu.Lock()
+		s.summaryCache = make(map[string]*financialSummaryCache)
+		s.summaryMu.Unlock()
+	})
+}
+
+// GetFinancialSummary 获取个股最近 quarters 期三大报表要点及毛利率、ROE、资产负债率、现金流/净利润等衍生比率
+// 三张报表按报告期对齐，报告期倒序；结果缓存一天
+func (s *EarningsService) GetFinancialSummary(ctx context.Context, code string, quarters int) (*FinancialReport, error) {
+	codes := normalizeEarningsCodes([]string{code})
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("股票代码不能为空")
+	}
+	if quarters <= 0 {
+		quarters = defaultSummaryQuarters
+	}
+	quarters = min(quarters, maxFinancialQuarters)
+
+	key := fmt.Sprintf("%s:%d", codes[0], quarters)
+	s.summaryMu.Lock()
+	if c, ok := s.summaryCache[key]; ok && time.Since(c.timestamp) < financialSummaryTTL {
+		s.summaryMu.Unlock()
+		return c.data, nil
+	}
+	s.summaryMu.Unlock()
+
+	reports := make(map[string]*FinancialReport, len(financialHighlights))
+	for _, h := range financialHighlights {
+		report, err := s.GetFinancialReport(ctx, codes[0], h.reportType, quarters)
+		if err != nil {
+			return nil, err
+		}
+		reports[h.reportType] = report
+	}
+	summary := buildFinancialSummary(reports)
+	summary.Code = codes[0]
+
+	s.summaryMu.Lock()
+	s.summaryCache[key] = &financialSummaryCache{data: summary, timestamp: time.Now()}
+	if len(s.summaryCache) > financialSummaryMax {
+		s.evictOldestSummary()
+	}
+	s.summaryMu.Unlock()
+	return summary, nil
+}
+
+// evictOldestSummary 淘汰最早获取的摘要，调用方持有锁
+func (s *EarningsService) evictOldestSummary() {
+	oldest := ""
+	for key, c := range s.summaryCache {
+		if oldest == "" || c.timestamp.Before(s.summaryCache[oldest].timestamp) {
+			oldest = key
+		}
+	}
+	delete(s.summaryCache, oldest)
+}
+
+// buildFinancialSummary 合并三张报表的要点并计算衍生比率
+// 报告期取利润表的报告期（缺失时依次取资产负债表、现金流量表），其他报表缺少该期时记为缺失
+func buildFinancialSummary(reports map[string]*FinancialReport) *FinancialReport {
+	summary := &FinancialReport{Periods: []string{}}
+	for _, h := range financialHighlights {
+		if r := reports[h.reportType]; r != nil && len(r.Periods) > 0 {
+			if len(summary.Periods) == 0 {
+				summary.Periods = r.Periods
+			}
+			if summary.Name == "" {
+				summary.Name = r.Name
+			}
+		}
+	}
+	if len(summary.Periods) == 0 {
+		return summary
+	}
+
+	// lookup 按报告期对齐后的科目数值
+	lookup := func(reportType, label string) []*float64 {
+		values := make([]*float64, len(summary.Periods))
+		r := reports[reportType]
+		if r == nil {
+			return values
+		}
+		for _, row := range r.Rows {
+			if row.Label != label {
+				continue
+			}
+			byPeriod := make(map[string]*float64, len(r.Periods))
+			for i, p := range r.Periods {
+				if i < len(row.Values) {
+					byPeriod[p] = row.Values[i]
+				}
+			}
+			for i, p := range summary.Periods {
+				values[i] = byPeriod[p]
+			}
+		}
+		return values
+	}
+
+	for _, h := range financialHighlights {
+		for _, label := range h.labels {
+			summary.Rows = append(summary.Rows, FinancialRow{
+				Label: label, Ratio: isRatioMetric(h.reportType, label), Values: lookup(h.reportType, label),
+			})
+		}
+	}
+
+	// 毛利率按营业收入计算：营业总收入含利息、手续费等收入，与营业成本口径不一致
+	// 接口未返回营业收入（OPERATE_INCOME）的报告期退回营业总收入
+	revenue, cost := lookup(FinancialIncome, "营业收入"), lookup(FinancialIncome, "营业成本")
+	totalRevenue := lookup(FinancialIncome, "营业总收入")
+	profit, equity := lookup(FinancialIncome, "归母净利润"), lookup(FinancialBalance, "股东权益")
+	assets, liabilities := lookup(FinancialBalance, "总资产"), lookup(FinancialBalance, "总负债")
+	debtRatio, ocf := lookup(FinancialBalance, "资产负债率"), lookup(FinancialCashflow, "经营现金流净额")
+
+	gross := make([]*float64, len(summary.Periods))
+	roe := make([]*float64, len(summary.Periods))
+	debt := make([]*float64, len(summary.Periods))
+	ocfProfit := make([]*float64, len(summary.Periods))
+	for i := range summary.Periods {
+		if revenue[i] == nil {
+			revenue[i] = totalRevenue[i]
+		}
+		if revenue[i] != nil && cost[i] != nil {
+			gross[i] = percentOf(*revenue[i]-*cost[i], *revenue[i])
+		}
+		if profit[i] != nil && equity[i] != nil {
+			roe[i] = percentOf(*profit[i], *equity[i])
+		}
+		// 优先按总负债/总资产计算，缺失时使用接口提供的资产负债率
+		debt[i] = debtRatio[i]
+		if assets[i] != nil && liabilities[i] != nil {
+			if v := percentOf(*liabilities[i], *assets[i]); v != nil {
+				debt[i] = v
+			}
+		}
+		if ocf[i] != nil && profit[i] != nil && *profit[i] > 0 {
+			ocfProfit[i] = percentOf(*ocf[i], *profit[i])
+		}
+	}
+	summary.Rows = append(summary.Rows,
+		FinancialRow{Label: ratioGrossMargin, Ratio: true, Values: gross},
+		FinancialRow{Label: ratioROE, Ratio: true, Values: roe},
+		FinancialRow{Label: ratioDebt, Ratio: true, Values: debt},
+		FinancialRow{Label: ratioOCFProfit, Ratio: true, Values: ocfProfit},
+	)
+	return summary
+}
+
+// isRatioMetric 报表科目是否为百分比指标
+func isRatioMetric(reportType, label string) bool {
+	for _, m := range financialReports[reportType].metrics {
+		if m.Label == label {
+			return m.Ratio
+		}
+	}
+	return false
+}
+
+// percentOf 计算 a/b 的百分比，分母不为正时返回 nil
+func percentOf(a, b float64) *float64 {
+	if b <= 0 {
+		return nil
+	}
+	v := a / b * 100
+	return &v
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildFinancialSummary(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	reports := map[string]*FinancialReport{
+		FinancialIncome: {Name: "贵州茅台", Periods: []string{"2025Q3", "2025Q2"}, Rows: []FinancialRow{
+			// 营业总收入含利息等收入，毛利率只按营业收入计算
+			{Label: "营业总收入", Values: []*float64{f(1100), f(700)}},
+			{Label: "营业收入", Values: []*float64{f(1000), f(600)}},
+			{Label: "营收同比", Ratio: true, Values: []*float64{f(6.3), nil}},
+			{Label: "营业成本", Values: []*float64{f(100), f(90)}},
+			{Label: "归母净利润", Values: []*float64{f(500), f(-10)}},
+		}},
+		// 资产负债表缺少 2025Q2，按报告期对齐后记为缺失
+		FinancialBalance: {Periods: []string{"2025Q3"}, Rows: []FinancialRow{
+			{Label: "总资产", Values: []*float64{f(3000)}},
+			{Label: "总负债", Values: []*float64{f(600)}},
+			{Label: "股东权益", Values: []*float64{f(2400)}},
+		}},
+		FinancialCashflow: {Periods: []string{"2025Q3", "2025Q2"}, Rows: []FinancialRow{
+			{Label: "经营现金流净额", Values: []*float64{f(450), f(20)}},
+		}},
+	}
+
+	summary := buildFinancialSummary(reports)
+	if summary.Name != "贵州茅台" || len(summary.Periods) != 2 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	rows := make(map[string]FinancialRow, len(summary.Rows))
+	for _, row := range summary.Rows {
+		rows[row.Label] = row
+	}
+	near := func(p *float64, want float64) bool { return p != nil && math.Abs(*p-want) < 1e-9 }
+
+	if !rows["营收同比"].Ratio || !near(rows["营收同比"].Values[0], 6.3) {
+		t.Errorf("营收同比 = %+v", rows["营收同比"])
+	}
+	if v := rows[ratioGrossMargin].Values; !near(v[0], 90) || !near(v[1], 85) {
+		t.Errorf("毛利率 = %v", v)
+	}
+	if v := rows[ratioROE].Values; !near(v[0], 500.0/2400*100) || v[1] != nil {
+		t.Errorf("ROE = %v", v)
+	}
+	if v := rows[ratioDebt].Values; !near(v[0], 20) || v[1] != nil {
+		t.Errorf("资产负债率 = %v", v)
+	}
+	// 净利润为负时现金流/净利润没有意义
+	if v := rows[ratioOCFProfit].Values; !near(v[0], 90) || v[1] != nil {
+		t.Errorf("现金流/净利润 = %v", v)
+	}
+	if rows["总资产"].Values[1] != nil {
+		t.Error("missing balance period should be nil")
+	}
+
+	if empty := buildFinancialSummary(map[string]*FinancialReport{}); len(empty.Periods) != 0 || len(empty.Rows) != 0 {
+		t.Errorf("empty = %+v", empty)
+	}
+}
+
+// 利润表响应不含 OPERATE_INCOME 时毛利率按营业总收入计算，不应整列缺失
+func TestBuildFinancialSummary_NoOperateIncome(t *testing.T) {
+	body := []byte(`{"success":true,"message":"ok","code":0,"result":{"data":[
+		{"SECURITY_CODE":"600519","SECURITY_NAME_ABBR":"贵州茅台","REPORT_DATE":"2025-09-30 00:00:00","TOTAL_OPERATE_INCOME":130903889634.88,"TOI_RATIO":6.32,"OPERATE_COST":11296264283.54,"PARENT_NETPROFIT":64626746712.64},
+		{"SECURITY_CODE":"600519","SECURITY_NAME_ABBR":"贵州茅台","REPORT_DATE":"2025-06-30 00:00:00","TOTAL_OPERATE_INCOME":91094322180.94,"TOI_RATIO":9.16,"OPERATE_COST":null,"PARENT_NETPROFIT":45402667411.89}
+	]}}`)
+	income, err := parseFinancialReport(body, financialReports[FinancialIncome].metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := buildFinancialSummary(map[string]*FinancialReport{FinancialIncome: income})
+	for _, row := range summary.Rows {
+		if row.Label != ratioGrossMargin {
+			continue
+		}
+		want := (130903889634.88 - 11296264283.54) / 130903889634.88 * 100
+		if row.Values[0] == nil || math.Abs(*row.Values[0]-want) > 1e-6 || row.Values[1] != nil {
+			t.Errorf("毛利率 = %v, want %.4f", row.Values, want)
+		}
+		return
+	}
+	t.Fatal("missing gross margin row")
+}
+
+func TestEvictOldestSummary(t *testing.T) {
+	s := NewEarningsService()
+	now := time.Now()
+	for i := 0; i <= financialSummaryMax; i++ {
+		s.summaryCache[fmt.Sprintf("%06d:8", i)] = &financialSummaryCache{timestamp: now.Add(time.Duration(i) * time.Second)}
+	}
+	s.evictOldestSummary()
+	if len(s.summaryCache) != financialSummaryMax {
+		t.Fatalf("len = %d", len(s.summaryCache))
+	}
+	if _, ok := s.summaryCache["000000:8"]; ok {
+		t.Error("oldest entry should be evicted")
+	}
+}
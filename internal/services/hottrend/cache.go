@@ -16,19 +16,21 @@ type CacheEntry struct {
 
 // FileCache 文件缓存管理器
 type FileCache struct {
-	cacheDir string
-	ttl      time.Duration
-	mu       sync.RWMutex
+	cacheDir  string
+	ttl       time.Duration // 新鲜期，期内直接使用缓存
+	retention time.Duration // 保留期，平台获取失败时可回退使用的最长缓存年龄
+	mu        sync.RWMutex
 }
 
 // NewFileCache 创建文件缓存
-func NewFileCache(cacheDir string, ttl time.Duration) (*FileCache, error) {
+func NewFileCache(cacheDir string, ttl, retention time.Duration) (*FileCache, error) {
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return nil, err
 	}
 	return &FileCache{
-		cacheDir: cacheDir,
-		ttl:      ttl,
+		cacheDir:  cacheDir,
+		ttl:       ttl,
+		retention: retention,
 	}, nil
 }
 
@@ -37,39 +39,53 @@ func (c *FileCache) cacheFilePath(platform string) string {
 	return filepath.Join(c.cacheDir, platform+".json")
 }
 
-// Get 获取缓存数据
-func (c *FileCache) Get(platform string) ([]HotItem, bool) {
+// Get 获取新鲜期内的缓存数据
+func (c *FileCache) Get(platform string) (CacheEntry, bool) {
+	entry, ok := c.read(platform)
+	if !ok || time.Since(entry.UpdatedAt) > c.ttl {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// GetStale 获取保留期内的最近一次成功数据（可能已过新鲜期），用于平台失败时回退
+func (c *FileCache) GetStale(platform string) (CacheEntry, bool) {
+	entry, ok := c.read(platform)
+	if !ok || len(entry.Data) == 0 || time.Since(entry.UpdatedAt) > c.retention {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// read 读取缓存文件
+func (c *FileCache) read(platform string) (CacheEntry, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	filePath := c.cacheFilePath(platform)
-	data, err := os.ReadFile(filePath)
+	data, err := os.ReadFile(c.cacheFilePath(platform))
 	if err != nil {
-		return nil, false
+		return CacheEntry{}, false
 	}
 
 	var entry CacheEntry
 	if err := json.Unmarshal(data, &entry); err != nil {
-		return nil, false
-	}
-
-	// 检查是否过期
-	if time.Since(entry.UpdatedAt) > c.ttl {
-		return nil, false
+		return CacheEntry{}, false
 	}
-
-	return entry.Data, true
+	return entry, true
 }
 
 // Set 设置缓存数据
 func (c *FileCache) Set(platform string, items []HotItem) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	entry := CacheEntry{
+	return c.write(platform, CacheEntry{
 		Data:      items,
 		UpdatedAt: time.Now(),
-	}
+	})
+}
+
+// write 写入缓存文件
+func (c *FileCache) write(platform string, entry CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	data, err := json.Marshal(entry)
 	if err != nil {
@@ -0,0 +1,50 @@
+package hottrend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFileCacheRetention(t *testing.T) {
+	c, err := NewFileCache(t.TempDir(), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []HotItem{{ID: "1", Title: "热点", Rank: 1}}
+
+	if err := c.Set("weibo", items); err != nil {
+		t.Fatal(err)
+	}
+	if entry, ok := c.Get("weibo"); !ok || len(entry.Data) != 1 {
+		t.Fatalf("新鲜缓存应命中: %+v %v", entry, ok)
+	}
+
+	// 过了新鲜期但仍在保留期：Get 不命中，GetStale 返回原始更新时间
+	updatedAt := time.Now().Add(-10 * time.Minute)
+	if err := c.write("weibo", CacheEntry{Data: items, UpdatedAt: updatedAt}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("weibo"); ok {
+		t.Error("过期缓存不应被 Get 命中")
+	}
+	entry, ok := c.GetStale("weibo")
+	if !ok || !entry.UpdatedAt.Equal(updatedAt) || entry.Data[0].Title != "热点" {
+		t.Errorf("GetStale = %+v %v", entry, ok)
+	}
+
+	// 超过保留期
+	if err := c.write("weibo", CacheEntry{Data: items, UpdatedAt: time.Now().Add(-2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.GetStale("weibo"); ok {
+		t.Error("超过保留期的缓存不应返回")
+	}
+
+	// 空数据不作为回退
+	if err := c.write("zhihu", CacheEntry{UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.GetStale("zhihu"); ok {
+		t.Error("空缓存不应作为回退数据")
+	}
+}
@@ -1,16 +1,45 @@
 package hottrend
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/pkg/paths"
 )
 
+var log = logger.New("hottrend")
+
+// EventUpdate 软截止后仍在加载的平台完成时推送的事件，数据为 HotTrendResult
+const EventUpdate = "hottrend:update"
+
+const (
+	cacheTTL        = 5 * time.Minute         // 新鲜期
+	cacheRetention  = 24 * time.Hour          // 过期缓存最长保留
+	platformTimeout = 4 * time.Second         // 单平台获取超时
+	softDeadline    = 1500 * time.Millisecond // GetAllHotTrends 先行返回的截止时间
+)
+
 // HotTrendService 舆情热点聚合服务
 type HotTrendService struct {
-	fetchers map[string]Fetcher
-	cache    *FileCache
+	fetchers  map[string]Fetcher
+	platforms []string // 平台顺序，与 SupportedPlatforms 一致
+	cache     *FileCache
+
+	platformTimeout time.Duration
+	softDeadline    time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]*fetchCall // 进行中的请求，超时后仍在后台完成并写入缓存
+	onUpdate func(HotTrendResult)
+}
+
+// fetchCall 单个平台的一次网络请求
+type fetchCall struct {
+	done  chan struct{}
+	items []HotItem
+	err   error
 }
 
 // NewHotTrendService 创建舆情热点服务
@@ -18,26 +47,42 @@ func NewHotTrendService() (*HotTrendService, error) {
 	// 获取缓存目录
 	cacheDir := paths.EnsureCacheDir("hottrend")
 
-	// 创建文件缓存，TTL 5分钟
-	cache, err := NewFileCache(cacheDir, 5*time.Minute)
+	cache, err := NewFileCache(cacheDir, cacheTTL, cacheRetention)
 	if err != nil {
 		return nil, err
 	}
 
 	// 注册所有 fetcher
-	fetchers := map[string]Fetcher{
-		"weibo":    NewWeiboFetcher(),
-		"zhihu":    NewZhihuFetcher(),
-		"bilibili": NewBilibiliFetcher(),
-		"baidu":    NewBaiduFetcher(),
-		"douyin":   NewDouyinFetcher(),
-		"toutiao":  NewToutiaoFetcher(),
-	}
-
-	return &HotTrendService{
-		fetchers: fetchers,
-		cache:    cache,
-	}, nil
+	return newHotTrendService(cache,
+		NewWeiboFetcher(),
+		NewZhihuFetcher(),
+		NewBilibiliFetcher(),
+		NewBaiduFetcher(),
+		NewDouyinFetcher(),
+		NewToutiaoFetcher(),
+	), nil
+}
+
+func newHotTrendService(cache *FileCache, fetchers ...Fetcher) *HotTrendService {
+	s := &HotTrendService{
+		fetchers:        make(map[string]Fetcher, len(fetchers)),
+		cache:           cache,
+		platformTimeout: platformTimeout,
+		softDeadline:    softDeadline,
+		inflight:        make(map[string]*fetchCall),
+	}
+	for _, f := range fetchers {
+		s.fetchers[f.Platform()] = f
+		s.platforms = append(s.platforms, f.Platform())
+	}
+	return s
+}
+
+// SetUpdateCallback 设置软截止后平台加载完成的回调
+func (s *HotTrendService) SetUpdateCallback(fn func(HotTrendResult)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpdate = fn
 }
 
 // GetPlatforms 获取支持的平台列表
@@ -46,6 +91,7 @@ func (s *HotTrendService) GetPlatforms() []PlatformInfo {
 }
 
 // GetHotTrend 获取单个平台的热点数据
+// 依次尝试新鲜缓存、网络（单平台超时）、保留期内的过期缓存，都不可用时返回错误
 func (s *HotTrendService) GetHotTrend(platform string) HotTrendResult {
 	fetcher, ok := s.fetchers[platform]
 	if !ok {
@@ -56,49 +102,160 @@ func (s *HotTrendService) GetHotTrend(platform string) HotTrendResult {
 	}
 
 	// 先检查缓存
-	if items, ok := s.cache.Get(platform); ok {
+	if entry, ok := s.cache.Get(platform); ok {
 		return HotTrendResult{
 			Platform:   platform,
 			PlatformCN: fetcher.PlatformCN(),
-			Items:      items,
-			UpdatedAt:  time.Now(),
+			Items:      entry.Data,
+			UpdatedAt:  entry.UpdatedAt,
 			FromCache:  true,
 		}
 	}
 
 	// 从网络获取
-	items, err := fetcher.Fetch()
-	if err != nil {
+	call := s.fetch(fetcher)
+	timer := time.NewTimer(s.platformTimeout)
+	defer timer.Stop()
+	select {
+	case <-call.done:
+	case <-timer.C:
+		return s.staleOrError(fetcher, fmt.Errorf("请求超时（%v）", s.platformTimeout))
+	}
+	if call.err != nil {
+		return s.staleOrError(fetcher, call.err)
+	}
+
+	return HotTrendResult{
+		Platform:   platform,
+		PlatformCN: fetcher.PlatformCN(),
+		Items:      call.items,
+		UpdatedAt:  time.Now(),
+		FromCache:  false,
+	}
+}
+
+// fetch 发起平台请求，同一平台已有请求进行中时复用
+func (s *HotTrendService) fetch(fetcher Fetcher) *fetchCall {
+	platform := fetcher.Platform()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if call, ok := s.inflight[platform]; ok {
+		return call
+	}
+
+	call := &fetchCall{done: make(chan struct{})}
+	s.inflight[platform] = call
+	go func() {
+		call.items, call.err = fetcher.Fetch()
+		if call.err == nil {
+			// 写入缓存（仅成功时覆盖，失败保留上次数据）
+			_ = s.cache.Set(platform, call.items)
+		}
+		s.mu.Lock()
+		delete(s.inflight, platform)
+		s.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// staleOrError 平台获取失败时回退到过期缓存
+func (s *HotTrendService) staleOrError(fetcher Fetcher, err error) HotTrendResult {
+	platform := fetcher.Platform()
+	if entry, ok := s.cache.GetStale(platform); ok {
+		log.Warn("%s 获取失败，使用 %s 的缓存: %v", platform, entry.UpdatedAt.Format("15:04:05"), err)
 		return HotTrendResult{
 			Platform:   platform,
 			PlatformCN: fetcher.PlatformCN(),
-			Error:      err.Error(),
+			Items:      entry.Data,
+			UpdatedAt:  entry.UpdatedAt,
+			FromCache:  true,
+			Stale:      true,
 		}
 	}
-
-	// 写入缓存
-	_ = s.cache.Set(platform, items)
-
 	return HotTrendResult{
 		Platform:   platform,
 		PlatformCN: fetcher.PlatformCN(),
-		Items:      items,
-		UpdatedAt:  time.Now(),
-		FromCache:  false,
+		Error:      err.Error(),
 	}
 }
 
 // GetAllHotTrends 并发获取所有平台的热点数据
+// 软截止时间到达后立即返回，未完成的平台标记为加载中（有过期缓存时附带），完成后通过回调推送
 func (s *HotTrendService) GetAllHotTrends() []HotTrendResult {
-	platforms := make([]string, 0, len(s.fetchers))
-	for p := range s.fetchers {
-		platforms = append(platforms, p)
+	type indexed struct {
+		idx    int
+		result HotTrendResult
+	}
+	platforms := s.platforms
+	ch := make(chan indexed, len(platforms))
+	for i, p := range platforms {
+		go func(idx int, p string) {
+			ch <- indexed{idx, s.GetHotTrend(p)}
+		}(i, p)
+	}
+
+	results := make([]HotTrendResult, len(platforms))
+	filled := make([]bool, len(platforms))
+	pending := len(platforms)
+	deadline := time.NewTimer(s.softDeadline)
+	defer deadline.Stop()
+collect:
+	for pending > 0 {
+		select {
+		case r := <-ch:
+			results[r.idx], filled[r.idx] = r.result, true
+			pending--
+		case <-deadline.C:
+			break collect
+		}
 	}
-	return s.GetHotTrends(platforms)
+	if pending == 0 {
+		return results
+	}
+
+	for i, p := range platforms {
+		if !filled[i] {
+			results[i] = s.loadingResult(p)
+		}
+	}
+	go func(n int) {
+		for ; n > 0; n-- {
+			r := <-ch
+			s.mu.Lock()
+			onUpdate := s.onUpdate
+			s.mu.Unlock()
+			if onUpdate != nil {
+				onUpdate(r.result)
+			}
+		}
+	}(pending)
+	return results
 }
 
-// GetHotTrends 并发获取指定平台的热点数据
+// loadingResult 加载中的平台占位，有过期缓存时先展示
+func (s *HotTrendService) loadingResult(platform string) HotTrendResult {
+	fetcher := s.fetchers[platform]
+	result := HotTrendResult{
+		Platform:   platform,
+		PlatformCN: fetcher.PlatformCN(),
+		Loading:    true,
+	}
+	if entry, ok := s.cache.GetStale(platform); ok {
+		result.Items = entry.Data
+		result.UpdatedAt = entry.UpdatedAt
+		result.FromCache = true
+		result.Stale = true
+	}
+	return result
+}
+
+// GetHotTrends 并发获取指定平台的热点数据，等待全部平台完成（每个平台受单平台超时约束）
+// platforms 为空时获取所有平台
 func (s *HotTrendService) GetHotTrends(platforms []string) []HotTrendResult {
+	if len(platforms) == 0 {
+		platforms = s.platforms
+	}
 	var wg sync.WaitGroup
 	results := make([]HotTrendResult, len(platforms))
 
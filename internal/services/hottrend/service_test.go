@@ -0,0 +1,131 @@
+package hottrend
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubFetcher 可控延迟与错误的测试 fetcher
+type stubFetcher struct {
+	id    string
+	delay time.Duration
+	err   error
+}
+
+func (f *stubFetcher) Platform() string   { return f.id }
+func (f *stubFetcher) PlatformCN() string { return f.id + "热榜" }
+
+func (f *stubFetcher) Fetch() ([]HotItem, error) {
+	time.Sleep(f.delay)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []HotItem{{ID: f.id + "_1", Title: f.id + " 新数据", Rank: 1, Platform: f.id}}, nil
+}
+
+func newTestService(t *testing.T, fetchers ...Fetcher) *HotTrendService {
+	t.Helper()
+	cache, err := NewFileCache(t.TempDir(), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newHotTrendService(cache, fetchers...)
+	s.platformTimeout = 200 * time.Millisecond
+	s.softDeadline = 50 * time.Millisecond
+	return s
+}
+
+func seedStale(t *testing.T, s *HotTrendService, platform string, at time.Time) {
+	t.Helper()
+	entry := CacheEntry{Data: []HotItem{{ID: platform + "_old", Title: "旧数据", Rank: 1}}, UpdatedAt: at}
+	if err := s.cache.write(platform, entry); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetHotTrendStaleFallback(t *testing.T) {
+	s := newTestService(t,
+		&stubFetcher{id: "down", err: errors.New("503")},
+		&stubFetcher{id: "slow", delay: time.Second},
+		&stubFetcher{id: "nocache", err: errors.New("503")},
+	)
+	staleAt := time.Now().Add(-30 * time.Minute).Truncate(time.Second)
+	seedStale(t, s, "down", staleAt)
+	seedStale(t, s, "slow", staleAt)
+
+	for _, p := range []string{"down", "slow"} {
+		r := s.GetHotTrend(p)
+		if r.Error != "" || !r.Stale || !r.FromCache || !r.UpdatedAt.Equal(staleAt) || r.Items[0].Title != "旧数据" {
+			t.Errorf("%s = %+v", p, r)
+		}
+	}
+
+	r := s.GetHotTrend("nocache")
+	if r.Error == "" || r.Stale || len(r.Items) != 0 {
+		t.Errorf("无缓存时应返回错误: %+v", r)
+	}
+
+	// 失败不覆盖上次成功的数据
+	if entry, ok := s.cache.GetStale("down"); !ok || entry.Data[0].Title != "旧数据" {
+		t.Errorf("失败后缓存被覆盖: %+v", entry)
+	}
+}
+
+func TestGetAllHotTrendsSoftDeadline(t *testing.T) {
+	s := newTestService(t,
+		&stubFetcher{id: "fast"},
+		&stubFetcher{id: "slow", delay: 120 * time.Millisecond},
+		&stubFetcher{id: "cold", delay: 120 * time.Millisecond},
+	)
+	staleAt := time.Now().Add(-time.Hour / 2)
+	seedStale(t, s, "slow", staleAt)
+
+	updates := make(chan HotTrendResult, 3)
+	s.SetUpdateCallback(func(r HotTrendResult) { updates <- r })
+
+	start := time.Now()
+	results := s.GetAllHotTrends()
+	if elapsed := time.Since(start); elapsed > 110*time.Millisecond {
+		t.Errorf("应在软截止后立即返回，耗时 %v", elapsed)
+	}
+
+	if len(results) != 3 || results[0].Platform != "fast" || results[1].Platform != "slow" || results[2].Platform != "cold" {
+		t.Fatalf("结果顺序应与平台注册顺序一致: %+v", results)
+	}
+	if results[0].Loading || results[0].Items[0].Title != "fast 新数据" {
+		t.Errorf("fast = %+v", results[0])
+	}
+	if !results[1].Loading || !results[1].Stale || results[1].Items[0].Title != "旧数据" {
+		t.Errorf("slow 应标记加载中并附带过期缓存: %+v", results[1])
+	}
+	if !results[2].Loading || len(results[2].Items) != 0 || results[2].Error != "" {
+		t.Errorf("cold = %+v", results[2])
+	}
+
+	got := map[string]HotTrendResult{}
+	for range 2 {
+		select {
+		case r := <-updates:
+			got[r.Platform] = r
+		case <-time.After(time.Second):
+			t.Fatal("未收到后续推送")
+		}
+	}
+	for _, p := range []string{"slow", "cold"} {
+		if r := got[p]; r.Loading || r.Stale || r.Items[0].Title != p+" 新数据" {
+			t.Errorf("%s 推送 = %+v", p, r)
+		}
+	}
+}
+
+func TestGetHotTrendsWaitsForAll(t *testing.T) {
+	s := newTestService(t,
+		&stubFetcher{id: "a"},
+		&stubFetcher{id: "b", delay: 100 * time.Millisecond},
+	)
+	results := s.GetHotTrends(nil)
+	if len(results) != 2 || results[1].Loading || len(results[1].Items) != 1 {
+		t.Errorf("results = %+v", results)
+	}
+}
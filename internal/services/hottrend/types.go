@@ -20,6 +20,8 @@ type HotTrendResult struct {
 	Items       []HotItem `json:"items"`        // 热点列表
 	UpdatedAt   time.Time `json:"updated_at"`   // 更新时间
 	FromCache   bool      `json:"from_cache"`   // 是否来自缓存
+	Stale       bool      `json:"stale"`        // 是否为过期缓存（平台获取失败时回退），UpdatedAt 为数据获取时间
+	Loading     bool      `json:"loading"`      // 仍在加载中，完成后通过 EventUpdate 推送
 	Error       string    `json:"error"`        // 错误信息
 }
 
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	// 东方财富指数行情（f104 上涨家数, f105 下跌家数, f106 平盘家数）
+	emIndexBreadthURL = "https://push2.eastmoney.com/api/qt/ulist.np/get?fltt=2&secids=%s&fields=f12,f14,f104,f105,f106"
+	// 东方财富行业板块排行（po=1 降序取领涨，po=0 升序取领跌）
+	emSectorRankURL = "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=%d&po=%d&np=1&fltt=2&invt=2&fid=f3&fs=m:90+t:2&fields=f3,f12,f14,f128,f136"
+
+	indexOverviewTTL     = 30 * time.Second
+	indexOverviewSectors = 5 // 领涨/领跌板块各取前 N 个
+)
+
+// emFloat 东方财富数值字段，停牌或无数据时返回 "-"
+type emFloat float64
+
+func (f *emFloat) UnmarshalJSON(data []byte) error {
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		*f = 0
+		return nil
+	}
+	*f = emFloat(v)
+	return nil
+}
+
+// emQuoteResponse 东方财富行情列表响应
+type emQuoteResponse struct {
+	Data *struct {
+		Total int `json:"total"`
+		Diff  []struct {
+			Code         string  `json:"f12"`
+			Name         string  `json:"f14"`
+			Change       emFloat `json:"f3"`
+			Up           emFloat `json:"f104"`
+			Down         emFloat `json:"f105"`
+			Flat         emFloat `json:"f106"`
+			LeaderName   string  `json:"f128"`
+			LeaderChange emFloat `json:"f136"`
+			NetInflow    emFloat `json:"f62"`  // 主力净流入(元)
+			Change5D     emFloat `json:"f109"` // 5日涨跌幅(%)
+		} `json:"diff"`
+	} `json:"data"`
+}
+
+// indexOverviewCache 指数概览缓存
+type indexOverviewCache struct {
+	data      *models.IndexOverview
+	timestamp time.Time
+}
+
+// GetIndexOverview 获取指数概览（成分涨跌家数 + 行业板块领涨领跌）
+func (ms *MarketService) GetIndexOverview(ctx context.Context, code string) (*models.IndexOverview, error) {
+	code = models.NormalizeSymbol(code)
+	if !models.IsIndexSymbol(code) {
+		return nil, fmt.Errorf("not an index symbol: %s", code)
+	}
+
+	ms.overviewCacheMu.Lock()
+	if c, ok := ms.overviewCache[code]; ok && time.Since(c.timestamp) < indexOverviewTTL {
+		ms.overviewCacheMu.Unlock()
+		return c.data, nil
+	}
+	ms.overviewCacheMu.Unlock()
+
+	overview, err := ms.fetchIndexBreadth(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	// 板块数据获取失败不影响涨跌家数
+	if overview.Leading, err = ms.fetchSectorRank(ctx, true); err != nil {
+		log.Warn("获取领涨板块失败: %v", err)
+	}
+	if overview.Lagging, err = ms.fetchSectorRank(ctx, false); err != nil {
+		log.Warn("获取领跌板块失败: %v", err)
+	}
+
+	ms.overviewCacheMu.Lock()
+	ms.overviewCache[code] = &indexOverviewCache{data: overview, timestamp: time.Now()}
+	ms.overviewCacheMu.Unlock()
+	return overview, nil
+}
+
+// eastMoneySecID 转换为东方财富 secid（沪市 1.，深市与北交所 0.），code 须带市场前缀
+func eastMoneySecID(code string) string {
+	if code[:2] == "sh" {
+		return "1." + code[2:]
+	}
+	return "0." + code[2:]
+}
+
+// fetchIndexBreadth 获取指数成分涨跌家数
+func (ms *MarketService) fetchIndexBreadth(ctx context.Context, code string) (*models.IndexOverview, error) {
+	resp, err := ms.getEastMoneyQuotes(ctx, fmt.Sprintf(emIndexBreadthURL, eastMoneySecID(code)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil || len(resp.Data.Diff) == 0 {
+		return nil, fmt.Errorf("index overview not found: %s", code)
+	}
+
+	d := resp.Data.Diff[0]
+	return &models.IndexOverview{
+		Code: code,
+		Name: d.Name,
+		Up:   int(d.Up),
+		Down: int(d.Down),
+		Flat: int(d.Flat),
+	}, nil
+}
+
+// fetchSectorRank 获取行业板块涨跌排行
+func (ms *MarketService) fetchSectorRank(ctx context.Context, leading bool) ([]models.SectorPerformance, error) {
+	order := 0
+	if leading {
+		order = 1
+	}
+
+	resp, err := ms.getEastMoneyQuotes(ctx, fmt.Sprintf(emSectorRankURL, indexOverviewSectors, order))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+
+	sectors := make([]models.SectorPerformance, 0, len(resp.Data.Diff))
+	for _, d := range resp.Data.Diff {
+		sectors = append(sectors, models.SectorPerformance{
+			Name:          d.Name,
+			ChangePercent: float64(d.Change),
+			LeaderName:    d.LeaderName,
+			LeaderChange:  float64(d.LeaderChange),
+		})
+	}
+	return sectors, nil
+}
+
+// getEastMoneyQuotes 请求东方财富行情接口
+func (ms *MarketService) getEastMoneyQuotes(ctx context.Context, url string) (*emQuoteResponse, error) {
+	var result emQuoteResponse
+	if err := ms.getEastMoneyJSON(ctx, url, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// getEastMoneyJSON 请求东方财富接口并解析 JSON 响应
+func (ms *MarketService) getEastMoneyJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://quote.eastmoney.com/")
+
+	resp, err := ms.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unmarshal eastmoney response: %w", err)
+	}
+	return nil
+}
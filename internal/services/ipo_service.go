@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 新股日历查询参数
+const (
+	// 新股申购与上市
+	ipoApplyReport = "RPTA_APP_IPOAPPLY"
+
+	defaultIPODays = 14
+	maxIPODays     = 60
+	// 申购到上市一般不超过一个月，向前多取这段时间内申购、尚未上市的新股
+	ipoApplyLookback = 30
+	maxIPOItems      = 50
+
+	// 新股日历当天内变化不大（主要是定价、中签结果），缓存半小时，零点清空
+	ipoCacheTTL = 30 * time.Minute
+
+	// ISSUE_NUM 与东方财富新股申购页面的「发行总数(万股)」列同源，单位为万股
+	ipoIssueNumUnit = 1e4
+)
+
+// IPOCalendarItem 新股日历条目
+type IPOCalendarItem struct {
+	Code        string  `json:"code"`        // 股票代码
+	Name        string  `json:"name"`        // 股票名称
+	Market      string  `json:"market"`      // 上市板块，如 "上交所科创板"
+	ApplyDate   string  `json:"applyDate"`   // 申购日期 YYYY-MM-DD
+	ListingDate string  `json:"listingDate"` // 上市日期 YYYY-MM-DD，未确定为空
+	IssuePrice  float64 `json:"issuePrice"`  // 发行价（元），未定价为0
+	MarketCap   float64 `json:"marketCap"`   // 按发行价计算的发行市值（元）
+	Industry    string  `json:"industry"`    // 所属行业，缺失时为主营业务
+}
+
+// ipoCalendarCache 新股日历缓存
+type ipoCalendarCache struct {
+	items     []IPOCalendarItem
+	timestamp time.Time
+}
+
+// IPOService 新股日历服务
+type IPOService struct {
+	client  *http.Client
+	apiURL  string
+	cache   map[int]*ipoCalendarCache // 按查询天数缓存，天数上限 60，无需淘汰
+	cacheMu sync.Mutex
+}
+
+// NewIPOService 创建新股日历服务
+func NewIPOService() *IPOService {
+	return &IPOService{
+		client: proxy.GetManager().GetClientWithTimeout(15 * time.Second),
+		apiURL: eastmoneyDatacenterAPI,
+		cache:  make(map[int]*ipoCalendarCache),
+	}
+}
+
+// RegisterCaches 将新股日历缓存注册到缓存纪元服务，零点清空
+func (s *IPOService) RegisterCaches(e *CacheEpochs) {
+	e.Register("ipo_calendar", CacheDaily, func() {
+		s.cacheMu.Lock()
+		s.cache = make(map[int]*ipoCalendarCache)
+		s.cacheMu.Unlock()
+	})
+}
+
+// GetIPOCalendar 获取未来 daysAhead 天内申购或上市的新股，按日期升序
+func (s *IPOService) GetIPOCalendar(ctx context.Context, daysAhead int) ([]IPOCalendarItem, error) {
+	return s.getIPOCalendar(ctx, daysAhead, time.Now())
+}
+
+func (s *IPOService) getIPOCalendar(ctx context.Context, daysAhead int, now time.Time) ([]IPOCalendarItem, error) {
+	if daysAhead <= 0 {
+		daysAhead = defaultIPODays
+	}
+	daysAhead = min(daysAhead, maxIPODays)
+
+	s.cacheMu.Lock()
+	if c, ok := s.cache[daysAhead]; ok && now.Sub(c.timestamp) < ipoCacheTTL {
+		s.cacheMu.Unlock()
+		return c.items, nil
+	}
+	s.cacheMu.Unlock()
+
+	now = now.In(cacheEpochLoc)
+	from := now.Format("2006-01-02")
+	to := now.AddDate(0, 0, daysAhead).Format("2006-01-02")
+	since := now.AddDate(0, 0, -ipoApplyLookback).Format("2006-01-02")
+
+	filter := fmt.Sprintf("(APPLY_DATE>='%s')", since)
+	body, err := queryEastmoneyDatacenter(ctx, s.client, s.apiURL, ipoApplyReport, filter, "APPLY_DATE", 100)
+	if err != nil {
+		return nil, err
+	}
+	items, err := parseIPOCalendar(body, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) > maxIPOItems {
+		items = items[:maxIPOItems]
+	}
+
+	s.cacheMu.Lock()
+	s.cache[daysAhead] = &ipoCalendarCache{items: items, timestamp: now}
+	s.cacheMu.Unlock()
+	return items, nil
+}
+
+// ipoApplyResponse 新股申购API响应结构
+type ipoApplyResponse struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Result  *struct {
+		Data []struct {
+			SecurityCode string   `json:"SECURITY_CODE"`
+			SecurityName string   `json:"SECURITY_NAME"`
+			TradeMarket  string   `json:"TRADE_MARKET"`
+			ApplyDate    string   `json:"APPLY_DATE"`
+			ListingDate  string   `json:"LISTING_DATE"`
+			IssuePrice   *float64 `json:"ISSUE_PRICE"`
+			IssueNum     *float64 `json:"ISSUE_NUM"` // 发行总数（万股）
+			Industry     string   `json:"INDUSTRY_NAME"`
+			MainBusiness string   `json:"MAIN_BUSINESS"`
+		} `json:"data"`
+	} `json:"result"`
+}
+
+// parseIPOCalendar 解析新股申购数据，保留 [from, to] 内申购或上市的新股
+// 已申购但上市日期未定的也保留，按上市日期（未定时为申购日期）升序
+func parseIPOCalendar(body []byte, from, to string) ([]IPOCalendarItem, error) {
+	var resp ipoApplyResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析新股数据失败: %w", err)
+	}
+	items := []IPOCalendarItem{}
+	if resp.Result == nil {
+		if resp.Code == datacenterEmptyCode {
+			return items, nil
+		}
+		return nil, fmt.Errorf("获取新股数据失败: %s", resp.Message)
+	}
+
+	inRange := func(d string) bool { return d != "" && d >= from && d <= to }
+	for _, d := range resp.Result.Data {
+		apply, listing := datePart(d.ApplyDate), datePart(d.ListingDate)
+		if !inRange(apply) && !inRange(listing) && !(listing == "" && apply != "" && apply < from) {
+			continue
+		}
+		item := IPOCalendarItem{
+			Code:        d.SecurityCode,
+			Name:        d.SecurityName,
+			Market:      d.TradeMarket,
+			ApplyDate:   apply,
+			ListingDate: listing,
+			Industry:    d.Industry,
+		}
+		if item.Industry == "" {
+			item.Industry = d.MainBusiness
+		}
+		if d.IssuePrice != nil {
+			item.IssuePrice = *d.IssuePrice
+			if d.IssueNum != nil {
+				item.MarketCap = *d.IssuePrice * *d.IssueNum * ipoIssueNumUnit
+			}
+		}
+		items = append(items, item)
+	}
+
+	sortKey := func(it IPOCalendarItem) string {
+		if it.ListingDate != "" {
+			return it.ListingDate
+		}
+		return it.ApplyDate
+	}
+	sort.SliceStable(items, func(i, j int) bool { return sortKey(items[i]) < sortKey(items[j]) })
+	return items, nil
+}
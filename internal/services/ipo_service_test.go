@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// 按 RPTA_APP_IPOAPPLY 的字段构造，ISSUE_NUM 为万股（对应申购页面「发行总数(万股)」）
+const ipoApplyFixture = `{"version":"x","result":{"pages":1,"data":[
+	{"SECURITY_CODE":"688801","SECURITY_NAME":"科创新股","TRADE_MARKET":"上海证券交易所科创板","APPLY_DATE":"2025-10-20 00:00:00","LISTING_DATE":null,"ISSUE_PRICE":null,"ISSUE_NUM":4000,"INDUSTRY_NAME":"半导体"},
+	{"SECURITY_CODE":"301602","SECURITY_NAME":"创业新股","TRADE_MARKET":"深圳证券交易所创业板","APPLY_DATE":"2025-10-09 00:00:00","LISTING_DATE":"2025-10-17 00:00:00","ISSUE_PRICE":25.5,"ISSUE_NUM":2000,"INDUSTRY_NAME":"","MAIN_BUSINESS":"工业机器人"},
+	{"SECURITY_CODE":"603301","SECURITY_NAME":"待上市","TRADE_MARKET":"上海证券交易所主板","APPLY_DATE":"2025-10-13 00:00:00","LISTING_DATE":null,"ISSUE_PRICE":12,"ISSUE_NUM":5000,"INDUSTRY_NAME":"汽车零部件"},
+	{"SECURITY_CODE":"001390","SECURITY_NAME":"已上市","TRADE_MARKET":"深圳证券交易所主板","APPLY_DATE":"2025-09-25 00:00:00","LISTING_DATE":"2025-10-10 00:00:00","ISSUE_PRICE":8,"ISSUE_NUM":3000,"INDUSTRY_NAME":"化工"},
+	{"SECURITY_CODE":"920001","SECURITY_NAME":"远期新股","TRADE_MARKET":"北京证券交易所","APPLY_DATE":"2025-11-20 00:00:00","LISTING_DATE":null,"ISSUE_PRICE":null,"ISSUE_NUM":null,"INDUSTRY_NAME":"软件"}
+]},"success":true,"message":"ok","code":0}`
+
+func TestGetIPOCalendar(t *testing.T) {
+	var filter string
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		filter = r.URL.Query().Get("filter")
+		if r.URL.Query().Get("reportName") != ipoApplyReport {
+			w.Write([]byte(`{"success":false,"message":"返回数据为空","code":9201,"result":null}`))
+			return
+		}
+		w.Write([]byte(ipoApplyFixture))
+	}))
+	defer srv.Close()
+
+	s := &IPOService{client: srv.Client(), apiURL: srv.URL, cache: make(map[int]*ipoCalendarCache)}
+	now := time.Date(2025, 10, 16, 10, 0, 0, 0, cacheEpochLoc)
+	items, err := s.getIPOCalendar(context.Background(), 0, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter != "(APPLY_DATE>='2025-09-16')" {
+		t.Errorf("filter = %s", filter)
+	}
+
+	// 已上市与窗口外的新股被过滤，待上市的保留，按上市/申购日期升序
+	want := []string{"603301", "301602", "688801"}
+	if len(items) != len(want) {
+		t.Fatalf("items = %+v", items)
+	}
+	for i, code := range want {
+		if items[i].Code != code {
+			t.Errorf("items[%d] = %s, want %s", i, items[i].Code, code)
+		}
+	}
+
+	robot := items[1]
+	if robot.IssuePrice != 25.5 || robot.MarketCap != 5.1e8 || robot.Industry != "工业机器人" || robot.ListingDate != "2025-10-17" {
+		t.Errorf("robot = %+v", robot)
+	}
+	if chip := items[2]; chip.IssuePrice != 0 || chip.MarketCap != 0 || chip.ListingDate != "" || chip.Industry != "半导体" {
+		t.Errorf("unpriced = %+v", chip)
+	}
+
+	// 超过上限的天数按 60 天处理，远期新股进入窗口
+	if items, _ := s.getIPOCalendar(context.Background(), 365, now); len(items) != 4 || items[3].Code != "920001" {
+		t.Errorf("max days = %+v", items)
+	}
+
+	// 同一天数在 TTL 内走缓存，过期或纪元清空后重新请求
+	if requests != 2 {
+		t.Fatalf("requests = %d, want 2", requests)
+	}
+	if _, err := s.getIPOCalendar(context.Background(), defaultIPODays, now.Add(ipoCacheTTL/2)); err != nil || requests != 2 {
+		t.Errorf("cached call: requests = %d, err = %v", requests, err)
+	}
+	if _, err := s.getIPOCalendar(context.Background(), 0, now.Add(ipoCacheTTL)); err != nil || requests != 3 {
+		t.Errorf("expired call: requests = %d, err = %v", requests, err)
+	}
+	e := newCacheEpochs(testTradeDate, time.Now)
+	s.RegisterCaches(e)
+	e.FlushAll()
+	if _, err := s.getIPOCalendar(context.Background(), 0, now.Add(ipoCacheTTL)); err != nil || requests != 4 {
+		t.Errorf("flushed call: requests = %d, err = %v", requests, err)
+	}
+}
+
+func TestParseIPOCalendar_Empty(t *testing.T) {
+	items, err := parseIPOCalendar([]byte(`{"success":false,"message":"返回数据为空","code":9201,"result":null}`), "2025-10-16", "2025-10-30")
+	if err != nil || len(items) != 0 {
+		t.Fatalf("empty = %+v, %v", items, err)
+	}
+	if _, err := parseIPOCalendar([]byte(`{"success":false,"message":"参数错误","code":9501,"result":null}`), "2025-10-16", "2025-10-30"); err == nil {
+		t.Fatal("接口错误应返回 error")
+	}
+}
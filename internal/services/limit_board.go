@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	// 东方财富涨停池、跌停池、炸板池，date 为 YYYYMMDD
+	emLimitPoolURL = "https://push2ex.eastmoney.com/%s?ut=7eea3edcaed734bea9cbfc24409ed989&dpt=wz.ztzt&Pageindex=0&pagesize=10000&sort=%s&date=%s"
+
+	limitBoardLiveTTL  = time.Minute // 盘中缓存时间，收盘后的数据不再变化，一直缓存
+	limitBoardCacheMax = 30          // 最多缓存的交易日数
+)
+
+// emLimitPoolResponse 东方财富涨跌停池响应，无数据时 data 为 null
+type emLimitPoolResponse struct {
+	Data *struct {
+		Total int `json:"tc"`
+		Pool  []struct {
+			Code       string  `json:"c"`
+			Name       string  `json:"n"`
+			Price      emFloat `json:"p"`   // 价格 × 1000
+			Change     emFloat `json:"zdp"` // 涨跌幅(%)
+			Amount     emFloat `json:"amount"`
+			Fund       emFloat `json:"fund"` // 封单额(元)
+			Boards     int     `json:"lbc"`  // 连板数（涨停池）
+			Days       int     `json:"days"` // 连续跌停天数（跌停池）
+			FirstSeal  int     `json:"fbt"`  // 首次封板时间 HHMMSS
+			LastSeal   int     `json:"lbt"`  // 最后封板时间 HHMMSS
+			BreakCount int     `json:"zbc"`  // 炸板次数
+			Industry   string  `json:"hybk"`
+		} `json:"pool"`
+	} `json:"data"`
+}
+
+// limitBoardCache 涨跌停统计缓存
+type limitBoardCache struct {
+	data      *models.LimitBoardStats
+	timestamp time.Time
+}
+
+// GetLimitBoardStats 获取某交易日的涨跌停统计：涨停/跌停列表、连板高度与炸板率
+// date 格式 YYYY-MM-DD 或 YYYYMMDD，为空时取最近一个已开盘的交易日；盘中缓存 60 秒，收盘后一直缓存
+func (ms *MarketService) GetLimitBoardStats(ctx context.Context, date string) (*models.LimitBoardStats, error) {
+	return ms.getLimitBoardStats(ctx, date, time.Now())
+}
+
+func (ms *MarketService) getLimitBoardStats(ctx context.Context, date string, now time.Time) (*models.LimitBoardStats, error) {
+	day, final, err := ms.resolveLimitBoardDate(date, now)
+	if err != nil {
+		return nil, err
+	}
+	key := day.Format("20060102")
+
+	ms.limitBoardCacheMu.Lock()
+	if c, ok := ms.limitBoardCache[key]; ok && (c.data.Final || now.Sub(c.timestamp) < limitBoardLiveTTL) {
+		ms.limitBoardCacheMu.Unlock()
+		return c.data, nil
+	}
+	ms.limitBoardCacheMu.Unlock()
+
+	stats, err := ms.fetchLimitBoardStats(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	stats.Date = day.Format("2006-01-02")
+	stats.Final = final
+	stats.UpdatedAt = now.UnixMilli()
+
+	ms.limitBoardCacheMu.Lock()
+	if ms.limitBoardCache == nil {
+		ms.limitBoardCache = make(map[string]*limitBoardCache)
+	}
+	ms.limitBoardCache[key] = &limitBoardCache{data: stats, timestamp: now}
+	if len(ms.limitBoardCache) > limitBoardCacheMax {
+		ms.evictOldestLimitBoard()
+	}
+	ms.limitBoardCacheMu.Unlock()
+	return stats, nil
+}
+
+// resolveLimitBoardDate 解析统计日期，返回该交易日是否已收盘
+// 未指定日期时，交易日开盘前取上一个交易日
+func (ms *MarketService) resolveLimitBoardDate(date string, now time.Time) (time.Time, bool, error) {
+	now = now.In(cacheEpochLoc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, cacheEpochLoc)
+	status := ms.marketStatusAt(now)
+
+	var day time.Time
+	if date == "" {
+		day = today
+		if !status.IsTradeDay || status.Status == "pre_market" {
+			day = ms.previousTradeDate(today)
+		}
+	} else {
+		var err error
+		if day, err = time.ParseInLocation("2006-01-02", date, cacheEpochLoc); err != nil {
+			if day, err = time.ParseInLocation("20060102", date, cacheEpochLoc); err != nil {
+				return time.Time{}, false, fmt.Errorf("日期格式错误: %s", date)
+			}
+		}
+		if day.After(today) {
+			return time.Time{}, false, fmt.Errorf("日期不能晚于今天: %s", date)
+		}
+		if !ms.isTradeDate(day) {
+			return time.Time{}, false, fmt.Errorf("%s 不是交易日", day.Format("2006-01-02"))
+		}
+	}
+	final := day.Before(today) || status.Status == "closed"
+	return day, final, nil
+}
+
+// fetchLimitBoardStats 获取涨停池、跌停池与炸板池并汇总
+func (ms *MarketService) fetchLimitBoardStats(ctx context.Context, date string) (*models.LimitBoardStats, error) {
+	var zt, dt, zb emLimitPoolResponse
+	for _, p := range []struct {
+		api, sort string
+		resp      *emLimitPoolResponse
+	}{
+		{"getTopicZTPool", "fbt:asc", &zt},
+		{"getTopicDTPool", "fund:asc", &dt},
+		{"getTopicZBPool", "fbt:asc", &zb},
+	} {
+		if err := ms.getEastMoneyJSON(ctx, fmt.Sprintf(emLimitPoolURL, p.api, p.sort, date), p.resp); err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", p.api, err)
+		}
+	}
+
+	stats := &models.LimitBoardStats{LimitUp: []models.LimitUpStock{}, LimitDown: []models.LimitDownStock{}, Ladder: []models.LimitBoardTier{}}
+	if zt.Data != nil {
+		for _, d := range zt.Data.Pool {
+			stats.LimitUp = append(stats.LimitUp, models.LimitUpStock{
+				Code:          d.Code,
+				Name:          d.Name,
+				Price:         float64(d.Price) / 1000,
+				ChangePercent: float64(d.Change),
+				Amount:        float64(d.Amount),
+				SealAmount:    float64(d.Fund),
+				Boards:        max(d.Boards, 1),
+				FirstSealTime: formatSealTime(d.FirstSeal),
+				LastSealTime:  formatSealTime(d.LastSeal),
+				BreakCount:    d.BreakCount,
+				Industry:      d.Industry,
+			})
+		}
+	}
+	if dt.Data != nil {
+		for _, d := range dt.Data.Pool {
+			stats.LimitDown = append(stats.LimitDown, models.LimitDownStock{
+				Code:          d.Code,
+				Name:          d.Name,
+				Price:         float64(d.Price) / 1000,
+				ChangePercent: float64(d.Change),
+				Amount:        float64(d.Amount),
+				SealAmount:    float64(d.Fund),
+				Days:          max(d.Days, 1),
+				LastSealTime:  formatSealTime(d.LastSeal),
+				Industry:      d.Industry,
+			})
+		}
+	}
+	if zb.Data != nil {
+		stats.BrokenCount = len(zb.Data.Pool)
+	}
+
+	// 连板股在前，同高度按首次封板时间（接口已按封板时间升序）
+	sort.SliceStable(stats.LimitUp, func(i, j int) bool { return stats.LimitUp[i].Boards > stats.LimitUp[j].Boards })
+	sort.SliceStable(stats.LimitDown, func(i, j int) bool { return stats.LimitDown[i].SealAmount > stats.LimitDown[j].SealAmount })
+
+	stats.LimitUpCount = len(stats.LimitUp)
+	stats.LimitDownCount = len(stats.LimitDown)
+	if n := stats.LimitUpCount + stats.BrokenCount; n > 0 {
+		stats.BrokenRatio = float64(stats.BrokenCount) / float64(n) * 100
+	}
+	for _, s := range stats.LimitUp {
+		if k := len(stats.Ladder); k > 0 && stats.Ladder[k-1].Boards == s.Boards {
+			stats.Ladder[k-1].Count++
+		} else {
+			stats.Ladder = append(stats.Ladder, models.LimitBoardTier{Boards: s.Boards, Count: 1})
+		}
+	}
+	if len(stats.Ladder) > 0 {
+		stats.MaxBoards = stats.Ladder[0].Boards
+	}
+	return stats, nil
+}
+
+// evictOldestLimitBoard 淘汰最早获取的一天，调用方持有锁
+func (ms *MarketService) evictOldestLimitBoard() {
+	oldest := ""
+	for key, c := range ms.limitBoardCache {
+		if oldest == "" || c.timestamp.Before(ms.limitBoardCache[oldest].timestamp) {
+			oldest = key
+		}
+	}
+	delete(ms.limitBoardCache, oldest)
+}
+
+// formatSealTime 封板时间 92500 → 09:25:00，无数据时为空
+func formatSealTime(v int) string {
+	if v <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", v/10000, v/100%100, v%100)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// limitBoardStub 模拟东方财富涨停池、跌停池与炸板池
+func limitBoardStub(t *testing.T) (*MarketService, *[]string) {
+	t.Helper()
+	type row map[string]any
+	pools := map[string][]row{
+		"getTopicZTPool": {
+			{"c": "600001", "n": "首板A", "p": 10100, "zdp": 10.0, "amount": 1e8, "fund": 5e7, "lbc": 1, "fbt": 93000, "lbt": 93000, "zbc": 0, "hybk": "电力"},
+			{"c": "000002", "n": "三连板", "p": 22000, "zdp": 10.0, "amount": 3e8, "fund": 2e8, "lbc": 3, "fbt": 92500, "lbt": 92500, "zbc": 0, "hybk": "算力"},
+			{"c": "600003", "n": "首板B", "p": 5500, "zdp": 9.98, "amount": 6e7, "fund": 1e7, "lbc": 1, "fbt": 140512, "lbt": 145501, "zbc": 2, "hybk": "电力"},
+		},
+		"getTopicDTPool": {
+			{"c": "000004", "n": "跌停A", "p": 3000, "zdp": -10.0, "amount": 2e7, "fund": 1e7, "days": 1, "lbt": 100000, "hybk": "地产"},
+			{"c": "000005", "n": "跌停B", "p": 4000, "zdp": -9.9, "amount": 4e7, "fund": 3e7, "days": 2, "lbt": 93100, "hybk": "地产"},
+		},
+		"getTopicZBPool": {{"c": "600006", "n": "炸板"}},
+	}
+	var requests []string
+	ms := &MarketService{client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		api := strings.TrimPrefix(req.URL.Path, "/")
+		requests = append(requests, api+"@"+req.URL.Query().Get("date"))
+		body, _ := json.Marshal(map[string]any{"data": map[string]any{"tc": len(pools[api]), "pool": pools[api]}})
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body)))}, nil
+	})}}
+	return ms, &requests
+}
+
+func TestGetLimitBoardStats(t *testing.T) {
+	withHolidays(t, 2025, map[string]bool{})
+	ms, requests := limitBoardStub(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 10, day, hour, minute, 0, 0, cacheEpochLoc)
+	}
+
+	// 2025-10-10（周五）盘中
+	s, err := ms.getLimitBoardStats(context.Background(), "", at(10, 10, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Date != "2025-10-10" || s.Final || s.LimitUpCount != 3 || s.LimitDownCount != 2 || s.BrokenCount != 1 || s.MaxBoards != 3 {
+		t.Fatalf("stats = %+v", s)
+	}
+	if s.BrokenRatio != 25 {
+		t.Errorf("broken ratio = %v", s.BrokenRatio)
+	}
+	if up := s.LimitUp[0]; up.Name != "三连板" || up.Price != 22 || up.FirstSealTime != "09:25:00" || s.LimitUp[1].Name != "首板A" {
+		t.Errorf("limit up = %+v", s.LimitUp)
+	}
+	if len(s.Ladder) != 2 || s.Ladder[0].Boards != 3 || s.Ladder[1].Count != 2 {
+		t.Errorf("ladder = %+v", s.Ladder)
+	}
+	if d := s.LimitDown[0]; d.Name != "跌停B" || d.Days != 2 || d.LastSealTime != "09:31:00" {
+		t.Errorf("limit down = %+v", s.LimitDown)
+	}
+
+	// 盘中缓存 60 秒
+	n := len(*requests)
+	ms.getLimitBoardStats(context.Background(), "2025-10-10", at(10, 10, 0).Add(30*time.Second))
+	if len(*requests) != n {
+		t.Error("expected cached stats within 60s")
+	}
+	ms.getLimitBoardStats(context.Background(), "20251010", at(10, 10, 2))
+	if len(*requests) != n+3 {
+		t.Errorf("expected refetch after 60s, requests %d -> %d", n, len(*requests))
+	}
+
+	// 收盘后的数据一直缓存
+	if s, _ := ms.getLimitBoardStats(context.Background(), "", at(10, 15, 30)); !s.Final {
+		t.Errorf("should be final after close: %+v", s)
+	}
+	n = len(*requests)
+	if _, err := ms.getLimitBoardStats(context.Background(), "", at(11, 9, 0)); err != nil || len(*requests) != n {
+		t.Errorf("weekend should reuse final stats of 10-10, err %v, requests %d -> %d", err, n, len(*requests))
+	}
+
+	// 开盘前取上一交易日
+	if s, _ := ms.getLimitBoardStats(context.Background(), "", at(13, 9, 20)); s.Date != "2025-10-10" {
+		t.Errorf("pre-market date = %s", s.Date)
+	}
+}
+
+func TestGetLimitBoardStats_InvalidDate(t *testing.T) {
+	withHolidays(t, 2025, map[string]bool{"2025-10-01": true})
+	ms, requests := limitBoardStub(t)
+	now := time.Date(2025, 10, 10, 10, 0, 0, 0, cacheEpochLoc)
+	for _, date := range []string{"2025/10/09", "2025-10-11", "2025-10-01", "2025-10-13"} {
+		if _, err := ms.getLimitBoardStats(context.Background(), date, now); err == nil {
+			t.Errorf("%s should be rejected", date)
+		}
+	}
+	if len(*requests) != 0 {
+		t.Errorf("invalid dates should not hit the network: %v", *requests)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -53,9 +54,18 @@ func NewLongHuBangService() *LongHuBangService {
 	}
 }
 
+// RegisterCaches 将龙虎榜缓存注册到缓存纪元服务，交易日盘后数据发布后清空
+func (s *LongHuBangService) RegisterCaches(e *CacheEpochs) {
+	e.Register("longhubang", CacheTradeDate, func() {
+		s.cacheMu.Lock()
+		s.cache = nil
+		s.cacheMu.Unlock()
+	})
+}
+
 // GetLongHuBangList 获取龙虎榜列表
 // tradeDate: 交易日期，格式 YYYY-MM-DD，为空则获取所有日期
-func (s *LongHuBangService) GetLongHuBangList(pageSize, pageNumber int, tradeDate string) (*LongHuBangListResult, error) {
+func (s *LongHuBangService) GetLongHuBangList(ctx context.Context, pageSize, pageNumber int, tradeDate string) (*LongHuBangListResult, error) {
 	if pageSize <= 0 {
 		pageSize = 50
 	}
@@ -82,7 +92,7 @@ func (s *LongHuBangService) GetLongHuBangList(pageSize, pageNumber int, tradeDat
 	s.cacheMu.RUnlock()
 
 	// 从API获取数据
-	result, err := s.fetchLongHuBangList(pageSize, pageNumber, tradeDate)
+	result, err := s.fetchLongHuBangList(ctx, pageSize, pageNumber, tradeDate)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +111,7 @@ func (s *LongHuBangService) GetLongHuBangList(pageSize, pageNumber int, tradeDat
 }
 
 // fetchLongHuBangList 从东方财富API获取龙虎榜数据
-func (s *LongHuBangService) fetchLongHuBangList(pageSize, pageNumber int, tradeDate string) (*LongHuBangListResult, error) {
+func (s *LongHuBangService) fetchLongHuBangList(ctx context.Context, pageSize, pageNumber int, tradeDate string) (*LongHuBangListResult, error) {
 	url := fmt.Sprintf(lhbListBaseURL, pageSize, pageNumber)
 
 	// 添加日期筛选
@@ -109,7 +119,7 @@ func (s *LongHuBangService) fetchLongHuBangList(pageSize, pageNumber int, tradeD
 		url += fmt.Sprintf("&filter=(TRADE_DATE%%3D%%27%s%%27)", tradeDate)
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -239,13 +249,13 @@ type lhbDetailItem struct {
 }
 
 // GetStockDetail 获取个股龙虎榜营业部明细
-func (s *LongHuBangService) GetStockDetail(code, tradeDate string) ([]models.LongHuBangDetail, error) {
-	buyDetails, err := s.fetchDetail(code, tradeDate, "buy")
+func (s *LongHuBangService) GetStockDetail(ctx context.Context, code, tradeDate string) ([]models.LongHuBangDetail, error) {
+	buyDetails, err := s.fetchDetail(ctx, code, tradeDate, "buy")
 	if err != nil {
 		return nil, err
 	}
 
-	sellDetails, err := s.fetchDetail(code, tradeDate, "sell")
+	sellDetails, err := s.fetchDetail(ctx, code, tradeDate, "sell")
 	if err != nil {
 		return nil, err
 	}
@@ -256,7 +266,7 @@ func (s *LongHuBangService) GetStockDetail(code, tradeDate string) ([]models.Lon
 }
 
 // fetchDetail 获取营业部明细
-func (s *LongHuBangService) fetchDetail(code, tradeDate, direction string) ([]models.LongHuBangDetail, error) {
+func (s *LongHuBangService) fetchDetail(ctx context.Context, code, tradeDate, direction string) ([]models.LongHuBangDetail, error) {
 	var url string
 	if direction == "buy" {
 		url = fmt.Sprintf(lhbBuyDetailURL, tradeDate, code)
@@ -264,7 +274,7 @@ func (s *LongHuBangService) fetchDetail(code, tradeDate, direction string) ([]mo
 		url = fmt.Sprintf(lhbSellDetailURL, tradeDate, code)
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
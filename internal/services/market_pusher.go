@@ -25,14 +25,18 @@ const (
 	EventOrderBookSubscribe  = "market:orderbook:subscribe"
 	EventKLineUpdate         = "market:kline:update"
 	EventKLineSubscribe      = "market:kline:subscribe"
+
+	// EventFocusOrderBookPrefix 聚焦股票的盘口+行情推送，完整事件名为 orderbook:<code>
+	EventFocusOrderBookPrefix = "orderbook:"
 )
 
 // 推送频率常量
 const (
-	tickerFast     = 1 * time.Second  // 盘口（交易时段）
-	tickerNormal   = 3 * time.Second  // 股票、指数、分时K线
-	tickerSlow     = 30 * time.Second // 快讯、非交易时段降频
-	tickerKLineDay = 5 * time.Minute  // 日/周/月K线
+	tickerFast     = 1 * time.Second        // 盘口（交易时段）
+	tickerNormal   = 3 * time.Second        // 股票、指数、分时K线
+	tickerSlow     = 30 * time.Second       // 快讯、非交易时段降频
+	tickerKLineDay = 5 * time.Minute        // 日/周/月K线
+	tickerFocus    = 500 * time.Millisecond // 聚焦股票（命中 MarketService 缓存，不额外放大请求）
 )
 
 // safeCall 安全调用，捕获 panic 避免崩溃
@@ -57,6 +61,7 @@ type MarketDataPusher struct {
 	marketService *MarketService
 	configService *ConfigService
 	newsService   *NewsService
+	alertService  *AlertService // 价格提醒（可选）
 
 	// 订阅管理
 	subscribedCodes  []string
@@ -74,6 +79,15 @@ type MarketDataPusher struct {
 	// 盘口缓存（用于diff检测）
 	lastOrderBookHash string
 
+	// 重拉后仍异常的代码，同一纪元内不再立即重拉
+	suspectCodes map[string]bool
+	suspectMu    sync.Mutex
+
+	// 聚焦股票（用户正在查看的单只股票，独立快速推送）
+	focusCode   string
+	focusCancel context.CancelFunc
+	focusMu     sync.Mutex
+
 	// 控制
 	stopChan  chan struct{}
 	stopped   bool
@@ -97,6 +111,11 @@ func NewMarketDataPusher(marketService *MarketService, configService *ConfigServ
 	}
 }
 
+// SetAlertService 设置价格提醒服务，需在 Start 前调用
+func (p *MarketDataPusher) SetAlertService(alertService *AlertService) {
+	p.alertService = alertService
+}
+
 // Start 启动推送服务
 func (p *MarketDataPusher) Start(ctx context.Context) {
 	p.ctrlMu.Lock()
@@ -133,6 +152,7 @@ func (p *MarketDataPusher) Stop() {
 	}
 	p.stopped = true
 	close(p.stopChan)
+	p.UnfocusStock()
 	// 清理事件监听
 	runtime.EventsOff(p.ctx, EventMarketSubscribe)
 	runtime.EventsOff(p.ctx, EventOrderBookSubscribe)
@@ -177,13 +197,19 @@ func (p *MarketDataPusher) setupEventListeners() {
 	})
 }
 
-// initSubscriptions 从自选股初始化订阅
-func (p *MarketDataPusher) initSubscriptions() {
+// watchlistCodes 按自选股顺序返回代码列表
+func (p *MarketDataPusher) watchlistCodes() []string {
 	watchlist := p.configService.GetWatchlist()
 	codes := make([]string, len(watchlist))
 	for i, stock := range watchlist {
 		codes[i] = stock.Symbol
 	}
+	return codes
+}
+
+// initSubscriptions 从自选股初始化订阅
+func (p *MarketDataPusher) initSubscriptions() {
+	codes := p.watchlistCodes()
 
 	p.mu.Lock()
 	p.subscribedCodes = codes
@@ -194,6 +220,24 @@ func (p *MarketDataPusher) initSubscriptions() {
 	p.mu.Unlock()
 }
 
+// SyncSubscriptions 按自选股当前分组与顺序重排订阅列表，推送顺序与自选股列表一致
+// 前端单独订阅、不在自选股中的代码保留在末尾
+func (p *MarketDataPusher) SyncSubscriptions() {
+	codes := p.watchlistCodes()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, code := range p.subscribedCodes {
+		if !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	p.subscribedCodes = codes
+	if p.currentOrderBook == "" && len(codes) > 0 {
+		p.currentOrderBook = codes[0]
+	}
+}
+
 // updateSubscriptions 更新订阅列表
 func (p *MarketDataPusher) updateSubscriptions(codes []any) {
 	p.mu.Lock()
@@ -239,8 +283,8 @@ func (p *MarketDataPusher) pushLoop() {
 			return
 		case <-fastTicker.C:
 			status := p.getMarketPhase()
-			// 仅交易时段高频推送盘口
-			if status == "trading" {
+			// 仅交易时段高频推送盘口，已聚焦时由 focusLoop 负责
+			if status == "trading" && !p.hasFocus() {
 				p.runParallel(2*time.Second, p.pushOrderBookData)
 			}
 		case <-normalTicker.C:
@@ -332,17 +376,118 @@ func (p *MarketDataPusher) pushStockData() {
 	copy(codes, p.subscribedCodes)
 	p.mu.RUnlock()
 
-	if len(codes) == 0 {
-		return
+	var stocks []models.Stock
+	if len(codes) > 0 {
+		var err error
+		stocks, err = p.fetchValidStocks(codes)
+		if err != nil {
+			return
+		}
+		// 推送到前端
+		if len(stocks) > 0 {
+			runtime.EventsEmit(p.ctx, EventStockUpdate, stocks)
+		}
+	}
+
+	p.checkAlerts(codes, stocks)
+}
+
+// fetchValidStocks 获取行情并剔除疑似异常的数据
+// 异常代码立即重拉一次，仍异常的本轮不推送，前端保留上一次的正常行情，下个周期再试
+// 重拉后仍异常的代码记入 suspectCodes，同一纪元内只随每轮行情获取，不再额外重拉
+func (p *MarketDataPusher) fetchValidStocks(codes []string) ([]models.Stock, error) {
+	stocks, err := p.marketService.GetStockRealTimeData(context.Background(), codes...)
+	if err != nil {
+		return nil, err
+	}
+	valid, suspect := splitSuspect(stocks)
+
+	p.suspectMu.Lock()
+	for _, s := range valid {
+		delete(p.suspectCodes, s.Symbol)
+	}
+	var retry []string
+	for _, code := range suspect {
+		if !p.suspectCodes[code] {
+			retry = append(retry, code)
+		}
+	}
+	p.suspectMu.Unlock()
+	if len(retry) == 0 {
+		return valid, nil
 	}
 
-	stocks, err := p.marketService.GetStockRealTimeData(codes...)
+	retried, err := p.marketService.GetStockRealTimeData(context.Background(), retry...)
 	if err != nil {
+		pusherLog.Warn("重新获取异常行情失败: %v", err)
+		return valid, nil
+	}
+	retriedValid, stillSuspect := splitSuspect(retried)
+	if len(stillSuspect) > 0 {
+		pusherLog.Warn("行情数据异常，跳过推送: %v", stillSuspect)
+		p.suspectMu.Lock()
+		if p.suspectCodes == nil {
+			p.suspectCodes = make(map[string]bool)
+		}
+		for _, code := range stillSuspect {
+			p.suspectCodes[code] = true
+		}
+		p.suspectMu.Unlock()
+	}
+	return append(valid, retriedValid...), nil
+}
+
+// RegisterCaches 将异常代码记录注册到缓存纪元服务，开盘、收盘等边界清空后重新校验
+func (p *MarketDataPusher) RegisterCaches(e *CacheEpochs) {
+	e.Register("pusher_suspect", CacheIntraday, func() {
+		p.suspectMu.Lock()
+		p.suspectCodes = nil
+		p.suspectMu.Unlock()
+	})
+}
+
+// splitSuspect 拆分正常行情与疑似异常的代码
+func splitSuspect(stocks []models.Stock) (valid []models.Stock, suspect []string) {
+	for _, s := range stocks {
+		if s.DataSuspect {
+			suspect = append(suspect, s.Symbol)
+		} else {
+			valid = append(valid, s)
+		}
+	}
+	return valid, suspect
+}
+
+// checkAlerts 检查价格提醒，复用本轮已获取的行情，仅补拉未订阅的代码
+func (p *MarketDataPusher) checkAlerts(subscribed []string, stocks []models.Stock) {
+	if p.alertService == nil {
+		return
+	}
+	var missing []string
+	for _, code := range p.alertService.ActiveSymbols() {
+		if !slices.Contains(subscribed, code) {
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) > 0 {
+		extra, err := p.fetchValidStocks(missing)
+		if err != nil {
+			pusherLog.Warn("获取提醒股票行情失败: %v", err)
+		}
+		stocks = append(stocks, extra...)
+	}
+	if len(stocks) == 0 {
 		return
 	}
 
-	// 推送到前端
-	runtime.EventsEmit(p.ctx, EventStockUpdate, stocks)
+	triggered, err := p.alertService.Check(stocks)
+	if err != nil {
+		pusherLog.Error("保存价格提醒状态失败: %v", err)
+	}
+	for _, a := range triggered {
+		pusherLog.Info("价格提醒触发: %s %s (现价 %.2f)", a.Symbol, a.Describe(), a.TriggerPrice)
+		runtime.EventsEmit(p.ctx, EventAlertTriggered, a)
+	}
 }
 
 // pushOrderBookData 推送盘口数据（带diff检测）
@@ -356,7 +501,7 @@ func (p *MarketDataPusher) pushOrderBookData() {
 		return
 	}
 
-	orderBook, err := p.marketService.GetRealOrderBook(code)
+	orderBook, err := p.marketService.GetRealOrderBook(context.Background(), code)
 	if err != nil {
 		return
 	}
@@ -380,7 +525,7 @@ func (p *MarketDataPusher) pushTelegraphData() {
 		return
 	}
 
-	telegraphs, err := p.newsService.GetTelegraphList()
+	telegraphs, err := p.newsService.GetTelegraphList(context.Background())
 	if err != nil || len(telegraphs) == 0 {
 		return
 	}
@@ -403,7 +548,7 @@ func (p *MarketDataPusher) pushTelegraphData() {
 
 // pushMarketIndices 推送大盘指数
 func (p *MarketDataPusher) pushMarketIndices() {
-	indices, err := p.marketService.GetMarketIndices()
+	indices, err := p.marketService.GetMarketIndices(context.Background())
 	if err != nil {
 		return
 	}
@@ -420,7 +565,7 @@ func (p *MarketDataPusher) pushKLineData() {
 		return
 	}
 
-	klines, err := p.marketService.GetKLineData(sub.Code, sub.Period, 240)
+	klines, err := p.marketService.GetKLineData(context.Background(), sub.Code, sub.Period, 240)
 	if err != nil {
 		return
 	}
@@ -444,7 +589,7 @@ func (p *MarketDataPusher) pushKLineMinute() {
 	}
 
 	// 只获取最新几根用于增量判断
-	klines, err := p.marketService.GetKLineData(sub.Code, "1m", 5)
+	klines, err := p.marketService.GetKLineData(context.Background(), sub.Code, "1m", 5)
 	if err != nil || len(klines) == 0 {
 		return
 	}
@@ -499,7 +644,7 @@ func (p *MarketDataPusher) pushKLineDay() {
 		return
 	}
 
-	klines, err := p.marketService.GetKLineData(sub.Code, sub.Period, 120)
+	klines, err := p.marketService.GetKLineData(context.Background(), sub.Code, sub.Period, 120)
 	if err != nil {
 		return
 	}
@@ -511,6 +656,99 @@ func (p *MarketDataPusher) pushKLineDay() {
 	})
 }
 
+// FocusStock 聚焦股票，为其启动快速盘口推送，同时取消之前的聚焦循环
+func (p *MarketDataPusher) FocusStock(code string) {
+	if code == "" {
+		return
+	}
+
+	p.focusMu.Lock()
+	defer p.focusMu.Unlock()
+	if p.focusCode == code && p.focusCancel != nil {
+		return
+	}
+	if p.focusCancel != nil {
+		p.focusCancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.focusCode = code
+	p.focusCancel = cancel
+
+	// 普通盘口推送也跟随聚焦股票
+	p.mu.Lock()
+	p.currentOrderBook = code
+	p.mu.Unlock()
+
+	go p.focusLoop(ctx, code)
+}
+
+// UnfocusStock 取消聚焦，停止快速推送
+func (p *MarketDataPusher) UnfocusStock() {
+	p.focusMu.Lock()
+	defer p.focusMu.Unlock()
+	if p.focusCancel != nil {
+		p.focusCancel()
+	}
+	p.focusCode = ""
+	p.focusCancel = nil
+}
+
+// hasFocus 是否存在聚焦股票
+func (p *MarketDataPusher) hasFocus() bool {
+	p.focusMu.Lock()
+	defer p.focusMu.Unlock()
+	return p.focusCode != ""
+}
+
+// focusLoop 聚焦股票推送循环（仅交易时段运行，非交易时段空转等待）
+func (p *MarketDataPusher) focusLoop(ctx context.Context, code string) {
+	ticker := time.NewTicker(tickerFocus)
+	defer ticker.Stop()
+
+	var lastHash string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stopChan:
+			return
+		case <-ticker.C:
+			if p.getMarketPhase() != "trading" {
+				continue
+			}
+			safeCall(func() {
+				lastHash = p.pushFocusData(ctx, code, lastHash)
+			})
+		}
+	}
+}
+
+// pushFocusData 推送聚焦股票的行情与盘口，与工具调用共享 MarketService 缓存
+// 返回本次的盘口hash，数据无变化时不推送
+func (p *MarketDataPusher) pushFocusData(ctx context.Context, code, lastHash string) string {
+	data, err := p.marketService.GetStockDataWithOrderBook(ctx, code)
+	if err != nil || len(data) == 0 {
+		return lastHash
+	}
+	// 请求期间聚焦已切换，丢弃结果
+	if ctx.Err() != nil {
+		return lastHash
+	}
+
+	item := data[0]
+	// 行情异常时不推送，下个周期重试
+	if item.DataSuspect {
+		return lastHash
+	}
+	hash := fmt.Sprintf("%s:%.3f:%d", orderBookHash(item.OrderBook), item.Price, item.Volume)
+	if hash == lastHash {
+		return lastHash
+	}
+	runtime.EventsEmit(p.ctx, EventFocusOrderBookPrefix+code, item)
+	return hash
+}
+
 // AddSubscription 添加订阅
 func (p *MarketDataPusher) AddSubscription(code string) {
 	p.mu.Lock()
@@ -545,6 +783,6 @@ func (p *MarketDataPusher) GetSubscribedStocks() []models.Stock {
 		return []models.Stock{}
 	}
 
-	stocks, _ := p.marketService.GetStockRealTimeData(codes...)
+	stocks, _ := p.marketService.GetStockRealTimeData(context.Background(), codes...)
 	return stocks
 }
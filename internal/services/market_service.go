@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -103,16 +104,34 @@ type MarketService struct {
 	klineCache    map[string]*klineCache
 	klineCacheMu  sync.RWMutex
 	klineCacheTTL time.Duration
+
+	// 指数概览缓存
+	overviewCache   map[string]*indexOverviewCache
+	overviewCacheMu sync.Mutex
+
+	// 板块快照缓存
+	sectorCache   *sectorSnapshot
+	sectorCacheMu sync.Mutex
+
+	// 涨跌停统计缓存，key: YYYYMMDD
+	limitBoardCache   map[string]*limitBoardCache
+	limitBoardCacheMu sync.Mutex
+
+	// 实时行情缓存（会议工具调用）
+	realtimeCache *realtimeCache
 }
 
 // NewMarketService 创建市场数据服务
 func NewMarketService() *MarketService {
 	ms := &MarketService{
-		client:        proxy.GetManager().GetClientWithTimeout(5 * time.Second),
-		cache:         make(map[string]*stockCache),
-		cacheTTL:      2 * time.Second, // 股票缓存2秒
-		klineCache:    make(map[string]*klineCache),
-		klineCacheTTL: klineCacheTTLDefault, // 日/周/月K使用较长缓存，减少API调用
+		client:          proxy.GetManager().GetClientWithTimeout(5 * time.Second),
+		cache:           make(map[string]*stockCache),
+		cacheTTL:        2 * time.Second, // 股票缓存2秒
+		klineCache:      make(map[string]*klineCache),
+		klineCacheTTL:   klineCacheTTLDefault, // 日/周/月K使用较长缓存，减少API调用
+		overviewCache:   make(map[string]*indexOverviewCache),
+		limitBoardCache: make(map[string]*limitBoardCache),
+		realtimeCache:   newRealtimeCache(),
 	}
 	// 启动缓存清理协程
 	go ms.cleanCacheLoop()
@@ -154,6 +173,30 @@ func (ms *MarketService) cleanExpiredCache() {
 		}
 	}
 	ms.klineCacheMu.Unlock()
+
+	// 清理实时行情缓存
+	ms.realtimeCache.clean(now)
+}
+
+// RegisterCaches 将行情缓存注册到缓存纪元服务
+// K线（最后一根为当日未收盘数据）、指数概览、板块与实时行情在零点、开盘、收盘时清空
+func (ms *MarketService) RegisterCaches(e *CacheEpochs) {
+	e.Register("kline", CacheIntraday, func() {
+		ms.klineCacheMu.Lock()
+		ms.klineCache = make(map[string]*klineCache)
+		ms.klineCacheMu.Unlock()
+	})
+	e.Register("index_overview", CacheIntraday, func() {
+		ms.overviewCacheMu.Lock()
+		ms.overviewCache = make(map[string]*indexOverviewCache)
+		ms.overviewCacheMu.Unlock()
+	})
+	e.Register("sector", CacheIntraday, func() {
+		ms.sectorCacheMu.Lock()
+		ms.sectorCache = nil
+		ms.sectorCacheMu.Unlock()
+	})
+	e.Register("realtime", CacheIntraday, ms.realtimeCache.reset)
 }
 
 // getKLineCacheTTL 返回不同周期的缓存策略
@@ -166,7 +209,7 @@ func (ms *MarketService) getKLineCacheTTL(period string) time.Duration {
 }
 
 // GetStockDataWithOrderBook 获取股票实时数据（含真实盘口），带缓存
-func (ms *MarketService) GetStockDataWithOrderBook(codes ...string) ([]StockWithOrderBook, error) {
+func (ms *MarketService) GetStockDataWithOrderBook(ctx context.Context, codes ...string) ([]StockWithOrderBook, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
@@ -188,7 +231,7 @@ func (ms *MarketService) GetStockDataWithOrderBook(codes ...string) ([]StockWith
 	ms.cacheMu.RUnlock()
 
 	// 从API获取数据
-	data, err := ms.fetchStockDataWithOrderBook(codes...)
+	data, err := ms.fetchStockDataWithOrderBook(ctx, codes...)
 	if err != nil {
 		return nil, err
 	}
@@ -205,11 +248,11 @@ func (ms *MarketService) GetStockDataWithOrderBook(codes ...string) ([]StockWith
 }
 
 // fetchStockDataWithOrderBook 从API获取股票数据（含盘口）
-func (ms *MarketService) fetchStockDataWithOrderBook(codes ...string) ([]StockWithOrderBook, error) {
+func (ms *MarketService) fetchStockDataWithOrderBook(ctx context.Context, codes ...string) ([]StockWithOrderBook, error) {
 	codeList := strings.Join(codes, ",")
 	url := fmt.Sprintf(sinaStockURL, time.Now().UnixNano(), codeList)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -250,7 +293,7 @@ func (ms *MarketService) parseSinaStockDataWithOrderBook(data string) ([]StockWi
 }
 
 // GetStockRealTimeData 获取股票实时数据
-func (ms *MarketService) GetStockRealTimeData(codes ...string) ([]models.Stock, error) {
+func (ms *MarketService) GetStockRealTimeData(ctx context.Context, codes ...string) ([]models.Stock, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
@@ -258,7 +301,7 @@ func (ms *MarketService) GetStockRealTimeData(codes ...string) ([]models.Stock,
 	codeList := strings.Join(codes, ",")
 	url := fmt.Sprintf(sinaStockURL, time.Now().UnixNano(), codeList)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -315,7 +358,7 @@ func (ms *MarketService) parseStockFields(code string, parts []string) models.St
 		changePercent = (change / preClose) * 100
 	}
 
-	return models.Stock{
+	stock := models.Stock{
 		Symbol:        code,
 		Name:          parts[0],
 		Price:         price,
@@ -327,7 +370,18 @@ func (ms *MarketService) parseStockFields(code string, parts []string) models.St
 		ChangePercent: changePercent,
 		Volume:        volume,
 		Amount:        amount,
+		Type:          models.ClassifySymbol(code),
+	}
+	// 新浪行情第 31、32 个字段为行情日期与时间
+	if len(parts) > 31 && parts[30] != "" {
+		stock.QuoteTime = strings.TrimSpace(parts[30] + " " + parts[31])
 	}
+	// 第 33 个字段为交易状态，00 正常，其余为停牌、暂停上市等
+	if len(parts) > 32 && parts[32] != "" && parts[32] != "00" {
+		stock.QuoteState = models.QuoteStateHalted
+	}
+	ValidateQuote(&stock)
+	return stock
 }
 
 // parseStockWithOrderBook 解析股票字段和真实盘口数据
@@ -410,7 +464,8 @@ func (ms *MarketService) calculateOrderBookTotals(items []models.OrderBookItem)
 }
 
 // GetKLineData 获取K线数据（带缓存）
-func (ms *MarketService) GetKLineData(code string, period string, days int) ([]models.KLineData, error) {
+// days 为K线根数：日线即交易日数，周线/月线为周数/月数，历史足够时恰好返回 days 根
+func (ms *MarketService) GetKLineData(ctx context.Context, code string, period string, days int) ([]models.KLineData, error) {
 	cacheKey := fmt.Sprintf("%s:%s:%d", code, period, days)
 	ttl := ms.getKLineCacheTTL(period)
 
@@ -429,7 +484,7 @@ func (ms *MarketService) GetKLineData(code string, period string, days int) ([]m
 	ms.klineCacheMu.RUnlock()
 
 	// 从API获取数据
-	klines, err := ms.fetchKLineData(code, period, days)
+	klines, err := ms.fetchKLineData(ctx, code, period, days)
 	if err != nil {
 		return nil, err
 	}
@@ -447,11 +502,15 @@ func (ms *MarketService) GetKLineData(code string, period string, days int) ([]m
 }
 
 // fetchKLineData 从API获取K线数据
-func (ms *MarketService) fetchKLineData(code string, period string, days int) ([]models.KLineData, error) {
+func (ms *MarketService) fetchKLineData(ctx context.Context, code string, period string, days int) ([]models.KLineData, error) {
 	scale := ms.periodToScale(period)
 	url := fmt.Sprintf(sinaKLineURL, code, scale, days)
 
-	resp, err := ms.client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ms.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -471,11 +530,21 @@ func (ms *MarketService) fetchKLineData(code string, period string, days int) ([
 	if period == "1m" {
 		klines = ms.filterTodayKLines(klines)
 		klines = ms.calculateAvgLine(klines)
+	} else {
+		klines = lastKLines(klines, days)
 	}
 
 	return klines, nil
 }
 
+// lastKLines 只保留最近 n 根K线（接口偶尔多返回当日未收盘的一根）
+func lastKLines(klines []models.KLineData, n int) []models.KLineData {
+	if n > 0 && len(klines) > n {
+		return klines[len(klines)-n:]
+	}
+	return klines
+}
+
 // periodToScale 周期转换为新浪API的scale参数
 func (ms *MarketService) periodToScale(period string) string {
 	switch period {
@@ -589,8 +658,8 @@ func (ms *MarketService) parseKLineData(data string) ([]models.KLineData, error)
 }
 
 // GetRealOrderBook 获取真实盘口数据
-func (ms *MarketService) GetRealOrderBook(code string) (models.OrderBook, error) {
-	data, err := ms.GetStockDataWithOrderBook(code)
+func (ms *MarketService) GetRealOrderBook(ctx context.Context, code string) (models.OrderBook, error) {
+	data, err := ms.GetStockDataWithOrderBook(ctx, code)
 	if err != nil || len(data) == 0 {
 		return models.OrderBook{}, err
 	}
@@ -624,7 +693,11 @@ func (ms *MarketService) GenerateOrderBook(price float64) models.OrderBook {
 
 // GetMarketStatus 获取当前市场交易状态
 func (ms *MarketService) GetMarketStatus() MarketStatus {
-	now := time.Now()
+	return ms.marketStatusAt(time.Now())
+}
+
+// marketStatusAt 获取指定时刻的市场交易状态
+func (ms *MarketService) marketStatusAt(now time.Time) MarketStatus {
 	// 使用固定时区 UTC+8，避免 Windows 缺少时区数据库的问题
 	loc := time.FixedZone("CST", 8*60*60)
 	now = now.In(loc)
@@ -869,21 +942,21 @@ func getTradeDatesCacheFile() string {
 	return filepath.Join(paths.EnsureCacheDir(""), "trade_dates.json")
 }
 
-// GetTradeDates 获取指定天数内的交易日列表（从今天往前推）
+// GetTradeDates 获取最近 days 个交易日（从今天往前推，含今天）
 func (ms *MarketService) GetTradeDates(days int) ([]string, error) {
 	// 先尝试从文件缓存加载
 	cached, err := ms.loadTradeDatesCache()
-	if err == nil && len(cached.TradeDates) > 0 {
-		// 检查缓存是否过期（每天更新一次）
-		if time.Since(cached.UpdatedAt) < 24*time.Hour {
+	if err == nil && len(cached.TradeDates) >= days {
+		// 交易日列表在盘后数据发布后才会新增当天，按交易日纪元判断是否过期
+		if cacheEpochKey(CacheTradeDate, cached.UpdatedAt, ms.isTradeDate) == cacheEpochKey(CacheTradeDate, time.Now(), ms.isTradeDate) {
 			log.Debug("使用交易日缓存，共 %d 天", len(cached.TradeDates))
 			return ms.filterTradeDates(cached.TradeDates, days), nil
 		}
 	}
 
-	// 缓存不存在或过期，重新获取
+	// 缓存不存在、过期或不够长，重新获取
 	log.Info("开始获取交易日列表")
-	tradeDates, err := ms.fetchTradeDates(90) // 获取90天的数据
+	tradeDates, err := ms.fetchTradeDates(max(days, defaultTradeDatesCount))
 	if err != nil {
 		// 如果获取失败但有旧缓存，使用旧缓存
 		if cached != nil && len(cached.TradeDates) > 0 {
@@ -935,41 +1008,22 @@ func (ms *MarketService) saveTradeDatesCache(dates []string) error {
 	return os.WriteFile(getTradeDatesCacheFile(), data, 0644)
 }
 
-// fetchTradeDates 获取交易日列表
-func (ms *MarketService) fetchTradeDates(days int) ([]string, error) {
-	var tradeDates []string
-	today := time.Now()
-
-	// 预加载需要的年份节假日数据
-	yearsNeeded := make(map[int]bool)
-	for i := 0; i < days; i++ {
-		yearsNeeded[today.AddDate(0, 0, -i).Year()] = true
-	}
-	for year := range yearsNeeded {
-		if _, err := ms.loadHolidayData(year); err != nil {
-			log.Warn("加载 %d 年节假日数据失败: %v", year, err)
-		}
-	}
-
-	for i := 0; i < days; i++ {
-		date := today.AddDate(0, 0, -i)
-		dateStr := date.Format("2006-01-02")
-
-		if ms.isTradeDate(date) {
-			tradeDates = append(tradeDates, dateStr)
-		}
-	}
+// defaultTradeDatesCount 交易日缓存默认长度
+const defaultTradeDatesCount = 60
 
+// fetchTradeDates 获取最近 days 个交易日
+func (ms *MarketService) fetchTradeDates(days int) ([]string, error) {
+	tradeDates := ms.TradingDaysBack(time.Now(), days)
 	log.Info("获取到 %d 个交易日", len(tradeDates))
 	return tradeDates, nil
 }
 
 // GetMarketIndices 获取大盘指数数据
-func (ms *MarketService) GetMarketIndices() ([]models.MarketIndex, error) {
+func (ms *MarketService) GetMarketIndices(ctx context.Context) ([]models.MarketIndex, error) {
 	codeList := strings.Join(defaultIndexCodes, ",")
 	url := fmt.Sprintf(sinaStockURL, time.Now().UnixNano(), codeList)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
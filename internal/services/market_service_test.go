@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 )
 
@@ -10,7 +11,7 @@ func TestGetStockRealTimeData(t *testing.T) {
 
 	// 测试上海股票 (贵州茅台)
 	t.Run("上海股票", func(t *testing.T) {
-		stocks, err := ms.GetStockRealTimeData("sh600519")
+		stocks, err := ms.GetStockRealTimeData(context.Background(), "sh600519")
 		if err != nil {
 			t.Fatalf("获取上海股票数据失败: %v", err)
 		}
@@ -35,7 +36,7 @@ func TestGetStockRealTimeData(t *testing.T) {
 
 	// 测试深圳股票 (平安银行)
 	t.Run("深圳股票", func(t *testing.T) {
-		stocks, err := ms.GetStockRealTimeData("sz000001")
+		stocks, err := ms.GetStockRealTimeData(context.Background(), "sz000001")
 		if err != nil {
 			t.Fatalf("获取深圳股票数据失败: %v", err)
 		}
@@ -54,7 +55,7 @@ func TestGetStockRealTimeData(t *testing.T) {
 
 	// 测试多只股票
 	t.Run("多只股票", func(t *testing.T) {
-		stocks, err := ms.GetStockRealTimeData("sh600519", "sz000001", "sh601318")
+		stocks, err := ms.GetStockRealTimeData(context.Background(), "sh600519", "sz000001", "sh601318")
 		if err != nil {
 			t.Fatalf("获取多只股票数据失败: %v", err)
 		}
@@ -73,7 +74,7 @@ func TestGetStockDataWithOrderBook(t *testing.T) {
 	ms := NewMarketService()
 
 	t.Run("获取盘口数据", func(t *testing.T) {
-		data, err := ms.GetStockDataWithOrderBook("sh600519")
+		data, err := ms.GetStockDataWithOrderBook(context.Background(), "sh600519")
 		if err != nil {
 			t.Fatalf("获取盘口数据失败: %v", err)
 		}
@@ -111,7 +112,7 @@ func TestGetKLineData(t *testing.T) {
 	ms := NewMarketService()
 
 	t.Run("日K线", func(t *testing.T) {
-		data, err := ms.GetKLineData("sh600519", "1d", 10)
+		data, err := ms.GetKLineData(context.Background(), "sh600519", "1d", 10)
 		if err != nil {
 			t.Fatalf("获取K线数据失败: %v", err)
 		}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var schedulerLog = logger.New("scheduler")
+
+// EventScheduledMeetingDone 定时会议单只股票完成事件，载荷为 ScheduledMeetingResult
+const EventScheduledMeetingDone = "meeting:scheduled:done"
+
+const (
+	schedulerTick = 20 * time.Second
+	// scheduleGrace 到点后的补触发窗口，应用启动或休眠唤醒时稍晚几分钟仍会执行
+	scheduleGrace = 10 * time.Minute
+	// maxScheduledMeetings 定时会议最大并发数，避免自选股同时开会打满模型限流
+	maxScheduledMeetings = 2
+)
+
+// defaultSchedulePrompt 未配置问题模板时使用的默认问题
+const defaultSchedulePrompt = "请为{{name}}（{{code}}）做一份{{date}}的简报：梳理最新行情与消息面，给出关键价位和操作建议。"
+
+// ScheduledMeetingResult 定时会议结果
+type ScheduledMeetingResult struct {
+	ScheduleID   string `json:"scheduleId"`
+	ScheduleName string `json:"scheduleName"`
+	StockCode    string `json:"stockCode"`
+	StockName    string `json:"stockName"`
+	MessageCount int    `json:"messageCount"` // 保存到会话的发言条数
+	Error        string `json:"error,omitempty"`
+	FinishedAt   int64  `json:"finishedAt"`
+}
+
+// ScheduledMeetingRunner 执行一场定时会议并保存到会话，返回保存的发言条数
+type ScheduledMeetingRunner func(ctx context.Context, stock models.Stock, query string) (int, error)
+
+// MeetingScheduler 定时会议服务：交易日到点后对选定股票依次开会
+type MeetingScheduler struct {
+	marketService *MarketService
+	configService *ConfigService
+	run           ScheduledMeetingRunner
+	emit          func(ScheduledMeetingResult)
+
+	mu      sync.Mutex
+	lastRun map[string]string // 定时任务 ID -> 最近触发日期，同一天只触发一次
+	sem     chan struct{}     // 限制同时进行的会议数
+	wg      sync.WaitGroup
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	loopDone chan struct{} // 检查循环退出信号，Start 后才有值
+}
+
+// NewMeetingScheduler 创建定时会议服务
+func NewMeetingScheduler(marketService *MarketService, configService *ConfigService, run ScheduledMeetingRunner) *MeetingScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MeetingScheduler{
+		marketService: marketService,
+		configService: configService,
+		run:           run,
+		lastRun:       make(map[string]string),
+		sem:           make(chan struct{}, maxScheduledMeetings),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Start 启动定时检查；会议使用独立的后台 context，不随前端请求取消
+func (s *MeetingScheduler) Start(appCtx context.Context) {
+	s.emit = func(r ScheduledMeetingResult) {
+		runtime.EventsEmit(appCtx, EventScheduledMeetingDone, r)
+	}
+	s.loopDone = make(chan struct{})
+	go s.loop()
+}
+
+// Stop 停止定时检查，取消进行中的定时会议并等待其退出
+func (s *MeetingScheduler) Stop() {
+	s.cancel()
+	if s.loopDone != nil {
+		<-s.loopDone
+	}
+	s.wg.Wait()
+}
+
+func (s *MeetingScheduler) loop() {
+	defer close(s.loopDone)
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			safeCall(func() { s.check(now) })
+		}
+	}
+}
+
+// check 触发到点的定时任务
+func (s *MeetingScheduler) check(now time.Time) {
+	now = now.In(time.FixedZone("CST", 8*60*60))
+	for _, schedule := range s.due(now) {
+		stocks := s.scheduleStocks(schedule)
+		schedulerLog.Info("定时会议 %s 触发，共 %d 只股票", schedule.Name, len(stocks))
+		for _, stock := range stocks {
+			s.wg.Add(1)
+			go s.runOne(schedule, stock, renderSchedulePrompt(schedule.Prompt, stock, now))
+		}
+	}
+}
+
+// due 返回当前应触发的定时任务（非交易日不触发），并记录触发日期
+func (s *MeetingScheduler) due(now time.Time) []models.MeetingSchedule {
+	now = now.In(time.FixedZone("CST", 8*60*60))
+	schedules := s.configService.GetConfig().MeetingSchedules
+	if len(schedules) == 0 || !s.marketService.isTradeDate(now) {
+		return nil
+	}
+	today := now.Format("2006-01-02")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []models.MeetingSchedule
+	for _, schedule := range schedules {
+		if !schedule.Enabled || s.lastRun[schedule.ID] == today {
+			continue
+		}
+		at, err := time.ParseInLocation("2006-01-02 15:04", today+" "+schedule.Time, now.Location())
+		if err != nil {
+			continue
+		}
+		if elapsed := now.Sub(at); elapsed >= 0 && elapsed < scheduleGrace {
+			s.lastRun[schedule.ID] = today
+			due = append(due, schedule)
+		}
+	}
+	return due
+}
+
+// scheduleStocks 定时任务的参会股票：指定代码优先，否则为全部自选股
+func (s *MeetingScheduler) scheduleStocks(schedule models.MeetingSchedule) []models.Stock {
+	watchlist := s.configService.GetWatchlist()
+	if len(schedule.StockCodes) == 0 {
+		return append([]models.Stock(nil), watchlist...)
+	}
+	names := make(map[string]string, len(watchlist))
+	for _, stock := range watchlist {
+		names[models.NormalizeSymbol(stock.Symbol)] = stock.Name
+	}
+	var stocks []models.Stock
+	seen := make(map[string]bool)
+	for _, code := range schedule.StockCodes {
+		symbol := models.NormalizeSymbol(code)
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		name := names[symbol]
+		if name == "" {
+			name = symbol
+		}
+		stocks = append(stocks, models.Stock{Symbol: symbol, Name: name})
+	}
+	return stocks
+}
+
+// runOne 在并发名额内执行单只股票的定时会议，并推送完成事件
+func (s *MeetingScheduler) runOne(schedule models.MeetingSchedule, stock models.Stock, query string) {
+	defer s.wg.Done()
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-s.ctx.Done():
+		return
+	}
+
+	result := ScheduledMeetingResult{
+		ScheduleID:   schedule.ID,
+		ScheduleName: schedule.Name,
+		StockCode:    stock.Symbol,
+		StockName:    stock.Name,
+	}
+	var err error
+	safeCall(func() { result.MessageCount, err = s.run(s.ctx, stock, query) })
+	if err != nil {
+		result.Error = err.Error()
+		schedulerLog.Warn("定时会议 %s %s 失败: %v", schedule.Name, stock.Symbol, err)
+	}
+	result.FinishedAt = time.Now().UnixMilli()
+	if s.emit != nil {
+		s.emit(result)
+	}
+}
+
+// renderSchedulePrompt 填充问题模板中的占位符
+func renderSchedulePrompt(tmpl string, stock models.Stock, now time.Time) string {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = defaultSchedulePrompt
+	}
+	return strings.NewReplacer(
+		"{{name}}", stock.Name,
+		"{{code}}", stock.Symbol,
+		"{{date}}", now.Format("2006-01-02"),
+	).Replace(tmpl)
+}
+
+// NormalizeMeetingSchedules 校验定时会议配置并补全 ID、规范时间与股票代码
+func NormalizeMeetingSchedules(schedules []models.MeetingSchedule) error {
+	for i := range schedules {
+		schedule := &schedules[i]
+		at, err := time.Parse("15:04", strings.TrimSpace(schedule.Time))
+		if err != nil {
+			return fmt.Errorf("定时会议「%s」的时间格式应为 HH:MM: %q", schedule.Name, schedule.Time)
+		}
+		schedule.Time = at.Format("15:04")
+		if schedule.ID == "" {
+			schedule.ID = uuid.New().String()[:8]
+		}
+		for j, code := range schedule.StockCodes {
+			schedule.StockCodes[j] = models.NormalizeSymbol(code)
+		}
+	}
+	return nil
+}
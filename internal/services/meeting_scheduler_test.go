@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// newTestScheduler 创建带自选股与定时任务配置的调度器，避免访问网络
+func newTestScheduler(t *testing.T, schedules []models.MeetingSchedule, run ScheduledMeetingRunner) *MeetingScheduler {
+	t.Helper()
+	withHolidays(t, 2025, map[string]bool{"2025-10-01": true})
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{"sh600519", "sz000001", "sh601318", "sz000858", "sh600036"} {
+		if err := cs.AddToWatchlist(models.Stock{Symbol: code, Name: "名称-" + code}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := NormalizeMeetingSchedules(schedules); err != nil {
+		t.Fatal(err)
+	}
+	cfg := cs.GetConfig()
+	cfg.MeetingSchedules = schedules
+	if err := cs.UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	s := NewMeetingScheduler(&MarketService{}, cs, run)
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func TestMeetingSchedulerDue(t *testing.T) {
+	cst := time.FixedZone("CST", 8*60*60)
+	s := newTestScheduler(t, []models.MeetingSchedule{
+		{ID: "pre", Name: "盘前", Enabled: true, Time: "9:15"},
+		{ID: "post", Name: "盘后", Enabled: true, Time: "15:10"},
+		{ID: "off", Name: "关闭", Enabled: false, Time: "09:15"},
+	}, nil)
+
+	ids := func(now time.Time) []string {
+		var got []string
+		for _, sc := range s.due(now) {
+			got = append(got, sc.ID)
+		}
+		return got
+	}
+	// 周末、节假日不触发
+	if got := ids(time.Date(2025, 10, 11, 9, 16, 0, 0, cst)); len(got) != 0 {
+		t.Errorf("周末触发了 %v", got)
+	}
+	if got := ids(time.Date(2025, 10, 1, 9, 16, 0, 0, cst)); len(got) != 0 {
+		t.Errorf("节假日触发了 %v", got)
+	}
+	// 未到点、超过补触发窗口均不触发
+	if got := ids(time.Date(2025, 10, 9, 9, 14, 0, 0, cst)); len(got) != 0 {
+		t.Errorf("未到点触发了 %v", got)
+	}
+	if got := ids(time.Date(2025, 10, 9, 9, 40, 0, 0, cst)); len(got) != 0 {
+		t.Errorf("超出窗口触发了 %v", got)
+	}
+	// 交易日到点只触发一次（UTC 时间按北京时间判断）
+	if got := ids(time.Date(2025, 10, 9, 1, 15, 30, 0, time.UTC)); len(got) != 1 || got[0] != "pre" {
+		t.Errorf("09:15 due = %v, want [pre]", got)
+	}
+	if got := ids(time.Date(2025, 10, 9, 9, 16, 0, 0, cst)); len(got) != 0 {
+		t.Errorf("重复触发 %v", got)
+	}
+	if got := ids(time.Date(2025, 10, 9, 15, 12, 0, 0, cst)); len(got) != 1 || got[0] != "post" {
+		t.Errorf("15:12 due = %v, want [post]", got)
+	}
+}
+
+func TestMeetingSchedulerConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	queries := make(map[string]string)
+	release := make(chan struct{})
+	run := func(ctx context.Context, stock models.Stock, query string) (int, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		queries[stock.Symbol] = query
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return 3, nil
+	}
+	s := newTestScheduler(t, []models.MeetingSchedule{
+		{ID: "pre", Name: "盘前", Enabled: true, Time: "09:15", Prompt: "{{date}} {{name}} 盘前"},
+	}, run)
+	results := make(chan ScheduledMeetingResult, 10)
+	s.emit = func(r ScheduledMeetingResult) { results <- r }
+
+	s.check(time.Date(2025, 10, 9, 9, 15, 0, 0, time.FixedZone("CST", 8*60*60)))
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < 5; i++ {
+		select {
+		case r := <-results:
+			if r.ScheduleID != "pre" || r.MessageCount != 3 || r.Error != "" {
+				t.Errorf("result = %+v", r)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("只收到 %d 个完成事件", i)
+		}
+	}
+	if peak > maxScheduledMeetings {
+		t.Errorf("最大并发 %d，超过限制 %d", peak, maxScheduledMeetings)
+	}
+	if got := queries["sh600519"]; got != "2025-10-09 名称-sh600519 盘前" {
+		t.Errorf("query = %q", got)
+	}
+}
+
+func TestNormalizeMeetingSchedules(t *testing.T) {
+	schedules := []models.MeetingSchedule{{Name: "盘后", Time: " 15:10 ", StockCodes: []string{"600519.SH"}}}
+	if err := NormalizeMeetingSchedules(schedules); err != nil {
+		t.Fatal(err)
+	}
+	if sc := schedules[0]; sc.ID == "" || sc.Time != "15:10" || sc.StockCodes[0] != "sh600519" {
+		t.Errorf("normalized = %+v", sc)
+	}
+	if err := NormalizeMeetingSchedules([]models.MeetingSchedule{{Name: "坏", Time: "25:00"}}); err == nil {
+		t.Error("非法时间应返回错误")
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 新闻情绪评分参数
+const (
+	newsSentimentTTL      = 5 * time.Minute // 同一批快讯 5 分钟内不重复评分
+	newsSentimentTopN     = 3               // 利好/利空代表性标题条数
+	newsSentimentMaxItems = 30              // 单次送模型分类的快讯上限
+)
+
+// 情绪评分方式
+const (
+	SentimentMethodLLM     = "llm"
+	SentimentMethodLexicon = "lexicon" // 未配置模型或模型分类失败时使用词典打分
+)
+
+// SentimentResult 新闻情绪评分结果
+type SentimentResult struct {
+	Code                string   `json:"code,omitempty"`
+	BullishCount        int      `json:"bullishCount"`
+	BearishCount        int      `json:"bearishCount"`
+	NeutralCount        int      `json:"neutralCount"`
+	Score               float64  `json:"score"` // (利好数-利空数)/总数，范围 [-1, 1]
+	TopBullishHeadlines []string `json:"topBullishHeadlines"`
+	TopBearishHeadlines []string `json:"topBearishHeadlines"`
+	Method              string   `json:"method"`         // llm / lexicon
+	Note                string   `json:"note,omitempty"` // 数据范围说明，如没有相关快讯
+}
+
+// NewsClassifier 对快讯逐条分类，返回与 headlines 等长的标签（positive/negative/neutral）
+type NewsClassifier func(ctx context.Context, headlines []string) ([]string, error)
+
+// newsSentimentCache 新闻情绪缓存
+type newsSentimentCache struct {
+	data      *SentimentResult
+	timestamp time.Time
+}
+
+// GetNewsSentiment 对最近快讯做情绪评分：stock 为空时评估全部快讯（市场整体情绪），否则只评估提及该股票的快讯
+// classify 为空或失败时降级为词典打分；同一批快讯的结果缓存 5 分钟
+// 快讯来源只提供最近一批（约20条）且只带时分不带日期，无法按天数筛选，统计范围即这一批快讯
+func (s *NewsService) GetNewsSentiment(ctx context.Context, stock models.Stock, classify NewsClassifier) (*SentimentResult, error) {
+	telegraphs, err := s.GetTelegraphList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	code := models.NormalizeSymbol(stock.Symbol)
+	if code != "" {
+		telegraphs = MatchTelegraphs(telegraphs, stock, newsSentimentMaxItems)
+	} else if len(telegraphs) > newsSentimentMaxItems {
+		telegraphs = telegraphs[:newsSentimentMaxItems]
+	}
+	if len(telegraphs) == 0 {
+		return &SentimentResult{
+			Code: code, TopBullishHeadlines: []string{}, TopBearishHeadlines: []string{},
+			Method: SentimentMethodLexicon, Note: "近期快讯中没有提及该股票的内容",
+		}, nil
+	}
+
+	key := newsSentimentKey(code, telegraphs)
+	s.sentimentMu.Lock()
+	if c, ok := s.sentimentCache[key]; ok && time.Since(c.timestamp) < newsSentimentTTL {
+		s.sentimentMu.Unlock()
+		return c.data, nil
+	}
+	s.sentimentMu.Unlock()
+
+	labels, method := lexiconLabels(telegraphs), SentimentMethodLexicon
+	if classify != nil {
+		headlines := make([]string, len(telegraphs))
+		for i, tg := range telegraphs {
+			headlines[i] = tg.Content
+		}
+		if llmLabels, err := classify(ctx, headlines); err != nil {
+			log.Warn("快讯情绪模型分类失败，使用词典打分: %v", err)
+		} else {
+			labels, method = llmLabels, SentimentMethodLLM
+		}
+	}
+	result := buildSentimentResult(telegraphs, labels)
+	result.Code, result.Method = code, method
+
+	s.sentimentMu.Lock()
+	now := time.Now()
+	for k, c := range s.sentimentCache {
+		if now.Sub(c.timestamp) >= newsSentimentTTL {
+			delete(s.sentimentCache, k)
+		}
+	}
+	s.sentimentCache[key] = &newsSentimentCache{data: result, timestamp: now}
+	s.sentimentMu.Unlock()
+	return result, nil
+}
+
+// newsSentimentKey 缓存键：股票与快讯内容共同决定，快讯更新后自动重新评分
+func newsSentimentKey(code string, telegraphs []Telegraph) string {
+	h := fnv.New64a()
+	for _, tg := range telegraphs {
+		h.Write([]byte(tg.Content))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%s:%x", code, h.Sum64())
+}
+
+// lexiconLabels 快讯已有的词典情绪标签
+func lexiconLabels(telegraphs []Telegraph) []string {
+	labels := make([]string, len(telegraphs))
+	for i, tg := range telegraphs {
+		labels[i] = tg.Sentiment
+	}
+	return labels
+}
+
+// buildSentimentResult 统计分类结果，代表性标题按词典得分强度排序
+func buildSentimentResult(telegraphs []Telegraph, labels []string) *SentimentResult {
+	result := &SentimentResult{TopBullishHeadlines: []string{}, TopBearishHeadlines: []string{}}
+	var bullish, bearish []Telegraph
+	for i, tg := range telegraphs {
+		switch labels[i] {
+		case SentimentPositive:
+			bullish = append(bullish, tg)
+		case SentimentNegative:
+			bearish = append(bearish, tg)
+		default:
+			result.NeutralCount++
+		}
+	}
+	result.BullishCount, result.BearishCount = len(bullish), len(bearish)
+	result.Score = math.Round(float64(len(bullish)-len(bearish))/float64(len(telegraphs))*100) / 100
+
+	sort.SliceStable(bullish, func(i, j int) bool { return bullish[i].SentimentScore > bullish[j].SentimentScore })
+	sort.SliceStable(bearish, func(i, j int) bool { return bearish[i].SentimentScore < bearish[j].SentimentScore })
+	for i := 0; i < len(bullish) && i < newsSentimentTopN; i++ {
+		result.TopBullishHeadlines = append(result.TopBullishHeadlines, bullish[i].Content)
+	}
+	for i := 0; i < len(bearish) && i < newsSentimentTopN; i++ {
+		result.TopBearishHeadlines = append(result.TopBearishHeadlines, bearish[i].Content)
+	}
+	return result
+}
+
+// newsClassifyPrompt 快讯分类提示词，要求模型只输出标签数组
+const newsClassifyPrompt = `你是A股新闻情绪分类器。判断下列每条快讯对相关股票或市场是利好、利空还是中性。
+只输出一个 JSON 字符串数组，按序号顺序给出每条的标签（"利好"、"利空"或"中性"），数组长度必须等于快讯条数，不要输出其他内容。
+
+快讯：
+`
+
+// newsLabelAliases 模型输出标签归一化
+var newsLabelAliases = map[string]string{
+	"利好": SentimentPositive, "positive": SentimentPositive, "bullish": SentimentPositive,
+	"利空": SentimentNegative, "negative": SentimentNegative, "bearish": SentimentNegative,
+	"中性": SentimentNeutral, "neutral": SentimentNeutral,
+}
+
+// NewLLMNewsClassifier 使用模型对快讯做轻量分类
+func NewLLMNewsClassifier(llm model.LLM) NewsClassifier {
+	return func(ctx context.Context, headlines []string) ([]string, error) {
+		var sb strings.Builder
+		sb.WriteString(newsClassifyPrompt)
+		for i, h := range headlines {
+			fmt.Fprintf(&sb, "%d. %s\n", i+1, h)
+		}
+		req := &model.LLMRequest{
+			Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: sb.String()}}}},
+		}
+		var text strings.Builder
+		for resp, err := range llm.GenerateContent(ctx, req, false) {
+			if err != nil {
+				return nil, err
+			}
+			if resp != nil && resp.Content != nil {
+				for _, part := range resp.Content.Parts {
+					if !part.Thought {
+						text.WriteString(part.Text)
+					}
+				}
+			}
+		}
+		return parseNewsLabels(text.String(), len(headlines))
+	}
+}
+
+// parseNewsLabels 解析模型输出的标签数组，条数不符或含未知标签时返回错误
+func parseNewsLabels(response string, n int) ([]string, error) {
+	start, end := strings.Index(response, "["), strings.LastIndex(response, "]")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("未找到标签数组")
+	}
+	var raw []string
+	if err := json.Unmarshal([]byte(response[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("标签解析失败: %w", err)
+	}
+	if len(raw) != n {
+		return nil, fmt.Errorf("标签数 %d 与快讯数 %d 不符", len(raw), n)
+	}
+	labels := make([]string, n)
+	for i, r := range raw {
+		label, ok := newsLabelAliases[strings.ToLower(strings.TrimSpace(r))]
+		if !ok {
+			return nil, fmt.Errorf("未知标签: %s", r)
+		}
+		labels[i] = label
+	}
+	return labels, nil
+}
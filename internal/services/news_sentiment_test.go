@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// newTestNewsService 预置快讯缓存，避免请求财联社
+func newTestNewsService(contents ...string) *NewsService {
+	s := NewNewsService()
+	for _, c := range contents {
+		tg := Telegraph{Time: "10:00:00", Content: c}
+		s.scoreTelegraph(&tg)
+		s.telegraphs = append(s.telegraphs, tg)
+	}
+	s.lastFetchTime = time.Now()
+	return s
+}
+
+func TestGetNewsSentiment(t *testing.T) {
+	s := newTestNewsService(
+		"贵州茅台前三季度净利润同比增长15%，业绩超预期",
+		"贵州茅台控股股东拟增持不超过2亿元",
+		"贵州茅台召开临时股东大会",
+		"某地产公司债券违约，股价闪崩",
+	)
+	stock := models.Stock{Symbol: "sh600519", Name: "贵州茅台"}
+
+	calls := 0
+	classify := func(_ context.Context, headlines []string) ([]string, error) {
+		calls++
+		if len(headlines) != 3 {
+			t.Errorf("headlines = %v", headlines)
+		}
+		return []string{SentimentPositive, SentimentNegative, SentimentNeutral}, nil
+	}
+	result, err := s.GetNewsSentiment(context.Background(), stock, classify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Method != SentimentMethodLLM || result.BullishCount != 1 || result.BearishCount != 1 || result.NeutralCount != 1 || result.Score != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if !reflect.DeepEqual(result.TopBearishHeadlines, []string{"贵州茅台控股股东拟增持不超过2亿元"}) {
+		t.Errorf("bearish = %v", result.TopBearishHeadlines)
+	}
+
+	// 同一批快讯 5 分钟内直接返回缓存
+	if _, err := s.GetNewsSentiment(context.Background(), stock, classify); err != nil || calls != 1 {
+		t.Errorf("cached call: calls=%d err=%v", calls, err)
+	}
+
+	// 模型失败时降级为词典打分
+	failing := func(context.Context, []string) ([]string, error) { return nil, errors.New("timeout") }
+	result, err = s.GetNewsSentiment(context.Background(), models.Stock{}, failing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Method != SentimentMethodLexicon || result.BullishCount != 2 || result.BearishCount != 1 || result.Score != 0.25 {
+		t.Errorf("lexicon result = %+v", result)
+	}
+	if result.TopBearishHeadlines[0] != "某地产公司债券违约，股价闪崩" {
+		t.Errorf("bearish = %v", result.TopBearishHeadlines)
+	}
+
+	// 没有提及该股票的快讯
+	result, _ = s.GetNewsSentiment(context.Background(), models.Stock{Symbol: "sz000001", Name: "平安银行"}, classify)
+	if result.Note == "" || result.BullishCount+result.BearishCount+result.NeutralCount != 0 {
+		t.Errorf("empty result = %+v", result)
+	}
+}
+
+func TestParseNewsLabels(t *testing.T) {
+	labels, err := parseNewsLabels("```json\n[\"利好\", \"中性\", \"Bearish\"]\n```", 3)
+	if err != nil || !reflect.DeepEqual(labels, []string{SentimentPositive, SentimentNeutral, SentimentNegative}) {
+		t.Errorf("labels = %v, %v", labels, err)
+	}
+	if _, err := parseNewsLabels(`["利好"]`, 2); err == nil {
+		t.Error("length mismatch should fail")
+	}
+	if _, err := parseNewsLabels(`["看涨"]`, 1); err == nil {
+		t.Error("unknown label should fail")
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"strings"
@@ -8,36 +9,55 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/run-bigpig/jcp/internal/models"
 	"github.com/run-bigpig/jcp/internal/pkg/proxy"
 )
 
 // Telegraph 快讯数据结构
 type Telegraph struct {
-	Time    string `json:"time"`
-	Content string `json:"content"`
-	URL     string `json:"url"`
+	Time           string  `json:"time"`
+	Content        string  `json:"content"`
+	URL            string  `json:"url"`
+	Sentiment      string  `json:"sentiment"`      // 词典情绪标签：positive/negative/neutral
+	SentimentScore float64 `json:"sentimentScore"` // 词典情绪得分 [-1, 1]
 }
 
 // NewsService 资讯服务
 type NewsService struct {
-	client *http.Client
+	client  *http.Client
+	lexicon *SentimentLexicon // 快讯情绪打分词典
 
 	// 缓存
 	telegraphs    []Telegraph
 	lastFetchTime time.Time
 	mu            sync.RWMutex
+
+	sentimentMu    sync.Mutex
+	sentimentCache map[string]*newsSentimentCache // key: 代码:天数:快讯批次哈希
+
+	downloadClient      *http.Client // 公告下载（含 PDF 附件），超时更长
+	announcementDir     string       // 公告正文磁盘缓存目录，为空时使用默认缓存目录
+	announcementAnyHost bool         // 测试时不限制公告域名
+	announcementMu      sync.Mutex
+	announcementCache   map[string]*announcementListCache // key: 代码:条数
 }
 
 // NewNewsService 创建资讯服务
 func NewNewsService() *NewsService {
-	return &NewsService{
-		client:     proxy.GetManager().GetClientWithTimeout(10 * time.Second),
-		telegraphs: make([]Telegraph, 0),
+	s := &NewsService{
+		client:         proxy.GetManager().GetClientWithTimeout(10 * time.Second),
+		lexicon:        NewSentimentLexicon(nil, nil),
+		telegraphs:     make([]Telegraph, 0),
+		sentimentCache: make(map[string]*newsSentimentCache),
+
+		announcementCache: make(map[string]*announcementListCache),
 	}
+	s.downloadClient = s.newAnnouncementClient()
+	return s
 }
 
 // GetTelegraphList 获取财联社快讯列表
-func (s *NewsService) GetTelegraphList() ([]Telegraph, error) {
+func (s *NewsService) GetTelegraphList(ctx context.Context) ([]Telegraph, error) {
 	// 检查缓存，30秒内不重复请求
 	s.mu.RLock()
 	if time.Since(s.lastFetchTime) < 30*time.Second && len(s.telegraphs) > 0 {
@@ -49,7 +69,7 @@ func (s *NewsService) GetTelegraphList() ([]Telegraph, error) {
 	s.mu.RUnlock()
 
 	// 请求财联社快讯页面
-	req, err := http.NewRequest("GET", "https://www.cls.cn/telegraph", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.cls.cn/telegraph", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -110,8 +130,11 @@ func (s *NewsService) GetTelegraphList() ([]Telegraph, error) {
 		}
 	})
 
-	// 更新缓存
+	// 更新缓存，入缓存前按词典打分
 	s.mu.Lock()
+	for i := range telegraphs {
+		s.scoreTelegraph(&telegraphs[i])
+	}
 	s.telegraphs = telegraphs
 	s.lastFetchTime = time.Now()
 	s.mu.Unlock()
@@ -119,6 +142,26 @@ func (s *NewsService) GetTelegraphList() ([]Telegraph, error) {
 	return telegraphs, nil
 }
 
+// SetSentimentTerms 设置用户自定义的利好/利空词条，并对已缓存快讯重新打分
+func (s *NewsService) SetSentimentTerms(positive, negative []string) {
+	lexicon := NewSentimentLexicon(positive, negative)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lexicon = lexicon
+	// 复制后再打分，已返回给调用方的切片不受影响
+	telegraphs := make([]Telegraph, len(s.telegraphs))
+	copy(telegraphs, s.telegraphs)
+	for i := range telegraphs {
+		s.scoreTelegraph(&telegraphs[i])
+	}
+	s.telegraphs = telegraphs
+}
+
+// scoreTelegraph 为快讯打上词典情绪分，调用方需持有写锁
+func (s *NewsService) scoreTelegraph(tg *Telegraph) {
+	tg.SentimentScore, tg.Sentiment = s.lexicon.Score(tg.Content)
+}
+
 // GetLatestTelegraph 获取最新一条快讯
 func (s *NewsService) GetLatestTelegraph() *Telegraph {
 	s.mu.RLock()
@@ -130,6 +173,26 @@ func (s *NewsService) GetLatestTelegraph() *Telegraph {
 	return nil
 }
 
+// MatchTelegraphs 从快讯中筛选提及该股票（名称或6位代码）的条目，按原顺序最多返回 limit 条
+func MatchTelegraphs(telegraphs []Telegraph, stock models.Stock, limit int) []Telegraph {
+	name := strings.ReplaceAll(strings.TrimSpace(stock.Name), " ", "")
+	code := models.NormalizeSymbol(stock.Symbol)
+	if len(code) == 8 {
+		code = code[2:]
+	}
+	var matched []Telegraph
+	for _, tg := range telegraphs {
+		if limit > 0 && len(matched) >= limit {
+			break
+		}
+		content := strings.ReplaceAll(tg.Content, " ", "")
+		if (name != "" && strings.Contains(content, name)) || (len(code) == 6 && strings.Contains(content, code)) {
+			matched = append(matched, tg)
+		}
+	}
+	return matched
+}
+
 // cleanContent 清理内容中的多余空白字符
 func cleanContent(s string) string {
 	// 替换多个空白字符为单个空格
@@ -1,13 +1,16 @@
 package services
 
 import (
+	"context"
 	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
 )
 
 func TestGetTelegraphList(t *testing.T) {
 	service := NewNewsService()
 
-	telegraphs, err := service.GetTelegraphList()
+	telegraphs, err := service.GetTelegraphList(context.Background())
 	if err != nil {
 		t.Fatalf("获取快讯失败: %v", err)
 	}
@@ -46,7 +49,7 @@ func TestGetLatestTelegraph(t *testing.T) {
 	service := NewNewsService()
 
 	// 先获取列表填充缓存
-	_, err := service.GetTelegraphList()
+	_, err := service.GetTelegraphList(context.Background())
 	if err != nil {
 		t.Fatalf("获取快讯失败: %v", err)
 	}
@@ -61,6 +64,27 @@ func TestGetLatestTelegraph(t *testing.T) {
 	t.Logf("最新快讯内容: %s", truncate(latest.Content, 150))
 }
 
+func TestMatchTelegraphs(t *testing.T) {
+	telegraphs := []Telegraph{
+		{Content: "贵州 茅台发布年报，净利润同比增长15%"},
+		{Content: "央行开展逆回购操作"},
+		{Content: "600519 大宗交易成交2亿元"},
+		{Content: "茅台酒批价回落"},
+	}
+	stock := models.Stock{Symbol: "sh600519", Name: "贵州茅台"}
+
+	got := MatchTelegraphs(telegraphs, stock, 0)
+	if len(got) != 2 || got[0].Content != telegraphs[0].Content || got[1].Content != telegraphs[2].Content {
+		t.Fatalf("matched = %+v", got)
+	}
+	if got := MatchTelegraphs(telegraphs, stock, 1); len(got) != 1 {
+		t.Errorf("limit 1 matched %d", len(got))
+	}
+	if got := MatchTelegraphs(telegraphs, models.Stock{Symbol: "sz000001", Name: "平安银行"}, 0); len(got) != 0 {
+		t.Errorf("unexpected match: %+v", got)
+	}
+}
+
 // truncate 截断字符串
 func truncate(s string, maxLen int) string {
 	runes := []rune(s)
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// maxChangePercent A股单日涨跌幅的合理上限（主板新股首日 +44%），超出视为数据异常
+const maxChangePercent = 44.0
+
+// quoteClock 判断盘前状态使用的市场时钟，测试中替换
+var quoteClock = time.Now
+
+// ValidateQuote 校验行情合理性，不合理时标记 DataSuspect 并记录原因
+// 数据源偶发返回现价 0、涨跌幅 0 的空行情，直接交给专家会得出"跌到 0 元"之类的错误结论
+// 盘前未开盘与停牌时现价同样为 0，属正常状态：以昨收作为现价并标记 QuoteState
+func ValidateQuote(stock *models.Stock) {
+	ValidateQuoteAt(stock, quoteClock())
+}
+
+// ValidateQuoteAt 按 now 所处的市场时段校验行情，用于判断行情获取时刻是否处于盘前
+func ValidateQuoteAt(stock *models.Stock, now time.Time) {
+	stock.DataSuspect, stock.SuspectReason = false, ""
+	if state := idleQuoteState(stock, now); state != "" {
+		stock.QuoteState = state
+		stock.Price, stock.Change, stock.ChangePercent = stock.PreClose, 0, 0
+	}
+	if reason := quoteSuspectReason(stock); reason != "" {
+		stock.DataSuspect, stock.SuspectReason = true, reason
+	}
+}
+
+func quoteSuspectReason(stock *models.Stock) string {
+	for _, v := range []float64{stock.Price, stock.ChangePercent, stock.PreClose, stock.Amount} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "行情包含无效数值"
+		}
+	}
+	switch {
+	case stock.Price <= 0:
+		return "现价为 0（数据源异常）"
+	case stock.PreClose <= 0:
+		return "缺少昨收价"
+	case stock.Volume < 0 || stock.Amount < 0:
+		return "成交量为负数"
+	case math.Abs(stock.ChangePercent) > maxChangePercent && !isNewListing(stock):
+		return "涨跌幅超出合理范围"
+	}
+	return ""
+}
+
+// idleQuoteState 现价为 0、无成交且昨收有效时，按停牌标记与市场时钟区分停牌和盘前未开盘
+// 开盘后既未停牌又无现价的行情仍按异常处理
+func idleQuoteState(stock *models.Stock, now time.Time) models.QuoteState {
+	if stock.Price != 0 || stock.Volume != 0 || !(stock.PreClose > 0) {
+		return ""
+	}
+	if stock.QuoteState == models.QuoteStateHalted {
+		return models.QuoteStateHalted
+	}
+	now = now.In(cacheEpochLoc)
+	if now.Hour()*60+now.Minute() < marketOpenMinutes {
+		return models.QuoteStatePreOpen
+	}
+	return ""
+}
+
+// isNewListing 是否为上市初期不设涨跌幅限制的新股（新浪行情名称以 N/C 开头）
+func isNewListing(stock *models.Stock) bool {
+	return stock.Type != models.InstrumentIndex &&
+		(strings.HasPrefix(stock.Name, "N") || strings.HasPrefix(stock.Name, "C"))
+}
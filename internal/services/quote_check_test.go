@@ -0,0 +1,130 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestValidateQuote(t *testing.T) {
+	normal := models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500, PreClose: 1480, ChangePercent: 1.35, Volume: 100, Amount: 1.5e5}
+	tests := []struct {
+		name    string
+		modify  func(s *models.Stock)
+		suspect bool
+	}{
+		{"正常行情", func(s *models.Stock) {}, false},
+		{"现价为0", func(s *models.Stock) { s.Price, s.ChangePercent = 0, 0 }, true},
+		{"现价为负", func(s *models.Stock) { s.Price = -1 }, true},
+		{"现价NaN", func(s *models.Stock) { s.Price = math.NaN() }, true},
+		{"涨跌幅Inf", func(s *models.Stock) { s.ChangePercent = math.Inf(1) }, true},
+		{"缺少昨收", func(s *models.Stock) { s.PreClose = 0 }, true},
+		{"成交量为负", func(s *models.Stock) { s.Volume = -1 }, true},
+		{"成交额为负", func(s *models.Stock) { s.Amount = -1 }, true},
+		{"零成交量", func(s *models.Stock) { s.Volume, s.Amount = 0, 0 }, false},
+		{"涨停20%", func(s *models.Stock) { s.ChangePercent = 20 }, false},
+		{"涨幅44%边界", func(s *models.Stock) { s.ChangePercent = 44 }, false},
+		{"涨幅越界", func(s *models.Stock) { s.ChangePercent = 60 }, true},
+		{"跌幅越界", func(s *models.Stock) { s.ChangePercent = -50 }, true},
+		{"新股首日N", func(s *models.Stock) { s.Name, s.ChangePercent = "N新股", 300 }, false},
+		{"新股C", func(s *models.Stock) { s.Name, s.ChangePercent = "C新股", -45 }, false},
+		{"指数不按新股豁免", func(s *models.Stock) {
+			s.Symbol, s.Name, s.Type, s.ChangePercent = "sz399006", "创业板指", models.InstrumentIndex, 50
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := normal
+			tt.modify(&s)
+			ValidateQuote(&s)
+			if s.DataSuspect != tt.suspect {
+				t.Fatalf("DataSuspect = %v, want %v (reason %q)", s.DataSuspect, tt.suspect, s.SuspectReason)
+			}
+			if s.DataSuspect == (s.SuspectReason == "") {
+				t.Fatalf("reason = %q", s.SuspectReason)
+			}
+		})
+	}
+
+	// 重新校验时清除旧标记
+	s := normal
+	s.DataSuspect, s.SuspectReason = true, "旧原因"
+	ValidateQuote(&s)
+	if s.DataSuspect || s.SuspectReason != "" {
+		t.Fatalf("stale mark: %+v", s)
+	}
+}
+
+func TestValidateQuote_IdleStates(t *testing.T) {
+	day := func(hour, minute int) time.Time { return time.Date(2025, 3, 7, hour, minute, 0, 0, cacheEpochLoc) }
+	preOpen := models.Stock{Symbol: "sh600519", Name: "贵州茅台", PreClose: 1480, Change: -1480, ChangePercent: -100}
+	tests := []struct {
+		name    string
+		modify  func(s *models.Stock)
+		now     time.Time
+		state   models.QuoteState
+		suspect bool
+	}{
+		{"9:15盘前", func(s *models.Stock) {}, day(9, 15), models.QuoteStatePreOpen, false},
+		{"9:29集合竞价", func(s *models.Stock) {}, day(9, 29), models.QuoteStatePreOpen, false},
+		{"开盘后无现价", func(s *models.Stock) {}, day(10, 0), "", true},
+		{"停牌", func(s *models.Stock) { s.QuoteState = models.QuoteStateHalted }, day(10, 0), models.QuoteStateHalted, false},
+		{"盘前缺少昨收", func(s *models.Stock) { s.PreClose = 0 }, day(9, 15), "", true},
+		{"盘前有成交无现价", func(s *models.Stock) { s.Volume = 100 }, day(9, 15), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := preOpen
+			tt.modify(&s)
+			ValidateQuoteAt(&s, tt.now)
+			if s.DataSuspect != tt.suspect || s.QuoteState != tt.state {
+				t.Fatalf("suspect = %v state = %q, want %v %q (reason %q)", s.DataSuspect, s.QuoteState, tt.suspect, tt.state, s.SuspectReason)
+			}
+			if tt.state != "" && (s.Price != s.PreClose || s.ChangePercent != 0 || s.Change != 0) {
+				t.Fatalf("idle quote should use PreClose: %+v", s)
+			}
+			// 以昨收补齐后再次校验，状态保持不变
+			ValidateQuoteAt(&s, tt.now)
+			if s.DataSuspect != tt.suspect || s.QuoteState != tt.state {
+				t.Fatalf("revalidate: suspect = %v state = %q", s.DataSuspect, s.QuoteState)
+			}
+		})
+	}
+}
+
+func TestParseStockFields_Suspect(t *testing.T) {
+	clock := time.Date(2025, 3, 7, 10, 0, 0, 0, cacheEpochLoc)
+	quoteClock = func() time.Time { return clock }
+	defer func() { quoteClock = time.Now }()
+
+	ms := &MarketService{}
+	parts := make([]string, 32)
+	for i := range parts {
+		parts[i] = "0"
+	}
+	parts[0] = "平安银行"
+	parts[2] = "10.50" // 昨收
+	stock := ms.parseStockFields("sz000001", parts)
+	if !stock.DataSuspect {
+		t.Fatalf("现价为 0 的行情应标记异常: %+v", stock)
+	}
+
+	// 停牌：交易状态字段非 00
+	halted := append(append([]string{}, parts...), "03")
+	if stock = ms.parseStockFields("sz000001", halted); stock.DataSuspect || stock.QuoteState != models.QuoteStateHalted || stock.Price != 10.50 {
+		t.Fatalf("停牌行情应以昨收为现价: %+v", stock)
+	}
+
+	// 盘前：9:25 前新浪现价为 0
+	clock = time.Date(2025, 3, 7, 9, 15, 0, 0, cacheEpochLoc)
+	if stock = ms.parseStockFields("sz000001", append(parts, "00")); stock.DataSuspect || stock.QuoteState != models.QuoteStatePreOpen || stock.Price != 10.50 {
+		t.Fatalf("盘前行情应以昨收为现价: %+v", stock)
+	}
+
+	parts[3] = "10.60"
+	if stock = ms.parseStockFields("sz000001", parts); stock.DataSuspect || stock.QuoteState != "" {
+		t.Fatalf("正常行情被标记异常: %+v", stock)
+	}
+}
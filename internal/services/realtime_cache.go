@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	// RealtimeCacheTTLTrading 交易时段实时行情缓存时长
+	RealtimeCacheTTLTrading = 3 * time.Second
+	// RealtimeCacheTTLIdle 非交易时段实时行情缓存时长
+	RealtimeCacheTTLIdle = 60 * time.Second
+)
+
+// RealtimeCacheStats 实时行情缓存命中统计
+type RealtimeCacheStats struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	Entries int     `json:"entries"`
+	HitRate float64 `json:"hitRate"` // 0~1，无请求时为 0
+}
+
+// realtimeCacheEntry 实时行情缓存项
+type realtimeCacheEntry struct {
+	data      []models.Stock
+	timestamp time.Time
+}
+
+// realtimeCache 实时行情缓存，key 为排序后的代码列表
+type realtimeCache struct {
+	mu         sync.RWMutex
+	entries    map[string]*realtimeCacheEntry
+	ttlTrading time.Duration
+	ttlIdle    time.Duration
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+}
+
+func newRealtimeCache() *realtimeCache {
+	return &realtimeCache{
+		entries:    make(map[string]*realtimeCacheEntry),
+		ttlTrading: RealtimeCacheTTLTrading,
+		ttlIdle:    RealtimeCacheTTLIdle,
+	}
+}
+
+// realtimeCacheKey 代码去重排序后拼接，保证同一组代码命中同一缓存
+func realtimeCacheKey(codes []string) string {
+	sorted := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		sorted = append(sorted, c)
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// get 读取缓存，未命中或过期时调用 fetch 并写回
+func (c *realtimeCache) get(codes []string, ttl time.Duration, fetch func(codes ...string) ([]models.Stock, error)) ([]models.Stock, error) {
+	key := realtimeCacheKey(codes)
+
+	c.mu.RLock()
+	cached, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && time.Since(cached.timestamp) < ttl {
+		c.hits.Add(1)
+		return append([]models.Stock(nil), cached.data...), nil
+	}
+	c.misses.Add(1)
+
+	data, err := fetch(codes...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &realtimeCacheEntry{data: data, timestamp: time.Now()}
+	c.mu.Unlock()
+	return append([]models.Stock(nil), data...), nil
+}
+
+// clean 清理过期缓存
+func (c *realtimeCache) clean(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, cached := range c.entries {
+		if now.Sub(cached.timestamp) > c.ttlIdle {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// reset 清空全部缓存，保留命中统计
+func (c *realtimeCache) reset() {
+	c.mu.Lock()
+	c.entries = make(map[string]*realtimeCacheEntry)
+	c.mu.Unlock()
+}
+
+// stats 返回命中统计
+func (c *realtimeCache) stats() RealtimeCacheStats {
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+
+	s := RealtimeCacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Entries: entries}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+	return s
+}
+
+// SetRealtimeCacheTTL 设置实时行情缓存时长（交易时段/非交易时段），非正数保留原值
+func (ms *MarketService) SetRealtimeCacheTTL(trading, idle time.Duration) {
+	ms.realtimeCache.mu.Lock()
+	defer ms.realtimeCache.mu.Unlock()
+	if trading > 0 {
+		ms.realtimeCache.ttlTrading = trading
+	}
+	if idle > 0 {
+		ms.realtimeCache.ttlIdle = idle
+	}
+}
+
+// realtimeCacheTTL 按当前市场状态返回缓存时长
+func (ms *MarketService) realtimeCacheTTL() time.Duration {
+	trading := ms.GetMarketStatus().Status == "trading"
+	ms.realtimeCache.mu.RLock()
+	defer ms.realtimeCache.mu.RUnlock()
+	if trading {
+		return ms.realtimeCache.ttlTrading
+	}
+	return ms.realtimeCache.ttlIdle
+}
+
+// GetStockRealTimeDataCached 获取股票实时数据，带缓存
+// 供会议内工具调用等高频场景使用；前端直接请求仍走 GetStockRealTimeData 拿最新数据
+func (ms *MarketService) GetStockRealTimeDataCached(ctx context.Context, codes ...string) ([]models.Stock, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	return ms.realtimeCache.get(codes, ms.realtimeCacheTTL(), func(codes ...string) ([]models.Stock, error) {
+		return ms.GetStockRealTimeData(ctx, codes...)
+	})
+}
+
+// CacheStats 返回实时行情缓存命中统计
+func (ms *MarketService) CacheStats() RealtimeCacheStats {
+	return ms.realtimeCache.stats()
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestRealtimeCacheKey(t *testing.T) {
+	a := realtimeCacheKey([]string{"sz000001", "SH600519 ", "sz000001"})
+	b := realtimeCacheKey([]string{"sh600519", "sz000001"})
+	if a != b || a != "sh600519,sz000001" {
+		t.Errorf("keys = %q / %q", a, b)
+	}
+}
+
+func TestRealtimeCacheHitMiss(t *testing.T) {
+	c := newRealtimeCache()
+	calls := 0
+	fetch := func(codes ...string) ([]models.Stock, error) {
+		calls++
+		return []models.Stock{{Symbol: codes[0], Price: 10}}, nil
+	}
+
+	if _, err := c.get([]string{"sh600519", "sz000001"}, time.Minute, fetch); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.get([]string{"sz000001", "sh600519"}, time.Minute, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || len(got) != 1 {
+		t.Fatalf("calls = %d, got = %+v", calls, got)
+	}
+	// 调用方修改返回值不影响缓存
+	got[0].Price = 0
+	if again, _ := c.get([]string{"sh600519", "sz000001"}, time.Minute, fetch); again[0].Price != 10 {
+		t.Errorf("cached data mutated: %+v", again)
+	}
+
+	// TTL 过期后重新拉取
+	if _, err := c.get([]string{"sh600519", "sz000001"}, 0, fetch); err != nil || calls != 2 {
+		t.Errorf("expired entry not refetched, calls = %d", calls)
+	}
+
+	// 拉取失败不写缓存
+	failing := func(codes ...string) ([]models.Stock, error) { return nil, errors.New("boom") }
+	if _, err := c.get([]string{"sh000001"}, time.Minute, failing); err == nil {
+		t.Error("expected error")
+	}
+
+	s := c.stats()
+	if s.Hits != 2 || s.Misses != 3 || s.Entries != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -62,7 +63,7 @@ func NewResearchReportService() *ResearchReportService {
 // stockCode: 股票代码 (如 "000001"，支持带前缀如 "sz000001")
 // pageSize: 每页数量
 // pageNo: 页码
-func (s *ResearchReportService) GetResearchReports(stockCode string, pageSize, pageNo int) (*ResearchReportResponse, error) {
+func (s *ResearchReportService) GetResearchReports(ctx context.Context, stockCode string, pageSize, pageNo int) (*ResearchReportResponse, error) {
 	// 去除股票代码前缀
 	code := strings.TrimPrefix(stockCode, "sz")
 	code = strings.TrimPrefix(code, "sh")
@@ -71,7 +72,7 @@ func (s *ResearchReportService) GetResearchReports(stockCode string, pageSize, p
 	url := fmt.Sprintf("%s?industryCode=*&pageSize=%d&industry=*&rating=*&ratingChange=*&beginTime=2020-01-01&endTime=%d-01-01&pageNo=%d&fields=&qType=0&orgCode=&code=%s&rcode=",
 		eastmoneyReportAPI, pageSize, time.Now().Year()+1, pageNo, code)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -127,7 +128,7 @@ func (s *ResearchReportService) GetReportPDFUrl(infoCode string) string {
 
 // GetReportContent 获取研报正文内容
 // infoCode: 研报唯一标识码
-func (s *ResearchReportService) GetReportContent(infoCode string) (*ReportContentResponse, error) {
+func (s *ResearchReportService) GetReportContent(ctx context.Context, infoCode string) (*ReportContentResponse, error) {
 	if infoCode == "" {
 		return nil, fmt.Errorf("infoCode 不能为空")
 	}
@@ -135,7 +136,7 @@ func (s *ResearchReportService) GetReportContent(infoCode string) (*ReportConten
 	// 从东方财富研报详情页面获取内容
 	url := fmt.Sprintf("https://data.eastmoney.com/report/zw_stock.jshtml?infocode=%s", infoCode)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -9,7 +10,7 @@ func TestGetResearchReports(t *testing.T) {
 	service := NewResearchReportService()
 
 	// 测试获取平安银行(000001)的研报
-	result, err := service.GetResearchReports("000001", 5, 1)
+	result, err := service.GetResearchReports(context.Background(), "000001", 5, 1)
 	if err != nil {
 		t.Fatalf("获取研报失败: %v", err)
 	}
@@ -26,7 +27,7 @@ func TestGetResearchReportsWithPrefix(t *testing.T) {
 	service := NewResearchReportService()
 
 	// 测试带前缀的股票代码
-	result, err := service.GetResearchReports("sz000001", 3, 1)
+	result, err := service.GetResearchReports(context.Background(), "sz000001", 3, 1)
 	if err != nil {
 		t.Fatalf("获取研报失败: %v", err)
 	}
@@ -38,7 +39,7 @@ func TestGetReportContent(t *testing.T) {
 	service := NewResearchReportService()
 
 	// 先获取研报列表，拿到 infoCode
-	result, err := service.GetResearchReports("000001", 1, 1)
+	result, err := service.GetResearchReports(context.Background(), "000001", 1, 1)
 	if err != nil {
 		t.Fatalf("获取研报列表失败: %v", err)
 	}
@@ -52,7 +53,7 @@ func TestGetReportContent(t *testing.T) {
 	fmt.Printf("InfoCode: %s\n\n", infoCode)
 
 	// 获取研报内容
-	content, err := service.GetReportContent(infoCode)
+	content, err := service.GetReportContent(context.Background(), infoCode)
 	if err != nil {
 		t.Fatalf("获取研报内容失败: %v", err)
 	}
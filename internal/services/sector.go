@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	// 东方财富板块列表，按当日涨幅降序（t:2 行业，t:3 概念；f62 主力净流入，f109 5日涨跌幅）
+	emSectorListURL = "https://push2.eastmoney.com/api/qt/clist/get?pn=%d&pz=%d&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:90+t:%d&fields=f3,f12,f14,f62,f109,f128,f136"
+	// 东方财富个股所属板块（含行业、概念、地域）
+	emStockBoardsURL = "https://push2.eastmoney.com/api/qt/slist/get?fltt=2&invt=2&np=1&spt=3&pi=0&pz=200&po=1&fid=f3&secid=%s&fields=f12,f14"
+	// 5日相对强弱基准：沪深300
+	emSectorBenchmarkURL = "https://push2.eastmoney.com/api/qt/ulist.np/get?fltt=2&secids=1.000300&fields=f12,f14,f3,f109"
+
+	sectorPageSize = 100
+	sectorMaxPages = 10
+	sectorCacheTTL = time.Minute
+
+	// SectorDefaultLimit 领涨/领跌板块默认各取前 N 个
+	SectorDefaultLimit = 5
+)
+
+// sectorSnapshot 全部行业/概念板块的当日表现，按涨幅降序
+type sectorSnapshot struct {
+	industries  []models.SectorPerformance
+	concepts    []models.SectorPerformance
+	benchmark   string
+	benchmark5D float64
+	timestamp   time.Time
+}
+
+// GetSectorPerformance 获取当日领涨/领跌的行业与概念板块
+func (ms *MarketService) GetSectorPerformance(ctx context.Context, limit int) (*models.SectorOverview, error) {
+	if limit <= 0 {
+		limit = SectorDefaultLimit
+	}
+	snap, err := ms.getSectorSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &models.SectorOverview{
+		Benchmark:         snap.benchmark,
+		BenchmarkChange5D: snap.benchmark5D,
+		IndustryCount:     len(snap.industries),
+		ConceptCount:      len(snap.concepts),
+	}
+	overview.TopIndustries, overview.BottomIndustries = topBottomSectors(snap.industries, limit)
+	overview.TopConcepts, overview.BottomConcepts = topBottomSectors(snap.concepts, limit)
+	return overview, nil
+}
+
+// GetStockSectors 获取个股所属的行业与概念板块及其当日表现
+func (ms *MarketService) GetStockSectors(ctx context.Context, code string) (*models.StockSectors, error) {
+	code = models.NormalizeSymbol(code)
+	if models.IsIndexSymbol(code) {
+		return nil, fmt.Errorf("not a stock symbol: %s", code)
+	}
+	code, err := stockSymbol(code)
+	if err != nil {
+		return nil, err
+	}
+	secid := eastMoneySecID(code)
+
+	resp, err := ms.getEastMoneyQuotes(ctx, fmt.Sprintf(emStockBoardsURL, secid))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil || len(resp.Data.Diff) == 0 {
+		return nil, fmt.Errorf("stock sectors not found: %s", code)
+	}
+
+	snap, err := ms.getSectorSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	industries := indexSectors(snap.industries)
+	concepts := indexSectors(snap.concepts)
+
+	result := &models.StockSectors{
+		Code:              code,
+		Benchmark:         snap.benchmark,
+		BenchmarkChange5D: snap.benchmark5D,
+		IndustryCount:     len(snap.industries),
+		ConceptCount:      len(snap.concepts),
+		Industries:        []models.SectorPerformance{},
+		Concepts:          []models.SectorPerformance{},
+	}
+	// 地域板块不在两张列表中，直接忽略
+	for _, d := range resp.Data.Diff {
+		if s, ok := industries[d.Code]; ok {
+			result.Industries = append(result.Industries, s)
+		} else if s, ok := concepts[d.Code]; ok {
+			result.Concepts = append(result.Concepts, s)
+		}
+	}
+	sort.SliceStable(result.Concepts, func(i, j int) bool { return result.Concepts[i].Rank < result.Concepts[j].Rank })
+	return result, nil
+}
+
+// getSectorSnapshot 获取板块快照，带缓存
+// 行业板块获取失败时返回错误；概念板块与基准指数失败时降级为空
+func (ms *MarketService) getSectorSnapshot(ctx context.Context) (*sectorSnapshot, error) {
+	ms.sectorCacheMu.Lock()
+	if c := ms.sectorCache; c != nil && time.Since(c.timestamp) < sectorCacheTTL {
+		ms.sectorCacheMu.Unlock()
+		return c, nil
+	}
+	ms.sectorCacheMu.Unlock()
+
+	industries, err := ms.fetchSectorList(ctx, 2, models.SectorIndustry)
+	if err != nil {
+		return nil, fmt.Errorf("fetch industry sectors: %w", err)
+	}
+	snap := &sectorSnapshot{industries: industries, timestamp: time.Now()}
+	if snap.concepts, err = ms.fetchSectorList(ctx, 3, models.SectorConcept); err != nil {
+		log.Warn("获取概念板块失败: %v", err)
+	}
+	if snap.benchmark, snap.benchmark5D, err = ms.fetchSectorBenchmark(ctx); err != nil {
+		log.Warn("获取板块相对强弱基准失败: %v", err)
+	}
+	if snap.benchmark != "" {
+		for _, list := range [][]models.SectorPerformance{snap.industries, snap.concepts} {
+			for i := range list {
+				list[i].RelStrength5D = list[i].Change5D - snap.benchmark5D
+			}
+		}
+	}
+
+	ms.sectorCacheMu.Lock()
+	ms.sectorCache = snap
+	ms.sectorCacheMu.Unlock()
+	return snap, nil
+}
+
+// fetchSectorList 分页获取某类板块的完整列表，排名按当日涨幅
+func (ms *MarketService) fetchSectorList(ctx context.Context, kind int, sectorType string) ([]models.SectorPerformance, error) {
+	var sectors []models.SectorPerformance
+	for page := 1; page <= sectorMaxPages; page++ {
+		resp, err := ms.getEastMoneyQuotes(ctx, fmt.Sprintf(emSectorListURL, page, sectorPageSize, kind))
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data == nil {
+			break
+		}
+		for _, d := range resp.Data.Diff {
+			sectors = append(sectors, models.SectorPerformance{
+				Code:          d.Code,
+				Type:          sectorType,
+				Name:          d.Name,
+				ChangePercent: float64(d.Change),
+				Rank:          len(sectors) + 1,
+				LeaderName:    d.LeaderName,
+				LeaderChange:  float64(d.LeaderChange),
+				NetInflow:     float64(d.NetInflow),
+				Change5D:      float64(d.Change5D),
+			})
+		}
+		if len(resp.Data.Diff) < sectorPageSize || len(sectors) >= resp.Data.Total {
+			break
+		}
+	}
+	if len(sectors) == 0 {
+		return nil, fmt.Errorf("empty sector list: t:%d", kind)
+	}
+	return sectors, nil
+}
+
+// fetchSectorBenchmark 获取相对强弱基准指数的5日涨跌幅
+func (ms *MarketService) fetchSectorBenchmark(ctx context.Context) (string, float64, error) {
+	resp, err := ms.getEastMoneyQuotes(ctx, emSectorBenchmarkURL)
+	if err != nil {
+		return "", 0, err
+	}
+	if resp.Data == nil || len(resp.Data.Diff) == 0 {
+		return "", 0, fmt.Errorf("benchmark not found")
+	}
+	d := resp.Data.Diff[0]
+	return d.Name, float64(d.Change5D), nil
+}
+
+// topBottomSectors 取涨幅前 N 与后 N 的板块（领跌按跌幅从大到小）
+func topBottomSectors(sectors []models.SectorPerformance, limit int) (top, bottom []models.SectorPerformance) {
+	n := min(limit, len(sectors))
+	top = append([]models.SectorPerformance{}, sectors[:n]...)
+	bottom = make([]models.SectorPerformance, 0, n)
+	for i := len(sectors) - 1; i >= len(sectors)-n; i-- {
+		bottom = append(bottom, sectors[i])
+	}
+	return top, bottom
+}
+
+// indexSectors 按板块代码建立索引
+func indexSectors(sectors []models.SectorPerformance) map[string]models.SectorPerformance {
+	m := make(map[string]models.SectorPerformance, len(sectors))
+	for _, s := range sectors {
+		m[s.Code] = s
+	}
+	return m
+}
+
+// stockSymbol 校验个股代码，不带市场前缀的 6 位代码按代码段补全前缀
+func stockSymbol(code string) (string, error) {
+	if len(code) == 6 && strings.Trim(code, "0123456789") == "" {
+		code = inferStockMarket(code) + code
+	}
+	if len(code) == 8 {
+		switch code[:2] {
+		case "sh", "sz", "bj":
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("invalid stock code: %s", code)
+}
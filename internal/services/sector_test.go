@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// sectorStub 模拟东方财富板块接口：120 个行业（两页）、3 个概念、沪深300 基准
+func sectorStub(t *testing.T, benchmarkOK bool) (*MarketService, *int) {
+	t.Helper()
+	type row map[string]any
+	industries := make([]row, 120)
+	for i := range industries {
+		industries[i] = row{"f12": fmt.Sprintf("BK%04d", i+1), "f14": fmt.Sprintf("行业%d", i+1), "f3": 5 - float64(i)*0.1, "f62": 1e8, "f109": 3.0, "f128": "龙头", "f136": 10.0}
+	}
+	concepts := []row{
+		{"f12": "BK1001", "f14": "算力", "f3": 4.0, "f62": 2e8, "f109": 8.0, "f128": "A", "f136": 20.0},
+		{"f12": "BK1002", "f14": "白酒概念", "f3": 1.0, "f62": -5e7, "f109": "-", "f128": "B", "f136": 2.0},
+		{"f12": "BK1003", "f14": "消费", "f3": -1.0, "f62": 0, "f109": -2.0, "f128": "C", "f136": 1.0},
+	}
+
+	requests := 0
+	write := func(total int, diff []row) (*http.Response, error) {
+		body, _ := json.Marshal(map[string]any{"data": map[string]any{"total": total, "diff": diff}})
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body)))}, nil
+	}
+	ms := &MarketService{client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		q := req.URL.Query()
+		switch {
+		case q.Get("fs") == "m:90 t:2" && q.Get("pn") == "1":
+			return write(len(industries), industries[:100])
+		case q.Get("fs") == "m:90 t:2" && q.Get("pn") == "2":
+			return write(len(industries), industries[100:])
+		case q.Get("fs") == "m:90 t:3":
+			return write(len(concepts), concepts)
+		case strings.Contains(req.URL.Path, "ulist") && benchmarkOK:
+			return write(1, []row{{"f12": "000300", "f14": "沪深300", "f3": 0.5, "f109": 2.0}})
+		case strings.Contains(req.URL.Path, "slist") && q.Get("secid") == "1.600519":
+			return write(3, []row{{"f12": "BK1002", "f14": "白酒概念"}, {"f12": "BK9999", "f14": "贵州板块"}, {"f12": "BK0003", "f14": "行业3"}, {"f12": "BK1001", "f14": "算力"}})
+		}
+		return &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}}
+	return ms, &requests
+}
+
+func TestGetSectorPerformance(t *testing.T) {
+	ms, requests := sectorStub(t, true)
+	o, err := ms.GetSectorPerformance(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.IndustryCount != 120 || o.ConceptCount != 3 || o.Benchmark != "沪深300" {
+		t.Fatalf("overview = %+v", o)
+	}
+	if len(o.TopIndustries) != 3 || o.TopIndustries[0].Name != "行业1" || o.TopIndustries[0].Rank != 1 {
+		t.Errorf("top = %+v", o.TopIndustries)
+	}
+	// 领跌从最弱开始，排名延续第二页
+	if b := o.BottomIndustries[0]; b.Name != "行业120" || b.Rank != 120 {
+		t.Errorf("bottom = %+v", o.BottomIndustries)
+	}
+	if c := o.TopConcepts[0]; c.RelStrength5D != 6 || c.NetInflow != 2e8 || c.Type != "concept" {
+		t.Errorf("concept = %+v", c)
+	}
+	// 停牌/无数据的 "-" 按 0 处理
+	if c := o.TopConcepts[1]; c.Change5D != 0 || c.RelStrength5D != -2 {
+		t.Errorf("concept with missing 5d = %+v", c)
+	}
+
+	// 快照缓存：第二次不再请求
+	n := *requests
+	if _, err := ms.GetSectorPerformance(context.Background(), 5); err != nil || *requests != n {
+		t.Errorf("expected cached snapshot, requests %d -> %d, err %v", n, *requests, err)
+	}
+}
+
+func TestGetStockSectors(t *testing.T) {
+	ms, _ := sectorStub(t, false)
+	s, err := ms.GetStockSectors(context.Background(), "600519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Industries) != 1 || s.Industries[0].Name != "行业3" || s.Industries[0].Rank != 3 {
+		t.Errorf("industries = %+v", s.Industries)
+	}
+	// 地域板块被忽略，概念按当日排名排序
+	if len(s.Concepts) != 2 || s.Concepts[0].Name != "算力" || s.Concepts[1].Name != "白酒概念" {
+		t.Errorf("concepts = %+v", s.Concepts)
+	}
+	// 基准获取失败时不计算相对强弱
+	if s.Benchmark != "" || s.Industries[0].RelStrength5D != 0 {
+		t.Errorf("benchmark should be empty: %+v", s)
+	}
+
+	if _, err := ms.GetStockSectors(context.Background(), "sh000001"); err == nil {
+		t.Error("index symbol should be rejected")
+	}
+}
+
+func TestStockSecID(t *testing.T) {
+	for code, want := range map[string]string{"sh600519": "1.600519", "sz000001": "0.000001", "bj830799": "0.830799", "688981": "1.688981", "300750": "0.300750", "920001": "0.920001"} {
+		symbol, err := stockSymbol(code)
+		if got := eastMoneySecID(symbol); err != nil || got != want {
+			t.Errorf("secid(%s) = %s, %v", code, got, err)
+		}
+	}
+	for _, code := range []string{"abc", "xx600519", "60051"} {
+		if _, err := stockSymbol(code); err == nil {
+			t.Errorf("invalid code %s should fail", code)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// 快讯情绪标签
+const (
+	SentimentPositive = "positive"
+	SentimentNegative = "negative"
+	SentimentNeutral  = "neutral"
+)
+
+// sentimentThreshold 归一化得分超过该值才判定为利好/利空
+const sentimentThreshold = 0.2
+
+// 内置金融情绪词典，权重 1~3 表示程度
+var (
+	positiveTerms = map[string]float64{
+		"上涨": 1, "大涨": 2, "涨停": 3, "走高": 1, "拉升": 1, "反弹": 1, "回升": 1, "创新高": 2, "新高": 1,
+		"增长": 1, "同比增长": 1, "增加": 1, "扭亏": 2, "扭亏为盈": 3, "盈利": 1, "净利润增长": 2, "预增": 2,
+		"超预期": 2, "好于预期": 2, "利好": 2, "重大利好": 3, "突破": 1, "中标": 2, "签约": 1, "获批": 2,
+		"增持": 2, "回购": 2, "分红": 1, "高送转": 2, "上调": 1, "买入评级": 2, "评级上调": 2, "看好": 1,
+		"提振": 1, "复苏": 1, "回暖": 1, "景气": 1, "降准": 2, "降息": 2, "宽松": 1, "支持": 1, "鼓励": 1,
+		"净流入": 1, "加仓": 1, "翻倍": 2, "大单": 1, "订单": 1, "放量上涨": 2, "领涨": 2, "强势": 1,
+	}
+	negativeTerms = map[string]float64{
+		"下跌": 1, "大跌": 2, "跌停": 3, "走低": 1, "跳水": 2, "暴跌": 3, "重挫": 2, "闪崩": 3, "新低": 1,
+		"下滑": 1, "同比下降": 1, "下降": 1, "减少": 1, "亏损": 2, "首亏": 2, "预亏": 2, "预减": 2, "不及预期": 2,
+		"低于预期": 2, "利空": 2, "减持": 2, "清仓": 2, "质押": 1, "爆雷": 3, "违约": 3, "退市": 3, "ST": 2,
+		"立案": 3, "调查": 2, "处罚": 2, "罚款": 2, "警示函": 2, "问询函": 1, "诉讼": 1, "冻结": 2, "下调": 1,
+		"评级下调": 2, "卖出评级": 2, "担忧": 1, "风险": 1, "收紧": 1, "加息": 2, "制裁": 2, "停产": 2,
+		"净流出": 1, "领跌": 2, "弱势": 1, "破发": 2, "终止": 1, "失败": 2, "裁员": 2,
+	}
+	// negationTerms 否定词，翻转同一分句内下一个情绪词的方向
+	negationTerms = []string{"不", "未", "没有", "无法", "并未", "尚未", "不再", "未能", "难以", "停止"}
+	// intensityTerms 程度词，放大同一分句内下一个情绪词
+	intensityTerms = map[string]float64{
+		"大幅": 1.8, "显著": 1.5, "明显": 1.3, "持续": 1.2, "急剧": 1.8, "全面": 1.3, "严重": 1.8, "较大": 1.3,
+		"略": 0.6, "小幅": 0.6, "微": 0.5, "稍": 0.6,
+	}
+)
+
+// clauseBreaks 分句符，否定与程度修饰不跨分句生效
+const clauseBreaks = "，。；！？,;!?\n"
+
+// sentimentTerm 词典条目
+type sentimentTerm struct {
+	weight    float64 // 情绪词：正为利好、负为利空
+	negation  bool
+	intensity float64
+}
+
+// SentimentLexicon 基于词典的快讯情绪打分器，构建后只读，可并发使用
+type SentimentLexicon struct {
+	terms  map[string]sentimentTerm
+	maxLen int // 最长词条的字符数，用于正向最大匹配
+}
+
+// NewSentimentLexicon 以内置词典加上用户自定义词条构建打分器，自定义词条权重为 2 并覆盖内置词条
+func NewSentimentLexicon(customPositive, customNegative []string) *SentimentLexicon {
+	l := &SentimentLexicon{terms: make(map[string]sentimentTerm)}
+	for _, w := range negationTerms {
+		l.add(w, sentimentTerm{negation: true})
+	}
+	for w, f := range intensityTerms {
+		l.add(w, sentimentTerm{intensity: f})
+	}
+	for w, v := range positiveTerms {
+		l.add(w, sentimentTerm{weight: v})
+	}
+	for w, v := range negativeTerms {
+		l.add(w, sentimentTerm{weight: -v})
+	}
+	for _, w := range customPositive {
+		l.add(w, sentimentTerm{weight: 2})
+	}
+	for _, w := range customNegative {
+		l.add(w, sentimentTerm{weight: -2})
+	}
+	return l
+}
+
+func (l *SentimentLexicon) add(word string, t sentimentTerm) {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return
+	}
+	l.terms[word] = t
+	if n := utf8.RuneCountInString(word); n > l.maxLen {
+		l.maxLen = n
+	}
+}
+
+// Score 对文本打分，返回 [-1, 1] 的归一化得分与标签
+// 按正向最大匹配切词：否定词翻转、程度词放大同一分句内随后的第一个情绪词
+func (l *SentimentLexicon) Score(text string) (float64, string) {
+	runes := []rune(text)
+	var raw float64
+	negated, intensity := false, 1.0
+	for i := 0; i < len(runes); {
+		if strings.ContainsRune(clauseBreaks, runes[i]) {
+			negated, intensity = false, 1.0
+			i++
+			continue
+		}
+		term, n := l.match(runes, i)
+		if n == 0 {
+			i++
+			continue
+		}
+		i += n
+		switch {
+		case term.negation:
+			negated = !negated
+		case term.intensity != 0:
+			intensity *= term.intensity
+		default:
+			w := term.weight * intensity
+			if negated {
+				w = -w
+			}
+			raw += w
+			negated, intensity = false, 1.0
+		}
+	}
+	score := math.Round(raw/(math.Abs(raw)+2)*100) / 100
+	switch {
+	case score >= sentimentThreshold:
+		return score, SentimentPositive
+	case score <= -sentimentThreshold:
+		return score, SentimentNegative
+	default:
+		return score, SentimentNeutral
+	}
+}
+
+// match 返回从 pos 开始的最长词条及其长度，无匹配时长度为 0
+func (l *SentimentLexicon) match(runes []rune, pos int) (sentimentTerm, int) {
+	maxLen := min(l.maxLen, len(runes)-pos)
+	for n := maxLen; n > 0; n-- {
+		if t, ok := l.terms[string(runes[pos:pos+n])]; ok {
+			return t, n
+		}
+	}
+	return sentimentTerm{}, 0
+}
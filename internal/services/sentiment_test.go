@@ -0,0 +1,87 @@
+package services
+
+import "testing"
+
+// sentimentFixtures 人工标注的快讯样本
+var sentimentFixtures = []struct {
+	text  string
+	label string
+}{
+	{"贵州茅台：前三季度净利润同比增长15.04%，业绩超预期", SentimentPositive},
+	{"宁德时代午后拉升涨停，成交额突破百亿", SentimentPositive},
+	{"某公司控股股东拟增持不超过2亿元，并启动股份回购", SentimentPositive},
+	{"央行宣布降准0.5个百分点，释放长期资金约1万亿元", SentimentPositive},
+	{"光伏板块大幅反弹，多只个股创新高", SentimentPositive},
+	{"公司公告扭亏为盈，订单饱满", SentimentPositive},
+	{"某券商给予买入评级，看好公司长期发展", SentimentPositive},
+	{"公司不再亏损，毛利率回升", SentimentPositive},
+	{"某公司股价盘中跳水跌停，控股股东质押比例过高", SentimentNegative},
+	{"证监会对某公司立案调查，涉嫌信息披露违法", SentimentNegative},
+	{"公司预计前三季度净利润同比下降60%，业绩不及预期", SentimentNegative},
+	{"某地产公司债券违约，股价闪崩", SentimentNegative},
+	{"股东拟减持不超过3%股份", SentimentNegative},
+	{"美联储宣布加息25个基点，市场担忧经济前景", SentimentNegative},
+	{"公司营收大幅下滑，净利润亏损", SentimentNegative},
+	{"某公司重组未能获批", SentimentNegative},
+	{"上证指数收盘报3050点，成交额8000亿元", SentimentNeutral},
+	{"国家统计局将于明日发布9月CPI数据", SentimentNeutral},
+	{"某公司召开2024年第三次临时股东大会", SentimentNeutral},
+	{"北向资金今日净流入12亿元，但两市个股涨少跌多，风险偏好下降", SentimentNeutral},
+}
+
+func TestSentimentLexiconAccuracy(t *testing.T) {
+	lexicon := NewSentimentLexicon(nil, nil)
+	correct := 0
+	for _, f := range sentimentFixtures {
+		score, label := lexicon.Score(f.text)
+		if label == f.label {
+			correct++
+		} else {
+			t.Logf("误判: %s => %s(%.2f), want %s", f.text, label, score, f.label)
+		}
+	}
+	acc := float64(correct) / float64(len(sentimentFixtures))
+	if acc < 0.85 {
+		t.Fatalf("准确率 %.2f 低于 0.85", acc)
+	}
+}
+
+func TestSentimentLexiconModifiers(t *testing.T) {
+	lexicon := NewSentimentLexicon(nil, nil)
+	plain, _ := lexicon.Score("营收增长")
+	strong, _ := lexicon.Score("营收大幅增长")
+	if strong <= plain {
+		t.Errorf("程度词应放大得分: %.2f <= %.2f", strong, plain)
+	}
+	if s, label := lexicon.Score("并未亏损"); s <= 0 || label != SentimentPositive {
+		t.Errorf("否定词应翻转得分: %.2f %s", s, label)
+	}
+	// 否定不跨分句
+	if s, _ := lexicon.Score("公司未披露原因，股价下跌"); s >= 0 {
+		t.Errorf("否定词不应跨分句: %.2f", s)
+	}
+	if s, label := lexicon.Score(""); s != 0 || label != SentimentNeutral {
+		t.Errorf("空文本应为中性: %.2f %s", s, label)
+	}
+}
+
+func TestSentimentLexiconCustomTerms(t *testing.T) {
+	text := "公司获得国家级专精特新认定"
+	if _, label := NewSentimentLexicon(nil, nil).Score(text); label != SentimentNeutral {
+		t.Fatalf("内置词典下应为中性, got %s", label)
+	}
+	if _, label := NewSentimentLexicon([]string{"专精特新"}, nil).Score(text); label != SentimentPositive {
+		t.Errorf("自定义利好词后应为 positive, got %s", label)
+	}
+	// 自定义词条覆盖内置词条
+	if _, label := NewSentimentLexicon(nil, []string{"回购"}).Score("公司启动回购"); label != SentimentNegative {
+		t.Errorf("自定义利空词应覆盖内置词条, got %s", label)
+	}
+
+	svc := NewNewsService()
+	svc.telegraphs = []Telegraph{{Content: text}}
+	svc.SetSentimentTerms([]string{"专精特新"}, nil)
+	if got := svc.GetLatestTelegraph(); got.Sentiment != SentimentPositive || got.SentimentScore <= 0 {
+		t.Errorf("更新词条后应对缓存重新打分: %+v", got)
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// Session 延迟写盘参数：首次修改后最迟 SessionFlushInterval 写盘，或累计 SessionFlushBatch 次修改立即写盘
+// 修改直接作用于内存中的 Session，读取始终得到最新数据；崩溃最多丢失一个写盘间隔内的修改
+const (
+	SessionFlushInterval = time.Second
+	SessionFlushBatch    = 20
+)
+
+// markDirtyLocked 记录一次待写盘的修改，达到批量上限时立即写盘（调用方需持有锁）
+func (ss *SessionService) markDirtyLocked(stockCode string) error {
+	ss.dirty[stockCode] = true
+	ss.pending++
+	if ss.pending >= ss.flushBatch {
+		return ss.flushLocked()
+	}
+	if ss.flushTimer == nil {
+		ss.flushTimer = time.AfterFunc(ss.flushInterval, ss.flushDue)
+	}
+	return nil
+}
+
+// saveNowLocked 立即写盘（用户主动的修改），同时清除该 Session 的待写标记（调用方需持有锁）
+func (ss *SessionService) saveNowLocked(stockCode string) error {
+	if err := ss.saveSession(ss.sessions[stockCode]); err != nil {
+		return err
+	}
+	delete(ss.dirty, stockCode)
+	return nil
+}
+
+// flushDue 定时写盘
+func (ss *SessionService) flushDue() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.flushTimer = nil
+	if err := ss.flushLocked(); err != nil {
+		log.Warn("Session 定时写盘失败: %v", err)
+	}
+}
+
+// flushLocked 将所有待写 Session 写盘，失败的保留待写标记并等待下次写盘（调用方需持有锁）
+func (ss *SessionService) flushLocked() error {
+	if ss.flushTimer != nil {
+		ss.flushTimer.Stop()
+		ss.flushTimer = nil
+	}
+	ss.pending = 0
+
+	var firstErr error
+	for code := range ss.dirty {
+		session, ok := ss.sessions[code]
+		if !ok {
+			delete(ss.dirty, code)
+			continue
+		}
+		if err := ss.saveSession(session); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("保存 Session %s 失败: %w", code, err)
+			}
+			continue
+		}
+		delete(ss.dirty, code)
+	}
+	if len(ss.dirty) > 0 {
+		ss.flushTimer = time.AfterFunc(ss.flushInterval, ss.flushDue)
+	}
+	return firstErr
+}
+
+// Flush 立即写盘所有未保存的修改（会议结束、应用退出时调用）
+func (ss *SessionService) Flush() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.flushLocked()
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// diskMessageCount 读取磁盘上 Session 的消息数（模拟崩溃后重启）
+func diskMessageCount(t *testing.T, dir, code string) int {
+	t.Helper()
+	session, err := NewSessionService(dir).loadSession(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(session.Messages)
+}
+
+func TestSessionWriteBehind(t *testing.T) {
+	dir := t.TempDir()
+	ss := NewSessionService(dir)
+	ss.flushInterval = time.Hour // 仅由批量或显式 Flush 触发
+	ss.flushBatch = 5
+	if _, err := ss.GetOrCreateSession("sh600519", "贵州茅台"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未达批量时只修改内存，读取立即可见
+	for i := 0; i < 4; i++ {
+		if err := ss.AddMessage("sh600519", models.ChatMessage{Content: fmt.Sprintf("m%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ss.AddTokenUsage("sh600519", models.TokenUsage{AgentID: "a1", InputTokens: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(ss.GetMessages("sh600519")); got != 4 {
+		t.Fatalf("内存消息数 = %d, want 4", got)
+	}
+
+	// 第 5 次修改达到批量上限，立即写盘
+	if got := diskMessageCount(t, dir, "sh600519"); got != 4 {
+		t.Fatalf("批量写盘后磁盘消息数 = %d, want 4", got)
+	}
+
+	// 显式 Flush 写入剩余修改
+	if err := ss.AddMessages("sh600519", []models.ChatMessage{{Content: "m4"}, {Content: "m5"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := diskMessageCount(t, dir, "sh600519"); got != 4 {
+		t.Fatalf("Flush 前磁盘消息数 = %d, want 4", got)
+	}
+	if err := ss.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := diskMessageCount(t, dir, "sh600519"); got != 6 {
+		t.Fatalf("Flush 后磁盘消息数 = %d, want 6", got)
+	}
+
+	// 用户主动的修改立即写盘
+	if err := ss.UpdatePosition("sh600519", 100, 1500); err != nil {
+		t.Fatal(err)
+	}
+	if p := NewSessionService(dir).GetPosition("sh600519"); p == nil || p.Shares != 100 {
+		t.Fatalf("持仓未立即写盘: %+v", p)
+	}
+}
+
+// 崩溃最多丢失一个写盘间隔内的修改
+func TestSessionWriteBehindInterval(t *testing.T) {
+	dir := t.TempDir()
+	ss := NewSessionService(dir)
+	ss.flushInterval = 50 * time.Millisecond
+	if _, err := ss.GetOrCreateSession("sz000001", "平安银行"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := ss.AddMessage("sz000001", models.ChatMessage{Content: "观点"}); err != nil {
+		t.Fatal(err)
+	}
+	for diskMessageCount(t, dir, "sz000001") != 1 {
+		if time.Since(start) > 2*time.Second {
+			t.Fatal("修改未在写盘间隔内落盘")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if len(ss.dirty) != 0 || ss.flushTimer != nil {
+		t.Fatalf("写盘后仍有待写修改: dirty=%v timer=%v", ss.dirty, ss.flushTimer != nil)
+	}
+}
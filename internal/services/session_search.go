@@ -0,0 +1,145 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 历史讨论检索参数
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 200
+	snippetContext     = 30 // 命中片段前后保留的字符数
+)
+
+// SessionSearchResult 历史讨论检索结果
+type SessionSearchResult struct {
+	StockCode      string `json:"stockCode"`
+	StockName      string `json:"stockName"`
+	MessageID      string `json:"messageId"`
+	AgentName      string `json:"agentName"`
+	MessageSnippet string `json:"messageSnippet"` // 命中位置附近的消息片段
+	Timestamp      int64  `json:"timestamp"`
+}
+
+// SessionSearcher 历史讨论检索后端
+// 默认为子串匹配，后续可替换为向量检索
+type SessionSearcher interface {
+	Search(sessions []*models.StockSession, query string, limit int) []SessionSearchResult
+}
+
+// SetSearcher 替换检索后端，传 nil 恢复默认子串匹配
+func (ss *SessionService) SetSearcher(searcher SessionSearcher) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.searcher = searcher
+}
+
+// SearchMessages 跨全部已保存的 Session 检索讨论消息，按时间倒序返回
+func (ss *SessionService) SearchMessages(query string, limit int) []SessionSearchResult {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []SessionSearchResult{}
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	ss.mu.Lock()
+	ss.loadAllSessions()
+	sessions := make([]*models.StockSession, 0, len(ss.sessions))
+	for _, session := range ss.sessions {
+		// 拷贝消息切片头，检索期间新增消息不影响本次结果
+		s := *session
+		s.Messages = session.Messages[:len(session.Messages):len(session.Messages)]
+		sessions = append(sessions, &s)
+	}
+	searcher := ss.searcher
+	ss.mu.Unlock()
+
+	if searcher == nil {
+		searcher = substringSearcher{}
+	}
+	return searcher.Search(sessions, query, limit)
+}
+
+// substringSearcher 默认检索：忽略大小写与全半角的子串匹配
+type substringSearcher struct{}
+
+func (substringSearcher) Search(sessions []*models.StockSession, query string, limit int) []SessionSearchResult {
+	needle := foldRunes(query)
+	results := []SessionSearchResult{}
+	for _, session := range sessions {
+		for _, msg := range session.Messages {
+			content := []rune(msg.Content)
+			pos := indexRunes(foldRunes(msg.Content), needle)
+			if pos < 0 {
+				continue
+			}
+			results = append(results, SessionSearchResult{
+				StockCode:      session.StockCode,
+				StockName:      session.StockName,
+				MessageID:      msg.ID,
+				AgentName:      msg.AgentName,
+				MessageSnippet: snippet(content, pos, len(needle)),
+				Timestamp:      msg.Timestamp,
+			})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Timestamp > results[j].Timestamp })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// foldRunes 统一大小写与全角字符，逐字符映射以保持与原文的下标对应
+func foldRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '　':
+			r = ' '
+		case r >= '！' && r <= '～':
+			r -= 0xFEE0
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// indexRunes 返回 needle 在 haystack 中首次出现的字符下标，未找到返回 -1
+func indexRunes(haystack, needle []rune) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j, r := range needle {
+			if haystack[i+j] != r {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// snippet 截取命中位置前后各 snippetContext 个字符，截断处用省略号标记
+func snippet(content []rune, pos, n int) string {
+	start := max(pos-snippetContext, 0)
+	end := min(pos+n+snippetContext, len(content))
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(strings.Join(strings.Fields(string(content[start:end])), " "))
+	if end < len(content) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestSearchMessages(t *testing.T) {
+	dir := t.TempDir()
+	ss := NewSessionService(dir)
+	long := strings.Repeat("前", 40) + "ｍａｃｄ顶背离" + strings.Repeat("后", 40)
+	for _, session := range []*models.StockSession{
+		{StockCode: "sh600519", StockName: "贵州茅台", Messages: []models.ChatMessage{
+			{AgentName: "技术分析师", Content: "MACD 金叉，短期看多", Timestamp: 100},
+			{AgentName: "基本面分析师", Content: "白酒库存偏高，注意风险", Timestamp: 200},
+		}},
+		{StockCode: "sz000001", StockName: "平安银行", Messages: []models.ChatMessage{
+			{AgentName: "技术分析师", Content: long, Timestamp: 300},
+		}},
+	} {
+		if err := ss.saveSession(session); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 新实例从磁盘加载，覆盖未缓存的 Session
+	ss = NewSessionService(dir)
+	results := ss.SearchMessages("macd", 10)
+	if len(results) != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+	if results[0].StockCode != "sz000001" || results[1].StockName != "贵州茅台" {
+		t.Fatalf("应按时间倒序: %+v", results)
+	}
+	want := "…" + strings.Repeat("前", 30) + "ｍａｃｄ顶背离" + strings.Repeat("后", 27) + "…"
+	if results[0].MessageSnippet != want {
+		t.Fatalf("snippet = %q", results[0].MessageSnippet)
+	}
+	if results[1].MessageSnippet != "MACD 金叉，短期看多" {
+		t.Fatalf("snippet = %q", results[1].MessageSnippet)
+	}
+
+	if got := ss.SearchMessages("风险", 10); len(got) != 1 || got[0].AgentName != "基本面分析师" {
+		t.Fatalf("中文检索 = %+v", got)
+	}
+	if got := ss.SearchMessages("macd", 1); len(got) != 1 || got[0].Timestamp != 300 {
+		t.Fatalf("limit = %+v", got)
+	}
+	if got := ss.SearchMessages("  ", 10); len(got) != 0 {
+		t.Fatalf("空查询应无结果: %+v", got)
+	}
+}
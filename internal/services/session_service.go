@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,7 +19,15 @@ import (
 type SessionService struct {
 	sessionsDir string
 	sessions    map[string]*models.StockSession
+	searcher    SessionSearcher // nil 时使用默认子串匹配
 	mu          sync.RWMutex
+
+	// 延迟写盘：高频修改（消息、用量）先记入 dirty，按间隔或批量写盘
+	dirty         map[string]bool
+	pending       int // 上次写盘后的修改次数
+	flushTimer    *time.Timer
+	flushInterval time.Duration
+	flushBatch    int
 }
 
 // NewSessionService 创建Session服务
@@ -25,6 +35,10 @@ func NewSessionService(dataDir string) *SessionService {
 	ss := &SessionService{
 		sessionsDir: filepath.Join(dataDir, "sessions"),
 		sessions:    make(map[string]*models.StockSession),
+
+		dirty:         make(map[string]bool),
+		flushInterval: SessionFlushInterval,
+		flushBatch:    SessionFlushBatch,
 	}
 	ss.ensureDir()
 	return ss
@@ -139,7 +153,7 @@ func (ss *SessionService) AddMessage(stockCode string, msg models.ChatMessage) e
 	msg.Timestamp = time.Now().UnixMilli()
 	session.Messages = append(session.Messages, msg)
 	session.UpdatedAt = time.Now().UnixMilli()
-	return ss.saveSession(session)
+	return ss.markDirtyLocked(stockCode)
 }
 
 // AddMessages 批量添加消息到Session
@@ -165,7 +179,7 @@ func (ss *SessionService) AddMessages(stockCode string, msgs []models.ChatMessag
 	}
 	session.Messages = append(session.Messages, msgs...)
 	session.UpdatedAt = now
-	return ss.saveSession(session)
+	return ss.markDirtyLocked(stockCode)
 }
 
 // GetMessages 获取Session消息
@@ -206,12 +220,16 @@ func (ss *SessionService) ClearMessages(stockCode string) error {
 	}
 
 	session.Messages = []models.ChatMessage{}
+	session.TokenUsage = nil // 清空讨论即开启新一轮会话，用量重新统计
 	session.UpdatedAt = time.Now().UnixMilli()
-	return ss.saveSession(session)
+	return ss.saveNowLocked(stockCode)
 }
 
 // UpdatePosition 更新持仓信息
 func (ss *SessionService) UpdatePosition(stockCode string, shares int64, costPrice float64) error {
+	if models.IsIndexSymbol(stockCode) {
+		return fmt.Errorf("指数不支持设置持仓: %s", stockCode)
+	}
 	ss.mu.Lock()
 	defer ss.mu.Unlock()
 
@@ -231,11 +249,184 @@ func (ss *SessionService) UpdatePosition(stockCode string, shares int64, costPri
 		CostPrice: costPrice,
 	}
 	session.UpdatedAt = time.Now().UnixMilli()
-	return ss.saveSession(session)
+	return ss.saveNowLocked(stockCode)
+}
+
+// SetAutoTranslate 设置会话的专家回复自动翻译目标语言，target 为空时关闭
+func (ss *SessionService) SetAutoTranslate(stockCode, target string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		// 尝试从文件加载
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return fmt.Errorf("session not found: %s", stockCode)
+		}
+		ss.sessions[stockCode] = session
+	}
+
+	session.AutoTranslate = target
+	session.UpdatedAt = time.Now().UnixMilli()
+	return ss.saveNowLocked(stockCode)
+}
+
+// GetAutoTranslate 获取会话的自动翻译目标语言，未开启时返回空
+func (ss *SessionService) GetAutoTranslate(stockCode string) string {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if session, ok := ss.sessions[stockCode]; ok {
+		return session.AutoTranslate
+	}
+	return ""
+}
+
+// AddTokenUsage 累加专家的 token 用量
+func (ss *SessionService) AddTokenUsage(stockCode string, usage models.TokenUsage) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		// 尝试从文件加载
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return fmt.Errorf("session not found: %s", stockCode)
+		}
+		ss.sessions[stockCode] = session
+	}
+
+	if session.TokenUsage == nil {
+		session.TokenUsage = make(map[string]*models.TokenUsage)
+	}
+	total, ok := session.TokenUsage[usage.AgentID]
+	if !ok {
+		total = &models.TokenUsage{AgentID: usage.AgentID}
+		session.TokenUsage[usage.AgentID] = total
+	}
+	total.AgentName = usage.AgentName
+	total.InputTokens += usage.InputTokens
+	total.OutputTokens += usage.OutputTokens
+	total.TotalTokens += usage.InputTokens + usage.OutputTokens
+	total.Calls += usage.Calls
+	session.UpdatedAt = time.Now().UnixMilli()
+	return ss.markDirtyLocked(stockCode)
+}
+
+// maxMeetingRuns 每个会话保留的会议运行记录条数
+const maxMeetingRuns = 20
+
+// AddMeetingRun 追加一场会议的运行记录，只保留最近 maxMeetingRuns 场
+func (ss *SessionService) AddMeetingRun(stockCode string, run models.MeetingRun) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		// 尝试从文件加载
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return fmt.Errorf("session not found: %s", stockCode)
+		}
+		ss.sessions[stockCode] = session
+	}
+
+	session.MeetingRuns = append(session.MeetingRuns, run)
+	if len(session.MeetingRuns) > maxMeetingRuns {
+		session.MeetingRuns = session.MeetingRuns[len(session.MeetingRuns)-maxMeetingRuns:]
+	}
+	session.UpdatedAt = time.Now().UnixMilli()
+	return ss.markDirtyLocked(stockCode)
+}
+
+// GetMeetingRuns 获取会话的会议运行记录，最近的在前
+func (ss *SessionService) GetMeetingRuns(stockCode string) []models.MeetingRun {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return []models.MeetingRun{}
+		}
+		ss.sessions[stockCode] = session
+	}
+	runs := make([]models.MeetingRun, 0, len(session.MeetingRuns))
+	for i := len(session.MeetingRuns) - 1; i >= 0; i-- {
+		runs = append(runs, session.MeetingRuns[i])
+	}
+	return runs
+}
+
+// maxAttachments 每个会话保留的附件数，超出时删除最早的
+const maxAttachments = 20
+
+// AddAttachment 保存会话附件并立即写盘，ID 为空时自动生成，返回附件 ID
+func (ss *SessionService) AddAttachment(stockCode string, att models.SessionAttachment) (string, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		// 尝试从文件加载
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return "", fmt.Errorf("session not found: %s", stockCode)
+		}
+		ss.sessions[stockCode] = session
+	}
+
+	if att.ID == "" {
+		att.ID = uuid.New().String()
+	}
+	if att.CreatedAt == 0 {
+		att.CreatedAt = time.Now().UnixMilli()
+	}
+	session.Attachments = append(session.Attachments, att)
+	if len(session.Attachments) > maxAttachments {
+		session.Attachments = session.Attachments[len(session.Attachments)-maxAttachments:]
+	}
+	session.UpdatedAt = time.Now().UnixMilli()
+	return att.ID, ss.saveNowLocked(stockCode)
+}
+
+// GetAttachment 获取会话附件，不存在时返回 nil
+func (ss *SessionService) GetAttachment(stockCode, id string) *models.SessionAttachment {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return nil
+		}
+		ss.sessions[stockCode] = session
+	}
+	for i := range session.Attachments {
+		if session.Attachments[i].ID == id {
+			att := session.Attachments[i]
+			return &att
+		}
+	}
+	return nil
 }
 
 // GetPosition 获取持仓信息
+// 指数无持仓概念，始终返回 nil
 func (ss *SessionService) GetPosition(stockCode string) *models.StockPosition {
+	if models.IsIndexSymbol(stockCode) {
+		return nil
+	}
 	ss.mu.Lock()
 	defer ss.mu.Unlock()
 
@@ -251,3 +442,43 @@ func (ss *SessionService) GetPosition(stockCode string) *models.StockPosition {
 	}
 	return session.Position
 }
+
+// loadAllSessions 将磁盘上尚未缓存的 Session 载入内存，调用方需持有锁
+func (ss *SessionService) loadAllSessions() {
+	entries, err := os.ReadDir(ss.sessionsDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		code := strings.TrimSuffix(e.Name(), ".json")
+		if _, ok := ss.sessions[code]; ok {
+			continue
+		}
+		if session, err := ss.loadSession(code); err == nil {
+			ss.sessions[code] = session
+		}
+	}
+}
+
+// ListPositions 列出所有持仓（按股票代码排序），持锁时复制，调用方读取时不受会话更新影响
+func (ss *SessionService) ListPositions() []PositionRecord {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.loadAllSessions()
+
+	var result []PositionRecord
+	for code, session := range ss.sessions {
+		if session.Position == nil || session.Position.Shares <= 0 || models.IsIndexSymbol(code) {
+			continue
+		}
+		result = append(result, PositionRecord{
+			Code: session.StockCode, Name: session.StockName, Shares: session.Position.Shares, CostPrice: session.Position.CostPrice,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result
+}
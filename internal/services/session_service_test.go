@@ -0,0 +1,28 @@
+package services
+
+import "testing"
+
+func TestListPositions(t *testing.T) {
+	ss := NewSessionService(t.TempDir())
+	for _, code := range []string{"sz000001", "sh600519", "sh600036"} {
+		if _, err := ss.GetOrCreateSession(code, "名称"+code); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ss.UpdatePosition("sh600519", 100, 1500)
+	ss.UpdatePosition("sz000001", 200, 10.5)
+	ss.UpdatePosition("sh600036", 0, 30)
+
+	positions := ss.ListPositions()
+	if len(positions) != 2 || positions[0].Code != "sh600519" || positions[1].Code != "sz000001" ||
+		positions[1].Name != "名称sz000001" || positions[1].Shares != 200 || positions[1].CostPrice != 10.5 {
+		t.Fatalf("positions = %+v", positions)
+	}
+
+	// 返回的是副本，之后的更新不影响已取得的结果
+	ss.UpdatePosition("sh600519", 300, 1400)
+	ss.GetSession("sh600519").StockName = "改名"
+	if p := positions[0]; p.Shares != 100 || p.CostPrice != 1500 || p.Name != "名称sh600519" {
+		t.Errorf("position changed after update: %+v", p)
+	}
+}
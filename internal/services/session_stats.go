@@ -0,0 +1,95 @@
+package services
+
+// estimateCharsPerToken 缺少实际用量时按内容长度估算 token 的比例
+const estimateCharsPerToken = 4
+
+// SessionStats 会话统计
+type SessionStats struct {
+	StockCode          string                `json:"stockCode"`
+	TotalMessages      int                   `json:"totalMessages"`      // 全部消息数（含用户提问）
+	ByAgent            map[string]AgentStats `json:"byAgent"`            // 各发言者统计（不含用户），key: agentID
+	FirstMessageAt     int64                 `json:"firstMessageAt"`     // 首条消息时间（毫秒），无消息为 0
+	LastMessageAt      int64                 `json:"lastMessageAt"`      // 最后一条消息时间（毫秒），无消息为 0
+	Rounds             int                   `json:"rounds"`             // 讨论轮数，每次用户提问开启一轮
+	AverageRoundLength float64               `json:"averageRoundLength"` // 平均每轮的回复条数
+}
+
+// AgentStats 单个发言者在会话内的统计
+type AgentStats struct {
+	AgentName         string  `json:"agentName"`
+	MessageCount      int     `json:"messageCount"`
+	TotalTokens       int     `json:"totalTokens"`
+	TokensEstimated   bool    `json:"tokensEstimated"`   // 会话未记录该发言者的实际用量，按内容长度估算
+	ParticipationRate float64 `json:"participationRate"` // 参与发言的轮数占总轮数的比例
+}
+
+// ComputeSessionStats 统计会话的消息数、各发言者 token 与参与率
+// token 优先取会话记录的实际用量，没有记录的发言者按内容长度/4 估算；会话不存在时返回空统计
+func (ss *SessionService) ComputeSessionStats(stockCode string) SessionStats {
+	stats := SessionStats{StockCode: stockCode, ByAgent: map[string]AgentStats{}}
+
+	ss.mu.Lock()
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			ss.mu.Unlock()
+			return stats
+		}
+		ss.sessions[stockCode] = session
+	}
+	messages := session.Messages[:len(session.Messages):len(session.Messages)]
+	usage := make(map[string]int64, len(session.TokenUsage))
+	for id, u := range session.TokenUsage {
+		usage[id] = u.TotalTokens
+	}
+	ss.mu.Unlock()
+
+	stats.TotalMessages = len(messages)
+	var replies int
+	roundOf := make(map[string]map[int]bool) // 发言者参与的轮次
+	round := 0
+	for i, msg := range messages {
+		if i == 0 || msg.Timestamp < stats.FirstMessageAt {
+			stats.FirstMessageAt = msg.Timestamp
+		}
+		stats.LastMessageAt = max(stats.LastMessageAt, msg.Timestamp)
+
+		if msg.AgentID == "user" {
+			round++
+			continue
+		}
+		// 首次提问前的回复（如定时会议）单独算一轮
+		if round == 0 {
+			round = 1
+		}
+		replies++
+		a := stats.ByAgent[msg.AgentID]
+		a.AgentName = msg.AgentName
+		a.MessageCount++
+		a.TotalTokens += len(msg.Content) / estimateCharsPerToken
+		stats.ByAgent[msg.AgentID] = a
+		if roundOf[msg.AgentID] == nil {
+			roundOf[msg.AgentID] = make(map[int]bool)
+		}
+		roundOf[msg.AgentID][round] = true
+	}
+	stats.Rounds = round
+	if round > 0 {
+		stats.AverageRoundLength = float64(replies) / float64(round)
+	}
+
+	for id, a := range stats.ByAgent {
+		if total, ok := usage[id]; ok {
+			a.TotalTokens = int(total)
+		} else {
+			a.TokensEstimated = true
+		}
+		if round > 0 {
+			a.ParticipationRate = float64(len(roundOf[id])) / float64(round)
+		}
+		stats.ByAgent[id] = a
+	}
+	return stats
+}
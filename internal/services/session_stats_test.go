@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestComputeSessionStats(t *testing.T) {
+	ss := NewSessionService(t.TempDir())
+	session := &models.StockSession{
+		StockCode: "sh600519",
+		Messages: []models.ChatMessage{
+			{AgentID: "user", Content: "能买吗", Timestamp: 100},
+			{AgentID: "tech", AgentName: "K线王", Content: "MACD 金叉，短期看多", Timestamp: 110},
+			{AgentID: "fund", AgentName: "老陈", Content: "估值偏高", Timestamp: 120},
+			{AgentID: "user", Content: "止损放哪", Timestamp: 200},
+			{AgentID: "tech", AgentName: "K线王", Content: "1600 附近", Timestamp: 210},
+		},
+		TokenUsage: map[string]*models.TokenUsage{"tech": {AgentID: "tech", TotalTokens: 900}},
+	}
+	if err := ss.saveSession(session); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := ss.ComputeSessionStats("sh600519")
+	if stats.TotalMessages != 5 || stats.FirstMessageAt != 100 || stats.LastMessageAt != 210 || stats.Rounds != 2 || stats.AverageRoundLength != 1.5 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	tech, fund := stats.ByAgent["tech"], stats.ByAgent["fund"]
+	if tech.MessageCount != 2 || tech.TotalTokens != 900 || tech.TokensEstimated || tech.ParticipationRate != 1 {
+		t.Errorf("tech = %+v", tech)
+	}
+	// 未记录实际用量时按内容长度/4 估算
+	if fund.MessageCount != 1 || fund.TotalTokens != len("估值偏高")/4 || !fund.TokensEstimated || fund.ParticipationRate != 0.5 {
+		t.Errorf("fund = %+v", fund)
+	}
+	if _, ok := stats.ByAgent["user"]; ok {
+		t.Error("user should not be counted as agent")
+	}
+
+	empty := ss.ComputeSessionStats("sz000001")
+	if empty.TotalMessages != 0 || empty.ByAgent == nil || empty.Rounds != 0 {
+		t.Errorf("empty stats = %+v", empty)
+	}
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 股票引用解析的置信度
+const (
+	// StockAutoOpenConfidence 首个候选达到该置信度且没有同样可信的其他候选时可直接打开
+	StockAutoOpenConfidence = 0.85
+
+	maxStockCandidates = 10
+)
+
+// StockCandidate 股票引用解析出的候选
+type StockCandidate struct {
+	StockSearchResult
+	Confidence float64 `json:"confidence"` // 0~1
+	Source     string  `json:"source"`     // url/code/name
+}
+
+// StockReferenceResult 股票引用解析结果，候选按置信度降序
+type StockReferenceResult struct {
+	Input      string           `json:"input"`
+	Candidates []StockCandidate `json:"candidates"`
+	AutoOpen   bool             `json:"autoOpen"` // 首个候选足够可信，可直接打开
+	Message    string           `json:"message,omitempty"`
+}
+
+// stockRefHint 从输入中提取出的代码线索
+type stockRefHint struct {
+	Code   string // 6 位代码
+	Market string // sh/sz/bj，未注明时为空
+	Source string // url/code
+}
+
+// stockURLPattern 行情网站网址中的代码格式，market 与 code 为命名分组
+type stockURLPattern struct {
+	host     string // 域名后缀
+	patterns []*regexp.Regexp
+}
+
+// stockURLPatterns 常见行情网站，按路径与查询参数（小写）匹配
+var stockURLPatterns = []stockURLPattern{
+	// xueqiu.com/S/SH600519
+	{"xueqiu.com", []*regexp.Regexp{regexp.MustCompile(`/s/(?P<market>sh|sz|bj)(?P<code>\d{6})\b`)}},
+	// quote.eastmoney.com/sh600519.html、/concept/sz000001.html、/bj/430047.html、/unify/r/1.600519、guba list,600519.html
+	{"eastmoney.com", []*regexp.Regexp{
+		regexp.MustCompile(`(?P<market>sh|sz|bj)(?P<code>\d{6})\.html`),
+		regexp.MustCompile(`/(?P<market>sh|sz|bj)/(?P<code>\d{6})\.html`),
+		regexp.MustCompile(`(?:/r/|secid=)(?P<market>[01])\.(?P<code>\d{6})\b`),
+		regexp.MustCompile(`(?:list,|code=|/)(?P<code>\d{6})(?:\.html|&|$)`),
+	}},
+	// finance.sina.com.cn/realstock/company/sh600519/nc.shtml
+	{"sina.com.cn", []*regexp.Regexp{regexp.MustCompile(`(?P<market>sh|sz|bj)(?P<code>\d{6})\b`)}},
+	// stockpage.10jqka.com.cn/600519/
+	{"10jqka.com.cn", []*regexp.Regexp{regexp.MustCompile(`/(?P<code>\d{6})(?:/|$)`)}},
+	// gu.qq.com/sh600519
+	{"qq.com", []*regexp.Regexp{regexp.MustCompile(`(?P<market>sh|sz|bj)(?P<code>\d{6})\b`)}},
+	// finance.yahoo.com/quote/600519.SS
+	{"yahoo.com", []*regexp.Regexp{regexp.MustCompile(`/quote/(?P<code>\d{6})\.(?P<market>ss|sz|bj)\b`)}},
+	// www.cninfo.com.cn/new/disclosure/stock?stockCode=600519
+	{"cninfo.com.cn", []*regexp.Regexp{regexp.MustCompile(`stockcode=(?P<code>\d{6})\b`)}},
+}
+
+// genericStockURLPatterns 其他网站只识别带市场前缀的代码
+var genericStockURLPatterns = []*regexp.Regexp{regexp.MustCompile(`\b(?P<market>sh|sz|bj)(?P<code>\d{6})\b`)}
+
+var (
+	stockURLRe = regexp.MustCompile(`(?i)(?:https?://)?(?:[a-z0-9-]+\.)+[a-z]{2,}(?:/[^\s]*)?`)
+	// 600519、SH600519、sh.600519、600519.SH
+	stockCodeRe = regexp.MustCompile(`(?i)(?:\b(sh|sz|bj)\.?|\b)(\d{6})(?:\.(sh|sz|bj|ss))?\b`)
+	// 名称两侧的分隔符，如雪球的 $贵州茅台(SH600519)$
+	stockNameSeparators = regexp.MustCompile(`[\s$()（）\[\]【】<>《》:：,，、;；"'“”‘’|/]+`)
+)
+
+// parseStockReference 从网址、代码或名称文本中提取代码线索与名称关键词
+func parseStockReference(input string) ([]stockRefHint, string) {
+	var hints []stockRefHint
+	rest := input
+	for _, raw := range stockURLRe.FindAllString(input, -1) {
+		if !strings.Contains(raw, "/") && !strings.HasPrefix(strings.ToLower(raw), "http") {
+			continue // 没有路径的裸域名当作普通文字
+		}
+		rest = strings.Replace(rest, raw, " ", 1)
+		hints = append(hints, parseStockURL(raw)...)
+	}
+
+	for _, m := range stockCodeRe.FindAllStringSubmatch(rest, -1) {
+		market := strings.ToLower(m[1])
+		if market == "" {
+			market = normalizeStockMarket(m[3])
+		}
+		hints = append(hints, stockRefHint{Code: m[2], Market: market, Source: "code"})
+	}
+	rest = stockCodeRe.ReplaceAllString(rest, " ")
+
+	// 取第一个像名称的片段（至少两个字符）
+	for _, part := range stockNameSeparators.Split(rest, -1) {
+		if len([]rune(part)) >= 2 {
+			return dedupeStockHints(hints), part
+		}
+	}
+	return dedupeStockHints(hints), ""
+}
+
+// parseStockURL 按已知行情网站的格式提取代码
+func parseStockURL(raw string) []stockRefHint {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	target := strings.ToLower(u.EscapedPath())
+	if u.RawQuery != "" {
+		target += "?" + strings.ToLower(u.RawQuery)
+	}
+
+	patterns := genericStockURLPatterns
+	for _, p := range stockURLPatterns {
+		if host == p.host || strings.HasSuffix(host, "."+p.host) {
+			patterns = p.patterns
+			break
+		}
+	}
+	for _, re := range patterns {
+		m := re.FindStringSubmatch(target)
+		if m == nil {
+			continue
+		}
+		hint := stockRefHint{Source: "url"}
+		if i := re.SubexpIndex("market"); i >= 0 {
+			hint.Market = normalizeStockMarket(m[i])
+		}
+		hint.Code = m[re.SubexpIndex("code")]
+		return []stockRefHint{hint}
+	}
+	return nil
+}
+
+// normalizeStockMarket 统一市场写法：东方财富 secid 的 1/0、雅虎的 ss
+func normalizeStockMarket(market string) string {
+	switch strings.ToLower(market) {
+	case "sh", "ss", "1":
+		return "sh"
+	case "sz", "0":
+		return "sz"
+	case "bj":
+		return "bj"
+	}
+	return ""
+}
+
+// inferStockMarket 按代码段推断市场，用于股票列表中查不到的代码
+// 北交所为 43/83/87 及新代码段 92，沪市 B 股为 900
+func inferStockMarket(code string) string {
+	switch {
+	case strings.HasPrefix(code, "92"), code[0] == '4', code[0] == '8':
+		return "bj"
+	case code[0] == '5', code[0] == '6', code[0] == '9':
+		return "sh"
+	}
+	return "sz"
+}
+
+// dedupeStockHints 去掉重复线索，保留先出现的（网址优先于代码）；未注明市场的代码已出现过时跳过
+func dedupeStockHints(hints []stockRefHint) []stockRefHint {
+	seen := make(map[string]bool)
+	seenCode := make(map[string]bool)
+	out := hints[:0]
+	for _, h := range hints {
+		key := h.Market + h.Code
+		if seen[key] || (h.Market == "" && seenCode[h.Code]) {
+			continue
+		}
+		seen[key], seenCode[h.Code] = true, true
+		out = append(out, h)
+	}
+	return out
+}
+
+// ResolveStockReference 将粘贴的网址、代码或名称解析为按置信度排序的候选股票
+// 有多个同样可信的候选时不自动打开，由用户选择
+func (cs *ConfigService) ResolveStockReference(input string) StockReferenceResult {
+	input = strings.TrimSpace(input)
+	result := StockReferenceResult{Input: input, Candidates: []StockCandidate{}}
+	hints, name := parseStockReference(input)
+
+	index := make(map[string]int) // symbol -> 候选下标
+	add := func(c StockCandidate) {
+		if i, ok := index[c.Symbol]; ok {
+			result.Candidates[i].Confidence = max(result.Candidates[i].Confidence, c.Confidence)
+			return
+		}
+		index[c.Symbol] = len(result.Candidates)
+		result.Candidates = append(result.Candidates, c)
+	}
+
+	for _, h := range hints {
+		base := 0.9
+		if h.Source == "url" || h.Market != "" {
+			base = 0.95
+		}
+		matches := cs.lookupStockCode(h.Code, h.Market)
+		if len(matches) == 0 {
+			// 列表中没有的代码（指数、基金等）仅在注明市场时给出较高置信度
+			market, confidence := h.Market, 0.8
+			if market == "" {
+				market, confidence = inferStockMarket(h.Code), 0.5
+			}
+			add(StockCandidate{StockSearchResult: StockSearchResult{Symbol: market + h.Code}, Confidence: confidence, Source: h.Source})
+			continue
+		}
+		for _, m := range matches {
+			confidence := base
+			if len(matches) > 1 {
+				confidence = 0.6
+			}
+			add(StockCandidate{StockSearchResult: m, Confidence: confidence, Source: h.Source})
+		}
+	}
+
+	if name != "" {
+		// 名称与代码指向同一只股票时直接确认
+		confirmed := false
+		for i := range result.Candidates {
+			if c := &result.Candidates[i]; c.Name != "" && strings.Contains(strings.ToUpper(c.Name), strings.ToUpper(name)) {
+				c.Confidence, confirmed = 1, true
+			}
+		}
+		if !confirmed {
+			matches := cs.SearchStocks(name, maxStockCandidates)
+			for _, m := range matches {
+				confidence := 0.6
+				switch {
+				case strings.EqualFold(m.Name, name):
+					confidence = 0.95
+				case len(matches) == 1:
+					confidence = StockAutoOpenConfidence
+				case strings.HasPrefix(strings.ToUpper(m.Name), strings.ToUpper(name)):
+					confidence = 0.65
+				}
+				add(StockCandidate{StockSearchResult: m, Confidence: confidence, Source: "name"})
+			}
+		}
+	}
+
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		return result.Candidates[i].Confidence > result.Candidates[j].Confidence
+	})
+	if len(result.Candidates) > maxStockCandidates {
+		result.Candidates = result.Candidates[:maxStockCandidates]
+	}
+
+	switch {
+	case len(result.Candidates) == 0:
+		result.Message = "未识别到股票代码或名称"
+	case result.Candidates[0].Confidence < StockAutoOpenConfidence:
+		result.Message = "未能确定股票，请从候选中选择"
+	case len(result.Candidates) > 1 && result.Candidates[1].Confidence >= StockAutoOpenConfidence:
+		result.Message = "匹配到多只股票，请从候选中选择"
+	default:
+		result.AutoOpen = true
+	}
+	return result
+}
+
+// lookupStockCode 按 6 位代码精确查找股票，market 非空时要求市场一致
+func (cs *ConfigService) lookupStockCode(code, market string) []StockSearchResult {
+	var matches []StockSearchResult
+	for _, r := range cs.SearchStocks(code, maxStockCandidates) {
+		symbol := r.Symbol
+		if len(symbol) != 8 || symbol[2:] != code || (market != "" && symbol[:2] != market) {
+			continue
+		}
+		matches = append(matches, r)
+	}
+	return matches
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStockReference(t *testing.T) {
+	cases := []struct {
+		input string
+		hints []stockRefHint
+		name  string
+	}{
+		{"https://xueqiu.com/S/SH600519", []stockRefHint{{"600519", "sh", "url"}}, ""},
+		{"xueqiu.com/S/SZ000001?from=share", []stockRefHint{{"000001", "sz", "url"}}, ""},
+		{"https://quote.eastmoney.com/sh600519.html", []stockRefHint{{"600519", "sh", "url"}}, ""},
+		{"https://quote.eastmoney.com/concept/sz300750.html?from=classic", []stockRefHint{{"300750", "sz", "url"}}, ""},
+		{"https://quote.eastmoney.com/bj/430047.html", []stockRefHint{{"430047", "bj", "url"}}, ""},
+		{"https://quote.eastmoney.com/unify/r/0.000858", []stockRefHint{{"000858", "sz", "url"}}, ""},
+		{"https://guba.eastmoney.com/list,600036.html", []stockRefHint{{"600036", "", "url"}}, ""},
+		{"https://finance.sina.com.cn/realstock/company/sh601318/nc.shtml", []stockRefHint{{"601318", "sh", "url"}}, ""},
+		{"http://stockpage.10jqka.com.cn/002594/", []stockRefHint{{"002594", "", "url"}}, ""},
+		{"https://gu.qq.com/sz000333/gp", []stockRefHint{{"000333", "sz", "url"}}, ""},
+		{"https://finance.yahoo.com/quote/600519.SS/", []stockRefHint{{"600519", "sh", "url"}}, ""},
+		{"http://www.cninfo.com.cn/new/disclosure/stock?stockCode=000651&orgId=gssz0000651", []stockRefHint{{"000651", "", "url"}}, ""},
+		{"https://example.com/news/sh600519", []stockRefHint{{"600519", "sh", "url"}}, ""},
+		{"https://example.com/news/20251016/123456.html", nil, ""},
+		{"茅台 600519", []stockRefHint{{"600519", "", "code"}}, "茅台"},
+		{"$贵州茅台(SH600519)$", []stockRefHint{{"600519", "sh", "code"}}, "贵州茅台"},
+		{"000001.SZ", []stockRefHint{{"000001", "sz", "code"}}, ""},
+		{"sh.000300 沪深300", []stockRefHint{{"000300", "sh", "code"}}, "沪深300"},
+		{"看看 https://xueqiu.com/S/SH600519 和 600519", []stockRefHint{{"600519", "sh", "url"}}, "看看"},
+		{"600519 000858", []stockRefHint{{"600519", "", "code"}, {"000858", "", "code"}}, ""},
+		{"电话13800138000", nil, "电话13800138000"},
+		{"平安", nil, "平安"},
+		{"a", nil, ""},
+	}
+	for _, c := range cases {
+		hints, name := parseStockReference(c.input)
+		if len(hints) == 0 {
+			hints = nil
+		}
+		if !reflect.DeepEqual(hints, c.hints) || name != c.name {
+			t.Errorf("parseStockReference(%q) = %v, %q; want %v, %q", c.input, hints, name, c.hints, c.name)
+		}
+	}
+}
+
+func TestResolveStockReference(t *testing.T) {
+	cs := &ConfigService{}
+	cases := []struct {
+		input    string
+		top      string
+		autoOpen bool
+	}{
+		{"https://xueqiu.com/S/SH600519", "sh600519", true},
+		{"茅台 600519", "sh600519", true},
+		{"600519", "sh600519", true},
+		{"贵州茅台", "sh600519", true},
+		{"sh000300", "sh000300", false}, // 列表中没有的指数
+		{"430047", "bj430047", true},
+		{"920002", "bj920002", true},
+		{"万达轴承", "bj920002", true},
+		{"https://quote.eastmoney.com/bj/430047.html", "bj430047", true},
+		{"920999", "bj920999", false}, // 列表中没有的北交所新代码
+		{"900901", "sh900901", false},
+		{"平安", "", false}, // 多只股票名称含“平安”
+		{"600519 000858", "", false},
+		{"随便写点什么", "", false},
+	}
+	for _, c := range cases {
+		res := cs.ResolveStockReference(c.input)
+		if res.AutoOpen != c.autoOpen {
+			t.Errorf("%q: autoOpen = %v, candidates = %+v", c.input, res.AutoOpen, res.Candidates)
+		}
+		if c.top != "" && (len(res.Candidates) == 0 || res.Candidates[0].Symbol != c.top) {
+			t.Errorf("%q: candidates = %+v, want top %s", c.input, res.Candidates, c.top)
+		}
+		if !res.AutoOpen && res.Message == "" {
+			t.Errorf("%q: message should explain why not opened", c.input)
+		}
+	}
+
+	if res := cs.ResolveStockReference("920002"); res.Candidates[0].Market != "北京" {
+		t.Errorf("bj candidate = %+v", res.Candidates[0])
+	}
+
+	res := cs.ResolveStockReference("茅台 600519")
+	if res.Candidates[0].Name != "贵州茅台" || res.Candidates[0].Confidence != 1 {
+		t.Errorf("confirmed candidate = %+v", res.Candidates[0])
+	}
+	if res := cs.ResolveStockReference("平安"); len(res.Candidates) < 2 {
+		t.Errorf("ambiguous name candidates = %+v", res.Candidates)
+	}
+}
+
+func TestInferStockMarket(t *testing.T) {
+	for code, want := range map[string]string{
+		"600519": "sh", "510300": "sh", "900901": "sh",
+		"000001": "sz", "300750": "sz", "159915": "sz",
+		"430047": "bj", "830799": "bj", "871981": "bj", "920002": "bj",
+	} {
+		if got := inferStockMarket(code); got != want {
+			t.Errorf("inferStockMarket(%s) = %s, want %s", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// StrategySourceImported 从文件导入的策略来源
+const StrategySourceImported = "imported"
+
+// strategyExportVersion 策略导出文件格式版本
+const strategyExportVersion = 1
+
+// portableStrategy 可分享的策略文件内容，不含 ID、来源与创建时间等本机信息
+type portableStrategy struct {
+	Version     int                    `json:"version"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Color       string                 `json:"color"`
+	Agents      []models.StrategyAgent `json:"agents"`
+}
+
+// SetKnownTools 设置可用的内置工具名称，导入策略时据此校验专家工具
+func (s *StrategyService) SetKnownTools(names []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.knownTools = make(map[string]bool, len(names))
+	for _, name := range names {
+		s.knownTools[name] = true
+	}
+}
+
+// findStrategyLocked 按 ID 查找策略（调用方需持有锁）
+func (s *StrategyService) findStrategyLocked(id string) (models.Strategy, bool) {
+	for _, st := range s.store.Strategies {
+		if st.ID == id {
+			return st, true
+		}
+	}
+	return models.Strategy{}, false
+}
+
+// ExportStrategy 导出单个策略为 JSON
+func (s *StrategyService) ExportStrategy(id string) ([]byte, error) {
+	s.mu.RLock()
+	st, ok := s.findStrategyLocked(id)
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("策略不存在: %s", id)
+	}
+	return json.MarshalIndent(portableStrategy{
+		Version:     strategyExportVersion,
+		Name:        st.Name,
+		Description: st.Description,
+		Color:       st.Color,
+		Agents:      st.Agents,
+	}, "", "  ")
+}
+
+// ImportStrategy 从 JSON 导入策略，分配新 ID 并以 imported 来源保存
+func (s *StrategyService) ImportStrategy(data []byte) (models.Strategy, error) {
+	var p portableStrategy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return models.Strategy{}, fmt.Errorf("解析策略文件失败: %w", err)
+	}
+	if p.Version > strategyExportVersion {
+		return models.Strategy{}, fmt.Errorf("策略文件版本 %d 过新，请升级应用", p.Version)
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return models.Strategy{}, fmt.Errorf("策略名称不能为空")
+	}
+	if len(p.Agents) == 0 {
+		return models.Strategy{}, fmt.Errorf("策略未包含任何成员")
+	}
+	if unknown := s.unknownTools(p.Agents); len(unknown) > 0 {
+		return models.Strategy{}, fmt.Errorf("策略包含未知工具: %s", strings.Join(unknown, ", "))
+	}
+
+	st := models.Strategy{
+		Name:        p.Name,
+		Description: p.Description,
+		Color:       p.Color,
+		Agents:      slices.Clone(p.Agents),
+		Source:      StrategySourceImported,
+	}
+	renewStrategyIDs(&st, StrategySourceImported)
+	if err := s.AddStrategy(st); err != nil {
+		return models.Strategy{}, err
+	}
+	strategyLog.Info("导入策略: %s (%s)", st.Name, st.ID)
+	return st, nil
+}
+
+// DuplicateStrategy 复制策略，新策略名为"原名 副本"（重名时追加序号）
+func (s *StrategyService) DuplicateStrategy(id string) (models.Strategy, error) {
+	s.mu.RLock()
+	src, ok := s.findStrategyLocked(id)
+	names := make(map[string]bool, len(s.store.Strategies))
+	for _, st := range s.store.Strategies {
+		names[st.Name] = true
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return models.Strategy{}, fmt.Errorf("策略不存在: %s", id)
+	}
+
+	name := src.Name + " 副本"
+	for i := 2; names[name]; i++ {
+		name = fmt.Sprintf("%s 副本 %d", src.Name, i)
+	}
+	st := models.Strategy{
+		Name:        name,
+		Description: src.Description,
+		Color:       src.Color,
+		Agents:      slices.Clone(src.Agents),
+		Source:      "user",
+	}
+	for i := range st.Agents {
+		st.Agents[i].Tools = slices.Clone(st.Agents[i].Tools)
+		st.Agents[i].MCPServers = slices.Clone(st.Agents[i].MCPServers)
+	}
+	renewStrategyIDs(&st, "copy")
+	if err := s.AddStrategy(st); err != nil {
+		return models.Strategy{}, err
+	}
+	return st, nil
+}
+
+// unknownTools 返回专家引用的未注册内置工具（未设置工具列表时不校验）
+func (s *StrategyService) unknownTools(agents []models.StrategyAgent) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.knownTools) == 0 {
+		return nil
+	}
+	var unknown []string
+	for _, agent := range agents {
+		for _, name := range agent.Tools {
+			if !s.knownTools[name] && !slices.Contains(unknown, name) {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	return unknown
+}
+
+// renewStrategyIDs 为策略及其专家分配新 ID，避免与来源策略冲突
+func renewStrategyIDs(st *models.Strategy, prefix string) {
+	st.ID = uuid.New().String()
+	short := st.ID[:8]
+	for i := range st.Agents {
+		st.Agents[i].ID = fmt.Sprintf("%s-%s-%d", prefix, short, i+1)
+	}
+	st.CreatedAt = time.Now().Unix()
+}
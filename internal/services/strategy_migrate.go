@@ -0,0 +1,129 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 旧版（策略功能之前）专家配置迁移
+const (
+	legacyAgentsFile     = "agents.json" // 旧版平铺的专家配置文件，与 strategies.json 同目录
+	legacyMigratedSuffix = ".migrated"   // 迁移完成后旧文件改名追加的后缀
+	legacyStrategyID     = "legacy-agents"
+	legacyStrategyName   = "迁移自旧版本"
+)
+
+// parseLegacyAgents 解析旧版专家配置，兼容顶层数组与 {"agents": [...]} 两种格式
+func parseLegacyAgents(data []byte) ([]models.AgentConfig, error) {
+	var agents []models.AgentConfig
+	if err := json.Unmarshal(data, &agents); err == nil {
+		return agents, nil
+	}
+	var wrapped struct {
+		Agents []models.AgentConfig `json:"agents"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, err
+	}
+	return wrapped.Agents, nil
+}
+
+// migrateLegacyAgents 将旧版 agents.json 中的专家迁移为一个新策略，迁移后旧文件改名为 agents.json.migrated
+// 已存在同 ID 的迁移策略时视为已迁移，只改名不覆盖；fresh 表示本次启动才初始化策略配置，此时激活迁移策略
+// 调用方需持有写锁
+func (s *StrategyService) migrateLegacyAgents(fresh bool) {
+	legacyPath := filepath.Join(filepath.Dir(s.configPath), legacyAgentsFile)
+	data, err := os.ReadFile(legacyPath)
+	if err != nil {
+		return
+	}
+
+	for _, st := range s.store.Strategies {
+		if st.ID == legacyStrategyID {
+			strategyLog.Info("旧版专家配置已迁移过，跳过")
+			s.markLegacyMigrated(legacyPath)
+			return
+		}
+	}
+
+	legacy, err := parseLegacyAgents(data)
+	if err != nil {
+		// 保留原文件，便于用户修复后下次启动再迁移
+		strategyLog.Warn("解析旧版专家配置失败，跳过迁移: %v", err)
+		return
+	}
+
+	agents, skipped := convertLegacyAgents(legacy)
+	if len(agents) == 0 {
+		strategyLog.Info("旧版专家配置为空，无需迁移")
+		s.markLegacyMigrated(legacyPath)
+		return
+	}
+
+	s.store.Strategies = append(s.store.Strategies, models.Strategy{
+		ID:          legacyStrategyID,
+		Name:        legacyStrategyName,
+		Description: fmt.Sprintf("从旧版 %s 迁移的 %d 位专家", legacyAgentsFile, len(agents)),
+		Color:       "#64748B",
+		Agents:      agents,
+		Source:      "user",
+		CreatedAt:   time.Now().Unix(),
+	})
+	if fresh {
+		s.store.ActiveID = legacyStrategyID
+	}
+	// 先保存策略再改名，保存失败时旧文件保留，下次启动重试
+	if err := s.saveNoLock(); err != nil {
+		strategyLog.Error("保存迁移策略失败: %v", err)
+		return
+	}
+	s.markLegacyMigrated(legacyPath)
+	strategyLog.Info("已将旧版 %d 位专家迁移为策略「%s」，跳过 %d 条无效配置", len(agents), legacyStrategyName, skipped)
+}
+
+// convertLegacyAgents 转换旧版专家，保留 ID、指令与工具；跳过无名称的条目，ID 为空或重复时重新编号
+func convertLegacyAgents(legacy []models.AgentConfig) ([]models.StrategyAgent, int) {
+	agents := make([]models.StrategyAgent, 0, len(legacy))
+	seen := make(map[string]bool, len(legacy))
+	skipped := 0
+	for i, a := range legacy {
+		if strings.TrimSpace(a.Name) == "" {
+			skipped++
+			continue
+		}
+		id := strings.TrimSpace(a.ID)
+		if id == "" || seen[id] {
+			id = fmt.Sprintf("legacy-%d", i+1)
+		}
+		seen[id] = true
+		agents = append(agents, models.StrategyAgent{
+			ID:                id,
+			Name:              a.Name,
+			Role:              a.Role,
+			Avatar:            a.Avatar,
+			Color:             a.Color,
+			Instruction:       a.Instruction,
+			Tools:             a.Tools,
+			MCPServers:        a.MCPServers,
+			Enabled:           a.Enabled,
+			AIConfigID:        a.AIConfigID,
+			MaxTokensPerAgent: a.MaxTokensPerAgent,
+			OutputLanguage:    a.OutputLanguage,
+			Tone:              a.Tone,
+		})
+	}
+	return agents, skipped
+}
+
+// markLegacyMigrated 将旧版配置文件改名，避免重复迁移
+func (s *StrategyService) markLegacyMigrated(legacyPath string) {
+	if err := os.Rename(legacyPath, legacyPath+legacyMigratedSuffix); err != nil {
+		strategyLog.Warn("旧版专家配置改名失败: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// copyLegacyFixture 将 testdata 中的旧版专家配置复制到数据目录
+func copyLegacyFixture(t *testing.T, dir, fixture string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, legacyAgentsFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// legacyStrategy 返回迁移策略及同 ID 策略的数量
+func legacyStrategy(s *StrategyService) (*models.Strategy, int) {
+	var found *models.Strategy
+	count := 0
+	for _, st := range s.GetAllStrategies() {
+		if st.ID == legacyStrategyID {
+			count++
+			found = &st
+		}
+	}
+	return found, count
+}
+
+func TestMigrateLegacyAgents(t *testing.T) {
+	dir := t.TempDir()
+	copyLegacyFixture(t, dir, "legacy_agents.json")
+
+	s := NewStrategyService(dir)
+	st, _ := legacyStrategy(s)
+	if st == nil {
+		t.Fatal("legacy strategy not created")
+	}
+	if st.Name != legacyStrategyName || s.GetActiveStrategy().ID != legacyStrategyID {
+		t.Errorf("strategy = %s, active = %s", st.Name, s.GetActiveStrategy().ID)
+	}
+	// 保留 ID、指令、工具与 AI 配置；重复 ID 重新编号，无名称条目跳过
+	var ids []string
+	for _, a := range st.Agents {
+		ids = append(ids, a.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"fundamental", "my-trader", "legacy-3"}) {
+		t.Errorf("agent ids = %v", ids)
+	}
+	first := st.Agents[0]
+	if first.Instruction != "你是老陈，只看估值和业绩。" || !reflect.DeepEqual(first.Tools, []string{"get_stock_realtime", "get_research_report"}) || first.AIConfigID != "deepseek" || !first.Enabled {
+		t.Errorf("first agent = %+v", first)
+	}
+	if st.Agents[1].Enabled {
+		t.Error("disabled agent should stay disabled")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, legacyAgentsFile)); !os.IsNotExist(err) {
+		t.Error("legacy file should be renamed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, legacyAgentsFile+legacyMigratedSuffix)); err != nil {
+		t.Errorf("migrated file missing: %v", err)
+	}
+
+	// 重新加载不会重复迁移
+	reloaded := NewStrategyService(dir)
+	if _, count := legacyStrategy(reloaded); count != 1 {
+		t.Errorf("legacy strategy count = %d", count)
+	}
+}
+
+func TestMigrateLegacyAgentsKeepsExisting(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStrategyService(dir)
+	if s.GetActiveStrategy().ID != "default" {
+		t.Fatalf("active = %s", s.GetActiveStrategy().ID)
+	}
+
+	// 已有策略配置时迁移不切换当前策略，兼容 {"agents": [...]} 格式
+	copyLegacyFixture(t, dir, "legacy_agents_wrapped.json")
+	s = NewStrategyService(dir)
+	st, _ := legacyStrategy(s)
+	if st == nil || len(st.Agents) != 1 || st.Agents[0].ID != "macro" {
+		t.Fatalf("legacy strategy = %+v", st)
+	}
+	if s.GetActiveStrategy().ID != "default" {
+		t.Errorf("active = %s", s.GetActiveStrategy().ID)
+	}
+
+	// 旧文件再次出现（如改名失败）时不覆盖已迁移的同 ID 策略
+	copyLegacyFixture(t, dir, "legacy_agents.json")
+	s = NewStrategyService(dir)
+	if st, count := legacyStrategy(s); st == nil || len(st.Agents) != 1 || count != 1 {
+		t.Errorf("existing legacy strategy overwritten: %+v, count = %d", st, count)
+	}
+	if _, err := os.Stat(filepath.Join(dir, legacyAgentsFile)); !os.IsNotExist(err) {
+		t.Error("legacy file should be renamed")
+	}
+}
+
+func TestMigrateLegacyAgentsInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, legacyAgentsFile), []byte("{broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewStrategyService(dir)
+	if st, _ := legacyStrategy(s); st != nil {
+		t.Error("invalid legacy file should not be migrated")
+	}
+	// 解析失败时保留原文件
+	if _, err := os.Stat(filepath.Join(dir, legacyAgentsFile)); err != nil {
+		t.Errorf("legacy file should be kept: %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -43,7 +44,7 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Avatar:      "财",
 			Color:       "#10B981",
 			Instruction: "你是老陈，一位在券商研究所深耕15年的基本面研究员。你说话沉稳务实，喜欢用数据说话。\n\n【分析框架】\n1. 盈利能力：ROE、毛利率、净利率趋势\n2. 成长性：营收/利润增速，行业天花板\n3. 估值水平：PE/PB分位，与同行对比\n4. 财务健康：现金流、负债率、商誉风险\n\n【回复风格】简洁专业，150字以内。先给结论，再用核心数据支撑。",
-			Tools:       []string{"get_research_report", "get_report_content", "get_stock_realtime"},
+			Tools:       []string{"get_research_report", "get_report_content", "get_stock_realtime", "get_earnings_calendar", "get_financial_report", "get_stock_financial_report"},
 			Enabled:     true,
 		},
 		{
@@ -53,7 +54,7 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Avatar:      "K",
 			Color:       "#3B82F6",
 			Instruction: "你是K线王，混迹A股20年的技术派老炮。你相信'价格包含一切信息'。\n\n【分析框架】\n1. 趋势判断：均线系统、趋势线\n2. 形态识别：头肩顶底、双重顶底\n3. 量价关系：放量突破、缩量回调\n4. 技术指标：MACD、KDJ、RSI\n\n【回复风格】直接了当，150字以内。明确给出关键价位和操作建议。",
-			Tools:       []string{"get_kline_data", "get_stock_realtime", "get_orderbook"},
+			Tools:       []string{"get_kline_data", "get_technical_indicators", "get_stock_realtime", "get_orderbook"},
 			Enabled:     true,
 		},
 		{
@@ -62,8 +63,8 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Role:        "资金流向分析师",
 			Avatar:      "资",
 			Color:       "#F59E0B",
-			Instruction: "你是钱姐，私募圈出身的资金流向专家。你深谙'跟着主力走'的生存法则。\n\n【分析框架】\n1. 主力动向：大单净流入、主力持仓变化\n2. 北向资金：外资流向、重仓股变化\n3. 筹码分布：集中度、套牢盘、获利盘\n4. 盘口异动：大单托盘、压盘信号\n\n【回复风格】直白实在，150字以内。重点说清资金动向和主力意图。",
-			Tools:       []string{"get_orderbook", "get_stock_realtime", "get_kline_data"},
+			Instruction: "你是钱姐，私募圈出身的资金流向专家。你深谙'跟着主力走'的生存法则。\n\n【分析框架】\n1. 主力动向：大单净流入、主力持仓变化\n2. 北向资金：外资流向、重仓股变化\n3. 筹码分布：集中度、套牢盘、获利盘\n4. 盘口异动：大单托盘、压盘信号\n5. 龙虎榜：机构席位、知名游资的买卖动向\n\n【回复风格】直白实在，150字以内。重点说清资金动向和主力意图。",
+			Tools:       []string{"get_orderbook", "get_stock_realtime", "get_kline_data", "get_longhubang", "get_longhubang_detail", "get_sector_data", "get_limit_board"},
 			Enabled:     true,
 		},
 		{
@@ -73,7 +74,7 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Avatar:      "政",
 			Color:       "#8B5CF6",
 			Instruction: "你是政策通，前财经记者出身，现专注政策研究。擅长解读政策背后的投资机会。\n\n【分析框架】\n1. 宏观政策：货币政策、财政政策、产业政策\n2. 行业监管：准入门槛、合规要求、扶持方向\n3. 地方政策：区域规划、地方补贴\n4. 政策周期：出台节奏、执行力度\n\n【回复风格】有理有据，150字以内。点明政策要点和投资含义。",
-			Tools:       []string{"get_news", "get_research_report", "get_stock_realtime"},
+			Tools:       []string{"get_news", "get_research_report", "get_stock_realtime", "get_sector_data", "get_ipo_calendar"},
 			Enabled:     true,
 		},
 		{
@@ -83,7 +84,7 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Avatar:      "险",
 			Color:       "#EF4444",
 			Instruction: "你是风控李，曾在公募基金做过5年风控。养成了'先想风险再想收益'的习惯。\n\n【分析框架】\n1. 下行风险：最大回撤、支撑位破位风险\n2. 波动风险：振幅、beta值、流动性\n3. 事件风险：财报、解禁、政策不确定性\n4. 仓位建议：根据风险收益比给出建议\n\n【回复风格】冷静客观，150字以内。明确风险点和应对建议。",
-			Tools:       []string{"get_kline_data", "get_stock_realtime", "get_research_report", "get_news"},
+			Tools:       []string{"get_kline_data", "get_stock_realtime", "get_research_report", "get_news", "get_announcements", "get_announcement_content"},
 			Enabled:     true,
 		},
 		{
@@ -93,7 +94,7 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Avatar:      "舆",
 			Color:       "#F97316",
 			Instruction: "你是舆情师，专注全网热点追踪。监控微博、知乎、B站等平台热搜，擅长从社会热点中发现投资机会或风险。\n\n【分析框架】\n1. 热点识别：筛选与市场相关的话题\n2. 关联分析：热点对相关行业/个股的影响\n3. 情绪判断：通过讨论判断市场情绪\n4. 时效评估：热点的持续性和发酵可能\n\n【回复风格】信息量大但有重点，150字以内。先说热点，再分析影响。",
-			Tools:       []string{"get_hottrend", "get_news", "get_stock_realtime"},
+			Tools:       []string{"get_hottrend", "get_news", "get_news_sentiment", "get_stock_realtime"},
 			Enabled:     true,
 		},
 	}
@@ -103,7 +104,7 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 type StrategyService struct {
 	configPath string
 	store      models.StrategyStore
-	llm        model.LLM
+	knownTools map[string]bool // 已注册的内置工具，导入时校验
 	mu         sync.RWMutex
 }
 
@@ -125,17 +126,20 @@ func (s *StrategyService) load() {
 	if err != nil {
 		strategyLog.Info("策略配置不存在，初始化默认配置")
 		s.initDefault()
+		s.migrateLegacyAgents(true)
 		return
 	}
 
 	if err := json.Unmarshal(data, &s.store); err != nil {
 		strategyLog.Error("解析策略配置失败: %v", err)
 		s.initDefault()
+		s.migrateLegacyAgents(false)
 		return
 	}
 
 	// 确保内置策略存在
 	s.ensureBuiltinStrategies()
+	s.migrateLegacyAgents(false)
 	strategyLog.Info("加载策略配置成功，共 %d 个策略", len(s.store.Strategies))
 }
 
@@ -169,8 +173,34 @@ func (s *StrategyService) save() error {
 	return s.saveNoLock()
 }
 
-// saveNoLock 保存配置（不带锁）
+// StrategyConflictError 策略在调用方读取后已被其他操作修改
+type StrategyConflictError struct {
+	BaseRevision int64 // 调用方读取时的版本
+	Revision     int64 // 当前版本
+}
+
+func (e *StrategyConflictError) Error() string {
+	return fmt.Sprintf("策略已被修改（基于版本 %d，当前版本 %d），请刷新后重试", e.BaseRevision, e.Revision)
+}
+
+// checkRevisionLocked 校验调用方基于的版本是否仍是最新（调用方需持有锁）
+func (s *StrategyService) checkRevisionLocked(baseRevision int64) error {
+	if baseRevision != s.store.Revision {
+		return &StrategyConflictError{BaseRevision: baseRevision, Revision: s.store.Revision}
+	}
+	return nil
+}
+
+// GetRevision 获取当前策略存储版本
+func (s *StrategyService) GetRevision() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store.Revision
+}
+
+// saveNoLock 保存配置（不带锁），每次保存版本号加一
 func (s *StrategyService) saveNoLock() error {
+	s.store.Revision++
 	data, err := json.MarshalIndent(s.store, "", "  ")
 	if err != nil {
 		return err
@@ -235,8 +265,25 @@ func (s *StrategyService) SetActiveStrategy(id string) error {
 	return nil
 }
 
+// validateMeetingLimits 校验策略的会议轮数上限与专家超时，0 表示使用默认值
+func validateMeetingLimits(strategy models.Strategy) error {
+	if strategy.MaxRounds < 0 {
+		return fmt.Errorf("发言轮数上限不能为负数: %d", strategy.MaxRounds)
+	}
+	if strategy.AgentTimeoutSeconds < 0 {
+		return fmt.Errorf("专家超时不能为负数: %d", strategy.AgentTimeoutSeconds)
+	}
+	return nil
+}
+
 // AddStrategy 添加新策略
 func (s *StrategyService) AddStrategy(strategy models.Strategy) error {
+	if err := validateMeetingLimits(strategy); err != nil {
+		return err
+	}
+	if err := normalizeAgentOutputs(&strategy); err != nil {
+		return err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -256,10 +303,19 @@ func (s *StrategyService) AddStrategy(strategy models.Strategy) error {
 	return s.saveNoLock()
 }
 
-// UpdateStrategy 更新策略
-func (s *StrategyService) UpdateStrategy(strategy models.Strategy) error {
+// UpdateStrategy 更新策略，baseRevision 为调用方读取时的版本，已变化时返回 StrategyConflictError
+func (s *StrategyService) UpdateStrategy(strategy models.Strategy, baseRevision int64) error {
+	if err := validateMeetingLimits(strategy); err != nil {
+		return err
+	}
+	if err := normalizeAgentOutputs(&strategy); err != nil {
+		return err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkRevisionLocked(baseRevision); err != nil {
+		return err
+	}
 
 	for i, st := range s.store.Strategies {
 		if st.ID == strategy.ID {
@@ -268,6 +324,12 @@ func (s *StrategyService) UpdateStrategy(strategy models.Strategy) error {
 				strategy.IsBuiltin = true
 				strategy.Source = "builtin"
 			}
+			// 覆盖前保存历史版本，内容未变化时不产生新版本
+			if !reflect.DeepEqual(st, strategy) {
+				if err := s.saveVersionLocked(st); err != nil {
+					return fmt.Errorf("保存历史版本失败: %w", err)
+				}
+			}
 			s.store.Strategies[i] = strategy
 			return s.saveNoLock()
 		}
@@ -275,10 +337,13 @@ func (s *StrategyService) UpdateStrategy(strategy models.Strategy) error {
 	return fmt.Errorf("策略不存在: %s", strategy.ID)
 }
 
-// DeleteStrategy 删除策略
-func (s *StrategyService) DeleteStrategy(id string) error {
+// DeleteStrategy 删除策略，baseRevision 含义同 UpdateStrategy
+func (s *StrategyService) DeleteStrategy(id string, baseRevision int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkRevisionLocked(baseRevision); err != nil {
+		return err
+	}
 
 	for i, st := range s.store.Strategies {
 		if st.ID == id {
@@ -298,6 +363,9 @@ func (s *StrategyService) DeleteStrategy(id string) error {
 
 // AddAgentToActiveStrategy 向当前激活策略添加专家
 func (s *StrategyService) AddAgentToActiveStrategy(agent models.StrategyAgent) error {
+	if err := normalizeAgentOutput(&agent); err != nil {
+		return err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -316,10 +384,16 @@ func (s *StrategyService) AddAgentToActiveStrategy(agent models.StrategyAgent) e
 	return fmt.Errorf("当前策略不存在")
 }
 
-// UpdateAgentInActiveStrategy 更新当前激活策略中的专家
-func (s *StrategyService) UpdateAgentInActiveStrategy(agent models.StrategyAgent) error {
+// UpdateAgentInActiveStrategy 更新当前激活策略中的专家，baseRevision 含义同 UpdateStrategy
+func (s *StrategyService) UpdateAgentInActiveStrategy(agent models.StrategyAgent, baseRevision int64) error {
+	if err := normalizeAgentOutput(&agent); err != nil {
+		return err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkRevisionLocked(baseRevision); err != nil {
+		return err
+	}
 
 	for i, st := range s.store.Strategies {
 		if st.ID == s.store.ActiveID {
@@ -335,10 +409,13 @@ func (s *StrategyService) UpdateAgentInActiveStrategy(agent models.StrategyAgent
 	return fmt.Errorf("当前策略不存在")
 }
 
-// DeleteAgentFromActiveStrategy 从当前激活策略删除专家
-func (s *StrategyService) DeleteAgentFromActiveStrategy(agentID string) error {
+// DeleteAgentFromActiveStrategy 从当前激活策略删除专家，baseRevision 含义同 UpdateStrategy
+func (s *StrategyService) DeleteAgentFromActiveStrategy(agentID string, baseRevision int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkRevisionLocked(baseRevision); err != nil {
+		return err
+	}
 
 	for i, st := range s.store.Strategies {
 		if st.ID == s.store.ActiveID {
@@ -357,11 +434,6 @@ func (s *StrategyService) DeleteAgentFromActiveStrategy(agentID string) error {
 	return fmt.Errorf("当前策略不存在")
 }
 
-// SetLLM 设置LLM用于AI生成策略
-func (s *StrategyService) SetLLM(llm model.LLM) {
-	s.llm = llm
-}
-
 // GenerateResult AI生成结果
 type GenerateResult struct {
 	Strategy  models.Strategy `json:"strategy"`
@@ -388,30 +460,60 @@ type MCPInfoForGen struct {
 	Tools []string `json:"tools"` // 该服务器提供的工具列表
 }
 
-// Generate 根据用户描述生成策略
-func (s *StrategyService) Generate(ctx context.Context, input GenerateInput) (*GenerateResult, error) {
-	if s.llm == nil {
+// maxGenerateAttempts 策略生成最大尝试次数（首次 + 修复重试）
+const maxGenerateAttempts = 2
+
+// Generate 使用 llm 根据用户描述生成策略
+// 调用失败时重试；输出无法解析时携带原始输出让模型修复后重试
+func (s *StrategyService) Generate(ctx context.Context, llm model.LLM, input GenerateInput) (*GenerateResult, error) {
+	if llm == nil {
 		return nil, fmt.Errorf("LLM未配置")
 	}
 	strategyLog.Info("开始生成策略, prompt=%s", input.Prompt)
 
 	// 构建AI提示词
 	aiPrompt := s.buildGeneratePrompt(input)
+	prompt := aiPrompt
 
-	// 调用LLM
-	response, err := s.callLLM(ctx, aiPrompt)
-	if err != nil {
-		return nil, fmt.Errorf("调用LLM失败: %w", err)
-	}
+	var lastErr error
+	for attempt := 1; attempt <= maxGenerateAttempts; attempt++ {
+		// 调用LLM
+		response, err := s.callLLM(ctx, llm, prompt)
+		if err != nil {
+			lastErr = fmt.Errorf("调用LLM失败: %w", err)
+			strategyLog.Warn("生成策略第 %d 次调用失败: %v", attempt, err)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
 
-	// 解析结果
-	result, err := s.parseGenerateResponse(response, input.Prompt)
-	if err != nil {
-		return nil, fmt.Errorf("解析结果失败: %w", err)
+		// 解析结果
+		result, err := s.parseGenerateResponse(response, input.Prompt)
+		if err == nil {
+			strategyLog.Info("策略生成完成: %s", result.Strategy.Name)
+			return result, nil
+		}
+		lastErr = fmt.Errorf("解析结果失败: %w", err)
+		strategyLog.Warn("生成策略第 %d 次解析失败: %v", attempt, err)
+		prompt = s.buildRepairPrompt(aiPrompt, response, err)
 	}
+	return nil, lastErr
+}
 
-	strategyLog.Info("策略生成完成: %s", result.Strategy.Name)
-	return result, nil
+// buildRepairPrompt 构建修复提示词（附带上次无法解析的输出）
+func (s *StrategyService) buildRepairPrompt(aiPrompt, lastResponse string, parseErr error) string {
+	runes := []rune(lastResponse)
+	if len(runes) > 4000 {
+		lastResponse = string(runes[:4000]) + "..."
+	}
+	var sb strings.Builder
+	sb.WriteString(aiPrompt)
+	sb.WriteString("\n\n## 修复要求\n")
+	fmt.Fprintf(&sb, "你上一次的输出无法解析（%v）。请严格按照输出格式重新输出纯JSON，不要附加任何解释。\n", parseErr)
+	sb.WriteString("上一次的输出：\n")
+	sb.WriteString(lastResponse)
+	return sb.String()
 }
 
 // buildGeneratePrompt 构建AI提示词
@@ -454,7 +556,8 @@ func (s *StrategyService) buildGeneratePrompt(input GenerateInput) string {
 	sb.WriteString("\n\n## 任务\n")
 	sb.WriteString("根据用户需求，设计一个投资策略，包含4-6个团队成员。\n")
 	sb.WriteString("每个成员需要有独特的分析视角和专业的系统指令。\n")
-	sb.WriteString("重要：必须为每个成员分配合适的工具，确保tools字段包含该成员需要使用的具体工具名称。\n\n")
+	sb.WriteString("重要：必须为每个成员分配合适的工具，确保tools字段包含该成员需要使用的具体工具名称。\n")
+	sb.WriteString("outputLanguage 为成员的回复语言（zh、zh-TW、en、ja 之一），用户需求未指定语言时填 zh；tone 为一句话的语气风格。\n\n")
 
 	sb.WriteString("## 输出格式（纯JSON）\n")
 	sb.WriteString("```json\n")
@@ -480,7 +583,9 @@ func (s *StrategyService) getOutputTemplate() string {
         "color": "#颜色代码",
         "instruction": "# 角色定位\n你是...\n\n## 核心职责\n- 职责1\n- 职责2\n\n## 分析框架\n### 1. 分析维度一\n- 要点\n\n### 2. 分析维度二\n- 要点\n\n## 工具使用\n- 使用 get-stock-info 获取股票基本信息\n- 使用 get-kline-data 获取K线数据进行技术分析\n\n## 输出要求\n1. 要求一\n2. 要求二",
         "tools": ["get-stock-info", "get-kline-data"],
-        "mcpServers": ["MCP服务器ID（可选）"]
+        "mcpServers": ["MCP服务器ID（可选）"],
+        "outputLanguage": "zh",
+        "tone": "沉稳专业，先结论后论据"
       }
     ]
   },
@@ -489,7 +594,7 @@ func (s *StrategyService) getOutputTemplate() string {
 }
 
 // callLLM 调用LLM生成内容
-func (s *StrategyService) callLLM(ctx context.Context, prompt string) (string, error) {
+func (s *StrategyService) callLLM(ctx context.Context, llm model.LLM, prompt string) (string, error) {
 	req := &model.LLMRequest{
 		Contents: []*genai.Content{
 			{
@@ -500,7 +605,7 @@ func (s *StrategyService) callLLM(ctx context.Context, prompt string) (string, e
 	}
 
 	var result string
-	for resp, err := range s.llm.GenerateContent(ctx, req, false) {
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
 		if err != nil {
 			return "", err
 		}
@@ -529,6 +634,9 @@ func (s *StrategyService) parseGenerateResponse(response, userPrompt string) (*G
 	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
 		return nil, fmt.Errorf("JSON解析失败: %w", err)
 	}
+	if len(result.Strategy.Agents) == 0 {
+		return nil, fmt.Errorf("策略未包含任何成员")
+	}
 
 	// 生成策略ID
 	strategyID := uuid.New().String()[:8]
@@ -539,8 +647,16 @@ func (s *StrategyService) parseGenerateResponse(response, userPrompt string) (*G
 
 	// 为每个agent生成唯一ID并设置默认启用
 	for i := range result.Strategy.Agents {
-		result.Strategy.Agents[i].ID = fmt.Sprintf("ai-%s-%d", strategyID, i+1)
-		result.Strategy.Agents[i].Enabled = true
+		agent := &result.Strategy.Agents[i]
+		agent.ID = fmt.Sprintf("ai-%s-%d", strategyID, i+1)
+		agent.Enabled = true
+		// 模型给出无法识别的语言时不限定，避免整个策略保存失败
+		if lang, ok := models.NormalizeOutputLanguage(agent.OutputLanguage); ok {
+			agent.OutputLanguage = lang
+		} else {
+			strategyLog.Warn("生成的专家「%s」输出语言无法识别，已忽略: %s", agent.Name, agent.OutputLanguage)
+			agent.OutputLanguage = ""
+		}
 	}
 
 	return &result, nil
@@ -590,6 +706,10 @@ func (s *StrategyService) getAgentConfigsFromStrategy() []models.AgentConfig {
 			MCPServers:  sa.MCPServers,
 			Enabled:     sa.Enabled,
 			AIConfigID:  sa.AIConfigID,
+
+			MaxTokensPerAgent: sa.MaxTokensPerAgent,
+			OutputLanguage:    sa.OutputLanguage,
+			Tone:              sa.Tone,
 		}
 	}
 	return agents
@@ -652,9 +772,9 @@ type EnhancePromptResult struct {
 	EnhancedPrompt string `json:"enhancedPrompt"` // 增强后的提示词
 }
 
-// EnhancePrompt 增强Agent提示词
-func (s *StrategyService) EnhancePrompt(ctx context.Context, input EnhancePromptInput) (*EnhancePromptResult, error) {
-	if s.llm == nil {
+// EnhancePrompt 使用 llm 增强Agent提示词
+func (s *StrategyService) EnhancePrompt(ctx context.Context, llm model.LLM, input EnhancePromptInput) (*EnhancePromptResult, error) {
+	if llm == nil {
 		return nil, fmt.Errorf("LLM未配置")
 	}
 	strategyLog.Info("开始增强提示词, agent=%s, role=%s", input.AgentName, input.AgentRole)
@@ -663,7 +783,7 @@ func (s *StrategyService) EnhancePrompt(ctx context.Context, input EnhancePrompt
 	aiPrompt := s.buildEnhancePrompt(input)
 
 	// 调用LLM
-	response, err := s.callLLM(ctx, aiPrompt)
+	response, err := s.callLLM(ctx, llm, aiPrompt)
 	if err != nil {
 		return nil, fmt.Errorf("调用LLM失败: %w", err)
 	}
@@ -0,0 +1,525 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	goopenai "github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+
+	"github.com/run-bigpig/jcp/internal/adk/openai"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// stubReply 桩服务器的一次应答
+type stubReply struct {
+	status  int
+	content string
+}
+
+// stubOpenAIServer 模拟 chat-completions 协议的桩服务器，按顺序返回预设应答
+type stubOpenAIServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	replies []stubReply
+	prompts []string
+}
+
+func newStubOpenAIServer(t *testing.T, replies ...stubReply) *stubOpenAIServer {
+	t.Helper()
+	stub := &stubOpenAIServer{replies: replies}
+	stub.Server = httptest.NewServer(http.HandlerFunc(stub.handle))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (s *stubOpenAIServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/chat/completions" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req goopenai.ChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	var prompt strings.Builder
+	for _, m := range req.Messages {
+		prompt.WriteString(m.Content)
+	}
+	s.prompts = append(s.prompts, prompt.String())
+	reply := stubReply{status: http.StatusInternalServerError, content: "no more replies"}
+	if len(s.replies) > 0 {
+		reply = s.replies[0]
+		s.replies = s.replies[1:]
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if reply.status != http.StatusOK {
+		w.WriteHeader(reply.status)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error": map[string]any{"message": reply.content, "type": "server_error"},
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(goopenai.ChatCompletionResponse{
+		ID:     "chatcmpl-stub",
+		Object: "chat.completion",
+		Model:  req.Model,
+		Choices: []goopenai.ChatCompletionChoice{{
+			Index:        0,
+			Message:      goopenai.ChatCompletionMessage{Role: "assistant", Content: reply.content},
+			FinishReason: goopenai.FinishReasonStop,
+		}},
+		Usage: goopenai.Usage{PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30},
+	})
+}
+
+func (s *stubOpenAIServer) requestPrompts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.prompts...)
+}
+
+// newStubStrategyService 创建策略服务及指向桩服务器的 LLM（真实 OpenAIModel 实例）
+func newStubStrategyService(t *testing.T, stub *stubOpenAIServer) (*StrategyService, model.LLM, string) {
+	t.Helper()
+	dir := t.TempDir()
+	svc := NewStrategyService(dir)
+	cfg := goopenai.DefaultConfig("test-key")
+	cfg.BaseURL = stub.URL + "/v1"
+	return svc, openai.NewOpenAIModel("stub-model", cfg, false), dir
+}
+
+const cannedStrategy = "好的，以下是策略：\n```json\n" + `{
+  "strategy": {
+    "name": "短线游资",
+    "description": "追踪游资动向的短线策略",
+    "color": "#EF4444",
+    "agents": [
+      {"id": "agent-1", "name": "打板王", "role": "短线交易员", "avatar": "板", "color": "#EF4444",
+       "instruction": "你是打板王", "tools": ["get_stock_realtime"], "enabled": false},
+      {"id": "agent-1", "name": "龙虎哥", "role": "龙虎榜分析师", "avatar": "龙", "color": "#F59E0B",
+       "instruction": "你是龙虎哥", "tools": ["get_longhubang"], "mcpServers": []}
+    ]
+  },
+  "reasoning": "聚焦短线资金"
+}` + "\n```"
+
+func TestStrategyGenerate_EndToEnd(t *testing.T) {
+	stub := newStubOpenAIServer(t, stubReply{status: http.StatusOK, content: cannedStrategy})
+	svc, llm, dir := newStubStrategyService(t, stub)
+
+	userPrompt := "帮我设计一个短线游资策略"
+	result, err := svc.Generate(context.Background(), llm, GenerateInput{
+		Prompt: userPrompt,
+		Tools:  []ToolInfoForGen{{Name: "get_stock_realtime", Description: "实时行情"}},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	prompts := stub.requestPrompts()
+	if len(prompts) != 1 {
+		t.Fatalf("stub received %d requests, want 1", len(prompts))
+	}
+	if !strings.Contains(prompts[0], userPrompt) || !strings.Contains(prompts[0], "get_stock_realtime") {
+		t.Errorf("prompt missing user input or tool list: %q", prompts[0])
+	}
+
+	st := result.Strategy
+	if !strings.HasPrefix(st.ID, "ai-") {
+		t.Errorf("strategy ID = %q, want ai- prefix", st.ID)
+	}
+	if st.Source != "ai" || st.SourceMeta != userPrompt {
+		t.Errorf("source = %q/%q, want ai/%q", st.Source, st.SourceMeta, userPrompt)
+	}
+	if st.CreatedAt == 0 {
+		t.Error("CreatedAt not stamped")
+	}
+	if result.Reasoning != "聚焦短线资金" {
+		t.Errorf("reasoning = %q", result.Reasoning)
+	}
+	if len(st.Agents) != 2 {
+		t.Fatalf("got %d agents, want 2", len(st.Agents))
+	}
+	for i, a := range st.Agents {
+		if want := fmt.Sprintf("%s-%d", st.ID, i+1); a.ID != want {
+			t.Errorf("agent[%d].ID = %q, want %q", i, a.ID, want)
+		}
+		if !a.Enabled {
+			t.Errorf("agent[%d] not enabled", i)
+		}
+	}
+
+	// 持久化并重新加载
+	if err := svc.AddStrategy(st); err != nil {
+		t.Fatalf("AddStrategy() error: %v", err)
+	}
+	if err := svc.AddStrategy(st); err == nil {
+		t.Error("AddStrategy() with duplicate ID should fail")
+	}
+
+	reloaded := NewStrategyService(dir)
+	var found *models.Strategy
+	var hasBuiltin bool
+	for _, s := range reloaded.GetAllStrategies() {
+		if s.ID == st.ID {
+			s := s
+			found = &s
+		}
+		if s.ID == "default" && s.IsBuiltin {
+			hasBuiltin = true
+		}
+	}
+	if found == nil {
+		t.Fatalf("generated strategy %s not persisted", st.ID)
+	}
+	if len(found.Agents) != 2 || found.Agents[1].Name != "龙虎哥" {
+		t.Errorf("persisted agents mismatch: %+v", found.Agents)
+	}
+	if !hasBuiltin {
+		t.Error("builtin strategy missing after reload")
+	}
+}
+
+func TestStrategyGenerate_ReloadRestoresBuiltin(t *testing.T) {
+	stub := newStubOpenAIServer(t, stubReply{status: http.StatusOK, content: cannedStrategy})
+	svc, llm, dir := newStubStrategyService(t, stub)
+
+	result, err := svc.Generate(context.Background(), llm, GenerateInput{Prompt: "短线"})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	// 模拟外部改写后只剩 AI 策略的配置文件
+	store := models.StrategyStore{ActiveID: result.Strategy.ID, Strategies: []models.Strategy{result.Strategy}}
+	data, err := json.Marshal(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "strategies.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewStrategyService(dir)
+	all := reloaded.GetAllStrategies()
+	if len(all) != 2 {
+		t.Fatalf("got %d strategies after reload, want 2", len(all))
+	}
+	if all[0].ID != result.Strategy.ID || all[1].ID != "default" {
+		t.Errorf("unexpected order: %s, %s", all[0].ID, all[1].ID)
+	}
+	if reloaded.GetActiveID() != result.Strategy.ID {
+		t.Errorf("active ID = %q, want %q", reloaded.GetActiveID(), result.Strategy.ID)
+	}
+}
+
+func TestStrategyGenerate_RepairMalformedJSON(t *testing.T) {
+	stub := newStubOpenAIServer(t,
+		stubReply{status: http.StatusOK, content: `{"strategy": {"name": "坏JSON", "agents": [`},
+		stubReply{status: http.StatusOK, content: cannedStrategy},
+	)
+	svc, llm, _ := newStubStrategyService(t, stub)
+
+	result, err := svc.Generate(context.Background(), llm, GenerateInput{Prompt: "短线"})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if result.Strategy.Name != "短线游资" {
+		t.Errorf("strategy name = %q", result.Strategy.Name)
+	}
+
+	prompts := stub.requestPrompts()
+	if len(prompts) != 2 {
+		t.Fatalf("stub received %d requests, want 2", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "修复要求") || !strings.Contains(prompts[1], "坏JSON") {
+		t.Errorf("repair prompt should carry the malformed output, got %q", prompts[1])
+	}
+}
+
+func TestStrategyGenerate_RetryOnServerError(t *testing.T) {
+	stub := newStubOpenAIServer(t,
+		stubReply{status: http.StatusInternalServerError, content: "upstream failure"},
+		stubReply{status: http.StatusOK, content: cannedStrategy},
+	)
+	svc, llm, _ := newStubStrategyService(t, stub)
+
+	result, err := svc.Generate(context.Background(), llm, GenerateInput{Prompt: "短线"})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if len(result.Strategy.Agents) != 2 {
+		t.Errorf("got %d agents, want 2", len(result.Strategy.Agents))
+	}
+	if n := len(stub.requestPrompts()); n != 2 {
+		t.Errorf("stub received %d requests, want 2", n)
+	}
+}
+
+func TestStrategyGenerate_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []stubReply
+		wantErr string
+	}{
+		{
+			name: "持续返回非法JSON",
+			replies: []stubReply{
+				{status: http.StatusOK, content: "抱歉，我无法完成"},
+				{status: http.StatusOK, content: `{"strategy": `},
+			},
+			wantErr: "解析结果失败",
+		},
+		{
+			name: "持续返回500",
+			replies: []stubReply{
+				{status: http.StatusInternalServerError, content: "boom"},
+				{status: http.StatusInternalServerError, content: "boom"},
+			},
+			wantErr: "调用LLM失败",
+		},
+		{
+			name: "策略没有成员",
+			replies: []stubReply{
+				{status: http.StatusOK, content: `{"strategy": {"name": "空"}}`},
+				{status: http.StatusOK, content: `{"strategy": {"name": "空", "agents": []}}`},
+			},
+			wantErr: "策略未包含任何成员",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newStubOpenAIServer(t, tt.replies...)
+			svc, llm, _ := newStubStrategyService(t, stub)
+
+			_, err := svc.Generate(context.Background(), llm, GenerateInput{Prompt: "短线"})
+			if err == nil {
+				t.Fatal("Generate() should fail")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want contains %q", err, tt.wantErr)
+			}
+			if n := len(stub.requestPrompts()); n != maxGenerateAttempts {
+				t.Errorf("stub received %d requests, want %d", n, maxGenerateAttempts)
+			}
+			if len(svc.GetAllStrategies()) != 1 {
+				t.Error("failed generation must not add strategies")
+			}
+		})
+	}
+}
+
+func TestStrategyExportImportDuplicate(t *testing.T) {
+	svc := NewStrategyService(t.TempDir())
+	svc.SetKnownTools([]string{"get_stock_realtime", "get_kline_data"})
+	src := models.Strategy{
+		ID:          "user-1",
+		Name:        "短线打板",
+		Description: "关注涨停与资金",
+		Color:       "#EF4444",
+		Source:      "user",
+		SourceMeta:  "打板策略",
+		Agents: []models.StrategyAgent{
+			{ID: "a1", Name: "资金", Tools: []string{"get_stock_realtime"}, Enabled: true},
+			{ID: "a2", Name: "技术", Tools: []string{"get_kline_data"}, Enabled: true},
+		},
+	}
+	if err := svc.AddStrategy(src); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := svc.ExportStrategy("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"id": "user-1"`, "isBuiltin", "source", "createdAt"} {
+		if strings.Contains(string(data), key) {
+			t.Errorf("导出内容不应包含 %s:\n%s", key, data)
+		}
+	}
+
+	imported, err := svc.ImportStrategy(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ID == src.ID || imported.Source != StrategySourceImported || imported.IsBuiltin || imported.CreatedAt == 0 {
+		t.Errorf("imported = %+v", imported)
+	}
+	if imported.Name != src.Name || len(imported.Agents) != 2 || imported.Agents[0].ID == "a1" || imported.Agents[1].Tools[0] != "get_kline_data" {
+		t.Errorf("imported agents = %+v", imported.Agents)
+	}
+
+	// 未注册的工具拒绝导入
+	bad := strings.Replace(string(data), "get_kline_data", "get_crystal_ball", 1)
+	if _, err := svc.ImportStrategy([]byte(bad)); err == nil || !strings.Contains(err.Error(), "get_crystal_ball") {
+		t.Errorf("unknown tool err = %v", err)
+	}
+	if _, err := svc.ImportStrategy([]byte(`{"name":"空","agents":[]}`)); err == nil {
+		t.Error("无成员的策略应拒绝导入")
+	}
+
+	// 复制两次，名称依次追加序号，专家配置互不共享
+	first, err := svc.DuplicateStrategy("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.DuplicateStrategy("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Name != "短线打板 副本" || second.Name != "短线打板 副本 2" || first.ID == second.ID {
+		t.Errorf("duplicate names = %q, %q", first.Name, second.Name)
+	}
+	first.Agents[0].Tools[0] = "changed"
+	if got, _ := svc.ExportStrategy("user-1"); strings.Contains(string(got), "changed") {
+		t.Error("副本与原策略共享了工具列表")
+	}
+	if n := len(svc.GetAllStrategies()); n != 5 {
+		t.Errorf("strategies = %d, want 5", n)
+	}
+}
+
+func TestStrategyRevisionConflict(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewStrategyService(dir)
+	if err := svc.AddStrategy(models.Strategy{ID: "user-1", Name: "短线", Agents: []models.StrategyAgent{{ID: "a1", Name: "资金"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 两个编辑方基于同一版本，后写入者收到冲突
+	base := svc.GetRevision()
+	st := svc.GetAllStrategies()[len(svc.GetAllStrategies())-1]
+	st.Description = "界面编辑"
+	if err := svc.UpdateStrategy(st, base); err != nil {
+		t.Fatal(err)
+	}
+	var conflict *StrategyConflictError
+	if err := svc.DeleteStrategy("user-1", base); !errors.As(err, &conflict) || conflict.Revision != base+1 {
+		t.Fatalf("err = %v, want StrategyConflictError", err)
+	}
+
+	// 版本随存储持久化，重新加载后仍可基于最新版本写入
+	reloaded := NewStrategyService(dir)
+	if reloaded.GetRevision() != base+1 {
+		t.Fatalf("revision = %d, want %d", reloaded.GetRevision(), base+1)
+	}
+	if err := reloaded.DeleteStrategy("user-1", base+1); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.UpdateAgentInActiveStrategy(models.StrategyAgent{ID: "x"}, base); !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want StrategyConflictError", err)
+	}
+}
+
+func TestStrategyVersionHistory(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewStrategyService(dir)
+	if err := svc.AddStrategy(models.Strategy{ID: "user-1", Name: "短线", Description: "v0"}); err != nil {
+		t.Fatal(err)
+	}
+	current := func() models.Strategy {
+		for _, st := range svc.GetAllStrategies() {
+			if st.ID == "user-1" {
+				return st
+			}
+		}
+		t.Fatal("策略不存在")
+		return models.Strategy{}
+	}
+
+	// 每次更新前保存旧内容，内容未变化不产生新版本，只保留最近 maxStrategyVersions 个
+	for i := 1; i <= maxStrategyVersions+2; i++ {
+		st := current()
+		st.Description = fmt.Sprintf("v%d", i)
+		if err := svc.UpdateStrategy(st, svc.GetRevision()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.UpdateStrategy(current(), svc.GetRevision()); err != nil {
+		t.Fatal(err)
+	}
+	history, err := svc.GetStrategyHistory("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != maxStrategyVersions {
+		t.Fatalf("len(history) = %d, want %d", len(history), maxStrategyVersions)
+	}
+	if history[0].Strategy.Description != "v11" || history[len(history)-1].Strategy.Description != "v2" {
+		t.Fatalf("history = %s ... %s", history[0].Strategy.Description, history[len(history)-1].Strategy.Description)
+	}
+
+	// 恢复后当前内容进入历史
+	if err := svc.RestoreStrategyVersion("user-1", history[5].VersionID); err != nil {
+		t.Fatal(err)
+	}
+	if got := current().Description; got != "v6" {
+		t.Fatalf("restored = %s, want v6", got)
+	}
+	history, _ = svc.GetStrategyHistory("user-1")
+	if history[0].Strategy.Description != "v12" {
+		t.Fatalf("latest version = %s, want v12", history[0].Strategy.Description)
+	}
+
+	// 已删除的策略可从历史恢复
+	if err := svc.DeleteStrategy("user-1", svc.GetRevision()); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RestoreStrategyVersion("user-1", history[0].VersionID); err != nil {
+		t.Fatal(err)
+	}
+	if got := current().Description; got != "v12" {
+		t.Fatalf("restored = %s, want v12", got)
+	}
+
+	// 非法 ID
+	if _, err := svc.GetStrategyHistory("../x"); err == nil {
+		t.Error("GetStrategyHistory 应拒绝非法 ID")
+	}
+	if err := svc.RestoreStrategyVersion("user-1", "../strategies"); err == nil {
+		t.Error("RestoreStrategyVersion 应拒绝非法版本 ID")
+	}
+	if err := svc.RestoreStrategyVersion("user-1", "404"); err == nil {
+		t.Error("RestoreStrategyVersion 应拒绝不存在的版本")
+	}
+}
+
+func TestStrategyMeetingLimits(t *testing.T) {
+	svc := NewStrategyService(t.TempDir())
+	st := models.Strategy{ID: "user-1", Name: "短线", MaxRounds: 1, AgentTimeoutSeconds: 30}
+	if err := svc.AddStrategy(st); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []models.Strategy{
+		{ID: "user-1", MaxRounds: -1},
+		{ID: "user-1", AgentTimeoutSeconds: -5},
+	} {
+		if err := svc.UpdateStrategy(bad, svc.GetRevision()); err == nil {
+			t.Errorf("UpdateStrategy(%+v) 应返回错误", bad)
+		}
+	}
+	if err := svc.AddStrategy(models.Strategy{ID: "user-2", MaxRounds: -1}); err == nil {
+		t.Error("AddStrategy 应拒绝负数轮数上限")
+	}
+
+	all := svc.GetAllStrategies()
+	if got := all[len(all)-1]; got.MaxRounds != 1 || got.AgentTimeoutSeconds != 30 {
+		t.Fatalf("strategy = %+v", got)
+	}
+}
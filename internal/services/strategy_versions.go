@@ -0,0 +1,186 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// maxStrategyVersions 每个策略保留的历史版本数
+const maxStrategyVersions = 10
+
+// StrategyVersion 策略历史版本（更新前的完整快照）
+type StrategyVersion struct {
+	VersionID string          `json:"versionId"`
+	CreatedAt int64           `json:"createdAt"` // 保存时间（毫秒）
+	Strategy  models.Strategy `json:"strategy"`
+}
+
+// versionsDir 策略历史版本目录：versions/{策略ID}/
+func (s *StrategyService) versionsDir(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("无效的策略ID: %s", id)
+	}
+	return filepath.Join(filepath.Dir(s.configPath), "versions", id), nil
+}
+
+// saveVersionLocked 将策略当前内容保存为历史版本，只保留最近 maxStrategyVersions 个（调用方需持有锁）
+func (s *StrategyService) saveVersionLocked(st models.Strategy) error {
+	dir, err := s.versionsDir(st.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	version := StrategyVersion{CreatedAt: now.UnixMilli(), Strategy: st}
+	// 时钟精度不足时顺延，避免覆盖同一时刻的版本
+	for nano := now.UnixNano(); ; nano++ {
+		version.VersionID = strconv.FormatInt(nano, 10)
+		if _, err := os.Stat(filepath.Join(dir, version.VersionID+".json")); os.IsNotExist(err) {
+			break
+		}
+	}
+	data, err := json.MarshalIndent(version, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, version.VersionID+".json"), data, 0644); err != nil {
+		return err
+	}
+
+	ids, err := listVersionIDs(dir)
+	if err != nil {
+		return err
+	}
+	for _, old := range ids[min(len(ids), maxStrategyVersions):] {
+		if err := os.Remove(filepath.Join(dir, old+".json")); err != nil {
+			strategyLog.Warn("清理策略历史版本失败 [%s/%s]: %v", st.ID, old, err)
+		}
+	}
+	return nil
+}
+
+// listVersionIDs 列出目录下的版本 ID，最新的在前
+func listVersionIDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	// 版本 ID 为纳秒时间戳，位数相同时按字典序即按时间排序
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) > len(ids[j])
+		}
+		return ids[i] > ids[j]
+	})
+	return ids, nil
+}
+
+// loadVersion 读取单个历史版本
+func loadVersion(dir, versionID string) (*StrategyVersion, error) {
+	if versionID == "" || versionID != filepath.Base(versionID) {
+		return nil, fmt.Errorf("无效的版本ID: %s", versionID)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, versionID+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("历史版本不存在: %s", versionID)
+		}
+		return nil, err
+	}
+	var version StrategyVersion
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, fmt.Errorf("解析历史版本失败: %w", err)
+	}
+	return &version, nil
+}
+
+// GetStrategyHistory 获取策略的历史版本，最新的在前
+func (s *StrategyService) GetStrategyHistory(id string) ([]StrategyVersion, error) {
+	dir, err := s.versionsDir(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids, err := listVersionIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]StrategyVersion, 0, len(ids))
+	for _, vid := range ids {
+		version, err := loadVersion(dir, vid)
+		if err != nil {
+			strategyLog.Warn("跳过损坏的策略历史版本 [%s/%s]: %v", id, vid, err)
+			continue
+		}
+		versions = append(versions, *version)
+	}
+	return versions, nil
+}
+
+// RestoreStrategyVersion 将策略恢复为指定历史版本，恢复前的内容同样保存为历史版本
+// 策略已被删除时重新添加
+func (s *StrategyService) RestoreStrategyVersion(id, versionID string) error {
+	dir, err := s.versionsDir(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	version, err := loadVersion(dir, versionID)
+	if err != nil {
+		return err
+	}
+	restored := version.Strategy
+	if restored.ID != id {
+		return fmt.Errorf("历史版本与策略不匹配: %s", versionID)
+	}
+
+	for i, st := range s.store.Strategies {
+		if st.ID != id {
+			continue
+		}
+		if st.IsBuiltin {
+			restored.IsBuiltin = true
+			restored.Source = "builtin"
+		}
+		if reflect.DeepEqual(st, restored) {
+			return nil
+		}
+		if err := s.saveVersionLocked(st); err != nil {
+			return fmt.Errorf("保存历史版本失败: %w", err)
+		}
+		s.store.Strategies[i] = restored
+		strategyLog.Info("策略已恢复到历史版本: %s (%s)", st.Name, versionID)
+		return s.saveNoLock()
+	}
+
+	s.store.Strategies = append(s.store.Strategies, restored)
+	strategyLog.Info("已从历史版本恢复被删除的策略: %s (%s)", restored.Name, versionID)
+	return s.saveNoLock()
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 推荐问题数量范围
+const (
+	minSuggestions = 3
+	maxSuggestions = 5
+)
+
+// 异动判定参数
+const (
+	suggestMoveThreshold = 5.0            // 涨跌幅绝对值达到该值视为大幅异动（%）
+	suggestAlertWindow   = 24 * time.Hour // 最近多久内触发的价格提醒视为异动
+)
+
+// SuggestedQuery 输入框下方的推荐问题，Content/MentionIds 与 MeetingMessageRequest 字段一致可直接发送
+type SuggestedQuery struct {
+	Label      string   `json:"label"`      // 按钮文案
+	Content    string   `json:"content"`    // 发送的问题
+	MentionIds []string `json:"mentionIds"` // 需要 @ 的专家，为空时由小韭菜安排
+}
+
+// SuggestionInput 生成推荐问题所需的上下文
+type SuggestionInput struct {
+	Stock        models.Stock
+	Agents       []models.AgentConfig  // 当前策略启用的专家（按策略顺序）
+	Position     *models.StockPosition // 无持仓为 nil
+	MarketStatus string                // trading / pre_market / lunch_break / closed
+	Alerts       []models.PriceAlert   // 该股票的价格提醒
+	Now          time.Time
+}
+
+// roleSuggestion 按专家角色关键词匹配的提问模板，%s 为股票名称
+type roleSuggestion struct {
+	keywords []string
+	label    string
+	content  string
+}
+
+// roleSuggestions 匹配顺序即优先级，一个专家只匹配第一个命中的模板
+var roleSuggestions = []roleSuggestion{
+	{[]string{"资金"}, "问问资金面", "%s最近主力资金和北向资金是什么动向？"},
+	{[]string{"基本面", "财务", "估值"}, "问问基本面", "%s的基本面和估值水平怎么样？"},
+	{[]string{"技术", "K线"}, "看看技术面", "%s的技术形态怎么看？关键支撑和压力位在哪里？"},
+	{[]string{"政策", "宏观"}, "问问政策面", "近期有哪些政策或宏观因素会影响%s？"},
+	{[]string{"舆情", "情绪"}, "看看舆情热度", "%s最近的市场讨论热度和情绪如何？"},
+	{[]string{"风控", "风险"}, "评估风险", "%s目前面临哪些主要风险？"},
+}
+
+// suggestionBuilder 按规则依次追加推荐问题，同一专家只被推荐一次
+type suggestionBuilder struct {
+	items     []SuggestedQuery
+	mentioned map[string]bool
+}
+
+func (b *suggestionBuilder) add(label, content string, agent *models.AgentConfig) {
+	if len(b.items) >= maxSuggestions {
+		return
+	}
+	q := SuggestedQuery{Label: label, Content: content, MentionIds: []string{}}
+	if agent != nil {
+		if b.mentioned[agent.ID] {
+			return
+		}
+		b.mentioned[agent.ID] = true
+		q.MentionIds = []string{agent.ID}
+	}
+	b.items = append(b.items, q)
+}
+
+// BuildSuggestedQueries 按异动、持仓、市场状态、专家角色的顺序用模板生成 3~5 条推荐问题
+// 不调用模型，相同输入得到相同结果
+func BuildSuggestedQueries(in SuggestionInput) []SuggestedQuery {
+	name := in.Stock.Name
+	if name == "" {
+		name = in.Stock.Symbol
+	}
+	isIndex := models.IsIndexSymbol(in.Stock.Symbol)
+	riskAgent := findAgentByRole(in.Agents, "风控", "风险")
+	techAgent := findAgentByRole(in.Agents, "技术", "K线")
+	b := &suggestionBuilder{mentioned: make(map[string]bool)}
+
+	// 1. 异动：最近触发的价格提醒、大幅涨跌
+	if alert := latestTriggeredAlert(in.Alerts, in.Now); alert != nil {
+		b.add("提醒触发后怎么办",
+			fmt.Sprintf("%s刚刚触发了%s的提醒，接下来该怎么应对？", name, alert.Describe()),
+			firstAgent(riskAgent, techAgent))
+	}
+	if chg := in.Stock.ChangePercent; math.Abs(chg) >= suggestMoveThreshold {
+		move := "大涨"
+		if chg < 0 {
+			move = "大跌"
+		}
+		b.add(fmt.Sprintf("为什么%s", move),
+			fmt.Sprintf("%s今天%s %.2f%%，原因是什么？后续怎么看？", name, move, math.Abs(chg)), nil)
+	}
+
+	// 2. 持仓：亏损问止损，盈利问止盈，空仓问建仓
+	if pos := in.Position; pos != nil && pos.Shares > 0 && pos.CostPrice > 0 && in.Stock.Price > 0 {
+		pct := (in.Stock.Price - pos.CostPrice) / pos.CostPrice * 100
+		if pct < 0 {
+			label := "评估止损位"
+			if riskAgent != nil {
+				label = "让" + riskAgent.Name + "评估止损位"
+			}
+			b.add(label, fmt.Sprintf("我持有%s，成本 %.2f，目前亏损 %.2f%%，止损位应该设在哪里？", name, pos.CostPrice, -pct), riskAgent)
+		} else {
+			b.add("要不要止盈", fmt.Sprintf("我持有%s，成本 %.2f，目前盈利 %.2f%%，现在是否该分批止盈？", name, pos.CostPrice, pct), firstAgent(riskAgent, techAgent))
+		}
+	} else if !isIndex {
+		b.add("现在适合建仓吗", fmt.Sprintf("%s现在适合建仓吗？合理的买入区间是多少？", name), nil)
+	}
+
+	// 3. 市场状态
+	switch in.MarketStatus {
+	case "trading":
+		b.add("看看盘中走势", fmt.Sprintf("%s今天盘中走势怎么看？短线支撑和压力位在哪里？", name), techAgent)
+	case "pre_market":
+		b.add("今日开盘预判", fmt.Sprintf("%s今天开盘可能怎么走？需要关注什么？", name), nil)
+	default:
+		b.add("复盘今日表现", fmt.Sprintf("复盘一下%s今天的表现，下个交易日需要关注什么？", name), nil)
+	}
+
+	// 4. 专家角色
+	for i := range in.Agents {
+		agent := &in.Agents[i]
+		for _, rs := range roleSuggestions {
+			if !containsAny(agent.Role, rs.keywords) {
+				continue
+			}
+			if !(isIndex && rs.label == "问问基本面") {
+				b.add(rs.label, fmt.Sprintf(rs.content, name), agent)
+			}
+			break
+		}
+	}
+
+	// 兜底：数量不足时交给小韭菜综合讨论
+	if len(b.items) < minSuggestions {
+		b.add("全面分析一下", fmt.Sprintf("全面分析一下%s，给出操作建议", name), nil)
+	}
+	if len(b.items) < minSuggestions {
+		b.add("后市怎么看", fmt.Sprintf("%s后市怎么看？", name), nil)
+	}
+	return b.items
+}
+
+// findAgentByRole 返回角色包含任一关键词的第一个专家
+func findAgentByRole(agents []models.AgentConfig, keywords ...string) *models.AgentConfig {
+	for i := range agents {
+		if containsAny(agents[i].Role, keywords) {
+			return &agents[i]
+		}
+	}
+	return nil
+}
+
+// firstAgent 返回第一个非 nil 的专家
+func firstAgent(agents ...*models.AgentConfig) *models.AgentConfig {
+	for _, a := range agents {
+		if a != nil {
+			return a
+		}
+	}
+	return nil
+}
+
+// latestTriggeredAlert 返回窗口期内最近触发的提醒
+func latestTriggeredAlert(alerts []models.PriceAlert, now time.Time) *models.PriceAlert {
+	var latest *models.PriceAlert
+	since := now.Add(-suggestAlertWindow).UnixMilli()
+	for i := range alerts {
+		a := &alerts[i]
+		if !a.Triggered || a.TriggeredAt < since {
+			continue
+		}
+		if latest == nil || a.TriggeredAt > latest.TriggeredAt {
+			latest = a
+		}
+	}
+	return latest
+}
+
+func containsAny(s string, keywords []string) bool {
+	for _, k := range keywords {
+		if strings.Contains(s, k) {
+			return true
+		}
+	}
+	return false
+}
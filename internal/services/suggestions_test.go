@@ -0,0 +1,74 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestBuildSuggestedQueries(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	var agents []models.AgentConfig
+	for _, sa := range getDefaultStrategyAgents() {
+		agents = append(agents, models.AgentConfig{ID: sa.ID, Name: sa.Name, Role: sa.Role})
+	}
+	stock := models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1500, ChangePercent: 1.2}
+
+	labels := func(items []SuggestedQuery) string {
+		var out []string
+		for _, it := range items {
+			out = append(out, it.Label)
+		}
+		return strings.Join(out, ",")
+	}
+
+	// 亏损持仓：优先让风控评估止损，其余按专家角色补足
+	losing := BuildSuggestedQueries(SuggestionInput{
+		Stock: stock, Agents: agents, MarketStatus: "trading", Now: now,
+		Position: &models.StockPosition{Shares: 100, CostPrice: 1800},
+	})
+	if got := labels(losing); got != "让风控李评估止损位,看看盘中走势,问问基本面,问问资金面,问问政策面" {
+		t.Fatalf("losing = %s", got)
+	}
+	if !reflect.DeepEqual(losing[0].MentionIds, []string{"risk"}) || !strings.Contains(losing[0].Content, "亏损 16.67%") {
+		t.Errorf("stop loss = %+v", losing[0])
+	}
+	if !reflect.DeepEqual(losing[1].MentionIds, []string{"technical"}) {
+		t.Errorf("intraday = %+v", losing[1])
+	}
+
+	// 相同输入结果一致
+	again := BuildSuggestedQueries(SuggestionInput{
+		Stock: stock, Agents: agents, MarketStatus: "trading", Now: now,
+		Position: &models.StockPosition{Shares: 100, CostPrice: 1800},
+	})
+	if !reflect.DeepEqual(losing, again) {
+		t.Fatal("结果应确定")
+	}
+
+	// 异动优先：触发的提醒与大幅下跌排在最前，过期提醒忽略
+	moved := stock
+	moved.ChangePercent = -7.5
+	anomaly := BuildSuggestedQueries(SuggestionInput{
+		Stock: moved, Agents: agents, MarketStatus: "closed", Now: now,
+		Alerts: []models.PriceAlert{
+			{Symbol: "sh600519", Condition: models.AlertBelow, Threshold: 1400, Triggered: true, TriggeredAt: now.Add(-48 * time.Hour).UnixMilli()},
+			{Symbol: "sh600519", Condition: models.AlertBelow, Threshold: 1450, Triggered: true, TriggeredAt: now.Add(-time.Hour).UnixMilli()},
+		},
+	})
+	if got := labels(anomaly); got != "提醒触发后怎么办,为什么大跌,现在适合建仓吗,复盘今日表现,问问基本面" {
+		t.Fatalf("anomaly = %s", got)
+	}
+	if !strings.Contains(anomaly[0].Content, "跌破 1450.00") || anomaly[1].MentionIds == nil || len(anomaly[1].MentionIds) != 0 {
+		t.Errorf("anomaly = %+v", anomaly[:2])
+	}
+
+	// 无专家时用兜底问题补足到 3 条
+	bare := BuildSuggestedQueries(SuggestionInput{Stock: models.Stock{Symbol: "sh000001", Name: "上证指数"}, MarketStatus: "pre_market", Now: now})
+	if got := labels(bare); got != "今日开盘预判,全面分析一下,后市怎么看" {
+		t.Fatalf("bare = %s", got)
+	}
+}
@@ -0,0 +1,34 @@
+package services
+
+import "time"
+
+// maxCalendarScan 往前查找交易日时最多扫描的自然日数（约 5 年）
+const maxCalendarScan = 1900
+
+// TradingDaysBack 从 end 当天（含）往前取 n 个交易日，按日期倒序
+// 工具与接口中的 days 参数统一按交易日理解，避免"最近20天"只拿到十几根K线
+func (ms *MarketService) TradingDaysBack(end time.Time, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	dates := make([]string, 0, n)
+	for i := 0; i < maxCalendarScan && len(dates) < n; i++ {
+		date := end.AddDate(0, 0, -i)
+		if ms.isTradeDate(date) {
+			dates = append(dates, date.Format("2006-01-02"))
+		}
+	}
+	return dates
+}
+
+// CalendarDaysToTradingDays 统计 end 当天（含）往前 calendarDays 个自然日内的交易日数
+// 供确实需要自然日窗口的场景（calendarDays 参数）换算为K线根数
+func (ms *MarketService) CalendarDaysToTradingDays(end time.Time, calendarDays int) int {
+	count := 0
+	for i := 0; i < calendarDays && i < maxCalendarScan; i++ {
+		if ms.isTradeDate(end.AddDate(0, 0, -i)) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// withHolidays 注入节假日数据，避免测试访问网络
+func withHolidays(t *testing.T, year int, days map[string]bool) {
+	holidayCacheMu.Lock()
+	old, had := holidayCacheData[year]
+	holidayCacheData[year] = days
+	holidayCacheMu.Unlock()
+	t.Cleanup(func() {
+		holidayCacheMu.Lock()
+		defer holidayCacheMu.Unlock()
+		if had {
+			holidayCacheData[year] = old
+		} else {
+			delete(holidayCacheData, year)
+		}
+	})
+}
+
+func TestTradingDaysAcrossHolidayWeek(t *testing.T) {
+	// 2025 国庆：10-01~10-08 休市，09-28(周日)、10-11(周六) 调休上班但不开市
+	withHolidays(t, 2025, map[string]bool{
+		"2025-09-28": false,
+		"2025-10-01": true, "2025-10-02": true, "2025-10-03": true, "2025-10-04": true,
+		"2025-10-05": true, "2025-10-06": true, "2025-10-07": true, "2025-10-08": true,
+		"2025-10-11": false,
+	})
+	ms := &MarketService{}
+	end := time.Date(2025, 10, 13, 15, 0, 0, 0, time.Local)
+
+	got := ms.TradingDaysBack(end, 6)
+	want := []string{"2025-10-13", "2025-10-10", "2025-10-09", "2025-09-30", "2025-09-29", "2025-09-26"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TradingDaysBack = %v, want %v", got, want)
+	}
+
+	// 两周自然日（09-30~10-13）只有 4 个交易日
+	if n := ms.CalendarDaysToTradingDays(end, 14); n != 4 {
+		t.Errorf("CalendarDaysToTradingDays(14) = %d, want 4", n)
+	}
+	if n := ms.CalendarDaysToTradingDays(time.Date(2025, 10, 5, 0, 0, 0, 0, time.Local), 3); n != 0 {
+		t.Errorf("holiday window = %d, want 0", n)
+	}
+}
+
+func TestLastKLines(t *testing.T) {
+	klines := []models.KLineData{{Time: "2025-09-30"}, {Time: "2025-10-09"}, {Time: "2025-10-10"}}
+	if got := lastKLines(klines, 2); len(got) != 2 || got[0].Time != "2025-10-09" {
+		t.Errorf("lastKLines = %+v", got)
+	}
+	if got := lastKLines(klines, 5); len(got) != 3 {
+		t.Errorf("short history should be returned as is, got %d", len(got))
+	}
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 会议记录导出格式
+const (
+	TranscriptFormatMarkdown = "markdown"
+	TranscriptFormatHTML     = "html"
+)
+
+// ErrEmptyTranscript 会话没有可导出的消息
+var ErrEmptyTranscript = errors.New("暂无讨论记录")
+
+// TranscriptMeta 会议记录页眉信息
+type TranscriptMeta struct {
+	StockCode    string
+	StockName    string
+	StrategyName string
+	ExportedAt   time.Time
+}
+
+// transcriptEntry 渲染用的单条发言
+type transcriptEntry struct {
+	Name      string // 发言人
+	Role      string
+	Meta      string // 轮次、类型与时间
+	Content   string
+	Error     string
+	ToolCalls []transcriptToolCall
+	Footnote  int // 工具调用脚注序号，0 表示无
+}
+
+// transcriptToolCall 解析后的工具调用
+type transcriptToolCall struct {
+	Name   string
+	Args   string // k=v, ... 参数摘要
+	Result string // 工具结果 JSON（可能被截断），无结果为空串
+}
+
+// Title 发言人与角色
+func (e transcriptEntry) Title() string {
+	if e.Role == "" {
+		return e.Name
+	}
+	return e.Name + "（" + e.Role + "）"
+}
+
+// NormalizeTranscriptFormat 规范化导出格式，不支持时返回空串
+// pdf 导出为可打印的 HTML，由浏览器或 wkhtmltopdf 等工具转换
+func NormalizeTranscriptFormat(format string) string {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "md", TranscriptFormatMarkdown:
+		return TranscriptFormatMarkdown
+	case "htm", "pdf", TranscriptFormatHTML:
+		return TranscriptFormatHTML
+	}
+	return ""
+}
+
+// TranscriptExporter 会议记录导出器
+type TranscriptExporter struct {
+	Meta     TranscriptMeta
+	Messages []models.ChatMessage
+}
+
+// NewTranscriptExporter 创建会议记录导出器
+func NewTranscriptExporter(meta TranscriptMeta, msgs []models.ChatMessage) *TranscriptExporter {
+	return &TranscriptExporter{Meta: meta, Messages: msgs}
+}
+
+// Markdown 渲染为 Markdown
+func (e *TranscriptExporter) Markdown() string {
+	return RenderTranscriptMarkdown(e.Meta, e.Messages)
+}
+
+// HTML 渲染为可打印为 PDF 的独立 HTML
+func (e *TranscriptExporter) HTML() string {
+	return RenderTranscriptHTML(e.Meta, e.Messages)
+}
+
+// Export 按格式渲染，返回内容与文件扩展名
+func (e *TranscriptExporter) Export(format string) ([]byte, string, error) {
+	if len(e.Messages) == 0 {
+		return nil, "", ErrEmptyTranscript
+	}
+	switch NormalizeTranscriptFormat(format) {
+	case TranscriptFormatMarkdown:
+		return []byte(e.Markdown()), ".md", nil
+	case TranscriptFormatHTML:
+		return []byte(e.HTML()), ".html", nil
+	}
+	return nil, "", fmt.Errorf("不支持的导出格式")
+}
+
+// WriteTranscriptFile 渲染会议记录并写入 dir，返回文件路径
+func WriteTranscriptFile(dir, format string, meta TranscriptMeta, msgs []models.ChatMessage) (string, error) {
+	content, ext, err := NewTranscriptExporter(meta, msgs).Export(format)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建导出目录失败: %w", err)
+	}
+	name := fmt.Sprintf("%s_%s%s", meta.StockCode, meta.ExportedAt.Format("20060102_150405"), ext)
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	return path, nil
+}
+
+// buildTranscript 整理发言与工具调用脚注
+func buildTranscript(msgs []models.ChatMessage) ([]transcriptEntry, [][]string) {
+	entries := make([]transcriptEntry, 0, len(msgs))
+	var footnotes [][]string
+	for _, msg := range msgs {
+		e := transcriptEntry{
+			Name: msg.AgentName, Role: msg.Role,
+			Content: strings.TrimSpace(msg.Content), Error: msg.Error,
+		}
+		for i, call := range msg.ToolCalls {
+			tc := transcriptToolCall{}
+			tc.Name, tc.Args = splitToolCall(call)
+			if i < len(msg.ToolResults) {
+				tc.Result = msg.ToolResults[i]
+			}
+			e.ToolCalls = append(e.ToolCalls, tc)
+		}
+
+		var meta []string
+		if msg.Round > 0 {
+			meta = append(meta, fmt.Sprintf("第%d轮", msg.Round))
+		}
+		if label := transcriptMsgTypeLabel(msg.MsgType); label != "" {
+			meta = append(meta, label)
+		}
+		if msg.Timestamp > 0 {
+			meta = append(meta, time.UnixMilli(msg.Timestamp).Format("2006-01-02 15:04:05"))
+		}
+		if msg.AsOf != "" {
+			meta = append(meta, "数据截至 "+msg.AsOf)
+		}
+		e.Meta = strings.Join(meta, " · ")
+
+		if len(msg.ToolCalls) > 0 {
+			footnotes = append(footnotes, msg.ToolCalls)
+			e.Footnote = len(footnotes)
+		}
+		entries = append(entries, e)
+	}
+	return entries, footnotes
+}
+
+// transcriptMsgTypeLabel 消息类型的中文标签
+func transcriptMsgTypeLabel(msgType string) string {
+	switch msgType {
+	case "opening":
+		return "开场"
+	case "summary":
+		return "总结"
+	}
+	return ""
+}
+
+// transcriptHeader 页眉字段
+func transcriptHeader(meta TranscriptMeta, count int) (title string, fields [][2]string) {
+	title = fmt.Sprintf("%s（%s）讨论记录", meta.StockName, meta.StockCode)
+	if meta.StockName == "" {
+		title = meta.StockCode + " 讨论记录"
+	}
+	strategy := meta.StrategyName
+	if strategy == "" {
+		strategy = "未设置"
+	}
+	fields = [][2]string{
+		{"导出时间", meta.ExportedAt.Format("2006-01-02 15:04:05")},
+		{"当前策略", strategy},
+		{"消息数", fmt.Sprintf("%d", count)},
+	}
+	return title, fields
+}
+
+// RenderTranscriptMarkdown 渲染为 Markdown：每条发言一个二级标题（发言人加粗），
+// 工具结果以引用块标注并附 JSON 代码块，全部工具调用列在文末脚注
+func RenderTranscriptMarkdown(meta TranscriptMeta, msgs []models.ChatMessage) string {
+	entries, footnotes := buildTranscript(msgs)
+	title, fields := transcriptHeader(meta, len(msgs))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "- %s：%s\n", f[0], f[1])
+	}
+	b.WriteString("\n---\n")
+
+	for _, e := range entries {
+		fmt.Fprintf(&b, "\n## **%s**", e.Name)
+		if e.Role != "" {
+			fmt.Fprintf(&b, "（%s）", e.Role)
+		}
+		if e.Footnote > 0 {
+			fmt.Fprintf(&b, "[^%d]", e.Footnote)
+		}
+		b.WriteString("\n\n")
+		if e.Meta != "" {
+			fmt.Fprintf(&b, "*%s*\n\n", e.Meta)
+		}
+		if e.Content != "" {
+			b.WriteString(e.Content + "\n\n")
+		}
+		for _, call := range e.ToolCalls {
+			if call.Result != "" {
+				writeToolResultCallout(&b, call)
+			}
+		}
+		if e.Error != "" {
+			fmt.Fprintf(&b, "> 发言失败：%s\n\n", e.Error)
+		}
+	}
+
+	if len(footnotes) > 0 {
+		b.WriteString("---\n\n")
+		for i, calls := range footnotes {
+			fmt.Fprintf(&b, "[^%d]: 工具调用：%s\n", i+1, strings.Join(calls, "；"))
+		}
+	}
+	return b.String()
+}
+
+// writeToolResultCallout 工具调用引用块与结果代码块，结果为合法 JSON 时格式化缩进
+func writeToolResultCallout(b *strings.Builder, call transcriptToolCall) {
+	fmt.Fprintf(b, "> **工具调用**：`%s`", call.Name)
+	if call.Args != "" {
+		fmt.Fprintf(b, "（%s）", call.Args)
+	}
+	result, lang := call.Result, "json"
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, []byte(result), "", "  ") == nil {
+		result = pretty.String()
+	} else {
+		lang = "" // 截断后不再是合法 JSON
+	}
+	fmt.Fprintf(b, "\n\n```%s\n%s\n```\n\n", lang, result)
+}
+
+// splitToolCall 拆分 formatToolCall 生成的 name(k=v, ...) 格式
+func splitToolCall(call string) (name, args string) {
+	i := strings.IndexByte(call, '(')
+	if i <= 0 || !strings.HasSuffix(call, ")") {
+		return call, ""
+	}
+	return call[:i], strings.TrimSpace(call[i+1 : len(call)-1])
+}
+
+// transcriptCSS 独立 HTML 的内联样式，打印时去掉背景便于另存为 PDF
+const transcriptCSS = `body{font-family:-apple-system,"PingFang SC","Microsoft YaHei",sans-serif;max-width:860px;margin:32px auto;padding:0 24px;color:#1f2937;line-height:1.7}
+h1{font-size:22px;margin-bottom:8px}
+.meta{color:#6b7280;font-size:13px;margin:0 0 24px;padding:0;list-style:none}
+.msg{border:1px solid #e5e7eb;border-radius:8px;padding:12px 16px;margin:12px 0;background:#f9fafb;page-break-inside:avoid}
+.msg h3{font-size:15px;margin:0}
+.msg .info{color:#6b7280;font-size:12px;margin-bottom:8px}
+.msg .content{white-space:pre-wrap;word-break:break-word}
+.msg .error{color:#b91c1c;font-size:13px}
+.footnotes{font-size:12px;color:#4b5563;border-top:1px solid #e5e7eb;margin-top:24px;padding-top:8px}
+@media print{body{margin:0}.msg{background:none}}`
+
+// RenderTranscriptHTML 渲染为独立 HTML 文档，可直接在浏览器中打印为 PDF
+func RenderTranscriptHTML(meta TranscriptMeta, msgs []models.ChatMessage) string {
+	entries, footnotes := buildTranscript(msgs)
+	title, fields := transcriptHeader(meta, len(msgs))
+	esc := html.EscapeString
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"zh-CN\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n", esc(title), transcriptCSS)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<ul class=\"meta\">\n", esc(title))
+	for _, f := range fields {
+		fmt.Fprintf(&b, "<li>%s：%s</li>\n", esc(f[0]), esc(f[1]))
+	}
+	b.WriteString("</ul>\n")
+
+	for _, e := range entries {
+		b.WriteString("<div class=\"msg\">\n<h3>" + esc(e.Title()))
+		if e.Footnote > 0 {
+			fmt.Fprintf(&b, "<sup><a href=\"#fn%d\">%d</a></sup>", e.Footnote, e.Footnote)
+		}
+		b.WriteString("</h3>\n")
+		if e.Meta != "" {
+			fmt.Fprintf(&b, "<div class=\"info\">%s</div>\n", esc(e.Meta))
+		}
+		if e.Content != "" {
+			fmt.Fprintf(&b, "<div class=\"content\">%s</div>\n", esc(e.Content))
+		}
+		if e.Error != "" {
+			fmt.Fprintf(&b, "<div class=\"error\">发言失败：%s</div>\n", esc(e.Error))
+		}
+		b.WriteString("</div>\n")
+	}
+
+	if len(footnotes) > 0 {
+		b.WriteString("<ol class=\"footnotes\">\n")
+		for i, calls := range footnotes {
+			fmt.Fprintf(&b, "<li id=\"fn%d\">工具调用：%s</li>\n", i+1, esc(strings.Join(calls, "；")))
+		}
+		b.WriteString("</ol>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
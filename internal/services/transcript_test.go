@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestRenderTranscript(t *testing.T) {
+	meta := TranscriptMeta{
+		StockCode: "sh600519", StockName: "贵州茅台", StrategyName: "价值投资",
+		ExportedAt: time.Date(2026, 3, 2, 15, 4, 5, 0, time.Local),
+	}
+	ts := time.Date(2026, 3, 2, 14, 0, 0, 0, time.Local).UnixMilli()
+	msgs := []models.ChatMessage{
+		{AgentID: "user", AgentName: "老韭菜", Content: "后市<怎么看>", Timestamp: ts},
+		{AgentID: "fundamental", AgentName: "老陈", Role: "基本面研究员", Content: "估值合理", Round: 1, MsgType: "opinion",
+			Timestamp: ts, ToolCalls: []string{"get_research_report(code=sh600519)", "get_stock_realtime(codes=[\"sh600519\"])", "get_news(days=3)"},
+			ToolResults: []string{"", `{"price":1500.5,"change":1.2}`, `{"items":["茅台…`}},
+		{AgentID: "technical", AgentName: "阿杰", Role: "技术分析师", Round: 1, Error: "timeout", Timestamp: ts},
+		{AgentID: "moderator", AgentName: "小韭菜", Content: "总结如下", MsgType: "summary", Timestamp: ts, AsOf: "2026-02-27 15:00"},
+	}
+
+	md := RenderTranscriptMarkdown(meta, msgs)
+	for _, want := range []string{
+		"# 贵州茅台（sh600519）讨论记录",
+		"- 导出时间：2026-03-02 15:04:05",
+		"- 当前策略：价值投资",
+		"## **老韭菜**\n",
+		"*第1轮 · 2026-03-02 14:00:00*",
+		"## **老陈**（基本面研究员）[^1]\n",
+		"> **工具调用**：`get_stock_realtime`（codes=[\"sh600519\"]）\n\n```json\n{\n  \"price\": 1500.5,\n  \"change\": 1.2\n}\n```",
+		"> **工具调用**：`get_news`（days=3）\n\n```\n{\"items\":[\"茅台…\n```",
+		"[^1]: 工具调用：get_research_report(code=sh600519)；get_stock_realtime(codes=[\"sh600519\"])；get_news(days=3)",
+		"> 发言失败：timeout",
+		"*总结 · 2026-03-02 14:00:00 · 数据截至 2026-02-27 15:00*",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown 缺少 %q:\n%s", want, md)
+		}
+	}
+
+	if strings.Contains(md, "`get_research_report`") {
+		t.Errorf("无结果的调用只应出现在脚注:\n%s", md)
+	}
+
+	page := RenderTranscriptHTML(meta, msgs)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>贵州茅台（sh600519）讨论记录</title>",
+		"后市&lt;怎么看&gt;",
+		`<sup><a href="#fn1">1</a></sup>`,
+		`<li id="fn1">工具调用：`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html 缺少 %q", want)
+		}
+	}
+}
+
+func TestWriteTranscriptFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "exports")
+	meta := TranscriptMeta{StockCode: "sz000001", ExportedAt: time.Date(2026, 3, 2, 15, 4, 5, 0, time.Local)}
+
+	if _, err := WriteTranscriptFile(dir, "html", meta, nil); !errors.Is(err, ErrEmptyTranscript) {
+		t.Fatalf("空会话 err = %v", err)
+	}
+	if _, err := WriteTranscriptFile(dir, "docx", meta, []models.ChatMessage{{Content: "x"}}); err == nil {
+		t.Fatal("不支持的格式应返回错误")
+	}
+	if _, ext, err := NewTranscriptExporter(meta, []models.ChatMessage{{Content: "x"}}).Export("pdf"); err != nil || ext != ".html" {
+		t.Fatalf("pdf 应导出为可打印 HTML: ext=%s err=%v", ext, err)
+	}
+
+	path, err := WriteTranscriptFile(dir, "md", meta, []models.ChatMessage{{AgentName: "老陈", Content: "观点"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "sz000001_20260302_150405.md" {
+		t.Fatalf("path = %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# sz000001 讨论记录") || !strings.Contains(string(data), "- 当前策略：未设置") {
+		t.Fatalf("content = %s", data)
+	}
+}
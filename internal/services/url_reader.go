@@ -0,0 +1,382 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// 网页读取参数
+const (
+	urlReaderTimeout      = 10 * time.Second
+	urlReaderMaxRedirects = 5
+	webPageMaxDownload    = 2 << 20
+	// DefaultURLReaderChars 网页正文默认截取字数
+	DefaultURLReaderChars = 6000
+)
+
+// ErrURLNotAllowed 网址协议或目标地址不允许访问
+var ErrURLNotAllowed = errors.New("仅支持访问公网 http/https 网址")
+
+// WebPage 网页读取结果
+type WebPage struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	PublishTime string `json:"publishTime,omitempty"` // 未识别到时为空
+	Text        string `json:"text"`
+	TotalChars  int    `json:"totalChars"`
+	Truncated   bool   `json:"truncated"`
+}
+
+// URLReader 网页读取器：经代理下载网页并提取正文，拒绝非 http(s) 协议与内网地址
+type URLReader struct {
+	client       *http.Client // 创建时构建，所有请求共用连接池；测试时替换
+	allowPrivate bool         // 测试时允许访问本机地址
+}
+
+// NewURLReader 创建网页读取器
+func NewURLReader() *URLReader {
+	r := &URLReader{}
+	r.client = &http.Client{Transport: r.newTransport(), Timeout: urlReaderTimeout}
+	return r
+}
+
+// newTransport 创建带内网地址拦截的 Transport
+// 代理按请求从代理管理器读取，代理设置修改后立即生效，无需重建 Transport
+func (r *URLReader) newTransport() *http.Transport {
+	transport := proxy.GetManager().GetTransport()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		if current := proxy.GetManager().SharedTransport().Proxy; current != nil {
+			return current(req)
+		}
+		return nil, nil
+	}
+	r.guardTransport(transport)
+	return transport
+}
+
+// guardTransport 直连时在建立连接前再校验一次实际 IP，防止 DNS 解析结果在校验后被替换
+// 是否走代理按请求判断：系统代理模式下未配置代理或命中例外时 Proxy 返回 nil，同样需要校验
+// 只有连接本次代理函数返回的代理地址时跳过校验，本机代理（如 127.0.0.1:7890）可正常使用
+func (r *URLReader) guardTransport(transport *http.Transport) {
+	var proxyAddrs sync.Map
+	if base := transport.Proxy; base != nil {
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			u, err := base(req)
+			if u != nil {
+				proxyAddrs.Store(proxyDialAddr(u), true)
+			}
+			return u, err
+		}
+	}
+	direct := &net.Dialer{Timeout: urlReaderTimeout, KeepAlive: 30 * time.Second}
+	guarded := &net.Dialer{Timeout: urlReaderTimeout, KeepAlive: 30 * time.Second, Control: r.dialControl}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, ok := proxyAddrs.Load(addr); ok {
+			return direct.DialContext(ctx, network, addr)
+		}
+		return guarded.DialContext(ctx, network, addr)
+	}
+}
+
+// proxyDialAddr 代理地址的 host:port，未写端口时按协议补默认端口（与 Transport 连接代理时一致）
+func proxyDialAddr(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return net.JoinHostPort(u.Hostname(), port)
+	}
+	port := "80"
+	switch u.Scheme {
+	case "https":
+		port = "443"
+	case "socks5", "socks5h":
+		port = "1080"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// dialControl 拒绝连接内网地址
+func (r *URLReader) dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip != nil && !r.allowPrivate && isPrivateIP(ip) {
+		return ErrURLNotAllowed
+	}
+	return nil
+}
+
+// isPrivateIP 判断是否为本机、内网、链路本地等非公网地址
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		// 100.64.0.0/10 运营商级 NAT
+		(ip.To4() != nil && ip.To4()[0] == 100 && ip.To4()[1]&0xc0 == 64)
+}
+
+// checkURL 校验协议并解析主机，任一解析结果为内网地址即拒绝
+func (r *URLReader) checkURL(ctx context.Context, u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" || u.Hostname() == "" {
+		return ErrURLNotAllowed
+	}
+	if r.allowPrivate {
+		return nil
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if isPrivateIP(ip) {
+			return ErrURLNotAllowed
+		}
+		return nil
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return ErrURLNotAllowed
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("解析域名失败: %w", err)
+	}
+	for _, addr := range addrs {
+		if isPrivateIP(addr.IP) {
+			return ErrURLNotAllowed
+		}
+	}
+	return nil
+}
+
+// Read 下载网页并提取标题、发布时间与正文，正文最多返回 maxChars 个字符（0 为默认值）
+func (r *URLReader) Read(ctx context.Context, rawURL string, maxChars int) (*WebPage, error) {
+	if maxChars <= 0 {
+		maxChars = DefaultURLReaderChars
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, ErrURLNotAllowed
+	}
+	if err := r.checkURL(ctx, u); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, urlReaderTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9")
+
+	// 跳转目标同样需要校验
+	client := *r.client
+	client.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if len(via) >= urlReaderMaxRedirects {
+			return errors.New("跳转次数过多")
+		}
+		return r.checkURL(next.Context(), next.URL)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrURLNotAllowed) {
+			return nil, ErrURLNotAllowed
+		}
+		return nil, fmt.Errorf("请求网页失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("请求网页失败: HTTP %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") && !strings.HasPrefix(contentType, "text/") {
+		return nil, fmt.Errorf("不支持的网页类型: %s", contentType)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, webPageMaxDownload))
+	if err != nil {
+		return nil, fmt.Errorf("读取网页失败: %w", err)
+	}
+	// 按响应头与 meta 声明转码，国内站点常见 GBK
+	if reader, err := charset.NewReader(bytes.NewReader(body), contentType); err == nil {
+		if decoded, err := io.ReadAll(reader); err == nil {
+			body = decoded
+		}
+	}
+
+	page := &WebPage{URL: resp.Request.URL.String()}
+	if strings.HasPrefix(contentType, "text/plain") {
+		page.Text = cleanAnnouncementText(string(body))
+	} else if err := extractArticle(body, page); err != nil {
+		return nil, err
+	}
+	runes := []rune(page.Text)
+	page.TotalChars = len(runes)
+	if len(runes) > maxChars {
+		page.Text = string(runes[:maxChars])
+		page.Truncated = true
+	}
+	return page, nil
+}
+
+// 正文提取时剔除的样板区域
+var (
+	boilerplateTags    = "script, style, noscript, iframe, svg, form, button, nav, header, footer, aside, select, textarea"
+	boilerplatePattern = regexp.MustCompile(`(?i)(^|[-_\s])(nav|menu|footer|header|sidebar|side|comment|share|related|recommend|breadcrumb|copyright|advert|ads?|banner|login|toolbar|popup)([-_\s]|$)`)
+	publishTimePattern = regexp.MustCompile(`(20\d{2})[-/.年](\d{1,2})[-/.月](\d{1,2})日?(?:[T\s]*(\d{1,2}):(\d{2}))?`)
+)
+
+// extractArticle 简化的 readability 提取：按段落文本长度为父节点打分，取得分最高的节点作为正文
+func extractArticle(body []byte, page *WebPage) error {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("解析网页失败: %w", err)
+	}
+	page.Title = pageTitle(doc)
+	page.PublishTime = pagePublishTime(doc)
+
+	doc.Find(boilerplateTags).Remove()
+	doc.Find("[class], [id]").Each(func(_ int, s *goquery.Selection) {
+		// 包含正文标记的外层容器（如带 has-sidebar 类名的页面布局）不删除
+		if goquery.NodeName(s) == "body" || s.Is("article, main") || s.Find("article, main, h1").Length() > 0 {
+			return
+		}
+		class, _ := s.Attr("class")
+		id, _ := s.Attr("id")
+		if boilerplatePattern.MatchString(class) || boilerplatePattern.MatchString(id) {
+			s.Remove()
+		}
+	})
+
+	root := articleRoot(doc)
+	root.Find("br").ReplaceWithHtml("\n")
+	root.Find("p, div, li, tr, h1, h2, h3, h4, h5, h6, section, article").AppendHtml("\n")
+	page.Text = cleanAnnouncementText(root.Text())
+	return nil
+}
+
+// articleRoot 选出正文节点：段落文本计入父节点全分、祖父节点半分，再按链接文字占比折减
+func articleRoot(doc *goquery.Document) *goquery.Selection {
+	type candidate struct {
+		sel   *goquery.Selection
+		score float64
+	}
+	var candidates []*candidate // 按首次出现顺序保存，得分相同时取靠前的节点
+	index := make(map[*html.Node]*candidate)
+	add := func(s *goquery.Selection, score float64) {
+		if s.Length() == 0 {
+			return
+		}
+		c, ok := index[s.Get(0)]
+		if !ok {
+			c = &candidate{sel: s}
+			index[s.Get(0)] = c
+			candidates = append(candidates, c)
+		}
+		c.score += score
+	}
+	doc.Find("p, pre, td").Each(func(_ int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		n := len([]rune(text))
+		if n < 20 {
+			return
+		}
+		// 基础 1 分 + 逗号句号数 + 每 100 字 1 分（最多 3 分）
+		score := 1 + float64(strings.Count(text, "，")+strings.Count(text, ",")+strings.Count(text, "。")) + min(float64(n)/100, 3)
+		add(p.Parent(), score)
+		add(p.Parent().Parent(), score/2)
+	})
+
+	var best *candidate
+	for _, c := range candidates {
+		if textLen := len([]rune(c.sel.Text())); textLen > 0 {
+			c.score *= 1 - float64(len([]rune(c.sel.Find("a").Text())))/float64(textLen)
+		}
+		if best == nil || c.score > best.score {
+			best = c
+		}
+	}
+	if best != nil && best.score > 0 {
+		return best.sel
+	}
+	if article := doc.Find("article, main").First(); article.Length() > 0 {
+		return article
+	}
+	return doc.Find("body")
+}
+
+// pageTitle 优先取 og:title，其次 title 标签与首个 h1
+func pageTitle(doc *goquery.Document) string {
+	if title, ok := doc.Find(`meta[property="og:title"]`).Attr("content"); ok && strings.TrimSpace(title) != "" {
+		return strings.TrimSpace(title)
+	}
+	if title := strings.TrimSpace(doc.Find("title").First().Text()); title != "" {
+		return title
+	}
+	return strings.TrimSpace(doc.Find("h1").First().Text())
+}
+
+// pagePublishTime 从 meta 标签、time 标签与日期样式的元素中识别发布时间，识别不到返回空
+func pagePublishTime(doc *goquery.Document) string {
+	var candidates []string
+	for _, sel := range []string{
+		`meta[property="article:published_time"]`,
+		`meta[name="pubdate"]`,
+		`meta[name="publishdate"]`,
+		`meta[name="PubDate"]`,
+		`meta[itemprop="datePublished"]`,
+	} {
+		if v, ok := doc.Find(sel).Attr("content"); ok {
+			candidates = append(candidates, v)
+		}
+	}
+	if v, ok := doc.Find("time[datetime]").Attr("datetime"); ok {
+		candidates = append(candidates, v)
+	}
+	doc.Find(`[class*="time"], [class*="date"], [id*="time"], [id*="date"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		candidates = append(candidates, s.Text())
+		return len(candidates) < 10
+	})
+	for _, v := range candidates {
+		if t := normalizePublishTime(v); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// normalizePublishTime 将常见日期写法统一为 2006-01-02 或 2006-01-02 15:04
+func normalizePublishTime(s string) string {
+	m := publishTimePattern.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Month() != time.Month(month) || t.Day() != day {
+		return ""
+	}
+	if m[4] == "" {
+		return t.Format("2006-01-02")
+	}
+	hour, _ := strconv.Atoi(m[4])
+	minute, _ := strconv.Atoi(m[5])
+	if hour > 23 || minute > 59 {
+		return t.Format("2006-01-02")
+	}
+	return t.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute).Format("2006-01-02 15:04")
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+const testArticle = `<html><head><title>茅台发布三季报 - 财经网</title>
+<meta name="pubdate" content="2025-10-16T08:30:00+08:00"></head>
+<body class="has-sidebar">
+<div class="nav-bar"><a href="/">首页</a><a href="/stock">股票</a><a href="/fund">基金</a></div>
+<div id="main-content">
+  <h1>茅台发布三季报</h1>
+  <p>贵州茅台10月16日晚间发布三季度报告，前三季度实现营业收入约1200亿元，同比增长约15%。</p>
+  <p>公司表示，直销渠道占比继续提升，i茅台平台贡献明显，毛利率维持在九成以上的高位。</p>
+  <p>分析人士认为，在白酒行业整体承压的背景下，茅台业绩仍具韧性，但增速有所放缓。</p>
+</div>
+<div class="related-news"><p><a href="/a">相关新闻：白酒板块午后走强，多只个股涨超百分之五</a></p></div>
+<div class="footer">版权所有 © 财经网</div>
+<script>track()</script>
+</body></html>`
+
+func TestURLReaderRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gbk":
+			w.Header().Set("Content-Type", "text/html; charset=gbk")
+			body, _ := simplifiedchinese.GBK.NewEncoder().String(`<html><body><p>这是一段使用GBK编码的网页正文内容，用于测试转码是否正确。</p><span class="date">2025年9月3日 14:05</span></body></html>`)
+			io.WriteString(w, body)
+		case "/pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+		case "/redirect":
+			http.Redirect(w, r, "/article", http.StatusFound)
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, testArticle)
+		}
+	}))
+	defer srv.Close()
+	reader := &URLReader{client: srv.Client(), allowPrivate: true}
+	ctx := context.Background()
+
+	page, err := reader.Read(ctx, srv.URL+"/redirect", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.URL != srv.URL+"/article" || page.Title != "茅台发布三季报 - 财经网" || page.PublishTime != "2025-10-16 08:30" {
+		t.Errorf("page = %+v", page)
+	}
+	if !strings.HasPrefix(page.Text, "茅台发布三季报\n贵州茅台") || !strings.HasSuffix(page.Text, "增速有所放缓。") {
+		t.Errorf("text = %q", page.Text)
+	}
+	for _, noise := range []string{"首页", "相关新闻", "版权所有", "track"} {
+		if strings.Contains(page.Text, noise) {
+			t.Errorf("text should not contain %q: %q", noise, page.Text)
+		}
+	}
+
+	page, err = reader.Read(ctx, srv.URL+"/article", 10)
+	if err != nil || page.Text != "茅台发布三季报\n贵州" || !page.Truncated || page.TotalChars <= 10 {
+		t.Errorf("truncated page = %+v, %v", page, err)
+	}
+
+	page, err = reader.Read(ctx, srv.URL+"/gbk", 0)
+	if err != nil || !strings.HasPrefix(page.Text, "这是一段使用GBK编码") || page.PublishTime != "2025-09-03 14:05" {
+		t.Errorf("gbk page = %+v, %v", page, err)
+	}
+
+	if _, err := reader.Read(ctx, srv.URL+"/pdf", 0); err == nil {
+		t.Error("non-html content should fail")
+	}
+}
+
+func TestURLReaderBlocksPrivateAddresses(t *testing.T) {
+	// 公网地址跳转到内网地址
+	var requested []string
+	reader := &URLReader{client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.String())
+		header := http.Header{"Location": []string{"http://192.168.1.1/admin"}}
+		return &http.Response{StatusCode: http.StatusFound, Header: header, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	})}}
+	ctx := context.Background()
+
+	for _, rawURL := range []string{
+		"file:///etc/passwd",
+		"ftp://8.8.8.8/a",
+		"javascript:alert(1)",
+		"http://127.0.0.1:8080/",
+		"http://localhost/",
+		"http://10.0.0.8/",
+		"http://[::1]/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://100.64.0.1/",
+	} {
+		if _, err := reader.Read(ctx, rawURL, 0); !errors.Is(err, ErrURLNotAllowed) {
+			t.Errorf("%s: err = %v, want ErrURLNotAllowed", rawURL, err)
+		}
+	}
+	if len(requested) != 0 {
+		t.Errorf("blocked urls should not be requested: %v", requested)
+	}
+
+	if _, err := reader.Read(ctx, "http://8.8.8.8/news", 0); !errors.Is(err, ErrURLNotAllowed) {
+		t.Errorf("redirect to private address: err = %v", err)
+	}
+	if len(requested) != 1 {
+		t.Errorf("requested = %v", requested)
+	}
+}
+
+func TestNormalizePublishTime(t *testing.T) {
+	cases := map[string]string{
+		"2025-10-16T08:30:00+08:00": "2025-10-16 08:30",
+		"发布时间：2025年1月5日":            "2025-01-05",
+		"2025/02/30":                "",
+		"来源：新华社":                    "",
+	}
+	for in, want := range cases {
+		if got := normalizePublishTime(in); got != want {
+			t.Errorf("normalizePublishTime(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestURLReaderGuardTransport(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	srvURL, _ := url.Parse(srv.URL)
+
+	reader := &URLReader{}
+	get := func(target string, proxyFunc func(*http.Request) (*url.URL, error)) error {
+		transport := &http.Transport{Proxy: proxyFunc}
+		reader.guardTransport(transport)
+		resp, err := (&http.Client{Transport: transport}).Get(target)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// 模拟 DNS 重绑定：通过预检后实际连接到本机地址
+	if err := get(srv.URL, nil); !errors.Is(err, ErrURLNotAllowed) {
+		t.Errorf("no proxy: err = %v", err)
+	}
+	// 系统代理模式未配置代理时 Proxy 返回 nil，同样直连，需要校验
+	noSystemProxy := func(*http.Request) (*url.URL, error) { return nil, nil }
+	if err := get(srv.URL, noSystemProxy); !errors.Is(err, ErrURLNotAllowed) {
+		t.Errorf("system proxy unset: err = %v", err)
+	}
+	if hits != 0 {
+		t.Fatalf("private address reached %d times", hits)
+	}
+
+	// 本机代理照常使用
+	if err := get("http://8.8.8.8/news", http.ProxyURL(srvURL)); err != nil || hits != 1 {
+		t.Errorf("local proxy: err = %v, hits = %d", err, hits)
+	}
+}
+
+// Transport 创建时构建一次，代理设置修改后仍按新配置连接
+func TestURLReaderFollowsProxyChange(t *testing.T) {
+	var hits int32
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, testArticle)
+	}))
+	defer proxySrv.Close()
+	t.Cleanup(func() { proxy.GetManager().SetConfig(&models.ProxyConfig{Mode: models.ProxyModeNone}) })
+
+	reader := NewURLReader()
+	transport := reader.client.Transport
+	proxy.GetManager().SetConfig(&models.ProxyConfig{Mode: models.ProxyModeCustom, CustomURL: proxySrv.URL})
+	for range 2 {
+		if _, err := reader.Read(context.Background(), "http://8.8.8.8/news", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits != 2 || reader.client.Transport != transport {
+		t.Errorf("hits = %d, transport rebuilt = %v", hits, reader.client.Transport != transport)
+	}
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// ErrWatchlistGroupNotFound 自选股分组不存在
+var ErrWatchlistGroupNotFound = errors.New("自选股分组不存在")
+
+// normalizeWatchlistLocked 补齐默认分组、将无效分组的自选股归入默认分组、整理分组成员，
+// 并按分组顺序与分组内顺序重排，SortOrder 重新编号为连续值；返回是否修正了分组数据(需要已持有锁)
+func (cs *ConfigService) normalizeWatchlistLocked() bool {
+	changed := false
+	if !slices.ContainsFunc(cs.groups, func(g models.WatchlistGroup) bool { return g.ID == models.DefaultWatchlistGroupID }) {
+		defaultGroup := models.WatchlistGroup{ID: models.DefaultWatchlistGroupID, Name: models.DefaultWatchlistGroupName}
+		cs.groups = append([]models.WatchlistGroup{defaultGroup}, cs.groups...)
+		changed = true
+	}
+	slices.SortStableFunc(cs.groups, func(a, b models.WatchlistGroup) int {
+		return cmp.Compare(a.SortOrder, b.SortOrder)
+	})
+	groupRank := make(map[string]int, len(cs.groups))
+	for i := range cs.groups {
+		cs.groups[i].SortOrder = i
+		groupRank[cs.groups[i].ID] = i
+	}
+
+	for i := range cs.watchlist {
+		if _, ok := groupRank[cs.watchlist[i].GroupID]; !ok {
+			cs.watchlist[i].GroupID = models.DefaultWatchlistGroupID
+			changed = true
+		}
+	}
+	if cs.normalizeGroupMembersLocked() {
+		changed = true
+	}
+
+	slices.SortStableFunc(cs.watchlist, func(a, b models.Stock) int {
+		if c := cmp.Compare(groupRank[a.GroupID], groupRank[b.GroupID]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.SortOrder, b.SortOrder)
+	})
+	counts := make(map[string]int, len(cs.groups))
+	for i := range cs.watchlist {
+		group := cs.watchlist[i].GroupID
+		cs.watchlist[i].SortOrder = counts[group]
+		counts[group]++
+	}
+	return changed
+}
+
+// normalizeGroupMembersLocked 去掉已删除或重复的成员，并保证每只自选股都在其主分组中；返回是否有修正(需要已持有锁)
+func (cs *ConfigService) normalizeGroupMembersLocked() bool {
+	changed := false
+	primary := make(map[string]string, len(cs.watchlist))
+	for _, s := range cs.watchlist {
+		primary[s.Symbol] = s.GroupID
+	}
+	for i := range cs.groups {
+		g := &cs.groups[i]
+		seen := make(map[string]bool, len(g.Symbols))
+		members := make([]string, 0, len(g.Symbols))
+		for _, symbol := range g.Symbols {
+			if _, ok := primary[symbol]; ok && !seen[symbol] {
+				seen[symbol] = true
+				members = append(members, symbol)
+			}
+		}
+		for _, s := range cs.watchlist {
+			if s.GroupID == g.ID && !seen[s.Symbol] {
+				seen[s.Symbol] = true
+				members = append(members, s.Symbol)
+			}
+		}
+		if g.Symbols == nil || !slices.Equal(members, g.Symbols) {
+			changed = true
+		}
+		g.Symbols = members
+	}
+	return changed
+}
+
+// GetWatchlistGroups 获取自选股分组，按分组顺序排列
+func (cs *ConfigService) GetWatchlistGroups() []models.WatchlistGroup {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	groups := slices.Clone(cs.groups)
+	for i := range groups {
+		groups[i].Symbols = slices.Clone(groups[i].Symbols)
+	}
+	return groups
+}
+
+// findGroupLocked 按 ID 查找分组下标，不存在时返回 -1(需要已持有锁)
+func (cs *ConfigService) findGroupLocked(id string) int {
+	return slices.IndexFunc(cs.groups, func(g models.WatchlistGroup) bool { return g.ID == id })
+}
+
+// checkGroupNameLocked 校验分组名称非空且不与其他分组重名(需要已持有锁)
+func (cs *ConfigService) checkGroupNameLocked(name, excludeID string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("分组名称不能为空")
+	}
+	for _, g := range cs.groups {
+		if g.ID != excludeID && g.Name == name {
+			return "", fmt.Errorf("分组名称已存在: %s", name)
+		}
+	}
+	return name, nil
+}
+
+// CreateWatchlistGroup 新建自选股分组，追加到分组末尾
+func (cs *ConfigService) CreateWatchlistGroup(name string) (models.WatchlistGroup, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	name, err := cs.checkGroupNameLocked(name, "")
+	if err != nil {
+		return models.WatchlistGroup{}, err
+	}
+	group := models.WatchlistGroup{ID: uuid.New().String()[:8], Name: name, SortOrder: len(cs.groups), Symbols: []string{}}
+	cs.groups = append(cs.groups, group)
+	return group, cs.saveWatchlistLocked()
+}
+
+// RenameWatchlistGroup 重命名自选股分组
+func (cs *ConfigService) RenameWatchlistGroup(id, name string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	idx := cs.findGroupLocked(id)
+	if idx < 0 {
+		return ErrWatchlistGroupNotFound
+	}
+	name, err := cs.checkGroupNameLocked(name, id)
+	if err != nil {
+		return err
+	}
+	cs.groups[idx].Name = name
+	return cs.saveWatchlistLocked()
+}
+
+// DeleteWatchlistGroup 删除自选股分组，默认分组不可删除
+// 以该分组为主分组的自选股改到所属的下一个分组，不属于其他分组时归入默认分组末尾
+func (cs *ConfigService) DeleteWatchlistGroup(id string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if id == models.DefaultWatchlistGroupID {
+		return fmt.Errorf("默认分组不能删除")
+	}
+	idx := cs.findGroupLocked(id)
+	if idx < 0 {
+		return ErrWatchlistGroupNotFound
+	}
+	cs.groups = slices.Delete(cs.groups, idx, idx+1)
+	for i := range cs.watchlist {
+		if cs.watchlist[i].GroupID == id {
+			cs.reassignPrimaryGroupLocked(i)
+		}
+	}
+	cs.normalizeWatchlistLocked()
+	return cs.saveWatchlistLocked()
+}
+
+// reassignPrimaryGroupLocked 为失去主分组的自选股选出新的主分组并排到末尾(需要已持有锁)
+func (cs *ConfigService) reassignPrimaryGroupLocked(i int) {
+	s := &cs.watchlist[i]
+	s.GroupID = models.DefaultWatchlistGroupID
+	for _, g := range cs.groups {
+		if slices.Contains(g.Symbols, s.Symbol) {
+			s.GroupID = g.ID
+			break
+		}
+	}
+	s.SortOrder = len(cs.watchlist)
+}
+
+// findStockLocked 按代码查找自选股下标(需要已持有锁)
+func (cs *ConfigService) findStockLocked(symbol string) (int, error) {
+	idx := slices.IndexFunc(cs.watchlist, func(s models.Stock) bool { return s.Symbol == symbol })
+	if idx < 0 {
+		return -1, fmt.Errorf("自选股不存在: %s", symbol)
+	}
+	return idx, nil
+}
+
+// AddStockToGroup 将自选股加入指定分组，保留其已有分组；主分组与展示位置不变
+func (cs *ConfigService) AddStockToGroup(symbol, groupID string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	gi := cs.findGroupLocked(groupID)
+	if gi < 0 {
+		return ErrWatchlistGroupNotFound
+	}
+	if _, err := cs.findStockLocked(symbol); err != nil {
+		return err
+	}
+	if slices.Contains(cs.groups[gi].Symbols, symbol) {
+		return nil
+	}
+	cs.groups[gi].Symbols = append(cs.groups[gi].Symbols, symbol)
+	return cs.saveWatchlistLocked()
+}
+
+// RemoveStockFromGroup 将自选股移出指定分组，自选股本身保留
+// 移出的是主分组时改到所属的下一个分组；只属于默认分组时不能移出，应直接删除自选股
+func (cs *ConfigService) RemoveStockFromGroup(symbol, groupID string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	gi := cs.findGroupLocked(groupID)
+	if gi < 0 {
+		return ErrWatchlistGroupNotFound
+	}
+	idx, err := cs.findStockLocked(symbol)
+	if err != nil {
+		return err
+	}
+	mi := slices.Index(cs.groups[gi].Symbols, symbol)
+	if mi < 0 {
+		return nil
+	}
+	isPrimary := cs.watchlist[idx].GroupID == groupID
+	inOtherGroup := slices.ContainsFunc(cs.groups, func(g models.WatchlistGroup) bool {
+		return g.ID != groupID && slices.Contains(g.Symbols, symbol)
+	})
+	if isPrimary && groupID == models.DefaultWatchlistGroupID && !inOtherGroup {
+		return fmt.Errorf("自选股至少需要属于一个分组，如需移除请从自选股中删除")
+	}
+	cs.groups[gi].Symbols = slices.Delete(cs.groups[gi].Symbols, mi, mi+1)
+	if isPrimary {
+		cs.reassignPrimaryGroupLocked(idx)
+		cs.normalizeWatchlistLocked()
+	}
+	return cs.saveWatchlistLocked()
+}
+
+// MoveStockToGroup 将自选股移动到指定分组末尾：离开原主分组并以目标分组为主分组
+func (cs *ConfigService) MoveStockToGroup(symbol, groupID string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	gi := cs.findGroupLocked(groupID)
+	if gi < 0 {
+		return ErrWatchlistGroupNotFound
+	}
+	idx, err := cs.findStockLocked(symbol)
+	if err != nil {
+		return err
+	}
+	oldGroup := cs.watchlist[idx].GroupID
+	if oldGroup == groupID {
+		return nil
+	}
+	if oi := cs.findGroupLocked(oldGroup); oi >= 0 {
+		cs.groups[oi].Symbols = slices.DeleteFunc(cs.groups[oi].Symbols, func(s string) bool { return s == symbol })
+	}
+	cs.watchlist[idx].GroupID = groupID
+	cs.watchlist[idx].SortOrder = len(cs.watchlist)
+	cs.normalizeWatchlistLocked()
+	return cs.saveWatchlistLocked()
+}
+
+// ReorderWatchlist 按 symbols 的先后调整自选股在各自分组内的顺序
+// 不跨分组移动；未列出的自选股保持原有相对顺序，排在同组已列出的之后
+func (cs *ConfigService) ReorderWatchlist(symbols []string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	position := make(map[string]int, len(symbols))
+	for i, symbol := range symbols {
+		if _, ok := position[symbol]; !ok {
+			position[symbol] = i
+		}
+	}
+	for i := range cs.watchlist {
+		if pos, ok := position[cs.watchlist[i].Symbol]; ok {
+			cs.watchlist[i].SortOrder = pos
+		} else {
+			cs.watchlist[i].SortOrder = len(symbols) + i
+		}
+	}
+	cs.normalizeWatchlistLocked()
+	return cs.saveWatchlistLocked()
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func watchlistLayout(cs *ConfigService) string {
+	var parts []string
+	for _, s := range cs.GetWatchlist() {
+		parts = append(parts, s.GroupID+":"+s.Symbol)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestWatchlistGroups_MigrateLegacy(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `[{"symbol":"sh600519","name":"贵州茅台"},{"symbol":"sz000001","name":"平安银行"}]`
+	if err := os.WriteFile(filepath.Join(dir, "watchlist.json"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := cs.GetWatchlistGroups()
+	if len(groups) != 1 || groups[0].ID != models.DefaultWatchlistGroupID || groups[0].Name != models.DefaultWatchlistGroupName {
+		t.Fatalf("groups = %+v", groups)
+	}
+	if got := watchlistLayout(cs); got != "default:sh600519,default:sz000001" {
+		t.Errorf("watchlist = %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "watchlist_groups.json")); err != nil {
+		t.Errorf("groups file not saved: %v", err)
+	}
+}
+
+func TestWatchlistGroups_MoveAndReorder(t *testing.T) {
+	dir := t.TempDir()
+	cs, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, symbol := range []string{"sh600519", "sz000001", "sz300750", "sh601318"} {
+		if err := cs.AddToWatchlist(models.Stock{Symbol: symbol}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bank, err := cs.CreateWatchlistGroup(" 银行 ")
+	if err != nil || bank.Name != "银行" || bank.SortOrder != 1 {
+		t.Fatalf("group = %+v, err = %v", bank, err)
+	}
+	if _, err := cs.CreateWatchlistGroup("银行"); err == nil {
+		t.Error("expected duplicate name error")
+	}
+	if err := cs.RenameWatchlistGroup(bank.ID, "金融"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.RenameWatchlistGroup("missing", "x"); err != ErrWatchlistGroupNotFound {
+		t.Errorf("rename missing err = %v", err)
+	}
+
+	if err := cs.MoveStockToGroup("sh601318", bank.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.MoveStockToGroup("sz000001", bank.ID); err != nil {
+		t.Fatal(err)
+	}
+	want := "default:sh600519,default:sz300750," + bank.ID + ":sh601318," + bank.ID + ":sz000001"
+	if got := watchlistLayout(cs); got != want {
+		t.Errorf("after move = %s, want %s", got, want)
+	}
+
+	// 只调整顺序，不跨分组
+	if err := cs.ReorderWatchlist([]string{"sz000001", "sz300750", "sh601318"}); err != nil {
+		t.Fatal(err)
+	}
+	want = "default:sz300750,default:sh600519," + bank.ID + ":sz000001," + bank.ID + ":sh601318"
+	if got := watchlistLayout(cs); got != want {
+		t.Errorf("after reorder = %s, want %s", got, want)
+	}
+
+	// 删除自选股不受分组影响，重新加载后分组与顺序保持
+	if err := cs.RemoveFromWatchlist("sz000001"); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "default:sz300750,default:sh600519," + bank.ID + ":sh601318"
+	if got := watchlistLayout(reloaded); got != want {
+		t.Errorf("reloaded = %s, want %s", got, want)
+	}
+	if groups := reloaded.GetWatchlistGroups(); len(groups) != 2 || groups[1].Name != "金融" {
+		t.Errorf("reloaded groups = %+v", groups)
+	}
+}
+
+func groupMembers(cs *ConfigService) string {
+	var parts []string
+	for _, g := range cs.GetWatchlistGroups() {
+		parts = append(parts, g.Name+"="+strings.Join(g.Symbols, "|"))
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestWatchlistGroups_MultiMembership(t *testing.T) {
+	dir := t.TempDir()
+	// 旧版分组文件没有 symbols 字段，加载时按主分组补齐
+	legacyList := `[{"symbol":"sh600519","groupId":"default"},{"symbol":"sz000063","groupId":"g1"},{"symbol":"sh600050","groupId":"g1"}]`
+	legacyGroups := `[{"id":"default","name":"默认分组","sortOrder":0},{"id":"g1","name":"通信","sortOrder":1}]`
+	os.WriteFile(filepath.Join(dir, "watchlist.json"), []byte(legacyList), 0644)
+	os.WriteFile(filepath.Join(dir, "watchlist_groups.json"), []byte(legacyGroups), 0644)
+	cs, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := groupMembers(cs); got != "默认分组=sh600519,通信=sz000063|sh600050" {
+		t.Fatalf("migrated members = %s", got)
+	}
+
+	half, err := cs.CreateWatchlistGroup("半仓")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, symbol := range []string{"sz000063", "sh600519", "sz000063"} {
+		if err := cs.AddStockToGroup(symbol, half.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cs.AddStockToGroup("sz300750", half.ID); err == nil {
+		t.Error("adding a stock outside the watchlist should fail")
+	}
+	if err := cs.AddStockToGroup("sh600519", "missing"); err != ErrWatchlistGroupNotFound {
+		t.Errorf("missing group err = %v", err)
+	}
+	if got := groupMembers(cs); got != "默认分组=sh600519,通信=sz000063|sh600050,半仓=sz000063|sh600519" {
+		t.Errorf("members = %s", got)
+	}
+	// 加入其他分组不改变展示位置
+	if got := watchlistLayout(cs); got != "default:sh600519,g1:sz000063,g1:sh600050" {
+		t.Errorf("layout = %s", got)
+	}
+
+	// 移出主分组后改到所属的下一个分组
+	if err := cs.RemoveStockFromGroup("sz000063", "g1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := watchlistLayout(cs); got != "default:sh600519,g1:sh600050,"+half.ID+":sz000063" {
+		t.Errorf("layout after remove = %s", got)
+	}
+	// 只属于默认分组的自选股不能移出
+	if err := cs.RemoveStockFromGroup("sh600050", "g1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.RemoveStockFromGroup("sh600050", models.DefaultWatchlistGroupID); err == nil {
+		t.Error("removing the only group should fail")
+	}
+
+	if err := cs.DeleteWatchlistGroup(models.DefaultWatchlistGroupID); err == nil {
+		t.Error("default group should not be deletable")
+	}
+	if err := cs.DeleteWatchlistGroup(half.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.DeleteWatchlistGroup(half.ID); err != ErrWatchlistGroupNotFound {
+		t.Errorf("delete twice err = %v", err)
+	}
+
+	// 删除自选股同时移出所有分组，重新加载后保持
+	if err := cs.RemoveFromWatchlist("sh600519"); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := watchlistLayout(reloaded); got != "default:sh600050,default:sz000063" {
+		t.Errorf("reloaded layout = %s", got)
+	}
+	if got := groupMembers(reloaded); got != "默认分组=sh600050|sz000063,通信=" {
+		t.Errorf("reloaded members = %s", got)
+	}
+}
+
+func TestSyncSubscriptions_KeepsExtraCodes(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, symbol := range []string{"sh600519", "sz000001"} {
+		if err := cs.AddToWatchlist(models.Stock{Symbol: symbol}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cs.ReorderWatchlist([]string{"sz000001", "sh600519"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 前端额外订阅的 sh601318 不在自选股中，重排后仍应保留
+	p := &MarketDataPusher{configService: cs, subscribedCodes: []string{"sh600519", "sh601318", "sz000001"}}
+	p.SyncSubscriptions()
+	if got := strings.Join(p.subscribedCodes, ","); got != "sz000001,sh600519,sh601318" {
+		t.Errorf("subscriptions = %s", got)
+	}
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/run-bigpig/jcp/internal/embed"
+	"github.com/run-bigpig/jcp/internal/models"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// 自选股导入导出格式
+const (
+	WatchlistFormatCSV  = "csv"
+	WatchlistFormatJSON = "json"
+	WatchlistFormatTHS  = "ths" // 同花顺自选股导出（制表符分隔文本，表头含 代码/名称）
+	WatchlistFormatTDX  = "tdx" // 通达信自选股 EBK 文件（每行为市场位加 6 位代码）
+)
+
+// tdxMarkets 通达信 EBK 市场位
+var tdxMarkets = map[byte]string{'0': "sz", '1': "sh", '2': "bj"}
+
+// WatchlistImportReport 自选股导入统计
+type WatchlistImportReport struct {
+	Imported int
+	Skipped  int // 已在自选股中或文件内重复
+	Invalid  int // 不在股票索引中的代码
+}
+
+// DetectWatchlistFormat 按扩展名推断导入格式，无法识别时按 CSV 处理
+func DetectWatchlistFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return WatchlistFormatJSON
+	case ".ebk":
+		return WatchlistFormatTDX
+	case ".txt", ".xls":
+		return WatchlistFormatTHS
+	}
+	return WatchlistFormatCSV
+}
+
+// WatchlistCSVHeader 自选股 CSV 列顺序
+var WatchlistCSVHeader = []string{"symbol", "name"}
+
+var (
+	stockIndexOnce sync.Once
+	stockIndex     map[string]string // sh600519 -> 贵州茅台
+)
+
+// loadStockIndex 从内置 stock_basic.json 构建代码到名称的索引（只构建一次）
+func loadStockIndex() map[string]string {
+	stockIndexOnce.Do(func() {
+		stockIndex = make(map[string]string)
+		var basicData stockBasicData
+		if err := json.Unmarshal(embed.StockBasicJSON, &basicData); err != nil {
+			return
+		}
+		symbolIdx, nameIdx, tsCodeIdx := -1, -1, -1
+		for i, field := range basicData.Data.Fields {
+			switch field {
+			case "symbol":
+				symbolIdx = i
+			case "name":
+				nameIdx = i
+			case "ts_code":
+				tsCodeIdx = i
+			}
+		}
+		if symbolIdx < 0 || nameIdx < 0 || tsCodeIdx < 0 {
+			return
+		}
+		for _, item := range basicData.Data.Items {
+			if len(item) <= symbolIdx || len(item) <= nameIdx || len(item) <= tsCodeIdx {
+				continue
+			}
+			tsCode, _ := item[tsCodeIdx].(string)
+			name, _ := item[nameIdx].(string)
+			if code := models.NormalizeSymbol(tsCode); code != "" {
+				stockIndex[code] = name
+			}
+		}
+	})
+	return stockIndex
+}
+
+// resolveImportSymbol 校验并规范化导入的代码，返回规范代码与名称
+// 纯数字代码按沪深北依次匹配；指数不在股票索引中，带名称时直接放行
+func resolveImportSymbol(symbol, name string) (string, string, bool) {
+	index := loadStockIndex()
+	code := models.NormalizeSymbol(symbol)
+	if len(code) == 6 {
+		for _, market := range []string{"sh", "sz", "bj"} {
+			if _, ok := index[market+code]; ok {
+				code = market + code
+				break
+			}
+		}
+	}
+	if indexName, ok := index[code]; ok {
+		if name == "" {
+			name = indexName
+		}
+		return code, name, true
+	}
+	if models.IsIndexSymbol(code) && name != "" {
+		return code, name, true
+	}
+	return "", "", false
+}
+
+// parseWatchlistCSV 解析 symbol,name 两列的 CSV，表头可选
+func parseWatchlistCSV(data []byte) ([]models.Stock, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var stocks []models.Stock
+	for line := 0; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 CSV 失败: %w", err)
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		first := strings.ToLower(strings.TrimSpace(record[0]))
+		if line == 0 && (first == "symbol" || first == "代码" || first == "股票代码") {
+			continue
+		}
+		stock := models.Stock{Symbol: strings.TrimSpace(record[0])}
+		if len(record) > 1 {
+			stock.Name = strings.TrimSpace(record[1])
+		}
+		stocks = append(stocks, stock)
+	}
+	return stocks, nil
+}
+
+// decodeWatchlistText 去除 BOM，非 UTF-8 内容按 GBK 解码（券商软件导出多为 GBK）
+func decodeWatchlistText(data []byte) []byte {
+	data = bytes.TrimPrefix(data, []byte(utf8BOM))
+	if utf8.Valid(data) {
+		return data
+	}
+	decoded, err := simplifiedchinese.GBK.NewDecoder().Bytes(data)
+	if err != nil {
+		return data
+	}
+	return decoded
+}
+
+// parseWatchlistTHS 解析同花顺导出的自选股：首个含“代码”列的行为表头，按表头取代码与名称
+// 单元格可能带 ="600519" 形式的引号，解析时去除
+func parseWatchlistTHS(data []byte) ([]models.Stock, error) {
+	codeIdx, nameIdx := -1, -1
+	var stocks []models.Stock
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		for i := range fields {
+			fields[i] = strings.Trim(fields[i], "=\"' \r")
+		}
+		if codeIdx < 0 {
+			for i, f := range fields {
+				switch f {
+				case "代码", "股票代码", "证券代码":
+					codeIdx = i
+				case "名称", "股票名称", "证券名称":
+					nameIdx = i
+				}
+			}
+			continue
+		}
+		if codeIdx >= len(fields) || fields[codeIdx] == "" {
+			continue
+		}
+		stock := models.Stock{Symbol: fields[codeIdx]}
+		if nameIdx >= 0 && nameIdx < len(fields) {
+			stock.Name = fields[nameIdx]
+		}
+		stocks = append(stocks, stock)
+	}
+	if codeIdx < 0 {
+		return nil, fmt.Errorf("未找到代码列，请确认是同花顺导出的自选股文件")
+	}
+	return stocks, nil
+}
+
+// parseWatchlistTDX 解析通达信 EBK 文件：每行为市场位（0 深圳、1 上海、2 北京）加 6 位代码
+// 文件不含名称，指数代码因无法校验名称会计入 invalid
+func parseWatchlistTDX(data []byte) []models.Stock {
+	var stocks []models.Stock
+	for _, line := range strings.Fields(string(data)) {
+		if market, ok := tdxMarkets[line[0]]; ok && len(line) == 7 {
+			line = market + line[1:]
+		}
+		stocks = append(stocks, models.Stock{Symbol: line})
+	}
+	return stocks
+}
+
+// ImportWatchlist 批量导入自选股，format 为 csv（symbol,name 两列）、json（models.Stock 数组）、
+// ths（同花顺导出）或 tdx（通达信 EBK）；全部处理完后只写一次磁盘
+func (cs *ConfigService) ImportWatchlist(data []byte, format string) (WatchlistImportReport, error) {
+	var report WatchlistImportReport
+	var stocks []models.Stock
+	var err error
+	switch strings.ToLower(format) {
+	case WatchlistFormatCSV:
+		if stocks, err = parseWatchlistCSV(decodeWatchlistText(data)); err != nil {
+			return report, err
+		}
+	case WatchlistFormatJSON:
+		if err = json.Unmarshal(bytes.TrimPrefix(data, []byte(utf8BOM)), &stocks); err != nil {
+			return report, fmt.Errorf("解析 JSON 失败: %w", err)
+		}
+	case WatchlistFormatTHS:
+		if stocks, err = parseWatchlistTHS(decodeWatchlistText(data)); err != nil {
+			return report, err
+		}
+	case WatchlistFormatTDX:
+		stocks = parseWatchlistTDX(decodeWatchlistText(data))
+	default:
+		return report, fmt.Errorf("不支持的导入格式: %s", format)
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	existing := make(map[string]bool, len(cs.watchlist)+len(stocks))
+	for _, s := range cs.watchlist {
+		existing[s.Symbol] = true
+	}
+	added := make([]models.Stock, 0, len(stocks))
+	for _, s := range stocks {
+		code, name, ok := resolveImportSymbol(s.Symbol, s.Name)
+		if !ok {
+			report.Invalid++
+			continue
+		}
+		if existing[code] {
+			report.Skipped++
+			continue
+		}
+		existing[code] = true
+		added = append(added, models.Stock{
+			Symbol:    code,
+			Name:      name,
+			Type:      models.ClassifySymbol(code),
+			GroupID:   models.DefaultWatchlistGroupID,
+			SortOrder: len(cs.watchlist) + len(added),
+		})
+	}
+	if len(added) == 0 {
+		return report, nil
+	}
+
+	prev := cs.watchlist
+	cs.watchlist = append(append([]models.Stock{}, cs.watchlist...), added...)
+	cs.normalizeWatchlistLocked()
+	if err := cs.saveWatchlistLocked(); err != nil {
+		cs.watchlist = prev
+		return report, err
+	}
+	report.Imported = len(added)
+	return report, nil
+}
+
+// ExportWatchlist 导出自选股，format 为 csv 或 json
+func (cs *ConfigService) ExportWatchlist(format string) ([]byte, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	switch strings.ToLower(format) {
+	case WatchlistFormatCSV:
+		rows := make([][]string, 0, len(cs.watchlist))
+		for _, s := range cs.watchlist {
+			rows = append(rows, []string{s.Symbol, s.Name})
+		}
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, WatchlistCSVHeader, rows, nil); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case WatchlistFormatJSON:
+		return json.MarshalIndent(cs.watchlist, "", "  ")
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
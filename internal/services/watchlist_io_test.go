@@ -0,0 +1,129 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestImportWatchlist(t *testing.T) {
+	dir := t.TempDir()
+	cs, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.AddToWatchlist(models.Stock{Symbol: "sh600519", Name: "贵州茅台"}); err != nil {
+		t.Fatal(err)
+	}
+
+	csvData := utf8BOM + "symbol,name\n600519,贵州茅台\n000001.SZ,\nSZ300750,宁德时代\n999999,不存在\nsh000001,上证指数\n000001.SZ,平安银行\n"
+	report, err := cs.ImportWatchlist([]byte(csvData), "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 600519 已存在、000001.SZ 重复、999999 不在索引
+	if report != (WatchlistImportReport{Imported: 3, Skipped: 2, Invalid: 1}) {
+		t.Errorf("report = %+v", report)
+	}
+
+	var symbols []string
+	for _, s := range cs.GetWatchlist() {
+		symbols = append(symbols, s.Symbol+":"+s.Name)
+	}
+	want := "sh600519:贵州茅台,sz000001:平安银行,sz300750:宁德时代,sh000001:上证指数"
+	if got := strings.Join(symbols, ","); got != want {
+		t.Errorf("watchlist = %s, want %s", got, want)
+	}
+
+	// 批量导入只写一次，落盘内容与内存一致
+	data, _ := os.ReadFile(filepath.Join(dir, "watchlist.json"))
+	var saved []models.Stock
+	if err := json.Unmarshal(data, &saved); err != nil || len(saved) != 4 {
+		t.Fatalf("saved watchlist = %s, err = %v", data, err)
+	}
+
+	report, err = cs.ImportWatchlist([]byte(`[{"symbol":"sh601318","name":"中国平安","price":50},{"symbol":"sh600519"}]`), "json")
+	if err != nil || report.Imported != 1 || report.Skipped != 1 {
+		t.Errorf("json import = %+v, err = %v", report, err)
+	}
+
+	if _, err := cs.ImportWatchlist([]byte("x"), "xlsx"); err == nil {
+		t.Error("expected unsupported format error")
+	}
+	if _, err := cs.ImportWatchlist([]byte("{"), "json"); err == nil {
+		t.Error("expected json parse error")
+	}
+}
+
+func TestImportWatchlist_BrokerFormats(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同花顺导出为 GBK 编码的制表符分隔文本
+	ths := "代码\t名称\t涨幅\r\n=\"600519\"\t贵州茅台\t1.2%\r\nSZ000001\t平安银行\t-0.5%\r\n999999\t不存在\t0\r\n"
+	gbk, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(ths))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := cs.ImportWatchlist(gbk, WatchlistFormatTHS)
+	if err != nil || report != (WatchlistImportReport{Imported: 2, Invalid: 1}) {
+		t.Fatalf("ths report = %+v, err = %v", report, err)
+	}
+	if _, err := cs.ImportWatchlist([]byte("a\tb\n"), WatchlistFormatTHS); err == nil {
+		t.Error("expected missing code column error")
+	}
+
+	// 通达信 EBK：首行为空，市场位 0 深圳、1 上海
+	report, err = cs.ImportWatchlist([]byte("\r\n1600519\r\n0300750\r\n1000001\r\n"), WatchlistFormatTDX)
+	if err != nil || report != (WatchlistImportReport{Imported: 1, Skipped: 1, Invalid: 1}) {
+		t.Fatalf("tdx report = %+v, err = %v", report, err)
+	}
+
+	var symbols []string
+	for _, s := range cs.GetWatchlist() {
+		symbols = append(symbols, s.Symbol+":"+s.Name)
+	}
+	if got := strings.Join(symbols, ","); got != "sh600519:贵州茅台,sz000001:平安银行,sz300750:宁德时代" {
+		t.Errorf("watchlist = %s", got)
+	}
+
+	for path, want := range map[string]string{"a.EBK": WatchlistFormatTDX, "自选股.xls": WatchlistFormatTHS, "b.json": WatchlistFormatJSON, "c": WatchlistFormatCSV} {
+		if got := DetectWatchlistFormat(path); got != want {
+			t.Errorf("DetectWatchlistFormat(%s) = %s, want %s", path, got, want)
+		}
+	}
+}
+
+func TestExportWatchlist(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.AddToWatchlist(models.Stock{Symbol: "sh600519", Name: "贵州茅台"})
+	cs.AddToWatchlist(models.Stock{Symbol: "sz000001", Name: "平安银行"})
+
+	data, err := cs.ExportWatchlist("csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := utf8BOM + "symbol,name\r\nsh600519,贵州茅台\r\nsz000001,平安银行\r\n"; string(data) != want {
+		t.Errorf("csv = %q", data)
+	}
+
+	// 导出再导入到新列表应完整还原
+	other, _ := NewConfigService(t.TempDir())
+	if report, err := other.ImportWatchlist(data, "csv"); err != nil || report != (WatchlistImportReport{Imported: 2}) {
+		t.Errorf("round trip = %+v, err = %v", report, err)
+	}
+
+	if data, err = cs.ExportWatchlist("json"); err != nil || !strings.Contains(string(data), `"symbol": "sz000001"`) {
+		t.Errorf("json = %s, err = %v", data, err)
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 搜索服务接口地址
+const (
+	tavilySearchAPI = "https://api.tavily.com/search"
+	serperSearchAPI = "https://google.serper.dev/search"
+	bingSearchAPI   = "https://api.bing.microsoft.com/v7.0/search"
+)
+
+// 网页搜索参数
+const (
+	defaultWebSearchResults = 5
+	maxWebSearchResults     = 10
+	// DefaultWebSearchCalls 每场会议默认最多调用 web_search 的次数
+	DefaultWebSearchCalls = 5
+)
+
+// ErrWebSearchNotConfigured 未配置搜索服务或 API Key
+var ErrWebSearchNotConfigured = errors.New("未配置网页搜索服务，请在设置中选择搜索服务并填写 API Key")
+
+// WebSearchResult 网页搜索结果
+type WebSearchResult struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+	URL     string `json:"url"`
+}
+
+// WebSearchService 网页搜索服务，按配置选择 Tavily、Serper 或 Bing
+type WebSearchService struct {
+	configService *ConfigService
+	client        *http.Client // 为空时使用代理管理器的客户端，测试时替换
+}
+
+// NewWebSearchService 创建网页搜索服务
+func NewWebSearchService(configService *ConfigService) *WebSearchService {
+	return &WebSearchService{configService: configService}
+}
+
+// Config 当前搜索服务配置
+func (s *WebSearchService) Config() models.SearchConfig {
+	if s.configService == nil {
+		return models.SearchConfig{}
+	}
+	return s.configService.GetConfig().Search
+}
+
+// httpClient 每次取代理管理器的客户端，代理设置修改后立即生效
+func (s *WebSearchService) httpClient() *http.Client {
+	if s.client != nil {
+		return s.client
+	}
+	return proxy.GetManager().GetClientWithTimeout(15 * time.Second)
+}
+
+// Search 搜索网页，limit 为 0 时使用配置的默认条数
+func (s *WebSearchService) Search(ctx context.Context, query string, limit int) ([]WebSearchResult, error) {
+	cfg := s.Config()
+	if cfg.Provider == "" || strings.TrimSpace(cfg.APIKey) == "" {
+		return nil, ErrWebSearchNotConfigured
+	}
+	if limit <= 0 {
+		limit = cfg.MaxResults
+	}
+	if limit <= 0 {
+		limit = defaultWebSearchResults
+	}
+	limit = min(limit, maxWebSearchResults)
+
+	var results []WebSearchResult
+	var err error
+	switch cfg.Provider {
+	case models.SearchProviderTavily:
+		results, err = s.searchTavily(ctx, cfg.APIKey, query, limit)
+	case models.SearchProviderSerper:
+		results, err = s.searchSerper(ctx, cfg.APIKey, query, limit)
+	case models.SearchProviderBing:
+		results, err = s.searchBing(ctx, cfg.APIKey, query, limit)
+	default:
+		return nil, fmt.Errorf("不支持的搜索服务: %s", cfg.Provider)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s 搜索失败: %w", cfg.Provider, err)
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchTavily Tavily 搜索，content 为与查询相关的摘录
+func (s *WebSearchService) searchTavily(ctx context.Context, apiKey, query string, limit int) ([]WebSearchResult, error) {
+	body := map[string]any{"query": query, "max_results": limit}
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	header := map[string]string{"Authorization": "Bearer " + apiKey}
+	if err := s.doJSON(ctx, http.MethodPost, tavilySearchAPI, header, body, &resp); err != nil {
+		return nil, err
+	}
+	results := make([]WebSearchResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, WebSearchResult{Title: r.Title, Snippet: r.Content, URL: r.URL})
+	}
+	return results, nil
+}
+
+// searchSerper Serper（Google）搜索，取自然结果
+func (s *WebSearchService) searchSerper(ctx context.Context, apiKey, query string, limit int) ([]WebSearchResult, error) {
+	body := map[string]any{"q": query, "num": limit, "gl": "cn", "hl": "zh-cn"}
+	var resp struct {
+		Organic []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+		} `json:"organic"`
+	}
+	header := map[string]string{"X-API-KEY": apiKey}
+	if err := s.doJSON(ctx, http.MethodPost, serperSearchAPI, header, body, &resp); err != nil {
+		return nil, err
+	}
+	results := make([]WebSearchResult, 0, len(resp.Organic))
+	for _, r := range resp.Organic {
+		results = append(results, WebSearchResult{Title: r.Title, Snippet: r.Snippet, URL: r.Link})
+	}
+	return results, nil
+}
+
+// searchBing Bing Web Search API 搜索
+func (s *WebSearchService) searchBing(ctx context.Context, apiKey, query string, limit int) ([]WebSearchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("count", fmt.Sprintf("%d", limit))
+	params.Set("mkt", "zh-CN")
+	var resp struct {
+		WebPages struct {
+			Value []struct {
+				Name    string `json:"name"`
+				URL     string `json:"url"`
+				Snippet string `json:"snippet"`
+			} `json:"value"`
+		} `json:"webPages"`
+	}
+	header := map[string]string{"Ocp-Apim-Subscription-Key": apiKey}
+	if err := s.doJSON(ctx, http.MethodGet, bingSearchAPI+"?"+params.Encode(), header, nil, &resp); err != nil {
+		return nil, err
+	}
+	results := make([]WebSearchResult, 0, len(resp.WebPages.Value))
+	for _, r := range resp.WebPages.Value {
+		results = append(results, WebSearchResult{Title: r.Name, Snippet: r.Snippet, URL: r.URL})
+	}
+	return results, nil
+}
+
+// doJSON 发送请求并解析 JSON 响应，body 为 nil 时不带请求体
+func (s *WebSearchService) doJSON(ctx context.Context, method, rawURL string, header map[string]string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncateRunes(string(data), 200))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// truncateRunes 按字符数截取，超出时末尾加省略号
+func truncateRunes(s string, maxChars int) string {
+	runes := []rune(s)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return s
+	}
+	return string(runes[:maxChars]) + "…"
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestWebSearchProviders(t *testing.T) {
+	var gotReq *http.Request
+	var gotBody map[string]any
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotReq, gotBody = req, nil
+		if req.Body != nil {
+			json.NewDecoder(req.Body).Decode(&gotBody)
+		}
+		var body string
+		switch req.URL.Host {
+		case "api.tavily.com":
+			body = `{"results":[{"title":"T1","url":"https://a.com/1","content":"c1"},{"title":"T2","url":"https://a.com/2","content":"c2"}]}`
+		case "google.serper.dev":
+			body = `{"organic":[{"title":"S1","link":"https://b.com/1","snippet":"s1"}]}`
+		case "api.bing.microsoft.com":
+			body = `{"webPages":{"value":[{"name":"B1","url":"https://c.com/1","snippet":"b1"}]}}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	newService := func(provider models.SearchProvider) *WebSearchService {
+		cfg := &models.AppConfig{Search: models.SearchConfig{Provider: provider, APIKey: "k", MaxResults: 1}}
+		return &WebSearchService{configService: &ConfigService{config: cfg}, client: client}
+	}
+	ctx := context.Background()
+
+	// Tavily：Bearer 鉴权，limit 为 0 时取配置的条数
+	res, err := newService(models.SearchProviderTavily).Search(ctx, "茅台 提价", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0] != (WebSearchResult{Title: "T1", Snippet: "c1", URL: "https://a.com/1"}) {
+		t.Errorf("tavily = %+v", res)
+	}
+	if gotReq.Header.Get("Authorization") != "Bearer k" || gotBody["query"] != "茅台 提价" || gotBody["max_results"] != float64(1) {
+		t.Errorf("tavily request = %v %v", gotReq.Header, gotBody)
+	}
+
+	res, err = newService(models.SearchProviderSerper).Search(ctx, "q", 20)
+	if err != nil || len(res) != 1 || res[0].URL != "https://b.com/1" || res[0].Snippet != "s1" {
+		t.Errorf("serper = %+v, %v", res, err)
+	}
+	if gotReq.Header.Get("X-API-KEY") != "k" || gotBody["num"] != float64(maxWebSearchResults) {
+		t.Errorf("serper request = %v %v", gotReq.Header, gotBody)
+	}
+
+	res, err = newService(models.SearchProviderBing).Search(ctx, "q", 3)
+	if err != nil || len(res) != 1 || res[0].Title != "B1" {
+		t.Errorf("bing = %+v, %v", res, err)
+	}
+	if gotReq.Header.Get("Ocp-Apim-Subscription-Key") != "k" || gotReq.URL.Query().Get("count") != "3" {
+		t.Errorf("bing request = %v %v", gotReq.Header, gotReq.URL)
+	}
+
+	if _, err := newService("").Search(ctx, "q", 0); !errors.Is(err, ErrWebSearchNotConfigured) {
+		t.Errorf("unconfigured err = %v", err)
+	}
+	if _, err := newService("baidu").Search(ctx, "q", 0); err == nil {
+		t.Error("unknown provider should fail")
+	}
+}